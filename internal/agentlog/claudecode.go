@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/atrest"
 )
 
 const (
@@ -170,9 +172,10 @@ func newestJSONLIn(dir string, since time.Time) (string, bool) {
 }
 
 // nativeSessionIDFromPath extracts the Claude Code session UUID from a JSONL file path.
-// The filename is <uuid>.jsonl, so we strip the extension.
+// The filename is <uuid>.jsonl (or <uuid>.jsonl.sealed once encrypted), so
+// we strip the extension.
 func nativeSessionIDFromPath(path string) string {
-	base := filepath.Base(path)
+	base := strings.TrimSuffix(filepath.Base(path), atrest.SealedSuffix)
 	return strings.TrimSuffix(base, ".jsonl")
 }
 
@@ -248,9 +251,53 @@ type ccEntry struct {
 
 // ccMessage is the message field of a ccEntry.
 type ccMessage struct {
-	Role    string      `json:"role"`
-	Content []ccContent `json:"content"`
-	Usage   *ccUsage    `json:"usage,omitempty"`
+	Role    string        `json:"role"`
+	Content ccContentList `json:"content"`
+	Usage   *ccUsage      `json:"usage,omitempty"`
+}
+
+// ccContentList is a message's content: a list of blocks, or a plain string
+// for prompts typed by the user.
+type ccContentList []ccContent
+
+func (l *ccContentList) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*l = ccContentList{{Type: "text", Text: text}}
+		return nil
+	}
+	var blocks []ccContent
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return err
+	}
+	*l = blocks
+	return nil
+}
+
+// ccResultText is a tool result's content: a string, or a list of text
+// blocks that are joined.
+type ccResultText string
+
+func (t *ccResultText) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*t = ccResultText(text)
+		return nil
+	}
+	var blocks []struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return err
+	}
+	parts := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		if b.Text != "" {
+			parts = append(parts, b.Text)
+		}
+	}
+	*t = ccResultText(strings.Join(parts, "\n"))
+	return nil
 }
 
 // ccUsage holds Claude API token usage counts for an assistant turn.
@@ -275,8 +322,8 @@ type ccContent struct {
 	Name  string          `json:"name,omitempty"`
 	Input json.RawMessage `json:"input,omitempty"`
 
-	// tool_result (content is a string in the simple case)
-	Content string `json:"content,omitempty"`
+	// tool_result
+	Content ccResultText `json:"content,omitempty"`
 }
 
 // parseClaudeCodeLine parses one JSONL line and returns 0 or more AgentEvents.
@@ -316,7 +363,7 @@ func parseClaudeCodeLine(line, sessionID, agentType, nativeSessionID string) []A
 			content = c.Name + ": " + string(c.Input)
 		case "tool_result":
 			eventType = "tool_result"
-			content = c.Content
+			content = string(c.Content)
 		default:
 			continue
 		}
@@ -0,0 +1,162 @@
+package agentlog
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/atrest"
+)
+
+// Transcript is one Claude Code conversation log on disk. Each Claude
+// session started in a work dir writes its own transcript.
+type Transcript struct {
+	Path            string
+	NativeSessionID string
+	ModTime         time.Time
+	Size            int64
+	Sealed          bool // Encrypted at rest (gt crypt); read functions open it
+}
+
+// ClaudeCodeTranscripts returns the transcripts Claude Code recorded for
+// sessions started in any of workDirs, newest first. Work dirs without
+// transcripts are skipped; the work dirs themselves need not exist any more
+// (polecat worktrees are removed after gt done, their transcripts are not).
+func ClaudeCodeTranscripts(workDirs ...string) ([]Transcript, error) {
+	var transcripts []Transcript
+	for _, workDir := range workDirs {
+		projectDir, err := claudeProjectDirFor(workDir)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := os.ReadDir(projectDir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, e := range entries {
+			sealed := strings.HasSuffix(e.Name(), ".jsonl"+atrest.SealedSuffix)
+			if e.IsDir() || (!sealed && !strings.HasSuffix(e.Name(), ".jsonl")) {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil {
+				continue
+			}
+			path := filepath.Join(projectDir, e.Name())
+			transcripts = append(transcripts, Transcript{
+				Path:            path,
+				NativeSessionID: nativeSessionIDFromPath(path),
+				ModTime:         info.ModTime(),
+				Size:            info.Size(),
+				Sealed:          sealed,
+			})
+		}
+	}
+	sort.Slice(transcripts, func(i, j int) bool {
+		return transcripts[i].ModTime.After(transcripts[j].ModTime)
+	})
+	return transcripts, nil
+}
+
+// ReadClaudeCodeTranscript parses a whole transcript into events, in log
+// order. sessionID tags the events as in Watch.
+func ReadClaudeCodeTranscript(path, sessionID string) ([]AgentEvent, error) {
+	f, err := OpenTranscript(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	nativeID := nativeSessionIDFromPath(path)
+	var events []AgentEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 256*1024), 64*1024*1024)
+	for scanner.Scan() {
+		events = append(events, parseClaudeCodeLine(scanner.Text(), sessionID, "claudecode", nativeID)...)
+	}
+	if err := scanner.Err(); err != nil {
+		return events, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return events, nil
+}
+
+// ReadClaudeCodeTranscriptFrom parses the complete lines of a transcript
+// after byte offset, for following a transcript that is still being
+// written. It returns the events and the offset to resume from; a partly
+// written last line is left for the next read.
+func ReadClaudeCodeTranscriptFrom(path, sessionID string, offset int64) ([]AgentEvent, int64, error) {
+	f, err := OpenTranscript(path)
+	if err != nil {
+		return nil, offset, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+
+	nativeID := nativeSessionIDFromPath(path)
+	var events []AgentEvent
+	reader := bufio.NewReaderSize(f, 256*1024)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				return events, offset, nil
+			}
+			return events, offset, fmt.Errorf("reading %s: %w", path, err)
+		}
+		offset += int64(len(line))
+		events = append(events, parseClaudeCodeLine(strings.TrimRight(line, "\r\n"), sessionID, "claudecode", nativeID)...)
+	}
+}
+
+// OpenTranscript opens a transcript for reading. Sealed transcripts are
+// opened in memory; they are never written to again, so offsets into
+// their plaintext stay valid.
+func OpenTranscript(path string) (io.ReadSeekCloser, error) {
+	if !strings.HasSuffix(path, atrest.SealedSuffix) {
+		return os.Open(path) //nolint:gosec // G304: path comes from ClaudeCodeTranscripts
+	}
+	data, err := atrest.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return plainTranscript{bytes.NewReader(data)}, nil
+}
+
+type plainTranscript struct{ *bytes.Reader }
+
+func (plainTranscript) Close() error { return nil }
+
+// ClaudeCodeProjectDirs returns the Claude Code project directories of
+// sessions started in root or anywhere below it.
+func ClaudeCodeProjectDirs(root string) ([]string, error) {
+	projectDir, err := claudeProjectDirFor(root)
+	if err != nil {
+		return nil, err
+	}
+	projects, prefix := filepath.Dir(projectDir), filepath.Base(projectDir)
+	entries, err := os.ReadDir(projects)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var dirs []string
+	for _, e := range entries {
+		if e.IsDir() && (e.Name() == prefix || strings.HasPrefix(e.Name(), prefix+"-")) {
+			dirs = append(dirs, filepath.Join(projects, e.Name()))
+		}
+	}
+	return dirs, nil
+}
@@ -0,0 +1,125 @@
+package agentlog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/atrest"
+)
+
+func TestReadClaudeCodeTranscript(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	workDir := "/town/gastown/polecats/Toast/gastown"
+	projectDir, err := claudeProjectDirFor(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := `{"type":"user","timestamp":"2026-01-02T10:00:00Z","message":{"role":"user","content":"Fix the flaky test"}}
+{"type":"assistant","timestamp":"2026-01-02T10:00:05Z","message":{"role":"assistant","content":[{"type":"tool_use","name":"Bash","input":{"command":"go test"}}]}}
+{"type":"user","timestamp":"2026-01-02T10:00:09Z","message":{"role":"user","content":[{"type":"tool_result","content":[{"type":"text","text":"ok"},{"type":"text","text":"PASS"}]}]}}
+{"type":"summary","summary":"ignored"}
+`
+	older := filepath.Join(projectDir, "aaa.jsonl")
+	newer := filepath.Join(projectDir, "bbb.jsonl")
+	for _, p := range []string{older, newer} {
+		if err := os.WriteFile(p, []byte(lines), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(older, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	transcripts, err := ClaudeCodeTranscripts("/town/gone", workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transcripts) != 2 || transcripts[0].NativeSessionID != "bbb" {
+		t.Fatalf("transcripts = %+v, want bbb first", transcripts)
+	}
+
+	events, err := ReadClaudeCodeTranscript(newer, "gastown/Toast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3: %+v", len(events), events)
+	}
+	if events[0].EventType != "text" || events[0].Content != "Fix the flaky test" {
+		t.Errorf("string prompt = %+v", events[0])
+	}
+	if events[1].EventType != "tool_use" || events[1].Content != `Bash: {"command":"go test"}` {
+		t.Errorf("tool use = %+v", events[1])
+	}
+	if events[2].Content != "ok\nPASS" || events[2].Timestamp.Sub(events[0].Timestamp) != 9*time.Second {
+		t.Errorf("tool result = %+v", events[2])
+	}
+}
+
+func TestReadClaudeCodeTranscriptFrom(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ccc.jsonl")
+	first := `{"type":"user","timestamp":"2026-01-02T10:00:00Z","message":{"role":"user","content":"Fix the flaky test"}}` + "\n"
+	partial := `{"type":"assistant","timestamp":"2026-01-02T10:00:05Z","message":{"role":"assistant",`
+	if err := os.WriteFile(path, []byte(first+partial), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	events, offset, err := ReadClaudeCodeTranscriptFrom(path, "gastown/Toast", 0)
+	if err != nil || len(events) != 1 || offset != int64(len(first)) {
+		t.Fatalf("first read: %d events, offset %d, err %v", len(events), offset, err)
+	}
+
+	rest := `"content":[{"type":"text","text":"On it"}]}}` + "\n"
+	if err := os.WriteFile(path, []byte(first+partial+rest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	events, offset, err = ReadClaudeCodeTranscriptFrom(path, "gastown/Toast", offset)
+	if err != nil || len(events) != 1 || events[0].Content != "On it" || offset != int64(len(first+partial+rest)) {
+		t.Fatalf("second read: %+v, offset %d, err %v", events, offset, err)
+	}
+}
+
+func TestReadSealedTranscript(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(atrest.EnvKey, "agentlogtest-1:"+strings.Repeat("A", 43)+"=")
+	workDir := "/town/gastown/crew/max"
+	projectDir, err := claudeProjectDirFor(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(projectDir, "ccc.jsonl")
+	line := `{"type":"user","timestamp":"2026-01-02T10:00:00Z","message":{"role":"user","content":"Rotate the keys"}}` + "\n"
+	if err := os.WriteFile(path, []byte(line), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := atrest.SealFile("agentlogtest-1", path); err != nil {
+		t.Fatal(err)
+	}
+
+	transcripts, err := ClaudeCodeTranscripts(workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(transcripts) != 1 || !transcripts[0].Sealed || transcripts[0].NativeSessionID != "ccc" {
+		t.Fatalf("transcripts = %+v, want sealed ccc", transcripts)
+	}
+	events, err := ReadClaudeCodeTranscript(transcripts[0].Path, "gastown/crew/max")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Content != "Rotate the keys" || events[0].NativeSessionID != "ccc" {
+		t.Errorf("events = %+v", events)
+	}
+}
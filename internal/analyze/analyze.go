@@ -0,0 +1,146 @@
+// Package analyze runs a rig's configured static analyzers against a
+// polecat's diff. Only violations on lines the branch added or changed are
+// reported, so pre-existing findings never block a submission.
+package analyze
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Built-in analyzer presets. An analyzer naming one of these with no cmd
+// gets the preset's command and file patterns.
+var presets = map[string]Analyzer{
+	"go-vet":      {Cmd: "go vet ./...", Files: []string{"*.go"}},
+	"staticcheck": {Cmd: "staticcheck ./...", Files: []string{"*.go"}},
+	"eslint":      {Cmd: "npx --no-install eslint -f unix {files}", Files: []string{"*.js", "*.jsx", "*.ts", "*.tsx", "*.mjs", "*.cjs"}},
+	"ruff":        {Cmd: "ruff check --output-format concise {files}", Files: []string{"*.py"}},
+	"shellcheck":  {Cmd: "shellcheck -f gcc {files}", Files: []string{"*.sh"}},
+}
+
+// Defaults.
+const (
+	DefaultTimeout       = 5 * time.Minute
+	DefaultMaxViolations = 50
+)
+
+// Config is the static_analysis section of a rig's settings/config.json.
+//
+//	"static_analysis": {
+//	  "analyzers": [
+//	    {"name": "go-vet"},
+//	    {"name": "staticcheck"},
+//	    {"name": "migrations", "cmd": "./scripts/check-migrations.sh {files}", "files": ["db/*.sql"]}
+//	  ]
+//	}
+type Config struct {
+	// Enabled turns the gate off without deleting the analyzer list.
+	// Defaults to true when analyzers are configured.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	Analyzers []Analyzer `json:"analyzers,omitempty"`
+
+	// MaxViolations caps how many violations are listed in the fix-it prompt.
+	MaxViolations int `json:"max_violations,omitempty"`
+}
+
+// Analyzer is one static analysis command.
+type Analyzer struct {
+	// Name identifies the analyzer in reports, and selects a preset
+	// (go-vet, staticcheck, eslint, ruff, shellcheck) when Cmd is empty.
+	Name string `json:"name"`
+
+	// Cmd is run with sh -c from the repo root. {files} is replaced with
+	// the changed files matching Files, shell-quoted. Output lines of the
+	// form file:line[:col]: message are parsed as violations.
+	Cmd string `json:"cmd,omitempty"`
+
+	// Files are glob patterns matched against changed file paths (and
+	// their base names). The analyzer is skipped when nothing matches.
+	// Empty means every changed file.
+	Files []string `json:"files,omitempty"`
+
+	// Timeout bounds one run (Go duration, default 5m).
+	Timeout string `json:"timeout,omitempty"`
+
+	// WarnOnly reports violations without blocking.
+	WarnOnly bool `json:"warn_only,omitempty"`
+}
+
+// IsEnabled reports whether the gate should run.
+func (c *Config) IsEnabled() bool {
+	if c == nil || len(c.Analyzers) == 0 {
+		return false
+	}
+	return c.Enabled == nil || *c.Enabled
+}
+
+// GetMaxViolations returns the prompt cap, defaulting to 50.
+func (c *Config) GetMaxViolations() int {
+	if c == nil || c.MaxViolations <= 0 {
+		return DefaultMaxViolations
+	}
+	return c.MaxViolations
+}
+
+// Validate checks every analyzer resolves to a command.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	for i, a := range c.Analyzers {
+		if a.Name == "" {
+			return fmt.Errorf("analyzers[%d]: name is required", i)
+		}
+		if seen[a.Name] {
+			return fmt.Errorf("analyzers[%d]: duplicate name %q", i, a.Name)
+		}
+		seen[a.Name] = true
+		if _, err := a.Resolve(); err != nil {
+			return fmt.Errorf("analyzers[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Resolve fills in preset defaults and checks the analyzer is runnable.
+func (a Analyzer) Resolve() (Analyzer, error) {
+	if p, ok := presets[a.Name]; ok {
+		if a.Cmd == "" {
+			a.Cmd = p.Cmd
+		}
+		if len(a.Files) == 0 {
+			a.Files = p.Files
+		}
+	}
+	if strings.TrimSpace(a.Cmd) == "" {
+		return a, fmt.Errorf("analyzer %q has no cmd and is not a preset (%s)", a.Name, strings.Join(Presets(), ", "))
+	}
+	if a.Timeout != "" {
+		if d, err := time.ParseDuration(a.Timeout); err != nil || d <= 0 {
+			return a, fmt.Errorf("analyzer %q: invalid timeout %q", a.Name, a.Timeout)
+		}
+	}
+	return a, nil
+}
+
+// GetTimeout returns the run timeout, defaulting to 5m.
+func (a Analyzer) GetTimeout() time.Duration {
+	if d, err := time.ParseDuration(a.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return DefaultTimeout
+}
+
+// Presets returns the built-in analyzer names.
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
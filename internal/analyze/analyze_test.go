@@ -0,0 +1,252 @@
+package analyze
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sampleDiff = `diff --git a/internal/foo/foo.go b/internal/foo/foo.go
+index 1111111..2222222 100644
+--- a/internal/foo/foo.go
++++ b/internal/foo/foo.go
+@@ -10,0 +11,3 @@ func Foo() {
++	a := 1
++	b := 2
++	_ = a + b
+@@ -40 +43 @@ func Bar() {
+-	old()
++	replaced()
+diff --git a/web/app.ts b/web/app.ts
+--- a/web/app.ts
++++ b/web/app.ts
+@@ -5,2 +4,0 @@
+-removed
+-lines
+`
+
+func TestParseDiff(t *testing.T) {
+	d := ParseDiff(sampleDiff)
+	if got := strings.Join(d.Files, ","); got != "internal/foo/foo.go,web/app.ts" {
+		t.Fatalf("Files = %q", got)
+	}
+	tests := []struct {
+		file string
+		line int
+		want bool
+	}{
+		{"internal/foo/foo.go", 11, true},
+		{"internal/foo/foo.go", 13, true},
+		{"internal/foo/foo.go", 14, false},
+		{"internal/foo/foo.go", 43, true},
+		{"internal/foo/foo.go", 0, true},
+		{"web/app.ts", 4, false},
+		{"other.go", 1, false},
+	}
+	for _, tt := range tests {
+		if got := d.Changed(tt.file, tt.line); got != tt.want {
+			t.Errorf("Changed(%s, %d) = %v, want %v", tt.file, tt.line, got, tt.want)
+		}
+	}
+	if !d.Touches("web/app.ts") {
+		t.Error("deletion-only file should still be touched")
+	}
+}
+
+func TestDiffMatch(t *testing.T) {
+	d := ParseDiff(sampleDiff)
+	if got := d.Match([]string{"*.go"}); len(got) != 1 || got[0] != "internal/foo/foo.go" {
+		t.Errorf("Match(*.go) = %v", got)
+	}
+	if got := d.Match([]string{"web/*.ts"}); len(got) != 1 {
+		t.Errorf("Match(web/*.ts) = %v", got)
+	}
+	if got := d.Match(nil); len(got) != 2 {
+		t.Errorf("Match(nil) = %v", got)
+	}
+	if got := d.Match([]string{"*.py"}); len(got) != 0 {
+		t.Errorf("Match(*.py) = %v", got)
+	}
+}
+
+func TestParseViolation(t *testing.T) {
+	dir := "/repo"
+	tests := []struct {
+		line string
+		want Violation
+		ok   bool
+	}{
+		{"internal/foo/foo.go:12:2: printf: wrong type", Violation{File: "internal/foo/foo.go", Line: 12, Col: 2, Message: "printf: wrong type"}, true},
+		{"vet: ./foo.go:3:1: unreachable code", Violation{File: "foo.go", Line: 3, Col: 1, Message: "unreachable code"}, true},
+		{"/repo/web/app.ts:7:5: 'x' is unused [Error/no-unused-vars]", Violation{File: "web/app.ts", Line: 7, Col: 5, Message: "'x' is unused [Error/no-unused-vars]"}, true},
+		{"script.sh:9: warning: quote this", Violation{File: "script.sh", Line: 9, Message: "warning: quote this"}, true},
+		{"/elsewhere/x.go:1:1: outside repo", Violation{}, false},
+		{"# github.com/example/foo", Violation{}, false},
+		{"ok  	example.com/foo	0.1s", Violation{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseViolation(tt.line, dir)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseViolation(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestExpandFiles(t *testing.T) {
+	got := expandFiles("lint {files}", []string{"a.go", "it's.go"})
+	if want := `lint 'a.go' 'it'\''s.go'`; got != want {
+		t.Errorf("expandFiles() = %q, want %q", got, want)
+	}
+	if got := expandFiles("go vet ./...", []string{"a.go"}); got != "go vet ./..." {
+		t.Errorf("expandFiles() without placeholder = %q", got)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	var nilCfg *Config
+	if nilCfg.IsEnabled() || nilCfg.Validate() != nil {
+		t.Error("nil config should be disabled and valid")
+	}
+	off := false
+	if (&Config{Enabled: &off, Analyzers: []Analyzer{{Name: "go-vet"}}}).IsEnabled() {
+		t.Error("enabled: false should disable the gate")
+	}
+
+	good := &Config{Analyzers: []Analyzer{{Name: "go-vet"}, {Name: "custom", Cmd: "./lint.sh", Timeout: "30s"}}}
+	if err := good.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	for name, cfg := range map[string]*Config{
+		"unnamed":        {Analyzers: []Analyzer{{Cmd: "true"}}},
+		"duplicate":      {Analyzers: []Analyzer{{Name: "go-vet"}, {Name: "go-vet"}}},
+		"unknown":        {Analyzers: []Analyzer{{Name: "mystery"}}},
+		"bad timeout":    {Analyzers: []Analyzer{{Name: "x", Cmd: "true", Timeout: "soon"}}},
+		"zero timeout":   {Analyzers: []Analyzer{{Name: "x", Cmd: "true", Timeout: "0s"}}},
+		"whitespace cmd": {Analyzers: []Analyzer{{Name: "x", Cmd: "  "}}},
+	} {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+// setupRepo creates a repo with a base commit on main and a branch that
+// adds lines to a.go and adds b.go.
+func setupRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "-q", "-b", "main")
+	write("a.go", "line1\nline2\nline3\n")
+	write("notes.txt", "hello\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "base")
+	run("checkout", "-q", "-b", "polecat/Toast")
+	write("a.go", "line1\nline2\nline3\nadded4\nadded5\n")
+	write("b.go", "new1\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "work")
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	dir := setupRepo(t)
+	diff, err := LoadDiff(dir, "main")
+	if err != nil {
+		t.Fatalf("LoadDiff() error = %v", err)
+	}
+	if strings.Join(diff.Files, ",") != "a.go,b.go" {
+		t.Fatalf("diff files = %v", diff.Files)
+	}
+
+	cfg := &Config{Analyzers: []Analyzer{
+		// Reports one new finding and one pre-existing one.
+		{Name: "lint", Cmd: `echo "a.go:4:1: new problem"; echo "a.go:2: old problem"; exit 1`, Files: []string{"*.go"}},
+		// Receives only the matching changed files.
+		{Name: "files", Cmd: `for f in {files}; do echo "$f:1: saw $f"; done`, Files: []string{"b.go"}},
+		{Name: "style", Cmd: `echo "./b.go:1:3: nit"`, WarnOnly: true},
+		{Name: "docs", Cmd: "exit 1", Files: []string{"*.md"}},
+		{Name: "broken", Cmd: "echo compile error; exit 2"},
+		{Name: "missing", Cmd: "definitely-not-a-real-analyzer-xyz"},
+	}}
+	report := Run(context.Background(), cfg, dir, diff)
+	byName := map[string]Result{}
+	for _, r := range report.Results {
+		byName[r.Analyzer] = r
+	}
+
+	if r := byName["lint"]; len(r.Violations) != 1 || r.Violations[0].Line != 4 || r.Ignored != 1 {
+		t.Errorf("lint result = %+v", r)
+	}
+	if r := byName["files"]; len(r.Violations) != 1 || r.Violations[0].Message != "saw b.go" {
+		t.Errorf("files result = %+v", r)
+	}
+	if r := byName["docs"]; r.Skipped == "" {
+		t.Errorf("docs should be skipped with no matching files: %+v", r)
+	}
+	if r := byName["broken"]; r.Error == "" || !strings.Contains(r.Output, "compile error") {
+		t.Errorf("broken result = %+v", r)
+	}
+	if r := byName["missing"]; !strings.Contains(r.Skipped, "not found") {
+		t.Errorf("missing result = %+v", r)
+	}
+
+	if report.Passed() {
+		t.Error("report should fail")
+	}
+	if got := len(report.Blocking()); got != 2 {
+		t.Errorf("Blocking() = %d, want 2", got)
+	}
+	if got := report.Warnings(); len(got) != 1 || got[0].File != "b.go" {
+		t.Errorf("Warnings() = %+v", got)
+	}
+
+	prompt := report.Prompt(1)
+	for _, want := range []string{"2 violation(s)", "1 analyzer(s) failed", "[lint] a.go:4:1", "new problem", "... and 1 more", "[broken] analyzer failed", "gt analyze"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Prompt() missing %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "old problem") {
+		t.Errorf("Prompt() includes finding on unchanged line:\n%s", prompt)
+	}
+}
+
+func TestRunCleanAndUncommitted(t *testing.T) {
+	dir := setupRepo(t)
+	// Uncommitted edits count as part of the diff.
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hello\nTODO\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	diff, err := LoadDiff(dir, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &Config{Analyzers: []Analyzer{{Name: "todo", Cmd: `grep -n TODO {files} | sed 's/^/notes.txt:/' | cut -d: -f1,2,3`, Files: []string{"*.txt"}}}}
+	report := Run(context.Background(), cfg, dir, diff)
+	if got := report.Blocking(); len(got) != 1 || got[0].File != "notes.txt" || got[0].Line != 2 {
+		t.Errorf("Blocking() = %+v", got)
+	}
+
+	clean := &Config{Analyzers: []Analyzer{{Name: "ok", Cmd: "true"}}}
+	if r := Run(context.Background(), clean, dir, diff); !r.Passed() {
+		t.Errorf("clean report should pass: %+v", r)
+	}
+}
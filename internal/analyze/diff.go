@@ -0,0 +1,142 @@
+package analyze
+
+import (
+	"bufio"
+	"fmt"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Diff is the set of lines a branch added or changed relative to its base.
+type Diff struct {
+	Base  string // Merge base commit
+	Files []string
+	lines map[string][][2]int // file → [start, end] ranges of new-side lines
+}
+
+// LoadDiff diffs the working tree in dir against its merge base with base
+// (e.g. origin/main), so both committed and uncommitted changes count.
+func LoadDiff(dir, base string) (*Diff, error) {
+	mb, err := gitOutput(dir, "merge-base", base, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("finding merge base with %s: %w", base, err)
+	}
+	mb = strings.TrimSpace(mb)
+	out, err := gitOutput(dir, "diff", "-U0", "--no-color", "--no-ext-diff", "--diff-filter=d", mb)
+	if err != nil {
+		return nil, fmt.Errorf("diffing against %s: %w", base, err)
+	}
+	d := ParseDiff(out)
+	d.Base = mb
+	return d, nil
+}
+
+// ParseDiff reads unified diff output (ideally -U0) into a Diff.
+func ParseDiff(out string) *Diff {
+	d := &Diff{lines: map[string][][2]int{}}
+	var file string
+	sc := bufio.NewScanner(strings.NewReader(out))
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = ""
+			if name := strings.TrimPrefix(line, "+++ "); name != "/dev/null" {
+				file = strings.TrimPrefix(name, "b/")
+				if _, ok := d.lines[file]; !ok {
+					d.lines[file] = nil
+					d.Files = append(d.Files, file)
+				}
+			}
+		case strings.HasPrefix(line, "@@ ") && file != "":
+			if start, count, ok := parseHunkHeader(line); ok && count > 0 {
+				d.lines[file] = append(d.lines[file], [2]int{start, start + count - 1})
+			}
+		}
+	}
+	return d
+}
+
+// parseHunkHeader returns the new-side start and length of "@@ -a,b +c,d @@".
+func parseHunkHeader(line string) (start, count int, ok bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, false
+	}
+	spec := strings.TrimPrefix(fields[2], "+")
+	count = 1
+	if i := strings.IndexByte(spec, ','); i >= 0 {
+		n, err := strconv.Atoi(spec[i+1:])
+		if err != nil {
+			return 0, 0, false
+		}
+		count = n
+		spec = spec[:i]
+	}
+	start, err := strconv.Atoi(spec)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, count, true
+}
+
+// Touches reports whether the diff changed file. It is true for a file
+// that appears in the diff even if only lines were removed.
+func (d *Diff) Touches(file string) bool {
+	_, ok := d.lines[file]
+	return ok
+}
+
+// Changed reports whether line of file was added or changed. Line 0 means
+// "anywhere in the file".
+func (d *Diff) Changed(file string, line int) bool {
+	ranges, ok := d.lines[file]
+	if !ok {
+		return false
+	}
+	if line <= 0 {
+		return true
+	}
+	for _, r := range ranges {
+		if line >= r[0] && line <= r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// Match returns the changed files matching any of patterns. Patterns are
+// matched against the full path and the base name; no patterns matches all.
+func (d *Diff) Match(patterns []string) []string {
+	if len(patterns) == 0 {
+		return append([]string(nil), d.Files...)
+	}
+	var out []string
+	for _, f := range d.Files {
+		for _, p := range patterns {
+			full, _ := path.Match(p, f)
+			base, _ := path.Match(p, path.Base(f))
+			if full || base {
+				out = append(out, f)
+				break
+			}
+		}
+	}
+	return out
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
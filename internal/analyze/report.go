@@ -0,0 +1,82 @@
+package analyze
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Blocking returns the violations that fail the gate.
+func (r *Report) Blocking() []Violation {
+	var out []Violation
+	for _, res := range r.Results {
+		if !res.WarnOnly {
+			out = append(out, res.Violations...)
+		}
+	}
+	return out
+}
+
+// Warnings returns violations from warn-only analyzers.
+func (r *Report) Warnings() []Violation {
+	var out []Violation
+	for _, res := range r.Results {
+		if res.WarnOnly {
+			out = append(out, res.Violations...)
+		}
+	}
+	return out
+}
+
+// Failures returns blocking analyzers that errored without findings.
+func (r *Report) Failures() []Result {
+	var out []Result
+	for _, res := range r.Results {
+		if res.Error != "" && !res.WarnOnly {
+			out = append(out, res)
+		}
+	}
+	return out
+}
+
+// Passed reports whether the gate lets the submission through.
+func (r *Report) Passed() bool {
+	return len(r.Blocking()) == 0 && len(r.Failures()) == 0
+}
+
+// Prompt renders the fix-it prompt shown to the polecat when the gate
+// fails. At most max violations are listed.
+func (r *Report) Prompt(max int) string {
+	blocking, failures := r.Blocking(), r.Failures()
+	var b strings.Builder
+	fmt.Fprintf(&b, "STATIC ANALYSIS: %d violation(s) in your changes", len(blocking))
+	if len(failures) > 0 {
+		fmt.Fprintf(&b, ", %d analyzer(s) failed", len(failures))
+	}
+	b.WriteString("\n")
+	if short := r.Base; short != "" {
+		if len(short) > 8 {
+			short = short[:8]
+		}
+		fmt.Fprintf(&b, "Checked %d changed file(s) since %s. Findings on unchanged lines are ignored.\n", r.Files, short)
+	}
+
+	for i, v := range blocking {
+		if i == max {
+			fmt.Fprintf(&b, "\n... and %d more\n", len(blocking)-max)
+			break
+		}
+		fmt.Fprintf(&b, "\n[%s] %s\n  %s\n", v.Analyzer, v.Location(), v.Message)
+	}
+	for _, f := range failures {
+		fmt.Fprintf(&b, "\n[%s] analyzer failed: %s\n", f.Analyzer, f.Error)
+		if f.Output != "" {
+			for _, line := range strings.Split(f.Output, "\n") {
+				fmt.Fprintf(&b, "  %s\n", line)
+			}
+		}
+	}
+
+	b.WriteString("\nFix each violation at the location shown (do not suppress the check),\n")
+	b.WriteString("commit, then run gt done again. Re-check first with: gt analyze\n")
+	return b.String()
+}
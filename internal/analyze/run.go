@@ -0,0 +1,182 @@
+package analyze
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxOutputLines bounds the raw output kept for an analyzer that failed
+// without reporting parseable violations.
+const maxOutputLines = 30
+
+// Violation is one finding on a changed line.
+type Violation struct {
+	Analyzer string `json:"analyzer"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Col      int    `json:"col,omitempty"`
+	Message  string `json:"message"`
+	WarnOnly bool   `json:"warn_only,omitempty"`
+}
+
+// Location formats file:line[:col].
+func (v Violation) Location() string {
+	loc := v.File
+	if v.Line > 0 {
+		loc += ":" + strconv.Itoa(v.Line)
+		if v.Col > 0 {
+			loc += ":" + strconv.Itoa(v.Col)
+		}
+	}
+	return loc
+}
+
+// Result is the outcome of one analyzer.
+type Result struct {
+	Analyzer   string      `json:"analyzer"`
+	Skipped    string      `json:"skipped,omitempty"` // Why the analyzer didn't run
+	Violations []Violation `json:"violations,omitempty"`
+	Ignored    int         `json:"ignored,omitempty"` // Findings outside the diff
+	Error      string      `json:"error,omitempty"`   // Failed without parseable findings
+	Output     string      `json:"output,omitempty"`  // Output tail when Error is set
+	WarnOnly   bool        `json:"warn_only,omitempty"`
+}
+
+// Report is the outcome of every analyzer against one diff.
+type Report struct {
+	Base    string   `json:"base"`
+	Files   int      `json:"files"`
+	Results []Result `json:"results"`
+}
+
+// Run runs each analyzer in dir and keeps the findings on lines in diff.
+// Analyzer failures are reported in the Result, not returned.
+func Run(ctx context.Context, cfg *Config, dir string, diff *Diff) *Report {
+	report := &Report{Base: diff.Base, Files: len(diff.Files)}
+	if cfg == nil {
+		return report
+	}
+	for _, a := range cfg.Analyzers {
+		report.Results = append(report.Results, runOne(ctx, a, dir, diff))
+	}
+	return report
+}
+
+func runOne(ctx context.Context, a Analyzer, dir string, diff *Diff) Result {
+	res := Result{Analyzer: a.Name, WarnOnly: a.WarnOnly}
+	a, err := a.Resolve()
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	files := diff.Match(a.Files)
+	if len(files) == 0 {
+		res.Skipped = "no matching changed files"
+		return res
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.GetTimeout())
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", expandFiles(a.Cmd, files))
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GT_DIFF_BASE="+diff.Base,
+		"GT_CHANGED_FILES="+strings.Join(files, "\n"),
+	)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	runErr := cmd.Run()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		res.Error = fmt.Sprintf("timed out after %s", a.GetTimeout())
+		return res
+	}
+	var exitErr *exec.ExitError
+	if errors.As(runErr, &exitErr) && exitErr.ExitCode() == 127 {
+		res.Skipped = "command not found: " + firstWord(a.Cmd)
+		return res
+	}
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		res.Error = runErr.Error()
+		return res
+	}
+
+	parsed := 0
+	for _, line := range strings.Split(out.String(), "\n") {
+		v, ok := parseViolation(line, dir)
+		if !ok {
+			continue
+		}
+		parsed++
+		if !diff.Changed(v.File, v.Line) {
+			res.Ignored++
+			continue
+		}
+		v.Analyzer, v.WarnOnly = a.Name, a.WarnOnly
+		res.Violations = append(res.Violations, v)
+	}
+	if runErr != nil && parsed == 0 {
+		res.Error = fmt.Sprintf("exited %d", exitErr.ExitCode())
+		res.Output = tail(out.String(), maxOutputLines)
+	}
+	return res
+}
+
+// violationRe matches file:line[:col]: message, the format shared by go vet,
+// staticcheck, gcc, eslint -f unix, ruff concise, and most custom scripts.
+var violationRe = regexp.MustCompile(`^(?:vet: )?([^\s:][^:]*):(\d+)(?::(\d+))?:\s*(.+)$`)
+
+func parseViolation(line, dir string) (Violation, bool) {
+	m := violationRe.FindStringSubmatch(strings.TrimSpace(line))
+	if m == nil {
+		return Violation{}, false
+	}
+	file := filepath.ToSlash(m[1])
+	if filepath.IsAbs(m[1]) {
+		rel, err := filepath.Rel(dir, m[1])
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return Violation{}, false
+		}
+		file = filepath.ToSlash(rel)
+	}
+	file = strings.TrimPrefix(file, "./")
+	ln, _ := strconv.Atoi(m[2])
+	col, _ := strconv.Atoi(m[3])
+	return Violation{File: file, Line: ln, Col: col, Message: strings.TrimSpace(m[4])}, true
+}
+
+// expandFiles replaces {files} with the shell-quoted file list.
+func expandFiles(cmd string, files []string) string {
+	if !strings.Contains(cmd, "{files}") {
+		return cmd
+	}
+	quoted := make([]string, len(files))
+	for i, f := range files {
+		quoted[i] = "'" + strings.ReplaceAll(f, "'", `'\''`) + "'"
+	}
+	return strings.ReplaceAll(cmd, "{files}", strings.Join(quoted, " "))
+}
+
+func firstWord(s string) string {
+	if f := strings.Fields(s); len(f) > 0 {
+		return f[0]
+	}
+	return s
+}
+
+func tail(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
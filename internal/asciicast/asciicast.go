@@ -0,0 +1,72 @@
+// Package asciicast writes terminal recordings in asciinema's asciicast v2
+// format: a JSON header line followed by one [time, "o", data] line per
+// chunk of output. Casts play back with asciinema play or the asciinema web
+// player, keeping the timing and colors that copied scrollback loses.
+package asciicast
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Header is the first line of a cast.
+type Header struct {
+	Version       int               `json:"version"`
+	Width         int               `json:"width"`
+	Height        int               `json:"height"`
+	Timestamp     int64             `json:"timestamp,omitempty"`
+	IdleTimeLimit float64           `json:"idle_time_limit,omitempty"`
+	Title         string            `json:"title,omitempty"`
+	Env           map[string]string `json:"env,omitempty"`
+}
+
+// Writer writes a cast. Output times are relative to the recording start
+// and must not go backwards; earlier times are clamped to the last one.
+type Writer struct {
+	w    io.Writer
+	last float64
+}
+
+// NewWriter writes h (as version 2) to w and returns a writer for the
+// output that follows.
+func NewWriter(w io.Writer, h Header) (*Writer, error) {
+	h.Version = 2
+	if h.Env == nil {
+		h.Env = map[string]string{"TERM": "xterm-256color", "SHELL": "/bin/bash"}
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Fprintf(w, "%s\n", data); err != nil {
+		return nil, err
+	}
+	return &Writer{w: w}, nil
+}
+
+// Output records data printed at offset t. Bare newlines are turned into
+// CRLF, as a terminal would receive them.
+func (cw *Writer) Output(t time.Duration, data string) error {
+	if data == "" {
+		return nil
+	}
+	secs := t.Seconds()
+	if secs < cw.last {
+		secs = cw.last
+	}
+	cw.last = secs
+	data = strings.ReplaceAll(strings.ReplaceAll(data, "\r\n", "\n"), "\n", "\r\n")
+	event, err := json.Marshal([]any{roundMicros(secs), "o", data})
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(cw.w, "%s\n", event)
+	return err
+}
+
+func roundMicros(secs float64) float64 {
+	return float64(int64(secs*1e6+0.5)) / 1e6
+}
@@ -0,0 +1,52 @@
+package asciicast
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Header{Width: 80, Height: 24, Title: "demo", IdleTimeLimit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Output(1500*time.Millisecond, "hello\nworld\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Output(time.Second, "late"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Output(3*time.Second, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want header + 2 events:\n%s", len(lines), buf.String())
+	}
+	var h Header
+	if err := json.Unmarshal([]byte(lines[0]), &h); err != nil {
+		t.Fatal(err)
+	}
+	if h.Version != 2 || h.Width != 80 || h.Title != "demo" || h.Env["TERM"] == "" {
+		t.Errorf("header = %+v", h)
+	}
+
+	var ev []any
+	if err := json.Unmarshal([]byte(lines[1]), &ev); err != nil {
+		t.Fatal(err)
+	}
+	if ev[0] != 1.5 || ev[1] != "o" || ev[2] != "hello\r\nworld\r\n" {
+		t.Errorf("event = %v", ev)
+	}
+	if err := json.Unmarshal([]byte(lines[2]), &ev); err != nil {
+		t.Fatal(err)
+	}
+	if ev[0] != 1.5 {
+		t.Errorf("out-of-order event at %v, want clamped to 1.5", ev[0])
+	}
+}
@@ -0,0 +1,200 @@
+// Package atrest encrypts mail bodies and agent transcripts at rest, for
+// towns that run agents on code whose conversations must not sit on disk in
+// the clear.
+//
+// Data is sealed with NaCl secretbox (XSalsa20-Poly1305) under a 256-bit
+// town key kept in the OS keychain (macOS Keychain, or the Secret Service
+// via secret-tool on Linux). Sealed data names the key it was sealed with,
+// so gt commands open it transparently for any operator whose keychain
+// holds that key, and fail closed for everyone else.
+//
+// Mail subjects, senders and labels stay in the clear: routing, threading
+// and protocol handlers need them without a key.
+package atrest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// DefaultSealAfter is how long a transcript must be untouched before it is
+// sealed, so the session writing it has finished with it.
+const DefaultSealAfter = time.Hour
+
+// Config is the encryption section of the town's settings/config.json.
+//
+//	"encryption": {
+//	  "key_id": "gastown-3f9a1c2e",
+//	  "mail": true,
+//	  "transcripts": true,
+//	  "seal_after": "1h"
+//	}
+//
+// gt crypt init creates the key and fills in key_id.
+type Config struct {
+	// KeyID names the town key in the keychain.
+	KeyID string `json:"key_id"`
+	// Mail seals the body of every message sent in the town.
+	Mail bool `json:"mail,omitempty"`
+	// Transcripts seals agent transcripts once they are idle.
+	Transcripts bool `json:"transcripts,omitempty"`
+	// SealAfter is how long a transcript must be idle before it is sealed;
+	// DefaultSealAfter if empty.
+	SealAfter string `json:"seal_after,omitempty"`
+}
+
+// SealsMail reports whether mail bodies are sealed.
+func (c *Config) SealsMail() bool {
+	return c != nil && c.Mail && c.KeyID != ""
+}
+
+// SealsTranscripts reports whether transcripts are sealed.
+func (c *Config) SealsTranscripts() bool {
+	return c != nil && c.Transcripts && c.KeyID != ""
+}
+
+// GetSealAfter returns SealAfter, or DefaultSealAfter if unset or invalid.
+func (c *Config) GetSealAfter() time.Duration {
+	if c != nil && c.SealAfter != "" {
+		if d, err := time.ParseDuration(c.SealAfter); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultSealAfter
+}
+
+// Validate checks the config. A nil config is valid (nothing sealed).
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if (c.Mail || c.Transcripts) && c.KeyID == "" {
+		return fmt.Errorf("key_id is required (run gt crypt init)")
+	}
+	if c.KeyID != "" && !keyIDRe.MatchString(c.KeyID) {
+		return fmt.Errorf("invalid key_id %q", c.KeyID)
+	}
+	if c.SealAfter != "" {
+		if d, err := time.ParseDuration(c.SealAfter); err != nil || d <= 0 {
+			return fmt.Errorf("invalid seal_after %q", c.SealAfter)
+		}
+	}
+	return nil
+}
+
+var keyIDRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// unsafeKeyChars matches characters not allowed in a key ID.
+var unsafeKeyChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// ErrNoKey is returned when the key sealed data names isn't available.
+var ErrNoKey = errors.New("encryption key not available")
+
+const (
+	keySize   = 32
+	nonceSize = 24
+
+	// stringPrefix starts a sealed string: gt-sealed:v1:<key-id>:<base64>.
+	stringPrefix = "gt-sealed:v1:"
+	// fileMagic starts a sealed file's header line: GTSEALED v1 <key-id>.
+	fileMagic = "GTSEALED v1 "
+)
+
+// IsSealed reports whether s is a sealed string.
+func IsSealed(s string) bool {
+	return strings.HasPrefix(s, stringPrefix)
+}
+
+// SealString seals s under the key keyID names.
+func SealString(keyID, s string) (string, error) {
+	box, err := seal(keyID, []byte(s))
+	if err != nil {
+		return "", err
+	}
+	return stringPrefix + keyID + ":" + base64.StdEncoding.EncodeToString(box), nil
+}
+
+// OpenString opens a sealed string. Strings that aren't sealed are returned
+// as they are, so callers need not know whether sealing was on.
+func OpenString(s string) (string, error) {
+	if !IsSealed(s) {
+		return s, nil
+	}
+	keyID, encoded, ok := strings.Cut(strings.TrimPrefix(s, stringPrefix), ":")
+	if !ok {
+		return "", fmt.Errorf("malformed sealed string")
+	}
+	box, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", fmt.Errorf("malformed sealed string: %w", err)
+	}
+	plain, err := open(keyID, box)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// SealBytes seals data as the contents of a sealed file.
+func SealBytes(keyID string, data []byte) ([]byte, error) {
+	box, err := seal(keyID, data)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(fileMagic+keyID+"\n"), box...), nil
+}
+
+// IsSealedBytes reports whether data is the contents of a sealed file.
+func IsSealedBytes(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(fileMagic))
+}
+
+// OpenBytes opens the contents of a sealed file.
+func OpenBytes(data []byte) ([]byte, error) {
+	if !IsSealedBytes(data) {
+		return nil, fmt.Errorf("not a sealed file")
+	}
+	header, box, ok := bytes.Cut(data, []byte("\n"))
+	if !ok {
+		return nil, fmt.Errorf("malformed sealed file")
+	}
+	return open(strings.TrimPrefix(string(header), fileMagic), box)
+}
+
+func seal(keyID string, plain []byte) ([]byte, error) {
+	key, err := LoadKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [nonceSize]byte
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+	return secretbox.Seal(nonce[:], plain, &nonce, key), nil
+}
+
+func open(keyID string, box []byte) ([]byte, error) {
+	if len(box) < nonceSize+secretbox.Overhead {
+		return nil, fmt.Errorf("sealed data is truncated")
+	}
+	key, err := LoadKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [nonceSize]byte
+	copy(nonce[:], box[:nonceSize])
+	plain, ok := secretbox.Open(nil, box[nonceSize:], &nonce, key)
+	if !ok {
+		return nil, fmt.Errorf("sealed data failed authentication (wrong key %s or tampered)", keyID)
+	}
+	return plain, nil
+}
@@ -0,0 +1,189 @@
+package atrest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeKeychain replaces the OS keychain with a map for the test.
+func fakeKeychain(t *testing.T) map[string]string {
+	t.Helper()
+	store := make(map[string]string)
+	origGet, origSet := keychainGetFn, keychainSetFn
+	keychainGetFn = func(keyID string) (string, error) {
+		if s, ok := store[keyID]; ok {
+			return s, nil
+		}
+		return "", errors.New("no key stored")
+	}
+	keychainSetFn = func(keyID, secret string) error {
+		store[keyID] = secret
+		return nil
+	}
+	t.Setenv(EnvKey, "")
+	t.Cleanup(func() {
+		keychainGetFn, keychainSetFn = origGet, origSet
+		keyMu.Lock()
+		keyCache = make(map[string]*[keySize]byte)
+		keyMu.Unlock()
+	})
+	return store
+}
+
+func TestSealStringRoundTrip(t *testing.T) {
+	fakeKeychain(t)
+	if _, err := GenerateKey("town-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	sealed, err := SealString("town-1", "the launch codes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealed(sealed) || strings.Contains(sealed, "launch") {
+		t.Fatalf("sealed string leaks plaintext or lacks prefix: %q", sealed)
+	}
+	got, err := OpenString(sealed)
+	if err != nil || got != "the launch codes" {
+		t.Fatalf("OpenString() = %q, %v", got, err)
+	}
+	if got, err := OpenString("plain body"); err != nil || got != "plain body" {
+		t.Errorf("OpenString(plain) = %q, %v; want it unchanged", got, err)
+	}
+}
+
+func TestOpenWithoutKey(t *testing.T) {
+	store := fakeKeychain(t)
+	if _, err := GenerateKey("town-1"); err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := SealString("town-1", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another operator: no key in the keychain, nothing cached.
+	delete(store, "town-1")
+	keyMu.Lock()
+	keyCache = make(map[string]*[keySize]byte)
+	keyMu.Unlock()
+	if _, err := OpenString(sealed); !errors.Is(err, ErrNoKey) {
+		t.Errorf("OpenString without key: err = %v, want ErrNoKey", err)
+	}
+
+	// A different key under the same ID fails authentication.
+	if _, err := GenerateKey("town-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenString(sealed); err == nil {
+		t.Error("OpenString with the wrong key succeeded")
+	}
+}
+
+func TestEnvKey(t *testing.T) {
+	fakeKeychain(t)
+	encoded, err := GenerateKey("town-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := SealString("town-1", "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyMu.Lock()
+	keyCache = make(map[string]*[keySize]byte)
+	keyMu.Unlock()
+	keychainGetFn = func(string) (string, error) { return "", ErrNoKeychain }
+
+	t.Setenv(EnvKey, "other-1:AAAA,"+encoded)
+	if got, err := OpenString(sealed); err != nil || got != "hello" {
+		t.Errorf("OpenString with %s = %q, %v", EnvKey, got, err)
+	}
+}
+
+func TestSealFile(t *testing.T) {
+	fakeKeychain(t)
+	if _, err := GenerateKey("town-1"); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "s.jsonl")
+	if err := os.WriteFile(path, []byte("line1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SealFile("town-1", path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("plaintext should be removed after sealing")
+	}
+
+	// The session wrote more after sealing: the next seal appends.
+	if err := os.WriteFile(path, []byte("line2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SealFile("town-1", path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadFile(path + SealedSuffix)
+	if err != nil || string(got) != "line1\nline2\n" {
+		t.Fatalf("ReadFile(sealed) = %q, %v", got, err)
+	}
+
+	restored, err := UnsealFile(path + SealedSuffix)
+	if err != nil || restored != path {
+		t.Fatalf("UnsealFile() = %q, %v", restored, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "line1\nline2\n" {
+		t.Errorf("restored file = %q", data)
+	}
+	if _, err := os.Stat(path + SealedSuffix); !os.IsNotExist(err) {
+		t.Error("sealed copy should be removed after unsealing")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"off", &Config{}, false},
+		{"mail", &Config{KeyID: "town-1", Mail: true}, false},
+		{"no key", &Config{Transcripts: true}, true},
+		{"bad key id", &Config{KeyID: "town 1", Mail: true}, true},
+		{"bad seal_after", &Config{KeyID: "town-1", Transcripts: true, SealAfter: "soon"}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+	if !(&Config{KeyID: "k", Mail: true}).SealsMail() || (&Config{Mail: true}).SealsMail() {
+		t.Error("SealsMail should need both mail and a key_id")
+	}
+}
+
+func TestKeychainCommand(t *testing.T) {
+	name, args, err := keychainCommand("darwin", "get", "town-1")
+	if err != nil || name != "security" || args[0] != "find-generic-password" {
+		t.Errorf("darwin get = %s %v, %v", name, args, err)
+	}
+	name, args, err = keychainCommand("linux", "set", "town-1")
+	if err != nil || name != "secret-tool" || args[0] != "store" {
+		t.Errorf("linux set = %s %v, %v", name, args, err)
+	}
+	if _, _, err := keychainCommand("windows", "get", "town-1"); !errors.Is(err, ErrNoKeychain) {
+		t.Errorf("windows: err = %v, want ErrNoKeychain", err)
+	}
+}
+
+func TestNewKeyID(t *testing.T) {
+	id := NewKeyID("my town/ops")
+	if !keyIDRe.MatchString(id) || !strings.HasPrefix(id, "my-town-ops-") {
+		t.Errorf("NewKeyID() = %q", id)
+	}
+}
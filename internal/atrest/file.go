@@ -0,0 +1,84 @@
+package atrest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SealedSuffix is appended to the name of a sealed file.
+const SealedSuffix = ".sealed"
+
+// SealFile seals the file at path into path+SealedSuffix and removes the
+// plaintext. If a sealed copy already exists (a session appended to its
+// transcript after it was sealed), the new lines are appended to it.
+func SealFile(keyID, path string) error {
+	plain, err := os.ReadFile(path) //nolint:gosec // G304: caller picks transcript paths
+	if err != nil {
+		return err
+	}
+	sealedPath := path + SealedSuffix
+	if existing, err := os.ReadFile(sealedPath); err == nil { //nolint:gosec // G304: derived from path
+		earlier, err := OpenBytes(existing)
+		if err != nil {
+			return fmt.Errorf("opening %s to append: %w", sealedPath, err)
+		}
+		plain = append(earlier, plain...)
+	}
+	data, err := SealBytes(keyID, plain)
+	if err != nil {
+		return err
+	}
+	tmp := sealedPath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, sealedPath); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Remove(path)
+}
+
+// UnsealFile restores a sealed file's plaintext next to it (the path
+// without SealedSuffix) and removes the sealed copy, e.g. so a runtime can
+// resume the session it belongs to.
+func UnsealFile(sealedPath string) (string, error) {
+	if !strings.HasSuffix(sealedPath, SealedSuffix) {
+		return "", fmt.Errorf("%s is not a sealed file", sealedPath)
+	}
+	plain, err := ReadFile(sealedPath)
+	if err != nil {
+		return "", err
+	}
+	path := strings.TrimSuffix(sealedPath, SealedSuffix)
+	if _, err := os.Stat(path); err == nil {
+		// The session went on writing after it was sealed: keep both parts.
+		tail, err := os.ReadFile(path) //nolint:gosec // G304: derived from sealedPath
+		if err != nil {
+			return "", err
+		}
+		plain = append(plain, tail...)
+	}
+	if err := os.WriteFile(path, plain, 0600); err != nil {
+		return "", err
+	}
+	return path, os.Remove(sealedPath)
+}
+
+// ReadFile reads a file, opening it if it is sealed.
+func ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: caller picks the path
+	if err != nil {
+		return nil, err
+	}
+	if !IsSealedBytes(data) {
+		return data, nil
+	}
+	plain, err := OpenBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return plain, nil
+}
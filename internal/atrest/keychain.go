@@ -0,0 +1,199 @@
+package atrest
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// EnvKey supplies keys without a keychain (headless hosts, CI), as
+// comma-separated <key-id>:<base64-key> pairs. It takes precedence over the
+// keychain.
+const EnvKey = "GT_ATREST_KEY"
+
+// keychainService is the service name keys are stored under.
+const keychainService = "gastown-at-rest"
+
+// ErrNoKeychain is returned on platforms without a supported keychain.
+var ErrNoKeychain = errors.New("no supported OS keychain (macOS security or Linux secret-tool)")
+
+var (
+	// keychainGetFn is a seam for tests. Production uses systemKeychainGet.
+	keychainGetFn = systemKeychainGet
+
+	// keychainSetFn is a seam for tests. Production uses systemKeychainSet.
+	keychainSetFn = systemKeychainSet
+)
+
+var (
+	keyMu    sync.Mutex
+	keyCache = make(map[string]*[keySize]byte)
+)
+
+// NewKeyID returns a fresh key ID for a town: its name and a random suffix,
+// so a new key never collides with one still needed to open old data.
+func NewKeyID(townName string) string {
+	var b [4]byte
+	_, _ = io.ReadFull(rand.Reader, b[:])
+	name := strings.Trim(unsafeKeyChars.ReplaceAllString(townName, "-"), "-")
+	if name == "" {
+		name = "town"
+	}
+	return name + "-" + hex.EncodeToString(b[:])
+}
+
+// GenerateKey creates a random key for keyID and stores it in the
+// keychain. It returns the key encoded for EnvKey, for hosts where storing
+// failed or for handing to another operator's keychain.
+func GenerateKey(keyID string) (encoded string, err error) {
+	var key [keySize]byte
+	if _, err := io.ReadFull(rand.Reader, key[:]); err != nil {
+		return "", fmt.Errorf("generating key: %w", err)
+	}
+	secret := base64.StdEncoding.EncodeToString(key[:])
+	encoded = keyID + ":" + secret
+	if err := keychainSetFn(keyID, secret); err != nil {
+		return encoded, fmt.Errorf("storing key in keychain: %w", err)
+	}
+	keyMu.Lock()
+	keyCache[keyID] = &key
+	keyMu.Unlock()
+	return encoded, nil
+}
+
+// ImportKey stores a key encoded as for EnvKey in this host's keychain.
+func ImportKey(encoded string) (keyID string, err error) {
+	keyID, secret, ok := strings.Cut(strings.TrimSpace(encoded), ":")
+	if !ok || !keyIDRe.MatchString(keyID) {
+		return "", fmt.Errorf("want <key-id>:<base64-key>")
+	}
+	if _, err := decodeKey(secret); err != nil {
+		return "", err
+	}
+	if err := keychainSetFn(keyID, secret); err != nil {
+		return "", fmt.Errorf("storing key in keychain: %w", err)
+	}
+	return keyID, nil
+}
+
+// LoadKey returns the key keyID names, from EnvKey or the keychain. Keys
+// are cached for the life of the process.
+func LoadKey(keyID string) (*[keySize]byte, error) {
+	keyMu.Lock()
+	defer keyMu.Unlock()
+	if key := keyCache[keyID]; key != nil {
+		return key, nil
+	}
+
+	secret := envKey(keyID)
+	if secret == "" {
+		var err error
+		if secret, err = keychainGetFn(keyID); err != nil {
+			return nil, fmt.Errorf("%w: %s (%v)", ErrNoKey, keyID, err)
+		}
+	}
+	key, err := decodeKey(secret)
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", keyID, err)
+	}
+	keyCache[keyID] = key
+	return key, nil
+}
+
+// HasKey reports whether the key keyID names is available here.
+func HasKey(keyID string) bool {
+	_, err := LoadKey(keyID)
+	return err == nil
+}
+
+func envKey(keyID string) string {
+	for _, pair := range strings.Split(os.Getenv(EnvKey), ",") {
+		if id, secret, ok := strings.Cut(strings.TrimSpace(pair), ":"); ok && id == keyID {
+			return secret
+		}
+	}
+	return ""
+}
+
+func decodeKey(secret string) (*[keySize]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(secret))
+	if err != nil || len(raw) != keySize {
+		return nil, fmt.Errorf("malformed key (want %d bytes, base64)", keySize)
+	}
+	var key [keySize]byte
+	copy(key[:], raw)
+	return &key, nil
+}
+
+// Keychain names the keychain this platform uses, or "" if none.
+func Keychain() string {
+	name, _, err := keychainCommand(runtime.GOOS, "get", "")
+	if err != nil {
+		return ""
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return ""
+	}
+	return name
+}
+
+func systemKeychainGet(keyID string) (string, error) {
+	name, args, err := keychainCommand(runtime.GOOS, "get", keyID)
+	if err != nil {
+		return "", err
+	}
+	out, err := exec.Command(name, args...).Output() //nolint:gosec // G204: fixed keychain binary
+	if err != nil {
+		return "", fmt.Errorf("%s: %v", name, err)
+	}
+	secret := strings.TrimSpace(string(out))
+	if secret == "" {
+		return "", fmt.Errorf("%s: no key stored", name)
+	}
+	return secret, nil
+}
+
+func systemKeychainSet(keyID, secret string) error {
+	name, args, err := keychainCommand(runtime.GOOS, "set", keyID)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(name, args...) //nolint:gosec // G204: fixed keychain binary
+	if name == "security" {
+		// security only reads a password from argv or a terminal prompt.
+		cmd.Args = append(cmd.Args, secret)
+	} else {
+		cmd.Stdin = strings.NewReader(secret)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %v: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// keychainCommand builds the keychain invocation for goos. For "set" on
+// macOS the secret is appended by the caller.
+func keychainCommand(goos, op, keyID string) (string, []string, error) {
+	switch goos {
+	case "darwin":
+		if op == "set" {
+			return "security", []string{"add-generic-password", "-U", "-s", keychainService, "-a", keyID, "-w"}, nil
+		}
+		return "security", []string{"find-generic-password", "-s", keychainService, "-a", keyID, "-w"}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if op == "set" {
+			return "secret-tool", []string{"store", "--label=Gas Town at-rest key " + keyID, "service", keychainService, "account", keyID}, nil
+		}
+		return "secret-tool", []string{"lookup", "service", keychainService, "account", keyID}, nil
+	default:
+		return "", nil, ErrNoKeychain
+	}
+}
@@ -0,0 +1,198 @@
+// Package auditlog records mutating operations in an append-only log.
+//
+// Every mutating gt command (and direct actions taken by deacon helpers)
+// appends one JSON line to <town>/logs/audit.jsonl: who ran it, what was run
+// with which arguments, when, and how it ended. Unlike the event bus
+// (.events.jsonl, curated and pruned by the feed daemon), this file is never
+// rewritten or truncated by Gas Town; rotation is left to the operator.
+package auditlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// Result values.
+const (
+	ResultOK     = "ok"
+	ResultError  = "error"
+	ResultDenied = "denied" // Rejected by the access policy before running
+)
+
+// Sources.
+const (
+	SourceGT     = "gt"
+	SourceDeacon = "deacon"
+)
+
+// maxArgLen bounds each recorded argument so mail bodies and prompts do not
+// bloat the log.
+const maxArgLen = 512
+
+// redacted replaces the values of sensitive arguments.
+const redacted = "[redacted]"
+
+// sensitiveNames are substrings marking a flag, setting, or variable name
+// whose value is a secret.
+var sensitiveNames = []string{"token", "secret", "password", "passwd", "apikey", "api-key", "api_key", "private-key", "private_key", "credential"}
+
+// Entry is one audited operation.
+type Entry struct {
+	Timestamp  time.Time `json:"ts"`
+	Source     string    `json:"source"`
+	Actor      string    `json:"actor"`              // Agent address or "overseer"
+	Operator   string    `json:"operator,omitempty"` // Human operator, when not an agent session
+	Command    string    `json:"command"`            // Command path, e.g. "rig remove"
+	Args       []string  `json:"args,omitempty"`
+	Cwd        string    `json:"cwd,omitempty"`
+	Result     string    `json:"result"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// Path returns the audit log for a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, "logs", "audit.jsonl")
+}
+
+// Record appends an entry. Timestamp defaults to now; secret arguments are
+// redacted and long ones truncated.
+func Record(townRoot string, e *Entry) error {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+	if e.Source == "" {
+		e.Source = SourceGT
+	}
+	redactArgs(e.Args)
+	for i, a := range e.Args {
+		if len(a) > maxArgLen {
+			e.Args[i] = a[:maxArgLen] + "…"
+		}
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encoding audit entry: %w", err)
+	}
+
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("acquiring audit log lock: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: audit log is readable by operators
+	if err != nil {
+		return fmt.Errorf("opening audit log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing audit entry: %w", err)
+	}
+	return f.Close()
+}
+
+// redactArgs replaces secret values in args, in place: "--token=x" and
+// "TOKEN=x" keep the name, and the argument after "--token" or after a bare
+// name such as "webhook.secret" (gt config set <key> <value>) is replaced.
+func redactArgs(args []string) {
+	for i := 0; i < len(args); i++ {
+		name, _, hasValue := strings.Cut(args[i], "=")
+		if !isSensitiveName(name) {
+			continue
+		}
+		if hasValue {
+			args[i] = name + "=" + redacted
+		} else if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			i++
+			args[i] = redacted
+		}
+	}
+}
+
+// isSensitiveName reports whether name, a flag or key without its value,
+// names a secret. Free text (anything with spaces) never does.
+func isSensitiveName(name string) bool {
+	if name == "" || strings.ContainsAny(name, " \t\n") {
+		return false
+	}
+	name = strings.ToLower(name)
+	for _, s := range sensitiveNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter selects entries from the log. Zero values match everything.
+type Filter struct {
+	Since   time.Time
+	Until   time.Time
+	Actor   string // Substring match against Actor or Operator
+	Command string // Prefix match against Command
+	Result  string
+}
+
+// Matches reports whether e passes the filter.
+func (f Filter) Matches(e Entry) bool {
+	if !f.Since.IsZero() && e.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Timestamp.After(f.Until) {
+		return false
+	}
+	if f.Actor != "" && !strings.Contains(e.Actor, f.Actor) && !strings.Contains(e.Operator, f.Actor) {
+		return false
+	}
+	if f.Command != "" && e.Command != f.Command && !strings.HasPrefix(e.Command, f.Command+" ") {
+		return false
+	}
+	if f.Result != "" && e.Result != f.Result {
+		return false
+	}
+	return true
+}
+
+// Read returns matching entries oldest first. Malformed lines are skipped.
+// A missing log yields no entries.
+func Read(townRoot string, filter Filter) ([]Entry, error) {
+	f, err := os.Open(Path(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening audit log: %w", err)
+	}
+	defer f.Close()
+
+	var out []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if filter.Matches(e) {
+			out = append(out, e)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return out, fmt.Errorf("reading audit log: %w", err)
+	}
+	return out, nil
+}
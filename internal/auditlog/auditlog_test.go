@@ -0,0 +1,123 @@
+package auditlog
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordAndRead(t *testing.T) {
+	town := t.TempDir()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	entries := []*Entry{
+		{Timestamp: base, Actor: "overseer", Operator: "alice", Command: "rig remove", Args: []string{"gastown"}, Result: ResultOK},
+		{Timestamp: base.Add(time.Hour), Actor: "gastown/witness", Command: "nudge", Args: []string{"gastown/Toast", strings.Repeat("x", 2000)}, Result: ResultOK},
+		{Timestamp: base.Add(2 * time.Hour), Actor: "overseer", Operator: "bob", Command: "rig park", Result: ResultDenied, Error: "access denied"},
+		{Timestamp: base.Add(3 * time.Hour), Source: SourceDeacon, Actor: "deacon", Command: "unhook", Args: []string{"gt-1"}, Result: ResultOK},
+	}
+	for _, e := range entries {
+		if err := Record(town, e); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	all, err := Read(town, Filter{})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("Read() returned %d entries, want 4", len(all))
+	}
+	if all[0].Source != SourceGT {
+		t.Errorf("default source = %q, want gt", all[0].Source)
+	}
+	if got := len(all[1].Args[1]); got > maxArgLen+len("…") {
+		t.Errorf("long arg not truncated: %d bytes", got)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   int
+	}{
+		{"by operator", Filter{Actor: "alice"}, 1},
+		{"by command prefix", Filter{Command: "rig"}, 2},
+		{"command prefix is word-based", Filter{Command: "ri"}, 0},
+		{"denied only", Filter{Result: ResultDenied}, 1},
+		{"since", Filter{Since: base.Add(90 * time.Minute)}, 2},
+		{"until", Filter{Until: base.Add(30 * time.Minute)}, 1},
+	}
+	for _, tt := range tests {
+		got, err := Read(town, tt.filter)
+		if err != nil {
+			t.Fatalf("%s: Read() error = %v", tt.name, err)
+		}
+		if len(got) != tt.want {
+			t.Errorf("%s: got %d entries, want %d", tt.name, len(got), tt.want)
+		}
+	}
+}
+
+func TestReadMissingAndMalformed(t *testing.T) {
+	town := t.TempDir()
+	if got, err := Read(town, Filter{}); err != nil || len(got) != 0 {
+		t.Fatalf("Read() on empty town = %v, %v", got, err)
+	}
+
+	if err := Record(town, &Entry{Command: "sling", Result: ResultOK}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(Path(town), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("{torn\n")
+	_ = f.Close()
+	if err := Record(town, &Entry{Command: "done", Result: ResultOK}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Read(town, Filter{})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Command != "sling" || got[1].Command != "done" {
+		t.Errorf("Read() = %+v, want sling and done", got)
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--token", "abc", "gastown"}, "--token [redacted] gastown"},
+		{[]string{"--api-key=abc", "--name=web"}, "--api-key=[redacted] --name=web"},
+		{[]string{"GITHUB_TOKEN=abc"}, "GITHUB_TOKEN=[redacted]"},
+		{[]string{"webhook.secret", "abc"}, "webhook.secret [redacted]"},
+		{[]string{"--password", "--force"}, "--password --force"},
+		{[]string{"mayor/", "rotate the password tomorrow", "ok"}, "mayor/ rotate the password tomorrow ok"},
+		{[]string{"--public-key", "AAAA"}, "--public-key AAAA"},
+	}
+	for _, tt := range tests {
+		args := append([]string(nil), tt.args...)
+		redactArgs(args)
+		if got := strings.Join(args, " "); got != tt.want {
+			t.Errorf("redactArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+
+	town := t.TempDir()
+	if err := Record(town, &Entry{Command: "config set", Args: []string{"--secret=hunter2"}, Result: ResultOK}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(Path(town))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Errorf("secret written to the audit log: %s", data)
+	}
+}
@@ -0,0 +1,136 @@
+// Package beadlink implements typed links between beads: one bead
+// duplicates, relates to, was caused by or follows up on another. Unlike
+// dependencies, links never block work; they record how beads relate so
+// duplicate detection, the dashboard and analytics can use it.
+//
+// A link is stored as a label on both beads, the second with the inverse
+// type, so each bead carries all of its links:
+//
+//	gt-b: link:duplicates:gt-a
+//	gt-a: link:duplicated-by:gt-b
+package beadlink
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LabelPrefix starts every link label.
+const LabelPrefix = "link:"
+
+// Link types. Each has an inverse, stored on the other bead.
+const (
+	Duplicates   = "duplicates"
+	DuplicatedBy = "duplicated-by"
+	RelatesTo    = "relates-to"
+	CausedBy     = "caused-by"
+	Causes       = "causes"
+	FollowUpOf   = "follow-up-of"
+	FollowedUpBy = "followed-up-by"
+)
+
+// types lists the link types in display order, each with its inverse and
+// how it reads in a sentence.
+var types = []struct{ name, inverse, phrase string }{
+	{Duplicates, DuplicatedBy, "duplicates"},
+	{DuplicatedBy, Duplicates, "duplicated by"},
+	{CausedBy, Causes, "caused by"},
+	{Causes, CausedBy, "causes"},
+	{FollowUpOf, FollowedUpBy, "follow-up of"},
+	{FollowedUpBy, FollowUpOf, "followed up by"},
+	{RelatesTo, RelatesTo, "relates to"},
+}
+
+// Types returns the link types.
+func Types() []string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = t.name
+	}
+	return names
+}
+
+func lookup(typ string) int {
+	for i, t := range types {
+		if t.name == typ {
+			return i
+		}
+	}
+	return -1
+}
+
+// Validate returns an error unless typ is a link type.
+func Validate(typ string) error {
+	if lookup(typ) < 0 {
+		return fmt.Errorf("unknown link type %q (want one of %s)", typ, strings.Join(Types(), ", "))
+	}
+	return nil
+}
+
+// Inverse returns the type the other bead of a typ link carries.
+func Inverse(typ string) string {
+	if i := lookup(typ); i >= 0 {
+		return types[i].inverse
+	}
+	return ""
+}
+
+// Phrase returns how typ reads in a sentence ("caused by").
+func Phrase(typ string) string {
+	if i := lookup(typ); i >= 0 {
+		return types[i].phrase
+	}
+	return typ
+}
+
+// IsDuplicate reports whether typ says the two beads are the same work.
+func IsDuplicate(typ string) bool {
+	return typ == Duplicates || typ == DuplicatedBy
+}
+
+// Link is one typed link from a bead to Bead.
+type Link struct {
+	Type string `json:"type"`
+	Bead string `json:"bead"`
+}
+
+// Label returns the label that stores the link.
+func (l Link) Label() string {
+	return LabelPrefix + l.Type + ":" + l.Bead
+}
+
+// Reverse returns the link as the other bead stores it.
+func (l Link) Reverse(from string) Link {
+	return Link{Type: Inverse(l.Type), Bead: from}
+}
+
+// Parse reads a link label. Labels of unknown types are not links.
+func Parse(label string) (Link, bool) {
+	rest, ok := strings.CutPrefix(label, LabelPrefix)
+	if !ok {
+		return Link{}, false
+	}
+	typ, bead, ok := strings.Cut(rest, ":")
+	if !ok || bead == "" || lookup(typ) < 0 {
+		return Link{}, false
+	}
+	return Link{Type: typ, Bead: bead}, true
+}
+
+// FromLabels returns the links in a bead's labels, in display order.
+func FromLabels(labels []string) []Link {
+	var links []Link
+	for _, label := range labels {
+		if l, ok := Parse(label); ok {
+			links = append(links, l)
+		}
+	}
+	sort.SliceStable(links, func(i, j int) bool {
+		if a, b := lookup(links[i].Type), lookup(links[j].Type); a != b {
+			return a < b
+		}
+		return links[i].Bead < links[j].Bead
+	})
+	return links
+}
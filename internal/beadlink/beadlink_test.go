@@ -0,0 +1,44 @@
+package beadlink
+
+import "testing"
+
+func TestInverse(t *testing.T) {
+	for _, typ := range Types() {
+		inv := Inverse(typ)
+		if inv == "" || Inverse(inv) != typ {
+			t.Errorf("Inverse(%q) = %q, whose inverse is %q", typ, inv, Inverse(inv))
+		}
+	}
+	if Validate("blocks") == nil {
+		t.Error("blocks is a dependency, not a link type")
+	}
+}
+
+func TestParse(t *testing.T) {
+	l := Link{Type: CausedBy, Bead: "gt-abc"}
+	got, ok := Parse(l.Label())
+	if !ok || got != l {
+		t.Errorf("Parse(%q) = %+v, %v", l.Label(), got, ok)
+	}
+	if rev := l.Reverse("gt-xyz"); rev.Type != Causes || rev.Bead != "gt-xyz" {
+		t.Errorf("Reverse = %+v", rev)
+	}
+	for _, label := range []string{"gt:task", "link:blocks:gt-1", "link:duplicates:", "link:duplicates"} {
+		if _, ok := Parse(label); ok {
+			t.Errorf("Parse(%q) should not be a link", label)
+		}
+	}
+}
+
+func TestFromLabels(t *testing.T) {
+	links := FromLabels([]string{"gt:task", "link:relates-to:gt-2", "link:duplicates:gt-9", "link:relates-to:gt-1", "bead:hq-1"})
+	want := []Link{{Duplicates, "gt-9"}, {RelatesTo, "gt-1"}, {RelatesTo, "gt-2"}}
+	if len(links) != len(want) {
+		t.Fatalf("FromLabels = %+v, want %+v", links, want)
+	}
+	for i := range want {
+		if links[i] != want[i] {
+			t.Errorf("link %d = %+v, want %+v", i, links[i], want[i])
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/chaos"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/telemetry"
 )
@@ -394,10 +395,11 @@ func (b *Beads) Init(prefix string) error {
 // run executes a bd command and returns stdout.
 func (b *Beads) run(args ...string) (_ []byte, retErr error) {
 	start := time.Now()
-	// Declare buffers before defer so the closure captures them after cmd.Run.
-	var stdout, stderr bytes.Buffer
+	// Declare outputs before defer so the closure captures them after the call.
+	var stdout []byte
+	var stderr string
 	defer func() {
-		telemetry.RecordBDCall(context.Background(), args, float64(time.Since(start).Milliseconds()), retErr, stdout.Bytes(), stderr.String())
+		telemetry.RecordBDCall(context.Background(), args, float64(time.Since(start).Milliseconds()), retErr, stdout, stderr)
 	}()
 	// bd v0.59+ requires --flat for --json to produce JSON output on "list" commands.
 	// Without --flat, bd list --json silently returns human-readable tree format,
@@ -411,56 +413,46 @@ func (b *Beads) run(args ...string) (_ []byte, retErr error) {
 	if beadsDir == "" {
 		beadsDir = ResolveBeadsDir(b.workDir)
 	}
-	runEnv := append(b.buildRunEnv(), "BEADS_DIR="+beadsDir)
-	fullArgs := MaybePrependAllowStaleWithEnv(runEnv, args)
-
 	// Always explicitly set BEADS_DIR to prevent inherited env vars from
 	// causing prefix mismatches. Use explicit beadsDir if set, otherwise
 	// resolve from working directory.
-	cmd := exec.Command("bd", fullArgs...) //nolint:gosec // G204: bd is a trusted internal tool
-	cmd.Dir = b.workDir
-
-	cmd.Env = runEnv
-	cmd.Env = append(cmd.Env, telemetry.OTELEnvForSubprocess()...)
-
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
-
-	// If bd doesn't support --flat, retry without it. The retry is done here
-	// (not in callers like List) so that InjectFlatForListJSON doesn't re-add
-	// --flat on the retry path.
-	if err != nil && strings.Contains(stderr.String(), "unknown flag: --flat") {
-		retryArgs := make([]string, 0, len(fullArgs))
-		for _, a := range fullArgs {
-			if a != "--flat" {
-				retryArgs = append(retryArgs, a)
+	runEnv := append(b.buildRunEnv(), "BEADS_DIR="+beadsDir)
+	fullArgs := MaybePrependAllowStaleWithEnv(runEnv, args)
+	runEnv = append(runEnv, telemetry.OTELEnvForSubprocess()...)
+
+	// gt town chaos may be holding bd calls back to test recovery.
+	chaos.DelayBD(b.getTownRoot(), commandName(args))
+
+	var err error
+	stdout, stderr, err = Invoke(Invocation{Args: args, BeadsDir: beadsDir, Dir: b.workDir}, func() ([]byte, string, error) {
+		out, errOut, err := execBD(b.workDir, runEnv, fullArgs)
+		// If bd doesn't support --flat, retry without it. The retry is done here
+		// (not in callers like List) so that InjectFlatForListJSON doesn't re-add
+		// --flat on the retry path.
+		if err != nil && strings.Contains(errOut, "unknown flag: --flat") {
+			retryArgs := make([]string, 0, len(fullArgs))
+			for _, a := range fullArgs {
+				if a != "--flat" {
+					retryArgs = append(retryArgs, a)
+				}
 			}
+			out, errOut, err = execBD(b.workDir, runEnv, retryArgs)
 		}
-		stdout.Reset()
-		stderr.Reset()
-		cmd = exec.Command("bd", retryArgs...) //nolint:gosec // G204: bd is a trusted internal tool
-		cmd.Dir = b.workDir
-		cmd.Env = runEnv
-		cmd.Env = append(cmd.Env, telemetry.OTELEnvForSubprocess()...)
-		cmd.Stdout = &stdout
-		cmd.Stderr = &stderr
-		err = cmd.Run()
-	}
+		return out, errOut, err
+	})
 
 	if err != nil {
-		return nil, b.wrapError(err, stderr.String(), args)
+		return nil, b.wrapError(err, stderr, args)
 	}
 
 	// Handle bd exit code 0 bug: when issue not found,
 	// bd may exit 0 but write error to stderr with empty stdout.
 	// Detect this case and treat as error to avoid JSON parse failures.
-	if stdout.Len() == 0 && stderr.Len() > 0 {
-		return nil, b.wrapError(fmt.Errorf("command produced no output"), stderr.String(), args)
+	if len(stdout) == 0 && len(stderr) > 0 {
+		return nil, b.wrapError(fmt.Errorf("command produced no output"), stderr, args)
 	}
 
-	return stripStdoutWarnings(stdout.Bytes()), nil
+	return stripStdoutWarnings(stdout), nil
 }
 
 // runWithRouting executes a bd command without setting BEADS_DIR, allowing bd's
@@ -470,32 +462,34 @@ func (b *Beads) run(args ...string) (_ []byte, retErr error) {
 // See: sling_helpers.go verifyBeadExists/hookBeadWithRetry for the same pattern.
 func (b *Beads) runWithRouting(args ...string) (_ []byte, retErr error) { //nolint:unparam // mirrors run() signature for consistency
 	start := time.Now()
-	var stdout, stderr bytes.Buffer
+	var stdout []byte
+	var stderr string
 	defer func() {
-		telemetry.RecordBDCall(context.Background(), args, float64(time.Since(start).Milliseconds()), retErr, stdout.Bytes(), stderr.String())
+		telemetry.RecordBDCall(context.Background(), args, float64(time.Since(start).Milliseconds()), retErr, stdout, stderr)
 	}()
 	runEnv := b.buildRoutingEnv()
 	fullArgs := MaybePrependAllowStaleWithEnv(runEnv, args)
+	runEnv = append(runEnv, telemetry.OTELEnvForSubprocess()...)
 
-	cmd := exec.Command("bd", fullArgs...) //nolint:gosec // G204: bd is a trusted internal tool
-	cmd.Dir = b.workDir
-
-	cmd.Env = runEnv
-	cmd.Env = append(cmd.Env, telemetry.OTELEnvForSubprocess()...)
-
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	err := cmd.Run()
+	// Writes are serialized against the local database; bd may route the
+	// call elsewhere, but the local one is where gt's own writes contend.
+	beadsDir := b.beadsDir
+	if beadsDir == "" {
+		beadsDir = ResolveBeadsDir(b.workDir)
+	}
+	var err error
+	stdout, stderr, err = Invoke(Invocation{Args: args, BeadsDir: beadsDir, Dir: b.workDir}, func() ([]byte, string, error) {
+		return execBD(b.workDir, runEnv, fullArgs)
+	})
 	if err != nil {
-		return nil, b.wrapError(err, stderr.String(), args)
+		return nil, b.wrapError(err, stderr, args)
 	}
 
-	if stdout.Len() == 0 && stderr.Len() > 0 {
-		return nil, b.wrapError(fmt.Errorf("command produced no output"), stderr.String(), args)
+	if len(stdout) == 0 && len(stderr) > 0 {
+		return nil, b.wrapError(fmt.Errorf("command produced no output"), stderr, args)
 	}
 
-	return stripStdoutWarnings(stdout.Bytes()), nil
+	return stripStdoutWarnings(stdout), nil
 }
 
 // Run executes a bd command and returns stdout.
@@ -1164,6 +1158,7 @@ type SearchOptions struct {
 	Query        string // Text query to search titles and descriptions
 	Status       string // "open", "closed", "all"
 	Label        string // Label filter (e.g., "gt:bug")
+	Assignee     string // Filter by assignee (e.g., "gastown/Toast")
 	Limit        int    // Max results (0 = default)
 	DescContains string // Filter by description substring
 }
@@ -1181,6 +1176,9 @@ func (b *Beads) Search(opts SearchOptions) ([]*Issue, error) {
 	if opts.Label != "" {
 		args = append(args, "--label="+opts.Label)
 	}
+	if opts.Assignee != "" {
+		args = append(args, "--assignee="+opts.Assignee)
+	}
 	if opts.Limit > 0 {
 		args = append(args, fmt.Sprintf("--limit=%d", opts.Limit))
 	}
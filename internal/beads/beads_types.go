@@ -139,7 +139,7 @@ func EnsureCustomTypes(beadsDir string) error {
 	// Set BEADS_DIR explicitly to ensure bd operates on the correct database.
 	// Strip inherited BEADS_DIR first — getenv() returns the first match (gt-uygpe).
 	cmd.Env = append(stripEnvPrefixes(os.Environ(), "BEADS_DIR="), "BEADS_DIR="+beadsDir)
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := CmdCombinedOutput(cmd); err != nil {
 		return fmt.Errorf("configure custom types in %s: %s: %w",
 			beadsDir, strings.TrimSpace(string(output)), err)
 	}
@@ -200,7 +200,7 @@ func EnsureCustomStatuses(beadsDir string) error {
 	getCmd := exec.Command("bd", "config", "get", "status.custom")
 	getCmd.Dir = beadsDir
 	getCmd.Env = append(stripEnvPrefixes(os.Environ(), "BEADS_DIR="), "BEADS_DIR="+beadsDir)
-	existingOutput, _ := getCmd.Output()
+	existingOutput, _ := CmdOutput(getCmd)
 
 	// Build merged set: existing + required
 	statusSet := make(map[string]bool)
@@ -228,7 +228,7 @@ func EnsureCustomStatuses(beadsDir string) error {
 	cmd := exec.Command("bd", "config", "set", "status.custom", mergedStr)
 	cmd.Dir = beadsDir
 	cmd.Env = append(stripEnvPrefixes(os.Environ(), "BEADS_DIR="), "BEADS_DIR="+beadsDir)
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := CmdCombinedOutput(cmd); err != nil {
 		return fmt.Errorf("configure custom statuses in %s: %s: %w",
 			beadsDir, strings.TrimSpace(string(output)), err)
 	}
@@ -310,7 +310,7 @@ func ensureDatabaseInitialized(beadsDir string) error {
 	cmd := exec.Command("bd", initArgs...)
 	cmd.Dir = parentDir
 	cmd.Env = append(stripEnvPrefixes(os.Environ(), "BEADS_DIR="), "BEADS_DIR="+beadsDir)
-	if output, err := cmd.CombinedOutput(); err != nil {
+	if output, err := CmdCombinedOutput(cmd); err != nil {
 		// Handle "already initialized" gracefully, matching install.go behavior.
 		// This can happen due to race conditions or if detection heuristics miss
 		// a valid database state.
@@ -327,7 +327,7 @@ func ensureDatabaseInitialized(beadsDir string) error {
 		pfxCmd := exec.Command("bd", "config", "set", "issue_prefix", prefix)
 		pfxCmd.Dir = parentDir
 		pfxCmd.Env = append(stripEnvPrefixes(os.Environ(), "BEADS_DIR="), "BEADS_DIR="+beadsDir)
-		_, _ = pfxCmd.CombinedOutput() // Best effort — crash prevention guard
+		_, _ = CmdCombinedOutput(pfxCmd) // Best effort — crash prevention guard
 	}
 
 	// Run bd migrate to ensure the wisps table and auxiliary tables exist.
@@ -341,14 +341,14 @@ func ensureDatabaseInitialized(beadsDir string) error {
 	migrateCmd := exec.Command("bd", "migrate", "--yes")
 	migrateCmd.Dir = parentDir
 	migrateCmd.Env = migrateEnv
-	if _, err := migrateCmd.CombinedOutput(); err != nil {
+	if _, err := CmdCombinedOutput(migrateCmd); err != nil {
 		// First attempt failed — server may not have registered the database yet.
 		// Wait briefly and retry once.
 		time.Sleep(500 * time.Millisecond)
 		retryCmd := exec.Command("bd", "migrate", "--yes")
 		retryCmd.Dir = parentDir
 		retryCmd.Env = migrateEnv
-		_, _ = retryCmd.CombinedOutput() // Best effort on retry — CreateAgentBead fallback handles failure
+		_, _ = CmdCombinedOutput(retryCmd) // Best effort on retry — CreateAgentBead fallback handles failure
 	}
 
 	return nil
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -245,11 +246,97 @@ func SetAttachmentFields(issue *Issue, fields *AttachmentFields) string {
 // ConvoyFields holds the structured fields for a convoy bead.
 // These fields are stored as key: value lines in the issue description.
 type ConvoyFields struct {
-	Owner      string // Convoy owner address (e.g., "mayor/")
-	Notify     string // Additional notification address
-	Molecule   string // Associated molecule/swarm ID
-	Merge      string // Merge strategy
-	BaseBranch string // Target branch for polecats (e.g., "feat/extraction-review")
+	Owner      string     // Convoy owner address (e.g., "mayor/")
+	Notify     string     // Additional notification address
+	Molecule   string     // Associated molecule/swarm ID
+	Merge      string     // Merge strategy
+	BaseBranch string     // Target branch for polecats (e.g., "feat/extraction-review")
+	After      []string   // Convoys that must close before this one dispatches
+	Phases     [][]string // Ordered waves of tracked issue IDs; Phases[0] is phase 1
+
+	// LinkedBranch marks a linked convoy: one logical change spread over
+	// several rigs. Every polecat works on this branch name in its own repo
+	// and the refinery holds each MR until all of them are submitted.
+	LinkedBranch string
+}
+
+// IsLinked reports whether the convoy is a linked multi-repo change.
+func (f *ConvoyFields) IsLinked() bool {
+	return f != nil && f.LinkedBranch != ""
+}
+
+// PhaseOf returns the 1-based phase an issue is assigned to, or 0 if the
+// issue is unphased.
+func (f *ConvoyFields) PhaseOf(issueID string) int {
+	if f == nil {
+		return 0
+	}
+	for i, ids := range f.Phases {
+		for _, id := range ids {
+			if id == issueID {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// SetPhase moves issueIDs into the given 1-based phase, removing them from
+// any other phase. Phase 0 clears the assignment; phases above
+// MaxConvoyPhase are clamped to it. Trailing empty phases are
+// dropped; empty phases in the middle are kept so numbering stays stable.
+func (f *ConvoyFields) SetPhase(phase int, issueIDs ...string) {
+	move := make(map[string]bool, len(issueIDs))
+	for _, id := range issueIDs {
+		move[id] = true
+	}
+	for i, ids := range f.Phases {
+		kept := ids[:0]
+		for _, id := range ids {
+			if !move[id] {
+				kept = append(kept, id)
+			}
+		}
+		f.Phases[i] = kept
+	}
+	phase = min(phase, MaxConvoyPhase)
+	if phase > 0 {
+		for len(f.Phases) < phase {
+			f.Phases = append(f.Phases, nil)
+		}
+		f.Phases[phase-1] = append(f.Phases[phase-1], issueIDs...)
+	}
+	for len(f.Phases) > 0 && len(f.Phases[len(f.Phases)-1]) == 0 {
+		f.Phases = f.Phases[:len(f.Phases)-1]
+	}
+}
+
+// MaxConvoyPhase is the highest phase a convoy can use. Phases are stored
+// densely, so a description naming a huge phase must not size the slice.
+const MaxConvoyPhase = 100
+
+// parsePhaseKey returns N for a "phase_N" key, or 0 if the key is not a
+// phase or N is out of range.
+func parsePhaseKey(key string) int {
+	rest, ok := strings.CutPrefix(key, "phase_")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.Atoi(rest)
+	if err != nil || n < 1 || n > MaxConvoyPhase {
+		return 0
+	}
+	return n
+}
+
+func splitIDList(value string) []string {
+	var ids []string
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
 }
 
 // ParseConvoyFields extracts convoy fields from an issue's description.
@@ -295,6 +382,20 @@ func ParseConvoyFields(issue *Issue) *ConvoyFields {
 		case "base_branch", "base-branch", "basebranch":
 			fields.BaseBranch = value
 			hasFields = true
+		case "after":
+			fields.After = splitIDList(value)
+			hasFields = true
+		case "linked_branch", "linked-branch", "linkedbranch":
+			fields.LinkedBranch = value
+			hasFields = true
+		default:
+			if n := parsePhaseKey(strings.ToLower(key)); n > 0 {
+				for len(fields.Phases) < n {
+					fields.Phases = append(fields.Phases, nil)
+				}
+				fields.Phases[n-1] = splitIDList(value)
+				hasFields = true
+			}
 		}
 	}
 
@@ -343,6 +444,17 @@ func FormatConvoyFields(fields *ConvoyFields) string {
 	if fields.BaseBranch != "" {
 		lines = append(lines, "base_branch: "+fields.BaseBranch)
 	}
+	if len(fields.After) > 0 {
+		lines = append(lines, "after: "+strings.Join(fields.After, ", "))
+	}
+	if fields.LinkedBranch != "" {
+		lines = append(lines, "linked_branch: "+fields.LinkedBranch)
+	}
+	for i, ids := range fields.Phases {
+		if len(ids) > 0 {
+			lines = append(lines, fmt.Sprintf("phase_%d: %s", i+1, strings.Join(ids, ", ")))
+		}
+	}
 
 	return strings.Join(lines, "\n")
 }
@@ -381,13 +493,17 @@ func SetConvoyFields(issue *Issue, fields *ConvoyFields) string {
 
 	// Known convoy field keys (lowercase)
 	convoyKeys := map[string]bool{
-		"owner":       true,
-		"notify":      true,
-		"merge":       true,
-		"molecule":    true,
-		"base_branch": true,
-		"base-branch": true,
-		"basebranch":  true,
+		"owner":         true,
+		"notify":        true,
+		"merge":         true,
+		"molecule":      true,
+		"base_branch":   true,
+		"base-branch":   true,
+		"basebranch":    true,
+		"after":         true,
+		"linked_branch": true,
+		"linked-branch": true,
+		"linkedbranch":  true,
 	}
 
 	// Collect non-convoy lines from existing description
@@ -407,7 +523,7 @@ func SetConvoyFields(issue *Issue, fields *ConvoyFields) string {
 			}
 
 			key := strings.ToLower(strings.TrimSpace(trimmed[:colonIdx]))
-			if !convoyKeys[key] {
+			if !convoyKeys[key] && parsePhaseKey(key) == 0 {
 				otherLines = append(otherLines, line)
 			}
 		}
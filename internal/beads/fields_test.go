@@ -547,3 +547,94 @@ func TestParseAgentFields_WithCompletionMetadata(t *testing.T) {
 		t.Errorf("MRID = %q, want empty (not in desc)", got.MRID)
 	}
 }
+
+func TestConvoyFieldsPhasesRoundTrip(t *testing.T) {
+	original := &ConvoyFields{
+		Merge:  "mr",
+		After:  []string{"hq-cv-schema", "hq-cv-auth"},
+		Phases: [][]string{{"gt-a", "gt-b"}, nil, {"bd-c"}},
+	}
+	formatted := FormatConvoyFields(original)
+	want := "Merge: mr\nafter: hq-cv-schema, hq-cv-auth\nphase_1: gt-a, gt-b\nphase_3: bd-c"
+	if formatted != want {
+		t.Fatalf("FormatConvoyFields() = %q, want %q", formatted, want)
+	}
+
+	parsed := ParseConvoyFields(&Issue{Description: "Convoy tracking 3 issues\n" + formatted})
+	if parsed == nil {
+		t.Fatal("round-trip parse returned nil")
+	}
+	if strings.Join(parsed.After, ",") != "hq-cv-schema,hq-cv-auth" {
+		t.Errorf("After = %v", parsed.After)
+	}
+	if len(parsed.Phases) != 3 || len(parsed.Phases[1]) != 0 || parsed.Phases[2][0] != "bd-c" {
+		t.Errorf("Phases = %v", parsed.Phases)
+	}
+	if got := parsed.PhaseOf("gt-b"); got != 1 {
+		t.Errorf("PhaseOf(gt-b) = %d, want 1", got)
+	}
+	if got := parsed.PhaseOf("bd-c"); got != 3 {
+		t.Errorf("PhaseOf(bd-c) = %d, want 3", got)
+	}
+	if got := parsed.PhaseOf("gt-z"); got != 0 {
+		t.Errorf("PhaseOf(gt-z) = %d, want 0", got)
+	}
+}
+
+func TestConvoyFieldsPhaseIgnoresProse(t *testing.T) {
+	parsed := ParseConvoyFields(&Issue{Description: "Phase 1: migrate the schema\nMerge: direct"})
+	if parsed == nil || len(parsed.Phases) != 0 {
+		t.Errorf("prose \"Phase 1:\" line should not parse as a phase, got %+v", parsed)
+	}
+}
+
+func TestConvoyFieldsPhaseCap(t *testing.T) {
+	parsed := ParseConvoyFields(&Issue{Description: "phase_999999999999: gt-a\nphase_2: gt-b"})
+	if parsed == nil || len(parsed.Phases) != 2 || parsed.PhaseOf("gt-a") != 0 {
+		t.Errorf("an out-of-range phase should be ignored, got %+v", parsed)
+	}
+}
+
+func TestConvoyFieldsSetPhase(t *testing.T) {
+	f := &ConvoyFields{Phases: [][]string{{"gt-a", "gt-b"}, {"gt-c"}}}
+
+	f.SetPhase(2, "gt-a")
+	if strings.Join(f.Phases[0], ",") != "gt-b" || strings.Join(f.Phases[1], ",") != "gt-c,gt-a" {
+		t.Errorf("after moving gt-a to phase 2: %v", f.Phases)
+	}
+
+	f.SetPhase(4, "gt-d")
+	if len(f.Phases) != 4 || f.PhaseOf("gt-d") != 4 {
+		t.Errorf("after adding gt-d to phase 4: %v", f.Phases)
+	}
+
+	f.SetPhase(0, "gt-d")
+	if len(f.Phases) != 2 {
+		t.Errorf("clearing the only issue in trailing phases should drop them: %v", f.Phases)
+	}
+}
+
+func TestSetConvoyFieldsReplacesPhases(t *testing.T) {
+	issue := &Issue{Description: "Migration waves\nMerge: mr\nafter: hq-cv-old\nphase_1: gt-a\nphase_2: gt-b"}
+	got := SetConvoyFields(issue, &ConvoyFields{Merge: "mr", Phases: [][]string{{"gt-b"}}})
+	want := "Migration waves\nMerge: mr\nphase_1: gt-b"
+	if got != want {
+		t.Errorf("SetConvoyFields() = %q, want %q", got, want)
+	}
+}
+
+func TestConvoyFieldsLinkedBranch(t *testing.T) {
+	f := ParseConvoyFields(&Issue{Description: "API v2\nMerge: mr\nlinked_branch: feat/api-v2"})
+	if !f.IsLinked() || f.LinkedBranch != "feat/api-v2" {
+		t.Fatalf("ParseConvoyFields() = %+v, want linked branch feat/api-v2", f)
+	}
+	var nilFields *ConvoyFields
+	if nilFields.IsLinked() || (&ConvoyFields{Merge: "mr"}).IsLinked() {
+		t.Error("convoys without linked_branch are not linked")
+	}
+
+	got := SetConvoyFields(&Issue{Description: "API v2\nlinked_branch: old"}, &ConvoyFields{LinkedBranch: "feat/api-v2"})
+	if want := "API v2\nlinked_branch: feat/api-v2"; got != want {
+		t.Errorf("SetConvoyFields() = %q, want %q", got, want)
+	}
+}
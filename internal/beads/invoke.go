@@ -0,0 +1,337 @@
+package beads
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// gt's bd subprocesses go through Invoke, which makes concurrent use of one
+// database safe. Beads methods and the cmd package's BdCmd builder call it
+// themselves; code that builds its own exec.Cmd runs it with RunCmd,
+// CmdOutput or CmdCombinedOutput. With Invoke:
+//
+//   - Writes to the same database are serialized, within this process by a
+//     mutex and across gt processes by a lock file in the .beads directory.
+//   - Identical reads that overlap in time share one subprocess.
+//   - Calls that fail because the database was locked or a transaction was
+//     aborted are retried with backoff.
+//   - Each call's duration, retries and lock wait are recorded (CallStats);
+//     GT_BD_TIMINGS=1 prints them when gt exits.
+//
+// Agents calling bd directly are not serialized, but gt's own calls no
+// longer contend with each other, except for these, which still exec bd
+// directly:
+//
+//   - the bd version probes (BdSupportsAllowStale, and internal/deps, which
+//     this package imports); they only read and run once per process.
+//   - bd activity --follow in the feed TUI, a stream that runs until closed.
+//   - test helpers in internal/testutil.
+
+// WriteLockFile is the lock file Invoke holds in a .beads directory while
+// a write runs.
+const WriteLockFile = ".gt-write.lock"
+
+// Retry and lock timing. Variables so tests can shorten them.
+var (
+	invokeMaxAttempts  = 4
+	invokeRetryBase    = 150 * time.Millisecond
+	invokeRetryMax     = 2 * time.Second
+	invokeLockTimeout  = 30 * time.Second
+	invokeLockInterval = 20 * time.Millisecond
+)
+
+// contentionMarkers are stderr fragments of transient lock failures. The
+// call did not take effect, so running it again is safe for writes too.
+var contentionMarkers = []string{
+	"database is locked",
+	"SQLITE_BUSY",
+	"database table is locked",
+	"lock wait timeout",
+	"Error 1205",
+	"deadlock",
+	"Error 1213",
+	"try restarting transaction",
+	"serialization failure",
+}
+
+// readCommands are bd subcommands that never write.
+var readCommands = map[string]bool{
+	"show": true, "list": true, "ready": true, "blocked": true, "search": true,
+	"stats": true, "count": true, "query": true, "info": true, "where": true,
+	"version": true,
+}
+
+// readSubcommands are read-only actions of bd commands that also write.
+var readSubcommands = map[string]map[string]bool{
+	"dep":        {"list": true, "tree": true},
+	"label":      {"list": true, "list-all": true},
+	"config":     {"get": true, "list": true},
+	"slot":       {"show": true},
+	"mol":        {"show": true, "current": true, "progress": true},
+	"merge-slot": {"check": true},
+}
+
+// IsReadCommand reports whether bd args (global flags allowed) only read.
+// Unknown commands count as writes.
+func IsReadCommand(args []string) bool {
+	name := commandName(args)
+	if readCommands[name] {
+		return true
+	}
+	if cmd, action, ok := strings.Cut(name, " "); ok {
+		return readSubcommands[cmd][action]
+	}
+	return false
+}
+
+// IsContention reports whether bd stderr describes a transient lock failure.
+func IsContention(stderr string) bool {
+	lower := strings.ToLower(stderr)
+	for _, m := range contentionMarkers {
+		if strings.Contains(lower, strings.ToLower(m)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Invocation identifies a bd call for Invoke.
+type Invocation struct {
+	Args     []string // bd arguments, used to classify the call and for stats
+	BeadsDir string   // Database the call targets; writes are serialized per directory
+	Dir      string   // Working directory; part of the key for shared reads
+}
+
+// RunFunc runs bd once. Invoke calls it again for each retry, so it must
+// build a fresh exec.Cmd every time.
+type RunFunc func() (stdout []byte, stderr string, err error)
+
+// Invoke runs a bd call through the serialization, sharing and retry layer.
+func Invoke(inv Invocation, run RunFunc) ([]byte, string, error) {
+	if IsReadCommand(inv.Args) {
+		key := inv.BeadsDir + "\x00" + inv.Dir + "\x00" + strings.Join(inv.Args, "\x00")
+		return sharedReads.do(key, inv.Args, func() ([]byte, string, error) {
+			return invokeWithRetry(inv.Args, 0, run)
+		})
+	}
+
+	start := time.Now()
+	unlock := lockDatabase(inv.BeadsDir)
+	wait := time.Since(start)
+	defer unlock()
+	return invokeWithRetry(inv.Args, wait, run)
+}
+
+func invokeWithRetry(args []string, lockWait time.Duration, run RunFunc) ([]byte, string, error) {
+	start := time.Now()
+	var (
+		stdout  []byte
+		stderr  string
+		err     error
+		retries int
+	)
+	for attempt := 1; ; attempt++ {
+		stdout, stderr, err = run()
+		if err == nil || attempt >= invokeMaxAttempts || !IsContention(stderr) {
+			break
+		}
+		retries++
+		time.Sleep(retryDelay(attempt))
+	}
+	callStats.record(args, time.Since(start), lockWait, retries, err != nil, false)
+	return stdout, stderr, err
+}
+
+// execBD runs bd once with the given environment, capturing both streams.
+func execBD(dir string, env, args []string) ([]byte, string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("bd", args...) //nolint:gosec // G204: bd is a trusted internal tool
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.Bytes(), stderr.String(), err
+}
+
+// retryDelay is exponential backoff with jitter, so processes that collided
+// once don't collide again in lockstep.
+func retryDelay(attempt int) time.Duration {
+	d := invokeRetryBase << (attempt - 1)
+	if d > invokeRetryMax {
+		d = invokeRetryMax
+	}
+	return d/2 + time.Duration(rand.Int64N(int64(d/2)+1)) //nolint:gosec // G404: jitter, not security
+}
+
+// dbLocks holds the in-process write mutex for each database.
+var dbLocks sync.Map // cleaned beads dir -> *sync.Mutex
+
+// lockDatabase serializes writes to beadsDir and returns the unlock func.
+// The cross-process lock is best-effort: if the directory does not exist
+// yet (bd init) or the lock cannot be had within invokeLockTimeout, the
+// write goes ahead with only the in-process lock.
+func lockDatabase(beadsDir string) func() {
+	if beadsDir == "" {
+		return func() {}
+	}
+	beadsDir = filepath.Clean(beadsDir)
+	mu, _ := dbLocks.LoadOrStore(beadsDir, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+
+	if info, err := os.Stat(beadsDir); err != nil || !info.IsDir() {
+		return mu.(*sync.Mutex).Unlock
+	}
+	fl := flock.New(filepath.Join(beadsDir, WriteLockFile))
+	deadline := time.Now().Add(invokeLockTimeout)
+	for {
+		ok, err := fl.TryLock()
+		if ok {
+			return func() {
+				_ = fl.Unlock()
+				mu.(*sync.Mutex).Unlock()
+			}
+		}
+		if err != nil || time.Now().After(deadline) {
+			return mu.(*sync.Mutex).Unlock
+		}
+		time.Sleep(invokeLockInterval)
+	}
+}
+
+// readGroup lets concurrent identical reads share one subprocess.
+type readGroup struct {
+	mu    sync.Mutex
+	calls map[string]*readCall
+}
+
+type readCall struct {
+	done   chan struct{}
+	stdout []byte
+	stderr string
+	err    error
+}
+
+var sharedReads = &readGroup{}
+
+func (g *readGroup) do(key string, args []string, fn RunFunc) ([]byte, string, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*readCall)
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-c.done
+		callStats.record(args, 0, 0, 0, c.err != nil, true)
+		return c.stdout, c.stderr, c.err
+	}
+	c := &readCall{done: make(chan struct{})}
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.stdout, c.stderr, c.err = fn()
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	close(c.done)
+	return c.stdout, c.stderr, c.err
+}
+
+// CallStat summarizes the bd calls of one subcommand made by this process.
+type CallStat struct {
+	Command   string        `json:"command"`
+	Calls     int           `json:"calls"`     // Subprocesses run
+	Shared    int           `json:"shared"`    // Reads answered by another caller's subprocess
+	Errors    int           `json:"errors"`    // Calls that failed after retries
+	Retries   int           `json:"retries"`   // Extra attempts after lock contention
+	Total     time.Duration `json:"total"`     // Time in bd, including retries
+	Max       time.Duration `json:"max"`       // Slowest call
+	LockWaits time.Duration `json:"lock_wait"` // Time waiting for the write lock
+}
+
+type statsTable struct {
+	mu    sync.Mutex
+	stats map[string]*CallStat
+}
+
+var callStats = &statsTable{}
+
+func (t *statsTable) record(args []string, took, lockWait time.Duration, retries int, failed, shared bool) {
+	name := commandName(args)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stats == nil {
+		t.stats = make(map[string]*CallStat)
+	}
+	s, ok := t.stats[name]
+	if !ok {
+		s = &CallStat{Command: name}
+		t.stats[name] = s
+	}
+	if shared {
+		s.Shared++
+	} else {
+		s.Calls++
+		s.Total += took
+		s.LockWaits += lockWait
+		if took > s.Max {
+			s.Max = took
+		}
+	}
+	s.Retries += retries
+	if failed {
+		s.Errors++
+	}
+}
+
+// commandName is the stats key for a call: the subcommand, plus the action
+// for commands listed in readSubcommands ("dep add", "dep tree").
+func commandName(args []string) string {
+	var words []string
+	for _, a := range args {
+		if !strings.HasPrefix(a, "-") {
+			words = append(words, a)
+		}
+	}
+	switch {
+	case len(words) == 0:
+		return "bd"
+	case len(words) > 1 && readSubcommands[words[0]] != nil:
+		return words[0] + " " + words[1]
+	default:
+		return words[0]
+	}
+}
+
+// CallStats returns this process's bd call statistics, slowest total first.
+func CallStats() []CallStat {
+	callStats.mu.Lock()
+	defer callStats.mu.Unlock()
+	out := make([]CallStat, 0, len(callStats.stats))
+	for _, s := range callStats.stats {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Total != out[j].Total {
+			return out[i].Total > out[j].Total
+		}
+		return out[i].Command < out[j].Command
+	})
+	return out
+}
+
+// ResetCallStats clears the statistics (for tests).
+func ResetCallStats() {
+	callStats.mu.Lock()
+	callStats.stats = nil
+	callStats.mu.Unlock()
+}
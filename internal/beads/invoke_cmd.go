@@ -0,0 +1,141 @@
+package beads
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// RunCmd runs cmd, a bd command the caller built with exec.Command, through
+// Invoke. It stands in for cmd.Run: Stdout and Stderr, if set, receive the
+// output of the final attempt once it finishes.
+func RunCmd(cmd *exec.Cmd) error {
+	return RunCmdContext(context.Background(), cmd)
+}
+
+// RunCmdContext is RunCmd for commands built with exec.CommandContext; pass
+// the same ctx, which exec.Cmd does not expose.
+func RunCmdContext(ctx context.Context, cmd *exec.Cmd) error {
+	stdout, stderr, err := invokeCmd(ctx, cmd, false)
+	if cmd.Stdout != nil {
+		_, _ = cmd.Stdout.Write(stdout)
+	}
+	if cmd.Stderr != nil {
+		_, _ = io.WriteString(cmd.Stderr, stderr)
+	}
+	return err
+}
+
+// CmdOutput stands in for cmd.Output, running it through Invoke.
+func CmdOutput(cmd *exec.Cmd) ([]byte, error) {
+	return CmdOutputContext(context.Background(), cmd)
+}
+
+// CmdOutputContext is CmdOutput for commands built with exec.CommandContext.
+func CmdOutputContext(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	if cmd.Stdout != nil {
+		return nil, errors.New("exec: Stdout already set")
+	}
+	stdout, stderr, err := invokeCmd(ctx, cmd, false)
+	if cmd.Stderr != nil {
+		_, _ = io.WriteString(cmd.Stderr, stderr)
+	} else if ee, ok := err.(*exec.ExitError); ok {
+		ee.Stderr = []byte(stderr)
+	}
+	return stdout, err
+}
+
+// CmdCombinedOutput stands in for cmd.CombinedOutput, running it through
+// Invoke.
+func CmdCombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	return CmdCombinedOutputContext(context.Background(), cmd)
+}
+
+// CmdCombinedOutputContext is CmdCombinedOutput for commands built with
+// exec.CommandContext.
+func CmdCombinedOutputContext(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	if cmd.Stdout != nil || cmd.Stderr != nil {
+		return nil, errors.New("exec: Stdout or Stderr already set")
+	}
+	out, _, err := invokeCmd(ctx, cmd, true)
+	return out, err
+}
+
+// InvocationFor describes a bd call run in dir with env (nil meaning this
+// process's environment). The database is the last BEADS_DIR in env, or
+// the one found from dir.
+func InvocationFor(args []string, dir string, env []string) Invocation {
+	if env == nil {
+		env = os.Environ()
+	}
+	beadsDir := ""
+	for i := len(env) - 1; i >= 0; i-- {
+		if d, ok := strings.CutPrefix(env[i], "BEADS_DIR="); ok && d != "" {
+			beadsDir = d
+			break
+		}
+	}
+	if beadsDir == "" {
+		from := dir
+		if from == "" {
+			from, _ = os.Getwd()
+		}
+		beadsDir = ResolveBeadsDir(from)
+	}
+	return Invocation{Args: args, BeadsDir: beadsDir, Dir: dir}
+}
+
+// invokeCmd runs a copy of cmd per attempt, since an exec.Cmd runs once.
+// Output is captured so contention can be detected; with combined, both
+// streams go to stdout and are also returned as stderr.
+func invokeCmd(ctx context.Context, cmd *exec.Cmd, combined bool) ([]byte, string, error) {
+	if cmd.Err != nil {
+		return nil, "", cmd.Err
+	}
+	stdin, err := replayableStdin(cmd.Stdin)
+	if err != nil {
+		return nil, "", err
+	}
+	inv := InvocationFor(cmd.Args[1:], cmd.Dir, cmd.Env)
+	return Invoke(inv, func() ([]byte, string, error) {
+		c := exec.CommandContext(ctx, cmd.Path) //nolint:gosec // G204: bd is a trusted internal tool
+		c.Args = cmd.Args
+		c.Dir = cmd.Dir
+		c.Env = cmd.Env
+		c.SysProcAttr = cmd.SysProcAttr
+		c.WaitDelay = cmd.WaitDelay
+		c.Stdin = stdin()
+
+		var out, errOut bytes.Buffer
+		c.Stdout = &out
+		c.Stderr = &errOut
+		if combined {
+			c.Stderr = &out
+		}
+		err := c.Run()
+		if combined {
+			return out.Bytes(), out.String(), err
+		}
+		return out.Bytes(), errOut.String(), err
+	})
+}
+
+// replayableStdin returns a func giving each attempt the same input. Files
+// (a terminal, a pipe) are passed through as they are.
+func replayableStdin(r io.Reader) (func() io.Reader, error) {
+	switch r := r.(type) {
+	case nil:
+		return func() io.Reader { return nil }, nil
+	case *os.File:
+		return func() io.Reader { return r }, nil
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return func() io.Reader { return bytes.NewReader(data) }, nil
+}
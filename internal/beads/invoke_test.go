@@ -0,0 +1,291 @@
+package beads
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func shortInvokeTimings(t *testing.T) {
+	t.Helper()
+	oldBase, oldMax, oldAttempts := invokeRetryBase, invokeRetryMax, invokeMaxAttempts
+	invokeRetryBase, invokeRetryMax = time.Millisecond, 4*time.Millisecond
+	t.Cleanup(func() {
+		invokeRetryBase, invokeRetryMax, invokeMaxAttempts = oldBase, oldMax, oldAttempts
+		ResetCallStats()
+	})
+	ResetCallStats()
+}
+
+func statFor(t *testing.T, command string) CallStat {
+	t.Helper()
+	for _, s := range CallStats() {
+		if s.Command == command {
+			return s
+		}
+	}
+	t.Fatalf("no stats for %q in %+v", command, CallStats())
+	return CallStat{}
+}
+
+func TestIsReadCommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"show", "gt-1", "--json"}, true},
+		{[]string{"--allow-stale", "list", "--json", "--flat"}, true},
+		{[]string{"ready"}, true},
+		{[]string{"dep", "tree", "gt-1"}, true},
+		{[]string{"dep", "add", "gt-1", "gt-2"}, false},
+		{[]string{"label", "list", "gt-1"}, true},
+		{[]string{"label", "add", "gt-1", "x"}, false},
+		{[]string{"config", "get", "issue_prefix"}, true},
+		{[]string{"config", "set", "issue_prefix", "gt"}, false},
+		{[]string{"create", "--title", "x"}, false},
+		{[]string{"update", "gt-1", "--status", "closed"}, false},
+		{[]string{"init"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := IsReadCommand(tt.args); got != tt.want {
+			t.Errorf("IsReadCommand(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestIsContention(t *testing.T) {
+	for _, stderr := range []string{
+		"Error: database is locked",
+		"sqlite: SQLITE_BUSY (5)",
+		"Error 1213 (40001): Deadlock found when trying to get lock; try restarting transaction",
+		"Error 1205: Lock wait timeout exceeded",
+		"serialization failure: this transaction conflicts with a committed transaction",
+	} {
+		if !IsContention(stderr) {
+			t.Errorf("IsContention(%q) = false, want true", stderr)
+		}
+	}
+	for _, stderr := range []string{"", "Error: issue not found", "unknown flag: --flat"} {
+		if IsContention(stderr) {
+			t.Errorf("IsContention(%q) = true, want false", stderr)
+		}
+	}
+}
+
+func TestInvoke_RetriesContention(t *testing.T) {
+	shortInvokeTimings(t)
+	attempts := 0
+	out, _, err := Invoke(Invocation{Args: []string{"update", "gt-1"}, BeadsDir: t.TempDir()}, func() ([]byte, string, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, "Error: database is locked", errors.New("exit status 1")
+		}
+		return []byte("ok"), "", nil
+	})
+	if err != nil || string(out) != "ok" {
+		t.Fatalf("Invoke = %q, %v; want ok", out, err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	s := statFor(t, "update")
+	if s.Calls != 1 || s.Retries != 2 || s.Errors != 0 {
+		t.Errorf("stats = %+v, want 1 call, 2 retries, 0 errors", s)
+	}
+}
+
+func TestInvoke_GivesUpAfterMaxAttempts(t *testing.T) {
+	shortInvokeTimings(t)
+	invokeMaxAttempts = 2
+	attempts := 0
+	_, stderr, err := Invoke(Invocation{Args: []string{"close", "gt-1"}}, func() ([]byte, string, error) {
+		attempts++
+		return nil, "database is locked", errors.New("exit status 1")
+	})
+	if err == nil || stderr != "database is locked" {
+		t.Fatalf("Invoke = %q, %v; want the last failure", stderr, err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+	if s := statFor(t, "close"); s.Errors != 1 {
+		t.Errorf("Errors = %d, want 1", s.Errors)
+	}
+}
+
+func TestInvoke_OtherErrorsNotRetried(t *testing.T) {
+	shortInvokeTimings(t)
+	attempts := 0
+	_, _, err := Invoke(Invocation{Args: []string{"show", "gt-x"}}, func() ([]byte, string, error) {
+		attempts++
+		return nil, "Error: issue not found", errors.New("exit status 1")
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("err = %v, attempts = %d; want error after 1 attempt", err, attempts)
+	}
+}
+
+func TestInvoke_SerializesWritesPerDatabase(t *testing.T) {
+	shortInvokeTimings(t)
+	dbA, dbB := t.TempDir(), t.TempDir()
+
+	var running, maxRunning atomic.Int32
+	write := func(dir string) {
+		_, _, _ = Invoke(Invocation{Args: []string{"update", "gt-1"}, BeadsDir: dir}, func() ([]byte, string, error) {
+			n := running.Add(1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return nil, "", nil
+		})
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() { defer wg.Done(); write(dbA) }()
+	}
+	wg.Wait()
+	if got := maxRunning.Load(); got != 1 {
+		t.Errorf("concurrent writes to one database = %d, want 1", got)
+	}
+	if runtime.GOOS != "windows" {
+		if _, err := os.Stat(filepath.Join(dbA, WriteLockFile)); err != nil {
+			t.Errorf("write lock file not created: %v", err)
+		}
+	}
+
+	// Different databases don't block each other.
+	maxRunning.Store(0)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go func() {
+		_, _, _ = Invoke(Invocation{Args: []string{"update"}, BeadsDir: dbA}, func() ([]byte, string, error) {
+			close(started)
+			<-release
+			return nil, "", nil
+		})
+	}()
+	<-started
+	done := make(chan struct{})
+	go func() { write(dbB); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("write to another database blocked")
+	}
+	close(release)
+}
+
+func TestInvoke_SharesConcurrentReads(t *testing.T) {
+	shortInvokeTimings(t)
+	var calls atomic.Int32
+	release := make(chan struct{})
+	run := func() ([]byte, string, error) {
+		calls.Add(1)
+		<-release
+		return []byte(`[{"id":"gt-1"}]`), "", nil
+	}
+	inv := Invocation{Args: []string{"show", "gt-1", "--json"}, BeadsDir: "/db"}
+
+	var wg sync.WaitGroup
+	results := make([]string, 4)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			out, _, _ := Invoke(inv, run)
+			results[i] = string(out)
+		}(i)
+	}
+	// Let the readers queue up behind the first before it finishes.
+	deadline := time.Now().Add(5 * time.Second)
+	for calls.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("subprocesses = %d, want 1", got)
+	}
+	for i, r := range results {
+		if r != `[{"id":"gt-1"}]` {
+			t.Errorf("reader %d got %q", i, r)
+		}
+	}
+	if s := statFor(t, "show"); s.Calls != 1 || s.Shared != 3 {
+		t.Errorf("stats = %+v, want 1 call, 3 shared", s)
+	}
+
+	// Once finished, the same read runs again.
+	_, _, _ = Invoke(inv, func() ([]byte, string, error) { calls.Add(1); return nil, "", nil })
+	if got := calls.Load(); got != 2 {
+		t.Errorf("subprocesses after completion = %d, want 2", got)
+	}
+}
+
+func TestCommandName(t *testing.T) {
+	tests := map[string][]string{
+		"show":    {"--allow-stale", "show", "gt-1", "--json"},
+		"dep add": {"dep", "add", "gt-1", "gt-2"},
+		"update":  {"update", "gt-1"},
+		"bd":      {"--version"},
+	}
+	for want, args := range tests {
+		if got := commandName(args); got != want {
+			t.Errorf("commandName(%v) = %q, want %q", args, got, want)
+		}
+	}
+}
+
+func TestCmdOutput_RetriesWithSameStdin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as bd")
+	}
+	shortInvokeTimings(t)
+	dir := t.TempDir()
+	bd := filepath.Join(dir, "bd")
+	script := `#!/bin/sh
+n=$(cat "$COUNT" 2>/dev/null || echo 0); n=$((n+1)); echo $n > "$COUNT"
+if [ $n -lt 2 ]; then echo "database is locked" >&2; exit 1; fi
+cat; echo " $*"
+`
+	if err := os.WriteFile(bd, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(bd, "update", "gt-1")
+	cmd.Env = append(os.Environ(), "COUNT="+filepath.Join(dir, "count"), "BEADS_DIR="+dir)
+	cmd.Stdin = strings.NewReader("hello")
+	out, err := CmdOutput(cmd)
+	if err != nil || string(out) != "hello update gt-1\n" {
+		t.Fatalf("CmdOutput = %q, %v", out, err)
+	}
+	if s := statFor(t, "update"); s.Retries != 1 {
+		t.Errorf("stats = %+v, want 1 retry", s)
+	}
+
+	// Failures keep exec's shape: stderr on the ExitError.
+	invokeMaxAttempts = 1
+	_ = os.Remove(filepath.Join(dir, "count"))
+	_, err = CmdOutput(exec.Command(bd, "update", "gt-1"))
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) || !strings.Contains(string(ee.Stderr), "database is locked") {
+		t.Errorf("err = %v, want an ExitError carrying stderr", err)
+	}
+}
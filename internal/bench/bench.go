@@ -0,0 +1,144 @@
+// Package bench detects benchmark regressions across merges.
+//
+// A rig's merge queue can run a benchmark command on every merge. Its output
+// is parsed in Go benchmark format (any language can emit it):
+//
+//	BenchmarkParse-8   	   50000	     23456 ns/op	    1024 B/op	      12 allocs/op
+//
+// Each metric ("BenchmarkParse ns/op") is compared against the same metric
+// from recent merges. A change is a regression only when it is both
+// statistically significant (one-sided t-test, p < 0.01) and larger than a
+// relative threshold, so noisy benchmarks don't bounce good branches.
+// Results of successful merges are appended to the rig's history.
+package bench
+
+import (
+	"bufio"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults for Config fields left unset.
+const (
+	DefaultThreshold  = 0.10 // 10% slower
+	DefaultWindow     = 20   // Merges of history to compare against
+	DefaultMinSamples = 5    // History needed before flagging anything
+)
+
+// Config is the merge_queue.bench section of a rig's config.json.
+type Config struct {
+	// Cmd is the shell command that runs the benchmarks, e.g.
+	// "go test -run=^$ -bench=. -count=5 ./...".
+	Cmd string
+
+	// Timeout bounds the benchmark run. Zero = no timeout.
+	Timeout time.Duration
+
+	// Threshold is the relative change that counts as a regression
+	// (0.10 = 10% worse). Default: 0.10.
+	Threshold float64
+
+	// Window is how many past merges form the baseline. Default: 20.
+	Window int
+
+	// MinSamples is the history required before a metric is judged.
+	// Default: 5.
+	MinSamples int
+}
+
+// IsEnabled reports whether a benchmark command is configured.
+func (c *Config) IsEnabled() bool {
+	return c != nil && strings.TrimSpace(c.Cmd) != ""
+}
+
+// GetThreshold returns the regression threshold or the default.
+func (c *Config) GetThreshold() float64 {
+	if c == nil || c.Threshold <= 0 {
+		return DefaultThreshold
+	}
+	return c.Threshold
+}
+
+// GetWindow returns the baseline window or the default.
+func (c *Config) GetWindow() int {
+	if c == nil || c.Window <= 0 {
+		return DefaultWindow
+	}
+	return c.Window
+}
+
+// GetMinSamples returns the minimum history or the default.
+func (c *Config) GetMinSamples() int {
+	if c == nil || c.MinSamples <= 0 {
+		return DefaultMinSamples
+	}
+	return c.MinSamples
+}
+
+// Results maps a metric ("BenchmarkParse ns/op") to its samples from one run.
+// -count=N yields N samples per metric.
+type Results map[string][]float64
+
+// Metrics returns the metric names in sorted order.
+func (r Results) Metrics() []string {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Parse extracts benchmark results from Go benchmark format output.
+// Non-benchmark lines are ignored.
+func Parse(output string) Results {
+	results := make(Results)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[1]); err != nil {
+			continue // Not an iterations column
+		}
+		name := trimProcs(fields[0])
+		// Remaining fields come in value/unit pairs.
+		for i := 2; i+1 < len(fields); i += 2 {
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				break
+			}
+			key := name + " " + fields[i+1]
+			results[key] = append(results[key], v)
+		}
+	}
+	return results
+}
+
+// trimProcs drops the -GOMAXPROCS suffix ("BenchmarkX-8" → "BenchmarkX")
+// so results compare across machines with different core counts.
+func trimProcs(name string) string {
+	i := strings.LastIndex(name, "-")
+	if i <= 0 {
+		return name
+	}
+	if _, err := strconv.Atoi(name[i+1:]); err != nil {
+		return name
+	}
+	return name[:i]
+}
+
+// HigherIsBetter reports the direction of a metric from its unit:
+// throughput units ("MB/s", "ops/s") improve upward, everything else
+// (ns/op, B/op, allocs/op) improves downward.
+func HigherIsBetter(metric string) bool {
+	unit := metric
+	if i := strings.LastIndex(metric, " "); i >= 0 {
+		unit = metric[i+1:]
+	}
+	return strings.HasSuffix(unit, "/s")
+}
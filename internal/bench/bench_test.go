@@ -0,0 +1,180 @@
+package bench
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+const goBenchOutput = `goos: linux
+goarch: amd64
+pkg: example.com/parser
+BenchmarkParse-8    	   50000	     23456 ns/op	    1024 B/op	      12 allocs/op
+BenchmarkParse-8    	   50000	     23500 ns/op	    1024 B/op	      12 allocs/op
+BenchmarkEncode-16  	  100000	     10.5 ns/op	  950.25 MB/s
+BenchmarkNoProcs    	    1000	      5000 ns/op
+PASS
+ok  	example.com/parser	3.2s
+`
+
+func TestParse(t *testing.T) {
+	r := Parse(goBenchOutput)
+	if got := r["BenchmarkParse ns/op"]; len(got) != 2 || got[0] != 23456 || got[1] != 23500 {
+		t.Errorf("BenchmarkParse ns/op = %v", got)
+	}
+	if got := r["BenchmarkParse allocs/op"]; len(got) != 2 || got[0] != 12 {
+		t.Errorf("BenchmarkParse allocs/op = %v", got)
+	}
+	if got := r["BenchmarkEncode MB/s"]; len(got) != 1 || got[0] != 950.25 {
+		t.Errorf("BenchmarkEncode MB/s = %v", got)
+	}
+	if _, ok := r["BenchmarkNoProcs ns/op"]; !ok {
+		t.Error("benchmark without -procs suffix not parsed")
+	}
+	want := []string{"BenchmarkEncode MB/s", "BenchmarkEncode ns/op", "BenchmarkNoProcs ns/op",
+		"BenchmarkParse B/op", "BenchmarkParse allocs/op", "BenchmarkParse ns/op"}
+	if got := r.Metrics(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Metrics() = %v, want %v", got, want)
+	}
+}
+
+func TestHigherIsBetter(t *testing.T) {
+	for metric, want := range map[string]bool{
+		"BenchmarkX ns/op":     false,
+		"BenchmarkX B/op":      false,
+		"BenchmarkX allocs/op": false,
+		"BenchmarkX MB/s":      true,
+		"BenchmarkX ops/s":     true,
+	} {
+		if got := HigherIsBetter(metric); got != want {
+			t.Errorf("HigherIsBetter(%q) = %v, want %v", metric, got, want)
+		}
+	}
+}
+
+// history builds per-merge entries for one metric.
+func history(metric string, values ...float64) []Entry {
+	out := make([]Entry, 0, len(values))
+	for _, v := range values {
+		out = append(out, Entry{Results: Results{metric: {v}}})
+	}
+	return out
+}
+
+func TestCompare(t *testing.T) {
+	const metric = "BenchmarkParse ns/op"
+	base := history(metric, 1000, 1010, 990, 1005, 995, 1002, 998)
+	cfg := &Config{}
+
+	tests := []struct {
+		name      string
+		cfg       *Config
+		history   []Entry
+		current   []float64
+		regressed bool
+		skipped   bool
+	}{
+		{"steady", cfg, base, []float64{1003, 997, 1001}, false, false},
+		{"clear regression", cfg, base, []float64{1300, 1310, 1290}, true, false},
+		{"significant but under threshold", cfg, base, []float64{1050, 1052, 1048}, false, false},
+		{"under lowered threshold", &Config{Threshold: 0.03}, base, []float64{1050, 1052, 1048}, true, false},
+		{"single sample regression", cfg, base, []float64{1400}, true, false},
+		{"improvement", cfg, base, []float64{700, 705, 702}, false, false},
+		{"not enough history", cfg, base[:3], []float64{5000}, false, true},
+	}
+	for _, tt := range tests {
+		report := Compare(tt.cfg, Results{metric: tt.current}, tt.history)
+		if len(report.Comparisons) != 1 {
+			t.Fatalf("%s: %d comparisons", tt.name, len(report.Comparisons))
+		}
+		c := report.Comparisons[0]
+		if c.Regressed != tt.regressed || (c.Skipped != "") != tt.skipped {
+			t.Errorf("%s: comparison = %+v, want regressed=%v skipped=%v", tt.name, c, tt.regressed, tt.skipped)
+		}
+	}
+}
+
+func TestCompareNoisyBenchmarkIsNotFlagged(t *testing.T) {
+	const metric = "BenchmarkNoisy ns/op"
+	base := history(metric, 800, 1300, 900, 1250, 1000, 1200, 850)
+	report := Compare(&Config{}, Results{metric: {1150}}, base)
+	if regs := report.Regressions(); len(regs) != 0 {
+		t.Errorf("noisy benchmark flagged: %+v", regs)
+	}
+}
+
+func TestCompareThroughputDirection(t *testing.T) {
+	const metric = "BenchmarkEncode MB/s"
+	base := history(metric, 900, 905, 895, 902, 898)
+	report := Compare(&Config{}, Results{metric: {600, 605, 598}}, base)
+	if len(report.Regressions()) != 1 {
+		t.Fatalf("throughput drop not flagged: %+v", report.Comparisons)
+	}
+	if !strings.Contains(report.Summary(), "BenchmarkEncode MB/s") {
+		t.Errorf("Summary() = %q", report.Summary())
+	}
+	report = Compare(&Config{}, Results{metric: {1200, 1210, 1190}}, base)
+	if len(report.Regressions()) != 0 {
+		t.Errorf("throughput gain flagged as regression: %+v", report.Comparisons)
+	}
+}
+
+func TestCompareWindow(t *testing.T) {
+	const metric = "BenchmarkParse ns/op"
+	// Old merges were slow; the last five are fast. With window 5 the fast
+	// merges are the baseline and a return to slow is a regression.
+	h := append(history(metric, 2000, 2010, 1990, 2005, 1995), history(metric, 1000, 1005, 995, 1002, 998)...)
+	report := Compare(&Config{Window: 5}, Results{metric: {2000, 2003, 1998}}, h)
+	if len(report.Regressions()) != 1 {
+		t.Errorf("window not applied: %+v", report.Comparisons)
+	}
+}
+
+func TestHistoryRoundTrip(t *testing.T) {
+	rigPath := t.TempDir()
+	if got, err := History(rigPath); err != nil || len(got) != 0 {
+		t.Fatalf("History() on empty rig = %v, %v", got, err)
+	}
+	for i, commit := range []string{"aaa", "bbb"} {
+		e := Entry{MergeCommit: commit, Branch: "polecat/Toast", Results: Results{"BenchmarkX ns/op": {float64(100 + i)}}}
+		if err := Append(rigPath, e); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+	got, err := History(rigPath)
+	if err != nil {
+		t.Fatalf("History() error = %v", err)
+	}
+	if len(got) != 2 || got[0].MergeCommit != "aaa" || got[1].Results["BenchmarkX ns/op"][0] != 101 {
+		t.Errorf("History() = %+v", got)
+	}
+	if got[0].Timestamp.IsZero() {
+		t.Error("Append() should default the timestamp")
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &Config{Cmd: "printf 'BenchmarkX-4 10 250 ns/op\\n'"}
+	results, err := Run(context.Background(), cfg, dir)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := results["BenchmarkX ns/op"]; len(got) != 1 || got[0] != 250 {
+		t.Errorf("Run() = %v", results)
+	}
+
+	if _, err := Run(context.Background(), &Config{Cmd: "echo no benchmarks here"}, dir); err == nil {
+		t.Error("expected error when output has no results")
+	}
+	if _, err := Run(context.Background(), &Config{Cmd: "exit 3"}, dir); err == nil {
+		t.Error("expected error when command fails")
+	}
+	if _, err := Run(context.Background(), &Config{Cmd: "sleep 5", Timeout: 50 * time.Millisecond}, dir); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Run() timeout error = %v", err)
+	}
+	if _, err := Run(context.Background(), &Config{}, dir); err == nil {
+		t.Error("expected error with no command")
+	}
+}
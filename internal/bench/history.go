@@ -0,0 +1,78 @@
+package bench
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Entry is the benchmark results recorded for one merge.
+type Entry struct {
+	Timestamp   time.Time `json:"ts"`
+	MergeCommit string    `json:"merge_commit"`
+	Issue       string    `json:"issue,omitempty"` // Source issue of the merged branch
+	Branch      string    `json:"branch,omitempty"`
+	Results     Results   `json:"results"`
+}
+
+// HistoryPath returns the benchmark history for a rig.
+func HistoryPath(rigPath string) string {
+	return filepath.Join(rigPath, constants.DirRuntime, "bench-history.jsonl")
+}
+
+// Append records a merge's results.
+func Append(rigPath string, e Entry) error {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encoding bench entry: %w", err)
+	}
+	path := HistoryPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: history is not sensitive
+	if err != nil {
+		return fmt.Errorf("opening bench history: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing bench history: %w", err)
+	}
+	return f.Close()
+}
+
+// History returns recorded entries oldest first. Malformed lines are skipped;
+// a missing file yields no entries.
+func History(rigPath string) ([]Entry, error) {
+	f, err := os.Open(HistoryPath(rigPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening bench history: %w", err)
+	}
+	defer f.Close()
+
+	var out []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || len(e.Results) == 0 {
+			continue
+		}
+		out = append(out, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return out, fmt.Errorf("reading bench history: %w", err)
+	}
+	return out, nil
+}
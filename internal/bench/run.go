@@ -0,0 +1,43 @@
+package bench
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Run executes the benchmark command in dir and parses its output.
+// The command comes from the rig's operator-controlled config.json.
+func Run(ctx context.Context, cfg *Config, dir string) (Results, error) {
+	if !cfg.IsEnabled() {
+		return nil, fmt.Errorf("no benchmark command configured")
+	}
+	if cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", cfg.Cmd) //nolint:gosec // G204: bench command is from trusted rig config
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("benchmarks timed out after %v", cfg.Timeout)
+		}
+		return nil, fmt.Errorf("benchmarks failed: %v: %s", err, lastLine(stderr.String()))
+	}
+	results := Parse(stdout.String())
+	if len(results) == 0 {
+		return nil, fmt.Errorf("benchmark command produced no results (expected Go benchmark format)")
+	}
+	return results, nil
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return lines[len(lines)-1]
+}
@@ -0,0 +1,193 @@
+package bench
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Comparison is the verdict for one metric.
+type Comparison struct {
+	Metric      string  `json:"metric"`
+	Baseline    float64 `json:"baseline"`          // Mean of per-merge means in the window
+	Current     float64 `json:"current"`           // Mean of this run's samples
+	Change      float64 `json:"change"`            // Relative change; positive = worse
+	History     int     `json:"history"`           // Merges in the baseline
+	Significant bool    `json:"significant"`       // p < 0.01
+	Regressed   bool    `json:"regressed"`         // Significant and beyond the threshold
+	Skipped     string  `json:"skipped,omitempty"` // Why no verdict was reached, if any
+}
+
+// Report is the comparison of one run against history.
+type Report struct {
+	Comparisons []Comparison `json:"comparisons"`
+}
+
+// Regressions returns the regressed metrics.
+func (r *Report) Regressions() []Comparison {
+	var out []Comparison
+	for _, c := range r.Comparisons {
+		if c.Regressed {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Summary is a one-line description of the regressions, for nudges.
+func (r *Report) Summary() string {
+	regs := r.Regressions()
+	if len(regs) == 0 {
+		return "no benchmark regressions"
+	}
+	parts := make([]string, 0, len(regs))
+	for _, c := range regs {
+		parts = append(parts, fmt.Sprintf("%s %s → %s (%+.1f%%)", c.Metric, formatValue(c.Baseline), formatValue(c.Current), c.Change*100))
+	}
+	return "benchmark regression: " + strings.Join(parts, "; ")
+}
+
+// Table renders all comparisons for humans.
+func (r *Report) Table() string {
+	var b strings.Builder
+	for _, c := range r.Comparisons {
+		verdict := "ok"
+		switch {
+		case c.Skipped != "":
+			verdict = c.Skipped
+		case c.Regressed:
+			verdict = "REGRESSION"
+		case c.Change > 0 && c.Significant:
+			verdict = "slower (within threshold)"
+		case c.Change < 0 && c.Significant:
+			verdict = "improved"
+		}
+		if c.Skipped != "" {
+			fmt.Fprintf(&b, "  %-50s %12s  %s\n", c.Metric, formatValue(c.Current), verdict)
+			continue
+		}
+		fmt.Fprintf(&b, "  %-50s %12s → %12s  %+6.1f%%  %s\n", c.Metric, formatValue(c.Baseline), formatValue(c.Current), c.Change*100, verdict)
+	}
+	return b.String()
+}
+
+// Compare judges a run against per-merge history (oldest first).
+func Compare(cfg *Config, current Results, history []Entry) *Report {
+	window := cfg.GetWindow()
+	if len(history) > window {
+		history = history[len(history)-window:]
+	}
+	report := &Report{}
+	for _, metric := range current.Metrics() {
+		samples := current[metric]
+		c := Comparison{Metric: metric, Current: mean(samples)}
+
+		var baseline []float64
+		for _, h := range history {
+			if s := h.Results[metric]; len(s) > 0 {
+				baseline = append(baseline, mean(s))
+			}
+		}
+		c.History = len(baseline)
+		if c.History < cfg.GetMinSamples() {
+			c.Skipped = fmt.Sprintf("baseline %d/%d merges", c.History, cfg.GetMinSamples())
+			report.Comparisons = append(report.Comparisons, c)
+			continue
+		}
+
+		c.Baseline = mean(baseline)
+		if c.Baseline != 0 {
+			c.Change = (c.Current - c.Baseline) / math.Abs(c.Baseline)
+		}
+		if HigherIsBetter(metric) {
+			c.Change = -c.Change
+		}
+		c.Significant = significant(baseline, samples)
+		c.Regressed = c.Significant && c.Change > cfg.GetThreshold()
+		report.Comparisons = append(report.Comparisons, c)
+	}
+	return report
+}
+
+// significant reports whether current differs from baseline at p < 0.01
+// (one-sided). With several current samples it uses Welch's t-test; with
+// one, whether it falls outside the baseline's 99% prediction interval.
+func significant(baseline, current []float64) bool {
+	nb, nc := float64(len(baseline)), float64(len(current))
+	mb, mc := mean(baseline), mean(current)
+	vb, vc := variance(baseline), variance(current)
+	diff := math.Abs(mc - mb)
+
+	if len(current) < 2 {
+		se := math.Sqrt(vb * (1 + 1/nb))
+		if se == 0 {
+			return diff > 0
+		}
+		return diff/se > tCritical(len(baseline)-1)
+	}
+
+	sb, sc := vb/nb, vc/nc
+	se := math.Sqrt(sb + sc)
+	if se == 0 {
+		return diff > 0
+	}
+	// Welch–Satterthwaite degrees of freedom.
+	df := (sb + sc) * (sb + sc) / (sb*sb/(nb-1) + sc*sc/(nc-1))
+	return diff/se > tCritical(int(df))
+}
+
+// tCritical is the one-sided 99% critical value of Student's t.
+func tCritical(df int) float64 {
+	table := []struct {
+		df int
+		t  float64
+	}{
+		{1, 31.821}, {2, 6.965}, {3, 4.541}, {4, 3.747}, {5, 3.365},
+		{6, 3.143}, {7, 2.998}, {8, 2.896}, {9, 2.821}, {10, 2.764},
+		{12, 2.681}, {15, 2.602}, {20, 2.528}, {30, 2.457}, {60, 2.390},
+	}
+	if df < 1 {
+		df = 1
+	}
+	for _, row := range table {
+		if df <= row.df {
+			return row.t
+		}
+	}
+	return 2.326
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// variance is the sample variance (n-1).
+func variance(xs []float64) float64 {
+	if len(xs) < 2 {
+		return 0
+	}
+	m := mean(xs)
+	sum := 0.0
+	for _, x := range xs {
+		sum += (x - m) * (x - m)
+	}
+	return sum / float64(len(xs)-1)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case v >= 100:
+		return fmt.Sprintf("%.0f", v)
+	case v >= 1:
+		return fmt.Sprintf("%.2f", v)
+	default:
+		return fmt.Sprintf("%.4g", v)
+	}
+}
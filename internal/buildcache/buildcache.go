@@ -0,0 +1,99 @@
+// Package buildcache measures the shared build caches configured by a rig's
+// build_cache settings.
+//
+// Walking a warm Go build cache touches tens of thousands of files, so
+// measurements are saved next to the caches and reused until they are
+// MaxAge old.
+package buildcache
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// MaxAge is how long a saved measurement is reused.
+const MaxAge = 15 * time.Minute
+
+// statsFile is written in the cache root.
+const statsFile = ".gt-stats.json"
+
+// Stat is the on-disk size of one shared cache.
+type Stat struct {
+	Cache string `json:"cache"`
+	Bytes int64  `json:"bytes"`
+	Files int    `json:"files"`
+}
+
+// Stats is a measurement of a rig's shared caches.
+type Stats struct {
+	Root       string    `json:"root"`
+	Caches     []Stat    `json:"caches"`
+	Bytes      int64     `json:"bytes"`
+	MeasuredAt time.Time `json:"measured_at"`
+}
+
+// Measure walks the enabled caches of the rig at rigPath. Caches that do
+// not exist yet count as empty.
+func Measure(rigPath string, cfg *config.BuildCacheConfig) *Stats {
+	stats := &Stats{Root: cfg.Root(rigPath), MeasuredAt: time.Now()}
+	dirs := cfg.Dirs(rigPath)
+	for _, kind := range cfg.Enabled() {
+		s := Stat{Cache: kind}
+		for _, dir := range dirs[kind] {
+			_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return nil
+				}
+				if info, err := d.Info(); err == nil {
+					s.Bytes += info.Size()
+					s.Files++
+				}
+				return nil
+			})
+		}
+		stats.Caches = append(stats.Caches, s)
+		stats.Bytes += s.Bytes
+	}
+	return stats
+}
+
+// Load returns the saved measurement if it is fresh and covers the same
+// caches, otherwise measures and saves. Returns nil if cfg is nil.
+func Load(rigPath string, cfg *config.BuildCacheConfig) *Stats {
+	if cfg == nil {
+		return nil
+	}
+	root := cfg.Root(rigPath)
+	path := filepath.Join(root, statsFile)
+	if data, err := os.ReadFile(path); err == nil { //nolint:gosec // G304: path is constructed internally
+		var saved Stats
+		if json.Unmarshal(data, &saved) == nil && time.Since(saved.MeasuredAt) < MaxAge && sameCaches(saved.Caches, cfg.Enabled()) {
+			return &saved
+		}
+	}
+
+	stats := Measure(rigPath, cfg)
+	if data, err := json.MarshalIndent(stats, "", "  "); err == nil {
+		if err := os.MkdirAll(root, 0755); err == nil {
+			_ = os.WriteFile(path, data, 0644) //nolint:gosec // G306: cache stats are not sensitive
+		}
+	}
+	return stats
+}
+
+func sameCaches(stats []Stat, kinds []string) bool {
+	if len(stats) != len(kinds) {
+		return false
+	}
+	for i, s := range stats {
+		if s.Cache != kinds[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package buildcache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func writeSized(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	rigPath := t.TempDir()
+	cfg := &config.BuildCacheConfig{Caches: []string{config.BuildCacheGo, config.BuildCachePnpm}}
+	writeSized(t, filepath.Join(rigPath, ".cache", "go", "mod", "a"), 100)
+	writeSized(t, filepath.Join(rigPath, ".cache", "go", "build", "b"), 50)
+
+	stats := Measure(rigPath, cfg)
+	if stats.Bytes != 150 || len(stats.Caches) != 2 {
+		t.Fatalf("stats = %+v", stats)
+	}
+	if stats.Caches[0] != (Stat{Cache: "go", Bytes: 150, Files: 2}) {
+		t.Errorf("go = %+v", stats.Caches[0])
+	}
+	if stats.Caches[1] != (Stat{Cache: "pnpm"}) {
+		t.Errorf("missing pnpm store should be empty, got %+v", stats.Caches[1])
+	}
+}
+
+func TestLoad_ReusesFreshStats(t *testing.T) {
+	rigPath := t.TempDir()
+	cfg := &config.BuildCacheConfig{Caches: []string{config.BuildCacheNpm}}
+	writeSized(t, filepath.Join(rigPath, ".cache", "npm", "x"), 10)
+
+	if first := Load(rigPath, cfg); first.Bytes != 10 {
+		t.Fatalf("first Load = %+v", first)
+	}
+	writeSized(t, filepath.Join(rigPath, ".cache", "npm", "y"), 10)
+	if again := Load(rigPath, cfg); again.Bytes != 10 {
+		t.Errorf("fresh stats should be reused, got %d bytes", again.Bytes)
+	}
+
+	// Changing the cache list invalidates the saved measurement.
+	cfg.Caches = append(cfg.Caches, config.BuildCachePip)
+	if changed := Load(rigPath, cfg); changed.Bytes != 20 {
+		t.Errorf("stats for new cache list = %d bytes, want 20", changed.Bytes)
+	}
+
+	if Load(rigPath, nil) != nil {
+		t.Error("Load with no config should be nil")
+	}
+}
@@ -0,0 +1,326 @@
+// Package changelog implements changelog fragments: a short entry per bead,
+// committed with the bead's work under the repo's fragment directory and
+// assembled into CHANGELOG.md at release time.
+//
+// When a rig requires fragments, gt done refuses to submit a branch that
+// changes user-facing files without one (gt changelog).
+package changelog
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/release"
+)
+
+// Defaults for an unset changelog config.
+const (
+	DefaultDir  = ".changelog"
+	DefaultFile = "CHANGELOG.md"
+)
+
+// DefaultIgnore lists the changes that never need a fragment.
+var DefaultIgnore = []string{"*_test.go", "testdata/", "test/", "tests/", ".github/", ".beads/"}
+
+// Config is the changelog section of a rig's settings/config.json.
+//
+//	"changelog": {
+//	  "require": true,
+//	  "dir": ".changelog",
+//	  "file": "CHANGELOG.md",
+//	  "ignore": ["*_test.go", "docs/internal/"],
+//	  "skip_labels": ["no-changelog"]
+//	}
+//
+// Ignore patterns are matched against each changed file's path and base
+// name; a pattern ending in "/" matches everything under a directory of that
+// name. A bead with one of the skip labels never needs a fragment.
+type Config struct {
+	Require    bool     `json:"require"`
+	Dir        string   `json:"dir,omitempty"`
+	File       string   `json:"file,omitempty"`
+	Ignore     []string `json:"ignore,omitempty"`
+	SkipLabels []string `json:"skip_labels,omitempty"`
+}
+
+// IsRequired reports whether done beads need a fragment.
+func (c *Config) IsRequired() bool {
+	return c != nil && c.Require
+}
+
+// GetDir returns the repo-relative fragment directory.
+func (c *Config) GetDir() string {
+	if c == nil || c.Dir == "" {
+		return DefaultDir
+	}
+	return c.Dir
+}
+
+// GetFile returns the repo-relative changelog file.
+func (c *Config) GetFile() string {
+	if c == nil || c.File == "" {
+		return DefaultFile
+	}
+	return c.File
+}
+
+// GetIgnore returns the patterns of changes that don't need a fragment.
+func (c *Config) GetIgnore() []string {
+	if c == nil || c.Ignore == nil {
+		return DefaultIgnore
+	}
+	return c.Ignore
+}
+
+// Skips reports whether a bead with labels is exempt.
+func (c *Config) Skips(labels []string) bool {
+	if c == nil {
+		return false
+	}
+	for _, l := range labels {
+		for _, s := range c.SkipLabels {
+			if l == s {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Validate checks the config. A nil config is valid (fragments optional).
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	for _, p := range c.GetIgnore() {
+		if _, err := path.Match(strings.TrimSuffix(p, "/"), ""); err != nil {
+			return fmt.Errorf("bad ignore pattern %q: %w", p, err)
+		}
+	}
+	if filepath.IsAbs(c.GetDir()) || strings.HasPrefix(path.Clean(c.GetDir()), "..") {
+		return fmt.Errorf("dir %q must be inside the repo", c.GetDir())
+	}
+	return nil
+}
+
+// UserFacing returns the changed files that need a fragment: everything
+// outside the fragment directory that no ignore pattern matches.
+func (c *Config) UserFacing(files []string) []string {
+	var out []string
+	for _, f := range files {
+		if strings.HasPrefix(f, path.Clean(c.GetDir())+"/") || matchAny(c.GetIgnore(), f) {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func matchAny(patterns []string, file string) bool {
+	for _, p := range patterns {
+		if dir, ok := strings.CutSuffix(p, "/"); ok {
+			if strings.HasPrefix(file, dir+"/") || strings.Contains(file, "/"+dir+"/") {
+				return true
+			}
+			continue
+		}
+		full, _ := path.Match(p, file)
+		base, _ := path.Match(p, path.Base(file))
+		if full || base {
+			return true
+		}
+	}
+	return false
+}
+
+// Fragment is one bead's changelog entry.
+type Fragment struct {
+	Bead string       `json:"bead"`
+	Kind release.Kind `json:"kind"`
+	Text string       `json:"text"`
+}
+
+// Path returns the repo-relative path of a bead's fragment.
+func (c *Config) Path(bead string) string {
+	return path.Join(c.GetDir(), bead+".md")
+}
+
+// Format renders the fragment file: a "kind:" line, a blank line and the
+// entry text.
+func (f *Fragment) Format() string {
+	return fmt.Sprintf("kind: %s\n\n%s\n", f.Kind, strings.TrimSpace(f.Text))
+}
+
+// ParseFragment reads a fragment file's content. Without a "kind:" line the
+// entry counts as "other".
+func ParseFragment(bead, content string) (*Fragment, error) {
+	f := &Fragment{Bead: bead, Kind: release.KindOther}
+	text := strings.TrimSpace(content)
+	if first, rest, _ := strings.Cut(text, "\n"); strings.HasPrefix(first, "kind:") {
+		kind, err := release.ParseKind(strings.TrimSpace(strings.TrimPrefix(first, "kind:")))
+		if err != nil {
+			return nil, fmt.Errorf("fragment %s: %w", bead, err)
+		}
+		f.Kind = kind
+		text = strings.TrimSpace(rest)
+	}
+	if text == "" {
+		return nil, fmt.Errorf("fragment %s is empty", bead)
+	}
+	f.Text = text
+	return f, nil
+}
+
+// Write saves a fragment in the checkout at root, replacing any earlier one
+// for the bead.
+func (c *Config) Write(root string, f *Fragment) error {
+	if f.Bead == "" {
+		return fmt.Errorf("fragment has no bead")
+	}
+	if strings.TrimSpace(f.Text) == "" {
+		return fmt.Errorf("fragment text is empty")
+	}
+	p := filepath.Join(root, filepath.FromSlash(c.Path(f.Bead)))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("creating fragment directory: %w", err)
+	}
+	return os.WriteFile(p, []byte(f.Format()), 0644) //nolint:gosec // G306: fragments are committed source
+}
+
+// Fragments returns the fragments in the checkout at root, sorted by bead.
+func (c *Config) Fragments(root string) ([]*Fragment, error) {
+	dir := filepath.Join(root, filepath.FromSlash(c.GetDir()))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []*Fragment
+	for _, entry := range entries {
+		bead, ok := strings.CutSuffix(entry.Name(), ".md")
+		if !ok || entry.IsDir() || strings.EqualFold(bead, "README") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name())) //nolint:gosec // G304: path is within the fragment dir
+		if err != nil {
+			return nil, err
+		}
+		f, err := ParseFragment(bead, string(data))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Bead < out[j].Bead })
+	return out, nil
+}
+
+// Render returns the changelog section for a version: the fragments grouped
+// by kind, most significant first.
+func Render(version string, date time.Time, fragments []*Fragment) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## [%s] - %s\n", version, date.Format("2006-01-02"))
+	for _, section := range []struct {
+		kind  release.Kind
+		title string
+	}{
+		{release.KindBreaking, "Breaking changes"},
+		{release.KindFeature, "Added"},
+		{release.KindFix, "Fixed"},
+		{release.KindOther, "Changed"},
+	} {
+		var lines []string
+		for _, f := range fragments {
+			if f.Kind != section.kind {
+				continue
+			}
+			text := strings.ReplaceAll(f.Text, "\n", "\n  ")
+			lines = append(lines, fmt.Sprintf("- %s (%s)", text, f.Bead))
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(&b, "\n### %s\n\n%s\n", section.title, strings.Join(lines, "\n"))
+		}
+	}
+	return b.String()
+}
+
+// Prepend inserts section into the changelog at file above the newest
+// entry (the first "## " heading), creating the file if needed.
+func Prepend(file, section string) error {
+	data, err := os.ReadFile(file) //nolint:gosec // G304: caller-provided changelog path
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	content := string(data)
+	if content == "" {
+		content = "# Changelog\n"
+	}
+	section = strings.TrimRight(section, "\n") + "\n"
+
+	var out string
+	switch i := strings.Index(content, "\n## "); {
+	case strings.HasPrefix(content, "## "):
+		out = section + "\n" + content
+	case i >= 0:
+		out = content[:i+1] + section + "\n" + content[i+1:]
+	default:
+		out = strings.TrimRight(content, "\n") + "\n\n" + section
+	}
+	return os.WriteFile(file, []byte(out), 0644) //nolint:gosec // G306: the changelog is committed source
+}
+
+// Lint is the result of checking a branch for its fragment.
+type Lint struct {
+	UserFacing []string // Changed files that need a fragment
+	Fragment   string   // Repo-relative path the fragment belongs at
+	Committed  bool     // The fragment is in the branch's HEAD
+	Exists     bool     // The fragment is in the working tree
+}
+
+// Missing reports whether the branch needs a fragment it doesn't have.
+func (l *Lint) Missing() bool {
+	return len(l.UserFacing) > 0 && !l.Committed
+}
+
+// Check looks at the changes between base and HEAD in the checkout at dir
+// for bead's fragment.
+func (c *Config) Check(dir, base, bead string) (*Lint, error) {
+	out, err := gitOutput(dir, "diff", "--name-only", base+"...HEAD")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, f := range strings.Split(out, "\n") {
+		if f = strings.TrimSpace(f); f != "" {
+			files = append(files, f)
+		}
+	}
+	l := &Lint{UserFacing: c.UserFacing(files), Fragment: c.Path(bead)}
+	_, err = gitOutput(dir, "cat-file", "-e", "HEAD:"+l.Fragment)
+	l.Committed = err == nil
+	if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(l.Fragment))); err == nil {
+		l.Exists = true
+	}
+	return l, nil
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
@@ -0,0 +1,179 @@
+package changelog
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/release"
+)
+
+func TestUserFacing(t *testing.T) {
+	files := []string{
+		"internal/mail/router.go",
+		"internal/mail/router_test.go",
+		"internal/mail/testdata/inbox.json",
+		".github/workflows/ci.yml",
+		".changelog/gt-abc12.md",
+		"docs/internal/design.md",
+		"README.md",
+	}
+	var cfg *Config
+	if got := strings.Join(cfg.UserFacing(files), ","); got != "internal/mail/router.go,docs/internal/design.md,README.md" {
+		t.Errorf("default UserFacing = %s", got)
+	}
+	cfg = &Config{Ignore: []string{"docs/", "*.md"}}
+	if got := strings.Join(cfg.UserFacing(files), ","); got != "internal/mail/router.go,internal/mail/router_test.go,internal/mail/testdata/inbox.json,.github/workflows/ci.yml" {
+		t.Errorf("custom UserFacing = %s", got)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	cfg := &Config{SkipLabels: []string{"no-changelog"}}
+	if !cfg.Skips([]string{"gt:task", "no-changelog"}) || cfg.Skips([]string{"gt:task"}) {
+		t.Error("Skips should match skip labels only")
+	}
+	if err := (&Config{Ignore: []string{"["}}).Validate(); err == nil {
+		t.Error("a bad ignore pattern should not validate")
+	}
+	if err := (&Config{Dir: "../elsewhere"}).Validate(); err == nil {
+		t.Error("a fragment dir outside the repo should not validate")
+	}
+	if err := (*Config)(nil).Validate(); err != nil {
+		t.Errorf("nil config: %v", err)
+	}
+}
+
+func TestParseFragment(t *testing.T) {
+	f := &Fragment{Bead: "gt-abc12", Kind: release.KindFix, Text: "Handle an empty queue."}
+	got, err := ParseFragment("gt-abc12", f.Format())
+	if err != nil || *got != *f {
+		t.Errorf("round trip = %+v, %v", got, err)
+	}
+	if got, err := ParseFragment("gt-x", "Just text\n"); err != nil || got.Kind != release.KindOther || got.Text != "Just text" {
+		t.Errorf("without kind = %+v, %v", got, err)
+	}
+	if _, err := ParseFragment("gt-x", "kind: chore\n\ntext"); err == nil {
+		t.Error("an unknown kind should fail")
+	}
+	if _, err := ParseFragment("gt-x", "kind: fix\n"); err == nil {
+		t.Error("an empty fragment should fail")
+	}
+}
+
+func TestWriteFragmentsRender(t *testing.T) {
+	root := t.TempDir()
+	var cfg *Config
+	for _, f := range []*Fragment{
+		{Bead: "gt-b", Kind: release.KindFeature, Text: "Release gates.\nAsk by mail."},
+		{Bead: "gt-a", Kind: release.KindFix, Text: "Empty queue."},
+		{Bead: "gt-c", Kind: release.KindBreaking, Text: "Drop the legacy mailbox."},
+	} {
+		if err := cfg.Write(root, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cfg.Write(root, &Fragment{Bead: "gt-d"}); err == nil {
+		t.Error("an empty fragment should not be written")
+	}
+
+	fragments, err := cfg.Fragments(root)
+	if err != nil || len(fragments) != 3 || fragments[0].Bead != "gt-a" {
+		t.Fatalf("Fragments = %+v, %v", fragments, err)
+	}
+	got := Render("1.4.0", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), fragments)
+	want := `## [1.4.0] - 2026-03-01
+
+### Breaking changes
+
+- Drop the legacy mailbox. (gt-c)
+
+### Added
+
+- Release gates.
+  Ask by mail. (gt-b)
+
+### Fixed
+
+- Empty queue. (gt-a)
+`
+	if got != want {
+		t.Errorf("Render =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "CHANGELOG.md")
+
+	if err := Prepend(file, "## [0.1.0] - 2026-01-01\n\n- First\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Prepend(file, "## [0.2.0] - 2026-02-01\n\n- Second\n"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(file)
+	want := "# Changelog\n\n## [0.2.0] - 2026-02-01\n\n- Second\n\n## [0.1.0] - 2026-01-01\n\n- First\n"
+	if string(data) != want {
+		t.Errorf("CHANGELOG.md =\n%q\nwant\n%q", data, want)
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q", "--initial-branch=main")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "user.name", "Test")
+	runGit(t, dir, "commit", "-q", "--allow-empty", "-m", "base")
+	runGit(t, dir, "checkout", "-q", "-b", "polecat/nux")
+
+	var cfg *Config
+	write := func(name string) {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, dir, "add", "-A")
+		runGit(t, dir, "commit", "-q", "-m", "change "+name)
+	}
+
+	write("queue_test.go")
+	l, err := cfg.Check(dir, "main", "gt-abc12")
+	if err != nil || l.Missing() || len(l.UserFacing) != 0 {
+		t.Fatalf("tests only = %+v, %v", l, err)
+	}
+
+	write("queue.go")
+	if l, _ = cfg.Check(dir, "main", "gt-abc12"); !l.Missing() || l.Exists {
+		t.Errorf("user-facing without fragment = %+v", l)
+	}
+
+	if err := cfg.Write(dir, &Fragment{Bead: "gt-abc12", Kind: release.KindFix, Text: "Fix."}); err != nil {
+		t.Fatal(err)
+	}
+	if l, _ = cfg.Check(dir, "main", "gt-abc12"); !l.Missing() || !l.Exists {
+		t.Errorf("uncommitted fragment = %+v", l)
+	}
+
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "fragment")
+	if l, _ = cfg.Check(dir, "main", "gt-abc12"); l.Missing() || !l.Committed {
+		t.Errorf("committed fragment = %+v", l)
+	}
+}
@@ -0,0 +1,163 @@
+package changes
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+)
+
+// APIChange is the change to one Go package's exported API. Identifiers
+// are "Name" or "Type.Method".
+type APIChange struct {
+	Package string   `json:"package"` // Repo-relative directory
+	Added   []string `json:"added,omitempty"`
+	Changed []string `json:"changed,omitempty"` // Signature or type definition changed
+	Removed []string `json:"removed,omitempty"`
+}
+
+// goAPIChanges compares the exported API of every Go package with a changed
+// non-test .go file. Files that don't parse are skipped.
+func goAPIChanges(dir, base, head string, files []FileChange) ([]APIChange, error) {
+	pkgs := map[string]bool{}
+	for _, f := range files {
+		if strings.HasSuffix(f.Path, ".go") && !strings.HasSuffix(f.Path, "_test.go") {
+			pkgs[path.Dir(f.Path)] = true
+		}
+	}
+	var out []APIChange
+	for pkg := range pkgs {
+		before, err := packageAPI(dir, base, pkg)
+		if err != nil {
+			return nil, err
+		}
+		after, err := packageAPI(dir, head, pkg)
+		if err != nil {
+			return nil, err
+		}
+		change := APIChange{Package: pkg}
+		for name, sig := range after {
+			old, existed := before[name]
+			switch {
+			case !existed:
+				change.Added = append(change.Added, name)
+			case old != sig:
+				change.Changed = append(change.Changed, name)
+			}
+		}
+		for name := range before {
+			if _, ok := after[name]; !ok {
+				change.Removed = append(change.Removed, name)
+			}
+		}
+		if len(change.Added)+len(change.Changed)+len(change.Removed) == 0 {
+			continue
+		}
+		sort.Strings(change.Added)
+		sort.Strings(change.Changed)
+		sort.Strings(change.Removed)
+		out = append(out, change)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Package < out[j].Package })
+	return out, nil
+}
+
+// packageAPI maps a package's exported identifiers at rev to their
+// declarations, printed without bodies or comments.
+func packageAPI(dir, rev, pkg string) (map[string]string, error) {
+	api := map[string]string{}
+	listing, err := git(dir, "ls-tree", "--name-only", rev, pkg+"/")
+	if err != nil {
+		// The package doesn't exist at rev (added or deleted).
+		return api, nil
+	}
+	fset := token.NewFileSet()
+	for _, file := range strings.Split(listing, "\n") {
+		if !strings.HasSuffix(file, ".go") || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		src, err := git(dir, "show", rev+":"+file)
+		if err != nil {
+			return nil, err
+		}
+		f, err := parser.ParseFile(fset, file, src, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		if f.Name.Name == "main" || strings.HasSuffix(f.Name.Name, "_test") {
+			continue
+		}
+		for _, decl := range f.Decls {
+			collectDecl(fset, decl, api)
+		}
+	}
+	return api, nil
+}
+
+func collectDecl(fset *token.FileSet, decl ast.Decl, api map[string]string) {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if !d.Name.IsExported() {
+			return
+		}
+		name := d.Name.Name
+		if d.Recv != nil && len(d.Recv.List) > 0 {
+			recv := receiverType(d.Recv.List[0].Type)
+			if !ast.IsExported(recv) {
+				return
+			}
+			name = recv + "." + name
+		}
+		api[name] = render(fset, d.Type)
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if s.Name.IsExported() {
+					api[s.Name.Name] = render(fset, s.Type)
+				}
+			case *ast.ValueSpec:
+				for _, n := range s.Names {
+					if n.IsExported() {
+						api[n.Name] = d.Tok.String() + " " + render(fset, s.Type)
+					}
+				}
+			}
+		}
+	}
+}
+
+// receiverType returns the base type name of a method receiver.
+func receiverType(expr ast.Expr) string {
+	for {
+		switch t := expr.(type) {
+		case *ast.StarExpr:
+			expr = t.X
+		case *ast.IndexExpr:
+			expr = t.X
+		case *ast.IndexListExpr:
+			expr = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// render prints a declaration on one line, so layout and comment edits
+// don't count as API changes.
+func render(fset *token.FileSet, node ast.Node) string {
+	if node == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
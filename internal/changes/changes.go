@@ -0,0 +1,222 @@
+// Package changes summarizes what a merged bead changed: files, the
+// directories (Go packages) they live in, dependency changes and the
+// exported API of Go packages.
+//
+// The refinery records a summary for every bead it merges, under the rig's
+// .runtime/changes/, for release notes and compliance (gt changes).
+package changes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/deppolicy"
+)
+
+// Summary is the change summary recorded for one merged bead.
+type Summary struct {
+	Bead         string                 `json:"bead"`
+	MR           string                 `json:"mr,omitempty"`
+	Rig          string                 `json:"rig,omitempty"`
+	Branch       string                 `json:"branch,omitempty"`
+	Target       string                 `json:"target,omitempty"`
+	MergeCommit  string                 `json:"merge_commit,omitempty"`
+	Base         string                 `json:"base"` // Commit the branch's changes are measured from
+	MergedAt     time.Time              `json:"merged_at"`
+	Files        []FileChange           `json:"files"`
+	Packages     []string               `json:"packages"` // Directories with changed files; "." is the repo root
+	Dependencies []deppolicy.Dependency `json:"dependencies,omitempty"`
+	API          []APIChange            `json:"api,omitempty"`
+}
+
+// FileChange is one changed file.
+type FileChange struct {
+	Path    string `json:"path"`
+	Status  string `json:"status"`            // A, M, D or T, as git diff --name-status
+	Added   int    `json:"added,omitempty"`   // Lines; 0 for binary files
+	Deleted int    `json:"deleted,omitempty"` // Lines; 0 for binary files
+}
+
+// NewDependencies returns the dependencies the change adds (as opposed to
+// re-versions).
+func (s *Summary) NewDependencies() []deppolicy.Dependency {
+	var out []deppolicy.Dependency
+	for _, d := range s.Dependencies {
+		if d.Previous == "" {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// Headline is a one-line summary, e.g. "12 files (+210 -35) in 3 packages,
+// 1 new dependency, API +2 ~1 -0".
+func (s *Summary) Headline() string {
+	added, deleted := 0, 0
+	for _, f := range s.Files {
+		added += f.Added
+		deleted += f.Deleted
+	}
+	parts := []string{fmt.Sprintf("%s (+%d -%d) in %s",
+		plural(len(s.Files), "file"), added, deleted, plural(len(s.Packages), "package"))}
+	if n := len(s.NewDependencies()); n > 0 {
+		parts = append(parts, plural(n, "new dependency"))
+	}
+	if upgraded := len(s.Dependencies) - len(s.NewDependencies()); upgraded > 0 {
+		parts = append(parts, plural(upgraded, "dependency update"))
+	}
+	if len(s.API) > 0 {
+		var a, c, r int
+		for _, api := range s.API {
+			a += len(api.Added)
+			c += len(api.Changed)
+			r += len(api.Removed)
+		}
+		parts = append(parts, fmt.Sprintf("API +%d ~%d -%d", a, c, r))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	if strings.HasSuffix(noun, "y") {
+		return fmt.Sprintf("%d %sies", n, strings.TrimSuffix(noun, "y"))
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// Generate summarizes the changes between base and head in the repository
+// at dir. Identification fields (Bead, MR, ...) are left to the caller.
+func Generate(dir, base, head string) (*Summary, error) {
+	s := &Summary{Base: base, MergedAt: time.Now().UTC()}
+
+	nameStatus, err := git(dir, "diff", "--name-status", "--no-renames", base, head)
+	if err != nil {
+		return nil, err
+	}
+	numstat, err := git(dir, "diff", "--numstat", "--no-renames", base, head)
+	if err != nil {
+		return nil, err
+	}
+	lines := map[string][2]int{}
+	for _, line := range strings.Split(numstat, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		a, _ := strconv.Atoi(fields[0]) // "-" for binary files
+		d, _ := strconv.Atoi(fields[1])
+		lines[fields[2]] = [2]int{a, d}
+	}
+
+	packages := map[string]bool{}
+	for _, line := range strings.Split(nameStatus, "\n") {
+		status, file, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		n := lines[file]
+		s.Files = append(s.Files, FileChange{Path: file, Status: status, Added: n[0], Deleted: n[1]})
+		packages[path.Dir(file)] = true
+
+		if deppolicy.ManifestEcosystem(file) != "" {
+			before, _ := git(dir, "show", base+":"+file) // Missing = newly added manifest
+			after, _ := git(dir, "show", head+":"+file)  // Missing = deleted manifest
+			s.Dependencies = append(s.Dependencies, deppolicy.Changes(file, before, after)...)
+		}
+	}
+	for p := range packages {
+		s.Packages = append(s.Packages, p)
+	}
+	sort.Strings(s.Packages)
+
+	s.API, err = goAPIChanges(dir, base, head, s.Files)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Dir is where a rig's change summaries are kept.
+func Dir(rigPath string) string {
+	return filepath.Join(rigPath, constants.DirRuntime, "changes")
+}
+
+// Save records a summary, replacing any earlier one for the bead.
+func Save(rigPath string, s *Summary) error {
+	if s.Bead == "" {
+		return fmt.Errorf("change summary has no bead")
+	}
+	if err := os.MkdirAll(Dir(rigPath), 0755); err != nil {
+		return fmt.Errorf("creating changes directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(Dir(rigPath), s.Bead+".json"), append(data, '\n'), 0644) //nolint:gosec // G306: summaries are not sensitive
+}
+
+// Load returns the summary recorded for a bead, or nil if there is none.
+func Load(rigPath, bead string) (*Summary, error) {
+	data, err := os.ReadFile(filepath.Join(Dir(rigPath), bead+".json")) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var s Summary
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing change summary for %s: %w", bead, err)
+	}
+	return &s, nil
+}
+
+// List returns a rig's summaries, oldest merge first. Unreadable files are
+// skipped.
+func List(rigPath string) ([]*Summary, error) {
+	entries, err := os.ReadDir(Dir(rigPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []*Summary
+	for _, entry := range entries {
+		bead, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if s, err := Load(rigPath, bead); err == nil && s != nil {
+			out = append(out, s)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].MergedAt.Before(out[j].MergedAt) })
+	return out, nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
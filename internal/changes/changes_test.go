@@ -0,0 +1,167 @@
+package changes
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func gitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q", "--initial-branch=main")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "user.name", "Test")
+	return dir
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func commitFiles(t *testing.T, dir, msg string, files map[string]string) string {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if content == "" {
+			if err := os.Remove(p); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", msg)
+	return runGit(t, dir, "rev-parse", "HEAD")
+}
+
+func TestGenerate(t *testing.T) {
+	dir := gitRepo(t)
+	base := commitFiles(t, dir, "base", map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.22\n\nrequire example.com/old v1.0.0\n",
+		"pkg/queue/queue.go": `package queue
+
+// Queue holds work.
+type Queue struct{ items []string }
+
+func (q *Queue) Push(s string) { q.items = append(q.items, s) }
+
+func (q *Queue) Len() int { return len(q.items) }
+
+func Drain(q *Queue) {}
+
+func helper() {}
+`,
+		"README.md": "hello\n",
+		"old.txt":   "bye\n",
+	})
+	head := commitFiles(t, dir, "change", map[string]string{
+		"go.mod": "module example.com/m\n\ngo 1.22\n\nrequire (\n\texample.com/old v1.1.0\n\texample.com/new v0.3.0\n)\n",
+		"pkg/queue/queue.go": `package queue
+
+// Queue holds work, now documented differently.
+type Queue struct{ items []string }
+
+// Push adds to the queue.
+func (q *Queue) Push(s string, front bool) { q.items = append(q.items, s) }
+
+func (q *Queue) Len() int { return len(q.items) }
+
+func (q *Queue) Peek() string { return q.items[0] }
+
+func helper2() {}
+`,
+		"pkg/queue/queue_test.go": "package queue\n\nfunc TestX() {}\n",
+		"README.md":               "hello\nworld\n",
+		"old.txt":                 "",
+	})
+
+	s, err := Generate(dir, base, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]FileChange{}
+	for _, f := range s.Files {
+		files[f.Path] = f
+	}
+	if f := files["README.md"]; f.Status != "M" || f.Added != 1 || f.Deleted != 0 {
+		t.Errorf("README.md = %+v", f)
+	}
+	if f := files["old.txt"]; f.Status != "D" {
+		t.Errorf("old.txt = %+v", f)
+	}
+	if f := files["pkg/queue/queue_test.go"]; f.Status != "A" {
+		t.Errorf("queue_test.go = %+v", f)
+	}
+	if got := strings.Join(s.Packages, ","); got != ".,pkg/queue" {
+		t.Errorf("Packages = %s", got)
+	}
+
+	if len(s.Dependencies) != 2 || len(s.NewDependencies()) != 1 || s.NewDependencies()[0].Name != "example.com/new" {
+		t.Errorf("Dependencies = %+v", s.Dependencies)
+	}
+
+	if len(s.API) != 1 {
+		t.Fatalf("API = %+v", s.API)
+	}
+	api := s.API[0]
+	if api.Package != "pkg/queue" ||
+		strings.Join(api.Added, ",") != "Queue.Peek" ||
+		strings.Join(api.Changed, ",") != "Queue.Push" ||
+		strings.Join(api.Removed, ",") != "Drain" {
+		t.Errorf("API = %+v", api)
+	}
+
+	want := "5 files (+13 -6) in 2 packages, 1 new dependency, 1 dependency update, API +1 ~1 -1"
+	if got := s.Headline(); got != want {
+		t.Errorf("Headline = %q, want %q", got, want)
+	}
+}
+
+func TestSaveLoadList(t *testing.T) {
+	rigPath := t.TempDir()
+	if s, err := Load(rigPath, "gt-none"); err != nil || s != nil {
+		t.Errorf("Load missing = %v, %v", s, err)
+	}
+	if list, err := List(rigPath); err != nil || list != nil {
+		t.Errorf("List empty = %v, %v", list, err)
+	}
+
+	now := time.Now().UTC()
+	for i, bead := range []string{"gt-b", "gt-a"} {
+		s := &Summary{Bead: bead, MergedAt: now.Add(time.Duration(i) * time.Minute), Files: []FileChange{{Path: "x", Status: "M"}}}
+		if err := Save(rigPath, s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Save(rigPath, &Summary{}); err == nil {
+		t.Error("a summary without a bead should not be saved")
+	}
+
+	s, err := Load(rigPath, "gt-a")
+	if err != nil || s == nil || s.Files[0].Path != "x" {
+		t.Fatalf("Load = %+v, %v", s, err)
+	}
+	list, err := List(rigPath)
+	if err != nil || len(list) != 2 || list[0].Bead != "gt-b" || list[1].Bead != "gt-a" {
+		t.Errorf("List = %+v, %v", list, err)
+	}
+}
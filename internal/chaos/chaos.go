@@ -0,0 +1,279 @@
+// Package chaos injects faults into a test town so recovery paths get
+// exercised before production finds them: sessions are killed, bd calls
+// are delayed, mail is dropped and heartbeats are knocked out of time.
+//
+// gt town chaos drives a run. It kills sessions and skews heartbeats
+// itself, and publishes the passive faults in .runtime/chaos.json, which
+// every gt process in the town consults: the beads wrapper delays bd calls
+// and the mail router drops messages while the file is live. The file
+// carries its own expiry, so a crashed run can't leave a town in chaos.
+package chaos
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Fault kinds.
+const (
+	KindKill     = "kill"      // A session was killed
+	KindSkew     = "skew"      // A heartbeat's timestamp was moved
+	KindBDDelay  = "bd_delay"  // A bd call was held back
+	KindMailDrop = "mail_drop" // A message was silently discarded
+)
+
+// Files under <town>/.runtime.
+const (
+	StateFile = "chaos.json"
+	LogFile   = "chaos.jsonl"
+)
+
+// Profile sets how hard a run pushes.
+type Profile struct {
+	Name string `json:"name"`
+
+	KillEvery time.Duration `json:"kill_every"` // Mean time between session kills; 0 disables
+	KillRoles []string      `json:"kill_roles"` // Roles whose sessions may be killed
+
+	SkewEvery time.Duration `json:"skew_every"` // Mean time between heartbeat skews; 0 disables
+	Skew      time.Duration `json:"skew"`       // How far a heartbeat is moved, backwards or forwards
+
+	BDDelay     time.Duration `json:"bd_delay"`      // Longest delay added to a bd call
+	BDDelayRate float64       `json:"bd_delay_rate"` // Fraction of bd calls delayed
+	MailDrop    float64       `json:"mail_drop"`     // Fraction of messages dropped
+}
+
+// Profiles are the built-in profiles, gentlest first.
+var Profiles = []Profile{
+	{
+		Name:      "mild",
+		KillEvery: 5 * time.Minute, KillRoles: []string{"polecat"},
+		SkewEvery: 10 * time.Minute, Skew: 10 * time.Minute,
+		BDDelay: 2 * time.Second, BDDelayRate: 0.05,
+		MailDrop: 0.02,
+	},
+	{
+		Name:      "moderate",
+		KillEvery: 2 * time.Minute, KillRoles: []string{"polecat", "witness", "refinery"},
+		SkewEvery: 4 * time.Minute, Skew: 20 * time.Minute,
+		BDDelay: 5 * time.Second, BDDelayRate: 0.15,
+		MailDrop: 0.05,
+	},
+	{
+		Name:      "severe",
+		KillEvery: 45 * time.Second, KillRoles: []string{"polecat", "witness", "refinery", "deacon", "dog"},
+		SkewEvery: 90 * time.Second, Skew: time.Hour,
+		BDDelay: 10 * time.Second, BDDelayRate: 0.3,
+		MailDrop: 0.15,
+	},
+}
+
+// LookupProfile returns the built-in profile with the given name.
+func LookupProfile(name string) (Profile, error) {
+	for _, p := range Profiles {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	var names []string
+	for _, p := range Profiles {
+		names = append(names, p.Name)
+	}
+	return Profile{}, fmt.Errorf("unknown chaos profile %q (want %s)", name, strings.Join(names, ", "))
+}
+
+// Kills reports whether the profile may kill sessions of role.
+func (p Profile) Kills(role string) bool {
+	for _, r := range p.KillRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// State is the live part of a run that other processes act on.
+type State struct {
+	Profile     string        `json:"profile"`
+	Started     time.Time     `json:"started"`
+	Until       time.Time     `json:"until"`
+	BDDelay     time.Duration `json:"bd_delay"`
+	BDDelayRate float64       `json:"bd_delay_rate"`
+	MailDrop    float64       `json:"mail_drop"`
+}
+
+// NewState returns the state for running p from now for d.
+func NewState(p Profile, now time.Time, d time.Duration) *State {
+	return &State{
+		Profile:     p.Name,
+		Started:     now,
+		Until:       now.Add(d),
+		BDDelay:     p.BDDelay,
+		BDDelayRate: p.BDDelayRate,
+		MailDrop:    p.MailDrop,
+	}
+}
+
+// StatePath returns the path of the town's chaos state file.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", StateFile)
+}
+
+// Start publishes st to the town.
+func Start(townRoot string, st *State) error {
+	path := StatePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Stop withdraws the town's chaos state.
+func Stop(townRoot string) error {
+	err := os.Remove(StatePath(townRoot))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Load returns the town's chaos state, or nil when no run is live.
+func Load(townRoot string) *State {
+	if townRoot == "" {
+		return nil
+	}
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed from townRoot
+	if err != nil {
+		return nil
+	}
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil || !time.Now().Before(st.Until) {
+		return nil
+	}
+	return &st
+}
+
+// rollFn is a seam for tests. Production uses rand.Float64 to decide the
+// passive faults.
+var rollFn = rand.Float64
+
+// DelayBD holds back a bd call when a run is live and the roll says so.
+// It returns the delay applied.
+func DelayBD(townRoot, command string) time.Duration {
+	st := Load(townRoot)
+	if st == nil || st.BDDelay <= 0 || rollFn() >= st.BDDelayRate {
+		return 0
+	}
+	d := time.Duration(rollFn() * float64(st.BDDelay))
+	_ = Record(townRoot, Fault{At: time.Now(), Kind: KindBDDelay, Target: command, Detail: d.Round(time.Millisecond).String()})
+	time.Sleep(d)
+	return d
+}
+
+// DropMail reports whether a message should be discarded instead of
+// delivered. The sender isn't told: that's the fault.
+func DropMail(townRoot, from, to, subject string) bool {
+	st := Load(townRoot)
+	if st == nil || st.MailDrop <= 0 || rollFn() >= st.MailDrop {
+		return false
+	}
+	_ = Record(townRoot, Fault{At: time.Now(), Kind: KindMailDrop, Target: to, Detail: fmt.Sprintf("from %s: %s", from, subject)})
+	return true
+}
+
+// Fault is one injected fault, as logged.
+type Fault struct {
+	At     time.Time `json:"at"`
+	Kind   string    `json:"kind"`
+	Target string    `json:"target"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// LogPath returns the path of the town's fault log.
+func LogPath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", LogFile)
+}
+
+// Record appends a fault to the town's log.
+func Record(townRoot string, f Fault) error {
+	path := LogPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G304: path is constructed from townRoot
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// ReadLog returns the faults logged at or after since, oldest first.
+func ReadLog(townRoot string, since time.Time) ([]Fault, error) {
+	file, err := os.Open(LogPath(townRoot)) //nolint:gosec // G304: path is constructed from townRoot
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+	var faults []Fault
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var f Fault
+		if json.Unmarshal(scanner.Bytes(), &f) != nil || f.At.Before(since) {
+			continue
+		}
+		faults = append(faults, f)
+	}
+	sort.SliceStable(faults, func(i, j int) bool { return faults[i].At.Before(faults[j].At) })
+	return faults, scanner.Err()
+}
+
+// SkewTimestamp moves the "timestamp" field of a JSON heartbeat file by
+// delta, leaving the other fields alone.
+func SkewTimestamp(path string, delta time.Duration) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: heartbeat path chosen by the caller
+	if err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("parsing %s: %w", path, err)
+	}
+	var ts time.Time
+	if err := json.Unmarshal(fields["timestamp"], &ts); err != nil {
+		return fmt.Errorf("%s has no timestamp: %w", path, err)
+	}
+	moved, err := json.Marshal(ts.Add(delta))
+	if err != nil {
+		return err
+	}
+	fields["timestamp"] = moved
+	out, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, info.Mode().Perm())
+}
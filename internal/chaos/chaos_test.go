@@ -0,0 +1,146 @@
+package chaos
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func stubRoll(t *testing.T, values ...float64) {
+	t.Helper()
+	prev := rollFn
+	t.Cleanup(func() { rollFn = prev })
+	rollFn = func() float64 {
+		v := values[0]
+		if len(values) > 1 {
+			values = values[1:]
+		}
+		return v
+	}
+}
+
+func TestPlan_DeterministicAndSorted(t *testing.T) {
+	p, err := LookupProfile("severe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := Plan(p, 30*time.Minute, rand.New(rand.NewPCG(7, 7)))
+	b := Plan(p, 30*time.Minute, rand.New(rand.NewPCG(7, 7)))
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("same seed gave different plans")
+	}
+	kinds := map[string]int{}
+	for i, act := range a {
+		if act.At <= 0 || act.At >= 30*time.Minute || (i > 0 && act.At < a[i-1].At) {
+			t.Fatalf("action %d at %s out of order or range", i, act.At)
+		}
+		kinds[act.Kind]++
+	}
+	// ~40 kills at one every 45s and ~20 skews at one every 90s.
+	if kinds[KindKill] < 20 || kinds[KindSkew] < 8 {
+		t.Errorf("plan kinds = %v, too few for severe over 30m", kinds)
+	}
+}
+
+func TestLookupProfile(t *testing.T) {
+	mild, err := LookupProfile("mild")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mild.Kills("polecat") || mild.Kills("deacon") {
+		t.Errorf("mild should kill polecats only, got %v", mild.KillRoles)
+	}
+	if _, err := LookupProfile("apocalyptic"); err == nil {
+		t.Error("unknown profile accepted")
+	}
+}
+
+func TestLoad_ExpiresAndStops(t *testing.T) {
+	town := t.TempDir()
+	if Load(town) != nil {
+		t.Fatal("Load with no state file should be nil")
+	}
+	p, _ := LookupProfile("mild")
+	if err := Start(town, NewState(p, time.Now().Add(-time.Hour), 30*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if Load(town) != nil {
+		t.Error("expired state still live")
+	}
+	if err := Start(town, NewState(p, time.Now(), time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if st := Load(town); st == nil || st.MailDrop != p.MailDrop {
+		t.Errorf("Load = %+v, want live mild state", st)
+	}
+	if err := Stop(town); err != nil || Load(town) != nil {
+		t.Errorf("after Stop: err=%v, live=%v", err, Load(town) != nil)
+	}
+}
+
+func TestPassiveFaults(t *testing.T) {
+	town := t.TempDir()
+	if DropMail(town, "mayor/", "gastown/witness", "hi") {
+		t.Fatal("dropped mail with no run live")
+	}
+	started := time.Now()
+	st := &State{Started: started, Until: started.Add(time.Minute), BDDelay: 100 * time.Millisecond, BDDelayRate: 0.5, MailDrop: 0.5}
+	if err := Start(town, st); err != nil {
+		t.Fatal(err)
+	}
+
+	stubRoll(t, 0.9)
+	if DropMail(town, "mayor/", "gastown/witness", "hi") || DelayBD(town, "show") != 0 {
+		t.Error("fault injected on a roll above the rate")
+	}
+
+	stubRoll(t, 0.1)
+	if !DropMail(town, "mayor/", "gastown/witness", "hi") {
+		t.Error("mail not dropped on a roll below the rate")
+	}
+	if d := DelayBD(town, "show"); d != 10*time.Millisecond {
+		t.Errorf("DelayBD = %s, want 10ms", d)
+	}
+
+	faults, err := ReadLog(town, started)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(faults) != 2 || faults[0].Kind != KindMailDrop || faults[0].Target != "gastown/witness" || faults[1].Kind != KindBDDelay {
+		t.Errorf("logged faults = %+v", faults)
+	}
+	if later, _ := ReadLog(town, time.Now().Add(time.Minute)); len(later) != 0 {
+		t.Errorf("ReadLog since the future = %d faults", len(later))
+	}
+}
+
+func TestSkewTimestamp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "heartbeat.json")
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	data, _ := json.Marshal(map[string]any{"timestamp": ts, "state": "working", "bead": "gt-abc"})
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SkewTimestamp(path, -10*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Timestamp time.Time `json:"timestamp"`
+		Bead      string    `json:"bead"`
+	}
+	data, _ = os.ReadFile(path)
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Timestamp.Equal(ts.Add(-10*time.Minute)) || got.Bead != "gt-abc" {
+		t.Errorf("after skew = %+v", got)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600 kept", info.Mode().Perm())
+	}
+}
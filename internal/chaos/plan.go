@@ -0,0 +1,63 @@
+package chaos
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+)
+
+// Action is a fault the runner injects itself, at an offset into the run.
+type Action struct {
+	At   time.Duration
+	Kind string // KindKill or KindSkew
+}
+
+// Plan schedules a run's kills and skews over d. Arrivals are Poisson, so
+// faults sometimes bunch up the way real failures do. The same seed gives
+// the same schedule.
+func Plan(p Profile, d time.Duration, rng *rand.Rand) []Action {
+	var actions []Action
+	for _, stream := range []struct {
+		kind  string
+		every time.Duration
+	}{{KindKill, p.KillEvery}, {KindSkew, p.SkewEvery}} {
+		if stream.every <= 0 {
+			continue
+		}
+		for at := arrival(stream.every, rng); at < d; at += arrival(stream.every, rng) {
+			actions = append(actions, Action{At: at, Kind: stream.kind})
+		}
+	}
+	sort.SliceStable(actions, func(i, j int) bool { return actions[i].At < actions[j].At })
+	return actions
+}
+
+// arrival draws an exponentially distributed gap with the given mean,
+// floored at a second so the runner's tick can keep up.
+func arrival(mean time.Duration, rng *rand.Rand) time.Duration {
+	gap := time.Duration(-math.Log(1-rng.Float64()) * float64(mean))
+	if gap < time.Second {
+		gap = time.Second
+	}
+	return gap
+}
+
+// Pick returns a random element of candidates, or "" when there are none.
+func Pick(candidates []string, rng *rand.Rand) string {
+	if len(candidates) == 0 {
+		return ""
+	}
+	return candidates[rng.IntN(len(candidates))]
+}
+
+// SkewDelta returns how far to move a heartbeat: usually back by up to
+// skew, which looks like a hung agent, sometimes forward, which looks like
+// clock drift and must not make a dead agent look alive for long.
+func SkewDelta(skew time.Duration, rng *rand.Rand) time.Duration {
+	d := time.Duration((0.5 + rng.Float64()/2) * float64(skew))
+	if rng.IntN(4) == 0 {
+		return d
+	}
+	return -d
+}
@@ -0,0 +1,41 @@
+package chaos
+
+import "time"
+
+// Check is one recovery invariant's verdict after a run.
+type Check struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Report is the outcome of a run.
+type Report struct {
+	Profile  string        `json:"profile"`
+	Seed     uint64        `json:"seed"`
+	Duration time.Duration `json:"duration"`
+	Settled  time.Duration `json:"settled"` // Time the town took to satisfy every invariant
+	Faults   []Fault       `json:"faults"`
+	Checks   []Check       `json:"checks"`
+}
+
+// Passed reports whether every invariant held.
+func (r *Report) Passed() bool {
+	for _, c := range r.Checks {
+		if !c.OK {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns the number of faults of a kind.
+func (r *Report) Count(kind string) int {
+	n := 0
+	for _, f := range r.Faults {
+		if f.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
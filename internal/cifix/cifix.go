@@ -0,0 +1,133 @@
+// Package cifix closes the loop between CI and the polecat that pushed the
+// branch.
+//
+// When CI fails on a polecat's branch, a follow-up bead carrying the failing
+// job logs is slung back to the same polecat (or its rig, if the polecat is
+// gone), up to a per-branch attempt limit. Each failing commit is handled at
+// most once, so polling and webhooks can both feed it safely.
+package cifix
+
+import (
+	"strings"
+	"time"
+)
+
+// Defaults for Config fields left unset.
+const (
+	DefaultMaxAttempts = 2
+	DefaultLogLines    = 200
+)
+
+// Config configures CI failure remediation (settings/config.json "ci_remediation").
+// Polling is turned on by the daemon's ci_remediation patrol; these settings
+// also apply to manual gt ci runs.
+type Config struct {
+	// MaxAttempts caps follow-ups per branch before a human is needed.
+	// Default: 2.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+
+	// LogLines is how many trailing lines of each failing job's log are
+	// attached to the follow-up. Default: 200.
+	LogLines int `json:"log_lines,omitempty"`
+
+	// Rigs limits remediation to these rigs. Empty = all rigs.
+	Rigs []string `json:"rigs,omitempty"`
+}
+
+// GetMaxAttempts returns the attempt cap or the default.
+func (c *Config) GetMaxAttempts() int {
+	if c == nil || c.MaxAttempts <= 0 {
+		return DefaultMaxAttempts
+	}
+	return c.MaxAttempts
+}
+
+// GetLogLines returns the log tail length or the default.
+func (c *Config) GetLogLines() int {
+	if c == nil || c.LogLines <= 0 {
+		return DefaultLogLines
+	}
+	return c.LogLines
+}
+
+// CoversRig reports whether the rig is in scope.
+func (c *Config) CoversRig(rig string) bool {
+	if c == nil || len(c.Rigs) == 0 {
+		return true
+	}
+	for _, r := range c.Rigs {
+		if r == rig {
+			return true
+		}
+	}
+	return false
+}
+
+// Run is a CI workflow run, as reported by gh run list.
+type Run struct {
+	ID         int64     `json:"databaseId"`
+	Workflow   string    `json:"workflowName"`
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion"`
+	HeadSHA    string    `json:"headSha"`
+	URL        string    `json:"url"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Failed reports whether the run completed unsuccessfully.
+func (r Run) Failed() bool {
+	if r.Status != "completed" {
+		return false
+	}
+	switch r.Conclusion {
+	case "failure", "timed_out", "startup_failure":
+		return true
+	}
+	return false
+}
+
+// LatestSHA returns the head commit of the most recent run.
+func LatestSHA(runs []Run) string {
+	var latest Run
+	for _, r := range runs {
+		if latest.HeadSHA == "" || r.CreatedAt.After(latest.CreatedAt) {
+			latest = r
+		}
+	}
+	return latest.HeadSHA
+}
+
+// Failures returns the failed runs for sha, keeping only the newest run of
+// each workflow (a re-run that passed supersedes an earlier failure).
+func Failures(runs []Run, sha string) []Run {
+	newest := make(map[string]Run)
+	var order []string
+	for _, r := range runs {
+		if r.HeadSHA != sha {
+			continue
+		}
+		prev, seen := newest[r.Workflow]
+		if !seen {
+			order = append(order, r.Workflow)
+		}
+		if !seen || r.CreatedAt.After(prev.CreatedAt) {
+			newest[r.Workflow] = r
+		}
+	}
+	var failed []Run
+	for _, w := range order {
+		if newest[w].Failed() {
+			failed = append(failed, newest[w])
+		}
+	}
+	return failed
+}
+
+// TailLines returns the last n lines of s.
+func TailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) <= n {
+		return strings.Join(lines, "\n")
+	}
+	return "…\n" + strings.Join(lines[len(lines)-n:], "\n")
+}
@@ -0,0 +1,148 @@
+package cifix
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigDefaults(t *testing.T) {
+	var c *Config
+	if c.GetMaxAttempts() != DefaultMaxAttempts || c.GetLogLines() != DefaultLogLines {
+		t.Errorf("nil config defaults = %d, %d", c.GetMaxAttempts(), c.GetLogLines())
+	}
+	if !c.CoversRig("gastown") {
+		t.Error("nil config should cover all rigs")
+	}
+	c = &Config{MaxAttempts: 5, LogLines: 50, Rigs: []string{"beads"}}
+	if c.GetMaxAttempts() != 5 || c.GetLogLines() != 50 {
+		t.Errorf("config values = %d, %d", c.GetMaxAttempts(), c.GetLogLines())
+	}
+	if c.CoversRig("gastown") || !c.CoversRig("beads") {
+		t.Error("CoversRig should honour the rig list")
+	}
+}
+
+func TestFailures(t *testing.T) {
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	runs := []Run{
+		{ID: 1, Workflow: "test", Status: "completed", Conclusion: "failure", HeadSHA: "aaa", CreatedAt: base},
+		{ID: 2, Workflow: "lint", Status: "completed", Conclusion: "failure", HeadSHA: "bbb", CreatedAt: base.Add(time.Minute)},
+		{ID: 3, Workflow: "test", Status: "completed", Conclusion: "failure", HeadSHA: "bbb", CreatedAt: base.Add(2 * time.Minute)},
+		{ID: 4, Workflow: "lint", Status: "completed", Conclusion: "success", HeadSHA: "bbb", CreatedAt: base.Add(3 * time.Minute)}, // re-run passed
+		{ID: 5, Workflow: "build", Status: "in_progress", HeadSHA: "bbb", CreatedAt: base.Add(4 * time.Minute)},
+	}
+
+	sha := LatestSHA(runs)
+	if sha != "bbb" {
+		t.Fatalf("LatestSHA() = %q, want bbb", sha)
+	}
+	got := Failures(runs, sha)
+	if len(got) != 1 || got[0].ID != 3 {
+		t.Errorf("Failures() = %+v, want only run 3", got)
+	}
+	if len(Failures(runs, "ccc")) != 0 {
+		t.Error("Failures() for unknown sha should be empty")
+	}
+	if LatestSHA(nil) != "" {
+		t.Error("LatestSHA(nil) should be empty")
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	if got := TailLines("a\nb\n", 5); got != "a\nb" {
+		t.Errorf("TailLines short = %q", got)
+	}
+	if got := TailLines("a\nb\nc\nd\n", 2); got != "…\nc\nd" {
+		t.Errorf("TailLines long = %q", got)
+	}
+}
+
+func TestListRunsAndFailedLog(t *testing.T) {
+	orig := runGH
+	defer func() { runGH = orig }()
+	var calls []string
+	runGH = func(dir string, args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(args, " "))
+		if args[1] == "list" {
+			return []byte(`[{"databaseId":42,"workflowName":"test","status":"completed","conclusion":"failure","headSha":"abc","url":"https://example/42"}]`), nil
+		}
+		var b strings.Builder
+		for i := 1; i <= 10; i++ {
+			fmt.Fprintf(&b, "line %d\n", i)
+		}
+		return []byte(b.String()), nil
+	}
+
+	runs, err := ListRuns("/repo", "polecat/Toast/gt-1")
+	if err != nil {
+		t.Fatalf("ListRuns() error = %v", err)
+	}
+	if len(runs) != 1 || runs[0].ID != 42 || !runs[0].Failed() {
+		t.Fatalf("ListRuns() = %+v", runs)
+	}
+	if !strings.Contains(calls[0], "--branch polecat/Toast/gt-1") {
+		t.Errorf("gh call = %q, want --branch", calls[0])
+	}
+
+	log, err := FailedLog("/repo", 42, 3)
+	if err != nil {
+		t.Fatalf("FailedLog() error = %v", err)
+	}
+	if log != "…\nline 8\nline 9\nline 10" {
+		t.Errorf("FailedLog() = %q", log)
+	}
+	if calls[1] != "run view 42 --log-failed" {
+		t.Errorf("gh call = %q", calls[1])
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	town := t.TempDir()
+	s, err := LoadState(town)
+	if err != nil {
+		t.Fatalf("LoadState() on empty town error = %v", err)
+	}
+	b := s.Branch("polecat/Toast/gt-1")
+	if b.IsHandled("abc") {
+		t.Error("new branch should have nothing handled")
+	}
+	b.RecordFollowUp("abc", "gt-fix1", time.Now())
+	if err := s.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadState(town)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	lb := loaded.Branch("polecat/Toast/gt-1")
+	if lb.Attempts != 1 || !lb.IsHandled("abc") || lb.FollowUps[0] != "gt-fix1" {
+		t.Errorf("loaded state = %+v", lb)
+	}
+}
+
+func TestFollowUp(t *testing.T) {
+	f := FollowUp{
+		Branch:      "polecat/Toast/gt-1",
+		SHA:         "0123456789abcdef",
+		SourceIssue: "gt-1",
+		MergeReq:    "gt-mr1",
+		Attempt:     1,
+		MaxAttempts: 2,
+		Failures: []FailureLog{
+			{Run: Run{Workflow: "test", Conclusion: "failure", URL: "https://example/1"}, Log: "FAIL TestThing"},
+			{Run: Run{Workflow: "lint", Conclusion: "timed_out", URL: "https://example/2"}},
+		},
+	}
+	if got := f.Title(); got != "Fix CI (test, lint) for gt-1" {
+		t.Errorf("Title() = %q", got)
+	}
+	desc := f.Description()
+	for _, want := range []string{"01234567", "attempt 1 of 2", "gt-mr1", "FAIL TestThing", "log unavailable", "https://example/2"} {
+		if !strings.Contains(desc, want) {
+			t.Errorf("Description() missing %q:\n%s", want, desc)
+		}
+	}
+}
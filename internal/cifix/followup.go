@@ -0,0 +1,66 @@
+package cifix
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FailureLog pairs a failed run with its log tail.
+type FailureLog struct {
+	Run Run
+	Log string // Empty if logs could not be fetched
+}
+
+// FollowUp describes the remediation bead for a failing branch.
+type FollowUp struct {
+	Branch      string
+	SHA         string
+	SourceIssue string // Original work bead, if known
+	MergeReq    string // MR bead, if known
+	Attempt     int    // 1-based
+	MaxAttempts int
+	Failures    []FailureLog
+}
+
+// Title returns the follow-up bead title.
+func (f FollowUp) Title() string {
+	names := make([]string, 0, len(f.Failures))
+	for _, fl := range f.Failures {
+		names = append(names, fl.Run.Workflow)
+	}
+	subject := f.Branch
+	if f.SourceIssue != "" {
+		subject = f.SourceIssue
+	}
+	return fmt.Sprintf("Fix CI (%s) for %s", strings.Join(names, ", "), subject)
+}
+
+// Description returns the follow-up body with failing job logs attached.
+func (f FollowUp) Description() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "CI failed on branch %s at %s (attempt %d of %d).\n\n", f.Branch, shortSHA(f.SHA), f.Attempt, f.MaxAttempts)
+	if f.SourceIssue != "" {
+		fmt.Fprintf(&b, "Original work: %s\n", f.SourceIssue)
+	}
+	if f.MergeReq != "" {
+		fmt.Fprintf(&b, "Merge request: %s\n", f.MergeReq)
+	}
+	b.WriteString("\nFix the failures on the same branch, push, and run gt done. ")
+	b.WriteString("Do not declare success until CI is green.\n")
+	for _, fl := range f.Failures {
+		fmt.Fprintf(&b, "\n## %s (%s)\n%s\n", fl.Run.Workflow, fl.Run.Conclusion, fl.Run.URL)
+		if fl.Log != "" {
+			fmt.Fprintf(&b, "\n```\n%s\n```\n", fl.Log)
+		} else {
+			b.WriteString("\n(log unavailable: see the run URL)\n")
+		}
+	}
+	return b.String()
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -0,0 +1,60 @@
+package cifix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// runGH executes gh in dir. Replaced in tests.
+var runGH = func(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("gh", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("gh %s: %v: %s", strings.Join(args[:2], " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// ListRuns returns recent CI runs for a branch of the repo checked out at dir.
+func ListRuns(dir, branch string) ([]Run, error) {
+	out, err := runGH(dir, "run", "list", "--branch", branch, "--limit", "30",
+		"--json", "databaseId,workflowName,status,conclusion,headSha,url,createdAt")
+	if err != nil {
+		return nil, err
+	}
+	var runs []Run
+	if err := json.Unmarshal(out, &runs); err != nil {
+		return nil, fmt.Errorf("parsing gh run list: %w", err)
+	}
+	return runs, nil
+}
+
+// GetRun returns a single run by ID.
+func GetRun(dir string, id int64) (*Run, error) {
+	out, err := runGH(dir, "run", "view", strconv.FormatInt(id, 10),
+		"--json", "databaseId,workflowName,status,conclusion,headSha,url,createdAt")
+	if err != nil {
+		return nil, err
+	}
+	var run Run
+	if err := json.Unmarshal(out, &run); err != nil {
+		return nil, fmt.Errorf("parsing gh run view: %w", err)
+	}
+	return &run, nil
+}
+
+// FailedLog returns the tail of the failing steps' logs for a run.
+func FailedLog(dir string, id int64, lines int) (string, error) {
+	out, err := runGH(dir, "run", "view", strconv.FormatInt(id, 10), "--log-failed")
+	if err != nil {
+		return "", err
+	}
+	return TailLines(string(out), lines), nil
+}
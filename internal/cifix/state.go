@@ -0,0 +1,94 @@
+package cifix
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// BranchState tracks remediation for one branch.
+type BranchState struct {
+	Attempts  int       `json:"attempts"`
+	Handled   []string  `json:"handled_shas"`        // Failing commits already acted on
+	FollowUps []string  `json:"follow_ups"`          // Beads created, oldest first
+	Exhausted bool      `json:"exhausted,omitempty"` // Attempt cap reached and reported
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// State is the remediation ledger in <town>/.runtime/ci-remediation.json.
+type State struct {
+	Branches map[string]*BranchState `json:"branches"`
+	path     string
+}
+
+// StatePath returns the ledger path for a town.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "ci-remediation.json")
+}
+
+// LoadState reads the ledger; a missing file yields an empty one.
+func LoadState(townRoot string) (*State, error) {
+	s := &State{Branches: make(map[string]*BranchState), path: StatePath(townRoot)}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("reading CI remediation state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing CI remediation state: %w", err)
+	}
+	if s.Branches == nil {
+		s.Branches = make(map[string]*BranchState)
+	}
+	return s, nil
+}
+
+// Branch returns (creating if needed) the state for a branch.
+func (s *State) Branch(branch string) *BranchState {
+	b, ok := s.Branches[branch]
+	if !ok {
+		b = &BranchState{}
+		s.Branches[branch] = b
+	}
+	return b
+}
+
+// IsHandled reports whether a failing commit was already acted on.
+func (b *BranchState) IsHandled(sha string) bool {
+	for _, h := range b.Handled {
+		if h == sha {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordFollowUp marks sha handled by beadID and counts the attempt.
+func (b *BranchState) RecordFollowUp(sha, beadID string, now time.Time) {
+	b.Attempts++
+	b.Handled = append(b.Handled, sha)
+	b.FollowUps = append(b.FollowUps, beadID)
+	b.UpdatedAt = now
+}
+
+// Save writes the ledger atomically.
+func (s *State) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: state is not sensitive
+		return fmt.Errorf("writing CI remediation state: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rbac"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// accessExemptCommands always run, so a denied human can still get help.
+var accessExemptCommands = map[string]bool{
+	"help":       true,
+	"version":    true,
+	"completion": true,
+}
+
+// accessRepairCommands run under an invalid access policy, with a warning,
+// so the policy can be fixed.
+var accessRepairCommands = map[string]bool{
+	"config": true,
+	"doctor": true,
+}
+
+// enforceAccess applies the town's RBAC policy to a human-issued command.
+// Agent sessions (GT_ROLE set) and towns without an access policy are not
+// checked, except that custom role agents are capped at the permissions
+// their role definition grants. The web dashboard shells out to gt, so this also covers its
+// command runner.
+func enforceAccess(cmd *cobra.Command, args []string) error {
+	if accessExemptCommands[cmd.Name()] {
+		return nil
+	}
+	if os.Getenv(EnvGTCustomRole) != "" {
+		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+			return enforceCustomRolePermissions(cmd, townRoot)
+		}
+		return nil
+	}
+	if os.Getenv("GT_ROLE") != "" {
+		return nil
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || !settings.Access.IsEnforced() {
+		return nil
+	}
+	if err := settings.Access.Validate(); err != nil {
+		// Fail closed, but leave a way to repair the policy.
+		top, _, _ := strings.Cut(accessCommandPath(cmd), " ")
+		if accessRepairCommands[top] {
+			style.PrintWarning("%v; access policy not enforced so it can be fixed", err)
+			return nil
+		}
+		return fmt.Errorf("%w (fix it with 'gt config' or 'gt doctor')", err)
+	}
+
+	operator := resolveShiftOperator("")
+	return settings.Access.Check(operator, accessCommand(cmd), accessTargetRig(cmd, args, townRoot))
+}
+
+// accessCommandPath returns the command path without the binary name
+// ("gt rig remove" → "rig remove").
+func accessCommandPath(cmd *cobra.Command) string {
+	fields := strings.Fields(cmd.CommandPath())
+	if len(fields) <= 1 {
+		return ""
+	}
+	return strings.Join(fields[1:], " ")
+}
+
+// accessCommand returns the command path followed by the flags the caller
+// set ("doctor --fix"), for rbac.Classify. Values are left out.
+func accessCommand(cmd *cobra.Command) string {
+	command := accessCommandPath(cmd)
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if f.Value.Type() == "bool" && f.Value.String() == "false" {
+			return
+		}
+		command += " --" + f.Name
+	})
+	return command
+}
+
+// accessTargetRig returns the rig a command acts on: the --rig flag when set,
+// otherwise the first positional argument naming a rig.
+func accessTargetRig(cmd *cobra.Command, args []string, townRoot string) string {
+	if f := cmd.Flags().Lookup("rig"); f != nil && f.Value.String() != "" {
+		return f.Value.String()
+	}
+	return rbac.TargetRig(townRoot, args)
+}
+
+// currentAccessPrincipal returns the human's principal and whether the town
+// enforces an access policy. Used by whoami.
+func currentAccessPrincipal(townRoot string) (rbac.Principal, bool) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || !settings.Access.IsEnforced() {
+		return rbac.Principal{}, false
+	}
+	return settings.Access.Lookup(resolveShiftOperator("")), true
+}
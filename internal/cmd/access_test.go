@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rbac"
+)
+
+func setupTownWithAccess(t *testing.T, access *rbac.Config) string {
+	t.Helper()
+	townRoot := setupTestTownForTheme(t)
+	settings := config.NewTownSettings()
+	settings.Access = access
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatalf("save settings: %v", err)
+	}
+	rigDir := filepath.Join(townRoot, "gastown")
+	if err := os.MkdirAll(rigDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rigDir, "config.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+	t.Setenv("GT_ROLE", "")
+	return townRoot
+}
+
+// accessTestCommand builds "gt <parent> <child>" so CommandPath matches a real command.
+func accessTestCommand(parent, child string) *cobra.Command {
+	root := &cobra.Command{Use: "gt"}
+	p := &cobra.Command{Use: parent}
+	c := &cobra.Command{Use: child}
+	c.Flags().String("rig", "", "")
+	root.AddCommand(p)
+	p.AddCommand(c)
+	return c
+}
+
+func TestEnforceAccess(t *testing.T) {
+	setupTownWithAccess(t, &rbac.Config{Principals: []rbac.Principal{
+		{Name: "alice", Role: rbac.RoleOperator},
+		{Name: "carol", Role: rbac.RoleRigAdmin, Rigs: []string{"gastown"}},
+	}})
+
+	rigRemove := accessTestCommand("rig", "remove")
+
+	t.Setenv("GT_OPERATOR", "alice")
+	if err := enforceAccess(rigRemove, []string{"gastown"}); err != nil {
+		t.Errorf("operator denied: %v", err)
+	}
+
+	t.Setenv("GT_OPERATOR", "bob") // unlisted → viewer
+	var denied *rbac.DeniedError
+	if err := enforceAccess(rigRemove, []string{"gastown"}); !errors.As(err, &denied) {
+		t.Errorf("viewer rig remove = %v, want DeniedError", err)
+	}
+	if err := enforceAccess(accessTestCommand("convoy", "list"), nil); err != nil {
+		t.Errorf("viewer convoy list denied: %v", err)
+	}
+
+	t.Setenv("GT_OPERATOR", "carol")
+	if err := enforceAccess(rigRemove, []string{"gastown"}); err != nil {
+		t.Errorf("rig admin denied on own rig: %v", err)
+	}
+	other := accessTestCommand("rig", "park")
+	if err := other.Flags().Set("rig", "beads"); err != nil {
+		t.Fatal(err)
+	}
+	if err := enforceAccess(other, nil); err == nil {
+		t.Error("rig admin allowed to park another rig")
+	}
+
+	t.Setenv("GT_OPERATOR", "bob")
+	doctor := accessTestCommand("doctor", "check")
+	doctor.Flags().Bool("fix", false, "")
+	if err := enforceAccess(doctor, nil); err != nil {
+		t.Errorf("viewer doctor denied: %v", err)
+	}
+	if err := doctor.Flags().Set("fix", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := enforceAccess(doctor, nil); !errors.As(err, &denied) {
+		t.Errorf("viewer doctor --fix = %v, want DeniedError", err)
+	}
+
+	// Agent sessions are not subject to RBAC.
+	t.Setenv("GT_OPERATOR", "bob")
+	t.Setenv("GT_ROLE", "gastown/witness")
+	if err := enforceAccess(rigRemove, []string{"gastown"}); err != nil {
+		t.Errorf("agent session denied: %v", err)
+	}
+}
+
+func TestEnforceAccess_NoPolicy(t *testing.T) {
+	setupTownWithAccess(t, nil)
+	t.Setenv("GT_OPERATOR", "bob")
+	if err := enforceAccess(accessTestCommand("rig", "remove"), []string{"gastown"}); err != nil {
+		t.Errorf("no policy should allow everything, got %v", err)
+	}
+}
+
+func TestEnforceAccess_InvalidPolicy(t *testing.T) {
+	setupTownWithAccess(t, &rbac.Config{Principals: []rbac.Principal{
+		{Name: "alice", Role: "superuser"},
+	}})
+	t.Setenv("GT_OPERATOR", "alice")
+
+	if err := enforceAccess(accessTestCommand("convoy", "list"), nil); err == nil {
+		t.Error("an invalid policy should fail closed")
+	}
+	// config and doctor still run so the policy can be repaired.
+	for _, parent := range []string{"config", "doctor"} {
+		if err := enforceAccess(accessTestCommand(parent, "set"), nil); err != nil {
+			t.Errorf("%s denied under an invalid policy: %v", parent, err)
+		}
+	}
+}
@@ -223,7 +223,7 @@ func modifyAgentState(agentBead, beadsDir string, hasIncr bool) error {
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" {
 			return fmt.Errorf("%s", errMsg)
@@ -268,7 +268,7 @@ func getAllAgentLabels(agentBead, beadsDir string) ([]string, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if strings.Contains(errMsg, "not found") {
 			return nil, fmt.Errorf("agent bead not found: %s", agentBead)
@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/analyze"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	analyzeRig  string
+	analyzeBase string
+	analyzeJSON bool
+)
+
+var analyzeCmd = &cobra.Command{
+	Use:     "analyze",
+	GroupID: GroupWork,
+	Short:   "Run the rig's static analyzers on your changes",
+	Long: `Run the rig's configured static analyzers against the current branch.
+
+Only findings on lines the branch added or changed are reported, so
+existing issues elsewhere in the codebase never block you. gt done runs
+the same check before pushing and refuses to submit while blocking
+violations remain.
+
+Analyzers are configured in <rig>/settings/config.json:
+  "static_analysis": {
+    "analyzers": [
+      {"name": "go-vet"},
+      {"name": "staticcheck"},
+      {"name": "eslint", "warn_only": true},
+      {"name": "migrations", "cmd": "./scripts/check-migrations.sh {files}", "files": ["db/*.sql"]}
+    ]
+  }
+
+Presets: go-vet, staticcheck, eslint, ruff, shellcheck. Custom commands run
+with sh -c from the repo root; {files} expands to the matching changed files
+and $GT_CHANGED_FILES / $GT_DIFF_BASE are set. Output lines of the form
+file:line[:col]: message are treated as violations.
+
+Examples:
+  gt analyze                      # Check this worktree against origin/<default>
+  gt analyze --base origin/integration/auth
+  gt analyze --json`,
+	Args: cobra.NoArgs,
+	RunE: runAnalyze,
+}
+
+func init() {
+	analyzeCmd.Flags().StringVar(&analyzeRig, "rig", "", "Rig whose analyzers to use (default: current rig)")
+	analyzeCmd.Flags().StringVar(&analyzeBase, "base", "", "Diff base (default: origin/<rig default branch>)")
+	analyzeCmd.Flags().BoolVar(&analyzeJSON, "json", false, "Output the report as JSON")
+	rootCmd.AddCommand(analyzeCmd)
+}
+
+// loadStaticAnalysisConfig returns the rig's static_analysis settings, or
+// nil if none are configured.
+func loadStaticAnalysisConfig(rigPath string) (*analyze.Config, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return settings.StaticAnalysis, nil
+}
+
+// rigOriginBase returns origin/<default branch> for the rig, the base that
+// branch-level checks diff against.
+func rigOriginBase(rigPath string) string {
+	defaultBranch := "main"
+	if rigCfg, err := rig.LoadRigConfig(rigPath); err == nil && rigCfg.DefaultBranch != "" {
+		defaultBranch = rigCfg.DefaultBranch
+	}
+	return "origin/" + defaultBranch
+}
+
+// runStaticAnalysis runs the rig's analyzers on the diff between dir and
+// base. Returns a nil report when the rig has no analyzers configured.
+func runStaticAnalysis(rigPath, dir, base string) (*analyze.Config, *analyze.Report, error) {
+	cfg, err := loadStaticAnalysisConfig(rigPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !cfg.IsEnabled() {
+		return cfg, nil, nil
+	}
+	diff, err := analyze.LoadDiff(dir, base)
+	if err != nil {
+		return cfg, nil, err
+	}
+	return cfg, analyze.Run(context.Background(), cfg, dir, diff), nil
+}
+
+// checkStaticAnalysisForDone is the gt done gate. It prints the fix-it
+// prompt and returns an error when blocking violations remain. Problems
+// running the gate itself only warn: a broken diff must not strand work.
+func checkStaticAnalysisForDone(rigPath, dir, base string) error {
+	cfg, report, err := runStaticAnalysis(rigPath, dir, base)
+	if err != nil {
+		style.PrintWarning("static analysis skipped: %v", err)
+		return nil
+	}
+	if report == nil {
+		return nil
+	}
+	printAnalyzeSkips(report)
+	for _, v := range report.Warnings() {
+		style.PrintWarning("[%s] %s: %s", v.Analyzer, v.Location(), v.Message)
+	}
+	if report.Passed() {
+		fmt.Printf("%s Static analysis passed (%d analyzer(s))\n", style.Bold.Render("✓"), len(report.Results))
+		return nil
+	}
+	fmt.Println()
+	fmt.Print(report.Prompt(cfg.GetMaxViolations()))
+	fmt.Println()
+	return fmt.Errorf("cannot complete: static analysis found %d violation(s) in your changes", len(report.Blocking())+len(report.Failures()))
+}
+
+func printAnalyzeSkips(report *analyze.Report) {
+	for _, res := range report.Results {
+		if res.Skipped != "" && res.Skipped != "no matching changed files" {
+			style.PrintWarning("analyzer %s skipped: %s", res.Analyzer, res.Skipped)
+		}
+	}
+}
+
+func runAnalyze(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	var r *rig.Rig
+	if analyzeRig != "" {
+		_, r, err = getRig(analyzeRig)
+	} else {
+		_, r, err = findCurrentRig(townRoot)
+	}
+	if err != nil {
+		return err
+	}
+	repoRoot, err := detectCloneRoot()
+	if err != nil {
+		return err
+	}
+	base := analyzeBase
+	if base == "" {
+		base = rigOriginBase(r.Path)
+	}
+
+	cfg, report, err := runStaticAnalysis(r.Path, repoRoot, base)
+	if err != nil {
+		return err
+	}
+	if report == nil {
+		return fmt.Errorf("rig %s has no analyzers configured (set static_analysis in %s)", r.Name,
+			filepath.Join(r.Name, "settings", "config.json"))
+	}
+
+	if analyzeJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+		if !report.Passed() {
+			return NewSilentExit(1)
+		}
+		return nil
+	}
+
+	for _, res := range report.Results {
+		switch {
+		case res.Skipped != "":
+			fmt.Printf("  %s %-14s %s\n", style.Dim.Render("-"), res.Analyzer, style.Dim.Render("skipped: "+res.Skipped))
+		case res.Error != "":
+			fmt.Printf("  %s %-14s %s\n", style.Warning.Render("✗"), res.Analyzer, "failed: "+res.Error)
+		case len(res.Violations) > 0:
+			fmt.Printf("  %s %-14s %d violation(s)\n", style.Warning.Render("✗"), res.Analyzer, len(res.Violations))
+		default:
+			fmt.Printf("  %s %-14s clean\n", style.Success.Render("✓"), res.Analyzer)
+		}
+		if res.Ignored > 0 {
+			fmt.Printf("      %s\n", style.Dim.Render(fmt.Sprintf("%d finding(s) on unchanged lines ignored", res.Ignored)))
+		}
+	}
+	for _, v := range report.Warnings() {
+		fmt.Printf("  %s [%s] %s: %s\n", style.Dim.Render("warn"), v.Analyzer, v.Location(), v.Message)
+	}
+	if report.Passed() {
+		fmt.Printf("\n%s No blocking violations in your changes\n", style.Success.Render("✓"))
+		return nil
+	}
+	fmt.Println()
+	fmt.Print(report.Prompt(cfg.GetMaxViolations()))
+	return NewSilentExit(1)
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeStaticAnalysisSettings(t *testing.T, rigPath, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(rigPath, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"type": "rig-settings", "version": 1, "static_analysis": ` + body + `}`
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadStaticAnalysisConfig(t *testing.T) {
+	rigPath := t.TempDir()
+	if cfg, err := loadStaticAnalysisConfig(rigPath); err != nil || cfg != nil {
+		t.Fatalf("missing settings = %v, %v; want nil, nil", cfg, err)
+	}
+
+	writeStaticAnalysisSettings(t, rigPath, `{"analyzers": [{"name": "go-vet"}]}`)
+	cfg, err := loadStaticAnalysisConfig(rigPath)
+	if err != nil || !cfg.IsEnabled() || cfg.Analyzers[0].Name != "go-vet" {
+		t.Fatalf("loadStaticAnalysisConfig() = %+v, %v", cfg, err)
+	}
+
+	writeStaticAnalysisSettings(t, rigPath, `{"analyzers": [{"name": "no-such-preset"}]}`)
+	if _, err := loadStaticAnalysisConfig(rigPath); err == nil || !strings.Contains(err.Error(), "static_analysis") {
+		t.Errorf("expected static_analysis validation error, got %v", err)
+	}
+}
+
+func TestCheckStaticAnalysisForDone(t *testing.T) {
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", ".")
+	git("commit", "-q", "-m", "base")
+	git("checkout", "-q", "-b", "polecat/Toast")
+	if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main\n\nfunc bad() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("commit", "-q", "-am", "work")
+
+	rigPath := t.TempDir()
+
+	// No analyzers configured: the gate is a no-op.
+	if err := checkStaticAnalysisForDone(rigPath, repo, "main"); err != nil {
+		t.Fatalf("unconfigured gate error = %v", err)
+	}
+
+	writeStaticAnalysisSettings(t, rigPath, `{"analyzers": [{"name": "lint", "cmd": "echo 'main.go:3:6: func bad is unused'; exit 1", "files": ["*.go"]}]}`)
+	var gateErr error
+	out := captureStdout(t, func() {
+		gateErr = checkStaticAnalysisForDone(rigPath, repo, "main")
+	})
+	if gateErr == nil || !strings.Contains(gateErr.Error(), "1 violation") {
+		t.Errorf("gate error = %v, want violation count", gateErr)
+	}
+	if !strings.Contains(out, "[lint] main.go:3:6") || !strings.Contains(out, "func bad is unused") {
+		t.Errorf("fix-it prompt missing violation:\n%s", out)
+	}
+
+	writeStaticAnalysisSettings(t, rigPath, `{"analyzers": [{"name": "lint", "cmd": "echo 'main.go:3:6: func bad is unused'", "warn_only": true}]}`)
+	if err := checkStaticAnalysisForDone(rigPath, repo, "main"); err != nil {
+		t.Errorf("warn-only analyzer should not block: %v", err)
+	}
+}
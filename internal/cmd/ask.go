@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/knowledge"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// askSessionReuseWindow is how long a previous ask session is resumed for
+// follow-up questions before a fresh one is started.
+const askSessionReuseWindow = 2 * time.Hour
+
+var (
+	askFresh    bool
+	askAgent    string
+	askJSON     bool
+	askNoRecord bool
+	askTimeout  time.Duration
+)
+
+func init() {
+	askCmd.Flags().BoolVar(&askFresh, "fresh", false, "Start a new expert session instead of resuming the recent one")
+	askCmd.Flags().StringVar(&askAgent, "agent", "", "Override agent/runtime for this question (e.g., claude, gemini)")
+	askCmd.Flags().BoolVar(&askJSON, "json", false, "Output the recorded artifact as JSON")
+	askCmd.Flags().BoolVar(&askNoRecord, "no-record", false, "Do not record the answer in the rig knowledge base")
+	askCmd.Flags().DurationVar(&askTimeout, "timeout", 5*time.Minute, "Maximum time to wait for an answer")
+	askCmd.GroupID = GroupWork
+	rootCmd.AddCommand(askCmd)
+}
+
+var askCmd = &cobra.Command{
+	Use:   `ask <rig> "question"`,
+	Short: "Ask a rig's resident expert a one-shot question",
+	Long: `Ask a read-only expert agent a question about a rig and print the answer.
+
+No bead is created and no worktree is allocated. The agent runs in the rig's
+canonical clone (mayor/rig) with read-only permissions, answers, and exits.
+Runtimes other than Claude need a read_only_flag in their non_interactive
+preset (agents.json); gt ask refuses to run them without one.
+
+Follow-up questions within ` + askSessionReuseWindow.String() + ` resume the same expert session so
+earlier context is retained (Claude runtimes). Use --fresh to start over.
+
+Each answer is recorded as a knowledge artifact in <rig>/knowledge/ so
+later agents and operators can find it.
+
+Examples:
+  gt ask gastown "Where is the merge queue retry logic?"
+  gt ask gastown "Why do polecats use worktrees instead of clones?" --fresh
+  gt ask beads "What does bd ready consider blocked?" --json`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runAsk,
+}
+
+func runAsk(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	question := strings.TrimSpace(strings.Join(args[1:], " "))
+	if question == "" {
+		return fmt.Errorf("question cannot be empty")
+	}
+
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	rc, agentName, err := config.ResolveAgentConfigWithOverride(townRoot, r.Path, askAgent)
+	if err != nil {
+		return fmt.Errorf("resolving agent: %w", err)
+	}
+
+	resumeID := ""
+	if !askFresh {
+		resumeID = loadAskSession(r.Path, agentName)
+	}
+
+	prompt := buildAskPrompt(rigName, r.GitURL, question)
+	inv, err := buildAskInvocation(rc, agentName, prompt, resumeID)
+	if err != nil {
+		return err
+	}
+
+	if !askJSON {
+		fmt.Fprintf(os.Stderr, "%s Asking %s expert (%s)...\n", style.Bold.Render("❓"), rigName, agentName)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), askTimeout)
+	defer cancel()
+	c := exec.CommandContext(ctx, inv.Argv[0], inv.Argv[1:]...) //nolint:gosec // G204: argv from trusted agent config
+	c.Dir = askWorkDir(r.Path)
+	c.Env = clearClaudeCodeEnv(os.Environ())
+	var stdout bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("expert did not answer within %s", askTimeout)
+		}
+		return fmt.Errorf("running expert agent: %w", err)
+	}
+
+	answer, sessionID := strings.TrimSpace(stdout.String()), ""
+	if inv.Structured {
+		answer, sessionID, err = parseAskStructuredOutput(stdout.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	if sessionID != "" {
+		saveAskSession(r.Path, agentName, sessionID)
+	}
+
+	artifact := &knowledge.Artifact{
+		Kind:      knowledge.KindAsk,
+		Rig:       rigName,
+		Question:  question,
+		Answer:    answer,
+		Agent:     agentName,
+		SessionID: sessionID,
+		Author:    detectSender(),
+	}
+	if !askNoRecord {
+		if err := knowledge.Append(r.Path, artifact); err != nil {
+			style.PrintWarning("could not record answer: %v", err)
+		}
+	}
+
+	if askJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(artifact)
+	}
+
+	fmt.Println(answer)
+	if !askNoRecord && artifact.ID != "" {
+		fmt.Fprintf(os.Stderr, "\n%s\n", style.Dim.Render("Recorded as "+artifact.ID+" in "+rigName+"/knowledge"))
+	}
+	return nil
+}
+
+// askInvocation is the argv for a one-shot expert run.
+type askInvocation struct {
+	Argv []string
+	// Structured is true when stdout is JSON carrying the answer and session ID.
+	Structured bool
+}
+
+// buildAskInvocation builds a non-interactive, read-only command line for the
+// resolved runtime. Claude gets plan permission mode (no edits, no shell
+// writes) and JSON output so the session can be resumed. Other runtimes use
+// their preset non-interactive flags and must have a read-only flag: the
+// canonical clone is the rig's source of truth, and a prompt asking the
+// agent not to write is no guard.
+func buildAskInvocation(rc *config.RuntimeConfig, agentName, prompt, resumeID string) (*askInvocation, error) {
+	command := rc.Command
+	if command == "" {
+		command = agentName
+	}
+
+	if rc.Provider == "claude" || filepath.Base(command) == "claude" {
+		argv := []string{command, "-p", "--output-format", "json", "--permission-mode", "plan"}
+		if resumeID != "" {
+			argv = append(argv, "--resume", resumeID)
+		}
+		return &askInvocation{Argv: append(argv, prompt), Structured: true}, nil
+	}
+
+	preset := config.GetAgentPresetByName(agentName)
+	if preset == nil || preset.NonInteractive == nil {
+		return nil, fmt.Errorf("agent %q has no non-interactive mode configured (set non_interactive in agents.json)", agentName)
+	}
+	if preset.NonInteractive.ReadOnlyFlag == "" {
+		return nil, fmt.Errorf("agent %q has no read-only mode configured (set non_interactive.read_only_flag in agents.json)", agentName)
+	}
+	argv := []string{command}
+	if preset.NonInteractive.Subcommand != "" {
+		argv = append(argv, preset.NonInteractive.Subcommand)
+	}
+	argv = append(argv, strings.Fields(preset.NonInteractive.ReadOnlyFlag)...)
+	if preset.NonInteractive.PromptFlag != "" {
+		argv = append(argv, preset.NonInteractive.PromptFlag)
+	}
+	return &askInvocation{Argv: append(argv, prompt)}, nil
+}
+
+// parseAskStructuredOutput extracts the answer and session ID from
+// "claude -p --output-format json" output.
+func parseAskStructuredOutput(out []byte) (answer, sessionID string, err error) {
+	var res struct {
+		Result    string `json:"result"`
+		SessionID string `json:"session_id"`
+		IsError   bool   `json:"is_error"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &res); err != nil {
+		return "", "", fmt.Errorf("parsing expert output: %w", err)
+	}
+	if res.IsError {
+		return "", res.SessionID, fmt.Errorf("expert returned an error: %s", res.Result)
+	}
+	return strings.TrimSpace(res.Result), res.SessionID, nil
+}
+
+// buildAskPrompt frames the question for a read-only expert.
+func buildAskPrompt(rigName, gitURL, question string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are the resident expert for the %q rig in a Gas Town workspace.\n", rigName)
+	if gitURL != "" {
+		fmt.Fprintf(&b, "The repository in your working directory is %s.\n", gitURL)
+	}
+	b.WriteString("Answer the question below by reading the code and docs. This is read-only:\n")
+	b.WriteString("do not modify files, create beads, commit, or run gt/bd commands that write state.\n")
+	b.WriteString("Reply with a concise, direct answer and cite file paths where relevant.\n\n")
+	b.WriteString("Question: ")
+	b.WriteString(question)
+	return b.String()
+}
+
+// askWorkDir returns the rig's canonical clone, falling back to the rig root.
+func askWorkDir(rigPath string) string {
+	for _, dir := range []string{filepath.Join(rigPath, "mayor", "rig"), filepath.Join(rigPath, "refinery", "rig")} {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return rigPath
+}
+
+// askSessionState is persisted in <rig>/.runtime/ask-session.json.
+type askSessionState struct {
+	Agent     string    `json:"agent"`
+	SessionID string    `json:"session_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func askSessionPath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "ask-session.json")
+}
+
+// loadAskSession returns the reusable session ID for agentName, or "" if the
+// last session is missing, stale, or belongs to a different runtime.
+func loadAskSession(rigPath, agentName string) string {
+	data, err := os.ReadFile(askSessionPath(rigPath))
+	if err != nil {
+		return ""
+	}
+	var st askSessionState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return ""
+	}
+	if st.Agent != agentName || time.Since(st.UpdatedAt) > askSessionReuseWindow {
+		return ""
+	}
+	return st.SessionID
+}
+
+// saveAskSession records the session for follow-up reuse (best-effort).
+func saveAskSession(rigPath, agentName, sessionID string) {
+	path := askSessionPath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	data, err := json.Marshal(askSessionState{Agent: agentName, SessionID: sessionID, UpdatedAt: time.Now().UTC()})
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0644) //nolint:gosec // G306: runtime state, not secret
+}
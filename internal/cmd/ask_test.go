@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestBuildAskInvocation_Claude(t *testing.T) {
+	rc := &config.RuntimeConfig{Provider: "claude", Command: "/usr/local/bin/claude", Args: []string{"--dangerously-skip-permissions"}}
+
+	inv, err := buildAskInvocation(rc, "claude", "why?", "")
+	if err != nil {
+		t.Fatalf("buildAskInvocation() error = %v", err)
+	}
+	if !inv.Structured {
+		t.Error("claude invocation should be structured")
+	}
+	joined := strings.Join(inv.Argv, " ")
+	if strings.Contains(joined, "dangerously-skip-permissions") {
+		t.Errorf("ask must not inherit permission bypass: %s", joined)
+	}
+	if !strings.Contains(joined, "--permission-mode plan") {
+		t.Errorf("ask should run in plan mode: %s", joined)
+	}
+	if strings.Contains(joined, "--resume") {
+		t.Errorf("no resume expected without session: %s", joined)
+	}
+	if inv.Argv[len(inv.Argv)-1] != "why?" {
+		t.Errorf("prompt should be last arg, got %q", inv.Argv[len(inv.Argv)-1])
+	}
+
+	inv, err = buildAskInvocation(rc, "claude", "follow-up", "sess-123")
+	if err != nil {
+		t.Fatalf("buildAskInvocation() error = %v", err)
+	}
+	if !strings.Contains(strings.Join(inv.Argv, " "), "--resume sess-123") {
+		t.Errorf("expected resume of sess-123: %v", inv.Argv)
+	}
+}
+
+func TestBuildAskInvocation_NonInteractivePreset(t *testing.T) {
+	rc := &config.RuntimeConfig{Provider: "generic", Command: "codex"}
+	inv, err := buildAskInvocation(rc, "codex", "what?", "ignored")
+	if err != nil {
+		t.Fatalf("buildAskInvocation() error = %v", err)
+	}
+	if inv.Structured {
+		t.Error("codex invocation should be plain text")
+	}
+	want := []string{"codex", "exec", "--sandbox", "read-only", "what?"}
+	if strings.Join(inv.Argv, "|") != strings.Join(want, "|") {
+		t.Errorf("Argv = %v, want %v", inv.Argv, want)
+	}
+}
+
+func TestBuildAskInvocation_RefusesWritableAgent(t *testing.T) {
+	rc := &config.RuntimeConfig{Provider: "generic", Command: "gemini"}
+	_, err := buildAskInvocation(rc, "gemini", "what?", "")
+	if err == nil || !strings.Contains(err.Error(), "read_only_flag") {
+		t.Errorf("err = %v, want refusal of an agent without a read-only mode", err)
+	}
+}
+
+func TestBuildAskInvocation_UnknownAgent(t *testing.T) {
+	rc := &config.RuntimeConfig{Provider: "generic", Command: "mystery-agent"}
+	if _, err := buildAskInvocation(rc, "mystery-agent", "q", ""); err == nil {
+		t.Error("expected error for agent without non-interactive mode")
+	}
+}
+
+func TestParseAskStructuredOutput(t *testing.T) {
+	answer, sid, err := parseAskStructuredOutput([]byte(`{"type":"result","result":"  It lives in sling.go\n","session_id":"abc"}`))
+	if err != nil {
+		t.Fatalf("parse error = %v", err)
+	}
+	if answer != "It lives in sling.go" || sid != "abc" {
+		t.Errorf("got (%q, %q)", answer, sid)
+	}
+
+	if _, _, err := parseAskStructuredOutput([]byte(`{"result":"boom","is_error":true}`)); err == nil {
+		t.Error("expected error for is_error result")
+	}
+	if _, _, err := parseAskStructuredOutput([]byte("not json")); err == nil {
+		t.Error("expected error for non-JSON output")
+	}
+}
+
+func TestAskSessionReuse(t *testing.T) {
+	rigPath := t.TempDir()
+
+	if got := loadAskSession(rigPath, "claude"); got != "" {
+		t.Errorf("loadAskSession() on empty rig = %q", got)
+	}
+
+	saveAskSession(rigPath, "claude", "sess-1")
+	if got := loadAskSession(rigPath, "claude"); got != "sess-1" {
+		t.Errorf("loadAskSession() = %q, want sess-1", got)
+	}
+	if got := loadAskSession(rigPath, "gemini"); got != "" {
+		t.Errorf("session from another agent should not be reused, got %q", got)
+	}
+
+	stale, _ := json.Marshal(askSessionState{Agent: "claude", SessionID: "old", UpdatedAt: time.Now().Add(-askSessionReuseWindow - time.Minute)})
+	if err := os.WriteFile(askSessionPath(rigPath), stale, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := loadAskSession(rigPath, "claude"); got != "" {
+		t.Errorf("stale session should not be reused, got %q", got)
+	}
+}
+
+func TestAskWorkDir(t *testing.T) {
+	rigPath := t.TempDir()
+	if got := askWorkDir(rigPath); got != rigPath {
+		t.Errorf("askWorkDir() = %q, want rig root fallback", got)
+	}
+	clone := filepath.Join(rigPath, "mayor", "rig")
+	if err := os.MkdirAll(clone, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := askWorkDir(rigPath); got != clone {
+		t.Errorf("askWorkDir() = %q, want %q", got, clone)
+	}
+}
@@ -40,6 +40,10 @@ Shows a unified timeline of work performed by an actor including:
   - Town log events (spawn, done, handoff, etc.)
   - Activity feed events
 
+The append-only record of mutating operations (who ran what, with which
+arguments, and how it ended) is available via 'gt audit list' and
+'gt audit export'.
+
 Examples:
   gt audit --actor=greenplace/crew/joe       # Show all work by joe
   gt audit --actor=greenplace/polecats/toast # Show polecat toast's work
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/steveyegge/gastown/internal/auditlog"
+	"github.com/steveyegge/gastown/internal/rbac"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Audit log subcommand flags
+var (
+	auditLogSince   string
+	auditLogActor   string
+	auditLogCommand string
+	auditLogResult  string
+	auditLogLimit   int
+	auditLogJSON    bool
+	auditLogFormat  string
+	auditLogOutput  string
+)
+
+var auditListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded mutating operations",
+	Long: `List entries from the append-only audit log (logs/audit.jsonl).
+
+Every mutating gt command is recorded with who ran it (agent address and,
+for humans, the operator), the command and arguments, the working
+directory, the result, and the duration. Commands rejected by the access
+policy are recorded with result "denied". Read-only commands are not logged.
+
+Examples:
+  gt audit list                          # Last 50 operations
+  gt audit list --since=24h --actor=alice
+  gt audit list --command="rig remove"
+  gt audit list --result=denied --json`,
+	Args: cobra.NoArgs,
+	RunE: runAuditList,
+}
+
+var auditExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the audit log as JSONL or CSV",
+	Long: `Export audit log entries for external review or archival.
+
+Examples:
+  gt audit export > audit.jsonl
+  gt audit export --since=720h --format=csv -o audit-30d.csv`,
+	Args: cobra.NoArgs,
+	RunE: runAuditExport,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{auditListCmd, auditExportCmd} {
+		c.Flags().StringVar(&auditLogSince, "since", "", "Only entries since duration (e.g., 1h, 24h, 7d)")
+		c.Flags().StringVar(&auditLogActor, "actor", "", "Filter by actor or operator (substring)")
+		c.Flags().StringVar(&auditLogCommand, "command", "", "Filter by command path prefix (e.g., \"rig\")")
+		c.Flags().StringVar(&auditLogResult, "result", "", "Filter by result: ok, error, denied")
+	}
+	auditListCmd.Flags().IntVarP(&auditLogLimit, "limit", "n", 50, "Maximum number of entries to show (0 = all)")
+	auditListCmd.Flags().BoolVar(&auditLogJSON, "json", false, "Output as JSON")
+	auditExportCmd.Flags().StringVar(&auditLogFormat, "format", "jsonl", "Export format: jsonl or csv")
+	auditExportCmd.Flags().StringVarP(&auditLogOutput, "output", "o", "", "Write to file instead of stdout")
+
+	auditCmd.AddCommand(auditListCmd, auditExportCmd)
+}
+
+// pendingAudit is the entry for the command being run, filled in by
+// persistentPreRun and written by Execute once the command returns.
+var pendingAudit *auditlog.Entry
+var pendingAuditTown string
+var pendingAuditStart time.Time
+
+// beginAuditRecord prepares an audit entry if cmd is mutating. Flags the
+// caller set are recorded as --name=value ahead of the positional args;
+// auditlog redacts secret values.
+func beginAuditRecord(cmd *cobra.Command, args []string) {
+	pendingAudit = nil
+	path := accessCommandPath(cmd)
+	if path == "" || rbac.Classify(accessCommand(cmd)) < rbac.LevelNudge {
+		return
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+
+	var recorded []string
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		recorded = append(recorded, "--"+f.Name+"="+f.Value.String())
+	})
+	e := &auditlog.Entry{
+		Source:  auditlog.SourceGT,
+		Actor:   detectSender(),
+		Command: path,
+		Args:    append(recorded, args...),
+	}
+	if os.Getenv("GT_ROLE") == "" {
+		e.Operator = rbac.OperatorFromEnv()
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		e.Cwd = cwd
+	}
+	pendingAudit, pendingAuditTown, pendingAuditStart = e, townRoot, time.Now()
+}
+
+// finishAuditRecord writes the pending entry with the command's outcome.
+// Failures to write are reported but never change the command's exit code.
+func finishAuditRecord(runErr error) {
+	e := pendingAudit
+	if e == nil {
+		return
+	}
+	pendingAudit = nil
+
+	e.DurationMs = time.Since(pendingAuditStart).Milliseconds()
+	var denied *rbac.DeniedError
+	switch {
+	case runErr == nil:
+		e.Result = auditlog.ResultOK
+	case errors.As(runErr, &denied):
+		e.Result = auditlog.ResultDenied
+		e.Error = runErr.Error()
+	default:
+		if code, ok := IsSilentExit(runErr); ok {
+			e.Result = auditlog.ResultError
+			e.Error = "exit " + strconv.Itoa(code)
+		} else {
+			e.Result = auditlog.ResultError
+			e.Error = runErr.Error()
+		}
+	}
+	if err := auditlog.Record(pendingAuditTown, e); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: audit log: %v\n", err)
+	}
+}
+
+func auditLogFilter() (auditlog.Filter, error) {
+	f := auditlog.Filter{Actor: auditLogActor, Command: auditLogCommand, Result: auditLogResult}
+	if auditLogSince != "" {
+		d, err := parseDuration(auditLogSince)
+		if err != nil {
+			return f, fmt.Errorf("invalid --since duration: %w", err)
+		}
+		f.Since = time.Now().Add(-d)
+	}
+	return f, nil
+}
+
+func runAuditList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	filter, err := auditLogFilter()
+	if err != nil {
+		return err
+	}
+	entries, err := auditlog.Read(townRoot, filter)
+	if err != nil {
+		return err
+	}
+	if auditLogLimit > 0 && len(entries) > auditLogLimit {
+		entries = entries[len(entries)-auditLogLimit:]
+	}
+
+	if auditLogJSON {
+		if entries == nil {
+			entries = []auditlog.Entry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	if len(entries) == 0 {
+		fmt.Println("No audit entries found.")
+		return nil
+	}
+
+	for _, e := range entries {
+		who := e.Actor
+		if e.Operator != "" {
+			who += " (" + e.Operator + ")"
+		}
+		result := style.Success.Render(e.Result)
+		if e.Result != auditlog.ResultOK {
+			result = style.Warning.Render(e.Result)
+		}
+		fmt.Printf("%s %-8s %s %s %s\n",
+			style.Dim.Render(e.Timestamp.Local().Format("2006-01-02 15:04:05")),
+			result, who, style.Bold.Render(e.Command), strings.Join(e.Args, " "))
+		if e.Error != "" && e.Result != auditlog.ResultOK {
+			fmt.Printf("    %s\n", style.Dim.Render(e.Error))
+		}
+	}
+	return nil
+}
+
+func runAuditExport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	filter, err := auditLogFilter()
+	if err != nil {
+		return err
+	}
+	entries, err := auditlog.Read(townRoot, filter)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if auditLogOutput != "" {
+		f, err := os.Create(auditLogOutput)
+		if err != nil {
+			return fmt.Errorf("creating %s: %w", auditLogOutput, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := writeAuditExport(w, auditLogFormat, entries); err != nil {
+		return err
+	}
+	if auditLogOutput != "" {
+		fmt.Fprintf(os.Stderr, "%s Exported %d entries to %s\n", style.Success.Render("✓"), len(entries), auditLogOutput)
+	}
+	return nil
+}
+
+// writeAuditExport writes entries as JSONL or CSV.
+func writeAuditExport(w io.Writer, format string, entries []auditlog.Entry) error {
+	switch format {
+	case "jsonl", "":
+		enc := json.NewEncoder(w)
+		for _, e := range entries {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "csv":
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"ts", "source", "actor", "operator", "command", "args", "cwd", "result", "error", "duration_ms"})
+		for _, e := range entries {
+			_ = cw.Write([]string{
+				e.Timestamp.UTC().Format(time.RFC3339), e.Source, e.Actor, e.Operator, e.Command,
+				strings.Join(e.Args, " "), e.Cwd, e.Result, e.Error, strconv.FormatInt(e.DurationMs, 10),
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unknown format %q (use jsonl or csv)", format)
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/auditlog"
+	"github.com/steveyegge/gastown/internal/rbac"
+)
+
+func TestAuditRecordLifecycle(t *testing.T) {
+	townRoot := setupTownWithAccess(t, nil)
+	t.Setenv("GT_OPERATOR", "alice")
+
+	// Read-only commands are not recorded.
+	beginAuditRecord(accessTestCommand("convoy", "list"), nil)
+	finishAuditRecord(nil)
+
+	beginAuditRecord(accessTestCommand("rig", "park"), []string{"gastown"})
+	finishAuditRecord(nil)
+
+	beginAuditRecord(accessTestCommand("rig", "remove"), []string{"gastown"})
+	finishAuditRecord(&rbac.DeniedError{Principal: rbac.Principal{Name: "alice", Role: rbac.RoleViewer}, Command: "rig remove", Level: rbac.LevelAdmin})
+
+	beginAuditRecord(accessTestCommand("convoy", "add"), []string{"hq-cv-1"})
+	finishAuditRecord(fmt.Errorf("boom"))
+
+	entries, err := auditlog.Read(townRoot, auditlog.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3: %+v", len(entries), entries)
+	}
+	if e := entries[0]; e.Command != "rig park" || e.Result != auditlog.ResultOK || e.Operator != "alice" || e.Args[0] != "gastown" {
+		t.Errorf("park entry = %+v", e)
+	}
+	if entries[1].Result != auditlog.ResultDenied {
+		t.Errorf("remove entry result = %q, want denied", entries[1].Result)
+	}
+	if entries[2].Result != auditlog.ResultError || entries[2].Error != "boom" {
+		t.Errorf("add entry = %+v", entries[2])
+	}
+}
+
+func TestAuditRecordFlags(t *testing.T) {
+	townRoot := setupTownWithAccess(t, nil)
+
+	sling := &cobra.Command{Use: "sling"}
+	sling.Flags().Bool("force", false, "")
+	sling.Flags().String("token", "", "")
+	sling.Flags().StringP("message", "m", "", "")
+	(&cobra.Command{Use: "gt"}).AddCommand(sling)
+	if err := sling.ParseFlags([]string{"--force", "--token", "hunter2", "gt-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	beginAuditRecord(sling, sling.Flags().Args())
+	finishAuditRecord(nil)
+
+	entries, err := auditlog.Read(townRoot, auditlog.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	if got := strings.Join(entries[0].Args, " "); got != "--force=true --token=[redacted] gt-1" {
+		t.Errorf("args = %q, want the set flags, redacted, before the positionals", got)
+	}
+}
+
+func TestWriteAuditExport(t *testing.T) {
+	entries := []auditlog.Entry{{
+		Timestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Source:    auditlog.SourceGT, Actor: "overseer", Operator: "alice",
+		Command: "sling", Args: []string{"gt-1", "gastown"}, Result: auditlog.ResultOK, DurationMs: 42,
+	}}
+
+	var csvOut bytes.Buffer
+	if err := writeAuditExport(&csvOut, "csv", entries); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "ts,source,actor") || !strings.Contains(lines[1], "gt-1 gastown") {
+		t.Errorf("csv export = %q", csvOut.String())
+	}
+
+	var jsonl bytes.Buffer
+	if err := writeAuditExport(&jsonl, "jsonl", entries); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(jsonl.String(), `"operator":"alice"`) {
+		t.Errorf("jsonl export = %q", jsonl.String())
+	}
+
+	if err := writeAuditExport(&jsonl, "xml", entries); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
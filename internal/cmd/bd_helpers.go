@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"bytes"
+	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 )
@@ -130,6 +133,8 @@ func (b *bdCmd) buildEnv() []string {
 
 // Build returns the configured exec.Cmd.
 // This allows callers to further customize the command before execution.
+// Run the result with beads.RunCmd (or CmdOutput) so it still goes through
+// beads.Invoke.
 func (b *bdCmd) Build() *exec.Cmd {
 	args := b.resolvedArgs()
 	cmd := exec.Command("bd", args...)
@@ -154,26 +159,68 @@ func (b *bdCmd) resolvedArgs() []string {
 }
 
 // Run builds and runs the command, returning any error.
-// This is a convenience method equivalent to Build().Run().
+// Like Output and CombinedOutput it goes through beads.Invoke, so writes to
+// the same database are serialized and lock contention is retried.
 func (b *bdCmd) Run() error {
-	return b.Build().Run()
+	_, err := b.invoke(false)
+	return err
 }
 
 // Output builds and runs the command, returning stdout and any error.
-// This is a convenience method equivalent to Build().Output().
 // Note: Output() captures stdout but Stderr must still be configured
 // separately if you want to capture stderr instead of it going to os.Stderr.
 func (b *bdCmd) Output() ([]byte, error) {
-	return b.Build().Output()
+	return b.invoke(false)
 }
 
 // CombinedOutput builds and runs the command, returning combined stdout+stderr.
 // This overrides the configured Stderr writer to capture both streams.
 // Useful for including command output in error messages.
 func (b *bdCmd) CombinedOutput() ([]byte, error) {
+	return b.invoke(true)
+}
+
+// invoke runs the command through beads.Invoke. Stderr is captured so
+// contention can be detected, then written to the configured writer once
+// the last attempt finishes (or appended to the output when combined).
+func (b *bdCmd) invoke(combined bool) ([]byte, error) {
 	args := b.resolvedArgs()
-	cmd := exec.Command("bd", args...)
-	cmd.Dir = b.dir
-	cmd.Env = b.buildEnv()
-	return cmd.CombinedOutput()
+	env := b.buildEnv()
+	inv := beads.InvocationFor(args, b.dir, env)
+	stdout, stderr, err := beads.Invoke(inv, func() ([]byte, string, error) {
+		var out, errOut bytes.Buffer
+		cmd := exec.Command("bd", args...) //nolint:gosec // G204: bd is a trusted internal tool
+		cmd.Dir = b.dir
+		cmd.Env = env
+		cmd.Stdout = &out
+		cmd.Stderr = &errOut
+		if combined {
+			cmd.Stderr = &out
+		}
+		err := cmd.Run()
+		if combined {
+			// Contention detection needs the error text, which is in out.
+			return out.Bytes(), out.String(), err
+		}
+		return out.Bytes(), errOut.String(), err
+	})
+	if !combined && stderr != "" && b.stderr != nil {
+		_, _ = io.WriteString(b.stderr, stderr)
+	}
+	return stdout, err
+}
+
+// printBDTimings writes the bd call summary requested by GT_BD_TIMINGS=1.
+func printBDTimings(w io.Writer, stats []beads.CallStat) {
+	if len(stats) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "bd timings (%d commands):\n", len(stats))
+	_, _ = fmt.Fprintf(w, "  %-16s %6s %6s %7s %6s %10s %10s %10s\n",
+		"COMMAND", "CALLS", "SHARED", "RETRIES", "ERRORS", "TOTAL", "MAX", "LOCK WAIT")
+	for _, s := range stats {
+		_, _ = fmt.Fprintf(w, "  %-16s %6d %6d %7d %6d %10s %10s %10s\n",
+			s.Command, s.Calls, s.Shared, s.Retries, s.Errors,
+			s.Total.Round(time.Millisecond), s.Max.Round(time.Millisecond), s.LockWaits.Round(time.Millisecond))
+	}
 }
@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 func TestBdCmd_Build(t *testing.T) {
@@ -520,3 +524,59 @@ func filterEnv(env []string, key string) []string {
 	}
 	return out
 }
+
+func TestBdCmd_RetriesLockContention(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake bd script requires a POSIX shell")
+	}
+	binDir := t.TempDir()
+	counter := filepath.Join(binDir, "count")
+	// Fails the first two updates with lock contention. Other invocations
+	// (the --allow-stale probe) just exit.
+	script := "#!/bin/sh\n" +
+		"[ \"$1\" = update ] || exit 0\n" +
+		"n=$(cat " + counter + " 2>/dev/null || echo 0)\n" +
+		"n=$((n+1)); echo $n > " + counter + "\n" +
+		"if [ $n -le 2 ]; then echo 'Error: database is locked' >&2; exit 1; fi\n" +
+		"echo 'warning: slow' >&2\n" +
+		"echo updated\n"
+	if err := os.WriteFile(filepath.Join(binDir, "bd"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	var stderr bytes.Buffer
+	out, err := BdCmd("update", "gt-1").WithBeadsDir(t.TempDir()).Stderr(&stderr).Output()
+	if err != nil {
+		t.Fatalf("Output() error = %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "updated" {
+		t.Errorf("Output() = %q, want updated", out)
+	}
+	if n, _ := os.ReadFile(counter); strings.TrimSpace(string(n)) != "3" {
+		t.Errorf("bd update ran %s times, want 3", strings.TrimSpace(string(n)))
+	}
+	// Only the final attempt's stderr reaches the caller.
+	if got := stderr.String(); got != "warning: slow\n" {
+		t.Errorf("stderr = %q, want the last attempt's warning", got)
+	}
+}
+
+func TestPrintBDTimings(t *testing.T) {
+	var buf bytes.Buffer
+	printBDTimings(&buf, nil)
+	if buf.Len() != 0 {
+		t.Errorf("no stats should print nothing, got %q", buf.String())
+	}
+
+	printBDTimings(&buf, []beads.CallStat{
+		{Command: "show", Calls: 3, Shared: 2, Total: 1500 * time.Millisecond, Max: 900 * time.Millisecond},
+		{Command: "update", Calls: 1, Retries: 2, LockWaits: 40 * time.Millisecond},
+	})
+	got := buf.String()
+	for _, want := range []string{"bd timings (2 commands)", "show", "1.5s", "900ms", "update", "40ms"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -26,7 +26,14 @@ prefix-based routing.
 Subcommands:
   move    Move a bead from one repository to another
   show    Show details of a bead (routes by prefix)
-  read    Alias for show`,
+  read    Alias for show
+  search  Search beads in every rig at once
+  link    Record how two beads relate (duplicates, caused-by, ...)
+  links   Show a bead's links
+  unlink  Remove a link between two beads
+  rules   Label automation rules
+  stash   Defer a bead until a date or another bead closes
+  unstash Bring a stashed bead back now`,
 }
 
 var beadMoveCmd = &cobra.Command{
@@ -182,7 +189,7 @@ func runBeadMove(cmd *cobra.Command, args []string) error {
 	// Create the new bead
 	createCmd := exec.Command("bd", createArgs...)
 	createCmd.Stderr = os.Stderr
-	newIDBytes, err := createCmd.Output()
+	newIDBytes, err := beads.CmdOutput(createCmd)
 	if err != nil {
 		return fmt.Errorf("creating new bead: %w", err)
 	}
@@ -194,11 +201,11 @@ func runBeadMove(cmd *cobra.Command, args []string) error {
 	closeReason := fmt.Sprintf("Moved to %s", newID)
 	closeCmd := exec.Command("bd", "close", sourceID, "--reason", closeReason)
 	closeCmd.Stderr = os.Stderr
-	if err := closeCmd.Run(); err != nil {
+	if err := beads.RunCmd(closeCmd); err != nil {
 		// Clean up the new bead since we couldn't close the source
 		fmt.Fprintf(os.Stderr, "Warning: failed to close source bead: %v\n", err)
 		cleanupCmd := exec.Command("bd", "close", newID, "--reason", "Cleanup: source bead close failed during move")
-		if cleanupErr := cleanupCmd.Run(); cleanupErr != nil {
+		if cleanupErr := beads.RunCmd(cleanupCmd); cleanupErr != nil {
 			fmt.Fprintf(os.Stderr, "Warning: also failed to clean up new bead %s: %v\n", newID, cleanupErr)
 			fmt.Fprintf(os.Stderr, "Both %s and %s remain open - manual cleanup needed\n", sourceID, newID)
 		} else {
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beadlink"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var beadLinksJSON bool
+
+var beadLinkCmd = &cobra.Command{
+	Use:   "link <bead-id> <type> <other-bead-id>",
+	Short: "Record how two beads relate",
+	Long: `Record a typed link between two beads. Links say how beads relate
+without blocking anything, which a plain dependency can't:
+
+  duplicates     the bead restates the other (duplicated-by is the inverse)
+  relates-to     the beads touch the same thing
+  caused-by      the bead is a bug the other's work introduced (causes)
+  follow-up-of   the bead continues the other (followed-up-by)
+
+The link is stored on both beads as a label (link:<type>:<id>), the other
+bead getting the inverse type, so either side shows it. Duplicate
+detection reports linked duplicates and stops flagging beads already
+linked as related work; gt metrics scorecards counts caused-by links
+against the polecat whose bead caused them.
+
+Examples:
+  gt bead link gt-def duplicates gt-abc
+  gt bead link gt-bug caused-by gt-abc
+  gt bead link gt-next follow-up-of gt-abc
+  gt bead links gt-abc
+  gt bead unlink gt-def duplicates gt-abc`,
+	Args: cobra.ExactArgs(3),
+	RunE: runBeadLink,
+}
+
+var beadUnlinkCmd = &cobra.Command{
+	Use:   "unlink <bead-id> <type> <other-bead-id>",
+	Short: "Remove a link between two beads",
+	Args:  cobra.ExactArgs(3),
+	RunE:  runBeadUnlink,
+}
+
+var beadLinksCmd = &cobra.Command{
+	Use:   "links <bead-id>",
+	Short: "Show a bead's links",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runBeadLinks,
+}
+
+var (
+	// beadLinkInfoFn is a seam for tests. Production uses getBeadInfo.
+	beadLinkInfoFn = getBeadInfo
+
+	// beadLinkLabelsFn is a seam for tests. Production updates the labels with
+	// bd.
+	beadLinkLabelsFn = func(id string, add, remove []string) error {
+		return beads.New(resolveBeadDir(id)).Update(id, beads.UpdateOptions{AddLabels: add, RemoveLabels: remove})
+	}
+)
+
+func init() {
+	beadLinksCmd.Flags().BoolVar(&beadLinksJSON, "json", false, "Output as JSON")
+	beadCmd.AddCommand(beadLinkCmd)
+	beadCmd.AddCommand(beadUnlinkCmd)
+	beadCmd.AddCommand(beadLinksCmd)
+}
+
+func runBeadLink(cmd *cobra.Command, args []string) error {
+	from, link, err := parseBeadLinkArgs(args)
+	if err != nil {
+		return err
+	}
+	for _, id := range []string{from, link.Bead} {
+		if _, err := beadLinkInfoFn(id); err != nil {
+			return fmt.Errorf("bead '%s' not found", id)
+		}
+	}
+	if err := setBeadLink(from, link, true); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s %s %s\n", style.SuccessPrefix, from, beadlink.Phrase(link.Type), link.Bead)
+	return nil
+}
+
+func runBeadUnlink(cmd *cobra.Command, args []string) error {
+	from, link, err := parseBeadLinkArgs(args)
+	if err != nil {
+		return err
+	}
+	if err := setBeadLink(from, link, false); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s no longer %s %s\n", style.SuccessPrefix, from, beadlink.Phrase(link.Type), link.Bead)
+	return nil
+}
+
+func parseBeadLinkArgs(args []string) (string, beadlink.Link, error) {
+	from, typ, to := args[0], strings.ToLower(args[1]), args[2]
+	if err := beadlink.Validate(typ); err != nil {
+		return "", beadlink.Link{}, err
+	}
+	if from == to {
+		return "", beadlink.Link{}, fmt.Errorf("a bead can't be linked to itself")
+	}
+	return from, beadlink.Link{Type: typ, Bead: to}, nil
+}
+
+// setBeadLink adds (or removes) the link on from and its inverse on the
+// other bead.
+func setBeadLink(from string, link beadlink.Link, add bool) error {
+	rev := link.Reverse(from)
+	for _, side := range []struct {
+		id    string
+		label string
+	}{{from, link.Label()}, {link.Bead, rev.Label()}} {
+		var err error
+		if add {
+			err = beadLinkLabelsFn(side.id, []string{side.label}, nil)
+		} else {
+			err = beadLinkLabelsFn(side.id, nil, []string{side.label})
+		}
+		if err != nil {
+			return fmt.Errorf("updating %s: %w", side.id, err)
+		}
+	}
+	_ = events.LogFeed(events.TypeBeadLink, detectSender(), map[string]interface{}{
+		"bead":   from,
+		"type":   link.Type,
+		"target": link.Bead,
+		"linked": add,
+	})
+	return nil
+}
+
+// beadLinkRow is one line of gt bead links.
+type beadLinkRow struct {
+	beadlink.Link
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+func runBeadLinks(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	info, err := beadLinkInfoFn(id)
+	if err != nil {
+		return fmt.Errorf("bead '%s' not found", id)
+	}
+	links := beadlink.FromLabels(info.Labels)
+	rows := make([]beadLinkRow, 0, len(links))
+	for _, l := range links {
+		row := beadLinkRow{Link: l}
+		if other, err := beadLinkInfoFn(l.Bead); err == nil {
+			row.Title, row.Status = other.Title, other.Status
+		}
+		rows = append(rows, row)
+	}
+
+	if beadLinksJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+	if len(rows) == 0 {
+		fmt.Printf("%s has no links.\n", id)
+		return nil
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render(id), info.Title)
+	for _, r := range rows {
+		status := ""
+		if r.Status != "" {
+			status = style.Dim.Render("[" + r.Status + "] ")
+		}
+		fmt.Printf("  %-15s %s %s%s\n", beadlink.Phrase(r.Type), r.Bead, status, r.Title)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beadlink"
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestBeadLink(t *testing.T) {
+	origInfo, origLabels := beadLinkInfoFn, beadLinkLabelsFn
+	t.Cleanup(func() { beadLinkInfoFn, beadLinkLabelsFn = origInfo, origLabels })
+	t.Chdir(t.TempDir()) // Keep the feed event out of the tree
+
+	labels := map[string][]string{"gt-abc": nil, "gt-bug": nil}
+	beadLinkInfoFn = func(id string) (*beadInfo, error) {
+		l, ok := labels[id]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return &beadInfo{Title: "bead " + id, Status: "open", Labels: l}, nil
+	}
+	beadLinkLabelsFn = func(id string, add, remove []string) error {
+		kept := labels[id][:0]
+		for _, l := range labels[id] {
+			if !strings.Contains(strings.Join(remove, " "), l) {
+				kept = append(kept, l)
+			}
+		}
+		labels[id] = append(kept, add...)
+		return nil
+	}
+
+	if err := runBeadLink(nil, []string{"gt-bug", "blocks", "gt-abc"}); err == nil {
+		t.Error("blocks is not a link type")
+	}
+	if err := runBeadLink(nil, []string{"gt-bug", "relates-to", "gt-bug"}); err == nil {
+		t.Error("a self-link should be refused")
+	}
+	if err := runBeadLink(nil, []string{"gt-bug", "caused-by", "gt-404"}); err == nil {
+		t.Error("a missing bead should be refused")
+	}
+
+	if err := runBeadLink(nil, []string{"gt-bug", "Caused-By", "gt-abc"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := beadlink.FromLabels(labels["gt-bug"]); len(got) != 1 || got[0] != (beadlink.Link{Type: beadlink.CausedBy, Bead: "gt-abc"}) {
+		t.Errorf("gt-bug links = %+v", got)
+	}
+	if got := beadlink.FromLabels(labels["gt-abc"]); len(got) != 1 || got[0] != (beadlink.Link{Type: beadlink.Causes, Bead: "gt-bug"}) {
+		t.Errorf("gt-abc links = %+v, want the inverse", got)
+	}
+
+	if err := runBeadUnlink(nil, []string{"gt-abc", "causes", "gt-bug"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(labels["gt-bug"]) != 0 || len(labels["gt-abc"]) != 0 {
+		t.Errorf("labels after unlink = %v", labels)
+	}
+}
+
+func TestFindLikelyDuplicates_Links(t *testing.T) {
+	orig := listDedupCandidatesFn
+	t.Cleanup(func() { listDedupCandidatesFn = orig })
+	listDedupCandidatesFn = func(string, int) ([]*beads.Issue, error) {
+		return []*beads.Issue{
+			{ID: "gt-same", Title: "Add retry to webhook delivery", Status: "in_progress"},
+			{ID: "gt-known", Title: "Add retry to webhook delivery", Status: "open"},
+			{ID: "gt-dup", Title: "Webhooks should retry", Status: "open"},
+		}, nil
+	}
+
+	info := &beadInfo{
+		Title:  "Add retry to webhook delivery",
+		Status: "open",
+		Labels: []string{"link:relates-to:gt-known", "link:duplicated-by:gt-dup"},
+	}
+	matches := findLikelyDuplicates(context.Background(), nil, "gt-self", info, time.Now())
+	var ids []string
+	for _, m := range matches {
+		ids = append(ids, m.ID)
+	}
+	// The linked duplicate comes first whatever its similarity; the bead
+	// linked as related work is known to be distinct.
+	if strings.Join(ids, ",") != "gt-dup,gt-same" {
+		t.Errorf("matches = %v, want gt-dup,gt-same", ids)
+	}
+}
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/labelrules"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var beadRulesDryRun bool
+
+var beadRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Show label automation rules",
+	Long: `Show the rules that act on beads when they gain a label.
+
+Rules live in town settings/config.json under "label_rules". A rule fires
+once when a bead in any rig gains its label, and can raise the bead's
+priority, nudge its assignee, mail addresses and sling it:
+
+  "label_rules": [
+    {"name": "urgent", "label": "urgent", "priority": 0, "nudge": true},
+    {"name": "blocked", "label": "blocked", "notify": ["mayor/"]},
+    {"name": "design", "label": "needs-design", "sling": "greenplace/crew/architect"}
+  ]
+
+Priority only ever raises a bead (0 is most urgent). Removing the label
+and adding it again fires the rule again. The daemon's label_rules patrol
+applies the rules with 'gt bead rules run'.
+
+Examples:
+  gt bead rules                  # List rules
+  gt bead rules run --dry-run    # Show what would fire now`,
+	Args: cobra.NoArgs,
+	RunE: runBeadRules,
+}
+
+var beadRulesRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Apply label rules to newly labeled beads",
+	Long: `Apply label rules to beads that gained a rule's label since the last run.
+
+Fired rules are recorded in .runtime/label-rules.json. The first run after
+a rule is added acts on every bead that already carries its label.
+
+Examples:
+  gt bead rules run
+  gt bead rules run --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runBeadRulesRun,
+}
+
+var (
+	// labelRuleSourceFn is a seam for tests. Production searches the database by
+	// label.
+	labelRuleSourceFn = func(beadsDir, label string) ([]*beads.Issue, error) {
+		return beadSearchSourceFn(beadsDir, beads.SearchOptions{Label: label})
+	}
+
+	// labelRuleSetPriorityFn is a seam for tests. Production updates the bead
+	// with bd.
+	labelRuleSetPriorityFn = func(beadsDir, id string, priority int) error {
+		return beads.New(beadsDir).Update(id, beads.UpdateOptions{Priority: &priority})
+	}
+
+	// labelRuleNudgeFn is a seam for tests. Production runs gt nudge in the
+	// town.
+	labelRuleNudgeFn = func(townRoot, agent, message string) error {
+		cmd := exec.Command("gt", "nudge", agent, "-m", message)
+		cmd.Dir = townRoot
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%v (%s)", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	// labelRuleNotifyFn is a seam for tests. Production sends mail through the
+	// town router.
+	labelRuleNotifyFn = func(townRoot, to, subject, body string) error {
+		router := mail.NewRouter(townRoot)
+		defer router.WaitPendingNotifications()
+		return router.Send(mail.NewMessage(detectSender(), to, subject, body))
+	}
+
+	// labelRuleSlingFn is a seam for tests. Production runs gt sling in the
+	// town.
+	labelRuleSlingFn = func(townRoot, id, target string) error {
+		cmd := exec.Command("gt", "sling", id, target)
+		cmd.Dir = townRoot
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%v (%s)", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+)
+
+func init() {
+	beadRulesRunCmd.Flags().BoolVarP(&beadRulesDryRun, "dry-run", "n", false, "Show what would be done")
+
+	beadRulesCmd.AddCommand(beadRulesRunCmd)
+	beadCmd.AddCommand(beadRulesCmd)
+}
+
+// loadLabelRules returns the town's label rules.
+func loadLabelRules(townRoot string) ([]config.LabelRule, error) {
+	ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if err := labelrules.Validate(ts.LabelRules); err != nil {
+		return nil, err
+	}
+	return ts.LabelRules, nil
+}
+
+func runBeadRules(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rules, err := loadLabelRules(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		fmt.Println("No label rules configured (settings/config.json \"label_rules\")")
+		return nil
+	}
+	for i, rule := range rules {
+		fmt.Printf("%d. %s  label=%s → %s\n", i+1, style.Bold.Render(rule.Name), rule.Label, describeLabelRuleActions(rule))
+	}
+	return nil
+}
+
+func describeLabelRuleActions(rule config.LabelRule) string {
+	var parts []string
+	if rule.Priority != nil {
+		parts = append(parts, fmt.Sprintf("priority P%d", *rule.Priority))
+	}
+	if rule.Nudge {
+		parts = append(parts, "nudge assignee")
+	}
+	if len(rule.Notify) > 0 {
+		parts = append(parts, "notify "+strings.Join(rule.Notify, ", "))
+	}
+	if rule.Sling != "" {
+		parts = append(parts, "sling "+rule.Sling)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func runBeadRulesRun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rules, err := loadLabelRules(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	dbs, err := beadSearchDBs(townRoot)
+	if err != nil {
+		return err
+	}
+	state, err := labelrules.LoadState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading label rules state: %w", err)
+	}
+
+	applyLabelRules(townRoot, rules, dbs, state, beadRulesDryRun, time.Now())
+
+	if beadRulesDryRun {
+		return nil
+	}
+	return state.Save(townRoot)
+}
+
+// applyLabelRules fires each rule for the beads that gained its label since
+// the state was last updated. A rule whose search failed in some database
+// keeps its state, so beads there aren't forgotten and fired twice.
+func applyLabelRules(townRoot string, rules []config.LabelRule, dbs []beadSearchDB, state *labelrules.State, dryRun bool, now time.Time) {
+	dirs := make(map[string]string, len(dbs))
+	for _, db := range dbs {
+		dirs[db.Rig] = db.Dir
+	}
+	state.Prune(rules)
+
+	for _, rule := range rules {
+		result := searchAllBeads(dbs, func(dir string) ([]*beads.Issue, error) {
+			return labelRuleSourceFn(dir, rule.Label)
+		})
+		for rigName, msg := range result.Errors {
+			style.PrintWarning("label rule %s: %s: %s", rule.Name, rigName, msg)
+		}
+
+		labeled := make([]string, 0, len(result.Results))
+		for _, hit := range result.Results {
+			labeled = append(labeled, hit.ID)
+			if state.HasFired(rule.Name, hit.ID) {
+				continue
+			}
+			if dryRun {
+				fmt.Printf("%s %s: %s → %s\n", style.Dim.Render("[dry-run]"), rule.Name, hit.ID, describeLabelRuleActions(rule))
+				continue
+			}
+			done := fireLabelRule(townRoot, rule, hit, dirs[hit.Rig])
+			if len(done) > 0 {
+				fmt.Printf("%s %s: %s → %s\n", style.Success.Render("✓"), rule.Name, hit.ID, strings.Join(done, ", "))
+			}
+			state.MarkFired(rule.Name, hit.ID, now)
+		}
+		if len(result.Errors) == 0 && !dryRun {
+			state.Forget(rule.Name, labeled)
+		}
+	}
+}
+
+// fireLabelRule runs a rule's actions on one bead and returns what it did.
+// Failed actions are warned about and skipped; the rest still run.
+func fireLabelRule(townRoot string, rule config.LabelRule, hit beadSearchHit, beadsDir string) []string {
+	var done []string
+	fail := func(action string, err error) {
+		style.PrintWarning("label rule %s: %s on %s failed: %v", rule.Name, action, hit.ID, err)
+	}
+	what := fmt.Sprintf("%s labeled %s: %s", hit.ID, rule.Label, hit.Title)
+
+	if labelrules.RaisesPriority(rule, hit.Priority) {
+		if err := labelRuleSetPriorityFn(beadsDir, hit.ID, *rule.Priority); err != nil {
+			fail("priority", err)
+		} else {
+			done = append(done, fmt.Sprintf("P%d→P%d", hit.Priority, *rule.Priority))
+		}
+	}
+	if rule.Nudge && hit.Assignee != "" {
+		if err := labelRuleNudgeFn(townRoot, hit.Assignee, what); err != nil {
+			fail("nudge", err)
+		} else {
+			done = append(done, "nudged "+hit.Assignee)
+		}
+	}
+	for _, to := range rule.Notify {
+		body := fmt.Sprintf("Bead: %s (%s)\nTitle: %s\nStatus: %s\nAssignee: %s\n\nLabel rule %q fired.\n",
+			hit.ID, hit.Rig, hit.Title, hit.Status, hit.Assignee, rule.Name)
+		if err := labelRuleNotifyFn(townRoot, to, what, body); err != nil {
+			fail("notify "+to, err)
+		} else {
+			done = append(done, "mailed "+to)
+		}
+	}
+	if rule.Sling != "" {
+		if err := labelRuleSlingFn(townRoot, hit.ID, rule.Sling); err != nil {
+			fail("sling", err)
+		} else {
+			done = append(done, "slung to "+rule.Sling)
+		}
+	}
+	return done
+}
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/labelrules"
+)
+
+func TestApplyLabelRules(t *testing.T) {
+	origSource, origPriority, origNudge, origNotify, origSling := labelRuleSourceFn, labelRuleSetPriorityFn, labelRuleNudgeFn, labelRuleNotifyFn, labelRuleSlingFn
+	t.Cleanup(func() {
+		labelRuleSourceFn, labelRuleSetPriorityFn, labelRuleNudgeFn, labelRuleNotifyFn, labelRuleSlingFn = origSource, origPriority, origNudge, origNotify, origSling
+	})
+
+	labeled := map[string][]*beads.Issue{
+		"urgent": {
+			{ID: "gp-1", Title: "login broken", Priority: 2, Assignee: "greenplace/polecats/toast"},
+			{ID: "gp-2", Title: "already hot", Priority: 0},
+		},
+		"needs-design": {{ID: "gp-3", Title: "new settings page", Priority: 2}},
+	}
+	labelRuleSourceFn = func(dir, label string) ([]*beads.Issue, error) {
+		return labeled[label], nil
+	}
+	var actions []string
+	labelRuleSetPriorityFn = func(dir, id string, priority int) error {
+		actions = append(actions, "priority "+id+" "+dir)
+		return nil
+	}
+	labelRuleNudgeFn = func(townRoot, agent, message string) error {
+		actions = append(actions, "nudge "+agent)
+		return nil
+	}
+	labelRuleNotifyFn = func(townRoot, to, subject, body string) error {
+		actions = append(actions, "mail "+to)
+		return nil
+	}
+	labelRuleSlingFn = func(townRoot, id, target string) error {
+		actions = append(actions, "sling "+id+" "+target)
+		return nil
+	}
+
+	zero := 0
+	rules := []config.LabelRule{
+		{Name: "urgent", Label: "urgent", Priority: &zero, Nudge: true},
+		{Name: "design", Label: "needs-design", Sling: "greenplace/crew/architect", Notify: []string{"mayor/"}},
+	}
+	dbs := []beadSearchDB{{Rig: "greenplace", Dir: "/town/greenplace/mayor/rig"}}
+	state := &labelrules.State{Fired: make(map[string]map[string]time.Time)}
+	now := time.Now()
+
+	applyLabelRules("/town", rules, dbs, state, false, now)
+	want := []string{
+		"priority gp-1 /town/greenplace/mayor/rig",
+		"nudge greenplace/polecats/toast",
+		"mail mayor/",
+		"sling gp-3 greenplace/crew/architect",
+	}
+	if len(actions) != len(want) {
+		t.Fatalf("actions = %v, want %v", actions, want)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Errorf("action %d = %q, want %q", i, actions[i], want[i])
+		}
+	}
+
+	// Second run: nothing new.
+	actions = nil
+	applyLabelRules("/town", rules, dbs, state, false, now)
+	if len(actions) != 0 {
+		t.Errorf("rules fired twice: %v", actions)
+	}
+
+	// gp-3 loses needs-design, then gets it back: the rule fires again.
+	labeled["needs-design"] = nil
+	applyLabelRules("/town", rules, dbs, state, false, now)
+	labeled["needs-design"] = []*beads.Issue{{ID: "gp-3", Title: "new settings page", Priority: 2}}
+	applyLabelRules("/town", rules, dbs, state, false, now)
+	if len(actions) != 2 || actions[1] != "sling gp-3 greenplace/crew/architect" {
+		t.Errorf("relabeled bead actions = %v", actions)
+	}
+}
+
+func TestApplyLabelRules_DryRun(t *testing.T) {
+	origSource, origSling := labelRuleSourceFn, labelRuleSlingFn
+	t.Cleanup(func() { labelRuleSourceFn, labelRuleSlingFn = origSource, origSling })
+
+	labelRuleSourceFn = func(dir, label string) ([]*beads.Issue, error) {
+		return []*beads.Issue{{ID: "gp-3"}}, nil
+	}
+	labelRuleSlingFn = func(townRoot, id, target string) error {
+		t.Errorf("dry run slung %s", id)
+		return nil
+	}
+
+	rules := []config.LabelRule{{Name: "design", Label: "needs-design", Sling: "greenplace/crew/architect"}}
+	state := &labelrules.State{Fired: make(map[string]map[string]time.Time)}
+	applyLabelRules("/town", rules, []beadSearchDB{{Rig: "greenplace", Dir: "/x"}}, state, true, time.Now())
+	if state.HasFired("design", "gp-3") {
+		t.Error("dry run should not record fired rules")
+	}
+}
@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadSearchLabel    string
+	beadSearchStatus   string
+	beadSearchAssignee string
+	beadSearchRig      string
+	beadSearchLimit    int
+	beadSearchJSON     bool
+)
+
+var beadSearchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search beads in every rig at once",
+	Long: `Search every routed beads database (town and each rig, per
+.beads/routes.jsonl) in parallel and list the matches prefixed by rig.
+
+The query matches bead titles, descriptions and IDs. Filters apply in
+every database; with filters alone, every bead matching them is listed.
+
+Examples:
+  gt bead search retries
+  gt bead search "flaky test" --status open
+  gt bead search --label gt:bug --assignee greenplace/polecats/toast
+  gt bead search retries --rig greenplace --limit 5
+  gt bead search retries --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBeadSearch,
+}
+
+// beadSearchSourceFn is a seam for tests. Production lists or searches the
+// database with bd.
+var beadSearchSourceFn = func(beadsDir string, opts beads.SearchOptions) ([]*beads.Issue, error) {
+	b := beads.New(beadsDir)
+	if opts.Query == "" {
+		return b.List(beads.ListOptions{
+			Status:   opts.Status,
+			Label:    opts.Label,
+			Assignee: opts.Assignee,
+			Priority: -1,
+			Limit:    opts.Limit,
+		})
+	}
+	return b.Search(opts)
+}
+
+func init() {
+	beadSearchCmd.Flags().StringVarP(&beadSearchLabel, "label", "l", "", "Only beads with this label")
+	beadSearchCmd.Flags().StringVarP(&beadSearchStatus, "status", "s", "", "Only beads with this status (open, in_progress, closed, all)")
+	beadSearchCmd.Flags().StringVarP(&beadSearchAssignee, "assignee", "a", "", "Only beads assigned to this agent")
+	beadSearchCmd.Flags().StringVar(&beadSearchRig, "rig", "", "Only search this rig (or \"town\")")
+	beadSearchCmd.Flags().IntVarP(&beadSearchLimit, "limit", "n", 0, "Max results per database (0 = bd default)")
+	beadSearchCmd.Flags().BoolVar(&beadSearchJSON, "json", false, "Output as JSON")
+	beadCmd.AddCommand(beadSearchCmd)
+}
+
+// beadSearchDB is one routed beads database.
+type beadSearchDB struct {
+	Rig string // Rig name, or "town" for town-level beads
+	Dir string
+}
+
+// beadSearchHit is one matching bead, tagged with the rig it lives in.
+type beadSearchHit struct {
+	Rig string `json:"rig"`
+	*beads.Issue
+}
+
+// beadSearchResult is the output of gt bead search.
+type beadSearchResult struct {
+	Query   string            `json:"query,omitempty"`
+	Results []beadSearchHit   `json:"results"`
+	Errors  map[string]string `json:"errors,omitempty"` // Rig -> error
+}
+
+// beadSearchDBs returns the distinct databases in the town's routes, town
+// first then rigs alphabetically. Several prefixes may route to one
+// database; it is searched once.
+func beadSearchDBs(townRoot string) ([]beadSearchDB, error) {
+	routes, err := beads.LoadRoutes(beads.GetTownBeadsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading routes: %w", err)
+	}
+	seen := make(map[string]bool)
+	dbs := []beadSearchDB{{Rig: "town", Dir: beads.GetTownBeadsPath(townRoot)}}
+	seen[townRoot] = true
+	for _, r := range routes {
+		dir := r.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(townRoot, dir)
+		}
+		dir = filepath.Clean(dir)
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+		rigName := strings.SplitN(filepath.ToSlash(r.Path), "/", 2)[0]
+		if filepath.IsAbs(r.Path) {
+			if rel, err := filepath.Rel(townRoot, dir); err == nil && !strings.HasPrefix(rel, "..") {
+				rigName = strings.SplitN(filepath.ToSlash(rel), "/", 2)[0]
+			} else {
+				rigName = strings.TrimSuffix(r.Prefix, "-")
+			}
+		}
+		dbs = append(dbs, beadSearchDB{Rig: rigName, Dir: dir})
+	}
+	sort.SliceStable(dbs[1:], func(i, j int) bool { return dbs[1+i].Rig < dbs[1+j].Rig })
+	return dbs, nil
+}
+
+// searchAllBeads queries every database in parallel. Results keep each
+// database's own ranking and are grouped by rig in dbs order.
+func searchAllBeads(dbs []beadSearchDB, query func(dir string) ([]*beads.Issue, error)) beadSearchResult {
+	perDB := make([][]*beads.Issue, len(dbs))
+	errs := make([]error, len(dbs))
+	var wg sync.WaitGroup
+	for i, db := range dbs {
+		wg.Add(1)
+		go func(i int, db beadSearchDB) {
+			defer wg.Done()
+			perDB[i], errs[i] = query(db.Dir)
+		}(i, db)
+	}
+	wg.Wait()
+
+	result := beadSearchResult{Results: []beadSearchHit{}}
+	for i, db := range dbs {
+		if errs[i] != nil {
+			if result.Errors == nil {
+				result.Errors = make(map[string]string)
+			}
+			result.Errors[db.Rig] = errs[i].Error()
+			continue
+		}
+		for _, issue := range perDB[i] {
+			result.Results = append(result.Results, beadSearchHit{Rig: db.Rig, Issue: issue})
+		}
+	}
+	return result
+}
+
+func runBeadSearch(cmd *cobra.Command, args []string) error {
+	opts := beads.SearchOptions{
+		Status:   beadSearchStatus,
+		Label:    beadSearchLabel,
+		Assignee: beadSearchAssignee,
+		Limit:    beadSearchLimit,
+	}
+	if len(args) > 0 {
+		opts.Query = strings.TrimSpace(args[0])
+	}
+	if opts.Query == "" && opts.Status == "" && opts.Label == "" && opts.Assignee == "" {
+		return fmt.Errorf("give a query or at least one of --label, --status, --assignee")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	dbs, err := beadSearchDBs(townRoot)
+	if err != nil {
+		return err
+	}
+	if beadSearchRig != "" {
+		var filtered []beadSearchDB
+		for _, db := range dbs {
+			if db.Rig == beadSearchRig {
+				filtered = append(filtered, db)
+			}
+		}
+		if len(filtered) == 0 {
+			return fmt.Errorf("no beads database routed for rig %s", beadSearchRig)
+		}
+		dbs = filtered
+	}
+
+	result := searchAllBeads(dbs, func(dir string) ([]*beads.Issue, error) {
+		return beadSearchSourceFn(dir, opts)
+	})
+	result.Query = opts.Query
+
+	if beadSearchJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		printBeadSearch(result)
+	}
+
+	if len(result.Errors) > 0 {
+		failed := make([]string, 0, len(result.Errors))
+		for rigName := range result.Errors {
+			failed = append(failed, rigName)
+		}
+		sort.Strings(failed)
+		if len(failed) == len(dbs) {
+			return fmt.Errorf("search failed in every database: %s", strings.Join(failed, ", "))
+		}
+		if !beadSearchJSON {
+			style.PrintWarning("search failed in: %s (results may be incomplete)", strings.Join(failed, ", "))
+		}
+	}
+	return nil
+}
+
+func printBeadSearch(result beadSearchResult) {
+	if len(result.Results) == 0 {
+		fmt.Println("No matching beads.")
+		return
+	}
+	width := 0
+	for _, hit := range result.Results {
+		width = max(width, len(hit.Rig))
+	}
+	for _, hit := range result.Results {
+		title := hit.Title
+		if len(title) > 60 {
+			title = title[:57] + "..."
+		}
+		line := fmt.Sprintf("%-*s  %s  %s  %s  %s", width, hit.Rig,
+			style.Bold.Render(hit.ID), style.Dim.Render(fmt.Sprintf("P%d", hit.Priority)),
+			style.Dim.Render(hit.Status), title)
+		if hit.Assignee != "" {
+			line += style.Dim.Render("  @" + hit.Assignee)
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("\n%d bead(s)\n", len(result.Results))
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestBeadSearchDBs(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, dir := range []string{".beads", "greenplace/mayor/rig", "beads/mayor/rig"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	routes := `{"prefix": "hq-", "path": "."}
+{"prefix": "gp-", "path": "greenplace/mayor/rig"}
+{"prefix": "gpx-", "path": "greenplace/mayor/rig"}
+{"prefix": "bd-", "path": "beads/mayor/rig"}
+{"prefix": "gone-", "path": "gone/mayor/rig"}
+`
+	if err := os.WriteFile(filepath.Join(townRoot, ".beads", "routes.jsonl"), []byte(routes), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dbs, err := beadSearchDBs(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, db := range dbs {
+		got = append(got, db.Rig)
+	}
+	want := []string{"town", "beads", "greenplace"}
+	if len(got) != len(want) {
+		t.Fatalf("rigs = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("rigs = %v, want %v (town first, deduped, missing skipped)", got, want)
+		}
+	}
+}
+
+func TestSearchAllBeads(t *testing.T) {
+	orig := beadSearchSourceFn
+	t.Cleanup(func() { beadSearchSourceFn = orig })
+
+	beadSearchSourceFn = func(dir string, opts beads.SearchOptions) ([]*beads.Issue, error) {
+		switch dir {
+		case "/town/.beads":
+			return []*beads.Issue{{ID: "hq-1", Title: "retry policy"}}, nil
+		case "/town/greenplace/mayor/rig":
+			return []*beads.Issue{{ID: "gp-7", Title: "add retries"}, {ID: "gp-9", Title: "retry backoff"}}, nil
+		}
+		return nil, errors.New("database locked")
+	}
+
+	dbs := []beadSearchDB{
+		{Rig: "town", Dir: "/town/.beads"},
+		{Rig: "beads", Dir: "/town/beads/mayor/rig"},
+		{Rig: "greenplace", Dir: "/town/greenplace/mayor/rig"},
+	}
+	result := searchAllBeads(dbs, func(dir string) ([]*beads.Issue, error) {
+		return beadSearchSourceFn(dir, beads.SearchOptions{Query: "retr"})
+	})
+
+	if len(result.Results) != 3 {
+		t.Fatalf("results = %d, want 3", len(result.Results))
+	}
+	order := []string{"town/hq-1", "greenplace/gp-7", "greenplace/gp-9"}
+	for i, hit := range result.Results {
+		if got := hit.Rig + "/" + hit.ID; got != order[i] {
+			t.Errorf("result %d = %s, want %s", i, got, order[i])
+		}
+	}
+	if result.Errors["beads"] != "database locked" {
+		t.Errorf("errors = %v, want beads: database locked", result.Errors)
+	}
+}
@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/stash"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadStashUntil  string
+	beadStashAfter  string
+	beadStashNote   string
+	beadStashDryRun bool
+)
+
+var beadStashCmd = &cobra.Command{
+	Use:   "stash <bead-id> --until <date> | --after <bead-id>",
+	Short: "Defer a bead and bring it back later",
+	Long: `Take a bead out of the ready queue until a date or until another bead
+closes.
+
+The bead is set to status deferred, so it isn't slung or picked up. Each
+patrol the Deacon runs 'gt bead stash resurface', which reopens stashed
+beads whose time has come and comments on them with the stash note, so
+deferred work comes back instead of disappearing into the backlog.
+
+--until takes a date (2025-07-01), a date and time (2025-07-01 09:00) or a
+duration from now (3d, 12h).
+
+Examples:
+  gt bead stash gt-abc --until 2025-07-01 -m "after the launch freeze"
+  gt bead stash gt-abc --until 2w
+  gt bead stash gt-abc --after gt-def -m "needs the new schema"
+  gt bead stash list
+  gt bead unstash gt-abc`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadStash,
+}
+
+var beadStashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stashed beads and when they resurface",
+	Args:  cobra.NoArgs,
+	RunE:  runBeadStashList,
+}
+
+var beadStashResurfaceCmd = &cobra.Command{
+	Use:   "resurface",
+	Short: "Reopen stashed beads whose time has come",
+	Long: `Reopen stashed beads that are due: their --until time has passed or
+their --after bead has closed. Each is set back to open and commented with
+its stash note. The Deacon runs this every patrol.
+
+Stashes of beads that were reopened or closed by hand are dropped.
+
+Examples:
+  gt bead stash resurface
+  gt bead stash resurface --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runBeadStashResurface,
+}
+
+var beadUnstashCmd = &cobra.Command{
+	Use:   "unstash <bead-id>",
+	Short: "Bring a stashed bead back now",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runBeadUnstash,
+}
+
+var (
+	// stashShowFn is a seam for tests. Production runs bd show.
+	stashShowFn = func(id string) (*beads.Issue, error) {
+		return beads.New(resolveBeadDir(id)).Show(id)
+	}
+
+	// stashSetStatusFn is a seam for tests. Production runs bd update.
+	stashSetStatusFn = func(id, status string) error {
+		return beads.New(resolveBeadDir(id)).Update(id, beads.UpdateOptions{Status: &status})
+	}
+
+	// stashCommentFn is a seam for tests. Production runs bd comments add.
+	stashCommentFn = func(id, text string) error {
+		_, err := beads.New(resolveBeadDir(id)).Run("comments", "add", id, text)
+		return err
+	}
+)
+
+func init() {
+	beadStashCmd.Flags().StringVar(&beadStashUntil, "until", "", "Resurface at this date, time or duration from now")
+	beadStashCmd.Flags().StringVar(&beadStashAfter, "after", "", "Resurface once this bead closes")
+	beadStashCmd.Flags().StringVarP(&beadStashNote, "message", "m", "", "Note left on the bead when it resurfaces")
+	beadStashCmd.MarkFlagsMutuallyExclusive("until", "after")
+	beadStashCmd.MarkFlagsOneRequired("until", "after")
+	beadStashResurfaceCmd.Flags().BoolVarP(&beadStashDryRun, "dry-run", "n", false, "Show what would resurface")
+
+	beadStashCmd.AddCommand(beadStashListCmd)
+	beadStashCmd.AddCommand(beadStashResurfaceCmd)
+	beadCmd.AddCommand(beadStashCmd)
+	beadCmd.AddCommand(beadUnstashCmd)
+}
+
+func runBeadStash(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	st, err := newStash(args[0], beadStashUntil, beadStashAfter, beadStashNote, time.Now())
+	if err != nil {
+		return err
+	}
+	state, err := stash.LoadState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading stash state: %w", err)
+	}
+	if err := stashBead(state, st); err != nil {
+		return err
+	}
+	if err := state.Save(townRoot); err != nil {
+		return fmt.Errorf("saving stash state: %w", err)
+	}
+	fmt.Printf("%s Stashed %s %s\n", style.SuccessPrefix, style.Bold.Render(st.Bead), st.Condition())
+	return nil
+}
+
+// newStash builds a stash from the command's flags.
+func newStash(id, until, after, note string, now time.Time) (*stash.Stash, error) {
+	st := &stash.Stash{Bead: id, After: after, Note: note, By: detectSender(), At: now}
+	if after == id {
+		return nil, fmt.Errorf("a bead can't wait for itself")
+	}
+	if until != "" {
+		t, err := parseStashUntil(until, now)
+		if err != nil {
+			return nil, err
+		}
+		if !t.After(now) {
+			return nil, fmt.Errorf("--until %s is not in the future", until)
+		}
+		st.Until = t
+	}
+	return st, nil
+}
+
+// parseStashUntil reads --until as a local date, date and time, RFC 3339
+// time or duration from now.
+func parseStashUntil(s string, now time.Time) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if strings.HasSuffix(s, "w") {
+		if d, err := parseDuration(strings.TrimSuffix(s, "w") + "d"); err == nil {
+			return now.Add(7 * d), nil
+		}
+	}
+	if d, err := parseDuration(s); err == nil {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --until %q (use 2025-07-01, \"2025-07-01 09:00\" or a duration like 3d)", s)
+}
+
+// stashBead checks the bead can be stashed, defers it and records the stash.
+func stashBead(state *stash.State, st *stash.Stash) error {
+	issue, err := stashShowFn(st.Bead)
+	if err != nil {
+		return fmt.Errorf("bead %s: %w", st.Bead, err)
+	}
+	switch issue.Status {
+	case "closed", "tombstone":
+		return fmt.Errorf("%s is %s", st.Bead, issue.Status)
+	case beads.StatusHooked, "in_progress":
+		return fmt.Errorf("%s is %s by %s; unsling it before stashing", st.Bead, issue.Status, orDash(issue.Assignee))
+	}
+	if st.After != "" {
+		after, err := stashShowFn(st.After)
+		if err != nil {
+			return fmt.Errorf("--after bead %s: %w", st.After, err)
+		}
+		if isClosedStatus(after.Status) {
+			return fmt.Errorf("%s is already %s; nothing to wait for", st.After, after.Status)
+		}
+	}
+	if issue.Status != "deferred" {
+		if err := stashSetStatusFn(st.Bead, "deferred"); err != nil {
+			return fmt.Errorf("deferring %s: %w", st.Bead, err)
+		}
+	}
+	state.Stashes[st.Bead] = st
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func isClosedStatus(status string) bool {
+	return status == "closed" || status == "tombstone"
+}
+
+func runBeadStashList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := stash.LoadState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading stash state: %w", err)
+	}
+	list := state.List()
+	if len(list) == 0 {
+		fmt.Println("No stashed beads.")
+		return nil
+	}
+	for _, st := range list {
+		line := fmt.Sprintf("%-14s %-32s %s", st.Bead, st.Condition(), style.Dim.Render("by "+orDash(st.By)+" "+st.At.Local().Format("2006-01-02")))
+		if st.Note != "" {
+			line += "  " + st.Note
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runBeadStashResurface(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := stash.LoadState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading stash state: %w", err)
+	}
+	resurfaceStashes(state, time.Now(), beadStashDryRun)
+	if beadStashDryRun {
+		return nil
+	}
+	return state.Save(townRoot)
+}
+
+// resurfaceStashes reopens the due stashes and removes them from state.
+// Lookups that fail are warned about and retried next run.
+func resurfaceStashes(state *stash.State, now time.Time, dryRun bool) {
+	for _, st := range state.List() {
+		issue, err := stashShowFn(st.Bead)
+		if err != nil {
+			style.PrintWarning("stash %s: %v", st.Bead, err)
+			continue
+		}
+		if issue.Status != "deferred" {
+			fmt.Printf("%s %s is %s now; dropped its stash\n", style.Dim.Render("○"), st.Bead, issue.Status)
+			if !dryRun {
+				delete(state.Stashes, st.Bead)
+			}
+			continue
+		}
+		afterClosed := false
+		if st.After != "" {
+			after, err := stashShowFn(st.After)
+			if err != nil {
+				style.PrintWarning("stash %s: --after bead %s: %v", st.Bead, st.After, err)
+				continue
+			}
+			afterClosed = isClosedStatus(after.Status)
+		}
+		if !st.Due(now, afterClosed) {
+			continue
+		}
+		if dryRun {
+			fmt.Printf("%s would resurface %s (%s)\n", style.Dim.Render("[dry-run]"), st.Bead, st.Condition())
+			continue
+		}
+		if err := resurfaceStash(st); err != nil {
+			style.PrintWarning("stash %s: %v", st.Bead, err)
+			continue
+		}
+		delete(state.Stashes, st.Bead)
+		fmt.Printf("%s Resurfaced %s (%s)\n", style.SuccessPrefix, st.Bead, st.Condition())
+	}
+}
+
+// resurfaceStash reopens a stashed bead and leaves the stash note on it.
+// A failed comment is only warned about: the bead is back either way.
+func resurfaceStash(st *stash.Stash) error {
+	if err := stashSetStatusFn(st.Bead, "open"); err != nil {
+		return fmt.Errorf("reopening: %w", err)
+	}
+	if err := stashCommentFn(st.Bead, st.ResurfaceComment()); err != nil {
+		style.PrintWarning("stash %s: adding note: %v", st.Bead, err)
+	}
+	return nil
+}
+
+func runBeadUnstash(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := stash.LoadState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading stash state: %w", err)
+	}
+	st, ok := state.Stashes[args[0]]
+	if !ok {
+		return fmt.Errorf("%s is not stashed (see gt bead stash list)", args[0])
+	}
+	if err := resurfaceStash(st); err != nil {
+		return err
+	}
+	delete(state.Stashes, st.Bead)
+	if err := state.Save(townRoot); err != nil {
+		return fmt.Errorf("saving stash state: %w", err)
+	}
+	fmt.Printf("%s Unstashed %s\n", style.SuccessPrefix, st.Bead)
+	return nil
+}
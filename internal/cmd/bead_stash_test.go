@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/stash"
+)
+
+// fakeStashBeads stands in for bd: statuses by bead ID, and the comments left.
+func fakeStashBeads(t *testing.T, statuses map[string]string) map[string]string {
+	t.Helper()
+	origShow, origSet, origComment := stashShowFn, stashSetStatusFn, stashCommentFn
+	t.Cleanup(func() { stashShowFn, stashSetStatusFn, stashCommentFn = origShow, origSet, origComment })
+	comments := make(map[string]string)
+	stashShowFn = func(id string) (*beads.Issue, error) {
+		status, ok := statuses[id]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return &beads.Issue{ID: id, Status: status}, nil
+	}
+	stashSetStatusFn = func(id, status string) error {
+		statuses[id] = status
+		return nil
+	}
+	stashCommentFn = func(id, text string) error {
+		comments[id] = text
+		return nil
+	}
+	return comments
+}
+
+func TestParseStashUntil(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.Local)
+	tests := map[string]time.Time{
+		"2026-07-01":       time.Date(2026, 7, 1, 0, 0, 0, 0, time.Local),
+		"2026-07-01 09:30": time.Date(2026, 7, 1, 9, 30, 0, 0, time.Local),
+		"3d":               now.Add(72 * time.Hour),
+		"2w":               now.Add(14 * 24 * time.Hour),
+		"12h":              now.Add(12 * time.Hour),
+	}
+	for in, want := range tests {
+		if got, err := parseStashUntil(in, now); err != nil || !got.Equal(want) {
+			t.Errorf("parseStashUntil(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseStashUntil("next tuesday", now); err == nil {
+		t.Error("expected an error for an unparseable date")
+	}
+	if _, err := newStash("gt-a", "2020-01-01", "", "", now); err == nil {
+		t.Error("a past --until should be refused")
+	}
+}
+
+func TestStashBead(t *testing.T) {
+	statuses := map[string]string{"gt-a": "open", "gt-b": "open", "gt-done": "closed", "gt-busy": beads.StatusHooked}
+	fakeStashBeads(t, statuses)
+	state := &stash.State{Stashes: make(map[string]*stash.Stash)}
+
+	if err := stashBead(state, &stash.Stash{Bead: "gt-a", After: "gt-b"}); err != nil {
+		t.Fatal(err)
+	}
+	if statuses["gt-a"] != "deferred" || state.Stashes["gt-a"] == nil {
+		t.Errorf("gt-a: status %s, stash %+v", statuses["gt-a"], state.Stashes["gt-a"])
+	}
+	for _, st := range []*stash.Stash{
+		{Bead: "gt-done", Until: time.Now().Add(time.Hour)},
+		{Bead: "gt-busy", Until: time.Now().Add(time.Hour)},
+		{Bead: "gt-b", After: "gt-done"},
+		{Bead: "gt-missing", Until: time.Now().Add(time.Hour)},
+	} {
+		if err := stashBead(state, st); err == nil {
+			t.Errorf("stashing %s (after %q) should fail", st.Bead, st.After)
+		}
+	}
+	if len(state.Stashes) != 1 {
+		t.Errorf("stashes = %v", state.Stashes)
+	}
+}
+
+func TestResurfaceStashes(t *testing.T) {
+	now := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+	statuses := map[string]string{
+		"gt-due": "deferred", "gt-later": "deferred", "gt-waits": "deferred", "gt-blocker": "closed",
+		"gt-waiting": "deferred", "gt-open-blocker": "open", "gt-reopened": "open",
+	}
+	comments := fakeStashBeads(t, statuses)
+	state := &stash.State{Stashes: map[string]*stash.Stash{
+		"gt-due":      {Bead: "gt-due", Until: now.Add(-time.Hour), Note: "launch is over"},
+		"gt-later":    {Bead: "gt-later", Until: now.Add(time.Hour)},
+		"gt-waits":    {Bead: "gt-waits", After: "gt-blocker"},
+		"gt-waiting":  {Bead: "gt-waiting", After: "gt-open-blocker"},
+		"gt-reopened": {Bead: "gt-reopened", Until: now.Add(time.Hour)},
+	}}
+
+	captureStdout(t, func() { resurfaceStashes(state, now, true) })
+	if len(state.Stashes) != 5 || statuses["gt-due"] != "deferred" {
+		t.Fatalf("dry run changed something: %v %v", state.Stashes, statuses)
+	}
+
+	captureStdout(t, func() { resurfaceStashes(state, now, false) })
+	if statuses["gt-due"] != "open" || statuses["gt-waits"] != "open" || statuses["gt-later"] != "deferred" {
+		t.Errorf("statuses = %v", statuses)
+	}
+	if _, ok := state.Stashes["gt-reopened"]; ok {
+		t.Error("the stash of a bead reopened by hand should be dropped")
+	}
+	if len(state.Stashes) != 2 || state.Stashes["gt-later"] == nil || state.Stashes["gt-waiting"] == nil {
+		t.Errorf("remaining stashes = %v", state.Stashes)
+	}
+	if comments["gt-due"] == "" || comments["gt-waits"] == "" || len(comments) != 2 {
+		t.Errorf("comments = %v", comments)
+	}
+}
@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/bench"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	benchRig    string
+	benchMetric string
+	benchLimit  int
+	benchJSON   bool
+)
+
+var benchCmd = &cobra.Command{
+	Use:     "bench",
+	GroupID: GroupWork,
+	Short:   "Benchmark regression detection for the merge queue",
+	Long: `Catch performance regressions before they merge.
+
+When a rig's config.json sets merge_queue.bench, the refinery runs the
+benchmark command on each merged tree before pushing. Results are compared
+against recent merges; a metric that is significantly worse (one-sided
+t-test, p < 0.01) and beyond the threshold bounces the branch back to the
+polecat with the comparison. Results of successful merges are recorded in
+<rig>/.runtime/bench-history.jsonl.
+
+Output must be in Go benchmark format (value/unit pairs after the iteration
+count). Units ending in /s are treated as higher-is-better.
+
+Example rig config:
+  "merge_queue": {
+    "bench": {
+      "cmd": "go test -run='^$' -bench=. -count=5 ./internal/parser",
+      "timeout": "10m",
+      "threshold": 0.10,
+      "window": 20,
+      "min_samples": 5
+    }
+  }
+
+Examples:
+  gt bench run                    # Benchmark this worktree against the rig's history
+  gt bench history gastown
+  gt bench history gastown --metric BenchmarkParse -n 10`,
+	RunE: requireSubcommand,
+}
+
+var benchRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run the rig's benchmarks here and compare with merge history",
+	Long: `Run the rig's benchmark command in the current directory and compare the
+results with recent merges, the same way the merge queue will. Nothing is
+recorded. Polecats can use this before gt done.`,
+	Args: cobra.NoArgs,
+	RunE: runBenchRun,
+}
+
+var benchHistoryCmd = &cobra.Command{
+	Use:   "history <rig>",
+	Short: "Show recorded benchmark results per merge",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runBenchHistory,
+}
+
+func init() {
+	benchRunCmd.Flags().StringVar(&benchRig, "rig", "", "Rig whose benchmark config and history to use (default: current rig)")
+	benchRunCmd.Flags().BoolVar(&benchJSON, "json", false, "Output the comparison as JSON")
+	benchHistoryCmd.Flags().StringVar(&benchMetric, "metric", "", "Only show metrics containing this text")
+	benchHistoryCmd.Flags().IntVarP(&benchLimit, "limit", "n", 20, "Number of merges to show (0 = all)")
+	benchHistoryCmd.Flags().BoolVar(&benchJSON, "json", false, "Output as JSON")
+
+	benchCmd.AddCommand(benchRunCmd, benchHistoryCmd)
+	rootCmd.AddCommand(benchCmd)
+}
+
+// loadBenchConfig returns the rig's merge_queue.bench config.
+func loadBenchConfig(r *rig.Rig) (*bench.Config, error) {
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return nil, err
+	}
+	cfg := eng.Config().Bench
+	if !cfg.IsEnabled() {
+		return nil, fmt.Errorf("rig %s has no benchmark command (set merge_queue.bench.cmd in %s/config.json)", r.Name, r.Name)
+	}
+	return cfg, nil
+}
+
+func runBenchRun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	var r *rig.Rig
+	if benchRig != "" {
+		_, r, err = getRig(benchRig)
+	} else {
+		_, r, err = findCurrentRig(townRoot)
+	}
+	if err != nil {
+		return err
+	}
+	cfg, err := loadBenchConfig(r)
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	if !benchJSON {
+		fmt.Printf("Running benchmarks: %s\n", style.Dim.Render(cfg.Cmd))
+	}
+	results, err := bench.Run(context.Background(), cfg, cwd)
+	if err != nil {
+		return err
+	}
+	history, err := bench.History(r.Path)
+	if err != nil {
+		return err
+	}
+	report := bench.Compare(cfg, results, history)
+
+	if benchJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Print(report.Table())
+	}
+	if regs := report.Regressions(); len(regs) > 0 {
+		if !benchJSON {
+			fmt.Printf("\n%s %s\n", style.Warning.Render("✗"), report.Summary())
+		}
+		return NewSilentExit(1)
+	}
+	if !benchJSON {
+		fmt.Printf("\n%s No regressions against %d recorded merge(s)\n", style.Success.Render("✓"), len(history))
+	}
+	return nil
+}
+
+func runBenchHistory(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	entries, err := bench.History(r.Path)
+	if err != nil {
+		return err
+	}
+	if benchLimit > 0 && len(entries) > benchLimit {
+		entries = entries[len(entries)-benchLimit:]
+	}
+	if benchMetric != "" {
+		for i := range entries {
+			filtered := make(bench.Results)
+			for name, samples := range entries[i].Results {
+				if strings.Contains(name, benchMetric) {
+					filtered[name] = samples
+				}
+			}
+			entries[i].Results = filtered
+		}
+	}
+
+	if benchJSON {
+		if entries == nil {
+			entries = []bench.Entry{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	if len(entries) == 0 {
+		fmt.Printf("No benchmark results recorded for %s.\n", r.Name)
+		return nil
+	}
+
+	for _, e := range entries {
+		commit := e.MergeCommit
+		if len(commit) > 8 {
+			commit = commit[:8]
+		}
+		fmt.Printf("%s %s %s\n", style.Dim.Render(e.Timestamp.Local().Format("2006-01-02 15:04")), style.Bold.Render(commit), e.Branch)
+		for _, name := range e.Results.Metrics() {
+			fmt.Printf("    %-50s %s\n", name, formatBenchSamples(e.Results[name]))
+		}
+	}
+	return nil
+}
+
+// formatBenchSamples shows the mean of a metric's samples and their count.
+func formatBenchSamples(samples []float64) string {
+	sum := 0.0
+	for _, s := range samples {
+		sum += s
+	}
+	mean := sum / float64(len(samples))
+	if len(samples) == 1 {
+		return fmt.Sprintf("%.4g", mean)
+	}
+	return fmt.Sprintf("%.4g (n=%d)", mean, len(samples))
+}
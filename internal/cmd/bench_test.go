@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestFormatBenchSamples(t *testing.T) {
+	if got := formatBenchSamples([]float64{250}); got != "250" {
+		t.Errorf("formatBenchSamples(single) = %q", got)
+	}
+	if got := formatBenchSamples([]float64{100, 200, 300}); got != "200 (n=3)" {
+		t.Errorf("formatBenchSamples(multi) = %q", got)
+	}
+}
+
+func TestLoadBenchConfigRequiresCmd(t *testing.T) {
+	_, err := loadBenchConfig(&rig.Rig{Name: "gastown", Path: t.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), "merge_queue.bench.cmd") {
+		t.Errorf("loadBenchConfig() error = %v, want hint about merge_queue.bench.cmd", err)
+	}
+}
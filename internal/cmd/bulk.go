@@ -0,0 +1,397 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/undo"
+	"github.com/steveyegge/gastown/internal/views"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	bulkLabel    string
+	bulkStatus   string
+	bulkAssignee string
+	bulkRig      string
+	bulkConvoy   string
+	bulkView     string
+	bulkLimit    int
+	bulkDryRun   bool
+	bulkYes      bool
+
+	bulkReason   string
+	bulkAdd      []string
+	bulkRemove   []string
+	bulkPriority int
+	bulkTo       string
+)
+
+var bulkCmd = &cobra.Command{
+	Use:     "bulk",
+	GroupID: GroupWork,
+	Short:   "Apply one operation to every bead matching a query",
+	RunE:    requireSubcommand,
+	Long: `Apply an operation to every bead matching a query.
+
+Beads are selected like gt bead search (text query, --label, --status,
+--assignee, --rig across every routed database) or by a saved --view, and
+can be narrowed to the beads a --convoy tracks. Agent, convoy and
+molecule beads are never touched. A query that fails in any database
+aborts the run rather than act on a partial match.
+
+Every run previews the matched beads and the change, then asks for
+confirmation according to the town's "bulk" confirmation policy (a
+[y/N] prompt unless configured otherwise; see gt confirm). --dry-run
+stops after the preview. A bulk close is recorded for gt undo.
+
+Examples:
+  gt bulk close --convoy hq-cv-abc --reason "mis-planned, re-filing"
+  gt bulk relabel --label needs-triage --add triaged --remove needs-triage
+  gt bulk reprioritize flaky --rig gastown --priority 1
+  gt bulk reassign --assignee gastown/polecats/Toast --to gastown/polecats/Nux
+  gt bulk resling --convoy hq-cv-abc --status open --to gastown --dry-run`,
+}
+
+var bulkCloseCmd = &cobra.Command{
+	Use:   "close [query]",
+	Short: "Close matching beads",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runBulk,
+}
+
+var bulkRelabelCmd = &cobra.Command{
+	Use:   "relabel [query]",
+	Short: "Add and remove labels on matching beads",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runBulk,
+}
+
+var bulkReprioritizeCmd = &cobra.Command{
+	Use:   "reprioritize [query]",
+	Short: "Set the priority of matching beads",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runBulk,
+}
+
+var bulkReassignCmd = &cobra.Command{
+	Use:   "reassign [query]",
+	Short: "Assign matching beads to another agent (--to none unassigns)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runBulk,
+}
+
+var bulkReslingCmd = &cobra.Command{
+	Use:   "resling [query]",
+	Short: "Sling matching beads to a target (gt sling <bead> <target>)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runBulk,
+}
+
+var (
+	// bulkDatabasesFn is a seam for tests. Production uses beadSearchDBs.
+	bulkDatabasesFn = beadSearchDBs
+
+	// bulkQueryFn is a seam for tests. Production lists every match and applies
+	// the text query locally: bd search caps results, and a bulk run must not
+	// silently miss beads.
+	bulkQueryFn = func(dbs []beadSearchDB, opts beads.SearchOptions) beadSearchResult {
+		return searchAllBeads(dbs, func(dir string) ([]*beads.Issue, error) {
+			issues, err := beads.New(dir).List(beads.ListOptions{
+				Status:   opts.Status,
+				Label:    opts.Label,
+				Assignee: opts.Assignee,
+				Priority: -1,
+				Limit:    opts.Limit,
+			})
+			if err != nil || opts.Query == "" {
+				return issues, err
+			}
+			var matched []*beads.Issue
+			for _, issue := range issues {
+				if matchesView(issue, &views.View{Query: opts.Query}, "") {
+					matched = append(matched, issue)
+				}
+			}
+			return matched, nil
+		})
+	}
+
+	// bulkRunViewFn is a seam for tests. Production uses runSavedView.
+	bulkRunViewFn = func(townRoot, name string) (beadSearchResult, error) {
+		v, err := lookupView(townRoot, name)
+		if err != nil {
+			return beadSearchResult{}, err
+		}
+		return runSavedView(townRoot, v, detectSender(), time.Now())
+	}
+
+	// bulkConvoyBeadsFn is a seam for tests. Production reads the convoy's
+	// tracked beads.
+	bulkConvoyBeadsFn = func(convoyID string) (map[string]bool, error) {
+		townBeads, err := getTownBeadsDir()
+		if err != nil {
+			return nil, err
+		}
+		tracked, err := getTrackedIssues(townBeads, convoyID)
+		if err != nil {
+			return nil, err
+		}
+		ids := map[string]bool{}
+		for _, t := range tracked {
+			ids[t.ID] = true
+		}
+		return ids, nil
+	}
+
+	// bulkUpdateFn is a seam for tests. Production runs bd update.
+	bulkUpdateFn = func(dir, id string, opts beads.UpdateOptions) error {
+		return beads.New(dir).Update(id, opts)
+	}
+
+	// bulkCloseFn is a seam for tests. Production runs bd close.
+	bulkCloseFn = func(dir, reason string, id string) error {
+		return beads.New(dir).CloseWithReason(reason, id)
+	}
+
+	// bulkCheckConvoysFn is a seam for tests. Production uses
+	// checkConvoyCompletion.
+	bulkCheckConvoysFn = checkConvoyCompletion
+
+	// bulkSlingFn is a seam for tests. Production runs gt sling in the town.
+	bulkSlingFn = func(townRoot, id, target string) error {
+		c := exec.Command("gt", "sling", id, target)
+		c.Dir = townRoot
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		return c.Run()
+	}
+)
+
+func init() {
+	pf := bulkCmd.PersistentFlags()
+	pf.StringVarP(&bulkLabel, "label", "l", "", "Only beads with this label")
+	pf.StringVarP(&bulkStatus, "status", "s", "open", "Only beads with this status (open, in_progress, hooked, closed, all)")
+	pf.StringVarP(&bulkAssignee, "assignee", "a", "", "Only beads assigned to this agent")
+	pf.StringVar(&bulkRig, "rig", "", "Only this rig's beads (or \"town\")")
+	pf.StringVar(&bulkConvoy, "convoy", "", "Only beads tracked by this convoy")
+	pf.StringVar(&bulkView, "view", "", "Select beads with a saved view (see gt view)")
+	pf.IntVarP(&bulkLimit, "limit", "n", 0, "Max beads per database (0 = no limit)")
+	pf.BoolVar(&bulkDryRun, "dry-run", false, "Preview the change without applying it")
+	pf.BoolVarP(&bulkYes, "yes", "y", false, "Confirm without prompting (if the town's policy allows)")
+
+	bulkCloseCmd.Flags().StringVarP(&bulkReason, "reason", "r", "", "Reason recorded on each closed bead")
+	bulkRelabelCmd.Flags().StringSliceVar(&bulkAdd, "add", nil, "Label to add (repeatable)")
+	bulkRelabelCmd.Flags().StringSliceVar(&bulkRemove, "remove", nil, "Label to remove (repeatable)")
+	bulkReprioritizeCmd.Flags().IntVarP(&bulkPriority, "priority", "p", -1, "New priority (0-4)")
+	bulkReassignCmd.Flags().StringVar(&bulkTo, "to", "", "Agent to assign the beads to (\"none\" to unassign)")
+	bulkReslingCmd.Flags().StringVar(&bulkTo, "to", "", "Sling target (rig, agent or polecat, as for gt sling)")
+
+	for _, c := range []*cobra.Command{bulkCloseCmd, bulkRelabelCmd, bulkReprioritizeCmd, bulkReassignCmd, bulkReslingCmd} {
+		bulkCmd.AddCommand(c)
+	}
+	rootCmd.AddCommand(bulkCmd)
+}
+
+// bulkOp is one bulk operation, resolved from its subcommand and flags.
+type bulkOp struct {
+	Name     string // Subcommand name
+	Describe string // What the op does to each bead, for the preview
+	apply    func(townRoot, dir string, issue *beads.Issue) error
+}
+
+// newBulkOp validates the subcommand's flags and builds its operation.
+func newBulkOp(name string) (*bulkOp, error) {
+	switch name {
+	case "close":
+		reason := bulkReason
+		if reason == "" {
+			reason = "bulk close"
+		}
+		return &bulkOp{Name: name, Describe: "close (" + reason + ")", apply: func(_, dir string, issue *beads.Issue) error {
+			return bulkCloseFn(dir, reason, issue.ID)
+		}}, nil
+
+	case "relabel":
+		if len(bulkAdd) == 0 && len(bulkRemove) == 0 {
+			return nil, fmt.Errorf("relabel needs --add or --remove")
+		}
+		var parts []string
+		for _, l := range bulkAdd {
+			parts = append(parts, "+"+l)
+		}
+		for _, l := range bulkRemove {
+			parts = append(parts, "-"+l)
+		}
+		add, remove := bulkAdd, bulkRemove
+		return &bulkOp{Name: name, Describe: "relabel " + strings.Join(parts, " "), apply: func(_, dir string, issue *beads.Issue) error {
+			return bulkUpdateFn(dir, issue.ID, beads.UpdateOptions{AddLabels: add, RemoveLabels: remove})
+		}}, nil
+
+	case "reprioritize":
+		if bulkPriority < 0 || bulkPriority > 4 {
+			return nil, fmt.Errorf("reprioritize needs --priority between 0 and 4")
+		}
+		p := bulkPriority
+		return &bulkOp{Name: name, Describe: fmt.Sprintf("set priority P%d", p), apply: func(_, dir string, issue *beads.Issue) error {
+			return bulkUpdateFn(dir, issue.ID, beads.UpdateOptions{Priority: &p})
+		}}, nil
+
+	case "reassign":
+		if bulkTo == "" {
+			return nil, fmt.Errorf("reassign needs --to <agent> (or --to none)")
+		}
+		to, describe := bulkTo, "assign to "+bulkTo
+		if strings.EqualFold(to, "none") {
+			to, describe = "", "unassign"
+		}
+		return &bulkOp{Name: name, Describe: describe, apply: func(_, dir string, issue *beads.Issue) error {
+			return bulkUpdateFn(dir, issue.ID, beads.UpdateOptions{Assignee: &to})
+		}}, nil
+
+	case "resling":
+		if bulkTo == "" {
+			return nil, fmt.Errorf("resling needs --to <target>")
+		}
+		target := bulkTo
+		return &bulkOp{Name: name, Describe: "sling to " + target, apply: func(townRoot, _ string, issue *beads.Issue) error {
+			return bulkSlingFn(townRoot, issue.ID, target)
+		}}, nil
+	}
+	return nil, fmt.Errorf("unknown bulk operation %q", name)
+}
+
+func runBulk(cmd *cobra.Command, args []string) error {
+	op, err := newBulkOp(cmd.Name())
+	if err != nil {
+		return err
+	}
+	query := ""
+	if len(args) > 0 {
+		query = strings.TrimSpace(args[0])
+	}
+	if query == "" && bulkLabel == "" && bulkAssignee == "" && bulkConvoy == "" && bulkView == "" {
+		return fmt.Errorf("give a query or at least one of --label, --assignee, --convoy, --view")
+	}
+	if bulkView != "" && (query != "" || bulkLabel != "" || bulkAssignee != "") {
+		return fmt.Errorf("--view selects beads itself; combine it only with --rig and --convoy")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	hits, dirs, err := bulkMatches(townRoot, query)
+	if err != nil {
+		return err
+	}
+	if len(hits) == 0 {
+		fmt.Println("No matching beads.")
+		return nil
+	}
+
+	fmt.Printf("%s %s on %d bead(s):\n", style.Bold.Render("Bulk"), op.Describe, len(hits))
+	printBeadSearch(beadSearchResult{Results: hits})
+	if bulkDryRun {
+		fmt.Println(style.Dim.Render("Dry run: nothing changed."))
+		return nil
+	}
+	if proceed, err := confirmDestructive("bulk", fmt.Sprintf("%s of %d beads", op.Name, len(hits)), bulkYes); err != nil {
+		return err
+	} else if !proceed {
+		fmt.Println("Aborted.")
+		return nil
+	}
+
+	var done []string
+	var closed []undo.ClosedBead
+	failed := 0
+	for _, hit := range hits {
+		if err := op.apply(townRoot, dirs[hit.Rig], hit.Issue); err != nil {
+			failed++
+			fmt.Printf("  %s %s: %v\n", style.Error.Render("✗"), hit.ID, err)
+			continue
+		}
+		done = append(done, hit.ID)
+		if op.Name == "close" {
+			closed = append(closed, undo.ClosedBead{ID: hit.ID, Status: hit.Status, Assignee: hit.Assignee})
+		}
+	}
+
+	if len(closed) > 0 {
+		recordUndo(townRoot, undo.KindClose, closeSummary(closed), undo.CloseState{Beads: closed})
+		bulkCheckConvoysFn(done)
+	}
+	fmt.Printf("\n%s %s: %d done", style.SuccessPrefix, op.Describe, len(done))
+	if failed > 0 {
+		fmt.Printf(", %d failed", failed)
+	}
+	fmt.Println()
+	if failed > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// bulkMatches resolves the query and filters to beads, skipping
+// infrastructure beads. It also returns each rig's database directory.
+func bulkMatches(townRoot, query string) ([]beadSearchHit, map[string]string, error) {
+	dbs, err := bulkDatabasesFn(townRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+	dirs := map[string]string{}
+	var selected []beadSearchDB
+	for _, db := range dbs {
+		dirs[db.Rig] = db.Dir
+		if bulkRig == "" || db.Rig == bulkRig {
+			selected = append(selected, db)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, nil, fmt.Errorf("no beads database routed for rig %s", bulkRig)
+	}
+
+	var result beadSearchResult
+	if bulkView != "" {
+		if result, err = bulkRunViewFn(townRoot, bulkView); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		result = bulkQueryFn(selected, beads.SearchOptions{Query: query, Status: bulkStatus, Label: bulkLabel, Assignee: bulkAssignee, Limit: bulkLimit})
+	}
+	if len(result.Errors) > 0 {
+		var failed []string
+		for rigName, msg := range result.Errors {
+			failed = append(failed, rigName+": "+msg)
+		}
+		sort.Strings(failed)
+		return nil, nil, fmt.Errorf("query failed, refusing to act on a partial match: %s", strings.Join(failed, "; "))
+	}
+
+	var tracked map[string]bool
+	if bulkConvoy != "" {
+		if tracked, err = bulkConvoyBeadsFn(bulkConvoy); err != nil {
+			return nil, nil, fmt.Errorf("listing beads tracked by %s: %w", bulkConvoy, err)
+		}
+	}
+
+	var hits []beadSearchHit
+	for _, hit := range result.Results {
+		switch {
+		case isDedupInfraBead(hit.Issue):
+		case tracked != nil && !tracked[hit.ID]:
+		case bulkRig != "" && hit.Rig != bulkRig:
+		default:
+			hits = append(hits, hit)
+		}
+	}
+	return hits, dirs, nil
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/undo"
+)
+
+// stubBulk points gt bulk at a fixed set of beads and records what it
+// does to them.
+func stubBulk(t *testing.T) (town string, calls *[]string) {
+	t.Helper()
+	town = t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(town)
+
+	origDBs, origQuery, origConvoy, origUpdate, origClose, origSling, origCheck :=
+		bulkDatabasesFn, bulkQueryFn, bulkConvoyBeadsFn, bulkUpdateFn, bulkCloseFn, bulkSlingFn, bulkCheckConvoysFn
+	t.Cleanup(func() {
+		bulkDatabasesFn, bulkQueryFn, bulkConvoyBeadsFn, bulkUpdateFn, bulkCloseFn, bulkSlingFn, bulkCheckConvoysFn =
+			origDBs, origQuery, origConvoy, origUpdate, origClose, origSling, origCheck
+		resetBulkFlags()
+	})
+	resetBulkFlags()
+	bulkYes = true
+
+	bulkDatabasesFn = func(string) ([]beadSearchDB, error) {
+		return []beadSearchDB{{Rig: "town", Dir: "/town"}, {Rig: "gastown", Dir: "/gastown"}}, nil
+	}
+	bulkQueryFn = func(dbs []beadSearchDB, opts beads.SearchOptions) beadSearchResult {
+		all := []beadSearchHit{
+			{Rig: "gastown", Issue: &beads.Issue{ID: "gt-1", Title: "flaky test", Status: "open", Labels: []string{"triage"}}},
+			{Rig: "gastown", Issue: &beads.Issue{ID: "gt-2", Title: "flaky build", Status: "open", Assignee: "gastown/polecats/Toast"}},
+			{Rig: "gastown", Issue: &beads.Issue{ID: "gt-cv", Title: "flaky convoy", Status: "open", Type: "convoy"}},
+			{Rig: "town", Issue: &beads.Issue{ID: "hq-3", Title: "unrelated", Status: "open"}},
+		}
+		result := beadSearchResult{Results: []beadSearchHit{}}
+		for _, hit := range all {
+			for _, db := range dbs {
+				if db.Rig == hit.Rig && strings.Contains(hit.Title, opts.Query) {
+					result.Results = append(result.Results, hit)
+				}
+			}
+		}
+		return result
+	}
+
+	var log []string
+	bulkUpdateFn = func(dir, id string, opts beads.UpdateOptions) error {
+		switch {
+		case opts.Priority != nil:
+			log = append(log, "priority "+dir+" "+id)
+		case opts.Assignee != nil:
+			log = append(log, "assign "+id+" "+*opts.Assignee)
+		default:
+			log = append(log, "labels "+id+" +"+strings.Join(opts.AddLabels, ",")+" -"+strings.Join(opts.RemoveLabels, ","))
+		}
+		return nil
+	}
+	bulkCloseFn = func(dir, reason, id string) error { log = append(log, "close "+id+" "+reason); return nil }
+	bulkSlingFn = func(_, id, target string) error { log = append(log, "sling "+id+" "+target); return nil }
+	bulkCheckConvoysFn = func([]string) {}
+	return town, &log
+}
+
+// resetBulkFlags restores the flag defaults.
+func resetBulkFlags() {
+	bulkLabel, bulkStatus, bulkAssignee, bulkRig, bulkConvoy, bulkView = "", "open", "", "", "", ""
+	bulkLimit, bulkDryRun, bulkYes = 0, false, false
+	bulkReason, bulkAdd, bulkRemove, bulkPriority, bulkTo = "", nil, nil, -1, ""
+}
+
+func TestRunBulkClose(t *testing.T) {
+	town, calls := stubBulk(t)
+	bulkReason = "mis-planned"
+
+	captureStdout(t, func() {
+		if err := runBulk(bulkCloseCmd, []string{"flaky"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	// The convoy bead matches the query but is infrastructure.
+	if got := strings.Join(*calls, "; "); got != "close gt-1 mis-planned; close gt-2 mis-planned" {
+		t.Errorf("calls = %s", got)
+	}
+	ops, err := undo.List(town)
+	if err != nil || len(ops) != 1 || ops[0].Kind != undo.KindClose {
+		t.Fatalf("undo log = %+v, %v", ops, err)
+	}
+}
+
+func TestRunBulkFilters(t *testing.T) {
+	_, calls := stubBulk(t)
+	bulkRig, bulkPriority = "gastown", 1
+	bulkConvoy = "hq-cv-1"
+	bulkConvoyBeadsFn = func(string) (map[string]bool, error) { return map[string]bool{"gt-2": true}, nil }
+
+	captureStdout(t, func() {
+		if err := runBulk(bulkReprioritizeCmd, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if got := strings.Join(*calls, "; "); got != "priority /gastown gt-2" {
+		t.Errorf("calls = %s", got)
+	}
+}
+
+func TestRunBulkDryRunAndValidation(t *testing.T) {
+	_, calls := stubBulk(t)
+	bulkDryRun, bulkLabel, bulkTo = true, "triage", "gastown"
+
+	out := captureStdout(t, func() {
+		if err := runBulk(bulkReslingCmd, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if len(*calls) != 0 || !strings.Contains(out, "gt-1") || !strings.Contains(out, "sling to gastown") {
+		t.Errorf("dry run: calls %v, output %q", *calls, out)
+	}
+
+	bulkDryRun, bulkLabel = false, ""
+	if err := runBulk(bulkCloseCmd, nil); err == nil {
+		t.Error("a bulk run with no query or filter should be refused")
+	}
+	bulkAdd, bulkRemove = nil, nil
+	if err := runBulk(bulkRelabelCmd, []string{"flaky"}); err == nil {
+		t.Error("relabel without --add or --remove should be refused")
+	}
+
+	bulkTo = "none"
+	captureStdout(t, func() {
+		if err := runBulk(bulkReassignCmd, []string{"build"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if got := strings.Join(*calls, "; "); got != "assign gt-2 " {
+		t.Errorf("calls = %q", got)
+	}
+}
+
+func TestRunBulkRefusesPartialMatch(t *testing.T) {
+	_, calls := stubBulk(t)
+	bulkQueryFn = func([]beadSearchDB, beads.SearchOptions) beadSearchResult {
+		return beadSearchResult{Results: []beadSearchHit{{Rig: "gastown", Issue: &beads.Issue{ID: "gt-1"}}}, Errors: map[string]string{"town": "dolt down"}}
+	}
+	if err := runBulk(bulkCloseCmd, []string{"flaky"}); err == nil || !strings.Contains(err.Error(), "partial") {
+		t.Errorf("err = %v", err)
+	}
+	if len(*calls) != 0 {
+		t.Errorf("acted on a partial match: %v", *calls)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"time"
 
 	"github.com/gofrs/flock"
 	"github.com/steveyegge/gastown/internal/beads"
@@ -62,19 +63,24 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 		schedulerCfg = capacity.DefaultSchedulerConfig()
 	}
 
-	// Nothing to dispatch when scheduler is in direct dispatch or disabled mode.
+	// In direct dispatch or disabled mode, only beads queued for a saturated
+	// rig (gt sling --on-full=queue) wait here, under the town safety cap.
 	maxPolecats := schedulerCfg.GetMaxPolecats()
+	rigQueueOnly := false
 	if maxPolecats <= 0 {
-		if !dryRun && !isDaemonDispatch() {
-			staleBeads, _ := getReadySlingContexts(townRoot)
-			if len(staleBeads) > 0 {
-				fmt.Printf("%s %d context bead(s) still open from a previous deferred mode\n",
-					style.Warning.Render("⚠"), len(staleBeads))
-				fmt.Printf("  Use: gt scheduler clear  (close all sling context beads)\n")
-				fmt.Printf("  Or:  gt config set scheduler.max_polecats N  (re-enable deferred dispatch)\n")
-			}
+		readyBeads, _ := getReadySlingContexts(townRoot)
+		rigQueued := onlyRigQueued(readyBeads)
+		if stale := len(readyBeads) - len(rigQueued); stale > 0 && !dryRun && !isDaemonDispatch() {
+			fmt.Printf("%s %d context bead(s) still open from a previous deferred mode\n",
+				style.Warning.Render("⚠"), stale)
+			fmt.Printf("  Use: gt scheduler clear  (close all sling context beads)\n")
+			fmt.Printf("  Or:  gt config set scheduler.max_polecats N  (re-enable deferred dispatch)\n")
 		}
-		return 0, nil
+		if len(rigQueued) == 0 {
+			return 0, nil
+		}
+		maxPolecats = defaultMaxActivePolecats
+		rigQueueOnly = true
 	}
 
 	// Determine limits
@@ -106,7 +112,16 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 			return cap, nil
 		},
 		QueryPending: func() ([]capacity.PendingBead, error) {
-			return getReadySlingContexts(townRoot)
+			pending, err := getReadySlingContexts(townRoot)
+			if err != nil {
+				return nil, err
+			}
+			now := time.Now()
+			pending = skipFrozenRigs(townRoot, skipQuietRigs(townRoot, pending, now), now)
+			if rigQueueOnly {
+				pending = onlyRigQueued(pending)
+			}
+			return capacity.LimitToRigCapacity(pending, rigFreeSlots(townRoot, pending)), nil
 		},
 		Execute: func(b capacity.PendingBead) error {
 			result, err := dispatchSingleBead(b, townRoot, actor)
@@ -198,6 +213,38 @@ func dispatchScheduledWork(townRoot, actor string, batchOverride int, dryRun boo
 	return report.Dispatched, nil
 }
 
+// onlyRigQueued returns the beads queued for a slot in a saturated rig.
+func onlyRigQueued(pending []capacity.PendingBead) []capacity.PendingBead {
+	var out []capacity.PendingBead
+	for _, b := range pending {
+		if b.Context != nil && b.Context.RigQueued {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// rigFreeSlots returns the free polecat slots of each pending bead's rig
+// that has a per-rig cap (capacity.max_polecats in its settings).
+func rigFreeSlots(townRoot string, pending []capacity.PendingBead) map[string]int {
+	free := make(map[string]int)
+	var active map[string]int
+	for _, b := range pending {
+		if _, seen := free[b.TargetRig]; seen {
+			continue
+		}
+		cfg := rigCapacityConfigFn(townRoot, b.TargetRig)
+		if cfg == nil || cfg.MaxPolecats == 0 {
+			continue
+		}
+		if active == nil {
+			active = rigActivePolecatsFn()
+		}
+		free[b.TargetRig] = capacity.RigLoad{Rig: b.TargetRig, Active: active[b.TargetRig], Config: cfg}.Free()
+	}
+	return free
+}
+
 // printDryRunPlan displays a dry-run dispatch plan.
 func printDryRunPlan(plan capacity.DispatchPlan, maxPolecats, batchSize int) {
 	if plan.Reason == "none" {
@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 var catJSON bool
@@ -52,7 +54,7 @@ func runCat(cmd *cobra.Command, args []string) error {
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
 
-	return bdCmd.Run()
+	return beads.RunCmd(bdCmd)
 }
 
 // isBeadID checks if a string looks like a bead ID.
@@ -0,0 +1,357 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/changelog"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/release"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	changelogKind   string
+	changelogBeadID string
+	changelogBase   string
+	changelogDate   string
+	changelogDryRun bool
+	changelogJSON   bool
+)
+
+var changelogCmd = &cobra.Command{
+	Use:     "changelog",
+	GroupID: GroupWork,
+	Short:   "Write changelog fragments and assemble them at release time",
+	Long: `Manage changelog fragments: one short entry per bead, committed with the
+bead's work as <dir>/<bead>.md and assembled into CHANGELOG.md when the rig
+releases.
+
+When the rig requires fragments, gt done refuses to submit a branch that
+changes user-facing files without one for its bead. Configure it in the
+rig's settings/config.json:
+
+  "changelog": {
+    "require": true,
+    "dir": ".changelog",
+    "file": "CHANGELOG.md",
+    "ignore": ["*_test.go", "testdata/", "docs/internal/"],
+    "skip_labels": ["no-changelog"]
+  }
+
+Ignored paths and beads with a skip label never need a fragment. The
+default ignore list covers tests, testdata, .github and .beads.
+
+Examples:
+  gt changelog add --kind fix "Handle an empty merge queue without panicking"
+  gt changelog lint
+  gt changelog assemble 1.4.0`,
+	RunE: requireSubcommand,
+}
+
+var changelogAddCmd = &cobra.Command{
+	Use:   "add <text>...",
+	Short: "Write the changelog fragment for your bead",
+	Long: `Write the changelog fragment for the bead you are working on (or --bead),
+replacing any earlier one. Commit it with your work.
+
+Write for users of the project, not for reviewers: what changed for them,
+in one or two sentences. Kinds: breaking, feature, fix, other.
+
+Examples:
+  gt changelog add --kind feature "gt mail approve sends the decision back to the requester"
+  gt changelog add --kind fix --bead gt-abc12 "Handle an empty merge queue"`,
+	Args:        cobra.MinimumNArgs(1),
+	Annotations: map[string]string{AnnotationPolecatSafe: "true"},
+	RunE:        runChangelogAdd,
+}
+
+var changelogListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List the fragments waiting for the next release",
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{AnnotationPolecatSafe: "true"},
+	RunE:        runChangelogList,
+}
+
+var changelogLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check that your branch has the fragment it needs",
+	Long: `Run the check gt done runs: if the branch changes user-facing files, the
+bead's fragment must be committed. Exits 1 when it is missing.
+
+Examples:
+  gt changelog lint
+  gt changelog lint --bead gt-abc12 --base origin/integration/auth`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{AnnotationPolecatSafe: "true"},
+	RunE:        runChangelogLint,
+}
+
+var changelogAssembleCmd = &cobra.Command{
+	Use:   "assemble <version>",
+	Short: "Assemble the fragments into the changelog",
+	Long: `Add a section for version to the top of the changelog, with the pending
+fragments grouped into breaking changes, added, fixed and changed, and
+delete the fragments. Commit the result. The release formula runs this in
+its changelog step.
+
+Examples:
+  gt changelog assemble 1.4.0 --dry-run
+  gt changelog assemble 1.4.0 --date 2026-03-01`,
+	Args: cobra.ExactArgs(1),
+	RunE: runChangelogAssemble,
+}
+
+// changelogBeadLabelsFn is a seam for tests. Production reads the labels
+// with bd show.
+var changelogBeadLabelsFn = func(dir, bead string) ([]string, error) {
+	issue, err := beads.New(dir).Show(bead)
+	if err != nil {
+		return nil, err
+	}
+	return issue.Labels, nil
+}
+
+func init() {
+	changelogAddCmd.Flags().StringVar(&changelogKind, "kind", "other", "Kind of change: breaking, feature, fix, other")
+	changelogAddCmd.Flags().StringVar(&changelogBeadID, "bead", "", "Bead the fragment is for (default: your bead)")
+	changelogListCmd.Flags().BoolVar(&changelogJSON, "json", false, "Output as JSON")
+	changelogLintCmd.Flags().StringVar(&changelogBeadID, "bead", "", "Bead to check for (default: your bead)")
+	changelogLintCmd.Flags().StringVar(&changelogBase, "base", "", "Diff base (default: origin/<rig default branch>)")
+	changelogAssembleCmd.Flags().StringVar(&changelogDate, "date", "", "Release date, YYYY-MM-DD (default: today)")
+	changelogAssembleCmd.Flags().BoolVarP(&changelogDryRun, "dry-run", "n", false, "Print the section without writing anything")
+
+	changelogCmd.AddCommand(changelogAddCmd)
+	changelogCmd.AddCommand(changelogListCmd)
+	changelogCmd.AddCommand(changelogLintCmd)
+	changelogCmd.AddCommand(changelogAssembleCmd)
+	rootCmd.AddCommand(changelogCmd)
+}
+
+// loadChangelogConfig returns a rig's changelog settings, or nil if none
+// are configured.
+func loadChangelogConfig(rigPath string) (*changelog.Config, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return settings.Changelog, nil
+}
+
+// changelogContext returns the checkout the command runs in, the current
+// rig's path ("" outside a rig) and its changelog config.
+func changelogContext() (root, rigPath string, cfg *changelog.Config, err error) {
+	root, err = detectCloneRoot()
+	if err != nil {
+		return "", "", nil, err
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return root, "", nil, nil
+	}
+	if _, r, err := findCurrentRig(townRoot); err == nil {
+		rigPath = r.Path
+		if cfg, err = loadChangelogConfig(rigPath); err != nil {
+			return "", "", nil, err
+		}
+	}
+	return root, rigPath, cfg, nil
+}
+
+// changelogBead returns --bead, or the bead the caller is working on.
+func changelogBead() (string, error) {
+	if changelogBeadID != "" {
+		return changelogBeadID, nil
+	}
+	if bead := stampBead(detectSender()); bead != "" {
+		return bead, nil
+	}
+	return "", fmt.Errorf("no bead on your hook (use --bead)")
+}
+
+func runChangelogAdd(cmd *cobra.Command, args []string) error {
+	root, _, cfg, err := changelogContext()
+	if err != nil {
+		return err
+	}
+	bead, err := changelogBead()
+	if err != nil {
+		return err
+	}
+	kind, err := release.ParseKind(changelogKind)
+	if err != nil {
+		return err
+	}
+	f := &changelog.Fragment{Bead: bead, Kind: kind, Text: strings.Join(args, " ")}
+	if err := cfg.Write(root, f); err != nil {
+		return err
+	}
+	fmt.Printf("%s Wrote %s (%s)\n", style.SuccessPrefix, cfg.Path(bead), kind)
+	fmt.Printf("  Commit it with your work: git add %s\n", cfg.Path(bead))
+	return nil
+}
+
+func runChangelogList(cmd *cobra.Command, args []string) error {
+	root, _, cfg, err := changelogContext()
+	if err != nil {
+		return err
+	}
+	fragments, err := cfg.Fragments(root)
+	if err != nil {
+		return err
+	}
+	if changelogJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if fragments == nil {
+			fragments = []*changelog.Fragment{}
+		}
+		return enc.Encode(fragments)
+	}
+	if len(fragments) == 0 {
+		fmt.Printf("No fragments in %s\n", cfg.GetDir())
+		return nil
+	}
+	for _, f := range fragments {
+		fmt.Printf("  %-12s %-9s %s\n", f.Bead, style.Dim.Render(f.Kind.String()), firstLine(f.Text))
+	}
+	return nil
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+func runChangelogLint(cmd *cobra.Command, args []string) error {
+	root, rigPath, cfg, err := changelogContext()
+	if err != nil {
+		return err
+	}
+	bead, err := changelogBead()
+	if err != nil {
+		return err
+	}
+	base := changelogBase
+	if base == "" {
+		base = rigOriginBase(rigPath)
+	}
+	l, err := cfg.Check(root, base, bead)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(l.UserFacing) == 0:
+		fmt.Printf("%s No user-facing changes: no fragment needed\n", style.SuccessPrefix)
+	case l.Committed:
+		fmt.Printf("%s %s is committed\n", style.SuccessPrefix, l.Fragment)
+	default:
+		fmt.Print(changelogPrompt(bead, l))
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// changelogPrompt tells the agent which changes need a fragment and how to
+// write one.
+func changelogPrompt(bead string, l *changelog.Lint) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s changes %d user-facing file(s) but has no changelog fragment:\n", style.ErrorPrefix, bead, len(l.UserFacing))
+	for i, f := range l.UserFacing {
+		if i == 5 {
+			fmt.Fprintf(&b, "    ... and %d more\n", len(l.UserFacing)-i)
+			break
+		}
+		fmt.Fprintf(&b, "    %s\n", f)
+	}
+	if l.Exists {
+		fmt.Fprintf(&b, "\n  %s exists but isn't committed:\n    git add %s && git commit -m \"docs: changelog fragment\"\n", l.Fragment, l.Fragment)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\n  Describe the change for users of the project, then commit it:\n")
+	fmt.Fprintf(&b, "    gt changelog add --kind <breaking|feature|fix|other> \"<what changed>\"\n")
+	fmt.Fprintf(&b, "    git add %s && git commit -m \"docs: changelog fragment\"\n", l.Fragment)
+	return b.String()
+}
+
+// checkChangelogForDone refuses to submit a branch that changes user-facing
+// files without the bead's changelog fragment, when the rig requires them.
+func checkChangelogForDone(rigPath, dir, issueID, base string) error {
+	cfg, err := loadChangelogConfig(rigPath)
+	if err != nil {
+		style.PrintWarning("changelog check skipped: %v", err)
+		return nil
+	}
+	if !cfg.IsRequired() || issueID == "" {
+		return nil
+	}
+	if labels, err := changelogBeadLabelsFn(dir, issueID); err == nil && cfg.Skips(labels) {
+		return nil
+	}
+	l, err := cfg.Check(dir, base, issueID)
+	if err != nil {
+		style.PrintWarning("changelog check skipped: %v", err)
+		return nil
+	}
+	if !l.Missing() {
+		if len(l.UserFacing) > 0 {
+			fmt.Printf("%s Changelog fragment %s present\n", style.Bold.Render("✓"), l.Fragment)
+		}
+		return nil
+	}
+	fmt.Println()
+	fmt.Print(changelogPrompt(issueID, l))
+	fmt.Println()
+	return fmt.Errorf("cannot complete: user-facing changes need a changelog fragment")
+}
+
+func runChangelogAssemble(cmd *cobra.Command, args []string) error {
+	root, _, cfg, err := changelogContext()
+	if err != nil {
+		return err
+	}
+	version := args[0]
+	date := time.Now()
+	if changelogDate != "" {
+		if date, err = time.Parse("2006-01-02", changelogDate); err != nil {
+			return fmt.Errorf("invalid --date: %w", err)
+		}
+	}
+	fragments, err := cfg.Fragments(root)
+	if err != nil {
+		return err
+	}
+	if len(fragments) == 0 {
+		return fmt.Errorf("no fragments in %s", cfg.GetDir())
+	}
+	section := changelog.Render(version, date, fragments)
+	if changelogDryRun {
+		fmt.Print(section)
+		return nil
+	}
+
+	file := filepath.Join(root, filepath.FromSlash(cfg.GetFile()))
+	if err := changelog.Prepend(file, section); err != nil {
+		return fmt.Errorf("updating %s: %w", cfg.GetFile(), err)
+	}
+	for _, f := range fragments {
+		if err := os.Remove(filepath.Join(root, filepath.FromSlash(cfg.Path(f.Bead)))); err != nil {
+			return fmt.Errorf("removing fragment %s: %w", f.Bead, err)
+		}
+	}
+	fmt.Printf("%s Added %s to %s from %d fragment(s)\n", style.SuccessPrefix, version, cfg.GetFile(), len(fragments))
+	fmt.Printf("  Commit it: git add -A %s %s\n", cfg.GetFile(), cfg.GetDir())
+	return nil
+}
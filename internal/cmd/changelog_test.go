@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/changelog"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/release"
+)
+
+func TestCheckChangelogForDone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	origLabels := changelogBeadLabelsFn
+	t.Cleanup(func() { changelogBeadLabelsFn = origLabels })
+	var labels []string
+	changelogBeadLabelsFn = func(dir, bead string) ([]string, error) { return labels, nil }
+
+	rigPath := t.TempDir()
+	settings := config.NewRigSettings()
+	settings.Changelog = &changelog.Config{Require: true, SkipLabels: []string{"no-changelog"}}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q", "--initial-branch=main")
+	git("config", "user.email", "test@example.com")
+	git("config", "user.name", "Test")
+	git("commit", "-q", "--allow-empty", "-m", "base")
+	git("checkout", "-q", "-b", "polecat/nux")
+	if err := os.WriteFile(filepath.Join(dir, "queue.go"), []byte("package queue\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "-A")
+	git("commit", "-q", "-m", "fix: empty queue")
+
+	if err := checkChangelogForDone(t.TempDir(), dir, "gt-abc12", "main"); err != nil {
+		t.Errorf("rig without changelog settings: %v", err)
+	}
+	if err := checkChangelogForDone(rigPath, dir, "gt-abc12", "main"); err == nil {
+		t.Error("user-facing change without a fragment should bounce")
+	}
+
+	labels = []string{"no-changelog"}
+	if err := checkChangelogForDone(rigPath, dir, "gt-abc12", "main"); err != nil {
+		t.Errorf("skip label: %v", err)
+	}
+	labels = nil
+
+	if err := settings.Changelog.Write(dir, &changelog.Fragment{Bead: "gt-abc12", Kind: release.KindFix, Text: "Empty queue."}); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "-A")
+	git("commit", "-q", "-m", "docs: changelog fragment")
+	if err := checkChangelogForDone(rigPath, dir, "gt-abc12", "main"); err != nil {
+		t.Errorf("committed fragment: %v", err)
+	}
+}
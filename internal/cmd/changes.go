@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/changes"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	changesRig   string
+	changesSince string
+	changesJSON  bool
+)
+
+var changesCmd = &cobra.Command{
+	Use:     "changes [bead...]",
+	GroupID: GroupWork,
+	Short:   "Show what merged beads changed (files, packages, dependencies, API)",
+	Long: `Show the change summary the refinery records when it merges a bead.
+
+Each summary lists the changed files with line counts, the directories
+(Go packages) they are in, dependencies added or re-versioned in go.mod,
+package.json, requirements.txt or Cargo.toml, and the exported API added,
+changed or removed in each Go package. The merged bead also gets a comment
+with the summary's headline.
+
+Without beads, lists the recorded summaries, newest last: feed --json into
+release notes or a compliance report.
+
+Examples:
+  gt changes gt-abc12
+  gt changes gt-abc12 gt-def34 --json
+  gt changes --rig gastown --since 7d      # Everything merged this week
+  gt changes --since 30d --json > changes.json`,
+	RunE: runChanges,
+}
+
+func init() {
+	changesCmd.Flags().StringVar(&changesRig, "rig", "", "Only this rig's merges")
+	changesCmd.Flags().StringVar(&changesSince, "since", "", "Only merges within this long (e.g. 24h, 7d)")
+	changesCmd.Flags().BoolVar(&changesJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(changesCmd)
+}
+
+func runChanges(cmd *cobra.Command, args []string) error {
+	rigs, err := changesRigs()
+	if err != nil {
+		return err
+	}
+
+	var summaries []*changes.Summary
+	if len(args) > 0 {
+		for _, bead := range args {
+			s, err := findChangeSummary(rigs, bead)
+			if err != nil {
+				return err
+			}
+			summaries = append(summaries, s)
+		}
+	} else {
+		var since time.Time
+		if changesSince != "" {
+			d, err := parseDuration(changesSince)
+			if err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+			since = time.Now().Add(-d)
+		}
+		for _, r := range rigs {
+			list, err := changes.List(r.Path)
+			if err != nil {
+				return fmt.Errorf("reading change summaries of %s: %w", r.Name, err)
+			}
+			for _, s := range list {
+				if !s.MergedAt.Before(since) {
+					summaries = append(summaries, s)
+				}
+			}
+		}
+		sort.Slice(summaries, func(i, j int) bool { return summaries[i].MergedAt.Before(summaries[j].MergedAt) })
+	}
+
+	if changesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if summaries == nil {
+			summaries = []*changes.Summary{}
+		}
+		return enc.Encode(summaries)
+	}
+
+	if len(args) == 0 {
+		if len(summaries) == 0 {
+			fmt.Println("No change summaries recorded")
+			return nil
+		}
+		for _, s := range summaries {
+			fmt.Printf("  %s  %-12s %s  %s\n", s.MergedAt.Local().Format("2006-01-02 15:04"), s.Bead,
+				style.Dim.Render(s.Rig), s.Headline())
+		}
+		return nil
+	}
+	for i, s := range summaries {
+		if i > 0 {
+			fmt.Println()
+		}
+		printChangeSummary(s)
+	}
+	return nil
+}
+
+// changesRigs returns the rigs to look in: --rig, or all of them.
+func changesRigs() ([]*rig.Rig, error) {
+	if changesRig != "" {
+		_, r, err := getRig(changesRig)
+		if err != nil {
+			return nil, err
+		}
+		return []*rig.Rig{r}, nil
+	}
+	return getAllRigs()
+}
+
+func findChangeSummary(rigs []*rig.Rig, bead string) (*changes.Summary, error) {
+	for _, r := range rigs {
+		s, err := changes.Load(r.Path, bead)
+		if err != nil {
+			return nil, err
+		}
+		if s != nil {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("no change summary for %s (summaries are recorded when the refinery merges a bead)", bead)
+}
+
+func printChangeSummary(s *changes.Summary) {
+	fmt.Printf("%s %s  %s\n", style.Bold.Render("📦"), style.Bold.Render(s.Bead), s.Headline())
+	commit := s.MergeCommit
+	if len(commit) > 8 {
+		commit = commit[:8]
+	}
+	fmt.Printf("   %s\n\n", style.Dim.Render(fmt.Sprintf("%s %s → %s %s, merged %s",
+		s.Rig, s.Branch, s.Target, commit, s.MergedAt.Local().Format("2006-01-02 15:04"))))
+
+	fmt.Println("  Files:")
+	for _, f := range s.Files {
+		fmt.Printf("    %s %-50s +%d -%d\n", f.Status, f.Path, f.Added, f.Deleted)
+	}
+	fmt.Printf("\n  Packages: %s\n", strings.Join(s.Packages, ", "))
+
+	if len(s.Dependencies) > 0 {
+		fmt.Println("\n  Dependencies:")
+		for _, d := range s.Dependencies {
+			if d.Previous == "" {
+				fmt.Printf("    + %s (%s)\n", d, d.Manifest)
+			} else {
+				fmt.Printf("    ~ %s (was %s, %s)\n", d, d.Previous, d.Manifest)
+			}
+		}
+	}
+
+	for _, api := range s.API {
+		fmt.Printf("\n  API of %s:\n", api.Package)
+		for _, name := range api.Added {
+			fmt.Printf("    + %s\n", name)
+		}
+		for _, name := range api.Changed {
+			fmt.Printf("    ~ %s\n", name)
+		}
+		for _, name := range api.Removed {
+			fmt.Printf("    - %s\n", name)
+		}
+	}
+}
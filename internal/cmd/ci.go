@@ -0,0 +1,448 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cifix"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	ciRig    string
+	ciDryRun bool
+	ciJSON   bool
+)
+
+var ciCmd = &cobra.Command{
+	Use:     "ci",
+	GroupID: GroupWork,
+	Short:   "Sling CI failures back to the polecat that pushed them",
+	Long: `Watch CI on polecat branches and remediate failures automatically.
+
+For each open merge request, gt ci check reads the branch's GitHub Actions
+runs (via the gh CLI). When the latest commit has failing runs, a follow-up
+bead is created with the failing job logs attached and slung to the same
+polecat. If the polecat is gone, the follow-up goes to the rig instead.
+
+Each failing commit is handled once. After max_attempts follow-ups on one
+branch, gt escalates to the overseer instead of slinging again; clear the
+count with gt ci reset once a human has looked.
+
+Enable the daemon's ci_remediation patrol to run gt ci check periodically.
+Limits live under "ci_remediation" in settings/config.json:
+  "ci_remediation": {"max_attempts": 2, "log_lines": 200, "rigs": ["gastown"]}
+
+Examples:
+  gt ci check                     # Scan open MRs in all rigs
+  gt ci check --rig gastown --dry-run
+  gt ci remediate gastown polecat/Toast/gt-abc@mk1
+  gt ci status
+  gt ci reset polecat/Toast/gt-abc@mk1`,
+	RunE: requireSubcommand,
+}
+
+var ciCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check CI for open merge requests and sling follow-ups",
+	Args:  cobra.NoArgs,
+	RunE:  runCICheck,
+}
+
+var ciRemediateCmd = &cobra.Command{
+	Use:   "remediate <rig> <branch>",
+	Short: "Check CI for one branch and sling a follow-up if it failed",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runCIRemediate,
+}
+
+var ciStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show remediation attempts per branch",
+	Args:  cobra.NoArgs,
+	RunE:  runCIStatus,
+}
+
+var ciResetCmd = &cobra.Command{
+	Use:   "reset <branch>",
+	Short: "Clear the attempt count for a branch",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runCIReset,
+}
+
+func init() {
+	ciCheckCmd.Flags().StringVar(&ciRig, "rig", "", "Only check this rig")
+	ciCheckCmd.Flags().BoolVarP(&ciDryRun, "dry-run", "n", false, "Show what would be slung without creating beads")
+	ciRemediateCmd.Flags().BoolVarP(&ciDryRun, "dry-run", "n", false, "Show what would be slung without creating beads")
+	ciStatusCmd.Flags().BoolVar(&ciJSON, "json", false, "Output as JSON")
+
+	ciCmd.AddCommand(ciCheckCmd, ciRemediateCmd, ciStatusCmd, ciResetCmd)
+	rootCmd.AddCommand(ciCmd)
+}
+
+// ciTarget is a branch whose CI is checked, usually taken from an open MR.
+type ciTarget struct {
+	Rig         *rig.Rig
+	Branch      string
+	Worker      string
+	SourceIssue string
+	MergeReq    string
+}
+
+// CI check outcomes.
+const (
+	ciOutcomeGreen     = "green"     // No failing runs on the latest commit
+	ciOutcomeHandled   = "handled"   // Failing commit already acted on
+	ciOutcomeSlung     = "slung"     // Follow-up created and slung
+	ciOutcomeExhausted = "exhausted" // Attempt cap reached; escalated
+)
+
+func runCICheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg := loadCIRemediationConfig(townRoot)
+
+	var rigs []*rig.Rig
+	if ciRig != "" {
+		_, r, err := getRig(ciRig)
+		if err != nil {
+			return err
+		}
+		rigs = []*rig.Rig{r}
+	} else {
+		rigs, err = getAllRigs()
+		if err != nil {
+			return err
+		}
+	}
+
+	state, err := cifix.LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+
+	checked, slung := 0, 0
+	for _, r := range rigs {
+		if !cfg.CoversRig(r.Name) {
+			continue
+		}
+		targets, err := ciTargetsForRig(r)
+		if err != nil {
+			style.PrintWarning("%s: listing merge requests: %v", r.Name, err)
+			continue
+		}
+		for _, t := range targets {
+			checked++
+			outcome, err := remediateCI(townRoot, t, cfg, state, ciDryRun)
+			if err != nil {
+				style.PrintWarning("%s %s: %v", r.Name, t.Branch, err)
+				continue
+			}
+			if outcome == ciOutcomeSlung {
+				slung++
+			}
+		}
+	}
+
+	if !ciDryRun {
+		if err := state.Save(); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("%s Checked %d branch(es), %d follow-up(s) slung\n", style.Success.Render("✓"), checked, slung)
+	return nil
+}
+
+func runCIRemediate(cmd *cobra.Command, args []string) error {
+	rigName, branch := args[0], args[1]
+	if strings.HasPrefix(branch, "-") {
+		return fmt.Errorf("invalid branch name %q", branch)
+	}
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	info := parseBranchName(branch)
+	t := ciTarget{Rig: r, Branch: branch, Worker: info.Worker, SourceIssue: info.Issue}
+	if mr, err := beads.New(r.BeadsPath()).FindMRForBranch(branch); err == nil && mr != nil {
+		t = ciTargetFromMR(r, mr)
+	}
+
+	state, err := cifix.LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+	outcome, err := remediateCI(townRoot, t, loadCIRemediationConfig(townRoot), state, ciDryRun)
+	if err != nil {
+		return err
+	}
+	switch outcome {
+	case ciOutcomeGreen:
+		fmt.Printf("%s No failing CI runs on %s\n", style.Success.Render("✓"), branch)
+	case ciOutcomeHandled:
+		fmt.Printf("%s Latest failure on %s was already remediated\n", style.Dim.Render("○"), branch)
+	}
+	if ciDryRun {
+		return nil
+	}
+	return state.Save()
+}
+
+func runCIStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := cifix.LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+	if ciJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(state.Branches)
+	}
+	if len(state.Branches) == 0 {
+		fmt.Println("No CI remediation recorded.")
+		return nil
+	}
+
+	maxAttempts := loadCIRemediationConfig(townRoot).GetMaxAttempts()
+	branches := make([]string, 0, len(state.Branches))
+	for b := range state.Branches {
+		branches = append(branches, b)
+	}
+	sort.Strings(branches)
+	for _, b := range branches {
+		bs := state.Branches[b]
+		attempts := fmt.Sprintf("%d/%d", bs.Attempts, maxAttempts)
+		if bs.Exhausted {
+			attempts = style.Warning.Render(attempts + " exhausted")
+		}
+		fmt.Printf("%s  %s  %s  %s\n", style.Bold.Render(b), attempts,
+			strings.Join(bs.FollowUps, ", "), style.Dim.Render(bs.UpdatedAt.Local().Format("Jan 2 15:04")))
+	}
+	return nil
+}
+
+func runCIReset(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := cifix.LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+	bs, ok := state.Branches[args[0]]
+	if !ok {
+		return fmt.Errorf("no CI remediation recorded for %s", args[0])
+	}
+	// Keep handled SHAs so a reset doesn't re-sling failures already seen.
+	bs.Attempts = 0
+	bs.Exhausted = false
+	bs.UpdatedAt = time.Now()
+	if err := state.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("%s Reset CI remediation for %s\n", style.Success.Render("✓"), args[0])
+	return nil
+}
+
+// loadCIRemediationConfig returns the town's remediation settings (nil = defaults).
+func loadCIRemediationConfig(townRoot string) *cifix.Config {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	return settings.CIRemediation
+}
+
+// ciTargetsForRig returns the branches of the rig's open merge requests.
+func ciTargetsForRig(r *rig.Rig) ([]ciTarget, error) {
+	issues, err := beads.New(r.BeadsPath()).ListMergeRequests(beads.ListOptions{
+		Label:    "gt:merge-request",
+		Status:   "open",
+		Priority: -1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var targets []ciTarget
+	for _, issue := range issues {
+		t := ciTargetFromMR(r, issue)
+		if t.Branch == "" {
+			continue
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// ciTargetFromMR builds a target from an MR bead, falling back to the branch
+// name for the worker and source issue.
+func ciTargetFromMR(r *rig.Rig, mr *beads.Issue) ciTarget {
+	t := ciTarget{Rig: r, MergeReq: mr.ID}
+	if fields := beads.ParseMRFields(mr); fields != nil {
+		t.Branch, t.Worker, t.SourceIssue = fields.Branch, fields.Worker, fields.SourceIssue
+	}
+	if t.Branch == "" {
+		return t
+	}
+	info := parseBranchName(t.Branch)
+	if t.Worker == "" {
+		t.Worker = info.Worker
+	}
+	if t.SourceIssue == "" {
+		t.SourceIssue = info.Issue
+	}
+	return t
+}
+
+// ciRepoDir returns a clone of the rig's repo for gh to run in.
+func ciRepoDir(r *rig.Rig) string {
+	for _, dir := range []string{
+		filepath.Join(r.Path, "refinery", "rig"),
+		filepath.Join(r.Path, "mayor", "rig"),
+	} {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+	}
+	return r.Path
+}
+
+// remediateCI checks one branch and, if its latest commit failed CI, slings a
+// follow-up with the failing logs. The state is updated but not saved.
+func remediateCI(townRoot string, t ciTarget, cfg *cifix.Config, state *cifix.State, dryRun bool) (string, error) {
+	repoDir := ciRepoDir(t.Rig)
+	runs, err := cifix.ListRuns(repoDir, t.Branch)
+	if err != nil {
+		return "", err
+	}
+	sha := cifix.LatestSHA(runs)
+	failures := cifix.Failures(runs, sha)
+	if len(failures) == 0 {
+		return ciOutcomeGreen, nil
+	}
+
+	bs := state.Branch(t.Branch)
+	if bs.IsHandled(sha) {
+		return ciOutcomeHandled, nil
+	}
+
+	maxAttempts := cfg.GetMaxAttempts()
+	if bs.Attempts >= maxAttempts {
+		if dryRun {
+			fmt.Printf("%s %s: CI still failing after %d attempt(s); would escalate\n", style.Dim.Render("[dry-run]"), t.Branch, bs.Attempts)
+			return ciOutcomeExhausted, nil
+		}
+		bs.Handled = append(bs.Handled, sha)
+		bs.UpdatedAt = time.Now()
+		if !bs.Exhausted {
+			bs.Exhausted = true
+			escalateCIExhausted(townRoot, t, bs.Attempts, failures)
+		}
+		return ciOutcomeExhausted, nil
+	}
+
+	followUp := cifix.FollowUp{
+		Branch:      t.Branch,
+		SHA:         sha,
+		SourceIssue: t.SourceIssue,
+		MergeReq:    t.MergeReq,
+		Attempt:     bs.Attempts + 1,
+		MaxAttempts: maxAttempts,
+	}
+	for _, run := range failures {
+		fl := cifix.FailureLog{Run: run}
+		if !dryRun {
+			logs, err := cifix.FailedLog(repoDir, run.ID, cfg.GetLogLines())
+			if err != nil {
+				style.PrintWarning("fetching logs for %s run %d: %v", run.Workflow, run.ID, err)
+			}
+			fl.Log = logs
+		}
+		followUp.Failures = append(followUp.Failures, fl)
+	}
+
+	slingTarget := ciSlingTarget(t)
+	if dryRun {
+		fmt.Printf("%s %s → %q (sling to %s)\n", style.Dim.Render("[dry-run]"), t.Branch, followUp.Title(), slingTarget)
+		return ciOutcomeSlung, nil
+	}
+
+	issue, err := beads.New(t.Rig.BeadsPath()).Create(beads.CreateOptions{
+		Title:       followUp.Title(),
+		Labels:      []string{"gt:task", "ci-fix"},
+		Priority:    -1,
+		Description: followUp.Description(),
+		Actor:       "ci",
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating follow-up: %w", err)
+	}
+	// Record before slinging: the bead exists, so never create it twice.
+	bs.RecordFollowUp(sha, issue.ID, time.Now())
+	fmt.Printf("%s %s → %s %s\n", style.Success.Render("✓"), t.Branch, style.Bold.Render(issue.ID), followUp.Title())
+
+	slingCmd := exec.Command("gt", "sling", issue.ID, slingTarget)
+	slingCmd.Dir = townRoot
+	slingCmd.Stdout = os.Stdout
+	slingCmd.Stderr = os.Stderr
+	if err := slingCmd.Run(); err != nil {
+		style.PrintWarning("created %s but sling to %s failed: %v (retry: gt sling %s %s)",
+			issue.ID, slingTarget, err, issue.ID, slingTarget)
+	}
+	return ciOutcomeSlung, nil
+}
+
+// ciSlingTarget returns <rig>/<polecat> when the polecat that pushed the
+// branch still exists, otherwise the rig.
+func ciSlingTarget(t ciTarget) string {
+	if t.Worker == "" {
+		return t.Rig.Name
+	}
+	mgr, _, err := getPolecatManager(t.Rig.Name)
+	if err != nil {
+		return t.Rig.Name
+	}
+	if _, err := mgr.Get(t.Worker); err != nil {
+		return t.Rig.Name
+	}
+	return t.Rig.Name + "/" + t.Worker
+}
+
+// escalateCIExhausted hands a branch that keeps failing to the overseer.
+func escalateCIExhausted(townRoot string, t ciTarget, attempts int, failures []cifix.Run) {
+	names := make([]string, 0, len(failures))
+	for _, f := range failures {
+		names = append(names, f.Workflow)
+	}
+	msg := fmt.Sprintf("CI still failing on %s (%s) after %d follow-up(s)", t.Branch, strings.Join(names, ", "), attempts)
+	args := []string{"escalate", "-s", "high", "--source", "ci:" + t.Rig.Name, msg}
+	if t.SourceIssue != "" {
+		args = append(args, "--related", t.SourceIssue)
+	}
+	escCmd := exec.Command("gt", args...)
+	escCmd.Dir = townRoot
+	if out, err := escCmd.CombinedOutput(); err != nil {
+		style.PrintWarning("%s; escalation failed: %v (%s)", msg, err, strings.TrimSpace(string(out)))
+		return
+	}
+	fmt.Printf("%s %s — escalated\n", style.Warning.Render("⚠"), msg)
+}
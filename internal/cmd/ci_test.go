@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cifix"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestCITargetFromMR(t *testing.T) {
+	r := &rig.Rig{Name: "gastown", Path: t.TempDir()}
+
+	mr := &beads.Issue{ID: "gt-mr1", Description: "branch: polecat/Toast/gt-abc@mk1\ntarget: main\nsource_issue: gt-abc\nworker: Toast\n"}
+	got := ciTargetFromMR(r, mr)
+	if got.Branch != "polecat/Toast/gt-abc@mk1" || got.Worker != "Toast" || got.SourceIssue != "gt-abc" || got.MergeReq != "gt-mr1" {
+		t.Errorf("ciTargetFromMR() = %+v", got)
+	}
+
+	// Worker and source issue fall back to the branch name.
+	mr = &beads.Issue{ID: "gt-mr2", Description: "branch: polecat/Nux/gt-def@mk2\n"}
+	got = ciTargetFromMR(r, mr)
+	if got.Worker != "Nux" || got.SourceIssue != "gt-def" {
+		t.Errorf("ciTargetFromMR() fallback = %+v", got)
+	}
+
+	if got := ciTargetFromMR(r, &beads.Issue{ID: "gt-mr3"}); got.Branch != "" {
+		t.Errorf("MR without fields should have no branch, got %+v", got)
+	}
+}
+
+func TestCIRepoDir(t *testing.T) {
+	r := &rig.Rig{Name: "gastown", Path: t.TempDir()}
+	if got := ciRepoDir(r); got != r.Path {
+		t.Errorf("ciRepoDir() with no clones = %q, want rig path", got)
+	}
+	mayorRig := filepath.Join(r.Path, "mayor", "rig")
+	if err := os.MkdirAll(mayorRig, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := ciRepoDir(r); got != mayorRig {
+		t.Errorf("ciRepoDir() = %q, want %q", got, mayorRig)
+	}
+	refineryRig := filepath.Join(r.Path, "refinery", "rig")
+	if err := os.MkdirAll(refineryRig, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got := ciRepoDir(r); got != refineryRig {
+		t.Errorf("ciRepoDir() = %q, want refinery clone %q", got, refineryRig)
+	}
+}
+
+func TestCIStatusAndReset(t *testing.T) {
+	townRoot := setupTestTownForTheme(t)
+	t.Chdir(townRoot)
+	t.Cleanup(func() { ciJSON = false })
+
+	settings := config.NewTownSettings()
+	settings.CIRemediation = &cifix.Config{MaxAttempts: 3}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := cifix.LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := state.Branch("polecat/Toast/gt-abc@mk1")
+	b.RecordFollowUp("aaa", "gt-fix1", time.Now())
+	b.RecordFollowUp("bbb", "gt-fix2", time.Now())
+	b.Exhausted = true
+	if err := state.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runCIStatus(ciStatusCmd, nil); err != nil {
+			t.Fatalf("runCIStatus() error = %v", err)
+		}
+	})
+	for _, want := range []string{"polecat/Toast/gt-abc@mk1", "2/3", "exhausted", "gt-fix1, gt-fix2"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("status output missing %q:\n%s", want, out)
+		}
+	}
+
+	if err := runCIReset(ciResetCmd, []string{"polecat/unknown"}); err == nil {
+		t.Error("expected error resetting unknown branch")
+	}
+	if err := runCIReset(ciResetCmd, []string{"polecat/Toast/gt-abc@mk1"}); err != nil {
+		t.Fatalf("runCIReset() error = %v", err)
+	}
+	state, err = cifix.LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = state.Branch("polecat/Toast/gt-abc@mk1")
+	if b.Attempts != 0 || b.Exhausted {
+		t.Errorf("after reset = %+v, want zero attempts", b)
+	}
+	if !b.IsHandled("bbb") {
+		t.Error("reset should keep handled SHAs")
+	}
+}
+
+func TestCIRemediateRejectsFlagLikeBranch(t *testing.T) {
+	if err := runCIRemediate(ciRemediateCmd, []string{"gastown", "--force"}); err == nil {
+		t.Error("expected error for branch starting with -")
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"strings"
 
 	beadsdk "github.com/steveyegge/beads"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/undo"
 	"github.com/steveyegge/gastown/internal/workspace"
 
 	"github.com/spf13/cobra"
@@ -35,9 +37,12 @@ Examples:
   gt close --reason "Done"     # Close with reason
   gt close --comment "Done"    # Same as --reason (alias)
   gt close --force             # Force close pinned beads
-  gt close gt-abc --cascade    # Close gt-abc and all its children`,
+  gt close gt-abc --cascade    # Close gt-abc and all its children
+
+Closing several beads, or a cascade, follows the town's confirmation
+policy for "close" (see gt confirm); --yes skips it if the policy allows.`,
 	DisableFlagParsing: true, // Pass all flags through to bd close
-	RunE:               runClose,
+	RunE:               journaled(runClose),
 }
 
 func init() {
@@ -52,6 +57,7 @@ func runClose(cmd *cobra.Command, args []string) error {
 
 	// Extract --cascade flag before passing to bd (gt-only flag)
 	cascade, filteredArgs := extractCascadeFlag(args)
+	yes, filteredArgs := extractYesFlag(filteredArgs)
 
 	// Convert --comment to --reason (alias support)
 	convertedArgs := make([]string, len(filteredArgs))
@@ -65,12 +71,27 @@ func runClose(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// A mass close (several beads, or a cascade) is recorded for gt undo,
+	// so note how the beads were before closing them.
+	var undoBeads []undo.ClosedBead
+	massClose := cascade || len(extractBeadIDs(filteredArgs)) > 1
+	if massClose {
+		ids := extractBeadIDs(filteredArgs)
+		if proceed, err := confirmDestructive("close", closeConfirmTarget(ids, cascade), yes); err != nil {
+			return err
+		} else if !proceed {
+			fmt.Println("Aborted.")
+			return nil
+		}
+		undoBeads = closePriorState(ids)
+	}
+
 	// If cascade, close children first (depth-first)
 	if cascade {
 		beadIDs := extractBeadIDs(filteredArgs)
 		visited := make(map[string]bool)
 		for _, id := range beadIDs {
-			if err := closeChildren(id, visited, 0); err != nil {
+			if err := closeChildren(id, visited, 0, &undoBeads); err != nil {
 				return fmt.Errorf("cascade close failed for children of %s: %w", id, err)
 			}
 		}
@@ -81,8 +102,7 @@ func runClose(cmd *cobra.Command, args []string) error {
 	bdCmd := exec.Command("bd", bdArgs...)
 	bdCmd.Stdin = os.Stdin
 	bdCmd.Stdout = os.Stdout
-	bdCmd.Stderr = os.Stderr
-	if err := bdCmd.Run(); err != nil {
+	if err := runTeeStderr(bdCmd); err != nil {
 		return err
 	}
 
@@ -94,9 +114,41 @@ func runClose(cmd *cobra.Command, args []string) error {
 		checkConvoyCompletion(beadIDs)
 	}
 
+	if massClose && len(undoBeads) > 0 {
+		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+			recordUndo(townRoot, undo.KindClose, closeSummary(undoBeads), undo.CloseState{Beads: undoBeads})
+		}
+	}
+
 	return nil
 }
 
+// extractYesFlag removes --yes from args and returns whether it was present.
+// It confirms a mass close and is not passed to bd.
+func extractYesFlag(args []string) (bool, []string) {
+	yes := false
+	var filtered []string
+	for _, arg := range args {
+		if arg == "--yes" {
+			yes = true
+		} else {
+			filtered = append(filtered, arg)
+		}
+	}
+	return yes, filtered
+}
+
+// closeConfirmTarget names what a mass close closes for its confirmation.
+func closeConfirmTarget(ids []string, cascade bool) string {
+	switch {
+	case cascade && len(ids) == 1:
+		return ids[0] + " and its children"
+	case cascade:
+		return fmt.Sprintf("%d beads and their children", len(ids))
+	}
+	return fmt.Sprintf("%d beads", len(ids))
+}
+
 // extractCascadeFlag removes --cascade from args and returns whether it was present.
 func extractCascadeFlag(args []string) (bool, []string) {
 	cascade := false
@@ -113,8 +165,9 @@ func extractCascadeFlag(args []string) (bool, []string) {
 
 // childBead represents a child bead from bd children --json output.
 type childBead struct {
-	ID     string `json:"id"`
-	Status string `json:"status"`
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Assignee string `json:"assignee"`
 }
 
 // maxCascadeDepth is the maximum recursion depth for cascade close.
@@ -123,8 +176,9 @@ const maxCascadeDepth = 50
 
 // closeChildren recursively closes all open children of a bead (depth-first).
 // visited tracks already-processed IDs to prevent cycles. depth guards against
-// excessively nested hierarchies.
-func closeChildren(parentID string, visited map[string]bool, depth int) error {
+// excessively nested hierarchies. Closed children are appended to closed
+// with their previous status, for gt undo.
+func closeChildren(parentID string, visited map[string]bool, depth int, closed *[]undo.ClosedBead) error {
 	if depth > maxCascadeDepth {
 		return fmt.Errorf("cascade depth limit (%d) exceeded at %s — possible cycle", maxCascadeDepth, parentID)
 	}
@@ -135,7 +189,7 @@ func closeChildren(parentID string, visited map[string]bool, depth int) error {
 
 	// Query children via bd children --json.
 	// Output is a JSON array of issue objects; childBead extracts only id+status.
-	out, err := exec.Command("bd", "children", parentID, "--json").Output()
+	out, err := beads.CmdOutput(exec.Command("bd", "children", parentID, "--json"))
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() != 0 {
 			fmt.Fprintf(os.Stderr, "Warning: bd children %s failed: %v\n", parentID, err)
@@ -159,10 +213,11 @@ func closeChildren(parentID string, visited map[string]bool, depth int) error {
 		if child.Status == "closed" {
 			continue
 		}
-		if err := closeChildren(child.ID, visited, depth+1); err != nil {
+		if err := closeChildren(child.ID, visited, depth+1, closed); err != nil {
 			return err
 		}
 		childIDs = append(childIDs, child.ID)
+		*closed = append(*closed, undo.ClosedBead{ID: child.ID, Status: child.Status, Assignee: child.Assignee})
 	}
 
 	if len(childIDs) == 0 {
@@ -180,7 +235,7 @@ func closeChildren(parentID string, visited map[string]bool, depth int) error {
 	bdCmd := exec.Command("bd", bdArgs...)
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
-	return bdCmd.Run()
+	return beads.RunCmd(bdCmd)
 }
 
 // extractBeadIDs extracts bead IDs from raw args, skipping flags and flag values.
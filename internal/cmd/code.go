@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/codeindex"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	codeRig   string
+	codeFull  bool
+	codeLimit int
+	codeJSON  bool
+)
+
+var codeCmd = &cobra.Command{
+	Use:     "code",
+	GroupID: GroupWork,
+	Short:   "Query the rig's code index (search, symbols)",
+	RunE:    requireSubcommand,
+	Long: `Query a rig's code index: where things are defined and which files
+relate to a task, without grepping the whole tree.
+
+The index is opt-in per rig, in settings/config.json:
+
+  "code_index": {
+    "enabled": true,
+    "exclude": ["testdata/", "*.pb.go"],
+    "embedding_endpoint": "http://localhost:11434/v1/embeddings",
+    "embedding_model": "nomic-embed-text"
+  }
+
+It holds each file's symbols and a short summary and, with an embedding
+endpoint, an embedding per file. The refinery refreshes it after every
+merge, re-reading only the files that changed. Without embeddings, search
+ranks by TF-IDF over paths, summaries and symbol names.
+
+The rig defaults to the one containing the current directory.
+
+Examples:
+  gt code search "retry a failed merge in the batch"
+  gt code symbol HandleMRInfoSuccess
+  gt code status --rig gastown
+  gt code index --rig gastown --full`,
+}
+
+var codeIndexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Build or refresh the rig's code index",
+	Long: `Build or refresh the rig's code index from its mayor clone.
+
+Only files whose content changed since the last refresh are re-read and
+re-embedded; --full re-reads everything. The refinery does this after each
+merge, so running it by hand is only needed to build the first index.
+
+Examples:
+  gt code index --rig gastown
+  gt code index --rig gastown --full`,
+	Args: cobra.NoArgs,
+	RunE: runCodeIndex,
+}
+
+var codeSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Find the files most related to a query",
+	Long: `Find the files most related to a natural-language or identifier query,
+best first, with their summaries and any symbols the query names.
+
+Examples:
+  gt code search "how does the refinery batch merges"
+  gt code search convoy close notification --limit 5
+  gt code search "rig settings validation" --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runCodeSearch,
+}
+
+var codeSymbolCmd = &cobra.Command{
+	Use:   "symbol <name>",
+	Short: "Find where a symbol is defined",
+	Long: `Find where a function, type or class is defined: exact (case-insensitive)
+matches first, then names containing <name>.
+
+Examples:
+  gt code symbol LoadRigSettings
+  gt code symbol engineer --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCodeSymbol,
+}
+
+var codeStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the code index's size and freshness",
+	Args:  cobra.NoArgs,
+	RunE:  runCodeStatus,
+}
+
+func init() {
+	codeCmd.PersistentFlags().StringVar(&codeRig, "rig", "", "Rig whose index to use (default: inferred from the current directory)")
+	codeIndexCmd.Flags().BoolVar(&codeFull, "full", false, "Re-read every file, not just changed ones")
+	codeSearchCmd.Flags().IntVar(&codeLimit, "limit", 10, "Maximum results")
+	codeSearchCmd.Flags().BoolVar(&codeJSON, "json", false, "Output as JSON")
+	codeSymbolCmd.Flags().BoolVar(&codeJSON, "json", false, "Output as JSON")
+	codeStatusCmd.Flags().BoolVar(&codeJSON, "json", false, "Output as JSON")
+
+	codeCmd.AddCommand(codeIndexCmd, codeSearchCmd, codeSymbolCmd, codeStatusCmd)
+	rootCmd.AddCommand(codeCmd)
+}
+
+// codeRigIndex resolves the rig and its code index settings.
+func codeRigIndex() (rigName, rigPath string, cfg *codeindex.Config, err error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigName = codeRig
+	if rigName == "" {
+		if rigName, err = inferRigFromCwd(townRoot); err != nil {
+			return "", "", nil, fmt.Errorf("could not determine rig (use --rig): %w", err)
+		}
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return "", "", nil, err
+	}
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return "", "", nil, fmt.Errorf("loading rig settings: %w", err)
+	}
+	if settings == nil || !settings.CodeIndex.IsEnabled() {
+		return "", "", nil, fmt.Errorf("rig %s has no code index (set code_index.enabled in %s)", rigName, config.RigSettingsPath(r.Path))
+	}
+	return rigName, r.Path, settings.CodeIndex, nil
+}
+
+// loadCodeIndex loads the rig's index, failing when none was built yet.
+func loadCodeIndex() (*codeindex.Index, *codeindex.Config, error) {
+	rigName, rigPath, cfg, err := codeRigIndex()
+	if err != nil {
+		return nil, nil, err
+	}
+	idx, err := codeindex.Load(rigPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(idx.Files) == 0 {
+		return nil, nil, fmt.Errorf("rig %s's code index is empty (run: gt code index --rig %s)", rigName, rigName)
+	}
+	return idx, cfg, nil
+}
+
+func runCodeIndex(cmd *cobra.Command, args []string) error {
+	rigName, rigPath, cfg, err := codeRigIndex()
+	if err != nil {
+		return err
+	}
+	repoDir := filepath.Join(rigPath, "mayor", "rig")
+	if _, err := os.Stat(repoDir); err != nil {
+		return fmt.Errorf("rig %s has no mayor clone to index: %w", rigName, err)
+	}
+
+	idx, err := codeindex.Load(rigPath)
+	if err != nil {
+		return err
+	}
+	stats, err := codeindex.Refresh(context.Background(), idx, repoDir, cfg, cfg.Embedder(), codeFull)
+	if err != nil {
+		return err
+	}
+	idx.Rig = rigName
+	if err := idx.Save(rigPath); err != nil {
+		return fmt.Errorf("saving code index: %w", err)
+	}
+
+	fmt.Printf("%s Indexed %s: %s\n", style.SuccessPrefix, rigName, stats)
+	if stats.EmbedErr != nil {
+		fmt.Printf("%s embeddings failed, search falls back to TF-IDF until the next refresh: %v\n", style.Warning.Render("⚠"), stats.EmbedErr)
+	}
+	return nil
+}
+
+func runCodeSearch(cmd *cobra.Command, args []string) error {
+	idx, cfg, err := loadCodeIndex()
+	if err != nil {
+		return err
+	}
+	results, err := codeindex.Search(context.Background(), idx, strings.Join(args, " "), cfg.Embedder(), codeLimit)
+	if err != nil {
+		return err
+	}
+
+	if codeJSON {
+		// Embeddings are noise to a reader of the results.
+		out := make([]codeindex.Result, len(results))
+		for i, r := range results {
+			f := *r.File
+			f.Embedding = nil
+			r.File = &f
+			out[i] = r
+		}
+		return printCodeJSON(out)
+	}
+	if len(results) == 0 {
+		fmt.Println("No matching files.")
+		return nil
+	}
+	for _, r := range results {
+		fmt.Printf("%s %s\n", style.Bold.Render(r.File.Path), style.Dim.Render(fmt.Sprintf("(%.2f)", r.Score)))
+		if r.File.Summary != "" {
+			fmt.Printf("  %s\n", r.File.Summary)
+		}
+		for _, s := range r.Matched {
+			fmt.Printf("  %s %s:%d\n", s.Kind, s.Name, s.Line)
+		}
+	}
+	fmt.Println(style.Dim.Render(fmt.Sprintf("Ranked by %s.", results[0].Method)))
+	return nil
+}
+
+func runCodeSymbol(cmd *cobra.Command, args []string) error {
+	idx, _, err := loadCodeIndex()
+	if err != nil {
+		return err
+	}
+	matches := codeindex.FindSymbol(idx, args[0])
+
+	if codeJSON {
+		return printCodeJSON(matches)
+	}
+	if len(matches) == 0 {
+		fmt.Printf("No symbol matching %q.\n", args[0])
+		return nil
+	}
+	for _, m := range matches {
+		fmt.Printf("%s:%d  %s %s\n", m.Path, m.Symbol.Line, style.Dim.Render(m.Symbol.Kind), m.Symbol.Name)
+	}
+	return nil
+}
+
+func runCodeStatus(cmd *cobra.Command, args []string) error {
+	rigName, rigPath, cfg, err := codeRigIndex()
+	if err != nil {
+		return err
+	}
+	idx, err := codeindex.Load(rigPath)
+	if err != nil {
+		return err
+	}
+
+	if codeJSON {
+		return printCodeJSON(map[string]interface{}{
+			"rig":        rigName,
+			"files":      len(idx.Files),
+			"symbols":    idx.SymbolCount(),
+			"embedded":   idx.Embedded(),
+			"model":      idx.Model,
+			"commit":     idx.Commit,
+			"updated_at": idx.UpdatedAt,
+		})
+	}
+	if len(idx.Files) == 0 {
+		fmt.Printf("Rig %s's code index is not built yet (run: gt code index --rig %s)\n", rigName, rigName)
+		return nil
+	}
+	fmt.Printf("%s code index\n", style.Bold.Render(rigName))
+	fmt.Printf("  Files:    %d (%d symbols)\n", len(idx.Files), idx.SymbolCount())
+	if cfg.Model() != "" {
+		fmt.Printf("  Embedded: %d of %d\n", idx.Embedded(), len(idx.Files))
+	}
+	if idx.Commit != "" {
+		fmt.Printf("  Commit:   %s\n", shortSHA(idx.Commit))
+	}
+	fmt.Printf("  Updated:  %s ago\n", time.Since(idx.UpdatedAt).Round(time.Second))
+	return nil
+}
+
+func printCodeJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -341,7 +341,7 @@ func createCompactReportBead(report *compactReport, markdown string) (string, er
 	}
 
 	bdCmd := exec.Command("bd", bdArgs...)
-	output, err := bdCmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(bdCmd)
 	if err != nil {
 		return "", fmt.Errorf("creating report bead: %w\nOutput: %s", err, string(output))
 	}
@@ -350,7 +350,7 @@ func createCompactReportBead(report *compactReport, markdown string) (string, er
 
 	// Auto-close (audit record, not work)
 	closeCmd := exec.Command("bd", "close", beadID, "--reason=daily compaction report")
-	_ = closeCmd.Run()
+	_ = beads.RunCmd(closeCmd)
 
 	return beadID, nil
 }
@@ -455,7 +455,7 @@ func queryCompactionReports(startDate, endDate string) ([]*compactReport, error)
 		"--json",
 		"--limit=0",
 	)
-	listOutput, err := listCmd.Output()
+	listOutput, err := beads.CmdOutput(listCmd)
 	if err != nil {
 		return nil, fmt.Errorf("listing event beads: %w", err)
 	}
@@ -563,7 +563,7 @@ func findExistingCompactReport(dateStr string) (string, error) {
 		"--json",
 		"--limit=50",
 	)
-	listOutput, err := listCmd.Output()
+	listOutput, err := beads.CmdOutput(listCmd)
 	if err != nil {
 		return "", err
 	}
@@ -594,7 +594,7 @@ func findExistingWeeklyRollup(weekStart, weekEnd string) (string, error) {
 		"--json",
 		"--limit=20",
 	)
-	listOutput, err := listCmd.Output()
+	listOutput, err := beads.CmdOutput(listCmd)
 	if err != nil {
 		return "", err
 	}
@@ -634,7 +634,7 @@ func createWeeklyRollupBead(rollup *weeklyRollup, markdown string) (string, erro
 	}
 
 	bdCmd := exec.Command("bd", bdArgs...)
-	output, err := bdCmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(bdCmd)
 	if err != nil {
 		return "", fmt.Errorf("creating weekly rollup bead: %w\nOutput: %s", err, string(output))
 	}
@@ -643,7 +643,7 @@ func createWeeklyRollupBead(rollup *weeklyRollup, markdown string) (string, erro
 
 	// Auto-close (audit record, not work)
 	closeCmd := exec.Command("bd", "close", beadID, "--reason=weekly compaction rollup")
-	_ = closeCmd.Run()
+	_ = beads.RunCmd(closeCmd)
 
 	return beadID, nil
 }
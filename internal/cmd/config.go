@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/i18n"
 	"github.com/steveyegge/gastown/internal/scheduler/capacity"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -33,7 +34,9 @@ Commands:
   gt config agent get <name>         Show agent configuration
   gt config agent set <name> <cmd>   Set custom agent command
   gt config agent remove <name>      Remove custom agent
-  gt config default-agent [name]     Get or set default agent`,
+  gt config default-agent [name]     Get or set default agent
+  gt config canary <name> --rig <r>  Trial a polecat config change on one polecat
+  gt config promote <canary>         Roll a proven canary out town-wide`,
 }
 
 // Agent subcommands
@@ -606,6 +609,8 @@ Supported keys:
   convoy.notify_on_complete   Push notification to Mayor session on convoy
                               completion (true/false, default: false)
   cli_theme                   CLI color scheme ("dark", "light", "auto")
+  locale                      Language for output and agent briefings (e.g. "ja";
+                              translations live in settings/locales/<locale>/)
   default_agent               Default agent preset name
   dolt.port                   Dolt SQL server port (default: 3307). Set this when
                               another Gas Town instance is using the same port.
@@ -632,6 +637,7 @@ Supported keys:
 Examples:
   gt config set convoy.notify_on_complete true
   gt config set cli_theme dark
+  gt config set locale ja
   gt config set default_agent claude
   gt config set dolt.port 3308
   gt config set scheduler.max_polecats 5
@@ -653,6 +659,7 @@ Supported keys:
   convoy.notify_on_complete   Push notification to Mayor session on convoy
                               completion (true/false, default: false)
   cli_theme                   CLI color scheme
+  locale                      Language for output and agent briefings
   default_agent               Default agent preset name
   scheduler.max_polecats      Dispatch mode (-1 = direct, N > 0 = deferred)
   scheduler.batch_size        Beads per heartbeat
@@ -716,6 +723,12 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("invalid cli_theme: %q (expected dark, light, or auto)", value)
 		}
 
+	case "locale":
+		if !i18n.Valid(value) {
+			return fmt.Errorf("invalid locale: %q (expected a language tag such as ja or pt-BR)", value)
+		}
+		townSettings.Locale = value
+
 	case "default_agent":
 		townSettings.DefaultAgent = value
 
@@ -780,7 +793,7 @@ func runConfigSet(cmd *cobra.Command, args []string) error {
 		if strings.HasPrefix(key, "lifecycle.") {
 			return setLifecycleConfig(townRoot, key, value)
 		}
-		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  dolt.port\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay\n  maintenance.window\n  maintenance.interval\n  maintenance.threshold\n  lifecycle.reaper.*\n  lifecycle.compactor.*\n  lifecycle.doctor.*\n  lifecycle.backup.*", key)
+		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  locale\n  default_agent\n  dolt.port\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay\n  maintenance.window\n  maintenance.interval\n  maintenance.threshold\n  lifecycle.reaper.*\n  lifecycle.compactor.*\n  lifecycle.doctor.*\n  lifecycle.backup.*", key)
 	}
 
 	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
@@ -820,6 +833,12 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 			value = "auto"
 		}
 
+	case "locale":
+		value = townSettings.Locale
+		if value == "" {
+			value = i18n.DefaultLocale
+		}
+
 	case "default_agent":
 		value = townSettings.DefaultAgent
 		if value == "" {
@@ -865,7 +884,7 @@ func runConfigGet(cmd *cobra.Command, args []string) error {
 		if strings.HasPrefix(key, "lifecycle.") {
 			return getLifecycleConfig(townRoot, key)
 		}
-		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  default_agent\n  dolt.port\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay\n  maintenance.window\n  maintenance.interval\n  maintenance.threshold\n  lifecycle.reaper.*\n  lifecycle.compactor.*\n  lifecycle.doctor.*\n  lifecycle.backup.*", key)
+		return fmt.Errorf("unknown config key: %q\n\nSupported keys:\n  convoy.notify_on_complete\n  cli_theme\n  locale\n  default_agent\n  dolt.port\n  scheduler.max_polecats\n  scheduler.batch_size\n  scheduler.spawn_delay\n  maintenance.window\n  maintenance.interval\n  maintenance.threshold\n  lifecycle.reaper.*\n  lifecycle.compactor.*\n  lifecycle.doctor.*\n  lifecycle.backup.*", key)
 	}
 
 	fmt.Println(value)
@@ -0,0 +1,276 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/experiment"
+	"github.com/steveyegge/gastown/internal/persona"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	canaryRig         string
+	canaryFormula     string
+	canaryAgent       string
+	canaryPrompt      string
+	canaryBeads       int
+	canaryDescription string
+	promoteForce      bool
+	promoteDryRun     bool
+)
+
+var configCanaryCmd = &cobra.Command{
+	Use:         "canary <name>",
+	Annotations: map[string]string{AnnotationFeature: config.FeatureExperiments},
+	Short:       "Trial a prompt, formula or agent change on one polecat before promoting it",
+	Long: `Start a canary rollout of a polecat configuration change.
+
+The change — a formula, an agent (runtime/model alias) or an extra prompt
+for the executor instructions — is applied to one bead at a time slung to
+the rig, so a single polecat runs it while the rest of the rig carries on
+as before. After --beads canary beads have closed, compare them against
+the baseline beads slung meanwhile and roll the change out town-wide with
+gt config promote.
+
+A canary is an experiment with arms "baseline" and "canary"; gt experiment
+report shows the comparison and gt experiment stop abandons it.
+
+Examples:
+  gt config canary terse --rig gastown --prompt @prompts/terse.md
+  gt config canary sonnet --rig gastown --agent claude-sonnet --beads 10
+  gt config canary new-work --rig gastown --formula mol-polecat-work-v2
+  gt experiment report terse
+  gt config promote terse`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigCanary,
+}
+
+var configPromoteCmd = &cobra.Command{
+	Use:         "promote <canary>",
+	Annotations: map[string]string{AnnotationFeature: config.FeatureExperiments},
+	Short:       "Roll a canary's change out town-wide",
+	Long: `Promote a canary once it has proven itself against baseline.
+
+Promotion needs every canary bead closed and outcomes no worse than
+baseline beyond a margin: median cycle time within 1.5x, cost per bead
+within 1.25x, and at most 0.25 more review rejections per bead. --force
+promotes anyway.
+
+What is rolled out:
+  formula  becomes polecat_formula in settings/config.json (replaces
+           mol-polecat-work as the default for polecat slings)
+  agent    becomes role_agents.polecat in settings/config.json
+  prompt   is appended to the polecat persona as a new version
+           (gt role diff polecat, gt role rollback polecat to undo)
+
+Examples:
+  gt config promote terse --dry-run
+  gt config promote terse
+  gt config promote terse --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigPromote,
+}
+
+// canaryBeadOpenFn is a seam for tests. Production looks the bead up with
+// getBeadInfo.
+var canaryBeadOpenFn = func(beadID string) bool {
+	info, err := getBeadInfo(beadID)
+	return err != nil || info.Status != "closed"
+}
+
+func init() {
+	configCanaryCmd.Flags().StringVar(&canaryRig, "rig", "", "Rig whose slings feed the canary (required)")
+	configCanaryCmd.Flags().StringVar(&canaryFormula, "formula", "", "Formula to trial instead of the default")
+	configCanaryCmd.Flags().StringVar(&canaryAgent, "agent", "", "Agent alias to trial for polecats")
+	configCanaryCmd.Flags().StringVar(&canaryPrompt, "prompt", "", "Extra executor prompt to trial (@file reads a file)")
+	configCanaryCmd.Flags().IntVar(&canaryBeads, "beads", 5, "Canary beads to run before promotion")
+	configCanaryCmd.Flags().StringVarP(&canaryDescription, "description", "d", "", "What the change is")
+	_ = configCanaryCmd.MarkFlagRequired("rig")
+
+	configPromoteCmd.Flags().BoolVar(&promoteForce, "force", false, "Promote even if the canary hasn't met the bar")
+	configPromoteCmd.Flags().BoolVarP(&promoteDryRun, "dry-run", "n", false, "Show the verdict and changes without applying them")
+
+	configCmd.AddCommand(configCanaryCmd)
+	configCmd.AddCommand(configPromoteCmd)
+}
+
+func runConfigCanary(cmd *cobra.Command, args []string) error {
+	townRoot, _, err := getRig(canaryRig)
+	if err != nil {
+		return err
+	}
+	if existing, err := experiment.ActiveCanary(townRoot, canaryRig); err != nil {
+		return err
+	} else if existing != nil {
+		return fmt.Errorf("rig %s already has canary %s (promote or stop it first)", canaryRig, existing.Name)
+	}
+
+	prompt := canaryPrompt
+	if path, isFile := strings.CutPrefix(prompt, "@"); isFile {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading prompt: %w", err)
+		}
+		prompt = strings.TrimSpace(string(data))
+	}
+	candidate := experiment.Arm{Formula: canaryFormula, Agent: canaryAgent, Prompt: prompt}
+
+	e := experiment.NewCanary(args[0], canaryRig, canaryBeads, candidate)
+	e.Description = canaryDescription
+	e.CreatedBy = changeAuthor()
+	if err := experiment.Create(townRoot, e); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Canary %s on %s for %d bead(s): %s\n", style.Bold.Render("🐤"), e.Name, canaryRig, canaryBeads, describeArm(*e.Arm(experiment.CanaryArm)))
+	fmt.Printf("  Slings to %s now run the change on one polecat at a time.\n", canaryRig)
+	fmt.Printf("  Compare: gt experiment report %s   Roll out: gt config promote %s\n", e.Name, e.Name)
+	return nil
+}
+
+func runConfigPromote(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	e, err := experiment.Load(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	if e.Canary == nil {
+		return fmt.Errorf("%s is an experiment, not a canary", e.Name)
+	}
+	if e.Status != experiment.StatusActive {
+		return fmt.Errorf("canary %s is %s", e.Name, e.Status)
+	}
+
+	report, err := buildExperimentReport(townRoot, e)
+	if err != nil {
+		return err
+	}
+	printExperimentReport(report)
+	fmt.Println()
+
+	problems := experiment.CanaryVerdict(e, report.Arms)
+	if len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Printf("  %s %s\n", style.Warning.Render("!"), p)
+		}
+		if !promoteForce {
+			return fmt.Errorf("canary %s is not ready to promote (--force to override)", e.Name)
+		}
+	} else {
+		fmt.Printf("%s Canary matches or beats baseline\n", style.Success.Render("✓"))
+	}
+
+	arm := e.Arm(experiment.CanaryArm)
+	if promoteDryRun {
+		for _, change := range canaryChanges(arm) {
+			fmt.Printf("%s %s\n", style.Dim.Render("[dry-run]"), change)
+		}
+		return nil
+	}
+	if err := promoteCanaryArm(townRoot, arm, fmt.Sprintf("promoted from canary %s", e.Name)); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	e.Status = experiment.StatusPromoted
+	e.StoppedAt = &now
+	if err := experiment.Save(townRoot, e); err != nil {
+		return err
+	}
+	for _, change := range canaryChanges(arm) {
+		fmt.Printf("%s %s\n", style.Bold.Render("✓"), change)
+	}
+	fmt.Printf("Promoted canary %s town-wide\n", e.Name)
+	return nil
+}
+
+// canaryChanges describes what promoting arm changes.
+func canaryChanges(arm *experiment.Arm) []string {
+	var changes []string
+	if arm.Formula != "" {
+		changes = append(changes, "polecat_formula = "+arm.Formula)
+	}
+	if arm.Agent != "" {
+		changes = append(changes, "role_agents.polecat = "+arm.Agent)
+	}
+	if arm.Prompt != "" {
+		changes = append(changes, "polecat persona += canary prompt")
+	}
+	return changes
+}
+
+// promoteCanaryArm writes a canary arm's overrides into town settings and
+// the polecat persona.
+func promoteCanaryArm(townRoot string, arm *experiment.Arm, note string) error {
+	if arm.Formula != "" || arm.Agent != "" {
+		path := config.TownSettingsPath(townRoot)
+		settings, err := config.LoadOrCreateTownSettings(path)
+		if err != nil {
+			return fmt.Errorf("loading town settings: %w", err)
+		}
+		if arm.Formula != "" {
+			settings.PolecatFormula = arm.Formula
+		}
+		if arm.Agent != "" {
+			if settings.RoleAgents == nil {
+				settings.RoleAgents = make(map[string]string)
+			}
+			settings.RoleAgents["polecat"] = arm.Agent
+		}
+		if err := config.SaveTownSettings(path, settings); err != nil {
+			return fmt.Errorf("saving town settings: %w", err)
+		}
+	}
+	if arm.Prompt != "" {
+		store := persona.NewStore(townRoot)
+		_, current, err := store.Current("polecat")
+		if err != nil {
+			return fmt.Errorf("reading polecat persona: %w", err)
+		}
+		content := arm.Prompt
+		if current = strings.TrimRight(current, "\n"); current != "" {
+			content = current + "\n\n" + arm.Prompt
+		}
+		if _, err := store.Save("polecat", content+"\n", changeAuthor(), note); err != nil && !errors.Is(err, persona.ErrUnchanged) {
+			return fmt.Errorf("saving polecat persona: %w", err)
+		}
+	}
+	return nil
+}
+
+// assignCanaryArm assigns beadID an arm of rigName's active canary. It
+// returns a nil arm when the rig has no canary.
+func assignCanaryArm(townRoot, rigName, beadID string, dryRun bool) (*experiment.Arm, string, error) {
+	e, err := experiment.ActiveCanary(townRoot, rigName)
+	if err != nil || e == nil {
+		return nil, "", err
+	}
+	arm, err := pickCanaryArm(townRoot, e, beadID, dryRun)
+	return arm, e.Name, err
+}
+
+// pickCanaryArm assigns beadID an arm of canary e. In dry-run mode the arm
+// is picked but not recorded.
+func pickCanaryArm(townRoot string, e *experiment.Experiment, beadID string, dryRun bool) (*experiment.Arm, error) {
+	if !dryRun {
+		return experiment.AssignCanary(townRoot, e, beadID, canaryBeadOpenFn)
+	}
+	if e.Status != experiment.StatusActive {
+		return nil, fmt.Errorf("experiment %s is %s", e.Name, e.Status)
+	}
+	assignments, err := experiment.Assignments(townRoot, e.Name)
+	if err != nil {
+		return nil, err
+	}
+	return e.PickCanary(beadID, assignments, canaryBeadOpenFn), nil
+}
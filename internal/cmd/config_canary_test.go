@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/experiment"
+	"github.com/steveyegge/gastown/internal/persona"
+)
+
+func TestPromoteCanaryArm(t *testing.T) {
+	townRoot := t.TempDir()
+	store := persona.NewStore(townRoot)
+	if _, err := store.Save("polecat", "Keep commits small.\n", "overseer", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	arm := &experiment.Arm{Name: experiment.CanaryArm, Formula: "mol-polecat-work-v2", Agent: "claude-sonnet", Prompt: "Be terse."}
+	if err := promoteCanaryArm(townRoot, arm, "promoted from canary terse"); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.PolecatFormula != "mol-polecat-work-v2" || settings.RoleAgents["polecat"] != "claude-sonnet" {
+		t.Errorf("settings: formula=%q role_agents=%v", settings.PolecatFormula, settings.RoleAgents)
+	}
+	v, content, err := store.Current("polecat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Version != 2 || !strings.HasPrefix(content, "Keep commits small.") || !strings.Contains(content, "Be terse.") {
+		t.Errorf("persona v%d = %q, want old guidance plus canary prompt", v.Version, content)
+	}
+}
+
+func TestAssignCanaryArm(t *testing.T) {
+	townRoot := t.TempDir()
+	orig := canaryBeadOpenFn
+	t.Cleanup(func() { canaryBeadOpenFn = orig })
+	canaryBeadOpenFn = func(string) bool { return true }
+
+	if arm, _, err := assignCanaryArm(townRoot, "gastown", "gt-a", false); err != nil || arm != nil {
+		t.Fatalf("no canary: arm=%v err=%v", arm, err)
+	}
+	if err := experiment.Create(townRoot, experiment.NewCanary("terse", "gastown", 3, experiment.Arm{Prompt: "Be terse."})); err != nil {
+		t.Fatal(err)
+	}
+
+	arm, name, err := assignCanaryArm(townRoot, "gastown", "gt-a", true)
+	if err != nil || arm.Name != experiment.CanaryArm || name != "terse" {
+		t.Fatalf("dry run = %v, %q, %v", arm, name, err)
+	}
+	if got, _ := experiment.Assignments(townRoot, "terse"); len(got) != 0 {
+		t.Errorf("dry run recorded %v", got)
+	}
+
+	if arm, _, _ := assignCanaryArm(townRoot, "gastown", "gt-a", false); arm.Name != experiment.CanaryArm {
+		t.Errorf("first bead = %s, want canary", arm.Name)
+	}
+	if arm, _, _ := assignCanaryArm(townRoot, "gastown", "gt-b", false); arm.Name != experiment.BaselineArm {
+		t.Errorf("second bead while canary open = %s, want baseline", arm.Name)
+	}
+}
+
+func TestResolveFormula_PromotedDefault(t *testing.T) {
+	orig := townPolecatFormulaFn
+	t.Cleanup(func() { townPolecatFormulaFn = orig })
+
+	townPolecatFormulaFn = func() string { return "" }
+	if got := resolveFormula("", false); got != "mol-polecat-work" {
+		t.Errorf("default = %q", got)
+	}
+	townPolecatFormulaFn = func() string { return "mol-polecat-work-v2" }
+	if got := resolveFormula("", false); got != "mol-polecat-work-v2" {
+		t.Errorf("promoted = %q", got)
+	}
+	if got := resolveFormula("mol-custom", false); got != "mol-custom" {
+		t.Errorf("explicit = %q, want flag to win", got)
+	}
+	if got := resolveFormula("", true); got != "" {
+		t.Errorf("hook-raw-bead = %q, want none", got)
+	}
+}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/confirm"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var confirmJSON bool
+
+var confirmCmd = &cobra.Command{
+	Use:     "confirm",
+	GroupID: GroupConfig,
+	Short:   "Confirmation policies for destructive commands",
+	RunE:    requireSubcommand,
+	Long: `Show confirmation policies and approve commands waiting on a second operator.
+
+Destructive commands are confirmed according to the town's policy in
+settings/config.json under "confirmations". Guarded commands:
+
+  rig remove     polecat nuke     close (several beads or --cascade)
+  shutdown       uninstall
+
+Modes:
+  none             Run without asking
+  prompt           Answer y at a [y/N] prompt
+  typed            Type the target's name
+  second-operator  Another operator runs gt confirm approve, then the
+                   requester re-runs the command
+  delay            Re-run the command after the waiting period
+
+--yes satisfies a policy only if it sets "allow_yes" (the default for
+none and prompt), so automation can skip confirmation only where the town
+allows it. Waiting requests expire after 24 hours.
+
+Examples:
+  gt confirm policy               # Effective policy per command
+  gt confirm list                 # Commands waiting on approval or a delay
+  gt confirm approve c-1a2b3c4d   # Approve another operator's request`,
+}
+
+var confirmPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Show the effective confirmation policy per command",
+	Args:  cobra.NoArgs,
+	RunE:  runConfirmPolicy,
+}
+
+var confirmListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List commands waiting on a second operator or a delay",
+	Args:  cobra.NoArgs,
+	RunE:  runConfirmList,
+}
+
+var confirmApproveCmd = &cobra.Command{
+	Use:   "approve <request-id>",
+	Short: "Approve a command requested by another operator",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfirmApprove,
+}
+
+// confirmReadLineFn is a seam for tests. Production prints the prompt and
+// reads a line from stdin.
+var confirmReadLineFn = func(prompt string) string {
+	fmt.Print(prompt)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+func init() {
+	confirmListCmd.Flags().BoolVar(&confirmJSON, "json", false, "Output as JSON")
+
+	confirmCmd.AddCommand(confirmPolicyCmd)
+	confirmCmd.AddCommand(confirmListCmd)
+	confirmCmd.AddCommand(confirmApproveCmd)
+	rootCmd.AddCommand(confirmCmd)
+}
+
+func runConfirmPolicy(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadConfirmations(townRoot)
+	if err != nil {
+		return err
+	}
+	for _, name := range confirm.Commands() {
+		p := cfg.PolicyFor(name)
+		mode := p.Mode
+		if mode == confirm.ModeDelay {
+			mode += " " + p.DelayDuration().String()
+		}
+		yes := style.Dim.Render("--yes refused")
+		if p.YesAllowed() {
+			yes = style.Dim.Render("--yes accepted")
+		}
+		source := ""
+		if _, ok := cfg[name]; !ok {
+			source = style.Dim.Render("(default)")
+		}
+		fmt.Printf("  %-14s %-18s %s %s\n", name, mode, yes, source)
+	}
+	return nil
+}
+
+func runConfirmList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	now := time.Now()
+	reqs, err := confirm.List(townRoot, now)
+	if err != nil {
+		return err
+	}
+	if confirmJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reqs)
+	}
+	if len(reqs) == 0 {
+		fmt.Println("No commands waiting on confirmation.")
+		return nil
+	}
+	for _, r := range reqs {
+		status := "waiting for approval"
+		switch {
+		case r.Ready(now) && r.Mode == confirm.ModeSecondOperator:
+			status = "approved by " + r.ApprovedBy
+		case r.Ready(now):
+			status = "ready"
+		case r.Mode == confirm.ModeDelay:
+			status = "ready in " + r.NotBefore.Sub(now).Round(time.Second).String()
+		}
+		fmt.Printf("%s  %s  %s  by %s  %s\n", style.Dim.Render(r.ID), r.Created.Local().Format("Jan 02 15:04"),
+			confirmDescribe(r.Command, r.Target), r.Requester, style.Dim.Render(status))
+	}
+	return nil
+}
+
+func runConfirmApprove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if os.Getenv("GT_ROLE") != "" {
+		return fmt.Errorf("approvals must come from a human operator, not an agent session")
+	}
+	r, err := confirm.Approve(townRoot, args[0], resolveShiftOperator(""), time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Approved %s for %s; they can re-run it now\n", style.SuccessPrefix, confirmDescribe(r.Command, r.Target), r.Requester)
+	return nil
+}
+
+// confirmDestructive applies the town's confirmation policy to a
+// destructive command about to act on target. It returns false when the
+// operator declined, and an error when the policy can't be satisfied in
+// this run (no terminal to type into, --yes refused, approval or delay pending).
+// Outside a town the built-in policies apply.
+func confirmDestructive(command, target string, yes bool) (bool, error) {
+	policy := confirm.Defaults.PolicyFor(command)
+	townRoot, _ := workspace.FindFromCwd()
+	if townRoot != "" {
+		cfg, err := config.LoadConfirmations(townRoot)
+		if err != nil {
+			return false, err
+		}
+		policy = cfg.PolicyFor(command)
+	}
+	return applyConfirmPolicy(townRoot, command, target, policy, yes, time.Now())
+}
+
+func applyConfirmPolicy(townRoot, command, target string, policy confirm.Policy, yes bool, now time.Time) (bool, error) {
+	if policy.Mode == confirm.ModeNone || (yes && policy.YesAllowed()) {
+		return true, nil
+	}
+	what := confirmDescribe(command, target)
+
+	switch policy.Mode {
+	case confirm.ModePrompt:
+		// The answer may be piped in ("echo y | gt shutdown"), as before
+		// policies existed.
+		answer := strings.ToLower(confirmReadLineFn(fmt.Sprintf("Proceed with %s? [y/N] ", what)))
+		return answer == "y" || answer == "yes", nil
+	case confirm.ModeTyped:
+		if !isStdinTerminal() {
+			if yes {
+				return false, fmt.Errorf("%s requires %s confirmation and the town's policy refuses --yes", what, policy.Mode)
+			}
+			return false, fmt.Errorf("%s needs confirmation: run it from a terminal or pass --yes", what)
+		}
+		word := target
+		if word == "" {
+			word = command
+		}
+		return confirmReadLineFn(fmt.Sprintf("Type %s to confirm %s: ", style.Bold.Render(word), what)) == word, nil
+	}
+
+	// second-operator and delay keep state between runs in the town.
+	if townRoot == "" {
+		return false, fmt.Errorf("%s requires %s confirmation, which needs a Gas Town workspace", what, policy.Mode)
+	}
+	requester := resolveShiftOperator("")
+	req, err := confirm.Open(townRoot, command, target, policy.Mode, requester, now.Add(policy.DelayDuration()), now)
+	if err != nil {
+		return false, err
+	}
+	if req.Ready(now) {
+		if err := confirm.Use(townRoot, req.ID, now); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if policy.Mode == confirm.ModeSecondOperator {
+		return false, fmt.Errorf("%s needs a second operator: ask someone other than %s to run %s, then re-run this command",
+			what, requester, style.Bold.Render("gt confirm approve "+req.ID))
+	}
+	return false, fmt.Errorf("%s is delayed: re-run this command in %s (request %s)",
+		what, req.NotBefore.Sub(now).Round(time.Second), req.ID)
+}
+
+// confirmDescribe names a command and its target for prompts and errors.
+func confirmDescribe(command, target string) string {
+	if target == "" {
+		return command
+	}
+	return command + " " + target
+}
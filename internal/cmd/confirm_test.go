@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/confirm"
+)
+
+func TestApplyConfirmPolicy(t *testing.T) {
+	origTerm, origRead := isStdinTerminal, confirmReadLineFn
+	t.Cleanup(func() { isStdinTerminal, confirmReadLineFn = origTerm, origRead })
+	terminal := true
+	answer := ""
+	isStdinTerminal = func() bool { return terminal }
+	confirmReadLineFn = func(string) string { return answer }
+	t.Setenv("GT_OPERATOR", "alice")
+
+	town := t.TempDir()
+	now := time.Now()
+	prompt := confirm.Policy{Mode: confirm.ModePrompt}
+	typed := confirm.Policy{Mode: confirm.ModeTyped}
+
+	answer = "y"
+	if ok, err := applyConfirmPolicy(town, "polecat nuke", "gastown/Toast", prompt, false, now); !ok || err != nil {
+		t.Errorf("prompt answered y: %v, %v", ok, err)
+	}
+	if ok, _ := applyConfirmPolicy(town, "rig remove", "alpha", typed, false, now); ok {
+		t.Error("typed mode accepted y instead of the rig name")
+	}
+	answer = "alpha"
+	if ok, err := applyConfirmPolicy(town, "rig remove", "alpha", typed, false, now); !ok || err != nil {
+		t.Errorf("typed the name: %v, %v", ok, err)
+	}
+
+	terminal = false
+	if ok, err := applyConfirmPolicy(town, "polecat nuke", "gastown/Toast", prompt, true, now); !ok || err != nil {
+		t.Errorf("prompt with --yes: %v, %v", ok, err)
+	}
+	if _, err := applyConfirmPolicy(town, "rig remove", "alpha", typed, true, now); err == nil || !strings.Contains(err.Error(), "refuses --yes") {
+		t.Errorf("typed with --yes and no terminal: %v", err)
+	}
+	// A prompt still reads a piped answer.
+	answer = "y"
+	if ok, err := applyConfirmPolicy(town, "polecat nuke", "gastown/Toast", prompt, false, now); !ok || err != nil {
+		t.Errorf("prompt with y piped in: %v, %v", ok, err)
+	}
+	answer = ""
+	if ok, err := applyConfirmPolicy(town, "polecat nuke", "gastown/Toast", prompt, false, now); ok || err != nil {
+		t.Errorf("prompt with nothing piped in: %v, %v; want declined", ok, err)
+	}
+	if _, err := applyConfirmPolicy(town, "rig remove", "alpha", typed, false, now); err == nil {
+		t.Error("typed without a terminal or --yes should fail")
+	}
+
+	second := confirm.Policy{Mode: confirm.ModeSecondOperator}
+	_, err := applyConfirmPolicy(town, "uninstall", "", second, true, now)
+	if err == nil || !strings.Contains(err.Error(), "gt confirm approve") {
+		t.Fatalf("second operator first run: %v", err)
+	}
+	reqs, _ := confirm.List(town, now)
+	if len(reqs) != 1 {
+		t.Fatalf("requests = %+v", reqs)
+	}
+	if _, err := confirm.Approve(town, reqs[0].ID, "bob", now); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := applyConfirmPolicy(town, "uninstall", "", second, false, now); !ok || err != nil {
+		t.Errorf("second operator after approval: %v, %v", ok, err)
+	}
+	if _, err := applyConfirmPolicy(town, "uninstall", "", second, false, now); err == nil {
+		t.Error("an approval must only confirm one run")
+	}
+}
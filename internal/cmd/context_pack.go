@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/contextpack"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	contextAddKind    string
+	contextAddNote    string
+	contextListJSON   bool
+	contextListRender bool
+)
+
+var contextCmd = &cobra.Command{
+	Use:     "context",
+	GroupID: GroupWork,
+	Short:   "Curate the files, docs and prior beads a bead's polecat should read",
+	Long: `Curate a context pack for a bead: the files, docs and prior beads a
+polecat should read before starting on it.
+
+When the bead is slung, its pack is materialized into a briefing: files are
+checked against the rig's repo, prior beads are looked up for their title,
+status and description. gt prime shows the briefing to the polecat with its
+hooked work, so it starts where the dispatcher would have pointed it instead
+of rediscovering the codebase.`,
+	RunE: requireSubcommand,
+}
+
+var contextAddCmd = &cobra.Command{
+	Use:   "add <bead-id> <file|doc|bead>...",
+	Short: "Add files, docs or prior beads to a bead's context pack",
+	Long: `Add entries to a bead's context pack.
+
+Each ref is classified by its shape: URLs and documentation files (.md,
+.rst, .txt, docs/...) are docs, bead IDs are prior beads and anything else
+is a repo-relative file. Use --kind when the guess is wrong. Adding a ref
+that is already in the pack replaces its note.
+
+Examples:
+  gt context add gt-abc internal/refinery/engineer.go internal/refinery/batch.go
+  gt context add gt-abc docs/reference.md -m "merge queue section"
+  gt context add gt-abc gt-xyz -m "same bug, fixed in the witness"
+  gt context add gt-abc make-check --kind file`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runContextAdd,
+}
+
+var contextListCmd = &cobra.Command{
+	Use:   "list <bead-id>",
+	Short: "Show a bead's context pack",
+	Long: `Show a bead's context pack.
+
+--render shows the briefing the pack would materialize into if the bead
+were slung now.
+
+Examples:
+  gt context list gt-abc
+  gt context list gt-abc --render`,
+	Args: cobra.ExactArgs(1),
+	RunE: runContextList,
+}
+
+var contextRemoveCmd = &cobra.Command{
+	Use:   "remove <bead-id> <ref>...",
+	Short: "Remove entries from a bead's context pack",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runContextRemove,
+}
+
+var (
+	// contextShowBeadFn is a seam for tests. Production runs bd show.
+	contextShowBeadFn = func(id string) (*beads.Issue, error) {
+		return beads.New(resolveBeadDir(id)).Show(id)
+	}
+
+	// contextFileLinesFn is a seam for tests. Production counts the lines in the
+	// file.
+	contextFileLinesFn = func(repoRoot, path string) (int, error) {
+		data, err := os.ReadFile(filepath.Join(repoRoot, filepath.FromSlash(path))) //nolint:gosec // G304: path is a curated repo-relative file
+		if err != nil {
+			return 0, err
+		}
+		return bytes.Count(data, []byte("\n")), nil
+	}
+)
+
+func init() {
+	contextAddCmd.Flags().StringVar(&contextAddKind, "kind", "", "Entry kind for every ref: file, doc or bead (default: guess)")
+	contextAddCmd.Flags().StringVarP(&contextAddNote, "message", "m", "", "Why the entries matter")
+	contextListCmd.Flags().BoolVar(&contextListJSON, "json", false, "Output as JSON")
+	contextListCmd.Flags().BoolVar(&contextListRender, "render", false, "Show the briefing the pack materializes into")
+
+	contextCmd.AddCommand(contextAddCmd, contextListCmd, contextRemoveCmd)
+	rootCmd.AddCommand(contextCmd)
+}
+
+func runContextAdd(cmd *cobra.Command, args []string) error {
+	if contextAddKind != "" && !contextpack.ValidKind(contextAddKind) {
+		return fmt.Errorf("invalid --kind %q (want %s, %s or %s)", contextAddKind, contextpack.KindFile, contextpack.KindDoc, contextpack.KindBead)
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	bead := args[0]
+	if _, err := contextShowBeadFn(bead); err != nil {
+		return fmt.Errorf("bead %s: %w", bead, err)
+	}
+
+	entries, err := newContextEntries(bead, args[1:], contextAddKind, contextAddNote, time.Now())
+	if err != nil {
+		return err
+	}
+	state, err := contextpack.LoadState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading context packs: %w", err)
+	}
+	pack := state.Pack(bead)
+	added := pack.Add(entries...)
+	if err := state.Save(townRoot); err != nil {
+		return fmt.Errorf("saving context packs: %w", err)
+	}
+
+	for _, e := range entries {
+		fmt.Printf("%s %s %s\n", style.SuccessPrefix, style.Dim.Render(fmt.Sprintf("%-4s", e.Kind)), e.Ref)
+	}
+	fmt.Printf("Context pack for %s: %d entries (%d new)\n", style.Bold.Render(bead), len(pack.Entries), added)
+	return nil
+}
+
+// newContextEntries builds pack entries for refs, checking that referenced
+// beads exist.
+func newContextEntries(bead string, refs []string, kind, note string, now time.Time) ([]*contextpack.Entry, error) {
+	by := detectSender()
+	var entries []*contextpack.Entry
+	for _, ref := range refs {
+		ref = strings.TrimPrefix(strings.TrimSpace(ref), "./")
+		if ref == "" {
+			continue
+		}
+		k := kind
+		if k == "" {
+			k = contextpack.Classify(ref, looksLikeBeadID)
+		}
+		if k == contextpack.KindBead {
+			if ref == bead {
+				return nil, fmt.Errorf("%s can't be in its own context pack", bead)
+			}
+			if _, err := contextShowBeadFn(ref); err != nil {
+				return nil, fmt.Errorf("bead %s: %w (use --kind file if it is a path)", ref, err)
+			}
+		}
+		entries = append(entries, &contextpack.Entry{Kind: k, Ref: ref, Note: note, By: by, At: now})
+	}
+	return entries, nil
+}
+
+func runContextList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := contextpack.LoadState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading context packs: %w", err)
+	}
+	pack := state.Packs[args[0]]
+
+	if contextListJSON {
+		if pack == nil {
+			pack = &contextpack.Pack{Bead: args[0], Entries: []*contextpack.Entry{}}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(pack)
+	}
+	if pack == nil {
+		fmt.Printf("No context pack for %s.\n", args[0])
+		return nil
+	}
+	if contextListRender {
+		fmt.Print(contextpack.Materialize(pack, contextResolver(townRoot, resolveRigForBead(townRoot, args[0]))))
+		return nil
+	}
+
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("Context pack for %s (%d entries)", args[0], len(pack.Entries))))
+	for _, e := range pack.Entries {
+		line := fmt.Sprintf("  %-4s  %s", e.Kind, e.Ref)
+		if e.Note != "" {
+			line += "  " + style.Dim.Render(e.Note)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runContextRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := contextpack.LoadState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading context packs: %w", err)
+	}
+	pack := state.Packs[args[0]]
+	if pack == nil {
+		return fmt.Errorf("no context pack for %s", args[0])
+	}
+	for _, ref := range args[1:] {
+		if !pack.Remove(strings.TrimPrefix(ref, "./")) {
+			return fmt.Errorf("%s is not in the context pack for %s", ref, args[0])
+		}
+	}
+	if err := state.Save(townRoot); err != nil {
+		return fmt.Errorf("saving context packs: %w", err)
+	}
+	fmt.Printf("%s Removed %d entries; %d left\n", style.SuccessPrefix, len(args)-1, len(pack.Entries))
+	return nil
+}
+
+// contextResolver resolves pack entries against the rig's repo (the mayor's
+// clone) and the routed beads databases. Without a rig clone (e.g. a sling
+// to the mayor), files are not checked.
+func contextResolver(townRoot, rigName string) contextpack.Resolver {
+	r := contextpack.Resolver{
+		Bead: func(id string) (*contextpack.BeadInfo, error) {
+			issue, err := contextShowBeadFn(id)
+			if err != nil {
+				return nil, err
+			}
+			return &contextpack.BeadInfo{Title: issue.Title, Status: issue.Status, Summary: issue.Description}, nil
+		},
+	}
+	repoRoot := filepath.Join(townRoot, rigName, "mayor", "rig")
+	if _, err := os.Stat(repoRoot); rigName != "" && err == nil {
+		r.FileLines = func(path string) (int, error) { return contextFileLinesFn(repoRoot, path) }
+	}
+	return r
+}
+
+// materializeContextPack writes the briefing for packBead's context pack
+// under hookedBead (they differ when a formula wisp is hooked for the bead),
+// returning the number of entries. It is a no-op without a pack.
+func materializeContextPack(townRoot, packBead, hookedBead, rigName string) (int, error) {
+	state, err := contextpack.LoadState(townRoot)
+	if err != nil {
+		return 0, err
+	}
+	pack := state.Packs[packBead]
+	if pack == nil || len(pack.Entries) == 0 {
+		return 0, nil
+	}
+	briefing := contextpack.Materialize(pack, contextResolver(townRoot, rigName))
+	if err := contextpack.WriteBriefing(townRoot, hookedBead, briefing); err != nil {
+		return 0, err
+	}
+	return len(pack.Entries), nil
+}
+
+// outputContextPack shows the hooked bead's materialized context pack.
+func outputContextPack(ctx RoleContext, hookedBead *beads.Issue) {
+	if ctx.TownRoot == "" || hookedBead == nil {
+		return
+	}
+	briefing := contextpack.ReadBriefing(ctx.TownRoot, hookedBead.ID)
+	if briefing == "" {
+		return
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render("## 📦 Context Pack"))
+	fmt.Println("The dispatcher curated this context for the bead. Read it before exploring:")
+	fmt.Println()
+	fmt.Println(strings.TrimRight(briefing, "\n"))
+	fmt.Println()
+}
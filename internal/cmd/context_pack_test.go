@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/contextpack"
+)
+
+func TestMaterializeContextPack(t *testing.T) {
+	origShow := contextShowBeadFn
+	t.Cleanup(func() { contextShowBeadFn = origShow })
+	contextShowBeadFn = func(id string) (*beads.Issue, error) {
+		if id == "gt-old" {
+			return &beads.Issue{ID: id, Title: "Batch merge flake", Status: "closed", Description: "Retried the bisection."}, nil
+		}
+		return nil, fmt.Errorf("%s not found", id)
+	}
+
+	town := t.TempDir()
+	repo := filepath.Join(town, "gastown", "mayor", "rig")
+	if err := os.MkdirAll(filepath.Join(repo, "internal"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "internal", "batch.go"), []byte("package x\n\nfunc f() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// No pack: nothing is written.
+	if n, err := materializeContextPack(town, "gt-abc", "gt-abc", "gastown"); err != nil || n != 0 {
+		t.Fatalf("without a pack = %d, %v", n, err)
+	}
+
+	entries, err := newContextEntries("gt-abc", []string{"./internal/batch.go", "gt-old", "docs/merge.md"}, "", "", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newContextEntries("gt-abc", []string{"gt-nope"}, "", "", time.Now()); err == nil {
+		t.Error("unknown bead refs should be rejected")
+	}
+	state, _ := contextpack.LoadState(town)
+	state.Pack("gt-abc").Add(entries...)
+	if err := state.Save(town); err != nil {
+		t.Fatal(err)
+	}
+
+	// Materialized under the hooked wisp, from the work bead's pack.
+	n, err := materializeContextPack(town, "gt-abc", "gt-wisp-1", "gastown")
+	if err != nil || n != 3 {
+		t.Fatalf("materializeContextPack = %d, %v", n, err)
+	}
+	got := contextpack.ReadBriefing(town, "gt-wisp-1")
+	for _, want := range []string{"- internal/batch.go (3 lines)", "- docs/merge.md (missing)", "- gt-old: Batch merge flake [closed]", "Retried the bisection."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("briefing missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	convoyops "github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/notification"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -77,6 +78,9 @@ var (
 	convoyOwned        bool
 	convoyMerge        string
 	convoyBaseBranch   string
+	convoyAfter        []string
+	convoyLinked       bool
+	convoyLinkedBranch string
 	convoyStatusJSON   bool
 	convoyListJSON     bool
 	convoyListStatus   string
@@ -197,6 +201,10 @@ TRACKING SEMANTICS:
 COMMANDS:
   create    Create a convoy tracking specified issues
   add       Add issues to an existing convoy (reopens if closed)
+  split     Move selected issues out into a new (or existing) convoy
+  merge     Fold convoys into one and close the sources
+  phase     Assign tracked issues to ordered dispatch phases
+  after     Hold a convoy until other convoys close
   close     Close a convoy (verifies all items done, or use --force)
   land      Land an owned convoy (cleanup worktrees, close convoy)
   status    Show convoy progress, tracked issues, and active workers
@@ -220,6 +228,11 @@ The --merge flag sets the merge strategy for all work in the convoy:
   mr      Create merge-request bead, refinery processes (default)
   local   Keep on feature branch (for upstream PRs, human review)
 
+The --linked flag makes a multi-repo convoy: polecats in every rig work on
+one shared branch name (--branch, or derived from the name), and each
+refinery holds its MR until every tracked issue has one. See
+'gt convoy linked'.
+
 Examples:
   gt convoy create "Deploy v2.0" gt-abc bd-xyz
   gt convoy create "Release prep" gt-abc --notify           # defaults to mayor/
@@ -227,7 +240,8 @@ Examples:
   gt convoy create "Feature rollout" gt-a gt-b --owner mayor/ --notify ops/
   gt convoy create "Feature rollout" gt-a gt-b gt-c --molecule mol-release
   gt convoy create --owned "Manual deploy" gt-abc           # caller-managed lifecycle
-  gt convoy create "Quick fix" gt-abc --merge=direct        # bypass refinery`,
+  gt convoy create "Quick fix" gt-abc --merge=direct        # bypass refinery
+  gt convoy create "API v2" gt-a cl-b --linked --branch feat/api-v2`,
 	Args: cobra.MinimumNArgs(1),
 	SilenceUsage: true,
 	RunE:         runConvoyCreate,
@@ -374,6 +388,9 @@ func init() {
 	convoyCreateCmd.Flags().BoolVar(&convoyOwned, "owned", false, "Mark convoy as caller-managed lifecycle (no automatic witness/refinery registration)")
 	convoyCreateCmd.Flags().StringVar(&convoyMerge, "merge", "", "Merge strategy: direct (push to main), mr (merge queue, default), local (keep on branch)")
 	convoyCreateCmd.Flags().StringVar(&convoyBaseBranch, "base-branch", "", "Target branch for polecats (e.g., 'feat/extraction-review')")
+	convoyCreateCmd.Flags().StringSliceVar(&convoyAfter, "after", nil, "Hold dispatch until these convoys close (repeatable)")
+	convoyCreateCmd.Flags().BoolVar(&convoyLinked, "linked", false, "Coordinate one branch and merge gate across rigs")
+	convoyCreateCmd.Flags().StringVar(&convoyLinkedBranch, "branch", "", "Shared branch name for a linked convoy (default: linked/<name>)")
 
 	// Status flags
 	convoyStatusCmd.Flags().BoolVar(&convoyStatusJSON, "json", false, "Output as JSON")
@@ -443,7 +460,7 @@ func runBdJSON(dir string, args ...string) ([]byte, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		if errMsg := strings.TrimSpace(stderr.String()); errMsg != "" {
 			return nil, fmt.Errorf("bd %s: %s", args[0], errMsg)
 		}
@@ -541,6 +558,19 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("invalid --merge value %q: must be direct, mr, or local", convoyMerge)
 		}
 	}
+	if convoyLinkedBranch != "" && !convoyLinked {
+		return fmt.Errorf("--branch requires --linked")
+	}
+	if convoyLinked {
+		if convoyMerge == "direct" || convoyMerge == "local" {
+			return fmt.Errorf("--linked needs the merge queue; it cannot be combined with --merge=%s", convoyMerge)
+		}
+		if convoyLinkedBranch != "" {
+			if err := validateBranchName(convoyLinkedBranch); err != nil {
+				return fmt.Errorf("invalid --branch: %w", err)
+			}
+		}
+	}
 
 	// If first arg looks like an issue ID (has beads prefix), treat all args as issues
 	// and auto-generate a name from the first issue's title
@@ -558,6 +588,11 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if len(trackedIssues) == 0 {
 		return fmt.Errorf("at least one issue ID is required\nUsage: gt convoy create <name> <issue-id> [issue-id...]")
 	}
+	for _, id := range convoyAfter {
+		if !isValidBeadID(id) {
+			return fmt.Errorf("invalid --after convoy ID %q", id)
+		}
+	}
 
 	townBeads, err := getTownBeadsDir()
 	if err != nil {
@@ -583,12 +618,21 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if owner == "" {
 		owner = detectSender()
 	}
+	linkedBranch := ""
+	if convoyLinked {
+		linkedBranch = convoyLinkedBranch
+		if linkedBranch == "" {
+			linkedBranch = defaultLinkedBranch(name)
+		}
+	}
 	convoyFieldValues := &beads.ConvoyFields{
-		Owner:      owner,
-		Notify:     convoyNotify,
-		Merge:      convoyMerge,
-		Molecule:   convoyMolecule,
-		BaseBranch: convoyBaseBranch,
+		Owner:        owner,
+		Notify:       convoyNotify,
+		Merge:        convoyMerge,
+		Molecule:     convoyMolecule,
+		BaseBranch:   convoyBaseBranch,
+		After:        convoyAfter,
+		LinkedBranch: linkedBranch,
 	}
 	description = beads.SetConvoyFields(&beads.Issue{Description: description}, convoyFieldValues)
 
@@ -669,6 +713,12 @@ func runConvoyCreate(cmd *cobra.Command, args []string) error {
 	if convoyBaseBranch != "" {
 		fmt.Printf("  Base:     %s\n", convoyBaseBranch)
 	}
+	if len(convoyAfter) > 0 {
+		fmt.Printf("  After:    %s\n", strings.Join(convoyAfter, ", "))
+	}
+	if linkedBranch != "" {
+		fmt.Printf("  Linked:   %s\n", linkedBranch)
+	}
 	if convoyOwned {
 		fmt.Printf("  Owned:    %s\n", style.Warning.Render("caller-managed lifecycle"))
 	}
@@ -814,7 +864,7 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := beads.RunCmd(showCmd); err != nil {
 		return fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
@@ -885,7 +935,7 @@ func checkSingleConvoy(townBeads, convoyID string, dryRun bool) error {
 	closeCmd := exec.Command("bd", closeArgs...)
 	closeCmd.Dir = townBeads
 
-	if err := closeCmd.Run(); err != nil {
+	if err := beads.RunCmd(closeCmd); err != nil {
 		return fmt.Errorf("closing convoy: %w", err)
 	}
 
@@ -912,7 +962,7 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := beads.RunCmd(showCmd); err != nil {
 		return fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
@@ -997,7 +1047,7 @@ func runConvoyClose(cmd *cobra.Command, args []string) error {
 	closeCmd := exec.Command("bd", closeArgs...)
 	closeCmd.Dir = townBeads
 
-	if err := closeCmd.Run(); err != nil {
+	if err := beads.RunCmd(closeCmd); err != nil {
 		return fmt.Errorf("closing convoy: %w", err)
 	}
 
@@ -1070,7 +1120,7 @@ func runConvoyLand(cmd *cobra.Command, args []string) error {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := beads.RunCmd(showCmd); err != nil {
 		return fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
@@ -1179,7 +1229,7 @@ func runConvoyLand(cmd *cobra.Command, args []string) error {
 	closeCmd := exec.Command("bd", closeArgs...)
 	closeCmd.Dir = townBeads
 
-	if err := closeCmd.Run(); err != nil {
+	if err := beads.RunCmd(closeCmd); err != nil {
 		return fmt.Errorf("closing convoy: %w", err)
 	}
 
@@ -1292,6 +1342,7 @@ type strandedConvoyInfo struct {
 	ReadyIssues  []string `json:"ready_issues"`
 	CreatedAt    string   `json:"created_at,omitempty"`
 	BaseBranch   string   `json:"base_branch,omitempty"`
+	WaitingOn    string   `json:"waiting_on,omitempty"` // Convoy ordering holding dispatch (after:/phase_N:)
 }
 
 // readyIssueInfo holds info about a ready (stranded) issue.
@@ -1328,6 +1379,8 @@ func runConvoyStranded(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  🚚 %s: %s\n", s.ID, s.Title)
 		if s.ReadyCount == 0 && s.TrackedCount == 0 {
 			fmt.Printf("     Empty convoy (0 tracked issues) — needs cleanup\n")
+		} else if s.ReadyCount == 0 && s.WaitingOn != "" {
+			fmt.Printf("     %d tracked issues, waiting on %s\n", s.TrackedCount, s.WaitingOn)
 		} else if s.ReadyCount == 0 && s.TrackedCount > 0 {
 			fmt.Printf("     %d tracked issues, 0 ready — needs agent review\n", s.TrackedCount)
 		} else {
@@ -1344,8 +1397,10 @@ func runConvoyStranded(cmd *cobra.Command, args []string) error {
 	for _, s := range stranded {
 		if s.ReadyCount > 0 {
 			feedable = append(feedable, s)
-		} else if s.TrackedCount > 0 {
+		} else if s.TrackedCount > 0 && s.WaitingOn == "" {
 			needsAttention = append(needsAttention, s)
+		} else if s.TrackedCount > 0 {
+			continue // Held by convoy ordering; proceeds on its own
 		} else {
 			empty = append(empty, s)
 		}
@@ -1404,9 +1459,10 @@ func findStrandedConvoys(townBeads string) ([]strandedConvoyInfo, error) {
 
 	// Check each convoy for stranded state
 	for _, convoy := range convoys {
-		// Extract base_branch from convoy description fields
+		// Extract base_branch and dispatch ordering from convoy description fields
 		var baseBranch string
-		if cf := beads.ParseConvoyFields(&beads.Issue{Description: convoy.Description}); cf != nil {
+		cf := beads.ParseConvoyFields(&beads.Issue{Description: convoy.Description})
+		if cf != nil {
 			baseBranch = cf.BaseBranch
 		}
 
@@ -1445,8 +1501,12 @@ func findStrandedConvoys(townBeads string) ([]strandedConvoyInfo, error) {
 		}
 		scheduledSet := areScheduled(trackedIDs)
 
+		gate := newConvoyGate(townBeads, cf, tracked)
 		var readyIssues []string
 		for _, t := range tracked {
+			if !gate.Allows(t.ID) {
+				continue
+			}
 			if isReadyIssue(t, scheduledSet) {
 				if !isSlingableBead(townRoot, t.ID) {
 					continue
@@ -1471,6 +1531,7 @@ func findStrandedConvoys(townBeads string) ([]strandedConvoyInfo, error) {
 		} else {
 			// Has tracked issues but none are ready — include in stranded
 			// list so callers can distinguish from truly empty convoys.
+			// WaitingOn tells them when that's by the convoy's own ordering.
 			stranded = append(stranded, strandedConvoyInfo{
 				ID:           convoy.ID,
 				Title:        convoy.Title,
@@ -1479,6 +1540,7 @@ func findStrandedConvoys(townBeads string) ([]strandedConvoyInfo, error) {
 				ReadyIssues:  []string{},
 				CreatedAt:    convoy.CreatedAt,
 				BaseBranch:   baseBranch,
+				WaitingOn:    gate.WaitingOn(),
 			})
 		}
 	}
@@ -1609,7 +1671,7 @@ func checkAndCloseCompletedConvoys(townBeads string, dryRun bool) ([]struct{ ID,
 			closeCmd := exec.Command("bd", closeArgs...)
 			closeCmd.Dir = townBeads
 
-			if err := closeCmd.Run(); err != nil {
+			if err := beads.RunCmd(closeCmd); err != nil {
 				style.PrintWarning("couldn't close convoy %s: %v", convoy.ID, err)
 				continue
 			}
@@ -1633,7 +1695,7 @@ func notifyConvoyCompletion(townBeads, convoyID, title string) {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := beads.RunCmd(showCmd); err != nil {
 		return
 	}
 
@@ -1658,6 +1720,14 @@ func notifyConvoyCompletion(townBeads, convoyID, title string) {
 
 	// Push notification to active Mayor session if configured
 	notifyMayorSession(townBeads, convoyID, title)
+
+	raiseNotification(filepath.Dir(townBeads), notification.Notification{
+		Event:    notification.EventConvoyLanded,
+		Severity: notification.SeverityLow,
+		Title:    "Convoy landed: " + title,
+		Body:     fmt.Sprintf("%s: all tracked issues are closed", convoyID),
+		Key:      "convoy-landed-" + convoyID,
+	})
 }
 
 // notifyMayorSession pushes a convoy completion notification into the active
@@ -1745,6 +1815,12 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	fields := beads.ParseConvoyFields(&beads.Issue{Description: convoy.Description})
+	var gate *convoyops.Gate
+	if fields != nil && (len(fields.After) > 0 || len(fields.Phases) > 0) {
+		gate = newConvoyGate(townBeads, fields, tracked)
+	}
+
 	if convoyStatusJSON {
 		lifecycle := "system-managed"
 		if isOwned {
@@ -1757,6 +1833,9 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Owned         bool               `json:"owned"`
 			Lifecycle     string             `json:"lifecycle"`
 			MergeStrategy string             `json:"merge_strategy,omitempty"`
+			After         []string           `json:"after,omitempty"`
+			Phase         int                `json:"phase,omitempty"`
+			WaitingOn     string             `json:"waiting_on,omitempty"`
 			Tracked       []trackedIssueInfo `json:"tracked"`
 			Completed     int                `json:"completed"`
 			Total         int                `json:"total"`
@@ -1768,10 +1847,15 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			Owned:         isOwned,
 			Lifecycle:     lifecycle,
 			MergeStrategy: convoyMergeFromFields(convoy.Description),
+			Phase:         gate.CurrentPhase(),
+			WaitingOn:     gate.WaitingOn(),
 			Tracked:       tracked,
 			Completed:     completed,
 			Total:         len(tracked),
 		}
+		if fields != nil {
+			out.After = fields.After
+		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
 		return enc.Encode(out)
@@ -1791,6 +1875,17 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Merge:     %s\n", merge)
 	}
 	fmt.Printf("  Progress:  %d/%d completed\n", completed, len(tracked))
+	if gate != nil {
+		if len(fields.After) > 0 {
+			fmt.Printf("  After:     %s\n", strings.Join(fields.After, ", "))
+		}
+		if n := gate.CurrentPhase(); n > 0 {
+			fmt.Printf("  Phase:     %d of %d\n", n, len(fields.Phases))
+		}
+		if waiting := gate.WaitingOn(); waiting != "" {
+			fmt.Printf("  Waiting:   %s\n", style.Warning.Render(waiting))
+		}
+	}
 	fmt.Printf("  Created:   %s\n", convoy.CreatedAt)
 	if convoy.ClosedAt != "" {
 		fmt.Printf("  Closed:    %s\n", convoy.ClosedAt)
@@ -1818,6 +1913,9 @@ func runConvoyStatus(cmd *cobra.Command, args []string) error {
 			}
 
 			line := fmt.Sprintf("    %s %s: %s [%s]", status, t.ID, t.Title, bracketContent)
+			if p := fields.PhaseOf(t.ID); p > 0 {
+				line += " " + style.Dim.Render(fmt.Sprintf("phase %d", p))
+			}
 			if t.Worker != "" {
 				workerDisplay := "@" + t.Worker
 				if t.WorkerAge != "" {
@@ -2325,7 +2423,7 @@ func getExternalIssueDetails(townBeads, rigName, issueID string) *issueDetails {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := beads.RunCmd(showCmd); err != nil {
 		return nil
 	}
 	if stdout.Len() == 0 {
@@ -2387,7 +2485,7 @@ func getIssueDetailsBatch(issueIDs []string) map[string]*issueDetails {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := beads.RunCmd(showCmd); err != nil {
 		// Batch failed - fall back to individual lookups for robustness
 		// This handles cases where some IDs are invalid/missing
 		for _, id := range issueIDs {
@@ -2418,7 +2516,7 @@ func getIssueDetails(issueID string) *issueDetails {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := beads.RunCmd(showCmd); err != nil {
 		return nil
 	}
 	// Handle bd exit 0 bug: empty stdout means not found
@@ -2499,7 +2597,7 @@ func getWorkersForIssues(issueIDs []string) map[string]*workerInfo {
 			cmd.Dir = beadsDir
 			var stdout bytes.Buffer
 			cmd.Stdout = &stdout
-			if err := cmd.Run(); err != nil {
+			if err := beads.RunCmd(cmd); err != nil {
 				resultChan <- rigResult{}
 				return
 			}
@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var convoyLinkedJSON bool
+
+var convoyLinkedCmd = &cobra.Command{
+	Use:   "linked <convoy-id>",
+	Short: "Show a linked convoy's branches, MRs and merge readiness",
+	Long: `Show the members of a linked convoy and whether the set is ready to merge.
+
+A linked convoy (gt convoy create --linked) is one logical change spread
+over several rigs, e.g. an API change and the client update that uses it.
+Every polecat on the convoy works on the convoy's shared branch name in
+its own repo (suffixed with the bead ID when a rig has more than one
+bead), so the pieces are easy to find together.
+
+Each refinery holds its linked MRs until every bead in the convoy has an
+MR in its rig's merge queue (or is already closed), then merges them
+normally. This command is what the refinery consults; --json prints the
+same answer.
+
+Examples:
+  gt convoy linked hq-cv-abc
+  gt convoy linked hq-cv-abc --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConvoyLinked,
+}
+
+// linkedMember is one bead of a linked convoy.
+type linkedMember struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Rig    string `json:"rig,omitempty"`
+	Branch string `json:"branch"`
+	MR     string `json:"mr,omitempty"`
+	Ready  bool   `json:"ready"`
+}
+
+// linkedConvoyStatus is the merge readiness of a linked convoy.
+type linkedConvoyStatus struct {
+	ID      string         `json:"id"`
+	Title   string         `json:"title"`
+	Branch  string         `json:"branch"`
+	Ready   bool           `json:"ready"`
+	Waiting []string       `json:"waiting,omitempty"`
+	Members []linkedMember `json:"members"`
+}
+
+var (
+	// linkedShowConvoyFn is a seam for tests. Production uses showConvoyIssue.
+	linkedShowConvoyFn = showConvoyIssue
+
+	// linkedTrackedIssuesFn is a seam for tests. Production uses
+	// getTrackedIssues.
+	linkedTrackedIssuesFn = getTrackedIssues
+
+	// linkedRigForBeadFn is a seam for tests. Production maps the bead's prefix
+	// to its rig.
+	linkedRigForBeadFn = func(townRoot, beadID string) string {
+		return beads.GetRigNameForPrefix(townRoot, beads.ExtractPrefix(beadID))
+	}
+
+	// linkedFindMRFn is a seam for tests. Production returns the open MR for a
+	// bead and its branch, if any.
+	linkedFindMRFn = func(beadID string) (string, string, error) {
+		mrs, err := beads.New(resolveBeadDir(beadID)).ListMergeRequests(beads.ListOptions{
+			Status:   "open",
+			Label:    "gt:merge-request",
+			Priority: -1,
+		})
+		if err != nil {
+			return "", "", err
+		}
+		for _, mr := range mrs {
+			if fields := beads.ParseMRFields(mr); fields != nil && fields.SourceIssue == beadID {
+				return mr.ID, fields.Branch, nil
+			}
+		}
+		return "", "", nil
+	}
+
+	// slingLinkedBranchFn is a seam for tests. Production returns the shared
+	// branch a polecat should use for beadID in rigName, or "" when the bead
+	// isn't in a linked convoy.
+	slingLinkedBranchFn = func(townRoot, rigName, beadID string) string {
+		convoyID := isTrackedByConvoy(beadID)
+		if convoyID == "" {
+			return ""
+		}
+		convoy, err := linkedShowConvoyFn(townRoot, convoyID)
+		if err != nil {
+			return ""
+		}
+		fields := beads.ParseConvoyFields(convoy)
+		if !fields.IsLinked() {
+			return ""
+		}
+		tracked, err := linkedTrackedIssuesFn(townRoot, convoyID)
+		if err != nil {
+			return ""
+		}
+		inRig := 0
+		for _, t := range tracked {
+			if linkedRigForBeadFn(townRoot, t.ID) == rigName {
+				inRig++
+			}
+		}
+		return linkedBranchFor(fields.LinkedBranch, beadID, inRig > 1)
+	}
+)
+
+func init() {
+	convoyLinkedCmd.Flags().BoolVar(&convoyLinkedJSON, "json", false, "Output as JSON")
+	convoyCmd.AddCommand(convoyLinkedCmd)
+}
+
+// showConvoyIssue reads a convoy bead from town beads.
+func showConvoyIssue(townBeads, id string) (*beads.Issue, error) {
+	out, err := runBdJSON(townBeads, "show", id, "--json")
+	if err != nil {
+		return nil, fmt.Errorf("convoy '%s' not found", id)
+	}
+	var issues []*beads.Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, fmt.Errorf("parsing convoy data: %w", err)
+	}
+	if len(issues) == 0 {
+		return nil, fmt.Errorf("convoy '%s' not found", id)
+	}
+	return issues[0], nil
+}
+
+// linkedBranchFor is the branch a linked convoy's bead is worked on. Rigs
+// with several of the convoy's beads need one branch each, so those get the
+// bead ID appended.
+func linkedBranchFor(branch, beadID string, sharedRig bool) string {
+	if sharedRig {
+		return branch + "-" + beadID
+	}
+	return branch
+}
+
+// defaultLinkedBranch derives a shared branch name from a convoy title.
+func defaultLinkedBranch(title string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, title)
+	for strings.Contains(slug, "--") {
+		slug = strings.ReplaceAll(slug, "--", "-")
+	}
+	slug = strings.Trim(slug, "-")
+	if len(slug) > 40 {
+		slug = strings.TrimRight(slug[:40], "-")
+	}
+	if slug == "" {
+		slug = "change"
+	}
+	return "linked/" + slug
+}
+
+// linkedConvoyReadiness works out which members of a linked convoy have
+// submitted their work. The set is ready once every member is closed or has
+// an open MR.
+func linkedConvoyReadiness(townRoot, townBeads, convoyID string) (*linkedConvoyStatus, error) {
+	convoy, err := linkedShowConvoyFn(townBeads, convoyID)
+	if err != nil {
+		return nil, err
+	}
+	fields := beads.ParseConvoyFields(convoy)
+	if !fields.IsLinked() {
+		return nil, fmt.Errorf("%s is not a linked convoy (create one with gt convoy create --linked)", convoyID)
+	}
+	tracked, err := linkedTrackedIssuesFn(townBeads, convoyID)
+	if err != nil {
+		return nil, fmt.Errorf("getting tracked issues for %s: %w", convoyID, err)
+	}
+
+	status := &linkedConvoyStatus{ID: convoy.ID, Title: convoy.Title, Branch: fields.LinkedBranch, Ready: true}
+	perRig := make(map[string]int)
+	rigs := make([]string, len(tracked))
+	for i, t := range tracked {
+		rigs[i] = linkedRigForBeadFn(townRoot, t.ID)
+		perRig[rigs[i]]++
+	}
+	for i, t := range tracked {
+		m := linkedMember{
+			ID:     t.ID,
+			Title:  t.Title,
+			Status: t.Status,
+			Rig:    rigs[i],
+			Branch: linkedBranchFor(fields.LinkedBranch, t.ID, perRig[rigs[i]] > 1),
+		}
+		if t.Status == "closed" {
+			m.Ready = true
+		} else {
+			mrID, branch, err := linkedFindMRFn(t.ID)
+			if err != nil {
+				return nil, fmt.Errorf("looking up MR for %s: %w", t.ID, err)
+			}
+			if mrID != "" {
+				m.MR, m.Ready = mrID, true
+				if branch != "" {
+					m.Branch = branch
+				}
+			}
+		}
+		if !m.Ready {
+			status.Ready = false
+			status.Waiting = append(status.Waiting, t.ID)
+		}
+		status.Members = append(status.Members, m)
+	}
+	return status, nil
+}
+
+func runConvoyLinked(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	status, err := linkedConvoyReadiness(townRoot, townRoot, args[0])
+	if err != nil {
+		return err
+	}
+
+	if convoyLinkedJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
+	}
+
+	fmt.Printf("🔗 %s %s\n", style.Bold.Render(status.ID), status.Title)
+	fmt.Printf("  Branch: %s\n\n", status.Branch)
+	for _, m := range status.Members {
+		mark := style.Dim.Render("○")
+		if m.Ready {
+			mark = style.Success.Render("✓")
+		}
+		state := m.Status
+		if m.MR != "" {
+			state = "MR " + m.MR
+		}
+		fmt.Printf("  %s %-12s %-12s %-32s %s\n", mark, m.ID, m.Rig, m.Branch, state)
+	}
+	fmt.Println()
+	if status.Ready {
+		fmt.Printf("%s Ready: every member has submitted; refineries will merge the set\n", style.Success.Render("✓"))
+	} else {
+		fmt.Printf("Holding merges: waiting on %s\n", strings.Join(status.Waiting, ", "))
+	}
+	return nil
+}
+
+// recordLinkedMR comments the new MR on the linked convoy so the convoy bead
+// cross-references every member's MR and branch.
+func recordLinkedMR(convoyID, issueID, rigName, branch, mrID string) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	comment := fmt.Sprintf("Linked MR: %s for %s (rig %s, branch %s)", mrID, issueID, rigName, branch)
+	if _, err := beads.New(filepath.Join(townRoot, ".beads")).Run("comments", "add", convoyID, comment); err != nil {
+		style.PrintWarning("could not record MR %s on linked convoy %s: %v", mrID, convoyID, err)
+	}
+}
+
+// linkedSpawnBranch is the branch override for a polecat spawned on beadID,
+// or "" to use the normal per-polecat branch.
+func linkedSpawnBranch(townRoot, rigName, beadID string) string {
+	if beadID == "" {
+		return ""
+	}
+	branch := slingLinkedBranchFn(townRoot, rigName, beadID)
+	if branch != "" {
+		fmt.Printf("  Linked convoy branch: %s\n", branch)
+	}
+	return branch
+}
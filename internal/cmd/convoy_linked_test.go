@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func stubLinked(t *testing.T, branch string, tracked []trackedIssueInfo, mrs map[string]string) {
+	t.Helper()
+	origShow, origTracked, origRig, origMR := linkedShowConvoyFn, linkedTrackedIssuesFn, linkedRigForBeadFn, linkedFindMRFn
+	t.Cleanup(func() {
+		linkedShowConvoyFn, linkedTrackedIssuesFn, linkedRigForBeadFn, linkedFindMRFn = origShow, origTracked, origRig, origMR
+	})
+
+	linkedShowConvoyFn = func(townBeads, id string) (*beads.Issue, error) {
+		desc := beads.SetConvoyFields(&beads.Issue{}, &beads.ConvoyFields{LinkedBranch: branch})
+		return &beads.Issue{ID: id, Title: "API v2", Type: "convoy", Description: desc}, nil
+	}
+	linkedTrackedIssuesFn = func(townBeads, convoyID string) ([]trackedIssueInfo, error) {
+		return tracked, nil
+	}
+	linkedRigForBeadFn = func(townRoot, beadID string) string {
+		return strings.SplitN(beadID, "-", 2)[0]
+	}
+	linkedFindMRFn = func(beadID string) (string, string, error) {
+		if id, ok := mrs[beadID]; ok {
+			return id, "polecat/" + beadID, nil
+		}
+		return "", "", nil
+	}
+}
+
+func TestLinkedBranchFor(t *testing.T) {
+	if got := linkedBranchFor("feat/api", "gt-a", false); got != "feat/api" {
+		t.Errorf("single bead in rig: got %q", got)
+	}
+	if got := linkedBranchFor("feat/api", "gt-a", true); got != "feat/api-gt-a" {
+		t.Errorf("shared rig: got %q", got)
+	}
+}
+
+func TestDefaultLinkedBranch(t *testing.T) {
+	tests := map[string]string{
+		"API v2":                  "linked/api-v2",
+		"  Fix: auth/SSO! ":       "linked/fix-auth-sso",
+		"!!!":                     "linked/change",
+		strings.Repeat("ab ", 30): "linked/" + strings.TrimRight(strings.Repeat("ab-", 14)[:40], "-"),
+	}
+	for title, want := range tests {
+		if got := defaultLinkedBranch(title); got != want {
+			t.Errorf("defaultLinkedBranch(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestLinkedConvoyReadiness_Waiting(t *testing.T) {
+	stubLinked(t, "feat/api-v2", []trackedIssueInfo{
+		{ID: "gt-a", Title: "Server", Status: "in_progress"},
+		{ID: "gt-b", Title: "Docs", Status: "closed"},
+		{ID: "cl-c", Title: "Client", Status: "in_progress"},
+	}, map[string]string{"gt-a": "gt-mr1"})
+
+	status, err := linkedConvoyReadiness("/town", "/town", "hq-cv-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Ready || len(status.Waiting) != 1 || status.Waiting[0] != "cl-c" {
+		t.Errorf("ready=%v waiting=%v, want waiting on cl-c", status.Ready, status.Waiting)
+	}
+	byID := map[string]linkedMember{}
+	for _, m := range status.Members {
+		byID[m.ID] = m
+	}
+	if m := byID["gt-a"]; !m.Ready || m.MR != "gt-mr1" || m.Branch != "polecat/gt-a" {
+		t.Errorf("gt-a = %+v, want ready with its MR's branch", m)
+	}
+	if m := byID["cl-c"]; m.Ready || m.Branch != "feat/api-v2" {
+		t.Errorf("cl-c = %+v, want unsuffixed shared branch", m)
+	}
+	if m := byID["gt-b"]; !m.Ready || m.Branch != "feat/api-v2-gt-b" {
+		t.Errorf("gt-b = %+v, want closed member ready on suffixed branch", m)
+	}
+}
+
+func TestLinkedConvoyReadiness_Ready(t *testing.T) {
+	stubLinked(t, "feat/api-v2", []trackedIssueInfo{
+		{ID: "gt-a", Status: "in_progress"},
+		{ID: "cl-c", Status: "in_progress"},
+	}, map[string]string{"gt-a": "gt-mr1", "cl-c": "cl-mr2"})
+
+	status, err := linkedConvoyReadiness("/town", "/town", "hq-cv-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Ready || len(status.Waiting) != 0 {
+		t.Errorf("ready=%v waiting=%v, want ready", status.Ready, status.Waiting)
+	}
+}
+
+func TestLinkedConvoyReadiness_NotLinked(t *testing.T) {
+	stubLinked(t, "", nil, nil)
+	if _, err := linkedConvoyReadiness("/town", "/town", "hq-cv-1"); err == nil || !strings.Contains(err.Error(), "not a linked convoy") {
+		t.Errorf("err = %v, want not-linked error", err)
+	}
+}
+
+func TestLinkedConvoyReadiness_MRLookupFails(t *testing.T) {
+	stubLinked(t, "feat/x", []trackedIssueInfo{{ID: "gt-a", Status: "open"}}, nil)
+	linkedFindMRFn = func(string) (string, string, error) { return "", "", errors.New("bd down") }
+	if _, err := linkedConvoyReadiness("/town", "/town", "hq-cv-1"); err == nil {
+		t.Error("expected error when MR lookup fails")
+	}
+}
@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	convoyops "github.com/steveyegge/gastown/internal/convoy"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var convoyAfterRemove bool
+
+var convoyPhaseCmd = &cobra.Command{
+	Use:   "phase <convoy-id> <phase> <issue-id> [issue-id...]",
+	Short: "Assign tracked issues to a dispatch phase",
+	Long: `Assign tracked issues to a phase of the convoy.
+
+Phases are ordered waves: nothing in phase 2 is dispatched until every issue
+in phase 1 is closed (for the mr strategy, the refinery closes an issue when
+it merges). Unphased issues dispatch as soon as they are ready. Phase 0
+removes an issue from its phase.
+
+The daemon and deacon honour phases when feeding convoys; gt convoy status
+and gt convoy stranded show what a convoy is waiting on.
+
+Examples:
+  gt convoy phase hq-cv-abc 1 gt-schema gt-backfill
+  gt convoy phase hq-cv-abc 2 gt-api gt-ui
+  gt convoy phase hq-cv-abc 0 gt-ui         # Unphase`,
+	Args:         cobra.MinimumNArgs(3),
+	SilenceUsage: true,
+	RunE:         runConvoyPhase,
+}
+
+var convoyAfterCmd = &cobra.Command{
+	Use:   "after <convoy-id> <prerequisite-convoy> [prerequisite-convoy...]",
+	Short: "Hold a convoy until other convoys close",
+	Long: `Declare that a convoy depends on other convoys: none of its issues are
+dispatched until every prerequisite convoy has closed.
+
+Use this for large migrations split into ordered convoys.
+
+Examples:
+  gt convoy after hq-cv-api hq-cv-schema
+  gt convoy after hq-cv-api hq-cv-schema --remove`,
+	Args:         cobra.MinimumNArgs(2),
+	SilenceUsage: true,
+	RunE:         runConvoyAfter,
+}
+
+func init() {
+	convoyAfterCmd.Flags().BoolVar(&convoyAfterRemove, "remove", false, "Remove the given prerequisites instead of adding them")
+
+	convoyCmd.AddCommand(convoyPhaseCmd)
+	convoyCmd.AddCommand(convoyAfterCmd)
+}
+
+// newConvoyGate evaluates a convoy's after:/phase_N: ordering against the
+// tracked issues' status and the status of prerequisite convoys.
+func newConvoyGate(townBeads string, fields *beads.ConvoyFields, tracked []trackedIssueInfo) *convoyops.Gate {
+	statusByID := make(map[string]string, len(tracked))
+	for _, t := range tracked {
+		statusByID[t.ID] = t.Status
+	}
+	return convoyops.NewGate(fields, func(id string) string { return statusByID[id] }, convoyStatusLookup(townBeads))
+}
+
+// convoyStatusLookup returns a func that fetches a convoy's status from town
+// beads, or "" if it can't be read.
+func convoyStatusLookup(townBeads string) func(string) string {
+	return func(id string) string {
+		out, err := BdCmd("show", id, "--json").
+			Dir(townBeads).
+			Stderr(io.Discard).
+			Output()
+		if err != nil {
+			return ""
+		}
+		var issues []struct {
+			Status string `json:"status"`
+		}
+		if err := json.Unmarshal(out, &issues); err != nil || len(issues) == 0 {
+			return ""
+		}
+		return issues[0].Status
+	}
+}
+
+// updateConvoyFields rewrites a convoy's description fields in town beads.
+func updateConvoyFields(townBeads string, convoy *beads.Issue, fields *beads.ConvoyFields) error {
+	if err := BdCmd("update", convoy.ID, "--description="+beads.SetConvoyFields(convoy, fields)).
+		Dir(townBeads).
+		WithAutoCommit().
+		Run(); err != nil {
+		return fmt.Errorf("updating convoy %s: %w", convoy.ID, err)
+	}
+	return nil
+}
+
+func runConvoyPhase(cmd *cobra.Command, args []string) error {
+	convoyID := args[0]
+	phase, err := strconv.Atoi(args[1])
+	if err != nil || phase < 0 || phase > beads.MaxConvoyPhase {
+		return fmt.Errorf("invalid phase %q: must be a number up to %d (0 to unphase)", args[1], beads.MaxConvoyPhase)
+	}
+	issueIDs := dedupeStrings(args[2:])
+
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	convoy, err := loadConvoy(townBeads, convoyID)
+	if err != nil {
+		return err
+	}
+
+	tracked, err := trackedIDs(townBeads, convoyID)
+	if err != nil {
+		return err
+	}
+	trackedSet := make(map[string]bool, len(tracked))
+	for _, id := range tracked {
+		trackedSet[id] = true
+	}
+	var notTracked []string
+	for _, id := range issueIDs {
+		if !trackedSet[id] {
+			notTracked = append(notTracked, id)
+		}
+	}
+	if len(notTracked) > 0 {
+		return fmt.Errorf("not tracked by %s: %s (add them with gt convoy add)", convoyID, strings.Join(notTracked, ", "))
+	}
+
+	fields := beads.ParseConvoyFields(convoy)
+	if fields == nil {
+		fields = &beads.ConvoyFields{}
+	}
+	fields.SetPhase(phase, issueIDs...)
+	if err := updateConvoyFields(townBeads, convoy, fields); err != nil {
+		return err
+	}
+
+	if phase == 0 {
+		fmt.Printf("%s Unphased %s in 🚚 %s\n", style.Bold.Render("✓"), strings.Join(issueIDs, ", "), convoyID)
+	} else {
+		fmt.Printf("%s Phase %d of 🚚 %s: %s\n", style.Bold.Render("✓"), phase, convoyID, strings.Join(fields.Phases[phase-1], ", "))
+	}
+	return nil
+}
+
+func runConvoyAfter(cmd *cobra.Command, args []string) error {
+	convoyID := args[0]
+	prereqs := dedupeStrings(args[1:])
+
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	convoy, err := loadConvoy(townBeads, convoyID)
+	if err != nil {
+		return err
+	}
+	fields := beads.ParseConvoyFields(convoy)
+	if fields == nil {
+		fields = &beads.ConvoyFields{}
+	}
+
+	if convoyAfterRemove {
+		drop := make(map[string]bool, len(prereqs))
+		for _, id := range prereqs {
+			drop[id] = true
+		}
+		var kept []string
+		for _, id := range fields.After {
+			if !drop[id] {
+				kept = append(kept, id)
+			}
+		}
+		fields.After = kept
+	} else {
+		for _, id := range prereqs {
+			if id == convoyID {
+				return fmt.Errorf("convoy %s cannot wait on itself", convoyID)
+			}
+			if _, err := loadConvoy(townBeads, id); err != nil {
+				return err
+			}
+			if path := convoyAfterPath(townBeads, id, convoyID); path != nil {
+				return fmt.Errorf("cycle: %s already waits on %s (%s)", id, convoyID, strings.Join(path, " → "))
+			}
+		}
+		fields.After = dedupeStrings(append(fields.After, prereqs...))
+	}
+
+	if err := updateConvoyFields(townBeads, convoy, fields); err != nil {
+		return err
+	}
+	if len(fields.After) == 0 {
+		fmt.Printf("%s 🚚 %s no longer waits on other convoys\n", style.Bold.Render("✓"), convoyID)
+	} else {
+		fmt.Printf("%s 🚚 %s waits on: %s\n", style.Bold.Render("✓"), convoyID, strings.Join(fields.After, ", "))
+	}
+	return nil
+}
+
+// convoyAfterPath returns the after: chain from one convoy to target, or nil
+// if from does not (transitively) wait on target.
+func convoyAfterPath(townBeads, from, target string) []string {
+	visited := map[string]bool{}
+	var walk func(id string) []string
+	walk = func(id string) []string {
+		if id == target {
+			return []string{id}
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+		convoy, err := loadConvoy(townBeads, id)
+		if err != nil {
+			return nil
+		}
+		fields := beads.ParseConvoyFields(convoy)
+		if fields == nil {
+			return nil
+		}
+		for _, next := range fields.After {
+			if path := walk(next); path != nil {
+				return append([]string{id}, path...)
+			}
+		}
+		return nil
+	}
+	return walk(from)
+}
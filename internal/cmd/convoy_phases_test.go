@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestConvoyPhase_UpdatesDescription(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+
+	dag := newTestDAG(t).
+		Convoy("hq-cv-mig", "Migration").WithDescription("Convoy tracking 3 issues\nMerge: mr\nphase_1: gt-a").
+		Task("gt-a", "Schema", "gastown").TrackedBy("hq-cv-mig").
+		Task("gt-b", "Backfill", "gastown").TrackedBy("hq-cv-mig").
+		Task("gt-c", "Cutover", "gastown").TrackedBy("hq-cv-mig")
+	_, logPath := dag.Setup(t)
+
+	if err := runConvoyPhase(convoyPhaseCmd, []string{"hq-cv-mig", "2", "gt-b", "gt-c"}); err != nil {
+		t.Fatalf("runConvoyPhase: %v", err)
+	}
+	log := readBdLog(t, logPath)
+	if !strings.Contains(log, "CMD:update hq-cv-mig --description=Convoy tracking 3 issues\nMerge: mr\nphase_1: gt-a\nphase_2: gt-b, gt-c") {
+		t.Errorf("expected phases written to the convoy description, got:\n%s", log)
+	}
+}
+
+func TestConvoyPhase_Validation(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+
+	dag := newTestDAG(t).
+		Convoy("hq-cv-mig", "Migration").
+		Task("gt-a", "Schema", "gastown").TrackedBy("hq-cv-mig")
+	_, logPath := dag.Setup(t)
+
+	if err := runConvoyPhase(convoyPhaseCmd, []string{"hq-cv-mig", "two", "gt-a"}); err == nil || !strings.Contains(err.Error(), "invalid phase") {
+		t.Errorf("non-numeric phase: err = %v", err)
+	}
+	if err := runConvoyPhase(convoyPhaseCmd, []string{"hq-cv-mig", "1000000000", "gt-a"}); err == nil || !strings.Contains(err.Error(), "invalid phase") {
+		t.Errorf("out-of-range phase: err = %v", err)
+	}
+	if err := runConvoyPhase(convoyPhaseCmd, []string{"hq-cv-mig", "1", "gt-zzz"}); err == nil || !strings.Contains(err.Error(), "not tracked by hq-cv-mig: gt-zzz") {
+		t.Errorf("untracked issue: err = %v", err)
+	}
+	if beadsModified(readBdLog(t, logPath)) {
+		t.Errorf("validation failures should not modify beads")
+	}
+}
+
+func TestConvoyAfter_AddsPrerequisite(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+	convoyAfterRemove = false
+	t.Cleanup(func() { convoyAfterRemove = false })
+
+	dag := newTestDAG(t).
+		Convoy("hq-cv-schema", "Schema").
+		Convoy("hq-cv-api", "API").WithDescription("Convoy tracking 2 issues")
+	_, logPath := dag.Setup(t)
+
+	if err := runConvoyAfter(convoyAfterCmd, []string{"hq-cv-api", "hq-cv-schema"}); err != nil {
+		t.Fatalf("runConvoyAfter: %v", err)
+	}
+	if log := readBdLog(t, logPath); !strings.Contains(log, "CMD:update hq-cv-api --description=Convoy tracking 2 issues\nafter: hq-cv-schema") {
+		t.Errorf("expected after: field written, got:\n%s", log)
+	}
+}
+
+func TestConvoyAfter_RejectsCycle(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+	convoyAfterRemove = false
+
+	dag := newTestDAG(t).
+		Convoy("hq-cv-a", "A").WithDescription("after: hq-cv-b").
+		Convoy("hq-cv-b", "B").WithDescription("after: hq-cv-c").
+		Convoy("hq-cv-c", "C")
+	_, logPath := dag.Setup(t)
+
+	err := runConvoyAfter(convoyAfterCmd, []string{"hq-cv-c", "hq-cv-a"})
+	if err == nil || !strings.Contains(err.Error(), "hq-cv-a → hq-cv-b → hq-cv-c") {
+		t.Fatalf("err = %v, want cycle through hq-cv-a → hq-cv-b → hq-cv-c", err)
+	}
+	if beadsModified(readBdLog(t, logPath)) {
+		t.Errorf("rejected cycle should not modify beads")
+	}
+
+	if err := runConvoyAfter(convoyAfterCmd, []string{"hq-cv-c", "hq-cv-c"}); err == nil || !strings.Contains(err.Error(), "itself") {
+		t.Errorf("self dependency: err = %v", err)
+	}
+}
+
+func TestConvoyAfter_Remove(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+	convoyAfterRemove = true
+	t.Cleanup(func() { convoyAfterRemove = false })
+
+	dag := newTestDAG(t).
+		Convoy("hq-cv-api", "API").WithDescription("Owner: mayor/\nafter: hq-cv-schema, hq-cv-auth")
+	_, logPath := dag.Setup(t)
+
+	if err := runConvoyAfter(convoyAfterCmd, []string{"hq-cv-api", "hq-cv-schema"}); err != nil {
+		t.Fatalf("runConvoyAfter --remove: %v", err)
+	}
+	if log := readBdLog(t, logPath); !strings.Contains(log, "--description=Owner: mayor/\nafter: hq-cv-auth\n") {
+		t.Errorf("expected only hq-cv-auth left, got:\n%s", log)
+	}
+}
+
+func TestFindStrandedConvoys_HoldsLaterPhases(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+
+	dag := newTestDAG(t).
+		Convoy("hq-cv-mig", "Migration").WithDescription("phase_1: gt-a\nphase_2: gt-b").
+		Task("gt-a", "Schema", "gastown").TrackedBy("hq-cv-mig").WithStatus("closed").
+		Task("gt-b", "Cutover", "gastown").TrackedBy("hq-cv-mig").
+		Task("gt-c", "Docs", "gastown").TrackedBy("hq-cv-mig")
+	townRoot, _ := dag.Setup(t)
+
+	stranded, err := findStrandedConvoys(filepath.Join(townRoot, ".beads"))
+	if err != nil {
+		t.Fatalf("findStrandedConvoys: %v", err)
+	}
+	if len(stranded) != 1 {
+		t.Fatalf("expected 1 stranded convoy, got %+v", stranded)
+	}
+	got := strings.Join(stranded[0].ReadyIssues, ",")
+	if got != "gt-b,gt-c" && got != "gt-c,gt-b" {
+		t.Errorf("phase 1 is closed, so phase 2 and unphased issues should be ready; got %q", got)
+	}
+}
+
+func TestFindStrandedConvoys_HoldsPhaseUntilEarlierCloses(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+
+	dag := newTestDAG(t).
+		Convoy("hq-cv-mig", "Migration").WithDescription("phase_1: gt-a\nphase_2: gt-b").
+		Task("gt-a", "Schema", "gastown").TrackedBy("hq-cv-mig").WithStatus("in_progress").
+		Task("gt-b", "Cutover", "gastown").TrackedBy("hq-cv-mig")
+	townRoot, _ := dag.Setup(t)
+
+	stranded, err := findStrandedConvoys(filepath.Join(townRoot, ".beads"))
+	if err != nil {
+		t.Fatalf("findStrandedConvoys: %v", err)
+	}
+	if len(stranded) != 1 {
+		t.Fatalf("expected 1 stranded convoy, got %+v", stranded)
+	}
+	// gt-a is in progress with no assignee, which the scan treats as
+	// orphaned and ready; gt-b is phase 2 and must be held.
+	if got := strings.Join(stranded[0].ReadyIssues, ","); got != "gt-a" {
+		t.Errorf("ReadyIssues = %q, want only the phase 1 issue", got)
+	}
+}
+
+func TestFindStrandedConvoys_WaitingOnConvoy(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+
+	dag := newTestDAG(t).
+		Convoy("hq-cv-schema", "Schema").
+		Task("gt-a", "Schema", "gastown").TrackedBy("hq-cv-schema").WithStatus("in_progress").
+		Convoy("hq-cv-api", "API").WithDescription("after: hq-cv-schema").
+		Task("gt-b", "Endpoints", "gastown").TrackedBy("hq-cv-api")
+	townRoot, _ := dag.Setup(t)
+
+	stranded, err := findStrandedConvoys(filepath.Join(townRoot, ".beads"))
+	if err != nil {
+		t.Fatalf("findStrandedConvoys: %v", err)
+	}
+	for _, s := range stranded {
+		if s.ID != "hq-cv-api" {
+			continue
+		}
+		if s.ReadyCount != 0 || s.WaitingOn != "convoy hq-cv-schema" {
+			t.Errorf("hq-cv-api should wait on hq-cv-schema, got %+v", s)
+		}
+		return
+	}
+	t.Fatalf("hq-cv-api missing from stranded list: %+v", stranded)
+}
@@ -0,0 +1,514 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Split/merge flags
+var (
+	convoySplitName   string
+	convoySplitInto   string
+	convoySplitOwner  string
+	convoySplitNotify string
+	convoySplitMerge  string
+	convoySplitBase   string
+	convoySplitDryRun bool
+	convoyMergeDryRun bool
+)
+
+var convoySplitCmd = &cobra.Command{
+	Use:   "split <convoy-id> <issue-id> [issue-id...]",
+	Short: "Move selected issues out of a convoy into a new one",
+	Long: `Split an in-flight convoy by moving selected tracked issues to a new convoy.
+
+The new convoy inherits the source's owner, notify address, merge strategy,
+base branch, and owned label unless overridden. Use --into to move the
+issues to an existing convoy instead of creating one.
+
+Moved issues keep their status and workers. Slung issues have their
+convoy_id and merge_strategy updated so gt done follows the new convoy.
+Both convoys get a comment recording the move.
+
+Issues may live on any rig: convoys are town-level and track across rigs.
+
+Examples:
+  gt convoy split hq-cv-abc gt-ui1 gt-ui2 --name "UI polish"
+  gt convoy split hq-cv-abc gt-later --merge=local
+  gt convoy split hq-cv-abc bd-x --into hq-cv-xyz
+  gt convoy split hq-cv-abc gt-ui1 --dry-run`,
+	Args:         cobra.MinimumNArgs(2),
+	SilenceUsage: true,
+	RunE:         runConvoySplit,
+}
+
+var convoyMergeCmd = &cobra.Command{
+	Use:   "merge <target-convoy> <source-convoy> [source-convoy...]",
+	Short: "Merge convoys into one",
+	Long: `Move every issue tracked by the source convoys into the target convoy, then
+close the sources with a pointer to the target.
+
+The target keeps its own owner, notify address, and merge strategy; slung
+issues from the sources are re-pointed at it. Moved issues keep their phase
+number unless the target already phases them; the sources' after:
+prerequisites are not carried over. A closed target is reopened. Both sides
+get a comment recording the merge.
+
+Examples:
+  gt convoy merge hq-cv-abc hq-cv-def
+  gt convoy merge hq-cv-abc hq-cv-def hq-cv-ghi --dry-run`,
+	Args:         cobra.MinimumNArgs(2),
+	SilenceUsage: true,
+	RunE:         runConvoyMerge,
+}
+
+func init() {
+	convoySplitCmd.Flags().StringVar(&convoySplitName, "name", "", "Name for the new convoy (default: \"<source title> (split)\")")
+	convoySplitCmd.Flags().StringVar(&convoySplitInto, "into", "", "Move into an existing convoy instead of creating one")
+	convoySplitCmd.Flags().StringVar(&convoySplitOwner, "owner", "", "Owner of the new convoy (default: source owner)")
+	convoySplitCmd.Flags().StringVar(&convoySplitNotify, "notify", "", "Notify address for the new convoy (default: source notify)")
+	convoySplitCmd.Flags().StringVar(&convoySplitMerge, "merge", "", "Merge strategy for the new convoy: direct, mr, local (default: source strategy)")
+	convoySplitCmd.Flags().StringVar(&convoySplitBase, "base-branch", "", "Target branch for the new convoy (default: source base branch)")
+	convoySplitCmd.Flags().BoolVar(&convoySplitDryRun, "dry-run", false, "Show what would move without acting")
+
+	convoyMergeCmd.Flags().BoolVar(&convoyMergeDryRun, "dry-run", false, "Show what would move without acting")
+
+	convoyCmd.AddCommand(convoySplitCmd)
+	convoyCmd.AddCommand(convoyMergeCmd)
+}
+
+// loadConvoy fetches a convoy bead from town beads and checks its type.
+func loadConvoy(townBeads, convoyID string) (*beads.Issue, error) {
+	out, err := BdCmd("show", convoyID, "--json").
+		Dir(townBeads).
+		Stderr(io.Discard).
+		Output()
+	if err != nil {
+		return nil, fmt.Errorf("convoy '%s' not found", convoyID)
+	}
+	var issues []beads.Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, fmt.Errorf("parsing convoy data: %w", err)
+	}
+	if len(issues) == 0 {
+		return nil, fmt.Errorf("convoy '%s' not found", convoyID)
+	}
+	convoy := &issues[0]
+	if convoy.Type != "convoy" {
+		return nil, fmt.Errorf("'%s' is not a convoy (type: %s)", convoyID, convoy.Type)
+	}
+	if err := ensureKnownConvoyStatus(convoy.Status); err != nil {
+		return nil, fmt.Errorf("convoy '%s' has invalid lifecycle state: %w", convoyID, err)
+	}
+	return convoy, nil
+}
+
+// trackedIDs returns the IDs a convoy tracks, with the same show fallback
+// getTrackedIssues uses for cross-database deps.
+func trackedIDs(townBeads, convoyID string) ([]string, error) {
+	ids, err := bdDepListTracked(townBeads, convoyID)
+	if err != nil {
+		return nil, fmt.Errorf("querying tracked issues for %s: %w", convoyID, err)
+	}
+	if len(ids) == 0 {
+		ids, err = bdShowTrackedDeps(townBeads, convoyID)
+		if err != nil {
+			return nil, fmt.Errorf("fallback show for tracked deps of %s: %w", convoyID, err)
+		}
+	}
+	return ids, nil
+}
+
+// moveTracked re-homes one issue from one convoy to another: adds the new
+// tracks relation before removing the old one, so a failure part-way never
+// leaves the issue untracked.
+func moveTracked(townBeads, fromID, toID, issueID string) error {
+	var stderr bytes.Buffer
+	if err := BdCmd("dep", "add", toID, issueID, "--type=tracks").
+		Dir(townBeads).
+		WithAutoCommit().
+		StripBeadsDir().
+		Stderr(&stderr).
+		Run(); err != nil {
+		return fmt.Errorf("tracking %s in %s: %s", issueID, toID, bdErrMsg(err, &stderr))
+	}
+	stderr.Reset()
+	if err := BdCmd("dep", "remove", fromID, issueID, "--type=tracks").
+		Dir(townBeads).
+		WithAutoCommit().
+		StripBeadsDir().
+		Stderr(&stderr).
+		Run(); err != nil {
+		return fmt.Errorf("untracking %s from %s: %s", issueID, fromID, bdErrMsg(err, &stderr))
+	}
+	return nil
+}
+
+func bdErrMsg(err error, stderr *bytes.Buffer) string {
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return msg
+	}
+	return err.Error()
+}
+
+// repointIssueConvoy updates a slung issue's convoy attachment fields so gt
+// done follows the new convoy's merge strategy. Issues that were never slung
+// under fromID (no convoy_id, or a different one) are left alone.
+func repointIssueConvoy(issueID, fromID, toID string, to *beads.ConvoyFields, toOwned bool) error {
+	dir := resolveBeadDir(issueID)
+	out, err := BdCmd("show", issueID, "--json").
+		Dir(dir).
+		StripBeadsDir().
+		Stderr(io.Discard).
+		Output()
+	if err != nil {
+		return fmt.Errorf("fetching %s: %w", issueID, err)
+	}
+	var issues []beads.Issue
+	if err := json.Unmarshal(out, &issues); err != nil || len(issues) == 0 {
+		return fmt.Errorf("parsing %s", issueID)
+	}
+	issue := &issues[0]
+	fields := beads.ParseAttachmentFields(issue)
+	if fields == nil || fields.ConvoyID != fromID {
+		return nil
+	}
+	fields.ConvoyID = toID
+	fields.MergeStrategy = ""
+	if to != nil {
+		fields.MergeStrategy = to.Merge
+	}
+	fields.ConvoyOwned = toOwned
+	return BdCmd("update", issueID, "--description="+beads.SetAttachmentFields(issue, fields)).
+		Dir(dir).
+		StripBeadsDir().
+		Run()
+}
+
+// commentOnConvoy records a history entry on a convoy. Best-effort.
+func commentOnConvoy(townBeads, convoyID, text string) {
+	if err := BdCmd("comments", "add", convoyID, text).
+		Dir(townBeads).
+		WithAutoCommit().
+		Stderr(io.Discard).
+		Run(); err != nil {
+		style.PrintWarning("couldn't record history on %s: %v", convoyID, err)
+	}
+}
+
+func runConvoySplit(cmd *cobra.Command, args []string) error {
+	sourceID := args[0]
+	toMove := dedupeStrings(args[1:])
+
+	if convoySplitMerge != "" {
+		switch convoySplitMerge {
+		case "direct", "mr", "local":
+		default:
+			return fmt.Errorf("invalid --merge value %q: must be direct, mr, or local", convoySplitMerge)
+		}
+	}
+	if convoySplitInto != "" && (convoySplitName != "" || convoySplitOwner != "" || convoySplitNotify != "" || convoySplitMerge != "" || convoySplitBase != "") {
+		return fmt.Errorf("--into moves issues to an existing convoy; --name, --owner, --notify, --merge, and --base-branch only apply to a new one")
+	}
+	if convoySplitInto == sourceID {
+		return fmt.Errorf("cannot split %s into itself", sourceID)
+	}
+
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	source, err := loadConvoy(townBeads, sourceID)
+	if err != nil {
+		return err
+	}
+	if normalizeConvoyStatus(source.Status) == convoyStatusClosed {
+		return fmt.Errorf("convoy %s is closed; reopen it with gt convoy add before splitting", sourceID)
+	}
+
+	tracked, err := trackedIDs(townBeads, sourceID)
+	if err != nil {
+		return err
+	}
+	trackedSet := make(map[string]bool, len(tracked))
+	for _, id := range tracked {
+		trackedSet[id] = true
+	}
+	var notTracked []string
+	for _, id := range toMove {
+		if !trackedSet[id] {
+			notTracked = append(notTracked, id)
+		}
+	}
+	if len(notTracked) > 0 {
+		return fmt.Errorf("not tracked by %s: %s", sourceID, strings.Join(notTracked, ", "))
+	}
+	if len(toMove) == len(tracked) {
+		return fmt.Errorf("that would move every issue out of %s; use gt convoy merge to combine convoys", sourceID)
+	}
+
+	sourceFields := beads.ParseConvoyFields(source)
+	if sourceFields == nil {
+		sourceFields = &beads.ConvoyFields{}
+	}
+
+	// Resolve the destination: an existing convoy, or the fields for a new one.
+	var destID, destTitle string
+	var destFields *beads.ConvoyFields
+	var destOwned bool
+	if convoySplitInto != "" {
+		dest, err := loadConvoy(townBeads, convoySplitInto)
+		if err != nil {
+			return err
+		}
+		destID, destTitle = dest.ID, dest.Title
+		destFields = beads.ParseConvoyFields(dest)
+		destOwned = hasLabel(dest.Labels, "gt:owned")
+	} else {
+		destTitle = convoySplitName
+		if destTitle == "" {
+			destTitle = source.Title + " (split)"
+		}
+		if beads.IsFlagLikeTitle(destTitle) {
+			return fmt.Errorf("refusing to create convoy: name %q looks like a CLI flag", destTitle)
+		}
+		destFields = &beads.ConvoyFields{
+			Owner:      firstNonEmpty(convoySplitOwner, sourceFields.Owner),
+			Notify:     firstNonEmpty(convoySplitNotify, sourceFields.Notify),
+			Merge:      firstNonEmpty(convoySplitMerge, sourceFields.Merge),
+			Molecule:   sourceFields.Molecule,
+			BaseBranch: firstNonEmpty(convoySplitBase, sourceFields.BaseBranch),
+		}
+		destOwned = hasLabel(source.Labels, "gt:owned")
+	}
+
+	if convoySplitDryRun {
+		target := destID
+		if target == "" {
+			target = fmt.Sprintf("new convoy %q", destTitle)
+		}
+		fmt.Printf("Would move %d of %d issue(s) from %s to %s:\n", len(toMove), len(tracked), sourceID, target)
+		for _, id := range toMove {
+			fmt.Printf("  %s\n", id)
+		}
+		return nil
+	}
+
+	if destID == "" {
+		destID = fmt.Sprintf("hq-cv-%s", generateShortID())
+		description := fmt.Sprintf("Convoy tracking %d issues\nSplit from %s on %s",
+			len(toMove), sourceID, time.Now().UTC().Format("2006-01-02"))
+		description = beads.SetConvoyFields(&beads.Issue{Description: description}, destFields)
+		createArgs := []string{
+			"create",
+			"--type=convoy",
+			"--id=" + destID,
+			"--title=" + destTitle,
+			"--description=" + description,
+			"--json",
+		}
+		if destOwned {
+			createArgs = append(createArgs, "--labels=gt:owned")
+		}
+		if beads.NeedsForceForID(destID) {
+			createArgs = append(createArgs, "--force")
+		}
+		var stderr bytes.Buffer
+		if err := BdCmd(createArgs...).
+			WithAutoCommit().
+			Dir(townBeads).
+			Stderr(&stderr).
+			Run(); err != nil {
+			return fmt.Errorf("creating convoy: %w (%s)", err, strings.TrimSpace(stderr.String()))
+		}
+		fmt.Printf("%s Created convoy 🚚 %s (%s)\n", style.Bold.Render("✓"), destID, destTitle)
+	}
+
+	moved := moveConvoyIssues(townBeads, sourceID, destID, toMove, destFields, destOwned)
+	if len(moved) > 0 {
+		commentOnConvoy(townBeads, sourceID, fmt.Sprintf("Split: moved %s to %s", strings.Join(moved, ", "), destID))
+		commentOnConvoy(townBeads, destID, fmt.Sprintf("Split from %s: %s", sourceID, strings.Join(moved, ", ")))
+	}
+
+	fmt.Printf("%s Moved %d issue(s) from %s to 🚚 %s\n", style.Bold.Render("✓"), len(moved), sourceID, destID)
+	if len(moved) > 0 {
+		fmt.Printf("  Issues: %s\n", strings.Join(moved, ", "))
+	}
+	if len(moved) < len(toMove) {
+		return fmt.Errorf("%d issue(s) could not be moved", len(toMove)-len(moved))
+	}
+	return nil
+}
+
+func runConvoyMerge(cmd *cobra.Command, args []string) error {
+	targetID := args[0]
+	sourceIDs := dedupeStrings(args[1:])
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return fmt.Errorf("cannot merge %s into itself", targetID)
+		}
+	}
+
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+	target, err := loadConvoy(townBeads, targetID)
+	if err != nil {
+		return err
+	}
+	targetFields := beads.ParseConvoyFields(target)
+	targetOwned := hasLabel(target.Labels, "gt:owned")
+	targetMerge, targetBase := "", ""
+	if targetFields != nil {
+		targetMerge, targetBase = targetFields.Merge, targetFields.BaseBranch
+	}
+
+	type mergeSource struct {
+		convoy *beads.Issue
+		fields *beads.ConvoyFields
+		ids    []string
+	}
+	var sources []mergeSource
+	total := 0
+	for _, id := range sourceIDs {
+		src, err := loadConvoy(townBeads, id)
+		if err != nil {
+			return err
+		}
+		ids, err := trackedIDs(townBeads, id)
+		if err != nil {
+			return err
+		}
+		f := beads.ParseConvoyFields(src)
+		if f != nil {
+			if f.Merge != targetMerge {
+				style.PrintWarning("%s uses merge strategy %q; its issues will follow %s's %q", id, orDefaultMerge(f.Merge), targetID, orDefaultMerge(targetMerge))
+			}
+			if f.BaseBranch != targetBase {
+				style.PrintWarning("%s targets base branch %q but %s targets %q", id, f.BaseBranch, targetID, targetBase)
+			}
+		}
+		sources = append(sources, mergeSource{convoy: src, fields: f, ids: ids})
+		total += len(ids)
+	}
+
+	if convoyMergeDryRun {
+		fmt.Printf("Would merge %d convoy(s) into %s (%d issue(s)):\n", len(sources), targetID, total)
+		for _, s := range sources {
+			fmt.Printf("  %s %s: %s\n", s.convoy.ID, s.convoy.Title, strings.Join(s.ids, ", "))
+		}
+		return nil
+	}
+
+	if normalizeConvoyStatus(target.Status) == convoyStatusClosed && total > 0 {
+		if err := BdCmd("update", targetID, "--status=open").
+			Dir(townBeads).
+			WithAutoCommit().
+			Run(); err != nil {
+			return fmt.Errorf("couldn't reopen convoy: %w", err)
+		}
+		fmt.Printf("%s Reopened convoy %s\n", style.Bold.Render("↺"), targetID)
+	}
+
+	phases := targetFields
+	if phases == nil {
+		phases = &beads.ConvoyFields{}
+	}
+	phased := false
+	failed := 0
+	for _, s := range sources {
+		srcID := s.convoy.ID
+		moved := moveConvoyIssues(townBeads, srcID, targetID, s.ids, targetFields, targetOwned)
+		for _, id := range moved {
+			if n := s.fields.PhaseOf(id); n > 0 && phases.PhaseOf(id) == 0 {
+				phases.SetPhase(n, id)
+				phased = true
+			}
+		}
+		if len(moved) > 0 {
+			commentOnConvoy(townBeads, targetID, fmt.Sprintf("Merged from %s (%s): %s", srcID, s.convoy.Title, strings.Join(moved, ", ")))
+		}
+		if len(moved) < len(s.ids) {
+			failed += len(s.ids) - len(moved)
+			style.PrintWarning("%s left open: %d issue(s) could not be moved", srcID, len(s.ids)-len(moved))
+			continue
+		}
+
+		reason := "Merged into " + targetID
+		commentOnConvoy(townBeads, srcID, fmt.Sprintf("%s: %d issue(s) moved", reason, len(moved)))
+		if normalizeConvoyStatus(s.convoy.Status) != convoyStatusClosed {
+			if err := BdCmd("close", srcID, "-r", reason).
+				Dir(townBeads).
+				WithAutoCommit().
+				Run(); err != nil {
+				style.PrintWarning("couldn't close %s: %v", srcID, err)
+			}
+		}
+		fmt.Printf("%s Merged %s into 🚚 %s (%d issue(s))\n", style.Bold.Render("✓"), srcID, targetID, len(moved))
+	}
+
+	if phased {
+		if err := updateConvoyFields(townBeads, target, phases); err != nil {
+			style.PrintWarning("couldn't carry phases over: %v", err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d issue(s) could not be moved", failed)
+	}
+	return nil
+}
+
+// moveConvoyIssues moves each issue and re-points slung ones, returning the
+// IDs that moved. Failures are reported per issue.
+func moveConvoyIssues(townBeads, fromID, toID string, ids []string, toFields *beads.ConvoyFields, toOwned bool) []string {
+	var moved []string
+	for _, id := range ids {
+		if err := moveTracked(townBeads, fromID, toID, id); err != nil {
+			style.PrintWarning("couldn't move %s: %v", id, err)
+			continue
+		}
+		if err := repointIssueConvoy(id, fromID, toID, toFields, toOwned); err != nil {
+			style.PrintWarning("moved %s but couldn't update its convoy fields: %v", id, err)
+		}
+		moved = append(moved, id)
+	}
+	return moved
+}
+
+func orDefaultMerge(strategy string) string {
+	if strategy == "" {
+		return "mr"
+	}
+	return strategy
+}
+
+func dedupeStrings(in []string) []string {
+	seen := make(map[string]bool, len(in))
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func resetConvoyReshapeFlags(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		convoySplitName, convoySplitInto = "", ""
+		convoySplitOwner, convoySplitNotify = "", ""
+		convoySplitMerge, convoySplitBase = "", ""
+		convoySplitDryRun, convoyMergeDryRun = false, false
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func readBdLog(t *testing.T, logPath string) string {
+	t.Helper()
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("read bd.log: %v", err)
+	}
+	return string(data)
+}
+
+// beadsModified reports whether the bd log contains any write the reshape
+// commands perform.
+func beadsModified(log string) bool {
+	for _, w := range []string{"CMD:dep add", "CMD:dep remove", "CMD:create", "CMD:close", "CMD:update"} {
+		if strings.Contains(log, w) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestConvoySplit_MovesSelectedIssues(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+	resetConvoyReshapeFlags(t)
+
+	dag := newTestDAG(t).
+		Convoy("hq-cv-src", "Release").
+		Task("gt-a", "Parser", "gastown").TrackedBy("hq-cv-src").
+		Task("gt-b", "UI", "gastown").TrackedBy("hq-cv-src").
+		Task("bd-c", "Docs", "beads").TrackedBy("hq-cv-src")
+	_, logPath := dag.Setup(t)
+
+	convoySplitName = "UI polish"
+	if err := runConvoySplit(convoySplitCmd, []string{"hq-cv-src", "gt-b", "bd-c"}); err != nil {
+		t.Fatalf("runConvoySplit: %v", err)
+	}
+
+	log := readBdLog(t, logPath)
+	if !strings.Contains(log, "CMD:create --type=convoy --id=hq-cv-") || !strings.Contains(log, "--title=UI polish") {
+		t.Errorf("expected new convoy to be created, got:\n%s", log)
+	}
+	if !strings.Contains(log, "Split from hq-cv-src") {
+		t.Errorf("expected new convoy description to record its origin, got:\n%s", log)
+	}
+	for _, id := range []string{"gt-b", "bd-c"} {
+		if !strings.Contains(log, "CMD:dep remove hq-cv-src "+id+" --type=tracks") {
+			t.Errorf("expected %s untracked from source, got:\n%s", id, log)
+		}
+	}
+	if strings.Contains(log, "dep remove hq-cv-src gt-a") {
+		t.Errorf("gt-a should stay in the source convoy, got:\n%s", log)
+	}
+	if !strings.Contains(log, "CMD:comments add hq-cv-src Split: moved gt-b, bd-c to hq-cv-") {
+		t.Errorf("expected history comment on source, got:\n%s", log)
+	}
+
+	// The tracks relation must be added before the old one is removed.
+	lines := strings.Split(log, "\n")
+	addIdx, removeIdx := -1, -1
+	for i, l := range lines {
+		if strings.HasPrefix(l, "CMD:dep add hq-cv-") && strings.HasSuffix(l, " gt-b --type=tracks") && addIdx < 0 {
+			addIdx = i
+		}
+		if l == "CMD:dep remove hq-cv-src gt-b --type=tracks" {
+			removeIdx = i
+		}
+	}
+	if addIdx < 0 || removeIdx < 0 || addIdx > removeIdx {
+		t.Errorf("dep add (line %d) should precede dep remove (line %d):\n%s", addIdx, removeIdx, log)
+	}
+}
+
+func TestConvoySplit_IntoExisting(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+	resetConvoyReshapeFlags(t)
+
+	dag := newTestDAG(t).
+		Convoy("hq-cv-src", "Release").
+		Task("gt-a", "Parser", "gastown").TrackedBy("hq-cv-src").
+		Task("gt-b", "UI", "gastown").TrackedBy("hq-cv-src").
+		Convoy("hq-cv-dst", "Later")
+	_, logPath := dag.Setup(t)
+
+	convoySplitInto = "hq-cv-dst"
+	if err := runConvoySplit(convoySplitCmd, []string{"hq-cv-src", "gt-b"}); err != nil {
+		t.Fatalf("runConvoySplit: %v", err)
+	}
+
+	log := readBdLog(t, logPath)
+	if strings.Contains(log, "CMD:create") {
+		t.Errorf("--into should not create a convoy, got:\n%s", log)
+	}
+	if !strings.Contains(log, "CMD:dep add hq-cv-dst gt-b --type=tracks") {
+		t.Errorf("expected gt-b tracked by destination, got:\n%s", log)
+	}
+	if !strings.Contains(log, "CMD:comments add hq-cv-dst Split from hq-cv-src: gt-b") {
+		t.Errorf("expected history comment on destination, got:\n%s", log)
+	}
+}
+
+func TestConvoySplit_Validation(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+
+	tests := []struct {
+		name    string
+		setup   func()
+		args    []string
+		wantErr string
+	}{
+		{"untracked issue", nil, []string{"hq-cv-src", "gt-x"}, "not tracked by hq-cv-src: gt-x"},
+		{"every issue", nil, []string{"hq-cv-src", "gt-a", "gt-b"}, "gt convoy merge"},
+		{"not a convoy", nil, []string{"gt-a", "gt-b"}, "is not a convoy"},
+		{"closed source", nil, []string{"hq-cv-done", "gt-c"}, "is closed"},
+		{"bad merge", func() { convoySplitMerge = "yolo" }, []string{"hq-cv-src", "gt-a"}, "invalid --merge"},
+		{"into with overrides", func() { convoySplitInto, convoySplitName = "hq-cv-other", "x" }, []string{"hq-cv-src", "gt-a"}, "--into"},
+		{"into itself", func() { convoySplitInto = "hq-cv-src" }, []string{"hq-cv-src", "gt-a"}, "into itself"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetConvoyReshapeFlags(t)
+			dag := newTestDAG(t).
+				Convoy("hq-cv-src", "Release").
+				Task("gt-a", "Parser", "gastown").TrackedBy("hq-cv-src").
+				Task("gt-b", "UI", "gastown").TrackedBy("hq-cv-src").
+				Convoy("hq-cv-done", "Shipped").WithStatus("closed").
+				Task("gt-c", "Old", "gastown").TrackedBy("hq-cv-done").
+				Task("gt-d", "Older", "gastown").TrackedBy("hq-cv-done")
+			_, logPath := dag.Setup(t)
+			if tt.setup != nil {
+				tt.setup()
+			}
+
+			err := runConvoySplit(convoySplitCmd, tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want containing %q", err, tt.wantErr)
+			}
+			if log, _ := os.ReadFile(logPath); beadsModified(string(log)) {
+				t.Errorf("no changes expected on validation failure, got:\n%s", log)
+			}
+		})
+	}
+}
+
+func TestConvoySplit_DryRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+	resetConvoyReshapeFlags(t)
+
+	dag := newTestDAG(t).
+		Convoy("hq-cv-src", "Release").
+		Task("gt-a", "Parser", "gastown").TrackedBy("hq-cv-src").
+		Task("gt-b", "UI", "gastown").TrackedBy("hq-cv-src")
+	_, logPath := dag.Setup(t)
+
+	convoySplitDryRun = true
+	out := captureStdout(t, func() {
+		if err := runConvoySplit(convoySplitCmd, []string{"hq-cv-src", "gt-b"}); err != nil {
+			t.Fatalf("runConvoySplit: %v", err)
+		}
+	})
+	if !strings.Contains(out, `Would move 1 of 2 issue(s) from hq-cv-src to new convoy "Release (split)"`) {
+		t.Errorf("unexpected dry-run output:\n%s", out)
+	}
+	if log := readBdLog(t, logPath); beadsModified(log) {
+		t.Errorf("dry run should not modify beads, got:\n%s", log)
+	}
+}
+
+func TestConvoyMerge_MovesAllAndClosesSources(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+	resetConvoyReshapeFlags(t)
+
+	dag := newTestDAG(t).
+		Convoy("hq-cv-dst", "Release").
+		Task("gt-a", "Parser", "gastown").TrackedBy("hq-cv-dst").
+		Convoy("hq-cv-one", "Hotfixes").
+		Task("gt-b", "Crash", "gastown").TrackedBy("hq-cv-one").
+		Convoy("hq-cv-two", "Docs").
+		Task("bd-c", "Guide", "beads").TrackedBy("hq-cv-two")
+	_, logPath := dag.Setup(t)
+
+	if err := runConvoyMerge(convoyMergeCmd, []string{"hq-cv-dst", "hq-cv-one", "hq-cv-two"}); err != nil {
+		t.Fatalf("runConvoyMerge: %v", err)
+	}
+
+	log := readBdLog(t, logPath)
+	for _, want := range []string{
+		"CMD:dep add hq-cv-dst gt-b --type=tracks",
+		"CMD:dep remove hq-cv-one gt-b --type=tracks",
+		"CMD:dep add hq-cv-dst bd-c --type=tracks",
+		"CMD:dep remove hq-cv-two bd-c --type=tracks",
+		"CMD:close hq-cv-one -r Merged into hq-cv-dst",
+		"CMD:close hq-cv-two -r Merged into hq-cv-dst",
+		"CMD:comments add hq-cv-dst Merged from hq-cv-one (Hotfixes): gt-b",
+	} {
+		if !strings.Contains(log, want) {
+			t.Errorf("bd.log missing %q:\n%s", want, log)
+		}
+	}
+	if strings.Contains(log, "close hq-cv-dst") {
+		t.Errorf("target must stay open, got:\n%s", log)
+	}
+}
+
+func TestConvoyMerge_CarriesPhases(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+	resetConvoyReshapeFlags(t)
+
+	dag := newTestDAG(t).
+		Convoy("hq-cv-dst", "Release").WithDescription("Convoy tracking 1 issues\nphase_1: gt-a").
+		Task("gt-a", "Schema", "gastown").TrackedBy("hq-cv-dst").
+		Convoy("hq-cv-one", "Cutover").WithDescription("Convoy tracking 2 issues\nphase_1: gt-b\nphase_2: gt-c").
+		Task("gt-b", "Backfill", "gastown").TrackedBy("hq-cv-one").
+		Task("gt-c", "Switch", "gastown").TrackedBy("hq-cv-one")
+	_, logPath := dag.Setup(t)
+
+	if err := runConvoyMerge(convoyMergeCmd, []string{"hq-cv-dst", "hq-cv-one"}); err != nil {
+		t.Fatalf("runConvoyMerge: %v", err)
+	}
+	log := readBdLog(t, logPath)
+	if !strings.Contains(log, "CMD:update hq-cv-dst --description=Convoy tracking 1 issues\nphase_1: gt-a, gt-b\nphase_2: gt-c") {
+		t.Errorf("expected the source's phases merged into the target, got:\n%s", log)
+	}
+}
+
+func TestConvoyMerge_ReopensClosedTarget(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+	resetConvoyReshapeFlags(t)
+
+	dag := newTestDAG(t).
+		Convoy("hq-cv-dst", "Release").WithStatus("closed").
+		Convoy("hq-cv-one", "Hotfixes").
+		Task("gt-b", "Crash", "gastown").TrackedBy("hq-cv-one")
+	_, logPath := dag.Setup(t)
+
+	if err := runConvoyMerge(convoyMergeCmd, []string{"hq-cv-dst", "hq-cv-one"}); err != nil {
+		t.Fatalf("runConvoyMerge: %v", err)
+	}
+	if log := readBdLog(t, logPath); !strings.Contains(log, "CMD:update hq-cv-dst --status=open") {
+		t.Errorf("expected closed target to be reopened, got:\n%s", log)
+	}
+}
+
+func TestConvoyMerge_RejectsSelf(t *testing.T) {
+	resetConvoyReshapeFlags(t)
+	err := runConvoyMerge(convoyMergeCmd, []string{"hq-cv-a", "hq-cv-b", "hq-cv-a"})
+	if err == nil || !strings.Contains(err.Error(), "into itself") {
+		t.Fatalf("err = %v, want self-merge rejection", err)
+	}
+}
+
+func TestDedupeStrings(t *testing.T) {
+	got := dedupeStrings([]string{"a", "b", "a", "c", "b"})
+	if strings.Join(got, ",") != "a,b,c" {
+		t.Errorf("dedupeStrings = %v", got)
+	}
+}
@@ -694,7 +694,7 @@ func createStagedConvoy(dag *ConvoyDAG, waves []Wave, status string, title strin
 	// Set the staged status.
 	statusCmd := exec.Command("bd", "update", convoyID, "--status="+status)
 	statusCmd.Dir = townBeads
-	if out, err := statusCmd.CombinedOutput(); err != nil {
+	if out, err := beads.CmdCombinedOutput(statusCmd); err != nil {
 		return "", fmt.Errorf("bd update convoy status: %w\noutput: %s", err, out)
 	}
 
@@ -1309,7 +1309,7 @@ type bdDepResult struct {
 // bdShow runs `bd show <id> --json` and returns the parsed bead info.
 // Returns error if bd exits non-zero or returns no results.
 func bdShow(beadID string) (*bdShowResult, error) {
-	out, err := exec.Command("bd", "show", beadID, "--json").Output()
+	out, err := beads.CmdOutput(exec.Command("bd", "show", beadID, "--json"))
 	if err != nil {
 		return nil, fmt.Errorf("bd show %s: %w", beadID, err)
 	}
@@ -1329,7 +1329,7 @@ func bdShow(beadID string) (*bdShowResult, error) {
 // bd dep list returns the beads that <id> depends on. Each result's
 // DependsOnID is the dependency target; IssueID is set to <id> by this func.
 func bdDepList(beadID string) ([]bdDepResult, error) {
-	out, err := exec.Command("bd", "dep", "list", beadID, "--json").Output()
+	out, err := beads.CmdOutput(exec.Command("bd", "dep", "list", beadID, "--json"))
 	if err != nil {
 		return nil, fmt.Errorf("bd dep list %s: %w", beadID, err)
 	}
@@ -1356,7 +1356,7 @@ func bdListChildren(parentID string) ([]bdShowResult, error) {
 	if dir := beadsDirForID(parentID); dir != "" {
 		cmd.Dir = dir
 	}
-	out, err := cmd.Output()
+	out, err := beads.CmdOutput(cmd)
 	if err != nil {
 		return nil, fmt.Errorf("bd list --parent=%s: %w", parentID, err)
 	}
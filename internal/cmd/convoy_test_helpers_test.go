@@ -24,6 +24,7 @@ type testBead struct {
 	Rig    string // e.g. "gastown"
 	Prefix string // e.g. "gt-"
 	Parent string // parent bead ID
+	Desc   string // description (e.g. convoy fields)
 }
 
 // testDep represents a dependency edge between two beads.
@@ -181,6 +182,16 @@ func (d *testDAG) WithStatus(status string) *testDAG {
 	return d
 }
 
+// WithDescription sets the description of the last-added bead.
+func (d *testDAG) WithDescription(desc string) *testDAG {
+	d.t.Helper()
+	if d.last == "" {
+		d.t.Fatal("WithDescription called with no current bead")
+	}
+	d.beads[d.last].Desc = desc
+	return d
+}
+
 // WaitsFor adds a "waits-for" dependency.
 func (d *testDAG) WaitsFor(waitID string) *testDAG {
 	d.t.Helper()
@@ -222,7 +233,8 @@ func (d *testDAG) BdStubScript() string {
 		beadJSON := d.beadJSON(b)
 		// Match both "show <id> --json" and "show --json <id>"
 		sb.WriteString(fmt.Sprintf("  show\\ %s\\ --json|show\\ --json\\ %s)\n", id, id))
-		sb.WriteString(fmt.Sprintf("    echo '%s'\n", beadJSON))
+		// printf, not echo: sh's echo would expand \n escapes in descriptions.
+		sb.WriteString(fmt.Sprintf("    printf '%%s\\n' '%s'\n", beadJSON))
 		sb.WriteString("    exit 0\n")
 		sb.WriteString("    ;;\n")
 	}
@@ -296,10 +308,16 @@ func (d *testDAG) BdStubScript() string {
 	sb.WriteString("    exit 0\n")
 	sb.WriteString("    ;;\n")
 
+	// --- handle: list --label=gt:sling-context (areScheduled: nothing scheduled) ---
+	sb.WriteString("  *list\\ --label=gt:sling-context*)\n")
+	sb.WriteString("    echo '[]'\n")
+	sb.WriteString("    exit 0\n")
+	sb.WriteString("    ;;\n")
+
 	// --- handle: list --type=convoy --all --json (overlapping convoy detection) ---
 	convoyListJSON := d.convoyListJSON()
 	sb.WriteString("  list\\ *--type=convoy*)\n")
-	sb.WriteString(fmt.Sprintf("    echo '%s'\n", convoyListJSON))
+	sb.WriteString(fmt.Sprintf("    printf '%%s\\n' '%s'\n", convoyListJSON))
 	sb.WriteString("    exit 0\n")
 	sb.WriteString("    ;;\n")
 
@@ -446,19 +464,21 @@ func (d *testDAG) beadJSON(b *testBead) string {
 	}
 
 	type beadOut struct {
-		ID        string `json:"id"`
-		Title     string `json:"title"`
-		Status    string `json:"status"`
-		IssueType string `json:"issue_type"`
-		Parent    string `json:"parent,omitempty"`
+		ID          string `json:"id"`
+		Title       string `json:"title"`
+		Status      string `json:"status"`
+		IssueType   string `json:"issue_type"`
+		Parent      string `json:"parent,omitempty"`
+		Description string `json:"description,omitempty"`
 	}
 
 	out := []beadOut{{
-		ID:        b.ID,
-		Title:     b.Title,
-		Status:    status,
-		IssueType: issueType,
-		Parent:    b.Parent,
+		ID:          b.ID,
+		Title:       b.Title,
+		Status:      status,
+		IssueType:   issueType,
+		Parent:      b.Parent,
+		Description: b.Desc,
 	}}
 	raw, _ := json.Marshal(out)
 	return string(raw)
@@ -597,8 +617,10 @@ func (d *testDAG) trackersSQLJSONFor(beadID string) string {
 // Returns all convoy-type beads with their ID and status.
 func (d *testDAG) convoyListJSON() string {
 	type convoyEntry struct {
-		ID     string `json:"id"`
-		Status string `json:"status"`
+		ID          string `json:"id"`
+		Title       string `json:"title"`
+		Status      string `json:"status"`
+		Description string `json:"description,omitempty"`
 	}
 
 	var out []convoyEntry
@@ -608,7 +630,7 @@ func (d *testDAG) convoyListJSON() string {
 			if status == "" {
 				status = "open"
 			}
-			out = append(out, convoyEntry{ID: b.ID, Status: status})
+			out = append(out, convoyEntry{ID: b.ID, Title: b.Title, Status: status, Description: b.Desc})
 		}
 	}
 	if out == nil {
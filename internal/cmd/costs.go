@@ -15,8 +15,10 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/notification"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -465,7 +467,7 @@ func querySessionEventsFromLocation(location string) ([]CostEntry, error) {
 
 	listCmd := exec.Command("bd", listArgs...)
 	listCmd.Dir = location
-	listOutput, err := listCmd.Output()
+	listOutput, err := beads.CmdOutput(listCmd)
 	if err != nil {
 		// If bd fails (e.g., no beads database), return empty list
 		return nil, nil
@@ -489,7 +491,7 @@ func querySessionEventsFromLocation(location string) ([]CostEntry, error) {
 
 	showCmd := exec.Command("bd", showArgs...)
 	showCmd.Dir = location
-	showOutput, err := showCmd.Output()
+	showOutput, err := beads.CmdOutput(showCmd)
 	if err != nil {
 		return nil, fmt.Errorf("showing events: %w", err)
 	}
@@ -548,7 +550,7 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 	}
 
 	listCmd := exec.Command("bd", listArgs...)
-	listOutput, err := listCmd.Output()
+	listOutput, err := beads.CmdOutput(listCmd)
 	if err != nil {
 		return nil, nil
 	}
@@ -569,7 +571,7 @@ func queryDigestBeads(days int) ([]CostEntry, error) {
 	}
 
 	showCmd := exec.Command("bd", showArgs...)
-	showOutput, err := showCmd.Output()
+	showOutput, err := beads.CmdOutput(showCmd)
 	if err != nil {
 		return nil, fmt.Errorf("showing events: %w", err)
 	}
@@ -1019,6 +1021,14 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("writing to costs log: %w", err)
 	}
 
+	recordExperimentCost(session, role, rig, worker, workDir, cost)
+
+	if cost > 0 {
+		if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+			checkDailyBudget(townRoot, time.Now())
+		}
+	}
+
 	// Output confirmation (silent if cost is zero and no work item)
 	if cost > 0 || recordWorkItem != "" {
 		fmt.Printf("%s Recorded $%.2f for %s", style.Success.Render("✓"), cost, session)
@@ -1031,6 +1041,52 @@ func runCostsRecord(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// checkDailyBudget raises a budget notification the first time today's
+// recorded session costs reach 80% and then 100% of the daily budget.
+func checkDailyBudget(townRoot string, now time.Time) {
+	cfg := config.LoadNotifications(townRoot)
+	if !cfg.IsEnabled() || cfg.DailyBudgetUSD <= 0 {
+		return
+	}
+	entries, err := querySessionCostEntries(now)
+	if err != nil {
+		return
+	}
+	var spent float64
+	for _, e := range entries {
+		spent += e.CostUSD
+	}
+	if n, ok := budgetNotification(spent, cfg.DailyBudgetUSD, now); ok {
+		raiseNotification(townRoot, n)
+	}
+}
+
+// budgetNotification returns the notification for the highest budget
+// threshold spent has reached, keyed by day and threshold so each fires
+// once a day.
+func budgetNotification(spent, budget float64, now time.Time) (notification.Notification, bool) {
+	day := now.Format("2006-01-02")
+	switch {
+	case spent >= budget:
+		return notification.Notification{
+			Event:    notification.EventBudget,
+			Severity: notification.SeverityCritical,
+			Title:    fmt.Sprintf("Daily budget exceeded: $%.2f of $%.2f", spent, budget),
+			Body:     "Session costs today are over the daily budget (gt costs --today)",
+			Key:      "budget-100-" + day,
+		}, true
+	case spent >= 0.8*budget:
+		return notification.Notification{
+			Event:    notification.EventBudget,
+			Severity: notification.SeverityNormal,
+			Title:    fmt.Sprintf("Daily budget at %.0f%%: $%.2f of $%.2f", 100*spent/budget, spent, budget),
+			Body:     "Session costs today are nearing the daily budget (gt costs --today)",
+			Key:      "budget-80-" + day,
+		}, true
+	}
+	return notification.Notification{}, false
+}
+
 // deriveSessionName derives the tmux session name from GT_* environment variables.
 // Uses session.* helpers for canonical naming. Parses GT_ROLE via parseRoleString
 // so compound forms (e.g. "gastown/witness") resolve to their canonical session names.
@@ -1322,7 +1378,7 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 	}
 
 	bdCmd := exec.Command("bd", bdArgs...)
-	output, err := bdCmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(bdCmd)
 	if err != nil {
 		return "", fmt.Errorf("creating digest bead: %w\nOutput: %s", err, string(output))
 	}
@@ -1331,7 +1387,7 @@ func createCostDigestBead(digest CostDigest) (string, error) {
 
 	// Auto-close the digest (it's an audit record, not work)
 	closeCmd := exec.Command("bd", "close", digestID, "--reason=daily cost digest")
-	_ = closeCmd.Run() // Best effort
+	_ = beads.RunCmd(closeCmd) // Best effort
 
 	return digestID, nil
 }
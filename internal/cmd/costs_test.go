@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/notification"
 	"github.com/steveyegge/gastown/internal/session"
 )
 
@@ -292,3 +293,18 @@ func TestCostDigestPayload_ExcludesSessions(t *testing.T) {
 		t.Errorf("by_role should have 3 entries, got %d", len(asDigest.ByRole))
 	}
 }
+
+func TestBudgetNotification(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	if _, ok := budgetNotification(50, 100, now); ok {
+		t.Error("50% of budget should not notify")
+	}
+	n, ok := budgetNotification(85, 100, now)
+	if !ok || n.Severity != notification.SeverityNormal || n.Key != "budget-80-2026-01-02" {
+		t.Errorf("85%% = %+v, %v", n, ok)
+	}
+	n, ok = budgetNotification(120, 100, now)
+	if !ok || n.Severity != notification.SeverityCritical || n.Key != "budget-100-2026-01-02" {
+		t.Errorf("120%% = %+v, %v", n, ok)
+	}
+}
@@ -88,7 +88,7 @@ func detectCrewFromCwd() (*crewDetection, error) {
 	}
 
 	// Get relative path from town root
-	relPath, err := filepath.Rel(townRoot, cwd)
+	relPath, err := workspace.RelPath(townRoot, cwd)
 	if err != nil {
 		return nil, fmt.Errorf("getting relative path: %w", err)
 	}
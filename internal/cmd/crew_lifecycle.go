@@ -129,7 +129,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 			deleteArgs := []string{"delete", agentBeadID, "--force"}
 			deleteCmd := exec.Command("bd", deleteArgs...)
 			deleteCmd.Dir = r.Path
-			if output, err := deleteCmd.CombinedOutput(); err != nil {
+			if output, err := beads.CmdCombinedOutput(deleteCmd); err != nil {
 				// Non-fatal: bead might not exist
 				if !strings.Contains(string(output), "no issue found") &&
 					!strings.Contains(string(output), "not found") {
@@ -144,7 +144,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 			unassignArgs := []string{"list", "--assignee=" + agentAddr, "--format=id"}
 			unassignCmd := exec.Command("bd", unassignArgs...)
 			unassignCmd.Dir = r.Path
-			if output, err := unassignCmd.CombinedOutput(); err == nil {
+			if output, err := beads.CmdCombinedOutput(unassignCmd); err == nil {
 				ids := strings.Fields(strings.TrimSpace(string(output)))
 				for _, id := range ids {
 					if id == "" {
@@ -152,7 +152,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 					}
 					updateCmd := exec.Command("bd", "update", id, "--unassign")
 					updateCmd.Dir = r.Path
-					if _, err := updateCmd.CombinedOutput(); err == nil {
+					if _, err := beads.CmdCombinedOutput(updateCmd); err == nil {
 						fmt.Printf("Unassigned: %s\n", id)
 					}
 				}
@@ -172,7 +172,7 @@ func runCrewRemove(cmd *cobra.Command, args []string) error {
 			}
 			closeCmd := exec.Command("bd", closeArgs...)
 			closeCmd.Dir = r.Path
-			if output, err := closeCmd.CombinedOutput(); err != nil {
+			if output, err := beads.CmdCombinedOutput(closeCmd); err != nil {
 				// Non-fatal: bead might not exist or already be closed
 				if !strings.Contains(string(output), "no issue found") &&
 					!strings.Contains(string(output), "already closed") {
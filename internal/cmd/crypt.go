@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/atrest"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	cryptInitMail        bool
+	cryptInitTranscripts bool
+	cryptInitSealAfter   string
+	cryptSealDryRun      bool
+)
+
+var cryptCmd = &cobra.Command{
+	Use:     "crypt",
+	GroupID: GroupConfig,
+	Short:   "Encrypt mail and transcripts at rest",
+	Long: `Encrypt mail bodies and agent transcripts at rest.
+
+Sealed data uses NaCl secretbox under a town key kept in the OS keychain
+(macOS Keychain, or the Secret Service via secret-tool on Linux). gt
+commands open sealed data transparently for operators whose keychain
+holds the key; without it, mail bodies read as "[encrypted message]" and
+transcripts can't be read. Mail subjects, senders and labels stay in the
+clear so routing keeps working.
+
+  "encryption": {
+    "key_id": "gastown-3f9a1c2e",
+    "mail": true,
+    "transcripts": true,
+    "seal_after": "1h"
+  }
+
+Mail bodies are sealed as they are sent. Transcripts are sealed once a
+session has left them idle for seal_after, by gt crypt seal (the daemon's
+seal_transcripts patrol runs it). gt seance unseals a transcript before
+resuming its session.
+
+On hosts without a keychain set GT_ATREST_KEY=<key-id>:<key> instead.`,
+	RunE: requireSubcommand,
+}
+
+var cryptInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create the town key and turn on encryption",
+	Long: `Create a town key, store it in the OS keychain and turn on encryption in
+the town settings. With neither --mail nor --transcripts both are sealed.
+
+A new key is created each time: data sealed under the old key still
+opens as long as the old key stays in the keychain.
+
+Examples:
+  gt crypt init
+  gt crypt init --mail
+  gt crypt init --transcripts --seal-after 30m`,
+	Args: cobra.NoArgs,
+	RunE: runCryptInit,
+}
+
+var cryptStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show encryption settings and whether the key is available",
+	Args:  cobra.NoArgs,
+	RunE:  runCryptStatus,
+}
+
+var cryptSealCmd = &cobra.Command{
+	Use:   "seal",
+	Short: "Seal transcripts idle longer than seal_after (daemon)",
+	Long: `Seal the town's agent transcripts that no session has written to for
+seal_after. The daemon's seal_transcripts patrol runs this.
+
+Examples:
+  gt crypt seal
+  gt crypt seal --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runCryptSeal,
+}
+
+var cryptUnsealCmd = &cobra.Command{
+	Use:   "unseal <file>...",
+	Short: "Restore sealed files to plaintext",
+	Long: `Restore sealed files (*.sealed) to plaintext next to them. Transcripts
+are sealed again by the next gt crypt seal once idle.
+
+Examples:
+  gt crypt unseal ~/.claude/projects/-home-me-gt/3f1c….jsonl.sealed`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runCryptUnseal,
+}
+
+var cryptImportKeyCmd = &cobra.Command{
+	Use:   "import-key <key-id>:<key>",
+	Short: "Store a town key in this host's keychain",
+	Long: `Store a town key in this host's keychain, so this operator can read
+sealed mail and transcripts. Get the key from an operator who has it
+(gt crypt init prints it when the keychain is unavailable).
+
+Examples:
+  gt crypt import-key gastown-3f9a1c2e:q8V1…=`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCryptImportKey,
+}
+
+func init() {
+	cryptInitCmd.Flags().BoolVar(&cryptInitMail, "mail", false, "Seal mail bodies")
+	cryptInitCmd.Flags().BoolVar(&cryptInitTranscripts, "transcripts", false, "Seal idle agent transcripts")
+	cryptInitCmd.Flags().StringVar(&cryptInitSealAfter, "seal-after", "", "Idle time before a transcript is sealed (default 1h)")
+	cryptSealCmd.Flags().BoolVar(&cryptSealDryRun, "dry-run", false, "List transcripts that would be sealed")
+
+	cryptCmd.AddCommand(cryptInitCmd)
+	cryptCmd.AddCommand(cryptStatusCmd)
+	cryptCmd.AddCommand(cryptSealCmd)
+	cryptCmd.AddCommand(cryptUnsealCmd)
+	cryptCmd.AddCommand(cryptImportKeyCmd)
+	rootCmd.AddCommand(cryptCmd)
+}
+
+func runCryptInit(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settingsPath := config.TownSettingsPath(townRoot)
+	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+
+	enc := &atrest.Config{Mail: cryptInitMail, Transcripts: cryptInitTranscripts, SealAfter: cryptInitSealAfter}
+	if !enc.Mail && !enc.Transcripts {
+		enc.Mail, enc.Transcripts = true, true
+	}
+	townName := filepath.Base(townRoot)
+	if tc, err := config.LoadTownConfig(filepath.Join(townRoot, "mayor", "town.json")); err == nil && tc.Name != "" {
+		townName = tc.Name
+	}
+	enc.KeyID = atrest.NewKeyID(townName)
+	if err := enc.Validate(); err != nil {
+		return err
+	}
+
+	encoded, err := atrest.GenerateKey(enc.KeyID)
+	if err != nil {
+		// Don't turn encryption on with a key nobody can load.
+		style.PrintWarning("%v", err)
+		fmt.Printf("Encryption was not enabled. To use this key without a keychain, set\n\n  %s=%s\n\n", atrest.EnvKey, encoded)
+		fmt.Println("in the environment of every gt process (daemon and agents included), then run gt crypt init again there.")
+		return fmt.Errorf("no keychain to store the key in")
+	}
+
+	townSettings.Encryption = enc
+	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+	fmt.Printf("%s Encryption on with key %s (stored in %s)\n", style.SuccessPrefix, enc.KeyID, atrest.Keychain())
+	printCryptScope(enc)
+	fmt.Println(style.Dim.Render("Give other operators the key with: gt crypt import-key " + encoded))
+	return nil
+}
+
+func printCryptScope(enc *atrest.Config) {
+	if enc.SealsMail() {
+		fmt.Println("  mail:        bodies sealed as they are sent")
+	}
+	if enc.SealsTranscripts() {
+		fmt.Printf("  transcripts: sealed after %s idle\n", enc.GetSealAfter())
+	}
+}
+
+func runCryptStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	enc := config.LoadEncryption(townRoot)
+	if enc == nil || (!enc.Mail && !enc.Transcripts) {
+		fmt.Println("Encryption: off (enable with gt crypt init)")
+		return nil
+	}
+	if err := enc.Validate(); err != nil {
+		fmt.Printf("%s Encryption settings invalid: %v\n", style.ErrorPrefix, err)
+		return nil
+	}
+	fmt.Printf("Encryption: on, key %s\n", style.Bold.Render(enc.KeyID))
+	printCryptScope(enc)
+
+	keychain := atrest.Keychain()
+	if keychain == "" {
+		keychain = "none"
+	}
+	if atrest.HasKey(enc.KeyID) {
+		fmt.Printf("  key:         %s (keychain: %s)\n", style.Success.Render("available"), keychain)
+	} else {
+		fmt.Printf("  key:         %s (keychain: %s); import it with gt crypt import-key\n", style.Error.Render("missing"), keychain)
+	}
+
+	sealed, plain, err := countTownTranscripts(townRoot)
+	if err == nil {
+		fmt.Printf("  on disk:     %d sealed, %d plaintext transcript(s)\n", sealed, plain)
+	}
+	return nil
+}
+
+// townTranscriptFiles returns the transcripts of sessions started in the
+// town, sealed or not.
+func townTranscriptFiles(townRoot string) ([]string, error) {
+	dirs, err := agentlog.ClaudeCodeProjectDirs(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if !e.IsDir() && (strings.HasSuffix(name, ".jsonl") || strings.HasSuffix(name, ".jsonl"+atrest.SealedSuffix)) {
+				files = append(files, filepath.Join(dir, name))
+			}
+		}
+	}
+	return files, nil
+}
+
+func countTownTranscripts(townRoot string) (sealed, plain int, err error) {
+	files, err := townTranscriptFiles(townRoot)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, f := range files {
+		if strings.HasSuffix(f, atrest.SealedSuffix) {
+			sealed++
+		} else {
+			plain++
+		}
+	}
+	return sealed, plain, nil
+}
+
+// idleTranscripts returns the plaintext transcripts untouched since before
+// cutoff.
+func idleTranscripts(files []string, cutoff time.Time) []string {
+	var idle []string
+	for _, f := range files {
+		if strings.HasSuffix(f, atrest.SealedSuffix) {
+			continue
+		}
+		if info, err := os.Stat(f); err == nil && info.ModTime().Before(cutoff) {
+			idle = append(idle, f)
+		}
+	}
+	return idle
+}
+
+func runCryptSeal(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	enc := config.LoadEncryption(townRoot)
+	if !enc.SealsTranscripts() {
+		fmt.Println("Transcript encryption is off.")
+		return nil
+	}
+	if err := enc.Validate(); err != nil {
+		return fmt.Errorf("encryption settings: %w", err)
+	}
+	files, err := townTranscriptFiles(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing transcripts: %w", err)
+	}
+	idle := idleTranscripts(files, time.Now().Add(-enc.GetSealAfter()))
+	if len(idle) == 0 {
+		fmt.Println("No idle transcripts to seal.")
+		return nil
+	}
+	if cryptSealDryRun {
+		for _, f := range idle {
+			fmt.Printf("  would seal %s\n", f)
+		}
+		return nil
+	}
+
+	var failed int
+	for _, f := range idle {
+		if err := atrest.SealFile(enc.KeyID, f); err != nil {
+			style.PrintWarning("sealing %s: %v", filepath.Base(f), err)
+			failed++
+		}
+	}
+	fmt.Printf("%s Sealed %d transcript(s)\n", style.SuccessPrefix, len(idle)-failed)
+	if failed > 0 {
+		return fmt.Errorf("%d transcript(s) could not be sealed", failed)
+	}
+	return nil
+}
+
+func runCryptUnseal(cmd *cobra.Command, args []string) error {
+	for _, path := range args {
+		plain, err := atrest.UnsealFile(path)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s\n", style.SuccessPrefix, plain)
+	}
+	return nil
+}
+
+func runCryptImportKey(cmd *cobra.Command, args []string) error {
+	keyID, err := atrest.ImportKey(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Key %s stored in %s\n", style.SuccessPrefix, keyID, atrest.Keychain())
+	return nil
+}
@@ -59,6 +59,8 @@ var daemonStatusCmd = &cobra.Command{
 
 Displays whether the daemon is running, its PID, uptime, heartbeat
 count, and whether the binary has been rebuilt since the daemon started.
+A running daemon whose heartbeats stopped is reported as stale, and one
+that exited without a clean shutdown (crash, kill -9, OOM) as dead.
 
 Examples:
   gt daemon status`,
@@ -255,27 +257,34 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
-	running, pid, err := daemon.IsRunning(townRoot)
+	health, state, err := daemon.ReadHealth(townRoot, time.Now())
 	if err != nil {
 		return fmt.Errorf("checking daemon status: %w", err)
 	}
 
-	if running {
+	if health.Status == daemon.HealthOK || health.Status == daemon.HealthStale {
 		fmt.Printf("%s Daemon is %s (PID %d)\n",
 			style.Bold.Render("●"),
 			style.Bold.Render("running"),
-			pid)
+			health.PID)
 		fmt.Printf("  Town: %s\n", townRoot)
 
-		// Load state for more details
-		state, err := daemon.LoadState(townRoot)
-		if err == nil && !state.StartedAt.IsZero() {
+		// State file holds more details
+		if !state.StartedAt.IsZero() {
 			fmt.Printf("  Started: %s\n", state.StartedAt.Format("2006-01-02 15:04:05"))
 			if !state.LastHeartbeat.IsZero() {
 				fmt.Printf("  Last heartbeat: %s (#%d)\n",
 					state.LastHeartbeat.Format("15:04:05"),
 					state.HeartbeatCount)
 			}
+			if health.Status == daemon.HealthStale {
+				fmt.Printf("  %s Heartbeat is stale: %s\n", style.Bold.Render("⚠"), health.Reason)
+			}
+			if state.UncleanExits > 0 {
+				fmt.Printf("  Unclean exits: %d (last %s)\n",
+					state.UncleanExits,
+					state.LastUncleanExit.Format("2006-01-02 15:04:05"))
+			}
 
 			// Check if binary is newer than process
 			if binaryModTime, err := getBinaryModTime(); err == nil {
@@ -287,6 +296,13 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 				}
 			}
 		}
+	} else if health.Status == daemon.HealthDead {
+		fmt.Printf("%s Daemon is %s: %s\n",
+			style.Error.Render("✗"),
+			style.Bold.Render("dead"),
+			health.Reason)
+		fmt.Printf("\nStart with: %s\n", style.Dim.Render("gt daemon start"))
+		fmt.Printf("Keep it running with: %s\n", style.Dim.Render("gt town install-service"))
 	} else {
 		fmt.Printf("%s Daemon is %s\n",
 			style.Dim.Render("○"),
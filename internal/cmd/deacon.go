@@ -1138,7 +1138,7 @@ func getAgentBeadUpdateTime(townRoot, beadID string) (time.Time, error) {
 	cmd := exec.Command("bd", "show", beadID, "--json")
 	cmd.Dir = townRoot
 
-	output, err := cmd.Output()
+	output, err := beads.CmdOutput(cmd)
 	if err != nil {
 		return time.Time{}, err
 	}
@@ -1174,7 +1174,7 @@ func updateAgentBeadState(townRoot, agent, state, _ string) { // reason unused b
 	// Use bd agent state command
 	cmd := exec.Command("bd", "agent", "state", beadID, state)
 	cmd.Dir = townRoot
-	_ = cmd.Run() // Best effort
+	_ = beads.RunCmd(cmd) // Best effort
 }
 
 // runDeaconStaleHooks finds and unhooks stale hooked beads.
@@ -1574,7 +1574,7 @@ func runDeaconFeedStranded(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  %s %s: %s\n", style.Bold.Render("✓"), d.ConvoyID, d.Message)
 		case "needs_attention":
 			fmt.Printf("  %s %s: %s\n", style.Warning.Render("?"), d.ConvoyID, d.Message)
-		case "cooldown":
+		case "cooldown", "waiting":
 			fmt.Printf("  %s %s: %s\n", style.Dim.Render("○"), d.ConvoyID, d.Message)
 		case "limit":
 			fmt.Printf("  %s %s: %s\n", style.Dim.Render("○"), d.ConvoyID, d.Message)
@@ -1588,8 +1588,8 @@ func runDeaconFeedStranded(cmd *cobra.Command, args []string) error {
 	}
 
 	// Summary
-	fmt.Printf("\n%s Fed: %d, Closed: %d, Needs attention: %d, Skipped: %d, Errors: %d\n",
-		style.Bold.Render("●"), result.Fed, result.Closed, result.NeedsAttention, result.Skipped, result.Errors)
+	fmt.Printf("\n%s Fed: %d, Closed: %d, Needs attention: %d, Waiting: %d, Skipped: %d, Errors: %d\n",
+		style.Bold.Render("●"), result.Fed, result.Closed, result.NeedsAttention, result.Waiting, result.Skipped, result.Errors)
 
 	return nil
 }
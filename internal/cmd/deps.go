@@ -0,0 +1,345 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/depupdate"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// depsCheckTimeout bounds one rig's checkers (go list -u hits the network).
+const depsCheckTimeout = 5 * time.Minute
+
+var (
+	depsJSON        bool
+	depsSweepDryRun bool
+	depsSweepForce  bool
+)
+
+var depsCmd = &cobra.Command{
+	Use:     "deps [rig...]",
+	GroupID: GroupWork,
+	Short:   "Check rigs for outdated dependencies",
+	Long: `List the outdated direct dependencies of each rig's checkout, without
+filing anything. With no rigs, every rig with dep_updates enabled is
+checked.
+
+Checkers are pluggable: go (go.mod) and npm (package.json) are built in,
+and a rig can add its own commands. Configure the sweep in the rig's
+settings/config.json:
+
+  "dep_updates": {
+    "enabled": true,
+    "every": "7d",
+    "group_by": "ecosystem",
+    "sling": "quiet_hours"
+  }
+
+See 'gt deps sweep' for filing update beads, and 'gt deps graph' for the
+dependencies between beads.
+
+Examples:
+  gt deps
+  gt deps gastown --json`,
+	RunE: runDeps,
+}
+
+var depsSweepCmd = &cobra.Command{
+	Use:   "sweep [rig...]",
+	Short: "File update beads for outdated dependencies",
+	Long: `Check each rig whose last check is older than dep_updates.every and
+file a bead per batch of outdated dependencies.
+
+Updates are grouped by group_by: "ecosystem" (default) files one bead per
+ecosystem and directory and gives each major upgrade its own bead,
+"dependency" files one per dependency and "rig" one for everything. A
+batch that already has an open bead isn't filed again; once its bead
+closes, the batch is filed anew if it is still outdated.
+
+The sling policy decides what happens to filed beads: "never" (default)
+leaves them in the rig's queue, "immediately" slings them to the rig, and
+"quiet_hours" slings them only while the rig is in quiet hours, so
+dependency churn runs when nobody is working.
+
+The daemon runs this hourly when the dep_updates patrol is enabled.
+
+Examples:
+  gt deps sweep
+  gt deps sweep gastown --force --dry-run`,
+	RunE: runDepsSweep,
+}
+
+var (
+	// depsCheckFn is a seam for tests. Production uses depupdate.Check.
+	depsCheckFn = func(checkout string, cfg *depupdate.Config) ([]depupdate.Update, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), depsCheckTimeout)
+		defer cancel()
+		return depupdate.Check(ctx, checkout, cfg)
+	}
+
+	// depsCreateFn is a seam for tests. Production creates the update bead with
+	// bd.
+	depsCreateFn = func(townRoot, rigName, title, description string) (string, error) {
+		issue, err := beads.New(rigBeadsWorkDir(townRoot, rigName)).Create(beads.CreateOptions{
+			Title:       title,
+			Labels:      []string{"gt:task", "deps"},
+			Priority:    3,
+			Description: description,
+			Actor:       "deps",
+		})
+		if err != nil {
+			return "", err
+		}
+		return issue.ID, nil
+	}
+
+	// depsStatusFn is a seam for tests. Production reads the bead status with bd
+	// show.
+	depsStatusFn = func(townRoot, rigName, id string) (string, error) {
+		issue, err := beads.New(rigBeadsWorkDir(townRoot, rigName)).Show(id)
+		if err != nil {
+			return "", err
+		}
+		return issue.Status, nil
+	}
+
+	// depsSlingFn is a seam for tests. Production runs gt sling in the town.
+	depsSlingFn = func(townRoot, id, rigName string) error {
+		cmd := exec.Command("gt", "sling", id, rigName)
+		cmd.Dir = townRoot
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%v (%s)", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	// depsQuietActiveFn is a seam for tests. Production checks the rig's quiet
+	// hours.
+	depsQuietActiveFn = func(townRoot, rigName string, now time.Time) bool {
+		return config.LoadQuietHours(townRoot, rigName).Active(now)
+	}
+)
+
+func init() {
+	depsCmd.Flags().BoolVar(&depsJSON, "json", false, "Output as JSON")
+	depsSweepCmd.Flags().BoolVarP(&depsSweepDryRun, "dry-run", "n", false, "Show what would be filed and slung")
+	depsSweepCmd.Flags().BoolVar(&depsSweepForce, "force", false, "Check even if the last check is recent")
+
+	depsCmd.AddCommand(depsSweepCmd)
+	rootCmd.AddCommand(depsCmd)
+}
+
+// depsRig is a rig and its dep_updates config.
+type depsRig struct {
+	Name string
+	Path string
+	Cfg  *depupdate.Config
+}
+
+// depsRigs returns the named rigs, or every rig with dep_updates enabled.
+func depsRigs(townRoot string, names []string) ([]depsRig, error) {
+	explicit := len(names) > 0
+	if !explicit {
+		names = diskRigNames(townRoot)
+	}
+	var rigs []depsRig
+	for _, name := range names {
+		rigPath := filepath.Join(townRoot, name)
+		var cfg *depupdate.Config
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+		switch {
+		case err == nil:
+			cfg = settings.DepUpdates
+		case !errors.Is(err, config.ErrNotFound):
+			return nil, fmt.Errorf("rig %s: %w", name, err)
+		}
+		if explicit {
+			if _, err := os.Stat(rigPath); err != nil {
+				return nil, fmt.Errorf("rig %s not found", name)
+			}
+		} else if !cfg.IsEnabled() {
+			continue
+		}
+		rigs = append(rigs, depsRig{Name: name, Path: rigPath, Cfg: cfg})
+	}
+	return rigs, nil
+}
+
+func runDeps(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigs, err := depsRigs(townRoot, args)
+	if err != nil {
+		return err
+	}
+	if len(rigs) == 0 {
+		fmt.Println("No rigs have dep_updates enabled (name a rig to check it anyway).")
+		return nil
+	}
+
+	byRig := make(map[string][]depupdate.Update)
+	for _, r := range rigs {
+		updates, err := depsCheckFn(askWorkDir(r.Path), r.Cfg)
+		if err != nil {
+			style.PrintWarning("%s: %v", r.Name, err)
+		}
+		byRig[r.Name] = updates
+	}
+	if depsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(byRig)
+	}
+	for _, r := range rigs {
+		updates := byRig[r.Name]
+		if len(updates) == 0 {
+			fmt.Printf("%s %s: up to date\n", style.SuccessPrefix, style.Bold.Render(r.Name))
+			continue
+		}
+		fmt.Printf("%s: %d outdated\n", style.Bold.Render(r.Name), len(updates))
+		for _, u := range updates {
+			line := fmt.Sprintf("  %-4s %-40s %s → %s", u.Ecosystem, u.Name, u.Current, u.Latest)
+			if u.Dir != "" && u.Dir != "." {
+				line += style.Dim.Render("  " + u.Dir)
+			}
+			if u.Major() {
+				line += "  " + style.Warning.Render("major")
+			}
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
+
+func runDepsSweep(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigs, err := depsRigs(townRoot, args)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, r := range rigs {
+		if err := sweepRigDeps(townRoot, r, now, depsSweepDryRun, depsSweepForce); err != nil {
+			style.PrintWarning("%s: %v", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// sweepRigDeps checks a rig when due, files beads for batches without an
+// open bead and slings unslung beads as the policy allows.
+func sweepRigDeps(townRoot string, r depsRig, now time.Time, dryRun, force bool) error {
+	state, err := depupdate.LoadState(r.Path)
+	if err != nil {
+		return fmt.Errorf("loading sweep state: %w", err)
+	}
+
+	if force || state.Due(now, r.Cfg.GetEvery()) {
+		updates, err := depsCheckFn(askWorkDir(r.Path), r.Cfg)
+		if err != nil {
+			// Checkers that worked still count; the failed ones retry next check.
+			style.PrintWarning("%s: %v", r.Name, err)
+		}
+		batches := depupdate.Group(updates, r.Cfg.GroupBy)
+		current := make(map[string]bool, len(batches))
+		for _, b := range batches {
+			current[b.Key] = true
+			if f := state.Filed[b.Key]; f != nil {
+				status, err := depsStatusFn(townRoot, r.Name, f.Bead)
+				if err != nil {
+					style.PrintWarning("%s: %s: %v", r.Name, f.Bead, err)
+					continue
+				}
+				if !isClosedStatus(status) {
+					continue
+				}
+			}
+			if dryRun {
+				fmt.Printf("%s %s: would file %q\n", style.Dim.Render("[dry-run]"), r.Name, b.Title())
+				continue
+			}
+			id, err := depsCreateFn(townRoot, r.Name, b.Title(), b.Description())
+			if err != nil {
+				style.PrintWarning("%s: filing %q: %v", r.Name, b.Title(), err)
+				continue
+			}
+			state.Filed[b.Key] = &depupdate.Filed{Bead: id, Title: b.Title(), FiledAt: now}
+			fmt.Printf("%s %s: filed %s %s\n", style.SuccessPrefix, r.Name, style.Bold.Render(id), b.Title())
+		}
+		// Forget closed beads whose batch is no longer outdated.
+		if err == nil && !dryRun {
+			for key, f := range state.Filed {
+				if current[key] {
+					continue
+				}
+				if status, err := depsStatusFn(townRoot, r.Name, f.Bead); err == nil && isClosedStatus(status) {
+					delete(state.Filed, key)
+				}
+			}
+		}
+		if !dryRun {
+			state.LastCheck = now
+		}
+	}
+
+	if slingDepBeads(townRoot, r, now) && !dryRun {
+		for _, key := range sortedFiledKeys(state) {
+			f := state.Filed[key]
+			if f.Slung {
+				continue
+			}
+			if status, err := depsStatusFn(townRoot, r.Name, f.Bead); err != nil || isClosedStatus(status) {
+				continue
+			}
+			if err := depsSlingFn(townRoot, f.Bead, r.Name); err != nil {
+				style.PrintWarning("%s: slinging %s: %v", r.Name, f.Bead, err)
+				continue
+			}
+			f.Slung = true
+			fmt.Printf("%s %s: slung %s\n", style.SuccessPrefix, r.Name, style.Bold.Render(f.Bead))
+		}
+	}
+
+	if dryRun {
+		return nil
+	}
+	return state.Save(r.Path)
+}
+
+// slingDepBeads reports whether the rig's sling policy allows slinging now.
+func slingDepBeads(townRoot string, r depsRig, now time.Time) bool {
+	switch r.Cfg.GetSling() {
+	case depupdate.SlingImmediate:
+		return true
+	case depupdate.SlingQuietHours:
+		return depsQuietActiveFn(townRoot, r.Name, now)
+	}
+	return false
+}
+
+func sortedFiledKeys(state *depupdate.State) []string {
+	keys := make([]string, 0, len(state.Filed))
+	for key := range state.Filed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
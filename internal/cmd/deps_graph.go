@@ -0,0 +1,470 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	depsGraphFormat string
+	depsGraphTop    int
+	depsGraphJSON   bool
+)
+
+var depsGraphCmd = &cobra.Command{
+	Use:   "graph <epic-id | task-id... | convoy-id>",
+	Short: "Show the dependency graph of an epic, convoy or set of beads",
+	Long: `Render the blocking dependencies between beads, with the critical path
+and the beads holding up the most downstream work.
+
+Takes the same inputs as 'gt convoy stage': an epic (its whole child
+tree), a convoy (its tracked beads) or a list of beads. Only blocking
+edges (blocks, conditional-blocks, waits-for, merge-blocks) order work;
+epic hierarchy is drawn dashed in dot and mermaid output.
+
+The critical path is the longest chain of unfinished beads: the least
+number of merges before the last of them can land. A bead's downstream
+count is the number of unfinished beads waiting on it, directly or
+transitively.
+
+Formats:
+  ascii    Beads by layer, each after everything it waits on (default)
+  dot      Graphviz, e.g. | dot -Tsvg > deps.svg
+  mermaid  A flowchart for markdown, PRs and issues
+
+Examples:
+  gt deps graph gt-epic-abc
+  gt deps graph hq-cv-xyz --format mermaid
+  gt deps graph gt-abc gt-def gt-ghi --format dot | dot -Tsvg > deps.svg
+  gt deps graph gt-epic-abc --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDepsGraph,
+}
+
+func init() {
+	depsGraphCmd.Flags().StringVarP(&depsGraphFormat, "format", "f", "ascii", "Output format: ascii, dot or mermaid")
+	depsGraphCmd.Flags().IntVar(&depsGraphTop, "top", 5, "How many of the most-blocking beads to highlight")
+	depsGraphCmd.Flags().BoolVar(&depsGraphJSON, "json", false, "Output as JSON")
+
+	depsCmd.AddCommand(depsGraphCmd)
+}
+
+// depGraphAnalysis is what gt deps graph highlights in a ConvoyDAG.
+type depGraphAnalysis struct {
+	Layers       map[string]int // Longest chain of blockers before a bead
+	Downstream   map[string]int // Unfinished beads waiting on a bead
+	CriticalPath []string
+	TopBlockers  []string // Unfinished beads with the most downstream, most first
+	Cycle        []string // A blocking cycle, if any
+}
+
+func runDepsGraph(cmd *cobra.Command, args []string) error {
+	switch depsGraphFormat {
+	case "ascii", "dot", "mermaid":
+	default:
+		return fmt.Errorf("invalid --format %q (want ascii, dot or mermaid)", depsGraphFormat)
+	}
+	if err := validateStageArgs(args); err != nil {
+		return err
+	}
+
+	beadTypes := make(map[string]string)
+	for _, arg := range args {
+		result, err := bdShow(arg)
+		if err != nil {
+			return fmt.Errorf("cannot resolve bead %s: %w", arg, err)
+		}
+		beadTypes[arg] = result.IssueType
+	}
+	input, err := resolveInputKind(beadTypes)
+	if err != nil {
+		return err
+	}
+	beadInfos, deps, err := collectBeads(input)
+	if err != nil {
+		return err
+	}
+	dag := buildConvoyDAG(beadInfos, deps)
+	a := analyzeDepGraph(dag, depsGraphTop)
+
+	if depsGraphJSON {
+		return outputDepGraphJSON(dag, a)
+	}
+	switch depsGraphFormat {
+	case "dot":
+		fmt.Print(renderDepGraphDOT(dag, a))
+	case "mermaid":
+		fmt.Print(renderDepGraphMermaid(dag, a))
+	default:
+		fmt.Print(renderDepGraphASCII(dag, a))
+	}
+	return nil
+}
+
+// depGraphOpen reports whether a bead still has work left.
+func depGraphOpen(node *ConvoyDAGNode) bool {
+	return node.Status != "closed" && node.Status != "tombstone"
+}
+
+// depGraphState is a bead's state for display: closed, in_progress, ready
+// (open with every blocker done) or blocked.
+func depGraphState(dag *ConvoyDAG, node *ConvoyDAGNode) string {
+	switch {
+	case !depGraphOpen(node):
+		return "closed"
+	case node.Status == "in_progress" || node.Status == "hooked":
+		return "in_progress"
+	}
+	for _, id := range node.BlockedBy {
+		if b := dag.Nodes[id]; b != nil && depGraphOpen(b) {
+			return "blocked"
+		}
+	}
+	return "ready"
+}
+
+// analyzeDepGraph layers the beads and finds the critical path and the
+// top most-blocking beads. Cycles don't hang it: a bead reached again while
+// it is being walked is treated as a dead end.
+func analyzeDepGraph(dag *ConvoyDAG, top int) *depGraphAnalysis {
+	a := &depGraphAnalysis{
+		Layers:     make(map[string]int),
+		Downstream: make(map[string]int),
+		Cycle:      detectCycles(dag),
+	}
+	ids := sortedNodeIDs(dag)
+
+	walking := make(map[string]bool)
+	var layer func(id string) int
+	layer = func(id string) int {
+		if l, ok := a.Layers[id]; ok {
+			return l
+		}
+		if walking[id] {
+			return 0
+		}
+		walking[id] = true
+		l := 0
+		for _, b := range dag.Nodes[id].BlockedBy {
+			if _, ok := dag.Nodes[b]; ok {
+				l = max(l, layer(b)+1)
+			}
+		}
+		walking[id] = false
+		a.Layers[id] = l
+		return l
+	}
+	for _, id := range ids {
+		layer(id)
+	}
+
+	// Downstream: unfinished beads reachable over blocking edges.
+	for _, id := range ids {
+		seen := map[string]bool{id: true}
+		queue := []string{id}
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+			for _, next := range dag.Nodes[cur].Blocks {
+				if n := dag.Nodes[next]; n != nil && !seen[next] {
+					seen[next] = true
+					queue = append(queue, next)
+					if depGraphOpen(n) {
+						a.Downstream[id]++
+					}
+				}
+			}
+		}
+	}
+
+	// Critical path: the longest chain of unfinished beads.
+	longest := make(map[string][]string)
+	var chain func(id string) []string
+	chain = func(id string) []string {
+		if c, ok := longest[id]; ok {
+			return c
+		}
+		if walking[id] {
+			return nil
+		}
+		walking[id] = true
+		var best []string
+		next := append([]string(nil), dag.Nodes[id].Blocks...)
+		sort.Strings(next)
+		for _, n := range next {
+			if node := dag.Nodes[n]; node != nil && depGraphOpen(node) {
+				if c := chain(n); len(c) > len(best) {
+					best = c
+				}
+			}
+		}
+		walking[id] = false
+		longest[id] = append([]string{id}, best...)
+		return longest[id]
+	}
+	for _, id := range ids {
+		if depGraphOpen(dag.Nodes[id]) {
+			if c := chain(id); len(c) > len(a.CriticalPath) {
+				a.CriticalPath = c
+			}
+		}
+	}
+	if len(a.CriticalPath) < 2 {
+		a.CriticalPath = nil // A lone bead is no path
+	}
+
+	for _, id := range ids {
+		if a.Downstream[id] > 0 && depGraphOpen(dag.Nodes[id]) {
+			a.TopBlockers = append(a.TopBlockers, id)
+		}
+	}
+	sort.SliceStable(a.TopBlockers, func(i, j int) bool {
+		return a.Downstream[a.TopBlockers[i]] > a.Downstream[a.TopBlockers[j]]
+	})
+	if len(a.TopBlockers) > top {
+		a.TopBlockers = a.TopBlockers[:max(top, 0)]
+	}
+	return a
+}
+
+// criticalEdges returns the blocker→blocked edges along the critical path.
+func (a *depGraphAnalysis) criticalEdges() map[[2]string]bool {
+	edges := make(map[[2]string]bool)
+	for i := 1; i < len(a.CriticalPath); i++ {
+		edges[[2]string{a.CriticalPath[i-1], a.CriticalPath[i]}] = true
+	}
+	return edges
+}
+
+func (a *depGraphAnalysis) onCriticalPath(id string) bool {
+	for _, c := range a.CriticalPath {
+		if c == id {
+			return true
+		}
+	}
+	return false
+}
+
+func depGraphIcon(state string) string {
+	switch state {
+	case "closed":
+		return style.Bold.Render("✓")
+	case "in_progress":
+		return style.Bold.Render("⧖")
+	case "ready":
+		return style.Bold.Render("○")
+	default:
+		return style.Dim.Render("◌")
+	}
+}
+
+// renderDepGraphASCII lists beads by layer: each bead comes after
+// everything it waits on.
+func renderDepGraphASCII(dag *ConvoyDAG, a *depGraphAnalysis) string {
+	var buf strings.Builder
+	open := 0
+	byLayer := make(map[int][]string)
+	maxLayer := 0
+	for _, id := range sortedNodeIDs(dag) {
+		if depGraphOpen(dag.Nodes[id]) {
+			open++
+		}
+		l := a.Layers[id]
+		byLayer[l] = append(byLayer[l], id)
+		maxLayer = max(maxLayer, l)
+	}
+	fmt.Fprintf(&buf, "%s %d beads, %d unfinished\n", style.Bold.Render("Dependency graph:"), len(dag.Nodes), open)
+
+	for l := 0; l <= maxLayer; l++ {
+		fmt.Fprintf(&buf, "\n  %s Layer %d\n", style.Bold.Render("─"), l)
+		for _, id := range byLayer[l] {
+			node := dag.Nodes[id]
+			line := fmt.Sprintf("    %s %s [%s] %s", depGraphIcon(depGraphState(dag, node)), id, node.Type, node.Title)
+			if len(node.BlockedBy) > 0 {
+				blockers := append([]string(nil), node.BlockedBy...)
+				sort.Strings(blockers)
+				line += style.Dim.Render(" ← " + strings.Join(blockers, ", "))
+			}
+			if a.onCriticalPath(id) {
+				line += " " + style.Warning.Render("★")
+			}
+			buf.WriteString(line + "\n")
+		}
+	}
+
+	if len(a.Cycle) > 0 {
+		fmt.Fprintf(&buf, "\n  %s %s\n", style.Warning.Render("Cycle:"), strings.Join(append(a.Cycle, a.Cycle[0]), " → "))
+	}
+	if len(a.CriticalPath) > 0 {
+		fmt.Fprintf(&buf, "\n  %s %s (%d beads)\n", style.Bold.Render("★ Critical path:"), strings.Join(a.CriticalPath, " → "), len(a.CriticalPath))
+	}
+	if len(a.TopBlockers) > 0 {
+		fmt.Fprintf(&buf, "\n  %s\n", style.Bold.Render("Blocking the most work:"))
+		for _, id := range a.TopBlockers {
+			fmt.Fprintf(&buf, "    %-14s %d unfinished downstream  %s\n", id, a.Downstream[id], style.Dim.Render(dag.Nodes[id].Title))
+		}
+	}
+	fmt.Fprintf(&buf, "\n  %s done  %s in progress  %s ready  %s blocked  %s critical path\n",
+		depGraphIcon("closed"), depGraphIcon("in_progress"), depGraphIcon("ready"), depGraphIcon("blocked"), style.Warning.Render("★"))
+	return buf.String()
+}
+
+// depGraphFill colors a bead by state in dot and mermaid output.
+var depGraphFill = map[string]string{
+	"closed":      "#e0e0e0",
+	"in_progress": "#fff2b3",
+	"ready":       "#d5f5d5",
+	"blocked":     "#f8d7d7",
+}
+
+// depGraphLabel is a bead's label in dot and mermaid output: its ID, title
+// (shortened) and downstream count when it is a top blocker.
+func depGraphLabel(dag *ConvoyDAG, a *depGraphAnalysis, id string) (head, title string) {
+	title = dag.Nodes[id].Title
+	if r := []rune(title); len(r) > 40 {
+		title = string(r[:39]) + "…"
+	}
+	head = id
+	for _, b := range a.TopBlockers {
+		if b == id {
+			head = fmt.Sprintf("%s (blocks %d)", id, a.Downstream[id])
+		}
+	}
+	return head, title
+}
+
+// renderDepGraphDOT renders the graph for Graphviz. Edges point from a
+// blocker to the bead it unblocks; the critical path is drawn in red.
+func renderDepGraphDOT(dag *ConvoyDAG, a *depGraphAnalysis) string {
+	quote := func(s string) string {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+	}
+	critical := a.criticalEdges()
+
+	var buf strings.Builder
+	buf.WriteString("digraph deps {\n")
+	buf.WriteString("  rankdir=LR;\n")
+	buf.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+	for _, id := range sortedNodeIDs(dag) {
+		head, title := depGraphLabel(dag, a, id)
+		attrs := fmt.Sprintf("label=%s, fillcolor=%s", quote(head+"\n"+title), quote(depGraphFill[depGraphState(dag, dag.Nodes[id])]))
+		if a.onCriticalPath(id) {
+			attrs += ", color=red, penwidth=2"
+		}
+		fmt.Fprintf(&buf, "  %s [%s];\n", quote(id), attrs)
+	}
+	for _, id := range sortedNodeIDs(dag) {
+		node := dag.Nodes[id]
+		for _, b := range sortedStrings(node.Blocks) {
+			attrs := ""
+			if critical[[2]string{id, b}] {
+				attrs = " [color=red, penwidth=2]"
+			}
+			fmt.Fprintf(&buf, "  %s -> %s%s;\n", quote(id), quote(b), attrs)
+		}
+		for _, c := range sortedStrings(node.Children) {
+			fmt.Fprintf(&buf, "  %s -> %s [style=dashed, arrowhead=none, color=gray];\n", quote(id), quote(c))
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// renderDepGraphMermaid renders the graph as a mermaid flowchart. Bead IDs
+// are mapped to n0, n1, ... since mermaid IDs can't hold every character a
+// bead ID can.
+func renderDepGraphMermaid(dag *ConvoyDAG, a *depGraphAnalysis) string {
+	ids := sortedNodeIDs(dag)
+	ref := make(map[string]string, len(ids))
+	for i, id := range ids {
+		ref[id] = fmt.Sprintf("n%d", i)
+	}
+	escape := strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace
+	critical := a.criticalEdges()
+
+	var buf strings.Builder
+	buf.WriteString("flowchart LR\n")
+	for _, id := range ids {
+		head, title := depGraphLabel(dag, a, id)
+		fmt.Fprintf(&buf, "  %s[\"%s<br/>%s\"]\n", ref[id], escape(head), escape(title))
+	}
+	for _, id := range ids {
+		node := dag.Nodes[id]
+		for _, b := range sortedStrings(node.Blocks) {
+			arrow := "-->"
+			if critical[[2]string{id, b}] {
+				arrow = "==>"
+			}
+			fmt.Fprintf(&buf, "  %s %s %s\n", ref[id], arrow, ref[b])
+		}
+		for _, c := range sortedStrings(node.Children) {
+			fmt.Fprintf(&buf, "  %s -.- %s\n", ref[id], ref[c])
+		}
+	}
+	for _, state := range []string{"closed", "in_progress", "ready", "blocked"} {
+		fmt.Fprintf(&buf, "  classDef %s fill:%s\n", state, depGraphFill[state])
+	}
+	buf.WriteString("  classDef critical stroke:#d00,stroke-width:3px\n")
+	for _, id := range ids {
+		fmt.Fprintf(&buf, "  class %s %s\n", ref[id], depGraphState(dag, dag.Nodes[id]))
+		if a.onCriticalPath(id) {
+			fmt.Fprintf(&buf, "  class %s critical\n", ref[id])
+		}
+	}
+	return buf.String()
+}
+
+func sortedStrings(in []string) []string {
+	out := append([]string(nil), in...)
+	sort.Strings(out)
+	return out
+}
+
+// depGraphNodeJSON is one bead in gt deps graph --json.
+type depGraphNodeJSON struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Type       string   `json:"type"`
+	Status     string   `json:"status"`
+	State      string   `json:"state"`
+	Layer      int      `json:"layer"`
+	BlockedBy  []string `json:"blocked_by,omitempty"`
+	Blocks     []string `json:"blocks,omitempty"`
+	Children   []string `json:"children,omitempty"`
+	Downstream int      `json:"downstream"`
+	Critical   bool     `json:"critical,omitempty"`
+}
+
+func outputDepGraphJSON(dag *ConvoyDAG, a *depGraphAnalysis) error {
+	out := struct {
+		Nodes        []depGraphNodeJSON `json:"nodes"`
+		CriticalPath []string           `json:"critical_path,omitempty"`
+		TopBlockers  []string           `json:"top_blockers,omitempty"`
+		Cycle        []string           `json:"cycle,omitempty"`
+	}{CriticalPath: a.CriticalPath, TopBlockers: a.TopBlockers, Cycle: a.Cycle}
+	for _, id := range sortedNodeIDs(dag) {
+		node := dag.Nodes[id]
+		out.Nodes = append(out.Nodes, depGraphNodeJSON{
+			ID:         id,
+			Title:      node.Title,
+			Type:       node.Type,
+			Status:     node.Status,
+			State:      depGraphState(dag, node),
+			Layer:      a.Layers[id],
+			BlockedBy:  sortedStrings(node.BlockedBy),
+			Blocks:     sortedStrings(node.Blocks),
+			Children:   sortedStrings(node.Children),
+			Downstream: a.Downstream[id],
+			Critical:   a.onCriticalPath(id),
+		})
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnalyzeDepGraph(t *testing.T) {
+	// a → b → d → e, a → c, with a closed: the critical path skips it.
+	dag := buildConvoyDAG([]BeadInfo{
+		{ID: "gt-a", Title: "Schema", Type: "task", Status: "closed"},
+		{ID: "gt-b", Title: "Migration", Type: "task", Status: "in_progress"},
+		{ID: "gt-c", Title: "Docs", Type: "task", Status: "open"},
+		{ID: "gt-d", Title: "API", Type: "task", Status: "open"},
+		{ID: "gt-e", Title: "UI \"beta\"", Type: "task", Status: "open"},
+		{ID: "gt-epic", Title: "Epic", Type: "epic", Status: "open"},
+	}, []DepInfo{
+		{IssueID: "gt-b", DependsOnID: "gt-a", Type: "blocks"},
+		{IssueID: "gt-c", DependsOnID: "gt-a", Type: "blocks"},
+		{IssueID: "gt-d", DependsOnID: "gt-b", Type: "blocks"},
+		{IssueID: "gt-e", DependsOnID: "gt-d", Type: "waits-for"},
+		{IssueID: "gt-b", DependsOnID: "gt-epic", Type: "parent-child"},
+	})
+	a := analyzeDepGraph(dag, 2)
+
+	if got := strings.Join(a.CriticalPath, ","); got != "gt-b,gt-d,gt-e" {
+		t.Errorf("CriticalPath = %s", got)
+	}
+	if a.Layers["gt-e"] != 3 || a.Layers["gt-c"] != 1 || a.Layers["gt-epic"] != 0 {
+		t.Errorf("Layers = %v", a.Layers)
+	}
+	if a.Downstream["gt-a"] != 4 || a.Downstream["gt-b"] != 2 || a.Downstream["gt-e"] != 0 {
+		t.Errorf("Downstream = %v", a.Downstream)
+	}
+	// gt-a is done, so it blocks nothing any more.
+	if got := strings.Join(a.TopBlockers, ","); got != "gt-b,gt-d" {
+		t.Errorf("TopBlockers = %s", got)
+	}
+	if got := depGraphState(dag, dag.Nodes["gt-c"]); got != "ready" {
+		t.Errorf("gt-c state = %s, want ready", got)
+	}
+	if got := depGraphState(dag, dag.Nodes["gt-d"]); got != "blocked" {
+		t.Errorf("gt-d state = %s, want blocked", got)
+	}
+
+	dot := renderDepGraphDOT(dag, a)
+	for _, want := range []string{`"gt-b" -> "gt-d" [color=red, penwidth=2];`, `"gt-a" -> "gt-c";`, `UI \"beta\"`, `"gt-epic" -> "gt-b" [style=dashed`} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("dot missing %q:\n%s", want, dot)
+		}
+	}
+	mermaid := renderDepGraphMermaid(dag, a)
+	for _, want := range []string{"flowchart LR", "n1 ==> n3", "n0 --> n2", "UI #quot;beta#quot;", "class n1 critical"} {
+		if !strings.Contains(mermaid, want) {
+			t.Errorf("mermaid missing %q:\n%s", want, mermaid)
+		}
+	}
+}
+
+func TestAnalyzeDepGraph_Cycle(t *testing.T) {
+	dag := buildConvoyDAG([]BeadInfo{
+		{ID: "gt-a", Type: "task", Status: "open"},
+		{ID: "gt-b", Type: "task", Status: "open"},
+	}, []DepInfo{
+		{IssueID: "gt-b", DependsOnID: "gt-a", Type: "blocks"},
+		{IssueID: "gt-a", DependsOnID: "gt-b", Type: "blocks"},
+	})
+	a := analyzeDepGraph(dag, 5)
+	if len(a.Cycle) == 0 {
+		t.Error("cycle not reported")
+	}
+	if len(a.CriticalPath) != 2 {
+		t.Errorf("CriticalPath = %v", a.CriticalPath)
+	}
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/depupdate"
+)
+
+// fakeDeps stands in for the checkers, bd and gt sling: the updates each
+// check finds, bead statuses by ID, and the beads slung.
+type fakeDeps struct {
+	updates  []depupdate.Update
+	statuses map[string]string
+	checks   int
+	slung    []string
+	quiet    bool
+}
+
+func newFakeDeps(t *testing.T) *fakeDeps {
+	t.Helper()
+	origCheck, origCreate, origStatus, origSling, origQuiet := depsCheckFn, depsCreateFn, depsStatusFn, depsSlingFn, depsQuietActiveFn
+	t.Cleanup(func() {
+		depsCheckFn, depsCreateFn, depsStatusFn, depsSlingFn, depsQuietActiveFn = origCheck, origCreate, origStatus, origSling, origQuiet
+	})
+	f := &fakeDeps{statuses: make(map[string]string)}
+	depsCheckFn = func(checkout string, cfg *depupdate.Config) ([]depupdate.Update, error) {
+		f.checks++
+		return f.updates, nil
+	}
+	depsCreateFn = func(townRoot, rigName, title, description string) (string, error) {
+		id := fmt.Sprintf("gp-%d", len(f.statuses)+1)
+		f.statuses[id] = "open"
+		return id, nil
+	}
+	depsStatusFn = func(townRoot, rigName, id string) (string, error) {
+		return f.statuses[id], nil
+	}
+	depsSlingFn = func(townRoot, id, rigName string) error {
+		f.slung = append(f.slung, id)
+		return nil
+	}
+	depsQuietActiveFn = func(townRoot, rigName string, now time.Time) bool { return f.quiet }
+	return f
+}
+
+func TestSweepRigDeps(t *testing.T) {
+	f := newFakeDeps(t)
+	f.updates = []depupdate.Update{
+		{Ecosystem: "go", Dir: ".", Name: "github.com/spf13/cobra", Current: "v1.8.0", Latest: "v1.9.1"},
+		{Ecosystem: "go", Dir: ".", Name: "github.com/google/go-github", Current: "v1.0.0", Latest: "v2.0.0"},
+	}
+	r := depsRig{Name: "greenplace", Path: t.TempDir(), Cfg: &depupdate.Config{Enabled: true, Sling: depupdate.SlingQuietHours}}
+	now := time.Date(2026, 3, 1, 14, 0, 0, 0, time.UTC)
+
+	if err := sweepRigDeps("/town", r, now, false, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.statuses) != 2 || len(f.slung) != 0 {
+		t.Fatalf("first sweep filed %v, slung %v; want 2 beads, none slung outside quiet hours", f.statuses, f.slung)
+	}
+
+	// Not due yet: no check. Quiet hours: the filed beads are slung.
+	f.quiet = true
+	if err := sweepRigDeps("/town", r, now.Add(time.Hour), false, false); err != nil {
+		t.Fatal(err)
+	}
+	if f.checks != 1 || len(f.slung) != 2 {
+		t.Fatalf("checks = %d, slung = %v", f.checks, f.slung)
+	}
+
+	// Due again with the beads still open: nothing new is filed or slung.
+	if err := sweepRigDeps("/town", r, now.Add(depupdate.DefaultEvery), false, false); err != nil {
+		t.Fatal(err)
+	}
+	if f.checks != 2 || len(f.statuses) != 2 || len(f.slung) != 2 {
+		t.Fatalf("open batches were filed again: %v, slung %v", f.statuses, f.slung)
+	}
+
+	// The major upgrade's bead closed but it's still outdated: refiled.
+	state, err := depupdate.LoadState(r.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.statuses[state.Filed["go:github.com/google/go-github@major"].Bead] = "closed"
+	if err := sweepRigDeps("/town", r, now.Add(2*depupdate.DefaultEvery), false, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.statuses) != 3 || len(f.slung) != 3 {
+		t.Errorf("closed batch not refiled: %v, slung %v", f.statuses, f.slung)
+	}
+}
+
+func TestSweepRigDepsDryRun(t *testing.T) {
+	f := newFakeDeps(t)
+	f.updates = []depupdate.Update{{Ecosystem: "npm", Dir: ".", Name: "react", Current: "18.2.0", Latest: "18.3.1"}}
+	r := depsRig{Name: "greenplace", Path: t.TempDir(), Cfg: &depupdate.Config{Enabled: true, Sling: depupdate.SlingImmediate}}
+
+	if err := sweepRigDeps("/town", r, time.Now(), true, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.statuses) != 0 || len(f.slung) != 0 {
+		t.Errorf("dry run filed %v, slung %v", f.statuses, f.slung)
+	}
+	state, err := depupdate.LoadState(r.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.LastCheck.IsZero() {
+		t.Error("dry run should not record the check")
+	}
+}
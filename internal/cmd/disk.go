@@ -0,0 +1,394 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/diskquota"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	diskJSON    bool
+	diskRefresh bool
+	diskDryRun  bool
+)
+
+var diskCmd = &cobra.Command{
+	Use:     "disk [rig]",
+	GroupID: GroupDiag,
+	Short:   "Show disk usage per rig against its disk quota",
+	Long: `Show how much disk each rig uses, split into worktrees (polecat, crew and
+refinery checkouts), agent transcripts and artifacts (shared build caches
+and runtime files), against the rig's disk_quota:
+
+  "disk_quota": {
+    "enabled": true,
+    "limit": "40GB",
+    "warn": "30GB"
+  }
+
+Warn defaults to 80% of the limit. Over warn, polecat spawns print a
+warning; over the limit they are refused until gt disk gc frees space.
+The daemon's disk_quota patrol runs gt disk check, which mails the mayor
+when a rig crosses a threshold and runs gc on rigs over their limit.
+
+Measurements are reused for 15 minutes; use --refresh to measure now.
+
+Examples:
+  gt disk                    # All rigs
+  gt disk greenplace --refresh
+  gt disk --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDisk,
+}
+
+var diskGCCmd = &cobra.Command{
+	Use:   "gc <rig>",
+	Short: "Free disk in a rig by removing idle polecat worktrees",
+	Long: `Remove idle polecats whose worktrees hold nothing unsaved, largest
+first, until the rig is back under its warn threshold.
+
+A polecat is only removed when it has no hooked work, no running session,
+no uncommitted or unpushed changes and no stashes. Its name returns to
+the pool and the next sling creates a fresh worktree.
+
+Examples:
+  gt disk gc greenplace
+  gt disk gc greenplace --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDiskGC,
+}
+
+var diskCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Measure every rig with a quota, alert and gc (daemon)",
+	Long: `Measure every rig with a disk_quota enabled. When a rig crosses its warn
+threshold or limit the mayor is mailed; rigs over their limit are garbage
+collected as with gt disk gc. The daemon's disk_quota patrol runs this.
+
+Examples:
+  gt disk check`,
+	Args: cobra.NoArgs,
+	RunE: runDiskCheck,
+}
+
+var (
+	// diskIdlePolecatsFn is a seam for tests. Production uses idleCleanPolecats.
+	diskIdlePolecatsFn = idleCleanPolecats
+
+	// diskNukePolecatFn is a seam for tests. Production uses nukePolecatFull.
+	diskNukePolecatFn = func(rigName, name string) error {
+		mgr, r, err := getPolecatManager(rigName)
+		if err != nil {
+			return err
+		}
+		return nukePolecatFull(name, rigName, mgr, r)
+	}
+
+	// diskNotifyFn is a seam for tests. Production uses notifyDiskQuota.
+	diskNotifyFn = notifyDiskQuota
+)
+
+func init() {
+	diskCmd.Flags().BoolVar(&diskJSON, "json", false, "Output as JSON")
+	diskCmd.Flags().BoolVar(&diskRefresh, "refresh", false, "Measure now instead of reusing a recent measurement")
+	diskGCCmd.Flags().BoolVar(&diskDryRun, "dry-run", false, "Show what would be removed")
+
+	diskCmd.AddCommand(diskGCCmd)
+	diskCmd.AddCommand(diskCheckCmd)
+	rootCmd.AddCommand(diskCmd)
+}
+
+// rigDisk is one rig's usage and quota.
+type rigDisk struct {
+	Rig   string            `json:"rig"`
+	Usage *diskquota.Usage  `json:"usage"`
+	Quota *diskquota.Config `json:"quota,omitempty"`
+	Level string            `json:"level"`
+	Error string            `json:"error,omitempty"`
+	path  string
+}
+
+// loadDiskQuota returns a rig's disk_quota, or nil if none is configured.
+func loadDiskQuota(rigPath string) (*diskquota.Config, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return settings.DiskQuota, nil
+}
+
+// measureRigDisk measures a rig, counting its shared build cache as artifacts.
+func measureRigDisk(townRoot, rigName string, refresh bool) rigDisk {
+	rigPath := filepath.Join(townRoot, rigName)
+	rd := rigDisk{Rig: rigName, path: rigPath}
+	quota, err := loadDiskQuota(rigPath)
+	if err != nil {
+		rd.Error = err.Error()
+	}
+	rd.Quota = quota
+	var artifacts []string
+	if bc := config.ResolveBuildCache(rigPath); bc != nil {
+		artifacts = append(artifacts, bc.Root(rigPath))
+	}
+	rd.Usage = diskquota.Load(rigPath, artifacts, refresh)
+	rd.Level = quota.Level(rd.Usage.Total)
+	return rd
+}
+
+// diskRigNames returns the registered rigs, sorted.
+func diskRigNames(townRoot string) []string {
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON))
+	if err != nil || rigsConfig == nil {
+		return nil
+	}
+	var names []string
+	for name := range rigsConfig.Rigs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func runDisk(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigs := args
+	if len(rigs) == 0 {
+		rigs = diskRigNames(townRoot)
+	}
+
+	var report []rigDisk
+	for _, rigName := range rigs {
+		if _, err := os.Stat(filepath.Join(townRoot, rigName)); err != nil {
+			return fmt.Errorf("rig '%s' not found", rigName)
+		}
+		report = append(report, measureRigDisk(townRoot, rigName, diskRefresh))
+	}
+
+	if diskJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	if len(report) == 0 {
+		fmt.Println("No rigs.")
+		return nil
+	}
+	fmt.Printf("%-16s %10s %12s %10s %10s  %s\n", "RIG", "WORKTREES", "TRANSCRIPTS", "ARTIFACTS", "TOTAL", "QUOTA")
+	for _, rd := range report {
+		fmt.Printf("%-16s %10s %12s %10s %10s  %s\n", rd.Rig,
+			formatBytes(rd.Usage.Worktrees), formatBytes(rd.Usage.Transcripts),
+			formatBytes(rd.Usage.Artifacts), formatBytes(rd.Usage.Total), formatDiskQuota(rd))
+	}
+	return nil
+}
+
+// formatDiskQuota renders a rig's quota column, e.g. "38% of 40.0 GB".
+func formatDiskQuota(rd rigDisk) string {
+	if rd.Error != "" {
+		return style.Error.Render(rd.Error)
+	}
+	if !rd.Quota.IsEnabled() || rd.Quota.LimitBytes() == 0 {
+		return style.Dim.Render("none")
+	}
+	limit := rd.Quota.LimitBytes()
+	s := fmt.Sprintf("%d%% of %s", rd.Usage.Total*100/limit, formatBytes(limit))
+	switch rd.Level {
+	case diskquota.LevelOver:
+		return style.Error.Render(s + " — spawns blocked")
+	case diskquota.LevelWarn:
+		return style.Warning.Render(s)
+	}
+	return s
+}
+
+// checkRigDiskQuota refuses a polecat spawn in a rig over its disk quota and
+// warns when the rig is over its warn threshold.
+func checkRigDiskQuota(townRoot, rigName string) error {
+	quota, err := loadDiskQuota(filepath.Join(townRoot, rigName))
+	if err != nil || !quota.IsEnabled() {
+		return nil
+	}
+	rd := measureRigDisk(townRoot, rigName, false)
+	switch rd.Level {
+	case diskquota.LevelOver:
+		return fmt.Errorf("rig %s is over its disk quota (%s of %s). "+
+			"Free space with: gt disk gc %s",
+			rigName, formatBytes(rd.Usage.Total), formatBytes(quota.LimitBytes()), rigName)
+	case diskquota.LevelWarn:
+		style.PrintWarning("rig %s is using %s of its %s disk quota", rigName,
+			formatBytes(rd.Usage.Total), formatBytes(quota.LimitBytes()))
+	}
+	return nil
+}
+
+// idleCleanPolecats returns the rig's idle polecats whose worktrees hold no
+// uncommitted, unpushed or stashed work, so removing them loses nothing.
+func idleCleanPolecats(rigName string) (map[string]bool, error) {
+	mgr, _, err := getPolecatManager(rigName)
+	if err != nil {
+		return nil, err
+	}
+	polecats, err := mgr.List()
+	if err != nil {
+		return nil, err
+	}
+	idle := make(map[string]bool)
+	for _, p := range polecats {
+		if p.State != polecat.StateIdle || p.Issue != "" {
+			continue
+		}
+		state, err := getGitState(p.ClonePath)
+		if err != nil || !state.Clean || state.UnpushedCommits > 0 || state.StashCount > 0 {
+			continue
+		}
+		idle[p.Name] = true
+	}
+	return idle, nil
+}
+
+// diskGCPlan picks idle polecats to remove, largest first, until the
+// projected total falls under the warn threshold.
+func diskGCPlan(usage *diskquota.Usage, quota *diskquota.Config, idle map[string]bool) []diskquota.Dir {
+	target := quota.WarnBytes()
+	total := usage.Total
+	var plan []diskquota.Dir
+	for _, d := range usage.Polecats {
+		if total < target {
+			break
+		}
+		if idle[filepath.Base(d.Path)] {
+			plan = append(plan, d)
+			total -= d.Bytes
+		}
+	}
+	return plan
+}
+
+func runDiskGC(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	_, err = diskGC(townRoot, args[0], diskDryRun)
+	return err
+}
+
+// diskGC removes idle polecats per diskGCPlan and returns the rig's usage
+// afterwards (remeasured unless dryRun).
+func diskGC(townRoot, rigName string, dryRun bool) (rigDisk, error) {
+	rd := measureRigDisk(townRoot, rigName, true)
+	if rd.Error != "" {
+		return rd, errors.New(rd.Error)
+	}
+	if !rd.Quota.IsEnabled() {
+		return rd, fmt.Errorf("rig %s has no disk_quota enabled", rigName)
+	}
+	if rd.Usage.Total < rd.Quota.WarnBytes() {
+		fmt.Printf("%s is under its warn threshold (%s of %s); nothing to do.\n",
+			rigName, formatBytes(rd.Usage.Total), formatBytes(rd.Quota.WarnBytes()))
+		return rd, nil
+	}
+	idle, err := diskIdlePolecatsFn(rigName)
+	if err != nil {
+		return rd, fmt.Errorf("listing idle polecats: %w", err)
+	}
+	plan := diskGCPlan(rd.Usage, rd.Quota, idle)
+	if len(plan) == 0 {
+		style.PrintWarning("%s is at %s but has no idle polecats to remove; check crew worktrees and build caches (gt disk %s)",
+			rigName, formatBytes(rd.Usage.Total), rigName)
+		return rd, nil
+	}
+
+	var freed int64
+	for _, d := range plan {
+		name := filepath.Base(d.Path)
+		if dryRun {
+			fmt.Printf("Would remove %s/%s (%s)\n", rigName, name, formatBytes(d.Bytes))
+			continue
+		}
+		fmt.Printf("Removing %s/%s (%s)...\n", rigName, name, formatBytes(d.Bytes))
+		if err := diskNukePolecatFn(rigName, name); err != nil {
+			fmt.Printf("  %s (%v)\n", style.Error.Render("failed"), err)
+			continue
+		}
+		freed += d.Bytes
+	}
+	if dryRun {
+		return rd, nil
+	}
+	rd = measureRigDisk(townRoot, rigName, true)
+	fmt.Printf("%s Freed %s; %s now uses %s.\n", style.SuccessPrefix, formatBytes(freed), rigName, formatBytes(rd.Usage.Total))
+	return rd, nil
+}
+
+func runDiskCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	for _, rigName := range diskRigNames(townRoot) {
+		quota, err := loadDiskQuota(filepath.Join(townRoot, rigName))
+		if err != nil || !quota.IsEnabled() {
+			continue
+		}
+		rd := measureRigDisk(townRoot, rigName, true)
+		if rd.Level == diskquota.LevelOver {
+			if after, err := diskGC(townRoot, rigName, false); err != nil {
+				style.PrintWarning("%s: gc failed: %v", rigName, err)
+			} else {
+				rd.Level, rd.Usage = after.Level, after.Usage
+			}
+		}
+		last := diskquota.LastLevel(rd.path)
+		if rd.Level != last && rd.Level != diskquota.LevelOK {
+			if err := diskNotifyFn(townRoot, rd); err != nil {
+				style.PrintWarning("%s: notifying mayor: %v", rigName, err)
+			}
+		}
+		if rd.Level != last {
+			fmt.Printf("%s: %s → %s (%s)\n", rigName, last, rd.Level, formatBytes(rd.Usage.Total))
+		}
+		_ = diskquota.SetLastLevel(rd.path, rd.Level)
+	}
+	return nil
+}
+
+// notifyDiskQuota mails the mayor that a rig crossed a quota threshold.
+func notifyDiskQuota(townRoot string, rd rigDisk) error {
+	subject := fmt.Sprintf("Disk quota: %s at %s of %s", rd.Rig,
+		formatBytes(rd.Usage.Total), formatBytes(rd.Quota.LimitBytes()))
+	body := fmt.Sprintf("Worktrees:   %s\nTranscripts: %s\nArtifacts:   %s\n\n",
+		formatBytes(rd.Usage.Worktrees), formatBytes(rd.Usage.Transcripts), formatBytes(rd.Usage.Artifacts))
+	if rd.Level == diskquota.LevelOver {
+		body += fmt.Sprintf("New polecat spawns in %s are refused until usage drops under the limit.\n"+
+			"Idle polecats were already removed; look for large crew worktrees or caches:\n  gt disk %s --refresh\n", rd.Rig, rd.Rig)
+	} else {
+		body += fmt.Sprintf("Over the warn threshold (%s). Spawns are still allowed.\n", formatBytes(rd.Quota.WarnBytes()))
+	}
+
+	msg := mail.NewMessage(detectSender(), "mayor/", subject, body)
+	if rd.Level == diskquota.LevelOver {
+		msg.Priority = mail.PriorityHigh
+	}
+	router := mail.NewRouter(townRoot)
+	defer router.WaitPendingNotifications()
+	return router.Send(msg)
+}
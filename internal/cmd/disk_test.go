@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/diskquota"
+)
+
+// setupDiskTown creates a town whose rig greenplace has a 10KB disk quota
+// (warn at 6KB) and two polecat worktrees (toast 8KB, nux 3KB).
+func setupDiskTown(t *testing.T) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	townRoot := t.TempDir()
+	files := map[string]string{
+		"mayor/rigs.json":                             `{"version": 1, "rigs": {"greenplace": {}}}`,
+		"greenplace/settings/config.json":             `{"type": "rig-settings", "version": 1, "disk_quota": {"enabled": true, "limit": "10KB", "warn": "6KB"}}`,
+		"greenplace/polecats/toast/greenplace/big.js": strings.Repeat("x", 8<<10),
+		"greenplace/polecats/nux/greenplace/main.go":  strings.Repeat("x", 3<<10),
+	}
+	for name, content := range files {
+		path := filepath.Join(townRoot, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return townRoot
+}
+
+func TestDiskGCPlan(t *testing.T) {
+	quota := &diskquota.Config{Enabled: true, Limit: "10KB", Warn: "4KB"}
+	usage := &diskquota.Usage{
+		Total: 12 << 10,
+		Polecats: []diskquota.Dir{
+			{Path: "/town/greenplace/polecats/toast", Bytes: 6 << 10},
+			{Path: "/town/greenplace/polecats/nux", Bytes: 3 << 10},
+			{Path: "/town/greenplace/polecats/furiosa", Bytes: 2 << 10},
+		},
+	}
+
+	plan := diskGCPlan(usage, quota, map[string]bool{"toast": true, "nux": true, "furiosa": true})
+	if len(plan) != 2 || filepath.Base(plan[0].Path) != "toast" || filepath.Base(plan[1].Path) != "nux" {
+		t.Errorf("plan = %+v, want toast then nux (stop once under warn)", plan)
+	}
+
+	plan = diskGCPlan(usage, quota, map[string]bool{"nux": true})
+	if len(plan) != 1 || filepath.Base(plan[0].Path) != "nux" {
+		t.Errorf("plan = %+v, want only the idle polecat nux", plan)
+	}
+}
+
+func TestCheckRigDiskQuota(t *testing.T) {
+	townRoot := setupDiskTown(t)
+
+	err := checkRigDiskQuota(townRoot, "greenplace")
+	if err == nil || !strings.Contains(err.Error(), "over its disk quota") {
+		t.Fatalf("checkRigDiskQuota() = %v, want over-quota error", err)
+	}
+
+	if err := os.RemoveAll(filepath.Join(townRoot, "greenplace", "polecats", "toast")); err != nil {
+		t.Fatal(err)
+	}
+	measureRigDisk(townRoot, "greenplace", true)
+	if err := checkRigDiskQuota(townRoot, "greenplace"); err != nil {
+		t.Errorf("checkRigDiskQuota() after freeing space = %v, want nil", err)
+	}
+}
+
+func TestDiskCheckRunsGCAndNotifiesOnce(t *testing.T) {
+	townRoot := setupDiskTown(t)
+	t.Chdir(townRoot)
+
+	origIdle, origNuke, origNotify := diskIdlePolecatsFn, diskNukePolecatFn, diskNotifyFn
+	t.Cleanup(func() { diskIdlePolecatsFn, diskNukePolecatFn, diskNotifyFn = origIdle, origNuke, origNotify })
+
+	var nuked []string
+	diskIdlePolecatsFn = func(string) (map[string]bool, error) {
+		return map[string]bool{"toast": true}, nil
+	}
+	diskNukePolecatFn = func(rigName, name string) error {
+		nuked = append(nuked, name)
+		return os.RemoveAll(filepath.Join(townRoot, rigName, "polecats", name))
+	}
+	var notified []string
+	diskNotifyFn = func(_ string, rd rigDisk) error {
+		notified = append(notified, rd.Level)
+		return nil
+	}
+
+	if err := runDiskCheck(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(nuked) != 1 || nuked[0] != "toast" {
+		t.Errorf("nuked = %v, want [toast]", nuked)
+	}
+	// nux is left: under the 6KB warn threshold, so nothing to report.
+	if len(notified) != 0 {
+		t.Errorf("notified = %v, want none once gc brought the rig under warn", notified)
+	}
+
+	// Growing past warn alerts once, not on every check.
+	path := filepath.Join(townRoot, "greenplace", "crew", "max", "data")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", 3<<10)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := runDiskCheck(nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(notified) != 1 || notified[0] != diskquota.LevelWarn {
+		t.Errorf("notified = %v, want one warn alert", notified)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/doltserver"
 	"github.com/steveyegge/gastown/internal/style"
@@ -1314,7 +1315,7 @@ func runDoltRollback(cmd *cobra.Command, args []string) error {
 	fmt.Println("\nValidating restored state...")
 	validateCmd := exec.Command("bd", "list", "--limit", "5")
 	validateCmd.Dir = townRoot
-	output, validateErr := validateCmd.CombinedOutput()
+	output, validateErr := beads.CmdCombinedOutput(validateCmd)
 	if validateErr != nil {
 		fmt.Printf("  %s bd list returned an error: %v\n",
 			style.Dim.Render("⚠"), validateErr)
@@ -144,6 +144,10 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 		return fmt.Errorf("cannot determine current rig (working directory may be deleted)")
 	}
 
+	// Work branches go to the rig's branch remote: origin, or a fork set
+	// with gt rig set-remote --push branches.
+	branchRemote := rig.LoadRemotes(filepath.Join(townRoot, rigName)).BranchRemote()
+
 	// When gt is invoked via shell alias (cd ~/gt && gt), or when Claude Code
 	// resets the shell CWD to mayor/rig, cwd is NOT the polecat's worktree.
 	// Detect and reconstruct actual path.
@@ -251,7 +255,7 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 					// CheckUncommittedWork.UnpushedCommits doesn't work for branches
 					// without upstream tracking (common for polecats). Use the more
 					// robust BranchPushedToRemote which compares against origin/main.
-					pushed, unpushedCount, err := g.BranchPushedToRemote(branch, "origin")
+					pushed, unpushedCount, err := g.BranchPushedToRemote(branch, branchRemote)
 					if err != nil {
 						style.PrintWarning("could not check if branch is pushed: %v", err)
 						doneCleanupStatus = "unpushed" // err on side of caution
@@ -492,6 +496,37 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			}
 		}
 
+		// Static analysis gate: run the rig's analyzers on this branch's diff
+		// and refuse to submit while violations remain on changed lines. The
+		// fix-it prompt is printed here so the polecat sees it in-session.
+		// Skipped on resume after push: the gate already passed.
+		if checkpoints[CheckpointPushed] == "" {
+			if err := checkStaticAnalysisForDone(filepath.Join(townRoot, rigName), cwd, originDefault); err != nil {
+				return err
+			}
+		}
+
+		// Acceptance gate: when the rig enables verify_done, a verifier agent
+		// checks the diff and test output against the bead's acceptance
+		// criteria and bounces the work back with specific gaps.
+		if checkpoints[CheckpointPushed] == "" {
+			if err := checkVerifyForDone(townRoot, filepath.Join(townRoot, rigName), cwd, issueID, originDefault); err != nil {
+				return err
+			}
+		}
+
+		// Changelog gate: when the rig requires fragments, user-facing changes
+		// must come with the bead's changelog fragment.
+		if checkpoints[CheckpointPushed] == "" {
+			if err := checkChangelogForDone(filepath.Join(townRoot, rigName), cwd, issueID, originDefault); err != nil {
+				return err
+			}
+		}
+
+		// Index the completed work into the rig knowledge base so future
+		// slings of similar beads see how this one was solved. Best-effort.
+		recordSolutionKnowledge(townRoot, rigName, issueID, sender, g, originDefault)
+
 		// Determine merge strategy from convoy (gt-myofa.3)
 		// Convoys can override the default MR-based workflow:
 		//   direct: push commits straight to target branch, bypass refinery
@@ -581,7 +616,7 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 		// bypassing the MR/refinery flow (G20 root cause).
 		fmt.Printf("Pushing branch to remote...\n")
 		refspec = branch + ":" + branch
-		pushErr = g.Push("origin", refspec, false)
+		pushErr = g.Push(branchRemote, refspec, false)
 		if pushErr != nil {
 			// Primary push failed — try fallback from the bare repo (GH #1348).
 			// When polecat sessions are reused or worktrees are stale, the worktree's
@@ -592,7 +627,7 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			bareRepoPath := filepath.Join(rigPath, ".repo.git")
 			if _, statErr := os.Stat(bareRepoPath); statErr == nil {
 				bareGit := git.NewGitWithDir(bareRepoPath, "")
-				pushErr = bareGit.Push("origin", refspec, false)
+				pushErr = bareGit.Push(branchRemote, refspec, false)
 				if pushErr != nil {
 					style.PrintWarning("bare repo push also failed: %v", pushErr)
 				} else {
@@ -603,7 +638,7 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 				mayorPath := filepath.Join(rigPath, "mayor", "rig")
 				if _, statErr := os.Stat(mayorPath); statErr == nil {
 					mayorGit := git.NewGit(mayorPath)
-					pushErr = mayorGit.Push("origin", refspec, false)
+					pushErr = mayorGit.Push(branchRemote, refspec, false)
 					if pushErr != nil {
 						style.PrintWarning("mayor/rig push also failed: %v", pushErr)
 					} else {
@@ -625,7 +660,7 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 		// Verify the branch actually exists on remote (GH #1348).
 		// Push can return exit 0 without actually pushing (e.g., stale refs,
 		// worktree/bare-repo state mismatch). Verify before creating MR bead.
-		if exists, verifyErr := g.RemoteBranchExists("origin", branch); verifyErr != nil {
+		if exists, verifyErr := g.RemoteBranchExists(branchRemote, branch); verifyErr != nil {
 			style.PrintWarning("could not verify push: %v (proceeding optimistically)", verifyErr)
 		} else if !exists {
 			// Push "succeeded" but branch not on remote — try bare repo verification
@@ -634,7 +669,7 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			bareRepoPath := filepath.Join(rigPath, ".repo.git")
 			if _, statErr := os.Stat(bareRepoPath); statErr == nil {
 				bareGit := git.NewGitWithDir(bareRepoPath, "")
-				exists, verifyErr = bareGit.RemoteBranchExists("origin", branch)
+				exists, verifyErr = bareGit.RemoteBranchExists(branchRemote, branch)
 			}
 			if verifyErr != nil || !exists {
 				pushFailed = true
@@ -644,7 +679,7 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 				goto notifyWitness
 			}
 		}
-		fmt.Printf("%s Branch pushed to origin\n", style.Bold.Render("✓"))
+		fmt.Printf("%s Branch pushed to %s\n", style.Bold.Render("✓"), branchRemote)
 
 		// Fix cleanup_status after successful push (gt-wcr).
 		// Status was detected before push, so "unpushed" is now stale.
@@ -800,6 +835,7 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 
 		// Pre-declare for checkpoint goto (gt-aufru)
 		var existingMR *beads.Issue
+		var linkedConvoy string
 
 		// Resume: skip MR creation if already completed in a previous run (gt-aufru).
 		// Mirrors the push checkpoint pattern above. Without this, every retry
@@ -817,6 +853,12 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 			// Continue with creation attempt - Create will fail if duplicate
 		}
 
+		if convoyInfo != nil {
+			if convoy, err := linkedShowConvoyFn(townRoot, convoyInfo.ID); err == nil && beads.ParseConvoyFields(convoy).IsLinked() {
+				linkedConvoy = convoyInfo.ID
+			}
+		}
+
 		if existingMR != nil {
 			// MR already exists - use it instead of creating a new one
 			mrID = existingMR.ID
@@ -853,9 +895,17 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 				}
 			}
 
+			// Linked convoys: tag the MR so the refinery holds it until every
+			// member of the multi-repo change has submitted.
+			mrLabels := []string{"gt:merge-request"}
+			if linkedConvoy != "" {
+				description += fmt.Sprintf("\nconvoy_id: %s", linkedConvoy)
+				mrLabels = append(mrLabels, "gt:linked")
+			}
+
 			mrIssue, err := bd.Create(beads.CreateOptions{
 				Title:       title,
-				Labels:      []string{"gt:merge-request"},
+				Labels:      mrLabels,
 				Priority:    priority,
 				Description: description,
 				Ephemeral:   true,
@@ -908,6 +958,9 @@ func runDone(cmd *cobra.Command, args []string) (retErr error) {
 					style.PrintWarning("could not back-link source issue %s to MR %s: %v", issueID, mrID, err)
 				}
 			}
+			if linkedConvoy != "" {
+				recordLinkedMR(linkedConvoy, issueID, rigName, branch, mrID)
+			}
 
 			// Success output
 			fmt.Printf("%s Work submitted to merge queue (verified)\n", style.Bold.Render("✓"))
@@ -950,6 +1003,11 @@ notifyWitness:
 		nudgeRefinery(rigName, "MERGE_READY received - check inbox for pending work")
 	}
 
+	// Return micro-tasks the session didn't reach (gt sling --micro)
+	if issueID != "" {
+		finishMicroQueue(townRoot, issueID)
+	}
+
 	// Write completion metadata to agent bead for audit trail.
 	// Self-managed completion (gt-1qlg): metadata is retained for anomaly
 	// detection and crash recovery by witness patrol, but the witness no
@@ -1,8 +1,22 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/errcode"
+	"github.com/steveyegge/gastown/internal/i18n"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/rbac"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/townlock"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // SilentExitError signals that the command should exit with a specific code
@@ -34,3 +48,50 @@ func IsSilentExit(err error) (int, bool) {
 	}
 	return 0, false
 }
+
+// Sentinel errors from other packages that carry a code.
+func init() {
+	errcode.Register(errcode.NotInTown, errcode.Is(workspace.ErrNotFound))
+	errcode.Register(errcode.BDMissing, errcode.Is(beads.ErrNotInstalled))
+	errcode.Register(errcode.BDMissing, func(err error) bool {
+		var execErr *exec.Error
+		return errors.As(err, &execErr) && execErr.Name == "bd" && errors.Is(execErr.Err, exec.ErrNotFound)
+	})
+	errcode.Register(errcode.BeadNotFound, errcode.Is(beads.ErrNotFound))
+	errcode.Register(errcode.RigNotFound, errcode.Is(rig.ErrRigNotFound))
+	errcode.Register(errcode.UnknownRecipient, errcode.Is(mail.ErrUnknownRecipient))
+	errcode.Register(errcode.VersionPinned, errcode.Is(config.ErrVersionPinned))
+	errcode.Register(errcode.UnknownFeature, errcode.Is(config.ErrUnknownFeature))
+	errcode.Register(errcode.AccessDenied, func(err error) bool {
+		var denied *rbac.DeniedError
+		return errors.As(err, &denied)
+	})
+	errcode.Register(errcode.TownLocked, errcode.Is(townlock.ErrHeld))
+}
+
+var (
+	msgErrorHint = i18n.New("error.hint", "Hint: %s")
+	msgErrorCode = i18n.New("error.code", "(%[1]s; see gt explain %[1]s)")
+)
+
+// reportError adds what Cobra's "Error:" line leaves out: the error's code
+// and hint on stderr, or, for a command run with --json, the whole error as
+// JSON on stdout so scripts don't have to parse messages.
+func reportError(cmd *cobra.Command, err error, stdout, stderr io.Writer) {
+	report := errcode.Describe(err)
+	if cmd != nil {
+		if f := cmd.Flags().Lookup("json"); f != nil && f.Value.String() == "true" {
+			enc := json.NewEncoder(stdout)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(map[string]errcode.Report{"error": report})
+			return
+		}
+	}
+	if report.Code == "" {
+		return
+	}
+	if report.Hint != "" {
+		fmt.Fprintln(stderr, msgErrorHint.T(report.Hint))
+	}
+	fmt.Fprintln(stderr, msgErrorCode.T(report.Code))
+}
@@ -1,9 +1,18 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os/exec"
+	"strings"
 	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/errcode"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 func TestSilentExitError_Error(t *testing.T) {
@@ -90,3 +99,42 @@ func TestSilentExitError_Is(t *testing.T) {
 		t.Errorf("errors.As extracted code = %d, want 1", target.Code)
 	}
 }
+
+func TestReportError(t *testing.T) {
+	cmd := &cobra.Command{Use: "show"}
+	var asJSON bool
+	cmd.Flags().BoolVar(&asJSON, "json", false, "")
+
+	var stdout, stderr bytes.Buffer
+	reportError(cmd, fmt.Errorf("showing: %w", beads.ErrNotFound), &stdout, &stderr)
+	if stdout.Len() != 0 || !strings.Contains(stderr.String(), "see gt explain bead-not-found") {
+		t.Errorf("plain report: stdout=%q stderr=%q", stdout.String(), stderr.String())
+	}
+
+	stderr.Reset()
+	reportError(cmd, errors.New("something odd"), &stdout, &stderr)
+	if stdout.Len() != 0 || stderr.Len() != 0 {
+		t.Errorf("uncoded error should add nothing, got %q", stderr.String())
+	}
+
+	asJSON = true
+	reportError(cmd, fmt.Errorf("no town: %w", workspace.ErrNotFound), &stdout, &stderr)
+	var got map[string]errcode.Report
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("JSON report: %v\n%s", err, stdout.String())
+	}
+	if r := got["error"]; r.Code != errcode.NotInTown || r.Message != "no town: not in a Gas Town workspace" || r.Hint == "" {
+		t.Errorf("JSON report = %+v", r)
+	}
+}
+
+func TestErrorCodes_BDMissing(t *testing.T) {
+	bd := &exec.Error{Name: "bd", Err: exec.ErrNotFound}
+	if code := errcode.Of(fmt.Errorf("listing: %w", bd)); code != errcode.BDMissing {
+		t.Errorf("missing bd = %q, want bd-missing", code)
+	}
+	tmux := &exec.Error{Name: "tmux", Err: exec.ErrNotFound}
+	if code := errcode.Of(tmux); code != "" {
+		t.Errorf("missing tmux = %q, want no code", code)
+	}
+}
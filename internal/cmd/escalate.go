@@ -23,7 +23,7 @@ var escalateCmd = &cobra.Command{
 	Use:     "escalate [description]",
 	GroupID: GroupComm,
 	Short:   "Escalation system for critical issues",
-	RunE:    runEscalate,
+	RunE:    journaled(runEscalate),
 	Long: `Create and manage escalations for critical issues.
 
 The escalation system provides severity-based routing for issues that need
@@ -15,6 +15,8 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/notification"
+	"github.com/steveyegge/gastown/internal/shard"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -104,6 +106,7 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	// Send mail to each target (actions with "mail:" prefix)
 	router := mail.NewRouter(townRoot)
 	defer router.WaitPendingNotifications()
+	shards := config.LoadMayorShards(townRoot)
 	for _, target := range targets {
 		msg := &mail.Message{
 			From:    agentID,
@@ -125,6 +128,8 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 			msg.Priority = mail.PriorityLow
 		}
 
+		ccPrimaryMayor(msg, shards, severity)
+
 		if err := router.Send(msg); err != nil {
 			style.PrintWarning("failed to send to %s: %v", target, err)
 		}
@@ -133,6 +138,13 @@ func runEscalate(cmd *cobra.Command, args []string) error {
 	// Process external notification actions (email:, sms:, slack, log)
 	executeExternalActions(actions, escalationConfig, issue.ID, severity, description, townRoot)
 
+	raiseNotification(townRoot, notification.Notification{
+		Event:    notification.EventEscalation,
+		Severity: escalationNotificationSeverity(severity),
+		Title:    fmt.Sprintf("Escalation [%s]: %s", strings.ToUpper(severity), description),
+		Body:     fmt.Sprintf("%s from %s", issue.ID, agentID),
+	})
+
 	// Log to activity feed
 	payload := events.EscalationPayload(issue.ID, agentID, strings.Join(targets, ","), description)
 	payload["severity"] = severity
@@ -239,6 +251,7 @@ func runEscalateAck(cmd *cobra.Command, args []string) error {
 	if ackedBy == "" {
 		ackedBy = "unknown"
 	}
+	ackedBy = attributeOperator(ackedBy)
 
 	bd := beads.New(beads.ResolveBeadsDir(townRoot))
 	if err := bd.AckEscalation(escalationID, ackedBy); err != nil {
@@ -268,6 +281,7 @@ func runEscalateClose(cmd *cobra.Command, args []string) error {
 	if closedBy == "" {
 		closedBy = "unknown"
 	}
+	closedBy = attributeOperator(closedBy)
 
 	bd := beads.New(beads.ResolveBeadsDir(townRoot))
 	if err := bd.CloseEscalation(escalationID, closedBy, escalateCloseReason); err != nil {
@@ -355,6 +369,7 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 	var results []*beads.ReescalationResult
 	router := mail.NewRouter(townRoot)
 	defer router.WaitPendingNotifications()
+	shards := config.LoadMayorShards(townRoot)
 
 	for _, issue := range stale {
 		result, err := bd.ReescalateEscalation(issue.ID, reescalatedBy, maxReescalations)
@@ -391,6 +406,8 @@ func runEscalateStale(cmd *cobra.Command, args []string) error {
 					msg.Priority = mail.PriorityLow
 				}
 
+				ccPrimaryMayor(msg, shards, result.NewSeverity)
+
 				if err := router.Send(msg); err != nil {
 					style.PrintWarning("failed to send reescalation to %s: %v", target, err)
 				}
@@ -555,6 +572,19 @@ func extractMailTargetsFromActions(actions []string) []string {
 	return targets
 }
 
+// ccPrimaryMayor copies the primary Mayor on escalation mail to "mayor/"
+// that mayor sharding will deliver to a shard mayor, once severity reaches
+// the shard's cc_primary threshold. The super-coordinator keeps sight of
+// serious escalations without fielding every one.
+func ccPrimaryMayor(msg *mail.Message, shards *shard.Config, severity string) {
+	if mail.AddressToIdentity(msg.To) != shard.PrimaryAddress {
+		return
+	}
+	if shards.CCPrimaryFor(msg.From, severity) {
+		msg.CC = append(msg.CC, shard.PrimaryAddress)
+	}
+}
+
 // executeExternalActions processes external notification actions (email:, sms:, slack, log).
 func executeExternalActions(actions []string, cfg *config.EscalationConfig, beadID, severity, description, townRoot string) {
 	for _, action := range actions {
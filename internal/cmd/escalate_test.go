@@ -8,6 +8,8 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/shard"
 )
 
 func TestGetNextSeverity(t *testing.T) {
@@ -92,6 +94,41 @@ func TestExtractMailTargetsFromActions(t *testing.T) {
 	}
 }
 
+func TestCCPrimaryMayor(t *testing.T) {
+	shards := &shard.Config{Shards: []shard.Shard{
+		{Name: "web", Rigs: []string{"frontend"}, CCPrimary: "high"},
+	}}
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		severity string
+		wantCC   bool
+	}{
+		{"sharded rig at threshold", "frontend/witness", "mayor", "high", true},
+		{"sharded rig below threshold", "frontend/witness", "mayor", "medium", false},
+		{"unsharded rig", "gastown/witness", "mayor", "critical", false},
+		{"not mayor mail", "frontend/witness", "deacon", "critical", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &mail.Message{From: tt.from, To: tt.to}
+			ccPrimaryMayor(msg, shards, tt.severity)
+			gotCC := len(msg.CC) == 1 && msg.CC[0] == "mayor/"
+			if gotCC != tt.wantCC {
+				t.Errorf("CC = %v, want primary CC %v", msg.CC, tt.wantCC)
+			}
+		})
+	}
+
+	msg := &mail.Message{From: "frontend/witness", To: "mayor"}
+	ccPrimaryMayor(msg, nil, "critical")
+	if len(msg.CC) != 0 {
+		t.Errorf("unsharded town CC = %v, want none", msg.CC)
+	}
+}
+
 func TestSeverityEmoji(t *testing.T) {
 	tests := []struct {
 		severity string
@@ -0,0 +1,440 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cli"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/experiment"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	experimentArms        []string
+	experimentDescription string
+	experimentJSON        bool
+)
+
+var experimentCmd = &cobra.Command{
+	Use:         "experiment",
+	Aliases:     []string{"exp"},
+	GroupID:     GroupWork,
+	Annotations: map[string]string{AnnotationFeature: config.FeatureExperiments},
+	Short:       "A/B test prompts, models and formulas",
+	Long: `Split comparable beads between dispatch variants and compare outcomes.
+
+An experiment has two or more arms. Each arm may override the formula,
+the agent (runtime/model alias), or add a prompt to the executor
+instructions. Sling beads with --experiment and each one is assigned an
+arm: balanced across arms, and sticky if the bead is slung again.
+
+The report compares arms on cycle time (sling to close), review
+rejections (merge requests rejected by the refinery) and cost (session
+costs recorded for the bead). Experiments live in <town>/experiments/.
+
+Arm specs: name[:key=value,...] with keys formula, agent, prompt.
+A prompt value starting with @ is read from a file.
+
+Examples:
+  gt experiment create terse --arm control --arm terse:prompt=@prompts/terse.md
+  gt experiment create model-swap --arm claude --arm gemini:agent=gemini
+  gt sling gt-abc gt-def gt-ghi gt-jkl gastown --experiment terse
+  gt experiment report terse
+  gt experiment stop terse`,
+	RunE: requireSubcommand,
+}
+
+var experimentCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Define a new experiment",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runExperimentCreate,
+}
+
+var experimentListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List experiments",
+	Args:  cobra.NoArgs,
+	RunE:  runExperimentList,
+}
+
+var experimentReportCmd = &cobra.Command{
+	Use:     "report <name>",
+	Aliases: []string{"show"},
+	Short:   "Compare outcome metrics per arm",
+	Long: `Compare an experiment's arms on cycle time, review rejections and cost.
+
+Cycle time is measured from the bead's assignment to its close, over
+closed beads only. Costs come from gt costs record for sessions working
+on the bead, so beads still in flight may be under-counted.
+
+Examples:
+  gt experiment report terse
+  gt experiment report terse --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExperimentReport,
+}
+
+var experimentStopCmd = &cobra.Command{
+	Use:   "stop <name>",
+	Short: "Stop assigning beads to an experiment",
+	Long: `Stop an experiment. Assigned beads keep their arm and keep counting
+towards the report; new slings with --experiment are refused.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExperimentStop,
+}
+
+func init() {
+	experimentCreateCmd.Flags().StringArrayVar(&experimentArms, "arm", nil, "Arm spec name[:formula=..,agent=..,prompt=..] (repeat, at least two)")
+	experimentCreateCmd.Flags().StringVarP(&experimentDescription, "description", "d", "", "What the experiment is testing")
+	experimentListCmd.Flags().BoolVar(&experimentJSON, "json", false, "Output as JSON")
+	experimentReportCmd.Flags().BoolVar(&experimentJSON, "json", false, "Output as JSON")
+
+	experimentCmd.AddCommand(experimentCreateCmd)
+	experimentCmd.AddCommand(experimentListCmd)
+	experimentCmd.AddCommand(experimentReportCmd)
+	experimentCmd.AddCommand(experimentStopCmd)
+	rootCmd.AddCommand(experimentCmd)
+}
+
+func runExperimentCreate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	e := &experiment.Experiment{
+		Name:        args[0],
+		Description: experimentDescription,
+		CreatedBy:   changeAuthor(),
+	}
+	for _, spec := range experimentArms {
+		arm, err := experiment.ParseArm(spec)
+		if err != nil {
+			return err
+		}
+		e.Arms = append(e.Arms, arm)
+	}
+	if err := experiment.Create(townRoot, e); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Created experiment %s with %d arms\n", style.Bold.Render("✓"), e.Name, len(e.Arms))
+	for _, arm := range e.Arms {
+		fmt.Printf("  %-12s %s\n", arm.Name, describeArm(arm))
+	}
+	fmt.Printf("\nSling beads with: %s sling <bead>... <rig> --experiment %s\n", cli.Name(), e.Name)
+	return nil
+}
+
+// describeArm summarizes what an arm overrides.
+func describeArm(arm experiment.Arm) string {
+	var parts []string
+	if arm.Formula != "" {
+		parts = append(parts, "formula="+arm.Formula)
+	}
+	if arm.Agent != "" {
+		parts = append(parts, "agent="+arm.Agent)
+	}
+	if arm.Prompt != "" {
+		prompt := arm.Prompt
+		if len(prompt) > 40 {
+			prompt = prompt[:37] + "..."
+		}
+		parts = append(parts, fmt.Sprintf("prompt=%q", prompt))
+	}
+	if len(parts) == 0 {
+		return style.Dim.Render("(sling defaults)")
+	}
+	return strings.Join(parts, " ")
+}
+
+func runExperimentList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	experiments, err := experiment.List(townRoot)
+	if err != nil {
+		return err
+	}
+	if experimentJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(experiments)
+	}
+	if len(experiments) == 0 {
+		fmt.Println("No experiments.")
+		return nil
+	}
+	for _, e := range experiments {
+		assignments, _ := experiment.Assignments(townRoot, e.Name)
+		arms := make([]string, len(e.Arms))
+		for i, a := range e.Arms {
+			arms[i] = a.Name
+		}
+		fmt.Printf("%-20s %-8s %3d beads  arms: %s\n", e.Name, e.Status, len(assignments), strings.Join(arms, ", "))
+		if e.Description != "" {
+			fmt.Printf("  %s\n", style.Dim.Render(e.Description))
+		}
+	}
+	return nil
+}
+
+func runExperimentStop(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	e, err := experiment.Load(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	if e.Status == experiment.StatusStopped {
+		fmt.Printf("Experiment %s is already stopped.\n", e.Name)
+		return nil
+	}
+	now := time.Now().UTC()
+	e.Status = experiment.StatusStopped
+	e.StoppedAt = &now
+	if err := experiment.Save(townRoot, e); err != nil {
+		return err
+	}
+	fmt.Printf("%s Stopped experiment %s\n", style.Bold.Render("✓"), e.Name)
+	return nil
+}
+
+// experimentReport is the JSON shape of gt experiment report.
+type experimentReport struct {
+	Experiment *experiment.Experiment `json:"experiment"`
+	Arms       []experiment.ArmStats  `json:"arms"`
+	Outcomes   []experiment.Outcome   `json:"outcomes"`
+}
+
+func runExperimentReport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	e, err := experiment.Load(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	report, err := buildExperimentReport(townRoot, e)
+	if err != nil {
+		return err
+	}
+
+	if experimentJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printExperimentReport(report)
+	return nil
+}
+
+// buildExperimentReport gathers an experiment's outcomes and per-arm stats.
+func buildExperimentReport(townRoot string, e *experiment.Experiment) (experimentReport, error) {
+	assignments, err := experiment.Assignments(townRoot, e.Name)
+	if err != nil {
+		return experimentReport{}, err
+	}
+	costs, err := experiment.Costs(townRoot, e.Name)
+	if err != nil {
+		return experimentReport{}, err
+	}
+
+	issues := map[string]*beads.Issue{}
+	townBeads := beads.New(townRoot)
+	for _, a := range assignments {
+		if issue, err := townBeads.Show(a.Bead); err == nil {
+			issues[a.Bead] = issue
+		}
+	}
+	outcomes := experimentOutcomes(assignments, issues, experimentRejections(townRoot, assignments), costs)
+	return experimentReport{Experiment: e, Arms: experiment.Summarize(e, outcomes), Outcomes: outcomes}, nil
+}
+
+// experimentOutcomes joins assignments with bead state, review rejections
+// and recorded costs.
+func experimentOutcomes(assignments []experiment.Assignment, issues map[string]*beads.Issue, rejections map[string]int, costs map[string]float64) []experiment.Outcome {
+	outcomes := make([]experiment.Outcome, 0, len(assignments))
+	for _, a := range assignments {
+		o := experiment.Outcome{Bead: a.Bead, Arm: a.Arm, Rejections: rejections[a.Bead], CostUSD: costs[a.Bead]}
+		if issue := issues[a.Bead]; issue != nil && issue.Status == "closed" {
+			o.Closed = true
+			if closedAt, err := time.Parse(time.RFC3339, issue.ClosedAt); err == nil && closedAt.After(a.At) {
+				o.CycleTime = closedAt.Sub(a.At)
+			}
+		}
+		outcomes = append(outcomes, o)
+	}
+	return outcomes
+}
+
+// experimentRejections counts merge requests rejected in review per source
+// issue, across the rigs the assigned beads belong to.
+func experimentRejections(townRoot string, assignments []experiment.Assignment) map[string]int {
+	rigs := map[string]bool{}
+	for _, a := range assignments {
+		if rigName := resolveRigForBead(townRoot, a.Bead); rigName != "" {
+			rigs[rigName] = true
+		}
+	}
+	rejections := map[string]int{}
+	for rigName := range rigs {
+		mrs, err := beads.New(filepath.Join(townRoot, rigName)).ListMergeRequests(beads.ListOptions{
+			Label:    "gt:merge-request",
+			Status:   "all",
+			Priority: -1,
+		})
+		if err != nil {
+			continue
+		}
+		for _, mr := range mrs {
+			if fields := beads.ParseMRFields(mr); fields != nil && fields.CloseReason == "rejected" {
+				rejections[fields.SourceIssue]++
+			}
+		}
+	}
+	return rejections
+}
+
+func printExperimentReport(r experimentReport) {
+	e := r.Experiment
+	fmt.Printf("%s %s (%s)\n", style.Bold.Render("🧪 Experiment"), e.Name, e.Status)
+	if e.Description != "" {
+		fmt.Printf("   %s\n", style.Dim.Render(e.Description))
+	}
+	fmt.Println()
+	fmt.Printf("  %-12s %6s %7s %12s %12s %10s %10s\n", "ARM", "BEADS", "CLOSED", "MEDIAN CYCLE", "MEAN CYCLE", "REJECTS", "COST/BEAD")
+	for _, s := range r.Arms {
+		median, mean := "-", "-"
+		if s.Closed > 0 {
+			median, mean = formatDuration(s.MedianCycle), formatDuration(s.MeanCycle)
+		}
+		fmt.Printf("  %-12s %6d %7d %12s %12s %10s %10s\n", s.Arm, s.Beads, s.Closed, median, mean,
+			fmt.Sprintf("%d (%.2f)", s.Rejections, s.RejectionRate()), fmt.Sprintf("$%.2f", s.CostPerBead()))
+	}
+
+	minClosed := -1
+	for _, s := range r.Arms {
+		if minClosed < 0 || s.Closed < minClosed {
+			minClosed = s.Closed
+		}
+	}
+	if minClosed < 5 {
+		fmt.Printf("\n%s\n", style.Dim.Render("Few closed beads per arm — treat differences as anecdotal."))
+	}
+}
+
+// assignExperimentArm assigns beadID to an arm of the named experiment. In
+// dry-run mode the arm is picked but not recorded.
+func assignExperimentArm(townRoot, name, beadID string, dryRun bool) (*experiment.Arm, error) {
+	e, err := experiment.Load(townRoot, name)
+	if err != nil {
+		return nil, err
+	}
+	if e.Canary != nil {
+		return pickCanaryArm(townRoot, e, beadID, dryRun)
+	}
+	if dryRun {
+		if e.Status != experiment.StatusActive {
+			return nil, fmt.Errorf("experiment %s is %s", e.Name, e.Status)
+		}
+		assignments, err := experiment.Assignments(townRoot, e.Name)
+		if err != nil {
+			return nil, err
+		}
+		return e.Pick(beadID, assignments), nil
+	}
+	return experiment.Assign(townRoot, e, beadID)
+}
+
+// applyExperimentArm returns the formula, agent and args for a bead
+// dispatched under arm. Fields the arm leaves empty keep the sling's value;
+// an arm prompt is appended to the args.
+func applyExperimentArm(arm *experiment.Arm, formula, agent, args string) (string, string, string) {
+	if arm.Formula != "" {
+		formula = arm.Formula
+	}
+	if arm.Agent != "" {
+		agent = arm.Agent
+	}
+	if arm.Prompt != "" {
+		if args != "" {
+			args += "\n\n"
+		}
+		args += arm.Prompt
+	}
+	return formula, agent, args
+}
+
+// validateExperimentFlags rejects sling flags that would override what the
+// experiment varies, which would silently collapse the arms.
+func validateExperimentFlags(cmd *cobra.Command, townRoot, name string) error {
+	e, err := experiment.Load(townRoot, name)
+	if err != nil {
+		return err
+	}
+	if e.Status != experiment.StatusActive {
+		return fmt.Errorf("experiment %s is %s", e.Name, e.Status)
+	}
+	formula, agent, _ := e.Varies()
+	var conflicts []string
+	if formula && cmd.Flags().Changed("formula") {
+		conflicts = append(conflicts, "--formula")
+	}
+	if formula && cmd.Flags().Changed("hook-raw-bead") {
+		conflicts = append(conflicts, "--hook-raw-bead")
+	}
+	if agent && cmd.Flags().Changed("agent") {
+		conflicts = append(conflicts, "--agent")
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("%s conflicts with --experiment %s: its arms set this per bead", strings.Join(conflicts, ", "), e.Name)
+	}
+	return nil
+}
+
+// printExperimentArm reports a bead's arm during sling.
+func printExperimentArm(beadID, name string, arm *experiment.Arm) {
+	fmt.Printf("  %s %s → arm %s of %s\n", style.Bold.Render("🧪"), beadID, arm.Name, name)
+}
+
+// recordExperimentCost credits a polecat session's running cost to its work
+// bead's experiment arm. It is a no-op in towns without experiments, so the
+// Stop hook only pays for a bead lookup when one might matter.
+func recordExperimentCost(sessionName, role, rigName, worker, workDir string, cost float64) {
+	if cost <= 0 {
+		return
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	if experiments, err := experiment.List(townRoot); err != nil || len(experiments) == 0 {
+		return
+	}
+	bead := recordWorkItem
+	if bead == "" && role == constants.RolePolecat && workDir != "" {
+		bead = findHookedBeadForAgent(beads.New(workDir), rigName+"/polecats/"+worker)
+	}
+	if bead == "" {
+		return
+	}
+	if err := experiment.RecordCost(townRoot, bead, sessionName, cost); err != nil && costsVerbose {
+		fmt.Fprintf(os.Stderr, "[costs] could not record experiment cost: %v\n", err)
+	}
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/experiment"
+)
+
+func TestApplyExperimentArm(t *testing.T) {
+	arm := &experiment.Arm{Name: "terse", Agent: "gemini", Prompt: "Be terse."}
+	formula, agent, args := applyExperimentArm(arm, "mol-polecat-work", "claude", "patch release")
+	if formula != "mol-polecat-work" {
+		t.Errorf("formula = %q, want sling value kept", formula)
+	}
+	if agent != "gemini" {
+		t.Errorf("agent = %q, want arm override", agent)
+	}
+	if args != "patch release\n\nBe terse." {
+		t.Errorf("args = %q, want prompt appended", args)
+	}
+
+	control := &experiment.Arm{Name: "control"}
+	if f, a, s := applyExperimentArm(control, "f", "a", ""); f != "f" || a != "a" || s != "" {
+		t.Errorf("control arm changed values: %q %q %q", f, a, s)
+	}
+}
+
+func TestExperimentOutcomes(t *testing.T) {
+	at := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	assignments := []experiment.Assignment{
+		{Bead: "gt-a", Arm: "control", At: at},
+		{Bead: "gt-b", Arm: "terse", At: at},
+		{Bead: "gt-gone", Arm: "terse", At: at},
+	}
+	issues := map[string]*beads.Issue{
+		"gt-a": {ID: "gt-a", Status: "closed", ClosedAt: "2026-10-01T13:30:00Z"},
+		"gt-b": {ID: "gt-b", Status: "in_progress"},
+	}
+	outcomes := experimentOutcomes(assignments, issues, map[string]int{"gt-b": 2}, map[string]float64{"gt-a": 1.5})
+	if len(outcomes) != 3 {
+		t.Fatalf("outcomes = %+v", outcomes)
+	}
+	if o := outcomes[0]; !o.Closed || o.CycleTime != 4*time.Hour+30*time.Minute || o.CostUSD != 1.5 {
+		t.Errorf("gt-a = %+v", o)
+	}
+	if o := outcomes[1]; o.Closed || o.Rejections != 2 {
+		t.Errorf("gt-b = %+v", o)
+	}
+	if o := outcomes[2]; o.Closed || o.Arm != "terse" {
+		t.Errorf("missing bead should still count as an open outcome: %+v", o)
+	}
+}
+
+func TestValidateExperimentFlags(t *testing.T) {
+	townRoot := t.TempDir()
+	e := &experiment.Experiment{
+		Name: "model-swap",
+		Arms: []experiment.Arm{{Name: "claude"}, {Name: "gemini", Agent: "gemini"}},
+	}
+	if err := experiment.Create(townRoot, e); err != nil {
+		t.Fatal(err)
+	}
+
+	newCmd := func(args ...string) *cobra.Command {
+		c := &cobra.Command{Use: "sling"}
+		c.Flags().String("formula", "", "")
+		c.Flags().String("agent", "", "")
+		c.Flags().Bool("hook-raw-bead", false, "")
+		if err := c.Flags().Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	if err := validateExperimentFlags(newCmd("--formula", "mol-x"), townRoot, "model-swap"); err != nil {
+		t.Errorf("--formula with agent-only experiment: %v", err)
+	}
+	err := validateExperimentFlags(newCmd("--agent", "codex"), townRoot, "model-swap")
+	if err == nil || !strings.Contains(err.Error(), "--agent") {
+		t.Errorf("--agent with agent experiment = %v, want conflict", err)
+	}
+	if err := validateExperimentFlags(newCmd(), townRoot, "nonesuch"); err == nil {
+		t.Error("unknown experiment = nil error")
+	}
+
+	e.Status = experiment.StatusStopped
+	if err := experiment.Save(townRoot, e); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateExperimentFlags(newCmd(), townRoot, "model-swap"); err == nil {
+		t.Error("stopped experiment = nil error")
+	}
+}
+
+func TestAssignExperimentArm_DryRunDoesNotRecord(t *testing.T) {
+	townRoot := t.TempDir()
+	e := &experiment.Experiment{Name: "terse", Arms: []experiment.Arm{{Name: "a"}, {Name: "b"}}}
+	if err := experiment.Create(townRoot, e); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := assignExperimentArm(townRoot, "terse", "gt-a", true); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := experiment.Assignments(townRoot, "terse"); len(got) != 0 {
+		t.Errorf("dry run recorded %v", got)
+	}
+	if _, err := assignExperimentArm(townRoot, "terse", "gt-a", false); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := experiment.Assignments(townRoot, "terse"); len(got) != 1 {
+		t.Errorf("assignments = %v, want one", got)
+	}
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/errcode"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var explainJSON bool
+
+var explainCmd = &cobra.Command{
+	Use:     "explain [error-code]",
+	GroupID: GroupDiag,
+	Short:   "Explain an error code and how to fix it",
+	Long: `Explain what an error code means and what to do about it.
+
+When a command fails with a known cause, gt prints the error's code after
+the message:
+
+  Error: bead 'gt-xyz' not found: no route for prefix gt-
+  Hint: run gt doctor --fix to repair routes.jsonl; gt rig list shows each rig's prefix
+  (route-missing; see gt explain route-missing)
+
+Commands run with --json print failures as JSON on stdout instead:
+
+  {"error": {"code": "route-missing", "message": "...", "hint": "..."}}
+
+Codes are stable: scripts may match on them. Errors without a known cause
+have no code. With no argument, every code is listed.
+
+Examples:
+  gt explain                    # List codes
+  gt explain bead-not-found
+  gt explain route-missing --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExplain,
+}
+
+func init() {
+	explainCmd.Flags().BoolVar(&explainJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(explainCmd)
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		entries := make([]errcode.Entry, 0, len(errcode.Catalog))
+		for _, code := range errcode.Codes() {
+			e, _ := errcode.Lookup(code)
+			entries = append(entries, e)
+		}
+		if explainJSON {
+			return printExplainJSON(entries)
+		}
+		for _, e := range entries {
+			fmt.Printf("  %-18s %s\n", e.Code, e.Summary)
+		}
+		return nil
+	}
+
+	e, ok := errcode.Lookup(errcode.Code(args[0]))
+	if !ok {
+		return fmt.Errorf("unknown error code %q (gt explain lists them)", args[0])
+	}
+	if explainJSON {
+		return printExplainJSON(e)
+	}
+	fmt.Printf("%s: %s\n\n%s\n\n%s %s\n", style.Bold.Render(string(e.Code)), e.Summary, e.Explain, style.Bold.Render("Fix:"), e.Hint)
+	return nil
+}
+
+func printExplainJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
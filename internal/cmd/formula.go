@@ -194,7 +194,7 @@ func runFormulaList(cmd *cobra.Command, args []string) error {
 	bdCmd := exec.Command("bd", bdArgs...)
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
-	return bdCmd.Run()
+	return beads.RunCmd(bdCmd)
 }
 
 // runFormulaShow delegates to bd formula show
@@ -208,7 +208,7 @@ func runFormulaShow(cmd *cobra.Command, args []string) error {
 	bdCmd := exec.Command("bd", bdArgs...)
 	bdCmd.Stdout = os.Stdout
 	bdCmd.Stderr = os.Stderr
-	return bdCmd.Run()
+	return beads.RunCmd(bdCmd)
 }
 
 // runFormulaRun executes a formula by spawning a convoy of polecats.
@@ -442,7 +442,7 @@ func executeConvoyFormula(f *formula.Formula, formulaName, targetRig string) err
 	createCmd := exec.Command("bd", createArgs...)
 	createCmd.Dir = townBeads
 	createCmd.Stderr = os.Stderr
-	if err := createCmd.Run(); err != nil {
+	if err := beads.RunCmd(createCmd); err != nil {
 		return fmt.Errorf("creating convoy bead: %w", err)
 	}
 
@@ -651,7 +651,7 @@ func executeConvoyFormula(f *formula.Formula, formulaName, targetRig string) err
 			commentArgs := []string{"comment", legBeadID, fmt.Sprintf("Failed to sling: %v", err)}
 			commentCmd := exec.Command("bd", commentArgs...)
 			commentCmd.Dir = townBeads
-			_ = commentCmd.Run()
+			_ = beads.RunCmd(commentCmd)
 			continue
 		}
 
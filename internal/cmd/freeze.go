@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/freeze"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	freezeReason string
+	freezeAt     string
+	freezeUntil  string
+	freezeJSON   bool
+)
+
+var freezeCmd = &cobra.Command{
+	Use:     "freeze [rig]",
+	GroupID: GroupServices,
+	Short:   "Freeze a rig's merge queue and code changes for a release window",
+	Long: `Put a rig under a change freeze, or list freezes.
+
+While a rig is frozen:
+  - The refinery merges nothing; MRs stay queued until the thaw
+  - gt sling and the scheduler refuse beads that would change code
+  - Beads labeled read-only or analysis are still dispatched
+
+Polecats already working keep going and can still run gt done; their MRs
+wait in the queue. A freeze is a hard stop: --force does not bypass it.
+
+A freeze can be scheduled: --at sets when it starts and --until when it
+thaws on its own. Without --until the rig stays frozen until gt thaw.
+Times are a duration from now (2h), a time of day (18:00, the next one),
+a local date and time ("2026-03-01 18:00") or RFC 3339.
+
+With no rig, lists current and scheduled freezes.
+
+Examples:
+  gt freeze gastown -m "v2.0 release"
+  gt freeze gastown --at 18:00 --until "2026-03-02 09:00"
+  gt freeze gastown --until 4h
+  gt freeze                                # List freezes
+  gt thaw gastown`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runFreeze,
+}
+
+var thawCmd = &cobra.Command{
+	Use:     "thaw <rig>...",
+	GroupID: GroupServices,
+	Short:   "Lift a rig's change freeze",
+	Long: `Lift the change freeze on one or more rigs, including scheduled ones.
+
+The refinery picks up held MRs on its next poll and dispatch resumes.
+
+Examples:
+  gt thaw gastown
+  gt thaw gastown beads`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runThaw,
+}
+
+// freezeAllowsBeadFn is a seam for tests. Production checks the bead for a
+// read-only label.
+var freezeAllowsBeadFn = func(beadID string) bool {
+	info, err := getBeadInfo(beadID)
+	return err == nil && freeze.IsReadOnly(info.Labels)
+}
+
+func init() {
+	freezeCmd.Flags().StringVarP(&freezeReason, "reason", "m", "", "Why the rig is frozen (shown to anyone blocked by it)")
+	freezeCmd.Flags().StringVar(&freezeAt, "at", "", "When the freeze starts (default: now)")
+	freezeCmd.Flags().StringVar(&freezeUntil, "until", "", "When the rig thaws on its own (default: at gt thaw)")
+	freezeCmd.Flags().BoolVar(&freezeJSON, "json", false, "Output the freeze list as JSON")
+	rootCmd.AddCommand(freezeCmd)
+	rootCmd.AddCommand(thawCmd)
+}
+
+func runFreeze(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return listFreezes()
+	}
+	rigName := args[0]
+	townRoot, _, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	f := &freeze.Freeze{Rig: rigName, Reason: freezeReason, By: detectSender(), From: now, CreatedAt: now}
+	if freezeAt != "" {
+		if f.From, err = freeze.ParseTime(freezeAt, now); err != nil {
+			return fmt.Errorf("--at: %w", err)
+		}
+	}
+	if freezeUntil != "" {
+		if f.Until, err = freeze.ParseTime(freezeUntil, now); err != nil {
+			return fmt.Errorf("--until: %w", err)
+		}
+		if !f.Until.After(f.From) {
+			return fmt.Errorf("--until must be after the freeze starts")
+		}
+	}
+
+	if err := freeze.Save(townRoot, f); err != nil {
+		return fmt.Errorf("saving freeze: %w", err)
+	}
+	_ = events.LogFeed(events.TypeFreeze, f.By, events.FreezePayload(rigName, true, f.Reason, f.From, f.Until))
+
+	verb := "frozen"
+	if !f.Active(now) {
+		verb = "freeze scheduled"
+	}
+	fmt.Printf("%s Rig %s %s %s\n", style.Success.Render("❄"), style.Bold.Render(rigName), verb, f.Describe(now))
+	if f.Reason != "" {
+		fmt.Printf("  Reason: %s\n", f.Reason)
+	}
+	fmt.Printf("  %s\n", style.Dim.Render("Merges held and code-changing beads blocked; read-only/analysis beads still dispatch"))
+	return nil
+}
+
+func runThaw(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	for _, rigName := range args {
+		f, err := freeze.Load(townRoot, rigName)
+		if err != nil {
+			return err
+		}
+		if f == nil {
+			fmt.Printf("Rig %s is not frozen\n", rigName)
+			continue
+		}
+		if err := freeze.Remove(townRoot, rigName); err != nil {
+			return fmt.Errorf("thawing %s: %w", rigName, err)
+		}
+		_ = events.LogFeed(events.TypeFreeze, detectSender(), events.FreezePayload(rigName, false, "", time.Time{}, time.Time{}))
+		fmt.Printf("%s Rig %s thawed\n", style.Success.Render("✓"), style.Bold.Render(rigName))
+	}
+	return nil
+}
+
+// freezeEntry is one row of the freeze list.
+type freezeEntry struct {
+	*freeze.Freeze
+	State string `json:"state"`
+}
+
+func listFreezes() error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	all, err := freeze.List(townRoot)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	entries := make([]freezeEntry, 0, len(all))
+	for _, f := range all {
+		entries = append(entries, freezeEntry{Freeze: f, State: f.State(now)})
+	}
+
+	if freezeJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	}
+	if len(entries) == 0 {
+		fmt.Println("No rigs are frozen.")
+		return nil
+	}
+	fmt.Printf("%s Change freezes\n", style.Bold.Render("❄"))
+	for _, e := range entries {
+		state := style.Warning.Render(e.State)
+		if e.State == "expired" {
+			state = style.Dim.Render(e.State)
+		}
+		line := fmt.Sprintf("  %-14s %-9s %s", e.Rig, state, e.Describe(now))
+		if e.Reason != "" {
+			line += style.Dim.Render("  " + e.Reason)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// checkRigFreeze returns an error when rigName is frozen and beadID would
+// change code. Read-only and analysis beads pass.
+func checkRigFreeze(townRoot, rigName, beadID string) error {
+	f := freeze.Check(townRoot, rigName, time.Now())
+	if f == nil || (beadID != "" && freezeAllowsBeadFn(beadID)) {
+		return nil
+	}
+	msg := fmt.Sprintf("rig %q is frozen", rigName)
+	if f.Reason != "" {
+		msg += ": " + f.Reason
+	}
+	return fmt.Errorf("%s\nOnly read-only/analysis beads may be slung until gt thaw %s", msg, rigName)
+}
+
+// skipFrozenRigs drops pending dispatches of code-changing beads to
+// frozen rigs. They stay queued and go out after the thaw.
+func skipFrozenRigs(townRoot string, pending []capacity.PendingBead, now time.Time) []capacity.PendingBead {
+	frozen := make(map[string]bool)
+	kept := pending[:0]
+	for _, b := range pending {
+		isFrozen, ok := frozen[b.TargetRig]
+		if !ok {
+			isFrozen = freeze.Check(townRoot, b.TargetRig, now) != nil
+			frozen[b.TargetRig] = isFrozen
+		}
+		if isFrozen && !freezeAllowsBeadFn(b.WorkBeadID) {
+			continue
+		}
+		kept = append(kept, b)
+	}
+	return kept
+}
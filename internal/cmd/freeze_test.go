@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/freeze"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+)
+
+func stubFreezeReadOnly(t *testing.T, readOnly ...string) {
+	t.Helper()
+	orig := freezeAllowsBeadFn
+	t.Cleanup(func() { freezeAllowsBeadFn = orig })
+	freezeAllowsBeadFn = func(beadID string) bool {
+		for _, id := range readOnly {
+			if id == beadID {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func TestCheckRigFreeze(t *testing.T) {
+	townRoot := t.TempDir()
+	stubFreezeReadOnly(t, "gt-analysis")
+
+	if err := checkRigFreeze(townRoot, "gastown", "gt-code"); err != nil {
+		t.Fatalf("unfrozen rig: %v", err)
+	}
+	if err := freeze.Save(townRoot, &freeze.Freeze{Rig: "gastown", Reason: "v2.0 release", From: time.Now().Add(-time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	err := checkRigFreeze(townRoot, "gastown", "gt-code")
+	if err == nil || !strings.Contains(err.Error(), "v2.0 release") {
+		t.Errorf("code bead on frozen rig: err = %v, want freeze error with reason", err)
+	}
+	if err := checkRigFreeze(townRoot, "gastown", "gt-analysis"); err != nil {
+		t.Errorf("read-only bead on frozen rig: %v", err)
+	}
+	if err := checkRigFreeze(townRoot, "beads", "gt-code"); err != nil {
+		t.Errorf("other rig: %v", err)
+	}
+}
+
+func TestSkipFrozenRigs(t *testing.T) {
+	townRoot := t.TempDir()
+	stubFreezeReadOnly(t, "gt-analysis")
+	now := time.Now()
+	if err := freeze.Save(townRoot, &freeze.Freeze{Rig: "gastown", From: now.Add(-time.Hour), Until: now.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	pending := []capacity.PendingBead{
+		{ID: "ctx-1", WorkBeadID: "gt-code", TargetRig: "gastown"},
+		{ID: "ctx-2", WorkBeadID: "gt-analysis", TargetRig: "gastown"},
+		{ID: "ctx-3", WorkBeadID: "bd-code", TargetRig: "beads"},
+	}
+	kept := skipFrozenRigs(townRoot, append([]capacity.PendingBead(nil), pending...), now)
+	if len(kept) != 2 || kept[0].ID != "ctx-2" || kept[1].ID != "ctx-3" {
+		t.Errorf("frozen: kept %+v, want the analysis bead and the beads dispatch", kept)
+	}
+	if kept := skipFrozenRigs(townRoot, append([]capacity.PendingBead(nil), pending...), now.Add(2*time.Hour)); len(kept) != 3 {
+		t.Errorf("after scheduled thaw: kept %d, want all 3", len(kept))
+	}
+}
@@ -869,6 +869,11 @@ func buildRestartCommandWithOpts(sessionName string, opts buildRestartCommandOpt
 		}
 	}
 
+	// Shard mayors share GT_ROLE=mayor; the shard carries their identity.
+	if identity.Role == session.RoleMayor && identity.Name != "" {
+		exports = append(exports, "GT_MAYOR_SHARD="+identity.Name)
+	}
+
 	// Propagate GT_ROOT so subsequent handoffs can use it as fallback
 	// when cwd-based detection fails (broken state recovery)
 	exports = append(exports, "GT_ROOT="+townRoot)
@@ -1015,6 +1020,9 @@ func sessionWorkDir(sessionName, townRoot string) (string, error) {
 		}
 		switch identity.Role {
 		case session.RoleMayor:
+			if identity.Name != "" {
+				return townRoot + "/mayor/shards/" + identity.Name, nil
+			}
 			return townRoot + "/mayor", nil
 		case session.RoleDeacon:
 			return townRoot + "/deacon", nil
@@ -1091,6 +1099,13 @@ func detectTownRootFromCwd() string {
 
 // handoffRemoteSession respawns a different session and optionally switches to it.
 func handoffRemoteSession(t *tmux.Tmux, targetSession, restartCmd string) error {
+	return respawnSessionPane(t, targetSession, restartCmd, handoffDryRun, handoffWatch)
+}
+
+// respawnSessionPane replaces the agent running in another session's pane with
+// restartCmd, keeping the tmux session (and anyone attached to it) in place.
+// When watch is set, the caller's client switches to the session afterwards.
+func respawnSessionPane(t *tmux.Tmux, targetSession, restartCmd string, dryRun, watch bool) error {
 	// Check if target session exists
 	exists, err := t.HasSession(targetSession)
 	if err != nil {
@@ -1109,10 +1124,10 @@ func handoffRemoteSession(t *tmux.Tmux, targetSession, restartCmd string) error
 	fmt.Printf("%s Handing off %s...\n", style.Bold.Render("🤝"), targetSession)
 
 	// Dry run mode
-	if handoffDryRun {
+	if dryRun {
 		fmt.Printf("Would execute: tmux clear-history -t %s\n", targetPane)
 		fmt.Printf("Would execute: tmux respawn-pane -k -t %s %s\n", targetPane, restartCmd)
-		if handoffWatch {
+		if watch {
 			fmt.Printf("Would execute: tmux switch-client -t %s\n", targetSession)
 		}
 		return nil
@@ -1156,7 +1171,7 @@ func handoffRemoteSession(t *tmux.Tmux, targetSession, restartCmd string) error
 	}
 
 	// If --watch, switch to that session
-	if handoffWatch {
+	if watch {
 		fmt.Printf("Switching to %s...\n", targetSession)
 		// Use tmux switch-client to move our view to the target session
 		if err := tmux.BuildCommand("switch-client", "-t", targetSession).Run(); err != nil {
@@ -1203,18 +1218,30 @@ func sendHandoffMail(subject, message string) (string, error) {
 		return "", fmt.Errorf("detecting agent identity: %w", err)
 	}
 
-	// Normalize identity to match mailbox query format
-	agentID = mail.AddressToIdentity(agentID)
-
 	// Detect town root for beads location
 	townRoot := detectTownRootFromCwd()
 	if townRoot == "" {
 		return "", fmt.Errorf("cannot detect town root")
 	}
 
+	return sendHandoffMailTo(townRoot, agentID, subject, message)
+}
+
+// sendHandoffMailTo creates handoff mail for agentAddr and hooks it so the
+// agent's next session picks it up. Subject and message are used as given.
+func sendHandoffMailTo(townRoot, agentAddr, subject, message string) (string, error) {
+	// Normalize identity to match mailbox query format
+	agentID := mail.AddressToIdentity(agentAddr)
+
 	// Build labels for mail metadata (matches mail router format)
 	labels := fmt.Sprintf("from:%s", agentID)
 
+	// Seal the body like the mail router does when mail is encrypted at rest.
+	body, err := mail.StoredBody(townRoot, message)
+	if err != nil {
+		return "", fmt.Errorf("creating handoff mail: %w", err)
+	}
+
 	// Create mail bead directly using bd create with --silent to get the ID
 	// Mail goes to town-level beads (hq- prefix)
 	// Flags go first, then -- to end flag parsing, then the positional subject.
@@ -1222,7 +1249,7 @@ func sendHandoffMail(subject, message string) (string, error) {
 	args := []string{
 		"create",
 		"--assignee", agentID,
-		"-d", message,
+		"-d", body,
 		"--priority", "1", // high — handoffs should float above normal mail
 		"--labels", labels + ",gt:message",
 		"--actor", agentID,
@@ -1242,7 +1269,7 @@ func sendHandoffMail(subject, message string) (string, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" {
 			return "", fmt.Errorf("creating handoff mail: %s", errMsg)
@@ -1263,7 +1290,7 @@ func sendHandoffMail(subject, message string) (string, error) {
 	hookCmd.Env = append(hookCmd.Env, "BEADS_DIR="+filepath.Join(townRoot, ".beads"))
 	hookCmd.Stderr = os.Stderr
 
-	if err := hookCmd.Run(); err != nil {
+	if err := beads.RunCmd(hookCmd); err != nil {
 		// Non-fatal: mail was created, just couldn't hook
 		style.PrintWarning("created mail %s but failed to auto-hook: %v", beadID, err)
 		return beadID, nil
@@ -1339,7 +1366,7 @@ func looksLikeBeadID(s string) bool {
 func hookBeadForHandoff(beadID string) error {
 	// Verify the bead exists first
 	verifyCmd := exec.Command("bd", "show", beadID, "--json")
-	if err := verifyCmd.Run(); err != nil {
+	if err := beads.RunCmd(verifyCmd); err != nil {
 		return fmt.Errorf("bead '%s' not found", beadID)
 	}
 
@@ -1359,7 +1386,7 @@ func hookBeadForHandoff(beadID string) error {
 	// Pin the bead using bd update (discovery-based approach)
 	pinCmd := exec.Command("bd", "update", beadID, "--status=pinned", "--assignee="+agentID)
 	pinCmd.Stderr = os.Stderr
-	if err := pinCmd.Run(); err != nil {
+	if err := beads.RunCmd(pinCmd); err != nil {
 		return fmt.Errorf("pinning bead: %w", err)
 	}
 
@@ -1403,7 +1430,7 @@ func collectHandoffState() string {
 	}
 
 	// Get ready beads
-	readyOutput, err := exec.Command("bd", "ready").Output()
+	readyOutput, err := beads.CmdOutput(exec.Command("bd", "ready"))
 	if err == nil {
 		readyStr := strings.TrimSpace(string(readyOutput))
 		if readyStr != "" && !strings.Contains(readyStr, "No issues ready") {
@@ -1417,7 +1444,7 @@ func collectHandoffState() string {
 	}
 
 	// Get in-progress beads
-	inProgressOutput, err := exec.Command("bd", "list", "--status=in_progress").Output()
+	inProgressOutput, err := beads.CmdOutput(exec.Command("bd", "list", "--status=in_progress"))
 	if err == nil {
 		ipStr := strings.TrimSpace(string(inProgressOutput))
 		if ipStr != "" && !strings.Contains(ipStr, "No issues") {
@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Limits on the grounding handed to the help agent, so a busy town doesn't
+// blow the prompt up.
+const (
+	helpAskMaxBeads        = 3
+	helpAskMaxCommands     = 3
+	helpAskMaxProbeOutput  = 8000
+	helpAskProbeTimeout    = 20 * time.Second
+	helpAskDefaultTimeout  = 3 * time.Minute
+	helpAskTruncatedMarker = "\n... (truncated)"
+)
+
+var (
+	helpAsk        bool
+	helpAskAgent   string
+	helpAskTimeout time.Duration
+)
+
+// helpCmd replaces cobra's default help command to add --ask.
+var helpCmd = &cobra.Command{
+	Use:     "help [command]",
+	GroupID: GroupDiag,
+	Short:   "Help about any command, or ask a question about gt",
+	Long: `Help provides help for any command in the application.
+Simply type gt help [path to command] for full details.
+
+With --ask, the rest of the line is a question for a help assistant that
+runs on the town's configured agent. The assistant sees the full gt
+command reference and a snapshot of the town: gt status, the scheduler
+queue, change freezes, and bd show for every bead the question mentions.
+It answers from that data and can't change anything.
+
+Examples:
+  gt help sling
+  gt help --ask "why isn't tr-123 dispatching?"
+  gt help --ask how do I pause a rig for the weekend
+  gt help --ask --agent gemini "what's the difference between park and dock?"`,
+	ValidArgsFunction: func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		cmd, _, err := c.Root().Find(args)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		if cmd == nil {
+			cmd = c.Root()
+		}
+		var completions []string
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() && strings.HasPrefix(sub.Name(), toComplete) {
+				completions = append(completions, sub.Name()+"\t"+sub.Short)
+			}
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: runHelp,
+}
+
+// helpAskSnapshot is one piece of town state given to the help agent.
+type helpAskSnapshot struct {
+	Title  string
+	Output string
+}
+
+// helpAskProbeFn is a seam for tests. Production runs the read-only probe
+// with a short timeout.
+var helpAskProbeFn = func(townRoot string, argv ...string) (string, error) {
+	if argv[0] == "gt" {
+		if exe, err := os.Executable(); err == nil {
+			argv[0] = exe
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), helpAskProbeTimeout)
+	defer cancel()
+	c := exec.CommandContext(ctx, argv[0], argv[1:]...) //nolint:gosec // G204: fixed read-only probes
+	c.Dir = townRoot
+	out, err := c.CombinedOutput()
+	return string(out), err
+}
+
+func init() {
+	helpCmd.Flags().BoolVar(&helpAsk, "ask", false, "Ask the help assistant a question about gt and this town")
+	helpCmd.Flags().StringVar(&helpAskAgent, "agent", "", "Override agent/runtime for --ask (e.g., claude, gemini)")
+	helpCmd.Flags().DurationVar(&helpAskTimeout, "timeout", helpAskDefaultTimeout, "Maximum time to wait for an answer with --ask")
+	rootCmd.SetHelpCommand(helpCmd)
+}
+
+func runHelp(cmd *cobra.Command, args []string) error {
+	if helpAsk {
+		return runHelpAsk(cmd.Root(), args)
+	}
+	target, _, err := cmd.Root().Find(args)
+	if target == nil || err != nil {
+		cmd.Printf("Unknown help topic %#q\n", args)
+		return cmd.Root().Usage()
+	}
+	target.InitDefaultHelpFlag()
+	target.InitDefaultVersionFlag()
+	return target.Help()
+}
+
+func runHelpAsk(root *cobra.Command, args []string) error {
+	question := strings.TrimSpace(strings.Join(args, " "))
+	if question == "" {
+		return fmt.Errorf("usage: gt help --ask \"question\"")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	rc, agentName, err := config.ResolveAgentConfigWithOverride(townRoot, filepath.Join(townRoot, "mayor"), helpAskAgent)
+	if err != nil {
+		return fmt.Errorf("resolving agent: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "%s Gathering town state...\n", style.Bold.Render("❓"))
+	snapshots := gatherHelpAskSnapshots(townRoot, question)
+	prompt := buildHelpAskPrompt(question, helpAskReference(root), helpAskCommandDocs(root, question), snapshots)
+
+	inv, err := buildAskInvocation(rc, agentName, prompt, "")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%s Asking help assistant (%s)...\n", style.Bold.Render("❓"), agentName)
+
+	ctx, cancel := context.WithTimeout(context.Background(), helpAskTimeout)
+	defer cancel()
+	c := exec.CommandContext(ctx, inv.Argv[0], inv.Argv[1:]...) //nolint:gosec // G204: argv from trusted agent config
+	c.Dir = townRoot
+	c.Env = clearClaudeCodeEnv(os.Environ())
+	var stdout bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("help assistant did not answer within %s", helpAskTimeout)
+		}
+		return fmt.Errorf("running help assistant: %w", err)
+	}
+
+	answer := strings.TrimSpace(stdout.String())
+	if inv.Structured {
+		if answer, _, err = parseAskStructuredOutput(stdout.Bytes()); err != nil {
+			return err
+		}
+	}
+	fmt.Println(answer)
+	return nil
+}
+
+// gatherHelpAskSnapshots runs read-only status commands in the town, plus
+// bd show for each bead the question mentions. Failing probes are kept:
+// "bead not found" is useful grounding too.
+func gatherHelpAskSnapshots(townRoot, question string) []helpAskSnapshot {
+	probes := [][]string{
+		{"gt", "status", "--fast"},
+		{"gt", "scheduler", "list"},
+		{"gt", "freeze"},
+	}
+	for _, id := range helpAskBeadIDs(question) {
+		probes = append(probes, []string{"bd", "show", id})
+	}
+
+	var snapshots []helpAskSnapshot
+	for _, argv := range probes {
+		title := strings.Join(argv, " ")
+		out, err := helpAskProbeFn(townRoot, argv...)
+		out = strings.TrimSpace(out)
+		if err != nil {
+			out = strings.TrimSpace(fmt.Sprintf("%s\n(exit: %v)", out, err))
+		}
+		if len(out) > helpAskMaxProbeOutput {
+			out = out[:helpAskMaxProbeOutput] + helpAskTruncatedMarker
+		}
+		snapshots = append(snapshots, helpAskSnapshot{Title: title, Output: out})
+	}
+	return snapshots
+}
+
+// helpAskBeadIDs picks bead IDs out of a question. Words like "read-only"
+// look like IDs too, so an ID must contain a digit after its prefix.
+func helpAskBeadIDs(question string) []string {
+	var ids []string
+	seen := map[string]bool{}
+	for _, word := range strings.Fields(question) {
+		word = strings.Trim(word, "\"'`?!,.:;()[]")
+		if seen[word] || !looksLikeBeadID(word) || !strings.ContainsAny(word[strings.Index(word, "-"):], "0123456789") {
+			continue
+		}
+		seen[word] = true
+		ids = append(ids, word)
+		if len(ids) == helpAskMaxBeads {
+			break
+		}
+	}
+	return ids
+}
+
+// helpAskReference lists every available command with its short help.
+func helpAskReference(root *cobra.Command) string {
+	var b strings.Builder
+	var walk func(c *cobra.Command)
+	walk = func(c *cobra.Command) {
+		for _, sub := range c.Commands() {
+			if !sub.IsAvailableCommand() {
+				continue
+			}
+			fmt.Fprintf(&b, "%s — %s\n", sub.CommandPath(), sub.Short)
+			walk(sub)
+		}
+	}
+	walk(root)
+	return b.String()
+}
+
+// helpAskCommandDocs returns the full help of top-level commands the
+// question names ("how do I sling to a crew member?").
+func helpAskCommandDocs(root *cobra.Command, question string) string {
+	var b strings.Builder
+	found := 0
+	words := map[string]bool{}
+	for _, word := range strings.Fields(strings.ToLower(question)) {
+		words[strings.Trim(word, "\"'`?!,.:;()[]")] = true
+	}
+	for _, c := range root.Commands() {
+		if !c.IsAvailableCommand() || !words[c.Name()] {
+			continue
+		}
+		fmt.Fprintf(&b, "### %s\n%s\n\nFlags:\n%s\n", c.CommandPath(), c.Long, c.LocalFlags().FlagUsages())
+		if found++; found == helpAskMaxCommands {
+			break
+		}
+	}
+	return b.String()
+}
+
+// buildHelpAskPrompt frames an operator's question for the help agent.
+func buildHelpAskPrompt(question, reference, commandDocs string, snapshots []helpAskSnapshot) string {
+	var b strings.Builder
+	b.WriteString("You are the help assistant for gt, the Gas Town multi-agent orchestrator.\n")
+	b.WriteString("An operator is asking about gt usage or about their town. Answer from the\n")
+	b.WriteString("command reference and town state below. This is read-only: do not modify\n")
+	b.WriteString("files or run commands that change state. Suggest exact gt commands the\n")
+	b.WriteString("operator can run. When the state below doesn't explain something, say so\n")
+	b.WriteString("and name the command that would show more instead of guessing.\n\n")
+
+	b.WriteString("## Command reference\n\n")
+	b.WriteString(reference)
+	if commandDocs != "" {
+		b.WriteString("\n## Commands mentioned in the question\n\n")
+		b.WriteString(commandDocs)
+	}
+
+	b.WriteString("\n## Town state (captured just now)\n")
+	for _, s := range snapshots {
+		fmt.Fprintf(&b, "\n$ %s\n%s\n", s.Title, s.Output)
+	}
+
+	b.WriteString("\n## Question\n\n")
+	b.WriteString(question)
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHelpAskBeadIDs(t *testing.T) {
+	got := helpAskBeadIDs(`why isn't tr-123 dispatching? is "gt-abc9" read-only, like tr-123?`)
+	if want := []string{"tr-123", "gt-abc9"}; !slices.Equal(got, want) {
+		t.Errorf("helpAskBeadIDs = %v, want %v", got, want)
+	}
+	if got := helpAskBeadIDs("how do I add a rig to a read-only town"); len(got) != 0 {
+		t.Errorf("no IDs expected, got %v", got)
+	}
+}
+
+func TestGatherHelpAskSnapshots(t *testing.T) {
+	orig := helpAskProbeFn
+	t.Cleanup(func() { helpAskProbeFn = orig })
+	var ran []string
+	helpAskProbeFn = func(townRoot string, argv ...string) (string, error) {
+		cmd := strings.Join(argv, " ")
+		ran = append(ran, cmd)
+		if cmd == "bd show tr-123" {
+			return "Error: no issue found", errors.New("exit status 1")
+		}
+		return "ok: " + cmd + "\n", nil
+	}
+
+	snapshots := gatherHelpAskSnapshots("/town", "why isn't tr-123 dispatching?")
+	want := []string{"gt status --fast", "gt scheduler list", "gt freeze", "bd show tr-123"}
+	if !slices.Equal(ran, want) {
+		t.Fatalf("probes = %v, want %v", ran, want)
+	}
+	if snapshots[0].Output != "ok: gt status --fast" {
+		t.Errorf("status snapshot = %q", snapshots[0].Output)
+	}
+	if last := snapshots[3].Output; !strings.Contains(last, "no issue found") || !strings.Contains(last, "exit status 1") {
+		t.Errorf("failed probe should keep its output and error, got %q", last)
+	}
+}
+
+func TestBuildHelpAskPrompt(t *testing.T) {
+	prompt := buildHelpAskPrompt("why isn't tr-123 dispatching?", "gt sling — Assign work\n", "",
+		[]helpAskSnapshot{{Title: "gt freeze", Output: "gastown frozen"}})
+	for _, want := range []string{"read-only", "gt sling — Assign work", "$ gt freeze\ngastown frozen", "why isn't tr-123 dispatching?"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+	if strings.Contains(prompt, "Commands mentioned") {
+		t.Error("prompt has a command docs section without any docs")
+	}
+}
+
+func TestHelpAskCommandDocs(t *testing.T) {
+	docs := helpAskCommandDocs(rootCmd, "How do I sling to a crew member?")
+	if !strings.Contains(docs, "### gt sling") || !strings.Contains(docs, "### gt crew") {
+		t.Errorf("docs missing sling or crew: %.200q", docs)
+	}
+	if strings.Contains(docs, "### gt mail") {
+		t.Error("docs include a command the question doesn't name")
+	}
+	if docs := helpAskCommandDocs(rootCmd, "what is a molecule?"); docs != "" {
+		t.Errorf("question names no command, got docs %.200q", docs)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // inferRigFromCwd tries to determine the rig from the current directory.
@@ -24,7 +25,7 @@ func inferRigFromCwd(townRoot string) (string, error) {
 	}
 
 	// Check if cwd is within a rig
-	rel, err := filepath.Rel(townRoot, cwd)
+	rel, err := workspace.RelPath(townRoot, cwd)
 	if err != nil {
 		return "", fmt.Errorf("not in workspace")
 	}
@@ -319,7 +319,7 @@ func runHook(_ *cobra.Command, args []string) error {
 					}
 					closeCmd := exec.Command("bd", closeArgs...)
 					closeCmd.Stderr = os.Stderr
-					if err := closeCmd.Run(); err != nil {
+					if err := beads.RunCmd(closeCmd); err != nil {
 						return fmt.Errorf("closing completed bead %s: %w", existing.ID, err)
 					}
 				} else {
@@ -0,0 +1,372 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/stalehook"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	hookListStale   bool
+	hookListJSON    bool
+	hookSweepDryRun bool
+)
+
+var hookListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List hooked beads across the town",
+	Long: `List every hooked bead in every rig, with its assignee and the last
+sign of work on it: output in the assignee's session, a commit in its
+worktree, or an update to the bead.
+
+With --stale, only hooks idle for longer than the town's stale_hooks
+"after" (default 6h) are listed. These are zombie hooks: the bead is
+claimed but nobody is working on it, so nothing else will pick it up.
+
+Configure the policy in town settings/config.json:
+
+  "stale_hooks": {"after": "6h", "auto_unhook": true, "grace": "1h"}
+
+With auto_unhook, 'gt hook sweep' (run by the daemon's stale_hooks patrol)
+unhooks and requeues stale hooks once they have stayed stale for the grace
+period.
+
+Examples:
+  gt hook list
+  gt hook list --stale
+  gt hook list --stale --json`,
+	Args: cobra.NoArgs,
+	RunE: runHookList,
+}
+
+var hookSweepCmd = &cobra.Command{
+	Use:   "sweep",
+	Short: "Apply the stale hook policy",
+	Long: `Flag stale hooks and unhook the ones whose grace period has run out.
+
+A hook first seen stale is flagged and its assignee nudged. With
+"auto_unhook": true in the town's stale_hooks settings, a hook still stale
+once "grace" (default 1h) has passed since it was flagged is unhooked: the
+bead goes back to open and unassigned, and polecat work is slung to its rig
+again. A hook that shows activity, or moves to another agent, loses its
+flag. Flags are kept in .runtime/stale-hooks.json.
+
+The daemon's stale_hooks patrol runs this periodically.
+
+Examples:
+  gt hook sweep
+  gt hook sweep --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runHookSweep,
+}
+
+var (
+	// hookListSourceFn is a seam for tests. Production searches the database for
+	// hooked beads.
+	hookListSourceFn = func(beadsDir string) ([]*beads.Issue, error) {
+		return beadSearchSourceFn(beadsDir, beads.SearchOptions{Status: beads.StatusHooked})
+	}
+
+	// hookSessionActivityFn is a seam for tests. Production reads the assignee's
+	// tmux session activity.
+	hookSessionActivityFn = func(assignee string) (bool, time.Time) {
+		sessionName, _ := assigneeToSessionName(assignee)
+		if sessionName == "" {
+			return false, time.Time{}
+		}
+		t := tmux.NewTmux()
+		if alive, err := t.HasSession(sessionName); err != nil || !alive {
+			return false, time.Time{}
+		}
+		activity, _ := t.GetSessionActivity(sessionName)
+		return true, activity
+	}
+
+	// hookLastCommitFn is a seam for tests. Production reads the last commit
+	// time in the assignee's worktree.
+	hookLastCommitFn = func(townRoot, assignee string) time.Time {
+		dir := hookWorktree(townRoot, assignee)
+		if dir == "" {
+			return time.Time{}
+		}
+		out, err := exec.Command("git", "-C", dir, "log", "-1", "--format=%cI").Output()
+		if err != nil {
+			return time.Time{}
+		}
+		t, _ := time.Parse(time.RFC3339, strings.TrimSpace(string(out)))
+		return t
+	}
+
+	// hookUnhookFn is a seam for tests. Production reopens the bead and clears
+	// its assignee.
+	hookUnhookFn = func(beadsDir, id string) error {
+		open, none := "open", ""
+		return beads.New(beadsDir).Update(id, beads.UpdateOptions{Status: &open, Assignee: &none})
+	}
+
+	// hookRequeueFn is a seam for tests. Production runs gt sling in the town.
+	hookRequeueFn = func(townRoot, id, rigName string) error {
+		cmd := exec.Command("gt", "sling", id, rigName)
+		cmd.Dir = townRoot
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%v (%s)", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	// hookStaleNudgeFn is a seam for tests. Production nudges like the label
+	// rules do.
+	hookStaleNudgeFn = labelRuleNudgeFn
+)
+
+func init() {
+	hookListCmd.Flags().BoolVar(&hookListStale, "stale", false, "Only list stale hooks")
+	hookListCmd.Flags().BoolVar(&hookListJSON, "json", false, "Output as JSON")
+	hookSweepCmd.Flags().BoolVarP(&hookSweepDryRun, "dry-run", "n", false, "Show what would be flagged and unhooked")
+	hookCmd.AddCommand(hookListCmd)
+	hookCmd.AddCommand(hookSweepCmd)
+}
+
+// hookedBead is a hook and the beads database it lives in.
+type hookedBead struct {
+	*stalehook.Hook
+	dir string
+}
+
+// listHooks returns every hooked bead in dbs with its signs of work, sorted
+// by bead ID, and warns about databases that could not be read.
+func listHooks(townRoot string, dbs []beadSearchDB) []hookedBead {
+	dirs := make(map[string]string, len(dbs))
+	for _, db := range dbs {
+		dirs[db.Rig] = db.Dir
+	}
+	result := searchAllBeads(dbs, hookListSourceFn)
+	for rigName, msg := range result.Errors {
+		style.PrintWarning("listing hooks in %s: %s", rigName, msg)
+	}
+
+	var hooks []hookedBead
+	for _, hit := range result.Results {
+		if hit.Status != beads.StatusHooked {
+			continue
+		}
+		h := &stalehook.Hook{Bead: hit.ID, Rig: hit.Rig, Title: hit.Title, Assignee: hit.Assignee}
+		h.Updated, _ = time.Parse(time.RFC3339, hit.UpdatedAt)
+		if h.Assignee != "" {
+			h.Session, h.Activity = hookSessionActivityFn(h.Assignee)
+			h.Commit = hookLastCommitFn(townRoot, h.Assignee)
+		}
+		hooks = append(hooks, hookedBead{Hook: h, dir: dirs[hit.Rig]})
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].Bead < hooks[j].Bead })
+	return hooks
+}
+
+// staleHooks filters hooks down to the stale ones.
+func staleHooks(hooks []hookedBead, after time.Duration, now time.Time) []hookedBead {
+	var stale []hookedBead
+	for _, h := range hooks {
+		if h.Stale(after, now) {
+			stale = append(stale, h)
+		}
+	}
+	return stale
+}
+
+// loadStaleHookConfig returns the town's stale_hooks settings, or nil (the
+// defaults) when there are none.
+func loadStaleHookConfig(townRoot string) (*stalehook.Config, error) {
+	ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if err := ts.StaleHooks.Validate(); err != nil {
+		return nil, fmt.Errorf("stale_hooks: %w", err)
+	}
+	return ts.StaleHooks, nil
+}
+
+func runHookList(_ *cobra.Command, _ []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := loadStaleHookConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	dbs, err := beadSearchDBs(townRoot)
+	if err != nil {
+		return err
+	}
+	hooks := listHooks(townRoot, dbs)
+	now := time.Now()
+	after := cfg.AfterDuration()
+	if hookListStale {
+		hooks = staleHooks(hooks, after, now)
+	}
+
+	if hookListJSON {
+		out := make([]*stalehook.Hook, 0, len(hooks))
+		for _, h := range hooks {
+			out = append(out, h.Hook)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+
+	if len(hooks) == 0 {
+		if hookListStale {
+			fmt.Printf("No stale hooks (idle over %s).\n", after)
+		} else {
+			fmt.Println("No hooked beads.")
+		}
+		return nil
+	}
+	for _, h := range hooks {
+		marker := style.Success.Render("●")
+		if h.Stale(after, now) {
+			marker = style.Warning.Render("⚠")
+		}
+		assignee := h.Assignee
+		if assignee == "" {
+			assignee = "(unassigned)"
+		}
+		fmt.Printf("%s %s  %s  %s\n", marker, style.Bold.Render(h.Bead), assignee, h.Title)
+		fmt.Printf("    %s\n", style.Dim.Render(describeHookSigns(h.Hook, now)))
+	}
+	return nil
+}
+
+// describeHookSigns summarizes a hook's session and last sign of work.
+func describeHookSigns(h *stalehook.Hook, now time.Time) string {
+	session := "no session"
+	if h.Session {
+		session = "session running"
+	}
+	last := h.LastSign()
+	if last.IsZero() {
+		return session + ", no sign of work"
+	}
+	what := "bead update"
+	switch {
+	case last.Equal(h.Activity):
+		what = "session output"
+	case last.Equal(h.Commit):
+		what = "commit"
+	}
+	return fmt.Sprintf("%s, last %s %s ago", session, what, now.Sub(last).Round(time.Minute))
+}
+
+func runHookSweep(_ *cobra.Command, _ []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := loadStaleHookConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	state, err := stalehook.LoadState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading stale hook state: %w", err)
+	}
+	dbs, err := beadSearchDBs(townRoot)
+	if err != nil {
+		return err
+	}
+	sweepStaleHooks(townRoot, cfg, listHooks(townRoot, dbs), state, hookSweepDryRun, time.Now())
+	if hookSweepDryRun {
+		return nil
+	}
+	return state.Save(townRoot)
+}
+
+// sweepStaleHooks flags newly stale hooks, nudging their assignees, and
+// unhooks and requeues the ones whose grace has run out when the policy
+// says to.
+func sweepStaleHooks(townRoot string, cfg *stalehook.Config, hooks []hookedBead, state *stalehook.State, dryRun bool, now time.Time) {
+	stale := staleHooks(hooks, cfg.AfterDuration(), now)
+	dirs := make(map[string]string, len(stale))
+	list := make([]*stalehook.Hook, 0, len(stale))
+	for _, h := range stale {
+		dirs[h.Bead] = h.dir
+		list = append(list, h.Hook)
+	}
+
+	grace := cfg.GraceDuration()
+	flagged, due := state.Sweep(list, grace, now)
+	for _, h := range flagged {
+		idle := describeHookSigns(h, now)
+		if dryRun {
+			fmt.Printf("%s flag %s (%s): %s\n", style.Dim.Render("[dry-run]"), h.Bead, h.Assignee, idle)
+			continue
+		}
+		fmt.Printf("%s Stale hook %s (%s): %s\n", style.Warning.Render("⚠"), h.Bead, h.Assignee, idle)
+		if h.Session {
+			msg := fmt.Sprintf("Your hook %s looks stale (%s).", h.Bead, idle)
+			if cfg.Unhooks() {
+				msg += fmt.Sprintf(" It will be unhooked in %s unless you work on it.", grace)
+			}
+			if err := hookStaleNudgeFn(townRoot, h.Assignee, msg); err != nil {
+				style.PrintWarning("nudging %s: %v", h.Assignee, err)
+			}
+		}
+	}
+
+	if cfg.Unhooks() {
+		for _, h := range due {
+			if dryRun {
+				fmt.Printf("%s unhook %s from %s\n", style.Dim.Render("[dry-run]"), h.Bead, h.Assignee)
+				continue
+			}
+			if err := hookUnhookFn(dirs[h.Bead], h.Bead); err != nil {
+				style.PrintWarning("unhooking %s: %v", h.Bead, err)
+				continue
+			}
+			state.Clear(h.Bead)
+			_ = events.LogFeed(events.TypeUnhook, detectActor(), events.UnhookPayload(h.Bead))
+			fmt.Printf("%s Unhooked %s from %s\n", style.Success.Render("✓"), h.Bead, h.Assignee)
+			if rigName, role, _ := strings.Cut(h.Assignee, "/"); strings.HasPrefix(role, "polecats/") {
+				if err := hookRequeueFn(townRoot, h.Bead, rigName); err != nil {
+					style.PrintWarning("requeueing %s to %s: %v", h.Bead, rigName, err)
+				} else {
+					fmt.Printf("  Requeued to %s\n", rigName)
+				}
+			}
+		}
+	}
+
+}
+
+// hookWorktree returns the git worktree of a polecat or crew assignee
+// (rig/polecats/<name>/<rig> or the older rig/polecats/<name>), or "" when
+// there is none.
+func hookWorktree(townRoot, assignee string) string {
+	parts := strings.Split(assignee, "/")
+	if len(parts) != 3 || (parts[1] != "polecats" && parts[1] != "crew") {
+		return ""
+	}
+	base := filepath.Join(townRoot, parts[0], parts[1], parts[2])
+	for _, dir := range []string{filepath.Join(base, parts[0]), base} {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/stalehook"
+)
+
+func TestListHooks(t *testing.T) {
+	origSource, origActivity, origCommit := hookListSourceFn, hookSessionActivityFn, hookLastCommitFn
+	t.Cleanup(func() {
+		hookListSourceFn, hookSessionActivityFn, hookLastCommitFn = origSource, origActivity, origCommit
+	})
+
+	now := time.Now()
+	hookListSourceFn = func(dir string) ([]*beads.Issue, error) {
+		return []*beads.Issue{
+			{ID: "gp-2", Status: beads.StatusHooked, Assignee: "greenplace/crew/max", UpdatedAt: now.Add(-time.Hour).Format(time.RFC3339)},
+			{ID: "gp-1", Status: beads.StatusHooked, Assignee: "greenplace/polecats/toast", UpdatedAt: now.Add(-20 * time.Hour).Format(time.RFC3339)},
+			{ID: "gp-3", Status: "open"},
+		}, nil
+	}
+	hookSessionActivityFn = func(assignee string) (bool, time.Time) {
+		if assignee == "greenplace/crew/max" {
+			return true, now.Add(-time.Minute)
+		}
+		return false, time.Time{}
+	}
+	hookLastCommitFn = func(townRoot, assignee string) time.Time { return time.Time{} }
+
+	hooks := listHooks("/town", []beadSearchDB{{Rig: "greenplace", Dir: "/town/greenplace/mayor/rig"}})
+	if len(hooks) != 2 || hooks[0].Bead != "gp-1" || hooks[1].Bead != "gp-2" {
+		t.Fatalf("hooks = %+v, want gp-1 and gp-2", hooks)
+	}
+	if hooks[0].dir != "/town/greenplace/mayor/rig" || hooks[0].Rig != "greenplace" {
+		t.Errorf("gp-1 located in %q (%s)", hooks[0].dir, hooks[0].Rig)
+	}
+	if !hooks[1].Session || !hooks[1].Activity.Equal(now.Add(-time.Minute)) {
+		t.Errorf("gp-2 session activity not recorded: %+v", hooks[1].Hook)
+	}
+	stale := staleHooks(hooks, 6*time.Hour, now)
+	if len(stale) != 1 || stale[0].Bead != "gp-1" {
+		t.Errorf("stale = %+v, want gp-1", stale)
+	}
+}
+
+func TestSweepStaleHooks(t *testing.T) {
+	t.Chdir(setupTestTownForConfig(t)) // The sweep logs unhook events to the town feed
+	origUnhook, origRequeue, origNudge := hookUnhookFn, hookRequeueFn, hookStaleNudgeFn
+	t.Cleanup(func() { hookUnhookFn, hookRequeueFn, hookStaleNudgeFn = origUnhook, origRequeue, origNudge })
+
+	var actions []string
+	hookUnhookFn = func(dir, id string) error {
+		actions = append(actions, "unhook "+id+" "+dir)
+		return nil
+	}
+	hookRequeueFn = func(townRoot, id, rigName string) error {
+		actions = append(actions, "requeue "+id+" "+rigName)
+		return nil
+	}
+	hookStaleNudgeFn = func(townRoot, agent, message string) error {
+		actions = append(actions, "nudge "+agent)
+		return nil
+	}
+
+	now := time.Now()
+	idle := now.Add(-10 * time.Hour)
+	hooks := []hookedBead{
+		{Hook: &stalehook.Hook{Bead: "gp-1", Assignee: "greenplace/polecats/toast", Updated: idle}, dir: "/db"},
+		{Hook: &stalehook.Hook{Bead: "gp-2", Assignee: "greenplace/crew/max", Updated: idle, Session: true}, dir: "/db"},
+		{Hook: &stalehook.Hook{Bead: "gp-3", Assignee: "greenplace/crew/ann", Updated: now}, dir: "/db"},
+	}
+	cfg := &stalehook.Config{AutoUnhook: true, Grace: "1h"}
+	state := &stalehook.State{Flagged: make(map[string]stalehook.Flag)}
+
+	sweepStaleHooks("/town", cfg, hooks, state, false, now)
+	if len(actions) != 1 || actions[0] != "nudge greenplace/crew/max" {
+		t.Errorf("first sweep actions = %v, want only the nudge", actions)
+	}
+	if len(state.Flagged) != 2 {
+		t.Errorf("flagged = %v, want gp-1 and gp-2", state.Flagged)
+	}
+
+	actions = nil
+	sweepStaleHooks("/town", cfg, hooks, state, true, now.Add(2*time.Hour))
+	if len(actions) != 0 {
+		t.Errorf("dry run acted: %v", actions)
+	}
+
+	actions = nil
+	sweepStaleHooks("/town", cfg, hooks, state, false, now.Add(2*time.Hour))
+	want := []string{"unhook gp-1 /db", "requeue gp-1 greenplace", "unhook gp-2 /db"}
+	if len(actions) != len(want) {
+		t.Fatalf("actions = %v, want %v", actions, want)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Errorf("action %d = %q, want %q", i, actions[i], want[i])
+		}
+	}
+	if len(state.Flagged) != 0 {
+		t.Errorf("unhooked beads still flagged: %v", state.Flagged)
+	}
+
+	// Without auto_unhook, stale hooks are only flagged.
+	actions = nil
+	state = &stalehook.State{Flagged: make(map[string]stalehook.Flag)}
+	sweepStaleHooks("/town", &stalehook.Config{Grace: "0s"}, hooks, state, false, now)
+	if len(actions) != 1 || actions[0] != "nudge greenplace/crew/max" {
+		t.Errorf("list-only policy acted: %v", actions)
+	}
+}
+
+func TestHookWorktree(t *testing.T) {
+	town := t.TempDir()
+	newLayout := filepath.Join(town, "greenplace", "polecats", "toast", "greenplace")
+	oldLayout := filepath.Join(town, "greenplace", "crew", "max")
+	for _, dir := range []string{newLayout, oldLayout} {
+		if err := os.MkdirAll(filepath.Join(dir, ".git"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := map[string]string{
+		"greenplace/polecats/toast": newLayout,
+		"greenplace/crew/max":       oldLayout,
+		"greenplace/crew/ann":       "",
+		"mayor/":                    "",
+	}
+	for assignee, want := range tests {
+		if got := hookWorktree(town, assignee); got != want {
+			t.Errorf("hookWorktree(%q) = %q, want %q", assignee, got, want)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/daemon"
 	"github.com/steveyegge/gastown/internal/git"
@@ -175,7 +176,7 @@ func registerCustomTypes(workDir string) error {
 	// Try to set custom types
 	cmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
 	cmd.Dir = workDir
-	output, err := cmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(cmd)
 	if err != nil {
 		// Check for common expected errors
 		outStr := string(output)
@@ -43,6 +43,7 @@ var (
 	installWrappers   bool
 	installSupervisor bool
 	installDoltPort   int
+	installTemplate   string
 )
 
 var installCmd = &cobra.Command{
@@ -70,7 +71,8 @@ Examples:
   gt install ~/gt --github=user/repo           # Create private GitHub repo (default)
   gt install ~/gt --github=user/repo --public  # Create public GitHub repo
   gt install ~/gt --shell                      # Install shell integration (sets GT_TOWN_ROOT/GT_RIG)
-  gt install ~/gt --supervisor                 # Configure launchd/systemd for daemon auto-restart`,
+  gt install ~/gt --supervisor                 # Configure launchd/systemd for daemon auto-restart
+  gt install ~/gt --template ~/templates/acme  # Bootstrap from a town template (gt town template)`,
 	Args:         cobra.MaximumNArgs(1),
 	RunE:         runInstall,
 	SilenceUsage: true,
@@ -88,6 +90,7 @@ func init() {
 	installCmd.Flags().BoolVar(&installShell, "shell", false, "Install shell integration (sets GT_TOWN_ROOT/GT_RIG env vars)")
 	installCmd.Flags().BoolVar(&installWrappers, "wrappers", false, "Install gt-codex/gt-gemini/gt-opencode wrapper scripts to ~/bin/")
 	installCmd.Flags().BoolVar(&installSupervisor, "supervisor", false, "Configure launchd/systemd for daemon auto-restart")
+	installCmd.Flags().StringVar(&installTemplate, "template", "", "Bootstrap settings, roles and formulas from a town template (directory or git URL)")
 	installCmd.Flags().IntVar(&installDoltPort, "dolt-port", 0, "Dolt SQL server port (default 3307; set when another instance owns the default port)")
 	rootCmd.AddCommand(installCmd)
 }
@@ -235,12 +238,13 @@ func runInstall(cmd *cobra.Command, args []string) error {
 	townPath := filepath.Join(mayorDir, "town.json")
 	if townInfo, err := os.Stat(townPath); os.IsNotExist(err) {
 		townConfig := &config.TownConfig{
-			Type:       "town",
-			Version:    config.CurrentTownVersion,
-			Name:       townName,
-			Owner:      owner,
-			PublicName: publicName,
-			CreatedAt:  time.Now(),
+			Type:        "town",
+			Version:     config.CurrentTownVersion,
+			Name:        townName,
+			Owner:       owner,
+			PublicName:  publicName,
+			CreatedAt:   time.Now(),
+			CreatedWith: Version,
 		}
 		if err := config.SaveTownConfig(townPath, townConfig); err != nil {
 			return fmt.Errorf("writing town.json: %w", err)
@@ -401,7 +405,7 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		routingCmd := exec.Command("bd", "config", "set", "routing.mode", "explicit")
 		routingCmd.Dir = absPath
 		routingCmd.Env = withBeadsDirEnv(filepath.Join(absPath, ".beads"))
-		if out, err := routingCmd.CombinedOutput(); err != nil {
+		if out, err := beads.CmdCombinedOutput(routingCmd); err != nil {
 			fmt.Printf("   %s Could not set routing.mode: %s\n", style.Dim.Render("⚠"), strings.TrimSpace(string(out)))
 		}
 	}
@@ -427,6 +431,14 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		fmt.Printf("   ✓ Created settings/escalation.json\n")
 	}
 
+	// Apply the town template before hooks are synced, so its role and
+	// agent settings are in place for the generated settings files.
+	if installTemplate != "" {
+		if err := applyTownTemplate(installTemplate, absPath, true); err != nil {
+			fmt.Printf("   %s Could not apply template: %v\n", style.Dim.Render("⚠"), err)
+		}
+	}
+
 	// Provision town-level slash commands (.claude/commands/)
 	// All agents inherit these via Claude's directory traversal - no per-workspace copies needed.
 	if err := templates.ProvisionCommands(absPath); err != nil {
@@ -603,7 +615,7 @@ func initTownBeads(townPath string) error {
 	cmd.Dir = townPath
 	cmd.Env = withBeadsDirEnv(filepath.Join(townPath, ".beads"))
 
-	output, err := cmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(cmd)
 	if err != nil {
 		// Check if beads is already initialized
 		if strings.Contains(string(output), "already initialized") {
@@ -637,7 +649,7 @@ func initTownBeads(townPath string) error {
 	roleSetCmd := exec.Command("bd", "config", "set", "beads.role", "maintainer")
 	roleSetCmd.Dir = townPath
 	roleSetCmd.Env = beadsEnv
-	if roleOutput, roleErr := roleSetCmd.CombinedOutput(); roleErr != nil {
+	if roleOutput, roleErr := beads.CmdCombinedOutput(roleSetCmd); roleErr != nil {
 		fmt.Printf("   %s Could not set beads.role: %s\n", style.Dim.Render("⚠"), strings.TrimSpace(string(roleOutput)))
 	}
 
@@ -645,7 +657,7 @@ func initTownBeads(townPath string) error {
 	prefixSetCmd := exec.Command("bd", "config", "set", "issue_prefix", "hq")
 	prefixSetCmd.Dir = townPath
 	prefixSetCmd.Env = beadsEnv
-	if prefixOutput, prefixErr := prefixSetCmd.CombinedOutput(); prefixErr != nil {
+	if prefixOutput, prefixErr := beads.CmdCombinedOutput(prefixSetCmd); prefixErr != nil {
 		return fmt.Errorf("bd config set issue_prefix failed: %s", strings.TrimSpace(string(prefixOutput)))
 	}
 
@@ -660,7 +672,7 @@ func initTownBeads(townPath string) error {
 	prefixCmd := exec.Command("bd", "config", "set", "allowed_prefixes", "hq,hq-cv")
 	prefixCmd.Dir = townPath
 	prefixCmd.Env = beadsEnv
-	if prefixOutput, prefixErr := prefixCmd.CombinedOutput(); prefixErr != nil {
+	if prefixOutput, prefixErr := beads.CmdCombinedOutput(prefixCmd); prefixErr != nil {
 		fmt.Printf("   %s Could not set allowed_prefixes: %s\n", style.Dim.Render("⚠"), strings.TrimSpace(string(prefixOutput)))
 	}
 
@@ -709,7 +721,7 @@ func withBeadsDirEnv(beadsDir string) []string {
 func ensureCustomTypes(beadsPath string) error {
 	cmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
 	cmd.Dir = beadsPath
-	output, err := cmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("bd config set types.custom: %s", strings.TrimSpace(string(output)))
 	}
@@ -800,7 +812,7 @@ func ensureBeadsCustomTypes(workDir string, types []string) error {
 
 	cmd := exec.Command("bd", "config", "set", "types.custom", strings.Join(types, ","))
 	cmd.Dir = workDir
-	output, err := cmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("bd config set types.custom failed: %s", strings.TrimSpace(string(output)))
 	}
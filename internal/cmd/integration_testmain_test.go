@@ -8,10 +8,14 @@ import (
 	"os"
 	"testing"
 
+	"github.com/steveyegge/gastown/internal/testtown"
 	"github.com/steveyegge/gastown/internal/testutil"
 )
 
 func TestMain(m *testing.M) {
+	// Tests built on testtown start this binary as bd and as the agent.
+	testtown.RunFakes()
+
 	// Force sequential test execution to avoid bd file locks on Windows.
 	_ = flag.Set("test.parallel", "1")
 	flag.Parse()
@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/knowledge"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Related solutions shown at prime time are deliberately few and fairly
+// similar: a wrong "past solution" costs more context than it saves.
+const (
+	primeSolutionThreshold = 0.3
+	primeSolutionMax       = 3
+	knowledgeTextMaxLines  = 8
+)
+
+var (
+	knowledgeKind  string
+	knowledgeLimit int
+	knowledgeJSON  bool
+)
+
+var knowledgeCmd = &cobra.Command{
+	Use:     "knowledge",
+	GroupID: GroupWork,
+	Short:   "Search and maintain the per-rig knowledge base",
+	Long: `Search and maintain the per-rig knowledge base.
+
+The knowledge base holds answers from 'gt ask' and solutions distilled from
+completed beads (problem, approach, final diff summary). Solutions are
+indexed automatically by 'gt done'; 'gt knowledge index' backfills from
+closed beads.
+
+When a polecat primes on a bead, the most similar past solutions from its
+rig are shown so it can reuse earlier decisions instead of re-deriving them.`,
+	RunE: requireSubcommand,
+}
+
+var knowledgeSearchCmd = &cobra.Command{
+	Use:   "search <rig> <query>",
+	Short: "Find knowledge artifacts similar to a query",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runKnowledgeSearch,
+}
+
+var knowledgeListCmd = &cobra.Command{
+	Use:   "list <rig>",
+	Short: "List knowledge artifacts for a rig",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runKnowledgeList,
+}
+
+var knowledgeIndexCmd = &cobra.Command{
+	Use:   "index <rig>",
+	Short: "Backfill solutions from closed beads",
+	Long: `Index closed beads that are not yet in the knowledge base.
+
+Backfilled entries carry the problem statement and close reason. Diff
+summaries are only available for beads completed through 'gt done'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runKnowledgeIndex,
+}
+
+func init() {
+	knowledgeSearchCmd.Flags().StringVar(&knowledgeKind, "kind", "", "Restrict to kind: ask or solution")
+	knowledgeSearchCmd.Flags().IntVar(&knowledgeLimit, "limit", 5, "Maximum results")
+	knowledgeSearchCmd.Flags().BoolVar(&knowledgeJSON, "json", false, "Output as JSON")
+	knowledgeListCmd.Flags().StringVar(&knowledgeKind, "kind", "", "Restrict to kind: ask or solution")
+	knowledgeListCmd.Flags().BoolVar(&knowledgeJSON, "json", false, "Output as JSON")
+
+	knowledgeCmd.AddCommand(knowledgeSearchCmd, knowledgeListCmd, knowledgeIndexCmd)
+	rootCmd.AddCommand(knowledgeCmd)
+}
+
+func runKnowledgeSearch(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	results, err := knowledge.Search(r.Path, knowledgeKind, strings.Join(args[1:], " "), 0.05, knowledgeLimit)
+	if err != nil {
+		return err
+	}
+	if knowledgeJSON {
+		return printKnowledgeJSON(results)
+	}
+	if len(results) == 0 {
+		fmt.Println("No matching knowledge.")
+		return nil
+	}
+	for _, res := range results {
+		printKnowledgeArtifact(res.Artifact, fmt.Sprintf("%.0f%%", res.Score*100))
+	}
+	return nil
+}
+
+func runKnowledgeList(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	all, err := knowledge.List(r.Path)
+	if err != nil {
+		return err
+	}
+	var filtered []knowledge.Artifact
+	for _, a := range all {
+		if knowledgeKind == "" || a.Kind == knowledgeKind {
+			filtered = append(filtered, a)
+		}
+	}
+	if knowledgeJSON {
+		return printKnowledgeJSON(filtered)
+	}
+	if len(filtered) == 0 {
+		fmt.Printf("No knowledge recorded for %s.\n", args[0])
+		return nil
+	}
+	for _, a := range filtered {
+		printKnowledgeArtifact(a, "")
+	}
+	return nil
+}
+
+func runKnowledgeIndex(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	existing, err := knowledge.List(r.Path)
+	if err != nil {
+		return err
+	}
+
+	closed, err := beads.New(r.BeadsPath()).List(beads.ListOptions{Status: "closed", Priority: -1})
+	if err != nil {
+		return fmt.Errorf("listing closed beads: %w", err)
+	}
+
+	added := 0
+	for _, issue := range closed {
+		if issue.Ephemeral || isDedupInfraBead(issue) || knowledge.HasSolution(existing, issue.ID) {
+			continue
+		}
+		a := solutionFromIssue(rigName, issue)
+		if err := knowledge.Append(r.Path, a); err != nil {
+			return err
+		}
+		added++
+	}
+	fmt.Printf("%s Indexed %d solution(s) for %s\n", style.Success.Render("✓"), added, rigName)
+	return nil
+}
+
+// solutionFromIssue builds a solution artifact from a closed bead.
+func solutionFromIssue(rigName string, issue *beads.Issue) *knowledge.Artifact {
+	return &knowledge.Artifact{
+		Kind:    knowledge.KindSolution,
+		Rig:     rigName,
+		BeadID:  issue.ID,
+		Title:   issue.Title,
+		Problem: issue.Description,
+		Author:  issue.Assignee,
+	}
+}
+
+// recordSolutionKnowledge indexes a bead completed via gt done, including the
+// commit subjects (approach) and diff stat against base. Failures are ignored:
+// the knowledge base must never block completion.
+func recordSolutionKnowledge(townRoot, rigName, issueID, author string, g *git.Git, base string) {
+	if townRoot == "" || rigName == "" || issueID == "" || g == nil {
+		return
+	}
+	rigPath := filepath.Join(townRoot, rigName)
+	if existing, err := knowledge.List(rigPath); err != nil || knowledge.HasSolution(existing, issueID) {
+		return
+	}
+
+	issue, err := beads.New(rigPath).Show(issueID)
+	if err != nil || issue == nil {
+		return
+	}
+
+	a := solutionFromIssue(rigName, issue)
+	a.Author = author
+	if subjects, err := g.CommitSubjects(base, "HEAD"); err == nil {
+		a.Approach = strings.Join(subjects, "\n")
+	}
+	if stat, err := g.DiffStat(base, "HEAD"); err == nil {
+		a.DiffSummary = stat
+	}
+	_ = knowledge.Append(rigPath, a)
+}
+
+// outputRelatedSolutions prints past solutions similar to the hooked bead.
+// Called from gt prime so newly slung work carries prior decisions.
+func outputRelatedSolutions(ctx RoleContext, hookedBead *beads.Issue) {
+	if ctx.Rig == "" || ctx.TownRoot == "" || hookedBead == nil {
+		return
+	}
+	results, err := knowledge.Search(filepath.Join(ctx.TownRoot, ctx.Rig), knowledge.KindSolution,
+		hookedBead.Title+"\n"+hookedBead.Description, primeSolutionThreshold, primeSolutionMax+1)
+	if err != nil {
+		return
+	}
+
+	var shown int
+	for _, res := range results {
+		if res.BeadID == hookedBead.ID || shown == primeSolutionMax {
+			continue
+		}
+		if shown == 0 {
+			fmt.Printf("%s\n\n", style.Bold.Render("## 📚 Related Past Solutions"))
+			fmt.Println("Similar beads were completed in this rig. Reuse their decisions where they apply:")
+			fmt.Println()
+		}
+		shown++
+		fmt.Printf("- %s: %s (%.0f%% similar)\n", res.BeadID, res.Title, res.Score*100)
+		if res.Approach != "" {
+			fmt.Println("  Approach:")
+			for _, line := range truncateLines(res.Approach, knowledgeTextMaxLines) {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+		if res.DiffSummary != "" {
+			fmt.Println("  Diff:")
+			for _, line := range truncateLines(res.DiffSummary, knowledgeTextMaxLines) {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+	}
+	if shown > 0 {
+		fmt.Println()
+	}
+}
+
+func printKnowledgeArtifact(a knowledge.Artifact, score string) {
+	label := a.Title
+	if label == "" {
+		label = a.Question
+	}
+	ref := a.ID
+	if a.BeadID != "" {
+		ref = a.BeadID
+	}
+	suffix := ""
+	if score != "" {
+		suffix = " " + style.Dim.Render("("+score+")")
+	}
+	fmt.Printf("%s [%s] %s%s\n", style.Bold.Render(ref), a.Kind, label, suffix)
+	body := a.Answer
+	if a.Kind == knowledge.KindSolution {
+		body = a.Approach
+	}
+	for _, line := range truncateLines(body, 4) {
+		fmt.Printf("    %s\n", line)
+	}
+}
+
+func printKnowledgeJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// truncateLines splits text into at most max non-empty lines, appending "..."
+// when lines were dropped.
+func truncateLines(text string, max int) []string {
+	var lines []string
+	for _, l := range strings.Split(strings.TrimSpace(text), "\n") {
+		if strings.TrimSpace(l) != "" {
+			lines = append(lines, l)
+		}
+	}
+	if len(lines) > max {
+		lines = append(lines[:max], "...")
+	}
+	return lines
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/knowledge"
+)
+
+func TestTruncateLines(t *testing.T) {
+	got := truncateLines("a\n\nb\nc\nd\n", 2)
+	want := []string{"a", "b", "..."}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("truncateLines() = %v, want %v", got, want)
+	}
+	if got := truncateLines("", 3); len(got) != 0 {
+		t.Errorf("truncateLines(\"\") = %v, want empty", got)
+	}
+}
+
+func TestOutputRelatedSolutions(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "gastown")
+	for _, a := range []*knowledge.Artifact{
+		{Kind: knowledge.KindSolution, BeadID: "gt-old", Title: "Retry webhook delivery on timeout", Approach: "Wrap sender in backoff loop"},
+		{Kind: knowledge.KindSolution, BeadID: "gt-self", Title: "Retry webhook delivery on timeout"},
+		{Kind: knowledge.KindSolution, BeadID: "gt-css", Title: "Dark mode toggle"},
+	} {
+		if err := knowledge.Append(rigPath, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx := RoleContext{Role: RolePolecat, Rig: "gastown", TownRoot: townRoot}
+	out := captureStdout(t, func() {
+		outputRelatedSolutions(ctx, &beads.Issue{ID: "gt-self", Title: "Webhook delivery should retry on timeout"})
+	})
+
+	if !strings.Contains(out, "Related Past Solutions") || !strings.Contains(out, "gt-old") {
+		t.Errorf("expected gt-old in related solutions, got:\n%s", out)
+	}
+	if strings.Contains(out, "gt-self") {
+		t.Errorf("hooked bead should not be listed as its own solution:\n%s", out)
+	}
+	if strings.Contains(out, "gt-css") {
+		t.Errorf("unrelated solution should not be shown:\n%s", out)
+	}
+}
+
+func TestOutputRelatedSolutions_EmptyKnowledge(t *testing.T) {
+	ctx := RoleContext{Role: RolePolecat, Rig: "gastown", TownRoot: t.TempDir()}
+	out := captureStdout(t, func() {
+		outputRelatedSolutions(ctx, &beads.Issue{ID: "gt-1", Title: "anything"})
+	})
+	if out != "" {
+		t.Errorf("expected no output without knowledge, got %q", out)
+	}
+}
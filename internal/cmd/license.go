@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/deppolicy"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	licenseRig  string
+	licenseBase string
+	licenseJSON bool
+)
+
+var licenseCmd = &cobra.Command{
+	Use:     "license",
+	GroupID: GroupWork,
+	Short:   "Dependency license and package policy",
+	Long: `Check dependency changes against the rig's license and package policy.
+
+When a rig's config.json sets merge_queue.dependency_policy, the refinery
+scans every merge for added or upgraded dependencies (go.mod, package.json,
+requirements*.txt, Cargo.toml) and refuses to merge branches that bring in
+a denied package or license. The polecat is told why and the violation is
+escalated for a human decision.
+
+Licenses are read from the Go module cache, node_modules, and the Cargo
+registry. Packages whose license can't be found follow the "unknown" mode
+(warn, block, or allow); pin them with "licenses" overrides.
+
+Example rig config:
+  "merge_queue": {
+    "dependency_policy": {
+      "deny_licenses": ["GPL-*", "AGPL-*", "LGPL-*", "SSPL-*"],
+      "deny_packages": ["github.com/abandoned/*"],
+      "allow_packages": ["github.com/ourco/*"],
+      "licenses": {"github.com/vendor/sdk": "MIT"},
+      "unknown": "block"
+    }
+  }
+
+Examples:
+  gt license check                    # Check this branch's dependency changes
+  gt license check --base origin/integration/auth --json`,
+	RunE: requireSubcommand,
+}
+
+var licenseCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check this branch's dependency changes against the rig policy",
+	Long: `Scan the dependencies this branch adds or upgrades relative to its base and
+apply the rig's dependency policy, the same way the refinery will. Exits
+non-zero if any dependency would block the merge.`,
+	Args: cobra.NoArgs,
+	RunE: runLicenseCheck,
+}
+
+func init() {
+	licenseCheckCmd.Flags().StringVar(&licenseRig, "rig", "", "Rig whose policy to use (default: current rig)")
+	licenseCheckCmd.Flags().StringVar(&licenseBase, "base", "", "Diff base (default: origin/<rig default branch>)")
+	licenseCheckCmd.Flags().BoolVar(&licenseJSON, "json", false, "Output the report as JSON")
+
+	licenseCmd.AddCommand(licenseCheckCmd)
+	rootCmd.AddCommand(licenseCmd)
+}
+
+// loadDependencyPolicy returns the rig's merge_queue.dependency_policy.
+func loadDependencyPolicy(r *rig.Rig) (*deppolicy.Config, error) {
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return nil, err
+	}
+	cfg := eng.Config().DependencyPolicy
+	if !cfg.IsEnabled() {
+		return nil, fmt.Errorf("rig %s has no dependency policy (set merge_queue.dependency_policy in %s/config.json)", r.Name, r.Name)
+	}
+	return cfg, nil
+}
+
+func runLicenseCheck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	var r *rig.Rig
+	if licenseRig != "" {
+		_, r, err = getRig(licenseRig)
+	} else {
+		_, r, err = findCurrentRig(townRoot)
+	}
+	if err != nil {
+		return err
+	}
+	cfg, err := loadDependencyPolicy(r)
+	if err != nil {
+		return err
+	}
+	repoRoot, err := detectCloneRoot()
+	if err != nil {
+		return err
+	}
+	base := licenseBase
+	if base == "" {
+		base = rigOriginBase(r.Path)
+	}
+
+	report, err := checkDependencyPolicyAt(cfg, repoRoot, base)
+	if err != nil {
+		return err
+	}
+
+	if licenseJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printDependencyReport(report)
+	}
+	if len(report.Violations()) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// checkDependencyPolicyAt applies cfg to the dependency changes between the
+// merge base of base and HEAD in dir.
+func checkDependencyPolicyAt(cfg *deppolicy.Config, dir, base string) (*deppolicy.Report, error) {
+	mbCmd := exec.Command("git", "merge-base", base, "HEAD")
+	mbCmd.Dir = dir
+	out, err := mbCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("finding merge base with %s: %w", base, err)
+	}
+	deps, err := deppolicy.Scan(dir, strings.TrimSpace(string(out)))
+	if err != nil {
+		return nil, err
+	}
+	resolver := &deppolicy.Resolver{Dir: dir}
+	return deppolicy.Check(cfg, deps, resolver.Resolve), nil
+}
+
+func printDependencyReport(report *deppolicy.Report) {
+	if len(report.Findings) == 0 {
+		fmt.Println("No dependency changes.")
+		return
+	}
+	for _, f := range report.Findings {
+		license := f.License
+		if license == "" {
+			license = "?"
+		}
+		var mark string
+		switch {
+		case f.Blocking:
+			mark = style.Warning.Render("✗")
+		case f.Verdict == deppolicy.Unknown:
+			mark = style.Dim.Render("?")
+		default:
+			mark = style.Success.Render("✓")
+		}
+		line := fmt.Sprintf("  %s %-50s %-20s %s", mark, f.Dependency, license, style.Dim.Render(f.Dependency.Manifest))
+		if f.Reason != "" {
+			line += "  " + f.Reason
+		}
+		fmt.Println(line)
+	}
+	if v := report.Violations(); len(v) > 0 {
+		fmt.Printf("\n%s %d dependency change(s) would be blocked by the merge queue\n", style.Warning.Render("✗"), len(v))
+		return
+	}
+	fmt.Printf("\n%s %d dependency change(s) within policy\n", style.Success.Render("✓"), len(report.Findings))
+}
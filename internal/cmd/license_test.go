@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/deppolicy"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestCheckDependencyPolicyAt(t *testing.T) {
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, "package.json"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	write(`{"dependencies": {"react": "18.0.0"}}`)
+	git("add", ".")
+	git("commit", "-q", "-m", "base")
+	git("checkout", "-q", "-b", "polecat/Toast")
+	write(`{"dependencies": {"react": "18.0.0", "gpl-thing": "1.0.0", "mit-thing": "2.0.0"}}`)
+	git("commit", "-q", "-am", "add deps")
+	// The base moves on after the branch point; only the branch's changes count.
+	git("checkout", "-q", "main")
+	write(`{"dependencies": {"react": "18.2.0"}}`)
+	git("commit", "-q", "-am", "upgrade react")
+	git("checkout", "-q", "polecat/Toast")
+
+	cfg := &deppolicy.Config{
+		DenyLicenses: []string{"GPL-*"},
+		Licenses:     map[string]string{"gpl-thing": "GPL-3.0", "mit-thing": "MIT"},
+	}
+	report, err := checkDependencyPolicyAt(cfg, repo, "main")
+	if err != nil {
+		t.Fatalf("checkDependencyPolicyAt() error = %v", err)
+	}
+	if len(report.Findings) != 2 {
+		t.Fatalf("findings = %+v, want gpl-thing and mit-thing only", report.Findings)
+	}
+	v := report.Violations()
+	if len(v) != 1 || v[0].Dependency.Name != "gpl-thing" {
+		t.Errorf("Violations() = %+v", v)
+	}
+
+	out := captureStdout(t, func() { printDependencyReport(report) })
+	if !strings.Contains(out, "gpl-thing@1.0.0") || !strings.Contains(out, "would be blocked") {
+		t.Errorf("report output:\n%s", out)
+	}
+}
+
+func TestLoadDependencyPolicyRequiresConfig(t *testing.T) {
+	_, err := loadDependencyPolicy(&rig.Rig{Name: "gastown", Path: t.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), "merge_queue.dependency_policy") {
+		t.Errorf("loadDependencyPolicy() error = %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/i18n"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/templates"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var localeCmd = &cobra.Command{
+	Use:     "locale",
+	GroupID: GroupConfig,
+	Short:   "Show the town's locale and how much of it is translated",
+	Long: `Show which locale gt is using and how much of it is translated.
+
+gt prints operator messages and injects agent briefings in the town's
+locale (gt config set locale ja; GT_LOCALE overrides it per process).
+Translations live in the town under settings/locales/<locale>/:
+
+  messages.json   Short messages: startup beacons, error hints, gt explain
+  roles/          Role briefings rendered by gt prime
+  messages/       Message templates (nudges, escalations, handoffs)
+
+Anything untranslated stays English, so a locale can be translated a piece
+at a time. gt locale export writes the English originals as a starting
+point.
+
+Examples:
+  gt locale
+  gt config set locale ja
+  gt locale export ja`,
+	Args: cobra.NoArgs,
+	RunE: runLocale,
+}
+
+var localeExportCmd = &cobra.Command{
+	Use:   "export [locale]",
+	Short: "Write English originals into a locale directory for translation",
+	Long: `Write gt's translatable text into settings/locales/<locale>/.
+
+messages.json gets every message gt declares: existing translations are
+kept, new messages are added in English, and messages gt no longer uses
+are dropped. Role and message templates are copied only where the locale
+has no copy yet, so translated templates are never overwritten.
+
+Run it again after upgrading gt to pick up new messages. Templates are
+not refreshed: compare a translated template against the embedded one
+(delete it and re-export to see the new English).
+
+With no argument, exports the active locale.
+
+Examples:
+  gt locale export ja`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLocaleExport,
+}
+
+func init() {
+	localeCmd.AddCommand(localeExportCmd)
+	rootCmd.AddCommand(localeCmd)
+}
+
+func runLocale(cmd *cobra.Command, args []string) error {
+	locale := i18n.Locale()
+	fmt.Printf("%s %s\n", style.Bold.Render("Locale:"), locale)
+	if os.Getenv(i18n.EnvLocale) != "" {
+		fmt.Printf("  %s\n", style.Dim.Render("(from "+i18n.EnvLocale+")"))
+	}
+	if locale == i18n.DefaultLocale {
+		return nil
+	}
+
+	dir := i18n.ActiveDir()
+	if dir == "" {
+		fmt.Printf("No translations: run gt locale export %s to start\n", locale)
+		return nil
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render("Translations:"), dir)
+	fmt.Printf("  Messages: %d of %d translated\n", i18n.Translated(), len(i18n.Messages()))
+	for _, sub := range []string{"roles", "messages"} {
+		files, _ := filepath.Glob(filepath.Join(dir, sub, "*.md.tmpl"))
+		fmt.Printf("  Templates in %s/: %d\n", sub, len(files))
+	}
+	return nil
+}
+
+func runLocaleExport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	locale := i18n.Locale()
+	if len(args) == 1 {
+		locale = args[0]
+	}
+	if !i18n.Valid(locale) {
+		return fmt.Errorf("invalid locale %q (use a language tag such as ja or pt-BR)", locale)
+	}
+	if locale == i18n.DefaultLocale {
+		return fmt.Errorf("%s is gt's own language; name the locale to translate into", locale)
+	}
+
+	dir := i18n.Dir(townRoot, locale)
+	untranslated, err := i18n.Export(townRoot, locale)
+	if err != nil {
+		return fmt.Errorf("exporting messages: %w", err)
+	}
+	written, err := templates.ExportTranslatable(dir)
+	if err != nil {
+		return fmt.Errorf("exporting templates: %w", err)
+	}
+
+	fmt.Printf("%s Exported %s to %s\n", style.Success.Render("✓"), locale, dir)
+	fmt.Printf("  %s: %d message(s) to translate\n", i18n.MessagesFile, untranslated)
+	fmt.Printf("  Templates copied: %d\n", len(written))
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sort"
 	"strings"
 	"time"
 
@@ -30,6 +31,7 @@ var (
 
 var logCmd = &cobra.Command{
 	Use:     "log",
+	Aliases: []string{"logs"},
 	GroupID: GroupDiag,
 	Short:   "View town activity log",
 	Long: `View the centralized log of Gas Town agent lifecycle events.
@@ -49,7 +51,8 @@ Examples:
   gt log --type spawn        # Show only spawn events
   gt log --agent greenplace/    # Show events for gastown rig
   gt log --since 1h          # Show events from last hour
-  gt log -f                  # Follow log (like tail -f)`,
+  gt log -f                  # Follow log (like tail -f)
+  gt logs export gastown/Toast -o toast.cast  # Session transcript as asciinema cast`,
 	RunE: runLog,
 }
 
@@ -142,8 +145,26 @@ func runLog(cmd *cobra.Command, args []string) error {
 		filter.Since = time.Now().Add(-duration)
 	}
 
-	// Apply filter
-	events = townlog.FilterEvents(events, filter)
+	// Apply filter. An agent's events include those recorded under names
+	// it had before a rename, up to the rename.
+	matched := townlog.FilterEvents(events, filter)
+	if filter.Agent != "" {
+		agent, slash := strings.CutSuffix(filter.Agent, "/")
+		for _, alias := range formerAddresses(townRoot, agent) {
+			aliasFilter := filter
+			aliasFilter.Agent = alias.Address
+			if slash {
+				aliasFilter.Agent += "/"
+			}
+			for _, e := range townlog.FilterEvents(events, aliasFilter) {
+				if e.Timestamp.Before(alias.Until) {
+					matched = append(matched, e)
+				}
+			}
+		}
+		sort.SliceStable(matched, func(i, j int) bool { return matched[i].Timestamp.Before(matched[j].Timestamp) })
+	}
+	events = matched
 
 	// Apply tail limit
 	if logTail > 0 && len(events) > logTail {
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/asciicast"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// maxCastResultLines caps how much of each tool result is replayed; a full
+// file read or test log would drown the agent's own output.
+const maxCastResultLines = 20
+
+// maxCastToolInput caps the tool call input shown next to the tool name.
+const maxCastToolInput = 160
+
+// ANSI styles for rendered transcripts.
+const (
+	castReset  = "\x1b[0m"
+	castPrompt = "\x1b[1;36m"
+	castTool   = "\x1b[33m"
+	castDim    = "\x1b[2m"
+	castItalic = "\x1b[2;3m"
+)
+
+var (
+	logExportFormat    string
+	logExportOutput    string
+	logExportSession   string
+	logExportList      bool
+	logExportThinking  bool
+	logExportIdleLimit time.Duration
+	logExportWidth     int
+	logExportHeight    int
+)
+
+var logExportCmd = &cobra.Command{
+	Use:   "export <rig>/<polecat>|<rig>/crew/<name>|<dir>",
+	Short: "Export an agent session transcript (asciinema cast)",
+	Long: `Export an agent's session transcript with its timing, for bug reports
+and demos.
+
+The transcript is the one Claude Code records for every session, so it
+survives after the polecat's worktree is gone. By default the newest
+session in the work dir is exported; --list shows them all and --session
+picks one by ID prefix.
+
+Formats:
+  asciinema  asciicast v2, played back with timing and colors
+             (asciinema play session.cast, or upload to asciinema.org)
+  text       the same rendering without timing or colors
+
+Idle gaps longer than --idle-limit are shortened on playback.
+
+Examples:
+  gt logs export gastown/Toast -o toast.cast
+  gt logs export gastown/Toast --list
+  gt logs export gastown/Toast --session 3f2a -o bug-1234.cast
+  gt logs export gastown/crew/max --format text
+  asciinema play toast.cast`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLogExport,
+}
+
+func init() {
+	logExportCmd.Flags().StringVar(&logExportFormat, "format", "asciinema", "Output format: asciinema or text")
+	logExportCmd.Flags().StringVarP(&logExportOutput, "output", "o", "", "Write to file instead of stdout")
+	logExportCmd.Flags().StringVar(&logExportSession, "session", "", "Session ID (or prefix) to export (default: newest)")
+	logExportCmd.Flags().BoolVar(&logExportList, "list", false, "List recorded sessions instead of exporting")
+	logExportCmd.Flags().BoolVar(&logExportThinking, "thinking", false, "Include the agent's thinking blocks")
+	logExportCmd.Flags().DurationVar(&logExportIdleLimit, "idle-limit", 2*time.Second, "Longest pause kept on playback (asciinema)")
+	logExportCmd.Flags().IntVar(&logExportWidth, "width", 120, "Terminal width of the cast")
+	logExportCmd.Flags().IntVar(&logExportHeight, "height", 40, "Terminal height of the cast")
+	logCmd.AddCommand(logExportCmd)
+}
+
+func runLogExport(cmd *cobra.Command, args []string) error {
+	if logExportFormat != "asciinema" && logExportFormat != "text" {
+		return fmt.Errorf("unknown format %q (use asciinema or text)", logExportFormat)
+	}
+	transcripts, err := logExportTranscripts(args[0])
+	if err != nil {
+		return err
+	}
+	if len(transcripts) == 0 {
+		return fmt.Errorf("no session transcripts recorded for %s", args[0])
+	}
+
+	if logExportList {
+		for _, t := range transcripts {
+			fmt.Printf("%s  %s  %s\n", t.NativeSessionID, t.ModTime.Format("2006-01-02 15:04"), style.Dim.Render(formatBytes(t.Size)))
+		}
+		return nil
+	}
+
+	t, err := pickTranscript(transcripts, logExportSession)
+	if err != nil {
+		return err
+	}
+	events, err := agentlog.ReadClaudeCodeTranscript(t.Path, args[0])
+	if err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if logExportOutput != "" {
+		f, err := os.Create(logExportOutput)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if logExportFormat == "text" {
+		for _, ev := range events {
+			if _, err := io.WriteString(out, stripCastStyles(renderCastEvent(ev, logExportThinking))); err != nil {
+				return err
+			}
+		}
+	} else {
+		title := fmt.Sprintf("%s (session %s)", args[0], t.NativeSessionID)
+		if err := writeSessionCast(out, events, title, logExportThinking); err != nil {
+			return err
+		}
+	}
+	if logExportOutput != "" {
+		fmt.Fprintf(os.Stderr, "%s Exported session %s of %s to %s\n", style.Success.Render("✓"), t.NativeSessionID, args[0], logExportOutput)
+	}
+	return nil
+}
+
+// logExportTranscripts returns the transcripts of an export target, newest
+// first. An agent's transcripts include those from sessions under a name it
+// had before a rename, up to the rename.
+func logExportTranscripts(target string) ([]agentlog.Transcript, error) {
+	if info, err := os.Stat(target); err == nil && info.IsDir() {
+		abs, err := filepath.Abs(target)
+		if err != nil {
+			return nil, err
+		}
+		return agentlog.ClaudeCodeTranscripts(abs)
+	}
+	rigName, name, err := parseAddress(target)
+	if err != nil {
+		return nil, err
+	}
+	townRoot, _, err := getRig(rigName)
+	if err != nil {
+		return nil, err
+	}
+	addr := agentWorkAddress(rigName, name)
+	transcripts, err := agentlog.ClaudeCodeTranscripts(agentWorkDirs(townRoot, addr)...)
+	if err != nil {
+		return nil, err
+	}
+	for _, alias := range formerAddresses(townRoot, addr) {
+		former, err := agentlog.ClaudeCodeTranscripts(agentWorkDirs(townRoot, alias.Address)...)
+		if err != nil {
+			continue
+		}
+		for _, t := range former {
+			if t.ModTime.Before(alias.Until) {
+				transcripts = append(transcripts, t)
+			}
+		}
+	}
+	sort.SliceStable(transcripts, func(i, j int) bool { return transcripts[i].ModTime.After(transcripts[j].ModTime) })
+	return transcripts, nil
+}
+
+// agentWorkAddress returns the full address (rig/crew/name or
+// rig/polecats/name) of a crew member or polecat named in a rig.
+func agentWorkAddress(rigName, name string) string {
+	if crewName, ok := strings.CutPrefix(name, "crew/"); ok {
+		return rigName + "/crew/" + crewName
+	}
+	return rigName + "/polecats/" + strings.TrimPrefix(name, "polecats/")
+}
+
+// agentWorkDirs returns the work dirs an agent's sessions ran in.
+// Polecats have used both polecats/<name>/<rig> and the older
+// polecats/<name>, so both are searched.
+func agentWorkDirs(townRoot, addr string) []string {
+	parts := strings.SplitN(addr, "/", 3)
+	if len(parts) != 3 {
+		return nil
+	}
+	rigPath := filepath.Join(townRoot, parts[0])
+	if parts[1] == "crew" {
+		return []string{filepath.Join(rigPath, "crew", parts[2])}
+	}
+	return []string{
+		filepath.Join(rigPath, "polecats", parts[2], parts[0]),
+		filepath.Join(rigPath, "polecats", parts[2]),
+	}
+}
+
+// pickTranscript returns the newest transcript, or the one whose session ID
+// starts with prefix.
+func pickTranscript(transcripts []agentlog.Transcript, prefix string) (*agentlog.Transcript, error) {
+	if prefix == "" {
+		return &transcripts[0], nil
+	}
+	var match *agentlog.Transcript
+	for i, t := range transcripts {
+		if !strings.HasPrefix(t.NativeSessionID, prefix) {
+			continue
+		}
+		if match != nil {
+			return nil, fmt.Errorf("session prefix %q is ambiguous (%s, %s)", prefix, match.NativeSessionID, t.NativeSessionID)
+		}
+		match = &transcripts[i]
+	}
+	if match == nil {
+		return nil, fmt.Errorf("no session matching %q (see --list)", prefix)
+	}
+	return match, nil
+}
+
+// writeSessionCast writes events as an asciicast, each at its offset from
+// the first event.
+func writeSessionCast(w io.Writer, events []agentlog.AgentEvent, title string, thinking bool) error {
+	h := asciicast.Header{
+		Width:         logExportWidth,
+		Height:        logExportHeight,
+		IdleTimeLimit: logExportIdleLimit.Seconds(),
+		Title:         title,
+	}
+	var start time.Time
+	if len(events) > 0 {
+		start = events[0].Timestamp
+		h.Timestamp = start.Unix()
+	}
+	cast, err := asciicast.NewWriter(w, h)
+	if err != nil {
+		return err
+	}
+	for _, ev := range events {
+		if err := cast.Output(ev.Timestamp.Sub(start), renderCastEvent(ev, thinking)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renderCastEvent renders a transcript event the way it reads in a
+// terminal: prompts marked, tool calls highlighted, tool output dimmed and
+// cut short. Usage events and (unless thinking is set) thinking render as
+// nothing.
+func renderCastEvent(ev agentlog.AgentEvent, thinking bool) string {
+	content := strings.TrimRight(ev.Content, "\n")
+	switch ev.EventType {
+	case "text":
+		if ev.Role == "user" {
+			return castPrompt + "❯ " + castReset + content + "\n\n"
+		}
+		return content + "\n\n"
+	case "tool_use":
+		name, input, _ := strings.Cut(content, ": ")
+		if r := []rune(input); len(r) > maxCastToolInput {
+			input = string(r[:maxCastToolInput]) + "…"
+		}
+		return castTool + "● " + name + castReset + castDim + " " + input + castReset + "\n"
+	case "tool_result":
+		return castDim + indentCast(truncateCastLines(content, maxCastResultLines)) + castReset + "\n\n"
+	case "thinking":
+		if thinking {
+			return castItalic + content + castReset + "\n\n"
+		}
+	}
+	return ""
+}
+
+func truncateCastLines(s string, limit int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) <= limit {
+		return s
+	}
+	return strings.Join(lines[:limit], "\n") + fmt.Sprintf("\n… (+%d lines)", len(lines)-limit)
+}
+
+func indentCast(s string) string {
+	return "  ⎿ " + strings.ReplaceAll(s, "\n", "\n    ")
+}
+
+func stripCastStyles(s string) string {
+	for _, code := range []string{castReset, castPrompt, castTool, castDim, castItalic} {
+		s = strings.ReplaceAll(s, code, "")
+	}
+	return s
+}
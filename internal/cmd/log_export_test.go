@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+)
+
+func TestRenderCastEvent(t *testing.T) {
+	tests := []struct {
+		ev       agentlog.AgentEvent
+		thinking bool
+		want     string
+	}{
+		{agentlog.AgentEvent{EventType: "text", Role: "user", Content: "Fix it"}, false, "❯ Fix it\n\n"},
+		{agentlog.AgentEvent{EventType: "text", Role: "assistant", Content: "Done.\n"}, false, "Done.\n\n"},
+		{agentlog.AgentEvent{EventType: "tool_use", Content: `Bash: {"command":"go test"}`}, false, "● Bash {\"command\":\"go test\"}\n"},
+		{agentlog.AgentEvent{EventType: "tool_result", Content: "ok\nPASS"}, false, "  ⎿ ok\n    PASS\n\n"},
+		{agentlog.AgentEvent{EventType: "thinking", Content: "hmm"}, false, ""},
+		{agentlog.AgentEvent{EventType: "thinking", Content: "hmm"}, true, "hmm\n\n"},
+		{agentlog.AgentEvent{EventType: "usage", OutputTokens: 10}, false, ""},
+	}
+	for _, tt := range tests {
+		if got := stripCastStyles(renderCastEvent(tt.ev, tt.thinking)); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.ev.EventType, got, tt.want)
+		}
+	}
+
+	long := strings.Repeat("line\n", maxCastResultLines+5)
+	if got := renderCastEvent(agentlog.AgentEvent{EventType: "tool_result", Content: long}, false); !strings.Contains(got, "(+5 lines)") {
+		t.Errorf("long tool result not truncated: %q", got)
+	}
+}
+
+func TestPickTranscript(t *testing.T) {
+	transcripts := []agentlog.Transcript{{NativeSessionID: "3f2a-new"}, {NativeSessionID: "3f19-old"}, {NativeSessionID: "a001"}}
+
+	if got, _ := pickTranscript(transcripts, ""); got.NativeSessionID != "3f2a-new" {
+		t.Errorf("default = %s, want newest", got.NativeSessionID)
+	}
+	if got, err := pickTranscript(transcripts, "a0"); err != nil || got.NativeSessionID != "a001" {
+		t.Errorf("prefix a0 = %v, %v", got, err)
+	}
+	if _, err := pickTranscript(transcripts, "3f"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("prefix 3f: err = %v, want ambiguous", err)
+	}
+	if _, err := pickTranscript(transcripts, "zz"); err == nil {
+		t.Error("unknown prefix should fail")
+	}
+}
+
+func TestWriteSessionCast(t *testing.T) {
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	events := []agentlog.AgentEvent{
+		{EventType: "text", Role: "user", Content: "Fix it", Timestamp: start},
+		{EventType: "text", Role: "assistant", Content: "Done", Timestamp: start.Add(4 * time.Second)},
+	}
+	var buf bytes.Buffer
+	if err := writeSessionCast(&buf, events, "gastown/Toast", false); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("cast = %s", buf.String())
+	}
+	if !strings.Contains(lines[0], `"title":"gastown/Toast"`) || !strings.Contains(lines[0], `"timestamp":1767348000`) {
+		t.Errorf("header = %s", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "[0,") || !strings.HasPrefix(lines[2], `[4,"o","Done\r\n\r\n"]`) {
+		t.Errorf("events = %s / %s", lines[1], lines[2])
+	}
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 )
 
@@ -20,11 +22,15 @@ var (
 	mailTo            string   // --to flag (alternative to positional arg)
 	mailSendSelf      bool
 	mailCC            []string // CC recipients
+	mailFields        []string // --field key=value for typed messages
+	mailRequireAck    bool
+	mailAckTimeout    time.Duration
 	mailInboxJSON     bool
 	mailReadJSON      bool
 	mailInboxUnread   bool
 	mailInboxAll      bool
 	mailInboxIdentity string
+	mailInboxFolder   string
 	mailCheckInject   bool
 	mailCheckJSON     bool
 	mailCheckIdentity string
@@ -111,11 +117,20 @@ sending to multiple recipients at once. Each recipient gets their
 own copy of the message.
 
 Message types:
-  task          - Required processing
+  task          - Required processing          (fields: bead, due)
+  question      - Needs an answer              (fields: bead, options)
+  report        - Progress or outcome          (fields: status*, bead)
+  approval      - Needs approve/reject         (fields: action*, bead)
   scavenge      - Optional first-come work
   notification  - Informational (default)
   reply         - Response to message
 
+Typed messages carry structured fields (* = required), set with --field
+and written as "key: value" lines at the top of the body. Report status
+is one of progress, done, blocked, failed. Questions and approvals show
+up in 'gt mail needs-human'; answer approvals with 'gt mail approve' or
+'gt mail reject'.
+
 Priority levels:
   0 - urgent/critical
   1 - high
@@ -125,6 +140,10 @@ Priority levels:
 
 Use --urgent as shortcut for --priority 0.
 
+Use --require-ack when the recipient must read the message: if it is still
+unread after --timeout, the Deacon escalates it (see 'gt mail receipts').
+Ack-required messages are always permanent.
+
 Examples:
   gt mail send greenplace/Toast -s "Status check" -m "How's that bug fix going?"
   gt mail send mayor/ -s "Work complete" -m "Finished gt-abc"
@@ -135,13 +154,16 @@ Examples:
   gt mail send --self -s "Handoff" -m "Context for next session"
   gt mail send greenplace/Toast -s "Update" -m "Progress report" --cc overseer
   gt mail send list:oncall -s "Alert" -m "System down"
+  gt mail send greenplace/Toast -s "Rebase now" -m "..." --require-ack --timeout 30m
+  gt mail send --human -s "Deploy?" --type approval --field action="deploy gt-abc"
+  gt mail send mayor/ -s "gt-abc blocked" --type report --field status=blocked --field bead=gt-abc
 
   # Read body from stdin (avoids shell quoting issues):
   gt mail send mayor/ -s "Update" --stdin <<'BODY'
   Message with 'quotes' and "quotes" and $variables.
   BODY`,
 	Args: cobra.MaximumNArgs(1),
-	RunE: runMailSend,
+	RunE: journaled(runMailSend),
 }
 
 var mailInboxCmd = &cobra.Command{
@@ -155,11 +177,15 @@ Use --identity for polecats to explicitly specify their identity.
 By default, shows all messages. Use --unread to filter to unread only,
 or --all to explicitly show all messages (read and unread).
 
+Messages filed into a folder by a mail rule (see 'gt mail rules') are
+hidden; use --folder to show a folder.
+
 Examples:
   gt mail inbox                       # Current context (auto-detected)
   gt mail inbox --all                 # Explicitly show all messages
   gt mail inbox --unread              # Show only unread messages
   gt mail inbox mayor/                # Mayor's inbox
+  gt mail inbox --folder merges       # Messages filed into "merges"
   gt mail inbox greenplace/Toast         # Polecat's inbox
   gt mail inbox --identity greenplace/Toast  # Explicit polecat identity`,
 	Args: cobra.MaximumNArgs(1),
@@ -461,7 +487,8 @@ func init() {
 	mailSendCmd.Flags().BoolVar(&mailStdin, "stdin", false, "Read message body from stdin (avoids shell quoting issues)")
 	mailSendCmd.Flags().IntVar(&mailPriority, "priority", 2, "Message priority (0=urgent, 1=high, 2=normal, 3=low, 4=backlog)")
 	mailSendCmd.Flags().BoolVar(&mailUrgent, "urgent", false, "Set priority=0 (urgent)")
-	mailSendCmd.Flags().StringVar(&mailType, "type", "notification", "Message type (task, scavenge, notification, reply)")
+	mailSendCmd.Flags().StringVar(&mailType, "type", "notification", "Message type (task, question, report, approval, scavenge, notification, reply)")
+	mailSendCmd.Flags().StringArrayVar(&mailFields, "field", nil, "Structured field for typed messages, key=value (can be used multiple times)")
 	mailSendCmd.Flags().StringVar(&mailReplyTo, "reply-to", "", "Message ID this is replying to")
 	mailSendCmd.Flags().BoolVarP(&mailNotify, "notify", "n", false, "Bump priority to high (notification is automatic; use --no-notify to suppress)")
 	mailSendCmd.Flags().BoolVar(&mailNoNotify, "no-notify", false, "Suppress auto-nudge notification to recipient")
@@ -472,6 +499,8 @@ func init() {
 	mailSendCmd.Flags().StringVar(&mailTo, "to", "", "Recipient address (alternative to positional argument)")
 	mailSendCmd.Flags().BoolVar(&mailSendSelf, "self", false, "Send to self (auto-detect from cwd)")
 	mailSendCmd.Flags().StringArrayVar(&mailCC, "cc", nil, "CC recipients (can be used multiple times)")
+	mailSendCmd.Flags().BoolVar(&mailRequireAck, "require-ack", false, "Escalate if the recipient hasn't read the message by --timeout")
+	mailSendCmd.Flags().DurationVar(&mailAckTimeout, "timeout", 30*time.Minute, "Ack deadline for --require-ack")
 	_ = mailSendCmd.MarkFlagRequired("subject") // cobra flags: error only at runtime if missing
 
 	// Inbox flags
@@ -480,6 +509,7 @@ func init() {
 	mailInboxCmd.Flags().BoolVarP(&mailInboxAll, "all", "a", false, "Show all messages (read and unread)")
 	mailInboxCmd.Flags().StringVar(&mailInboxIdentity, "identity", "", "Explicit identity for inbox (e.g., greenplace/Toast)")
 	mailInboxCmd.Flags().StringVar(&mailInboxIdentity, "address", "", "Alias for --identity")
+	mailInboxCmd.Flags().StringVar(&mailInboxFolder, "folder", "", "Show messages filed into this folder by mail rules")
 
 	// Read flags
 	mailReadCmd.Flags().BoolVar(&mailReadJSON, "json", false, "Output as JSON")
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	mailDecisionMessage string
+	mailNeedsHumanID    string
+	mailNeedsHumanJSON  bool
+)
+
+var mailApproveCmd = &cobra.Command{
+	Use:   "approve <message-id>",
+	Short: "Approve an approval request",
+	Long: `Approve an approval request (a message sent with --type approval).
+
+Replies to the requester with "decision: approved" and marks the request
+read. The requester sees the decision as a structured reply.
+
+Examples:
+  gt mail approve hq-abc
+  gt mail approve hq-abc -m "Go ahead, but skip the migration"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMailDecision(args[0], mail.DecisionApproved)
+	},
+}
+
+var mailRejectCmd = &cobra.Command{
+	Use:   "reject <message-id>",
+	Short: "Reject an approval request",
+	Long: `Reject an approval request (a message sent with --type approval).
+
+Replies to the requester with "decision: rejected" and marks the request
+read. A reason is required so the requester knows what to change.
+
+Examples:
+  gt mail reject hq-abc -m "Wait until the release branch is cut"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if mailDecisionMessage == "" {
+			return fmt.Errorf("a reason is required: use -m")
+		}
+		return runMailDecision(args[0], mail.DecisionRejected)
+	},
+}
+
+var mailNeedsHumanCmd = &cobra.Command{
+	Use:   "needs-human",
+	Short: "List unanswered questions and approval requests",
+	Long: `List unread question and approval messages — mail waiting on a decision.
+
+Defaults to the overseer's inbox, where agents send questions for the
+human. Use --identity to check another mailbox.
+
+Examples:
+  gt mail needs-human
+  gt mail needs-human --identity mayor/
+  gt mail needs-human --json`,
+	Args: cobra.NoArgs,
+	RunE: runMailNeedsHuman,
+}
+
+func init() {
+	mailApproveCmd.Flags().StringVarP(&mailDecisionMessage, "message", "m", "", "Note for the requester")
+	mailRejectCmd.Flags().StringVarP(&mailDecisionMessage, "message", "m", "", "Reason for rejecting (required)")
+	mailNeedsHumanCmd.Flags().StringVar(&mailNeedsHumanID, "identity", "overseer", "Mailbox to check")
+	mailNeedsHumanCmd.Flags().BoolVar(&mailNeedsHumanJSON, "json", false, "Output as JSON")
+
+	mailCmd.AddCommand(mailApproveCmd)
+	mailCmd.AddCommand(mailRejectCmd)
+	mailCmd.AddCommand(mailNeedsHumanCmd)
+}
+
+func runMailDecision(msgID, decision string) error {
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	from := detectSender()
+	router := mail.NewRouter(workDir)
+	mailbox, err := router.GetMailbox(from)
+	if err != nil {
+		return fmt.Errorf("getting mailbox: %w", err)
+	}
+	original, err := mailbox.Get(msgID)
+	if err != nil {
+		return fmt.Errorf("getting message: %w", err)
+	}
+	if original.Type != mail.TypeApproval {
+		return fmt.Errorf("%s is a %s message, not an approval request", msgID, original.Type)
+	}
+
+	reply := newMailDecisionReply(from, original, decision, mailDecisionMessage)
+	defer router.WaitPendingNotifications()
+	if err := router.Send(reply); err != nil {
+		return fmt.Errorf("sending decision: %w", err)
+	}
+	if err := mailbox.MarkReadOnly(msgID); err != nil {
+		style.PrintWarning("decision sent but could not mark %s read: %v", msgID, err)
+	}
+
+	fmt.Printf("%s %s %s (%s)\n", style.Bold.Render("✓"), decision, original.Fields[mail.FieldAction], msgID)
+	fmt.Printf("  Reply sent to %s\n", original.From)
+	return nil
+}
+
+// newMailDecisionReply builds the structured reply to an approval request.
+func newMailDecisionReply(from string, original *mail.Message, decision, note string) *mail.Message {
+	fields := map[string]string{
+		mail.FieldDecision: decision,
+		mail.FieldApproval: original.ID,
+	}
+	reply := mail.NewReplyMessage(from, original.From,
+		fmt.Sprintf("%s: %s", decisionTitle(decision), original.Subject),
+		mail.FormatMessageBody(fields, note), original)
+	reply.Fields = fields
+	if reply.ThreadID == "" {
+		reply.ThreadID = generateThreadID()
+	}
+	return reply
+}
+
+func decisionTitle(decision string) string {
+	if decision == mail.DecisionApproved {
+		return "Approved"
+	}
+	return "Rejected"
+}
+
+func runMailNeedsHuman(cmd *cobra.Command, args []string) error {
+	mailbox, err := getMailbox(mailNeedsHumanID)
+	if err != nil {
+		return err
+	}
+	unread, err := mailbox.ListUnread()
+	if err != nil {
+		return fmt.Errorf("listing messages: %w", err)
+	}
+	pending := filterNeedsHuman(unread)
+
+	if mailNeedsHumanJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(pending)
+	}
+	printNeedsHuman(os.Stdout, mailNeedsHumanID, pending)
+	return nil
+}
+
+// filterNeedsHuman keeps the messages that wait on a decision or answer.
+func filterNeedsHuman(messages []*mail.Message) []*mail.Message {
+	pending := make([]*mail.Message, 0)
+	for _, msg := range messages {
+		if mail.NeedsHuman(msg.Type) {
+			pending = append(pending, msg)
+		}
+	}
+	return pending
+}
+
+func printNeedsHuman(w io.Writer, address string, pending []*mail.Message) {
+	_, _ = fmt.Fprintf(w, "%s Needs human: %s (%d)\n\n", style.Bold.Render("🙋"), address, len(pending))
+	if len(pending) == 0 {
+		_, _ = fmt.Fprintf(w, "  %s\n", style.Dim.Render("(nothing waiting)"))
+		return
+	}
+	for _, msg := range pending {
+		_, _ = fmt.Fprintf(w, "  [%s] %s\n", msg.Type, msg.Subject)
+		_, _ = fmt.Fprintf(w, "      %s from %s\n", style.Dim.Render(msg.ID), msg.From)
+		switch msg.Type {
+		case mail.TypeApproval:
+			_, _ = fmt.Fprintf(w, "      action: %s\n", msg.Fields[mail.FieldAction])
+			_, _ = fmt.Fprintf(w, "      %s\n", style.Dim.Render(fmt.Sprintf("gt mail approve %s | gt mail reject %s -m <reason>", msg.ID, msg.ID)))
+		case mail.TypeQuestion:
+			if options := msg.Fields[mail.FieldOptions]; options != "" {
+				_, _ = fmt.Fprintf(w, "      options: %s\n", options)
+			}
+			_, _ = fmt.Fprintf(w, "      %s\n", style.Dim.Render(fmt.Sprintf("gt mail reply %s -m <answer>", msg.ID)))
+		}
+	}
+}
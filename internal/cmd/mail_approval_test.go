@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func TestNewMailDecisionReply(t *testing.T) {
+	original := &mail.Message{
+		ID:       "hq-1",
+		From:     "gastown/Toast",
+		Subject:  "Deploy?",
+		Type:     mail.TypeApproval,
+		ThreadID: "thread-abc",
+		Fields:   map[string]string{mail.FieldAction: "deploy"},
+	}
+	reply := newMailDecisionReply("overseer", original, mail.DecisionRejected, "Not today")
+
+	if reply.To != "gastown/Toast" || reply.ReplyTo != "hq-1" || reply.ThreadID != "thread-abc" {
+		t.Errorf("reply routing = to %q reply-to %q thread %q", reply.To, reply.ReplyTo, reply.ThreadID)
+	}
+	if reply.Subject != "Rejected: Deploy?" {
+		t.Errorf("Subject = %q", reply.Subject)
+	}
+	fields := mail.ParseMessageFields(reply.Type, reply.Body)
+	if fields[mail.FieldDecision] != mail.DecisionRejected || fields[mail.FieldApproval] != "hq-1" {
+		t.Errorf("reply fields = %v", fields)
+	}
+	if !strings.HasSuffix(reply.Body, "\n\nNot today") {
+		t.Errorf("Body = %q, want note after fields", reply.Body)
+	}
+}
+
+func TestFilterNeedsHuman(t *testing.T) {
+	messages := []*mail.Message{
+		{ID: "a", Type: mail.TypeQuestion, Subject: "Which DB?", Fields: map[string]string{mail.FieldOptions: "dolt | sqlite"}},
+		{ID: "b", Type: mail.TypeReport},
+		{ID: "c", Type: mail.TypeApproval, Fields: map[string]string{mail.FieldAction: "merge"}},
+	}
+	pending := filterNeedsHuman(messages)
+	if len(pending) != 2 || pending[0].ID != "a" || pending[1].ID != "c" {
+		t.Fatalf("filterNeedsHuman() = %v", pending)
+	}
+
+	var buf bytes.Buffer
+	printNeedsHuman(&buf, "overseer", pending)
+	for _, want := range []string{"options: dolt | sqlite", "gt mail approve c", "action: merge"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestParseMailFields(t *testing.T) {
+	fields, err := parseMailFields(mail.TypeReport, []string{"Status=blocked", "bead = gt-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["status"] != "blocked" || fields["bead"] != "gt-1" {
+		t.Errorf("fields = %v", fields)
+	}
+	if _, err := parseMailFields(mail.TypeReport, []string{"status"}); err == nil {
+		t.Error("expected error for missing =")
+	}
+	if _, err := parseMailFields(mail.TypeReport, []string{"owner=me"}); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
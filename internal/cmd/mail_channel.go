@@ -506,7 +506,7 @@ func listChannelMessages(townRoot, channelName string) ([]channelMessage, error)
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" {
 			return nil, fmt.Errorf("%s", errMsg)
@@ -104,6 +104,9 @@ func detectSenderFromRole(role string) string {
 	// GT_ROLE is a simple role name, build the full address
 	switch role {
 	case constants.RoleMayor:
+		if shard := os.Getenv("GT_MAYOR_SHARD"); shard != "" {
+			return "mayor/" + shard
+		}
 		return "mayor/"
 	case constants.RoleDeacon:
 		return "deacon/"
@@ -205,6 +208,14 @@ func detectSenderFromCwd() string {
 		}
 	}
 
+	// If in a shard mayor's directory, extract address (format: mayor/shard)
+	if strings.Contains(cwd, "/mayor/shards/") {
+		parts := strings.Split(cwd, "/mayor/shards/")
+		if len(parts) >= 2 {
+			return "mayor/" + strings.Split(parts[1], "/")[0]
+		}
+	}
+
 	// If in the town's mayor directory
 	if strings.Contains(cwd, "/mayor") {
 		return "mayor"
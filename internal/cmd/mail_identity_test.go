@@ -73,3 +73,34 @@ func TestDetectSenderFromCwdUsesAgentFileRefineryIdentity(t *testing.T) {
 		t.Fatalf("detectSender() = %q, want %q", got, "x267/refinery")
 	}
 }
+
+func TestDetectSenderShardMayor(t *testing.T) {
+	t.Setenv("GT_ROLE", "mayor")
+	t.Setenv("GT_MAYOR_SHARD", "west")
+	if got := detectSender(); got != "mayor/west" {
+		t.Errorf("detectSender() with GT_MAYOR_SHARD = %q, want %q", got, "mayor/west")
+	}
+
+	t.Setenv("GT_MAYOR_SHARD", "")
+	if got := detectSender(); got != "mayor/" {
+		t.Errorf("detectSender() without GT_MAYOR_SHARD = %q, want %q", got, "mayor/")
+	}
+
+	t.Setenv("GT_ROLE", "")
+	tmp := t.TempDir()
+	shardDir := filepath.Join(tmp, "mayor", "shards", "west")
+	if err := os.MkdirAll(shardDir, 0o755); err != nil {
+		t.Fatalf("mkdir shard dir: %v", err)
+	}
+	oldWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	defer func() { _ = os.Chdir(oldWd) }()
+	if err := os.Chdir(shardDir); err != nil {
+		t.Fatalf("chdir shard dir: %v", err)
+	}
+	if got := detectSender(); got != "mayor/west" {
+		t.Errorf("detectSender() from shard dir = %q, want %q", got, "mayor/west")
+	}
+}
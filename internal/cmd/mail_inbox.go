@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -65,9 +66,7 @@ func runMailInbox(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("listing messages: %w", err)
 	}
-	if messages == nil {
-		messages = make([]*mail.Message, 0)
-	}
+	messages, filed := filterMailFolder(messages, mailInboxFolder)
 
 	// JSON output
 	if mailInboxJSON {
@@ -88,11 +87,17 @@ func runMailInbox(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		style.PrintWarning("could not count messages: %v", err)
 	}
-	fmt.Printf("%s Inbox: %s (%d messages, %d unread)\n\n",
-		style.Bold.Render("📬"), address, total, unread)
+	if mailInboxFolder != "" {
+		fmt.Printf("%s Folder: %s/%s (%d messages)\n\n",
+			style.Bold.Render("📁"), address, mailInboxFolder, len(messages))
+	} else {
+		fmt.Printf("%s Inbox: %s (%d messages, %d unread)\n\n",
+			style.Bold.Render("📬"), address, total, unread)
+	}
 
 	if len(messages) == 0 {
 		fmt.Printf("  %s\n", style.Dim.Render("(no messages)"))
+		printFiledMailHint(filed)
 		return nil
 	}
 
@@ -123,6 +128,7 @@ func runMailInbox(cmd *cobra.Command, args []string) error {
 		fmt.Printf("      %s\n",
 			style.Dim.Render(msg.Timestamp.Local().Format("2006-01-02 15:04")))
 	}
+	printFiledMailHint(filed)
 
 	// Ack after output so human-readable display is not delayed by bd subprocesses.
 	if ackErr := mailbox.AcknowledgeDeliveries(address, messages); ackErr != nil {
@@ -132,6 +138,34 @@ func runMailInbox(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// filterMailFolder keeps the messages in folder ("" = the unfiled inbox)
+// and counts, per folder, the messages it hid.
+func filterMailFolder(messages []*mail.Message, folder string) ([]*mail.Message, map[string]int) {
+	kept := make([]*mail.Message, 0, len(messages))
+	hidden := make(map[string]int)
+	for _, msg := range messages {
+		if msg.Folder == folder {
+			kept = append(kept, msg)
+		} else if folder == "" {
+			hidden[msg.Folder]++
+		}
+	}
+	return kept, hidden
+}
+
+// printFiledMailHint notes the messages mail rules filed out of the inbox.
+func printFiledMailHint(filed map[string]int) {
+	if len(filed) == 0 {
+		return
+	}
+	folders := make([]string, 0, len(filed))
+	for name, n := range filed {
+		folders = append(folders, fmt.Sprintf("%s (%d)", name, n))
+	}
+	sort.Strings(folders)
+	fmt.Printf("\n  %s\n", style.Dim.Render("Filed by mail rules: "+strings.Join(folders, ", ")+" — gt mail inbox --folder <name>"))
+}
+
 func runMailRead(cmd *cobra.Command, args []string) error {
 	if len(args) == 0 {
 		return fmt.Errorf("message ID or index required\n\nRun 'gt mail inbox' to list messages and their IDs")
@@ -154,6 +188,8 @@ func runMailRead(cmd *cobra.Command, args []string) error {
 		if err != nil {
 			return fmt.Errorf("listing messages: %w", err)
 		}
+		// Indexes follow the inbox view, which hides filed messages.
+		messages, _ = filterMailFolder(messages, "")
 		if idx > len(messages) {
 			return fmt.Errorf("index %d out of range (inbox has %d messages)", idx, len(messages))
 		}
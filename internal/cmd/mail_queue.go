@@ -189,7 +189,7 @@ func listUnclaimedQueueMessages(beadsDir, queueName string) ([]queueMessage, err
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" {
 			return nil, fmt.Errorf("%s", errMsg)
@@ -272,7 +272,7 @@ func claimQueueMessage(beadsDir, messageID, claimant string) error {
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" {
 			return fmt.Errorf("%s", errMsg)
@@ -350,7 +350,7 @@ func getQueueMessageInfo(beadsDir, messageID string) (*queueMessageInfo, error)
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if strings.Contains(errMsg, "not found") {
 			return nil, fmt.Errorf("message not found: %s", messageID)
@@ -434,7 +434,7 @@ func releaseQueueMessage(beadsDir, messageID, actor string) error {
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" && !strings.Contains(errMsg, "does not have label") {
 			return fmt.Errorf("%s", errMsg)
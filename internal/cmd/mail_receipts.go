@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	mailReceiptsAll      bool
+	mailReceiptsOpen     bool
+	mailReceiptsEscalate bool
+	mailReceiptsJSON     bool
+)
+
+var mailReceiptsCmd = &cobra.Command{
+	Use:   "receipts",
+	Short: "Show delivery and read status of ack-required mail",
+	Long: `Show delivery and read status of messages sent with --require-ack.
+
+Each message is in one of these states:
+  pending    Written, but the recipient hasn't listed their inbox yet
+  delivered  Shown in the recipient's inbox, not yet read
+  read       Read by the recipient (acknowledged)
+  overdue    Still unread after its --timeout deadline
+
+With --escalate, each overdue message is escalated once (gt escalate) so a
+busy or stuck recipient doesn't silently swallow it. The Deacon runs
+'gt mail receipts --all --escalate' every patrol.
+
+Examples:
+  gt mail receipts                     # Your ack-required mail
+  gt mail receipts --open              # Only messages not yet read
+  gt mail receipts --all --escalate    # Escalate overdue mail (Deacon)`,
+	Args: cobra.NoArgs,
+	RunE: runMailReceipts,
+}
+
+// mailReceiptEscalateFn is a seam for tests. Production files a medium
+// escalation through gt escalate.
+var mailReceiptEscalateFn = func(townRoot string, msg *mail.Message) error {
+	reason := fmt.Sprintf("%s sent %q to %s with --require-ack; still unread after its deadline (%s).",
+		msg.From, msg.Subject, msg.To, msg.AckDeadline.Local().Format("2006-01-02 15:04"))
+	escCmd := exec.Command("gt", "escalate", "-s", "medium",
+		"--source", "mail:"+msg.ID, "--reason", reason,
+		fmt.Sprintf("Unacknowledged mail to %s: %s", msg.To, msg.Subject))
+	escCmd.Dir = townRoot
+	if out, err := escCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func init() {
+	mailReceiptsCmd.Flags().BoolVar(&mailReceiptsAll, "all", false, "Ack-required mail from every sender")
+	mailReceiptsCmd.Flags().BoolVar(&mailReceiptsOpen, "open", false, "Only messages not yet read")
+	mailReceiptsCmd.Flags().BoolVar(&mailReceiptsEscalate, "escalate", false, "Escalate overdue messages (once each)")
+	mailReceiptsCmd.Flags().BoolVar(&mailReceiptsJSON, "json", false, "Output as JSON")
+
+	mailCmd.AddCommand(mailReceiptsCmd)
+}
+
+// mailReceipt is the JSON form of one ack-required message.
+type mailReceipt struct {
+	ID        string     `json:"id"`
+	From      string     `json:"from"`
+	To        string     `json:"to"`
+	Subject   string     `json:"subject"`
+	SentAt    time.Time  `json:"sent_at"`
+	Deadline  *time.Time `json:"deadline,omitempty"`
+	State     string     `json:"state"`
+	Escalated bool       `json:"escalated,omitempty"`
+}
+
+func runMailReceipts(cmd *cobra.Command, args []string) error {
+	townRoot, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	router := mail.NewRouter(townRoot)
+
+	from := ""
+	if !mailReceiptsAll {
+		from = mail.AddressToIdentity(detectSender())
+	}
+	// Escalation only concerns unread mail, so skip the closed beads.
+	messages, err := router.ListAckRequired(from, !mailReceiptsOpen && !mailReceiptsEscalate)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var failed []string
+	if mailReceiptsEscalate {
+		for _, msg := range messages {
+			if msg.AckEscalated || mail.ReceiptState(msg, now) != mail.ReceiptOverdue {
+				continue
+			}
+			if err := mailReceiptEscalateFn(townRoot, msg); err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", msg.ID, err))
+				continue
+			}
+			if err := router.MarkAckEscalated(msg.ID); err != nil {
+				style.PrintWarning("escalated %s but could not label it: %v", msg.ID, err)
+			}
+			msg.AckEscalated = true
+			if !mailReceiptsJSON {
+				fmt.Printf("%s Escalated unacknowledged %s to %s: %s\n", style.Warning.Render("⚠"), msg.ID, msg.To, msg.Subject)
+			}
+		}
+	}
+
+	receipts := buildMailReceipts(messages, now, mailReceiptsOpen || mailReceiptsEscalate)
+	if mailReceiptsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(receipts); err != nil {
+			return err
+		}
+	} else if !mailReceiptsEscalate || len(receipts) > 0 {
+		printMailReceipts(os.Stdout, receipts, now)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("escalation failed:\n  %s", strings.Join(failed, "\n  "))
+	}
+	return nil
+}
+
+// buildMailReceipts converts messages to receipts, dropping read ones when
+// openOnly is set.
+func buildMailReceipts(messages []*mail.Message, now time.Time, openOnly bool) []mailReceipt {
+	receipts := make([]mailReceipt, 0, len(messages))
+	for _, msg := range messages {
+		state := mail.ReceiptState(msg, now)
+		if openOnly && state == mail.ReceiptRead {
+			continue
+		}
+		receipts = append(receipts, mailReceipt{
+			ID:        msg.ID,
+			From:      msg.From,
+			To:        msg.To,
+			Subject:   msg.Subject,
+			SentAt:    msg.Timestamp,
+			Deadline:  msg.AckDeadline,
+			State:     state,
+			Escalated: msg.AckEscalated,
+		})
+	}
+	return receipts
+}
+
+func printMailReceipts(w io.Writer, receipts []mailReceipt, now time.Time) {
+	if len(receipts) == 0 {
+		_, _ = fmt.Fprintf(w, "%s\n", style.Dim.Render("No ack-required mail"))
+		return
+	}
+	for _, r := range receipts {
+		var state string
+		switch r.State {
+		case mail.ReceiptRead:
+			state = style.Success.Render("✓ read")
+		case mail.ReceiptOverdue:
+			state = style.Error.Render("✗ overdue")
+			if r.Escalated {
+				state += style.Dim.Render(" (escalated)")
+			}
+		case mail.ReceiptDelivered:
+			state = style.Warning.Render("● delivered")
+		default:
+			state = style.Dim.Render("○ pending")
+		}
+		_, _ = fmt.Fprintf(w, "%s %s → %s  %s\n", state, r.From, r.To, r.Subject)
+		detail := r.ID
+		if r.Deadline != nil && r.State != mail.ReceiptRead {
+			if left := r.Deadline.Sub(now); left > 0 {
+				detail += ", due in " + formatDuration(left)
+			} else {
+				detail += ", due " + formatDuration(-left) + " ago"
+			}
+		}
+		_, _ = fmt.Fprintf(w, "    %s\n", style.Dim.Render(detail))
+	}
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func TestBuildMailReceipts(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-10 * time.Minute)
+	messages := []*mail.Message{
+		{ID: "hq-1", From: "mayor/", To: "gastown/Toast", Subject: "Rebase", AckRequired: true, AckDeadline: &past},
+		{ID: "hq-2", From: "mayor/", To: "gastown/Nux", Subject: "Done?", AckRequired: true, AckDeadline: &past, Read: true},
+	}
+
+	all := buildMailReceipts(messages, now, false)
+	if len(all) != 2 || all[0].State != mail.ReceiptOverdue || all[1].State != mail.ReceiptRead {
+		t.Fatalf("buildMailReceipts(all) = %+v", all)
+	}
+	open := buildMailReceipts(messages, now, true)
+	if len(open) != 1 || open[0].ID != "hq-1" {
+		t.Fatalf("buildMailReceipts(open) = %+v, want only hq-1", open)
+	}
+
+	var buf bytes.Buffer
+	printMailReceipts(&buf, all, now)
+	out := buf.String()
+	for _, want := range []string{"overdue", "gastown/Toast", "due 10m 0s ago", "read"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestSummarizeMailBacklog(t *testing.T) {
+	older := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	agent := &AgentRuntime{}
+	summarizeMailBacklog(agent, []*mail.Message{
+		{Timestamp: newer, AckRequired: true},
+		{Timestamp: older},
+	})
+	if agent.OldestUnread == nil || !agent.OldestUnread.Equal(older) {
+		t.Errorf("OldestUnread = %v, want %v", agent.OldestUnread, older)
+	}
+	if agent.AwaitingAck != 1 {
+		t.Errorf("AwaitingAck = %d, want 1", agent.AwaitingAck)
+	}
+}
@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	mailRulesIdentity string
+	mailRulesAll      bool
+	mailRulesDryRun   bool
+)
+
+var mailRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Show mailbox triage rules",
+	Long: `Show the mail rules that triage a mailbox as messages arrive.
+
+Rules live in config/messaging.json under "rules", keyed by mailbox
+address. Each rule matches on sender, subject and labels, and files the
+message into a folder, marks it read (ack), forwards a copy, and/or
+attaches it to an agent's hook. Rules are tried in order; every matching
+rule's actions run unless a matching rule sets "stop".
+
+  "rules": {
+    "mayor/": [
+      {"name": "merges", "from": "*/refinery", "subject": "MERGED*",
+       "folder": "merges", "ack": true},
+      {"name": "help", "subject": "HELP:*", "forward": ["overseer"], "stop": true},
+      {"name": "recovered", "subject": "RECOVERED_BEAD*", "hook": "deacon/"}
+    ]
+  }
+
+Globs: "*" matches any run of characters, "?" one character. Subject
+globs ignore case. Filed messages are hidden from 'gt mail inbox' (see
+--folder). Forwarded copies are never forwarded again.
+
+The Deacon applies rules every patrol with 'gt mail rules run --all'.
+
+Examples:
+  gt mail rules                        # Rules for your mailbox
+  gt mail rules --identity mayor/      # Rules for the Mayor's mailbox
+  gt mail rules --all                  # Every mailbox with rules`,
+	Args: cobra.NoArgs,
+	RunE: runMailRules,
+}
+
+var mailRulesRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Apply mail rules to new messages",
+	Long: `Apply mail rules to messages the rules haven't processed yet.
+
+Each message is processed once per mailbox; processed IDs are kept in
+.runtime/mail-rules.json. Run by the Deacon each patrol.
+
+Examples:
+  gt mail rules run                    # Your mailbox
+  gt mail rules run --all              # Every mailbox with rules (Deacon)
+  gt mail rules run --all --dry-run    # Show what would happen`,
+	Args: cobra.NoArgs,
+	RunE: runMailRulesRun,
+}
+
+var (
+	// mailRuleSendFn is a seam for tests. Production sends through the mail router.
+	mailRuleSendFn = func(msg *mail.Message) error {
+		workDir, err := findMailWorkDir()
+		if err != nil {
+			return err
+		}
+		return mail.NewRouter(workDir).Send(msg)
+	}
+
+	// mailRuleHookFn is a seam for tests. Production uses runHook.
+	mailRuleHookFn = func(msgID, agent string) error {
+		return runHook(nil, []string{msgID, agent})
+	}
+)
+
+func init() {
+	for _, c := range []*cobra.Command{mailRulesCmd, mailRulesRunCmd} {
+		c.Flags().StringVar(&mailRulesIdentity, "identity", "", "Mailbox address (default: your own)")
+		c.Flags().BoolVar(&mailRulesAll, "all", false, "Every mailbox that has rules")
+	}
+	mailRulesRunCmd.Flags().BoolVarP(&mailRulesDryRun, "dry-run", "n", false, "Show what would be done")
+
+	mailRulesCmd.AddCommand(mailRulesRunCmd)
+	mailCmd.AddCommand(mailRulesCmd)
+}
+
+// mailRulesTargets returns the mailboxes selected by --identity/--all and
+// the messaging config holding their rules.
+func mailRulesTargets() (string, *config.MessagingConfig, []string, error) {
+	townRoot, err := findMailWorkDir()
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := config.LoadOrCreateMessagingConfig(config.MessagingConfigPath(townRoot))
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("loading messaging config: %w", err)
+	}
+	if mailRulesAll {
+		addresses := make([]string, 0, len(cfg.Rules))
+		for address := range cfg.Rules {
+			addresses = append(addresses, address)
+		}
+		sort.Strings(addresses)
+		return townRoot, cfg, addresses, nil
+	}
+	address := mailRulesIdentity
+	if address == "" {
+		address = detectSender()
+	}
+	return townRoot, cfg, []string{address}, nil
+}
+
+func runMailRules(cmd *cobra.Command, args []string) error {
+	_, cfg, addresses, err := mailRulesTargets()
+	if err != nil {
+		return err
+	}
+	if len(addresses) == 0 {
+		fmt.Println("No mail rules configured (config/messaging.json \"rules\")")
+		return nil
+	}
+	for _, address := range addresses {
+		printMailRules(os.Stdout, address, mail.RulesForMailbox(cfg, address))
+	}
+	return nil
+}
+
+func printMailRules(w io.Writer, address string, rules []config.MailRule) {
+	_, _ = fmt.Fprintf(w, "%s %s\n", style.Bold.Render("📬"), address)
+	if len(rules) == 0 {
+		_, _ = fmt.Fprintf(w, "  %s\n", style.Dim.Render("(no rules)"))
+		return
+	}
+	for i, rule := range rules {
+		_, _ = fmt.Fprintf(w, "  %d. %s  %s → %s\n", i+1, style.Bold.Render(rule.Name), describeMailRuleMatch(rule), describeMailRuleActions(rule))
+	}
+}
+
+func describeMailRuleMatch(rule config.MailRule) string {
+	var parts []string
+	if rule.From != "" {
+		parts = append(parts, "from "+rule.From)
+	}
+	if rule.Subject != "" {
+		parts = append(parts, fmt.Sprintf("subject %q", rule.Subject))
+	}
+	if len(rule.Labels) > 0 {
+		parts = append(parts, "labels "+strings.Join(rule.Labels, ","))
+	}
+	if len(parts) == 0 {
+		return "any message"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describeMailRuleActions(rule config.MailRule) string {
+	var parts []string
+	if rule.Folder != "" {
+		parts = append(parts, "file to "+rule.Folder)
+	}
+	if rule.Ack {
+		parts = append(parts, "ack")
+	}
+	if len(rule.Forward) > 0 {
+		parts = append(parts, "forward to "+strings.Join(rule.Forward, ","))
+	}
+	if rule.Hook != "" {
+		parts = append(parts, "hook on "+rule.Hook)
+	}
+	if rule.Stop {
+		parts = append(parts, "stop")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func runMailRulesRun(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, addresses, err := mailRulesTargets()
+	if err != nil {
+		return err
+	}
+	state, err := mail.LoadRulesState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading mail rules state: %w", err)
+	}
+
+	var failed []string
+	for _, address := range addresses {
+		rules := mail.RulesForMailbox(cfg, address)
+		if len(rules) == 0 {
+			continue
+		}
+		mailbox, err := getMailbox(address)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", address, err))
+			continue
+		}
+		if err := applyMailRules(os.Stdout, mailbox, address, rules, state, mailRulesDryRun); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", address, err))
+		}
+	}
+
+	if !mailRulesDryRun {
+		if err := state.Save(townRoot); err != nil {
+			return fmt.Errorf("saving mail rules state: %w", err)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("mail rules failed:\n  %s", strings.Join(failed, "\n  "))
+	}
+	return nil
+}
+
+// applyMailRules runs the rules' actions on every message in the mailbox
+// that the rules haven't processed yet. Messages whose actions fail are
+// left unprocessed so the next run retries them.
+func applyMailRules(w io.Writer, mailbox *mail.Mailbox, address string, rules []config.MailRule, state *mail.RulesState, dryRun bool) error {
+	messages, err := mailbox.List()
+	if err != nil {
+		return fmt.Errorf("listing messages: %w", err)
+	}
+	if !dryRun {
+		state.Prune(address, messages)
+	}
+
+	var errs []string
+	for _, msg := range messages {
+		if state.Seen(address, msg.ID) {
+			continue
+		}
+		matched := mail.MatchingRules(rules, msg)
+		for _, rule := range matched {
+			_, _ = fmt.Fprintf(w, "  %s %s %q → %s\n", style.Dim.Render(msg.ID), style.Bold.Render(rule.Name), msg.Subject, describeMailRuleActions(rule))
+		}
+		if dryRun {
+			continue
+		}
+		if err := runMailRuleActions(mailbox, address, msg, matched); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", msg.ID, err))
+			continue
+		}
+		state.MarkSeen(address, msg.ID)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func runMailRuleActions(mailbox *mail.Mailbox, address string, msg *mail.Message, rules []config.MailRule) error {
+	for _, rule := range rules {
+		if rule.Folder != "" && msg.Folder != rule.Folder {
+			if err := mailbox.FileToFolder(msg.ID, rule.Folder); err != nil {
+				return fmt.Errorf("rule %s: filing to %s: %w", rule.Name, rule.Folder, err)
+			}
+			msg.Folder = rule.Folder
+		}
+		if rule.Ack && !msg.Read {
+			if err := mailbox.MarkReadOnly(msg.ID); err != nil {
+				return fmt.Errorf("rule %s: marking read: %w", rule.Name, err)
+			}
+			if err := mailbox.AcknowledgeDeliveries(address, []*mail.Message{msg}); err != nil {
+				style.PrintWarning("rule %s: delivery ack for %s failed: %v", rule.Name, msg.ID, err)
+			}
+			msg.Read = true
+		}
+		if len(rule.Forward) > 0 && !strings.HasPrefix(msg.Subject, mail.ForwardSubjectPrefix) {
+			for _, to := range rule.Forward {
+				if err := mailRuleSendFn(newMailRuleForward(address, to, rule.Name, msg)); err != nil {
+					return fmt.Errorf("rule %s: forwarding to %s: %w", rule.Name, to, err)
+				}
+			}
+		}
+		if rule.Hook != "" {
+			agent := rule.Hook
+			if agent == "self" {
+				agent = address
+			}
+			if err := mailRuleHookFn(msg.ID, agent); err != nil {
+				return fmt.Errorf("rule %s: hooking on %s: %w", rule.Name, agent, err)
+			}
+		}
+	}
+	return nil
+}
+
+// newMailRuleForward builds the copy of msg that a rule forwards to "to".
+func newMailRuleForward(from, to, ruleName string, msg *mail.Message) *mail.Message {
+	body := fmt.Sprintf("Forwarded by mail rule %q\nFrom: %s\nTo: %s\nDate: %s\n\n%s",
+		ruleName, msg.From, msg.To, msg.Timestamp.Format("2006-01-02 15:04"), msg.Body)
+	fwd := mail.NewMessage(from, to, mail.ForwardSubjectPrefix+msg.Subject, body)
+	fwd.Priority = msg.Priority
+	fwd.Type = msg.Type
+	return fwd
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+func stubMailRuleActions(t *testing.T) (*[]*mail.Message, *[]string) {
+	t.Helper()
+	var sent []*mail.Message
+	var hooked []string
+	oldSend, oldHook := mailRuleSendFn, mailRuleHookFn
+	mailRuleSendFn = func(msg *mail.Message) error { sent = append(sent, msg); return nil }
+	mailRuleHookFn = func(id, agent string) error { hooked = append(hooked, id+"→"+agent); return nil }
+	t.Cleanup(func() { mailRuleSendFn, mailRuleHookFn = oldSend, oldHook })
+	return &sent, &hooked
+}
+
+func TestApplyMailRules(t *testing.T) {
+	sent, hooked := stubMailRuleActions(t)
+	mailbox := mail.NewMailbox(t.TempDir())
+	for _, msg := range []*mail.Message{
+		{ID: "msg-1", From: "gastown/refinery", Subject: "MERGED gt-abc", Body: "done"},
+		{ID: "msg-2", From: "gastown/Toast", Subject: "HELP: tests hang"},
+		{ID: "msg-3", From: "gastown/witness", Subject: "RECOVERED_BEAD gt-xyz"},
+		{ID: "msg-4", From: "gastown/Toast", Subject: "Fwd: HELP: loop"},
+	} {
+		if err := mailbox.Append(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rules := []config.MailRule{
+		{Name: "merges", From: "*/refinery", Subject: "MERGED*", Folder: "merges", Ack: true},
+		{Name: "help", Subject: "*HELP:*", Forward: []string{"overseer"}, Stop: true},
+		{Name: "recovered", Subject: "RECOVERED_BEAD*", Hook: "self"},
+	}
+	state := &mail.RulesState{Processed: map[string][]string{}}
+
+	var out bytes.Buffer
+	if err := applyMailRules(&out, mailbox, "mayor/", rules, state, false); err != nil {
+		t.Fatal(err)
+	}
+
+	merged, _ := mailbox.Get("msg-1")
+	if merged.Folder != "merges" || !merged.Read {
+		t.Errorf("msg-1 Folder=%q Read=%v, want filed and acked", merged.Folder, merged.Read)
+	}
+	if len(*sent) != 1 || (*sent)[0].To != "overseer" || (*sent)[0].Subject != "Fwd: HELP: tests hang" {
+		t.Errorf("forwarded %+v, want one copy of msg-2 to overseer (forwards aren't re-forwarded)", *sent)
+	}
+	if !strings.Contains((*sent)[0].Body, "From: gastown/Toast") {
+		t.Errorf("forward body should name the original sender:\n%s", (*sent)[0].Body)
+	}
+	if len(*hooked) != 1 || (*hooked)[0] != "msg-3→mayor/" {
+		t.Errorf("hooked %v, want msg-3 on mayor/", *hooked)
+	}
+
+	// A second run must not repeat actions.
+	if err := applyMailRules(&out, mailbox, "mayor/", rules, state, false); err != nil {
+		t.Fatal(err)
+	}
+	if len(*sent) != 1 || len(*hooked) != 1 {
+		t.Errorf("second run repeated actions: sent %d, hooked %d", len(*sent), len(*hooked))
+	}
+}
+
+func TestApplyMailRules_DryRun(t *testing.T) {
+	sent, _ := stubMailRuleActions(t)
+	mailbox := mail.NewMailbox(t.TempDir())
+	if err := mailbox.Append(&mail.Message{ID: "msg-1", Subject: "HELP: stuck"}); err != nil {
+		t.Fatal(err)
+	}
+	rules := []config.MailRule{{Name: "help", Subject: "HELP*", Folder: "help", Forward: []string{"overseer"}}}
+	state := &mail.RulesState{Processed: map[string][]string{}}
+
+	var out bytes.Buffer
+	if err := applyMailRules(&out, mailbox, "mayor/", rules, state, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "file to help, forward to overseer") {
+		t.Errorf("dry-run output:\n%s", out.String())
+	}
+	msg, _ := mailbox.Get("msg-1")
+	if msg.Folder != "" || len(*sent) != 0 || state.Seen("mayor/", "msg-1") {
+		t.Error("dry run must not act or record state")
+	}
+}
+
+func TestFilterMailFolder(t *testing.T) {
+	messages := []*mail.Message{{ID: "a"}, {ID: "b", Folder: "merges"}, {ID: "c", Folder: "merges"}, {ID: "d", Folder: "help"}}
+
+	inbox, filed := filterMailFolder(messages, "")
+	if len(inbox) != 1 || inbox[0].ID != "a" {
+		t.Errorf("inbox = %v", inbox)
+	}
+	if filed["merges"] != 2 || filed["help"] != 1 {
+		t.Errorf("filed = %v", filed)
+	}
+
+	merges, filed := filterMailFolder(messages, "merges")
+	if len(merges) != 2 || len(filed) != 0 {
+		t.Errorf("--folder merges = %v (filed %v)", merges, filed)
+	}
+}
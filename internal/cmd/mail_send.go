@@ -8,11 +8,13 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/notification"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -87,8 +89,19 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		msg.Priority = mail.PriorityHigh
 	}
 
-	// Set message type
+	// Set message type and its structured fields
 	msg.Type = mail.ParseMessageType(mailType)
+	if len(mailFields) > 0 {
+		fields, err := parseMailFields(msg.Type, mailFields)
+		if err != nil {
+			return err
+		}
+		msg.Body = mail.FormatMessageBody(fields, msg.Body)
+		msg.Fields = fields
+	}
+	if err := mail.ValidateMessageFields(msg.Type, mail.ParseMessageFields(msg.Type, msg.Body)); err != nil {
+		return fmt.Errorf("%w (use --field)", err)
+	}
 
 	// Set pinned flag
 	msg.Pinned = mailPinned
@@ -99,6 +112,19 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Set CC recipients
 	msg.CC = mailCC
 
+	// Ack-required mail is tracked until read (see gt mail receipts)
+	if cmd.Flags().Changed("timeout") && !mailRequireAck {
+		return fmt.Errorf("--timeout requires --require-ack")
+	}
+	if mailRequireAck {
+		if mailAckTimeout <= 0 {
+			return fmt.Errorf("--timeout must be positive")
+		}
+		deadline := time.Now().Add(mailAckTimeout)
+		msg.AckRequired = true
+		msg.AckDeadline = &deadline
+	}
+
 	// Suppress router-side notification when --no-notify is passed.
 	// Otherwise the router handles idle-aware notification per-recipient,
 	// which also works correctly for fan-out (groups, lists, channels).
@@ -135,6 +161,14 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 		msg.ThreadID = generateThreadID()
 	}
 
+	// Mail from outside an agent session (scripts, integrations) is
+	// scanned for prompt injection before it can reach an agent.
+	if os.Getenv("GT_ROLE") == "" {
+		if guardRoot, err := workspace.FindFromCwd(); err == nil && guardRoot != "" && holdExternalMail(guardRoot, msg) {
+			return nil
+		}
+	}
+
 	// Use address resolver for new address types
 	townRoot, _ := workspace.FindFromCwd()
 	b := beads.New(townRoot)
@@ -209,6 +243,15 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	// Log mail event to activity feed
 	_ = events.LogFeed(events.TypeMail, from, events.MailPayload(to, mailSubject))
 
+	if msg.Type == mail.TypeApproval && townRoot != "" {
+		raiseNotification(townRoot, notification.Notification{
+			Event:    notification.EventApproval,
+			Severity: notification.SeverityNormal,
+			Title:    "Approval requested: " + mailSubject,
+			Body:     fmt.Sprintf("From %s to %s (gt mail needs-human)", from, to),
+		})
+	}
+
 	fmt.Printf("%s Message sent to %s\n", style.Bold.Render("✓"), to)
 	fmt.Printf("  Subject: %s\n", mailSubject)
 
@@ -223,10 +266,34 @@ func runMailSend(cmd *cobra.Command, args []string) error {
 	if msg.Type != mail.TypeNotification {
 		fmt.Printf("  Type: %s\n", msg.Type)
 	}
+	if msg.AckRequired {
+		fmt.Printf("  Ack required by: %s\n", msg.AckDeadline.Local().Format("2006-01-02 15:04"))
+	}
 
 	return nil
 }
 
+// parseMailFields parses --field key=value flags and checks them against
+// the message type's schema.
+func parseMailFields(t mail.MessageType, flags []string) (map[string]string, error) {
+	fields := make(map[string]string, len(flags))
+	for _, f := range flags {
+		key, value, ok := strings.Cut(f, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --field %q: want key=value", f)
+		}
+		if strings.Contains(value, "\n") {
+			return nil, fmt.Errorf("invalid --field %q: value must be a single line", f)
+		}
+		fields[key] = strings.TrimSpace(value)
+	}
+	if err := mail.CheckMessageFields(t, fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
 // generateThreadID creates a random thread ID for new message threads.
 func generateThreadID() string {
 	b := make([]byte, 6)
@@ -70,6 +70,9 @@ func runMailThread(cmd *cobra.Command, args []string) error {
 			msg.From, msg.To)
 		fmt.Printf("    %s\n",
 			style.Dim.Render(msg.Timestamp.Local().Format("2006-01-02 15:04")))
+		if linked := msg.LinkedBeads(); len(linked) > 0 {
+			fmt.Printf("    %s %s\n", style.Dim.Render("filed as"), strings.Join(linked, ", "))
+		}
 
 		if msg.Body != "" {
 			fmt.Printf("    %s\n", msg.Body)
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/triage"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	mailToBeadRig      string
+	mailToBeadTitle    string
+	mailToBeadPriority int
+	mailToBeadLabels   []string
+	mailToBeadForce    bool
+	mailToBeadDryRun   bool
+)
+
+var mailToBeadCmd = &cobra.Command{
+	Use:   "to-bead <message-id>",
+	Short: "File a mail message as a bead",
+	Long: `Turn a mail message into a bead, so an idea raised in mail (an agent's
+"we should also fix X") becomes tracked work instead of evaporating.
+
+The bead's title is the message subject (or --title), and its description
+the message body followed by a link back to the message and its thread.
+It is labeled gt:task and mail:<sender>, and the message is labeled
+bead:<id> so the conversion shows up on the thread and isn't repeated.
+
+The bead is routed to:
+  - the rig given with --rig
+  - otherwise, when the town has triage configured, town beads as an
+    intake bead (triage:new) for triage to route
+  - otherwise, the sender's rig, or town beads when the sender has none
+
+Examples:
+  gt mail to-bead hq-abc12
+  gt mail to-bead hq-abc12 --rig gastown --title "Retry refinery pushes"
+  gt mail to-bead hq-abc12 -p 1 -l refinery
+  gt mail to-bead hq-abc12 --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMailToBead,
+}
+
+var (
+	// mailToBeadRigForFn is a seam for tests. Production uses IsRigName.
+	mailToBeadRigForFn = func(name string) string {
+		rigName, _ := IsRigName(name)
+		return rigName
+	}
+
+	// mailToBeadCreateFn is a seam for tests. Production creates the bead with
+	// bd.
+	mailToBeadCreateFn = func(dir string, opts beads.CreateOptions) (string, error) {
+		issue, err := beads.New(dir).Create(opts)
+		if err != nil {
+			return "", err
+		}
+		return issue.ID, nil
+	}
+)
+
+func init() {
+	mailToBeadCmd.Flags().StringVar(&mailToBeadRig, "rig", "", "Create the bead in this rig")
+	mailToBeadCmd.Flags().StringVar(&mailToBeadTitle, "title", "", "Bead title (default: the message subject)")
+	mailToBeadCmd.Flags().IntVarP(&mailToBeadPriority, "priority", "p", -1, "Bead priority 0-4 (default: bd default)")
+	mailToBeadCmd.Flags().StringSliceVarP(&mailToBeadLabels, "label", "l", nil, "Extra label for the bead (repeatable)")
+	mailToBeadCmd.Flags().BoolVarP(&mailToBeadForce, "force", "f", false, "File the message again even if it already was")
+	mailToBeadCmd.Flags().BoolVarP(&mailToBeadDryRun, "dry-run", "n", false, "Show the bead without creating it")
+	mailCmd.AddCommand(mailToBeadCmd)
+}
+
+func runMailToBead(_ *cobra.Command, args []string) error {
+	msgID := args[0]
+	if mailToBeadPriority < -1 || mailToBeadPriority > 4 {
+		return fmt.Errorf("--priority must be 0-4")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	workDir, err := findMailWorkDir()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	router := mail.NewRouter(workDir)
+	mailbox, err := router.GetMailbox(detectSender())
+	if err != nil {
+		return fmt.Errorf("getting mailbox: %w", err)
+	}
+	msg, err := mailbox.Get(msgID)
+	if err != nil {
+		return fmt.Errorf("getting message: %w", err)
+	}
+	if linked := msg.LinkedBeads(); len(linked) > 0 && !mailToBeadForce {
+		return fmt.Errorf("%s was already filed as %s (use --force to file it again)", msgID, strings.Join(linked, ", "))
+	}
+
+	triageOn := false
+	if ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		triageOn = ts.Triage != nil
+	}
+	rigName, err := mailToBeadRoute(msg, mailToBeadRig, triageOn)
+	if err != nil {
+		return err
+	}
+	opts := mailToBeadOptions(msg, mailToBeadTitle, triageOn && mailToBeadRig == "")
+	opts.Priority = mailToBeadPriority
+	opts.Labels = append(opts.Labels, mailToBeadLabels...)
+	opts.Actor = detectSender()
+	if opts.Title == "" {
+		return fmt.Errorf("%s has no subject: give the bead a --title", msgID)
+	}
+
+	dir, where := townRoot, "town"
+	if rigName != "" {
+		dir, where = filepath.Join(townRoot, rigName), rigName
+	}
+	if mailToBeadDryRun {
+		fmt.Printf("Would create %s bead %q\n", where, opts.Title)
+		fmt.Printf("  Labels: %s\n", strings.Join(opts.Labels, ", "))
+		return nil
+	}
+
+	id, err := mailToBeadCreateFn(dir, opts)
+	if err != nil {
+		return fmt.Errorf("creating bead: %w", err)
+	}
+	fmt.Printf("%s Filed %s as %s bead %s: %s\n", style.SuccessPrefix, msgID, where, style.Bold.Render(id), opts.Title)
+	if err := mailbox.LinkBead(msgID, id); err != nil {
+		style.PrintWarning("could not link %s back to %s: %v", msgID, id, err)
+	}
+	return nil
+}
+
+// mailToBeadRoute picks the rig a message's bead is created in: the --rig
+// flag, else none when triage will route it from town beads, else the
+// sender's rig. "" means town beads.
+func mailToBeadRoute(msg *mail.Message, rigFlag string, triageOn bool) (string, error) {
+	if rigFlag != "" {
+		rigName := mailToBeadRigForFn(rigFlag)
+		if rigName == "" {
+			return "", fmt.Errorf("'%s' is not a known rig", rigFlag)
+		}
+		return rigName, nil
+	}
+	if triageOn {
+		return "", nil
+	}
+	sender, _, _ := strings.Cut(strings.TrimSuffix(msg.From, "/"), "/")
+	if sender == "" {
+		return "", nil
+	}
+	return mailToBeadRigForFn(sender), nil
+}
+
+// mailToBeadOptions builds the bead for a message: its title (the subject
+// without reply prefixes unless title is given), a description that links
+// back to the message and thread, and its labels. intake marks it for
+// triage.
+func mailToBeadOptions(msg *mail.Message, title string, intake bool) beads.CreateOptions {
+	if title == "" {
+		title = strings.TrimSpace(msg.Subject)
+		for strings.HasPrefix(title, "Re: ") {
+			title = strings.TrimSpace(strings.TrimPrefix(title, "Re: "))
+		}
+	}
+
+	var desc strings.Builder
+	if body := strings.TrimSpace(msg.Body); body != "" {
+		desc.WriteString(body)
+		desc.WriteString("\n\n")
+	}
+	fmt.Fprintf(&desc, "From mail %s sent by %s", msg.ID, msg.From)
+	if !msg.Timestamp.IsZero() {
+		fmt.Fprintf(&desc, " on %s", msg.Timestamp.Local().Format("2006-01-02 15:04"))
+	}
+	if msg.ThreadID != "" {
+		fmt.Fprintf(&desc, "\nThread: %s (gt mail thread %s)", msg.ThreadID, msg.ThreadID)
+	}
+
+	labels := []string{"gt:task", "mail:" + strings.TrimSuffix(msg.From, "/")}
+	if intake {
+		labels = append(labels, triage.LabelNew)
+	}
+	return beads.CreateOptions{
+		Title:       title,
+		Description: desc.String(),
+		Labels:      labels,
+	}
+}
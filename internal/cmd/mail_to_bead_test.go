@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/triage"
+)
+
+func TestMailToBeadRoute(t *testing.T) {
+	orig := mailToBeadRigForFn
+	t.Cleanup(func() { mailToBeadRigForFn = orig })
+	mailToBeadRigForFn = func(name string) string {
+		if name == "gastown" || name == "beads" {
+			return name
+		}
+		return ""
+	}
+
+	fromCrew := &mail.Message{From: "gastown/crew/max"}
+	tests := []struct {
+		name     string
+		msg      *mail.Message
+		rigFlag  string
+		triageOn bool
+		want     string
+		wantErr  bool
+	}{
+		{"sender's rig", fromCrew, "", false, "gastown", false},
+		{"rig flag wins", fromCrew, "beads", true, "beads", false},
+		{"unknown rig flag", fromCrew, "nope", false, "", true},
+		{"triage routes from town", fromCrew, "", true, "", false},
+		{"town sender", &mail.Message{From: "mayor/"}, "", false, "", false},
+	}
+	for _, tt := range tests {
+		got, err := mailToBeadRoute(tt.msg, tt.rigFlag, tt.triageOn)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("%s: mailToBeadRoute() = %q, %v; want %q (err %v)", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestMailToBeadOptions(t *testing.T) {
+	msg := &mail.Message{
+		ID:        "hq-abc12",
+		From:      "gastown/polecats/toast",
+		Subject:   "Re: Re: refinery retries",
+		Body:      "Done. We should also fix the push retry loop.\n",
+		Timestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, time.Local),
+		ThreadID:  "thread-123",
+	}
+
+	opts := mailToBeadOptions(msg, "", false)
+	if opts.Title != "refinery retries" {
+		t.Errorf("title = %q", opts.Title)
+	}
+	for _, want := range []string{"push retry loop", "From mail hq-abc12 sent by gastown/polecats/toast on 2026-03-01 12:00", "gt mail thread thread-123"} {
+		if !strings.Contains(opts.Description, want) {
+			t.Errorf("description missing %q:\n%s", want, opts.Description)
+		}
+	}
+	if want := []string{"gt:task", "mail:gastown/polecats/toast"}; !reflect.DeepEqual(opts.Labels, want) {
+		t.Errorf("labels = %v, want %v", opts.Labels, want)
+	}
+
+	opts = mailToBeadOptions(msg, "Fix push retry loop", true)
+	if opts.Title != "Fix push retry loop" {
+		t.Errorf("title override = %q", opts.Title)
+	}
+	if opts.Labels[len(opts.Labels)-1] != triage.LabelNew {
+		t.Errorf("intake bead labels = %v, want %s", opts.Labels, triage.LabelNew)
+	}
+}
@@ -35,12 +35,17 @@ The Mayor is the global coordinator for Gas Town:
 The Mayor is the primary interface between the human Overseer and the
 automated agents. When in doubt, escalate to the Mayor.
 
+Large towns can split coordination across shard mayors, each owning a
+subset of rigs (see gt mayor shards). Use --shard to manage a shard
+mayor; without it, commands act on the primary Mayor.
+
 Role shortcuts: "mayor" in mail/nudge addresses resolves to this agent.`,
 }
 
 var (
 	mayorAgentOverride string
 	mayorStatusRunning bool
+	mayorShard         string
 )
 
 var mayorStartCmd = &cobra.Command{
@@ -115,6 +120,8 @@ var acpRigOverride string
 var acpTownRootOverride string
 
 func init() {
+	mayorCmd.PersistentFlags().StringVar(&mayorShard, "shard", "", "Act on the named shard mayor instead of the primary Mayor")
+
 	mayorCmd.AddCommand(mayorStartCmd)
 	mayorCmd.AddCommand(mayorStopCmd)
 	mayorCmd.AddCommand(mayorAttachCmd)
@@ -135,13 +142,42 @@ func init() {
 	rootCmd.AddCommand(mayorCmd)
 }
 
-// getMayorManager returns a mayor manager for the current workspace.
+// getMayorManager returns a mayor manager for the current workspace,
+// honoring --shard.
 func getMayorManager() (*mayor.Manager, error) {
 	townRoot, err := workspace.FindFromCwdOrError()
 	if err != nil {
 		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
-	return mayor.NewManager(townRoot), nil
+	return mayorManagerFor(townRoot, mayorShard)
+}
+
+// mayorManagerFor returns the manager for the named shard mayor, or the
+// primary Mayor when shardName is empty.
+func mayorManagerFor(townRoot, shardName string) (*mayor.Manager, error) {
+	if shardName == "" {
+		return mayor.NewManager(townRoot), nil
+	}
+	if config.LoadMayorShards(townRoot).Get(shardName) == nil {
+		return nil, fmt.Errorf("unknown mayor shard %q (see gt mayor shards)", shardName)
+	}
+	return mayor.NewShardManager(townRoot, shardName), nil
+}
+
+// mayorLabel names the managed mayor in command output.
+func mayorLabel(mgr *mayor.Manager) string {
+	if mgr.Shard() != "" {
+		return "Mayor " + mgr.Shard()
+	}
+	return "Mayor"
+}
+
+// mayorCommandHint renders a gt mayor subcommand for the managed mayor.
+func mayorCommandHint(mgr *mayor.Manager, sub string) string {
+	if mgr.Shard() != "" {
+		return fmt.Sprintf("gt mayor %s --shard %s", sub, mgr.Shard())
+	}
+	return "gt mayor " + sub
 }
 
 // getMayorSessionName returns the Mayor session name.
@@ -155,17 +191,17 @@ func runMayorStart(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Println("Starting Mayor session...")
+	fmt.Printf("Starting %s session...\n", mayorLabel(mgr))
 	if err := mgr.Start(mayorAgentOverride); err != nil {
 		if err == mayor.ErrAlreadyRunning {
-			return fmt.Errorf("Mayor session already running. Attach with: gt mayor attach")
+			return fmt.Errorf("%s session already running. Attach with: %s", mayorLabel(mgr), mayorCommandHint(mgr, "attach"))
 		}
 		return err
 	}
 
-	fmt.Printf("%s Mayor session started. Attach with: %s\n",
-		style.Bold.Render("✓"),
-		style.Dim.Render("gt mayor attach"))
+	fmt.Printf("%s %s session started. Attach with: %s\n",
+		style.Bold.Render("✓"), mayorLabel(mgr),
+		style.Dim.Render(mayorCommandHint(mgr, "attach")))
 
 	return nil
 }
@@ -176,15 +212,15 @@ func runMayorStop(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Println("Stopping Mayor session...")
+	fmt.Printf("Stopping %s session...\n", mayorLabel(mgr))
 	if err := mgr.Stop(); err != nil {
 		if err == mayor.ErrNotRunning {
-			return fmt.Errorf("Mayor session is not running")
+			return fmt.Errorf("%s session is not running", mayorLabel(mgr))
 		}
 		return err
 	}
 
-	fmt.Printf("%s Mayor session stopped.\n", style.Bold.Render("✓"))
+	fmt.Printf("%s %s session stopped.\n", style.Bold.Render("✓"), mayorLabel(mgr))
 	return nil
 }
 
@@ -201,7 +237,7 @@ func runMayorAttach(cmd *cobra.Command, args []string) error {
 
 	// Check if ACP is active and gracefully shut it down before switching to tmux.
 	// Only 'gt mayor attach' is allowed to transition from ACP to tmux mode.
-	if mayor.IsACPActive(townRoot) {
+	if mgr.Shard() == "" && mayor.IsACPActive(townRoot) {
 		fmt.Fprintf(os.Stderr, "ACP Mayor is active. Switching to tmux mode...\n")
 		if err := gracefullyShutdownACP(townRoot); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: could not gracefully shutdown ACP: %v\n", err)
@@ -222,7 +258,7 @@ func runMayorAttach(cmd *cobra.Command, args []string) error {
 	}
 	if !running {
 		// Auto-start if not running
-		fmt.Println("Mayor session not running, starting...")
+		fmt.Printf("%s session not running, starting...\n", mayorLabel(mgr))
 		if err := mgr.Start(mayorAgentOverride); err != nil {
 			return err
 		}
@@ -241,8 +277,9 @@ func runMayorAttach(cmd *cobra.Command, args []string) error {
 			}
 
 			// Build startup beacon for context (like gt handoff does)
+			identity := &session.AgentIdentity{Role: session.RoleMayor, Name: mgr.Shard()}
 			beacon := session.FormatStartupBeacon(session.BeaconConfig{
-				Recipient: "mayor",
+				Recipient: identity.BeaconAddress(),
 				Sender:    "human",
 				Topic:     "attach",
 			})
@@ -252,6 +289,9 @@ func runMayorAttach(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				return fmt.Errorf("building startup command: %w", err)
 			}
+			if mgr.Shard() != "" {
+				startupCmd = config.PrependEnv(startupCmd, map[string]string{"GT_MAYOR_SHARD": mgr.Shard()})
+			}
 
 			// Set remain-on-exit so the pane survives process death during respawn.
 			// Without this, killing processes causes tmux to destroy the pane.
@@ -271,7 +311,7 @@ func runMayorAttach(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf("restarting runtime: %w", err)
 			}
 
-			fmt.Printf("%s Mayor restarted with context\n", style.Bold.Render("✓"))
+			fmt.Printf("%s %s restarted with context\n", style.Bold.Render("✓"), mayorLabel(mgr))
 		}
 	}
 
@@ -326,7 +366,10 @@ func runMayorStatus(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	mgr := mayor.NewManager(townRoot)
+	mgr, err := mayorManagerFor(townRoot, mayorShard)
+	if err != nil {
+		return err
+	}
 	status, err := mgr.CombinedStatus()
 	if err != nil {
 		return err
@@ -338,10 +381,10 @@ func runMayorStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	if !status.Active {
-		fmt.Printf("%s Mayor session is %s\n",
-			style.Dim.Render("○"),
+		fmt.Printf("%s %s session is %s\n",
+			style.Dim.Render("○"), mayorLabel(mgr),
 			"not running")
-		fmt.Printf("\nStart with: %s\n", style.Dim.Render("gt mayor start"))
+		fmt.Printf("\nStart with: %s\n", style.Dim.Render(mayorCommandHint(mgr, "start")))
 		return nil
 	}
 
@@ -350,8 +393,8 @@ func runMayorStatus(cmd *cobra.Command, args []string) error {
 		if status.Tmux.Attached {
 			attachedStatus = "attached"
 		}
-		fmt.Printf("%s Mayor (tmux) is %s\n",
-			style.Bold.Render("●"),
+		fmt.Printf("%s %s (tmux) is %s\n",
+			style.Bold.Render("●"), mayorLabel(mgr),
 			style.Bold.Render("running"))
 		fmt.Printf("  Status: %s\n", attachedStatus)
 		fmt.Printf("  Created: %s\n", status.Tmux.Created)
@@ -365,7 +408,7 @@ func runMayorStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	if status.Tmux != nil {
-		fmt.Printf("\nAttach with: %s\n", style.Dim.Render("gt mayor attach"))
+		fmt.Printf("\nAttach with: %s\n", style.Dim.Render(mayorCommandHint(mgr, "attach")))
 	} else if status.ACPPid != 0 {
 		fmt.Printf("\nAttach with: %s\n", style.Dim.Render("gt mayor acp"))
 	}
@@ -446,6 +489,9 @@ func ensureMayorInfra(townRoot string) error {
 // A PID file is created to signal that automatic cleanup should be vetoed,
 // allowing the Mayor to review worker diffs before cleanup.
 func runMayorAcp(cmd *cobra.Command, args []string) error {
+	if mayorShard != "" {
+		return fmt.Errorf("ACP is not supported for shard mayors")
+	}
 	ctx := context.Background()
 
 	townRoot := acpTownRootOverride
@@ -0,0 +1,337 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// mayorCarryoverLimit caps each list in the carryover summary so the fresh
+// session starts with a digest, not another full context.
+const mayorCarryoverLimit = 15
+
+// mayorRotateHandoffGrace is how long a scheduled rotation waits for the
+// Mayor to hand off itself before rotating it with a summary built from
+// town state.
+const mayorRotateHandoffGrace = time.Hour
+
+var (
+	mayorRotateMessage string
+	mayorRotateStdin   bool
+	mayorRotateIfOlder time.Duration
+	mayorRotateReason  string
+	mayorRotateForce   bool
+	mayorRotateDryRun  bool
+)
+
+var mayorRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Refresh the Mayor with a fresh session, carrying over its plans",
+	Long: `Rotate the Mayor onto a fresh session without losing the thread.
+
+A long-running Mayor degrades as its context fills. Rotation:
+  1. Summarizes what the Mayor is juggling: open convoys, unread mail,
+     and its hooked or in-progress work, plus any notes you pass
+  2. Sends the summary as handoff mail and hooks it to the Mayor
+  3. Respawns the Mayor's pane with a fresh agent, which picks the
+     summary up from its hook on startup (gt prime)
+
+With --shard, the named shard mayor is rotated instead.
+
+The tmux session survives rotation, so attached terminals stay attached.
+The Mayor can rotate itself: run from inside the Mayor session, the notes
+should carry the plans only it knows about.
+
+With --if-older, rotation only happens once the current context has been
+running that long. The Mayor is then asked (by queued nudge) to rotate
+itself with notes on its plans at its next stopping point. Only if it has
+not done so within an hour is it rotated from outside, and then only while
+it sits idle at its prompt and nobody is attached. --force skips the wait.
+The daemon's mayor_rotation patrol uses this to rotate on a schedule;
+enable it in mayor/daemon.json:
+
+  "patrols": {"mayor_rotation": {"enabled": true, "max_age": "8h"}}
+
+Examples:
+  gt mayor rotate
+  gt mayor rotate -m "Waiting on auth convoy; then cut the release"
+  gt mayor rotate --if-older 8h --reason scheduled
+  gt mayor rotate --dry-run`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runMayorRotate,
+}
+
+func init() {
+	mayorRotateCmd.Flags().StringVarP(&mayorRotateMessage, "message", "m", "", "Notes to carry over (plans, open threads)")
+	mayorRotateCmd.Flags().BoolVar(&mayorRotateStdin, "stdin", false, "Read carryover notes from stdin")
+	mayorRotateCmd.Flags().DurationVar(&mayorRotateIfOlder, "if-older", 0, "Only rotate if the current context is at least this old (e.g. 8h)")
+	mayorRotateCmd.Flags().StringVar(&mayorRotateReason, "reason", "manual", "Reason recorded for the rotation")
+	mayorRotateCmd.Flags().BoolVar(&mayorRotateForce, "force", false, "With --if-older, rotate now even while the Mayor is busy or attached")
+	mayorRotateCmd.Flags().BoolVarP(&mayorRotateDryRun, "dry-run", "n", false, "Show the carryover summary without rotating")
+
+	mayorCmd.AddCommand(mayorRotateCmd)
+}
+
+func runMayorRotate(cmd *cobra.Command, args []string) error {
+	if mayorRotateStdin {
+		if mayorRotateMessage != "" {
+			return fmt.Errorf("cannot use --stdin with --message/-m")
+		}
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("reading stdin: %w", err)
+		}
+		mayorRotateMessage = strings.TrimRight(string(data), "\n")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	mgr, err := mayorManagerFor(townRoot, mayorShard)
+	if err != nil {
+		return err
+	}
+	if mgr.Shard() == "" && mayor.IsACPActive(townRoot) {
+		return fmt.Errorf("ACP Mayor is active; rotation only applies to the tmux session")
+	}
+
+	label, address := mayorLabel(mgr), mgr.Address()
+	sessionName := mgr.SessionName()
+	info, err := mgr.Status()
+	if err != nil {
+		if err == mayor.ErrNotRunning {
+			if mayorRotateIfOlder > 0 {
+				fmt.Printf("%s session is not running; nothing to rotate\n", label)
+				return nil
+			}
+			return fmt.Errorf("%s session is not running (start with: %s)", label, mayorCommandHint(mgr, "start"))
+		}
+		return err
+	}
+
+	now := time.Now()
+	t := tmux.NewTmux()
+	if mayorRotateIfOlder > 0 {
+		last := mayor.LastRotation(townRoot, mgr.Shard())
+		created, _ := time.ParseInLocation("2006-01-02 15:04:05", info.Created, time.Local)
+		age := mayor.SessionAge(now, created, last)
+		if age < mayorRotateIfOlder {
+			fmt.Printf("%s context is %s old (rotates after %s); skipping\n",
+				label, age.Round(time.Minute), mayorRotateIfOlder)
+			return nil
+		}
+		if !mayorRotateForce && !rotatingSelf(sessionName) {
+			var requested time.Time
+			if last != nil && last.Requested.After(now.Add(-age)) {
+				requested = last.Requested
+			}
+			wait, err := deferScheduledRotation(t, townRoot, mgr, info.Attached, now, requested)
+			if err != nil {
+				return err
+			}
+			if wait != "" {
+				fmt.Printf("%s context is %s old; %s\n", label, age.Round(time.Minute), wait)
+				return nil
+			}
+		}
+	}
+
+	subject := fmt.Sprintf("🤝 HANDOFF: %s rotation (%s)", label, mayorRotateReason)
+	summary := formatMayorCarryover(now, mayorRotateReason, mayorRotateMessage, collectMayorCarryover(townRoot, address))
+
+	if mayorRotateDryRun {
+		fmt.Printf("Would send handoff mail to %s: %s\n\n%s\n\n", address, subject, summary)
+		fmt.Printf("Would respawn %s with a fresh session\n", sessionName)
+		return nil
+	}
+
+	// Run from inside the Mayor's own pane: hand off in place. Killing our
+	// own pane first would take this process with it, so reuse gt handoff's
+	// self-respawn path.
+	if rotatingSelf(sessionName) {
+		if err := mayor.RecordRotation(townRoot, mgr.Shard(), mayor.Rotation{At: now, Reason: mayorRotateReason}); err != nil {
+			style.PrintWarning("could not record rotation: %v", err)
+		}
+		handoffSubject = subject
+		handoffMessage = summary
+		handoffNoGitCheck = true
+		return runHandoff(cmd, nil)
+	}
+
+	fmt.Printf("%s Rotating %s (%s)...\n", style.Bold.Render("🔄"), label, mayorRotateReason)
+
+	beadID, err := sendHandoffMailTo(townRoot, address, subject, summary)
+	if err != nil {
+		return fmt.Errorf("sending carryover: %w", err)
+	}
+	fmt.Printf("%s Sent carryover %s (hooked to %s)\n", style.Bold.Render("📬"), beadID, address)
+
+	restartCmd, err := buildRestartCommand(sessionName)
+	if err != nil {
+		return err
+	}
+	updateSessionEnvForHandoff(t, sessionName, "")
+	if err := respawnSessionPane(t, sessionName, restartCmd, false, false); err != nil {
+		return err
+	}
+
+	if err := mayor.RecordRotation(townRoot, mgr.Shard(), mayor.Rotation{At: now, Reason: mayorRotateReason, Carryover: beadID}); err != nil {
+		style.PrintWarning("could not record rotation: %v", err)
+	}
+	actor := strings.TrimSuffix(address, "/")
+	_ = LogHandoff(townRoot, actor, subject)
+	_ = events.LogFeed(events.TypeHandoff, actor, events.HandoffPayload(subject, true))
+
+	fmt.Printf("%s %s rotated onto a fresh session\n", style.Bold.Render("✓"), label)
+	return nil
+}
+
+// deferScheduledRotation decides whether a due scheduled rotation waits,
+// returning why, or "" to rotate now. The Mayor is first asked to rotate
+// itself so the carryover holds its own plans; it is rotated from outside
+// only after mayorRotateHandoffGrace, and never mid-work. requested is when
+// the Mayor was asked during this context, or zero.
+func deferScheduledRotation(t *tmux.Tmux, townRoot string, mgr *mayor.Manager, attached bool, now, requested time.Time) (string, error) {
+	if requested.IsZero() {
+		err := nudge.Enqueue(townRoot, mgr.SessionName(), nudge.QueuedNudge{
+			Sender: "daemon",
+			Message: fmt.Sprintf("Your context is due for rotation. At your next stopping point, run: %s -m \"<your plans and open threads>\"",
+				mayorCommandHint(mgr, "rotate")),
+			Priority: nudge.PriorityNormal,
+		})
+		if err != nil {
+			return "", fmt.Errorf("asking the Mayor to rotate: %w", err)
+		}
+		if err := mayor.RecordRotationRequest(townRoot, mgr.Shard(), now); err != nil {
+			return "", err
+		}
+		return "asked it to rotate itself at its next stopping point", nil
+	}
+	if waited := now.Sub(requested); waited < mayorRotateHandoffGrace {
+		return fmt.Sprintf("waiting for it to rotate itself (asked %s ago)", waited.Round(time.Minute)), nil
+	}
+	if attached {
+		return "the session is attached; deferring rotation", nil
+	}
+	if !t.IsIdle(mgr.SessionName()) {
+		return "the Mayor is working; deferring rotation", nil
+	}
+	return "", nil
+}
+
+// rotatingSelf reports whether gt is running inside the rotated mayor's own session.
+func rotatingSelf(sessionName string) bool {
+	if !tmux.IsInsideTmux() {
+		return false
+	}
+	if role, _, _ := parseRoleString(os.Getenv("GT_ROLE")); role != RoleMayor {
+		return false
+	}
+	current, err := getCurrentTmuxSession()
+	return err == nil && current == sessionName
+}
+
+// mayorCarryover is the town state the Mayor was coordinating.
+type mayorCarryover struct {
+	Convoys []string // "hq-cv-abc: Title"
+	Mail    []string // "hq-xyz from gastown/witness: Subject"
+	Work    []string // "gt-abc [hooked]: Title"
+}
+
+// collectMayorCarryover gathers the open threads of the mayor at address
+// from town beads. Each source is best-effort: a failing query leaves its
+// section empty.
+func collectMayorCarryover(townRoot, address string) mayorCarryover {
+	var c mayorCarryover
+
+	type listedIssue struct {
+		ID     string   `json:"id"`
+		Title  string   `json:"title"`
+		Status string   `json:"status"`
+		Labels []string `json:"labels"`
+	}
+
+	if out, err := runBdJSON(townRoot, "list", "--type=convoy", "--status=open", "--json"); err == nil {
+		var convoys []listedIssue
+		if json.Unmarshal(out, &convoys) == nil {
+			for _, cv := range convoys {
+				c.Convoys = append(c.Convoys, fmt.Sprintf("%s: %s", cv.ID, cv.Title))
+			}
+		}
+	}
+
+	mailbox := mail.NewMailboxFromAddress(address, townRoot)
+	if msgs, err := mailbox.ListUnread(); err == nil {
+		for _, msg := range msgs {
+			// Earlier carryovers are summarized by this one.
+			if strings.Contains(msg.Subject, "HANDOFF") {
+				continue
+			}
+			c.Mail = append(c.Mail, fmt.Sprintf("%s from %s: %s", msg.ID, msg.From, msg.Subject))
+		}
+	}
+
+	for _, status := range []string{"hooked", "in_progress"} {
+		out, err := runBdJSON(townRoot, "list", "--assignee="+address, "--status="+status, "--json")
+		if err != nil {
+			continue
+		}
+		var issues []listedIssue
+		if json.Unmarshal(out, &issues) != nil {
+			continue
+		}
+		for _, iss := range issues {
+			if hasLabel(iss.Labels, "gt:message") {
+				continue
+			}
+			c.Work = append(c.Work, fmt.Sprintf("%s [%s]: %s", iss.ID, iss.Status, iss.Title))
+		}
+	}
+
+	return c
+}
+
+// formatMayorCarryover renders the handoff mail body the fresh Mayor reads
+// on startup.
+func formatMayorCarryover(now time.Time, reason, notes string, c mayorCarryover) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Mayor session rotated (%s) at %s to refresh context.\n", reason, now.Format("2006-01-02 15:04"))
+	b.WriteString("This is what the previous session was coordinating; pick up from here.")
+
+	if notes = strings.TrimSpace(notes); notes != "" {
+		b.WriteString("\n\n## Notes from the previous session\n")
+		b.WriteString(notes)
+	}
+	writeSection := func(title string, items []string, empty string) {
+		fmt.Fprintf(&b, "\n\n## %s\n", title)
+		if len(items) == 0 {
+			b.WriteString(empty)
+			return
+		}
+		lines := items
+		if len(lines) > mayorCarryoverLimit {
+			lines = append(lines[:mayorCarryoverLimit:mayorCarryoverLimit], fmt.Sprintf("... and %d more", len(items)-mayorCarryoverLimit))
+		}
+		b.WriteString("- " + strings.Join(lines, "\n- "))
+	}
+	writeSection("Open convoys", c.Convoys, "None.")
+	writeSection("Unread mail", c.Mail, "Inbox clear.")
+	writeSection("Hooked / in-progress work", c.Work, "Nothing on the Mayor's hook.")
+
+	b.WriteString("\n\nRun gt convoy status and gt mail inbox for detail.")
+	return b.String()
+}
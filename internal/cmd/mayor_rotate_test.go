@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func TestFormatMayorCarryover(t *testing.T) {
+	now := time.Date(2026, 3, 1, 18, 5, 0, 0, time.UTC)
+	got := formatMayorCarryover(now, "scheduled", "  Release after auth lands  ", mayorCarryover{
+		Convoys: []string{"hq-cv-auth: Auth rewrite"},
+		Work:    []string{"hq-esc1 [hooked]: Decide on schema"},
+	})
+
+	for _, want := range []string{
+		"Mayor session rotated (scheduled) at 2026-03-01 18:05",
+		"## Notes from the previous session\nRelease after auth lands\n",
+		"## Open convoys\n- hq-cv-auth: Auth rewrite\n",
+		"## Unread mail\nInbox clear.\n",
+		"## Hooked / in-progress work\n- hq-esc1 [hooked]: Decide on schema\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("carryover missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestFormatMayorCarryover_NoNotesAndTruncation(t *testing.T) {
+	var mail []string
+	for i := 0; i < mayorCarryoverLimit+3; i++ {
+		mail = append(mail, fmt.Sprintf("hq-m%d from gastown/witness: Ping %d", i, i))
+	}
+	got := formatMayorCarryover(time.Now(), "manual", "", mayorCarryover{Mail: mail})
+
+	if strings.Contains(got, "Notes from the previous session") {
+		t.Errorf("empty notes should omit the notes section:\n%s", got)
+	}
+	if !strings.Contains(got, "- ... and 3 more") {
+		t.Errorf("expected truncation marker:\n%s", got)
+	}
+	if strings.Contains(got, fmt.Sprintf("hq-m%d ", mayorCarryoverLimit)) {
+		t.Errorf("items past the limit should be dropped:\n%s", got)
+	}
+	if !strings.Contains(got, "## Open convoys\nNone.") {
+		t.Errorf("expected empty convoy section:\n%s", got)
+	}
+}
+
+func TestDeferScheduledRotation(t *testing.T) {
+	townRoot := t.TempDir()
+	mgr := mayor.NewManager(townRoot)
+	tm := tmux.NewTmux()
+	now := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+
+	// First the Mayor is asked to write its own handoff.
+	wait, err := deferScheduledRotation(tm, townRoot, mgr, false, now, time.Time{})
+	if err != nil || !strings.Contains(wait, "asked it to rotate itself") {
+		t.Fatalf("first check = %q, %v; want the Mayor asked", wait, err)
+	}
+	if n, _ := nudge.Pending(townRoot, mgr.SessionName()); n != 1 {
+		t.Errorf("queued nudges = %d, want 1", n)
+	}
+	last := mayor.LastRotation(townRoot, "")
+	if last == nil || !last.Requested.Equal(now) {
+		t.Fatalf("rotation record = %+v, want the request recorded", last)
+	}
+
+	// It gets the grace period to do so.
+	wait, _ = deferScheduledRotation(tm, townRoot, mgr, false, now.Add(20*time.Minute), last.Requested)
+	if !strings.Contains(wait, "waiting for it to rotate itself") {
+		t.Errorf("within grace = %q, want waiting", wait)
+	}
+	if n, _ := nudge.Pending(townRoot, mgr.SessionName()); n != 1 {
+		t.Errorf("queued nudges = %d, want the Mayor asked once", n)
+	}
+
+	// After that it is still never rotated under an attached human.
+	wait, _ = deferScheduledRotation(tm, townRoot, mgr, true, now.Add(2*time.Hour), last.Requested)
+	if !strings.Contains(wait, "attached") {
+		t.Errorf("after grace, attached = %q, want deferred", wait)
+	}
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/shard"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var mayorShardsCmd = &cobra.Command{
+	Use:   "shards",
+	Short: "List shard mayors and the rigs each coordinates",
+	Long: `List the town's shard mayors.
+
+A single Mayor becomes the bottleneck once a town runs many rigs. Shards
+split coordination: each shard mayor owns a subset of rigs, and mail or
+escalations addressed to "mayor" from an agent in an owned rig are routed
+to that shard mayor (mayor/<shard>). Everything else — unsharded rigs,
+town-level agents, the overseer and the shard mayors themselves — still
+reaches the primary Mayor, which acts as super-coordinator. Escalations at
+or above a shard's cc_primary severity (default: critical) are also copied
+to the primary Mayor.
+
+Configure shards in settings/config.json:
+
+  "mayors": {
+    "shards": [
+      {"name": "web", "rigs": ["frontend", "api"]},
+      {"name": "data", "rigs": ["pipeline"], "cc_primary": "high"}
+    ]
+  }
+
+Manage a shard mayor with --shard, e.g. gt mayor start --shard web.
+
+Examples:
+  gt mayor shards
+  gt mayor start --shard web
+  gt mail send mayor/web -s "Status?" -m "How is the api convoy going?"`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runMayorShards,
+}
+
+func init() {
+	mayorCmd.AddCommand(mayorShardsCmd)
+}
+
+func runMayorShards(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	shards := settings.Mayors
+	if !shards.Enabled() {
+		fmt.Println("No mayor shards configured; the Mayor coordinates every rig.")
+		fmt.Printf("Add a %s section to %s to shard.\n",
+			style.Bold.Render(`"mayors"`), style.Dim.Render("settings/config.json"))
+		return nil
+	}
+	if err := shards.Validate(); err != nil {
+		return fmt.Errorf("invalid mayor shards (mail goes to the primary Mayor until fixed): %w", err)
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		rigsConfig = &config.RigsConfig{Rigs: make(map[string]config.RigEntry)}
+	}
+
+	for _, sh := range shards.Shards {
+		fmt.Printf("%s %s  %s\n", mayorRunningIcon(mayor.NewShardManager(townRoot, sh.Name)),
+			style.Bold.Render(sh.Address()), style.Dim.Render("cc primary: "+sh.GetCCPrimary()))
+		fmt.Printf("    rigs: %s\n", strings.Join(sh.Rigs, ", "))
+		for _, rig := range sh.Rigs {
+			if _, ok := rigsConfig.Rigs[rig]; !ok {
+				style.PrintWarning("shard %s: rig %q is not registered", sh.Name, rig)
+			}
+		}
+	}
+
+	fmt.Printf("%s %s  %s\n", mayorRunningIcon(mayor.NewManager(townRoot)),
+		style.Bold.Render(shard.PrimaryAddress), style.Dim.Render("super-coordinator"))
+	if unsharded := unshardedRigs(shards, rigsConfig); len(unsharded) > 0 {
+		fmt.Printf("    rigs: %s\n", strings.Join(unsharded, ", "))
+	}
+	return nil
+}
+
+// mayorRunningIcon renders ● for a running mayor session and ○ otherwise.
+func mayorRunningIcon(mgr *mayor.Manager) string {
+	if running, _ := mgr.IsRunning(); running {
+		return style.Bold.Render("●")
+	}
+	return style.Dim.Render("○")
+}
+
+// unshardedRigs returns the registered rigs that no shard owns, sorted.
+func unshardedRigs(shards *shard.Config, rigsConfig *config.RigsConfig) []string {
+	var rigs []string
+	for name := range rigsConfig.Rigs {
+		if shards.ForRig(name) == nil {
+			rigs = append(rigs, name)
+		}
+	}
+	sort.Strings(rigs)
+	return rigs
+}
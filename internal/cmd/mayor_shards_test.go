@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/shard"
+)
+
+func TestUnshardedRigs(t *testing.T) {
+	shards := &shard.Config{Shards: []shard.Shard{
+		{Name: "web", Rigs: []string{"frontend", "api"}},
+	}}
+	rigsConfig := &config.RigsConfig{Rigs: map[string]config.RigEntry{
+		"frontend": {}, "api": {}, "gastown": {}, "beads": {},
+	}}
+	if got, want := unshardedRigs(shards, rigsConfig), []string{"beads", "gastown"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unshardedRigs() = %v, want %v", got, want)
+	}
+}
+
+func TestMayorManagerFor_UnknownShard(t *testing.T) {
+	townRoot := t.TempDir()
+	if _, err := mayorManagerFor(townRoot, "web"); err == nil {
+		t.Error("mayorManagerFor with no shards configured should fail")
+	}
+	mgr, err := mayorManagerFor(townRoot, "")
+	if err != nil || mgr.Shard() != "" {
+		t.Errorf("mayorManagerFor(\"\") = %v, %v; want the primary Mayor", mgr, err)
+	}
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/scorecard"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// scorecardStatusWindow is the rolling window gt status scores polecats over.
+const scorecardStatusWindow = 7 * 24 * time.Hour
+
+var (
+	scorecardsBy     string
+	scorecardsWindow string
+	scorecardsRig    string
+	scorecardsJSON   bool
+)
+
+func init() {
+	metricsScorecardsCmd.Flags().StringVar(&scorecardsBy, "by", scorecard.ByPolecat, "Group by polecat, rig or config")
+	metricsScorecardsCmd.Flags().StringVar(&scorecardsWindow, "window", "7d", "Rolling window to score (e.g. 24h, 7d, 30d)")
+	metricsScorecardsCmd.Flags().StringVar(&scorecardsRig, "rig", "", "Only score polecats in this rig")
+	metricsScorecardsCmd.Flags().BoolVar(&scorecardsJSON, "json", false, "Output as JSON")
+
+	metricsCmd.AddCommand(metricsScorecardsCmd)
+}
+
+var metricsScorecardsCmd = &cobra.Command{
+	Use:   "scorecards",
+	Short: "Score polecats on completion, review bounces, cost and stalls",
+	Long: `Score polecats over a rolling window from the town event log and the
+session cost log.
+
+Each scorecard shows:
+  beads       Beads slung or hooked to the polecat
+  done        Share of those finished with gt done
+  bounced     Share of beads that reached the merge queue and failed a merge
+  $/bead      Session cost per bead
+  stalls/bead Stalls the witness classified, per bead
+  caused      Beads later linked caused-by to its work (gt bead link)
+
+Group by config to compare agent presets: a configuration that finishes
+less, bounces more or stalls more than the others is flagged.
+
+Examples:
+  gt metrics scorecards
+  gt metrics scorecards --by config --window 30d
+  gt metrics scorecards --rig gastown --json`,
+	Args: cobra.NoArgs,
+	RunE: runMetricsScorecards,
+}
+
+func runMetricsScorecards(cmd *cobra.Command, args []string) error {
+	switch scorecardsBy {
+	case scorecard.ByPolecat, scorecard.ByRig, scorecard.ByConfig:
+	default:
+		return fmt.Errorf("invalid --by %q (want %s, %s or %s)", scorecardsBy, scorecard.ByPolecat, scorecard.ByRig, scorecard.ByConfig)
+	}
+	window, err := parseDuration(scorecardsWindow)
+	if err != nil {
+		return fmt.Errorf("invalid --window: %w", err)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	cards, err := loadScorecards(townRoot, time.Now().Add(-window))
+	if err != nil {
+		return err
+	}
+	if scorecardsRig != "" {
+		cards = scorecardsForRig(cards, scorecardsRig)
+	}
+	cards = scorecard.Group(cards, scorecardsBy)
+
+	if scorecardsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(cards)
+	}
+
+	if len(cards) == 0 {
+		fmt.Printf("No polecat work in the last %s.\n", scorecardsWindow)
+		return nil
+	}
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("Scorecards (last %s, by %s)", scorecardsWindow, scorecardsBy)))
+	fmt.Printf("%-32s %8s %6s %6s %8s %8s %12s %7s\n", strings.ToUpper(scorecardsBy), "POLECATS", "BEADS", "DONE", "BOUNCED", "$/BEAD", "STALLS/BEAD", "CAUSED")
+	for _, c := range cards {
+		line := fmt.Sprintf("%-32s %8d %6d %5.0f%% %7.0f%% %8.2f %12.1f %7d",
+			c.Key, c.Polecats, c.Assigned, 100*c.CompletionRate(), 100*c.BounceRate(), c.CostPerBead(), c.StallsPerBead(), c.Caused)
+		if flags := c.Flags(); len(flags) > 0 {
+			line += "  " + style.Warning.Render("⚠ "+strings.Join(flags, ", "))
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// loadScorecards computes per-polecat scorecards from the town event log
+// and the polecat entries of the session cost log since the given time.
+func loadScorecards(townRoot string, since time.Time) ([]scorecard.Card, error) {
+	evs, err := timeLoadEventsFn(townRoot, since)
+	if err != nil {
+		return nil, fmt.Errorf("loading events: %w", err)
+	}
+	costs, err := loadPolecatCosts(since)
+	if err != nil {
+		return nil, err
+	}
+	return scorecard.Compute(evs, costs, since), nil
+}
+
+// loadPolecatCosts reads polecat session costs ended since the given time.
+func loadPolecatCosts(since time.Time) ([]scorecard.Cost, error) {
+	data, err := os.ReadFile(getCostsLogPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading costs log: %w", err)
+	}
+	var costs []scorecard.Cost
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var e CostLogEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			continue
+		}
+		if e.Role != constants.RolePolecat || e.EndedAt.Before(since) {
+			continue
+		}
+		costs = append(costs, scorecard.Cost{Rig: e.Rig, Worker: e.Worker, CostUSD: e.CostUSD, EndedAt: e.EndedAt})
+	}
+	return costs, nil
+}
+
+// scorecardsForRig keeps the cards of polecats in rigName.
+func scorecardsForRig(cards []scorecard.Card, rigName string) []scorecard.Card {
+	var out []scorecard.Card
+	for _, c := range cards {
+		if c.Rig == rigName {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// formatScorecard summarizes a config's card for gt status, e.g.
+// "7d claude: 3 polecats, 12 beads, 83% done, 10% bounced, $1.20/bead, 0.2 stalls/bead".
+func formatScorecard(c scorecard.Card) string {
+	s := fmt.Sprintf("7d %s: %d polecats, %d beads, %.0f%% done, %.0f%% bounced, $%.2f/bead, %.1f stalls/bead",
+		c.Key, c.Polecats, c.Assigned, 100*c.CompletionRate(), 100*c.BounceRate(), c.CostPerBead(), c.StallsPerBead())
+	if flags := c.Flags(); len(flags) > 0 {
+		s += " ⚠ " + strings.Join(flags, ", ")
+	}
+	return s
+}
@@ -0,0 +1,328 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/microtask"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	microJSON       bool
+	microSkipReason string
+)
+
+var microCmd = &cobra.Command{
+	Use:         "micro",
+	GroupID:     GroupWork,
+	Annotations: map[string]string{AnnotationPolecatSafe: "true"},
+	Short:       "Work through a micro-task queue",
+	Long: `Work through a micro-task queue: tiny beads slung with gt sling --micro
+that one polecat works one after another in a single session, on one
+branch, instead of a spawn and merge request per bead.
+
+The first bead of the queue is hooked as usual. For each task, commit the
+work, then run gt micro next: it records the commit, closes the bead
+(it merges with the batch) and shows the next task. gt micro skip gives a
+task back to the rig instead. When the queue is finished, run gt done:
+one merge request carries the whole batch, and any tasks still queued are
+returned to the rig.
+
+With no subcommand, shows your queue.
+
+Examples:
+  gt micro                       # Your queue and the current task
+  gt micro next                  # Record this task, start the next
+  gt micro skip -m "needs a design decision"
+  gt micro list                  # Every queue in the town`,
+	RunE: runMicroStatus,
+}
+
+var microStatusCmd = &cobra.Command{
+	Use:   "status [head-bead]",
+	Short: "Show a micro-task queue (default: yours)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runMicroStatus,
+}
+
+var microListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the town's micro-task queues",
+	Args:  cobra.NoArgs,
+	RunE:  runMicroList,
+}
+
+var microNextCmd = &cobra.Command{
+	Use:   "next",
+	Short: "Record the current micro-task as done and start the next",
+	Args:  cobra.NoArgs,
+	RunE:  runMicroNext,
+}
+
+var microSkipCmd = &cobra.Command{
+	Use:   "skip",
+	Short: "Give the current micro-task back and start the next",
+	Args:  cobra.NoArgs,
+	RunE:  runMicroSkip,
+}
+
+var (
+	// microHookedBeadFn is a seam for tests. Production uses
+	// findHookedBeadForAgent.
+	microHookedBeadFn = func(workDir string) string {
+		return findHookedBeadForAgent(beads.New(workDir), detectSender())
+	}
+
+	// microHeadCommitFn is a seam for tests. Production reads HEAD with git.
+	microHeadCommitFn = func(workDir string) string {
+		sha, _ := git.NewGit(workDir).Rev("HEAD")
+		return sha
+	}
+
+	// microBeadInfoFn is a seam for tests. Production uses getBeadInfo.
+	microBeadInfoFn = getBeadInfo
+
+	// microStartBeadFn is a seam for tests. Production marks the bead in
+	// progress with bd.
+	microStartBeadFn = func(id, assignee string) error {
+		status := "in_progress"
+		return beads.New(resolveBeadDir(id)).Update(id, beads.UpdateOptions{Status: &status, Assignee: &assignee})
+	}
+
+	// microCloseBeadFn is a seam for tests. Production runs bd close.
+	microCloseBeadFn = func(id, reason string) error {
+		return beads.New(resolveBeadDir(id)).CloseWithReason(reason, id)
+	}
+
+	// microReopenBeadFn is a seam for tests. Production reopens the bead and
+	// clears its assignee.
+	microReopenBeadFn = func(id string) error {
+		open, none := "open", ""
+		return beads.New(resolveBeadDir(id)).Update(id, beads.UpdateOptions{Status: &open, Assignee: &none})
+	}
+)
+
+func init() {
+	microCmd.Flags().BoolVar(&microJSON, "json", false, "Output as JSON")
+	microStatusCmd.Flags().BoolVar(&microJSON, "json", false, "Output as JSON")
+	microListCmd.Flags().BoolVar(&microJSON, "json", false, "Output as JSON")
+	microSkipCmd.Flags().StringVarP(&microSkipReason, "reason", "m", "", "Why the task is given back")
+	_ = microSkipCmd.MarkFlagRequired("reason")
+	for _, c := range []*cobra.Command{microStatusCmd, microListCmd, microNextCmd, microSkipCmd} {
+		c.Annotations = map[string]string{AnnotationPolecatSafe: "true"}
+		microCmd.AddCommand(c)
+	}
+	rootCmd.AddCommand(microCmd)
+}
+
+// loadMicroQueue returns the queue headed by head, or by the bead hooked
+// to this agent when head is empty.
+func loadMicroQueue(townRoot, workDir, head string) (*microtask.Queue, error) {
+	if head == "" {
+		if head = microHookedBeadFn(workDir); head == "" {
+			return nil, fmt.Errorf("nothing is hooked, so there is no micro-task queue (see gt micro list)")
+		}
+	}
+	q, err := microtask.Load(townRoot, head)
+	if err != nil {
+		return nil, err
+	}
+	if q == nil {
+		return nil, fmt.Errorf("%s has no micro-task queue", head)
+	}
+	return q, nil
+}
+
+func runMicroStatus(cmd *cobra.Command, args []string) error {
+	townRoot, cwd, err := microWorkspace()
+	if err != nil {
+		return err
+	}
+	head := ""
+	if len(args) > 0 {
+		head = args[0]
+	}
+	q, err := loadMicroQueue(townRoot, cwd, head)
+	if err != nil {
+		return err
+	}
+	if microJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(q)
+	}
+	fmt.Printf("%s Micro-task queue %s (%s) — %s\n", style.Bold.Render("🎯"), q.Head, q.Rig, q.Progress())
+	for i, t := range q.Tasks {
+		line := fmt.Sprintf("  %d. %-10s %-12s %s", i+1, t.Status, t.Bead, t.Title)
+		switch t.Status {
+		case microtask.StatusActive:
+			line = style.Bold.Render(line)
+		case microtask.StatusDone:
+			line += style.Dim.Render("  " + shortSHA(t.Commit))
+		case microtask.StatusSkipped, microtask.StatusReturned:
+			line = style.Dim.Render(line + "  " + t.Note)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runMicroList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	queues, err := microtask.List(townRoot)
+	if err != nil {
+		return err
+	}
+	if microJSON {
+		if queues == nil {
+			queues = []*microtask.Queue{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(queues)
+	}
+	if len(queues) == 0 {
+		fmt.Println("No micro-task queues.")
+		return nil
+	}
+	for _, q := range queues {
+		polecat := q.Polecat
+		if polecat == "" {
+			polecat = q.Rig + "/polecats/?"
+		}
+		current := "finished"
+		if t := q.Current(); t != nil {
+			current = "on " + t.Bead
+		}
+		fmt.Printf("  %-12s %-28s %-20s %s\n", q.Head, polecat, q.Progress(), style.Dim.Render(current))
+	}
+	return nil
+}
+
+func runMicroNext(cmd *cobra.Command, args []string) error {
+	return advanceMicroQueue(microtask.StatusDone, "")
+}
+
+func runMicroSkip(cmd *cobra.Command, args []string) error {
+	return advanceMicroQueue(microtask.StatusSkipped, microSkipReason)
+}
+
+// advanceMicroQueue finishes the agent's current micro-task (done or
+// skipped) and starts the next one that is still open.
+func advanceMicroQueue(status, note string) error {
+	townRoot, cwd, err := microWorkspace()
+	if err != nil {
+		return err
+	}
+	q, err := loadMicroQueue(townRoot, cwd, "")
+	if err != nil {
+		return err
+	}
+	if q.Polecat == "" {
+		q.Polecat = detectSender()
+	}
+	cur := q.Current()
+	if cur == nil {
+		return fmt.Errorf("%w: run gt done to submit the batch", microtask.ErrEmpty)
+	}
+
+	commit := ""
+	now := time.Now()
+	switch {
+	case status == microtask.StatusSkipped && cur.Bead == q.Head:
+		return fmt.Errorf("%s is the head of the queue and carries its branch, so it can't be skipped\n  Finish it, or stop with gt done --status ESCALATED", cur.Bead)
+	case status == microtask.StatusSkipped:
+		if err := microReopenBeadFn(cur.Bead); err != nil {
+			return fmt.Errorf("giving %s back: %w", cur.Bead, err)
+		}
+	default:
+		commit = microHeadCommitFn(cwd)
+		// The head closes when the batch merges; the others are done now.
+		if cur.Bead != q.Head {
+			reason := fmt.Sprintf("Done in micro-task batch %s (commit %s); merges with it", q.Head, shortSHA(commit))
+			if err := microCloseBeadFn(cur.Bead, reason); err != nil {
+				return fmt.Errorf("closing %s: %w", cur.Bead, err)
+			}
+		}
+	}
+
+	finished, next, err := q.Advance(status, commit, note, now)
+	if err != nil {
+		return err
+	}
+	for next != nil {
+		info, err := microBeadInfoFn(next.Bead)
+		if err == nil && info.Status == "open" {
+			if err := microStartBeadFn(next.Bead, q.Polecat); err != nil {
+				style.PrintWarning("could not mark %s in progress: %v", next.Bead, err)
+			}
+			break
+		}
+		why := "not found"
+		if err == nil {
+			why = "no longer open (" + info.Status + ")"
+		}
+		fmt.Printf("  %s %s skipped: %s\n", style.Dim.Render("○"), next.Bead, why)
+		if _, next, err = q.Advance(microtask.StatusSkipped, "", why, now); err != nil {
+			return err
+		}
+	}
+	if err := microtask.Save(townRoot, q); err != nil {
+		return fmt.Errorf("saving micro-task queue: %w", err)
+	}
+
+	if finished.Status == microtask.StatusDone {
+		fmt.Printf("%s %s done %s\n", style.SuccessPrefix, finished.Bead, style.Dim.Render(shortSHA(commit)))
+	} else {
+		fmt.Printf("%s %s given back: %s\n", style.Bold.Render("↩"), finished.Bead, note)
+	}
+	if next == nil {
+		fmt.Printf("\nQueue finished (%s). Run gt done to submit the batch in one merge request.\n", q.Progress())
+		return nil
+	}
+	fmt.Printf("\n%s Next micro-task: %s — %s\n", style.Bold.Render("→"), next.Bead, next.Title)
+	if info, err := microBeadInfoFn(next.Bead); err == nil && info.Description != "" {
+		fmt.Printf("\n%s\n", info.Description)
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("Commit it, then run gt micro next (or gt micro skip -m <why>)."))
+	return nil
+}
+
+// finishMicroQueue closes out the micro-task queue headed by issueID when
+// its polecat runs gt done: tasks it didn't reach go back to the rig, and
+// the queue is removed.
+func finishMicroQueue(townRoot, issueID string) {
+	q, err := microtask.Load(townRoot, issueID)
+	if err != nil || q == nil {
+		return
+	}
+	for _, t := range q.Release(time.Now()) {
+		if err := microReopenBeadFn(t.Bead); err != nil {
+			style.PrintWarning("could not return micro-task %s: %v", t.Bead, err)
+			continue
+		}
+		fmt.Printf("  %s Micro-task %s returned to %s (not reached)\n", style.Dim.Render("↩"), t.Bead, q.Rig)
+	}
+	fmt.Printf("Micro-task batch %s: %s\n", q.Head, q.Progress())
+	if err := microtask.Remove(townRoot, q.Head); err != nil {
+		style.PrintWarning("could not remove micro-task queue %s: %v", q.Head, err)
+	}
+}
+
+func microWorkspace() (townRoot, cwd string, err error) {
+	townRoot, cwd, err = workspace.FindFromCwdWithFallback()
+	if err != nil {
+		return "", "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return townRoot, cwd, nil
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/microtask"
+)
+
+// stubMicroBeads replaces the micro-task bead seams with an in-memory rig
+// and returns the log of bead changes.
+func stubMicroBeads(t *testing.T, hooked string, status map[string]string) *[]string {
+	t.Helper()
+	origHooked, origCommit, origInfo := microHookedBeadFn, microHeadCommitFn, microBeadInfoFn
+	origStart, origClose, origReopen := microStartBeadFn, microCloseBeadFn, microReopenBeadFn
+	t.Cleanup(func() {
+		microHookedBeadFn, microHeadCommitFn, microBeadInfoFn = origHooked, origCommit, origInfo
+		microStartBeadFn, microCloseBeadFn, microReopenBeadFn = origStart, origClose, origReopen
+	})
+
+	var log []string
+	microHookedBeadFn = func(string) string { return hooked }
+	microHeadCommitFn = func(string) string { return "0123456789abcdef" }
+	microBeadInfoFn = func(id string) (*beadInfo, error) {
+		s, ok := status[id]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return &beadInfo{Title: "task " + id, Status: s}, nil
+	}
+	microStartBeadFn = func(id, assignee string) error {
+		status[id] = "in_progress"
+		log = append(log, "start "+id+" "+assignee)
+		return nil
+	}
+	microCloseBeadFn = func(id, reason string) error {
+		status[id] = "closed"
+		log = append(log, "close "+id)
+		return nil
+	}
+	microReopenBeadFn = func(id string) error {
+		status[id] = "open"
+		log = append(log, "reopen "+id)
+		return nil
+	}
+	return &log
+}
+
+func TestAdvanceMicroQueue(t *testing.T) {
+	townRoot := setupTestTownForConfig(t)
+	t.Chdir(townRoot)
+	status := map[string]string{"gt-1": "hooked", "gt-2": "open", "gt-3": "closed", "gt-4": "open"}
+	log := stubMicroBeads(t, "gt-1", status)
+
+	q, err := microtask.New("gastown", []string{"gt-1", "gt-2", "gt-3", "gt-4"}, nil, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Polecat = "gastown/polecats/toast"
+	if err := microtask.Save(townRoot, q); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := advanceMicroQueue(microtask.StatusSkipped, "no"); err == nil || !strings.Contains(err.Error(), "can't be skipped") {
+		t.Errorf("skipping the head = %v", err)
+	}
+
+	// The head stays open for its merge request; gt-2 starts.
+	if err := advanceMicroQueue(microtask.StatusDone, ""); err != nil {
+		t.Fatal(err)
+	}
+	// gt-2 closes; gt-3 was closed by someone else, so gt-4 starts.
+	if err := advanceMicroQueue(microtask.StatusDone, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := advanceMicroQueue(microtask.StatusSkipped, "needs design"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"start gt-2 gastown/polecats/toast", "close gt-2", "start gt-4 gastown/polecats/toast", "reopen gt-4"}
+	if strings.Join(*log, "; ") != strings.Join(want, "; ") {
+		t.Errorf("bead changes = %v, want %v", *log, want)
+	}
+
+	q, err = microtask.Load(townRoot, "gt-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Current() != nil || q.Progress() != "2/4 done, 2 skipped" || q.Tasks[0].Commit != "0123456789abcdef" {
+		t.Errorf("queue = %s, %+v", q.Progress(), q.Tasks[0])
+	}
+	if err := advanceMicroQueue(microtask.StatusDone, ""); !errors.Is(err, microtask.ErrEmpty) {
+		t.Errorf("next on a finished queue = %v, want ErrEmpty", err)
+	}
+}
+
+func TestFinishMicroQueue(t *testing.T) {
+	townRoot := t.TempDir()
+	status := map[string]string{"gt-1": "hooked", "gt-2": "in_progress", "gt-3": "open"}
+	log := stubMicroBeads(t, "gt-1", status)
+
+	q, err := microtask.New("gastown", []string{"gt-1", "gt-2", "gt-3"}, nil, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := q.Advance(microtask.StatusDone, "abc", "", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := microtask.Save(townRoot, q); err != nil {
+		t.Fatal(err)
+	}
+
+	finishMicroQueue(townRoot, "gt-1")
+	if strings.Join(*log, "; ") != "reopen gt-2; reopen gt-3" {
+		t.Errorf("bead changes = %v, want gt-2 and gt-3 returned", *log)
+	}
+	if q, _ := microtask.Load(townRoot, "gt-1"); q != nil {
+		t.Error("finished queue not removed")
+	}
+
+	// Beads without a queue are left alone.
+	*log = nil
+	finishMicroQueue(townRoot, "gt-9")
+	if len(*log) != 0 {
+		t.Errorf("no queue, but beads changed: %v", *log)
+	}
+}
+
+func TestPrepareMicroSling(t *testing.T) {
+	origInfo, origForce := slingMicroBeadInfoFn, slingForce
+	t.Cleanup(func() { slingMicroBeadInfoFn, slingForce = origInfo, origForce })
+	slingForce = true // Skip the cross-rig guard; these beads have no routes
+	status := map[string]string{"gt-1": "open", "gt-2": "open", "gt-3": "hooked"}
+	slingMicroBeadInfoFn = func(id string) (*beadInfo, error) {
+		if s, ok := status[id]; ok {
+			return &beadInfo{Title: "task " + id, Status: s}, nil
+		}
+		return nil, errors.New("not found")
+	}
+	townRoot := t.TempDir()
+
+	if _, err := prepareMicroSling(townRoot, []string{"gt-1"}); err == nil {
+		t.Error("one bead should be refused")
+	}
+	if _, err := prepareMicroSling(townRoot, []string{"gt-1", "gt-3"}); err == nil {
+		t.Error("a hooked bead should be refused")
+	}
+	if _, err := prepareMicroSling(townRoot, []string{"gt-1", "gt-404"}); err == nil {
+		t.Error("a missing bead should be refused")
+	}
+	if _, err := prepareMicroSling(townRoot, []string{"gt-1", "gt-2", "no such rig"}); err == nil {
+		t.Error("an unknown target should be refused")
+	}
+
+	q := &microtask.Queue{Head: "gt-1", Tasks: []*microtask.Task{{Bead: "gt-1"}, {Bead: "gt-2"}, {Bead: "gt-4"}}}
+	got := microSlingInstructions(q)
+	if !strings.Contains(got, "gt-2, gt-4 are queued") || !strings.Contains(got, "gt micro next") {
+		t.Errorf("instructions = %q", got)
+	}
+}
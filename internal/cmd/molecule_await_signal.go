@@ -407,7 +407,7 @@ func parseIntSimple(s string) (int, error) {
 func updateAgentHeartbeat(agentBead, beadsDir string) error {
 	cmd := exec.Command("bd", "agent", "heartbeat", agentBead)
 	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-	return cmd.Run()
+	return beads.RunCmd(cmd)
 }
 
 // setAgentIdleCycles sets the idle:N label on an agent bead.
@@ -441,7 +441,7 @@ func setAgentIdleCycles(agentBead, beadsDir string, cycles int) error {
 	cmd := exec.Command("bd", args...)
 	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		return fmt.Errorf("setting idle label: %w", err)
 	}
 
@@ -473,7 +473,7 @@ func setAgentBackoffUntil(agentBead, beadsDir string, until time.Time) error {
 
 	cmd := exec.Command("bd", args...)
 	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		return fmt.Errorf("setting backoff-until label: %w", err)
 	}
 	return nil
@@ -512,7 +512,7 @@ func clearAgentBackoffUntil(agentBead, beadsDir string) error {
 
 	cmd := exec.Command("bd", args...)
 	cmd.Env = append(os.Environ(), "BEADS_DIR="+beadsDir)
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		return fmt.Errorf("clearing backoff-until label: %w", err)
 	}
 	return nil
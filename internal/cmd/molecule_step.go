@@ -297,7 +297,7 @@ func handleStepContinue(cwd, townRoot string, nextStep *beads.Issue, dryRun bool
 	pinCmd := exec.Command("bd", "update", nextStep.ID, "--status=pinned", "--assignee="+agentID)
 	pinCmd.Dir = gitRoot
 	pinCmd.Stderr = os.Stderr
-	if err := pinCmd.Run(); err != nil {
+	if err := beads.RunCmd(pinCmd); err != nil {
 		return fmt.Errorf("pinning next step: %w", err)
 	}
 
@@ -376,7 +376,7 @@ func handleParallelSteps(cwd, townRoot, _ string, steps []*beads.Issue, dryRun b
 		markCmd := exec.Command("bd", "update", step.ID, "--status=in_progress")
 		markCmd.Dir = gitRoot
 		markCmd.Stderr = os.Stderr
-		if err := markCmd.Run(); err != nil {
+		if err := beads.RunCmd(markCmd); err != nil {
 			style.PrintWarning("could not mark step %s as in_progress: %v", step.ID, err)
 		}
 	}
@@ -470,7 +470,7 @@ func handleMoleculeComplete(cwd, townRoot, moleculeID string, dryRun bool) error
 			unpinCmd := exec.Command("bd", "update", pinnedBeads[0].ID, "--status=open")
 			unpinCmd.Dir = gitRoot
 			unpinCmd.Stderr = os.Stderr
-			if err := unpinCmd.Run(); err != nil {
+			if err := beads.RunCmd(unpinCmd); err != nil {
 				style.PrintWarning("could not unpin bead: %v", err)
 			} else {
 				fmt.Printf("%s Work unpinned\n", style.Bold.Render("✓"))
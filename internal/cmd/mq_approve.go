@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/ownership"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var mqApproveCmd = &cobra.Command{
+	Use:   "approve <rig> <mr-id>",
+	Short: "Approve a merge request as an owner of the paths it changes",
+	Long: `Record your approval of a merge request.
+
+With "ownership": {"require_review": true} in the rig's settings, the
+refinery holds an MR that changes owned paths (per settings/OWNERS or the
+repository's CODEOWNERS on the target branch) until every owning agent
+other than its author has approved. Owners are mailed when an MR starts
+waiting on them. Only an owner the MR is waiting on can approve it; to turn
+it down, use gt mq reject.
+
+Examples:
+  gt mq approve gastown gt-mr-abc123`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMQApprove,
+}
+
+func init() {
+	mqCmd.AddCommand(mqApproveCmd)
+}
+
+func runMQApprove(_ *cobra.Command, args []string) error {
+	rigName, mrID := args[0], args[1]
+
+	_, r, _, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+	b := beads.New(r.BeadsPath())
+	issue, err := b.Show(mrID)
+	if err != nil {
+		return fmt.Errorf("getting merge request %s: %w", mrID, err)
+	}
+	if !beads.HasLabel(issue, "gt:merge-request") {
+		return fmt.Errorf("%s is not a merge request", mrID)
+	}
+
+	pending, err := refinery.NewEngineer(r).PendingOwnerReview(issue)
+	if err != nil {
+		return fmt.Errorf("checking owner review: %w", err)
+	}
+	if len(pending) == 0 {
+		fmt.Printf("%s %s is not waiting on owner review\n", style.Dim.Render("○"), mrID)
+		return nil
+	}
+	approver := detectSender()
+	if !slices.Contains(pending, approver) {
+		return fmt.Errorf("%s is waiting on %s, not %s", mrID, strings.Join(pending, ", "), approver)
+	}
+
+	if err := b.Update(mrID, beads.UpdateOptions{AddLabels: []string{ownership.ApprovalLabel(approver)}}); err != nil {
+		return fmt.Errorf("recording approval: %w", err)
+	}
+	fmt.Printf("%s Approved %s as %s\n", style.Bold.Render("✓"), mrID, approver)
+	if rest := slices.DeleteFunc(pending, func(a string) bool { return a == approver }); len(rest) > 0 {
+		fmt.Printf("  Still waiting on: %s\n", strings.Join(rest, ", "))
+	} else {
+		fmt.Printf("  %s\n", style.Dim.Render("All owners approved; the refinery will pick it up"))
+	}
+	return nil
+}
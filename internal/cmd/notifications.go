@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/notification"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	notificationsLimit    int
+	notificationsJSON     bool
+	notificationsEvent    string
+	notificationsSeverity string
+	notificationsTitle    string
+	notificationsBody     string
+	notificationsKey      string
+	notificationsDNDFor   time.Duration
+)
+
+var notificationsCmd = &cobra.Command{
+	Use:     "notifications",
+	GroupID: GroupComm,
+	Short:   "Desktop notifications and the notification center",
+	Long: `Show the notification center: recent desktop notifications, including
+the ones held back by do-not-disturb, quiet hours or the minimum severity.
+
+Desktop notifications (osascript on macOS, notify-send on Linux) are raised
+for events that are easy to miss in a terminal:
+
+  escalation     gt escalate (low→low, medium→normal, high/critical→critical)
+  approval       mail sent with --type approval (normal)
+  convoy_landed  all of a convoy's issues closed (low)
+  budget         today's session costs reach 80% (normal) and 100%
+                 (critical) of daily_budget_usd
+
+Configure them in settings/config.json:
+
+  "notifications": {
+    "enabled": true,
+    "min_severity": "normal",
+    "events": {"convoy_landed": "off", "approval": "critical"},
+    "daily_budget_usd": 200
+  }
+
+events overrides an event's severity ("off" silences it). Only critical
+notifications are shown during do-not-disturb (gt notifications dnd) and
+the town's quiet hours; the rest wait here.
+
+Examples:
+  gt notifications              # Recent notifications
+  gt notifications --limit 50 --json
+  gt notifications test         # Check desktop notifications work
+  gt notifications dnd on --for 2h
+  gt notifications dnd off`,
+	Args: cobra.NoArgs,
+	RunE: runNotifications,
+}
+
+var notificationsSendCmd = &cobra.Command{
+	Use:   "send",
+	Short: "Raise a notification (for scripts and hooks)",
+	Long: `Raise a notification through the town's notification settings.
+
+The event's configured severity, do-not-disturb and quiet hours apply as
+for built-in events. --key makes the notification one-shot: it is dropped
+if one with the same key was already raised.
+
+Examples:
+  gt notifications send --event convoy_landed --title "Convoy landed: auth" --body "hq-cv-abc"
+  gt notifications send --event budget --severity critical --title "Spend over budget" --key budget-2026-01-02`,
+	Args: cobra.NoArgs,
+	RunE: runNotificationsSend,
+}
+
+var notificationsTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Show a test desktop notification",
+	Long: `Show a test desktop notification and report whether it was delivered
+or held (and why).
+
+Examples:
+  gt notifications test
+  gt notifications test --severity critical`,
+	Args: cobra.NoArgs,
+	RunE: runNotificationsTest,
+}
+
+var notificationsDNDCmd = &cobra.Command{
+	Use:   "dnd [on|off|status]",
+	Short: "Hold non-critical desktop notifications",
+	Long: `Turn do-not-disturb on or off for desktop notifications.
+
+While it is on, only critical notifications are shown; the rest are kept
+in the notification center. --for turns it off again automatically.
+
+This is separate from gt dnd, which mutes an agent's mail nudges.
+
+Examples:
+  gt notifications dnd            # Show status
+  gt notifications dnd on         # Until turned off
+  gt notifications dnd on --for 90m
+  gt notifications dnd off`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runNotificationsDND,
+}
+
+func init() {
+	notificationsCmd.Flags().IntVar(&notificationsLimit, "limit", 20, "Number of notifications to show (0 = all)")
+	notificationsCmd.Flags().BoolVar(&notificationsJSON, "json", false, "Output as JSON")
+
+	notificationsSendCmd.Flags().StringVar(&notificationsEvent, "event", "", "Event name (escalation, approval, convoy_landed, budget)")
+	notificationsSendCmd.Flags().StringVar(&notificationsSeverity, "severity", notification.SeverityNormal, "Default severity: low, normal or critical")
+	notificationsSendCmd.Flags().StringVar(&notificationsTitle, "title", "", "Notification title")
+	notificationsSendCmd.Flags().StringVar(&notificationsBody, "body", "", "Notification body")
+	notificationsSendCmd.Flags().StringVar(&notificationsKey, "key", "", "Raise at most once per key")
+	_ = notificationsSendCmd.MarkFlagRequired("event")
+	_ = notificationsSendCmd.MarkFlagRequired("title")
+
+	notificationsTestCmd.Flags().StringVar(&notificationsSeverity, "severity", notification.SeverityNormal, "Severity: low, normal or critical")
+
+	notificationsDNDCmd.Flags().DurationVar(&notificationsDNDFor, "for", 0, "Turn do-not-disturb off again after this long")
+
+	notificationsCmd.AddCommand(notificationsSendCmd, notificationsTestCmd, notificationsDNDCmd)
+	rootCmd.AddCommand(notificationsCmd)
+}
+
+// raiseNotification raises n for the town. Best-effort: a notification that
+// can't be shown never fails the command that raised it.
+func raiseNotification(townRoot string, n notification.Notification) *notification.Record {
+	now := time.Now()
+	rec, _ := notification.Raise(townRoot, config.LoadNotifications(townRoot), config.LoadQuietHours(townRoot, "").Active(now), n, now)
+	return rec
+}
+
+// escalationNotificationSeverity maps an escalation severity to the
+// desktop notification severity it is raised with.
+func escalationNotificationSeverity(severity string) string {
+	switch severity {
+	case config.SeverityCritical, config.SeverityHigh:
+		return notification.SeverityCritical
+	case config.SeverityMedium:
+		return notification.SeverityNormal
+	default:
+		return notification.SeverityLow
+	}
+}
+
+func runNotifications(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	records, err := notification.List(townRoot, notificationsLimit)
+	if err != nil {
+		return err
+	}
+	if notificationsJSON {
+		out, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+
+	printNotificationStatus(townRoot)
+	if len(records) == 0 {
+		fmt.Printf("\n%s\n", style.Dim.Render("No notifications yet."))
+		return nil
+	}
+	fmt.Println()
+	for _, r := range records {
+		mark := style.Success.Render("●")
+		if !r.Delivered {
+			mark = style.Dim.Render("○")
+		}
+		fmt.Printf("%s %s  %-8s %-13s %s\n", mark, r.Time.Local().Format("01-02 15:04"), r.Severity, r.Event, r.Title)
+		if r.Body != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(r.Body))
+		}
+		if r.Held != "" {
+			fmt.Printf("    %s\n", style.Dim.Render("held: "+r.Held))
+		}
+	}
+	return nil
+}
+
+// printNotificationStatus prints whether notifications are on and what
+// would hold them right now.
+func printNotificationStatus(townRoot string) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		style.PrintWarning("could not load town settings: %v", err)
+		return
+	}
+	cfg := settings.Notifications
+	if err := cfg.Validate(); err != nil {
+		style.PrintWarning("notifications config invalid (notifications are off): %v", err)
+		return
+	}
+	if !cfg.IsEnabled() {
+		fmt.Printf("🔕 Desktop notifications are off %s\n", style.Dim.Render(`(set "notifications": {"enabled": true} in settings/config.json)`))
+		return
+	}
+
+	notifier := notification.Notifier()
+	if notifier == "" {
+		notifier = "none found"
+	}
+	fmt.Printf("🔔 Desktop notifications on %s\n", style.Dim.Render("(notifier: "+notifier+")"))
+	now := time.Now()
+	if on, until := notification.DNDActive(townRoot, now); on {
+		if until.IsZero() {
+			fmt.Println("   Do not disturb: on (only critical notifications shown)")
+		} else {
+			fmt.Printf("   Do not disturb: on until %s (only critical notifications shown)\n", until.Local().Format("15:04"))
+		}
+	}
+	if settings.QuietHours.Active(now) {
+		fmt.Println("   Quiet hours: active (only critical notifications shown)")
+	}
+}
+
+func runNotificationsSend(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	raiseNotification(townRoot, notification.Notification{
+		Event:    notificationsEvent,
+		Severity: notificationsSeverity,
+		Title:    notificationsTitle,
+		Body:     notificationsBody,
+		Key:      notificationsKey,
+	})
+	return nil
+}
+
+func runNotificationsTest(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if !config.LoadNotifications(townRoot).IsEnabled() {
+		printNotificationStatus(townRoot)
+		return fmt.Errorf("notifications are not enabled")
+	}
+	rec := raiseNotification(townRoot, notification.Notification{
+		Event:    notification.EventTest,
+		Severity: notificationsSeverity,
+		Title:    "Test notification",
+		Body:     "Desktop notifications are working.",
+	})
+	switch {
+	case rec == nil:
+		return fmt.Errorf("notification was not raised")
+	case rec.Delivered:
+		fmt.Printf("%s Test notification shown\n", style.SuccessPrefix)
+	default:
+		fmt.Printf("%s Test notification held: %s\n", style.WarningPrefix, rec.Held)
+	}
+	return nil
+}
+
+func runNotificationsDND(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	action := "status"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "on":
+		var until time.Time
+		if notificationsDNDFor > 0 {
+			until = time.Now().Add(notificationsDNDFor)
+		}
+		if err := notification.SetDND(townRoot, until); err != nil {
+			return fmt.Errorf("enabling do not disturb: %w", err)
+		}
+		if until.IsZero() {
+			fmt.Printf("%s Do not disturb on - only critical notifications shown\n", style.SuccessPrefix)
+			fmt.Printf("  Run %s to resume notifications\n", style.Bold.Render("gt notifications dnd off"))
+		} else {
+			fmt.Printf("%s Do not disturb on until %s\n", style.SuccessPrefix, until.Format("15:04"))
+		}
+
+	case "off":
+		if err := notification.ClearDND(townRoot); err != nil {
+			return fmt.Errorf("disabling do not disturb: %w", err)
+		}
+		fmt.Printf("%s Do not disturb off\n", style.SuccessPrefix)
+		if held := heldNotificationCount(townRoot); held > 0 {
+			fmt.Printf("  %d held notification(s): %s\n", held, style.Bold.Render("gt notifications"))
+		}
+
+	case "status":
+		on, until := notification.DNDActive(townRoot, time.Now())
+		switch {
+		case !on:
+			fmt.Println("🔔 Do not disturb: off")
+		case until.IsZero():
+			fmt.Println("🔕 Do not disturb: on")
+		default:
+			fmt.Printf("🔕 Do not disturb: on until %s\n", until.Format("15:04"))
+		}
+
+	default:
+		return fmt.Errorf("unknown action %q: use on, off, or status", action)
+	}
+	return nil
+}
+
+// heldNotificationCount counts notifications held by do-not-disturb since
+// the last one that was shown.
+func heldNotificationCount(townRoot string) int {
+	records, err := notification.List(townRoot, 0)
+	if err != nil {
+		style.PrintWarning("reading notifications: %v", err)
+		return 0
+	}
+	n := 0
+	for _, r := range records {
+		if r.Delivered {
+			break
+		}
+		if r.Held == notification.HeldDND {
+			n++
+		}
+	}
+	return n
+}
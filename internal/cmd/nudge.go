@@ -413,6 +413,8 @@ func runNudge(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
+	sender = attributeOperator(sender)
+
 	// Handle channel syntax: channel:<name>
 	if strings.HasPrefix(target, "channel:") {
 		channelName := strings.TrimPrefix(target, "channel:")
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/observe"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// maxObserveLines caps the lines shown for one agent message; tool output
+// gets fewer, it is rarely what an observer is after.
+const (
+	maxObserveLines       = 12
+	maxObserveResultLines = 3
+)
+
+// observePollInterval is how often the transcripts and event log are read.
+const observePollInterval = time.Second
+
+var (
+	observeSince    string
+	observeNoFollow bool
+	observeJSON     bool
+	observeThinking bool
+)
+
+var observeCmd = &cobra.Command{
+	Use:     "observe <rig>",
+	GroupID: GroupDiag,
+	Short:   "Watch a rig's agents and events, read-only",
+	Long: `Follow what a rig's agents are doing, for stakeholders who want to
+watch without any risk of interfering.
+
+The view merges the rig's feed events with the conversation transcripts
+of its witness, refinery, polecats and crew, in time order. It only reads
+the transcripts and the event log: it never attaches to an agent's tmux
+session and runs no agent of its own, so it has no way to send input and
+costs no tokens. New polecats and sessions show up as they start.
+
+--json writes one entry per line for an external viewer or dashboard.
+
+Examples:
+  gt observe gastown                      # Last 15 minutes, then follow
+  gt observe gastown --since 2h --no-follow
+  gt observe gastown --json | my-viewer`,
+	Args: cobra.ExactArgs(1),
+	RunE: runObserve,
+}
+
+func init() {
+	observeCmd.Flags().StringVar(&observeSince, "since", "15m", "Show activity since this long ago (e.g. 30m, 2h, 1d)")
+	observeCmd.Flags().BoolVar(&observeNoFollow, "no-follow", false, "Show recent activity and exit")
+	observeCmd.Flags().BoolVar(&observeJSON, "json", false, "Write entries as JSON lines")
+	observeCmd.Flags().BoolVar(&observeThinking, "thinking", false, "Include the agents' thinking blocks")
+	rootCmd.AddCommand(observeCmd)
+}
+
+func runObserve(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	since, err := parseDuration(observeSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+
+	o := observe.New(townRoot, r.Name, r.Path, time.Now().Add(-since))
+	if !observeJSON {
+		fmt.Printf("%s Observing %s (read-only) %s\n\n", style.Bold.Render("👁"), r.Name,
+			style.Dim.Render("— since "+observeSince+", Ctrl-C to stop"))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ticker := time.NewTicker(observePollInterval)
+	defer ticker.Stop()
+	for {
+		entries, err := o.Poll()
+		if err != nil {
+			return err
+		}
+		if err := writeObserveEntries(os.Stdout, entries, observeJSON, observeThinking); err != nil {
+			return err
+		}
+		if observeNoFollow {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeObserveEntries writes entries as JSON lines or as the text view.
+func writeObserveEntries(w io.Writer, entries []observe.Entry, asJSON, thinking bool) error {
+	enc := json.NewEncoder(w)
+	for _, e := range entries {
+		if e.Kind == "thinking" && !thinking {
+			continue
+		}
+		if asJSON {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+			continue
+		}
+		if line := renderObserveEntry(e); line != "" {
+			if _, err := io.WriteString(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// renderObserveEntry renders one entry as a timestamped block: events and
+// tool calls on one line, messages cut short, tool output dimmed.
+func renderObserveEntry(e observe.Entry) string {
+	text := strings.TrimSpace(e.Text)
+	if text == "" {
+		return ""
+	}
+	var body string
+	switch e.Kind {
+	case observe.KindEvent:
+		body = style.Dim.Render("→ ") + text
+	case "tool_use":
+		name, input, _ := strings.Cut(text, ": ")
+		if r := []rune(input); len(r) > maxCastToolInput {
+			input = string(r[:maxCastToolInput]) + "…"
+		}
+		body = style.Warning.Render("● "+name) + " " + style.Dim.Render(input)
+	case "tool_result":
+		body = style.Dim.Render("⎿ " + truncateCastLines(text, maxObserveResultLines))
+	case "thinking":
+		body = style.Dim.Render(truncateCastLines(text, maxObserveLines))
+	default:
+		if e.Role == "user" {
+			body = style.Bold.Render("❯ ") + truncateCastLines(text, maxObserveLines)
+		} else {
+			body = truncateCastLines(text, maxObserveLines)
+		}
+	}
+	prefix := fmt.Sprintf("%s %-24s ", e.At.Local().Format("15:04:05"), e.Agent)
+	indent := strings.Repeat(" ", len(prefix))
+	return style.Dim.Render(prefix[:9]) + prefix[9:] + strings.ReplaceAll(body, "\n", "\n"+indent) + "\n"
+}
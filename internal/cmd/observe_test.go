@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/observe"
+)
+
+func TestWriteObserveEntries(t *testing.T) {
+	at := time.Date(2026, 1, 2, 10, 0, 0, 0, time.Local)
+	entries := []observe.Entry{
+		{At: at, Agent: "mayor", Kind: observe.KindEvent, Text: "slung gt-1 to gastown/Toast"},
+		{At: at, Agent: "gastown/Toast", Kind: "thinking", Role: "assistant", Text: "hmm"},
+		{At: at, Agent: "gastown/Toast", Kind: "text", Role: "assistant", Text: "Looking at it\nsecond line"},
+		{At: at, Agent: "gastown/Toast", Kind: "tool_use", Role: "assistant", Text: `Bash: {"command":"go test"}`},
+	}
+
+	var buf bytes.Buffer
+	if err := writeObserveEntries(&buf, entries, false, false); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"10:00:00", "slung gt-1 to gastown/Toast", "Looking at it\n", "second line", "Bash"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("text view missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "hmm") {
+		t.Errorf("thinking shown without --thinking:\n%s", out)
+	}
+	if lines := strings.Split(out, "\n"); !strings.HasPrefix(strings.TrimLeft(lines[2], " "), "second line") {
+		t.Errorf("continuation line not indented: %q", lines[2])
+	}
+
+	buf.Reset()
+	if err := writeObserveEntries(&buf, entries, true, true); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("json lines = %d, want 4", len(lines))
+	}
+	var e observe.Entry
+	if err := json.Unmarshal([]byte(lines[1]), &e); err != nil || e.Kind != "thinking" || e.Agent != "gastown/Toast" {
+		t.Errorf("json entry = %+v, %v", e, err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -190,7 +191,7 @@ func queryPatrolDigests(targetDate time.Time) ([]PatrolCycleEntry, error) {
 		"--json",
 		"--limit=0", // Get all
 	)
-	listOutput, err := listCmd.Output()
+	listOutput, err := beads.CmdOutput(listCmd)
 	if err != nil {
 		if patrolDigestVerbose {
 			fmt.Fprintf(os.Stderr, "[patrol] bd list failed: %v\n", err)
@@ -306,7 +307,7 @@ func createPatrolDigestBead(digest PatrolDigest) (string, error) {
 	}
 
 	bdCmd := exec.Command("bd", bdArgs...)
-	output, err := bdCmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(bdCmd)
 	if err != nil {
 		return "", fmt.Errorf("creating digest bead: %w\nOutput: %s", err, string(output))
 	}
@@ -315,7 +316,7 @@ func createPatrolDigestBead(digest PatrolDigest) (string, error) {
 
 	// Auto-close the digest (it's an audit record, not work)
 	closeCmd := exec.Command("bd", "close", digestID, "--reason=daily patrol digest")
-	_ = closeCmd.Run() // Best effort
+	_ = beads.RunCmd(closeCmd) // Best effort
 
 	return digestID, nil
 }
@@ -331,7 +332,7 @@ func findExistingPatrolDigest(dateStr string) (string, error) {
 		"--json",
 		"--limit=50", // Recent events only
 	)
-	listOutput, err := listCmd.Output()
+	listOutput, err := beads.CmdOutput(listCmd)
 	if err != nil {
 		return "", err
 	}
@@ -375,7 +376,7 @@ func deletePatrolDigests(targetDate time.Time) (int, error) {
 	// Delete in batch
 	deleteArgs := append([]string{"delete", "--force"}, idsToDelete...)
 	deleteCmd := exec.Command("bd", deleteArgs...)
-	if err := deleteCmd.Run(); err != nil {
+	if err := beads.RunCmd(deleteCmd); err != nil {
 		return 0, fmt.Errorf("deleting patrol digests: %w", err)
 	}
 
@@ -260,7 +260,7 @@ func autoSpawnPatrol(cfg PatrolConfig) (string, error) {
 	cmdSpawn.Stdout = &stdoutSpawn
 	cmdSpawn.Stderr = &stderrSpawn
 
-	if err := cmdSpawn.Run(); err != nil {
+	if err := beads.RunCmd(cmdSpawn); err != nil {
 		return "", fmt.Errorf("failed to create patrol wisp: %s", stderrSpawn.String())
 	}
 
@@ -54,6 +54,37 @@ func TestBuildRefineryPatrolVars_MissingSettings(t *testing.T) {
 	}
 }
 
+func TestBuildRefineryPatrolVars_BatchMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	rigDir := filepath.Join(tmpDir, "testrig")
+	if err := os.MkdirAll(rigDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	ctx := RoleContext{TownRoot: tmpDir, Rig: "testrig"}
+
+	for _, tc := range []struct {
+		batch string
+		want  bool
+	}{
+		{`{"max_batch_size":4}`, true},
+		{`{"max_batch_size":1}`, false},
+	} {
+		data := `{"type":"rig","name":"testrig","merge_queue":{"batch":` + tc.batch + `}}`
+		if err := os.WriteFile(filepath.Join(rigDir, "config.json"), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got := false
+		for _, v := range buildRefineryPatrolVars(ctx) {
+			if v == "batch_mode=true" {
+				got = true
+			}
+		}
+		if got != tc.want {
+			t.Errorf("batch %s: batch_mode injected = %v, want %v", tc.batch, got, tc.want)
+		}
+	}
+}
+
 func TestBuildRefineryPatrolVars_NilMergeQueue(t *testing.T) {
 	tmpDir := t.TempDir()
 	rigDir := filepath.Join(tmpDir, "testrig")
@@ -4,23 +4,35 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tui/feed"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // Peek command flags
-var peekLines int
+var (
+	peekLines      int
+	peekMulti      bool
+	peekConvoy     string
+	peekStallAfter time.Duration
+	peekInterval   time.Duration
+)
 
 func init() {
 	rootCmd.AddCommand(peekCmd)
 	peekCmd.Flags().IntVarP(&peekLines, "lines", "n", 100, "Number of lines to capture")
+	peekCmd.Flags().BoolVar(&peekMulti, "multi", false, "Tiled live view of several agents (by rig or --convoy)")
+	peekCmd.Flags().StringVar(&peekConvoy, "convoy", "", "With --multi: show the agents working on a convoy's issues")
+	peekCmd.Flags().DurationVar(&peekStallAfter, "stall-after", time.Duration(feed.StalledThresholdMinutes)*time.Minute, "With --multi: highlight agents whose pane has been idle this long")
+	peekCmd.Flags().DurationVar(&peekInterval, "interval", 2*time.Second, "With --multi: refresh interval")
 }
 
 var peekCmd = &cobra.Command{
-	Use:     "peek <rig/polecat> [count]",
+	Use:     "peek <rig/polecat> [count] | --multi [rig...]",
 	GroupID: GroupComm,
 	Short:   "View recent output from a polecat or crew session",
 	Long: `Capture and display recent terminal output from an agent session.
@@ -37,18 +49,41 @@ Supports polecats, crew workers, and town-level agents:
   - Crew: rig/crew/name format (e.g., beads/crew/dave)
   - Town-level: mayor, deacon, boot (or hq/mayor, hq/deacon, hq/boot)
 
+With --multi, several agents are shown side by side in a tiled, read-only
+view that refreshes every --interval. Pick the agents by rig (every
+polecat and crew session in each rig named) or with --convoy (the agents
+assigned to the convoy's open issues). Agents whose pane has been idle for
+--stall-after are outlined in red with the probable cause (permission
+prompt, rate limit, question, ...). Arrow keys move the focus, enter zooms
+into the focused agent. When output isn't a terminal, one capture of each
+agent is printed instead.
+
 Examples:
   gt peek greenplace/furiosa         # Polecat: last 100 lines (default)
   gt peek greenplace/furiosa 50      # Polecat: last 50 lines
   gt peek beads/crew/dave            # Crew: last 100 lines
   gt peek beads/crew/dave -n 200     # Crew: last 200 lines
   gt peek mayor                      # Mayor: last 100 lines
-  gt peek deacon -n 50               # Deacon: last 50 lines`,
-	Args: cobra.RangeArgs(1, 2),
+  gt peek deacon -n 50               # Deacon: last 50 lines
+  gt peek --multi gastown            # Tiled view of gastown's agents
+  gt peek --multi gastown beads      # Agents of both rigs
+  gt peek --multi --convoy hq-cv-abc # Agents working on a convoy`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if peekMulti {
+			return nil
+		}
+		return cobra.RangeArgs(1, 2)(cmd, args)
+	},
 	RunE: runPeek,
 }
 
 func runPeek(cmd *cobra.Command, args []string) error {
+	if peekMulti {
+		return runPeekMulti(args)
+	}
+	if peekConvoy != "" {
+		return fmt.Errorf("--convoy requires --multi")
+	}
 	address := args[0]
 
 	// Handle optional positional count argument
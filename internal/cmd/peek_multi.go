@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tui/peek"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// runPeekMulti shows several agents in the tiled peek view.
+func runPeekMulti(rigNames []string) error {
+	if len(rigNames) == 0 && peekConvoy == "" {
+		return fmt.Errorf("--multi needs at least one rig or --convoy")
+	}
+	if _, err := workspace.FindFromCwdOrError(); err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var targets []peek.Target
+	for _, rigName := range rigNames {
+		rigTargets, err := peekRigTargets(rigName)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, rigTargets...)
+	}
+	if peekConvoy != "" {
+		convoyTargets, err := peekConvoyTargets(peekConvoy)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, convoyTargets...)
+	}
+	targets = dedupePeekTargets(targets)
+	if len(targets) == 0 {
+		return fmt.Errorf("no running agent sessions to show")
+	}
+
+	t := tmux.NewTmux()
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		fmt.Print(peek.RenderPlain(peek.Capture(t, targets, peekLines, peekStallAfter, time.Now()), peekLines))
+		return nil
+	}
+	m := peek.New(t, targets, peekLines, peekStallAfter, peekInterval)
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+// peekRigTargets returns a rig's running polecat and crew sessions.
+func peekRigTargets(rigName string) ([]peek.Target, error) {
+	mgr, _, err := getSessionManager(rigName)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := mgr.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions for %s: %w", rigName, err)
+	}
+	var targets []peek.Target
+	for _, info := range infos {
+		name := info.Polecat
+		switch {
+		case name == "witness" || name == "refinery":
+			continue
+		case strings.HasPrefix(name, "crew-"):
+			name = "crew/" + strings.TrimPrefix(name, "crew-")
+		}
+		targets = append(targets, peek.Target{Address: rigName + "/" + name, Session: info.SessionID})
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Address < targets[j].Address })
+	return targets, nil
+}
+
+// peekConvoyTargets returns the agents working on a convoy's open issues.
+func peekConvoyTargets(convoyID string) ([]peek.Target, error) {
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return nil, err
+	}
+	tracked, err := getTrackedIssues(townBeads, convoyID)
+	if err != nil {
+		return nil, err
+	}
+	var targets []peek.Target
+	for _, issue := range tracked {
+		if issue.Status == "closed" {
+			continue
+		}
+		agent := issue.Worker
+		if agent == "" {
+			agent = issue.Assignee
+		}
+		if target, ok := peekTargetForAgent(agent); ok {
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
+
+// peekTargetForAgent maps an agent identity as it appears in an assignee or
+// agent bead (gastown/polecats/nux, gastown/polecat/nux, gastown/crew/max)
+// to its session. Rig-level singletons and town agents are not work
+// assignees and are skipped.
+func peekTargetForAgent(agent string) (peek.Target, bool) {
+	parts := strings.Split(agent, "/")
+	switch {
+	case len(parts) == 2 && parts[1] != "witness" && parts[1] != "refinery":
+		parts = []string{parts[0], "polecats", parts[1]}
+	case len(parts) != 3:
+		return peek.Target{}, false
+	}
+	rigName, role, name := parts[0], parts[1], parts[2]
+	if rigName == "" || name == "" {
+		return peek.Target{}, false
+	}
+	prefix := session.PrefixFor(rigName)
+	switch role {
+	case "polecats", "polecat":
+		return peek.Target{Address: rigName + "/" + name, Session: session.PolecatSessionName(prefix, name)}, true
+	case "crew":
+		return peek.Target{Address: rigName + "/crew/" + name, Session: session.CrewSessionName(prefix, name)}, true
+	}
+	return peek.Target{}, false
+}
+
+// dedupePeekTargets drops repeated sessions, keeping the first.
+func dedupePeekTargets(targets []peek.Target) []peek.Target {
+	seen := make(map[string]bool, len(targets))
+	var out []peek.Target
+	for _, t := range targets {
+		if seen[t.Session] {
+			continue
+		}
+		seen[t.Session] = true
+		out = append(out, t)
+	}
+	return out
+}
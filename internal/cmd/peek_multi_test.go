@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tui/peek"
+)
+
+func TestPeekTargetForAgent(t *testing.T) {
+	prefix := session.PrefixFor("gastown")
+	tests := []struct {
+		agent string
+		want  peek.Target
+		ok    bool
+	}{
+		{"gastown/polecats/nux", peek.Target{Address: "gastown/nux", Session: session.PolecatSessionName(prefix, "nux")}, true},
+		{"gastown/polecat/nux", peek.Target{Address: "gastown/nux", Session: session.PolecatSessionName(prefix, "nux")}, true},
+		{"gastown/nux", peek.Target{Address: "gastown/nux", Session: session.PolecatSessionName(prefix, "nux")}, true},
+		{"gastown/crew/max", peek.Target{Address: "gastown/crew/max", Session: session.CrewSessionName(prefix, "max")}, true},
+		{"gastown/witness", peek.Target{}, false},
+		{"mayor", peek.Target{}, false},
+		{"", peek.Target{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := peekTargetForAgent(tt.agent)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("peekTargetForAgent(%q) = %+v, %v; want %+v, %v", tt.agent, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDedupePeekTargets(t *testing.T) {
+	got := dedupePeekTargets([]peek.Target{
+		{Address: "gastown/nux", Session: "gt-nux"},
+		{Address: "gastown/polecats/nux", Session: "gt-nux"},
+		{Address: "gastown/crew/max", Session: "gt-crew-max"},
+	})
+	if len(got) != 2 || got[0].Address != "gastown/nux" {
+		t.Errorf("dedupePeekTargets = %+v", got)
+	}
+}
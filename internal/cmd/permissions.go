@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/auditlog"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/permprompt"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	permissionsRig    string
+	permissionsDryRun bool
+	permissionsQuiet  bool
+	permissionsJSON   bool
+	permissionsKind   string
+)
+
+var permissionsCmd = &cobra.Command{
+	Use:     "permissions",
+	GroupID: GroupAgents,
+	Short:   "Auto-answer agent permission prompts by rig policy",
+	RunE:    requireSubcommand,
+	Long: `Answer runtime permission prompts in agent sessions according to policy.
+
+Agents stop at tool-permission dialogs ("Do you want to proceed?") for
+shell commands, file writes and other tools. A rig's permission_policy in
+settings/config.json lists which of those gt may answer for you:
+
+  "permission_policy": {
+    "enabled": true,
+    "allow_commands": ["go test *", "go build *", "make lint"],
+    "deny_commands": ["git push --force*", "rm -rf *"],
+    "allow_writes": ["*"],
+    "deny_writes": [".github/*", "*.env"],
+    "allow_tools": ["WebFetch"],
+    "unmatched": "ask"
+  }
+
+Deny rules win over allow rules. Compound commands are approved only when
+every part is allowed, and commands using substitution are never approved.
+Prompts the policy doesn't cover are left for a human ("ask", the default)
+or rejected ("deny").
+
+Every automatic answer is written to the audit log:
+  gt audit list --command permissions
+
+The daemon's permission_responder patrol runs gt permissions respond every
+minute when enabled.`,
+}
+
+var permissionsRespondCmd = &cobra.Command{
+	Use:   "respond",
+	Short: "Answer pending permission prompts the policy covers",
+	Long: `Scan polecat and crew sessions for open permission prompts and answer
+the ones the rig's permission_policy decides. Approved prompts are
+accepted; denied prompts are dismissed and the agent is told which rule
+refused it. Prompts the policy leaves to a human are reported only.
+
+Examples:
+  gt permissions respond                  # All rigs with a policy enabled
+  gt permissions respond --rig greenplace
+  gt permissions respond --dry-run        # Show decisions without answering`,
+	Args: cobra.NoArgs,
+	RunE: runPermissionsRespond,
+}
+
+var permissionsCheckCmd = &cobra.Command{
+	Use:   "check <rig> <subject>",
+	Short: "Show how a rig's policy would answer a prompt",
+	Long: `Evaluate a rig's permission_policy against a command, file path or
+tool name without touching any session.
+
+Examples:
+  gt permissions check greenplace "go test ./..."
+  gt permissions check greenplace "go test ./... && git push --force"
+  gt permissions check greenplace .github/workflows/ci.yml --kind write
+  gt permissions check greenplace WebFetch --kind tool`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPermissionsCheck,
+}
+
+var (
+	// permissionsWorkersFn is a seam for tests. Production uses standupWorkers.
+	permissionsWorkersFn = standupWorkers
+
+	// permissionsCaptureFn is a seam for tests. Production captures the last 40
+	// pane lines over tmux.
+	permissionsCaptureFn = func(sessionName string) ([]string, error) {
+		return tmux.NewTmux().CapturePaneLines(sessionName, 40)
+	}
+
+	// permissionsAnswerFn is a seam for tests. Production uses
+	// answerPermissionPrompt.
+	permissionsAnswerFn = answerPermissionPrompt
+)
+
+func init() {
+	permissionsRespondCmd.Flags().StringVar(&permissionsRig, "rig", "", "Only answer prompts in this rig")
+	permissionsRespondCmd.Flags().BoolVar(&permissionsDryRun, "dry-run", false, "Show decisions without answering")
+	permissionsRespondCmd.Flags().BoolVarP(&permissionsQuiet, "quiet", "q", false, "Only print prompts that were answered")
+	permissionsRespondCmd.Flags().BoolVar(&permissionsJSON, "json", false, "Output as JSON")
+	permissionsCheckCmd.Flags().StringVar(&permissionsKind, "kind", permprompt.KindCommand, "Prompt kind: command, write or tool")
+
+	permissionsCmd.AddCommand(permissionsRespondCmd)
+	permissionsCmd.AddCommand(permissionsCheckCmd)
+	rootCmd.AddCommand(permissionsCmd)
+}
+
+// loadPermissionPolicy returns the rig's permission_policy, or nil if none
+// is configured.
+func loadPermissionPolicy(townRoot, rigName string) (*permprompt.Config, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rigName)))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return settings.PermissionPolicy, nil
+}
+
+// permissionAnswer is the outcome for one agent's open prompt.
+type permissionAnswer struct {
+	Agent    string             `json:"agent"`
+	Prompt   *permprompt.Prompt `json:"prompt"`
+	Decision string             `json:"decision"`
+	Rule     string             `json:"rule,omitempty"`
+	Answered bool               `json:"answered"`
+	Error    string             `json:"error,omitempty"`
+}
+
+func runPermissionsRespond(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	workers, err := permissionsWorkersFn()
+	if err != nil {
+		return err
+	}
+
+	policies := make(map[string]*permprompt.Config)
+	var answers []permissionAnswer
+	for _, agent := range workers {
+		if permissionsRig != "" && agent.Rig != permissionsRig {
+			continue
+		}
+		policy, seen := policies[agent.Rig]
+		if !seen {
+			if policy, err = loadPermissionPolicy(townRoot, agent.Rig); err != nil {
+				style.PrintWarning("%s: %v", agent.Rig, err)
+			}
+			policies[agent.Rig] = policy
+		}
+		if !policy.IsEnabled() {
+			continue
+		}
+		if a := respondToPermissionPrompt(townRoot, agent.Name, formatAgentName(agent), policy, permissionsDryRun); a != nil {
+			answers = append(answers, *a)
+		}
+	}
+
+	if permissionsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(answers)
+	}
+	for _, a := range answers {
+		if permissionsQuiet && !a.Answered {
+			continue
+		}
+		printPermissionAnswer(a, permissionsDryRun)
+	}
+	if len(answers) == 0 && !permissionsQuiet {
+		fmt.Println("No open permission prompts.")
+	}
+	return nil
+}
+
+// respondToPermissionPrompt checks one session for an open prompt and, unless
+// dryRun, answers it per the policy. Returns nil when no prompt is showing.
+// Every answer, successful or not, is recorded in the audit log.
+func respondToPermissionPrompt(townRoot, sessionName, agent string, policy *permprompt.Config, dryRun bool) *permissionAnswer {
+	lines, err := permissionsCaptureFn(sessionName)
+	if err != nil {
+		return nil
+	}
+	prompt, ok := permprompt.Detect(lines)
+	if !ok {
+		return nil
+	}
+	decision, rule := policy.Decide(prompt)
+	a := &permissionAnswer{Agent: agent, Prompt: prompt, Decision: decision, Rule: rule}
+	if decision == permprompt.Ask || dryRun {
+		return a
+	}
+
+	start := time.Now()
+	err = permissionsAnswerFn(sessionName, decision, prompt, rule)
+	entry := &auditlog.Entry{
+		Actor:      "permissions",
+		Command:    "permissions " + decision,
+		Args:       []string{agent, prompt.Kind, prompt.Subject, rule},
+		Result:     auditlog.ResultOK,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		entry.Result, entry.Error = auditlog.ResultError, err.Error()
+		a.Error = err.Error()
+	} else {
+		a.Answered = true
+	}
+	_ = auditlog.Record(townRoot, entry)
+	return a
+}
+
+// answerPermissionPrompt sends the keys for a decision. The dialog's first
+// option ("Yes") is selected by default, so Enter approves; Escape rejects,
+// after which the agent is told which rule refused it so it can find
+// another way instead of retrying.
+func answerPermissionPrompt(sessionName, decision string, prompt *permprompt.Prompt, rule string) error {
+	t := tmux.NewTmux()
+	switch decision {
+	case permprompt.Approve:
+		return t.SendKeysRaw(sessionName, "Enter")
+	case permprompt.Deny:
+		if err := t.SendKeysRaw(sessionName, "Escape"); err != nil {
+			return err
+		}
+		msg := fmt.Sprintf("Permission policy denied %s %q", prompt.Kind, prompt.Subject)
+		if rule != "" {
+			msg += fmt.Sprintf(" (rule %q)", rule)
+		}
+		msg += ". Don't retry it; find another way or use gt escalate if you're blocked."
+		return t.NudgeSession(sessionName, msg)
+	}
+	return fmt.Errorf("unknown decision %q", decision)
+}
+
+func printPermissionAnswer(a permissionAnswer, dryRun bool) {
+	var mark string
+	switch {
+	case a.Error != "":
+		mark = style.Error.Render("✗ " + a.Decision)
+	case a.Decision == permprompt.Approve:
+		mark = style.Success.Render("✓ " + a.Decision)
+	case a.Decision == permprompt.Deny:
+		mark = style.Warning.Render("✗ " + a.Decision)
+	default:
+		mark = style.Dim.Render("? " + a.Decision)
+	}
+	fmt.Printf("%s %s: %s %s", mark, a.Agent, a.Prompt.Kind, a.Prompt.Subject)
+	if a.Rule != "" {
+		fmt.Printf(" %s", style.Dim.Render("("+a.Rule+")"))
+	}
+	if dryRun && a.Decision != permprompt.Ask {
+		fmt.Printf(" %s", style.Dim.Render("[dry run]"))
+	}
+	if a.Error != "" {
+		fmt.Printf(" %s", style.Error.Render(a.Error))
+	}
+	fmt.Println()
+}
+
+func runPermissionsCheck(cmd *cobra.Command, args []string) error {
+	switch permissionsKind {
+	case permprompt.KindCommand, permprompt.KindWrite, permprompt.KindTool:
+	default:
+		return fmt.Errorf("--kind must be command, write or tool")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	policy, err := loadPermissionPolicy(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	if !policy.IsEnabled() {
+		fmt.Printf("%s has no permission_policy enabled; every prompt is left for a human.\n", args[0])
+		return nil
+	}
+	prompt := &permprompt.Prompt{Kind: permissionsKind, Subject: strings.TrimSpace(args[1])}
+	decision, rule := policy.Decide(prompt)
+	printPermissionAnswer(permissionAnswer{Agent: args[0], Prompt: prompt, Decision: decision, Rule: rule}, false)
+	return nil
+}
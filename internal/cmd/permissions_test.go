@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/auditlog"
+	"github.com/steveyegge/gastown/internal/permprompt"
+)
+
+func TestRespondToPermissionPrompt(t *testing.T) {
+	townRoot := setupTestTownForConfig(t)
+	t.Chdir(townRoot)
+	prompt := []string{
+		"│ Bash command                │",
+		"│   go test ./...             │",
+		"│ Do you want to proceed?     │",
+		"│ ❯ 1. Yes                    │",
+	}
+	origCapture, origAnswer := permissionsCaptureFn, permissionsAnswerFn
+	t.Cleanup(func() { permissionsCaptureFn, permissionsAnswerFn = origCapture, origAnswer })
+	permissionsCaptureFn = func(string) ([]string, error) { return prompt, nil }
+	var sent []string
+	permissionsAnswerFn = func(sessionName, decision string, _ *permprompt.Prompt, _ string) error {
+		sent = append(sent, sessionName+":"+decision)
+		return nil
+	}
+
+	policy := &permprompt.Config{Enabled: true, AllowCommands: []string{"go test *"}}
+
+	// Dry run decides but neither answers nor audits.
+	a := respondToPermissionPrompt(townRoot, "gt-gp-Toast", "greenplace/Toast", policy, true)
+	if a == nil || a.Decision != permprompt.Approve || a.Answered || len(sent) != 0 {
+		t.Fatalf("dry run: answer = %+v, sent = %v", a, sent)
+	}
+
+	a = respondToPermissionPrompt(townRoot, "gt-gp-Toast", "greenplace/Toast", policy, false)
+	if a == nil || !a.Answered || a.Rule != "go test *" {
+		t.Fatalf("answer = %+v, want approved by go test *", a)
+	}
+	if len(sent) != 1 || sent[0] != "gt-gp-Toast:approve" {
+		t.Errorf("sent = %v, want one approve", sent)
+	}
+	entries, err := auditlog.Read(townRoot, auditlog.Filter{Command: "permissions"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Command != "permissions approve" || entries[0].Args[0] != "greenplace/Toast" {
+		t.Errorf("audit entries = %+v, want one permissions approve for greenplace/Toast", entries)
+	}
+
+	// Prompts the policy doesn't cover are left alone.
+	sent = nil
+	strict := &permprompt.Config{Enabled: true, AllowCommands: []string{"make lint"}}
+	a = respondToPermissionPrompt(townRoot, "gt-gp-Toast", "greenplace/Toast", strict, false)
+	if a == nil || a.Decision != permprompt.Ask || a.Answered || len(sent) != 0 {
+		t.Errorf("uncovered prompt: answer = %+v, sent = %v", a, sent)
+	}
+
+	// No prompt on screen.
+	permissionsCaptureFn = func(string) ([]string, error) { return []string{"⏺ Working"}, nil }
+	if a := respondToPermissionPrompt(townRoot, "gt-gp-Toast", "greenplace/Toast", policy, false); a != nil {
+		t.Errorf("no prompt: answer = %+v, want nil", a)
+	}
+}
@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/ping"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	pingTimeout    time.Duration
+	pingJSON       bool
+	pingStatsSince time.Duration
+	pingStatsJSON  bool
+)
+
+var pingCmd = &cobra.Command{
+	Use:     "ping <agent>...",
+	GroupID: GroupDiag,
+	Short:   "Measure an agent's round-trip responsiveness",
+	Long: `Probe agent sessions and measure time to acknowledgment.
+
+Each agent is nudged with a token and asked to run 'gt ping ack <token>'.
+The time until it does is the round-trip latency. Results tell a slow
+model apart from a dead session:
+
+  ok          acknowledged; latency reported
+  timeout     session and agent process alive, no ack in time (slow or wedged)
+  no-agent    tmux session exists but the agent process is gone
+  no-session  no tmux session
+
+Every probe is recorded in .runtime/ping/ and summarized as latency
+percentiles per agent by 'gt ping stats' and in 'gt status --verbose'.
+Exits 1 unless every agent acknowledged.
+
+Agent addresses: mayor, deacon, <rig>/witness, <rig>/refinery,
+<rig>/polecats/<name>, <rig>/crew/<name>.
+
+Examples:
+  gt ping gastown/witness
+  gt ping gastown/polecats/Toast gastown/polecats/Nux --timeout 2m
+  gt ping stats
+  gt ping stats --since 1h --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runPing,
+}
+
+var pingAckCmd = &cobra.Command{
+	Use:   "ack <token>",
+	Short: "Acknowledge a ping (run by the agent that was pinged)",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runPingAck,
+}
+
+var pingStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show responsiveness percentiles per agent",
+	Args:  cobra.NoArgs,
+	RunE:  runPingStats,
+}
+
+var (
+	// pingSessionForFn is a seam for tests. Production uses agentAddressToIDs.
+	pingSessionForFn = func(agent string) (string, error) {
+		_, sessionName, err := agentAddressToIDs(agent)
+		return sessionName, err
+	}
+
+	// pingHasSessionFn is a seam for tests. Production asks tmux.
+	pingHasSessionFn = func(sessionName string) (bool, error) {
+		return tmux.NewTmux().HasSession(sessionName)
+	}
+
+	// pingAgentAliveFn is a seam for tests. Production asks tmux.
+	pingAgentAliveFn = func(sessionName string) bool {
+		return tmux.NewTmux().IsAgentAlive(sessionName)
+	}
+
+	// pingNudgeFn is a seam for tests. Production nudges the session over tmux.
+	pingNudgeFn = func(sessionName, message string) error {
+		// Immediate delivery: a queued nudge would wait for the agent's
+		// next turn and measure its workload, not its responsiveness.
+		return tmux.NewTmux().NudgeSession(sessionName, message)
+	}
+
+	// pingPollInterval is how often ping checks for a reply. Tests shorten it.
+	pingPollInterval = 500 * time.Millisecond
+)
+
+func init() {
+	pingCmd.Flags().DurationVar(&pingTimeout, "timeout", time.Minute, "How long to wait for each acknowledgment")
+	pingCmd.Flags().BoolVar(&pingJSON, "json", false, "Output as JSON")
+	pingStatsCmd.Flags().DurationVar(&pingStatsSince, "since", 24*time.Hour, "Summarize probes sent within this window")
+	pingStatsCmd.Flags().BoolVar(&pingStatsJSON, "json", false, "Output as JSON")
+
+	pingCmd.AddCommand(pingAckCmd)
+	pingCmd.AddCommand(pingStatsCmd)
+	rootCmd.AddCommand(pingCmd)
+}
+
+func runPing(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	agents := make([]string, len(args))
+	for i, a := range args {
+		agents[i] = normalizePingAgent(a)
+		if _, err := pingSessionForFn(agents[i]); err != nil {
+			return fmt.Errorf("invalid agent address %q: %w", a, err)
+		}
+	}
+
+	if !pingJSON {
+		fmt.Printf("%s Pinging %d agent(s), waiting up to %s...\n", style.Bold.Render("→"), len(agents), pingTimeout)
+	}
+	probes := make([]ping.Probe, len(agents))
+	errs := make([]error, len(agents))
+	var wg sync.WaitGroup
+	for i, agent := range agents {
+		wg.Add(1)
+		go func(i int, agent string) {
+			defer wg.Done()
+			p, err := probeAgent(townRoot, agent, pingTimeout)
+			if p != nil {
+				probes[i] = *p
+			}
+			errs[i] = err
+		}(i, agent)
+	}
+	wg.Wait()
+
+	failed := false
+	if pingJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(probes); err != nil {
+			return err
+		}
+	}
+	for i, p := range probes {
+		if errs[i] != nil {
+			failed = true
+			if !pingJSON {
+				fmt.Printf("  %s %s: %v\n", style.Error.Render("✗"), agents[i], errs[i])
+			}
+			continue
+		}
+		if p.Result != ping.ResultOK {
+			failed = true
+		}
+		if !pingJSON {
+			printProbe(p)
+		}
+	}
+	if failed {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// normalizePingAgent accepts the trailing-slash form of town agents.
+func normalizePingAgent(agent string) string {
+	switch trimmed := strings.TrimSuffix(agent, "/"); trimmed {
+	case constants.RoleMayor, constants.RoleDeacon:
+		return trimmed
+	}
+	return agent
+}
+
+// probeAgent pings one agent and waits for its acknowledgment. The probe
+// is recorded whatever the outcome.
+func probeAgent(townRoot, agent string, timeout time.Duration) (*ping.Probe, error) {
+	sessionName, err := pingSessionForFn(agent)
+	if err != nil {
+		return nil, err
+	}
+	p, err := ping.Start(townRoot, agent, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("recording ping: %w", err)
+	}
+	finish := func(result string) (*ping.Probe, error) {
+		if err := ping.Finish(townRoot, p.ID, result); err != nil {
+			return nil, fmt.Errorf("recording ping result: %w", err)
+		}
+		got, err := ping.Get(townRoot, p.ID)
+		if err != nil || got == nil {
+			return nil, fmt.Errorf("reading ping %s: %v", p.ID, err)
+		}
+		return got, nil
+	}
+
+	exists, err := pingHasSessionFn(sessionName)
+	if err != nil {
+		return nil, fmt.Errorf("checking session: %w", err)
+	}
+	if !exists {
+		return finish(ping.ResultNoSession)
+	}
+	if !pingAgentAliveFn(sessionName) {
+		return finish(ping.ResultNoAgent)
+	}
+
+	msg := fmt.Sprintf("PING %s: run `gt ping ack %s` now to confirm you are responsive, then carry on with what you were doing.", p.ID, p.ID)
+	if err := pingNudgeFn(sessionName, msg); err != nil {
+		_, _ = finish(ping.ResultNoSession)
+		return nil, fmt.Errorf("sending ping: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		got, err := ping.Get(townRoot, p.ID)
+		if err == nil && got != nil && !got.AckedAt.IsZero() {
+			return got, nil
+		}
+		if !time.Now().Before(deadline) {
+			return finish(ping.ResultTimeout)
+		}
+		time.Sleep(pingPollInterval)
+	}
+}
+
+func printProbe(p ping.Probe) {
+	switch p.Result {
+	case ping.ResultOK:
+		fmt.Printf("  %s %s acknowledged in %s\n", style.Success.Render("✓"), p.Agent, formatPingLatency(p.Latency()))
+	case ping.ResultTimeout:
+		fmt.Printf("  %s %s did not acknowledge in time %s\n", style.Warning.Render("⚠"), p.Agent,
+			style.Dim.Render("(session and agent alive: slow or wedged)"))
+	case ping.ResultNoAgent:
+		fmt.Printf("  %s %s: session exists but the agent process is gone\n", style.Error.Render("✗"), p.Agent)
+	case ping.ResultNoSession:
+		fmt.Printf("  %s %s: no session\n", style.Error.Render("✗"), p.Agent)
+	}
+}
+
+func runPingAck(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	p, err := ping.Ack(townRoot, args[0], time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Ping %s acknowledged (%s)\n", style.SuccessPrefix, p.ID, formatPingLatency(p.Latency()))
+	return nil
+}
+
+func runPingStats(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	probes, err := ping.List(townRoot, time.Now().Add(-pingStatsSince))
+	if err != nil {
+		return fmt.Errorf("reading ping records: %w", err)
+	}
+	stats := ping.Summarize(probes)
+	agents := make([]string, 0, len(stats))
+	for agent := range stats {
+		agents = append(agents, agent)
+	}
+	sort.Strings(agents)
+
+	if pingStatsJSON {
+		out := make([]*ping.Stats, 0, len(agents))
+		for _, agent := range agents {
+			out = append(out, stats[agent])
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	if len(agents) == 0 {
+		fmt.Printf("No pings in the last %s. Probe agents with %s.\n", pingStatsSince, style.Bold.Render("gt ping <agent>"))
+		return nil
+	}
+
+	fmt.Printf("%-28s %6s %8s %8s %8s %8s %5s  %s\n", "AGENT", "PINGS", "P50", "P90", "P99", "TIMEOUTS", "DEAD", "LAST")
+	for _, agent := range agents {
+		s := stats[agent]
+		fmt.Printf("%-28s %6d %8s %8s %8s %8d %5d  %s\n", agent, s.Probes,
+			formatPingLatency(s.P50), formatPingLatency(s.P90), formatPingLatency(s.P99),
+			s.Timeouts, s.Dead, s.Last)
+	}
+	return nil
+}
+
+// formatPingLatency renders a latency at a readable precision; zero is "-".
+func formatPingLatency(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "-"
+	case d < 10*time.Second:
+		return d.Round(100 * time.Millisecond).String()
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
+// pingAgentForStatus maps a gt status agent address to the address gt
+// ping records it under.
+func pingAgentForStatus(address string) string {
+	address = normalizePingAgent(address)
+	parts := strings.Split(address, "/")
+	if len(parts) == 2 && parts[1] != constants.RoleWitness && parts[1] != constants.RoleRefinery {
+		return parts[0] + "/polecats/" + parts[1]
+	}
+	return address
+}
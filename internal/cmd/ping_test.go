@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/ping"
+)
+
+func TestProbeAgent(t *testing.T) {
+	town := t.TempDir()
+
+	origSession, origHas, origAlive, origNudge, origPoll := pingSessionForFn, pingHasSessionFn, pingAgentAliveFn, pingNudgeFn, pingPollInterval
+	t.Cleanup(func() {
+		pingSessionForFn, pingHasSessionFn, pingAgentAliveFn, pingNudgeFn, pingPollInterval = origSession, origHas, origAlive, origNudge, origPoll
+	})
+	pingPollInterval = 10 * time.Millisecond
+	pingSessionForFn = func(agent string) (string, error) { return "gt-" + strings.ReplaceAll(agent, "/", "-"), nil }
+
+	sessions := map[string]bool{"gt-gastown-witness": true, "gt-gastown-refinery": true, "gt-gastown-polecats-Nux": true}
+	alive := map[string]bool{"gt-gastown-witness": true, "gt-gastown-refinery": true}
+	pingHasSessionFn = func(s string) (bool, error) { return sessions[s], nil }
+	pingAgentAliveFn = func(s string) bool { return alive[s] }
+	pingNudgeFn = func(s, msg string) error {
+		if s != "gt-gastown-witness" {
+			return nil // The refinery never answers
+		}
+		id := strings.Fields(strings.TrimPrefix(msg, "PING "))[0]
+		id = strings.TrimSuffix(id, ":")
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			_, _ = ping.Ack(town, id, time.Now())
+		}()
+		return nil
+	}
+
+	tests := []struct {
+		agent string
+		want  string
+	}{
+		{"gastown/witness", ping.ResultOK},
+		{"gastown/refinery", ping.ResultTimeout},
+		{"gastown/polecats/Nux", ping.ResultNoAgent},
+		{"gastown/polecats/Toast", ping.ResultNoSession},
+	}
+	for _, tt := range tests {
+		p, err := probeAgent(town, tt.agent, 200*time.Millisecond)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.agent, err)
+		}
+		if p.Result != tt.want {
+			t.Errorf("%s: result = %q, want %q", tt.agent, p.Result, tt.want)
+		}
+		if tt.want == ping.ResultOK && p.Latency() <= 0 {
+			t.Errorf("%s: no latency recorded", tt.agent)
+		}
+	}
+
+	probes, err := ping.List(town, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(probes) != len(tests) {
+		t.Errorf("recorded %d probes, want %d", len(probes), len(tests))
+	}
+}
+
+func TestPingAgentForStatus(t *testing.T) {
+	tests := map[string]string{
+		"mayor/":               "mayor",
+		"deacon/":              "deacon",
+		"gastown/witness":      "gastown/witness",
+		"gastown/refinery":     "gastown/refinery",
+		"gastown/Toast":        "gastown/polecats/Toast",
+		"gastown/crew/joe":     "gastown/crew/joe",
+		"gastown/polecats/Nux": "gastown/polecats/Nux",
+	}
+	for in, want := range tests {
+		if got := pingAgentForStatus(in); got != want {
+			t.Errorf("pingAgentForStatus(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/undo"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -142,6 +143,7 @@ var (
 	polecatNukeAll           bool
 	polecatNukeDryRun        bool
 	polecatNukeForce         bool
+	polecatNukeYes           bool
 	polecatCheckRecoveryJSON bool
 	polecatPoolInitDryRun    bool
 	polecatPoolInitSize      int
@@ -185,6 +187,11 @@ SAFETY CHECKS: The command refuses to nuke a polecat if:
 
 Use --force to bypass safety checks (LOSES WORK).
 Use --dry-run to see what would happen and safety check status.
+The town's confirmation policy may require confirming first; --yes skips
+that only if the policy allows it (see gt confirm).
+
+The branch tip is recorded, so 'gt undo' can recreate the worktree at the
+same commit. Uncommitted changes can't be brought back.
 
 Examples:
   gt polecat nuke greenplace/Toast
@@ -344,6 +351,7 @@ func init() {
 	polecatNukeCmd.Flags().BoolVar(&polecatNukeAll, "all", false, "Nuke all polecats in the rig")
 	polecatNukeCmd.Flags().BoolVar(&polecatNukeDryRun, "dry-run", false, "Show what would be nuked without doing it")
 	polecatNukeCmd.Flags().BoolVarP(&polecatNukeForce, "force", "f", false, "Force nuke, bypassing all safety checks (LOSES WORK)")
+	polecatNukeCmd.Flags().BoolVarP(&polecatNukeYes, "yes", "y", false, "Skip confirmation if the town's policy allows it")
 
 	// Check-recovery flags
 	polecatCheckRecoveryCmd.Flags().BoolVar(&polecatCheckRecoveryJSON, "json", false, "Output as JSON")
@@ -1188,6 +1196,15 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if !polecatNukeDryRun {
+		if proceed, err := confirmDestructive("polecat nuke", polecatNukeTarget(targets), polecatNukeYes); err != nil {
+			return err
+		} else if !proceed {
+			fmt.Println("Aborted.")
+			return nil
+		}
+	}
+
 	// Nuke each polecat
 	var nukeErrors []string
 	nuked := 0
@@ -1211,10 +1228,14 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 			fmt.Printf("Nuking %s/%s...\n", p.rigName, p.polecatName)
 		}
 
+		undoState := polecatNukeUndoState(p)
 		if err := nukePolecatFull(p.polecatName, p.rigName, p.mgr, p.r); err != nil {
 			nukeErrors = append(nukeErrors, fmt.Sprintf("%s/%s: %v", p.rigName, p.polecatName, err))
 			continue
 		}
+		if undoState.Commit != "" {
+			recordUndo(filepath.Dir(p.r.Path), undo.KindPolecatNuke, fmt.Sprintf("polecat nuke %s/%s", p.rigName, p.polecatName), undoState)
+		}
 
 		nuked++
 	}
@@ -1249,6 +1270,30 @@ func runPolecatNuke(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// polecatNukeTarget names what a nuke destroys for its confirmation:
+// the polecat's address, or a count when there are several.
+func polecatNukeTarget(targets []polecatTarget) string {
+	if len(targets) == 1 {
+		return targets[0].rigName + "/" + targets[0].polecatName
+	}
+	return fmt.Sprintf("%d polecats", len(targets))
+}
+
+// polecatNukeUndoState records the polecat's branch and its tip before a
+// nuke deletes them, so gt undo can recreate the worktree.
+func polecatNukeUndoState(p polecatTarget) undo.PolecatNukeState {
+	st := undo.PolecatNukeState{Rig: p.rigName, Polecat: p.polecatName}
+	info, err := p.mgr.Get(p.polecatName)
+	if err != nil || info == nil || info.Branch == "" {
+		return st
+	}
+	st.Branch, st.Issue = info.Branch, info.Issue
+	if commit, err := getRepoGitForRig(p.r.Path).Rev(info.Branch); err == nil {
+		st.Commit = commit
+	}
+	return st
+}
+
 // nukePolecatFull performs the complete cleanup sequence for a single polecat:
 // 1. Kill tmux session
 // 2. Delete worktree (via RemoveWithOptions with nuclear=true)
@@ -1305,7 +1350,7 @@ func nukePolecatFull(polecatName, rigName string, mgr *polecat.Manager, r *rig.R
 		}
 		if pushGit != nil {
 			refspec := branchToDelete + ":" + branchToDelete
-			if err := pushGit.Push("origin", refspec, false); err != nil {
+			if err := pushGit.Push(r.Remotes.BranchRemote(), refspec, false); err != nil {
 				fmt.Printf("  %s best-effort push failed (proceeding): %v\n", style.Dim.Render("○"), err)
 			} else {
 				fmt.Printf("  %s pushed branch %s before nuke\n", style.Success.Render("✓"), branchToDelete)
@@ -1623,7 +1668,8 @@ func runPolecatPrune(cmd *cobra.Command, args []string) error {
 		fmt.Println("Pruning remote polecat branches...")
 
 		defaultBranch := repoGit.RemoteDefaultBranch()
-		remoteRefs, lsErr := repoGit.ListPushRemoteRefs("origin", "refs/heads/polecat/")
+		branchRemote := r.Remotes.BranchRemote()
+		remoteRefs, lsErr := repoGit.ListPushRemoteRefs(branchRemote, "refs/heads/polecat/")
 		if lsErr != nil {
 			return fmt.Errorf("listing remote refs: %w", lsErr)
 		}
@@ -1643,7 +1689,7 @@ func runPolecatPrune(cmd *cobra.Command, args []string) error {
 			if polecatPruneDryRun {
 				fmt.Printf("  Would delete remote: %s\n", style.Dim.Render(branch))
 			} else {
-				if delErr := repoGit.DeleteRemoteBranch("origin", branch); delErr != nil {
+				if delErr := repoGit.DeleteRemoteBranch(branchRemote, branch); delErr != nil {
 					fmt.Printf("  %s remote %s: %v\n", style.Warning.Render("⚠"), branch, delErr)
 				} else {
 					fmt.Printf("  %s deleted remote %s\n", style.Success.Render("✓"), branch)
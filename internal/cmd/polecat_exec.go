@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+var (
+	polecatExecNoSandbox  bool
+	polecatExecNoAgentEnv bool
+)
+
+var polecatExecCmd = &cobra.Command{
+	Use:   "exec <rig>/<polecat> -- <command> [args...]",
+	Short: "Run a command inside a polecat's worktree environment",
+	Long: `Run an ad-hoc command in a polecat's worktree with the polecat's environment.
+
+The command runs in the polecat's worktree (on its branch) with the same
+identity variables the agent session gets (GT_ROLE, GT_RIG, GT_POLECAT,
+BD_ACTOR, GIT_AUTHOR_NAME, GT_BRANCH, ...) plus the rig's declared env
+(vars, PATH additions, tool versions). If the rig configures an
+exec_wrapper (sandbox/container), the command runs inside it too.
+Output goes straight to your terminal and the exit code is preserved.
+
+A single argument is run through 'sh -c' so pipes and globs work; multiple
+arguments are executed directly.
+
+Because the agent identity is applied, gt/bd commands run this way act as the
+polecat. Use --no-agent-env to keep your own identity.
+
+Examples:
+  gt polecat exec gastown/Toast -- go test ./...
+  gt polecat exec gastown/Toast -- 'make build 2>&1 | tail -40'
+  gt polecat exec gastown/Toast --no-sandbox -- git log --oneline -5`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runPolecatExec,
+}
+
+func init() {
+	polecatExecCmd.Flags().BoolVar(&polecatExecNoSandbox, "no-sandbox", false, "Do not apply the rig's exec_wrapper")
+	polecatExecCmd.Flags().BoolVar(&polecatExecNoAgentEnv, "no-agent-env", false, "Do not apply the polecat's identity variables")
+	polecatCmd.AddCommand(polecatExecCmd)
+}
+
+func runPolecatExec(cmd *cobra.Command, args []string) error {
+	rigName, polecatName, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	mgr, r, err := getPolecatManager(rigName)
+	if err != nil {
+		return err
+	}
+	p, err := mgr.Get(polecatName)
+	if err != nil {
+		return fmt.Errorf("polecat '%s' not found in rig '%s'", polecatName, rigName)
+	}
+	if info, err := os.Stat(p.ClonePath); err != nil || !info.IsDir() {
+		return fmt.Errorf("polecat %s/%s has no worktree at %s", rigName, polecatName, p.ClonePath)
+	}
+
+	townRoot := filepath.Dir(r.Path)
+	var wrapper []string
+	if !polecatExecNoSandbox {
+		wrapper = config.ResolveExecWrapper(r.Path)
+	}
+	agentEnv := map[string]string{}
+	if !polecatExecNoAgentEnv {
+		agentEnv = polecatExecEnv(townRoot, rigName, polecatName, p.ClonePath)
+	}
+	// Rig-declared env (vars, PATH additions, tool pins) applies either way.
+	rigEnv := config.ResolveRigEnv(r.Path)
+	rigEnv.ApplyVars(agentEnv)
+	config.ResolveBuildCache(r.Path).ApplyEnv(agentEnv, r.Path)
+	if path := rigEnv.ExecPath(os.Getenv("PATH")); path != os.Getenv("PATH") {
+		agentEnv["PATH"] = path
+	}
+	wrapper = append(wrapper, rigEnv.ToolWrapper()...)
+
+	argv := buildPolecatExecArgv(wrapper, args[1:])
+	c := exec.Command(argv[0], argv[1:]...) //nolint:gosec // G204: operator-supplied command, by design
+	c.Dir = p.ClonePath
+	c.Env = config.EnvForExecCommand(agentEnv)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return NewSilentExit(exitErr.ExitCode())
+		}
+		return fmt.Errorf("running command: %w", err)
+	}
+	return nil
+}
+
+// buildPolecatExecArgv prefixes the sandbox wrapper. A single command string
+// goes through sh -c; multiple arguments are run as-is.
+func buildPolecatExecArgv(wrapper, command []string) []string {
+	argv := append([]string(nil), wrapper...)
+	if len(command) == 1 {
+		return append(argv, "sh", "-c", command[0])
+	}
+	return append(argv, command...)
+}
+
+// polecatExecEnv returns the identity variables a polecat session runs with.
+func polecatExecEnv(townRoot, rigName, polecatName, workDir string) map[string]string {
+	env := config.AgentEnv(config.AgentEnvConfig{
+		Role:      constants.RolePolecat,
+		Rig:       rigName,
+		AgentName: polecatName,
+		TownRoot:  townRoot,
+	})
+	env["GT_POLECAT_PATH"] = workDir
+	env["GT_TOWN_ROOT"] = townRoot
+	if b, err := git.NewGit(workDir).CurrentBranch(); err == nil && b != "" {
+		env["GT_BRANCH"] = b
+	}
+	return env
+}
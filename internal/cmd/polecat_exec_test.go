@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildPolecatExecArgv(t *testing.T) {
+	tests := []struct {
+		name    string
+		wrapper []string
+		command []string
+		want    []string
+	}{
+		{"single string uses shell", nil, []string{"go test ./... | tail"}, []string{"sh", "-c", "go test ./... | tail"}},
+		{"argv passed through", nil, []string{"git", "status"}, []string{"git", "status"}},
+		{"wrapper prefixed", []string{"exitbox", "run", "--"}, []string{"make"}, []string{"exitbox", "run", "--", "sh", "-c", "make"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildPolecatExecArgv(tt.wrapper, tt.command)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildPolecatExecArgv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolecatExecEnv(t *testing.T) {
+	dir := t.TempDir()
+	env := polecatExecEnv("/town", "gastown", "Toast", dir)
+
+	want := map[string]string{
+		"GT_ROLE":         "gastown/polecats/Toast",
+		"GT_RIG":          "gastown",
+		"GT_POLECAT":      "Toast",
+		"GT_POLECAT_PATH": dir,
+		"GT_TOWN_ROOT":    "/town",
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("env[%s] = %q, want %q", k, env[k], v)
+		}
+	}
+	if _, ok := env["GT_BRANCH"]; ok {
+		t.Errorf("GT_BRANCH set for a non-git directory: %q", env["GT_BRANCH"])
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 // Polecat identity command flags
@@ -113,6 +114,9 @@ Safety checks:
   - New name must not already exist
   - Polecat session must not be running
 
+Only the identity bead moves. gt polecat rename also moves the worktree,
+assigned work and mail, and forwards the old address.
+
 Example:
   gt polecat identity rename gastown Toast Imperator`,
 	Args: cobra.ExactArgs(3),
@@ -590,36 +594,19 @@ func runPolecatIdentityRename(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot rename: polecat session %s is running", oldName)
 	}
 
-	// Create new identity bead with inherited fields
-	newFields := &beads.AgentFields{
-		RoleType:          "polecat",
-		Rig:               rigName,
-		AgentState:        oldFields.AgentState,
-		HookBead:          oldFields.HookBead,
-		CleanupStatus:     oldFields.CleanupStatus,
-		ActiveMR:          oldFields.ActiveMR,
-		NotificationLevel: oldFields.NotificationLevel,
-	}
-
+	// Create new identity bead with inherited fields, close the old one
+	newFields := *oldFields
+	newFields.RoleType = "polecat"
 	newTitle := fmt.Sprintf("Polecat %s in %s", newName, rigName)
-	_, err = bd.CreateOrReopenAgentBead(newBeadID, newTitle, newFields)
-	if err != nil {
-		return fmt.Errorf("creating new identity bead: %w", err)
-	}
-
-	// Close old bead with reference to new one
-	closeReason := fmt.Sprintf("renamed to %s", newBeadID)
-	if err := bd.CloseWithReason(closeReason, oldBeadID); err != nil {
-		// Try to clean up new bead
-		_ = bd.CloseWithReason("rename failed", newBeadID)
-		return fmt.Errorf("closing old identity bead: %w", err)
+	if err := moveAgentBead(bd, oldBeadID, newBeadID, newTitle, &newFields); err != nil {
+		return err
 	}
 
 	fmt.Printf("%s Renamed identity:\n", style.SuccessPrefix)
 	fmt.Printf("  Old: %s\n", oldBeadID)
 	fmt.Printf("  New: %s\n", newBeadID)
-	fmt.Printf("\n%s Note: If a worktree exists for %s, you'll need to recreate it with the new name.\n",
-		style.Warning.Render("⚠"), oldName)
+	fmt.Printf("\n%s Note: only the identity bead moved. To move the worktree, work and mail too, use gt polecat rename.\n",
+		style.Warning.Render("⚠"))
 
 	return nil
 }
@@ -801,8 +788,35 @@ type IssueInfo struct {
 	Updated string `json:"updated_at"`
 }
 
-// queryAssignedIssues queries beads for issues assigned to a specific agent.
+// queryAssignedIssues queries beads for issues assigned to a specific agent,
+// including those assigned to it under a name it had before a rename.
 func queryAssignedIssues(rigPath, assignee, status string) ([]IssueInfo, error) {
+	issues, err := queryAssignedIssuesAs(rigPath, assignee, status)
+	if err != nil {
+		return nil, err
+	}
+	townRoot, _ := workspace.Find(rigPath)
+	for _, alias := range formerAddresses(townRoot, assignee) {
+		former, err := queryAssignedIssuesAs(rigPath, alias.Address, status)
+		if err != nil {
+			continue
+		}
+		for _, issue := range former {
+			// After the rename, the old name may belong to someone else.
+			if updated, err := time.Parse(time.RFC3339, issue.Updated); err == nil && !updated.Before(alias.Until) {
+				continue
+			}
+			issues = append(issues, issue)
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Updated > issues[j].Updated
+	})
+	return issues, nil
+}
+
+// queryAssignedIssuesAs queries beads for issues with exactly this assignee.
+func queryAssignedIssuesAs(rigPath, assignee, status string) ([]IssueInfo, error) {
 	// Use bd list with filters
 	args := []string{"list", "--assignee=" + assignee, "--json", "--flat"}
 	if status != "" {
@@ -811,7 +825,7 @@ func queryAssignedIssues(rigPath, assignee, status string) ([]IssueInfo, error)
 
 	cmd := exec.Command("bd", args...)
 	cmd.Dir = rigPath
-	out, err := cmd.Output()
+	out, err := beads.CmdOutput(cmd)
 	if err != nil {
 		return nil, err
 	}
@@ -824,12 +838,6 @@ func queryAssignedIssues(rigPath, assignee, status string) ([]IssueInfo, error)
 	if err := json.Unmarshal(out, &issues); err != nil {
 		return nil, err
 	}
-
-	// Sort by updated date (most recent first)
-	sort.Slice(issues, func(i, j int) bool {
-		return issues[i].Updated > issues[j].Updated
-	})
-
 	return issues, nil
 }
 
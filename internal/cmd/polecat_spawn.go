@@ -57,6 +57,24 @@ type SlingSpawnOptions struct {
 	BaseBranch string // Override base branch for polecat worktree (e.g., "develop", "release/v2")
 }
 
+// defaultMaxActivePolecats is the town-wide polecat safety cap for direct
+// dispatch (clown show #22).
+const defaultMaxActivePolecats = 25
+
+// spawnEventPayload is the spawn event payload plus the agent preset the
+// polecat runs, so scorecards can compare configurations.
+func spawnEventPayload(townRoot, rigPath, rigName, polecatName, agentOverride string) map[string]interface{} {
+	payload := events.SpawnPayload(rigName, polecatName)
+	agent := agentOverride
+	if agent == "" {
+		agent, _ = config.ResolveRoleAgentName(constants.RolePolecat, townRoot, rigPath)
+	}
+	if agent != "" {
+		payload["agent"] = agent
+	}
+	return payload
+}
+
 // SpawnPolecatForSling creates a fresh polecat and optionally starts its session.
 // This is used by gt sling when the target is a rig name.
 // The caller (sling) handles hook attachment and nudging.
@@ -102,7 +120,6 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	// too many active polecats. This is a last-resort safety net for the direct-dispatch
 	// path. For configurable capacity gating, use scheduler.max_polecats in town settings
 	// (see internal/scheduler/capacity/).
-	const defaultMaxActivePolecats = 25
 	activeCount := countActivePolecats()
 	if activeCount >= defaultMaxActivePolecats {
 		return nil, fmt.Errorf("polecat cap reached: %d active polecats (max %d). "+
@@ -145,6 +162,12 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		}
 	}
 
+	// Per-rig disk quota: refuse to spawn into a rig over its disk_quota
+	// (one runaway worktree can otherwise fill the disk).
+	if err := checkRigDiskQuota(townRoot, rigName); err != nil {
+		return nil, err
+	}
+
 	// Persistent polecat model (gt-4ac): try to reuse an idle polecat first.
 	// Idle polecats have completed their work but kept their sandbox (worktree).
 	// Reusing avoids the overhead of creating a new worktree.
@@ -183,6 +206,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		addOpts := polecat.AddOptions{
 			HookBead:   opts.HookBead,
 			BaseBranch: baseBranch,
+			Branch:     linkedSpawnBranch(townRoot, rigName, opts.HookBead),
 		}
 		reuseOK := false
 		if _, err := polecatMgr.ReuseIdlePolecat(polecatName, addOpts); err != nil {
@@ -210,7 +234,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 			sessionName := polecatSessMgr.SessionName(polecatName)
 
 			fmt.Printf("%s Polecat %s reused (idle → working, session start deferred)\n", style.Bold.Render("✓"), polecatName)
-			_ = events.LogFeed(events.TypeSpawn, "gt", events.SpawnPayload(rigName, polecatName))
+			_ = events.LogFeed(events.TypeSpawn, "gt", spawnEventPayload(townRoot, r.Path, rigName, polecatName, opts.Agent))
 
 			effectiveBranch := strings.TrimPrefix(baseBranch, "origin/")
 			if effectiveBranch == "" {
@@ -260,6 +284,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	addOpts := polecat.AddOptions{
 		HookBead:   opts.HookBead,
 		BaseBranch: baseBranch,
+		Branch:     linkedSpawnBranch(townRoot, rigName, opts.HookBead),
 	}
 
 	// No idle polecat available — allocate and create atomically (GH#2215).
@@ -293,7 +318,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 	fmt.Printf("%s Polecat %s spawned (session start deferred)\n", style.Bold.Render("✓"), polecatName)
 
 	// Log spawn event to activity feed
-	_ = events.LogFeed(events.TypeSpawn, "gt", events.SpawnPayload(rigName, polecatName))
+	_ = events.LogFeed(events.TypeSpawn, "gt", spawnEventPayload(townRoot, r.Path, rigName, polecatName, opts.Agent))
 
 	// Compute effective base branch (strip origin/ prefix since formula prepends it)
 	effectiveBranch := strings.TrimPrefix(baseBranch, "origin/")
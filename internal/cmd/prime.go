@@ -9,13 +9,16 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/atrest"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/cli"
+	"github.com/steveyegge/gastown/internal/injectguard"
 	"github.com/steveyegge/gastown/internal/lock"
 	"github.com/steveyegge/gastown/internal/state"
 	"github.com/steveyegge/gastown/internal/style"
@@ -392,6 +395,7 @@ func outputRoleContext(ctx RoleContext) (string, error) {
 	}
 
 	outputContextFile(ctx)
+	outputPersona(ctx)
 	outputHandoffContent(ctx)
 	outputAttachmentStatus(ctx)
 	return formula, nil
@@ -422,7 +426,7 @@ func runBdPrime(workDir string) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		// Skip if bd prime fails (beads might not be available)
 		// But log stderr if present for debugging
 		if errMsg := strings.TrimSpace(stderr.String()); errMsg != "" {
@@ -510,6 +514,8 @@ func checkSlungWork(ctx RoleContext, hookedBead *beads.Issue) bool {
 
 	outputAutonomousDirective(ctx, hookedBead, hasWorkflow)
 	outputHookedBeadDetails(hookedBead)
+	outputContextPack(ctx, hookedBead)
+	outputRelatedSolutions(ctx, hookedBead)
 
 	if hasWorkflow {
 		outputMoleculeWorkflow(ctx, attachment)
@@ -696,8 +702,18 @@ func outputHookedBeadDetails(hookedBead *beads.Issue) {
 	fmt.Printf("%s\n\n", style.Bold.Render("## Hooked Work"))
 	fmt.Printf("  Bead ID: %s\n", style.Bold.Render(hookedBead.ID))
 	fmt.Printf("  Title: %s\n", hookedBead.Title)
-	if hookedBead.Description != "" {
-		lines := strings.Split(hookedBead.Description, "\n")
+	description := hookedBead.Description
+	// Handoff mail is sealed when the town encrypts mail at rest.
+	if opened, err := atrest.OpenString(description); err == nil {
+		description = opened
+	} else {
+		description = fmt.Sprintf("[encrypted: %v]", err)
+	}
+	if slices.Contains(hookedBead.Labels, injectguard.LabelQuarantined) {
+		description = quarantinedDescription(hookedBead.ID)
+	}
+	if description != "" {
+		lines := strings.Split(description, "\n")
 		maxLines := 5
 		if len(lines) > maxLines {
 			lines = lines[:maxLines]
@@ -802,7 +818,7 @@ func outputBeadPreview(hookedBead *beads.Issue) {
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		if errMsg := strings.TrimSpace(stderr.String()); errMsg != "" {
 			fmt.Fprintf(os.Stderr, "  bd show %s: %s\n", hookedBead.ID, errMsg)
 		} else {
@@ -840,6 +856,9 @@ func buildRoleAnnouncement(ctx RoleContext) string {
 	case RoleCrew:
 		return fmt.Sprintf("%s Crew %s, checking in.", ctx.Rig, ctx.Polecat)
 	default:
+		if ctx.Custom {
+			return strings.TrimSpace(ctx.Rig+" "+string(ctx.Role)) + ", checking in."
+		}
 		return "Agent, checking in."
 	}
 }
@@ -872,6 +891,9 @@ func getAgentIdentity(ctx RoleContext) string {
 	case RoleRefinery:
 		return fmt.Sprintf("%s/refinery", ctx.Rig)
 	default:
+		if ctx.Custom {
+			return customRoleAddress(ctx)
+		}
 		return ""
 	}
 }
@@ -1064,7 +1086,7 @@ func checkPendingEscalations(ctx RoleContext) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		// Silently skip - escalation check is best-effort
 		return
 	}
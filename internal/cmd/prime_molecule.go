@@ -14,6 +14,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/style"
 )
@@ -42,7 +43,7 @@ func showMoleculeExecutionPrompt(workDir, moleculeID string) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		// Fall back to simple message if bd mol current fails
 		fmt.Println(style.Bold.Render("→ PROPULSION PRINCIPLE: Work is on your hook. RUN IT."))
 		fmt.Println("  Begin working on this molecule immediately.")
@@ -359,6 +360,13 @@ func buildRefineryPatrolVars(ctx RoleContext) []string {
 	}
 	vars = append(vars, fmt.Sprintf("target_branch=%s", defaultBranch))
 
+	// Batch mode is configured with the engineer's merge_queue settings in
+	// the rig's config.json, not in settings/config.json.
+	eng := refinery.NewEngineer(&rig.Rig{Name: ctx.Rig, Path: rigPath})
+	if err := eng.LoadConfig(); err == nil && eng.Config().Batch.Enabled() {
+		vars = append(vars, "batch_mode=true")
+	}
+
 	// MQ-specific vars: try settings/config.json first (legacy format), then
 	// fall back to the layered rig config (bead labels / wisp layer).
 	settingsPath := filepath.Join(rigPath, "settings", "config.json")
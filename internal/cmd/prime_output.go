@@ -51,6 +51,9 @@ func outputPrimeContext(ctx RoleContext) (string, error) {
 	case RoleDog:
 		roleName = "dog"
 	default:
+		if ctx.Custom {
+			return outputCustomRoleContext(ctx), nil
+		}
 		// Unknown role - use fallback
 		outputPrimeContextFallback(ctx)
 		return "", nil
@@ -493,6 +496,10 @@ func outputStartupDirective(ctx RoleContext) {
 		fmt.Println("1. Run `" + cli.Name() + " prime` (loads full context)")
 		fmt.Println("2. Run `" + cli.Name() + " boot triage` immediately")
 		fmt.Println("3. When triage completes, exit cleanly")
+	default:
+		if ctx.Custom {
+			outputCustomRoleStartupDirective(ctx)
+		}
 	}
 }
 
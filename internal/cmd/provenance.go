@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/provenance"
+	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var provenanceCmd = &cobra.Command{
+	Use:     "provenance",
+	GroupID: GroupWork,
+	Short:   "Signed commits and Gt-* provenance trailers for agent work",
+	Long: `Record who made each agent commit, and prove it.
+
+With provenance on, every commit made in an agent session carries trailers:
+
+  Gt-Agent: gastown/polecats/nux
+  Gt-Bead: gt-abc12
+  Gt-Version: 0.12.0
+  Gt-Session: 3f2c...
+
+and can be signed with an SSH or OpenPGP key, one per town or one per
+agent identity. Sessions get the signing key and a commit-msg hook through
+GIT_CONFIG_* environment variables, so plain git commit is covered and no
+clone's config changes. The hook passes every hook on to the repository's
+own (.githooks or .git/hooks).
+
+With verify on, the refinery refuses branches with a commit that lacks the
+Gt-Agent and Gt-Version trailers or a good signature.
+
+Configure in settings/config.json:
+
+  "provenance": {
+    "trailers": true,
+    "signing": {
+      "format": "ssh",
+      "key": "~/.ssh/gastown-agents.pub",
+      "keys": {"gastown/crew/*": "~/.ssh/gastown-crew.pub"},
+      "allowed_signers": "~/.ssh/gastown-allowed-signers"
+    },
+    "verify": true
+  }
+
+Sessions pick up changes when they restart.
+
+Examples:
+  gt provenance status
+  gt provenance verify                 # Check this branch before gt done
+  gt provenance verify origin/main feature`,
+	RunE: requireSubcommand,
+}
+
+var provenanceStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show provenance settings and what this session commits with",
+	Args:  cobra.NoArgs,
+	RunE:  runProvenanceStatus,
+}
+
+var provenanceVerifyCmd = &cobra.Command{
+	Use:         "verify [base] [branch]",
+	Short:       "Check a branch's commits the way the merge queue will",
+	Annotations: map[string]string{AnnotationPolecatSafe: "true"},
+	Long: `Check the commits on branch (default HEAD) that are not on base
+(default origin/<default branch>) for provenance trailers and signatures,
+the same check the refinery runs when provenance.verify is on.
+
+Exits non-zero when a commit fails.
+
+Examples:
+  gt provenance verify
+  gt provenance verify origin/main polecat/nux/gt-abc12`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runProvenanceVerify,
+}
+
+var provenanceStampCmd = &cobra.Command{
+	Use:         "stamp <commit-msg-file>",
+	Short:       "Add provenance trailers to a commit message (commit-msg hook)",
+	Hidden:      true,
+	Annotations: map[string]string{AnnotationPolecatSafe: "true"},
+	Args:        cobra.ExactArgs(1),
+	RunE:        runProvenanceStamp,
+}
+
+func init() {
+	provenanceCmd.AddCommand(provenanceStatusCmd)
+	provenanceCmd.AddCommand(provenanceVerifyCmd)
+	provenanceCmd.AddCommand(provenanceStampCmd)
+	rootCmd.AddCommand(provenanceCmd)
+}
+
+// loadTownProvenance returns the town's provenance settings as written,
+// valid or not.
+func loadTownProvenance() (*provenance.Config, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	return settings.Provenance, nil
+}
+
+func runProvenanceStatus(cmd *cobra.Command, args []string) error {
+	prov, err := loadTownProvenance()
+	if err != nil {
+		return err
+	}
+	if !prov.Enabled() {
+		fmt.Println("Provenance is off (settings/config.json \"provenance\")")
+		return nil
+	}
+	if err := prov.Validate(); err != nil {
+		return fmt.Errorf("invalid provenance settings: %w", err)
+	}
+
+	onOff := func(b bool) string {
+		if b {
+			return "on"
+		}
+		return "off"
+	}
+	fmt.Printf("%s Commit provenance\n\n", style.Bold.Render("🔏"))
+	fmt.Printf("  Trailers:  %s\n", onOff(prov.Trailers))
+	fmt.Printf("  Signing:   %s\n", onOff(prov.Signing != nil))
+	fmt.Printf("  Verify:    %s\n", onOff(prov.Verify))
+
+	identity := os.Getenv("BD_ACTOR")
+	if identity == "" {
+		fmt.Printf("\n  %s\n", style.Dim.Render("Not in an agent session: commits here are not stamped or signed"))
+		return nil
+	}
+	fmt.Printf("\n  Identity:  %s\n", identity)
+	if key := prov.Signing.KeyFor(identity); key != "" {
+		fmt.Printf("  Key:       %s\n", key)
+	}
+	if os.Getenv("GIT_CONFIG_COUNT") == "" {
+		style.PrintWarning("this session was started before provenance was configured; restart it to pick it up")
+	}
+	return nil
+}
+
+func runProvenanceVerify(cmd *cobra.Command, args []string) error {
+	prov, err := loadTownProvenance()
+	if err != nil {
+		return err
+	}
+	if !prov.Enabled() {
+		return fmt.Errorf("provenance is off: nothing to verify")
+	}
+	if err := prov.Validate(); err != nil {
+		return fmt.Errorf("invalid provenance settings: %w", err)
+	}
+	// Check what the merge queue would, even when it isn't enforcing yet.
+	check := *prov
+	check.Verify = true
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	g := git.NewGit(cwd)
+	base, branch := "", "HEAD"
+	if len(args) > 0 {
+		base = args[0]
+	} else {
+		base = "origin/" + g.RemoteDefaultBranch()
+	}
+	if len(args) > 1 {
+		branch = args[1]
+	}
+
+	commits, err := g.CommitLog(base, branch, check.VerifyGitConfig()...)
+	if err != nil {
+		return fmt.Errorf("listing commits %s..%s: %w", base, branch, err)
+	}
+	problems := check.Check(commits)
+	for _, p := range problems {
+		fmt.Printf("  %s %s\n", style.ErrorPrefix, p)
+	}
+	if len(problems) > 0 {
+		if !prov.Verify {
+			style.PrintWarning("provenance.verify is off: the merge queue will not refuse these")
+		}
+		return NewSilentExit(1)
+	}
+	fmt.Printf("%s %d commit(s) on %s pass provenance checks\n", style.SuccessPrefix, len(commits), branch)
+	return nil
+}
+
+func runProvenanceStamp(cmd *cobra.Command, args []string) error {
+	identity := os.Getenv("BD_ACTOR")
+	if identity == "" {
+		identity = detectSender()
+	}
+	if identity == "" || identity == "overseer" {
+		return nil
+	}
+	session := runtime.SessionIDFromEnv()
+	if session == "" {
+		session = os.Getenv("GT_SESSION")
+	}
+	return provenance.StampFile(args[0], provenance.Stamp{
+		Agent:   identity,
+		Bead:    stampBead(identity),
+		Version: Version,
+		Session: session,
+	})
+}
+
+// stampBead returns the bead the agent is committing for: the one named by
+// a polecat branch, else the bead hooked to the agent.
+func stampBead(identity string) string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	if branch, err := git.NewGit(cwd).CurrentBranch(); err == nil && strings.HasPrefix(branch, constants.BranchPolecatPrefix) {
+		if info := parseBranchName(branch); info.Issue != "" {
+			return info.Issue
+		}
+	}
+	return findHookedBeadForAgent(beads.New(cwd), identity)
+}
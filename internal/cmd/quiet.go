@@ -0,0 +1,391 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/quiet"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var quietJSON bool
+
+var quietCmd = &cobra.Command{
+	Use:     "quiet",
+	GroupID: GroupServices,
+	Short:   "Show quiet hours: scheduled windows with no new work",
+	Long: `Show quiet hours for the town and each rig.
+
+Quiet hours are a daily window (settings/config.json, town or rig) during
+which the town winds down instead of grinding unattended:
+
+  - The scheduler and convoy feeder dispatch no new work to the rig
+  - Idle polecats and crew are parked after park_after (default 15m)
+  - Low and normal priority mail is delivered but the nudge is held
+
+When the window ends, parked crew are restarted, held notifications go
+out as one summary nudge per agent and dispatch resumes. Work already in
+progress is left to finish. High and urgent mail always notifies.
+
+  "quiet_hours": {
+    "enabled": true,
+    "start": "22:00",
+    "end": "07:00",
+    "days": ["mon", "tue", "wed", "thu", "fri"],
+    "park_after": "15m"
+  }
+
+Times are local; a window may run past midnight. A rig's quiet_hours
+replaces the town's. The daemon applies the schedule on every heartbeat.
+
+Examples:
+  gt quiet             # Which rigs are quiet and until when
+  gt quiet --json`,
+	Args: cobra.NoArgs,
+	RunE: runQuiet,
+}
+
+var quietTickCmd = &cobra.Command{
+	Use:    "tick",
+	Short:  "Apply quiet-hours transitions (daemon)",
+	Hidden: true,
+	Long: `Enter or leave quiet hours for each rig as the schedule says.
+
+Entering a window parks idle crew; leaving it restarts them and releases
+held mail notifications. Safe to run often; the daemon calls it on every
+heartbeat.`,
+	Args: cobra.NoArgs,
+	RunE: runQuietTick,
+}
+
+var (
+	// quietIdleCrewFn is a seam for tests. Production uses idleCrewSessions.
+	quietIdleCrewFn = idleCrewSessions
+
+	// quietRunGtFn is a seam for tests. Production uses runGtInTown.
+	quietRunGtFn = runGtInTown
+
+	// quietNotifyHeldFn is a seam for tests. Production uses notifyHeldMail.
+	quietNotifyHeldFn = notifyHeldMail
+)
+
+func init() {
+	quietCmd.Flags().BoolVar(&quietJSON, "json", false, "Output as JSON")
+	quietCmd.AddCommand(quietTickCmd)
+	rootCmd.AddCommand(quietCmd)
+}
+
+// quietScope is the town or one rig, with the quiet hours that apply to it.
+type quietScope struct {
+	Name      string        `json:"scope"` // Rig name or quiet.TownScope
+	Rig       string        `json:"rig,omitempty"`
+	Config    *quiet.Config `json:"config,omitempty"`
+	Inherited bool          `json:"inherited,omitempty"` // Rig uses the town's window
+	Active    bool          `json:"active"`
+	Until     *time.Time    `json:"until,omitempty"`
+	Held      int           `json:"held,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// quietScopes resolves quiet hours for the town and every registered rig.
+func quietScopes(townRoot string, now time.Time) []quietScope {
+	town := config.LoadQuietHours(townRoot, "")
+	scopes := []quietScope{newQuietScope(quiet.TownScope, "", town, false, now)}
+	if ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		if err := ts.QuietHours.Validate(); err != nil {
+			scopes[0].Error = err.Error()
+		}
+	}
+
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, constants.DirMayor, constants.FileRigsJSON))
+	if err != nil || rigsConfig == nil {
+		return scopes
+	}
+	var rigs []string
+	for name := range rigsConfig.Rigs {
+		rigs = append(rigs, name)
+	}
+	sort.Strings(rigs)
+	for _, rigName := range rigs {
+		cfg := town
+		inherited := true
+		var loadErr error
+		if settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rigName))); err == nil {
+			if settings.QuietHours != nil {
+				cfg, inherited = settings.QuietHours, false
+			}
+		} else if !errors.Is(err, config.ErrNotFound) {
+			loadErr = err
+		}
+		scope := newQuietScope(rigName, rigName, cfg, inherited, now)
+		if loadErr != nil {
+			scope.Error = loadErr.Error()
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes
+}
+
+func newQuietScope(name, rig string, cfg *quiet.Config, inherited bool, now time.Time) quietScope {
+	s := quietScope{Name: name, Rig: rig, Config: cfg, Inherited: inherited}
+	if _, end, active := cfg.Window(now); active {
+		s.Active, s.Until = true, &end
+	}
+	return s
+}
+
+func runQuiet(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	scopes := quietScopes(townRoot, time.Now())
+	held, _ := quiet.PendingHeld(townRoot)
+	for i := range scopes {
+		for _, h := range held {
+			if h.Scope == scopes[i].Name {
+				scopes[i].Held++
+			}
+		}
+	}
+
+	if quietJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(scopes)
+	}
+
+	fmt.Printf("%s Quiet hours\n", style.Bold.Render("🌙"))
+	for _, s := range scopes {
+		window := style.Dim.Render("off")
+		if s.Config.IsEnabled() {
+			window = describeQuietWindow(s.Config)
+		}
+		if s.Inherited && s.Config.IsEnabled() {
+			window += style.Dim.Render(" (town)")
+		}
+		line := fmt.Sprintf("  %-14s %s", s.Name, window)
+		if s.Active {
+			line += "  " + style.Warning.Render("quiet until "+s.Until.Format("15:04"))
+		}
+		if s.Held > 0 {
+			line += style.Dim.Render(fmt.Sprintf("  %d held notification(s)", s.Held))
+		}
+		if s.Error != "" {
+			line += "  " + style.Error.Render(s.Error)
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// describeQuietWindow renders a window as "22:00–07:00 mon,tue".
+func describeQuietWindow(c *quiet.Config) string {
+	days := "daily"
+	if len(c.Days) > 0 {
+		days = strings.Join(c.Days, ",")
+	}
+	return fmt.Sprintf("%s–%s %s", c.Start, c.End, days)
+}
+
+func runQuietTick(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return quietTick(townRoot, time.Now())
+}
+
+// quietTick enters and leaves quiet hours per scope. Entering is recorded
+// in the state file so leaving undoes exactly what the window did, even if
+// the config changed or the rig was removed in between.
+func quietTick(townRoot string, now time.Time) error {
+	state, err := quiet.LoadState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading quiet state: %w", err)
+	}
+	if state.Active == nil {
+		state.Active = make(map[string]time.Time)
+	}
+	if state.ParkedCrew == nil {
+		state.ParkedCrew = make(map[string][]string)
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range quietScopes(townRoot, now) {
+		seen[s.Name] = true
+		_, was := state.Active[s.Name]
+		switch {
+		case s.Active && !was:
+			state.Active[s.Name] = *s.Until
+			fmt.Printf("%s: quiet hours until %s\n", s.Name, s.Until.Format("15:04"))
+			_ = events.LogFeed(events.TypeQuietHours, "daemon", events.QuietHoursPayload(s.Name, true, *s.Until))
+		case !s.Active && was:
+			resumeQuietScope(townRoot, s.Name, state)
+		case !s.Active:
+			// Mail held while no tick saw the window (daemon down).
+			releaseHeldMail(townRoot, s.Name)
+		}
+		if s.Active && s.Rig != "" {
+			parkIdleCrew(townRoot, s.Rig, s.Config.ParkAfter(), state)
+		}
+	}
+	// Rigs removed mid-window still get their crew and mail back.
+	for name := range state.Active {
+		if !seen[name] {
+			resumeQuietScope(townRoot, name, state)
+		}
+	}
+	return state.Save(townRoot)
+}
+
+// resumeQuietScope ends a scope's quiet window: parked crew are restarted
+// and held notifications are released.
+func resumeQuietScope(townRoot, scope string, state *quiet.State) {
+	delete(state.Active, scope)
+	fmt.Printf("%s: quiet hours over\n", scope)
+	_ = events.LogFeed(events.TypeQuietHours, "daemon", events.QuietHoursPayload(scope, false, time.Time{}))
+
+	if crew := state.ParkedCrew[scope]; len(crew) > 0 {
+		args := append([]string{"crew", "start", scope}, crew...)
+		if err := quietRunGtFn(townRoot, append(args, "--resume")...); err != nil {
+			fmt.Printf("%s: restarting parked crew: %v\n", scope, err)
+		} else {
+			fmt.Printf("%s: restarted %s\n", scope, strings.Join(crew, ", "))
+		}
+		delete(state.ParkedCrew, scope)
+	}
+
+	releaseHeldMail(townRoot, scope)
+}
+
+// releaseHeldMail sends one summary nudge per recipient for the scope's
+// held notifications.
+func releaseHeldMail(townRoot, scope string) {
+	held, err := quiet.TakeHeld(townRoot, scope)
+	if err != nil {
+		fmt.Printf("%s: releasing held mail: %v\n", scope, err)
+		return
+	}
+	for to, msgs := range groupHeldMail(held) {
+		if err := quietNotifyHeldFn(to, msgs); err != nil {
+			fmt.Printf("%s: notifying %s: %v\n", scope, to, err)
+		}
+	}
+	if len(held) > 0 {
+		fmt.Printf("%s: released %d held notification(s)\n", scope, len(held))
+	}
+}
+
+// parkIdleCrew stops crew sessions in a quiet rig that have been idle for
+// at least idle, remembering them for restart. Idle polecats are reaped by
+// the daemon with the same threshold.
+func parkIdleCrew(townRoot, rigName string, idle time.Duration, state *quiet.State) {
+	names, err := quietIdleCrewFn(rigName, idle)
+	if err != nil || len(names) == 0 {
+		return
+	}
+	for _, name := range names {
+		if err := quietRunGtFn(townRoot, "crew", "stop", rigName+"/"+name, "--force"); err != nil {
+			fmt.Printf("%s: parking crew %s: %v\n", rigName, name, err)
+			continue
+		}
+		if !slices.Contains(state.ParkedCrew[rigName], name) {
+			state.ParkedCrew[rigName] = append(state.ParkedCrew[rigName], name)
+		}
+		fmt.Printf("%s: parked idle crew %s\n", rigName, name)
+	}
+}
+
+// idleCrewSessions returns crew in a rig whose sessions have had no
+// activity for at least idle.
+func idleCrewSessions(rigName string, idle time.Duration) ([]string, error) {
+	agents, err := getAgentSessions(false)
+	if err != nil {
+		return nil, err
+	}
+	t := tmux.NewTmux()
+	var names []string
+	for _, agent := range agents {
+		if agent.Type != AgentCrew || agent.Rig != rigName {
+			continue
+		}
+		activity, err := t.GetSessionActivity(agent.Name)
+		if err != nil || time.Since(activity) < idle {
+			continue
+		}
+		names = append(names, agent.AgentName)
+	}
+	return names, nil
+}
+
+// groupHeldMail groups held notifications by recipient.
+func groupHeldMail(held []quiet.Held) map[string][]quiet.Held {
+	byTo := make(map[string][]quiet.Held)
+	for _, h := range held {
+		byTo[h.To] = append(byTo[h.To], h)
+	}
+	return byTo
+}
+
+// heldMailSummary is the one nudge an agent gets for mail held overnight.
+func heldMailSummary(msgs []quiet.Held) string {
+	senders := make(map[string]bool)
+	var from []string
+	for _, m := range msgs {
+		if !senders[m.From] {
+			senders[m.From] = true
+			from = append(from, m.From)
+		}
+	}
+	return fmt.Sprintf("📬 %d message(s) arrived during quiet hours (from %s). Run 'gt mail inbox' to read.",
+		len(msgs), strings.Join(from, ", "))
+}
+
+// notifyHeldMail sends the summary nudge to the recipient's session, if any.
+func notifyHeldMail(to string, msgs []quiet.Held) error {
+	t := tmux.NewTmux()
+	for _, sessionID := range mail.AddressToSessionIDs(to) {
+		if ok, _ := t.HasSession(sessionID); !ok {
+			continue
+		}
+		if to == "overseer" {
+			return t.SendNotificationBanner(sessionID, "quiet hours", fmt.Sprintf("%d message(s) held overnight", len(msgs)))
+		}
+		return t.NudgeSession(sessionID, heldMailSummary(msgs))
+	}
+	return nil
+}
+
+// skipQuietRigs drops pending dispatches whose target rig is in quiet
+// hours. They stay queued and go out once the window ends.
+func skipQuietRigs(townRoot string, pending []capacity.PendingBead, now time.Time) []capacity.PendingBead {
+	quietRig := make(map[string]bool)
+	kept := pending[:0]
+	for _, b := range pending {
+		isQuiet, ok := quietRig[b.TargetRig]
+		if !ok {
+			isQuiet = config.LoadQuietHours(townRoot, b.TargetRig).Active(now)
+			quietRig[b.TargetRig] = isQuiet
+		}
+		if !isQuiet {
+			kept = append(kept, b)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/quiet"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+)
+
+// setupQuietTown creates a town with quiet hours 22:00-07:00, rig
+// greenplace inheriting them and rig beads opting out, and runs the test
+// from it.
+func setupQuietTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	files := map[string]string{
+		"mayor/rigs.json":            `{"version": 1, "rigs": {"greenplace": {}, "beads": {}}}`,
+		"settings/config.json":       `{"type": "town-settings", "version": 1, "quiet_hours": {"enabled": true, "start": "22:00", "end": "07:00"}}`,
+		"beads/settings/config.json": `{"type": "rig-settings", "version": 1, "quiet_hours": {"enabled": false, "start": "22:00", "end": "07:00"}}`,
+		"greenplace/settings/.keep":  ``,
+	}
+	for name, content := range files {
+		path := filepath.Join(townRoot, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Run from the town so quiet_hours feed events land in it, not the repo.
+	t.Chdir(townRoot)
+	return townRoot
+}
+
+func TestQuietScopes(t *testing.T) {
+	townRoot := setupQuietTown(t)
+	night := time.Date(2026, 10, 12, 23, 0, 0, 0, time.Local)
+
+	scopes := quietScopes(townRoot, night)
+	byName := make(map[string]quietScope)
+	for _, s := range scopes {
+		byName[s.Name] = s
+	}
+	if !byName[quiet.TownScope].Active {
+		t.Error("town should be quiet at 23:00")
+	}
+	if gp := byName["greenplace"]; !gp.Active || !gp.Inherited {
+		t.Errorf("greenplace = %+v, want quiet via the town window", gp)
+	}
+	if b := byName["beads"]; b.Active || b.Inherited {
+		t.Errorf("beads = %+v, want its own (disabled) window", b)
+	}
+}
+
+func TestSkipQuietRigs(t *testing.T) {
+	townRoot := setupQuietTown(t)
+	pending := []capacity.PendingBead{
+		{ID: "ctx-1", TargetRig: "greenplace"},
+		{ID: "ctx-2", TargetRig: "beads"},
+		{ID: "ctx-3", TargetRig: "greenplace"},
+	}
+	night := time.Date(2026, 10, 12, 23, 0, 0, 0, time.Local)
+	kept := skipQuietRigs(townRoot, append([]capacity.PendingBead(nil), pending...), night)
+	if len(kept) != 1 || kept[0].ID != "ctx-2" {
+		t.Errorf("night: kept %+v, want only the beads dispatch", kept)
+	}
+	day := time.Date(2026, 10, 13, 10, 0, 0, 0, time.Local)
+	if kept := skipQuietRigs(townRoot, append([]capacity.PendingBead(nil), pending...), day); len(kept) != 3 {
+		t.Errorf("day: kept %d, want all 3", len(kept))
+	}
+}
+
+func TestQuietTickParksAndResumes(t *testing.T) {
+	townRoot := setupQuietTown(t)
+	origIdle, origRun, origNotify := quietIdleCrewFn, quietRunGtFn, quietNotifyHeldFn
+	t.Cleanup(func() { quietIdleCrewFn, quietRunGtFn, quietNotifyHeldFn = origIdle, origRun, origNotify })
+
+	quietIdleCrewFn = func(rigName string, idle time.Duration) ([]string, error) {
+		if rigName == "greenplace" {
+			return []string{"max"}, nil
+		}
+		return []string{"joe"}, nil
+	}
+	var calls []string
+	quietRunGtFn = func(_ string, args ...string) error {
+		calls = append(calls, strings.Join(args, " "))
+		return nil
+	}
+	notified := make(map[string]int)
+	quietNotifyHeldFn = func(to string, msgs []quiet.Held) error {
+		notified[to] += len(msgs)
+		return nil
+	}
+
+	night := time.Date(2026, 10, 12, 23, 0, 0, 0, time.Local)
+	if err := quietTick(townRoot, night); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 || calls[0] != "crew stop greenplace/max --force" {
+		t.Fatalf("night calls = %v, want only greenplace/max parked", calls)
+	}
+	state, _ := quiet.LoadState(townRoot)
+	if _, ok := state.Active["greenplace"]; !ok || len(state.ParkedCrew["greenplace"]) != 1 {
+		t.Errorf("state = %+v, want greenplace quiet with max parked", state)
+	}
+
+	_ = quiet.Hold(townRoot, quiet.Held{Scope: "greenplace", To: "greenplace/Toast", From: "mayor/"})
+	_ = quiet.Hold(townRoot, quiet.Held{Scope: "greenplace", To: "greenplace/Toast", From: "greenplace/witness"})
+
+	calls = nil
+	morning := time.Date(2026, 10, 13, 7, 5, 0, 0, time.Local)
+	if err := quietTick(townRoot, morning); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 || calls[0] != "crew start greenplace max --resume" {
+		t.Errorf("morning calls = %v, want max restarted", calls)
+	}
+	if notified["greenplace/Toast"] != 2 {
+		t.Errorf("notified = %v, want one summary covering 2 messages for greenplace/Toast", notified)
+	}
+	state, _ = quiet.LoadState(townRoot)
+	if len(state.Active) != 0 || len(state.ParkedCrew) != 0 {
+		t.Errorf("state after window = %+v, want empty", state)
+	}
+	if quiet.Pending(townRoot) {
+		t.Error("Pending() after resume = true")
+	}
+}
+
+func TestHeldMailSummary(t *testing.T) {
+	msg := heldMailSummary([]quiet.Held{{From: "mayor/"}, {From: "greenplace/witness"}, {From: "mayor/"}})
+	if !strings.Contains(msg, "3 message(s)") || !strings.Contains(msg, "mayor/, greenplace/witness") {
+		t.Errorf("summary = %q, want count and distinct senders", msg)
+	}
+}
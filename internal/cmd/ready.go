@@ -385,7 +385,7 @@ func filterFormulaScaffolds(issues []*beads.Issue, formulaNames map[string]bool)
 func getWispIDs(beadsPath string) map[string]bool {
 	cmd := exec.Command("bd", "mol", "wisp", "list", "--json")
 	cmd.Dir = beadsPath
-	output, err := cmd.Output()
+	output, err := beads.CmdOutput(cmd)
 	if err != nil {
 		return nil // Wisp table may not exist or Dolt unavailable
 	}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/refinery"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	refineryBatchDryRun bool
+	refineryBatchJSON   bool
+)
+
+var refineryBatchCmd = &cobra.Command{
+	Use:   "batch [rig]",
+	Short: "Land the next batch of ready MRs together",
+	Long: `Land several ready merge requests in one pass (batch mode).
+
+Takes up to max_batch_size ready MRs for one target branch, squash-merges
+them onto the target in queue order, and runs the gates once on the tip
+of the stack. If the gates pass, the whole stack is pushed. If they fail,
+the batch is retried once (when retry_batch_on_flaky is set) and then
+bisected to find the culprit: the good MRs land, the culprit is bounced to
+its polecat. MRs that conflict with the stack are pulled out of it and get
+a conflict-resolution task, as in serial processing.
+
+While fewer than max_batch_size MRs are ready, the batch waits up to
+batch_wait_time (counted from the oldest ready MR) to fill.
+
+Batch mode is enabled in the rig's config.json:
+  "merge_queue": {
+    "batch": {
+      "max_batch_size": 5,
+      "batch_wait_time": "30s",
+      "retry_batch_on_flaky": true
+    }
+  }
+
+Examples:
+  gt refinery batch                # Land the next batch for this rig
+  gt refinery batch greenplace -n  # Show the batch that would be taken`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRefineryBatch,
+}
+
+func init() {
+	refineryBatchCmd.Flags().BoolVarP(&refineryBatchDryRun, "dry-run", "n", false, "Show the next batch without claiming or merging it")
+	refineryBatchCmd.Flags().BoolVar(&refineryBatchJSON, "json", false, "Output as JSON")
+	refineryCmd.AddCommand(refineryBatchCmd)
+}
+
+func runRefineryBatch(cmd *cobra.Command, args []string) error {
+	rigName := ""
+	if len(args) > 0 {
+		rigName = args[0]
+	}
+	_, r, rigName, err := getRefineryManager(rigName)
+	if err != nil {
+		return err
+	}
+
+	eng := refinery.NewEngineer(r)
+	if err := eng.LoadConfig(); err != nil {
+		return fmt.Errorf("loading merge queue config: %w", err)
+	}
+	if refineryBatchJSON {
+		// Keep stdout for the JSON document.
+		eng.SetOutput(os.Stderr)
+	}
+
+	var cycle *refinery.BatchCycle
+	if refineryBatchDryRun {
+		cycle, err = eng.NextBatch(time.Now())
+	} else {
+		cycle, err = eng.RunBatchCycle(context.Background(), getWorkerID(), time.Now())
+	}
+	if err != nil {
+		return err
+	}
+
+	if refineryBatchJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(batchCycleJSON(cycle))
+	}
+	printBatchCycle(rigName, cycle)
+	return nil
+}
+
+// batchCycleOutput is the JSON form of a batch cycle, by MR ID.
+type batchCycleOutput struct {
+	Target      string   `json:"target,omitempty"`
+	Batch       []string `json:"batch"`
+	Wait        string   `json:"wait,omitempty"`
+	Merged      []string `json:"merged,omitempty"`
+	Culprits    []string `json:"culprits,omitempty"`
+	Conflicts   []string `json:"conflicts,omitempty"`
+	MergeCommit string   `json:"merge_commit,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+func batchCycleJSON(cycle *refinery.BatchCycle) batchCycleOutput {
+	out := batchCycleOutput{Target: cycle.Target, Batch: batchMRIDs(cycle.Batch)}
+	if cycle.Wait > 0 {
+		out.Wait = cycle.Wait.Round(time.Second).String()
+	}
+	if res := cycle.Result; res != nil {
+		out.Merged = batchMRIDs(res.Merged)
+		out.Culprits = batchMRIDs(res.Culprits)
+		out.Conflicts = batchMRIDs(res.Conflicts)
+		out.MergeCommit = res.MergeCommit
+		if res.Error != nil {
+			out.Error = res.Error.Error()
+		}
+	}
+	return out
+}
+
+func batchMRIDs(mrs []*refinery.MRInfo) []string {
+	ids := make([]string, 0, len(mrs))
+	for _, mr := range mrs {
+		ids = append(ids, mr.ID)
+	}
+	return ids
+}
+
+func printBatchCycle(rigName string, cycle *refinery.BatchCycle) {
+	switch {
+	case cycle.Wait > 0:
+		fmt.Printf("%s Batch for %s → %s is filling; processing in %s\n",
+			style.Dim.Render("○"), rigName, cycle.Target, cycle.Wait.Round(time.Second))
+		return
+	case len(cycle.Batch) == 0:
+		fmt.Printf("%s No ready MRs for '%s'\n", style.Dim.Render("○"), rigName)
+		return
+	}
+
+	if cycle.Result == nil {
+		fmt.Printf("%s Next batch for '%s' (%d MRs → %s):\n\n", style.Bold.Render("📦"), rigName, len(cycle.Batch), cycle.Target)
+		for i, mr := range cycle.Batch {
+			fmt.Printf("  %d. [P%d] %s\n", i+1, mr.Priority, mr.Branch)
+			fmt.Printf("     ID: %s  Worker: %s\n", mr.ID, mr.Worker)
+		}
+		return
+	}
+
+	res := cycle.Result
+	fmt.Printf("\n%s Batch of %d MRs → %s\n", style.Bold.Render("📦"), len(cycle.Batch), cycle.Target)
+	for _, mr := range res.Merged {
+		fmt.Printf("  %s merged     %s (%s)\n", style.Success.Render("✓"), mr.ID, mr.Branch)
+	}
+	for _, mr := range res.Culprits {
+		fmt.Printf("  %s failed     %s (%s)\n", style.Error.Render("✗"), mr.ID, mr.Branch)
+	}
+	for _, mr := range res.Conflicts {
+		fmt.Printf("  %s not merged %s (%s)\n", style.Warning.Render("⚠"), mr.ID, mr.Branch)
+	}
+	if res.MergeCommit != "" {
+		fmt.Printf("  Merge commit: %s\n", res.MergeCommit)
+	}
+	if res.Error != nil {
+		style.PrintWarning("batch failed: %v (unmerged MRs released for the next cycle)", res.Error)
+	}
+}
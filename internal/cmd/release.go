@@ -14,7 +14,7 @@ var releaseReason string
 var releaseCmd = &cobra.Command{
 	Use:     "release <issue-id>...",
 	GroupID: GroupWork,
-	Short:   "Release stuck in_progress issues back to pending",
+	Short:   "Release stuck issues, or run a rig's release workflow",
 	Long: `Release one or more in_progress issues back to open/pending status.
 
 This is used to recover stuck steps when a worker dies mid-task.
@@ -27,7 +27,30 @@ Examples:
   gt release gt-abc -r "worker died"  # Release with reason
 
 This implements nondeterministic idempotence - work can be safely
-retried by releasing and reclaiming stuck steps.`,
+retried by releasing and reclaiming stuck steps.
+
+Release workflow:
+  gt release notes gastown    # Draft notes for the changes since the last tag
+  gt release start gastown    # File a release bead and sling the release formula
+  gt release gate <bead> tag  # Ask for approval before a release step
+  gt release status           # Releases and their gates
+
+A release collects the beads merged since the last tag, drafts release
+notes from their commits and change summaries, and runs the rig's release
+formula (version bump, changelog, tag, publish) as the work of a release
+bead. Each checklist step can be gated: the agent running the formula asks
+the approver by mail and waits for 'gt mail approve'. Configure it in the
+rig's settings/config.json (all optional):
+
+  "release": {
+    "formula": "mol-release",
+    "tag_prefix": "v",
+    "gates": ["version-bump", "changelog", "tag", "publish"],
+    "approver": "overseer",
+    "assignee": "gastown/crew/max",
+    "bump_command": "./scripts/bump-version.sh",
+    "publish_command": "goreleaser release --clean"
+  }`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runRelease,
 }
@@ -0,0 +1,651 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/release"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	releaseSince   string
+	releaseTo      string
+	releaseVersion string
+	releaseBead    string
+	releaseJSON    bool
+
+	releaseStartTarget  string
+	releaseStartDryRun  bool
+	releaseStartNoSling bool
+
+	releaseGateMessage string
+	releaseGateWait    bool
+	releaseGateTimeout time.Duration
+)
+
+var releaseNotesCmd = &cobra.Command{
+	Use:   "notes [rig]",
+	Short: "Draft release notes for the changes since the last tag",
+	Long: `Draft release notes for a rig: the commits on its default branch since
+the last release tag, grouped into breaking changes, features, fixes and
+other changes (by conventional-commit type), each with its bead, followed
+by the dependency and API changes from the beads' change summaries.
+
+With --bead, redraws the notes of a started release.
+
+Examples:
+  gt release notes gastown
+  gt release notes gastown --since v0.11.0 --version 0.12.0
+  gt release notes --bead gt-rel12
+  gt release notes gastown --json`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{AnnotationPolecatSafe: "true"},
+	RunE:        runReleaseNotes,
+}
+
+var releaseStartCmd = &cobra.Command{
+	Use:   "start <rig> [version]",
+	Short: "Start a release: file its bead and sling the release formula",
+	Long: `Start a release of a rig.
+
+Drafts the notes, files a release bead holding them, and slings the rig's
+release formula onto the bead with the version and tag as variables.
+Without a version, the next one is picked from the changes since the last
+tag: breaking changes bump the major version (the minor before 1.0.0),
+features the minor and everything else the patch.
+
+The formula goes to release.assignee, or --to, or a polecat in the rig.
+
+Examples:
+  gt release start gastown
+  gt release start gastown 1.0.0 --to gastown/crew/max
+  gt release start gastown --dry-run
+  gt release start gastown --no-sling     # File the bead only`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runReleaseStart,
+}
+
+var releaseGateCmd = &cobra.Command{
+	Use:   "gate <release-bead> <step>",
+	Short: "Ask for approval before a release step",
+	Long: `Check, and if needed request, approval for a release checklist step
+(version-bump, changelog, tag or publish).
+
+The first call mails an approval request to the rig's release approver
+(--message adds context, such as the changelog). Later calls look for the
+decision. Exit codes: 0 approved (or the step isn't gated), 1 rejected,
+2 still pending. With --wait, polls until decided.
+
+When the approver runs the gate themselves, it is approved on the spot.
+
+Examples:
+  gt release gate gt-rel12 tag --wait
+  gt release gate gt-rel12 changelog -m "$(gt release notes --bead gt-rel12)"`,
+	Args:        cobra.ExactArgs(2),
+	Annotations: map[string]string{AnnotationPolecatSafe: "true"},
+	RunE:        runReleaseGate,
+}
+
+var releaseStatusCmd = &cobra.Command{
+	Use:   "status [release-bead]",
+	Short: "Show releases and their gates",
+	Long: `Without a bead, lists the releases of every rig. With one, shows the
+release's version, tag, beads and the state of each gate.
+
+Examples:
+  gt release status
+  gt release status gt-rel12 --json`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{AnnotationPolecatSafe: "true"},
+	RunE:        runReleaseStatus,
+}
+
+// releaseGatePoll is how often gt release gate --wait checks for a decision.
+const releaseGatePoll = 30 * time.Second
+
+var (
+	// releaseCreateFn is a seam for tests. Production creates the bead in the
+	// rig database.
+	releaseCreateFn = func(townRoot, rigName, title, description string) (string, error) {
+		issue, err := beads.New(rigBeadsWorkDir(townRoot, rigName)).Create(beads.CreateOptions{
+			Title:       title,
+			Labels:      []string{"gt:task", "release"},
+			Priority:    1,
+			Description: description,
+			Actor:       "release",
+		})
+		if err != nil {
+			return "", err
+		}
+		return issue.ID, nil
+	}
+
+	// releaseSlingFn is a seam for tests. Production runs gt sling in the town.
+	releaseSlingFn = func(townRoot string, args []string) error {
+		cmd := exec.Command("gt", append([]string{"sling"}, args...)...)
+		cmd.Dir = townRoot
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%v (%s)", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	// releaseCommentFn is a seam for tests. Production runs bd comments add.
+	releaseCommentFn = func(townRoot, rigName, bead, text string) error {
+		_, err := beads.New(rigBeadsWorkDir(townRoot, rigName)).Run("comments", "add", bead, text)
+		return err
+	}
+
+	// releaseBeadStatusFn is a seam for tests. Production reads the bead status
+	// with bd show.
+	releaseBeadStatusFn = func(townRoot, rigName, bead string) (string, error) {
+		issue, err := beads.New(rigBeadsWorkDir(townRoot, rigName)).Show(bead)
+		if err != nil {
+			return "", err
+		}
+		return issue.Status, nil
+	}
+
+	// releaseSendMailFn is a seam for tests. Production sends through the town
+	// router.
+	releaseSendMailFn = func(townRoot string, msg *mail.Message) error {
+		router := mail.NewRouter(townRoot)
+		defer router.WaitPendingNotifications()
+		return router.Send(msg)
+	}
+
+	// releaseFindDecisionFn is a seam for tests. Production returns the reply
+	// carrying a decision in thread, from address's mailbox, or nil if there is
+	// none yet.
+	releaseFindDecisionFn = func(townRoot, address, thread string) (*mail.Message, error) {
+		mailbox, err := mail.NewRouter(townRoot).GetMailbox(address)
+		if err != nil {
+			return nil, err
+		}
+		messages, err := mailbox.List()
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range messages {
+			if m.ThreadID == thread && m.Fields[mail.FieldDecision] != "" {
+				_ = mailbox.MarkReadOnly(m.ID)
+				return m, nil
+			}
+		}
+		return nil, nil
+	}
+
+	// releaseSenderFn is a seam for tests. Production uses detectSender.
+	releaseSenderFn = detectSender
+
+	// releaseSleepFn is a seam for tests. Production uses time.Sleep.
+	releaseSleepFn = time.Sleep
+)
+
+func init() {
+	releaseNotesCmd.Flags().StringVar(&releaseSince, "since", "", "Tag to start from (default: the last release tag)")
+	releaseNotesCmd.Flags().StringVar(&releaseTo, "to", "", "Ref to end at (default: origin/<default branch>)")
+	releaseNotesCmd.Flags().StringVar(&releaseVersion, "version", "", "Version to title the notes with (default: the next version)")
+	releaseNotesCmd.Flags().StringVar(&releaseBead, "bead", "", "Redraw the notes of this release")
+	releaseNotesCmd.Flags().BoolVar(&releaseJSON, "json", false, "Output as JSON")
+
+	releaseStartCmd.Flags().StringVar(&releaseSince, "since", "", "Tag to start from (default: the last release tag)")
+	releaseStartCmd.Flags().StringVar(&releaseStartTarget, "to", "", "Sling the release formula to this agent or rig")
+	releaseStartCmd.Flags().BoolVarP(&releaseStartDryRun, "dry-run", "n", false, "Show the notes and what would be done")
+	releaseStartCmd.Flags().BoolVar(&releaseStartNoSling, "no-sling", false, "File the release bead without slinging the formula")
+
+	releaseGateCmd.Flags().StringVarP(&releaseGateMessage, "message", "m", "", "Context for the approver")
+	releaseGateCmd.Flags().BoolVar(&releaseGateWait, "wait", false, "Wait for the decision")
+	releaseGateCmd.Flags().DurationVar(&releaseGateTimeout, "timeout", 24*time.Hour, "Give up waiting after this long (exit 2)")
+
+	releaseStatusCmd.Flags().BoolVar(&releaseJSON, "json", false, "Output as JSON")
+
+	releaseCmd.AddCommand(releaseNotesCmd)
+	releaseCmd.AddCommand(releaseStartCmd)
+	releaseCmd.AddCommand(releaseGateCmd)
+	releaseCmd.AddCommand(releaseStatusCmd)
+}
+
+// loadReleaseConfig returns a rig's release config (nil for all defaults).
+func loadReleaseConfig(rigPath string) (*release.Config, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return settings.Release, nil
+}
+
+// draftRelease drafts the notes for a rig's next release from since (the
+// last release tag when empty) to to (the rig's default branch on origin
+// when empty). The notes are titled with version, or the next version.
+func draftRelease(r *rig.Rig, cfg *release.Config, since, to, version string) (*release.Notes, error) {
+	dir := askWorkDir(r.Path)
+	if err := git.NewGit(dir).Fetch("origin"); err != nil {
+		style.PrintWarning("fetching %s: %v (drafting from local refs)", r.Name, err)
+	}
+	if to == "" {
+		to = "origin/" + r.DefaultBranch()
+	}
+	prefix := cfg.GetTagPrefix()
+	if since == "" {
+		tag, err := release.LastTag(dir, prefix, to)
+		if err != nil {
+			return nil, err
+		}
+		since = tag
+	}
+	notes, err := release.Collect(dir, r.Path, since, to)
+	if err != nil {
+		return nil, err
+	}
+	if version == "" {
+		version, err = release.NextVersion(strings.TrimPrefix(since, prefix), notes.Kind())
+		if err != nil {
+			return nil, fmt.Errorf("last tag %s: %w (pass a version)", since, err)
+		}
+	}
+	version = strings.TrimPrefix(version, prefix)
+	if !release.ValidVersion(version) {
+		return nil, fmt.Errorf("version %q is not MAJOR.MINOR.PATCH", version)
+	}
+	notes.Rig = r.Name
+	notes.Version = version
+	notes.Tag = prefix + version
+	return notes, nil
+}
+
+func runReleaseNotes(cmd *cobra.Command, args []string) error {
+	var notes *release.Notes
+	if releaseBead != "" {
+		r, rel, err := findRelease(releaseBead)
+		if err != nil {
+			return err
+		}
+		notes, err = release.Collect(askWorkDir(r.Path), r.Path, rel.PreviousTag, rel.Commit)
+		if err != nil {
+			return err
+		}
+		notes.Rig, notes.Version, notes.Tag = rel.Rig, rel.Version, rel.Tag
+	} else {
+		if len(args) == 0 {
+			return fmt.Errorf("a rig (or --bead) is required")
+		}
+		_, r, err := getRig(args[0])
+		if err != nil {
+			return err
+		}
+		cfg, err := loadReleaseConfig(r.Path)
+		if err != nil {
+			return err
+		}
+		notes, err = draftRelease(r, cfg, releaseSince, releaseTo, releaseVersion)
+		if err != nil {
+			return err
+		}
+	}
+
+	if releaseJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(notes)
+	}
+	fmt.Print(notes.Markdown())
+	return nil
+}
+
+func runReleaseStart(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	cfg, err := loadReleaseConfig(r.Path)
+	if err != nil {
+		return err
+	}
+	version := ""
+	if len(args) > 1 {
+		version = args[1]
+	}
+	notes, err := draftRelease(r, cfg, releaseSince, "", version)
+	if err != nil {
+		return err
+	}
+	return startRelease(townRoot, r, cfg, notes)
+}
+
+// startRelease files the release bead for notes, records the release and
+// slings the release formula onto the bead.
+func startRelease(townRoot string, r *rig.Rig, cfg *release.Config, notes *release.Notes) error {
+	if len(notes.Entries) == 0 {
+		return fmt.Errorf("nothing to release: no changes since %s", notes.PreviousTag)
+	}
+	target := releaseStartTarget
+	if target == "" {
+		target = cfg.Assignee
+	}
+	if target == "" {
+		target = r.Name
+	}
+	slingArgs := func(bead string) []string {
+		return []string{cfg.GetFormula(), "--on", bead, target,
+			"--var", "version=" + notes.Version,
+			"--var", "tag=" + notes.Tag,
+			"--var", "previous_tag=" + notes.PreviousTag,
+			"--var", "bump_command=" + cfg.BumpCommand,
+			"--var", "publish_command=" + cfg.PublishCommand,
+		}
+	}
+
+	title := fmt.Sprintf("Release %s %s", r.Name, notes.Tag)
+	if releaseStartDryRun {
+		fmt.Print(notes.Markdown())
+		fmt.Printf("\n%s would file %q with these notes (%d commits, %d beads)\n",
+			style.Dim.Render("[dry-run]"), title, len(notes.Entries), len(notes.Beads()))
+		if !releaseStartNoSling {
+			fmt.Printf("%s would run: gt sling %s\n", style.Dim.Render("[dry-run]"), strings.Join(slingArgs("<bead>"), " "))
+		}
+		return nil
+	}
+
+	id, err := releaseCreateFn(townRoot, r.Name, title, notes.Markdown())
+	if err != nil {
+		return fmt.Errorf("filing release bead: %w", err)
+	}
+	rel := &release.Release{
+		Bead:        id,
+		Rig:         r.Name,
+		Version:     notes.Version,
+		Tag:         notes.Tag,
+		PreviousTag: notes.PreviousTag,
+		Commit:      notes.Head,
+		Formula:     cfg.GetFormula(),
+		Beads:       notes.Beads(),
+		StartedAt:   time.Now().UTC(),
+	}
+	if !releaseStartNoSling {
+		rel.Assignee = target
+	}
+	if err := release.Save(r.Path, rel); err != nil {
+		return fmt.Errorf("recording release: %w", err)
+	}
+	fmt.Printf("%s Filed %s: %s (%d commits, %d beads)\n", style.SuccessPrefix, style.Bold.Render(id), title,
+		len(notes.Entries), len(rel.Beads))
+
+	if releaseStartNoSling {
+		fmt.Printf("  Sling it with: gt sling %s\n", strings.Join(slingArgs(id), " "))
+		return nil
+	}
+	if err := releaseSlingFn(townRoot, slingArgs(id)); err != nil {
+		return fmt.Errorf("slinging %s onto %s: %w", rel.Formula, id, err)
+	}
+	fmt.Printf("%s Slung %s onto %s → %s\n", style.SuccessPrefix, rel.Formula, id, target)
+	return nil
+}
+
+// findRelease returns the rig and state of the release tracked by bead.
+func findRelease(bead string) (*rig.Rig, *release.Release, error) {
+	rigs, err := getAllRigs()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, r := range rigs {
+		rel, err := release.Load(r.Path, bead)
+		if err != nil {
+			return nil, nil, err
+		}
+		if rel != nil {
+			return r, rel, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%s is not a release (see gt release status)", bead)
+}
+
+func runReleaseGate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	r, rel, err := findRelease(args[0])
+	if err != nil {
+		return err
+	}
+	cfg, err := loadReleaseConfig(r.Path)
+	if err != nil {
+		return err
+	}
+	return gateRelease(townRoot, r.Path, cfg, rel, args[1])
+}
+
+// gateRelease requests or checks approval of step, exiting 1 if it was
+// rejected and 2 if it is still pending.
+func gateRelease(townRoot, rigPath string, cfg *release.Config, rel *release.Release, step string) error {
+	if !release.IsStep(step) {
+		return fmt.Errorf("unknown step %q (steps: %s)", step, strings.Join(release.Steps, ", "))
+	}
+	if !cfg.Gated(step) {
+		fmt.Printf("%s %s is not gated\n", style.SuccessPrefix, step)
+		return nil
+	}
+
+	g := rel.Gates[step]
+	if g == nil {
+		var err error
+		if g, err = requestGate(townRoot, cfg, rel, step); err != nil {
+			return err
+		}
+		if rel.Gates == nil {
+			rel.Gates = map[string]*release.Gate{}
+		}
+		rel.Gates[step] = g
+		if err := release.Save(rigPath, rel); err != nil {
+			return fmt.Errorf("recording gate: %w", err)
+		}
+	}
+
+	deadline := time.Now().Add(releaseGateTimeout)
+	for g.Pending() {
+		reply, err := releaseFindDecisionFn(townRoot, g.RequestedBy, g.Thread)
+		if err != nil {
+			return fmt.Errorf("checking for a decision: %w", err)
+		}
+		if reply != nil {
+			recordGateDecision(townRoot, rigPath, rel, step, g, reply)
+			break
+		}
+		if !releaseGateWait || !time.Now().Before(deadline) {
+			fmt.Printf("⏳ %s of %s is waiting for approval from %s (requested %s)\n",
+				step, rel.Tag, g.Approver, g.RequestedAt.Local().Format("2006-01-02 15:04"))
+			return NewSilentExit(2)
+		}
+		releaseSleepFn(releaseGatePoll)
+	}
+
+	if g.Decision != mail.DecisionApproved {
+		fmt.Printf("%s %s of %s was rejected by %s: %s\n", style.ErrorPrefix, step, rel.Tag, g.Approver, g.Note)
+		return NewSilentExit(1)
+	}
+	fmt.Printf("%s %s of %s approved by %s\n", style.SuccessPrefix, step, rel.Tag, g.Approver)
+	return nil
+}
+
+// requestGate mails the approval request for step. An approver requesting
+// approval from themselves is approved on the spot.
+func requestGate(townRoot string, cfg *release.Config, rel *release.Release, step string) (*release.Gate, error) {
+	now := time.Now().UTC()
+	g := &release.Gate{Approver: cfg.GetApprover(), RequestedBy: releaseSenderFn(), RequestedAt: now}
+	if g.RequestedBy == g.Approver {
+		g.Decision, g.DecidedAt = mail.DecisionApproved, &now
+		return g, nil
+	}
+
+	fields := map[string]string{
+		mail.FieldAction: fmt.Sprintf("%s for %s %s", step, rel.Rig, rel.Tag),
+		mail.FieldBead:   rel.Bead,
+	}
+	msg := mail.NewMessage(g.RequestedBy, g.Approver,
+		fmt.Sprintf("Release %s: approve %s?", rel.Tag, step),
+		mail.FormatMessageBody(fields, releaseGateMessage))
+	msg.Type = mail.TypeApproval
+	msg.Fields = fields
+	msg.Priority = mail.PriorityHigh
+	if err := releaseSendMailFn(townRoot, msg); err != nil {
+		return nil, fmt.Errorf("requesting approval from %s: %w", g.Approver, err)
+	}
+	g.Thread = msg.ThreadID
+	fmt.Printf("📨 Asked %s to approve %s of %s\n", g.Approver, step, rel.Tag)
+	if err := releaseCommentFn(townRoot, rel.Rig, rel.Bead, fmt.Sprintf("Gate %s: approval requested from %s", step, g.Approver)); err != nil {
+		style.PrintWarning("could not comment on %s: %v", rel.Bead, err)
+	}
+	return g, nil
+}
+
+func recordGateDecision(townRoot, rigPath string, rel *release.Release, step string, g *release.Gate, reply *mail.Message) {
+	now := time.Now().UTC()
+	g.Decision = reply.Fields[mail.FieldDecision]
+	g.DecidedAt = &now
+	if _, note, ok := strings.Cut(reply.Body, "\n\n"); ok {
+		g.Note = strings.TrimSpace(note)
+	}
+	if err := release.Save(rigPath, rel); err != nil {
+		style.PrintWarning("recording decision: %v", err)
+	}
+	comment := fmt.Sprintf("Gate %s: %s by %s", step, g.Decision, g.Approver)
+	if g.Note != "" {
+		comment += ": " + g.Note
+	}
+	if err := releaseCommentFn(townRoot, rel.Rig, rel.Bead, comment); err != nil {
+		style.PrintWarning("could not comment on %s: %v", rel.Bead, err)
+	}
+}
+
+func runReleaseStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		r, rel, err := findRelease(args[0])
+		if err != nil {
+			return err
+		}
+		cfg, err := loadReleaseConfig(r.Path)
+		if err != nil {
+			return err
+		}
+		if releaseJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(rel)
+		}
+		printRelease(townRoot, cfg, rel)
+		return nil
+	}
+
+	rigs, err := getAllRigs()
+	if err != nil {
+		return err
+	}
+	var all []*release.Release
+	for _, r := range rigs {
+		list, err := release.List(r.Path)
+		if err != nil {
+			return fmt.Errorf("reading releases of %s: %w", r.Name, err)
+		}
+		all = append(all, list...)
+	}
+	if releaseJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if all == nil {
+			all = []*release.Release{}
+		}
+		return enc.Encode(all)
+	}
+	if len(all) == 0 {
+		fmt.Println("No releases started (see gt release start)")
+		return nil
+	}
+	for _, rel := range all {
+		status, err := releaseBeadStatusFn(townRoot, rel.Rig, rel.Bead)
+		if err != nil {
+			status = "unknown"
+		}
+		fmt.Printf("  %s  %-12s %-10s %-10s %s\n", rel.StartedAt.Local().Format("2006-01-02"), rel.Bead,
+			rel.Rig, rel.Tag, style.Dim.Render(status+gateSummary(rel)))
+	}
+	return nil
+}
+
+// gateSummary names the gates waiting on a decision, e.g. ", waiting: tag".
+func gateSummary(rel *release.Release) string {
+	var pending []string
+	for _, step := range release.Steps {
+		if g := rel.Gates[step]; g != nil && g.Pending() {
+			pending = append(pending, step)
+		}
+	}
+	if len(pending) == 0 {
+		return ""
+	}
+	return ", waiting: " + strings.Join(pending, ", ")
+}
+
+func printRelease(townRoot string, cfg *release.Config, rel *release.Release) {
+	status, err := releaseBeadStatusFn(townRoot, rel.Rig, rel.Bead)
+	if err != nil {
+		status = "unknown"
+	}
+	fmt.Printf("%s %s %s  %s\n", style.Bold.Render("🚀"), style.Bold.Render(rel.Bead), rel.Tag, style.Dim.Render(status))
+	since := rel.PreviousTag
+	if since == "" {
+		since = "the first commit"
+	}
+	fmt.Printf("   %s\n\n", style.Dim.Render(fmt.Sprintf("%s, changes since %s, formula %s, started %s",
+		rel.Rig, since, rel.Formula, rel.StartedAt.Local().Format("2006-01-02 15:04"))))
+	if rel.Assignee != "" {
+		fmt.Printf("  Assignee: %s\n", rel.Assignee)
+	}
+	fmt.Printf("  Beads:    %d", len(rel.Beads))
+	if len(rel.Beads) > 0 {
+		fmt.Printf(" (%s)", strings.Join(rel.Beads, ", "))
+	}
+	fmt.Println()
+
+	fmt.Println("\n  Gates:")
+	for _, step := range release.Steps {
+		g := rel.Gates[step]
+		var state string
+		switch {
+		case !cfg.Gated(step) && g == nil:
+			state = style.Dim.Render("not gated")
+		case g == nil:
+			state = "not requested"
+		case g.Pending():
+			state = fmt.Sprintf("⏳ waiting for %s since %s", g.Approver, g.RequestedAt.Local().Format("2006-01-02 15:04"))
+		case g.Decision == mail.DecisionApproved:
+			state = fmt.Sprintf("✓ approved by %s", g.Approver)
+		default:
+			state = fmt.Sprintf("✗ rejected by %s", g.Approver)
+			if g.Note != "" {
+				state += ": " + g.Note
+			}
+		}
+		fmt.Printf("    %-13s %s\n", step, state)
+	}
+}
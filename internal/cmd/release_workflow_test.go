@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/release"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// fakeRelease stands in for bd, gt sling and mail: the beads filed, the
+// sling arguments, bead comments, approval requests and the decision replies
+// by thread.
+type fakeRelease struct {
+	filed     []string
+	slung     [][]string
+	comments  []string
+	requests  []*mail.Message
+	decisions map[string]*mail.Message
+	sender    string
+	sleeps    int
+}
+
+func newFakeRelease(t *testing.T) *fakeRelease {
+	t.Helper()
+	origCreate, origSling, origComment := releaseCreateFn, releaseSlingFn, releaseCommentFn
+	origSend, origFind, origSender, origSleep := releaseSendMailFn, releaseFindDecisionFn, releaseSenderFn, releaseSleepFn
+	origTarget, origDryRun, origNoSling := releaseStartTarget, releaseStartDryRun, releaseStartNoSling
+	origMessage, origWait := releaseGateMessage, releaseGateWait
+	t.Cleanup(func() {
+		releaseCreateFn, releaseSlingFn, releaseCommentFn = origCreate, origSling, origComment
+		releaseSendMailFn, releaseFindDecisionFn, releaseSenderFn, releaseSleepFn = origSend, origFind, origSender, origSleep
+		releaseStartTarget, releaseStartDryRun, releaseStartNoSling = origTarget, origDryRun, origNoSling
+		releaseGateMessage, releaseGateWait = origMessage, origWait
+	})
+	f := &fakeRelease{decisions: map[string]*mail.Message{}, sender: "gastown/polecats/nux"}
+	releaseCreateFn = func(townRoot, rigName, title, description string) (string, error) {
+		f.filed = append(f.filed, title+"\n"+description)
+		return "gt-rel1", nil
+	}
+	releaseSlingFn = func(townRoot string, args []string) error {
+		f.slung = append(f.slung, args)
+		return nil
+	}
+	releaseCommentFn = func(townRoot, rigName, bead, text string) error {
+		f.comments = append(f.comments, text)
+		return nil
+	}
+	releaseSendMailFn = func(townRoot string, msg *mail.Message) error {
+		f.requests = append(f.requests, msg)
+		return nil
+	}
+	releaseFindDecisionFn = func(townRoot, address, thread string) (*mail.Message, error) {
+		return f.decisions[thread], nil
+	}
+	releaseSenderFn = func() string { return f.sender }
+	releaseSleepFn = func(time.Duration) { f.sleeps++ }
+	return f
+}
+
+func (f *fakeRelease) decide(thread, decision, note string) {
+	fields := map[string]string{mail.FieldDecision: decision}
+	f.decisions[thread] = &mail.Message{ThreadID: thread, Fields: fields, Body: mail.FormatMessageBody(fields, note)}
+}
+
+func testNotes() *release.Notes {
+	return &release.Notes{
+		Version:     "0.2.0",
+		Tag:         "v0.2.0",
+		PreviousTag: "v0.1.0",
+		Head:        "abc123",
+		Entries: []release.Entry{
+			{Commit: "abc123", Subject: "approval gates", Kind: release.KindFeature, Bead: "gt-feat2"},
+		},
+	}
+}
+
+func TestStartRelease(t *testing.T) {
+	f := newFakeRelease(t)
+	r := &rig.Rig{Name: "gastown", Path: t.TempDir()}
+	cfg := &release.Config{BumpCommand: "./bump.sh"}
+
+	if err := startRelease("/town", r, cfg, &release.Notes{PreviousTag: "v0.1.0"}); err == nil {
+		t.Error("a release without changes should not start")
+	}
+
+	if err := startRelease("/town", r, cfg, testNotes()); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.filed) != 1 || !strings.HasPrefix(f.filed[0], "Release gastown v0.2.0\n## v0.2.0") {
+		t.Errorf("filed = %q", f.filed)
+	}
+	if len(f.slung) != 1 {
+		t.Fatalf("slung = %v", f.slung)
+	}
+	args := strings.Join(f.slung[0], " ")
+	for _, want := range []string{"mol-release --on gt-rel1 gastown", "version=0.2.0", "tag=v0.2.0", "previous_tag=v0.1.0", "bump_command=./bump.sh"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("sling args %q missing %q", args, want)
+		}
+	}
+
+	rel, err := release.Load(r.Path, "gt-rel1")
+	if err != nil || rel == nil {
+		t.Fatalf("Load = %v, %v", rel, err)
+	}
+	if rel.Tag != "v0.2.0" || rel.Assignee != "gastown" || strings.Join(rel.Beads, ",") != "gt-feat2" {
+		t.Errorf("release = %+v", rel)
+	}
+}
+
+func TestGateRelease(t *testing.T) {
+	f := newFakeRelease(t)
+	rigPath := t.TempDir()
+	cfg := &release.Config{Gates: []string{release.StepTag}}
+	rel := &release.Release{Bead: "gt-rel1", Rig: "gastown", Tag: "v0.2.0"}
+
+	// Ungated steps pass without asking.
+	if err := gateRelease("/town", rigPath, cfg, rel, release.StepChangelog); err != nil {
+		t.Errorf("ungated step: %v", err)
+	}
+	if err := gateRelease("/town", rigPath, cfg, rel, "deploy"); err == nil {
+		t.Error("unknown step should fail")
+	}
+
+	// The first call requests approval and reports pending.
+	err := gateRelease("/town", rigPath, cfg, rel, release.StepTag)
+	var exit *SilentExitError
+	if !errors.As(err, &exit) || exit.Code != 2 {
+		t.Fatalf("pending gate = %v, want exit 2", err)
+	}
+	if len(f.requests) != 1 || f.requests[0].Type != mail.TypeApproval || f.requests[0].To != "overseer" ||
+		f.requests[0].Fields[mail.FieldBead] != "gt-rel1" {
+		t.Fatalf("requests = %+v", f.requests)
+	}
+	thread := f.requests[0].ThreadID
+
+	// Asking again doesn't send a second request.
+	_ = gateRelease("/town", rigPath, cfg, rel, release.StepTag)
+	if len(f.requests) != 1 {
+		t.Errorf("requests = %d, want 1", len(f.requests))
+	}
+
+	// --wait polls until the decision arrives.
+	releaseGateWait = true
+	releaseSleepFn = func(time.Duration) {
+		f.sleeps++
+		f.decide(thread, mail.DecisionApproved, "")
+	}
+	if err := gateRelease("/town", rigPath, cfg, rel, release.StepTag); err != nil {
+		t.Fatalf("approved gate: %v", err)
+	}
+	if f.sleeps != 1 {
+		t.Errorf("sleeps = %d, want 1", f.sleeps)
+	}
+	saved, _ := release.Load(rigPath, "gt-rel1")
+	if g := saved.Gates[release.StepTag]; g == nil || g.Decision != mail.DecisionApproved || g.DecidedAt == nil {
+		t.Errorf("saved gate = %+v", g)
+	}
+	if last := f.comments[len(f.comments)-1]; last != "Gate tag: approved by overseer" {
+		t.Errorf("comments = %q", f.comments)
+	}
+}
+
+func TestGateRelease_Rejected(t *testing.T) {
+	f := newFakeRelease(t)
+	rigPath := t.TempDir()
+	rel := &release.Release{Bead: "gt-rel1", Rig: "gastown", Tag: "v0.2.0"}
+
+	_ = gateRelease("/town", rigPath, nil, rel, release.StepPublish)
+	f.decide(f.requests[0].ThreadID, mail.DecisionRejected, "CI is red")
+
+	err := gateRelease("/town", rigPath, nil, rel, release.StepPublish)
+	var exit *SilentExitError
+	if !errors.As(err, &exit) || exit.Code != 1 {
+		t.Fatalf("rejected gate = %v, want exit 1", err)
+	}
+	if g := rel.Gates[release.StepPublish]; g.Note != "CI is red" {
+		t.Errorf("gate = %+v", g)
+	}
+}
+
+func TestGateRelease_ApproverSelf(t *testing.T) {
+	f := newFakeRelease(t)
+	f.sender = "overseer"
+	rel := &release.Release{Bead: "gt-rel1", Rig: "gastown", Tag: "v0.2.0"}
+
+	if err := gateRelease("/town", t.TempDir(), nil, rel, release.StepTag); err != nil {
+		t.Fatalf("self-approved gate: %v", err)
+	}
+	if len(f.requests) != 0 {
+		t.Errorf("the approver shouldn't mail themselves: %+v", f.requests)
+	}
+}
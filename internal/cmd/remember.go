@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/style"
 )
 
@@ -39,7 +40,7 @@ Examples:
   gt remember --key refinery-worktree "Refinery uses worktree, cannot checkout main"
   gt remember "Always use --stdin for multi-line mail"`,
 	Args: cobra.ExactArgs(1),
-	RunE: runRemember,
+	RunE: journaled(runRemember),
 }
 
 func runRemember(cmd *cobra.Command, args []string) error {
@@ -135,14 +136,13 @@ func sanitizeKey(key string) string {
 // bdKvSet calls bd kv set <key> <value>.
 func bdKvSet(key, value string) error {
 	cmd := exec.Command("bd", "kv", "set", key, value)
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return runTeeStderr(cmd)
 }
 
 // bdKvGet calls bd kv get <key> and returns the value.
 func bdKvGet(key string) (string, error) {
 	cmd := exec.Command("bd", "kv", "get", key)
-	out, err := cmd.Output()
+	out, err := beads.CmdOutput(cmd)
 	if err != nil {
 		return "", err
 	}
@@ -153,13 +153,13 @@ func bdKvGet(key string) (string, error) {
 func bdKvClear(key string) error {
 	cmd := exec.Command("bd", "kv", "clear", key)
 	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return beads.RunCmd(cmd)
 }
 
 // bdKvListJSON calls bd kv list --json and returns the parsed map.
 func bdKvListJSON() (map[string]string, error) {
 	cmd := exec.Command("bd", "kv", "list", "--json")
-	out, err := cmd.Output()
+	out, err := beads.CmdOutput(cmd)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/renames"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var polecatRenameCmd = &cobra.Command{
+	Use:   "rename <rig>/<polecat> <new-name>",
+	Short: "Rename a polecat, keeping its history, work and mail",
+	Long: `Rename a polecat without orphaning any of it.
+
+The rename moves:
+  - the polecat's directory and git worktree (its branch keeps its name)
+  - its identity bead, with agent state and hook (the old bead is closed
+    with a pointer to the new one)
+  - beads and unread mail assigned to it
+  - its name pool slot
+
+and records the rename in the town's rename ledger (mayor/renames.json),
+so mail sent to the old address is forwarded, and gt log --agent, gt logs
+export and the polecat's CV still find what happened under the old name.
+The old name goes back to the pool; once reused, it stops forwarding.
+
+The polecat's session must be stopped first.
+
+Examples:
+  gt polecat rename gastown/Toast Imperator`,
+	Args: cobra.ExactArgs(2),
+	RunE: runPolecatRename,
+}
+
+var rigRenameCmd = &cobra.Command{
+	Use:   "rename <old-name> <new-name>",
+	Short: "Rename a rig, keeping its agents' history, work and mail",
+	Long: `Rename a rig and every agent address in it.
+
+The rename moves:
+  - the rig directory, and every git worktree in it
+  - the rig's registration (mayor/rigs.json), config.json, routes.jsonl
+    entry and daemon patrol entries
+  - the identity beads of its agents (witness, refinery, polecats, crew)
+  - beads and unread mail assigned to its agents
+
+and records the rename in the town's rename ledger (mayor/renames.json),
+so mail to old addresses (oldrig/witness, oldrig/Toast) is forwarded and
+history under the old addresses stays linked.
+
+The beads prefix is unchanged, so bead IDs and tmux session names stay the
+same. All of the rig's sessions must be stopped first (gt rig shutdown).
+
+Examples:
+  gt rig shutdown gastown
+  gt rig rename gastown gt_core`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRigRename,
+}
+
+func init() {
+	polecatCmd.AddCommand(polecatRenameCmd)
+	rigCmd.AddCommand(rigRenameCmd)
+}
+
+var (
+	// renameListBeadsFn is a seam for tests. Production runs bd list.
+	renameListBeadsFn = func(bd *beads.Beads, status string) ([]*beads.Issue, error) {
+		return bd.List(beads.ListOptions{Status: status, Priority: -1})
+	}
+
+	// renameSetAssigneeFn is a seam for tests. Production runs bd update.
+	renameSetAssigneeFn = func(bd *beads.Beads, id, assignee string) error {
+		return bd.Update(id, beads.UpdateOptions{Assignee: &assignee})
+	}
+)
+
+func runPolecatRename(cmd *cobra.Command, args []string) error {
+	rigName, oldName, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	oldName = strings.TrimPrefix(oldName, "polecats/")
+	newName := args[1]
+	if oldName == newName {
+		return fmt.Errorf("old and new names are the same")
+	}
+
+	townRoot, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	t := tmux.NewTmux()
+	if running, _ := polecat.NewSessionManager(t, r).IsRunning(oldName); running {
+		return fmt.Errorf("cannot rename: polecat session %s is running (gt session stop %s/%s)", oldName, rigName, oldName)
+	}
+
+	bd := beads.New(r.Path)
+	oldBeadID := polecatBeadIDForRig(r, rigName, oldName)
+	newBeadID := polecatBeadIDForRig(r, rigName, newName)
+	oldIssue, oldFields, _ := bd.GetAgentBead(oldBeadID)
+	if newIssue, _, _ := bd.GetAgentBead(newBeadID); newIssue != nil && newIssue.Status != "closed" {
+		return fmt.Errorf("identity bead %s already exists", newBeadID)
+	}
+
+	mgr := polecat.NewManager(r, git.NewGit(r.Path), t)
+	if err := mgr.Rename(oldName, newName); err != nil {
+		if errors.Is(err, polecat.ErrPolecatNotFound) {
+			return fmt.Errorf("polecat %s/%s not found", rigName, oldName)
+		}
+		if errors.Is(err, polecat.ErrPolecatExists) {
+			return fmt.Errorf("polecat %s/%s already exists", rigName, newName)
+		}
+		return fmt.Errorf("renaming polecat: %w", err)
+	}
+
+	// The polecat now lives under its new name; from here on, failures
+	// are reported and the rest carries on.
+	oldAddr := fmt.Sprintf("%s/polecats/%s", rigName, oldName)
+	newAddr := fmt.Sprintf("%s/polecats/%s", rigName, newName)
+	rename := recordRename(townRoot, renames.KindAgent, oldAddr, newAddr)
+
+	if oldIssue != nil && oldIssue.Status != "closed" && oldFields != nil {
+		title := fmt.Sprintf("Polecat %s in %s", newName, rigName)
+		if err := moveAgentBead(bd, oldBeadID, newBeadID, title, oldFields); err != nil {
+			style.PrintWarning("identity bead: %v", err)
+		}
+	}
+	moved := reassignRenamed([]*beads.Beads{bd, beads.New(townRoot)}, rename)
+
+	fmt.Printf("%s Renamed polecat %s → %s\n", style.SuccessPrefix, oldAddr, newAddr)
+	fmt.Printf("  Worktree: %s\n", mgr.ClonePath(newName))
+	if oldIssue != nil {
+		fmt.Printf("  Identity: %s → %s\n", oldBeadID, newBeadID)
+	}
+	fmt.Printf("  Reassigned: %d bead(s) and message(s)\n", moved)
+	fmt.Printf("  Mail to %s is forwarded\n", oldAddr)
+	return nil
+}
+
+func runRigRename(cmd *cobra.Command, args []string) error {
+	oldName, newName := args[0], args[1]
+	if oldName == newName {
+		return fmt.Errorf("old and new names are the same")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	entry, ok := rigsConfig.Rigs[oldName]
+	if !ok {
+		return fmt.Errorf("rig %q: %w", oldName, rig.ErrRigNotFound)
+	}
+
+	sessions, err := findRigSessions(tmux.NewTmux(), oldName)
+	if err != nil {
+		return fmt.Errorf("could not verify session state for rig %s: %w", oldName, err)
+	}
+	if len(sessions) > 0 {
+		return fmt.Errorf("rig %s has %d running session(s) (%s); stop them first: gt rig shutdown %s",
+			oldName, len(sessions), strings.Join(sessions, ", "), oldName)
+	}
+
+	// Agent beads are named after the rig; collect them before the move.
+	_, r, err := getRig(oldName)
+	if err != nil {
+		return err
+	}
+	bd := beads.New(r.Path)
+	agents, _ := bd.ListAgentBeads()
+
+	mgr := rig.NewManager(townRoot, rigsConfig, git.NewGit(townRoot))
+	if err := mgr.RenameRig(oldName, newName); err != nil {
+		return fmt.Errorf("renaming rig: %w", err)
+	}
+	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+		return fmt.Errorf("saving rigs config: %w", err)
+	}
+
+	// The rig now lives under its new name; from here on, failures are
+	// reported and the rest carries on.
+	rename := recordRename(townRoot, renames.KindRig, oldName, newName)
+
+	if entry.BeadsConfig != nil && entry.BeadsConfig.Prefix != "" {
+		if err := renameRoute(townRoot, entry.BeadsConfig.Prefix+"-", oldName, newName); err != nil {
+			style.PrintWarning("routes.jsonl: %v", err)
+		}
+	}
+	if err := config.RemoveRigFromDaemonPatrols(townRoot, oldName); err != nil {
+		style.PrintWarning("daemon.json patrols: %v", err)
+	} else if err := config.AddRigToDaemonPatrols(townRoot, newName); err != nil {
+		style.PrintWarning("daemon.json patrols: %v", err)
+	}
+
+	bd = beads.New(filepath.Join(townRoot, newName))
+	identities := 0
+	for id, issue := range agents {
+		agentRig, role, name, ok := beads.ParseAgentBeadID(id)
+		if !ok || agentRig != oldName || issue.Status == "closed" {
+			continue
+		}
+		_, fields, err := bd.GetAgentBead(id)
+		if err != nil || fields == nil {
+			continue
+		}
+		newID := beads.AgentBeadIDWithPrefix(beads.ExtractAgentPrefix(id), newName, role, name)
+		title := strings.ReplaceAll(issue.Title, oldName, newName)
+		if err := moveAgentBead(bd, id, newID, title, fields); err != nil {
+			style.PrintWarning("identity bead %s: %v", id, err)
+			continue
+		}
+		identities++
+	}
+	moved := reassignRenamed([]*beads.Beads{bd, beads.New(townRoot)}, rename)
+
+	fmt.Printf("%s Renamed rig %s → %s\n", style.SuccessPrefix, oldName, newName)
+	fmt.Printf("  Directory: %s\n", filepath.Join(townRoot, newName))
+	fmt.Printf("  Identities moved: %d\n", identities)
+	fmt.Printf("  Reassigned: %d bead(s) and message(s)\n", moved)
+	fmt.Printf("  Mail to %s/... is forwarded\n", oldName)
+	fmt.Printf("\nStart the rig again with: %s\n", style.Dim.Render("gt rig start "+newName))
+	return nil
+}
+
+// recordRename adds a rename to the town's ledger and returns a ledger
+// holding just that rename, for moving what the old name owned.
+func recordRename(townRoot, kind, from, to string) *renames.Ledger {
+	ledger, err := renames.Load(townRoot)
+	if err != nil {
+		style.PrintWarning("rename ledger: %v (starting a new one)", err)
+		ledger = &renames.Ledger{}
+	}
+	ledger.Record(kind, from, to)
+	if err := ledger.Save(townRoot); err != nil {
+		style.PrintWarning("saving rename ledger: %v (old addresses won't forward)", err)
+	}
+	return &renames.Ledger{Renames: ledger.Renames[len(ledger.Renames)-1:]}
+}
+
+// moveAgentBead creates an agent's identity bead under newID with its
+// fields and closes the old one with a pointer to it.
+func moveAgentBead(bd *beads.Beads, oldID, newID, title string, fields *beads.AgentFields) error {
+	newFields := *fields
+	if r, _, _, ok := beads.ParseAgentBeadID(newID); ok && r != "" {
+		newFields.Rig = r
+	}
+	if _, err := bd.CreateOrReopenAgentBead(newID, title, &newFields); err != nil {
+		return fmt.Errorf("creating new identity bead: %w", err)
+	}
+	if err := bd.CloseWithReason(fmt.Sprintf("renamed to %s", newID), oldID); err != nil {
+		// Try to clean up new bead
+		_ = bd.CloseWithReason("rename failed", newID)
+		return fmt.Errorf("closing old identity bead: %w", err)
+	}
+	return nil
+}
+
+// reassignRenamed moves open, in-progress and hooked beads (work and
+// unread mail) from the renamed addresses to the new ones. Agent beads are
+// moved by moveAgentBead. It returns how many beads it moved.
+func reassignRenamed(dbs []*beads.Beads, rename *renames.Ledger) int {
+	moved := 0
+	for _, bd := range dbs {
+		for _, status := range []string{"open", "in_progress", beads.StatusHooked} {
+			issues, err := renameListBeadsFn(bd, status)
+			if err != nil {
+				style.PrintWarning("listing %s beads: %v", status, err)
+				continue
+			}
+			for _, issue := range issues {
+				if issue.Assignee == "" || beads.IsAgentBead(issue) {
+					continue
+				}
+				to := rename.Current(issue.Assignee)
+				if to == issue.Assignee {
+					continue
+				}
+				if err := renameSetAssigneeFn(bd, issue.ID, to); err != nil {
+					style.PrintWarning("reassigning %s to %s: %v", issue.ID, to, err)
+					continue
+				}
+				moved++
+			}
+		}
+	}
+	return moved
+}
+
+// formerAddresses returns the addresses an agent had before being renamed,
+// each with when it stopped answering to it.
+func formerAddresses(townRoot, addr string) []renames.Alias {
+	if townRoot == "" {
+		return nil
+	}
+	ledger, err := renames.Load(townRoot)
+	if err != nil {
+		return nil
+	}
+	return ledger.Former(addr)
+}
+
+// renameRoute points a rig's route at its new directory.
+func renameRoute(townRoot, prefix, oldName, newName string) error {
+	beadsDir := filepath.Join(townRoot, ".beads")
+	routes, err := beads.LoadRoutes(beadsDir)
+	if err != nil {
+		return err
+	}
+	for i, r := range routes {
+		if r.Prefix == prefix && (r.Path == oldName || strings.HasPrefix(r.Path, oldName+"/")) {
+			routes[i].Path = newName + strings.TrimPrefix(r.Path, oldName)
+		}
+	}
+	return beads.WriteRoutes(beadsDir, routes)
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/renames"
+)
+
+func TestReassignRenamed(t *testing.T) {
+	origList, origSet := renameListBeadsFn, renameSetAssigneeFn
+	t.Cleanup(func() { renameListBeadsFn, renameSetAssigneeFn = origList, origSet })
+
+	renameListBeadsFn = func(_ *beads.Beads, status string) ([]*beads.Issue, error) {
+		if status != "open" {
+			return nil, nil
+		}
+		return []*beads.Issue{
+			{ID: "gt-1", Assignee: "gastown/polecats/Toast"},
+			{ID: "hq-msg", Assignee: "gastown/Toast"}, // Mail uses the short form
+			{ID: "gt-2", Assignee: "gastown/polecats/nux"},
+			{ID: "gt-3", Assignee: ""},
+			{ID: "gt-gastown-polecat-Toast", Assignee: "gastown/polecats/Toast", Labels: []string{"gt:agent"}},
+		}, nil
+	}
+	var got []string
+	renameSetAssigneeFn = func(_ *beads.Beads, id, assignee string) error {
+		got = append(got, id+"="+assignee)
+		return nil
+	}
+
+	rename := &renames.Ledger{}
+	rename.Record(renames.KindAgent, "gastown/polecats/Toast", "gastown/polecats/Imperator")
+	if n := reassignRenamed([]*beads.Beads{nil}, rename); n != 2 {
+		t.Errorf("moved %d, want 2", n)
+	}
+	want := []string{"gt-1=gastown/polecats/Imperator", "hq-msg=gastown/Imperator"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reassigned %q, want %q", got, want)
+	}
+}
+
+func TestRenameRoute(t *testing.T) {
+	town := t.TempDir()
+	beadsDir := filepath.Join(town, ".beads")
+	routes := []beads.Route{
+		{Prefix: "gt-", Path: "gastown/mayor/rig"},
+		{Prefix: "gs-", Path: "gastown_old/mayor/rig"},
+		{Prefix: "hq-", Path: "."},
+	}
+	if err := beads.WriteRoutes(beadsDir, routes); err != nil {
+		t.Fatal(err)
+	}
+	if err := renameRoute(town, "gt-", "gastown", "core"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := beads.LoadRoutes(beadsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []beads.Route{
+		{Prefix: "gt-", Path: "core/mayor/rig"},
+		{Prefix: "gs-", Path: "gastown_old/mayor/rig"},
+		{Prefix: "hq-", Path: "."},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("routes = %+v", got)
+	}
+}
+
+func TestAgentWorkDirs(t *testing.T) {
+	tests := []struct {
+		rig, name string
+		want      []string
+	}{
+		{"gastown", "Toast", []string{"/town/gastown/polecats/Toast/gastown", "/town/gastown/polecats/Toast"}},
+		{"gastown", "polecats/Toast", []string{"/town/gastown/polecats/Toast/gastown", "/town/gastown/polecats/Toast"}},
+		{"gastown", "crew/max", []string{"/town/gastown/crew/max"}},
+	}
+	for _, tt := range tests {
+		got := agentWorkDirs("/town", agentWorkAddress(tt.rig, tt.name))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("agentWorkDirs(%s/%s) = %q", tt.rig, tt.name, got)
+		}
+	}
+}
+
+func TestFormerAddresses(t *testing.T) {
+	town := t.TempDir()
+	if got := formerAddresses(town, "gastown/polecats/Imperator"); len(got) != 0 {
+		t.Errorf("no ledger: %+v", got)
+	}
+	ledger := &renames.Ledger{}
+	ledger.Record(renames.KindAgent, "gastown/polecats/Toast", "gastown/polecats/Imperator")
+	if err := ledger.Save(town); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(renames.Path(town)); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, a := range formerAddresses(town, "gastown/polecats/Imperator") {
+		got = append(got, a.Address)
+	}
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, []string{"gastown/polecats/Toast"}) {
+		t.Errorf("formerAddresses = %q", got)
+	}
+}
@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/persona"
+	"github.com/steveyegge/gastown/internal/replay"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// replayBriefingTimeout bounds the gt prime run that records a briefing.
+const replayBriefingTimeout = 30 * time.Second
+
+var (
+	replayCaptureID string
+	replayAgent     string
+	replayDir       string
+	replayDryRun    bool
+	replayNoStart   bool
+	replayList      bool
+)
+
+var replayCmd = &cobra.Command{
+	Use:         "replay <bead-id>",
+	GroupID:     GroupWork,
+	Annotations: map[string]string{AnnotationFeature: config.FeatureReplay},
+	Short:       "Re-run a bead from its captured starting state",
+	Long: `Re-run a bead in a scratch checkout against the state it was dispatched with.
+
+Every time a bead is slung to a new polecat, gt records a replay capture:
+the bead and molecule as they were, the formula, args and vars, the agent,
+the repo commit the polecat's worktree started from, and the briefing the
+polecat saw from gt prime.
+
+gt replay checks that commit out into a scratch worktree outside the town,
+writes the captured inputs to .replay/ inside it, and starts an agent there
+in its own tmux session (replay-<bead>). The replay is disconnected from the
+town: it has no hook, no mail and no merge queue, so it is safe for
+debugging a bad run or comparing how different agents handle the same work.
+
+Use --agent to replay with a different runtime than the original dispatch.
+
+Examples:
+  gt replay gt-abc              # Replay the latest capture
+  gt replay gt-abc --list       # Show captures for a bead
+  gt replay gt-abc --capture 20261014T091500.000Z
+  gt replay gt-abc --agent gemini
+  gt replay gt-abc --no-start   # Prepare the checkout only`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	replayCmd.Flags().StringVar(&replayCaptureID, "capture", "", "Capture id to replay (default: latest)")
+	replayCmd.Flags().StringVar(&replayAgent, "agent", "", "Agent runtime to replay with (default: the original)")
+	replayCmd.Flags().StringVar(&replayDir, "dir", "", "Scratch checkout directory (default: a new temp dir)")
+	replayCmd.Flags().BoolVarP(&replayDryRun, "dry-run", "n", false, "Show what would be replayed")
+	replayCmd.Flags().BoolVar(&replayNoStart, "no-start", false, "Prepare the checkout without starting an agent")
+	replayCmd.Flags().BoolVar(&replayList, "list", false, "List captures for the bead")
+	rootCmd.AddCommand(replayCmd)
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	beadID := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if replayList {
+		return listReplayCaptures(townRoot, beadID)
+	}
+
+	var c *replay.Capture
+	if replayCaptureID != "" {
+		c, err = replay.Load(townRoot, beadID, replayCaptureID)
+	} else {
+		c, err = replay.Latest(townRoot, beadID)
+	}
+	if err != nil {
+		return err
+	}
+
+	agent := c.Agent
+	if replayAgent != "" {
+		agent = replayAgent
+	}
+	sessionName := "replay-" + beadID
+
+	fmt.Printf("%s Replaying %s (capture %s)\n", style.Bold.Render("⏪"), beadID, c.ID)
+	fmt.Printf("  Rig:     %s @ %s\n", c.Rig, c.RepoSHA)
+	if c.Formula != "" {
+		fmt.Printf("  Formula: %s\n", c.Formula)
+	}
+	if agent != "" {
+		fmt.Printf("  Agent:   %s\n", agent)
+	}
+	if replayDryRun {
+		fmt.Printf("\nWould check out %s into a scratch worktree, write %s/, and start session %s\n",
+			c.RepoSHA, replay.ContextDir, sessionName)
+		return nil
+	}
+
+	t := tmux.NewTmux()
+	if !replayNoStart {
+		if exists, _ := t.HasSession(sessionName); exists {
+			return fmt.Errorf("session %s already exists (kill it with: tmux kill-session -t %s)", sessionName, sessionName)
+		}
+	}
+
+	dir := replayDir
+	if dir == "" {
+		if dir, err = os.MkdirTemp("", "gt-replay-"+beadID+"-"); err != nil {
+			return fmt.Errorf("creating scratch directory: %w", err)
+		}
+	}
+	g := replayRepo(townRoot, c.Rig)
+	if g == nil {
+		return fmt.Errorf("rig %s has no repository to replay from", c.Rig)
+	}
+	if err := g.WorktreeAddDetached(dir, c.RepoSHA); err != nil {
+		return fmt.Errorf("checking out %s: %w", c.RepoSHA, err)
+	}
+	if err := replay.Materialize(c, dir); err != nil {
+		return err
+	}
+	fmt.Printf("%s Scratch checkout at %s\n", style.Bold.Render("✓"), dir)
+
+	if !replayNoStart {
+		rc, _, err := config.ResolveAgentConfigWithOverride(townRoot, filepath.Join(townRoot, c.Rig), agent)
+		if err != nil {
+			return fmt.Errorf("resolving agent: %w", err)
+		}
+		command := config.PrependEnv(rc.BuildCommandWithPrompt(replay.Prompt(c)), map[string]string{"GT_REPLAY": c.ID})
+		if err := t.NewSessionWithCommand(sessionName, dir, command); err != nil {
+			return fmt.Errorf("starting replay session: %w", err)
+		}
+		fmt.Printf("%s Session %s started\n", style.Bold.Render("▶"), sessionName)
+		fmt.Printf("  Attach:  tmux attach -t %s\n", sessionName)
+		fmt.Printf("  Stop:    tmux kill-session -t %s\n", sessionName)
+	}
+	fmt.Printf("  Cleanup: git -C %s worktree remove --force .\n", dir)
+	return nil
+}
+
+func listReplayCaptures(townRoot, beadID string) error {
+	captures, err := replay.List(townRoot, beadID)
+	if err != nil {
+		return err
+	}
+	if len(captures) == 0 {
+		fmt.Printf("No replay captures for %s\n", beadID)
+		return nil
+	}
+	for _, c := range captures {
+		who := c.Rig
+		if c.Polecat != "" {
+			who = c.Rig + "/polecats/" + c.Polecat
+		}
+		line := fmt.Sprintf("%s  %s  %s", c.ID, who, replay.ShortSHA(c.RepoSHA))
+		if c.Formula != "" {
+			line += "  " + c.Formula
+		}
+		if c.Agent != "" {
+			line += "  agent=" + c.Agent
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// replayRepo returns the repository polecat worktrees are created from:
+// the rig's shared bare repo, or the mayor's clone for older rigs.
+func replayRepo(townRoot, rigName string) *git.Git {
+	rigPath := filepath.Join(townRoot, rigName)
+	if bare := filepath.Join(rigPath, ".repo.git"); dirExists(bare) {
+		return git.NewGitWithDir(bare, "")
+	}
+	if clone := filepath.Join(rigPath, "mayor", "rig"); dirExists(clone) {
+		return git.NewGit(clone)
+	}
+	return nil
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// replayInputs are the sling decisions recorded alongside a new polecat.
+type replayInputs struct {
+	BeadID     string
+	MoleculeID string
+	Formula    string
+	Args       string
+	Vars       []string
+	Agent      string
+	Dispatcher string
+}
+
+// captureSlingReplay records what a freshly spawned polecat is starting
+// with so gt replay can reproduce it. Capture is best-effort: a failure is
+// reported and never blocks the sling.
+func captureSlingReplay(townRoot string, spawnInfo *SpawnedPolecatInfo, in replayInputs) {
+	if spawnInfo == nil || spawnInfo.ClonePath == "" {
+		return
+	}
+	c, err := buildReplayCapture(townRoot, spawnInfo, in)
+	if err == nil {
+		err = replay.Save(townRoot, c)
+	}
+	if err != nil {
+		fmt.Printf("%s Could not record replay capture: %v\n", style.Dim.Render("Warning:"), err)
+	}
+}
+
+func buildReplayCapture(townRoot string, spawnInfo *SpawnedPolecatInfo, in replayInputs) (*replay.Capture, error) {
+	sha, err := git.NewGit(spawnInfo.ClonePath).Rev("HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("reading worktree commit: %w", err)
+	}
+	c := &replay.Capture{
+		Bead:       in.BeadID,
+		HookedBead: in.MoleculeID,
+		Dispatcher: in.Dispatcher,
+		Rig:        spawnInfo.RigName,
+		Polecat:    spawnInfo.PolecatName,
+		BaseBranch: spawnInfo.BaseBranch,
+		RepoSHA:    sha,
+		Formula:    in.Formula,
+		Args:       in.Args,
+		Vars:       append([]string(nil), in.Vars...),
+		Agent:      in.Agent,
+		GTVersion:  Version,
+	}
+
+	b := beads.New(townRoot)
+	c.BeadSnapshot = replaySnapshot(b, in.BeadID)
+	if in.MoleculeID != "" {
+		c.MoleculeSnapshot = replaySnapshot(b, in.MoleculeID)
+	}
+	if v, _, err := persona.NewStore(townRoot).Current("polecat"); err == nil && v != nil {
+		c.PersonaVersion = v.Version
+	}
+	c.Briefing = replayBriefingFn(spawnInfo)
+	return c, nil
+}
+
+func replaySnapshot(b *beads.Beads, id string) json.RawMessage {
+	issue, err := b.Show(id)
+	if err != nil {
+		return nil
+	}
+	data, err := json.Marshal(issue)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+var replayBriefingFn = replayBriefing
+
+// replayBriefing runs gt prime --dry-run in the polecat's worktree to
+// record the context its session will be primed with.
+func replayBriefing(spawnInfo *SpawnedPolecatInfo) string {
+	gtPath, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), replayBriefingTimeout)
+	defer cancel()
+	primeCmd := exec.CommandContext(ctx, gtPath, "prime", "--dry-run") //nolint:gosec // G204: our own binary
+	primeCmd.Dir = spawnInfo.ClonePath
+	primeCmd.Env = append(os.Environ(),
+		"GT_ROLE="+spawnInfo.AgentID(),
+		"GT_RIG="+spawnInfo.RigName,
+		"GT_POLECAT="+spawnInfo.PolecatName,
+	)
+	out, err := primeCmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out)) + "\n"
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/replay"
+)
+
+func TestBuildReplayCapture(t *testing.T) {
+	townRoot := t.TempDir()
+	clone := filepath.Join(townRoot, "gastown", "polecats", "nux", "gastown")
+	if err := os.MkdirAll(clone, 0755); err != nil {
+		t.Fatal(err)
+	}
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = clone
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q", "-b", "main")
+	git("commit", "-q", "--allow-empty", "-m", "base")
+	head := git("rev-parse", "HEAD")
+
+	prev := replayBriefingFn
+	t.Cleanup(func() { replayBriefingFn = prev })
+	replayBriefingFn = func(s *SpawnedPolecatInfo) string { return "briefing for " + s.AgentID() }
+
+	spawnInfo := &SpawnedPolecatInfo{RigName: "gastown", PolecatName: "nux", ClonePath: clone, BaseBranch: "main"}
+	vars := []string{"base_branch=main"}
+	c, err := buildReplayCapture(townRoot, spawnInfo, replayInputs{
+		BeadID:  "gt-abc",
+		Formula: "mol-polecat-work",
+		Args:    "patch release",
+		Vars:    vars,
+		Agent:   "gemini",
+	})
+	if err != nil {
+		t.Fatalf("buildReplayCapture: %v", err)
+	}
+	if c.RepoSHA != head {
+		t.Errorf("RepoSHA = %q, want %q", c.RepoSHA, head)
+	}
+	if c.Rig != "gastown" || c.Polecat != "nux" || c.Agent != "gemini" || c.Formula != "mol-polecat-work" {
+		t.Errorf("capture = %+v", c)
+	}
+	if c.Briefing != "briefing for gastown/polecats/nux" {
+		t.Errorf("Briefing = %q", c.Briefing)
+	}
+	vars[0] = "mutated"
+	if c.Vars[0] != "base_branch=main" {
+		t.Error("capture shares the caller's vars slice")
+	}
+}
+
+func TestCaptureSlingReplay_NotARepo(t *testing.T) {
+	townRoot := t.TempDir()
+	out := captureStdout(t, func() {
+		captureSlingReplay(townRoot, &SpawnedPolecatInfo{RigName: "gastown", PolecatName: "nux", ClonePath: filepath.Join(townRoot, "missing")}, replayInputs{BeadID: "gt-abc"})
+	})
+	if !strings.Contains(out, "Could not record replay capture") {
+		t.Errorf("output = %q, want a warning", out)
+	}
+	if captures, _ := replay.List(townRoot, "gt-abc"); len(captures) != 0 {
+		t.Errorf("captures = %v, want none", captures)
+	}
+}
+
+func TestReplayRepo(t *testing.T) {
+	townRoot := t.TempDir()
+	if g := replayRepo(townRoot, "gastown"); g != nil {
+		t.Error("replayRepo for a rig without a repo should be nil")
+	}
+	clone := filepath.Join(townRoot, "gastown", "mayor", "rig")
+	if err := os.MkdirAll(clone, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if g := replayRepo(townRoot, "gastown"); g == nil || g.WorkDir() != clone {
+		t.Errorf("replayRepo = %+v, want mayor clone", g)
+	}
+	if err := os.MkdirAll(filepath.Join(townRoot, "gastown", ".repo.git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if g := replayRepo(townRoot, "gastown"); g == nil || g.WorkDir() != "" {
+		t.Errorf("replayRepo = %+v, want the shared bare repo", g)
+	}
+}
+
+func TestListReplayCaptures(t *testing.T) {
+	townRoot := t.TempDir()
+	out := captureStdout(t, func() {
+		if err := listReplayCaptures(townRoot, "gt-abc"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "No replay captures") {
+		t.Errorf("empty output = %q", out)
+	}
+
+	if err := replay.Save(townRoot, &replay.Capture{Bead: "gt-abc", Rig: "gastown", Polecat: "nux", RepoSHA: "0123456789abcdef", Agent: "gemini"}); err != nil {
+		t.Fatal(err)
+	}
+	out = captureStdout(t, func() {
+		if err := listReplayCaptures(townRoot, "gt-abc"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	for _, want := range []string{"gastown/polecats/nux", "0123456789ab ", "agent=gemini"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
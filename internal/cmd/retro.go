@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/retro"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	retroJSON   bool
+	retroCreate bool
+	retroSling  string
+	retroDryRun bool
+)
+
+var retroCmd = &cobra.Command{
+	Use:     "retro <convoy-id>",
+	GroupID: GroupWork,
+	Short:   "Generate a retrospective for a completed convoy",
+	Long: `Generate a retrospective for a convoy: what stalled, where time and cost
+went, how often work bounced in review, and what escalated.
+
+The report is built from the convoy's tracked beads, the town events log
+(slings, stalls, merge results), the session cost log and escalation beads
+that name a tracked bead. It ends with findings: beads that took much
+longer than the median or whose worker stalled, beads that failed the
+merge queue repeatedly, beads that were slung more than once, beads that
+ate a large share of the cost, and every escalation.
+
+The report is saved to .runtime/retros/<convoy-id>.md (and .json). With
+--create, each finding becomes an improvement bead in town beads labeled
+"retro"; --sling also slings them to a target.
+
+Running retro on an open convoy gives a partial report.
+
+Examples:
+  gt retro hq-cv-abc                          # Show and save the retrospective
+  gt retro 1                                  # First convoy in 'gt convoy list'
+  gt retro hq-cv-abc --create                 # File improvement beads
+  gt retro hq-cv-abc --sling gastown --dry-run
+  gt retro hq-cv-abc --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRetro,
+}
+
+var (
+	// retroShowConvoyFn is a seam for tests. Production uses showConvoyIssue.
+	retroShowConvoyFn = showConvoyIssue
+
+	// retroTrackedBeadsFn is a seam for tests. Production shows each bead the
+	// convoy tracks.
+	retroTrackedBeadsFn = func(townBeads, convoyID string) ([]*beads.Issue, error) {
+		tracked, err := getTrackedIssues(townBeads, convoyID)
+		if err != nil {
+			return nil, err
+		}
+		issues := make([]*beads.Issue, 0, len(tracked))
+		for _, t := range tracked {
+			issue, err := beads.New(resolveBeadDir(t.ID)).Show(t.ID)
+			if err != nil {
+				issue = &beads.Issue{ID: t.ID, Title: t.Title, Status: t.Status, Assignee: t.Assignee}
+			}
+			issues = append(issues, issue)
+		}
+		return issues, nil
+	}
+
+	// retroEscalationsFn is a seam for tests. Production lists escalation beads
+	// in the town database.
+	retroEscalationsFn = func(townBeads string) ([]*beads.Issue, error) {
+		return beads.New(townBeads).List(beads.ListOptions{Label: "gt:escalation", Status: "all", Priority: -1})
+	}
+
+	// retroCreateBeadFn is a seam for tests. Production creates the finding bead
+	// with bd.
+	retroCreateBeadFn = func(townBeads string, f retro.Finding, convoyID string) (string, error) {
+		desc := fmt.Sprintf("%s\n\nFrom the retrospective of convoy %s (gt retro %s).", f.Detail, convoyID, convoyID)
+		issue, err := beads.New(townBeads).Create(beads.CreateOptions{
+			Title:       f.Title,
+			Description: desc,
+			Labels:      []string{"gt:task", "retro"},
+			Priority:    2,
+			Actor:       detectSender(),
+		})
+		if err != nil {
+			return "", err
+		}
+		return issue.ID, nil
+	}
+
+	// retroSlingBeadFn is a seam for tests. Production runs gt sling in the
+	// town.
+	retroSlingBeadFn = func(townRoot, id, target string) error {
+		cmd := exec.Command("gt", "sling", id, target)
+		cmd.Dir = townRoot
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%v (%s)", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+)
+
+func init() {
+	retroCmd.Flags().BoolVar(&retroJSON, "json", false, "Output as JSON")
+	retroCmd.Flags().BoolVar(&retroCreate, "create", false, "Create an improvement bead for each finding")
+	retroCmd.Flags().StringVar(&retroSling, "sling", "", "Create improvement beads and sling them to this target")
+	retroCmd.Flags().BoolVarP(&retroDryRun, "dry-run", "n", false, "Show the improvement beads without creating them")
+
+	rootCmd.AddCommand(retroCmd)
+}
+
+func runRetro(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	townBeads, err := getTownBeadsDir()
+	if err != nil {
+		return err
+	}
+
+	convoyID := args[0]
+	if n, err := strconv.Atoi(convoyID); err == nil && n > 0 {
+		if convoyID, err = resolveConvoyNumber(townBeads, n); err != nil {
+			return err
+		}
+	}
+
+	in, err := gatherRetroInput(townRoot, townBeads, convoyID)
+	if err != nil {
+		return err
+	}
+	report := retro.Build(in, time.Now())
+
+	path, saveErr := report.Save(townRoot)
+	if saveErr != nil {
+		style.PrintWarning("could not save retrospective: %v", saveErr)
+	}
+
+	if retroJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Print(report.Markdown())
+		if saveErr == nil {
+			fmt.Printf("\n%s\n", style.Dim.Render("Saved to "+path))
+		}
+	}
+
+	if retroCreate || retroSling != "" {
+		return fileRetroFindings(townRoot, townBeads, report, retroSling, retroDryRun)
+	}
+	return nil
+}
+
+// gatherRetroInput collects the convoy, its beads, and the events, costs
+// and escalations that concern them.
+func gatherRetroInput(townRoot, townBeads, convoyID string) (retro.Input, error) {
+	convoy, err := retroShowConvoyFn(townBeads, convoyID)
+	if err != nil {
+		return retro.Input{}, err
+	}
+	if convoy.Type != "" && convoy.Type != "convoy" {
+		return retro.Input{}, fmt.Errorf("%s is a %s, not a convoy", convoyID, convoy.Type)
+	}
+	in := retro.Input{
+		ConvoyID:  convoy.ID,
+		Title:     convoy.Title,
+		Status:    convoy.Status,
+		CreatedAt: parseBeadTime(convoy.CreatedAt),
+		ClosedAt:  parseBeadTime(convoy.ClosedAt),
+	}
+
+	issues, err := retroTrackedBeadsFn(townBeads, convoyID)
+	if err != nil {
+		return retro.Input{}, fmt.Errorf("getting tracked issues for %s: %w", convoyID, err)
+	}
+	tracked := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		tracked[issue.ID] = true
+		in.Beads = append(in.Beads, retro.Bead{
+			ID:        issue.ID,
+			Title:     issue.Title,
+			Status:    issue.Status,
+			Assignee:  issue.Assignee,
+			CreatedAt: parseBeadTime(issue.CreatedAt),
+			ClosedAt:  parseBeadTime(issue.ClosedAt),
+		})
+	}
+
+	if in.Events, err = retro.LoadEvents(townRoot, in.CreatedAt); err != nil {
+		style.PrintWarning("events log unreadable, stalls and merges not counted: %v", err)
+	}
+	in.Costs = loadRetroCosts(tracked)
+
+	escalations, err := retroEscalationsFn(townBeads)
+	if err != nil {
+		style.PrintWarning("could not list escalations: %v", err)
+	}
+	for _, issue := range escalations {
+		fields := beads.ParseEscalationFields(issue.Description)
+		if fields == nil || !tracked[fields.RelatedBead] {
+			continue
+		}
+		in.Escalations = append(in.Escalations, retro.Escalation{
+			ID:       issue.ID,
+			Title:    issue.Title,
+			Severity: fields.Severity,
+			Reason:   fields.Reason,
+			Related:  fields.RelatedBead,
+			Closed:   issue.Status == "closed",
+		})
+	}
+	return in, nil
+}
+
+// loadRetroCosts returns the session costs recorded against tracked beads.
+func loadRetroCosts(tracked map[string]bool) []retro.Cost {
+	data, err := os.ReadFile(getCostsLogPath())
+	if err != nil {
+		return nil
+	}
+	var costs []retro.Cost
+	for _, line := range strings.Split(string(data), "\n") {
+		var entry CostLogEntry
+		if json.Unmarshal([]byte(line), &entry) != nil || !tracked[entry.WorkItem] {
+			continue
+		}
+		costs = append(costs, retro.Cost{WorkItem: entry.WorkItem, CostUSD: entry.CostUSD})
+	}
+	return costs
+}
+
+// fileRetroFindings creates an improvement bead per finding and, with a
+// target, slings each one. Failures are warned about; the rest still run.
+func fileRetroFindings(townRoot, townBeads string, report *retro.Report, target string, dryRun bool) error {
+	if len(report.Findings) == 0 {
+		fmt.Println("No findings; no improvement beads to create.")
+		return nil
+	}
+	fmt.Println()
+	created := 0
+	for _, f := range report.Findings {
+		if dryRun {
+			line := fmt.Sprintf("%s create %q", style.Dim.Render("[dry-run]"), f.Title)
+			if target != "" {
+				line += " and sling to " + target
+			}
+			fmt.Println(line)
+			continue
+		}
+		id, err := retroCreateBeadFn(townBeads, f, report.ConvoyID)
+		if err != nil {
+			style.PrintWarning("creating improvement bead %q: %v", f.Title, err)
+			continue
+		}
+		created++
+		fmt.Printf("%s %s %s\n", style.SuccessPrefix, style.Bold.Render(id), f.Title)
+		if target == "" {
+			continue
+		}
+		if err := retroSlingBeadFn(townRoot, id, target); err != nil {
+			style.PrintWarning("slinging %s to %s: %v", id, target, err)
+			continue
+		}
+		fmt.Printf("  slung to %s\n", target)
+	}
+	if !dryRun && created == 0 {
+		return fmt.Errorf("no improvement beads were created")
+	}
+	return nil
+}
+
+// parseBeadTime parses a bd timestamp, returning the zero time if empty or
+// malformed.
+func parseBeadTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
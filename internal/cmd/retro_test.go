@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/retro"
+)
+
+func stubRetro(t *testing.T) {
+	t.Helper()
+	origConvoy, origTracked, origEsc := retroShowConvoyFn, retroTrackedBeadsFn, retroEscalationsFn
+	t.Cleanup(func() { retroShowConvoyFn, retroTrackedBeadsFn, retroEscalationsFn = origConvoy, origTracked, origEsc })
+
+	retroShowConvoyFn = func(townBeads, id string) (*beads.Issue, error) {
+		return &beads.Issue{ID: id, Title: "Auth", Status: "closed", Type: "convoy",
+			CreatedAt: "2026-03-01T09:00:00Z", ClosedAt: "2026-03-01T12:00:00Z"}, nil
+	}
+	retroTrackedBeadsFn = func(townBeads, convoyID string) ([]*beads.Issue, error) {
+		return []*beads.Issue{
+			{ID: "gt-a", Title: "Token refresh", Status: "closed", CreatedAt: "2026-03-01T09:00:00Z", ClosedAt: "2026-03-01T10:00:00Z"},
+			{ID: "gt-b", Title: "SSO", Status: "closed", CreatedAt: "2026-03-01T09:00:00Z"},
+		}, nil
+	}
+	retroEscalationsFn = func(townBeads string) ([]*beads.Issue, error) {
+		return []*beads.Issue{
+			{ID: "hq-e1", Title: "SSO blocked", Status: "open", Description: beads.FormatEscalationDescription("SSO blocked",
+				&beads.EscalationFields{Severity: "high", Reason: "no sandbox", RelatedBead: "gt-b"})},
+			{ID: "hq-e2", Title: "Unrelated", Status: "open", Description: beads.FormatEscalationDescription("Unrelated",
+				&beads.EscalationFields{Severity: "low", RelatedBead: "gt-zzz"})},
+		}, nil
+	}
+}
+
+func TestGatherRetroInput(t *testing.T) {
+	stubRetro(t)
+	home := t.TempDir()
+	t.Setenv("GT_HOME", home)
+	costs := `{"session_id":"s1","role":"polecat","cost_usd":1.5,"work_item":"gt-a"}
+{"session_id":"s2","role":"polecat","cost_usd":9,"work_item":"gt-other"}
+`
+	if err := os.MkdirAll(filepath.Join(home, ".gt"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".gt", "costs.jsonl"), []byte(costs), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	in, err := gatherRetroInput(t.TempDir(), "/town/.beads", "hq-cv-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.Title != "Auth" || in.CreatedAt.IsZero() || in.ClosedAt.IsZero() {
+		t.Errorf("convoy fields = %+v", in)
+	}
+	if len(in.Beads) != 2 || in.Beads[0].ClosedAt.IsZero() || !in.Beads[1].ClosedAt.IsZero() {
+		t.Errorf("beads = %+v", in.Beads)
+	}
+	if len(in.Costs) != 1 || in.Costs[0].CostUSD != 1.5 {
+		t.Errorf("costs = %+v, want only gt-a's", in.Costs)
+	}
+	if len(in.Escalations) != 1 || in.Escalations[0].ID != "hq-e1" || in.Escalations[0].Reason != "no sandbox" {
+		t.Errorf("escalations = %+v", in.Escalations)
+	}
+}
+
+func TestGatherRetroInput_NotAConvoy(t *testing.T) {
+	stubRetro(t)
+	retroShowConvoyFn = func(townBeads, id string) (*beads.Issue, error) {
+		return &beads.Issue{ID: id, Type: "task"}, nil
+	}
+	if _, err := gatherRetroInput(t.TempDir(), "/town/.beads", "gt-a"); err == nil || !strings.Contains(err.Error(), "not a convoy") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestFileRetroFindings(t *testing.T) {
+	origCreate, origSling := retroCreateBeadFn, retroSlingBeadFn
+	t.Cleanup(func() { retroCreateBeadFn, retroSlingBeadFn = origCreate, origSling })
+
+	var created, slung []string
+	retroCreateBeadFn = func(townBeads string, f retro.Finding, convoyID string) (string, error) {
+		if f.Bead == "gt-bad" {
+			return "", errors.New("bd down")
+		}
+		created = append(created, f.Title)
+		return "hq-" + f.Bead, nil
+	}
+	retroSlingBeadFn = func(townRoot, id, target string) error {
+		slung = append(slung, id+"→"+target)
+		return nil
+	}
+
+	report := &retro.Report{ConvoyID: "hq-cv-1", Findings: []retro.Finding{
+		{Bead: "gt-a", Title: "Investigate why gt-a stalled"},
+		{Bead: "gt-bad", Title: "Review the cost of gt-bad"},
+	}}
+
+	if err := fileRetroFindings("/town", "/town/.beads", report, "gastown", true); err != nil || created != nil || slung != nil {
+		t.Fatalf("dry run acted: err=%v created=%v slung=%v", err, created, slung)
+	}
+	if err := fileRetroFindings("/town", "/town/.beads", report, "gastown", false); err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 1 || len(slung) != 1 || slung[0] != "hq-gt-a→gastown" {
+		t.Errorf("created=%v slung=%v", created, slung)
+	}
+
+	report.Findings = report.Findings[1:]
+	if err := fileRetroFindings("/town", "/town/.beads", report, "", false); err == nil {
+		t.Error("expected an error when no bead could be created")
+	}
+}
@@ -27,6 +27,8 @@ import (
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/suggest"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/towntemplate"
+	"github.com/steveyegge/gastown/internal/undo"
 	"github.com/steveyegge/gastown/internal/wisp"
 	"github.com/steveyegge/gastown/internal/witness"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -50,7 +52,7 @@ A rig is a container for managing a project and its agents:
 }
 
 var rigAddCmd = &cobra.Command{
-	Use:   "add <name> <git-url>",
+	Use:   "add <name> [git-url]",
 	Short: "Add a new rig to the workspace",
 	Long: `Add a new rig by cloning a repository.
 
@@ -74,10 +76,24 @@ Use --adopt to register an existing directory instead of creating new:
   - Auto-detects git URL from origin remote (git-url argument not required)
   - Adds entry to mayor/rigs.json
 
+Use --monorepo to add a rig for one subdirectory of a repository that an
+existing rig (the host) already clones:
+  - Borrows git objects from the host, so nothing is downloaded again
+  - Keeps its own branches, refinery and merge queue, and its own beads prefix
+  - Mayor clone is a sparse checkout of --subdir (override with --sparse-checkout)
+  - The refinery rejects MRs that change files outside --subdir; list
+    extra paths (go.work, lockfiles) in config.json monorepo.shared
+  - git-url defaults to the host's
+
+Use --template to start the rig from a rig template: settings saved in
+settings/rig-templates/<name>.json, usually by gt town template apply.
+
 Example:
   gt rig add gastown https://github.com/steveyegge/gastown
   gt rig add my-project git@github.com:user/repo.git --prefix mp
-  gt rig add existing-rig --adopt`,
+  gt rig add existing-rig --adopt
+  gt rig add api --monorepo platform --subdir services/api --prefix api
+  gt rig add api https://github.com/acme/api.git --template backend`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runRigAdd,
 }
@@ -112,6 +128,8 @@ you must shut them down first with 'gt rig shutdown' or use --force to
 kill them automatically.
 
 To fully remove a rig, delete the directory manually after unregistering.
+A removal can be reversed with 'gt undo' while the files are still there.
+The town's confirmation policy may require confirming first (see gt confirm).
 
 Examples:
   gt rig remove myproject                    # Unregister (fails if sessions running)
@@ -305,6 +323,9 @@ var (
 	rigAddAdoptForce     bool
 	rigAddFilter         string
 	rigAddSparseCheckout []string
+	rigAddMonorepo       string
+	rigAddSubdir         string
+	rigAddTemplate       string
 	rigResetHandoff    bool
 	rigResetMail       bool
 	rigResetStale      bool
@@ -320,6 +341,7 @@ var (
 	rigRestartNuclear  bool
 	rigListJSON        bool
 	rigRemoveForce     bool
+	rigRemoveYes       bool
 )
 
 var (
@@ -356,6 +378,7 @@ func init() {
 	rigListCmd.Flags().BoolVar(&rigListJSON, "json", false, "Output as JSON")
 
 	rigRemoveCmd.Flags().BoolVarP(&rigRemoveForce, "force", "f", false, "Kill running tmux sessions before removing (may lose uncommitted work)")
+	rigRemoveCmd.Flags().BoolVarP(&rigRemoveYes, "yes", "y", false, "Skip confirmation if the town's policy allows it")
 
 	rigAddCmd.Flags().StringVar(&rigAddPrefix, "prefix", "", "Beads issue prefix (default: derived from name)")
 	rigAddCmd.Flags().StringVar(&rigAddLocalRepo, "local-repo", "", "Local repo path to share git objects (optional)")
@@ -367,6 +390,9 @@ func init() {
 	rigAddCmd.Flags().BoolVar(&rigAddAdoptForce, "force", false, "With --adopt, register even if git remote cannot be detected")
 	rigAddCmd.Flags().StringVar(&rigAddFilter, "filter", "", "Partial clone filter (e.g. \"blob:none\", \"tree:0\") to reduce clone size")
 	rigAddCmd.Flags().StringSliceVar(&rigAddSparseCheckout, "sparse-checkout", nil, "Sparse checkout paths (cone mode); comma-separated or repeated")
+	rigAddCmd.Flags().StringVar(&rigAddMonorepo, "monorepo", "", "Host rig whose repository this rig shares (monorepo mode)")
+	rigAddCmd.Flags().StringVar(&rigAddSubdir, "subdir", "", "Repo-relative directory the rig owns (with --monorepo)")
+	rigAddCmd.Flags().StringVar(&rigAddTemplate, "template", "", "Rig template whose settings the new rig starts with")
 
 	rigResetCmd.Flags().BoolVar(&rigResetHandoff, "handoff", false, "Clear handoff content")
 	rigResetCmd.Flags().BoolVar(&rigResetMail, "mail", false, "Clear stale mail messages")
@@ -482,13 +508,19 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		return runRigAdopt(cmd, args)
 	}
 
-	// Normal add mode requires git URL
-	if len(args) < 2 {
+	if (rigAddMonorepo == "") != (rigAddSubdir == "") {
+		return fmt.Errorf("--monorepo and --subdir must be used together")
+	}
+
+	// Normal add mode requires git URL; monorepo rigs take the host's
+	var gitURL string
+	if len(args) >= 2 {
+		gitURL = args[1]
+	} else if rigAddMonorepo == "" {
 		return fmt.Errorf("git-url is required (or use --adopt to register an existing directory)")
 	}
-	gitURL := args[1]
 
-	if !isGitRemoteURL(gitURL) {
+	if gitURL != "" && !isGitRemoteURL(gitURL) {
 		return fmt.Errorf("invalid git URL %q: expected a remote URL (e.g. https://, git@host:, ssh://, s3://)\n\nTo register a local directory, use:\n  gt rig add %s --adopt", gitURL, name)
 	}
 
@@ -503,6 +535,14 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	// Load the rig template up front so a typo doesn't leave a half-set-up rig
+	var rigTemplate *config.RigSettings
+	if rigAddTemplate != "" {
+		if rigTemplate, err = towntemplate.LoadRigTemplate(townRoot, rigAddTemplate); err != nil {
+			return err
+		}
+	}
+
 	// Load rigs config
 	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
 	rigsConfig, err := config.LoadRigsConfig(rigsPath)
@@ -518,8 +558,21 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 	g := git.NewGit(townRoot)
 	mgr := rig.NewManager(townRoot, rigsConfig, g)
 
+	if rigAddMonorepo != "" {
+		host, ok := rigsConfig.Rigs[rigAddMonorepo]
+		if !ok {
+			return fmt.Errorf("monorepo host %q is not a rig in this town", rigAddMonorepo)
+		}
+		if gitURL == "" {
+			gitURL = host.GitURL
+		}
+	}
+
 	fmt.Printf("Creating rig %s...\n", style.Bold.Render(name))
 	fmt.Printf("  Repository: %s\n", gitURL)
+	if rigAddMonorepo != "" {
+		fmt.Printf("  Monorepo: %s (subdir %s)\n", rigAddMonorepo, rigAddSubdir)
+	}
 	if rigAddLocalRepo != "" {
 		fmt.Printf("  Local repo: %s\n", rigAddLocalRepo)
 	}
@@ -568,6 +621,8 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		DefaultBranch:  rigAddBranch,
 		CloneFilter:    rigAddFilter,
 		SparseCheckout: rigAddSparseCheckout,
+		MonorepoHost:   rigAddMonorepo,
+		Subdir:         rigAddSubdir,
 	})
 	if err != nil {
 		return fmt.Errorf("adding rig: %w", err)
@@ -636,6 +691,14 @@ func runRigAdd(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if rigTemplate != nil {
+		if err := config.SaveRigSettings(config.RigSettingsPath(filepath.Join(townRoot, name)), rigTemplate); err != nil {
+			fmt.Printf("  %s Could not apply rig template %s: %v\n", style.Warning.Render("!"), rigAddTemplate, err)
+		} else {
+			fmt.Printf("  Applied rig template: %s\n", rigAddTemplate)
+		}
+	}
+
 	// Sync hooks for the new rig's targets
 	if err := syncRigHooks(townRoot, name); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to sync hooks for new rig: %v\n", err)
@@ -756,6 +819,7 @@ func runRigList(cmd *cobra.Command, args []string) error {
 		Refinery    string `json:"refinery"`
 		Polecats    int    `json:"polecats"`
 		Crew        int    `json:"crew"`
+		Monorepo    string `json:"monorepo,omitempty"` // host/subdir for monorepo rigs
 		// sorting fields (not exported to JSON)
 		sortPrio int
 	}
@@ -788,6 +852,10 @@ func runRigList(cmd *cobra.Command, args []string) error {
 		}
 
 		summary := r.Summary()
+		var monorepo string
+		if r.Monorepo != nil {
+			monorepo = r.Monorepo.Host + "/" + r.Monorepo.Subdir
+		}
 		rigs = append(rigs, rigInfo{
 			Name:        name,
 			BeadsPrefix: prefix,
@@ -796,6 +864,7 @@ func runRigList(cmd *cobra.Command, args []string) error {
 			Refinery:    refineryStatus,
 			Polecats:    summary.PolecatCount,
 			Crew:        summary.CrewCount,
+			Monorepo:    monorepo,
 			sortPrio:    rigStatePriority(witnessRunning, refineryRunning, opState),
 		})
 	}
@@ -842,6 +911,9 @@ func runRigList(cmd *cobra.Command, args []string) error {
 		fmt.Printf("   Witness: %s %s  Refinery: %s %s\n",
 			witnessIcon, ri.Witness, refineryIcon, ri.Refinery)
 		fmt.Printf("   Polecats: %d  Crew: %d\n", ri.Polecats, ri.Crew)
+		if ri.Monorepo != "" {
+			fmt.Printf("   %s\n", style.Dim.Render("Monorepo: "+ri.Monorepo))
+		}
 		fmt.Println()
 	}
 
@@ -870,6 +942,29 @@ func runRigRemove(cmd *cobra.Command, args []string) error {
 		beadsPrefix = entry.BeadsConfig.Prefix
 	}
 
+	// Keep what's being removed so gt undo can restore it
+	undoState := undo.RigRemoveState{Rig: name}
+	if entry, ok := rigsConfig.Rigs[name]; ok {
+		undoState.Entry, _ = json.Marshal(entry)
+	}
+	if beadsPrefix != "" {
+		if routes, err := beads.LoadRoutes(filepath.Join(townRoot, ".beads")); err == nil {
+			for _, r := range routes {
+				if r.Prefix == beadsPrefix+"-" {
+					route := r
+					undoState.Route = &route
+				}
+			}
+		}
+	}
+
+	if proceed, err := confirmDestructive("rig remove", name, rigRemoveYes); err != nil {
+		return err
+	} else if !proceed {
+		fmt.Println("Aborted.")
+		return nil
+	}
+
 	// Create rig manager
 	g := git.NewGit(townRoot)
 	mgr := rig.NewManager(townRoot, rigsConfig, g)
@@ -954,7 +1049,10 @@ func runRigRemove(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	recordUndo(townRoot, undo.KindRigRemove, "rig remove "+name, undoState)
+
 	fmt.Printf("%s Rig %s removed from registry\n", style.Success.Render("✓"), name)
+	fmt.Printf("Undo with: %s\n", style.Dim.Render("gt undo"))
 	fmt.Printf("\nNote: Files at %s were NOT deleted.\n", filepath.Join(townRoot, name))
 	fmt.Printf("To delete: %s\n", style.Dim.Render(fmt.Sprintf("rm -rf %s", filepath.Join(townRoot, name))))
 
@@ -1088,7 +1186,7 @@ func runRigAdopt(_ *cobra.Command, args []string) error {
 				workDir := filepath.Dir(beadsDir)
 				bdCmd := exec.Command("bd", "config", "get", "issue_prefix")
 				bdCmd.Dir = workDir
-				if out, bdErr := bdCmd.Output(); bdErr == nil {
+				if out, bdErr := beads.CmdOutput(bdCmd); bdErr == nil {
 					detected := strings.TrimSpace(string(out))
 					if detected != "" {
 						if rigAddPrefix != "" && strings.TrimSuffix(rigAddPrefix, "-") != detected {
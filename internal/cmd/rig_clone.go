@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	rigCloneBranch      string
+	rigClonePrefix      string
+	rigCloneCopyBacklog bool
+	rigCloneDiffJSON    bool
+	rigCloneMergeDryRun bool
+	rigCloneMergeForce  bool
+)
+
+var rigCloneCmd = &cobra.Command{
+	Use:   "clone <source-rig> <new-rig>",
+	Short: "Clone a rig for experiments under a new prefix",
+	Long: `Clone a rig into a new experiment rig.
+
+The clone is a separate rig with its own bead prefix and its own clone of
+the repository, checked out from --branch (default: the source's default
+branch). The source's configuration (settings/ and roles/) is copied, so
+fleet-config ideas can be tried on the clone without touching the
+production rig. The clone starts with an empty bead database unless
+--copy-backlog copies the source's open work items.
+
+Configuration changes made in the clone can be reviewed with
+'gt rig clone diff' and applied to the source with 'gt rig clone merge'.
+Files the source changed in the meantime are reported as conflicts and
+left alone unless --force is given.
+
+Examples:
+  gt rig clone gastown gastown-exp
+  gt rig clone gastown gastown-exp --branch experiment --prefix gx
+  gt rig clone gastown gastown-exp --copy-backlog
+  gt rig clone diff gastown-exp
+  gt rig clone merge gastown-exp --dry-run`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRigClone,
+}
+
+var rigCloneDiffCmd = &cobra.Command{
+	Use:   "diff <clone-rig>",
+	Short: "Show configuration changes made in a cloned rig",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRigCloneDiff,
+}
+
+var rigCloneMergeCmd = &cobra.Command{
+	Use:   "merge <clone-rig>",
+	Short: "Apply a cloned rig's configuration changes to its source",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRigCloneMerge,
+}
+
+var (
+	// rigCloneAddFn is a seam for tests. Production runs gt rig add.
+	rigCloneAddFn = func(townRoot string, args []string) error {
+		c := exec.Command("gt", append([]string{"rig", "add"}, args...)...)
+		c.Dir = townRoot
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		return c.Run()
+	}
+
+	// rigCloneListBacklogFn is a seam for tests. Production lists the source
+	// rig's open beads.
+	rigCloneListBacklogFn = func(townRoot, rigName string) ([]*beads.Issue, error) {
+		return beads.New(rigBeadsWorkDir(townRoot, rigName)).List(beads.ListOptions{Status: "open", Priority: -1})
+	}
+
+	// rigCloneCreateBeadFn is a seam for tests. Production creates the copy with
+	// bd.
+	rigCloneCreateBeadFn = func(townRoot, rigName string, issue *beads.Issue) error {
+		_, err := beads.New(rigBeadsWorkDir(townRoot, rigName)).Create(beads.CreateOptions{
+			Title:       issue.Title,
+			Description: issue.Description,
+			Priority:    issue.Priority,
+			Labels:      issue.Labels,
+		})
+		return err
+	}
+)
+
+func init() {
+	rigCloneCmd.Flags().StringVar(&rigCloneBranch, "branch", "", "Branch to check out (default: the source's default branch)")
+	rigCloneCmd.Flags().StringVar(&rigClonePrefix, "prefix", "", "Beads prefix for the clone (default: derived from the name)")
+	rigCloneCmd.Flags().BoolVar(&rigCloneCopyBacklog, "copy-backlog", false, "Copy the source's open work items into the clone")
+	rigCloneDiffCmd.Flags().BoolVar(&rigCloneDiffJSON, "json", false, "Output as JSON")
+	rigCloneMergeCmd.Flags().BoolVar(&rigCloneMergeDryRun, "dry-run", false, "Show what would be applied")
+	rigCloneMergeCmd.Flags().BoolVar(&rigCloneMergeForce, "force", false, "Apply conflicting changes too, overwriting the source's")
+
+	rigCloneCmd.AddCommand(rigCloneDiffCmd)
+	rigCloneCmd.AddCommand(rigCloneMergeCmd)
+	rigCmd.AddCommand(rigCloneCmd)
+}
+
+func runRigClone(cmd *cobra.Command, args []string) error {
+	source, name := args[0], args[1]
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigsConfig, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	entry, ok := rigsConfig.Rigs[source]
+	if !ok {
+		return fmt.Errorf("rig %q not found", source)
+	}
+	if _, exists := rigsConfig.Rigs[name]; exists {
+		return fmt.Errorf("rig %q already exists", name)
+	}
+	sourcePath := filepath.Join(townRoot, source)
+	if rigClonePrefix != "" && entry.BeadsConfig != nil && rigClonePrefix == entry.BeadsConfig.Prefix {
+		return fmt.Errorf("the clone needs its own prefix; %q belongs to %s", rigClonePrefix, source)
+	}
+
+	branch := rigCloneBranch
+	if branch == "" {
+		if cfg, err := rig.LoadRigConfig(sourcePath); err == nil {
+			branch = cfg.DefaultBranch
+		}
+	}
+
+	addArgs := []string{name, entry.GitURL}
+	if rigClonePrefix != "" {
+		addArgs = append(addArgs, "--prefix", rigClonePrefix)
+	}
+	if branch != "" {
+		addArgs = append(addArgs, "--branch", branch)
+	}
+	if mayorClone := filepath.Join(sourcePath, "mayor", "rig"); dirExists(mayorClone) {
+		addArgs = append(addArgs, "--local-repo", mayorClone)
+	}
+	fmt.Printf("Cloning rig %s as %s...\n", style.Bold.Render(source), style.Bold.Render(name))
+	if err := rigCloneAddFn(townRoot, addArgs); err != nil {
+		return fmt.Errorf("gt rig add failed: %w", err)
+	}
+
+	clonePath := filepath.Join(townRoot, name)
+	base, err := rig.CopyConfig(sourcePath, clonePath)
+	if err != nil {
+		return fmt.Errorf("copying configuration: %w", err)
+	}
+	info := &rig.CloneInfo{Source: source, Branch: branch, Created: time.Now().UTC(), Base: base}
+	if err := rig.SaveCloneInfo(clonePath, info); err != nil {
+		return fmt.Errorf("recording clone origin: %w", err)
+	}
+	fmt.Printf("  %s Copied %d configuration file(s) from %s\n", style.Success.Render("✓"), len(base), source)
+
+	if rigCloneCopyBacklog {
+		n, err := copyRigBacklog(townRoot, source, name)
+		if err != nil {
+			style.PrintWarning("copied %d work item(s) before failing: %v", n, err)
+		} else {
+			fmt.Printf("  %s Copied %d open work item(s)\n", style.Success.Render("✓"), n)
+		}
+	}
+
+	fmt.Printf("\n%s Cloned %s as %s\n", style.SuccessPrefix, source, name)
+	fmt.Printf("  Review config changes later with %s\n", style.Bold.Render("gt rig clone diff "+name))
+	return nil
+}
+
+// copyRigBacklog copies the source rig's open work items into the clone
+// under the clone's prefix. Agent, convoy, molecule and other
+// infrastructure beads stay behind. Returns how many were copied.
+func copyRigBacklog(townRoot, source, clone string) (int, error) {
+	issues, err := rigCloneListBacklogFn(townRoot, source)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, issue := range issues {
+		if isDedupInfraBead(issue) {
+			continue
+		}
+		if err := rigCloneCreateBeadFn(townRoot, clone, issue); err != nil {
+			return n, fmt.Errorf("copying %s: %w", issue.ID, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// loadRigClone returns a cloned rig's path, origin and its source's path.
+func loadRigClone(name string) (clonePath string, info *rig.CloneInfo, sourcePath string, err error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	clonePath = filepath.Join(townRoot, name)
+	info, err = rig.LoadCloneInfo(clonePath)
+	if os.IsNotExist(err) {
+		return "", nil, "", fmt.Errorf("rig %q is not a clone (no %s)", name, filepath.Base(rig.CloneInfoPath(clonePath)))
+	} else if err != nil {
+		return "", nil, "", err
+	}
+	sourcePath = filepath.Join(townRoot, info.Source)
+	if !dirExists(sourcePath) {
+		return "", nil, "", fmt.Errorf("source rig %s no longer exists", info.Source)
+	}
+	return clonePath, info, sourcePath, nil
+}
+
+func runRigCloneDiff(cmd *cobra.Command, args []string) error {
+	clonePath, info, sourcePath, err := loadRigClone(args[0])
+	if err != nil {
+		return err
+	}
+	clone, err := rig.ReadConfigFiles(clonePath)
+	if err != nil {
+		return err
+	}
+	source, err := rig.ReadConfigFiles(sourcePath)
+	if err != nil {
+		return err
+	}
+	changes := rig.DiffClone(info, clone, source)
+
+	if rigCloneDiffJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(changes)
+	}
+	if len(changes) == 0 {
+		fmt.Printf("No configuration changes in %s since it was cloned from %s.\n", args[0], info.Source)
+		return nil
+	}
+	fmt.Printf("Configuration changes in %s (cloned from %s):\n", args[0], info.Source)
+	printCloneChanges(changes)
+	return nil
+}
+
+func runRigCloneMerge(cmd *cobra.Command, args []string) error {
+	clonePath, info, sourcePath, err := loadRigClone(args[0])
+	if err != nil {
+		return err
+	}
+
+	if rigCloneMergeDryRun {
+		clone, err := rig.ReadConfigFiles(clonePath)
+		if err != nil {
+			return err
+		}
+		source, err := rig.ReadConfigFiles(sourcePath)
+		if err != nil {
+			return err
+		}
+		changes := rig.DiffClone(info, clone, source)
+		if len(changes) == 0 {
+			fmt.Println("Nothing to merge.")
+			return nil
+		}
+		fmt.Printf("Would apply to %s:\n", info.Source)
+		printCloneChanges(changes)
+		return nil
+	}
+
+	applied, skipped, err := rig.MergeClone(sourcePath, clonePath, rigCloneMergeForce)
+	if len(applied) > 0 {
+		fmt.Printf("Applied to %s:\n", info.Source)
+		printCloneChanges(applied)
+	}
+	if err != nil {
+		return fmt.Errorf("merging %s: %w", args[0], err)
+	}
+	if len(skipped) > 0 {
+		fmt.Printf("\n%s Skipped %d conflicting change(s); %s changed them too:\n", style.Warning.Render("⚠"), len(skipped), info.Source)
+		printCloneChanges(skipped)
+		fmt.Printf("  Resolve by hand, or re-run with %s to overwrite the source's version.\n", style.Bold.Render("--force"))
+	}
+	if len(applied) == 0 && len(skipped) == 0 {
+		fmt.Println("Nothing to merge.")
+	}
+	return nil
+}
+
+func printCloneChanges(changes []rig.ConfigChange) {
+	for _, c := range changes {
+		conflict := ""
+		if c.Conflict {
+			conflict = style.Warning.Render(" (conflict: changed in source too)")
+		}
+		fmt.Printf("  %-9s %s%s\n", c.Kind, c.Path, conflict)
+	}
+}
+
+// rigBeadsWorkDir returns the directory to run bd in for a rig's beads.
+func rigBeadsWorkDir(townRoot, rigName string) string {
+	if mayorRig := filepath.Join(townRoot, rigName, "mayor", "rig"); dirExists(filepath.Join(mayorRig, ".beads")) {
+		return mayorRig
+	}
+	return filepath.Join(townRoot, rigName)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestRunRigClone(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveRigsConfig(filepath.Join(town, "mayor", "rigs.json"), &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{
+		"gastown": {GitURL: "https://example.com/gastown.git", BeadsConfig: &config.BeadsConfig{Prefix: "gt"}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	settings := filepath.Join(town, "gastown", "settings")
+	if err := os.MkdirAll(settings, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(settings, "config.json"), []byte(`{"type":"rig-settings"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(town)
+
+	var addArgs []string
+	var created []string
+	origAdd, origList, origCreate := rigCloneAddFn, rigCloneListBacklogFn, rigCloneCreateBeadFn
+	t.Cleanup(func() { rigCloneAddFn, rigCloneListBacklogFn, rigCloneCreateBeadFn = origAdd, origList, origCreate })
+	rigCloneAddFn = func(_ string, args []string) error {
+		addArgs = args
+		return os.MkdirAll(filepath.Join(town, args[0]), 0755)
+	}
+	rigCloneListBacklogFn = func(_, rigName string) ([]*beads.Issue, error) {
+		return []*beads.Issue{
+			{ID: "gt-1", Title: "Fix the thing"},
+			{ID: "gt-witness", Title: "witness", Labels: []string{"gt:agent"}},
+		}, nil
+	}
+	rigCloneCreateBeadFn = func(_, rigName string, issue *beads.Issue) error {
+		created = append(created, rigName+":"+issue.Title)
+		return nil
+	}
+	rigClonePrefix, rigCloneBranch, rigCloneCopyBacklog = "gx", "experiment", true
+	t.Cleanup(func() { rigClonePrefix, rigCloneBranch, rigCloneCopyBacklog = "", "", false })
+
+	if err := runRigClone(rigCloneCmd, []string{"gastown", "gastown-exp"}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(addArgs[:6], []string{"gastown-exp", "https://example.com/gastown.git", "--prefix", "gx", "--branch", "experiment"}) {
+		t.Errorf("gt rig add args = %v", addArgs)
+	}
+	if !slices.Equal(created, []string{"gastown-exp:Fix the thing"}) {
+		t.Errorf("copied backlog = %v", created)
+	}
+	info, err := rig.LoadCloneInfo(filepath.Join(town, "gastown-exp"))
+	if err != nil || info.Source != "gastown" || info.Base["settings/config.json"] == "" {
+		t.Fatalf("clone info = %+v, %v", info, err)
+	}
+
+	rigClonePrefix = "gt"
+	if err := runRigClone(rigCloneCmd, []string{"gastown", "other"}); err == nil {
+		t.Error("cloning with the source's prefix should fail")
+	}
+}
@@ -83,7 +83,7 @@ func runRigDetect(cmd *cobra.Command, args []string) error {
 }
 
 func detectRigFromPath(townRoot, absPath string) string {
-	rel, err := filepath.Rel(townRoot, absPath)
+	rel, err := workspace.RelPath(townRoot, absPath)
 	if err != nil || strings.HasPrefix(rel, "..") {
 		return ""
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/errcode"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/wisp"
@@ -58,7 +59,7 @@ func getRig(rigName string) (string, *rig.Rig, error) {
 	rigMgr := rig.NewManager(townRoot, rigsConfig, g)
 	r, err := rigMgr.GetRig(rigName)
 	if err != nil {
-		return "", nil, fmt.Errorf("rig '%s' not found", rigName)
+		return "", nil, errcode.New(errcode.RigNotFound, fmt.Errorf("rig '%s' not found", rigName))
 	}
 
 	return townRoot, r, nil
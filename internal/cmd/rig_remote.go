@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+var (
+	rigSetRemotePush    string
+	rigSetRemotePushURL string
+	rigSetRemoteRemove  bool
+	rigRemotesJSON      bool
+)
+
+var rigSetRemoteCmd = &cobra.Command{
+	Use:   "set-remote <rig> <name> [url]",
+	Short: "Add, update or remove a git remote with a push policy",
+	Long: `Configure a git remote of a rig besides origin.
+
+origin (the rig's git_url) is where the merge queue lands. Extra remotes
+take a push policy:
+
+  branches  Polecat work branches are pushed here instead of origin.
+            Use this for your fork when origin is an upstream you
+            contribute to: polecats push to the fork, the merge queue
+            targets upstream.
+  mirror    Gets a best-effort copy of every merge-queue push.
+  none      Fetch only (the default for new remotes).
+
+The remote is added to the rig's shared repository (.repo.git, or mayor/rig)
+so every polecat, crew and refinery worktree sees it, and is recorded under
+"remotes" in the rig's config.json.
+
+Examples:
+  gt rig set-remote gastown fork git@github.com:me/gastown.git --push branches
+  gt rig set-remote gastown backup git@backup:gastown.git --push mirror
+  gt rig set-remote gastown fork --push none     # Stop pushing branches to the fork
+  gt rig set-remote gastown fork --remove`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: runRigSetRemote,
+}
+
+var rigRemotesCmd = &cobra.Command{
+	Use:   "remotes <rig>",
+	Short: "Show a rig's git remotes and where pushes go",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRigRemotes,
+}
+
+func init() {
+	rigSetRemoteCmd.Flags().StringVar(&rigSetRemotePush, "push", "", "Push policy: "+strings.Join(rig.PushPolicies, ", "))
+	rigSetRemoteCmd.Flags().StringVar(&rigSetRemotePushURL, "push-url", "", "Push to this URL instead of the fetch URL")
+	rigSetRemoteCmd.Flags().BoolVar(&rigSetRemoteRemove, "remove", false, "Remove the remote")
+	rigRemotesCmd.Flags().BoolVar(&rigRemotesJSON, "json", false, "Output as JSON")
+
+	rigCmd.AddCommand(rigSetRemoteCmd)
+	rigCmd.AddCommand(rigRemotesCmd)
+}
+
+func runRigSetRemote(cmd *cobra.Command, args []string) error {
+	rigName, name := args[0], args[1]
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	repo, err := rig.RemoteRepo(r.Path)
+	if err != nil {
+		return err
+	}
+
+	if rigSetRemoteRemove {
+		if len(args) > 2 || rigSetRemotePush != "" || rigSetRemotePushURL != "" {
+			return fmt.Errorf("--remove takes no url, --push or --push-url")
+		}
+		remotes, ok := r.Remotes.Remove(name)
+		if !ok {
+			return fmt.Errorf("rig %s has no remote %q", rigName, name)
+		}
+		if err := rig.SaveRemotes(r.Path, remotes); err != nil {
+			return err
+		}
+		if err := repo.RemoveRemote(name); err != nil {
+			style.PrintWarning("removing git remote %s: %v", name, err)
+		}
+		fmt.Printf("%s Removed remote %s from %s\n", style.SuccessPrefix, name, rigName)
+		return nil
+	}
+
+	rc, err := mergeRemoteConfig(r.Remotes.Get(name), name, args[2:], rigSetRemotePush, rigSetRemotePushURL)
+	if err != nil {
+		return err
+	}
+	remotes := r.Remotes.Set(rc)
+	if err := remotes.Validate(); err != nil {
+		return err
+	}
+	if err := rig.ConfigureRemote(repo, rc); err != nil {
+		return err
+	}
+	if err := rig.SaveRemotes(r.Path, remotes); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s Remote %s → %s (push: %s)\n", style.SuccessPrefix, style.Bold.Render(name), util.RedactURL(rc.URL), rc.Push)
+	if rc.Push == rig.PushBranches && r.PushURL != "" {
+		style.PrintWarning("origin also has push_url %s: merge-queue pushes still go there, not to origin's fetch URL",
+			util.RedactURL(r.PushURL))
+	}
+	return nil
+}
+
+// mergeRemoteConfig applies the url and flags to the existing remote (nil
+// when adding one). New remotes need a url and default to fetch-only.
+func mergeRemoteConfig(existing *rig.RemoteConfig, name string, urlArg []string, push, pushURL string) (rig.RemoteConfig, error) {
+	rc := rig.RemoteConfig{Name: name, Push: rig.PushNone}
+	if existing != nil {
+		rc = *existing
+	}
+	if len(urlArg) > 0 {
+		rc.URL = strings.TrimSpace(urlArg[0])
+	}
+	if rc.URL == "" {
+		return rc, fmt.Errorf("a new remote needs a url: gt rig set-remote <rig> %s <url>", name)
+	}
+	if push != "" {
+		rc.Push = push
+	}
+	if pushURL != "" {
+		rc.PushURL = strings.TrimSpace(pushURL)
+	}
+	return rc, nil
+}
+
+func runRigRemotes(cmd *cobra.Command, args []string) error {
+	_, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+
+	if rigRemotesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Origin       string      `json:"origin"`
+			Remotes      rig.Remotes `json:"remotes"`
+			BranchRemote string      `json:"branch_remote"`
+			Mirrors      []string    `json:"mirrors,omitempty"`
+		}{r.GitURL, r.Remotes, r.Remotes.BranchRemote(), r.Remotes.Mirrors()})
+	}
+
+	fmt.Printf("%s Remotes of %s:\n\n", style.Bold.Render("🔀"), r.Name)
+	origin := util.RedactURL(r.GitURL)
+	if r.PushURL != "" {
+		origin += " (push: " + util.RedactURL(r.PushURL) + ")"
+	}
+	fmt.Printf("  %-10s %s  %s\n", "origin", origin, style.Dim.Render("[merge queue]"))
+	for _, rc := range r.Remotes {
+		url := util.RedactURL(rc.URL)
+		if rc.PushURL != "" {
+			url += " (push: " + util.RedactURL(rc.PushURL) + ")"
+		}
+		fmt.Printf("  %-10s %s  %s\n", rc.Name, url, style.Dim.Render("["+rc.Push+"]"))
+	}
+	fmt.Printf("\n  Work branches → %s\n", r.Remotes.BranchRemote())
+	if mirrors := r.Remotes.Mirrors(); len(mirrors) > 0 {
+		fmt.Printf("  Merges mirrored to %s\n", strings.Join(mirrors, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+func TestMergeRemoteConfig(t *testing.T) {
+	if _, err := mergeRemoteConfig(nil, "fork", nil, rig.PushBranches, ""); err == nil {
+		t.Error("a new remote without a url should fail")
+	}
+
+	rc, err := mergeRemoteConfig(nil, "fork", []string{"git@example.com:me/fork.git"}, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.Push != rig.PushNone {
+		t.Errorf("new remote push = %q, want fetch-only by default", rc.Push)
+	}
+
+	// Updating keeps what isn't given.
+	existing := &rig.RemoteConfig{Name: "fork", URL: "git@example.com:me/fork.git", PushURL: "git@example.com:me/push.git", Push: rig.PushBranches}
+	rc, err = mergeRemoteConfig(existing, "fork", nil, rig.PushMirror, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.URL != existing.URL || rc.PushURL != existing.PushURL || rc.Push != rig.PushMirror {
+		t.Errorf("updated remote = %+v", rc)
+	}
+}
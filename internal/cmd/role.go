@@ -35,6 +35,7 @@ type RoleInfo struct {
 	EnvIncomplete bool   `json:"env_incomplete,omitempty"` // True if env was set but missing rig/polecat, filled from cwd
 	TownRoot      string `json:"town_root,omitempty"`
 	WorkDir       string `json:"work_dir,omitempty"`    // Current working directory
+	Custom        bool   `json:"custom,omitempty"`      // Role is a custom role from <town>/roles/
 }
 
 var roleCmd = &cobra.Command{
@@ -93,7 +94,8 @@ var roleListCmd = &cobra.Command{
 
 Roles include mayor, deacon, witness, refinery, polecat, and crew.
 Each role has a specific scope and responsibilities within the
-Gas Town multi-agent architecture.`,
+Gas Town multi-agent architecture. Custom roles defined with
+gt role define are listed after the built-ins.`,
 	RunE: runRoleList,
 }
 
@@ -121,6 +123,8 @@ Role configuration is layered:
   2. Town-level overrides (<town>/roles/<role>.toml)
   3. Rig-level overrides (<rig>/roles/<role>.toml)
 
+Custom roles (see gt role define) are read from <town>/roles/<role>.toml.
+
 Examples:
   gt role def witness    # Show witness role definition
   gt role def crew       # Show crew role definition`,
@@ -182,6 +186,12 @@ func GetRoleWithContext(cwd, townRoot string) (RoleInfo, error) {
 	cwdCtx := detectRole(cwd, townRoot)
 	info.CwdRole = cwdCtx.Role
 
+	// Custom role sessions carry their own identity (see gt role start)
+	if customRoleInfo(&info, townRoot) {
+		info.CwdRole = cwdCtx.Role
+		return info, nil
+	}
+
 	// Determine authoritative role
 	if envRole != "" {
 		// Parse env role - it might be simple ("mayor") or compound ("gastown/witness")
@@ -249,7 +259,7 @@ func detectRole(cwd, townRoot string) RoleInfo {
 	}
 
 	// Get relative path from town root
-	relPath, err := filepath.Rel(townRoot, cwd)
+	relPath, err := workspace.RelPath(townRoot, cwd)
 	if err != nil {
 		return ctx
 	}
@@ -427,6 +437,9 @@ func (info RoleInfo) ActorString() string {
 	case RoleBoot:
 		return "deacon-boot"
 	default:
+		if info.Custom {
+			return strings.TrimSuffix(customRoleAddress(info), "/")
+		}
 		return string(info.Role)
 	}
 }
@@ -618,6 +631,27 @@ func runRoleList(cmd *cobra.Command, args []string) error {
 	for _, r := range roles {
 		fmt.Printf("  %-10s  %s\n", style.Bold.Render(string(r.name)), r.desc)
 	}
+
+	townRoot, _ := workspace.FindFromCwd()
+	if townRoot == "" {
+		return nil
+	}
+	custom, errs := config.ListCustomRoles(townRoot)
+	if len(custom) > 0 {
+		fmt.Println()
+		fmt.Println("Custom roles:")
+		fmt.Println()
+		for _, def := range custom {
+			desc := def.Description
+			if desc == "" {
+				desc = def.Scope + "-scoped custom role"
+			}
+			fmt.Printf("  %-10s  %s\n", style.Bold.Render(def.Role), desc)
+		}
+	}
+	for _, err := range errs {
+		style.PrintWarning("%v", err)
+	}
 	return nil
 }
 
@@ -681,6 +715,9 @@ func runRoleEnv(cmd *cobra.Command, args []string) error {
 func runRoleDef(cmd *cobra.Command, args []string) error {
 	roleName := args[0]
 
+	// Determine town root and rig path
+	townRoot, _ := workspace.FindFromCwd()
+
 	// Validate role name
 	validRoles := config.AllRoles()
 	isValid := false
@@ -690,12 +727,10 @@ func runRoleDef(cmd *cobra.Command, args []string) error {
 			break
 		}
 	}
-	if !isValid {
+	if !isValid && (townRoot == "" || !config.IsCustomRole(townRoot, roleName)) {
 		return fmt.Errorf("unknown role %q - valid roles: %s", roleName, strings.Join(validRoles, ", "))
 	}
 
-	// Determine town root and rig path
-	townRoot, _ := workspace.FindFromCwd()
 	rigPath := ""
 	if townRoot != "" {
 		// Try to get rig path if we're in a rig directory
@@ -713,6 +748,15 @@ func runRoleDef(cmd *cobra.Command, args []string) error {
 	// Display role info
 	fmt.Printf("%s %s\n", style.Bold.Render("Role:"), def.Role)
 	fmt.Printf("%s %s\n", style.Bold.Render("Scope:"), def.Scope)
+	if def.Description != "" {
+		fmt.Printf("%s %s\n", style.Bold.Render("Description:"), def.Description)
+	}
+	if def.Permissions != "" {
+		fmt.Printf("%s %s\n", style.Bold.Render("Permissions:"), def.Permissions)
+	}
+	if def.Autonomous {
+		fmt.Printf("%s %v\n", style.Bold.Render("Autonomous:"), def.Autonomous)
+	}
 	fmt.Println()
 
 	// Session config
@@ -0,0 +1,442 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cli"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/rbac"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// EnvGTCustomRole names the custom role of an agent session, so role
+// detection doesn't mistake "gastown/architect" for a polecat.
+const EnvGTCustomRole = "GT_CUSTOM_ROLE"
+
+var roleDefineCmd = &cobra.Command{
+	Use:   "define <name>",
+	Short: "Define a custom agent role",
+	Long: `Define a custom agent role beyond the built-in cast.
+
+Writes <town>/roles/<name>.toml (session, health, permissions) and a
+briefing at <town>/roles/<name>.md that gt prime shows the agent. Edit
+both to taste; gt role def <name> shows the effective definition.
+
+A town-scoped role runs one agent in <town>/<name> (session hq-<name>),
+addressed as <name>/ in mail and <name> as a sling target. A rig-scoped
+role runs one agent per rig in <town>/<rig>/<name>, addressed as
+<rig>/<name>.
+
+--permissions caps what the agent may run, using the access levels of
+gt's RBAC (read, nudge, work, admin). Without it the agent is uncapped,
+like the built-in roles.
+
+Examples:
+  gt role define architect --description "Owns cross-rig design"
+  gt role define security-reviewer --scope rig --permissions nudge
+  gt role define release-manager --autonomous`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runRoleDefine,
+}
+
+var roleStartCmd = &cobra.Command{
+	Use:   "start <name>",
+	Short: "Start a custom role's agent session",
+	Long: `Start the tmux session for a custom role's agent.
+
+Rig-scoped roles need --rig.
+
+Examples:
+  gt role start architect
+  gt role start security-reviewer --rig gastown`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runRoleStart,
+}
+
+var roleStopCmd = &cobra.Command{
+	Use:          "stop <name>",
+	Short:        "Stop a custom role's agent session",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runRoleStop,
+}
+
+var roleAttachCmd = &cobra.Command{
+	Use:          "attach <name>",
+	Short:        "Attach to a custom role's agent session",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runRoleAttach,
+}
+
+var (
+	roleDefineScope       string
+	roleDefineDescription string
+	roleDefinePermissions string
+	roleDefineAutonomous  bool
+	roleSessionRig        string
+)
+
+func init() {
+	roleCmd.AddCommand(roleDefineCmd)
+	roleCmd.AddCommand(roleStartCmd)
+	roleCmd.AddCommand(roleStopCmd)
+	roleCmd.AddCommand(roleAttachCmd)
+
+	roleDefineCmd.Flags().StringVar(&roleDefineScope, "scope", "town", "Where the agent runs: town or rig")
+	roleDefineCmd.Flags().StringVar(&roleDefineDescription, "description", "", "One-line summary of the role")
+	roleDefineCmd.Flags().StringVar(&roleDefinePermissions, "permissions", "", "Highest access level the agent may use: read, nudge, work, admin")
+	roleDefineCmd.Flags().BoolVar(&roleDefineAutonomous, "autonomous", false, "Run hooked work immediately on startup instead of waiting for instructions")
+
+	for _, c := range []*cobra.Command{roleStartCmd, roleStopCmd, roleAttachCmd} {
+		c.Flags().StringVar(&roleSessionRig, "rig", "", "Rig for rig-scoped roles")
+	}
+}
+
+func runRoleDefine(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	def := config.NewCustomRole(args[0], roleDefineScope)
+	def.Description = roleDefineDescription
+	def.Permissions = roleDefinePermissions
+	def.Autonomous = roleDefineAutonomous
+	if err := config.WriteCustomRole(townRoot, def); err != nil {
+		return err
+	}
+
+	briefing := def.BriefingPath(townRoot)
+	if _, err := os.Stat(briefing); os.IsNotExist(err) {
+		if err := os.WriteFile(briefing, []byte(customRoleBriefingScaffold(def)), 0644); err != nil {
+			return fmt.Errorf("writing briefing: %w", err)
+		}
+	}
+
+	fmt.Printf("%s Defined role %s (%s scope)\n", style.Bold.Render("✓"), style.Bold.Render(def.Role), def.Scope)
+	fmt.Printf("  definition: %s\n", style.Dim.Render(config.CustomRolePath(townRoot, def.Role)))
+	fmt.Printf("  briefing:   %s\n", style.Dim.Render(briefing))
+	hint := cli.Name() + " role start " + def.Role
+	if def.Scope == "rig" {
+		hint += " --rig <rig>"
+	}
+	fmt.Printf("Start it with: %s\n", hint)
+	return nil
+}
+
+// customRoleBriefingScaffold returns the starting briefing for a new role.
+// {town}, {rig} and {role} are expanded when gt prime renders it.
+func customRoleBriefingScaffold(def *config.RoleDefinition) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s Context\n\n", def.Role)
+	fmt.Fprintf(&b, "You are the **%s**, a custom Gas Town role.\n", def.Role)
+	if def.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", def.Description)
+	}
+	b.WriteString("\n## Responsibilities\n\n- Describe what this role owns and decides.\n")
+	b.WriteString("\n## Working\n\n")
+	fmt.Fprintf(&b, "- Your mail address is `%s`.\n", def.Address("{rig}"))
+	fmt.Fprintf(&b, "- Check your hook: `%s hook`\n", cli.Name())
+	fmt.Fprintf(&b, "- Check mail: `%s mail inbox`\n", cli.Name())
+	fmt.Fprintf(&b, "- Escalate blockers: `%s escalate`\n", cli.Name())
+	return b.String()
+}
+
+// loadCustomRoleFor loads a custom role and checks that rig agrees with its
+// scope: required and registered for rig roles, absent for town roles.
+func loadCustomRoleFor(townRoot, name, rig string) (*config.RoleDefinition, error) {
+	if config.IsBuiltinRole(name) {
+		return nil, fmt.Errorf("%s is a built-in role; use %s %s start", name, cli.Name(), name)
+	}
+	if !config.IsCustomRole(townRoot, name) {
+		return nil, fmt.Errorf("unknown role %q - define it with %s role define %s", name, cli.Name(), name)
+	}
+	def, err := config.LoadCustomRole(townRoot, name)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case def.Scope == "rig" && rig == "":
+		return nil, fmt.Errorf("%s is a rig-scoped role: specify --rig", name)
+	case def.Scope == "town" && rig != "":
+		return nil, fmt.Errorf("%s is a town-scoped role: --rig does not apply", name)
+	case rig != "":
+		rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+		if err != nil {
+			return nil, fmt.Errorf("loading rigs: %w", err)
+		}
+		if _, ok := rigsConfig.Rigs[rig]; !ok {
+			return nil, fmt.Errorf("rig %q is not registered", rig)
+		}
+	}
+	return def, nil
+}
+
+// customRoleSessionName returns the tmux session for a custom role's agent.
+func customRoleSessionName(def *config.RoleDefinition, townRoot, rig string) string {
+	return config.ExpandPattern(def.Session.Pattern, townRoot, rig, "", def.Role, session.PrefixFor(rig))
+}
+
+// customRoleWorkDir returns the working directory for a custom role's agent.
+func customRoleWorkDir(def *config.RoleDefinition, townRoot, rig string) string {
+	return config.ExpandPattern(def.Session.WorkDir, townRoot, rig, "", def.Role, session.PrefixFor(rig))
+}
+
+// customRoleActor returns the BD_ACTOR for a custom role's agent: "<name>"
+// in town scope, "<rig>/<name>" in rig scope.
+func customRoleActor(def *config.RoleDefinition, rig string) string {
+	return strings.TrimSuffix(def.Address(rig), "/")
+}
+
+// customRoleEnv returns the environment for a custom role's agent session.
+// GT_ROLE carries the full address so mail identity needs no further lookup.
+func customRoleEnv(def *config.RoleDefinition, townRoot, rig string) map[string]string {
+	env := map[string]string{
+		EnvGTRole:         def.Address(rig),
+		EnvGTCustomRole:   def.Role,
+		"BD_ACTOR":        customRoleActor(def, rig),
+		"GIT_AUTHOR_NAME": customRoleActor(def, rig),
+		"GT_SCOPE":        def.Scope,
+	}
+	if rig != "" {
+		env["GT_RIG"] = rig
+	}
+	for k, v := range def.Env {
+		env[k] = config.ExpandPattern(v, townRoot, rig, "", def.Role, session.PrefixFor(rig))
+	}
+	return env
+}
+
+func runRoleStart(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	def, err := loadCustomRoleFor(townRoot, args[0], roleSessionRig)
+	if err != nil {
+		return err
+	}
+
+	t := tmux.NewTmux()
+	sessionID := customRoleSessionName(def, townRoot, roleSessionRig)
+	if running, _ := t.HasSession(sessionID); running {
+		return fmt.Errorf("%s session %s is already running", def.Role, sessionID)
+	}
+
+	workDir := customRoleWorkDir(def, townRoot, roleSessionRig)
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", workDir, err)
+	}
+
+	rigPath := ""
+	if roleSessionRig != "" {
+		rigPath = filepath.Join(townRoot, roleSessionRig)
+	}
+	fmt.Printf("Starting %s session...\n", def.Address(roleSessionRig))
+	_, err = session.StartSession(t, session.SessionConfig{
+		SessionID: sessionID,
+		WorkDir:   workDir,
+		Role:      def.Role,
+		TownRoot:  townRoot,
+		RigPath:   rigPath,
+		RigName:   roleSessionRig,
+		Beacon: session.BeaconConfig{
+			Recipient: def.Address(roleSessionRig),
+			Sender:    "human",
+			Topic:     "cold-start",
+		},
+		Instructions: def.Nudge,
+		ExtraEnv:     customRoleEnv(def, townRoot, roleSessionRig),
+		WaitForAgent: true,
+		AcceptBypass: true,
+	})
+	if err != nil {
+		return fmt.Errorf("starting %s: %w", def.Role, err)
+	}
+
+	fmt.Printf("%s %s session started: %s\n", style.Bold.Render("✓"), def.Role, sessionID)
+	fmt.Printf("  Attach with: %s role attach %s%s\n", cli.Name(), def.Role, customRoleRigFlag(roleSessionRig))
+	return nil
+}
+
+func runRoleStop(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	def, err := loadCustomRoleFor(townRoot, args[0], roleSessionRig)
+	if err != nil {
+		return err
+	}
+
+	t := tmux.NewTmux()
+	sessionID := customRoleSessionName(def, townRoot, roleSessionRig)
+	if running, _ := t.HasSession(sessionID); !running {
+		return fmt.Errorf("%s session is not running", def.Address(roleSessionRig))
+	}
+	if err := t.KillSessionWithProcesses(sessionID); err != nil {
+		return fmt.Errorf("stopping %s: %w", sessionID, err)
+	}
+	fmt.Printf("%s %s session stopped.\n", style.Bold.Render("✓"), def.Address(roleSessionRig))
+	return nil
+}
+
+func runRoleAttach(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	def, err := loadCustomRoleFor(townRoot, args[0], roleSessionRig)
+	if err != nil {
+		return err
+	}
+
+	sessionID := customRoleSessionName(def, townRoot, roleSessionRig)
+	if running, _ := tmux.NewTmux().HasSession(sessionID); !running {
+		return fmt.Errorf("%s session is not running - start it with %s role start %s%s",
+			def.Address(roleSessionRig), cli.Name(), def.Role, customRoleRigFlag(roleSessionRig))
+	}
+	return attachToTmuxSession(sessionID)
+}
+
+func customRoleRigFlag(rig string) string {
+	if rig == "" {
+		return ""
+	}
+	return " --rig " + rig
+}
+
+// customRoleInfo fills role info for an agent session started by gt role
+// start. Returns false when the session isn't a custom role's.
+func customRoleInfo(info *RoleInfo, townRoot string) bool {
+	name := os.Getenv(EnvGTCustomRole)
+	if name == "" || info.EnvRole == "" {
+		return false
+	}
+	info.Role = Role(name)
+	info.Custom = true
+	info.Rig = os.Getenv("GT_RIG")
+	info.Source = "env"
+	if def, err := config.LoadCustomRole(townRoot, name); err == nil {
+		info.Home = customRoleWorkDir(def, townRoot, info.Rig)
+	}
+	return true
+}
+
+// customRoleAddress returns the mail address and sling agent ID of a custom
+// role agent.
+func customRoleAddress(info RoleInfo) string {
+	if info.Rig != "" {
+		return info.Rig + "/" + string(info.Role)
+	}
+	return string(info.Role) + "/"
+}
+
+// resolveCustomRoleTarget resolves a sling target naming a custom role
+// ("architect" or "gastown/security-reviewer") to its agent ID and session.
+// Returns ok=false when target isn't a custom role.
+func resolveCustomRoleTarget(townRoot, target string) (agentID, sessionID string, ok bool) {
+	if townRoot == "" {
+		return "", "", false
+	}
+	rig, name, found := strings.Cut(strings.TrimSuffix(target, "/"), "/")
+	if !found {
+		rig, name = "", rig
+	}
+	if !config.IsCustomRole(townRoot, name) {
+		return "", "", false
+	}
+	def, err := config.LoadCustomRole(townRoot, name)
+	if err != nil || (def.Scope == "rig") != (rig != "") {
+		return "", "", false
+	}
+	return def.Address(rig), customRoleSessionName(def, townRoot, rig), true
+}
+
+// enforceCustomRolePermissions caps a custom role agent's commands at the
+// access level its definition grants. Roles without permissions are uncapped.
+func enforceCustomRolePermissions(cmd *cobra.Command, townRoot string) error {
+	name := os.Getenv(EnvGTCustomRole)
+	def, err := config.LoadCustomRole(townRoot, name)
+	if err != nil || def.Permissions == "" {
+		return nil
+	}
+	granted, err := rbac.ParseLevel(def.Permissions)
+	if err != nil {
+		return nil
+	}
+	command := accessCommand(cmd)
+	if level := rbac.Classify(command); level > granted {
+		return &rbac.DeniedError{
+			Principal: rbac.Principal{Name: strings.TrimSuffix(os.Getenv(EnvGTRole), "/"), Role: name},
+			Command:   command,
+			Level:     level,
+		}
+	}
+	return nil
+}
+
+// outputCustomRoleContext prints a custom role's briefing for gt prime.
+func outputCustomRoleContext(ctx RoleContext) string {
+	def, err := config.LoadCustomRole(ctx.TownRoot, string(ctx.Role))
+	if err != nil {
+		outputUnknownContext(ctx)
+		return ""
+	}
+	var output string
+	if path := def.BriefingPath(ctx.TownRoot); path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			output = config.ExpandPattern(string(data), ctx.TownRoot, ctx.Rig, "", def.Role, session.PrefixFor(ctx.Rig))
+		}
+	}
+	if output == "" {
+		output = fmt.Sprintf("# %s Context\n\nYou are the %s, a custom Gas Town role.\n", def.Role, def.Role)
+		if def.Description != "" {
+			output += "\n" + def.Description + "\n"
+		}
+	}
+	if !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	output += fmt.Sprintf("\nAddress: `%s`", customRoleAddress(ctx))
+	if def.Permissions != "" {
+		output += fmt.Sprintf(" · Permissions: %s", def.Permissions)
+	}
+	output += "\n"
+	fmt.Print(output)
+	return output
+}
+
+// outputCustomRoleStartupDirective prints the startup protocol for a custom
+// role. Autonomous roles run hooked work at once; others await instructions.
+func outputCustomRoleStartupDirective(ctx RoleContext) {
+	autonomous := false
+	if def, err := config.LoadCustomRole(ctx.TownRoot, string(ctx.Role)); err == nil {
+		autonomous = def.Autonomous
+	}
+	fmt.Println()
+	fmt.Println("---")
+	fmt.Println()
+	fmt.Printf("**STARTUP PROTOCOL**: You are the %s. Please:\n", ctx.Role)
+	fmt.Println("1. Announce: \"" + buildRoleAnnouncement(ctx) + "\"")
+	fmt.Println("2. Check mail: `" + cli.Name() + " mail inbox` - look for 🤝 HANDOFF messages")
+	fmt.Println("3. Check for attached work: `" + cli.Name() + " hook`")
+	if autonomous {
+		fmt.Println("   - If attached → **RUN IT** (no human input needed)")
+		fmt.Println("   - If nothing attached → wait for mail or slung work")
+	} else {
+		fmt.Println("   - If attached → work it")
+		fmt.Println("   - If nothing attached → await user instruction")
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rbac"
+)
+
+func writeTestCustomRole(t *testing.T, townRoot, name, scope, permissions string) *config.RoleDefinition {
+	t.Helper()
+	def := config.NewCustomRole(name, scope)
+	def.Permissions = permissions
+	if err := config.WriteCustomRole(townRoot, def); err != nil {
+		t.Fatalf("WriteCustomRole: %v", err)
+	}
+	return def
+}
+
+func TestGetRoleWithContext_CustomRole(t *testing.T) {
+	townRoot := t.TempDir()
+	writeTestCustomRole(t, townRoot, "security-reviewer", "rig", "")
+
+	t.Setenv(EnvGTRole, "gastown/security-reviewer")
+	t.Setenv(EnvGTCustomRole, "security-reviewer")
+	t.Setenv("GT_RIG", "gastown")
+
+	info, err := GetRoleWithContext(townRoot, townRoot)
+	if err != nil {
+		t.Fatalf("GetRoleWithContext: %v", err)
+	}
+	if info.Role != "security-reviewer" || !info.Custom || info.Rig != "gastown" {
+		t.Errorf("info = %+v, want custom security-reviewer in gastown", info)
+	}
+	if want := filepath.Join(townRoot, "gastown", "security-reviewer"); info.Home != want {
+		t.Errorf("Home = %q, want %q", info.Home, want)
+	}
+	if got := info.ActorString(); got != "gastown/security-reviewer" {
+		t.Errorf("ActorString() = %q", got)
+	}
+	if got := getAgentIdentity(info); got != "gastown/security-reviewer" {
+		t.Errorf("getAgentIdentity() = %q", got)
+	}
+}
+
+func TestCustomRoleEnv(t *testing.T) {
+	townRoot := t.TempDir()
+	def := config.NewCustomRole("architect", "town")
+	def.Env = map[string]string{"ARCH_HOME": "{town}/{role}"}
+
+	env := customRoleEnv(def, townRoot, "")
+	if env[EnvGTRole] != "architect/" || env[EnvGTCustomRole] != "architect" || env["BD_ACTOR"] != "architect" {
+		t.Errorf("identity env = %v", env)
+	}
+	if _, ok := env["GT_RIG"]; ok {
+		t.Error("town-scoped role must not set GT_RIG")
+	}
+	if env["ARCH_HOME"] != townRoot+"/architect" {
+		t.Errorf("ARCH_HOME = %q, want placeholders expanded", env["ARCH_HOME"])
+	}
+	if got := customRoleSessionName(def, townRoot, ""); got != "hq-architect" {
+		t.Errorf("session name = %q, want hq-architect", got)
+	}
+}
+
+func TestResolveCustomRoleTarget(t *testing.T) {
+	townRoot := t.TempDir()
+	writeTestCustomRole(t, townRoot, "architect", "town", "")
+	writeTestCustomRole(t, townRoot, "security-reviewer", "rig", "")
+
+	tests := []struct {
+		target    string
+		wantAgent string
+		wantOK    bool
+	}{
+		{"architect", "architect/", true},
+		{"architect/", "architect/", true},
+		{"gastown/security-reviewer", "gastown/security-reviewer", true},
+		{"security-reviewer", "", false}, // rig role needs a rig
+		{"gastown/architect", "", false}, // town role has no rig
+		{"gastown/nux", "", false},
+		{"mayor", "", false},
+	}
+	for _, tt := range tests {
+		agent, sessionID, ok := resolveCustomRoleTarget(townRoot, tt.target)
+		if ok != tt.wantOK || agent != tt.wantAgent {
+			t.Errorf("resolveCustomRoleTarget(%q) = %q, %v; want %q, %v", tt.target, agent, ok, tt.wantAgent, tt.wantOK)
+		}
+		if ok && sessionID == "" {
+			t.Errorf("resolveCustomRoleTarget(%q) returned no session", tt.target)
+		}
+	}
+}
+
+func TestEnforceAccess_CustomRolePermissions(t *testing.T) {
+	townRoot := setupTownWithAccess(t, nil)
+	writeTestCustomRole(t, townRoot, "auditor", "town", "nudge")
+	writeTestCustomRole(t, townRoot, "architect", "town", "")
+
+	t.Setenv(EnvGTRole, "auditor/")
+	t.Setenv(EnvGTCustomRole, "auditor")
+	if err := enforceAccess(accessTestCommand("convoy", "list"), nil); err != nil {
+		t.Errorf("read command denied: %v", err)
+	}
+	if err := enforceAccess(accessTestCommand("escalate", "ack"), nil); err != nil {
+		t.Errorf("nudge command denied: %v", err)
+	}
+	var denied *rbac.DeniedError
+	err := enforceAccess(accessTestCommand("mail", "send"), nil)
+	if !errors.As(err, &denied) {
+		t.Fatalf("work command for nudge role = %v, want DeniedError", err)
+	}
+	if !strings.Contains(err.Error(), "auditor") {
+		t.Errorf("error %q should name the role", err)
+	}
+
+	// Roles without permissions are uncapped, like built-in agents.
+	t.Setenv(EnvGTRole, "architect/")
+	t.Setenv(EnvGTCustomRole, "architect")
+	if err := enforceAccess(accessTestCommand("rig", "remove"), []string{"gastown"}); err != nil {
+		t.Errorf("uncapped role denied: %v", err)
+	}
+}
+
+func TestCustomRoleBriefingScaffold(t *testing.T) {
+	def := config.NewCustomRole("release-manager", "town")
+	def.Description = "Cuts releases"
+	got := customRoleBriefingScaffold(def)
+	for _, want := range []string{"# release-manager Context", "Cuts releases", "`release-manager/`"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("briefing missing %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,454 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/cli"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/persona"
+	"github.com/steveyegge/gastown/internal/rbac"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var rolePersonaCmd = &cobra.Command{
+	Use:   "persona <role>",
+	Short: "Show a role's active persona",
+	Long: `Show the persona gt prime layers on top of a role's briefing.
+
+A persona holds the operator's guidance for a role — tone, priorities,
+house rules — in one versioned place instead of scattered CLAUDE.md edits.
+Versions live in <town>/roles/personas/<role>/ and every change records
+who made it.
+
+Examples:
+  gt role persona mayor
+  gt role persona witness --version 2`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runRolePersona,
+}
+
+var roleEditCmd = &cobra.Command{
+	Use:   "edit <role>",
+	Short: "Edit a role's persona (creates a new version)",
+	Long: `Edit a role's persona in $EDITOR and save it as a new version.
+
+If the role has running sessions, you are asked before the change is
+re-injected into them; agents are nudged to re-read their persona.
+
+Examples:
+  gt role edit mayor
+  gt role edit witness --file witness-persona.md --note "stricter triage"
+  gt role edit polecat --yes          # re-inject without asking`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runRoleEdit,
+}
+
+var roleDiffCmd = &cobra.Command{
+	Use:   "diff <role> [from] [to]",
+	Short: "Diff persona versions",
+	Long: `Show a line diff between two persona versions.
+
+With no versions, compares the previous version to the active one. With
+one, compares that version to the active one.
+
+Examples:
+  gt role diff mayor
+  gt role diff mayor 1
+  gt role diff mayor 1 3`,
+	Args:         cobra.RangeArgs(1, 3),
+	SilenceUsage: true,
+	RunE:         runRoleDiff,
+}
+
+var roleRollbackCmd = &cobra.Command{
+	Use:   "rollback <role> <version>",
+	Short: "Restore an earlier persona version",
+	Long: `Restore an earlier persona version.
+
+Rollback appends a new version with the old content, so history is never
+rewritten. Running sessions are offered re-injection as with gt role edit.
+
+Examples:
+  gt role rollback mayor 2`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE:         runRoleRollback,
+}
+
+var roleHistoryCmd = &cobra.Command{
+	Use:   "history <role>",
+	Short: "List persona versions",
+	Args:  cobra.ExactArgs(1),
+	Long: `List a role's persona versions with author, time and note.
+
+Examples:
+  gt role history mayor`,
+	SilenceUsage: true,
+	RunE:         runRoleHistory,
+}
+
+var (
+	rolePersonaVersion int
+	roleEditFile       string
+	roleEditNote       string
+	roleEditYes        bool
+	roleEditNoInject   bool
+)
+
+func init() {
+	roleCmd.AddCommand(rolePersonaCmd)
+	roleCmd.AddCommand(roleEditCmd)
+	roleCmd.AddCommand(roleDiffCmd)
+	roleCmd.AddCommand(roleRollbackCmd)
+	roleCmd.AddCommand(roleHistoryCmd)
+
+	rolePersonaCmd.Flags().IntVar(&rolePersonaVersion, "version", 0, "Show this version instead of the active one")
+
+	roleEditCmd.Flags().StringVar(&roleEditFile, "file", "", "Read the new persona from a file instead of opening $EDITOR")
+	roleEditCmd.Flags().StringVar(&roleEditNote, "note", "", "Change note recorded in the history")
+	for _, c := range []*cobra.Command{roleEditCmd, roleRollbackCmd} {
+		c.Flags().BoolVarP(&roleEditYes, "yes", "y", false, "Re-inject into running sessions without asking")
+		c.Flags().BoolVar(&roleEditNoInject, "no-inject", false, "Don't re-inject into running sessions")
+	}
+}
+
+// personaRoleTown resolves the town and checks role names a built-in or
+// custom role.
+func personaRoleTown(role string) (string, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if !config.IsBuiltinRole(role) && !config.IsCustomRole(townRoot, role) {
+		return "", fmt.Errorf("unknown role %q - see %s role list", role, cli.Name())
+	}
+	return townRoot, nil
+}
+
+// changeAuthor attributes town changes such as persona edits: the agent's
+// address in agent sessions, otherwise the human operator.
+func changeAuthor() string {
+	if os.Getenv(EnvGTRole) != "" {
+		return detectSender()
+	}
+	return rbac.OperatorFromEnv()
+}
+
+func runRolePersona(cmd *cobra.Command, args []string) error {
+	role := args[0]
+	townRoot, err := personaRoleTown(role)
+	if err != nil {
+		return err
+	}
+	store := persona.NewStore(townRoot)
+	if rolePersonaVersion > 0 {
+		content, err := store.Content(role, rolePersonaVersion)
+		if err != nil {
+			return err
+		}
+		fmt.Print(content)
+		return nil
+	}
+	v, content, err := store.Current(role)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		fmt.Printf("No persona for %s. Create one with: %s role edit %s\n", role, cli.Name(), role)
+		return nil
+	}
+	fmt.Print(content)
+	return nil
+}
+
+func runRoleEdit(cmd *cobra.Command, args []string) error {
+	role := args[0]
+	townRoot, err := personaRoleTown(role)
+	if err != nil {
+		return err
+	}
+	store := persona.NewStore(townRoot)
+	_, current, err := store.Current(role)
+	if err != nil {
+		return err
+	}
+
+	var content string
+	if roleEditFile != "" {
+		data, err := os.ReadFile(roleEditFile)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", roleEditFile, err)
+		}
+		content = string(data)
+	} else {
+		content, err = editPersonaInEditor(role, current)
+		if err != nil {
+			return err
+		}
+	}
+
+	v, err := store.Save(role, content, changeAuthor(), roleEditNote)
+	if errors.Is(err, persona.ErrUnchanged) {
+		fmt.Println("No changes; persona not updated.")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Saved %s persona v%d\n", style.Bold.Render("✓"), role, v.Version)
+	return offerPersonaReinject(townRoot, role, v)
+}
+
+// editPersonaInEditor opens current in $EDITOR (default vi) and returns the
+// edited text.
+func editPersonaInEditor(role, current string) (string, error) {
+	f, err := os.CreateTemp("", "gt-persona-"+role+"-*.md")
+	if err != nil {
+		return "", fmt.Errorf("creating temp file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+	if _, err := f.WriteString(current); err != nil {
+		f.Close()
+		return "", err
+	}
+	f.Close()
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	editorCmd := exec.Command(editor, path)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return "", fmt.Errorf("running editor: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func runRoleDiff(cmd *cobra.Command, args []string) error {
+	role := args[0]
+	townRoot, err := personaRoleTown(role)
+	if err != nil {
+		return err
+	}
+	store := persona.NewStore(townRoot)
+	history, err := store.History(role)
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		fmt.Printf("No persona for %s.\n", role)
+		return nil
+	}
+
+	latest := history[len(history)-1].Version
+	from, to := latest-1, latest
+	if len(args) >= 2 {
+		if from, err = strconv.Atoi(args[1]); err != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+	}
+	if len(args) == 3 {
+		if to, err = strconv.Atoi(args[2]); err != nil {
+			return fmt.Errorf("invalid version %q", args[2])
+		}
+	}
+
+	var a string
+	if from > 0 {
+		if a, err = store.Content(role, from); err != nil {
+			return err
+		}
+	}
+	b, err := store.Content(role, to)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render(fmt.Sprintf("%s persona v%d → v%d", role, from, to)))
+	lines := persona.Diff(a, b)
+	if !persona.Changed(lines) {
+		fmt.Println(style.Dim.Render("(no differences)"))
+		return nil
+	}
+	for _, l := range lines {
+		switch l.Op {
+		case '+':
+			fmt.Println(diffAdd.Render("+ " + l.Text))
+		case '-':
+			fmt.Println(diffRemove.Render("- " + l.Text))
+		default:
+			fmt.Println(style.Dim.Render("  " + l.Text))
+		}
+	}
+	return nil
+}
+
+func runRoleRollback(cmd *cobra.Command, args []string) error {
+	role := args[0]
+	townRoot, err := personaRoleTown(role)
+	if err != nil {
+		return err
+	}
+	version, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid version %q", args[1])
+	}
+	v, err := persona.NewStore(townRoot).Rollback(role, version, changeAuthor())
+	if errors.Is(err, persona.ErrUnchanged) {
+		fmt.Printf("v%d is already the active %s persona.\n", version, role)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Restored %s persona v%d as v%d\n", style.Bold.Render("✓"), role, version, v.Version)
+	return offerPersonaReinject(townRoot, role, v)
+}
+
+func runRoleHistory(cmd *cobra.Command, args []string) error {
+	role := args[0]
+	townRoot, err := personaRoleTown(role)
+	if err != nil {
+		return err
+	}
+	history, err := persona.NewStore(townRoot).History(role)
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		fmt.Printf("No persona for %s.\n", role)
+		return nil
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		v := history[i]
+		marker := " "
+		if i == len(history)-1 {
+			marker = "●"
+		}
+		note := v.Note
+		if note == "" {
+			note = "-"
+		}
+		fmt.Printf("%s v%-3d %s  %-16s %s\n", marker, v.Version,
+			style.Dim.Render(v.At.Local().Format("2006-01-02 15:04")), v.Author, note)
+	}
+	return nil
+}
+
+// offerPersonaReinject asks the operator whether to push a persona change to
+// the role's running sessions, then nudges each one to re-read it.
+func offerPersonaReinject(townRoot, role string, v *persona.Version) error {
+	if roleEditNoInject {
+		return nil
+	}
+	t := tmux.NewTmux()
+	sessions := personaSessions(t, townRoot, role)
+	if len(sessions) == 0 {
+		return nil
+	}
+	if !roleEditYes && !promptYesNo(fmt.Sprintf("Re-inject into %d running %s session(s)?", len(sessions), role)) {
+		fmt.Printf("Not re-injected; sessions pick up v%d on their next gt prime.\n", v.Version)
+		return nil
+	}
+	msg := fmt.Sprintf("🎭 Your %s persona was updated to v%d by %s. Run `%s role persona %s` and follow it from now on.",
+		role, v.Version, v.Author, cli.Name(), role)
+	for _, s := range sessions {
+		if err := t.NudgeSession(s, msg); err != nil {
+			style.PrintWarning("could not nudge %s: %v", s, err)
+			continue
+		}
+		fmt.Printf("  %s %s\n", style.Bold.Render("→"), s)
+	}
+	return nil
+}
+
+// personaSessions returns the running tmux sessions of role's agents.
+func personaSessions(t *tmux.Tmux, townRoot, role string) []string {
+	running, err := t.ListSessions()
+	if err != nil {
+		return nil
+	}
+	if config.IsCustomRole(townRoot, role) {
+		return customRoleSessions(townRoot, role, running)
+	}
+	var out []string
+	for _, s := range running {
+		id, err := session.ParseSessionName(s)
+		if err != nil || string(id.Role) != role {
+			continue
+		}
+		if id.Role == session.RoleDeacon && id.Name == "boot" {
+			continue
+		}
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// customRoleSessions returns the running sessions of a custom role: its one
+// town session, or its session in each registered rig.
+func customRoleSessions(townRoot, role string, running []string) []string {
+	def, err := config.LoadCustomRole(townRoot, role)
+	if err != nil {
+		return nil
+	}
+	want := map[string]bool{}
+	if def.Scope == "rig" {
+		if rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+			for rig := range rigsConfig.Rigs {
+				want[customRoleSessionName(def, townRoot, rig)] = true
+			}
+		}
+	} else {
+		want[customRoleSessionName(def, townRoot, "")] = true
+	}
+	var out []string
+	for _, s := range running {
+		if want[s] {
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// outputPersona appends the role's active persona to gt prime output.
+func outputPersona(ctx RoleContext) {
+	if ctx.Role == RoleUnknown || ctx.TownRoot == "" {
+		return
+	}
+	role := string(ctx.Role)
+	v, content, err := persona.NewStore(ctx.TownRoot).Current(role)
+	if err != nil || v == nil {
+		explain(true, "Persona: none for "+role)
+		return
+	}
+	explain(true, fmt.Sprintf("Persona: %s v%d", role, v.Version))
+	fmt.Println()
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("## 🎭 Persona (v%d)", v.Version)))
+	fmt.Print(content)
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		fmt.Println()
+	}
+}
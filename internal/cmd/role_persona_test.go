@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/persona"
+)
+
+func TestOutputPersona(t *testing.T) {
+	townRoot := t.TempDir()
+	ctx := RoleContext{Role: RoleWitness, TownRoot: townRoot}
+
+	if out := captureStdout(t, func() { outputPersona(ctx) }); out != "" {
+		t.Errorf("output with no persona = %q, want empty", out)
+	}
+
+	store := persona.NewStore(townRoot)
+	for _, c := range []string{"Triage fast.\n", "Triage fast.\nEscalate stuck polecats."} {
+		if _, err := store.Save("witness", c, "alice", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out := captureStdout(t, func() { outputPersona(ctx) })
+	if !strings.Contains(out, "Persona (v2)") || !strings.HasSuffix(out, "Escalate stuck polecats.\n") {
+		t.Errorf("output = %q, want active v2 persona", out)
+	}
+
+	// Another role's persona must not leak in.
+	if out := captureStdout(t, func() { outputPersona(RoleContext{Role: RoleMayor, TownRoot: townRoot}) }); out != "" {
+		t.Errorf("mayor output = %q, want empty", out)
+	}
+}
+
+func TestCustomRoleSessions(t *testing.T) {
+	townRoot := t.TempDir()
+	writeTestCustomRole(t, townRoot, "architect", "town", "")
+	writeTestCustomRole(t, townRoot, "security-reviewer", "rig", "")
+
+	rigsPath := constants.MayorRigsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(rigsPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rigs := `{"version":1,"rigs":{"gastown":{"git_url":"x","beads":{"prefix":"gt"}}}}`
+	if err := os.WriteFile(rigsPath, []byte(rigs), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	running := []string{"hq-mayor", "hq-architect", "gt-security-reviewer", "gt-nux"}
+	if got := customRoleSessions(townRoot, "architect", running); len(got) != 1 || got[0] != "hq-architect" {
+		t.Errorf("architect sessions = %v, want [hq-architect]", got)
+	}
+	if got := customRoleSessions(townRoot, "security-reviewer", running); len(got) != 1 || got[0] != "gt-security-reviewer" {
+		t.Errorf("security-reviewer sessions = %v, want [gt-security-reviewer]", got)
+	}
+}
+
+func TestPersonaRoleTown_UnknownRole(t *testing.T) {
+	townRoot := setupTownWithAccess(t, nil)
+	t.Chdir(townRoot)
+	if _, err := personaRoleTown("mayor"); err != nil {
+		t.Errorf("personaRoleTown(mayor) = %v", err)
+	}
+	if _, err := personaRoleTown("nonesuch"); err == nil {
+		t.Error("personaRoleTown(nonesuch) = nil error, want unknown role")
+	}
+}
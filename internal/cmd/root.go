@@ -10,8 +10,10 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/cli"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/i18n"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
@@ -72,6 +74,12 @@ var beadsExemptCommands = map[string]bool{
 	"health":              true, // Health check doesn't require beads
 	"upgrade":             true, // Post-install migration orchestrator
 	"heartbeat":           true, // Heartbeat state update — must be fast and dependency-free
+	"sync":                true, // Replays journaled commands, which check beads themselves
+	"ask":                 true, // Read-only expert query, no beads access
+	"whereami":            true, // Diagnoses town discovery, must work outside towns
+	"ping":                true, // Probes sessions; timing must not include beads checks
+	"explain":             true, // Static error catalog, must work when bd is missing
+	"locale":              true, // Translation files only; must work without bd
 }
 
 // Commands exempt from the town root branch warning.
@@ -104,6 +112,9 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	// Initialize CLI theme (dark/light mode support)
 	initCLITheme()
 
+	// Load the town's translations for output and agent briefings
+	initLocale()
+
 	// Log command usage telemetry (fire-and-forget, excludes tap/signal)
 	logCommandUsage(cmd, args)
 
@@ -121,6 +132,24 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	// Get the root command name being run
 	cmdName := cmd.Name()
 
+	// Start the audit record for mutating commands (written by Execute)
+	beginAuditRecord(cmd, args)
+
+	// Enforce the town access policy (RBAC) for human operators
+	if err := enforceAccess(cmd, args); err != nil {
+		return err
+	}
+
+	// Enforce the town's gt version pin and experimental feature gates
+	if err := enforceTownPolicy(cmd); err != nil {
+		return err
+	}
+
+	// Hold mutating commands while another operator has the town locked
+	if err := enforceTownLock(cmd); err != nil {
+		return err
+	}
+
 	// Check for stale binary (warning only, doesn't block)
 	if !beadsExemptCommands[cmdName] {
 		checkStaleBinaryWarning()
@@ -179,6 +208,22 @@ func initCLITheme() {
 	ui.ApplyThemeMode()
 }
 
+// initLocale activates the town's locale (GT_LOCALE overrides it). A broken
+// translation file is reported and English used instead.
+func initLocale() {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	var configured string
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		configured = settings.Locale
+	}
+	if err := i18n.Use(townRoot, i18n.Resolve(configured)); err != nil {
+		style.PrintWarning("locale: %v (using English)", err)
+	}
+}
+
 // touchPolecatHeartbeat touches the session heartbeat file for polecat agents.
 // Called from persistentPreRun on every gt command. The heartbeat signals that
 // the agent process is alive and actively running gt commands. Used by
@@ -300,12 +345,18 @@ func Execute() int {
 		telemetry.SetProcessOTELAttrs()
 	}
 
-	if err := rootCmd.Execute(); err != nil {
+	cmd, err := rootCmd.ExecuteC()
+	finishAuditRecord(err)
+	if os.Getenv("GT_BD_TIMINGS") != "" {
+		printBDTimings(os.Stderr, beads.CallStats())
+	}
+	if err != nil {
 		// Check for silent exit (scripting commands that signal status via exit code)
 		if code, ok := IsSilentExit(err); ok {
 			return code
 		}
-		// Other errors already printed by cobra
+		// Cobra printed the message; add the code and hint
+		reportError(cmd, err, os.Stdout, os.Stderr)
 		return 1
 	}
 	return 0
@@ -483,13 +483,20 @@ func beadsSearchDirs(townRoot string) []string {
 
 // countActivePolecats counts all running polecats across all rigs in the town.
 func countActivePolecats() int {
+	count := 0
+	forEachPolecatSession(func(*session.AgentIdentity) { count++ })
+	return count
+}
+
+// forEachPolecatSession calls fn with the identity of each running polecat
+// tmux session.
+func forEachPolecatSession(fn func(*session.AgentIdentity)) {
 	listCmd := tmux.BuildCommand("list-sessions", "-F", "#{session_name}")
 	out, err := listCmd.Output()
 	if err != nil {
-		return 0
+		return
 	}
 
-	count := 0
 	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
 		if line == "" {
 			continue
@@ -499,8 +506,7 @@ func countActivePolecats() int {
 			continue
 		}
 		if identity.Role == session.RolePolecat {
-			count++
+			fn(identity)
 		}
 	}
-	return count
 }
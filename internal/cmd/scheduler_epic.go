@@ -306,7 +306,7 @@ func getEpicChildren(epicID string) ([]epicChild, error) {
 
 	var stderr bytes.Buffer
 	depCmd.Stderr = &stderr
-	if err := depCmd.Run(); err != nil {
+	if err := beads.RunCmd(depCmd); err != nil {
 		if stdout.Len() == 0 && stderr.Len() == 0 {
 			return nil, nil
 		}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/gofrs/flock"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/atrest"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
@@ -526,10 +527,12 @@ func findSessionLocation(townRoot, sessionID string) *sessionLocation {
 					continue
 				}
 				sessionFile := filepath.Join(fallbackProjectsDir, entry.Name(), sessionID+".jsonl")
-				if _, statErr := os.Stat(sessionFile); statErr == nil {
-					return &sessionLocation{
-						configDir:  resolved,
-						projectDir: entry.Name(),
+				for _, candidate := range []string{sessionFile, sessionFile + atrest.SealedSuffix} {
+					if _, statErr := os.Stat(candidate); statErr == nil {
+						return &sessionLocation{
+							configDir:  resolved,
+							projectDir: entry.Name(),
+						}
 					}
 				}
 			}
@@ -539,6 +542,19 @@ func findSessionLocation(townRoot, sessionID string) *sessionLocation {
 	return nil
 }
 
+// unsealSessionFile restores a session transcript sealed at rest (gt crypt)
+// so the runtime can resume it. It is sealed again once idle.
+func unsealSessionFile(loc *sessionLocation, sessionID string) error {
+	sealed := filepath.Join(loc.configDir, "projects", loc.projectDir, sessionID+".jsonl"+atrest.SealedSuffix)
+	if _, err := os.Stat(sealed); err != nil {
+		return nil
+	}
+	if _, err := atrest.UnsealFile(sealed); err != nil {
+		return fmt.Errorf("unsealing session transcript: %w", err)
+	}
+	return nil
+}
+
 // symlinkSessionToCurrentAccount finds a session in any account and symlinks
 // it to the current account so Claude can access it.
 // Returns a cleanup function to remove the symlink after use.
@@ -568,6 +584,9 @@ func symlinkSessionToConfigDir(townRoot, sessionID, targetConfigDir string) (cle
 	if loc == nil {
 		return nil, fmt.Errorf("session not found in any account")
 	}
+	if err := unsealSessionFile(loc, sessionID); err != nil {
+		return nil, err
+	}
 
 	// Session in same account but possibly different project dir.
 	// Symlink into cwd-based project dir so Claude can find it via --resume.
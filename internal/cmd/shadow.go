@@ -0,0 +1,462 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/shadow"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// shadowTestTimeout bounds one side's test run.
+const shadowTestTimeout = 30 * time.Minute
+
+// shadowTestTailLines is how much of a failing test run's output is kept.
+const shadowTestTailLines = 30
+
+var (
+	shadowCompareTests bool
+	shadowCompareJSON  bool
+	shadowPickDryRun   bool
+)
+
+var shadowCmd = &cobra.Command{
+	Use:     "shadow",
+	GroupID: GroupWork,
+	Short:   "Compare beads run on two agents (gt sling --shadow)",
+	Long: `Inspect and settle shadow runs.
+
+'gt sling <bead> <rig> --shadow <agent>' runs a bead on one polecat and a
+copy of it (the shadow bead) on another, with a different agent or
+--shadow-args. Neither run is merged. Once both are done, compare their
+branches and test results, then pick the one that lands: it goes to the
+merge queue as the original bead and the shadow bead is closed.
+
+Examples:
+  gt shadow list
+  gt shadow compare gt-abc --tests
+  gt shadow pick gt-abc b`,
+	RunE: requireSubcommand,
+}
+
+var shadowListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List shadow runs",
+	Args:  cobra.NoArgs,
+	RunE:  runShadowList,
+}
+
+var shadowCompareCmd = &cobra.Command{
+	Use:   "compare <bead>",
+	Short: "Compare the two runs' diffs and test results",
+	Long: `Compare what the two sides of a shadow run produced: bead status,
+commits, changed files and lines against the rig's default branch, and
+which files both or only one of them touched.
+
+--tests runs the rig's merge_queue.test_command on each branch in a
+temporary worktree.
+
+Examples:
+  gt shadow compare gt-abc
+  gt shadow compare gt-abc --tests --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShadowCompare,
+}
+
+var shadowPickCmd = &cobra.Command{
+	Use:   "pick <bead> <a|b>",
+	Short: "Land one side of a shadow run",
+	Long: `Pick the winning side of a shadow run: its branch is pushed and
+submitted to the merge queue for the original bead, and the shadow bead is
+closed. The losing branch is left in place.
+
+Side a is the original bead's run, side b the shadow bead's.
+
+Examples:
+  gt shadow pick gt-abc a
+  gt shadow pick gt-abc b --dry-run`,
+	Args: cobra.ExactArgs(2),
+	RunE: runShadowPick,
+}
+
+var (
+	// shadowGitFn is a seam for tests. Production runs git in dir.
+	shadowGitFn = func(dir string, args ...string) (string, error) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("git %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return strings.TrimSpace(stdout.String()), nil
+	}
+
+	// shadowShowFn is a seam for tests. Production runs bd show.
+	shadowShowFn = func(id string) (*beads.Issue, error) {
+		return beads.New(resolveBeadDir(id)).Show(id)
+	}
+
+	// shadowRunTestsFn is a seam for tests. Production uses runShadowTests.
+	shadowRunTestsFn = runShadowTests
+
+	// shadowSubmitFn is a seam for tests. Production runs gt mq submit in the
+	// rig.
+	shadowSubmitFn = func(rigPath, branch, bead string) error {
+		cmd := exec.Command("gt", "mq", "submit", "--branch", branch, "--issue", bead)
+		cmd.Dir = askWorkDir(rigPath)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%v (%s)", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	// shadowCloseFn is a seam for tests. Production runs bd close.
+	shadowCloseFn = func(id, reason string) error {
+		return beads.New(resolveBeadDir(id)).CloseWithReason(reason, id)
+	}
+)
+
+func init() {
+	shadowCompareCmd.Flags().BoolVar(&shadowCompareTests, "tests", false, "Run the rig's test command on each branch")
+	shadowCompareCmd.Flags().BoolVar(&shadowCompareJSON, "json", false, "Output as JSON")
+	shadowPickCmd.Flags().BoolVarP(&shadowPickDryRun, "dry-run", "n", false, "Show what would be done")
+
+	shadowCmd.AddCommand(shadowListCmd)
+	shadowCmd.AddCommand(shadowCompareCmd)
+	shadowCmd.AddCommand(shadowPickCmd)
+	rootCmd.AddCommand(shadowCmd)
+}
+
+func runShadowList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := shadow.LoadState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading shadow state: %w", err)
+	}
+	runs := state.List()
+	if len(runs) == 0 {
+		fmt.Println("No shadow runs.")
+		return nil
+	}
+	for _, r := range runs {
+		outcome := style.Dim.Render("running")
+		if r.Winner != "" {
+			outcome = "picked " + r.Winner
+		}
+		fmt.Printf("%-14s %-12s a: %-10s b: %-10s %-14s %s  %s\n", r.Bead, r.Rig, r.A.AgentName(), r.B.AgentName(),
+			r.B.Bead, outcome, style.Dim.Render(r.At.Local().Format("2006-01-02")))
+	}
+	return nil
+}
+
+// loadShadowRun loads the state and the run for a bead.
+func loadShadowRun(townRoot, id string) (*shadow.State, *shadow.Run, error) {
+	state, err := shadow.LoadState(townRoot)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading shadow state: %w", err)
+	}
+	run := state.Find(id)
+	if run == nil {
+		return nil, nil, fmt.Errorf("%s has no shadow run (see gt shadow list)", id)
+	}
+	return state, run, nil
+}
+
+func runShadowCompare(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, run, err := loadShadowRun(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	c := compareShadowRun(townRoot, run, shadowCompareTests)
+	// Keep the branches found so later compares and the pick don't depend
+	// on the polecats still holding them.
+	if err := state.Save(townRoot); err != nil {
+		style.PrintWarning("saving shadow state: %v", err)
+	}
+	if shadowCompareJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(c)
+	}
+	printShadowComparison(c, run.Rig)
+	return nil
+}
+
+// compareShadowRun reads both sides' work and compares it.
+func compareShadowRun(townRoot string, run *shadow.Run, tests bool) shadow.Comparison {
+	rigPath := filepath.Join(townRoot, run.Rig)
+	repo := shadowRepoDir(rigPath)
+	base := shadowBase(repo, rigPath)
+	testCmd := ""
+	if tests {
+		testCmd = getTestCommand(rigPath)
+		if testCmd == "" {
+			style.PrintWarning("%s has no merge_queue.test_command; skipping tests", run.Rig)
+		}
+	}
+	a := shadowSideResult(townRoot, repo, base, testCmd, shadow.SideA, &run.A)
+	b := shadowSideResult(townRoot, repo, base, testCmd, shadow.SideB, &run.B)
+	return shadow.Compare(run.Bead, base, a, b)
+}
+
+// shadowRepoDir returns the rig's shared repo: the bare .repo.git that the
+// polecat worktrees hang off, or mayor/rig in older rigs.
+func shadowRepoDir(rigPath string) string {
+	if info, err := os.Stat(filepath.Join(rigPath, ".repo.git")); err == nil && info.IsDir() {
+		return filepath.Join(rigPath, ".repo.git")
+	}
+	return filepath.Join(rigPath, "mayor", "rig")
+}
+
+// shadowBase returns the ref the sides are compared against: the rig's
+// default branch, from origin when the repo has it.
+func shadowBase(repo, rigPath string) string {
+	branch := "main"
+	if cfg, err := rig.LoadRigConfig(rigPath); err == nil && cfg.DefaultBranch != "" {
+		branch = cfg.DefaultBranch
+	}
+	if _, err := shadowGitFn(repo, "rev-parse", "--verify", "--quiet", "origin/"+branch); err == nil {
+		return "origin/" + branch
+	}
+	return branch
+}
+
+// shadowSideResult reads one side's bead, branch, diff and (with testCmd)
+// test results. The branch is recorded on the side once found.
+func shadowSideResult(townRoot, repo, base, testCmd, label string, side *shadow.Side) shadow.Result {
+	res := shadow.Result{Label: label, Bead: side.Bead, Agent: side.AgentName()}
+	assignee := ""
+	if issue, err := shadowShowFn(side.Bead); err == nil {
+		res.Status, assignee = issue.Status, issue.Assignee
+	}
+	if side.Branch == "" {
+		side.Branch = findShadowBranch(townRoot, repo, side.Bead, assignee)
+	}
+	res.Branch = side.Branch
+	if res.Branch == "" {
+		res.Error = "no branch yet"
+		return res
+	}
+
+	count, err := shadowGitFn(repo, "rev-list", "--count", base+".."+res.Branch)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Commits, _ = strconv.Atoi(count)
+	numstat, err := shadowGitFn(repo, "diff", "--numstat", base+"..."+res.Branch)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Files, res.Insertions, res.Deletions = shadow.ParseNumstat(numstat)
+	if testCmd != "" {
+		res.Tests = shadowRunTestsFn(repo, res.Branch, testCmd)
+	}
+	return res
+}
+
+// findShadowBranch finds the branch a bead's work is on: the branch checked
+// out in its polecat's worktree, else the newest polecat branch named after
+// the bead (the default polecat branch template).
+func findShadowBranch(townRoot, repo, bead, assignee string) string {
+	if parts := strings.Split(assignee, "/"); len(parts) == 3 && parts[1] == "polecats" {
+		for _, dir := range []string{
+			filepath.Join(townRoot, parts[0], "polecats", parts[2], parts[0]),
+			filepath.Join(townRoot, parts[0], "polecats", parts[2]),
+		} {
+			if branch, err := shadowGitFn(dir, "rev-parse", "--abbrev-ref", "HEAD"); err == nil && strings.HasPrefix(branch, "polecat/") {
+				return branch
+			}
+		}
+	}
+	refs, err := shadowGitFn(repo, "for-each-ref", "--sort=-committerdate", "--format=%(refname:short)",
+		"refs/heads/polecat/", "refs/remotes/origin/polecat/")
+	if err != nil {
+		return ""
+	}
+	for _, ref := range strings.Split(refs, "\n") {
+		if strings.Contains(ref, "/"+bead+"@") || strings.HasSuffix(ref, "/"+bead) {
+			return ref
+		}
+	}
+	return ""
+}
+
+// runShadowTests runs the test command on a branch in a throwaway detached
+// worktree, so neither polecat's worktree is touched.
+func runShadowTests(repo, branch, testCmd string) *shadow.TestResult {
+	res := &shadow.TestResult{Command: testCmd}
+	dir, err := os.MkdirTemp("", "gt-shadow-*")
+	if err != nil {
+		res.Output = err.Error()
+		return res
+	}
+	defer os.RemoveAll(dir)
+	wt := filepath.Join(dir, "wt")
+	if _, err := shadowGitFn(repo, "worktree", "add", "--detach", wt, branch); err != nil {
+		res.Output = err.Error()
+		return res
+	}
+	defer func() { _, _ = shadowGitFn(repo, "worktree", "remove", "--force", wt) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), shadowTestTimeout)
+	defer cancel()
+	c := exec.CommandContext(ctx, "sh", "-c", testCmd) //nolint:gosec // G204: TestCommand is from trusted rig config
+	c.Dir = wt
+	start := time.Now()
+	out, err := c.CombinedOutput()
+	res.Duration = time.Since(start).Round(time.Second)
+	res.Passed = err == nil
+	res.Output = shadow.Tail(string(out), shadowTestTailLines)
+	return res
+}
+
+// printShadowComparison prints the two sides in columns, then the file
+// overlap and any failing test output.
+func printShadowComparison(c shadow.Comparison, rigName string) {
+	fmt.Printf("%s %s %s\n\n", style.Bold.Render("Shadow run"), c.Bead, style.Dim.Render("("+rigName+", against "+c.Base+")"))
+	row := func(label, a, b string) {
+		fmt.Printf("  %-9s %-36s %s\n", label, a, b)
+	}
+	row("", style.Bold.Render(fmt.Sprintf("%-36s", "a ("+c.A.Agent+")")), style.Bold.Render("b ("+c.B.Agent+")"))
+	row("bead", c.A.Bead+" "+orDash(c.A.Status), c.B.Bead+" "+orDash(c.B.Status))
+	row("branch", orDash(c.A.Branch), orDash(c.B.Branch))
+	if c.A.Error != "" || c.B.Error != "" {
+		row("error", orDash(c.A.Error), orDash(c.B.Error))
+	}
+	row("commits", strconv.Itoa(c.A.Commits), strconv.Itoa(c.B.Commits))
+	row("changes", shadowChanges(c.A), shadowChanges(c.B))
+	if c.A.Tests != nil || c.B.Tests != nil {
+		row("tests", shadowTests(c.A.Tests), shadowTests(c.B.Tests))
+	}
+
+	for _, group := range []struct {
+		title string
+		files []string
+	}{{"Changed by both", c.Common}, {"Only a", c.OnlyA}, {"Only b", c.OnlyB}} {
+		if len(group.files) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s (%d)\n", group.title, len(group.files))
+		for _, f := range group.files {
+			fmt.Printf("  %s\n", f)
+		}
+	}
+	for _, r := range []shadow.Result{c.A, c.B} {
+		if r.Tests != nil && !r.Tests.Passed && r.Tests.Output != "" {
+			fmt.Printf("\n%s\n%s\n", style.Warning.Render("Tests failed on "+r.Label+":"), style.Dim.Render(r.Tests.Output))
+		}
+	}
+	fmt.Printf("\nLand one with: gt shadow pick %s a|b\n", c.Bead)
+}
+
+func shadowChanges(r shadow.Result) string {
+	return fmt.Sprintf("%d files, +%d −%d", len(r.Files), r.Insertions, r.Deletions)
+}
+
+func shadowTests(t *shadow.TestResult) string {
+	switch {
+	case t == nil:
+		return "-"
+	case t.Passed:
+		return fmt.Sprintf("✓ passed (%s)", t.Duration)
+	default:
+		return fmt.Sprintf("✗ failed (%s)", t.Duration)
+	}
+}
+
+func runShadowPick(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, run, err := loadShadowRun(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	if err := pickShadowRun(townRoot, run, args[1], shadowPickDryRun); err != nil {
+		return err
+	}
+	if shadowPickDryRun {
+		return nil
+	}
+	return state.Save(townRoot)
+}
+
+// pickShadowRun lands the winning side: its branch goes to the merge queue
+// for the original bead and the shadow bead is closed.
+func pickShadowRun(townRoot string, run *shadow.Run, label string, dryRun bool) error {
+	if run.Winner != "" {
+		return fmt.Errorf("%s was already settled: side %s was picked", run.Bead, run.Winner)
+	}
+	winner := run.Side(label)
+	if winner == nil {
+		return fmt.Errorf("pick side a (%s) or b (%s)", run.A.Bead, run.B.Bead)
+	}
+	label = strings.ToLower(label)
+	loser, loserLabel := &run.B, shadow.SideB
+	if label == shadow.SideB {
+		loser, loserLabel = &run.A, shadow.SideA
+	}
+
+	rigPath := filepath.Join(townRoot, run.Rig)
+	repo := shadowRepoDir(rigPath)
+	if winner.Branch == "" {
+		assignee := ""
+		if issue, err := shadowShowFn(winner.Bead); err == nil {
+			assignee = issue.Assignee
+		}
+		winner.Branch = findShadowBranch(townRoot, repo, winner.Bead, assignee)
+	}
+	if winner.Branch == "" {
+		return fmt.Errorf("no branch found for side %s (%s); is its polecat done?", label, winner.Bead)
+	}
+	branch := strings.TrimPrefix(winner.Branch, "origin/")
+
+	reason := fmt.Sprintf("Shadow run settled: side %s (%s) was picked over side %s (%s)",
+		label, winner.AgentName(), loserLabel, loser.AgentName())
+	if dryRun {
+		fmt.Printf("Would submit %s to the merge queue for %s and close %s\n", branch, run.Bead, run.B.Bead)
+		return nil
+	}
+
+	if !strings.HasPrefix(winner.Branch, "origin/") {
+		if _, err := shadowGitFn(repo, "push", rig.LoadRemotes(rigPath).BranchRemote(), branch); err != nil {
+			return fmt.Errorf("pushing %s: %w", branch, err)
+		}
+	}
+	if err := shadowSubmitFn(rigPath, branch, run.Bead); err != nil {
+		return fmt.Errorf("submitting %s: %w", branch, err)
+	}
+	fmt.Printf("%s Submitted %s for %s\n", style.SuccessPrefix, style.Bold.Render(branch), run.Bead)
+	run.Winner = label
+
+	if err := shadowCloseFn(run.B.Bead, reason); err != nil {
+		style.PrintWarning("closing shadow bead %s: %v", run.B.Bead, err)
+	}
+	if loser.Branch != "" {
+		fmt.Printf("%s\n", style.Dim.Render("Losing branch "+loser.Branch+" left in place."))
+	}
+	return nil
+}
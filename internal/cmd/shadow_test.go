@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/shadow"
+)
+
+func TestShadowDescription(t *testing.T) {
+	desc := shadowDescription("gt-abc", "Fix the login flow.")
+	if !strings.HasPrefix(desc, "Fix the login flow.\n\n---\n") || !strings.Contains(desc, "gt shadow compare gt-abc") {
+		t.Errorf("shadowDescription = %q", desc)
+	}
+}
+
+func TestCompareShadowRun(t *testing.T) {
+	origShow := shadowShowFn
+	t.Cleanup(func() { shadowShowFn = origShow })
+	shadowShowFn = func(id string) (*beads.Issue, error) {
+		return &beads.Issue{ID: id, Status: "closed"}, nil
+	}
+
+	town := t.TempDir()
+	repo := filepath.Join(town, "gastown", "mayor", "rig")
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	write("main.go", "package main\n")
+	git("add", ".")
+	git("commit", "-qm", "init")
+	git("checkout", "-qb", "polecat/Toast/gt-abc@m1")
+	write("main.go", "package main\n\nfunc main() {}\n")
+	git("commit", "-qam", "a")
+	git("checkout", "-q", "main")
+	git("checkout", "-qb", "polecat/Nux/gt-xyz@m2")
+	write("main.go", "package main\n\nfunc main() { run() }\n")
+	write("run.go", "package main\n\nfunc run() {}\n")
+	git("add", ".")
+	git("commit", "-qm", "b1")
+	git("commit", "-q", "--allow-empty", "-m", "b2")
+	git("checkout", "-q", "main")
+
+	run := &shadow.Run{Bead: "gt-abc", Rig: "gastown", A: shadow.Side{Bead: "gt-abc"}, B: shadow.Side{Bead: "gt-xyz", Agent: "gemini"}}
+	c := compareShadowRun(town, run, false)
+	if c.Base != "main" || c.A.Branch != "polecat/Toast/gt-abc@m1" || c.B.Branch != "polecat/Nux/gt-xyz@m2" {
+		t.Fatalf("comparison = %+v", c)
+	}
+	if c.A.Commits != 1 || c.B.Commits != 2 || c.A.Insertions != 2 || len(c.B.Files) != 2 {
+		t.Errorf("a = %+v, b = %+v", c.A, c.B)
+	}
+	if strings.Join(c.Common, ",") != "main.go" || strings.Join(c.OnlyB, ",") != "run.go" {
+		t.Errorf("common = %v, only b = %v", c.Common, c.OnlyB)
+	}
+	if run.B.Branch != "polecat/Nux/gt-xyz@m2" {
+		t.Error("the branch found should be recorded on the run")
+	}
+}
+
+func TestPickShadowRun(t *testing.T) {
+	origGit, origSubmit, origClose := shadowGitFn, shadowSubmitFn, shadowCloseFn
+	t.Cleanup(func() { shadowGitFn, shadowSubmitFn, shadowCloseFn = origGit, origSubmit, origClose })
+	var actions []string
+	shadowGitFn = func(dir string, args ...string) (string, error) {
+		actions = append(actions, "git "+strings.Join(args, " "))
+		return "", nil
+	}
+	shadowSubmitFn = func(rigPath, branch, bead string) error {
+		actions = append(actions, "submit "+branch+" "+bead)
+		return nil
+	}
+	shadowCloseFn = func(id, reason string) error {
+		actions = append(actions, "close "+id+": "+reason)
+		return nil
+	}
+
+	run := &shadow.Run{
+		Bead: "gt-abc", Rig: "gastown",
+		A: shadow.Side{Bead: "gt-abc", Branch: "polecat/Toast/gt-abc@m1"},
+		B: shadow.Side{Bead: "gt-xyz", Agent: "gemini", Branch: "polecat/Nux/gt-xyz@m2"},
+	}
+	if err := pickShadowRun("/town", run, "c", false); err == nil {
+		t.Error("picking an unknown side should fail")
+	}
+	if err := pickShadowRun("/town", run, "B", false); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"git push origin polecat/Nux/gt-xyz@m2",
+		"submit polecat/Nux/gt-xyz@m2 gt-abc",
+		"close gt-xyz: Shadow run settled: side b (gemini) was picked over side a (default)",
+	}
+	if strings.Join(actions, "\n") != strings.Join(want, "\n") {
+		t.Errorf("actions =\n%s", strings.Join(actions, "\n"))
+	}
+	if run.Winner != shadow.SideB {
+		t.Errorf("Winner = %q", run.Winner)
+	}
+	if err := pickShadowRun("/town", run, "a", false); err == nil {
+		t.Error("a settled run should not be picked again")
+	}
+}
+
+func TestPickShadowRun_SubmitFails(t *testing.T) {
+	origGit, origSubmit, origClose := shadowGitFn, shadowSubmitFn, shadowCloseFn
+	t.Cleanup(func() { shadowGitFn, shadowSubmitFn, shadowCloseFn = origGit, origSubmit, origClose })
+	shadowGitFn = func(dir string, args ...string) (string, error) { return "", nil }
+	shadowSubmitFn = func(rigPath, branch, bead string) error { return errors.New("no refinery") }
+	shadowCloseFn = func(id, reason string) error {
+		t.Errorf("closed %s after a failed submit", id)
+		return nil
+	}
+
+	run := &shadow.Run{Bead: "gt-abc", Rig: "gastown", A: shadow.Side{Bead: "gt-abc", Branch: "polecat/Toast/gt-abc@m1"}, B: shadow.Side{Bead: "gt-xyz"}}
+	if err := pickShadowRun("/town", run, "a", false); err == nil {
+		t.Fatal("a failed submit should fail the pick")
+	}
+	if run.Winner != "" {
+		t.Error("a failed pick should leave the run open")
+	}
+}
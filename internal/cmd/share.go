@@ -0,0 +1,406 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/changes"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/retro"
+	"github.com/steveyegge/gastown/internal/share"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/timetrack"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// maxShareDiffLines caps each diff in a share bundle; a bug report needs
+// the shape of the change, not every generated file.
+const maxShareDiffLines = 400
+
+// maxShareCommits caps the commits found by searching history for a bead
+// that has no change summary.
+const maxShareCommits = 10
+
+var (
+	shareFormat        string
+	shareOutput        string
+	shareRedact        []string
+	shareNoTranscripts bool
+	shareNoDiffs       bool
+	shareNoMail        bool
+)
+
+var shareCmd = &cobra.Command{
+	Use:     "share <bead-id>",
+	GroupID: GroupWork,
+	Short:   "Export a bead's history as a redacted, shareable bundle",
+	Long: `Export one bead's history as a single redacted markdown or HTML file, for
+bug reports and demos.
+
+The timeline gathers:
+  events       slings, hooks, merges and stalls from the town events log
+  transcripts  excerpts of each agent's session while it held the bead
+               (prompts, replies and tool calls; tool output is left out)
+  diffs        the merged change (from its change summary), or commits
+               that name the bead
+  mail         messages whose subject or body names the bead
+
+Everything is redacted before it is written: API keys and tokens, private
+keys, passwords in URLs and assignments, and email addresses are replaced,
+the town root becomes <town> and the home directory ~. Add patterns of
+your own with --redact. Redaction is best effort: read the bundle before
+posting it.
+
+The HTML bundle is one self-contained page with no scripts or external
+resources. The format follows the --output extension (.html, .md) unless
+--format is given.
+
+Examples:
+  gt share gt-abc                      # Markdown to stdout
+  gt share gt-abc -o gt-abc.html       # HTML page
+  gt share gt-abc --no-transcripts -o bug.md
+  gt share gt-abc --redact 'acme-[a-z]+' --redact 'internal\.example\.com'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShare,
+}
+
+var (
+	// shareShowBeadFn is a seam for tests. Production runs bd show.
+	shareShowBeadFn = func(id string) (*beads.Issue, error) {
+		return beads.New(resolveBeadDir(id)).Show(id)
+	}
+
+	// shareListMailFn is a seam for tests. Production lists message beads in the
+	// town database.
+	shareListMailFn = func(townBeads string) ([]*beads.Issue, error) {
+		return beads.New(townBeads).List(beads.ListOptions{Label: "gt:message", Status: "all", Priority: -1})
+	}
+
+	// shareDiffsFn is a seam for tests. Production uses gatherShareDiffs.
+	shareDiffsFn = gatherShareDiffs
+
+	// shareTranscriptsFn is a seam for tests. Production uses
+	// gatherShareTranscripts.
+	shareTranscriptsFn = gatherShareTranscripts
+)
+
+func init() {
+	shareCmd.Flags().StringVar(&shareFormat, "format", "", "Output format: markdown, html or json (default from --output, else markdown)")
+	shareCmd.Flags().StringVarP(&shareOutput, "output", "o", "", "Write to file instead of stdout")
+	shareCmd.Flags().StringArrayVar(&shareRedact, "redact", nil, "Extra regular expression to redact (repeatable)")
+	shareCmd.Flags().BoolVar(&shareNoTranscripts, "no-transcripts", false, "Leave out transcript excerpts")
+	shareCmd.Flags().BoolVar(&shareNoDiffs, "no-diffs", false, "Leave out diffs")
+	shareCmd.Flags().BoolVar(&shareNoMail, "no-mail", false, "Leave out mail")
+
+	rootCmd.AddCommand(shareCmd)
+}
+
+func runShare(cmd *cobra.Command, args []string) error {
+	format, err := shareOutputFormat(shareFormat, shareOutput)
+	if err != nil {
+		return err
+	}
+	redactor, err := share.NewRedactor(shareRedact)
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	bundle, err := gatherShareBundle(townRoot, args[0], time.Now())
+	if err != nil {
+		return err
+	}
+	redactor.AliasPath(townRoot, "<town>")
+	if home, err := os.UserHomeDir(); err == nil {
+		redactor.AliasPath(home, "~")
+	}
+	bundle.Redact(redactor)
+
+	var out string
+	switch format {
+	case "html":
+		if out, err = bundle.HTML(); err != nil {
+			return err
+		}
+	case "json":
+		data, err := json.MarshalIndent(bundle, "", "  ")
+		if err != nil {
+			return err
+		}
+		out = string(data) + "\n"
+	default:
+		out = bundle.Markdown()
+	}
+
+	if shareOutput == "" {
+		fmt.Print(out)
+		return nil
+	}
+	if err := os.WriteFile(shareOutput, []byte(out), 0600); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%s Wrote %s (%d timeline entries, %d redactions) — review it before sharing\n",
+		style.SuccessPrefix, shareOutput, len(bundle.Entries), bundle.Redactions)
+	return nil
+}
+
+// shareOutputFormat picks the format: --format, else the output file's
+// extension, else markdown.
+func shareOutputFormat(format, output string) (string, error) {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(output)) {
+		case ".html", ".htm":
+			return "html", nil
+		case ".json":
+			return "json", nil
+		}
+		return "markdown", nil
+	}
+	switch format {
+	case "markdown", "md":
+		return "markdown", nil
+	case "html", "json":
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown format %q (use markdown, html or json)", format)
+}
+
+// gatherShareBundle collects the bead and everything on its timeline.
+func gatherShareBundle(townRoot, beadID string, now time.Time) (*share.Bundle, error) {
+	issue, err := shareShowBeadFn(beadID)
+	if err != nil {
+		return nil, fmt.Errorf("bead %s: %w", beadID, err)
+	}
+	b := &share.Bundle{
+		Bead:        issue.ID,
+		Title:       issue.Title,
+		Status:      issue.Status,
+		Assignee:    issue.Assignee,
+		Description: issue.Description,
+		CreatedAt:   parseBeadTime(issue.CreatedAt),
+		ClosedAt:    parseBeadTime(issue.ClosedAt),
+		GeneratedAt: now,
+	}
+
+	evs, err := retro.LoadEvents(townRoot, b.CreatedAt)
+	if err != nil {
+		style.PrintWarning("events log unreadable, timeline has no events: %v", err)
+	}
+	var mine []events.Event
+	for _, e := range evs {
+		if share.EventConcerns(e, b.Bead) {
+			b.Add(share.EventEntry(e))
+			mine = append(mine, e)
+		}
+	}
+
+	if !shareNoTranscripts {
+		for _, e := range shareTranscriptsFn(townRoot, b.Bead, mine) {
+			b.Add(e)
+		}
+	}
+	if !shareNoDiffs {
+		for _, e := range shareDiffsFn(townRoot, b.Bead) {
+			b.Add(e)
+		}
+	}
+	if !shareNoMail {
+		entries, err := gatherShareMail(townRoot, b.Bead)
+		if err != nil {
+			style.PrintWarning("could not list mail: %v", err)
+		}
+		for _, e := range entries {
+			b.Add(e)
+		}
+	}
+	b.Sort()
+	return b, nil
+}
+
+// gatherShareTranscripts excerpts each session of each agent that held the
+// bead, limited to the time it held it.
+func gatherShareTranscripts(townRoot, beadID string, evs []events.Event) []share.Entry {
+	var entries []share.Entry
+	for _, span := range timetrack.Assignments(evs) {
+		if span.Bead != beadID {
+			continue
+		}
+		workDirs := []string{filepath.Join(townRoot, span.Agent)}
+		if timetrack.RigOf(span.Agent) != "" {
+			rigName, name, err := parseAddress(span.Agent)
+			if err != nil {
+				continue
+			}
+			workDirs = agentWorkDirs(townRoot, agentWorkAddress(rigName, name))
+		}
+		transcripts, err := agentlog.ClaudeCodeTranscripts(workDirs...)
+		if err != nil {
+			continue
+		}
+		for _, t := range transcripts {
+			if t.ModTime.Before(span.From) {
+				continue
+			}
+			sessionEvents, err := agentlog.ReadClaudeCodeTranscript(t.Path, span.Agent)
+			if err != nil {
+				style.PrintWarning("transcript %s: %v", t.NativeSessionID, err)
+				continue
+			}
+			body, at, ok := share.Excerpt(sessionEvents, span.From, span.To)
+			if !ok {
+				continue
+			}
+			entries = append(entries, share.Entry{
+				At:    at,
+				Kind:  share.KindTranscript,
+				Actor: span.Agent,
+				Title: "Session " + t.NativeSessionID,
+				Body:  body,
+			})
+		}
+	}
+	return entries
+}
+
+// gatherShareDiffs returns the bead's merged change, from the change
+// summary the refinery recorded, or else the commits that name the bead.
+func gatherShareDiffs(townRoot, beadID string) []share.Entry {
+	rigs, err := getAllRigs()
+	if err != nil {
+		return nil
+	}
+	for _, r := range rigs {
+		s, err := changes.Load(r.Path, beadID)
+		if err != nil || s == nil || s.MergeCommit == "" {
+			continue
+		}
+		repo := shareRigRepo(r.Path)
+		if repo == "" {
+			continue
+		}
+		diff, err := shareGit(repo, "diff", "--stat", "--patch", s.Base, s.MergeCommit)
+		if err != nil {
+			style.PrintWarning("diff of %s: %v", beadID, err)
+			continue
+		}
+		return []share.Entry{{
+			At:    s.MergedAt,
+			Kind:  share.KindDiff,
+			Actor: r.Name + "/refinery",
+			Title: fmt.Sprintf("Merged %s → %s (%s): %s", s.Branch, s.Target, shortCommit(s.MergeCommit), s.Headline()),
+			Body:  capShareLines(diff, maxShareDiffLines),
+		}}
+	}
+
+	var entries []share.Entry
+	for _, r := range rigs {
+		repo := shareRigRepo(r.Path)
+		if repo == "" {
+			continue
+		}
+		log, err := shareGit(repo, "log", "--all", "-F", "--grep", beadID, "-n", fmt.Sprint(maxShareCommits), "--format=%H%x00%an%x00%aI%x00%s")
+		if err != nil || log == "" {
+			continue
+		}
+		for _, line := range strings.Split(log, "\n") {
+			fields := strings.SplitN(line, "\x00", 4)
+			if len(fields) != 4 {
+				continue
+			}
+			patch, err := shareGit(repo, "show", "--stat", "--patch", "--format=", fields[0])
+			if err != nil {
+				continue
+			}
+			at, _ := time.Parse(time.RFC3339, fields[2])
+			entries = append(entries, share.Entry{
+				At:    at,
+				Kind:  share.KindDiff,
+				Actor: fields[1],
+				Title: fmt.Sprintf("Commit %s in %s: %s", shortCommit(fields[0]), r.Name, fields[3]),
+				Body:  capShareLines(patch, maxShareDiffLines),
+			})
+		}
+	}
+	return entries
+}
+
+// shareRigRepo returns a git checkout of the rig to read history from.
+func shareRigRepo(rigPath string) string {
+	for _, dir := range []string{filepath.Join(rigPath, "refinery", "rig"), filepath.Join(rigPath, "mayor", "rig")} {
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+	}
+	return ""
+}
+
+func shareGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+func shortCommit(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
+
+// capShareLines keeps the first n lines of s.
+func capShareLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) <= n {
+		return s
+	}
+	return strings.Join(lines[:n], "\n") + fmt.Sprintf("\n… %d more lines", len(lines)-n)
+}
+
+// gatherShareMail returns the mail whose subject or body names the bead.
+// Bodies sealed at rest are opened when the key is available.
+func gatherShareMail(townRoot, beadID string) ([]share.Entry, error) {
+	issues, err := shareListMailFn(filepath.Join(townRoot, ".beads"))
+	if err != nil {
+		return nil, err
+	}
+	var entries []share.Entry
+	for _, issue := range issues {
+		bm := mail.BeadsMessage{
+			ID:          issue.ID,
+			Title:       issue.Title,
+			Description: issue.Description,
+			Assignee:    issue.Assignee,
+			Status:      issue.Status,
+			CreatedAt:   parseBeadTime(issue.CreatedAt),
+			Labels:      issue.Labels,
+		}
+		msg := bm.ToMessage()
+		if !strings.Contains(msg.Subject, beadID) && !strings.Contains(msg.Body, beadID) {
+			continue
+		}
+		entries = append(entries, share.Entry{
+			At:    msg.Timestamp,
+			Kind:  share.KindMail,
+			Actor: msg.From,
+			Title: fmt.Sprintf("%s (to %s)", msg.Subject, msg.To),
+			Body:  msg.Body,
+		})
+	}
+	return entries, nil
+}
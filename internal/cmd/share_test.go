@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/share"
+)
+
+func stubShare(t *testing.T) {
+	t.Helper()
+	origShow, origMail, origDiffs, origTranscripts := shareShowBeadFn, shareListMailFn, shareDiffsFn, shareTranscriptsFn
+	t.Cleanup(func() {
+		shareShowBeadFn, shareListMailFn, shareDiffsFn, shareTranscriptsFn = origShow, origMail, origDiffs, origTranscripts
+	})
+
+	shareShowBeadFn = func(id string) (*beads.Issue, error) {
+		return &beads.Issue{ID: id, Title: "Flaky login test", Status: "closed", Assignee: "gastown/polecats/Toast",
+			CreatedAt: "2026-03-01T09:00:00Z", ClosedAt: "2026-03-01T12:00:00Z"}, nil
+	}
+	shareListMailFn = func(townBeads string) ([]*beads.Issue, error) {
+		return []*beads.Issue{
+			{ID: "hq-m1", Title: "Done with gt-a", Description: "Branch pushed", Assignee: "mayor/",
+				CreatedAt: "2026-03-01T11:00:00Z", Labels: []string{"from:gastown/Toast"}},
+			{ID: "hq-m2", Title: "Lunch", Description: "Unrelated", Assignee: "mayor/", CreatedAt: "2026-03-01T11:30:00Z"},
+		}, nil
+	}
+	shareDiffsFn = func(townRoot, beadID string) []share.Entry {
+		return []share.Entry{{At: time.Date(2026, 3, 1, 11, 45, 0, 0, time.UTC), Kind: share.KindDiff, Title: "Merged"}}
+	}
+	shareTranscriptsFn = func(townRoot, beadID string, evs []events.Event) []share.Entry {
+		if len(evs) == 0 {
+			t.Error("transcripts should get the bead's events to find who held it")
+		}
+		return nil
+	}
+}
+
+func TestGatherShareBundle(t *testing.T) {
+	stubShare(t)
+	townRoot := t.TempDir()
+	log := `{"ts":"2026-03-01T09:30:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-a","target":"gastown/Toast"}}
+{"ts":"2026-03-01T09:31:00Z","type":"sling","actor":"mayor","payload":{"bead":"gt-b","target":"gastown/Nux"}}
+{"ts":"2026-03-01T11:50:00Z","type":"merged","actor":"gastown/refinery","payload":{"branch":"polecat/Toast/gt-a","mr":"gt-mr1"}}
+`
+	if err := os.WriteFile(filepath.Join(townRoot, events.EventsFile), []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := gatherShareBundle(townRoot, "gt-a", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Title != "Flaky login test" || b.CreatedAt.IsZero() {
+		t.Errorf("bundle = %+v", b)
+	}
+	kinds := ""
+	for _, e := range b.Entries {
+		kinds += e.Kind + " "
+	}
+	if kinds != "event mail diff event " {
+		t.Errorf("timeline kinds = %q, want sling, mail, diff, merge in time order", kinds)
+	}
+	if b.Entries[1].Actor != "gastown/Toast" {
+		t.Errorf("mail actor = %q", b.Entries[1].Actor)
+	}
+}
+
+func TestShareOutputFormat(t *testing.T) {
+	tests := []struct {
+		format, output, want string
+		wantErr              bool
+	}{
+		{"", "", "markdown", false},
+		{"", "bug.HTML", "html", false},
+		{"", "bug.json", "json", false},
+		{"md", "bug.html", "markdown", false},
+		{"pdf", "", "", true},
+	}
+	for _, tt := range tests {
+		got, err := shareOutputFormat(tt.format, tt.output)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("shareOutputFormat(%q, %q) = %q, %v", tt.format, tt.output, got, err)
+		}
+	}
+}
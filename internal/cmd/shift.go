@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/rbac"
+	"github.com/steveyegge/gastown/internal/shift"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	shiftAs       string
+	shiftTakeover bool
+	shiftNotes    string
+	shiftIssues   []string
+	shiftJSON     bool
+)
+
+var shiftCmd = &cobra.Command{
+	Use:     "shift",
+	GroupID: GroupWorkspace,
+	Short:   "Hand a town over between human operators",
+	Long: `Track which human operator is on shift and pass handoff notes between them.
+
+When several people share one town, agents only see "overseer". Starting a
+shift records who is at the controls: events, nudges, and escalation acks
+issued outside agent sessions are attributed to that operator.
+
+Ending a shift writes a handoff summary (active convoys, pending approvals,
+known issues, notes). The next operator sees it when they start their shift.
+
+The operator name comes from --as, then $GT_OPERATOR, then $USER.
+
+Examples:
+  gt shift start                     # Go on shift and read the last handoff
+  gt shift start --as dana --takeover
+  gt shift end -m "Auth convoy waiting on review" --issue "refinery flaky on gastown"
+  gt shift status`,
+	RunE: requireSubcommand,
+}
+
+var shiftStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Go on shift and show the previous operator's handoff",
+	Args:  cobra.NoArgs,
+	RunE:  runShiftStart,
+}
+
+var shiftEndCmd = &cobra.Command{
+	Use:   "end",
+	Short: "End your shift and leave a handoff summary",
+	Args:  cobra.NoArgs,
+	RunE:  runShiftEnd,
+}
+
+var shiftStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show who is on shift and the last handoff",
+	Args:  cobra.NoArgs,
+	RunE:  runShiftStatus,
+}
+
+func init() {
+	shiftStartCmd.Flags().StringVar(&shiftAs, "as", "", "Operator name (default: $GT_OPERATOR or $USER)")
+	shiftStartCmd.Flags().BoolVar(&shiftTakeover, "takeover", false, "Take over from an operator who did not end their shift")
+	shiftEndCmd.Flags().StringVarP(&shiftNotes, "message", "m", "", "Free-form handoff notes")
+	shiftEndCmd.Flags().StringArrayVar(&shiftIssues, "issue", nil, "Known issue for the next operator (repeatable)")
+	shiftEndCmd.Flags().BoolVar(&shiftJSON, "json", false, "Output the handoff as JSON")
+	shiftStatusCmd.Flags().BoolVar(&shiftJSON, "json", false, "Output as JSON")
+
+	shiftCmd.AddCommand(shiftStartCmd, shiftEndCmd, shiftStatusCmd)
+	rootCmd.AddCommand(shiftCmd)
+}
+
+func runShiftStart(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	operator := resolveShiftOperator(shiftAs)
+	if operator == "" {
+		return fmt.Errorf("cannot determine operator name: use --as <name>")
+	}
+
+	prev, _ := shift.Current(townRoot)
+	st, err := shift.Start(townRoot, operator, shiftTakeover, time.Now())
+	if errors.Is(err, shift.ErrOnShift) {
+		return fmt.Errorf("%w\nUse --takeover if they have left without ending their shift", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{"operator": operator}
+	if prev != nil && prev.Operator != operator {
+		payload["took_over_from"] = prev.Operator
+	}
+	_ = events.LogFeed(events.TypeShiftStart, "overseer", payload)
+
+	fmt.Printf("%s %s is on shift (since %s)\n", style.Success.Render("✓"), st.Operator, st.StartedAt.Local().Format("Jan 2 15:04"))
+	if prev != nil && prev.Operator != operator {
+		fmt.Printf("  %s\n", style.Warning.Render("Took over from "+prev.Operator+" (shift was not ended)"))
+	}
+
+	h, err := shift.LatestHandoff(townRoot)
+	if err != nil {
+		style.PrintWarning("could not read last handoff: %v", err)
+		return nil
+	}
+	if h == nil {
+		fmt.Println(style.Dim.Render("  No previous handoff recorded."))
+		return nil
+	}
+	fmt.Println()
+	printShiftHandoff(h)
+	return nil
+}
+
+func runShiftEnd(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cur, err := shift.Current(townRoot)
+	if err != nil {
+		return err
+	}
+	if cur == nil {
+		return fmt.Errorf("nobody is on shift (start one with: gt shift start)")
+	}
+
+	h := &shift.Handoff{
+		Operator:         cur.Operator,
+		StartedAt:        cur.StartedAt,
+		ActiveConvoys:    collectShiftConvoys(townRoot),
+		PendingApprovals: collectShiftApprovals(townRoot),
+		KnownIssues:      shiftIssues,
+		Notes:            strings.TrimSpace(shiftNotes),
+	}
+	if err := shift.End(townRoot, h, time.Now()); err != nil {
+		return err
+	}
+	_ = events.LogFeed(events.TypeShiftEnd, "overseer", map[string]interface{}{
+		"operator":          h.Operator,
+		"active_convoys":    len(h.ActiveConvoys),
+		"pending_approvals": len(h.PendingApprovals),
+		"known_issues":      len(h.KnownIssues),
+	})
+
+	if shiftJSON {
+		return printShiftJSON(h)
+	}
+	fmt.Printf("%s Shift ended for %s\n\n", style.Success.Render("✓"), h.Operator)
+	printShiftHandoff(h)
+	return nil
+}
+
+func runShiftStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cur, err := shift.Current(townRoot)
+	if err != nil {
+		return err
+	}
+	last, err := shift.LatestHandoff(townRoot)
+	if err != nil {
+		return err
+	}
+
+	if shiftJSON {
+		return printShiftJSON(struct {
+			Current     *shift.State   `json:"current"`
+			LastHandoff *shift.Handoff `json:"last_handoff,omitempty"`
+		}{cur, last})
+	}
+
+	if cur == nil {
+		fmt.Println("Nobody is on shift.")
+	} else {
+		fmt.Printf("%s on shift since %s (%s)\n", style.Bold.Render(cur.Operator),
+			cur.StartedAt.Local().Format("Jan 2 15:04"), formatWorkerAge(time.Since(cur.StartedAt)))
+	}
+	if last != nil {
+		fmt.Println()
+		printShiftHandoff(last)
+	}
+	return nil
+}
+
+// resolveShiftOperator picks the operator name from flag, $GT_OPERATOR, or $USER.
+func resolveShiftOperator(flag string) string {
+	if name := strings.TrimSpace(flag); name != "" {
+		return name
+	}
+	return rbac.OperatorFromEnv()
+}
+
+// attributeOperator returns identity annotated with the human who issued the
+// command (no GT_ROLE) while the town has someone on shift: "mayor" becomes
+// "mayor (bob)". The human is $GT_OPERATOR or $USER, falling back to the
+// operator on shift. Agent identities and towns without an active shift are
+// returned unchanged.
+func attributeOperator(identity string) string {
+	if os.Getenv("GT_ROLE") != "" {
+		return identity
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return identity
+	}
+	onShift := shift.CurrentOperator(townRoot)
+	if onShift == "" {
+		return identity
+	}
+	op := resolveShiftOperator("")
+	if op == "" {
+		op = onShift
+	}
+	if identity == "" {
+		identity = "overseer"
+	}
+	return fmt.Sprintf("%s (%s)", identity, op)
+}
+
+// collectShiftConvoys lists open convoys for the handoff (best-effort).
+func collectShiftConvoys(townRoot string) []shift.Item {
+	out, err := runBdJSON(townRoot, "list", "--type=convoy", "--status=open", "--json", "--flat")
+	if err != nil {
+		return nil
+	}
+	var convoys []struct {
+		ID     string `json:"id"`
+		Title  string `json:"title"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(out, &convoys); err != nil {
+		return nil
+	}
+	items := make([]shift.Item, 0, len(convoys))
+	for _, c := range convoys {
+		item := shift.Item{ID: c.ID, Title: c.Title, Status: c.Status}
+		if tracked, err := getTrackedIssues(townRoot, c.ID); err == nil && len(tracked) > 0 {
+			done := 0
+			for _, t := range tracked {
+				if t.Status == "closed" {
+					done++
+				}
+			}
+			item.Detail = fmt.Sprintf("%d/%d done", done, len(tracked))
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// collectShiftApprovals gathers items waiting on a human: unacknowledged
+// escalations and unread overseer mail (best-effort).
+func collectShiftApprovals(townRoot string) []shift.Item {
+	var items []shift.Item
+	if escalations, err := beads.New(beads.ResolveBeadsDir(townRoot)).ListEscalations(); err == nil {
+		for _, issue := range escalations {
+			if beads.HasLabel(issue, "acked") {
+				continue
+			}
+			fields := beads.ParseEscalationFields(issue.Description)
+			items = append(items, shift.Item{
+				ID:     issue.ID,
+				Title:  issue.Title,
+				Status: "escalation",
+				Detail: strings.TrimSpace(fields.Severity + " from " + fields.EscalatedBy),
+			})
+		}
+	}
+	if mailbox, err := mail.NewRouter(townRoot).GetMailbox("overseer"); err == nil {
+		if messages, err := mailbox.ListUnread(); err == nil {
+			for _, m := range messages {
+				items = append(items, shift.Item{ID: m.ID, Title: m.Subject, Status: "mail", Detail: "from " + m.From})
+			}
+		}
+	}
+	return items
+}
+
+func printShiftHandoff(h *shift.Handoff) {
+	fmt.Printf("%s from %s (%s → %s)\n", style.Bold.Render("Handoff"), h.Operator,
+		h.StartedAt.Local().Format("Jan 2 15:04"), h.EndedAt.Local().Format("Jan 2 15:04"))
+	if h.Notes != "" {
+		fmt.Printf("\n  %s\n", h.Notes)
+	}
+	printShiftItems("Active convoys", h.ActiveConvoys)
+	printShiftItems("Pending approvals", h.PendingApprovals)
+	if len(h.KnownIssues) > 0 {
+		fmt.Printf("\n  %s\n", style.Bold.Render("Known issues"))
+		for _, issue := range h.KnownIssues {
+			fmt.Printf("    • %s\n", issue)
+		}
+	}
+}
+
+func printShiftItems(heading string, items []shift.Item) {
+	fmt.Printf("\n  %s (%d)\n", style.Bold.Render(heading), len(items))
+	if len(items) == 0 {
+		fmt.Printf("    %s\n", style.Dim.Render("none"))
+		return
+	}
+	for _, it := range items {
+		line := fmt.Sprintf("    %s %s", it.ID, it.Title)
+		if it.Status != "" || it.Detail != "" {
+			line += " " + style.Dim.Render("["+strings.TrimSpace(it.Status+" "+it.Detail)+"]")
+		}
+		fmt.Println(line)
+	}
+}
+
+func printShiftJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
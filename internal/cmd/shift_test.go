@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/shift"
+)
+
+func TestResolveShiftOperator(t *testing.T) {
+	t.Setenv("GT_OPERATOR", "")
+	t.Setenv("USER", "unixuser")
+	if got := resolveShiftOperator(""); got != "unixuser" {
+		t.Errorf("resolveShiftOperator() = %q, want $USER", got)
+	}
+	t.Setenv("GT_OPERATOR", "dana")
+	if got := resolveShiftOperator(""); got != "dana" {
+		t.Errorf("resolveShiftOperator() = %q, want $GT_OPERATOR", got)
+	}
+	if got := resolveShiftOperator(" lee "); got != "lee" {
+		t.Errorf("resolveShiftOperator(--as) = %q, want lee", got)
+	}
+}
+
+func TestAttributeOperator(t *testing.T) {
+	townRoot := setupTestTownForTheme(t)
+	t.Chdir(townRoot)
+	t.Setenv("GT_ROLE", "")
+	t.Setenv("GT_OPERATOR", "alice")
+
+	if got := attributeOperator("overseer"); got != "overseer" {
+		t.Errorf("without a shift, attributeOperator() = %q, want overseer", got)
+	}
+
+	if _, err := shift.Start(townRoot, "alice", false, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if got := attributeOperator("overseer"); got != "overseer (alice)" {
+		t.Errorf("attributeOperator() = %q, want overseer (alice)", got)
+	}
+	// The operator is added to the identity, not substituted for it.
+	if got := attributeOperator("mayor"); got != "mayor (alice)" {
+		t.Errorf("attributeOperator(mayor) = %q, want mayor (alice)", got)
+	}
+
+	// Bob acting during Alice's shift is credited to Bob.
+	t.Setenv("GT_OPERATOR", "bob")
+	if got := attributeOperator("overseer"); got != "overseer (bob)" {
+		t.Errorf("attributeOperator() by bob = %q, want overseer (bob)", got)
+	}
+	// With no way to tell who is typing, the operator on shift is assumed.
+	t.Setenv("GT_OPERATOR", "")
+	t.Setenv("USER", "")
+	if got := attributeOperator("overseer"); got != "overseer (alice)" {
+		t.Errorf("attributeOperator() by unknown = %q, want overseer (alice)", got)
+	}
+
+	// Agent sessions keep their own identity even while a human is on shift.
+	t.Setenv("GT_ROLE", "gastown/witness")
+	if got := attributeOperator("gastown/witness"); got != "gastown/witness" {
+		t.Errorf("attributeOperator() for agent = %q, want unchanged", got)
+	}
+}
+
+func TestPrintShiftHandoff(t *testing.T) {
+	start := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+	h := &shift.Handoff{
+		Operator:         "alice",
+		StartedAt:        start,
+		EndedAt:          start.Add(8 * time.Hour),
+		ActiveConvoys:    []shift.Item{{ID: "hq-cv-1", Title: "Auth rework", Status: "open", Detail: "2/5 done"}},
+		PendingApprovals: nil,
+		KnownIssues:      []string{"refinery flaky on gastown"},
+		Notes:            "Watch the auth convoy",
+	}
+	out := captureStdout(t, func() { printShiftHandoff(h) })
+	for _, want := range []string{"alice", "Watch the auth convoy", "hq-cv-1", "2/5 done", "Pending approvals", "none", "refinery flaky"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("handoff output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -8,12 +8,15 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/lock"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/microtask"
 	"github.com/steveyegge/gastown/internal/nudge"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/telemetry"
@@ -64,6 +67,26 @@ Spawning Options (when target is a rig):
   gt sling gp-abc greenplace --force                # Ignore unread mail
   gt sling gp-abc greenplace --account work         # Use specific Claude account
 
+Saturated Rigs (--on-full):
+  gt sling gt-abc gastown --on-full queue    # Wait for a slot in gastown
+  gt sling gt-abc gastown --on-full reroute  # Use a rig with the needed capabilities
+  gt sling gt-abc gastown --on-full scale    # Spawn past the cap, within burst and budget
+
+  When the rig is at its capacity.max_polecats, sling lists the queue
+  position and estimated wait, capable alternative rigs, and whether a
+  scale-up is allowed, then asks (on a terminal) or fails (--on-full fail).
+
+Code Ownership:
+  When the target is a rig with a settings/OWNERS or CODEOWNERS file, a bead
+  naming paths (in its title, description or context pack) goes to the agent
+  that owns most of them, if that agent's session is running. Configure via
+  "ownership" in settings/config.json; skip with --no-owner.
+
+Duplicate Detection:
+  Before dispatch, the bead is compared against in-flight and recently closed
+  beads. Likely duplicates are printed as a warning (dispatch continues).
+  Configure via "dedup" in settings/config.json; skip with --no-dedup.
+
 Natural Language Args:
   gt sling gt-abc --args "patch release"
   gt sling code-review --args "focus on security"
@@ -103,8 +126,60 @@ Batch Slinging:
 
   When multiple beads are provided with a rig target, each bead gets its own
   polecat. This parallelizes work dispatch without running gt sling N times.
-  Use --max-concurrent to throttle spawn rate and prevent Dolt server overload.`,
-	Args: cobra.MinimumNArgs(1),
+  Use --max-concurrent to throttle spawn rate and prevent Dolt server overload.
+
+Experiments:
+  gt sling gt-abc gt-def gastown --experiment terse  # Split beads across arms
+
+  Each bead is assigned an arm of the experiment, which may set its formula,
+  agent or extra prompt. See gt experiment.
+
+Ad-hoc Tasks (--new):
+  echo "Fix the flaky login test" | gt sling --new - gastown
+  gt sling --new gastown                # Write the task in $EDITOR
+
+  Creates a task bead from the text (first line is the title, the rest the
+  description) in the target's rig, or town beads, and slings it in one step.
+
+Interactive (--interactive --view):
+  gt sling --interactive --view ready-frontend gastown
+
+  Lists the beads in a saved view (see gt view) and slings the ones you
+  pick ("1,3", "2-4" or "all") to the target.
+
+Splitting Large Beads (--split):
+  gt sling gt-epic gastown --split
+
+  A read-only decomposer agent in the rig proposes child beads. Review the
+  proposal (accept, edit as JSON in $EDITOR, or quit); accepted children are
+  created under the bead, tracked by one convoy (--no-convoy to skip) and
+  slung to the target (default: the bead's rig) as a batch.
+
+Shadow Runs (--shadow):
+  gt sling gt-abc gastown --shadow gemini
+  gt sling gt-abc gastown --shadow claude --shadow-args "write the tests first"
+
+  Runs the bead on one polecat and a copy of it on another, with a different
+  agent or instructions. Neither is merged: compare the branches and test
+  results with gt shadow compare, then land one with gt shadow pick.
+
+Micro-task Queues (--micro):
+  gt sling gt-abc gt-def gt-ghi gastown --micro
+
+  For tiny beads (doc fixes, one-liners), where spinning up a session costs
+  more than the work: one polecat works them one after another on a single
+  branch. The first bead is hooked as usual; the rest wait in its queue and
+  the polecat moves through them with gt micro next. One merge request
+  carries the batch. See gt micro.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if slingInteractive {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		if slingNew {
+			return cobra.MaximumNArgs(2)(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: runSling,
 }
 
@@ -117,6 +192,11 @@ var (
 	slingArgs        string   // --args flag: natural language instructions for executor
 	slingStdin       bool     // --stdin: read --message and/or --args from stdin
 	slingHookRawBead bool     // --hook-raw-bead: hook raw bead without default formula (expert mode)
+	slingInteractive bool     // --interactive: pick beads from a saved view
+	slingView        string   // --view: saved view for --interactive
+	slingSplit       bool     // --split: decompose the bead into children first
+	slingNew         bool     // --new: create the bead from stdin or $EDITOR, then sling it
+	slingMicro       bool     // --micro: queue the beads for one polecat session
 
 	// Flags migrated for polecat spawning (used by sling for work assignment)
 	slingCreate        bool   // --create: create polecat if it doesn't exist
@@ -133,6 +213,12 @@ var (
 	slingRalph         bool   // --ralph: enable Ralph Wiggum loop mode for multi-step workflows
 	slingFormula       string // --formula: override formula for dispatch (default: mol-polecat-work)
 	slingCrew          string // --crew: target a crew member in the specified rig
+	slingNoDedup       bool   // --no-dedup: skip duplicate-bead detection
+	slingExperiment    string // --experiment: assign each bead to an arm of this experiment
+	slingShadow        string // --shadow: also run a copy of the bead on this agent, for comparison
+	slingShadowArgs    string // --shadow-args: --args for the shadow run instead of --args
+	slingOnFull        string // --on-full: what to do when the target rig is at capacity
+	slingNoOwner       bool   // --no-owner: spawn a polecat even when an agent owns the bead's paths
 )
 
 func init() {
@@ -159,6 +245,18 @@ func init() {
 	slingCmd.Flags().StringVar(&slingBaseBranch, "base-branch", "", "Override base branch for polecat worktree (e.g., 'develop', 'release/v2')")
 	slingCmd.Flags().BoolVar(&slingRalph, "ralph", false, "Enable Ralph Wiggum loop mode (fresh context per step, for multi-step workflows)")
 	slingCmd.Flags().StringVar(&slingFormula, "formula", "", "Formula to apply (default: mol-polecat-work for polecat targets)")
+	slingCmd.Flags().BoolVar(&slingNoDedup, "no-dedup", false, "Skip the likely-duplicate check against recent and in-flight beads")
+	slingCmd.Flags().StringVar(&slingExperiment, "experiment", "", "Assign each bead to an arm of this experiment (see gt experiment)")
+	_ = slingCmd.Flags().SetAnnotation("experiment", AnnotationFeature, []string{config.FeatureExperiments})
+	slingCmd.Flags().BoolVarP(&slingInteractive, "interactive", "i", false, "Pick the beads to sling from a saved view (requires --view)")
+	slingCmd.Flags().StringVar(&slingView, "view", "", "Saved view to pick beads from (see gt view)")
+	slingCmd.Flags().BoolVar(&slingSplit, "split", false, "Have a decomposer agent split the bead into child beads, review them, then sling the children")
+	slingCmd.Flags().BoolVar(&slingNew, "new", false, "Create a task bead from stdin (-) or $EDITOR, then sling it")
+	slingCmd.Flags().BoolVar(&slingMicro, "micro", false, "Work the beads one after another in a single polecat session (see gt micro)")
+	slingCmd.Flags().StringVar(&slingShadow, "shadow", "", "Also run a copy of the bead on this agent in its own polecat, then compare (see gt shadow)")
+	slingCmd.Flags().StringVar(&slingShadowArgs, "shadow-args", "", "Executor instructions for the shadow run (default: --args)")
+	slingCmd.Flags().StringVar(&slingOnFull, "on-full", "", "When the target rig is at capacity: ask, queue, reroute, scale or fail (default: ask on a terminal, else fail)")
+	slingCmd.Flags().BoolVar(&slingNoOwner, "no-owner", false, "Spawn a fresh polecat even when an agent owns the paths the bead names")
 	slingCmd.Flags().StringVar(&slingCrew, "crew", "", "Target a crew member in the specified rig (e.g., --crew mel with target gastown → gastown/crew/mel)")
 
 	slingCmd.AddCommand(slingRespawnResetCmd)
@@ -270,6 +368,92 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 	}
 	townBeadsDir := filepath.Join(townRoot, ".beads")
 
+	// --new: the bead is written now, from stdin ("-") or $EDITOR.
+	if slingNew {
+		if slingInteractive || slingSplit || slingStdin || slingOnTarget != "" {
+			return fmt.Errorf("--new cannot be combined with --interactive, --split, --stdin or --on")
+		}
+		newArgs, err := slingNewArgs(townRoot, args, os.Stdin)
+		if err != nil || newArgs == nil {
+			return err
+		}
+		args = newArgs
+	}
+
+	// --interactive: the beads come from a saved view; args hold at most the target.
+	if slingInteractive {
+		if slingView == "" {
+			return fmt.Errorf("--interactive requires --view <name> (see gt view)")
+		}
+		if slingStdin {
+			return fmt.Errorf("--interactive reads the selection from stdin; it cannot be combined with --stdin")
+		}
+		picked, err := slingPickFromView(townRoot, slingView, os.Stdin)
+		if err != nil {
+			return err
+		}
+		if len(picked) == 0 {
+			fmt.Println("Nothing slung.")
+			return nil
+		}
+		args = append(picked, args...)
+	} else if slingView != "" {
+		return fmt.Errorf("--view requires --interactive")
+	}
+
+	// --split: a decomposer proposes children of the bead; once the operator
+	// accepts them, they are slung in its place (batch, with a convoy).
+	if slingSplit {
+		if slingInteractive || slingStdin || slingOnTarget != "" {
+			return fmt.Errorf("--split cannot be combined with --interactive, --stdin or --on")
+		}
+		if len(args) > 2 {
+			return fmt.Errorf("--split takes one bead and an optional target")
+		}
+		target := ""
+		if len(args) == 2 {
+			target = args[1]
+		}
+		children, rigName, err := runSlingSplit(townRoot, args[0], target, os.Stdin, slingDryRun)
+		if err != nil {
+			return err
+		}
+		if len(children) == 0 {
+			return nil
+		}
+		if target == "" {
+			target = rigName
+		}
+		// One convoy tracks the children; dispatch then skips per-bead convoys.
+		if !slingNoConvoy {
+			if convoyID, _, err := createBatchConvoy(children, rigName, slingOwned, slingMerge, slingBaseBranch); err != nil {
+				style.PrintWarning("could not create convoy for %s's children: %v", args[0], err)
+			} else {
+				fmt.Printf("%s Convoy %s tracks %d children of %s\n", style.SuccessPrefix, convoyID, len(children), args[0])
+			}
+		}
+		args = append(children, target)
+	}
+
+	// --shadow: the bead and a copy of it run on two agents for comparison.
+	if slingShadow != "" {
+		if slingInteractive || slingSplit || slingOnTarget != "" || slingExperiment != "" || slingNoMerge || slingMerge != "" {
+			return fmt.Errorf("--shadow cannot be combined with --interactive, --split, --on, --experiment, --no-merge or --merge")
+		}
+		if len(args) != 2 {
+			return fmt.Errorf("--shadow takes one bead and a rig target")
+		}
+		return runSlingShadow(townRoot, args[0], args[1], slingDryRun)
+	} else if slingShadowArgs != "" {
+		return fmt.Errorf("--shadow-args requires --shadow")
+	}
+
+	if slingExperiment != "" {
+		if err := validateExperimentFlags(cmd, townRoot, slingExperiment); err != nil {
+			return err
+		}
+	}
+
 	// Normalize target arguments: trim trailing slashes from target to handle tab-completion
 	// artifacts like "gt sling sl-123 slingshot/" → "gt sling sl-123 slingshot"
 	// This makes sling more forgiving without breaking existing functionality.
@@ -296,6 +480,35 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
+	// --micro: the first bead is slung as usual and the rest queue behind it
+	// for the same polecat session.
+	if slingMicro {
+		if slingSplit || slingOnTarget != "" || slingExperiment != "" || slingCrew != "" {
+			return fmt.Errorf("--micro cannot be combined with --split, --on, --experiment or --crew")
+		}
+		queue, err := prepareMicroSling(townRoot, args)
+		if err != nil {
+			return err
+		}
+		printMicroQueue(queue, slingDryRun)
+		if !slingDryRun {
+			if err := microtask.Save(townRoot, queue); err != nil {
+				return fmt.Errorf("saving micro-task queue: %w", err)
+			}
+			defer func() {
+				if retErr != nil {
+					_ = microtask.Remove(townRoot, queue.Head)
+				}
+			}()
+		}
+		args = []string{queue.Head, queue.Rig}
+		slingArgs = strings.TrimSpace(slingArgs + "\n\n" + microSlingInstructions(queue))
+	}
+
+	if err := validateOnFull(slingOnFull); err != nil {
+		return err
+	}
+
 	// Config-driven dispatch mode: check scheduler.max_polecats
 	deferred, deferErr := shouldDeferDispatch()
 	if deferErr != nil {
@@ -537,6 +750,9 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 				// Standalone formula mode: gt sling <formula> [target]
 				// Standalone formula: deferred dispatch is handled above (formula-on-bead),
 				// so no scheduler check needed here.
+				if slingExperiment != "" {
+					return fmt.Errorf("--experiment assigns beads to arms; use --on <bead> with a formula")
+				}
 				return runSlingFormula(ctx, args)
 			}
 			// Not a formula either - check if it looks like a bead ID (routing issue workaround).
@@ -586,6 +802,17 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		return fmt.Errorf("refusing to sling deferred bead %s: %q\nDeferred work should not consume polecat slots. Use --force to override", beadID, info.Title)
 	}
 
+	// Guard against hooking prompt injection from outside material (GitHub
+	// issues, webhook payloads) into an agent session. Not bypassed by
+	// --force; a human releases quarantined beads with gt quarantine release.
+	guardTarget := ""
+	if len(args) > 1 {
+		guardTarget = args[len(args)-1]
+	}
+	if err := guardSlingBead(townRoot, beadID, info, guardTarget, slingDryRun); err != nil {
+		return err
+	}
+
 	originalStatus := info.Status
 	originalAssignee := info.Assignee
 	force := slingForce // local copy to avoid mutating package-level flag
@@ -643,6 +870,99 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
+	// Code ownership: a bead naming paths an agent owns goes to that owner
+	// instead of a fresh polecat. Routed before the capacity check, which
+	// only applies to spawns.
+	if len(args) > 1 && !slingNoOwner {
+		if rigName, isRig := IsRigName(args[1]); isRig {
+			if owner, owned := ownerRouteFn(townRoot, rigName, beadID, info); owner != "" {
+				fmt.Printf("%s Routing %s to %s, owner of %s\n", style.Bold.Render("→"), beadID, owner, strings.Join(owned, ", "))
+				args[1] = owner
+			}
+		}
+	}
+
+	// Rig capacity: a rig at its own polecat cap offers to queue the bead,
+	// reroute it to a capable rig or scale up, instead of spawning. Checked
+	// before the experiment arm and canary so a reroute gets the new rig's.
+	rerouted := false
+	if len(args) > 1 {
+		if rigName, isRig := IsRigName(args[1]); isRig {
+			if sat := checkRigSaturation(townRoot, rigName, info.Labels, time.Now()); sat != nil {
+				sat.print()
+				if !slingDryRun {
+					choice, err := chooseOnFull(sat, slingOnFull)
+					if err != nil {
+						return err
+					}
+					switch choice {
+					case onFullQueue:
+						formula := formulaName
+						if slingOnTarget == "" {
+							formula = resolveFormula(slingFormula, slingHookRawBead)
+						} else if slingHookRawBead {
+							formula = ""
+						}
+						return scheduleBead(beadID, rigName, ScheduleOptions{
+							Formula:     formula,
+							Args:        slingArgs,
+							Vars:        slingVars,
+							Merge:       slingMerge,
+							BaseBranch:  slingBaseBranch,
+							NoConvoy:    slingNoConvoy,
+							Owned:       slingOwned,
+							Force:       force,
+							NoMerge:     slingNoMerge,
+							Account:     slingAccount,
+							Agent:       slingAgent,
+							HookRawBead: slingHookRawBead,
+							Ralph:       slingRalph,
+							RigQueue:    true,
+						})
+					case onFullReroute:
+						args[1] = sat.Alternatives[0].Rig
+						rerouted = true
+						fmt.Printf("%s Rerouting %s from %s to %s\n", style.Bold.Render("→"), beadID, rigName, args[1])
+					case onFullScale:
+						fmt.Printf("%s Scaling %s up to %d polecats\n", style.Bold.Render("→"), rigName, sat.Load.Active+1)
+					}
+				}
+			}
+		}
+	}
+
+	// Experiment arm: may swap the formula, agent or args for this bead.
+	// Resolved before resolveTarget, which spawns with the agent.
+	if slingExperiment != "" {
+		arm, err := assignExperimentArm(townRoot, slingExperiment, beadID, slingDryRun)
+		if err != nil {
+			return err
+		}
+		printExperimentArm(beadID, slingExperiment, arm)
+		if slingOnTarget != "" && arm.Formula != "" {
+			formulaName = arm.Formula
+		}
+		slingFormula, slingAgent, slingArgs = applyExperimentArm(arm, slingFormula, slingAgent, slingArgs)
+	} else if len(args) > 1 {
+		// Canary rollout (gt config canary): the rig's canary may take this bead.
+		if rigName, isRig := IsRigName(args[1]); isRig {
+			arm, name, err := assignCanaryArm(townRoot, rigName, beadID, slingDryRun)
+			if err != nil {
+				return err
+			}
+			if arm != nil {
+				printExperimentArm(beadID, name, arm)
+				slingFormula, slingAgent, slingArgs = applyExperimentArm(arm, slingFormula, slingAgent, slingArgs)
+			}
+		}
+	}
+
+	// Warn about likely duplicates before resolveTarget can spawn a polecat.
+	// Skipped on --force re-slings: the operator already knows this bead.
+	if !slingNoDedup && !force {
+		warnLikelyDuplicates(ctx, townRoot, beadID, info)
+	}
+
 	// TODO(scheduler-unify): Migrate single-sling rig dispatch to use executeSling().
 	// The inline logic below duplicates executeSling's 12-step flow. Batch sling
 	// and scheduler dispatch already use the unified path. Single-sling is deferred
@@ -668,6 +988,7 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		BeadID:     beadID,
 		TownRoot:   townRoot,
 		BaseBranch: slingBaseBranch,
+		Rerouted:   rerouted,
 	})
 	if err != nil {
 		return err
@@ -687,8 +1008,9 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 
 	// Cross-rig guard: prevent slinging beads to polecats in the wrong rig (gt-myecw).
 	// Polecats work in their rig's worktree and cannot fix code owned by another rig.
-	// Skip for self-sling (user knows what they're doing) and --force overrides.
-	if strings.Contains(targetAgent, "/polecats/") && !force && !isSelfSling {
+	// Skip for self-sling (user knows what they're doing), --force overrides
+	// and reroutes to a rig advertising the capabilities the bead needs.
+	if strings.Contains(targetAgent, "/polecats/") && !force && !isSelfSling && !rerouted {
 		if err := checkCrossRigGuard(beadID, targetAgent, townRoot); err != nil {
 			return err
 		}
@@ -743,7 +1065,7 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		// Unhook the bead from old owner (set status back to open)
 		unhookCmd := exec.Command("bd", "update", beadID, "--status=open", "--assignee=")
 		unhookCmd.Dir = beads.ResolveHookDir(townRoot, beadID, "")
-		if err := unhookCmd.Run(); err != nil {
+		if err := beads.RunCmd(unhookCmd); err != nil {
 			fmt.Printf("%s Could not unhook bead from old owner: %v\n", style.Dim.Render("Warning:"), err)
 		}
 	}
@@ -952,6 +1274,25 @@ func runSling(cmd *cobra.Command, args []string) (retErr error) {
 		}
 	}
 
+	// Materialize the bead's context pack into the briefing gt prime shows.
+	if n, err := materializeContextPack(townRoot, beadID, beadID, strings.SplitN(targetAgent, "/", 2)[0]); err != nil {
+		fmt.Printf("%s Could not materialize context pack: %v\n", style.Dim.Render("Warning:"), err)
+	} else if n > 0 {
+		fmt.Printf("%s Context pack materialized (%d entries)\n", style.Bold.Render("✓"), n)
+	}
+
+	if newPolecatInfo != nil {
+		captureSlingReplay(townRoot, newPolecatInfo, replayInputs{
+			BeadID:     beadID,
+			MoleculeID: attachedMoleculeID,
+			Formula:    formulaName,
+			Args:       slingArgs,
+			Vars:       slingVars,
+			Agent:      slingAgent,
+			Dispatcher: actor,
+		})
+	}
+
 	// Start delayed dog session now that hook is set
 	// This ensures dog sees the hook when gt prime runs on session start
 	if delayedDogInfo != nil {
@@ -1061,7 +1402,7 @@ func restorePinnedBead(townRoot, beadID, assignee string) {
 	if dir != "" {
 		cmd.Dir = dir
 	}
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		fmt.Printf("  %s Could not restore pinned state for bead %s: %v\n", style.Dim.Render("Warning:"), beadID, err)
 	} else {
 		fmt.Printf("  %s Restored pinned state for bead %s\n", style.Dim.Render("○"), beadID)
@@ -1120,7 +1461,7 @@ func rollbackSlingArtifacts(spawnInfo *SpawnedPolecatInfo, beadID, hookWorkDir,
 			unhookDir := beads.ResolveHookDir(townRoot, beadID, hookWorkDir)
 			unhookCmd := exec.Command("bd", "update", beadID, "--status=open", "--assignee=")
 			unhookCmd.Dir = unhookDir
-			if err := unhookCmd.Run(); err != nil {
+			if err := beads.RunCmd(unhookCmd); err != nil {
 				fmt.Printf("  %s Could not unhook bead %s: %v\n", style.Dim.Render("Warning:"), beadID, err)
 			} else {
 				fmt.Printf("  %s Unhooked bead %s\n", style.Dim.Render("○"), beadID)
@@ -10,6 +10,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/experiment"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
@@ -68,6 +69,40 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 	// Issue #288: Auto-apply formula for batch sling (resolved via flags)
 	formulaName := resolveFormula(slingFormula, slingHookRawBead)
 
+	// Experiment arms are picked up front so dry-run shows the split.
+	arms := map[string]*experiment.Arm{}
+	armExperiment := slingExperiment
+	if slingExperiment != "" {
+		townRoot := filepath.Dir(townBeadsDir)
+		for _, beadID := range beadIDs {
+			arm, err := assignExperimentArm(townRoot, slingExperiment, beadID, slingDryRun)
+			if err != nil {
+				return err
+			}
+			arms[beadID] = arm
+		}
+	} else {
+		// Canary rollout (gt config canary): at most one bead of the batch
+		// runs the canary while the rig's canary is in flight.
+		// Dry runs record nothing, so stop after the first canary pick.
+		townRoot := filepath.Dir(townBeadsDir)
+		sawCanary := false
+		for _, beadID := range beadIDs {
+			if slingDryRun && sawCanary {
+				break
+			}
+			arm, name, err := assignCanaryArm(townRoot, rigName, beadID, slingDryRun)
+			if err != nil {
+				return err
+			}
+			if arm == nil {
+				break // No canary on this rig
+			}
+			arms[beadID], armExperiment = arm, name
+			sawCanary = sawCanary || arm.Name == experiment.CanaryArm
+		}
+	}
+
 	if slingDryRun {
 		fmt.Printf("%s Batch slinging %d beads to rig '%s':\n", style.Bold.Render("🎯"), len(beadIDs), rigName)
 		if formulaName != "" {
@@ -76,6 +111,9 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			fmt.Printf("  Would hook raw beads (no formula)\n")
 		}
 		for _, beadID := range beadIDs {
+			if arm := arms[beadID]; arm != nil {
+				printExperimentArm(beadID, armExperiment, arm)
+			}
 			if formulaName != "" {
 				fmt.Printf("  Would spawn polecat and apply %s to: %s\n", formulaName, beadID)
 			} else {
@@ -165,6 +203,12 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			BeadsDir:         townBeadsDir,
 		}
 
+		if arm := arms[beadID]; arm != nil {
+			printExperimentArm(beadID, armExperiment, arm)
+			params.FormulaName, params.Agent, params.Args = applyExperimentArm(arm, params.FormulaName, params.Agent, params.Args)
+			params.SkipCook = formulaCooked && params.FormulaName == formulaName
+		}
+
 		result, err := executeSling(params)
 		if err != nil {
 			errMsg := ""
@@ -365,7 +409,7 @@ func closeConvoy(convoyID, reason string) {
 	closeArgs := []string{"close", convoyID, "-r", reason}
 	closeCmd := exec.Command("bd", closeArgs...)
 	closeCmd.Dir = townBeads
-	if err := closeCmd.Run(); err != nil {
+	if err := beads.RunCmd(closeCmd); err != nil {
 		fmt.Printf("  %s Could not close convoy %s: %v\n", style.Dim.Render("Warning:"), convoyID, err)
 	} else {
 		fmt.Printf("  %s Closed convoy %s\n", style.Dim.Render("○"), convoyID)
@@ -0,0 +1,278 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/retro"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/timetrack"
+)
+
+// --on-full choices for a sling to a rig at its polecat cap.
+const (
+	onFullAsk     = "ask"     // Prompt (the default on a terminal)
+	onFullQueue   = "queue"   // Wait for a slot in the rig
+	onFullReroute = "reroute" // Sling to an alternative rig instead
+	onFullScale   = "scale"   // Spawn past the cap, within burst and budget
+	onFullFail    = "fail"    // Refuse (the default off a terminal)
+)
+
+// typicalDurationWindow is how far back polecat assignments are read to
+// estimate queue waits.
+const typicalDurationWindow = 30 * 24 * time.Hour
+
+var (
+	// rigCapacityConfigFn is a seam for tests. Production reads the rig's
+	// capacity settings.
+	rigCapacityConfigFn = func(townRoot, rigName string) *capacity.RigConfig {
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rigName)))
+		if err != nil {
+			return nil
+		}
+		return settings.Capacity
+	}
+
+	// rigActivePolecatsFn is a seam for tests. Production uses
+	// countActivePolecatsByRig.
+	rigActivePolecatsFn = countActivePolecatsByRig
+
+	// rigQueuedContextsFn is a seam for tests. Production uses
+	// countScheduledByRig.
+	rigQueuedContextsFn = countScheduledByRig
+
+	// rigTypicalDurationFn is a seam for tests. Production uses
+	// typicalPolecatDuration.
+	rigTypicalDurationFn = typicalPolecatDuration
+
+	// slingSpendTodayFn is a seam for tests. Production sums today's session
+	// costs against the daily budget.
+	slingSpendTodayFn = func(townRoot string, now time.Time) (spent, budget float64) {
+		budget = config.LoadNotifications(townRoot).DailyBudgetUSD
+		if budget <= 0 {
+			return 0, 0
+		}
+		entries, err := querySessionCostEntries(now)
+		if err != nil {
+			return 0, budget
+		}
+		for _, e := range entries {
+			spent += e.CostUSD
+		}
+		return spent, budget
+	}
+)
+
+// rigSaturation is what a saturated rig can offer instead of a spawn.
+type rigSaturation struct {
+	Load         capacity.RigLoad
+	Needs        []string           // Capabilities the bead needs
+	Position     int                // Place in the rig's queue if queued
+	Wait         time.Duration      // Estimated wait at Position (0 = unknown)
+	Alternatives []capacity.RigLoad // Unsaturated rigs with the needed capabilities
+	ScaleErr     error              // Why scaling up is not allowed (nil = allowed)
+	Spent        float64
+	Budget       float64
+}
+
+// checkRigSaturation returns nil when rigName has room for another polecat,
+// or what it can offer instead when it is at its per-rig cap.
+func checkRigSaturation(townRoot, rigName string, labels []string, now time.Time) *rigSaturation {
+	cfg := rigCapacityConfigFn(townRoot, rigName)
+	if cfg == nil || cfg.MaxPolecats == 0 {
+		return nil
+	}
+	active := rigActivePolecatsFn()
+	queued := rigQueuedContextsFn(townRoot)
+	load := capacity.RigLoad{Rig: rigName, Active: active[rigName], Queued: queued[rigName], Config: cfg}
+	if !load.Saturated() {
+		return nil
+	}
+
+	sat := &rigSaturation{Load: load, Position: load.Queued + 1}
+	sat.Wait = capacity.EstimateWait(sat.Position, load.Max(), rigTypicalDurationFn(townRoot, rigName, now))
+
+	// Without needs:<capability> labels, a rig that can do everything the
+	// target rig does is a fit.
+	sat.Needs = capacity.NeededCapabilities(labels)
+	if len(sat.Needs) == 0 {
+		sat.Needs = cfg.Capabilities
+	}
+	sat.Alternatives = capacity.Alternatives(loadOtherRigs(townRoot, rigName, active), rigName, sat.Needs)
+
+	sat.Spent, sat.Budget = slingSpendTodayFn(townRoot, now)
+	switch {
+	case !load.CanScale():
+		sat.ScaleErr = fmt.Errorf("no burst headroom (max_polecats %d, burst %d)", load.Max(), cfg.Burst)
+	case sat.Budget > 0 && sat.Spent >= sat.Budget:
+		sat.ScaleErr = fmt.Errorf("daily budget spent ($%.2f of $%.2f)", sat.Spent, sat.Budget)
+	}
+	return sat
+}
+
+// loadOtherRigs returns the capacity load of every registered rig but exclude.
+func loadOtherRigs(townRoot, exclude string, active map[string]int) []capacity.RigLoad {
+	rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	var loads []capacity.RigLoad
+	for name := range rigsConfig.Rigs {
+		if name == exclude {
+			continue
+		}
+		if blocked, _ := IsRigParkedOrDocked(townRoot, name); blocked {
+			continue
+		}
+		loads = append(loads, capacity.RigLoad{Rig: name, Active: active[name], Config: rigCapacityConfigFn(townRoot, name)})
+	}
+	return loads
+}
+
+// options lists the choices that are available, in prompt order.
+func (s *rigSaturation) options() []string {
+	opts := []string{onFullQueue}
+	if len(s.Alternatives) > 0 {
+		opts = append(opts, onFullReroute)
+	}
+	if s.ScaleErr == nil {
+		opts = append(opts, onFullScale)
+	}
+	return opts
+}
+
+// print describes the saturated rig and each option.
+func (s *rigSaturation) print() {
+	l := s.Load
+	fmt.Printf("%s Rig %s is at capacity: %d/%d polecats\n", style.Warning.Render("⚠"), l.Rig, l.Active, l.Max())
+
+	wait := "unknown wait (no polecat history)"
+	if s.Wait > 0 {
+		wait = "about " + retro.FormatDuration(s.Wait)
+	}
+	fmt.Printf("  %-8s wait for a slot: position %d, %s\n", onFullQueue, s.Position, wait)
+
+	if len(s.Alternatives) > 0 {
+		var alts []string
+		for _, a := range s.Alternatives {
+			if a.Max() == 0 {
+				alts = append(alts, a.Rig)
+			} else {
+				alts = append(alts, fmt.Sprintf("%s (%d free)", a.Rig, a.Free()))
+			}
+		}
+		fmt.Printf("  %-8s sling to %s instead (has %s)\n", onFullReroute, strings.Join(alts, ", "), strings.Join(s.Needs, ", "))
+	} else if len(s.Needs) > 0 {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%-8s no other rig with %s has room", onFullReroute, strings.Join(s.Needs, ", "))))
+	} else {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%-8s the bead has no needs:<capability> labels and %s advertises no capabilities", onFullReroute, l.Rig)))
+	}
+
+	if s.ScaleErr == nil {
+		budget := "no daily budget set"
+		if s.Budget > 0 {
+			budget = fmt.Sprintf("$%.2f of $%.2f budget spent today", s.Spent, s.Budget)
+		}
+		fmt.Printf("  %-8s start polecat %d of %d allowed with burst (%s)\n", onFullScale, l.Active+1, l.Max()+l.Config.Burst, budget)
+	} else {
+		fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("%-8s %v", onFullScale, s.ScaleErr)))
+	}
+}
+
+// chooseOnFull resolves an --on-full mode to the option to take: the mode
+// itself, or the operator's pick when asking. An empty mode asks on a
+// terminal and fails otherwise.
+func chooseOnFull(s *rigSaturation, mode string) (string, error) {
+	if mode == "" {
+		mode = onFullFail
+		if isStdinTerminal() {
+			mode = onFullAsk
+		}
+	}
+	if mode == onFullAsk {
+		opts := s.options()
+		answer := strings.ToLower(confirmReadLineFn(fmt.Sprintf("Choose %s or fail [%s]: ", strings.Join(opts, ", "), onFullFail)))
+		mode = onFullFail
+		for _, opt := range opts {
+			if answer != "" && strings.HasPrefix(opt, answer) {
+				mode = opt
+				break
+			}
+		}
+	}
+
+	switch mode {
+	case onFullQueue:
+		return mode, nil
+	case onFullReroute:
+		if len(s.Alternatives) == 0 {
+			return "", fmt.Errorf("cannot reroute: no unsaturated rig with the needed capabilities")
+		}
+		return mode, nil
+	case onFullScale:
+		if s.ScaleErr != nil {
+			return "", fmt.Errorf("cannot scale up %s: %w", s.Load.Rig, s.ScaleErr)
+		}
+		return mode, nil
+	case onFullFail:
+		return "", fmt.Errorf("rig %s is at capacity (%d/%d polecats)\nRetry with --on-full=%s",
+			s.Load.Rig, s.Load.Active, s.Load.Max(), strings.Join(s.options(), "|"))
+	}
+	return "", validateOnFull(mode)
+}
+
+// validateOnFull checks the --on-full flag before any dispatch work.
+func validateOnFull(mode string) error {
+	switch mode {
+	case "", onFullAsk, onFullQueue, onFullReroute, onFullScale, onFullFail:
+		return nil
+	}
+	return fmt.Errorf("invalid --on-full %q (want %s, %s, %s, %s or %s)",
+		mode, onFullAsk, onFullQueue, onFullReroute, onFullScale, onFullFail)
+}
+
+// countActivePolecatsByRig counts running polecat sessions per rig.
+func countActivePolecatsByRig() map[string]int {
+	counts := make(map[string]int)
+	forEachPolecatSession(func(id *session.AgentIdentity) { counts[id.Rig]++ })
+	return counts
+}
+
+// countScheduledByRig counts open sling contexts per target rig.
+func countScheduledByRig(townRoot string) map[string]int {
+	counts := make(map[string]int)
+	contexts, err := listAllSlingContexts(townRoot)
+	if err != nil {
+		return counts
+	}
+	for _, ctx := range contexts {
+		if fields := beads.ParseSlingContextFields(ctx.Description); fields != nil {
+			counts[fields.TargetRig]++
+		}
+	}
+	return counts
+}
+
+// typicalPolecatDuration is the median time a polecat in the rig held a
+// bead over the last 30 days, or 0 without history.
+func typicalPolecatDuration(townRoot, rigName string, now time.Time) time.Duration {
+	evs, err := timeLoadEventsFn(townRoot, now.Add(-typicalDurationWindow))
+	if err != nil {
+		return 0
+	}
+	var durations []time.Duration
+	for _, span := range timetrack.Assignments(evs) {
+		if span.To.IsZero() || timetrack.RigOf(span.Agent) != rigName || !strings.Contains(span.Agent, "/polecats/") {
+			continue
+		}
+		durations = append(durations, span.To.Sub(span.From))
+	}
+	return capacity.TypicalDuration(durations)
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+)
+
+func fakeRigCapacity(t *testing.T, configs map[string]*capacity.RigConfig, active map[string]int, spent, budget float64) string {
+	t.Helper()
+	origConfig, origActive, origQueued := rigCapacityConfigFn, rigActivePolecatsFn, rigQueuedContextsFn
+	origTypical, origSpend := rigTypicalDurationFn, slingSpendTodayFn
+	t.Cleanup(func() {
+		rigCapacityConfigFn, rigActivePolecatsFn, rigQueuedContextsFn = origConfig, origActive, origQueued
+		rigTypicalDurationFn, slingSpendTodayFn = origTypical, origSpend
+	})
+	rigCapacityConfigFn = func(_, rig string) *capacity.RigConfig { return configs[rig] }
+	rigActivePolecatsFn = func() map[string]int { return active }
+	rigQueuedContextsFn = func(string) map[string]int { return map[string]int{"app": 2} }
+	rigTypicalDurationFn = func(string, string, time.Time) time.Duration { return 45 * time.Minute }
+	slingSpendTodayFn = func(string, time.Time) (float64, float64) { return spent, budget }
+
+	town := t.TempDir()
+	var rigs []string
+	for rig := range configs {
+		rigs = append(rigs, `"`+rig+`": {}`)
+	}
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rigsJSON := `{"version": 1, "rigs": {` + strings.Join(rigs, ", ") + `}}`
+	if err := os.WriteFile(filepath.Join(town, "mayor", "rigs.json"), []byte(rigsJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return town
+}
+
+func TestCheckRigSaturation(t *testing.T) {
+	configs := map[string]*capacity.RigConfig{
+		"app":   {MaxPolecats: 2, Burst: 1, Capabilities: []string{"go"}},
+		"web":   {MaxPolecats: 2, Capabilities: []string{"go", "frontend"}},
+		"tools": {MaxPolecats: 1, Capabilities: []string{"go"}},
+	}
+	town := fakeRigCapacity(t, configs, map[string]int{"app": 2, "tools": 1}, 10, 50)
+	now := time.Now()
+
+	sat := checkRigSaturation(town, "app", nil, now)
+	if sat == nil {
+		t.Fatal("app at 2/2 should be saturated")
+	}
+	if sat.Position != 3 || sat.Wait != 90*time.Minute {
+		t.Errorf("queue: position %d, wait %v", sat.Position, sat.Wait)
+	}
+	if len(sat.Alternatives) != 1 || sat.Alternatives[0].Rig != "web" {
+		t.Errorf("alternatives = %+v", sat.Alternatives)
+	}
+	if sat.ScaleErr != nil || strings.Join(sat.options(), ",") != "queue,reroute,scale" {
+		t.Errorf("options = %v, scale %v", sat.options(), sat.ScaleErr)
+	}
+
+	if sat := checkRigSaturation(town, "app", []string{"needs:rust"}, now); len(sat.Alternatives) != 0 {
+		t.Errorf("no rig has rust: %+v", sat.Alternatives)
+	}
+	if sat := checkRigSaturation(town, "web", nil, now); sat != nil {
+		t.Errorf("web at 0/2 = %+v", sat)
+	}
+
+	town = fakeRigCapacity(t, configs, map[string]int{"app": 2}, 60, 50)
+	if sat := checkRigSaturation(town, "app", nil, now); sat.ScaleErr == nil || !strings.Contains(sat.ScaleErr.Error(), "budget") {
+		t.Errorf("over budget scale = %v", sat.ScaleErr)
+	}
+	town = fakeRigCapacity(t, configs, map[string]int{"app": 3}, 0, 0)
+	if sat := checkRigSaturation(town, "app", nil, now); sat.ScaleErr == nil || !strings.Contains(sat.ScaleErr.Error(), "burst") {
+		t.Errorf("past burst scale = %v", sat.ScaleErr)
+	}
+}
+
+func TestChooseOnFull(t *testing.T) {
+	origTerm, origRead := isStdinTerminal, confirmReadLineFn
+	t.Cleanup(func() { isStdinTerminal, confirmReadLineFn = origTerm, origRead })
+	terminal := false
+	answer := ""
+	isStdinTerminal = func() bool { return terminal }
+	confirmReadLineFn = func(string) string { return answer }
+
+	sat := &rigSaturation{
+		Load:     capacity.RigLoad{Rig: "app", Active: 2, Config: &capacity.RigConfig{MaxPolecats: 2}},
+		ScaleErr: errors.New("no burst headroom"),
+	}
+	if _, err := chooseOnFull(sat, ""); err == nil || !strings.Contains(err.Error(), "--on-full=queue") {
+		t.Errorf("non-terminal default = %v", err)
+	}
+	if _, err := chooseOnFull(sat, onFullReroute); err == nil {
+		t.Error("reroute without alternatives should fail")
+	}
+	if _, err := chooseOnFull(sat, onFullScale); err == nil {
+		t.Error("scale without headroom should fail")
+	}
+	if choice, err := chooseOnFull(sat, onFullQueue); choice != onFullQueue || err != nil {
+		t.Errorf("queue = %q, %v", choice, err)
+	}
+
+	terminal = true
+	sat.Alternatives = []capacity.RigLoad{{Rig: "web"}}
+	answer = "r"
+	if choice, err := chooseOnFull(sat, ""); choice != onFullReroute || err != nil {
+		t.Errorf("asked, answered r = %q, %v", choice, err)
+	}
+	answer = "s" // scale is not on offer
+	if _, err := chooseOnFull(sat, onFullAsk); err == nil {
+		t.Error("an unavailable answer should fail")
+	}
+	if _, err := chooseOnFull(sat, "later"); err == nil {
+		t.Error("unknown mode should fail")
+	}
+}
@@ -71,7 +71,7 @@ func findConvoyByDescription(townRoot, beadID string) string {
 	listCmd := exec.Command("bd", "list", "--type=convoy", "--status=open", "--json")
 	listCmd.Dir = townBeads
 
-	out, err := listCmd.Output()
+	out, err := beads.CmdOutput(listCmd)
 	if err != nil {
 		return ""
 	}
@@ -155,7 +155,7 @@ func getConvoyInfoForIssue(issueID string) *ConvoyInfo {
 	showCmd.Stdout = &stdout
 	showCmd.Stderr = &stderr
 
-	if err := showCmd.Run(); err != nil {
+	if err := beads.RunCmd(showCmd); err != nil {
 		// Check if this is a "not found" error (phantom convoy) vs transient error.
 		// Phantom convoys occur when a convoy bead is deleted from HQ but tracking
 		// deps still exist in local beads DB (gt-9xum2). Return nil to treat as
@@ -238,7 +238,7 @@ func printConvoyConflict(beadID, convoyID string) {
 	showCmd.Dir = townBeads
 	var showOut bytes.Buffer
 	showCmd.Stdout = &showOut
-	if err := showCmd.Run(); err == nil {
+	if err := beads.RunCmd(showCmd); err == nil {
 		var items []struct {
 			Title string `json:"title"`
 		}
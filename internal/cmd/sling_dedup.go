@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beadlink"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/dedup"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// dedupInfraLabels marks beads that are orchestration plumbing rather than
+// work items. They are never reported as duplicates of real work.
+var dedupInfraLabels = []string{
+	"gt:agent", "gt:merge-request", "gt:message", "gt:convoy", "gt:molecule",
+	"gt:wisp", "gt:role", "gt:rig", "gt:channel", "gt:queue", "gt:group",
+	"gt:escalation", "gt:sling-context", "gt:standing-orders",
+}
+
+// listDedupCandidatesFn fetches beads to compare against. Overridable in tests.
+var listDedupCandidatesFn = func(beadID string, limit int) ([]*beads.Issue, error) {
+	return beads.New(resolveBeadDir(beadID)).List(beads.ListOptions{
+		Status:   "all",
+		Priority: -1,
+		Limit:    limit,
+	})
+}
+
+// warnLikelyDuplicates compares the bead about to be slung against recently
+// closed and in-flight beads in the same database and prints a warning for
+// likely duplicates. It never blocks dispatch: detection is advisory and any
+// failure (bd unavailable, endpoint down) is swallowed.
+func warnLikelyDuplicates(ctx context.Context, townRoot, beadID string, info *beadInfo) {
+	var cfg *dedup.Config
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		cfg = settings.Dedup
+	}
+	if !cfg.IsEnabled() {
+		return
+	}
+
+	matches := findLikelyDuplicates(ctx, cfg, beadID, info, time.Now())
+	if len(matches) == 0 {
+		return
+	}
+
+	linked := linkedDuplicates(info.Labels)
+	fmt.Printf("%s %s may duplicate existing work:\n", style.Warning.Render("⚠"), beadID)
+	for _, m := range matches {
+		why := fmt.Sprintf("%.0f%% similar", m.Score*100)
+		if linked[m.ID] {
+			why = "linked as duplicate"
+		}
+		fmt.Printf("  %s %s [%s] %s (%s)\n",
+			style.Dim.Render("→"), m.ID, m.Status, m.Title, why)
+		fmt.Printf("    %s\n", style.Dim.Render("gt show "+m.ID))
+	}
+	fmt.Printf("  %s\n", style.Dim.Render("Continuing. Use --no-dedup to skip this check."))
+}
+
+// findLikelyDuplicates returns candidates similar to the bead, best first.
+// Beads linked to it as duplicates (gt bead link) come first; beads linked
+// as other related work are known to be distinct and never reported.
+func findLikelyDuplicates(ctx context.Context, cfg *dedup.Config, beadID string, info *beadInfo, now time.Time) []dedup.Match {
+	issues, err := listDedupCandidatesFn(beadID, cfg.GetCandidateScan())
+	if err != nil || len(issues) == 0 {
+		return nil
+	}
+
+	linked := linkedDuplicates(info.Labels)
+	distinct := make(map[string]bool)
+	for _, l := range beadlink.FromLabels(info.Labels) {
+		if !beadlink.IsDuplicate(l.Type) {
+			distinct[l.Bead] = true
+		}
+	}
+	var declared []dedup.Match
+	docs := dedupDocuments(issues)
+	kept := docs[:0]
+	for _, d := range docs {
+		switch {
+		case linked[d.ID]:
+			declared = append(declared, dedup.Match{Document: d, Score: 1})
+		case !distinct[d.ID]:
+			kept = append(kept, d)
+		}
+	}
+
+	candidates := dedup.FilterCandidates(kept, now, cfg.GetLookback())
+	query := dedup.Document{ID: beadID, Title: info.Title, Description: info.Description, Status: info.Status}
+	scorer := dedup.NewScorer(cfg, func(err error) {
+		fmt.Printf("%s duplicate check: embedding endpoint failed, using TF-IDF: %v\n", style.Dim.Render("Warning:"), err)
+	})
+
+	matches, err := dedup.FindDuplicates(ctx, scorer, query, candidates, cfg.GetThreshold(), cfg.GetMaxResults())
+	if err != nil {
+		return declared
+	}
+	return append(declared, matches...)
+}
+
+// linkedDuplicates returns the beads a bead's labels link as duplicates.
+func linkedDuplicates(labels []string) map[string]bool {
+	linked := make(map[string]bool)
+	for _, l := range beadlink.FromLabels(labels) {
+		if beadlink.IsDuplicate(l.Type) {
+			linked[l.Bead] = true
+		}
+	}
+	return linked
+}
+
+// dedupDocuments converts work-item issues to dedup documents, skipping
+// ephemeral and infrastructure beads.
+func dedupDocuments(issues []*beads.Issue) []dedup.Document {
+	docs := make([]dedup.Document, 0, len(issues))
+	for _, issue := range issues {
+		if issue == nil || issue.Ephemeral || isDedupInfraBead(issue) {
+			continue
+		}
+		docs = append(docs, dedup.Document{
+			ID:          issue.ID,
+			Title:       issue.Title,
+			Description: issue.Description,
+			Status:      issue.Status,
+			ClosedAt:    parseBeadsTimestamp(issue.ClosedAt),
+		})
+	}
+	return docs
+}
+
+func isDedupInfraBead(issue *beads.Issue) bool {
+	for _, l := range dedupInfraLabels {
+		if beads.HasLabel(issue, l) {
+			return true
+		}
+	}
+	return issue.Type == "agent" || issue.Type == "convoy" || issue.Type == "molecule"
+}
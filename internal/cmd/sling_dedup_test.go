@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestFindLikelyDuplicates(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-48 * time.Hour).Format(time.RFC3339)
+	stale := now.Add(-60 * 24 * time.Hour).Format(time.RFC3339)
+
+	orig := listDedupCandidatesFn
+	t.Cleanup(func() { listDedupCandidatesFn = orig })
+	listDedupCandidatesFn = func(string, int) ([]*beads.Issue, error) {
+		return []*beads.Issue{
+			{ID: "gt-self", Title: "Add retry to webhook delivery", Status: "open"},
+			{ID: "gt-done", Title: "Add retries to webhook delivery", Status: "closed", ClosedAt: recent},
+			{ID: "gt-old", Title: "Add retry to webhook delivery", Status: "closed", ClosedAt: stale},
+			{ID: "gt-mr", Title: "Add retry to webhook delivery", Status: "in_progress", Labels: []string{"gt:merge-request"}},
+			{ID: "gt-wisp", Title: "Add retry to webhook delivery", Status: "in_progress", Ephemeral: true},
+			{ID: "gt-unrelated", Title: "Dashboard dark mode", Status: "in_progress"},
+		}, nil
+	}
+
+	info := &beadInfo{Title: "Add retry to webhook delivery", Status: "open"}
+	matches := findLikelyDuplicates(context.Background(), nil, "gt-self", info, now)
+	if len(matches) != 1 {
+		t.Fatalf("got %d matches, want 1: %+v", len(matches), matches)
+	}
+	if matches[0].ID != "gt-done" {
+		t.Errorf("match = %s, want gt-done", matches[0].ID)
+	}
+}
+
+func TestFindLikelyDuplicates_ListError(t *testing.T) {
+	orig := listDedupCandidatesFn
+	t.Cleanup(func() { listDedupCandidatesFn = orig })
+	listDedupCandidatesFn = func(string, int) ([]*beads.Issue, error) {
+		return nil, context.DeadlineExceeded
+	}
+
+	matches := findLikelyDuplicates(context.Background(), nil, "gt-x", &beadInfo{Title: "anything"}, time.Now())
+	if matches != nil {
+		t.Errorf("expected nil matches on list error, got %+v", matches)
+	}
+}
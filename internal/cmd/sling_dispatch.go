@@ -130,6 +130,15 @@ func executeSling(params SlingParams) (*SlingResult, error) {
 		return result, fmt.Errorf("could not get bead info: %w", err)
 	}
 
+	// Change freeze: only read-only beads reach a frozen rig. Not bypassed
+	// by --force; a release freeze is a hard stop.
+	if params.RigName != "" {
+		if err := checkRigFreeze(townRoot, params.RigName, params.BeadID); err != nil {
+			result.ErrMsg = "rig frozen"
+			return result, err
+		}
+	}
+
 	// Guard against dispatching closed/tombstone beads (defense-in-depth).
 	// Not bypassed by --force — if you need to re-dispatch, reopen the bead first.
 	if info.Status == "closed" || info.Status == "tombstone" {
@@ -163,6 +172,12 @@ func executeSling(params SlingParams) (*SlingResult, error) {
 		return result, fmt.Errorf("bead %s is deferred (use --force to override)", params.BeadID)
 	}
 
+	// Content guard: quarantined or suspicious beads never reach an agent.
+	if err := guardSlingBead(townRoot, params.BeadID, info, params.RigName, false); err != nil {
+		result.ErrMsg = "quarantined"
+		return result, err
+	}
+
 	// Send LIFECYCLE:Shutdown to the witness when force-stealing a bead from a
 	// live polecat. Without this, the old polecat becomes a zombie — still running
 	// but unaware it lost its hook. Mirrors the same logic in runSling (sling.go).
@@ -342,11 +357,27 @@ func executeSling(params SlingParams) (*SlingResult, error) {
 		fmt.Printf("  %s Could not store fields in bead: %v\n", style.Dim.Render("Warning:"), err)
 	}
 
+	if n, err := materializeContextPack(townRoot, params.BeadID, beadToHook, params.RigName); err != nil {
+		fmt.Printf("  %s Could not materialize context pack: %v\n", style.Dim.Render("Warning:"), err)
+	} else if n > 0 {
+		fmt.Printf("  %s Context pack materialized (%d entries)\n", style.Bold.Render("✓"), n)
+	}
+
 	// Update agent bead mode (for stuck detector to identify ralphcats)
 	if params.Mode != "" {
 		updateAgentMode(targetAgent, params.Mode, hookWorkDir, beadsDir)
 	}
 
+	captureSlingReplay(townRoot, spawnInfo, replayInputs{
+		BeadID:     params.BeadID,
+		MoleculeID: attachedMoleculeID,
+		Formula:    params.FormulaName,
+		Args:       params.Args,
+		Vars:       allVars,
+		Agent:      params.Agent,
+		Dispatcher: actor,
+	})
+
 	// 11. Start polecat session
 	pane, err := spawnInfo.StartSession()
 	if err != nil {
@@ -19,6 +19,7 @@ import (
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/errcode"
 	"github.com/steveyegge/gastown/internal/formula"
 	rigpkg "github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
@@ -235,10 +236,10 @@ func getBeadInfo(beadID string) (*beadInfo, error) {
 		Stderr(io.Discard).
 		Output()
 	if err != nil {
-		return nil, fmt.Errorf("bead '%s' not found", beadID)
+		return nil, beadLookupError(beadID, err)
 	}
 	if len(out) == 0 {
-		return nil, fmt.Errorf("bead '%s' not found", beadID)
+		return nil, beadLookupError(beadID, nil)
 	}
 	// bd show --json returns an array (issue + dependents), take first element
 	var infos []beadInfo
@@ -246,11 +247,27 @@ func getBeadInfo(beadID string) (*beadInfo, error) {
 		return nil, fmt.Errorf("parsing bead info: %w", err)
 	}
 	if len(infos) == 0 {
-		return nil, fmt.Errorf("bead '%s' not found", beadID)
+		return nil, beadLookupError(beadID, nil)
 	}
 	return &infos[0], nil
 }
 
+// beadLookupError says why bd couldn't show a bead: bd is missing, the
+// bead's prefix has no route (so bd looked in the wrong database), or
+// there is no such bead.
+func beadLookupError(beadID string, err error) error {
+	if errcode.Of(err) == errcode.BDMissing {
+		return errcode.New(errcode.BDMissing, fmt.Errorf("looking up bead '%s': %w", beadID, err))
+	}
+	if townRoot, ferr := workspace.FindFromCwd(); ferr == nil && townRoot != "" {
+		prefix := beads.ExtractPrefix(beadID)
+		if prefix != "" && beads.GetRigPathForPrefix(townRoot, prefix) == "" && resolveBeadDirFromRigsJSON(townRoot, prefix) == "" {
+			return errcode.New(errcode.RouteMissing, fmt.Errorf("bead '%s' not found: no route for prefix %s", beadID, prefix))
+		}
+	}
+	return errcode.New(errcode.BeadNotFound, fmt.Errorf("bead '%s' not found", beadID))
+}
+
 // beadFieldUpdates holds all the fields that need to be stored in a bead's description.
 // This enables a single read-modify-write cycle instead of sequential independent updates,
 // eliminating the race condition where concurrent writers could overwrite each other's fields.
@@ -9,6 +9,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/testtown"
 )
 
 func setupSlingTestRegistry(t *testing.T) {
@@ -236,3 +237,34 @@ func TestIsSlingConfigError(t *testing.T) {
 		})
 	}
 }
+
+// TestBeadLookupRoutesByPrefix runs the sling helpers against testtown's
+// fake bd, so prefix routing is covered without bd installed.
+func TestBeadLookupRoutesByPrefix(t *testing.T) {
+	town := testtown.New(t)
+	gastown := town.AddRig("gastown", "gt")
+	town.AddRig("beads", "bd")
+	crew := gastown.AddCrew("max")
+
+	rigBead := town.CreateBead(gastown.Path, "Rig work")
+	townBead := town.CreateBead(town.Root, "Town work")
+
+	// From a crew workspace, each ID must resolve to its own database.
+	town.Chdir(crew)
+	for id, title := range map[string]string{rigBead: "Rig work", townBead: "Town work"} {
+		if err := verifyBeadExists(id); err != nil {
+			t.Errorf("verifyBeadExists(%s): %v", id, err)
+		}
+		info, err := getBeadInfo(id)
+		if err != nil {
+			t.Fatalf("getBeadInfo(%s): %v", id, err)
+		}
+		if info.Title != title || info.Status != "open" {
+			t.Errorf("getBeadInfo(%s) = %+v", id, info)
+		}
+	}
+
+	if err := verifyBeadExists("bd-404"); err == nil {
+		t.Error("verifyBeadExists(bd-404) succeeded for a bead that doesn't exist")
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// slingPickFromView runs a saved view, lists its beads and reads which to
+// sling from in. Returns the chosen bead IDs (none if the user cancels).
+func slingPickFromView(townRoot, viewName string, in io.Reader) ([]string, error) {
+	v, err := lookupView(townRoot, viewName)
+	if err != nil {
+		return nil, err
+	}
+	result, err := runSavedView(townRoot, v, detectSender(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for rigName, msg := range result.Errors {
+		style.PrintWarning("%s: %s", rigName, msg)
+	}
+	if len(result.Results) == 0 {
+		return nil, fmt.Errorf("view %s has no beads to sling", viewName)
+	}
+
+	fmt.Printf("%s %s\n\n", style.Bold.Render("View:"), viewName)
+	for i, hit := range result.Results {
+		fmt.Printf("  %2d. %-12s %s %s\n", i+1, hit.ID, style.Dim.Render(fmt.Sprintf("P%d %s", hit.Priority, hit.Rig)), hit.Title)
+	}
+	fmt.Print("\nSling which? (e.g. 1,3 or 2-4 or all; empty to cancel): ")
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("reading selection: %w", err)
+	}
+	picks, err := parseSlingSelection(line, len(result.Results))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(picks))
+	for i, n := range picks {
+		ids[i] = result.Results[n-1].ID
+	}
+	return ids, nil
+}
+
+// parseSlingSelection parses "1,3", "2-4" or "all" into 1-based indexes,
+// in order and without duplicates.
+func parseSlingSelection(s string, count int) ([]int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if strings.EqualFold(s, "all") {
+		s = fmt.Sprintf("1-%d", count)
+	}
+	seen := make(map[int]bool)
+	var picks []int
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		lo, hi, isRange := strings.Cut(part, "-")
+		if !isRange {
+			hi = lo
+		}
+		from, err1 := strconv.Atoi(lo)
+		to, err2 := strconv.Atoi(hi)
+		if err1 != nil || err2 != nil || from < 1 || to > count || from > to {
+			return nil, fmt.Errorf("invalid selection %q: pick numbers from 1 to %d", part, count)
+		}
+		for n := from; n <= to; n++ {
+			if !seen[n] {
+				seen[n] = true
+				picks = append(picks, n)
+			}
+		}
+	}
+	return picks, nil
+}
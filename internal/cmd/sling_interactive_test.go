@@ -0,0 +1,40 @@
+package cmd
+
+import "testing"
+
+func TestParseSlingSelection(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []int
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"  \n", nil, false},
+		{"2", []int{2}, false},
+		{"1,3", []int{1, 3}, false},
+		{"3, 1 3", []int{3, 1}, false},
+		{"2-4", []int{2, 3, 4}, false},
+		{"all\n", []int{1, 2, 3, 4}, false},
+		{"0", nil, true},
+		{"5", nil, true},
+		{"3-2", nil, true},
+		{"x", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSlingSelection(tt.in, 4)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSlingSelection(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("parseSlingSelection(%q) = %v, want %v", tt.in, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("parseSlingSelection(%q) = %v, want %v", tt.in, got, tt.want)
+				break
+			}
+		}
+	}
+}
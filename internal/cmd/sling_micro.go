@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/microtask"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// slingMicroBeadInfoFn is a seam for tests. Production uses getBeadInfo.
+var slingMicroBeadInfoFn = getBeadInfo
+
+// prepareMicroSling builds the micro-task queue for a --micro sling: two or
+// more open beads, then optionally the rig (resolved from the bead prefixes
+// when omitted). Nothing is written.
+func prepareMicroSling(townRoot string, args []string) (*microtask.Queue, error) {
+	beadIDs := args
+	rigName := ""
+	if len(args) > 1 {
+		if name, isRig := IsRigName(args[len(args)-1]); isRig {
+			beadIDs, rigName = args[:len(args)-1], name
+		}
+	}
+	if len(beadIDs) < 2 {
+		return nil, fmt.Errorf("--micro takes two or more beads and an optional rig")
+	}
+	if rigName == "" {
+		if !allBeadIDs(beadIDs) {
+			return nil, fmt.Errorf("--micro targets a rig: '%s' is not a known rig", args[len(args)-1])
+		}
+		var err error
+		if rigName, err = resolveRigFromBeadIDs(beadIDs, townRoot); err != nil {
+			return nil, err
+		}
+	}
+
+	titles := make([]string, len(beadIDs))
+	for i, id := range beadIDs {
+		info, err := slingMicroBeadInfoFn(id)
+		if err != nil {
+			return nil, fmt.Errorf("bead '%s' not found", id)
+		}
+		if info.Status != "open" {
+			return nil, fmt.Errorf("%s is %s: only open beads can be queued", id, info.Status)
+		}
+		if !slingForce {
+			if err := checkCrossRigGuard(id, rigName+"/polecats/_", townRoot); err != nil {
+				return nil, err
+			}
+		}
+		titles[i] = info.Title
+	}
+	return microtask.New(rigName, beadIDs, titles, time.Now())
+}
+
+// printMicroQueue shows the queue a --micro sling sets up.
+func printMicroQueue(q *microtask.Queue, dryRun bool) {
+	verb := "Queueing"
+	if dryRun {
+		verb = "Would queue"
+	}
+	fmt.Printf("%s %s %d micro-tasks for one %s polecat:\n", style.Bold.Render("🎯"), verb, len(q.Tasks), q.Rig)
+	for i, t := range q.Tasks {
+		fmt.Printf("  %d. %s %s\n", i+1, t.Bead, style.Dim.Render(t.Title))
+	}
+}
+
+// microSlingInstructions tells the polecat how to work its queue. They are
+// appended to the head bead's --args.
+func microSlingInstructions(q *microtask.Queue) string {
+	rest := make([]string, 0, len(q.Tasks)-1)
+	for _, t := range q.Tasks[1:] {
+		rest = append(rest, t.Bead)
+	}
+	return fmt.Sprintf("Micro-task batch: %s is the first of %d tiny beads for this session; %s are queued after it. "+
+		"Work them one at a time on this branch, one commit each. After committing a task, run `gt micro next` "+
+		"to record it and get the next one (`gt micro skip -m <why>` gives a task back). "+
+		"Run gt done only once gt micro next reports the queue finished: one merge request carries the batch.",
+		q.Head, len(q.Tasks), strings.Join(rest, ", "))
+}
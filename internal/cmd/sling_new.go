@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// slingNewTemplate is shown in $EDITOR for gt sling --new.
+const slingNewTemplate = `
+# Describe the task. The first line is the bead title, the rest its
+# description. Lines starting with '#' are ignored; an empty task aborts.
+`
+
+var (
+	// slingNewEditFn is a seam for tests. Production uses editSlingNewTask.
+	slingNewEditFn = editSlingNewTask
+
+	// slingNewRigForFn is a seam for tests. Production uses IsRigName on the
+	// first path segment.
+	slingNewRigForFn = func(target string) string {
+		rigName, _ := IsRigName(strings.SplitN(target, "/", 2)[0])
+		return rigName
+	}
+
+	// slingNewCreateFn is a seam for tests. Production creates the task bead
+	// with bd.
+	slingNewCreateFn = func(dir, title, description string) (string, error) {
+		issue, err := beads.New(dir).Create(beads.CreateOptions{
+			Title:       title,
+			Description: description,
+			Labels:      []string{"gt:task"},
+			Priority:    -1,
+			Actor:       detectSender(),
+		})
+		if err != nil {
+			return "", err
+		}
+		return issue.ID, nil
+	}
+)
+
+// slingNewArgs reads an ad-hoc task for gt sling --new, from stdin when the
+// first argument is "-" and from $EDITOR otherwise, creates it as a bead and
+// returns the sling arguments with the new bead in front of the target.
+// The bead lives in the target's rig (or the current agent's rig when
+// slinging to self), falling back to town beads. Returns nil args when
+// nothing should be slung: an empty task, or a dry run.
+func slingNewArgs(townRoot string, args []string, stdin io.Reader) ([]string, error) {
+	fromStdin := len(args) > 0 && args[0] == "-"
+	if fromStdin {
+		args = args[1:]
+	}
+	if len(args) > 1 {
+		return nil, fmt.Errorf("--new takes at most one target (gt sling --new [-] [target])")
+	}
+	target := ""
+	if len(args) == 1 {
+		target = strings.TrimRight(args[0], "/")
+		if err := ValidateTarget(target); err != nil {
+			return nil, err
+		}
+	}
+
+	var text string
+	if fromStdin {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading stdin: %w", err)
+		}
+		text = string(data)
+	} else {
+		edited, err := slingNewEditFn(slingNewTemplate)
+		if err != nil {
+			return nil, err
+		}
+		text = stripSlingNewComments(edited)
+	}
+	title, description := parseSlingNewTask(text)
+	if title == "" {
+		fmt.Println("Empty task; nothing slung.")
+		return nil, nil
+	}
+
+	rigName := os.Getenv("GT_RIG")
+	if target != "" {
+		rigName = slingNewRigForFn(target)
+	}
+	dir, where := townRoot, "town"
+	if rigName != "" {
+		dir, where = filepath.Join(townRoot, rigName), rigName
+	}
+
+	if slingDryRun {
+		dest := target
+		if dest == "" {
+			dest = "self"
+		}
+		fmt.Printf("Would create %s bead %q and sling it to %s\n", where, title, dest)
+		return nil, nil
+	}
+	id, err := slingNewCreateFn(dir, title, description)
+	if err != nil {
+		return nil, fmt.Errorf("creating bead: %w", err)
+	}
+	fmt.Printf("%s Created %s: %s\n", style.SuccessPrefix, style.Bold.Render(id), title)
+	return append([]string{id}, args...), nil
+}
+
+// parseSlingNewTask splits a task into its title, the first non-blank line
+// (without markdown heading marks), and its description, the rest.
+func parseSlingNewTask(text string) (title, description string) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		title = strings.TrimSpace(strings.TrimLeft(line, "#"))
+		description = strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+		return title, description
+	}
+	return "", ""
+}
+
+// stripSlingNewComments drops the '#' comment lines of the editor template.
+func stripSlingNewComments(text string) string {
+	var kept []string
+	for _, line := range strings.Split(text, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// editSlingNewTask opens template in $EDITOR (default vi) and returns the
+// edited text.
+func editSlingNewTask(template string) (string, error) {
+	f, err := os.CreateTemp("", "gt-sling-*.md")
+	if err != nil {
+		return "", fmt.Errorf("creating temp file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+	if _, err := f.WriteString(template); err != nil {
+		f.Close()
+		return "", err
+	}
+	f.Close()
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	editorCmd := exec.Command(editor, path)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return "", fmt.Errorf("running editor: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
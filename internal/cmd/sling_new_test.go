@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseSlingNewTask(t *testing.T) {
+	title, desc := parseSlingNewTask("\n\n## Fix flaky login test  \nIt times out on CI.\n\nSee run 42.\n")
+	if title != "Fix flaky login test" || desc != "It times out on CI.\n\nSee run 42." {
+		t.Errorf("title %q, description %q", title, desc)
+	}
+	if title, _ := parseSlingNewTask(stripSlingNewComments(slingNewTemplate)); title != "" {
+		t.Errorf("untouched template should be empty, got title %q", title)
+	}
+}
+
+func TestSlingNewArgs(t *testing.T) {
+	town := t.TempDir()
+	origEdit, origRig, origCreate, origDry := slingNewEditFn, slingNewRigForFn, slingNewCreateFn, slingDryRun
+	t.Cleanup(func() {
+		slingNewEditFn, slingNewRigForFn, slingNewCreateFn, slingDryRun = origEdit, origRig, origCreate, origDry
+	})
+	t.Setenv("GT_RIG", "")
+
+	var created []string
+	slingNewCreateFn = func(dir, title, description string) (string, error) {
+		created = append(created, dir+"|"+title+"|"+description)
+		return "gt-new", nil
+	}
+	slingNewRigForFn = func(target string) string {
+		if strings.HasPrefix(target, "gastown") {
+			return "gastown"
+		}
+		return ""
+	}
+
+	var args []string
+	captureStdout(t, func() {
+		var err error
+		args, err = slingNewArgs(town, []string{"-", "gastown/"}, strings.NewReader("Fix login\nDetails here\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	if strings.Join(args, " ") != "gt-new gastown/" {
+		t.Errorf("args = %v", args)
+	}
+	if want := filepath.Join(town, "gastown") + "|Fix login|Details here"; len(created) != 1 || created[0] != want {
+		t.Errorf("created = %v, want %q", created, want)
+	}
+
+	// Editor mode, slinging to self outside a rig: town beads, comments dropped.
+	slingNewEditFn = func(template string) (string, error) {
+		return "Tidy docs\n# a comment\nbody\n" + template, nil
+	}
+	captureStdout(t, func() {
+		var err error
+		args, err = slingNewArgs(town, nil, strings.NewReader(""))
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+	if strings.Join(args, " ") != "gt-new" || created[1] != town+"|Tidy docs|body" {
+		t.Errorf("args = %v, created = %v", args, created)
+	}
+
+	// An empty task and a dry run create nothing.
+	slingNewEditFn = func(template string) (string, error) { return template, nil }
+	captureStdout(t, func() {
+		if args, err := slingNewArgs(town, []string{"mayor"}, nil); err != nil || args != nil {
+			t.Errorf("empty task: args %v, err %v", args, err)
+		}
+		slingDryRun = true
+		if args, err := slingNewArgs(town, []string{"-"}, strings.NewReader("Something")); err != nil || args != nil {
+			t.Errorf("dry run: args %v, err %v", args, err)
+		}
+	})
+	if len(created) != 2 {
+		t.Errorf("created = %v", created)
+	}
+
+	if _, err := slingNewArgs(town, []string{"-", "gastown", "mayor"}, strings.NewReader("x")); err == nil {
+		t.Error("two targets should be refused")
+	}
+}
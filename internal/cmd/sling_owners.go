@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/contextpack"
+	"github.com/steveyegge/gastown/internal/ownership"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+var (
+	// ownerRouteFn is a seam for tests. Production uses ownerRoute.
+	ownerRouteFn = ownerRoute
+
+	// ownerSessionAliveFn is a seam for tests. Production checks the owner's
+	// tmux session.
+	ownerSessionAliveFn = func(agent string) bool {
+		sessionName, _ := assigneeToSessionName(agent)
+		if sessionName == "" {
+			return false
+		}
+		alive, err := tmux.NewTmux().HasSession(sessionName)
+		return err == nil && alive
+	}
+)
+
+// ownerRoute returns the agent that owns the paths a bead names (in its
+// title, description or context pack), to sling it to instead of a fresh
+// polecat in rigName, along with the owned paths. It returns "" when the
+// rig has no ownership file, routing is off, nobody owns the paths, or the
+// owner has no running session to take the work.
+func ownerRoute(townRoot, rigName, beadID string, info *beadInfo) (string, []string) {
+	rigPath := filepath.Join(townRoot, rigName)
+	var cfg *ownership.Config
+	if settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil {
+		cfg = settings.Ownership
+	}
+	if !cfg.Routes() {
+		return "", nil
+	}
+	repoRoot := filepath.Join(rigPath, "mayor", "rig")
+	owners, err := ownership.Load(rigPath, func(path string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(repoRoot, path)) //nolint:gosec // G304: path is in the rig's repo
+	})
+	if err != nil || owners == nil {
+		return "", nil
+	}
+	owner, owned := owners.Route(rigName, cfg, beadPaths(townRoot, beadID, info))
+	if owner == "" || !ownerSessionAliveFn(owner) {
+		return "", nil
+	}
+	return owner, owned
+}
+
+// beadPaths returns the repo paths a bead mentions and the files in its
+// context pack.
+func beadPaths(townRoot, beadID string, info *beadInfo) []string {
+	paths := ownership.Paths(info.Title + "\n" + info.Description)
+	if state, err := contextpack.LoadState(townRoot); err == nil {
+		if pack := state.Packs[beadID]; pack != nil {
+			for _, e := range pack.Entries {
+				if e.Kind == contextpack.KindFile {
+					paths = append(paths, e.Ref)
+				}
+			}
+		}
+	}
+	return paths
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestOwnerRoute(t *testing.T) {
+	town := t.TempDir()
+	rigPath := filepath.Join(town, "gastown")
+	for _, dir := range []string{"settings", "mayor/rig/.github"} {
+		if err := os.MkdirAll(filepath.Join(rigPath, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	settings := `{"type": "rig-settings", "version": 1, "ownership": {"aliases": {"@alice": "crew/alice"}}}`
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	codeowners := "/internal/refinery/ @alice\n/docs/ @bob\n"
+	if err := os.WriteFile(filepath.Join(rigPath, "mayor", "rig", ".github", "CODEOWNERS"), []byte(codeowners), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	orig := ownerSessionAliveFn
+	t.Cleanup(func() { ownerSessionAliveFn = orig })
+	alive := map[string]bool{"gastown/crew/alice": true}
+	ownerSessionAliveFn = func(agent string) bool { return alive[agent] }
+
+	info := &beadInfo{
+		Title:       "Refinery retries forever",
+		Description: "The loop in internal/refinery/engineer.go:1740 never backs off. See docs/reference.md.",
+	}
+	owner, owned := ownerRoute(town, "gastown", "gt-1", info)
+	if owner != "gastown/crew/alice" || !reflect.DeepEqual(owned, []string{"internal/refinery/engineer.go"}) {
+		t.Errorf("ownerRoute = %q %q", owner, owned)
+	}
+
+	alive["gastown/crew/alice"] = false
+	if owner, _ := ownerRoute(town, "gastown", "gt-1", info); owner != "" {
+		t.Errorf("routed to %q with no running session", owner)
+	}
+	alive["gastown/crew/alice"] = true
+
+	if owner, _ := ownerRoute(town, "gastown", "gt-1", &beadInfo{Title: "Update go.mod"}); owner != "" {
+		t.Errorf("routed unowned bead to %q", owner)
+	}
+
+	settings = `{"type": "rig-settings", "version": 1, "ownership": {"route": false, "aliases": {"@alice": "crew/alice"}}}`
+	if err := os.WriteFile(filepath.Join(rigPath, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if owner, _ := ownerRoute(town, "gastown", "gt-1", info); owner != "" {
+		t.Errorf("routed to %q with routing off", owner)
+	}
+}
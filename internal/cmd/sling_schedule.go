@@ -59,6 +59,7 @@ type ScheduleOptions struct {
 	Agent       string   // Agent override (e.g., "gemini", "codex")
 	HookRawBead bool     // Hook raw bead without default formula
 	Ralph       bool     // Ralph Wiggum loop mode
+	RigQueue    bool     // Queued for a slot in a saturated rig (dispatched in direct mode too)
 }
 
 // scheduleBead schedules a bead for deferred dispatch via the capacity scheduler.
@@ -77,6 +78,15 @@ func scheduleBead(beadID, rigName string, opts ScheduleOptions) error {
 		return fmt.Errorf("'%s' is not a known rig", rigName)
 	}
 
+	if slingExperiment != "" {
+		arm, err := assignExperimentArm(townRoot, slingExperiment, beadID, opts.DryRun)
+		if err != nil {
+			return err
+		}
+		printExperimentArm(beadID, slingExperiment, arm)
+		opts.Formula, opts.Agent, opts.Args = applyExperimentArm(arm, opts.Formula, opts.Agent, opts.Args)
+	}
+
 	if !opts.Force {
 		if err := checkCrossRigGuard(beadID, rigName+"/polecats/_", townRoot); err != nil {
 			return err
@@ -162,6 +172,7 @@ func scheduleBead(beadID, rigName string, opts ScheduleOptions) error {
 		fields.Mode = "ralph"
 	}
 	fields.Owned = opts.Owned
+	fields.RigQueued = opts.RigQueue
 
 	// Create sling context bead — single atomic operation. No two-step write.
 	ctxBead, err := townBeads.CreateSlingContext(info.Title, beadID, fields)
@@ -251,7 +262,8 @@ func resolveRigForBead(townRoot, beadID string) string {
 	return beads.GetRigNameForPrefix(townRoot, prefix)
 }
 
-// resolveFormula determines the formula name from user flags.
+// resolveFormula determines the formula name from user flags, falling back
+// to the town's promoted polecat formula, then mol-polecat-work.
 func resolveFormula(explicit string, hookRawBead bool) string {
 	if hookRawBead {
 		return ""
@@ -259,9 +271,26 @@ func resolveFormula(explicit string, hookRawBead bool) string {
 	if explicit != "" {
 		return explicit
 	}
+	if promoted := townPolecatFormulaFn(); promoted != "" {
+		return promoted
+	}
 	return "mol-polecat-work"
 }
 
+// townPolecatFormulaFn is a seam for tests. Production reads the town's
+// promoted polecat formula from its settings.
+var townPolecatFormulaFn = func() string {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return ""
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return ""
+	}
+	return settings.PolecatFormula
+}
+
 // slingContextTTL is the maximum age of a sling context before it's considered
 // stale and ignored by areScheduled(). This prevents orphaned sling contexts
 // (from failed spawns or throttled dispatches) from permanently blocking tasks.
@@ -376,7 +405,7 @@ func detectSchedulerIDType(id string) (string, error) {
 // not convoy or epic mode.
 var schedulerTaskOnlyFlagNames = []string{
 	"account", "agent", "ralph", "args", "var",
-	"merge", "base-branch", "no-convoy", "owned", "no-merge",
+	"merge", "base-branch", "no-convoy", "owned", "no-merge", "experiment",
 }
 
 // validateNoTaskOnlySchedulerFlags checks that no task-only flags were set.
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/shadow"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	// shadowCreateFn is a seam for tests. Production files the shadow copy of a
+	// bead next to the original.
+	shadowCreateFn = func(beadID string, info *beadInfo) (string, error) {
+		issue, err := beads.New(resolveBeadDir(beadID)).Create(beads.CreateOptions{
+			Title:       "[shadow] " + info.Title,
+			Description: shadowDescription(beadID, info.Description),
+			Labels:      []string{"gt:task", "shadow"},
+			Priority:    -1,
+			Actor:       detectSender(),
+		})
+		if err != nil {
+			return "", err
+		}
+		return issue.ID, nil
+	}
+
+	// shadowSlingFn is a seam for tests. Production dispatches one side to a
+	// polecat in the rig. Both sides run with --no-merge so neither lands before
+	// the operator picks.
+	shadowSlingFn = func(townRoot, rigName string, side shadow.Side) error {
+		args := []string{"sling", side.Bead, rigName, "--no-merge", "--no-convoy", "--no-dedup"}
+		if side.Agent != "" {
+			args = append(args, "--agent", side.Agent)
+		}
+		if side.Args != "" {
+			args = append(args, "--args", side.Args)
+		}
+		cmd := exec.Command("gt", args...)
+		cmd.Dir = townRoot
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%v (%s)", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+)
+
+// shadowDescription is the shadow bead's description: the original's, and
+// where it came from.
+func shadowDescription(beadID, description string) string {
+	note := fmt.Sprintf("Shadow copy of %s: the same task on a second agent, for comparison (gt shadow compare %s). "+
+		"Work normally; the operator picks which run lands.", beadID, beadID)
+	if description == "" {
+		return note
+	}
+	return description + "\n\n---\n" + note
+}
+
+// runSlingShadow dispatches beadID and a shadow copy of it to two polecats
+// in the target rig: the original with --agent/--args, the copy with
+// --shadow/--shadow-args. The run is recorded for gt shadow.
+func runSlingShadow(townRoot, beadID, target string, dryRun bool) error {
+	rigName, ok := IsRigName(strings.TrimRight(target, "/"))
+	if !ok {
+		return fmt.Errorf("--shadow needs a rig target: each side gets its own polecat (gt sling %s <rig> --shadow %s)", beadID, slingShadow)
+	}
+	info, err := getBeadInfo(beadID)
+	if err != nil {
+		return err
+	}
+	if info.Status == "closed" || info.Status == beads.StatusHooked || info.Status == "in_progress" {
+		return fmt.Errorf("bead %s is %s; shadow runs start from an open bead", beadID, info.Status)
+	}
+
+	a := shadow.Side{Bead: beadID, Agent: slingAgent, Args: slingArgs}
+	b := shadow.Side{Agent: slingShadow, Args: slingArgs}
+	if slingShadowArgs != "" {
+		b.Args = slingShadowArgs
+	}
+	if a.Agent == b.Agent && a.Args == b.Args {
+		return fmt.Errorf("the shadow run is identical to the original: use a different --shadow agent or --shadow-args")
+	}
+
+	if dryRun {
+		fmt.Printf("Would run %s on %s twice:\n", beadID, rigName)
+		fmt.Printf("  a: %s (agent %s)\n", beadID, a.AgentName())
+		fmt.Printf("  b: shadow copy (agent %s)\n", b.AgentName())
+		return nil
+	}
+
+	state, err := shadow.LoadState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading shadow state: %w", err)
+	}
+	if run := state.Find(beadID); run != nil && run.Winner == "" {
+		return fmt.Errorf("%s already has a shadow run (see gt shadow compare %s)", beadID, run.Bead)
+	}
+
+	b.Bead, err = shadowCreateFn(beadID, info)
+	if err != nil {
+		return fmt.Errorf("creating shadow bead: %w", err)
+	}
+	fmt.Printf("%s Shadow bead %s for %s\n", style.SuccessPrefix, style.Bold.Render(b.Bead), beadID)
+
+	// Record before slinging: the shadow bead exists, so never create it twice.
+	state.Runs[beadID] = &shadow.Run{Bead: beadID, Rig: rigName, A: a, B: b, By: detectSender(), At: time.Now()}
+	if err := state.Save(townRoot); err != nil {
+		return fmt.Errorf("saving shadow state: %w", err)
+	}
+
+	for _, side := range []shadow.Side{a, b} {
+		if err := shadowSlingFn(townRoot, rigName, side); err != nil {
+			style.PrintWarning("slinging %s: %v (retry: gt sling %s %s --no-merge)", side.Bead, err, side.Bead, rigName)
+			continue
+		}
+		fmt.Printf("%s Slung %s to %s (agent %s)\n", style.SuccessPrefix, side.Bead, rigName, side.AgentName())
+	}
+	fmt.Printf("\nWhen both are done: gt shadow compare %s, then gt shadow pick %s a|b\n", beadID, beadID)
+	return nil
+}
@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// slingSplitTimeout bounds one decomposer run.
+const slingSplitTimeout = 10 * time.Minute
+
+// splitChild is one child bead proposed by the decomposer.
+type splitChild struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Priority    *int   `json:"priority,omitempty"`
+}
+
+var (
+	// slingSplitProposeFn is a seam for tests. Production asks a read-only
+	// agent in the rig to decompose the bead and returns its raw answer.
+	slingSplitProposeFn = func(townRoot, rigName, beadID string, info *beadInfo) (string, error) {
+		_, r, err := getRig(rigName)
+		if err != nil {
+			return "", err
+		}
+		rc, agentName, err := config.ResolveAgentConfigWithOverride(townRoot, r.Path, "")
+		if err != nil {
+			return "", fmt.Errorf("resolving agent: %w", err)
+		}
+		inv, err := buildAskInvocation(rc, agentName, buildSplitPrompt(rigName, beadID, info), "")
+		if err != nil {
+			return "", err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), slingSplitTimeout)
+		defer cancel()
+		c := exec.CommandContext(ctx, inv.Argv[0], inv.Argv[1:]...) //nolint:gosec // G204: argv from trusted agent config
+		c.Dir = askWorkDir(r.Path)
+		c.Env = clearClaudeCodeEnv(os.Environ())
+		var stdout bytes.Buffer
+		c.Stdout = &stdout
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return "", fmt.Errorf("decomposer did not answer within %s", slingSplitTimeout)
+			}
+			return "", fmt.Errorf("running decomposer agent: %w", err)
+		}
+		if inv.Structured {
+			answer, _, err := parseAskStructuredOutput(stdout.Bytes())
+			return answer, err
+		}
+		return stdout.String(), nil
+	}
+
+	// slingSplitEditFn is a seam for tests. Production uses editSplitProposal.
+	slingSplitEditFn = editSplitProposal
+
+	// slingSplitCreateFn is a seam for tests. Production creates the child bead
+	// with bd.
+	slingSplitCreateFn = func(parentID string, child splitChild) (string, error) {
+		priority := -1
+		if child.Priority != nil {
+			priority = *child.Priority
+		}
+		issue, err := beads.New(resolveBeadDir(parentID)).Create(beads.CreateOptions{
+			Title:       child.Title,
+			Description: child.Description,
+			Labels:      []string{"gt:task"},
+			Priority:    priority,
+			Parent:      parentID,
+			Actor:       detectSender(),
+		})
+		if err != nil {
+			return "", err
+		}
+		return issue.ID, nil
+	}
+)
+
+// runSlingSplit sends beadID to a decomposer agent in the target's rig (or
+// the bead's own), lets the operator accept or edit the proposed children,
+// and creates them under beadID. Returns the new child IDs, or none if the
+// operator quits, and the rig the decomposer ran in. With dryRun the
+// proposal is shown but nothing is created.
+func runSlingSplit(townRoot, beadID, target string, in io.Reader, dryRun bool) ([]string, string, error) {
+	info, err := getBeadInfo(beadID)
+	if err != nil {
+		return nil, "", err
+	}
+	rigName := ""
+	if target != "" {
+		rigName, _ = IsRigName(strings.SplitN(target, "/", 2)[0])
+	}
+	if rigName == "" {
+		rigName = beads.GetRigNameForPrefix(townRoot, beads.ExtractPrefix(beadID))
+	}
+	if rigName == "" {
+		return nil, "", fmt.Errorf("--split needs a rig to run the decomposer in: give a rig target (gt sling %s <rig> --split)", beadID)
+	}
+
+	fmt.Printf("%s Asking %s decomposer to split %s...\n", style.Bold.Render("✂"), rigName, beadID)
+	answer, err := slingSplitProposeFn(townRoot, rigName, beadID, info)
+	if err != nil {
+		return nil, "", err
+	}
+	children, err := parseSplitProposal(answer)
+	if err != nil {
+		return nil, "", err
+	}
+
+	children, ok, err := reviewSplitProposal(beadID, info.Title, children, in)
+	if err != nil || !ok {
+		return nil, "", err
+	}
+	if dryRun {
+		fmt.Printf("Would create %d child bead(s) under %s\n", len(children), beadID)
+		return nil, rigName, nil
+	}
+
+	ids := make([]string, 0, len(children))
+	for _, child := range children {
+		id, err := slingSplitCreateFn(beadID, child)
+		if err != nil {
+			return nil, "", fmt.Errorf("creating child %q (created so far: %s): %w", child.Title, strings.Join(ids, " "), err)
+		}
+		fmt.Printf("%s %s %s\n", style.SuccessPrefix, style.Bold.Render(id), child.Title)
+		ids = append(ids, id)
+	}
+	return ids, rigName, nil
+}
+
+// reviewSplitProposal shows the proposal and reads accept/edit/quit until
+// the operator accepts (ok) or quits.
+func reviewSplitProposal(beadID, title string, children []splitChild, in io.Reader) ([]splitChild, bool, error) {
+	reader := bufio.NewReader(in)
+	for {
+		fmt.Printf("\n%s %s %s\n\n", style.Bold.Render("Proposed split of"), beadID, style.Dim.Render(title))
+		for i, child := range children {
+			p := ""
+			if child.Priority != nil {
+				p = style.Dim.Render(fmt.Sprintf(" P%d", *child.Priority))
+			}
+			fmt.Printf("  %d. %s%s\n", i+1, child.Title, p)
+			if child.Description != "" {
+				fmt.Printf("     %s\n", style.Dim.Render(strings.SplitN(child.Description, "\n", 2)[0]))
+			}
+		}
+		fmt.Print("\n[a]ccept, [e]dit, [q]uit: ")
+
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, false, fmt.Errorf("reading answer: %w", err)
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "a", "accept", "y", "yes":
+			return children, true, nil
+		case "e", "edit":
+			edited, editErr := slingSplitEditFn(children)
+			if editErr != nil {
+				style.PrintWarning("%v (keeping previous proposal)", editErr)
+				continue
+			}
+			children = edited
+		default:
+			fmt.Println("Split cancelled; nothing created.")
+			return nil, false, nil
+		}
+	}
+}
+
+// editSplitProposal opens the proposal as JSON in $EDITOR.
+func editSplitProposal(children []splitChild) ([]splitChild, error) {
+	data, err := json.MarshalIndent(children, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp("", "gt-split-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return nil, err
+	}
+	f.Close()
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	editorCmd := exec.Command(editor, path)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	if err := editorCmd.Run(); err != nil {
+		return nil, fmt.Errorf("running editor: %w", err)
+	}
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseSplitProposal(string(edited))
+}
+
+// parseSplitProposal extracts the JSON array of children from the
+// decomposer's answer, tolerating prose or code fences around it.
+func parseSplitProposal(answer string) ([]splitChild, error) {
+	start, end := strings.Index(answer, "["), strings.LastIndex(answer, "]")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("decomposer did not return a JSON list of child beads")
+	}
+	var children []splitChild
+	if err := json.Unmarshal([]byte(answer[start:end+1]), &children); err != nil {
+		return nil, fmt.Errorf("parsing proposed children: %w", err)
+	}
+	kept := children[:0]
+	for _, child := range children {
+		child.Title = strings.TrimSpace(child.Title)
+		if child.Title == "" {
+			continue
+		}
+		if child.Priority != nil && (*child.Priority < 0 || *child.Priority > 4) {
+			child.Priority = nil
+		}
+		kept = append(kept, child)
+	}
+	if len(kept) < 2 {
+		return nil, fmt.Errorf("decomposer proposed %d child bead(s); a split needs at least 2", len(kept))
+	}
+	return kept, nil
+}
+
+// buildSplitPrompt asks a read-only agent to break a bead into children.
+func buildSplitPrompt(rigName, beadID string, info *beadInfo) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "You are decomposing an oversized work item for the %q rig in a Gas Town workspace.\n", rigName)
+	b.WriteString("Read the code in your working directory as needed. This is read-only:\n")
+	b.WriteString("do not modify files, create beads, commit, or run gt/bd commands that write state.\n\n")
+	b.WriteString("Split the bead below into 2-8 child beads, each small enough for one agent to finish\n")
+	b.WriteString("in a single session, ordered so earlier children unblock later ones.\n")
+	b.WriteString("Reply with ONLY a JSON array, no prose:\n")
+	b.WriteString(`[{"title": "...", "description": "what to do and how to verify it", "priority": 2}]`)
+	fmt.Fprintf(&b, "\n\nBead %s: %s\n", beadID, info.Title)
+	if info.Description != "" {
+		b.WriteString("\n")
+		b.WriteString(info.Description)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseSplitProposal(t *testing.T) {
+	answer := "Here is the split:\n```json\n" + `[
+  {"title": "Add retry config", "description": "Wire settings", "priority": 1},
+  {"title": "  Retry on 5xx  "},
+  {"title": ""},
+  {"title": "Document retries", "priority": 9}
+]` + "\n```\n"
+	children, err := parseSplitProposal(answer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(children) != 3 {
+		t.Fatalf("children = %d, want 3 (empty title dropped)", len(children))
+	}
+	if children[1].Title != "Retry on 5xx" {
+		t.Errorf("title not trimmed: %q", children[1].Title)
+	}
+	if children[0].Priority == nil || *children[0].Priority != 1 {
+		t.Error("priority 1 lost")
+	}
+	if children[2].Priority != nil {
+		t.Error("out-of-range priority should be dropped")
+	}
+}
+
+func TestParseSplitProposal_Errors(t *testing.T) {
+	for _, answer := range []string{
+		"I can't split this.",
+		`[{"title": "only one"}]`,
+		`[{"title": 3}]`,
+	} {
+		if _, err := parseSplitProposal(answer); err == nil {
+			t.Errorf("parseSplitProposal(%q) should fail", answer)
+		}
+	}
+}
+
+func TestReviewSplitProposal(t *testing.T) {
+	children := []splitChild{{Title: "a"}, {Title: "b"}}
+
+	got, ok, err := reviewSplitProposal("gt-1", "epic", children, strings.NewReader("a\n"))
+	if err != nil || !ok || len(got) != 2 {
+		t.Errorf("accept: got %v ok=%v err=%v", got, ok, err)
+	}
+
+	_, ok, err = reviewSplitProposal("gt-1", "epic", children, strings.NewReader("q\n"))
+	if err != nil || ok {
+		t.Errorf("quit: ok=%v err=%v", ok, err)
+	}
+
+	_, ok, _ = reviewSplitProposal("gt-1", "epic", children, strings.NewReader(""))
+	if ok {
+		t.Error("EOF should cancel")
+	}
+}
+
+func TestReviewSplitProposal_Edit(t *testing.T) {
+	orig := slingSplitEditFn
+	t.Cleanup(func() { slingSplitEditFn = orig })
+
+	calls := 0
+	slingSplitEditFn = func(children []splitChild) ([]splitChild, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("bad JSON")
+		}
+		return append(children, splitChild{Title: "c"}), nil
+	}
+
+	got, ok, err := reviewSplitProposal("gt-1", "epic", []splitChild{{Title: "a"}, {Title: "b"}}, strings.NewReader("e\ne\naccept\n"))
+	if err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if len(got) != 3 || got[2].Title != "c" {
+		t.Errorf("edited proposal = %v, want a failed edit kept and the second applied", got)
+	}
+}
+
+func TestBuildSplitPrompt(t *testing.T) {
+	prompt := buildSplitPrompt("gastown", "gt-42", &beadInfo{Title: "Rewrite scheduler", Description: "Make it fair"})
+	for _, want := range []string{"gastown", "gt-42: Rewrite scheduler", "Make it fair", "JSON array", "read-only"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+}
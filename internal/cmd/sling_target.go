@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/cli"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -81,6 +82,10 @@ func resolveSelfTarget() (agentID string, pane string, hookRoot string, err erro
 	case RoleDog:
 		agentID = fmt.Sprintf("deacon/dogs/%s", roleInfo.Polecat)
 	default:
+		if roleInfo.Custom {
+			agentID = customRoleAddress(roleInfo)
+			break
+		}
 		return "", "", "", fmt.Errorf("cannot determine agent identity (role: %s)", roleInfo.Role)
 	}
 
@@ -110,6 +115,7 @@ type ResolveTargetOptions struct {
 	TownRoot   string
 	WorkDesc   string // Description for dog dispatch (defaults to HookBead if empty)
 	BaseBranch string // Override base branch for polecat worktree
+	Rerouted   bool   // Rerouted off a saturated rig (--on-full): skip the cross-rig guard
 }
 
 // ResolvedTarget holds the results of target resolution.
@@ -193,9 +199,13 @@ func resolveTarget(target string, opts ResolveTargetOptions) (*ResolvedTarget, e
 				}
 				return nil, fmt.Errorf("cannot sling to %s rig %q\n%s %s", reason, rigName, undoCmd, rigName)
 			}
+			// Change freeze is a hard stop: not bypassed by --force.
+			if err := checkRigFreeze(townRoot, rigName, opts.BeadID); err != nil {
+				return nil, err
+			}
 		}
 
-		if opts.BeadID != "" && !opts.Force {
+		if opts.BeadID != "" && !opts.Force && !opts.Rerouted {
 			if err := checkCrossRigGuard(opts.BeadID, rigName+"/polecats/_", opts.TownRoot); err != nil {
 				return nil, err
 			}
@@ -229,6 +239,31 @@ func resolveTarget(target string, opts ResolveTargetOptions) (*ResolvedTarget, e
 		return result, nil
 	}
 
+	// Custom role agent ("architect", "gastown/security-reviewer")
+	customTownRoot := opts.TownRoot
+	if customTownRoot == "" {
+		customTownRoot, _ = workspace.FindFromCwd()
+	}
+	if agentID, sessionName, ok := resolveCustomRoleTarget(customTownRoot, target); ok {
+		result.Agent = agentID
+		if opts.DryRun {
+			fmt.Printf("Would sling to custom role %s\n", agentID)
+			result.Pane = "<custom-role-pane>"
+			return result, nil
+		}
+		pane, err := getSessionPane(sessionName)
+		if err != nil {
+			return nil, fmt.Errorf("%s has no running session (start it with %s role start): %w", agentID, cli.Name(), err)
+		}
+		workDir, err := tmux.NewTmux().GetPaneWorkDir(sessionName)
+		if err != nil {
+			return nil, fmt.Errorf("getting working dir for %s: %w", sessionName, err)
+		}
+		result.Pane = pane
+		result.WorkDir = workDir
+		return result, nil
+	}
+
 	// Existing agent (with dead polecat fallback).
 	// Uses resolveTargetAgentFn seam — crew, mayor, and all existing agents
 	// resolve here, getting their pane for nudge delivery (gt-in7b).
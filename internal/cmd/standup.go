@@ -0,0 +1,410 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/standup"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	standupJSON  bool
+	standupSince time.Duration
+	standupLast  bool
+	standupTo    string
+	standupAt    string
+	standupLead  time.Duration
+)
+
+// standupPromptText is the nudge sent to each worker before the standup.
+const standupPromptText = `Standup: record one line on your progress (done, next, blockers) with: gt standup note "<note>" — then carry on with your work.`
+
+var standupCmd = &cobra.Command{
+	Use:     "standup",
+	GroupID: GroupComm,
+	Short:   "Show the town standup: one progress line per worker",
+	Long: `Compile a standup report for every running polecat and crew member.
+
+Each line shows the agent's hooked work and its latest progress note
+(gt standup note). Agents without a fresh note get a one-line summary of
+what their pane last showed, so you can skim the town without peeking at
+every session.
+
+The daemon's standup patrol (opt-in) prompts workers for notes shortly
+before the configured time and then publishes the report to the overseer.
+The same report is shown in the web dashboard.
+
+Examples:
+  gt standup                      # Compile now
+  gt standup --since 4h           # Ignore notes older than 4h
+  gt standup --last               # Last published report
+  gt standup note "Auth tests green, starting on token refresh"
+  gt standup prompt               # Ask workers for notes now
+  gt standup publish              # Mail the report to the overseer`,
+	Args: cobra.NoArgs,
+	RunE: runStandup,
+}
+
+var standupNoteCmd = &cobra.Command{
+	Use:   "note <text>",
+	Short: "Record your progress note for the standup",
+	Long: `Record a one-line progress note for the standup report.
+
+The note replaces your previous one. Keep it short: what you finished,
+what's next, and anything blocking you.
+
+Examples:
+  gt standup note "Merged gt-abc; writing tests for gt-def, blocked on CI quota"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runStandupNote,
+}
+
+var standupPromptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Nudge running workers to record a standup note",
+	Long: `Nudge every running polecat and crew member to record a standup note.
+
+Workers in Do Not Disturb mode are skipped; their line falls back to a
+pane summary.
+
+Examples:
+  gt standup prompt`,
+	Args: cobra.NoArgs,
+	RunE: runStandupPrompt,
+}
+
+var standupPublishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Compile the standup and mail it",
+	Long: `Compile the standup report, save it as the latest report and mail it.
+
+Examples:
+  gt standup publish
+  gt standup publish --to mayor/`,
+	Args: cobra.NoArgs,
+	RunE: runStandupPublish,
+}
+
+var standupTickCmd = &cobra.Command{
+	Use:   "tick",
+	Short: "Prompt or publish if the daily standup is due (daemon)",
+	Long: `Run the scheduled standup steps that are due.
+
+Workers are prompted --lead before --at, and the report is published at
+--at (local time). Each step runs once per day however often tick is
+called; the daemon's standup patrol calls it every few minutes.
+
+Examples:
+  gt standup tick --at 09:00 --lead 30m`,
+	Args:   cobra.NoArgs,
+	Hidden: true,
+	RunE:   runStandupTick,
+}
+
+var (
+	// standupCapturePaneFn is a seam for tests. Production captures the last 40
+	// pane lines over tmux.
+	standupCapturePaneFn = func(session string) ([]string, error) {
+		return tmux.NewTmux().CapturePaneLines(session, 40)
+	}
+
+	// standupHookedWorkFn is a seam for tests. Production lists hooked beads in
+	// the rig.
+	standupHookedWorkFn = func(townRoot, rig, assignee string) (id, title string) {
+		b := beads.New(filepath.Join(townRoot, rig, "mayor", "rig"))
+		hooked, err := b.List(beads.ListOptions{
+			Status:   beads.StatusHooked,
+			Assignee: assignee,
+			Priority: -1,
+		})
+		if err != nil || len(hooked) == 0 {
+			return "", ""
+		}
+		return hooked[0].ID, hooked[0].Title
+	}
+)
+
+func init() {
+	standupCmd.Flags().BoolVar(&standupJSON, "json", false, "Output as JSON")
+	standupCmd.Flags().DurationVar(&standupSince, "since", 24*time.Hour, "Ignore notes older than this")
+	standupCmd.Flags().BoolVar(&standupLast, "last", false, "Show the last published report")
+	standupPublishCmd.Flags().StringVar(&standupTo, "to", "overseer", "Address to mail the report to")
+	standupPublishCmd.Flags().DurationVar(&standupSince, "since", 24*time.Hour, "Ignore notes older than this")
+	standupTickCmd.Flags().StringVar(&standupAt, "at", "09:00", "Publish time of day (HH:MM, local)")
+	standupTickCmd.Flags().DurationVar(&standupLead, "lead", 30*time.Minute, "How long before --at to prompt workers")
+	standupTickCmd.Flags().StringVar(&standupTo, "to", "overseer", "Address to mail the report to")
+	standupTickCmd.Flags().DurationVar(&standupSince, "since", 24*time.Hour, "Ignore notes older than this")
+
+	standupCmd.AddCommand(standupNoteCmd)
+	standupCmd.AddCommand(standupPromptCmd)
+	standupCmd.AddCommand(standupPublishCmd)
+	standupCmd.AddCommand(standupTickCmd)
+	rootCmd.AddCommand(standupCmd)
+}
+
+func runStandup(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var report *standup.Report
+	if standupLast {
+		report, err = standup.LoadLatest(townRoot)
+		if err != nil {
+			return fmt.Errorf("loading last report: %w", err)
+		}
+		if report == nil {
+			return fmt.Errorf("no standup published yet (run: gt standup publish)")
+		}
+	} else {
+		report, err = compileStandup(townRoot, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	if standupJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printStandup(report)
+	return nil
+}
+
+func runStandupNote(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	agent := mail.AddressToIdentity(detectSender())
+	note, err := standup.RecordNote(townRoot, agent, strings.Join(args, " "), time.Now())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Standup note recorded for %s\n", style.Bold.Render("✓"), agent)
+	fmt.Printf("  %s\n", note.Text)
+	return nil
+}
+
+func runStandupPrompt(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return promptStandup(townRoot)
+}
+
+func runStandupPublish(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return publishStandup(townRoot, time.Now())
+}
+
+func runStandupTick(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	state, err := standup.LoadState(townRoot)
+	if err != nil {
+		return fmt.Errorf("loading standup state: %w", err)
+	}
+	now := time.Now()
+	prompt, publish, err := standup.Due(now, standupAt, standupLead, state)
+	if err != nil {
+		return err
+	}
+
+	if prompt {
+		// Record the attempt even if some nudges fail, so a flaky session
+		// doesn't get re-prompted every tick.
+		state.LastPrompt = now
+		if err := state.Save(townRoot); err != nil {
+			return fmt.Errorf("saving standup state: %w", err)
+		}
+		if err := promptStandup(townRoot); err != nil {
+			style.PrintWarning("standup prompt: %v", err)
+		}
+	}
+	if publish {
+		if err := publishStandup(townRoot, now); err != nil {
+			return err
+		}
+		state.LastPublish = now
+		if err := state.Save(townRoot); err != nil {
+			return fmt.Errorf("saving standup state: %w", err)
+		}
+	}
+	return nil
+}
+
+// standupWorkers returns the running polecat and crew sessions.
+func standupWorkers() ([]*AgentSession, error) {
+	agents, err := getAgentSessions(true)
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	var workers []*AgentSession
+	for _, agent := range agents {
+		if agent.Type == AgentCrew || agent.Type == AgentPolecat {
+			workers = append(workers, agent)
+		}
+	}
+	return workers, nil
+}
+
+func compileStandup(townRoot string, now time.Time) (*standup.Report, error) {
+	workers, err := standupWorkers()
+	if err != nil {
+		return nil, err
+	}
+	notes, err := standup.LoadNotes(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("loading standup notes: %w", err)
+	}
+	return buildStandupReport(townRoot, workers, notes, now, now.Add(-standupSince)), nil
+}
+
+// buildStandupReport assembles one entry per worker. Notes recorded before
+// since are treated as stale and replaced by a pane summary.
+func buildStandupReport(townRoot string, workers []*AgentSession, notes map[string]standup.Note, now, since time.Time) *standup.Report {
+	report := &standup.Report{GeneratedAt: now, Since: since, Entries: make([]standup.Entry, 0, len(workers))}
+	for _, agent := range workers {
+		name := formatAgentName(agent)
+		entry := standup.Entry{Agent: name, Rig: agent.Rig, Role: constants.RolePolecat}
+		if agent.Type == AgentCrew {
+			entry.Role = constants.RoleCrew
+		}
+		entry.Hook, entry.HookTitle = standupHookedWorkFn(townRoot, agent.Rig, standupAssignee(agent))
+
+		if note, ok := notes[mail.AddressToIdentity(name)]; ok && !note.At.Before(since) {
+			at := note.At
+			entry.Note = note.Text
+			entry.NoteAt = &at
+		} else if lines, err := standupCapturePaneFn(agent.Name); err == nil {
+			entry.Activity = standup.SummarizePane(lines)
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+	report.Sort()
+	return report
+}
+
+// standupAssignee returns the bead assignee form of a worker's identity.
+func standupAssignee(agent *AgentSession) string {
+	if agent.Type == AgentCrew {
+		return fmt.Sprintf("%s/crew/%s", agent.Rig, agent.AgentName)
+	}
+	return fmt.Sprintf("%s/polecats/%s", agent.Rig, agent.AgentName)
+}
+
+func promptStandup(townRoot string) error {
+	workers, err := standupWorkers()
+	if err != nil {
+		return err
+	}
+	if len(workers) == 0 {
+		fmt.Println("No workers running to prompt.")
+		return nil
+	}
+
+	t := tmux.NewTmux()
+	var prompted, skipped, failed int
+	for i, agent := range workers {
+		name := formatAgentName(agent)
+		if shouldSend, level, _ := shouldNudgeTarget(townRoot, name, false); !shouldSend {
+			skipped++
+			fmt.Printf("  %s %s %s (DND: %s)\n", style.Dim.Render("○"), AgentTypeIcons[agent.Type], name, level)
+			continue
+		}
+		if err := t.NudgeSession(agent.Name, standupPromptText); err != nil {
+			failed++
+			fmt.Printf("  %s %s %s: %v\n", style.ErrorPrefix, AgentTypeIcons[agent.Type], name, err)
+		} else {
+			prompted++
+			fmt.Printf("  %s %s %s\n", style.SuccessPrefix, AgentTypeIcons[agent.Type], name)
+		}
+		// Small delay between nudges to avoid overwhelming tmux
+		if i < len(workers)-1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	summary := fmt.Sprintf("Standup prompt sent to %d worker(s)", prompted)
+	if skipped > 0 {
+		summary += fmt.Sprintf(", %d skipped (DND)", skipped)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%s, %d failed", summary, failed)
+	}
+	fmt.Printf("%s %s\n", style.SuccessPrefix, summary)
+	return nil
+}
+
+func publishStandup(townRoot string, now time.Time) error {
+	report, err := compileStandup(townRoot, now)
+	if err != nil {
+		return err
+	}
+	if err := report.Save(townRoot); err != nil {
+		return fmt.Errorf("saving report: %w", err)
+	}
+
+	subject := "Standup " + now.Local().Format("2006-01-02")
+	msg := mail.NewMessage(detectSender(), standupTo, subject, report.Markdown())
+	msg.ThreadID = generateThreadID()
+	router := mail.NewRouter(townRoot)
+	defer router.WaitPendingNotifications()
+	if err := router.Send(msg); err != nil {
+		return fmt.Errorf("mailing report: %w", err)
+	}
+	fmt.Printf("%s Standup published to %s (%d agents)\n", style.Bold.Render("✓"), standupTo, len(report.Entries))
+	return nil
+}
+
+func printStandup(report *standup.Report) {
+	fmt.Printf("%s Standup — %s\n", style.Bold.Render("☕"), report.GeneratedAt.Local().Format("Mon Jan 2 15:04"))
+	if len(report.Entries) == 0 {
+		fmt.Printf("\n  %s\n", style.Dim.Render("No workers running"))
+		return
+	}
+	rig := "\x00"
+	for _, e := range report.Entries {
+		if e.Rig != rig {
+			rig = e.Rig
+			fmt.Printf("\n%s\n", style.Bold.Render(rig))
+		}
+		icon := AgentTypeIcons[AgentPolecat]
+		if e.Role == constants.RoleCrew {
+			icon = AgentTypeIcons[AgentCrew]
+		}
+		line := fmt.Sprintf("  %s %s", icon, e.Agent)
+		if e.Hook != "" {
+			line += style.Dim.Render(" [" + e.Hook + "]")
+		}
+		fmt.Println(line)
+		switch {
+		case e.Note != "":
+			fmt.Printf("      %s %s\n", e.Note, style.Dim.Render("("+formatWorkerAge(report.GeneratedAt.Sub(*e.NoteAt))+" ago)"))
+		case e.Activity != "":
+			fmt.Printf("      %s %s\n", style.Warning.Render("no note"), style.Dim.Render("— last seen: "+e.Activity))
+		default:
+			fmt.Printf("      %s\n", style.Warning.Render("no note"))
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/standup"
+)
+
+func TestBuildStandupReport(t *testing.T) {
+	origCapture, origHook := standupCapturePaneFn, standupHookedWorkFn
+	t.Cleanup(func() { standupCapturePaneFn, standupHookedWorkFn = origCapture, origHook })
+
+	var captured []string
+	standupCapturePaneFn = func(session string) ([]string, error) {
+		captured = append(captured, session)
+		if session == "gt-gastown-Nux" {
+			return nil, errors.New("no pane")
+		}
+		return []string{"⏺ Editing internal/auth/token.go", "  ? for shortcuts"}, nil
+	}
+	var assignees []string
+	standupHookedWorkFn = func(townRoot, rig, assignee string) (string, string) {
+		assignees = append(assignees, assignee)
+		if assignee == "gastown/polecats/Toast" {
+			return "gt-abc", "Fix auth"
+		}
+		return "", ""
+	}
+
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	since := now.Add(-24 * time.Hour)
+	workers := []*AgentSession{
+		{Name: "gt-gastown-Toast", Type: AgentPolecat, Rig: "gastown", AgentName: "Toast"},
+		{Name: "gt-gastown-crew-max", Type: AgentCrew, Rig: "gastown", AgentName: "max"},
+		{Name: "gt-gastown-Nux", Type: AgentPolecat, Rig: "gastown", AgentName: "Nux"},
+	}
+	notes := map[string]standup.Note{
+		"gastown/Toast": {Agent: "gastown/Toast", Text: "tests green", At: now.Add(-time.Hour)},
+		// Crew notes are keyed by normalized identity; this one is stale.
+		"gastown/max": {Agent: "gastown/max", Text: "old news", At: now.Add(-48 * time.Hour)},
+	}
+
+	report := buildStandupReport("/town", workers, notes, now, since)
+	if len(report.Entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(report.Entries))
+	}
+	byAgent := make(map[string]standup.Entry)
+	for _, e := range report.Entries {
+		byAgent[e.Agent] = e
+	}
+
+	toast := byAgent["gastown/Toast"]
+	if toast.Note != "tests green" || toast.Hook != "gt-abc" || toast.Activity != "" {
+		t.Errorf("Toast entry = %+v, want fresh note and hook", toast)
+	}
+	max := byAgent["gastown/crew/max"]
+	if max.Note != "" || max.Activity != "Editing internal/auth/token.go" || max.Role != "crew" {
+		t.Errorf("max entry = %+v, want stale note replaced by pane summary", max)
+	}
+	if nux := byAgent["gastown/Nux"]; nux.Note != "" || nux.Activity != "" {
+		t.Errorf("Nux entry = %+v, want no note or activity", nux)
+	}
+
+	// Fresh notes skip the pane capture.
+	for _, s := range captured {
+		if s == "gt-gastown-Toast" {
+			t.Error("captured pane for agent with a fresh note")
+		}
+	}
+	wantAssignees := map[string]bool{"gastown/polecats/Toast": true, "gastown/crew/max": true, "gastown/polecats/Nux": true}
+	for _, a := range assignees {
+		if !wantAssignees[a] {
+			t.Errorf("unexpected hook assignee %q", a)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"os"
@@ -99,7 +98,8 @@ Shutdown levels (progressively more aggressive):
   --all           - Also stop crew sessions
   --polecats-only - Only stop polecats (leaves infrastructure running)
 
-Use --force or --yes to skip confirmation prompt.
+Use --force or --yes to skip confirmation prompt, unless the town's
+confirmation policy refuses --yes (see gt confirm).
 Use --graceful to allow agents time to save state before killing.
 Use --nuclear to force cleanup even if polecats have uncommitted work (DANGER).
 Use --cleanup-orphans to use a longer grace period for orphan cleanup (default 60s).
@@ -539,16 +539,12 @@ func runShutdown(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
-	// Confirmation prompt
-	if !shutdownYes && !shutdownForce {
-		fmt.Printf("Proceed with shutdown? [y/N] ")
-		reader := bufio.NewReader(os.Stdin)
-		response, _ := reader.ReadString('\n')
-		response = strings.TrimSpace(strings.ToLower(response))
-		if response != "y" && response != "yes" {
-			fmt.Println("Shutdown canceled.")
-			return nil
-		}
+	// Confirmation per the town's policy
+	if proceed, err := confirmDestructive("shutdown", "", shutdownYes || shutdownForce); err != nil {
+		return err
+	} else if !proceed {
+		fmt.Println("Shutdown canceled.")
+		return nil
 	}
 
 	if shutdownGraceful {
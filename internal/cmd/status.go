@@ -15,6 +15,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/buildcache"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/crew"
@@ -23,8 +24,11 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/ping"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/scorecard"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/shard"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -67,6 +71,7 @@ type TownStatus struct {
 	Location string         `json:"location"`
 	Overseer *OverseerInfo  `json:"overseer,omitempty"` // Human operator
 	DND      *DNDInfo       `json:"dnd,omitempty"`      // Current agent DND status
+	Journal  *JournalInfo   `json:"journal,omitempty"`  // Commands journaled while offline
 	Daemon   *ServiceInfo   `json:"daemon,omitempty"`   // Daemon status
 	Dolt     *DoltInfo      `json:"dolt,omitempty"`     // Dolt server status
 	Tmux     *TmuxInfo      `json:"tmux,omitempty"`     // Tmux server status
@@ -74,6 +79,10 @@ type TownStatus struct {
 	Agents   []AgentRuntime `json:"agents"`             // Global agents (Mayor, Deacon)
 	Rigs     []RigStatus    `json:"rigs"`
 	Summary  StatusSum      `json:"summary"`
+
+	// ShardedMayors is set when shard mayors split the rigs; the primary
+	// Mayor then acts as super-coordinator over them.
+	ShardedMayors bool `json:"sharded_mayors,omitempty"`
 }
 
 // ServiceInfo represents a background service status.
@@ -118,37 +127,50 @@ type DNDInfo struct {
 	Agent   string `json:"agent,omitempty"`
 }
 
+// JournalInfo summarizes commands waiting for gt sync.
+type JournalInfo struct {
+	Pending int       `json:"pending"`
+	Oldest  time.Time `json:"oldest"`
+}
+
 // AgentRuntime represents the runtime state of an agent.
 type AgentRuntime struct {
-	Name         string `json:"name"`                    // Display name (e.g., "mayor", "witness")
-	Address      string `json:"address"`                 // Full address (e.g., "greenplace/witness")
-	Session      string `json:"session"`                 // tmux session name
-	Role         string `json:"role"`                    // Role type
-	Running      bool   `json:"running"`                 // Is tmux session running?
-	ACP          bool   `json:"acp"`                     // Is ACP session active?
-	HasWork      bool   `json:"has_work"`                // Has pinned work?
-	WorkTitle    string `json:"work_title,omitempty"`    // Title of pinned work
-	HookBead     string `json:"hook_bead,omitempty"`     // Pinned bead ID from agent bead
-	State             string `json:"state,omitempty"`              // Agent state from agent bead
-	NotificationLevel string `json:"notification_level,omitempty"` // Notification level (verbose, normal, muted)
-	UnreadMail        int    `json:"unread_mail"`                  // Number of unread messages
-	FirstSubject      string `json:"first_subject,omitempty"`      // Subject of first unread message
-	AgentAlias        string `json:"agent_alias,omitempty"`        // Configured agent name (e.g., "opus-46", "pi")
-	AgentInfo         string `json:"agent_info,omitempty"`         // Runtime summary (e.g., "claude/opus", "pi/kimi-k2p5")
+	Name              string      `json:"name"`                         // Display name (e.g., "mayor", "witness")
+	Address           string      `json:"address"`                      // Full address (e.g., "greenplace/witness")
+	Session           string      `json:"session"`                      // tmux session name
+	Role              string      `json:"role"`                         // Role type
+	Running           bool        `json:"running"`                      // Is tmux session running?
+	ACP               bool        `json:"acp"`                          // Is ACP session active?
+	HasWork           bool        `json:"has_work"`                     // Has pinned work?
+	WorkTitle         string      `json:"work_title,omitempty"`         // Title of pinned work
+	HookBead          string      `json:"hook_bead,omitempty"`          // Pinned bead ID from agent bead
+	State             string      `json:"state,omitempty"`              // Agent state from agent bead
+	NotificationLevel string      `json:"notification_level,omitempty"` // Notification level (verbose, normal, muted)
+	UnreadMail        int         `json:"unread_mail"`                  // Number of unread messages
+	FirstSubject      string      `json:"first_subject,omitempty"`      // Subject of first unread message
+	OldestUnread      *time.Time  `json:"oldest_unread,omitempty"`      // Sent time of the oldest unread message
+	AwaitingAck       int         `json:"awaiting_ack,omitempty"`       // Unread messages sent with --require-ack
+	AgentAlias        string      `json:"agent_alias,omitempty"`        // Configured agent name (e.g., "opus-46", "pi")
+	AgentInfo         string      `json:"agent_info,omitempty"`         // Runtime summary (e.g., "claude/opus", "pi/kimi-k2p5")
+	Rigs              []string    `json:"rigs,omitempty"`               // Rigs coordinated directly (sharded mayors only)
+	Ping              *ping.Stats `json:"ping,omitempty"`               // gt ping responsiveness over the last day
 }
 
 // RigStatus represents status of a single rig.
 type RigStatus struct {
-	Name         string          `json:"name"`
-	Polecats     []string        `json:"polecats"`
-	PolecatCount int             `json:"polecat_count"`
-	Crews        []string        `json:"crews"`
-	CrewCount    int             `json:"crew_count"`
-	HasWitness   bool            `json:"has_witness"`
-	HasRefinery  bool            `json:"has_refinery"`
-	Hooks        []AgentHookInfo `json:"hooks,omitempty"`
-	Agents       []AgentRuntime  `json:"agents,omitempty"` // Runtime state of all agents in rig
-	MQ           *MQSummary      `json:"mq,omitempty"`     // Merge queue summary
+	Name         string            `json:"name"`
+	Polecats     []string          `json:"polecats"`
+	PolecatCount int               `json:"polecat_count"`
+	Crews        []string          `json:"crews"`
+	CrewCount    int               `json:"crew_count"`
+	HasWitness   bool              `json:"has_witness"`
+	HasRefinery  bool              `json:"has_refinery"`
+	Hooks        []AgentHookInfo   `json:"hooks,omitempty"`
+	Agents       []AgentRuntime    `json:"agents,omitempty"`      // Runtime state of all agents in rig
+	MQ           *MQSummary        `json:"mq,omitempty"`          // Merge queue summary
+	Mayor        string            `json:"mayor,omitempty"`       // Coordinating mayor when mayors are sharded
+	BuildCache   *buildcache.Stats `json:"build_cache,omitempty"` // Shared build caches, if configured
+	Scorecards   []scorecard.Card  `json:"scorecards,omitempty"`  // Last 7 days of polecat work, by agent config
 }
 
 // MQSummary represents the merge queue status for a rig.
@@ -787,6 +809,7 @@ func gatherStatus() (TownStatus, error) {
 		Location: townRoot,
 		Overseer: overseerInfo,
 		DND:      detectCurrentDNDStatus(townRoot),
+		Journal:  loadJournalInfo(townRoot),
 		Rigs:     make([]RigStatus, len(rigs)),
 	}
 
@@ -908,18 +931,42 @@ func gatherStatus() (TownStatus, error) {
 				rs.MQ = getMQSummary(r)
 			}
 
+			// Shared build cache sizes (measurements are reused for a while)
+			if !statusFast {
+				rs.BuildCache = buildcache.Load(r.Path, config.ResolveBuildCache(r.Path))
+			}
+
 			status.Rigs[idx] = rs
 		}(i, r)
 	}
 
 	wg.Wait()
 
+	// Polecat scorecards per agent config (skipped in --fast mode: reads the
+	// event and cost logs)
+	if !statusFast {
+		if cards, err := loadScorecards(townRoot, time.Now().Add(-scorecardStatusWindow)); err == nil {
+			for i := range status.Rigs {
+				status.Rigs[i].Scorecards = scorecard.Group(scorecardsForRig(cards, status.Rigs[i].Name), scorecard.ByConfig)
+			}
+		}
+	}
+
+	if shards := config.LoadMayorShards(townRoot); shards != nil {
+		assignMayorShards(&status, shards)
+	}
+
 	// Enrich agents with runtime info — inspect actual running processes
+	var pingStats map[string]*ping.Stats
+	if probes, err := ping.List(townRoot, time.Now().Add(-24*time.Hour)); err == nil {
+		pingStats = ping.Summarize(probes)
+	}
 	for i := range status.Agents {
 		a := &status.Agents[i]
 		alias, info := resolveAgentDisplay(townRoot, townSettings, a.Role, a.Session, a.Running)
 		a.AgentAlias = alias
 		a.AgentInfo = info
+		a.Ping = pingStats[pingAgentForStatus(a.Address)]
 	}
 	for i := range status.Rigs {
 		for j := range status.Rigs[i].Agents {
@@ -927,6 +974,7 @@ func gatherStatus() (TownStatus, error) {
 			alias, info := resolveAgentDisplay(townRoot, townSettings, a.Role, a.Session, a.Running)
 			a.AgentAlias = alias
 			a.AgentInfo = info
+			a.Ping = pingStats[pingAgentForStatus(a.Address)]
 		}
 	}
 
@@ -947,6 +995,46 @@ func gatherStatus() (TownStatus, error) {
 	return status, nil
 }
 
+// assignMayorShards records which mayor coordinates each rig, and the rigs
+// each mayor coordinates directly: its shard's rigs, or for the primary
+// Mayor, the rigs outside every shard.
+func assignMayorShards(status *TownStatus, shards *shard.Config) {
+	status.ShardedMayors = true
+	direct := make(map[string][]string)
+	for i := range status.Rigs {
+		rs := &status.Rigs[i]
+		rs.Mayor = shard.PrimaryAddress
+		if sh := shards.ForRig(rs.Name); sh != nil {
+			rs.Mayor = sh.Address()
+		}
+		direct[rs.Mayor] = append(direct[rs.Mayor], rs.Name)
+	}
+	for i := range status.Agents {
+		a := &status.Agents[i]
+		if sh := shards.Get(strings.TrimPrefix(a.Address, "mayor/")); sh != nil {
+			a.Rigs = sh.Rigs
+		} else if a.Address == shard.PrimaryAddress {
+			a.Rigs = direct[shard.PrimaryAddress]
+		}
+	}
+}
+
+// mayorShardSuffix describes a mayor's domain in the compact status line
+// of a town with sharded mayors.
+func mayorShardSuffix(agent AgentRuntime, sharded bool) string {
+	if !sharded || agent.Role != "coordinator" {
+		return ""
+	}
+	suffix := ""
+	if agent.Address == shard.PrimaryAddress {
+		suffix = " super-coordinator"
+	}
+	if len(agent.Rigs) > 0 {
+		suffix += " rigs: " + strings.Join(agent.Rigs, ", ")
+	}
+	return style.Dim.Render(suffix)
+}
+
 func outputStatusJSON(status TownStatus) error {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
@@ -990,6 +1078,12 @@ func outputStatusText(w io.Writer, status TownStatus) error {
 		fmt.Fprintf(w, "\n   %s\n\n", style.Dim.Render(desc))
 	}
 
+	if status.Journal != nil {
+		fmt.Fprintf(w, "📓 %s %d command(s) waiting %s\n   %s\n\n", style.Bold.Render("Offline journal:"), status.Journal.Pending,
+			style.Dim.Render("(oldest "+formatDurationAgo(time.Since(status.Journal.Oldest))+")"),
+			style.Dim.Render("run 'gt sync' to apply"))
+	}
+
 	// Infrastructure services
 	if status.Daemon != nil || status.Dolt != nil || status.Tmux != nil {
 		fmt.Fprintf(w, "%s ", style.Bold.Render("Services:"))
@@ -1053,13 +1147,14 @@ func outputStatusText(w io.Writer, status TownStatus) error {
 		if icon == "" {
 			icon = roleIcons[agent.Name]
 		}
+		shardSuffix := mayorShardSuffix(agent, status.ShardedMayors)
 		if statusVerbose {
-			fmt.Fprintf(w, "%s %s\n", icon, style.Bold.Render(capitalizeFirst(agent.Name)))
+			fmt.Fprintf(w, "%s %s%s\n", icon, style.Bold.Render(capitalizeFirst(agent.Name)), shardSuffix)
 			renderAgentDetails(w, agent, "   ", nil, status.Location)
 			fmt.Fprintln(w)
 		} else {
 			// Compact: icon + name on one line
-			renderAgentCompact(w, agent, icon+" ", nil, status.Location)
+			renderAgentCompactWithSuffix(w, agent, icon+" ", nil, status.Location, shardSuffix)
 		}
 	}
 	if !statusVerbose && len(status.Agents) > 0 {
@@ -1074,7 +1169,11 @@ func outputStatusText(w io.Writer, status TownStatus) error {
 	// Rigs
 	for _, r := range status.Rigs {
 		// Rig header with separator
-		fmt.Fprintf(w, "─── %s ───────────────────────────────────────────\n\n", style.Bold.Render(r.Name+"/"))
+		mayorNote := ""
+		if r.Mayor != "" {
+			mayorNote = " " + style.Dim.Render("("+r.Mayor+")")
+		}
+		fmt.Fprintf(w, "─── %s%s ───────────────────────────────────────────\n\n", style.Bold.Render(r.Name+"/"), mayorNote)
 
 		// Group agents by role
 		var witnesses, refineries, crews, polecats []AgentRuntime
@@ -1172,6 +1271,13 @@ func outputStatusText(w io.Writer, status TownStatus) error {
 		if len(witnesses) == 0 && len(refineries) == 0 && len(crews) == 0 && len(polecats) == 0 {
 			fmt.Fprintf(w, "   %s\n", style.Dim.Render("(no agents)"))
 		}
+
+		if cacheStr := formatBuildCache(r.BuildCache); cacheStr != "" {
+			fmt.Fprintf(w, "   %s\n", style.Dim.Render(cacheStr))
+		}
+		for _, c := range r.Scorecards {
+			fmt.Fprintf(w, "   %s\n", style.Dim.Render(formatScorecard(c)))
+		}
 		fmt.Fprintln(w)
 	}
 
@@ -1280,11 +1386,29 @@ func renderAgentDetails(w io.Writer, agent AgentRuntime, indent string, hooks []
 	// Line 5: Mail (if any unread)
 	if agent.UnreadMail > 0 {
 		mailStr := fmt.Sprintf("📬 %d unread", agent.UnreadMail)
+		if agent.OldestUnread != nil {
+			mailStr += fmt.Sprintf(", oldest %s", formatWorkerAge(time.Since(*agent.OldestUnread)))
+		}
+		if agent.AwaitingAck > 0 {
+			mailStr += fmt.Sprintf(", %d awaiting ack", agent.AwaitingAck)
+		}
 		if agent.FirstSubject != "" {
-			mailStr = fmt.Sprintf("📬 %d unread → %s", agent.UnreadMail, truncateWithEllipsis(agent.FirstSubject, 35))
+			mailStr += " → " + truncateWithEllipsis(agent.FirstSubject, 35)
 		}
 		fmt.Fprintf(w, "%s  mail: %s\n", indent, mailStr)
 	}
+
+	// Line 6: Responsiveness (if pinged recently)
+	if p := agent.Ping; p != nil {
+		pingStr := fmt.Sprintf("p50 %s, p90 %s, p99 %s over %d", formatPingLatency(p.P50), formatPingLatency(p.P90), formatPingLatency(p.P99), p.Probes)
+		if p.Timeouts > 0 {
+			pingStr += fmt.Sprintf(", %d timed out", p.Timeouts)
+		}
+		if p.Dead > 0 {
+			pingStr += fmt.Sprintf(", %d dead", p.Dead)
+		}
+		fmt.Fprintf(w, "%s  ping: %s\n", indent, pingStr)
+	}
 }
 
 // formatMQSummary formats the MQ status for verbose display
@@ -1321,6 +1445,24 @@ func formatMQSummary(mq *MQSummary) string {
 	return fmt.Sprintf("%s %s%s", stateIcon, strings.Join(mqParts, ", "), healthSuffix)
 }
 
+// formatBuildCache summarizes shared cache sizes, e.g.
+// "cache: 2.1 GB (go 1.9 GB, pnpm 210.0 MB)".
+func formatBuildCache(stats *buildcache.Stats) string {
+	if stats == nil {
+		return ""
+	}
+	var parts []string
+	for _, c := range stats.Caches {
+		if c.Bytes > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s", c.Cache, formatBytes(c.Bytes)))
+		}
+	}
+	if len(parts) == 0 {
+		return "cache: empty"
+	}
+	return fmt.Sprintf("cache: %s (%s)", formatBytes(stats.Bytes), strings.Join(parts, ", "))
+}
+
 // formatMQSummaryCompact formats MQ status for compact single-line display
 func formatMQSummaryCompact(mq *MQSummary) string {
 	if mq == nil {
@@ -1373,6 +1515,7 @@ func renderAgentCompactWithSuffix(w io.Writer, agent AgentRuntime, indent string
 	if agent.UnreadMail > 0 {
 		mailSuffix = fmt.Sprintf(" 📬%d", agent.UnreadMail)
 	}
+	mailSuffix += pingWarningSuffix(agent)
 
 	// Agent runtime info
 	agentSuffix := ""
@@ -1384,6 +1527,14 @@ func renderAgentCompactWithSuffix(w io.Writer, agent AgentRuntime, indent string
 	fmt.Fprintf(w, "%s%-12s %s%s%s%s%s\n", indent, agent.Name, statusIndicator, agentSuffix, hookSuffix, mailSuffix, suffix)
 }
 
+// pingWarningSuffix flags an agent whose most recent gt ping failed.
+func pingWarningSuffix(agent AgentRuntime) string {
+	if agent.Ping == nil || agent.Ping.Last == ping.ResultOK {
+		return ""
+	}
+	return " " + style.Warning.Render("[ping: "+agent.Ping.Last+"]")
+}
+
 // renderAgentCompact renders a single-line agent status
 func renderAgentCompact(w io.Writer, agent AgentRuntime, indent string, hooks []AgentHookInfo, _ string) {
 	// Build status indicator (gt-zecmc: use tmux state, not bead state)
@@ -1419,6 +1570,7 @@ func renderAgentCompact(w io.Writer, agent AgentRuntime, indent string, hooks []
 	if agent.UnreadMail > 0 {
 		mailSuffix = fmt.Sprintf(" 📬%d", agent.UnreadMail)
 	}
+	mailSuffix += pingWarningSuffix(agent)
 
 	// Agent runtime info
 	agentSuffix := ""
@@ -1556,6 +1708,18 @@ func discoverGlobalAgents(townRoot string, allSessions map[string]bool, allAgent
 		{constants.RoleMayor, constants.RoleMayor + "/", mayorSession, "coordinator", beads.MayorBeadIDTown()},
 		{constants.RoleDeacon, constants.RoleDeacon + "/", deaconSession, "health-check", beads.DeaconBeadIDTown()},
 	}
+	// Shard mayors have no agent bead; session and mail carry their state.
+	if shards := config.LoadMayorShards(townRoot); shards != nil {
+		for _, sh := range shards.Shards {
+			agentDefs = append(agentDefs, struct {
+				name    string
+				address string
+				session string
+				role    string
+				beadID  string
+			}{sh.Address(), sh.Address(), session.MayorShardSessionName(sh.Name), "coordinator", ""})
+		}
+	}
 
 	agents := make([]AgentRuntime, len(agentDefs))
 	var wg sync.WaitGroup
@@ -1624,7 +1788,8 @@ func discoverGlobalAgents(townRoot string, allSessions map[string]bool, allAgent
 	return agents
 }
 
-// populateMailInfo fetches unread mail count and first subject for an agent
+// populateMailInfo fetches the unread mail backlog for an agent: count,
+// first subject, oldest message and how many senders await an ack.
 func populateMailInfo(agent *AgentRuntime, router *mail.Router) {
 	if router == nil {
 		return
@@ -1638,6 +1803,21 @@ func populateMailInfo(agent *AgentRuntime, router *mail.Router) {
 	if unread > 0 {
 		if messages, err := mailbox.ListUnread(); err == nil && len(messages) > 0 {
 			agent.FirstSubject = messages[0].Subject
+			summarizeMailBacklog(agent, messages)
+		}
+	}
+}
+
+// summarizeMailBacklog records the oldest unread message and the number
+// of unread messages whose sender required an ack.
+func summarizeMailBacklog(agent *AgentRuntime, unread []*mail.Message) {
+	for _, msg := range unread {
+		if agent.OldestUnread == nil || msg.Timestamp.Before(*agent.OldestUnread) {
+			ts := msg.Timestamp
+			agent.OldestUnread = &ts
+		}
+		if msg.AckRequired {
+			agent.AwaitingAck++
 		}
 	}
 }
@@ -9,8 +9,10 @@ import (
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/buildcache"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/shard"
 )
 
 func captureStdout(t *testing.T, fn func()) string {
@@ -230,6 +232,39 @@ func TestOutputStatusText_IncludesDNDSection(t *testing.T) {
 	}
 }
 
+func TestOutputStatusText_ShardedMayors(t *testing.T) {
+	status := TownStatus{
+		Name:     "gt",
+		Location: "/tmp/gt",
+		Agents: []AgentRuntime{
+			{Name: "mayor", Address: "mayor/", Session: "hq-mayor", Role: "coordinator", Running: true},
+			{Name: "mayor/web", Address: "mayor/web", Session: "hq-mayor-web", Role: "coordinator"},
+		},
+		Rigs: []RigStatus{{Name: "frontend"}, {Name: "gastown"}},
+	}
+	assignMayorShards(&status, &shard.Config{Shards: []shard.Shard{
+		{Name: "web", Rigs: []string{"frontend"}},
+	}})
+
+	if status.Rigs[0].Mayor != "mayor/web" || status.Rigs[1].Mayor != "mayor/" {
+		t.Errorf("rig mayors = %q, %q", status.Rigs[0].Mayor, status.Rigs[1].Mayor)
+	}
+	if got := status.Agents[0].Rigs; len(got) != 1 || got[0] != "gastown" {
+		t.Errorf("primary mayor rigs = %v, want [gastown]", got)
+	}
+
+	var buf bytes.Buffer
+	if err := outputStatusText(&buf, status); err != nil {
+		t.Fatalf("outputStatusText error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"super-coordinator", "rigs: frontend", "(mayor/web)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in status output, got: %q", want, out)
+		}
+	}
+}
+
 func TestRunStatusWatch_RejectsZeroInterval(t *testing.T) {
 	oldInterval := statusInterval
 	oldWatch := statusWatch
@@ -574,3 +609,20 @@ func TestExtractBaseName(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatBuildCache(t *testing.T) {
+	if got := formatBuildCache(nil); got != "" {
+		t.Errorf("nil stats = %q, want empty", got)
+	}
+	empty := &buildcache.Stats{Caches: []buildcache.Stat{{Cache: "go"}}}
+	if got := formatBuildCache(empty); got != "cache: empty" {
+		t.Errorf("empty caches = %q", got)
+	}
+	stats := &buildcache.Stats{
+		Bytes:  3 << 20,
+		Caches: []buildcache.Stat{{Cache: "go", Bytes: 2 << 20}, {Cache: "npm"}, {Cache: "pnpm", Bytes: 1 << 20}},
+	}
+	if got := formatBuildCache(stats); got != "cache: 3.0 MB (go 2.0 MB, pnpm 1.0 MB)" {
+		t.Errorf("formatBuildCache = %q", got)
+	}
+}
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/journal"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	syncList      bool
+	syncDrop      []string
+	syncKeepGoing bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:     "sync",
+	GroupID: GroupWork,
+	Short:   "Apply commands journaled while offline",
+	Long: `Replay gt commands that were journaled because bd, the network or the
+agent runtime was unavailable.
+
+These commands journal instead of failing when they hit an outage:
+  gt close, gt mail send, gt escalate, gt remember
+
+Set GT_OFFLINE=1 to journal them without trying (e.g. on a plane, to skip
+connection timeouts). Journaled commands are kept in
+<town>/.runtime/journal.jsonl with their working directory and GT_*/BD_*
+environment, and are replayed oldest first. Replay stops at the first
+failure so later commands don't run out of order; a command that is still
+offline stays journaled.
+
+Examples:
+  gt sync                   # Replay everything journaled
+  gt sync --list            # Show journaled commands without running them
+  gt sync --drop <id>       # Discard a journaled command
+  gt sync --keep-going      # Continue past failed commands`,
+	RunE: runSync,
+}
+
+func init() {
+	syncCmd.Flags().BoolVar(&syncList, "list", false, "List journaled commands without running them")
+	syncCmd.Flags().StringSliceVar(&syncDrop, "drop", nil, "Discard journaled commands by ID (repeatable)")
+	syncCmd.Flags().BoolVar(&syncKeepGoing, "keep-going", false, "Continue replaying after a command fails")
+	rootCmd.AddCommand(syncCmd)
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	if len(syncDrop) > 0 {
+		if err := journal.Remove(townRoot, syncDrop...); err != nil {
+			return err
+		}
+		fmt.Printf("%s Dropped %d journaled command(s)\n", style.Success.Render("✓"), len(syncDrop))
+		return nil
+	}
+
+	entries, err := journal.List(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("Nothing journaled.")
+		return nil
+	}
+	if syncList {
+		printJournal(os.Stdout, entries)
+		return nil
+	}
+	if os.Getenv(journal.EnvOffline) != "" {
+		return fmt.Errorf("%s is set; unset it to replay %d journaled command(s)", journal.EnvOffline, len(entries))
+	}
+
+	applied, failed := 0, 0
+	for _, e := range entries {
+		fmt.Printf("→ %s\n", e.Command())
+		if err := replayJournalEntry(e); err != nil {
+			failed++
+			_ = journal.RecordFailure(townRoot, e.ID, err)
+			fmt.Printf("  %s %v\n", style.Error.Render("✗"), err)
+			if journal.IsOffline(err) {
+				fmt.Printf("  Still offline; %d command(s) left journaled.\n", len(entries)-applied)
+				return NewSilentExit(1)
+			}
+			if !syncKeepGoing {
+				fmt.Printf("  Stopped. Fix and rerun 'gt sync', skip with --keep-going, or discard with 'gt sync --drop %s'.\n", e.ID)
+				return NewSilentExit(1)
+			}
+			continue
+		}
+		if err := journal.Remove(townRoot, e.ID); err != nil {
+			return err
+		}
+		applied++
+	}
+
+	fmt.Printf("%s Applied %d journaled command(s)", style.Success.Render("✓"), applied)
+	if failed > 0 {
+		fmt.Printf(", %d failed (see 'gt sync --list')", failed)
+	}
+	fmt.Println()
+	if failed > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func printJournal(w io.Writer, entries []journal.Entry) {
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(w, "%s  %s  %s\n", style.Dim.Render(e.ID), e.Time.Local().Format("Jan 2 15:04"), e.Command())
+		_, _ = fmt.Fprintf(w, "    %s %s\n", style.Dim.Render("in"), e.Dir)
+		_, _ = fmt.Fprintf(w, "    %s %s\n", style.Dim.Render("journaled:"), e.Reason)
+		if e.Attempts > 0 {
+			_, _ = fmt.Fprintf(w, "    %s %d, last: %s\n", style.Warning.Render("failed replays:"), e.Attempts, e.LastError)
+		}
+	}
+}
+
+// replayJournalEntry runs a journaled command with the working directory
+// and environment it was journaled with.
+func replayJournalEntry(e journal.Entry) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding gt binary: %w", err)
+	}
+	var stderr bytes.Buffer
+	c := exec.Command(exe, e.Args...) //nolint:gosec // G204: replaying the user's own gt command
+	c.Dir = e.Dir
+	c.Env = os.Environ()
+	for k, v := range e.Env {
+		c.Env = append(filterEnvKey(c.Env, k), k+"="+v)
+	}
+	c.Env = append(c.Env, journal.EnvReplay+"=1")
+	c.Stdout = os.Stdout
+	c.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	if err := c.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, lastStderrLine(msg))
+		}
+		return journal.Offline(err, stderr.String())
+	}
+	return nil
+}
+
+func lastStderrLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// journalArgs returns the gt arguments of the running command. A variable
+// so tests can supply them.
+var journalArgs = func() []string { return os.Args[1:] }
+
+// journaled wraps a command's RunE so an offline failure, or GT_OFFLINE=1,
+// journals the command for gt sync instead of failing. Only commands that
+// take effect in one step should use it: a replay reruns the whole command.
+func journaled(run func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if os.Getenv(journal.EnvReplay) != "" || isHelpRequest(journalArgs()) {
+			return run(cmd, args)
+		}
+		if os.Getenv(journal.EnvOffline) != "" {
+			return journalCommand(journal.EnvOffline + " is set")
+		}
+		err := run(cmd, args)
+		if !journal.IsOffline(err) {
+			return err
+		}
+		if jerr := journalCommand(journal.Reason(err)); jerr != nil {
+			style.PrintWarning("could not journal command: %v", jerr)
+			return err
+		}
+		return nil
+	}
+}
+
+func journalCommand(reason string) error {
+	argv := journalArgs()
+	if slices.Contains(argv, "--stdin") {
+		return fmt.Errorf("offline (%s), and commands reading --stdin can't be journaled", reason)
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	e := journal.NewEntry(argv, cwd, reason)
+	if err := journal.Append(townRoot, e); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%s Offline (%s): journaled %s\n", style.Warning.Render("⚠"), reason, style.Bold.Render(e.Command()))
+	fmt.Fprintf(os.Stderr, "  Run %s when back online.\n", style.Dim.Render("gt sync"))
+	return nil
+}
+
+func isHelpRequest(argv []string) bool {
+	return slices.Contains(argv, "--help") || slices.Contains(argv, "-h")
+}
+
+// runTeeStderr runs c, a bd command, with stderr shown to the user and also
+// captured, so an outage reported by the subprocess can be recognized by
+// journaled.
+func runTeeStderr(c *exec.Cmd) error {
+	var stderr bytes.Buffer
+	c.Stderr = io.MultiWriter(os.Stderr, &stderr)
+	return journal.Offline(beads.RunCmd(c), stderr.String())
+}
+
+// loadJournalInfo summarizes the town's journal for gt status; nil when
+// nothing is journaled.
+func loadJournalInfo(townRoot string) *JournalInfo {
+	entries, err := journal.List(townRoot)
+	if err != nil || len(entries) == 0 {
+		return nil
+	}
+	return &JournalInfo{Pending: len(entries), Oldest: entries[0].Time}
+}
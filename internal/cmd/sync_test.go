@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/journal"
+)
+
+func setupJournalTest(t *testing.T, argv ...string) string {
+	t.Helper()
+	townRoot := setupTestTownForConfig(t)
+	t.Chdir(townRoot)
+	old := journalArgs
+	journalArgs = func() []string { return argv }
+	t.Cleanup(func() { journalArgs = old })
+	t.Setenv(journal.EnvOffline, "")
+	t.Setenv(journal.EnvReplay, "")
+	return townRoot
+}
+
+func TestJournaled_OfflineErrorIsJournaled(t *testing.T) {
+	townRoot := setupJournalTest(t, "close", "gt-abc", "--reason", "done")
+	run := journaled(func(*cobra.Command, []string) error {
+		return journal.Offline(errors.New("exit status 1"), "Error: dial tcp 127.0.0.1:3307: connect: connection refused")
+	})
+	if err := run(closeCmd, nil); err != nil {
+		t.Fatalf("offline failure should be journaled, got %v", err)
+	}
+
+	entries, err := journal.List(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries = %d, want 1", len(entries))
+	}
+	if got := entries[0].Command(); got != "gt close gt-abc --reason done" {
+		t.Errorf("journaled %q", got)
+	}
+	if !strings.Contains(entries[0].Reason, "connection refused") {
+		t.Errorf("Reason = %q", entries[0].Reason)
+	}
+	if info := loadJournalInfo(townRoot); info == nil || info.Pending != 1 {
+		t.Errorf("loadJournalInfo = %+v, want 1 pending", info)
+	}
+}
+
+func TestJournaled_OtherErrorsPassThrough(t *testing.T) {
+	townRoot := setupJournalTest(t, "close", "gt-missing")
+	want := errors.New("bd close: issue gt-missing not found")
+	run := journaled(func(*cobra.Command, []string) error { return want })
+	if err := run(closeCmd, nil); err != want {
+		t.Errorf("err = %v, want %v", err, want)
+	}
+	if info := loadJournalInfo(townRoot); info != nil {
+		t.Errorf("nothing should be journaled, got %+v", info)
+	}
+}
+
+func TestJournaled_ForcedOffline(t *testing.T) {
+	townRoot := setupJournalTest(t, "remember", "the build needs go 1.25")
+	t.Setenv(journal.EnvOffline, "1")
+	ran := false
+	run := journaled(func(*cobra.Command, []string) error { ran = true; return nil })
+	if err := run(rememberCmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ran {
+		t.Error("GT_OFFLINE=1 should journal without running the command")
+	}
+	entries, _ := journal.List(townRoot)
+	if len(entries) != 1 || entries[0].Command() != `gt remember "the build needs go 1.25"` {
+		t.Errorf("entries = %+v", entries)
+	}
+}
+
+func TestJournaled_ReplayDoesNotJournalAgain(t *testing.T) {
+	townRoot := setupJournalTest(t, "close", "gt-abc")
+	t.Setenv(journal.EnvReplay, "1")
+	offline := journal.Offline(errors.New("exit status 1"), "connection refused")
+	run := journaled(func(*cobra.Command, []string) error { return offline })
+	if err := run(closeCmd, nil); err != offline {
+		t.Errorf("replay should return the error, got %v", err)
+	}
+	if info := loadJournalInfo(townRoot); info != nil {
+		t.Errorf("replay should not journal, got %+v", info)
+	}
+}
+
+func TestJournaled_StdinNotJournaled(t *testing.T) {
+	townRoot := setupJournalTest(t, "mail", "send", "mayor/", "-s", "hi", "--stdin")
+	t.Setenv(journal.EnvOffline, "1")
+	run := journaled(func(*cobra.Command, []string) error { return nil })
+	err := run(mailSendCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--stdin") {
+		t.Errorf("err = %v, want refusal to journal --stdin", err)
+	}
+	if info := loadJournalInfo(townRoot); info != nil {
+		t.Errorf("nothing should be journaled, got %+v", info)
+	}
+}
+
+func TestPrintJournal(t *testing.T) {
+	e := journal.NewEntry([]string{"close", "gt-abc"}, "/town/gastown", "connection refused")
+	e.Attempts = 2
+	e.LastError = "exit status 1: still down"
+
+	var buf bytes.Buffer
+	printJournal(&buf, []journal.Entry{e})
+	out := buf.String()
+	for _, want := range []string{e.ID, "gt close gt-abc", "/town/gastown", "connection refused", "still down"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
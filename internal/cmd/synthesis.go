@@ -328,7 +328,7 @@ func runSynthesisClose(cmd *cobra.Command, args []string) error {
 	showCmd.Dir = townBeads
 	var showOut bytes.Buffer
 	showCmd.Stdout = &showOut
-	if err := showCmd.Run(); err != nil {
+	if err := beads.RunCmd(showCmd); err != nil {
 		return fmt.Errorf("reading convoy '%s': %w", convoyID, err)
 	}
 	var convoys []struct {
@@ -358,7 +358,7 @@ func runSynthesisClose(cmd *cobra.Command, args []string) error {
 	closeCmd.Dir = townBeads
 	closeCmd.Stderr = os.Stderr
 
-	if err := closeCmd.Run(); err != nil {
+	if err := beads.RunCmd(closeCmd); err != nil {
 		return fmt.Errorf("closing convoy: %w", err)
 	}
 
@@ -382,7 +382,7 @@ func getConvoyMeta(convoyID string) (*ConvoyMeta, error) {
 	var stdout bytes.Buffer
 	showCmd.Stdout = &stdout
 
-	if err := showCmd.Run(); err != nil {
+	if err := beads.RunCmd(showCmd); err != nil {
 		return nil, fmt.Errorf("convoy '%s' not found", convoyID)
 	}
 
@@ -581,7 +581,7 @@ func createSynthesisBead(convoyID string, meta *ConvoyMeta, f *formula.Formula,
 	createCmd.Stdout = &stdout
 	createCmd.Stderr = os.Stderr
 
-	if err := createCmd.Run(); err != nil {
+	if err := beads.RunCmd(createCmd); err != nil {
 		return "", fmt.Errorf("creating synthesis bead: %w", err)
 	}
 
@@ -602,7 +602,7 @@ func createSynthesisBead(convoyID string, meta *ConvoyMeta, f *formula.Formula,
 	depArgs := []string{"dep", "add", convoyID, result.ID, "--type=tracks"}
 	depCmd := exec.Command("bd", depArgs...)
 	depCmd.Dir = townBeads
-	_ = depCmd.Run() // Non-fatal if this fails
+	_ = beads.RunCmd(depCmd) // Non-fatal if this fails
 
 	return result.ID, nil
 }
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/fsscope"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var tapGuardScopeCmd = &cobra.Command{
+	Use:   "scope",
+	Short: "Block polecat writes outside its worktree",
+	Long: `Block a polecat from writing outside its own directory via Claude Code
+PreToolUse hooks.
+
+A polecat may write under <town>/<rig>/polecats/<name>/ and to temp
+directories. Writes to sibling polecats, other rigs, the shared clones or
+the town root are blocked. The guard checks:
+  - Write, Edit, MultiEdit and NotebookEdit file paths
+  - Bash redirections, the operands of rm, cp, mv, touch, mkdir, tee,
+    sed -i and similar, and the repository of mutating git commands
+
+Relative paths are resolved against the session's working directory (and
+any cd in the command), and symlinks are followed, so ../../other-rig/x
+is caught. Blocked writes are recorded for gt witness scope and shown in
+the feed.
+
+Outside a polecat session the guard allows everything.
+
+Exit codes:
+  0 - Operation allowed
+  2 - Operation BLOCKED`,
+	RunE: runTapGuardScope,
+}
+
+func init() {
+	tapGuardCmd.AddCommand(tapGuardScopeCmd)
+}
+
+func runTapGuardScope(cmd *cobra.Command, args []string) error {
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil // fail open
+	}
+	var hook struct {
+		ToolName  string          `json:"tool_name"`
+		ToolInput json.RawMessage `json:"tool_input"`
+		Cwd       string          `json:"cwd"`
+	}
+	if json.Unmarshal(input, &hook) != nil {
+		return nil
+	}
+	if hook.Cwd == "" {
+		hook.Cwd, _ = os.Getwd()
+	}
+
+	townRoot, rigName, polecatName := scopeGuardIdentity(hook.Cwd)
+	if polecatName == "" {
+		return nil
+	}
+	scope := fsscope.ForPolecat(townRoot, rigName, polecatName)
+	outside := scope.Outside(fsscope.ToolTargets(hook.Cwd, hook.ToolName, hook.ToolInput))
+	if len(outside) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	vs := make([]fsscope.Violation, 0, len(outside))
+	for _, p := range outside {
+		vs = append(vs, fsscope.Violation{Time: now, Source: fsscope.SourceGuard, Rig: rigName, Polecat: polecatName, Tool: hook.ToolName, Path: p})
+	}
+	_, _ = fsscope.Record(townRoot, vs)
+	_ = events.LogFeed(events.TypeScopeViolation, rigName+"/"+polecatName,
+		events.ScopeViolationPayload(rigName, polecatName, outside[0], fsscope.SourceGuard))
+
+	printScopeBlock(outside[0], scope.Root)
+	return NewSilentExit(2)
+}
+
+// scopeGuardIdentity returns the town, rig and polecat of the session,
+// from GT_POLECAT/GT_RIG or, failing that, the working directory. The
+// polecat is empty outside a polecat session.
+func scopeGuardIdentity(cwd string) (townRoot, rigName, polecatName string) {
+	townRoot = os.Getenv("GT_ROOT")
+	if townRoot == "" {
+		townRoot, _ = workspace.Find(cwd)
+	}
+	if townRoot == "" {
+		return "", "", ""
+	}
+	if p, r := os.Getenv("GT_POLECAT"), os.Getenv("GT_RIG"); p != "" && r != "" {
+		return townRoot, r, p
+	}
+	rel, err := filepath.Rel(townRoot, cwd)
+	if err != nil {
+		return townRoot, "", ""
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) >= 3 && parts[1] == "polecats" && parts[0] != ".." {
+		return townRoot, parts[0], parts[2]
+	}
+	return townRoot, "", ""
+}
+
+func printScopeBlock(path, root string) {
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "╔══════════════════════════════════════════════════════════════════╗")
+	fmt.Fprintln(os.Stderr, "║  ❌ WRITE OUTSIDE WORKTREE BLOCKED                               ║")
+	fmt.Fprintln(os.Stderr, "╠══════════════════════════════════════════════════════════════════╣")
+	fmt.Fprintf(os.Stderr, "║  Path:  %-56s ║\n", truncateStr(path, 56))
+	fmt.Fprintf(os.Stderr, "║  Scope: %-56s ║\n", truncateStr(root, 56))
+	fmt.Fprintln(os.Stderr, "║                                                                  ║")
+	fmt.Fprintln(os.Stderr, "║  Polecats only change files in their own worktree. If the work  ║")
+	fmt.Fprintln(os.Stderr, "║  needs changes elsewhere, say so with gt escalate.               ║")
+	fmt.Fprintln(os.Stderr, "╚══════════════════════════════════════════════════════════════════╝")
+	fmt.Fprintln(os.Stderr, "")
+}
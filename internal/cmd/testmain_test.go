@@ -0,0 +1,13 @@
+//go:build !integration
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/testtown"
+)
+
+// TestMain lets tests built on testtown start this binary as bd and as a
+// scripted agent. The integration build has its own TestMain.
+func TestMain(m *testing.M) { testtown.Main(m) }
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/retro"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/timetrack"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	timeReportBy      string
+	timeReportSince   string
+	timeReportRig     string
+	timeReportIdleGap time.Duration
+	timeReportFormat  string
+	timeReportOutput  string
+)
+
+var timeCmd = &cobra.Command{
+	Use:     "time",
+	GroupID: GroupDiag,
+	Short:   "Active-work time per bead and agent",
+	Long: `Report how much active work agents put into beads.
+
+Time is measured from session activity, not wall clock. On every heartbeat
+the daemon records each agent session's last tmux activity; consecutive
+signals no more than --idle-gap apart count as active time, longer gaps
+as idle. Active time is attributed to the bead the agent was hooked to at
+the time, according to the event log. Signals are kept per month in
+.runtime/timetrack/.
+
+Commands:
+  gt time report    Active time grouped by rig, agent, label or bead`,
+	RunE: requireSubcommand,
+}
+
+var timeReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show active-work time by rig, agent, label or bead",
+	Long: `Show active-work time grouped by rig, agent, label or bead.
+
+A bead with several labels counts toward each of them. Active time while an
+agent had nothing hooked is reported as "(no bead)". Town-level agents
+(mayor, deacon, dogs) are grouped under the "(town)" rig.
+
+Use --format csv to export the report, e.g. to compare agent time per bead
+against a human baseline.
+
+Examples:
+  gt time report
+  gt time report --by rig --since 30d
+  gt time report --by label --rig gastown
+  gt time report --by bead --format csv -o time.csv`,
+	Args: cobra.NoArgs,
+	RunE: runTimeReport,
+}
+
+var (
+	// timeLoadEventsFn is a seam for tests. Production uses retro.LoadEvents.
+	timeLoadEventsFn = retro.LoadEvents
+
+	// timeBeadLabelsFn is a seam for tests. Production reads the labels with bd
+	// show.
+	timeBeadLabelsFn = func(id string) []string {
+		issue, err := beads.New(resolveBeadDir(id)).Show(id)
+		if err != nil {
+			return nil
+		}
+		return issue.Labels
+	}
+)
+
+func init() {
+	timeReportCmd.Flags().StringVar(&timeReportBy, "by", "agent", "Group by: rig, agent, label or bead")
+	timeReportCmd.Flags().StringVar(&timeReportSince, "since", "7d", "Report activity within this window (e.g., 24h, 7d)")
+	timeReportCmd.Flags().StringVar(&timeReportRig, "rig", "", "Only report agents of this rig")
+	timeReportCmd.Flags().DurationVar(&timeReportIdleGap, "idle-gap", timetrack.DefaultIdleGap, "Longest gap between activity signals that still counts as active")
+	timeReportCmd.Flags().StringVar(&timeReportFormat, "format", "table", "Output format: table, csv or json")
+	timeReportCmd.Flags().StringVarP(&timeReportOutput, "output", "o", "", "Write to file instead of stdout")
+
+	timeCmd.AddCommand(timeReportCmd)
+	rootCmd.AddCommand(timeCmd)
+}
+
+// timeRow is one line of a time report.
+type timeRow struct {
+	Key    string        `json:"key"`
+	Active time.Duration `json:"active_ns"`
+	Beads  int           `json:"beads"`
+}
+
+func runTimeReport(cmd *cobra.Command, args []string) error {
+	switch timeReportBy {
+	case "rig", "agent", "label", "bead":
+	default:
+		return fmt.Errorf("invalid --by %q (use rig, agent, label or bead)", timeReportBy)
+	}
+	switch timeReportFormat {
+	case "table", "csv", "json":
+	default:
+		return fmt.Errorf("unknown format %q (use table, csv or json)", timeReportFormat)
+	}
+	window, err := parseDuration(timeReportSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	since := time.Now().Add(-window)
+	signals, err := timetrack.Load(townRoot, since)
+	if err != nil {
+		return fmt.Errorf("reading activity signals: %w", err)
+	}
+	// Assignments made before the window still attribute time inside it.
+	evs, err := timeLoadEventsFn(townRoot, time.Time{})
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+
+	var entries []timetrack.Entry
+	for _, e := range timetrack.Compute(signals, timetrack.Assignments(evs), timeReportIdleGap) {
+		if timeReportRig == "" || e.Rig == timeReportRig {
+			entries = append(entries, e)
+		}
+	}
+	rows := groupTimeEntries(entries, timeReportBy, timeBeadLabelsFn)
+
+	w := io.Writer(os.Stdout)
+	if timeReportOutput != "" {
+		f, err := os.Create(timeReportOutput)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writeTimeReport(w, rows); err != nil {
+		return err
+	}
+	if timeReportOutput != "" {
+		fmt.Fprintf(os.Stderr, "%s Wrote %d rows to %s\n", style.Success.Render("✓"), len(rows), timeReportOutput)
+	}
+	return nil
+}
+
+// groupTimeEntries sums entries by the --by key, largest first.
+func groupTimeEntries(entries []timetrack.Entry, by string, labelsFor func(string) []string) []timeRow {
+	active := map[string]time.Duration{}
+	beadSets := map[string]map[string]bool{}
+	add := func(key string, e timetrack.Entry) {
+		active[key] += e.Active
+		if e.Bead == "" {
+			return
+		}
+		if beadSets[key] == nil {
+			beadSets[key] = map[string]bool{}
+		}
+		beadSets[key][e.Bead] = true
+	}
+
+	labels := map[string][]string{}
+	for _, e := range entries {
+		switch by {
+		case "rig":
+			key := e.Rig
+			if key == "" {
+				key = "(town)"
+			}
+			add(key, e)
+		case "bead":
+			key := e.Bead
+			if key == "" {
+				key = "(no bead)"
+			}
+			add(key, e)
+		case "label":
+			if e.Bead == "" {
+				add("(no bead)", e)
+				continue
+			}
+			ls, ok := labels[e.Bead]
+			if !ok {
+				ls = labelsFor(e.Bead)
+				labels[e.Bead] = ls
+			}
+			if len(ls) == 0 {
+				add("(no label)", e)
+			}
+			for _, l := range ls {
+				add(l, e)
+			}
+		default:
+			add(e.Agent, e)
+		}
+	}
+
+	rows := make([]timeRow, 0, len(active))
+	for key, d := range active {
+		rows = append(rows, timeRow{Key: key, Active: d, Beads: len(beadSets[key])})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Active != rows[j].Active {
+			return rows[i].Active > rows[j].Active
+		}
+		return rows[i].Key < rows[j].Key
+	})
+	return rows
+}
+
+func writeTimeReport(w io.Writer, rows []timeRow) error {
+	switch timeReportFormat {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case "csv":
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{timeReportBy, "active_seconds", "active_hours", "beads"})
+		for _, r := range rows {
+			_ = cw.Write([]string{
+				r.Key, strconv.FormatInt(int64(r.Active.Seconds()), 10),
+				strconv.FormatFloat(r.Active.Hours(), 'f', 2, 64), strconv.Itoa(r.Beads),
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	if len(rows) == 0 {
+		fmt.Fprintf(w, "No active time recorded in the last %s.\n", timeReportSince)
+		fmt.Fprintf(w, "%s\n", style.Dim.Render("Activity is sampled by the daemon; is it running? (gt daemon status)"))
+		return nil
+	}
+	var total time.Duration
+	fmt.Fprintf(w, "%-32s %10s %6s\n", strings.ToUpper(timeReportBy), "ACTIVE", "BEADS")
+	for _, r := range rows {
+		total += r.Active
+		fmt.Fprintf(w, "%-32s %10s %6d\n", r.Key, retro.FormatDuration(r.Active), r.Beads)
+	}
+	if timeReportBy != "label" {
+		// Label totals double-count beads with several labels.
+		fmt.Fprintf(w, "%-32s %10s\n", "TOTAL", retro.FormatDuration(total))
+	}
+	return nil
+}
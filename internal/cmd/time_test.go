@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/timetrack"
+)
+
+func TestGroupTimeEntries(t *testing.T) {
+	entries := []timetrack.Entry{
+		{Agent: "gastown/polecats/Toast", Rig: "gastown", Bead: "gt-1", Active: 30 * time.Minute},
+		{Agent: "gastown/polecats/Toast", Rig: "gastown", Bead: "", Active: 5 * time.Minute},
+		{Agent: "gastown/polecats/Nux", Rig: "gastown", Bead: "gt-2", Active: time.Hour},
+		{Agent: "mayor", Bead: "hq-3", Active: 10 * time.Minute},
+	}
+	labels := func(id string) []string {
+		switch id {
+		case "gt-1":
+			return []string{"backend", "bug"}
+		case "gt-2":
+			return []string{"backend"}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		by   string
+		want []timeRow
+	}{
+		{"rig", []timeRow{{"gastown", 95 * time.Minute, 2}, {"(town)", 10 * time.Minute, 1}}},
+		{"agent", []timeRow{{"gastown/polecats/Nux", time.Hour, 1}, {"gastown/polecats/Toast", 35 * time.Minute, 1}, {"mayor", 10 * time.Minute, 1}}},
+		{"label", []timeRow{{"backend", 90 * time.Minute, 2}, {"bug", 30 * time.Minute, 1}, {"(no label)", 10 * time.Minute, 1}, {"(no bead)", 5 * time.Minute, 0}}},
+	}
+	for _, tt := range tests {
+		got := groupTimeEntries(entries, tt.by, labels)
+		if len(got) != len(tt.want) {
+			t.Errorf("by %s: rows = %+v", tt.by, got)
+			continue
+		}
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("by %s: row %d = %+v, want %+v", tt.by, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestRunTimeReportCSV(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(town)
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	var signals []timetrack.Signal
+	for i := 0; i <= 4; i++ {
+		signals = append(signals, timetrack.Signal{Agent: "gastown/polecats/Toast", At: start.Add(time.Duration(i*3) * time.Minute)})
+	}
+	if err := timetrack.Record(town, signals); err != nil {
+		t.Fatal(err)
+	}
+
+	origEvents, origBy, origFormat, origOutput, origSince, origGap, origRig :=
+		timeLoadEventsFn, timeReportBy, timeReportFormat, timeReportOutput, timeReportSince, timeReportIdleGap, timeReportRig
+	t.Cleanup(func() {
+		timeLoadEventsFn, timeReportBy, timeReportFormat, timeReportOutput, timeReportSince, timeReportIdleGap, timeReportRig =
+			origEvents, origBy, origFormat, origOutput, origSince, origGap, origRig
+	})
+	timeLoadEventsFn = func(string, time.Time) ([]events.Event, error) {
+		return []events.Event{{
+			Timestamp: start.Add(-24 * time.Hour).Format(time.RFC3339),
+			Type:      events.TypeSling,
+			Payload:   map[string]interface{}{"bead": "gt-1", "target": "gastown/polecats/Toast"},
+		}}, nil
+	}
+	timeReportBy, timeReportFormat, timeReportOutput, timeReportSince = "bead", "csv", "", "2h"
+	timeReportIdleGap, timeReportRig = timetrack.DefaultIdleGap, ""
+
+	out := captureStdout(t, func() {
+		if err := runTimeReport(timeReportCmd, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+	want := "bead,active_seconds,active_hours,beads\ngt-1,720,0.20,1\n"
+	if out != want {
+		t.Errorf("csv = %q, want %q", out, want)
+	}
+
+	timeReportBy = "team"
+	if err := runTimeReport(timeReportCmd, nil); err == nil || !strings.Contains(err.Error(), "--by") {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,449 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/chaos"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/daemon"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	townChaosProfile  string
+	townChaosDuration time.Duration
+	townChaosSettle   time.Duration
+	townChaosSeed     uint64
+	townChaosDryRun   bool
+	townChaosJSON     bool
+)
+
+// Runner timing. Variables so tests can run a chaos session instantly.
+var (
+	chaosTick       = time.Second
+	chaosSettlePoll = 10 * time.Second
+)
+
+var (
+	// chaosListSessionsFn is a seam for tests. Production asks tmux.
+	chaosListSessionsFn = func() ([]string, error) { return tmux.NewTmux().ListSessions() }
+
+	// chaosKillSessionFn is a seam for tests. Production kills the session and
+	// its processes over tmux.
+	chaosKillSessionFn = func(name string) error { return tmux.NewTmux().KillSessionWithProcesses(name) }
+
+	// chaosHasSessionFn is a seam for tests. Production asks tmux.
+	chaosHasSessionFn = func(name string) bool {
+		ok, _ := tmux.NewTmux().HasSession(name)
+		return ok
+	}
+
+	// chaosDaemonRunningFn is a seam for tests. Production uses
+	// daemon.IsRunning.
+	chaosDaemonRunningFn = func(townRoot string) bool {
+		ok, _, _ := daemon.IsRunning(townRoot)
+		return ok
+	}
+
+	// chaosShowBeadFn is a seam for tests. Production runs bd show in the town.
+	chaosShowBeadFn = func(townRoot, id string) (*beads.Issue, error) { return beads.New(townRoot).Show(id) }
+
+	// chaosSleepFn is a seam for tests. Production uses time.Sleep.
+	chaosSleepFn = time.Sleep
+)
+
+var townChaosCmd = &cobra.Command{
+	Use:         "chaos",
+	Annotations: map[string]string{AnnotationFeature: config.FeatureChaos},
+	Short:       "Inject faults into a test town and check that it recovers",
+	Long: `Inject faults into a running test town, then check that the deacon,
+witness and daemon put it back together.
+
+For --duration, faults arrive at random:
+
+  kill       a session of one of the profile's roles is killed
+  skew       the deacon's or a polecat's heartbeat is moved back (a hung
+             agent) or forward (clock drift)
+  bd_delay   bd calls made by any gt process are held back
+  mail_drop  mail sent by any gt process is silently discarded
+
+Then the town gets --settle to recover, and these invariants are checked:
+
+  daemon running            the daemon survived, if it was up at the start
+  killed agents restarted   every killed deacon, witness, refinery or dog
+                            session is back
+  killed polecats' work     each killed polecat is back, or its hooked bead
+  released                  was released or finished
+  heartbeats recovered      every skewed heartbeat of a live agent is fresh
+                            and not in the future
+
+gt town chaos exits 1 if an invariant fails. Faults are logged to
+.runtime/chaos.jsonl and the town feed. Profiles:
+
+  mild      polecat kills every ~5m, 5% of bd calls delayed up to 2s,
+            2% of mail dropped, a heartbeat skewed every ~10m
+  moderate  witness and refinery kills too, every ~2m; 15% delayed up to
+            5s; 5% dropped; skews every ~4m
+  severe    deacon and dogs too, every ~45s; 30% delayed up to 10s; 15%
+            dropped; skews of up to an hour every ~90s
+
+Only for test towns: the town must enable the chaos feature
+(gt config feature chaos on). The passive faults expire on their
+own if the run is interrupted.
+
+Examples:
+  gt town chaos --profile mild
+  gt town chaos --profile moderate --duration 30m --settle 10m
+  gt town chaos --profile severe --seed 42 --dry-run
+  gt town chaos --json`,
+	Args: cobra.NoArgs,
+	RunE: runTownChaos,
+}
+
+func init() {
+	townChaosCmd.Flags().StringVar(&townChaosProfile, "profile", "mild", "Fault profile: mild, moderate or severe")
+	townChaosCmd.Flags().DurationVar(&townChaosDuration, "duration", 10*time.Minute, "How long to inject faults")
+	townChaosCmd.Flags().DurationVar(&townChaosSettle, "settle", 5*time.Minute, "How long the town gets to recover")
+	townChaosCmd.Flags().Uint64Var(&townChaosSeed, "seed", 0, "Seed for the fault schedule (default random)")
+	townChaosCmd.Flags().BoolVar(&townChaosDryRun, "dry-run", false, "Print the fault schedule without injecting anything")
+	townChaosCmd.Flags().BoolVar(&townChaosJSON, "json", false, "Output the report as JSON")
+	townCmd.AddCommand(townChaosCmd)
+}
+
+// chaosRun is one chaos session against a town.
+type chaosRun struct {
+	townRoot string
+	profile  chaos.Profile
+	rng      *rand.Rand
+
+	daemonWasUp bool
+	killed      map[string]string // Killed session -> bead it held (polecats)
+	skewed      map[string]bool   // Skewed heartbeats: "deacon" or a polecat session
+}
+
+func runTownChaos(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	profile, err := chaos.LookupProfile(townChaosProfile)
+	if err != nil {
+		return err
+	}
+	if townChaosDuration <= 0 {
+		return fmt.Errorf("--duration must be positive")
+	}
+	seed := townChaosSeed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	run := &chaosRun{
+		townRoot: townRoot,
+		profile:  profile,
+		rng:      rand.New(rand.NewPCG(seed, seed)),
+		killed:   make(map[string]string),
+		skewed:   make(map[string]bool),
+	}
+	plan := chaos.Plan(profile, townChaosDuration, run.rng)
+
+	if townChaosDryRun {
+		printChaosPlan(profile, seed, plan)
+		return nil
+	}
+	if chaos.Load(townRoot) != nil {
+		return fmt.Errorf("a chaos run is already live in this town (%s)", chaos.StatePath(townRoot))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	run.daemonWasUp = chaosDaemonRunningFn(townRoot)
+	started := time.Now()
+	if err := chaos.Start(townRoot, chaos.NewState(profile, started, townChaosDuration)); err != nil {
+		return fmt.Errorf("starting chaos: %w", err)
+	}
+	defer func() { _ = chaos.Stop(townRoot) }()
+	if !townChaosJSON {
+		fmt.Printf("%s Chaos (%s, seed %d) for %s; Ctrl-C stops early\n", style.Bold.Render("💥"), profile.Name, seed, townChaosDuration)
+	}
+
+	next := 0
+	for elapsed := time.Duration(0); elapsed < townChaosDuration && ctx.Err() == nil; elapsed += chaosTick {
+		for ; next < len(plan) && plan[next].At <= elapsed; next++ {
+			run.inject(plan[next].Kind)
+		}
+		chaosSleepFn(chaosTick)
+	}
+	if err := chaos.Stop(townRoot); err != nil {
+		style.PrintWarning("could not withdraw chaos state: %v", err)
+	}
+
+	report := &chaos.Report{Profile: profile.Name, Seed: seed, Duration: time.Since(started).Round(time.Second)}
+	for waited := time.Duration(0); ; waited += chaosSettlePoll {
+		report.Checks = run.checkInvariants()
+		report.Settled = waited
+		if report.Passed() || waited >= townChaosSettle || ctx.Err() != nil {
+			break
+		}
+		chaosSleepFn(chaosSettlePoll)
+	}
+	report.Faults, _ = chaos.ReadLog(townRoot, started)
+
+	if townChaosJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printChaosReport(report)
+	}
+	if !report.Passed() {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// inject carries out one scheduled fault. A fault with no target (no
+// killable session, no heartbeat) is skipped.
+func (r *chaosRun) inject(kind string) {
+	var f chaos.Fault
+	var ok bool
+	switch kind {
+	case chaos.KindKill:
+		f, ok = r.kill()
+	case chaos.KindSkew:
+		f, ok = r.skew()
+	}
+	if !ok {
+		return
+	}
+	f.At, f.Kind = time.Now(), kind
+	_ = chaos.Record(r.townRoot, f)
+	_ = events.LogFeed(events.TypeChaos, "chaos", map[string]interface{}{
+		"fault":  kind,
+		"target": f.Target,
+		"detail": f.Detail,
+	})
+	if !townChaosJSON {
+		fmt.Printf("  %s %-5s %s %s\n", f.At.Format("15:04:05"), kind, f.Target, style.Dim.Render(f.Detail))
+	}
+}
+
+func (r *chaosRun) kill() (chaos.Fault, bool) {
+	sessions, err := chaosListSessionsFn()
+	if err != nil {
+		return chaos.Fault{}, false
+	}
+	var candidates []string
+	for _, name := range sessions {
+		if id, err := session.ParseSessionName(name); err == nil && r.profile.Kills(string(id.Role)) {
+			candidates = append(candidates, name)
+		}
+	}
+	sort.Strings(candidates)
+	victim := chaos.Pick(candidates, r.rng)
+	if victim == "" {
+		return chaos.Fault{}, false
+	}
+
+	var bead string
+	if hb := polecat.ReadSessionHeartbeat(r.townRoot, victim); hb != nil {
+		bead = hb.Bead
+	}
+	if err := chaosKillSessionFn(victim); err != nil {
+		return chaos.Fault{}, false
+	}
+	r.killed[victim] = bead
+	detail := ""
+	if bead != "" {
+		detail = "holding " + bead
+	}
+	return chaos.Fault{Target: victim, Detail: detail}, true
+}
+
+func (r *chaosRun) skew() (chaos.Fault, bool) {
+	targets := map[string]string{}
+	if _, err := os.Stat(deacon.HeartbeatFile(r.townRoot)); err == nil {
+		targets["deacon"] = deacon.HeartbeatFile(r.townRoot)
+	}
+	if sessions, err := chaosListSessionsFn(); err == nil {
+		for _, name := range sessions {
+			path := polecat.SessionHeartbeatPath(r.townRoot, name)
+			if _, err := os.Stat(path); err == nil {
+				targets[name] = path
+			}
+		}
+	}
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	target := chaos.Pick(names, r.rng)
+	if target == "" {
+		return chaos.Fault{}, false
+	}
+
+	delta := chaos.SkewDelta(r.profile.Skew, r.rng)
+	if err := chaos.SkewTimestamp(targets[target], delta); err != nil {
+		return chaos.Fault{}, false
+	}
+	r.skewed[target] = true
+	return chaos.Fault{Target: target, Detail: fmt.Sprintf("%+v", delta.Round(time.Second))}, true
+}
+
+// checkInvariants evaluates the recovery invariants against the town now.
+func (r *chaosRun) checkInvariants() []chaos.Check {
+	var checks []chaos.Check
+	if r.daemonWasUp {
+		c := chaos.Check{Name: "daemon running", OK: chaosDaemonRunningFn(r.townRoot)}
+		if !c.OK {
+			c.Detail = "daemon is down"
+		}
+		checks = append(checks, c)
+	}
+
+	var down, orphaned []string
+	for _, name := range sortedKeys(r.killed) {
+		if chaosHasSessionFn(name) {
+			continue
+		}
+		id, err := session.ParseSessionName(name)
+		if err != nil {
+			continue
+		}
+		if id.Role != session.RolePolecat {
+			down = append(down, name)
+			continue
+		}
+		if held := r.killed[name]; held != "" && r.stillHooked(held, id.Address()) {
+			orphaned = append(orphaned, fmt.Sprintf("%s still held by %s", held, id.Address()))
+		}
+	}
+	checks = append(checks,
+		chaosCheck("killed agents restarted", down, "still down: "),
+		chaosCheck("killed polecats' work released", orphaned, ""),
+	)
+
+	var stale []string
+	for _, target := range sortedKeys(r.skewed) {
+		if problem := r.heartbeatProblem(target); problem != "" {
+			stale = append(stale, target+" "+problem)
+		}
+	}
+	checks = append(checks, chaosCheck("heartbeats recovered", stale, ""))
+	return checks
+}
+
+// stillHooked reports whether bead is still assigned to address and open.
+// Lookup errors count as released: the bead can't be proven orphaned.
+func (r *chaosRun) stillHooked(bead, address string) bool {
+	issue, err := chaosShowBeadFn(r.townRoot, bead)
+	if err != nil {
+		return false
+	}
+	return issue.Assignee == address && (issue.Status == beads.StatusHooked || issue.Status == "in_progress")
+}
+
+// heartbeatProblem describes what is wrong with a skewed heartbeat, or
+// returns "" when it has recovered or its agent is gone (no one left to
+// write it).
+func (r *chaosRun) heartbeatProblem(target string) string {
+	var ts time.Time
+	var stale time.Duration
+	if target == "deacon" {
+		if !chaosHasSessionFn(session.DeaconSessionName()) {
+			return ""
+		}
+		hb := deacon.ReadHeartbeat(r.townRoot)
+		if hb == nil {
+			return "missing"
+		}
+		ts, stale = hb.Timestamp, deacon.HeartbeatStaleThreshold
+	} else {
+		if !chaosHasSessionFn(target) {
+			return ""
+		}
+		hb := polecat.ReadSessionHeartbeat(r.townRoot, target)
+		if hb == nil {
+			return ""
+		}
+		ts, stale = hb.Timestamp, polecat.SessionHeartbeatStaleThreshold
+	}
+	age := time.Since(ts)
+	switch {
+	case age < -time.Minute:
+		return fmt.Sprintf("in the future by %s", (-age).Round(time.Second))
+	case age >= stale:
+		return fmt.Sprintf("stale (%s old)", age.Round(time.Second))
+	}
+	return ""
+}
+
+func chaosCheck(name string, problems []string, prefix string) chaos.Check {
+	c := chaos.Check{Name: name, OK: len(problems) == 0}
+	if !c.OK {
+		c.Detail = prefix + strings.Join(problems, "; ")
+	}
+	return c
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func printChaosPlan(p chaos.Profile, seed uint64, plan []chaos.Action) {
+	fmt.Printf("Chaos plan (%s, seed %d), nothing injected:\n", p.Name, seed)
+	for _, a := range plan {
+		fmt.Printf("  %s  %s\n", formatSimOffset(a.At), a.Kind)
+	}
+	if len(plan) == 0 {
+		fmt.Println("  no kills or skews scheduled")
+	}
+	fmt.Printf("  throughout: %.0f%% of bd calls delayed up to %s, %.0f%% of mail dropped\n",
+		p.BDDelayRate*100, p.BDDelay, p.MailDrop*100)
+}
+
+func printChaosReport(r *chaos.Report) {
+	fmt.Printf("\nFaults: %d kill, %d skew, %d bd_delay, %d mail_drop over %s\n",
+		r.Count(chaos.KindKill), r.Count(chaos.KindSkew), r.Count(chaos.KindBDDelay), r.Count(chaos.KindMailDrop), r.Duration)
+	for _, c := range r.Checks {
+		mark := style.Success.Render("✓")
+		if !c.OK {
+			mark = style.Error.Render("✗")
+		}
+		line := fmt.Sprintf("  %s %s", mark, c.Name)
+		if c.Detail != "" {
+			line += ": " + c.Detail
+		}
+		fmt.Println(line)
+	}
+	if r.Passed() {
+		fmt.Printf("%s Town recovered (settled in %s)\n", style.SuccessPrefix, r.Settled)
+	} else {
+		fmt.Printf("%s Town did not recover within %s\n", style.ErrorPrefix, r.Settled)
+	}
+}
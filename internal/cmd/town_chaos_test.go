@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/chaos"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// stubChaos sets up a town with sessions that stay dead once killed, and
+// runs a chaos session without sleeping.
+func stubChaos(t *testing.T, sessions ...string) (town string, killed map[string]bool) {
+	t.Helper()
+	town = t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(town)
+
+	origReg := session.DefaultRegistry()
+	reg := session.NewPrefixRegistry()
+	reg.Register("gt", "gastown")
+	session.SetDefaultRegistry(reg)
+
+	origList, origKill, origHas, origDaemon, origShow, origSleep := chaosListSessionsFn, chaosKillSessionFn, chaosHasSessionFn, chaosDaemonRunningFn, chaosShowBeadFn, chaosSleepFn
+	origProfile, origDuration, origSettle, origSeed, origJSON := townChaosProfile, townChaosDuration, townChaosSettle, townChaosSeed, townChaosJSON
+	t.Cleanup(func() {
+		session.SetDefaultRegistry(origReg)
+		chaosListSessionsFn, chaosKillSessionFn, chaosHasSessionFn, chaosDaemonRunningFn, chaosShowBeadFn, chaosSleepFn = origList, origKill, origHas, origDaemon, origShow, origSleep
+		townChaosProfile, townChaosDuration, townChaosSettle, townChaosSeed, townChaosJSON = origProfile, origDuration, origSettle, origSeed, origJSON
+	})
+
+	killed = map[string]bool{}
+	chaosListSessionsFn = func() ([]string, error) {
+		var live []string
+		for _, s := range sessions {
+			if !killed[s] {
+				live = append(live, s)
+			}
+		}
+		return live, nil
+	}
+	chaosKillSessionFn = func(name string) error { killed[name] = true; return nil }
+	chaosHasSessionFn = func(name string) bool { return !killed[name] }
+	chaosDaemonRunningFn = func(string) bool { return false }
+	chaosSleepFn = func(time.Duration) {}
+
+	townChaosProfile, townChaosDuration, townChaosSettle, townChaosSeed, townChaosJSON = "severe", 10*time.Minute, time.Minute, 1, true
+	return town, killed
+}
+
+func TestRunTownChaos_OrphanedPolecatFails(t *testing.T) {
+	town, killed := stubChaos(t, "gt-toast")
+	polecat.TouchSessionHeartbeatWithState(town, "gt-toast", polecat.HeartbeatWorking, "", "gt-abc")
+	chaosShowBeadFn = func(_, id string) (*beads.Issue, error) {
+		return &beads.Issue{ID: id, Status: beads.StatusHooked, Assignee: "gastown/polecats/toast"}, nil
+	}
+
+	var err error
+	out := captureStdout(t, func() { err = runTownChaos(nil, nil) })
+	if err == nil {
+		t.Fatalf("expected failure with the bead still hooked:\n%s", out)
+	}
+	if !killed["gt-toast"] {
+		t.Fatal("polecat never killed")
+	}
+	var report chaos.Report
+	if jerr := json.Unmarshal([]byte(out), &report); jerr != nil {
+		t.Fatalf("report not JSON: %v\n%s", jerr, out)
+	}
+	for _, c := range report.Checks {
+		if c.Name == "killed polecats' work released" && (c.OK || !strings.Contains(c.Detail, "gt-abc")) {
+			t.Errorf("work check = %+v", c)
+		}
+	}
+	if report.Count(chaos.KindKill) != 1 || report.Faults[0].Detail != "holding gt-abc" {
+		t.Errorf("faults = %+v", report.Faults)
+	}
+	if chaos.Load(town) != nil {
+		t.Error("chaos state left live after the run")
+	}
+}
+
+func TestRunTownChaos_RecoveredTownPasses(t *testing.T) {
+	town, _ := stubChaos(t, "gt-toast", "gt-witness")
+	polecat.TouchSessionHeartbeatWithState(town, "gt-toast", polecat.HeartbeatWorking, "", "gt-abc")
+	// The witness released the bead back to the pool.
+	chaosShowBeadFn = func(_, id string) (*beads.Issue, error) {
+		return &beads.Issue{ID: id, Status: "open"}, nil
+	}
+	// Something restarts anything lost, and the polecat keeps its heartbeat
+	// fresh, so skews heal once the run ends.
+	chaosHasSessionFn = func(string) bool { return true }
+	chaosSleepFn = func(time.Duration) { polecat.TouchSessionHeartbeat(town, "gt-toast") }
+
+	var err error
+	out := captureStdout(t, func() { err = runTownChaos(nil, nil) })
+	if err != nil {
+		t.Fatalf("runTownChaos: %v\n%s", err, out)
+	}
+}
+
+func TestRunTownChaos_RefusesSecondRun(t *testing.T) {
+	town, _ := stubChaos(t)
+	p, _ := chaos.LookupProfile("mild")
+	if err := chaos.Start(town, chaos.NewState(p, time.Now(), time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := runTownChaos(nil, nil); err == nil || !strings.Contains(err.Error(), "already live") {
+		t.Errorf("err = %v, want already live", err)
+	}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/templates"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	townServicePrint     bool
+	townServiceUninstall bool
+)
+
+var townInstallServiceCmd = &cobra.Command{
+	Use:   "install-service",
+	Short: "Run the daemon under systemd/launchd supervision",
+	Long: `Install a supervisor service that keeps the Gas Town daemon running.
+
+On Linux this is a systemd user unit (gastown-daemon.service) with
+Restart=always; on macOS a launchd agent (com.gastown.daemon) with
+KeepAlive. The supervisor starts the daemon at login and restarts it
+whenever it dies, instead of the death only being noticed once dispatch
+stops.
+
+The daemon records its health in daemon/state.json on every heartbeat.
+When it starts after a run that ended without a clean shutdown, it
+replays the event log (gt town rebuild-state --repair) before its first
+heartbeat, so hooks, the scheduler queue and freezes a crash left
+half-written are reconciled. 'gt daemon status' and 'gt doctor' report
+a daemon whose heartbeat is stale or that died uncleanly.
+
+Examples:
+  gt town install-service              # Install, enable and start
+  gt town install-service --print      # Show the service file only
+  gt town install-service --uninstall  # Stop and remove the service`,
+	Args: cobra.NoArgs,
+	RunE: runTownInstallService,
+}
+
+var (
+	// townServiceRenderFn is a seam for tests. Production uses
+	// templates.RenderSupervisor.
+	townServiceRenderFn = templates.RenderSupervisor
+
+	// townServiceProvisionFn is a seam for tests. Production uses
+	// templates.ProvisionSupervisor.
+	townServiceProvisionFn = templates.ProvisionSupervisor
+
+	// townServiceRemoveFn is a seam for tests. Production uses
+	// templates.RemoveSupervisor.
+	townServiceRemoveFn = templates.RemoveSupervisor
+)
+
+func init() {
+	townInstallServiceCmd.Flags().BoolVar(&townServicePrint, "print", false, "Print the service file and where it would go, without installing")
+	townInstallServiceCmd.Flags().BoolVar(&townServiceUninstall, "uninstall", false, "Stop and remove the installed service")
+	townInstallServiceCmd.MarkFlagsMutuallyExclusive("print", "uninstall")
+	townCmd.AddCommand(townInstallServiceCmd)
+}
+
+func runTownInstallService(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	switch {
+	case townServicePrint:
+		svc, err := townServiceRenderFn(townRoot)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("# %s service %s → %s\n", svc.Kind, svc.Name, svc.Path)
+		fmt.Print(string(svc.Content))
+		return nil
+
+	case townServiceUninstall:
+		msg, err := townServiceRemoveFn(townRoot)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s\n", style.SuccessPrefix, msg)
+		return nil
+	}
+
+	msg, err := townServiceProvisionFn(townRoot)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s\n", style.SuccessPrefix, msg)
+	fmt.Printf("  Check on it with %s\n", style.Bold.Render("gt daemon status"))
+	return nil
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/templates"
+)
+
+func TestRunTownInstallService(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(town)
+
+	var calls []string
+	origRender, origProvision, origRemove := townServiceRenderFn, townServiceProvisionFn, townServiceRemoveFn
+	origPrint, origUninstall := townServicePrint, townServiceUninstall
+	t.Cleanup(func() {
+		townServiceRenderFn, townServiceProvisionFn, townServiceRemoveFn = origRender, origProvision, origRemove
+		townServicePrint, townServiceUninstall = origPrint, origUninstall
+	})
+	townServiceRenderFn = func(root string) (*templates.SupervisorService, error) {
+		calls = append(calls, "render")
+		return &templates.SupervisorService{Kind: "systemd", Name: "gastown-daemon.service", Path: "/u/gastown-daemon.service", Content: []byte("ExecStart=gt daemon run\n")}, nil
+	}
+	townServiceProvisionFn = func(root string) (string, error) { calls = append(calls, "provision"); return "installed", nil }
+	townServiceRemoveFn = func(root string) (string, error) { calls = append(calls, "remove"); return "removed", nil }
+
+	townServicePrint, townServiceUninstall = true, false
+	out := captureStdout(t, func() {
+		if err := runTownInstallService(nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "/u/gastown-daemon.service") || !strings.Contains(out, "ExecStart=gt daemon run") {
+		t.Errorf("--print output = %q", out)
+	}
+
+	townServicePrint = false
+	captureStdout(t, func() {
+		if err := runTownInstallService(nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+	townServiceUninstall = true
+	captureStdout(t, func() {
+		if err := runTownInstallService(nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if strings.Join(calls, ",") != "render,provision,remove" {
+		t.Errorf("calls = %v", calls)
+	}
+
+	townServiceRemoveFn = func(string) (string, error) { return "", errors.New("systemctl failed") }
+	if err := runTownInstallService(nil, nil); err == nil {
+		t.Error("expected the uninstall error to be returned")
+	}
+}
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/errcode"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/rbac"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/townlock"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	townLockReason  string
+	townLockFor     time.Duration
+	townLockJSON    bool
+	townUnlockForce bool
+)
+
+var townLockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Lock the town for a maintenance window",
+	Long: `Take the town lock for a risky maintenance window (a migration, route
+surgery), so another operator doesn't run mutating commands against the
+town at the same time.
+
+The lock is advisory and only applies to human operators: agents keep
+working, read-only commands always run, and the lock holder is never
+held by their own lock. What happens to other operators' mutating
+commands is the town's town_lock policy (settings/config.json):
+
+  "town_lock": {"policy": "block"}   refuse them (default)
+  "town_lock": {"policy": "warn"}    run them after a warning
+
+The lock expires after --for, so a forgotten lock doesn't hold the town
+forever. Locking again while you hold it renews it. Release it with
+gt town unlock. GT_IGNORE_TOWN_LOCK=1 runs one command despite the lock.
+
+Examples:
+  gt town lock -m "migrating routes"
+  gt town lock -m "dolt upgrade" --for 3h
+  gt town lock status`,
+	Args: cobra.NoArgs,
+	RunE: runTownLock,
+}
+
+var townLockStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show who holds the town lock",
+	Args:  cobra.NoArgs,
+	RunE:  runTownLockStatus,
+}
+
+var townUnlockCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Release the town lock",
+	Long: `Release the town lock taken with gt town lock.
+
+Only the lock holder can release it; --force releases another operator's
+lock (for when they've gone home with it held).
+
+Examples:
+  gt town unlock
+  gt town unlock --force`,
+	Args: cobra.NoArgs,
+	RunE: runTownUnlock,
+}
+
+// townLockExemptCommands manage the lock itself, so they run while another
+// operator holds it.
+var townLockExemptCommands = map[string]bool{
+	"town lock":        true,
+	"town lock status": true,
+	"town unlock":      true,
+}
+
+func init() {
+	townLockCmd.Flags().StringVarP(&townLockReason, "reason", "m", "", "Why the town is locked (shown to anyone held by it)")
+	townLockCmd.Flags().DurationVar(&townLockFor, "for", townlock.DefaultFor, "How long until the lock expires")
+	_ = townLockCmd.MarkFlagRequired("reason")
+	townLockStatusCmd.Flags().BoolVar(&townLockJSON, "json", false, "Output as JSON")
+	townUnlockCmd.Flags().BoolVarP(&townUnlockForce, "force", "f", false, "Release another operator's lock")
+	townLockCmd.AddCommand(townLockStatusCmd)
+	townCmd.AddCommand(townLockCmd)
+	townCmd.AddCommand(townUnlockCmd)
+}
+
+func runTownLock(cmd *cobra.Command, args []string) error {
+	if townLockFor <= 0 {
+		return fmt.Errorf("--for must be positive")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	owner := resolveShiftOperator("")
+	now := time.Now()
+	l, err := townlock.Acquire(townRoot, owner, townLockReason, now.Add(townLockFor), now)
+	if err != nil {
+		if errors.Is(err, townlock.ErrHeld) {
+			return errcode.New(errcode.TownLocked, err).WithHint("wait for the lock to expire, or ask its holder to run gt town unlock")
+		}
+		return fmt.Errorf("locking town: %w", err)
+	}
+	_ = events.LogFeed(events.TypeTownLock, owner, map[string]interface{}{
+		"locked":  true,
+		"reason":  l.Reason,
+		"expires": l.Expires.UTC().Format(time.RFC3339),
+	})
+
+	fmt.Printf("%s Town locked by %s until %s\n", style.Success.Render("🔒"), style.Bold.Render(owner), l.Expires.Local().Format("2006-01-02 15:04"))
+	fmt.Printf("  Reason: %s\n", l.Reason)
+	fmt.Printf("  %s\n", style.Dim.Render(fmt.Sprintf("Other operators' mutating commands: %s. Release with gt town unlock.", townLockPolicy(townRoot))))
+	return nil
+}
+
+func runTownLockStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	l, err := townlock.Load(townRoot)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if !l.Active(now) {
+		l = nil
+	}
+
+	if townLockJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Locked bool           `json:"locked"`
+			Policy string         `json:"policy"`
+			Lock   *townlock.Lock `json:"lock,omitempty"`
+		}{Locked: l != nil, Policy: townLockPolicy(townRoot), Lock: l})
+	}
+	if l == nil {
+		fmt.Println("Town is not locked.")
+		return nil
+	}
+	fmt.Printf("%s Town locked by %s since %s, until %s\n", style.Warning.Render("🔒"), style.Bold.Render(l.Owner),
+		l.Since.Local().Format("2006-01-02 15:04"), l.Expires.Local().Format("2006-01-02 15:04"))
+	fmt.Printf("  Reason: %s\n", l.Reason)
+	fmt.Printf("  Policy: %s\n", townLockPolicy(townRoot))
+	return nil
+}
+
+func runTownUnlock(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	operator := resolveShiftOperator("")
+	l, err := townlock.Release(townRoot, operator, townUnlockForce, time.Now())
+	if err != nil {
+		if errors.Is(err, townlock.ErrHeld) {
+			return errcode.New(errcode.TownLocked, err).WithHint("only the holder can unlock; use --force to release it anyway")
+		}
+		return fmt.Errorf("unlocking town: %w", err)
+	}
+	if l == nil {
+		fmt.Println("Town is not locked.")
+		return nil
+	}
+	_ = events.LogFeed(events.TypeTownLock, operator, map[string]interface{}{
+		"locked": false,
+		"owner":  l.Owner,
+	})
+	if l.Owner != operator {
+		fmt.Printf("%s Released %s's town lock\n", style.Success.Render("✓"), style.Bold.Render(l.Owner))
+		return nil
+	}
+	fmt.Printf("%s Town unlocked\n", style.Success.Render("✓"))
+	return nil
+}
+
+// townLockPolicy returns the town's town_lock policy.
+func townLockPolicy(townRoot string) string {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return townlock.PolicyBlock
+	}
+	return settings.TownLock.GetPolicy()
+}
+
+// enforceTownLock holds a human operator's mutating command while another
+// operator has the town locked: it warns or refuses per the town's
+// town_lock policy. Agents (GT_ROLE set), read-only commands and the lock
+// commands themselves are not held.
+func enforceTownLock(cmd *cobra.Command) error {
+	if os.Getenv("GT_ROLE") != "" {
+		return nil
+	}
+	path := accessCommandPath(cmd)
+	if townLockExemptCommands[path] || rbac.Classify(accessCommand(cmd)) < rbac.LevelWork {
+		return nil
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	l := townlock.Check(townRoot, resolveShiftOperator(""), time.Now())
+	if l == nil {
+		return nil
+	}
+	held := &townlock.HeldError{Lock: l}
+	if os.Getenv(townlock.EnvIgnore) == "1" {
+		style.PrintWarning("%v (ignored: %s=1)", held, townlock.EnvIgnore)
+		return nil
+	}
+	if townLockPolicy(townRoot) == townlock.PolicyWarn {
+		style.PrintWarning("%v", held)
+		return nil
+	}
+	return errcode.New(errcode.TownLocked, fmt.Errorf("%w\n  Wait for the lock to expire or ask %s to run gt town unlock\n  (or set %s=1 to run anyway)",
+		held, l.Owner, townlock.EnvIgnore)).WithHint("gt town lock status")
+}
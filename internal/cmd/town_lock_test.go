@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/townlock"
+)
+
+func TestEnforceTownLock(t *testing.T) {
+	townRoot := setupTownPolicyTest(t, func(s *config.TownSettings) {})
+	t.Setenv("GT_ROLE", "")
+	t.Setenv("GT_OPERATOR", "sam")
+	t.Setenv(townlock.EnvIgnore, "")
+
+	if err := enforceTownLock(accessTestCommand("rig", "add")); err != nil {
+		t.Fatalf("unlocked town held a command: %v", err)
+	}
+
+	now := time.Now()
+	if _, err := townlock.Acquire(townRoot, "dana", "route migration", now.Add(time.Hour), now); err != nil {
+		t.Fatal(err)
+	}
+	err := enforceTownLock(accessTestCommand("rig", "add"))
+	if !errors.Is(err, townlock.ErrHeld) {
+		t.Fatalf("err = %v, want ErrHeld", err)
+	}
+
+	// doctor only reads, but doctor --fix rewrites routes and configs.
+	doctor := &cobra.Command{Use: "doctor"}
+	doctor.Flags().Bool("fix", false, "")
+	(&cobra.Command{Use: "gt"}).AddCommand(doctor)
+	if err := enforceTownLock(doctor); err != nil {
+		t.Errorf("gt doctor held: %v", err)
+	}
+	if err := doctor.Flags().Set("fix", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := enforceTownLock(doctor); !errors.Is(err, townlock.ErrHeld) {
+		t.Errorf("gt doctor --fix: err = %v, want ErrHeld", err)
+	}
+
+	// Reads, the lock commands and the holder pass.
+	for _, c := range [][2]string{{"convoy", "list"}, {"town", "unlock"}, {"mail", "inbox"}} {
+		if err := enforceTownLock(accessTestCommand(c[0], c[1])); err != nil {
+			t.Errorf("gt %s %s held: %v", c[0], c[1], err)
+		}
+	}
+	t.Setenv("GT_OPERATOR", "dana")
+	if err := enforceTownLock(accessTestCommand("rig", "add")); err != nil {
+		t.Errorf("lock holder held by their own lock: %v", err)
+	}
+	t.Setenv("GT_OPERATOR", "sam")
+
+	// Agents aren't operators.
+	t.Setenv("GT_ROLE", "gastown/polecats/toast")
+	if err := enforceTownLock(accessTestCommand("rig", "add")); err != nil {
+		t.Errorf("agent held: %v", err)
+	}
+	t.Setenv("GT_ROLE", "")
+
+	t.Setenv(townlock.EnvIgnore, "1")
+	if err := enforceTownLock(accessTestCommand("rig", "add")); err != nil {
+		t.Errorf("%s=1 still held: %v", townlock.EnvIgnore, err)
+	}
+	t.Setenv(townlock.EnvIgnore, "")
+
+	path := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.TownLock = &townlock.Config{Policy: townlock.PolicyWarn}
+	if err := config.SaveTownSettings(path, settings); err != nil {
+		t.Fatal(err)
+	}
+	if err := enforceTownLock(accessTestCommand("rig", "add")); err != nil {
+		t.Errorf("warn policy refused: %v", err)
+	}
+}
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/errcode"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// AnnotationFeature marks a command (or flag) as part of an experimental
+// feature; it only runs when the town enables that feature.
+//
+// Commands: Annotations: map[string]string{AnnotationFeature: config.FeatureReplay}
+// Flags:    cmd.Flags().SetAnnotation("flag", AnnotationFeature, []string{name})
+const AnnotationFeature = "gtFeature"
+
+// versionPinExemptCommands run under any gt version, so an out-of-range
+// binary can still report itself, diagnose the town and upgrade.
+var versionPinExemptCommands = map[string]bool{
+	"help":       true,
+	"version":    true,
+	"completion": true,
+	"upgrade":    true,
+	"doctor":     true,
+}
+
+// enforceTownPolicy refuses to run a command when this gt is outside the
+// town's version pin, or when the command belongs to an experimental
+// feature the town hasn't enabled. Running mixed gt versions against one
+// town has corrupted state before, so the pin applies to agents too.
+func enforceTownPolicy(cmd *cobra.Command) error {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return nil
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	if err := checkVersionPin(cmd, settings.GTVersion); err != nil {
+		return err
+	}
+	return checkFeatureGate(cmd, settings)
+}
+
+// checkVersionPin applies the town's gt_version pin to this binary.
+func checkVersionPin(cmd *cobra.Command, pin *config.VersionPin) error {
+	if versionPinExemptCommands[topLevelCommand(cmd).Name()] {
+		return nil
+	}
+	err := pin.Check(Version)
+	if err == nil {
+		return nil
+	}
+	if os.Getenv(config.EnvIgnoreVersionPin) == "1" {
+		style.PrintWarning("%v (ignored: %s=1)", err, config.EnvIgnoreVersionPin)
+		return nil
+	}
+	return fmt.Errorf("%w\n  Install an allowed version: gt upgrade --self --to %s\n  (or set %s=1 to run anyway)", err, pin.Nearest(Version), config.EnvIgnoreVersionPin)
+}
+
+// checkFeatureGate refuses commands and flags annotated with a feature the
+// town hasn't enabled.
+func checkFeatureGate(cmd *cobra.Command, settings *config.TownSettings) error {
+	for c := cmd; c != nil; c = c.Parent() {
+		if name := c.Annotations[AnnotationFeature]; name != "" && !settings.FeatureEnabled(name) {
+			return featureDisabledError(name, "gt "+accessCommandPath(c))
+		}
+	}
+	var gated error
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if names := f.Annotations[AnnotationFeature]; gated == nil && len(names) > 0 && !settings.FeatureEnabled(names[0]) {
+			gated = featureDisabledError(names[0], "--"+f.Name)
+		}
+	})
+	return gated
+}
+
+func featureDisabledError(name, what string) error {
+	return errcode.New(errcode.FeatureDisabled, fmt.Errorf("%s is part of the experimental %q feature, which is off for this town\n  Enable it: gt config feature %s on (or %s=1 for one command)",
+		what, name, name, config.FeatureEnvVar(name))).WithHint("gt config feature " + name + " on")
+}
+
+// topLevelCommand returns the child of the root command that cmd belongs to
+// ("gt config get" → config).
+func topLevelCommand(cmd *cobra.Command) *cobra.Command {
+	for cmd.HasParent() && cmd.Parent().HasParent() {
+		cmd = cmd.Parent()
+	}
+	return cmd
+}
+
+var (
+	configVersionPinMin   string
+	configVersionPinMax   string
+	configVersionPinClear bool
+)
+
+var configVersionPinCmd = &cobra.Command{
+	Use:   "version-pin",
+	Short: "Show or set the gt versions allowed to run against this town",
+	Long: `Show or set the town's gt version pin.
+
+Every gt command (except help, version, completion, upgrade and doctor)
+refuses to run when the binary is outside the pin, so a stale or
+too-new gt can't write state the rest of the town doesn't understand.
+Both bounds are inclusive. gt upgrade --self refuses releases outside
+the pin unless --force.
+
+With no flags, shows the pin, the running version and the version that
+created the town. Set GT_IGNORE_VERSION_PIN=1 to bypass the pin for one
+command.
+
+Examples:
+  gt config version-pin                        # Show the pin
+  gt config version-pin --min 0.12.0           # Require gt 0.12.0 or newer
+  gt config version-pin --min 0.12.0 --max 0.12.9
+  gt config version-pin --clear                # Allow any version`,
+	Args: cobra.NoArgs,
+	RunE: runConfigVersionPin,
+}
+
+var configFeatureCmd = &cobra.Command{
+	Use:   "feature [name] [on|off]",
+	Short: "List or toggle experimental features",
+	Long: `List or toggle the town's experimental features.
+
+Experimental subsystems are off until the town enables them. Commands and
+flags that belong to a disabled feature refuse to run. GT_FEATURE_<NAME>=1
+(or 0) overrides the town setting for one command.
+
+Examples:
+  gt config feature                 # List features and their state
+  gt config feature replay          # Show one feature
+  gt config feature replay on       # Enable gt replay for the town
+  gt config feature experiments off # Disable gt experiment`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runConfigFeature,
+}
+
+func init() {
+	configVersionPinCmd.Flags().StringVar(&configVersionPinMin, "min", "", "Oldest gt version allowed (inclusive)")
+	configVersionPinCmd.Flags().StringVar(&configVersionPinMax, "max", "", "Newest gt version allowed (inclusive)")
+	configVersionPinCmd.Flags().BoolVar(&configVersionPinClear, "clear", false, "Remove the pin")
+
+	configCmd.AddCommand(configVersionPinCmd)
+	configCmd.AddCommand(configFeatureCmd)
+}
+
+func runConfigVersionPin(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+	settingsPath := config.TownSettingsPath(townRoot)
+	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+
+	setting := cmd.Flags().Changed("min") || cmd.Flags().Changed("max")
+	if !setting && !configVersionPinClear {
+		fmt.Printf("Allowed gt versions: %s\n", style.Bold.Render(townSettings.GTVersion.String()))
+		fmt.Printf("Running:             gt %s\n", Version)
+		if tc, err := config.LoadTownConfig(filepath.Join(townRoot, "mayor", "town.json")); err == nil && tc.CreatedWith != "" {
+			fmt.Printf("Town created with:   gt %s\n", tc.CreatedWith)
+		}
+		if !townSettings.GTVersion.Allows(Version) {
+			fmt.Printf("%s This binary is outside the pin\n", style.WarningPrefix)
+		}
+		return nil
+	}
+
+	if configVersionPinClear {
+		townSettings.GTVersion = nil
+	} else {
+		pin := &config.VersionPin{}
+		if townSettings.GTVersion != nil {
+			*pin = *townSettings.GTVersion
+		}
+		if cmd.Flags().Changed("min") {
+			pin.Min = configVersionPinMin
+		}
+		if cmd.Flags().Changed("max") {
+			pin.Max = configVersionPinMax
+		}
+		if err := pin.Validate(); err != nil {
+			return err
+		}
+		// Refuse a pin that excludes the binary setting it: every later
+		// command would be refused until someone upgrades.
+		if !pin.Allows(Version) {
+			return fmt.Errorf("pin %s would exclude this binary (gt %s); upgrade first", pin, Version)
+		}
+		townSettings.GTVersion = pin
+		if pin.Min == "" && pin.Max == "" {
+			townSettings.GTVersion = nil
+		}
+	}
+
+	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+	fmt.Printf("%s Allowed gt versions: %s\n", style.SuccessPrefix, townSettings.GTVersion)
+	return nil
+}
+
+func runConfigFeature(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+	settingsPath := config.TownSettingsPath(townRoot)
+	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+
+	names := config.ExperimentalFeatures()
+	if len(args) > 0 {
+		if err := config.ValidateFeature(args[0]); err != nil {
+			return err
+		}
+		names = args[:1]
+	}
+	if len(args) < 2 {
+		for _, name := range names {
+			state := style.Dim.Render("off")
+			if townSettings.FeatureEnabled(name) {
+				state = style.Success.Render("on")
+			}
+			fmt.Printf("  %-12s %-3s  %s\n", name, state, config.FeatureDescription(name))
+		}
+		return nil
+	}
+
+	name := args[0]
+	state := args[1]
+	switch state {
+	case "on", "true", "1":
+		state = "on"
+	case "off", "false", "0":
+		state = "off"
+	default:
+		return fmt.Errorf("invalid state %q (want on or off)", args[1])
+	}
+	if state == "on" {
+		if townSettings.Features == nil {
+			townSettings.Features = make(map[string]bool)
+		}
+		townSettings.Features[name] = true
+	} else {
+		delete(townSettings.Features, name)
+	}
+	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+	fmt.Printf("%s Feature %s is %s\n", style.SuccessPrefix, name, state)
+	if env := os.Getenv(config.FeatureEnvVar(name)); env != "" {
+		fmt.Printf("  %s %s=%s overrides this in the current shell\n", style.WarningPrefix, config.FeatureEnvVar(name), env)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func setupTownPolicyTest(t *testing.T, edit func(*config.TownSettings)) string {
+	t.Helper()
+	townRoot := setupTestTownForConfig(t)
+	t.Chdir(townRoot)
+	path := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edit(settings)
+	if err := config.SaveTownSettings(path, settings); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(config.EnvIgnoreVersionPin, "")
+	return townRoot
+}
+
+func withVersion(t *testing.T, v string) {
+	t.Helper()
+	old := Version
+	Version = v
+	t.Cleanup(func() { Version = old })
+}
+
+func TestEnforceTownPolicy_VersionPin(t *testing.T) {
+	setupTownPolicyTest(t, func(s *config.TownSettings) {
+		s.GTVersion = &config.VersionPin{Min: "0.12.0", Max: "0.13.4"}
+	})
+
+	withVersion(t, "0.12.5")
+	if err := enforceTownPolicy(accessTestCommand("mail", "send")); err != nil {
+		t.Errorf("allowed version refused: %v", err)
+	}
+
+	withVersion(t, "0.11.0")
+	err := enforceTownPolicy(accessTestCommand("mail", "send"))
+	if !errors.Is(err, config.ErrVersionPinned) {
+		t.Fatalf("err = %v, want ErrVersionPinned", err)
+	}
+	if !strings.Contains(err.Error(), "--to 0.12.0") {
+		t.Errorf("error should suggest upgrading to the minimum: %v", err)
+	}
+
+	// The way out must stay open.
+	for _, exempt := range []string{"upgrade", "doctor", "version"} {
+		root := &cobra.Command{Use: "gt"}
+		c := &cobra.Command{Use: exempt}
+		root.AddCommand(c)
+		if err := enforceTownPolicy(c); err != nil {
+			t.Errorf("gt %s refused: %v", exempt, err)
+		}
+	}
+
+	t.Setenv(config.EnvIgnoreVersionPin, "1")
+	if err := enforceTownPolicy(accessTestCommand("mail", "send")); err != nil {
+		t.Errorf("%s=1 should bypass the pin: %v", config.EnvIgnoreVersionPin, err)
+	}
+}
+
+func TestEnforceTownPolicy_FeatureGate(t *testing.T) {
+	setupTownPolicyTest(t, func(s *config.TownSettings) {})
+
+	report := accessTestCommand("experiment", "report")
+	report.Parent().Annotations = map[string]string{AnnotationFeature: config.FeatureExperiments}
+	err := enforceTownPolicy(report)
+	if err == nil || !strings.Contains(err.Error(), "gt config feature experiments on") {
+		t.Errorf("disabled feature: err = %v", err)
+	}
+
+	t.Setenv(config.FeatureEnvVar(config.FeatureExperiments), "1")
+	if err := enforceTownPolicy(report); err != nil {
+		t.Errorf("GT_FEATURE_EXPERIMENTS=1: %v", err)
+	}
+}
+
+func TestEnforceTownPolicy_FeatureGatedFlag(t *testing.T) {
+	setupTownPolicyTest(t, func(s *config.TownSettings) {})
+
+	sling := accessTestCommand("sling", "")
+	sling.Flags().String("experiment", "", "")
+	_ = sling.Flags().SetAnnotation("experiment", AnnotationFeature, []string{config.FeatureExperiments})
+	if err := enforceTownPolicy(sling); err != nil {
+		t.Errorf("unset gated flag refused: %v", err)
+	}
+	_ = sling.Flags().Set("experiment", "prompt-v2")
+	if err := enforceTownPolicy(sling); err == nil || !strings.Contains(err.Error(), "--experiment") {
+		t.Errorf("gated flag: err = %v", err)
+	}
+}
+
+func TestExperimentalCommandsAreGated(t *testing.T) {
+	for cmd, feature := range map[*cobra.Command]string{
+		experimentCmd:   config.FeatureExperiments,
+		replayCmd:       config.FeatureReplay,
+		townSimulateCmd: config.FeatureSimulate,
+	} {
+		if got := cmd.Annotations[AnnotationFeature]; got != feature {
+			t.Errorf("%s: feature = %q, want %q", cmd.CommandPath(), got, feature)
+		}
+	}
+}
+
+func TestConfigFeature_Toggle(t *testing.T) {
+	townRoot := setupTownPolicyTest(t, func(s *config.TownSettings) {})
+
+	if err := runConfigFeature(configFeatureCmd, []string{"replay", "on"}); err != nil {
+		t.Fatal(err)
+	}
+	settings, _ := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if !settings.FeatureEnabled(config.FeatureReplay) {
+		t.Error("replay should be on")
+	}
+	if err := runConfigFeature(configFeatureCmd, []string{"teleport", "on"}); !errors.Is(err, config.ErrUnknownFeature) {
+		t.Errorf("unknown feature: err = %v", err)
+	}
+}
+
+func TestConfigVersionPin_RefusesToExcludeSelf(t *testing.T) {
+	townRoot := setupTownPolicyTest(t, func(s *config.TownSettings) {})
+	withVersion(t, "0.12.0")
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringVar(&configVersionPinMin, "min", "", "")
+	cmd.Flags().StringVar(&configVersionPinMax, "max", "", "")
+	t.Cleanup(func() { configVersionPinMin, configVersionPinMax = "", "" })
+
+	_ = cmd.Flags().Set("min", "0.13.0")
+	if err := runConfigVersionPin(cmd, nil); err == nil || !strings.Contains(err.Error(), "exclude this binary") {
+		t.Errorf("err = %v, want refusal", err)
+	}
+
+	_ = cmd.Flags().Set("min", "0.12.0")
+	_ = cmd.Flags().Set("max", "0.12.9")
+	if err := runConfigVersionPin(cmd, nil); err != nil {
+		t.Fatal(err)
+	}
+	settings, _ := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if settings.GTVersion == nil || settings.GTVersion.String() != ">=0.12.0, <=0.12.9" {
+		t.Errorf("GTVersion = %v", settings.GTVersion)
+	}
+}
@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/freeze"
+	"github.com/steveyegge/gastown/internal/retro"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/townstate"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	townRebuildRepair bool
+	townRebuildJSON   bool
+)
+
+var townRebuildStateCmd = &cobra.Command{
+	Use:   "rebuild-state",
+	Short: "Check town state against the event log and repair drift",
+	Long: `Rebuild critical town state from the event log and compare it with
+what the town holds now.
+
+The event log (.events.jsonl) records every change to:
+
+  assignments  which agent each bead is hooked to (sling, hook, unhook, done)
+  queue        beads scheduled for deferred dispatch (sling context beads)
+  freezes      rig change freezes (.runtime/freeze/)
+
+Each disagreement is reported as drift. With --repair, drift the log
+explains is fixed from the log: beads are re-hooked to the agent the log
+says holds them, missing sling contexts are recreated (without formula
+arguments, which the log doesn't keep), stale ones are closed, and freeze
+files are rewritten or removed. Drift the log can't explain (state older
+than the log, or a hook still held after gt done) is only reported.
+
+Exits 1 when drift remains.
+
+Examples:
+  gt town rebuild-state             # Report drift
+  gt town rebuild-state --repair    # Repair what the log explains
+  gt town rebuild-state --json`,
+	Args: cobra.NoArgs,
+	RunE: runTownRebuildState,
+}
+
+var (
+	// townStateBeadFn is a seam for tests. Production looks the bead up with
+	// getBeadInfo.
+	townStateBeadFn = func(id string) (townstate.Bead, bool) {
+		info, err := getBeadInfo(id)
+		if err != nil {
+			return townstate.Bead{}, false
+		}
+		return townstate.Bead{Status: info.Status, Assignee: info.Assignee}, true
+	}
+
+	// townStateListHookedFn is a seam for tests. Production lists hooked beads
+	// in every database.
+	townStateListHookedFn = func(townRoot string) (map[string]string, error) {
+		dirs := []string{townRoot}
+		if rigs, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json")); err == nil {
+			for name := range rigs.Rigs {
+				dirs = append(dirs, rigBeadsWorkDir(townRoot, name))
+			}
+		}
+		hooked := map[string]string{}
+		for _, dir := range dirs {
+			issues, err := beads.New(dir).List(beads.ListOptions{Status: beads.StatusHooked, Priority: -1})
+			if err != nil {
+				return nil, fmt.Errorf("listing hooked beads in %s: %w", dir, err)
+			}
+			for _, issue := range issues {
+				hooked[issue.ID] = issue.Assignee
+			}
+		}
+		return hooked, nil
+	}
+
+	// townStateListQueuedFn is a seam for tests. Production lists open sling
+	// contexts.
+	townStateListQueuedFn = func(townRoot string) (rigs, contexts map[string]string, err error) {
+		issues, err := beads.NewWithBeadsDir(townRoot, filepath.Join(townRoot, ".beads")).ListOpenSlingContexts()
+		if err != nil {
+			return nil, nil, fmt.Errorf("listing sling contexts: %w", err)
+		}
+		rigs, contexts = map[string]string{}, map[string]string{}
+		for _, issue := range issues {
+			if f := beads.ParseSlingContextFields(issue.Description); f != nil && f.WorkBeadID != "" {
+				rigs[f.WorkBeadID] = f.TargetRig
+				contexts[f.WorkBeadID] = issue.ID
+			}
+		}
+		return rigs, contexts, nil
+	}
+
+	// townStateHookFn is a seam for tests. Production hooks the bead with bd
+	// update.
+	townStateHookFn = func(id, target string) error {
+		return BdCmd("update", id, "--status="+beads.StatusHooked, "--assignee="+target).
+			Dir(resolveBeadDir(id)).StripBeadsDir().Stderr(io.Discard).Run()
+	}
+
+	// townStateEnqueueFn is a seam for tests. Production creates a sling
+	// context.
+	townStateEnqueueFn = func(townRoot, id, rig string) error {
+		title := id
+		if info, err := getBeadInfo(id); err == nil {
+			title = info.Title
+		}
+		_, err := beads.NewWithBeadsDir(townRoot, filepath.Join(townRoot, ".beads")).CreateSlingContext(title, id, &capacity.SlingContextFields{
+			Version:    1,
+			WorkBeadID: id,
+			TargetRig:  rig,
+			EnqueuedAt: time.Now().UTC().Format(time.RFC3339),
+		})
+		return err
+	}
+
+	// townStateDequeueFn is a seam for tests. Production closes the sling
+	// context.
+	townStateDequeueFn = func(townRoot, contextID string) error {
+		return beads.NewWithBeadsDir(townRoot, filepath.Join(townRoot, ".beads")).CloseSlingContext(contextID, "dispatched per event log (gt town rebuild-state)")
+	}
+)
+
+func init() {
+	townRebuildStateCmd.Flags().BoolVar(&townRebuildRepair, "repair", false, "Repair drift the event log explains")
+	townRebuildStateCmd.Flags().BoolVar(&townRebuildJSON, "json", false, "Output as JSON")
+	townCmd.AddCommand(townRebuildStateCmd)
+}
+
+func runTownRebuildState(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	evs, err := retro.LoadEvents(townRoot, time.Time{})
+	if err != nil {
+		return fmt.Errorf("reading event log: %w", err)
+	}
+	want := townstate.Replay(evs)
+	have, contexts, err := observeTownState(townRoot, want)
+	if err != nil {
+		return err
+	}
+	drifts := townstate.Compare(want, have, time.Now())
+
+	var repaired []townstate.Drift
+	var failed []string
+	if townRebuildRepair {
+		remaining := drifts[:0:0]
+		for _, d := range drifts {
+			if !d.Repairable() {
+				remaining = append(remaining, d)
+				continue
+			}
+			if err := repairTownDrift(townRoot, d, want, contexts); err != nil {
+				failed = append(failed, fmt.Sprintf("%s %s: %v", d.Kind, d.Subject, err))
+				remaining = append(remaining, d)
+				continue
+			}
+			repaired = append(repaired, d)
+		}
+		drifts = remaining
+	}
+
+	if townRebuildJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(map[string]any{"state": want, "drift": drifts, "repaired": repaired, "failed": failed}); err != nil {
+			return err
+		}
+	} else {
+		printTownRebuild(want, drifts, repaired, failed)
+	}
+	if len(drifts) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// observeTownState gathers the town's current assignments, queue and
+// freezes, and the sling context holding each queued bead.
+func observeTownState(townRoot string, want *townstate.State) (*townstate.Observed, map[string]string, error) {
+	have := &townstate.Observed{Beads: map[string]townstate.Bead{}, Frozen: map[string]townstate.Freeze{}}
+	for id, h := range want.Hooks {
+		if h.Hooked {
+			if b, ok := townStateBeadFn(id); ok {
+				have.Beads[id] = b
+			}
+		}
+	}
+	for id, q := range want.Queue {
+		if _, seen := have.Beads[id]; q.Queued && !seen {
+			if b, ok := townStateBeadFn(id); ok {
+				have.Beads[id] = b
+			}
+		}
+	}
+
+	var err error
+	if have.Hooked, err = townStateListHookedFn(townRoot); err != nil {
+		return nil, nil, err
+	}
+	var contexts map[string]string
+	if have.Queued, contexts, err = townStateListQueuedFn(townRoot); err != nil {
+		return nil, nil, err
+	}
+	freezes, err := freeze.List(townRoot)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing freezes: %w", err)
+	}
+	for _, f := range freezes {
+		have.Frozen[f.Rig] = townstate.Freeze{Rig: f.Rig, Frozen: true, Reason: f.Reason, By: f.By, From: f.From, Until: f.Until}
+	}
+	return have, contexts, nil
+}
+
+// repairTownDrift makes the town agree with the event log for one drift.
+func repairTownDrift(townRoot string, d townstate.Drift, want *townstate.State, contexts map[string]string) error {
+	switch d.Repair {
+	case townstate.RepairHook:
+		return townStateHookFn(d.Subject, want.Hooks[d.Subject].Target)
+	case townstate.RepairEnqueue:
+		return townStateEnqueueFn(townRoot, d.Subject, want.Queue[d.Subject].Rig)
+	case townstate.RepairDequeue:
+		return townStateDequeueFn(townRoot, contexts[d.Subject])
+	case townstate.RepairFreeze:
+		f := want.Freezes[d.Subject]
+		return freeze.Save(townRoot, &freeze.Freeze{Rig: f.Rig, Reason: f.Reason, By: f.By, From: f.From, Until: f.Until, CreatedAt: f.At})
+	case townstate.RepairUnfreeze:
+		return freeze.Remove(townRoot, d.Subject)
+	}
+	return fmt.Errorf("no repair for %s drift", d.Kind)
+}
+
+func printTownRebuild(want *townstate.State, drifts, repaired []townstate.Drift, failed []string) {
+	hooked, queued, frozen := 0, 0, 0
+	for _, h := range want.Hooks {
+		if h.Hooked {
+			hooked++
+		}
+	}
+	for _, q := range want.Queue {
+		if q.Queued {
+			queued++
+		}
+	}
+	for _, f := range want.Freezes {
+		if f.Frozen {
+			frozen++
+		}
+	}
+	since := "empty log"
+	if want.Events > 0 {
+		since = "since " + want.First.Local().Format("Jan 02 15:04")
+	}
+	fmt.Printf("Replayed %d events (%s): %d hooked, %d queued, %d frozen\n", want.Events, since, hooked, queued, frozen)
+
+	for _, d := range repaired {
+		fmt.Printf("  %s %-10s %s: %s (was %s)\n", style.Success.Render("✓"), d.Kind, d.Subject, d.Want, d.Have)
+	}
+	for _, f := range failed {
+		fmt.Printf("  %s repair failed: %s\n", style.Error.Render("✗"), f)
+	}
+	for _, d := range drifts {
+		mark := style.Warning.Render("⚠")
+		note := ""
+		if !d.Repairable() {
+			mark = style.Dim.Render("?")
+			note = style.Dim.Render(" (not repairable from the log)")
+		}
+		fmt.Printf("  %s %-10s %s: log says %s, town has %s%s\n", mark, d.Kind, d.Subject, d.Want, d.Have, note)
+	}
+
+	switch {
+	case len(drifts) == 0 && len(repaired) == 0:
+		fmt.Printf("%s Town state matches the event log\n", style.SuccessPrefix)
+	case len(drifts) == 0:
+		fmt.Printf("%s Repaired %d drift(s)\n", style.SuccessPrefix, len(repaired))
+	case !townRebuildRepair:
+		fmt.Printf("\n%d drift(s). Run %s to fix what the log explains.\n", len(drifts), style.Bold.Render("gt town rebuild-state --repair"))
+	}
+}
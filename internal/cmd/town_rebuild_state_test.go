@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/freeze"
+	"github.com/steveyegge/gastown/internal/townstate"
+)
+
+func TestRepairTownDrift(t *testing.T) {
+	town := t.TempDir()
+	from := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	want := &townstate.State{
+		Hooks:   map[string]townstate.Hook{"gt-a": {Target: "gastown/polecats/Toast", Hooked: true}},
+		Queue:   map[string]townstate.Queued{"gt-q": {Rig: "gastown", Queued: true}},
+		Freezes: map[string]townstate.Freeze{"beads": {Rig: "beads", Frozen: true, Reason: "release", From: from}},
+	}
+
+	var calls []string
+	origHook, origEnq, origDeq := townStateHookFn, townStateEnqueueFn, townStateDequeueFn
+	t.Cleanup(func() { townStateHookFn, townStateEnqueueFn, townStateDequeueFn = origHook, origEnq, origDeq })
+	townStateHookFn = func(id, target string) error { calls = append(calls, "hook "+id+" "+target); return nil }
+	townStateEnqueueFn = func(_, id, rig string) error { calls = append(calls, "enqueue "+id+" "+rig); return nil }
+	townStateDequeueFn = func(_, ctx string) error { calls = append(calls, "dequeue "+ctx); return nil }
+
+	drifts := []townstate.Drift{
+		{Kind: townstate.KindAssignment, Subject: "gt-a", Repair: townstate.RepairHook},
+		{Kind: townstate.KindQueue, Subject: "gt-q", Repair: townstate.RepairEnqueue},
+		{Kind: townstate.KindQueue, Subject: "gt-d", Repair: townstate.RepairDequeue},
+		{Kind: townstate.KindFreeze, Subject: "beads", Repair: townstate.RepairFreeze},
+	}
+	for _, d := range drifts {
+		if err := repairTownDrift(town, d, want, map[string]string{"gt-d": "hq-ctx1"}); err != nil {
+			t.Fatalf("%+v: %v", d, err)
+		}
+	}
+	wantCalls := []string{"hook gt-a gastown/polecats/Toast", "enqueue gt-q gastown", "dequeue hq-ctx1"}
+	if len(calls) != 3 || calls[0] != wantCalls[0] || calls[1] != wantCalls[1] || calls[2] != wantCalls[2] {
+		t.Errorf("calls = %v", calls)
+	}
+	f, err := freeze.Load(town, "beads")
+	if err != nil || f == nil || f.Reason != "release" || !f.From.Equal(from) {
+		t.Fatalf("freeze after repair = %+v, %v", f, err)
+	}
+
+	if err := repairTownDrift(town, townstate.Drift{Kind: townstate.KindFreeze, Subject: "beads", Repair: townstate.RepairUnfreeze}, want, nil); err != nil {
+		t.Fatal(err)
+	}
+	if f, _ := freeze.Load(town, "beads"); f != nil {
+		t.Errorf("freeze still present: %+v", f)
+	}
+	if err := repairTownDrift(town, townstate.Drift{Kind: townstate.KindAssignment, Subject: "gt-x"}, want, nil); err == nil {
+		t.Error("an unrepairable drift should fail")
+	}
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/sim"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	townSimulateJSON     bool
+	townSimulateTimeline bool
+)
+
+var townSimulateCmd = &cobra.Command{
+	Use:         "simulate <scenario.toml>",
+	Annotations: map[string]string{AnnotationFeature: config.FeatureSimulate},
+	Short:       "Run orchestration against scripted agents",
+	Long: `Simulate a town end-to-end with scripted agents instead of LLM sessions.
+
+A scenario file lists the beads to sling, when they arrive, the formula
+each one runs, and a script for its polecat: work for a while, finish
+formula steps, escalate, stall, crash or fail to spawn, then gt done. The
+simulation drives the real scheduler dispatch cycle, formula step order,
+witness stall handling and escalation ladder on a virtual clock, and
+reports what happened. Nothing starts tmux sessions or touches beads.
+
+Use it to check orchestration changes — queueing, escalation routing, new
+formulas — before they meet real agents. When run inside a town, the
+town's escalation config and formulas are used unless the scenario
+overrides them. An [expect] table in the scenario turns the run into a
+test: gt town simulate exits 1 if any expectation fails.
+
+Examples:
+  gt town simulate scenarios/escalation.toml
+  gt town simulate scenarios/capacity.toml --timeline
+  gt town simulate scenarios/capacity.toml --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTownSimulate,
+}
+
+func init() {
+	townSimulateCmd.Flags().BoolVar(&townSimulateJSON, "json", false, "Output the report as JSON")
+	townSimulateCmd.Flags().BoolVarP(&townSimulateTimeline, "timeline", "t", false, "Print every event")
+	townCmd.AddCommand(townSimulateCmd)
+}
+
+func runTownSimulate(cmd *cobra.Command, args []string) error {
+	path := args[0]
+	searchPaths := []string{filepath.Dir(path)}
+	var escalation *config.EscalationConfig
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		searchPaths = append(searchPaths, filepath.Join(townRoot, ".beads", "formulas"))
+		if cfg, err := config.LoadEscalationConfig(config.EscalationConfigPath(townRoot)); err == nil {
+			escalation = cfg
+		}
+	}
+
+	scenario, err := sim.LoadScenario(path, searchPaths)
+	if err != nil {
+		return err
+	}
+	report, err := sim.Run(scenario, sim.Options{Escalation: escalation})
+	if err != nil {
+		return fmt.Errorf("simulating %s: %w", path, err)
+	}
+	failures := report.Check(scenario.Expect)
+
+	if townSimulateJSON {
+		out := struct {
+			*sim.Report
+			Failures []string `json:"failures,omitempty"`
+		}{report, failures}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	} else {
+		printSimReport(report, townSimulateTimeline)
+		printSimFailures(failures)
+	}
+	if len(failures) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func printSimReport(r *sim.Report, timeline bool) {
+	name := r.Scenario
+	if name == "" {
+		name = "scenario"
+	}
+	fmt.Printf("%s Simulated %s: %s\n\n", style.Bold.Render("🧪"), name, formatDuration(r.Elapsed.Duration))
+
+	if timeline {
+		for _, e := range r.Events {
+			line := fmt.Sprintf("  %8s  %-16s %s", formatSimOffset(e.At.Duration), e.Kind, e.Bead)
+			if e.Polecat != "" {
+				line += " (" + e.Polecat + ")"
+			}
+			if e.Detail != "" {
+				line += "  " + style.Dim.Render(e.Detail)
+			}
+			fmt.Println(line)
+		}
+		fmt.Println()
+	}
+
+	for _, b := range r.Beads {
+		line := fmt.Sprintf("  %-14s %-12s", b.ID, b.Status)
+		if cycle, ok := b.CycleTime(); ok {
+			line += " cycle " + formatDuration(cycle)
+		}
+		if b.Attempts > 1 {
+			line += fmt.Sprintf(", %d attempts", b.Attempts)
+		}
+		if b.DispatchFailures > 0 {
+			line += fmt.Sprintf(", %d dispatch failures", b.DispatchFailures)
+		}
+		if b.Escalations > 0 {
+			line += fmt.Sprintf(", escalated (%s)", b.MaxSeverity)
+		}
+		if b.OpenSteps > 0 {
+			line += fmt.Sprintf(", %d formula steps open", b.OpenSteps)
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Println()
+	capacity := fmt.Sprintf("direct dispatch, %d polecats peak", r.PeakPolecats)
+	if r.MaxPolecats > 0 {
+		capacity = fmt.Sprintf("%d/%d polecats peak, %.0f%% utilized", r.PeakPolecats, r.MaxPolecats, r.Utilization*100)
+	}
+	fmt.Printf("  Capacity:    %s, max queue depth %d\n", capacity, r.MaxQueueDepth)
+	if r.Escalations > 0 {
+		fmt.Printf("  Escalations: %d raised, %d re-escalated, highest %s\n", r.Escalations, r.Reescalations, r.MaxSeverity)
+	}
+}
+
+func printSimFailures(failures []string) {
+	if len(failures) == 0 {
+		return
+	}
+	fmt.Printf("\n%s %d expectation(s) failed:\n", style.Error.Render("✗"), len(failures))
+	for _, f := range failures {
+		fmt.Printf("  - %s\n", f)
+	}
+}
+
+// formatSimOffset renders a timeline offset as h:mm.
+func formatSimOffset(d time.Duration) string {
+	return fmt.Sprintf("+%d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
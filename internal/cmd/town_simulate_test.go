@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTestScenario(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "scenario.toml")
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunTownSimulate(t *testing.T) {
+	t.Chdir(t.TempDir())
+	prevTimeline := townSimulateTimeline
+	t.Cleanup(func() { townSimulateTimeline = prevTimeline })
+	townSimulateTimeline = true
+
+	path := writeTestScenario(t, `
+name = "pair"
+
+[scheduler]
+max_polecats = 1
+
+[[bead]]
+id = "gt-a"
+[[bead]]
+id = "gt-b"
+script = "flaky"
+
+[script.flaky]
+steps = [{ do = "crash" }, { do = "work", for = "15m" }, { do = "done" }]
+
+[expect]
+all_closed = true
+`)
+	var err error
+	out := captureStdout(t, func() { err = runTownSimulate(nil, []string{path}) })
+	if err != nil {
+		t.Fatalf("runTownSimulate: %v\n%s", err, out)
+	}
+	for _, want := range []string{"Simulated pair", "crashed", "2 attempts", "1/1 polecats peak"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunTownSimulate_FailedExpectation(t *testing.T) {
+	t.Chdir(t.TempDir())
+	path := writeTestScenario(t, `
+[[bead]]
+id = "gt-a"
+
+[expect]
+within = "10m"
+`)
+	var err error
+	out := captureStdout(t, func() { err = runTownSimulate(nil, []string{path}) })
+	if code, ok := IsSilentExit(err); !ok || code != 1 {
+		t.Fatalf("err = %v, want silent exit 1", err)
+	}
+	if !strings.Contains(out, "expectation(s) failed") || !strings.Contains(out, "gt-a closed at 30m0s") {
+		t.Errorf("output = %s", out)
+	}
+}
+
+func TestFormatSimOffset(t *testing.T) {
+	if got := formatSimOffset(2*time.Hour + 5*time.Minute); got != "+2:05" {
+		t.Errorf("formatSimOffset = %q", got)
+	}
+}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/diskquota"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	townStatsStorage bool
+	townStatsTop     int
+	townStatsJSON    bool
+)
+
+var townStatsCmd = &cobra.Command{
+	Use:   "stats [rig...]",
+	Short: "Show town-wide statistics per rig",
+	Long: `Show per-rig statistics for the town: agent checkouts and disk used.
+
+With --storage, every file of each rig is measured and sorted into:
+
+  worktrees    polecat, crew, refinery, witness and mayor checkouts
+  transcripts  agent session transcripts (~/.claude/projects)
+  artifacts    rig runtime files, logs and anything else in the rig
+  beads        beads databases (.beads directories)
+  caches       shared build caches (build_cache, <rig>/.cache)
+
+Each category gets an age histogram by last modification (<1d, 1-7d, 7-30d,
+>30d), followed by the largest worktrees and how long they have been
+untouched. Polecats that gt disk gc may remove (idle, nothing unsaved) are
+marked, with the total it could reclaim, so you can see what to prune
+before pruning. --storage walks every file and can take a while.
+
+Examples:
+  gt town stats
+  gt town stats --storage
+  gt town stats greenplace --storage --top 10
+  gt town stats --storage --json`,
+	RunE: runTownStats,
+}
+
+func init() {
+	townStatsCmd.Flags().BoolVar(&townStatsStorage, "storage", false, "Break down storage by category and age")
+	townStatsCmd.Flags().IntVar(&townStatsTop, "top", 5, "Largest worktrees to list per rig (with --storage)")
+	townStatsCmd.Flags().BoolVar(&townStatsJSON, "json", false, "Output as JSON")
+
+	townCmd.AddCommand(townStatsCmd)
+}
+
+// rigStorage is one rig's storage breakdown and what gt disk gc could free.
+type rigStorage struct {
+	Rig         string              `json:"rig"`
+	Storage     *diskquota.Storage  `json:"storage"`
+	GCEligible  []string            `json:"gc_eligible,omitempty"` // Polecat worktrees gt disk gc may remove
+	Reclaimable int64               `json:"reclaimable"`           // Their total size
+	Quota       *diskquota.Config   `json:"quota,omitempty"`
+	idle        map[string]struct{} // Paths of GCEligible
+}
+
+func runTownStats(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigs := args
+	if len(rigs) == 0 {
+		rigs = diskRigNames(townRoot)
+	}
+	for _, rigName := range rigs {
+		if _, err := os.Stat(filepath.Join(townRoot, rigName)); err != nil {
+			return fmt.Errorf("rig '%s' not found", rigName)
+		}
+	}
+
+	if !townStatsStorage {
+		return printTownSummary(townRoot, rigs)
+	}
+
+	now := time.Now()
+	report := make([]rigStorage, 0, len(rigs))
+	for _, rigName := range rigs {
+		report = append(report, measureRigStorage(townRoot, rigName, now))
+	}
+	if townStatsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	if len(report) == 0 {
+		fmt.Println("No rigs.")
+		return nil
+	}
+	for i, rs := range report {
+		if i > 0 {
+			fmt.Println()
+		}
+		printRigStorage(rs, townStatsTop, now)
+	}
+	return nil
+}
+
+// measureRigStorage breaks a rig's storage down and finds the polecats
+// gt disk gc could remove.
+func measureRigStorage(townRoot, rigName string, now time.Time) rigStorage {
+	rigPath := filepath.Join(townRoot, rigName)
+	cacheDirs := []string{config.ResolveBuildCache(rigPath).Root(rigPath)}
+	rs := rigStorage{
+		Rig:     rigName,
+		Storage: diskquota.MeasureStorage(rigPath, cacheDirs, now),
+		idle:    make(map[string]struct{}),
+	}
+	rs.Quota, _ = loadDiskQuota(rigPath)
+	idle, err := diskIdlePolecatsFn(rigName)
+	if err != nil {
+		return rs
+	}
+	for _, w := range rs.Storage.Worktrees {
+		if w.Kind == "polecats" && idle[filepath.Base(w.Path)] {
+			rs.GCEligible = append(rs.GCEligible, filepath.Base(w.Path))
+			rs.Reclaimable += w.Bytes
+			rs.idle[w.Path] = struct{}{}
+		}
+	}
+	return rs
+}
+
+func printRigStorage(rs rigStorage, top int, now time.Time) {
+	s := rs.Storage
+	total := formatBytes(s.Total)
+	if rs.Quota.IsEnabled() && rs.Quota.LimitBytes() > 0 {
+		total += fmt.Sprintf(" (%d%% of %s quota)", s.Total*100/rs.Quota.LimitBytes(), formatBytes(rs.Quota.LimitBytes()))
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render(rs.Rig), total)
+
+	fmt.Printf("  %-12s %10s", "CATEGORY", "SIZE")
+	for _, label := range diskquota.AgeLabels {
+		fmt.Printf(" %10s", label)
+	}
+	fmt.Println()
+	for _, c := range s.Categories {
+		fmt.Printf("  %-12s %10s", c.Name, formatBytes(c.Bytes))
+		for _, n := range c.Ages {
+			cell := fmt.Sprintf("%10s", "-")
+			if n > 0 {
+				cell = fmt.Sprintf("%10s", formatBytes(n))
+			}
+			fmt.Print(" " + cell)
+		}
+		fmt.Println()
+	}
+
+	if top > 0 && len(s.Worktrees) > 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("Largest worktrees:"))
+		for i, w := range s.Worktrees {
+			if i == top {
+				fmt.Printf("    %s\n", style.Dim.Render(fmt.Sprintf("… and %d more", len(s.Worktrees)-i)))
+				break
+			}
+			line := fmt.Sprintf("    %-28s %10s  untouched %s", w.Kind+"/"+filepath.Base(w.Path),
+				formatBytes(w.Bytes), formatStorageAge(now.Sub(w.LastModified)))
+			if _, ok := rs.idle[w.Path]; ok {
+				line += " " + style.Warning.Render("(idle, gc-eligible)")
+			}
+			fmt.Println(line)
+		}
+	}
+	if rs.Reclaimable > 0 {
+		fmt.Printf("  gt disk gc %s could reclaim %s from %d idle polecat(s): %s\n",
+			rs.Rig, formatBytes(rs.Reclaimable), len(rs.GCEligible), strings.Join(rs.GCEligible, ", "))
+	}
+}
+
+// formatStorageAge renders how long ago something changed, in days once
+// past a day.
+func formatStorageAge(d time.Duration) string {
+	if d < 24*time.Hour {
+		return d.Round(time.Minute).String()
+	}
+	return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+}
+
+// printTownSummary prints each rig's checkouts and disk used, reusing
+// recent gt disk measurements.
+func printTownSummary(townRoot string, rigs []string) error {
+	type rigSummary struct {
+		Rig      string `json:"rig"`
+		Polecats int    `json:"polecats"`
+		Crew     int    `json:"crew"`
+		Disk     int64  `json:"disk"`
+	}
+	var summary []rigSummary
+	var total int64
+	for _, rigName := range rigs {
+		rd := measureRigDisk(townRoot, rigName, false)
+		rs := rigSummary{
+			Rig:      rigName,
+			Polecats: len(rd.Usage.Polecats),
+			Crew:     len(listSubdirs(filepath.Join(townRoot, rigName, "crew"))),
+			Disk:     rd.Usage.Total,
+		}
+		total += rs.Disk
+		summary = append(summary, rs)
+	}
+
+	if townStatsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summary)
+	}
+	if len(summary) == 0 {
+		fmt.Println("No rigs.")
+		return nil
+	}
+	fmt.Printf("%-16s %8s %6s %10s\n", "RIG", "POLECATS", "CREW", "DISK")
+	for _, rs := range summary {
+		fmt.Printf("%-16s %8d %6d %10s\n", rs.Rig, rs.Polecats, rs.Crew, formatBytes(rs.Disk))
+	}
+	fmt.Printf("%-16s %8s %6s %10s\n", "total", "", "", formatBytes(total))
+	fmt.Println(style.Dim.Render("Break down by category and age: gt town stats --storage"))
+	return nil
+}
+
+// listSubdirs returns the names of dir's subdirectories, skipping hidden ones.
+func listSubdirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
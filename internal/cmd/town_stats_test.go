@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMeasureRigStorageMarksGCEligible(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	town := t.TempDir()
+	for name, size := range map[string]int{"toast": 3000, "nux": 1000, "furiosa": 2000} {
+		p := filepath.Join(town, "greenplace", "polecats", name, "greenplace", "main.go")
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(strings.Repeat("x", size)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	origIdle := diskIdlePolecatsFn
+	t.Cleanup(func() { diskIdlePolecatsFn = origIdle })
+	diskIdlePolecatsFn = func(string) (map[string]bool, error) {
+		return map[string]bool{"nux": true, "furiosa": true}, nil
+	}
+
+	rs := measureRigStorage(town, "greenplace", time.Now())
+	if rs.Storage.Total != 6000 {
+		t.Errorf("Total = %d, want 6000", rs.Storage.Total)
+	}
+	if strings.Join(rs.GCEligible, ",") != "furiosa,nux" || rs.Reclaimable != 3000 {
+		t.Errorf("GCEligible = %v (%d bytes), want furiosa,nux (3000)", rs.GCEligible, rs.Reclaimable)
+	}
+}
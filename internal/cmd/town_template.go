@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/towntemplate"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	townTemplateName        string
+	townTemplateDescription string
+	townTemplateForce       bool
+	townTemplateJSON        bool
+)
+
+var townTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Export a town's structure as a template, or apply one",
+	Long: `Share a town's setup instead of rebuilding it from scratch.
+
+A town template holds the town's structure: settings (agents, role
+assignments, policies), role definitions and briefings, active personas,
+the town's own formulas, and each rig's settings as a rig template. It
+holds no code, beads or runtime state, and export strips secrets:
+environment variables, the people in the access policy and mayor shards.
+The manifest (template.json) lists everything that was stripped.
+
+A template is a plain directory, so publish it as a git repository and
+apply it by URL.
+
+Examples:
+  gt town template export ~/templates/acme
+  gt town template show https://github.com/acme/gt-template.git
+  gt town template apply ~/templates/acme
+  gt install ~/gt --template https://github.com/acme/gt-template.git
+  gt rig add api https://github.com/acme/api.git --template backend`,
+	RunE: requireSubcommand,
+}
+
+var townTemplateExportCmd = &cobra.Command{
+	Use:   "export <dir>",
+	Short: "Write this town's structure as a template",
+	Long: `Write this town's structure into dir as a shareable template.
+
+dir must be empty or not exist yet. Stock formulas that gt provisions on
+every install are left out; modified ones are kept.
+
+Examples:
+  gt town template export ~/templates/acme
+  gt town template export ./tpl --name acme -d "Acme backend defaults"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTownTemplateExport,
+}
+
+var townTemplateApplyCmd = &cobra.Command{
+	Use:   "apply <dir|git-url>",
+	Short: "Bootstrap this town from a template",
+	Long: `Copy a template's settings, roles, formulas and rig templates into this
+town.
+
+Files the town already has are kept unless --force is given. Personas
+are saved as new versions, so gt role rollback undoes them. Settings
+stripped on export (see gt town template show) must be set by hand.
+
+Examples:
+  gt town template apply ~/templates/acme
+  gt town template apply https://github.com/acme/gt-template.git --force`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTownTemplateApply,
+}
+
+var townTemplateShowCmd = &cobra.Command{
+	Use:   "show <dir|git-url>",
+	Short: "Show what a template contains",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTownTemplateShow,
+}
+
+// cloneTownTemplateFn is a seam for tests. Production makes a shallow git
+// clone.
+var cloneTownTemplateFn = func(url, dir string) error {
+	out, err := exec.Command("git", "clone", "--depth", "1", "--", url, dir).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("cloning %s: %s", url, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func init() {
+	townTemplateExportCmd.Flags().StringVar(&townTemplateName, "name", "", "Template name (default: town directory name)")
+	townTemplateExportCmd.Flags().StringVarP(&townTemplateDescription, "description", "d", "", "What the template is for")
+	townTemplateApplyCmd.Flags().BoolVarP(&townTemplateForce, "force", "f", false, "Overwrite files the town already has")
+	townTemplateShowCmd.Flags().BoolVar(&townTemplateJSON, "json", false, "Output the manifest as JSON")
+
+	townTemplateCmd.AddCommand(townTemplateExportCmd)
+	townTemplateCmd.AddCommand(townTemplateApplyCmd)
+	townTemplateCmd.AddCommand(townTemplateShowCmd)
+	townCmd.AddCommand(townTemplateCmd)
+}
+
+func runTownTemplateExport(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	m, err := towntemplate.Export(townRoot, args[0], towntemplate.ExportOptions{
+		Name:        townTemplateName,
+		Description: townTemplateDescription,
+		GTVersion:   Version,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Exported template %s to %s\n", style.Success.Render("✓"), style.Bold.Render(m.Name), args[0])
+	printTownTemplate(m)
+	return nil
+}
+
+func runTownTemplateApply(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	return applyTownTemplate(args[0], townRoot, townTemplateForce)
+}
+
+func runTownTemplateShow(cmd *cobra.Command, args []string) error {
+	dir, cleanup, err := fetchTownTemplate(args[0])
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	m, err := towntemplate.Load(dir)
+	if err != nil {
+		return err
+	}
+	if townTemplateJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(m)
+	}
+	fmt.Printf("%s", style.Bold.Render(m.Name))
+	if m.Description != "" {
+		fmt.Printf(" — %s", m.Description)
+	}
+	fmt.Println()
+	printTownTemplate(m)
+	return nil
+}
+
+// applyTownTemplate applies the template at src (a directory or git URL)
+// to townRoot. gt install --template uses it too.
+func applyTownTemplate(src, townRoot string, force bool) error {
+	dir, cleanup, err := fetchTownTemplate(src)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+	m, err := towntemplate.Load(dir)
+	if err != nil {
+		return err
+	}
+	result, err := towntemplate.Apply(dir, townRoot, force, changeAuthor())
+	if result != nil {
+		for _, w := range result.Written {
+			fmt.Printf("   ✓ %s\n", w)
+		}
+		for _, s := range result.Skipped {
+			fmt.Printf("   %s %s (exists; --force to overwrite)\n", style.Dim.Render("-"), s)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("applying template %s: %w", m.Name, err)
+	}
+	fmt.Printf("%s Applied template %s\n", style.Success.Render("✓"), style.Bold.Render(m.Name))
+	if len(m.Stripped) > 0 {
+		fmt.Printf("   %s Set these by hand (stripped on export): %s\n", style.Warning.Render("!"), strings.Join(m.Stripped, ", "))
+	}
+	return nil
+}
+
+// fetchTownTemplate resolves src to a template directory, cloning git URLs
+// into a temporary directory that cleanup removes.
+func fetchTownTemplate(src string) (dir string, cleanup func(), err error) {
+	if !isGitRemoteURL(src) {
+		return src, func() {}, nil
+	}
+	tmp, err := os.MkdirTemp("", "gt-template-*")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup = func() { _ = os.RemoveAll(tmp) }
+	if err := cloneTownTemplateFn(src, tmp); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return tmp, cleanup, nil
+}
+
+func printTownTemplate(m *towntemplate.Manifest) {
+	list := func(label string, items []string) {
+		if len(items) > 0 {
+			fmt.Printf("  %-14s %s\n", label+":", strings.Join(items, ", "))
+		}
+	}
+	if m.Settings {
+		fmt.Printf("  %-14s %s\n", "Settings:", "settings/config.json")
+	}
+	list("Roles", m.Roles)
+	list("Personas", m.Personas)
+	list("Formulas", m.Formulas)
+	list("Rig templates", m.RigTemplates)
+	if len(m.Stripped) > 0 {
+		fmt.Printf("  %-14s %s\n", "Stripped:", style.Dim.Render(strings.Join(m.Stripped, ", ")))
+	}
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+)
+
+func TestFetchTownTemplate(t *testing.T) {
+	orig := cloneTownTemplateFn
+	t.Cleanup(func() { cloneTownTemplateFn = orig })
+	var cloned string
+	cloneTownTemplateFn = func(url, dir string) error {
+		cloned = url
+		return nil
+	}
+
+	dir, cleanup, err := fetchTownTemplate("./templates/acme")
+	if err != nil || dir != "./templates/acme" || cloned != "" {
+		t.Fatalf("local path: dir=%q cloned=%q err=%v", dir, cloned, err)
+	}
+	cleanup()
+
+	dir, cleanup, err = fetchTownTemplate("https://github.com/acme/gt-template.git")
+	if err != nil || cloned != "https://github.com/acme/gt-template.git" {
+		t.Fatalf("git URL: cloned=%q err=%v", cloned, err)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Fatalf("clone dir missing: %v", err)
+	}
+	cleanup()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("cleanup left %s behind", dir)
+	}
+}
@@ -0,0 +1,783 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/cli"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/dedup"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/timetrack"
+	"github.com/steveyegge/gastown/internal/triage"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	triageJSON     bool
+	triageDryRun   bool
+	triageEscalate bool
+	triageNoIntake bool
+	triageLabels   []string
+	triageEstimate string
+	triageNoSling  bool
+	triageMessage  string
+)
+
+var triageCmd = &cobra.Command{
+	Use:     "triage",
+	GroupID: GroupWork,
+	Short:   "Triage inbound beads: dedup, label, estimate and route to rigs",
+	Long: `Triage new beads from intake sources and route them to rigs.
+
+Intake beads are town beads labeled triage:new. They come from:
+  - GitHub: open issues of the configured repos (labeled github:<repo>)
+  - Mail: messages sent to the triage agent, triage/ (labeled mail:<sender>)
+  - Webhooks: any webhook rule whose labels include triage:new
+
+gt triage run imports new items, then decides each pending bead. A bead
+similar enough to existing work is closed as a duplicate; one whose routes
+agree is labeled, sized from the active time similar beads took
+(estimate:s/m/l/xl) and slung to its rig. The rest are left in the queue
+for the triage agent, which escalates to the human only what it can't
+decide. Configure it in the town's settings/config.json:
+
+  "triage": {
+    "github": [{"repo": "acme/app", "labels": ["bug"]}],
+    "mail": true,
+    "routes": [
+      {"rig": "app", "keywords": ["checkout", "cart"], "labels": ["github:acme/app"]},
+      {"rig": "infra", "keywords": ["deploy", "dns"], "add_labels": ["ops"]}
+    ],
+    "min_confidence": 0.7,
+    "duplicate_score": 0.8,
+    "escalate_to": "overseer"
+  }
+
+A route's confidence is its share of the matched evidence: keyword hits
+count once, label hits twice.
+
+Examples:
+  gt triage setup                    # Define the standing triage agent role
+  gt role start triage               # Start it
+  gt triage run --dry-run            # Show what a pass would do
+  gt triage queue                    # Beads waiting for a decision
+  gt triage route hq-abc12 app --estimate m`,
+	RunE: requireSubcommand,
+}
+
+var triageSetupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Define the triage agent role",
+	Long: `Define the standing triage agent: a town-scoped custom role named triage,
+with work permissions, that runs triage passes and decides what they leave.
+Start it with gt role start triage.`,
+	Args: cobra.NoArgs,
+	RunE: runTriageSetup,
+}
+
+var triageIntakeCmd = &cobra.Command{
+	Use:   "intake",
+	Short: "Import new GitHub issues and triage mail as intake beads",
+	Args:  cobra.NoArgs,
+	RunE:  runTriageIntake,
+}
+
+var triageRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Import intake and triage every pending bead",
+	Long: `Import new intake, then decide each pending bead: close clear duplicates,
+route beads whose routes agree, and leave the rest for review.
+
+Examples:
+  gt triage run
+  gt triage run --dry-run
+  gt triage run --escalate     # Mail ambiguous beads to the human instead of leaving them`,
+	Args: cobra.NoArgs,
+	RunE: runTriageRun,
+}
+
+var triageQueueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Show pending intake beads with suggested decisions",
+	Args:  cobra.NoArgs,
+	RunE:  runTriageQueue,
+}
+
+var triageRouteCmd = &cobra.Command{
+	Use:   "route <bead> <rig>",
+	Short: "Route an intake bead to a rig",
+	Long: `Label an intake bead as triaged and sling it to a rig.
+
+Examples:
+  gt triage route hq-abc12 app
+  gt triage route hq-abc12 infra --label ops --estimate l
+  gt triage route hq-abc12 app --no-sling    # Label only; the rig picks it up`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTriageRoute,
+}
+
+var triageDuplicateCmd = &cobra.Command{
+	Use:   "duplicate <bead> <of>",
+	Short: "Close an intake bead as a duplicate of another",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runTriageDuplicate,
+}
+
+var triageEscalateCmd = &cobra.Command{
+	Use:   "escalate <bead>",
+	Short: "Ask the human to decide an intake bead",
+	Long: `Mail the human the bead with the triage evidence (route candidates, likely
+duplicates) and your question, and take it out of the queue until they
+answer.
+
+Examples:
+  gt triage escalate hq-abc12 -m "Checkout or infra? The 502 only shows on /cart."`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTriageEscalate,
+}
+
+var (
+	// triageListPendingFn is a seam for tests. Production lists open beads
+	// labelled for triage.
+	triageListPendingFn = func(townRoot string) ([]*beads.Issue, error) {
+		return beads.New(townRoot).List(beads.ListOptions{Status: "open", Label: triage.LabelNew, Priority: -1})
+	}
+
+	// triageListCandidatesFn is a seam for tests. Production returns the town's
+	// and every rig's beads to compare intake against.
+	triageListCandidatesFn = func(townRoot string, limit int) ([]*beads.Issue, error) {
+		dirs := []string{townRoot}
+		if rigs, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+			var names []string
+			for name := range rigs.Rigs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				dirs = append(dirs, rigBeadsWorkDir(townRoot, name))
+			}
+		}
+		var all []*beads.Issue
+		for _, dir := range dirs {
+			issues, err := beads.New(dir).List(beads.ListOptions{Status: "all", Priority: -1, Limit: limit})
+			if err != nil {
+				continue // A rig without a beads database has nothing to compare
+			}
+			all = append(all, issues...)
+		}
+		return all, nil
+	}
+
+	// triageShowFn is a seam for tests. Production runs bd show.
+	triageShowFn = func(bead string) (*beads.Issue, error) {
+		return beads.New(resolveBeadDir(bead)).Show(bead)
+	}
+
+	// triageCreateFn is a seam for tests. Production creates the bead in the
+	// town database.
+	triageCreateFn = func(townRoot, title, description string, labels []string) (string, error) {
+		issue, err := beads.New(townRoot).Create(beads.CreateOptions{
+			Title:       title,
+			Labels:      labels,
+			Priority:    2,
+			Description: description,
+			Actor:       triage.DefaultRole,
+		})
+		if err != nil {
+			return "", err
+		}
+		return issue.ID, nil
+	}
+
+	// triageUpdateLabelsFn is a seam for tests. Production updates the labels
+	// with bd.
+	triageUpdateLabelsFn = func(bead string, add, remove []string) error {
+		return beads.New(resolveBeadDir(bead)).Update(bead, beads.UpdateOptions{AddLabels: add, RemoveLabels: remove})
+	}
+
+	// triageCloseFn is a seam for tests. Production runs bd close.
+	triageCloseFn = func(bead, reason string) error {
+		return beads.New(resolveBeadDir(bead)).CloseWithReason(reason, bead)
+	}
+
+	// triageCommentFn is a seam for tests. Production runs bd comments add.
+	triageCommentFn = func(bead, text string) error {
+		_, err := beads.New(resolveBeadDir(bead)).Run("comments", "add", bead, text)
+		return err
+	}
+
+	// triageSlingFn is a seam for tests. Production runs gt sling in the town.
+	triageSlingFn = func(townRoot string, args []string) error {
+		cmd := exec.Command("gt", append([]string{"sling"}, args...)...)
+		cmd.Dir = townRoot
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%v (%s)", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	// triageSendMailFn is a seam for tests. Production sends through the town
+	// router.
+	triageSendMailFn = func(townRoot string, msg *mail.Message) error {
+		router := mail.NewRouter(townRoot)
+		defer router.WaitPendingNotifications()
+		return router.Send(msg)
+	}
+
+	// triageReadMailFn is a seam for tests. Production lists unread mail for the
+	// address.
+	triageReadMailFn = func(townRoot, address string) ([]*mail.Message, error) {
+		mailbox, err := mail.NewRouter(townRoot).GetMailbox(address)
+		if err != nil {
+			return nil, err
+		}
+		return mailbox.ListUnread()
+	}
+
+	// triageMarkReadFn is a seam for tests. Production marks the message read.
+	triageMarkReadFn = func(townRoot, address, id string) error {
+		mailbox, err := mail.NewRouter(townRoot).GetMailbox(address)
+		if err != nil {
+			return err
+		}
+		return mailbox.MarkReadOnly(id)
+	}
+
+	// triageActiveTimeFn is a seam for tests. Production returns the active work
+	// time per bead over the last 90 days, for estimates.
+	triageActiveTimeFn = func(townRoot string) map[string]time.Duration {
+		out := map[string]time.Duration{}
+		signals, err := timetrack.Load(townRoot, time.Now().Add(-90*24*time.Hour))
+		if err != nil || len(signals) == 0 {
+			return out
+		}
+		evs, err := timeLoadEventsFn(townRoot, time.Time{})
+		if err != nil {
+			return out
+		}
+		for _, e := range timetrack.Compute(signals, timetrack.Assignments(evs), timetrack.DefaultIdleGap) {
+			if e.Bead != "" {
+				out[e.Bead] += e.Active
+			}
+		}
+		return out
+	}
+
+	// triageListIssuesFn is a seam for tests. Production uses triage.ListIssues.
+	triageListIssuesFn = triage.ListIssues
+
+	// triageSenderFn is a seam for tests. Production uses detectSender.
+	triageSenderFn = detectSender
+)
+
+func init() {
+	triageRunCmd.Flags().BoolVarP(&triageDryRun, "dry-run", "n", false, "Show decisions without applying them")
+	triageRunCmd.Flags().BoolVar(&triageEscalate, "escalate", false, "Escalate beads left for review to the human")
+	triageRunCmd.Flags().BoolVar(&triageNoIntake, "no-intake", false, "Skip importing from GitHub and mail")
+	triageQueueCmd.Flags().BoolVar(&triageJSON, "json", false, "Output as JSON")
+	triageRouteCmd.Flags().StringSliceVar(&triageLabels, "label", nil, "Labels to add (repeatable)")
+	triageRouteCmd.Flags().StringVar(&triageEstimate, "estimate", "", "Size estimate: s, m, l or xl")
+	triageRouteCmd.Flags().BoolVar(&triageNoSling, "no-sling", false, "Label the bead for the rig without slinging it")
+	triageEscalateCmd.Flags().StringVarP(&triageMessage, "message", "m", "", "Your question for the human")
+
+	triageCmd.AddCommand(triageSetupCmd, triageIntakeCmd, triageRunCmd, triageQueueCmd,
+		triageRouteCmd, triageDuplicateCmd, triageEscalateCmd)
+	rootCmd.AddCommand(triageCmd)
+}
+
+// loadTriageConfig returns the town's triage and dedup settings.
+func loadTriageConfig(townRoot string) (*triage.Config, *dedup.Config, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if err := settings.Triage.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("triage settings: %w", err)
+	}
+	return settings.Triage, settings.Dedup, nil
+}
+
+func runTriageSetup(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if config.IsCustomRole(townRoot, triage.DefaultRole) {
+		fmt.Printf("Role %s is already defined: %s\n", triage.DefaultRole, style.Dim.Render(config.CustomRolePath(townRoot, triage.DefaultRole)))
+		fmt.Printf("Start it with: %s role start %s\n", cli.Name(), triage.DefaultRole)
+		return nil
+	}
+
+	def := config.NewCustomRole(triage.DefaultRole, "town")
+	def.Description = "Triages inbound beads: dedups, labels, estimates and routes them to rigs."
+	def.Permissions = "work"
+	def.Autonomous = true
+	def.Nudge = fmt.Sprintf("Run %s triage run, then work through %s triage queue.", cli.Name(), cli.Name())
+	if err := config.WriteCustomRole(townRoot, def); err != nil {
+		return err
+	}
+	briefing := def.BriefingPath(townRoot)
+	if _, err := os.Stat(briefing); os.IsNotExist(err) {
+		if err := os.WriteFile(briefing, []byte(triageBriefing()), 0644); err != nil { //nolint:gosec // G306: briefings are not sensitive
+			return fmt.Errorf("writing briefing: %w", err)
+		}
+	}
+
+	fmt.Printf("%s Defined the %s role\n", style.Bold.Render("✓"), style.Bold.Render(triage.DefaultRole))
+	fmt.Printf("  definition: %s\n", style.Dim.Render(config.CustomRolePath(townRoot, def.Role)))
+	fmt.Printf("  briefing:   %s\n", style.Dim.Render(briefing))
+	fmt.Printf("Configure intake and routes under \"triage\" in %s\n", style.Dim.Render(config.TownSettingsPath(townRoot)))
+	fmt.Printf("Start it with: %s role start %s\n", cli.Name(), triage.DefaultRole)
+	return nil
+}
+
+// triageBriefing returns the triage agent's briefing.
+func triageBriefing() string {
+	gt := cli.Name()
+	return fmt.Sprintf(`# triage Context
+
+You are the **triage** agent. New work reaches the town through GitHub
+issues, webhooks and mail; you turn it into routed, labeled, sized beads so
+the human doesn't have to. Escalate only what you genuinely can't decide.
+
+## Loop
+
+1. Run a pass: `+"`%[1]s triage run`"+`. It imports new intake, closes clear
+   duplicates and routes beads whose routes agree.
+2. Work the queue: `+"`%[1]s triage queue`"+`. For each bead, read it
+   (`+"`%[1]s show <bead>`"+`) and the suggestion, then decide:
+   - Route it: `+"`%[1]s triage route <bead> <rig> [--label ...] [--estimate s|m|l|xl]`"+`
+   - Close it as a duplicate: `+"`%[1]s triage duplicate <bead> <other>`"+`
+   - Escalate it: `+"`%[1]s triage escalate <bead> -m \"<question>\"`"+`
+3. Check mail: `+"`%[1]s mail inbox`"+`. Answers to your escalations arrive as
+   replies; act on them with route or duplicate.
+4. When the queue is empty, wait a few minutes and start over.
+
+## Deciding
+
+- Read the whole report, not just the title. Stack traces and URLs usually
+  name the component, and so the rig.
+- A bead that restates open or recently finished work is a duplicate even
+  when the wording differs; link the original.
+- Keep the estimate the pass suggests unless the report clearly says
+  otherwise.
+- Escalate when the report is unclear about what is wanted, when routing
+  needs knowledge you don't have, or when it touches security, data loss or
+  a promise to a customer. Ask one specific question.
+`, gt)
+}
+
+func runTriageIntake(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, _, err := loadTriageConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	n, err := triageIntake(townRoot, cfg)
+	fmt.Printf("Imported %d intake bead(s)\n", n)
+	return err
+}
+
+// triageIntake imports new GitHub issues and mail to the triage agent as
+// intake beads. A failing source doesn't stop the others; its error is
+// returned after the rest are imported.
+func triageIntake(townRoot string, cfg *triage.Config) (int, error) {
+	if cfg == nil {
+		return 0, nil
+	}
+	state, err := triage.LoadState(townRoot)
+	if err != nil {
+		return 0, err
+	}
+	imported := 0
+	var errs []string
+	for _, src := range cfg.GitHub {
+		issues, err := triageListIssuesFn(src)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", src.Repo, err))
+			continue
+		}
+		for i := range issues {
+			issue := &issues[i]
+			key := issue.Key(src.Repo)
+			if state.Has(key) {
+				continue
+			}
+			id, err := triageCreateFn(townRoot, issue.Title, issue.Description(src.Repo),
+				[]string{"gt:task", triage.LabelNew, "github:" + src.Repo})
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s#%d: %v", src.Repo, issue.Number, err))
+				continue
+			}
+			state.Record(key, id, time.Now())
+			imported++
+			fmt.Printf("  %s %s %s#%d %s\n", style.Success.Render("+"), id, src.Repo, issue.Number, issue.Title)
+		}
+	}
+
+	if cfg.Mail {
+		address := triage.DefaultRole + "/"
+		messages, err := triageReadMailFn(townRoot, address)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("mail: %v", err))
+		}
+		for _, msg := range messages {
+			// Replies answer escalations; they are for the agent, not intake.
+			if msg.Type == mail.TypeReply || msg.ReplyTo != "" {
+				continue
+			}
+			key := "mail:" + msg.ID
+			if !state.Has(key) {
+				from := strings.TrimSuffix(msg.From, "/")
+				id, err := triageCreateFn(townRoot, msg.Subject, fmt.Sprintf("Mail from %s\n\n%s", msg.From, msg.Body),
+					[]string{"gt:task", triage.LabelNew, "mail:" + from})
+				if err != nil {
+					errs = append(errs, fmt.Sprintf("mail %s: %v", msg.ID, err))
+					continue
+				}
+				state.Record(key, id, time.Now())
+				imported++
+				fmt.Printf("  %s %s mail from %s: %s\n", style.Success.Render("+"), id, from, msg.Subject)
+			}
+			if err := triageMarkReadFn(townRoot, address, msg.ID); err != nil {
+				errs = append(errs, fmt.Sprintf("mail %s: %v", msg.ID, err))
+			}
+		}
+	}
+
+	if err := state.Save(); err != nil {
+		return imported, err
+	}
+	if len(errs) > 0 {
+		return imported, fmt.Errorf("intake: %s", strings.Join(errs, "; "))
+	}
+	return imported, nil
+}
+
+// triageItem reduces an intake bead to what triage looks at.
+func triageItem(issue *beads.Issue) triage.Item {
+	return triage.Item{ID: issue.ID, Title: issue.Title, Description: issue.Description, Labels: issue.Labels}
+}
+
+// triageDecide decides each pending bead against the town's beads.
+func triageDecide(ctx context.Context, townRoot string, cfg *triage.Config, dcfg *dedup.Config, pending []*beads.Issue) []*triage.Decision {
+	candidates, _ := triageListCandidatesFn(townRoot, dcfg.GetCandidateScan())
+	active := triageActiveTimeFn(townRoot)
+	scorer := dedup.NewScorer(dcfg, func(err error) {
+		fmt.Printf("%s duplicate check: embedding endpoint failed, using TF-IDF: %v\n", style.Dim.Render("Warning:"), err)
+	})
+	now := time.Now()
+
+	var decisions []*triage.Decision
+	for _, issue := range pending {
+		query := dedup.Document{ID: issue.ID, Title: issue.Title, Description: issue.Description, Status: issue.Status}
+		pool := triageDocuments(candidates, issue, now, dcfg.GetLookback())
+		matches, _ := dedup.FindDuplicates(ctx, scorer, query, pool, dcfg.GetThreshold(), dcfg.GetMaxResults())
+		var history []time.Duration
+		for _, m := range matches {
+			if isClosedStatus(m.Status) && active[m.ID] > 0 {
+				history = append(history, active[m.ID])
+			}
+		}
+		decisions = append(decisions, cfg.Decide(triageItem(issue), matches, history))
+	}
+	return decisions
+}
+
+// triageDocuments returns the beads an intake bead may duplicate: open and
+// in-flight work, work closed within lookback, and intake filed before it.
+// Unlike sling's check, open beads count: a report already filed is a
+// duplicate whether or not anyone has started on it.
+func triageDocuments(issues []*beads.Issue, item *beads.Issue, now time.Time, lookback time.Duration) []dedup.Document {
+	var pool []*beads.Issue
+	for _, issue := range issues {
+		if issue == nil || (beads.HasLabel(issue, triage.LabelNew) && issue.CreatedAt >= item.CreatedAt) {
+			continue
+		}
+		pool = append(pool, issue)
+	}
+	var out []dedup.Document
+	for _, d := range dedupDocuments(pool) {
+		if isClosedStatus(d.Status) && (d.ClosedAt.IsZero() || now.Sub(d.ClosedAt) > lookback) {
+			continue
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// triageSummary counts what a triage pass did.
+type triageSummary struct {
+	Routed, Duplicates, Escalated, Review int
+}
+
+// runTriagePass decides and applies every pending bead. Beads left for
+// review are escalated when escalate is set.
+func runTriagePass(ctx context.Context, townRoot string, cfg *triage.Config, dcfg *dedup.Config, dryRun, escalate bool) (*triageSummary, error) {
+	pending, err := triageListPendingFn(townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("listing intake: %w", err)
+	}
+	sum := &triageSummary{}
+	var errs []string
+	for i, d := range triageDecide(ctx, townRoot, cfg, dcfg, pending) {
+		printTriageDecision(pending[i], d)
+		if dryRun {
+			continue
+		}
+		var err error
+		switch {
+		case d.Action == triage.ActionRoute:
+			err = routeTriage(townRoot, d.Bead, d.Rig, d.Labels, "Routed to "+d.Rig+": "+d.Reason, true)
+			if err == nil {
+				sum.Routed++
+			}
+		case d.Action == triage.ActionDuplicate:
+			err = closeTriageDuplicate(d.Bead, d.DuplicateOf, d.Reason)
+			if err == nil {
+				sum.Duplicates++
+			}
+		case escalate:
+			err = escalateTriage(townRoot, cfg, pending[i], d, "")
+			if err == nil {
+				sum.Escalated++
+			}
+		default:
+			sum.Review++
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", d.Bead, err))
+		}
+	}
+	if len(errs) > 0 {
+		return sum, fmt.Errorf("triage: %s", strings.Join(errs, "; "))
+	}
+	return sum, nil
+}
+
+func printTriageDecision(issue *beads.Issue, d *triage.Decision) {
+	fmt.Printf("%s %s\n", style.Bold.Render(d.Bead), issue.Title)
+	switch d.Action {
+	case triage.ActionRoute:
+		fmt.Printf("  %s route to %s (%.0f%%): %s\n", style.Success.Render("→"), d.Rig, d.Confidence*100, d.Reason)
+	case triage.ActionDuplicate:
+		fmt.Printf("  %s duplicate of %s: %s\n", style.Warning.Render("="), d.DuplicateOf, d.Reason)
+	default:
+		fmt.Printf("  %s review: %s\n", style.Warning.Render("?"), d.Reason)
+		if d.DuplicateOf != "" {
+			fmt.Printf("    resembles %s (%.0f%%)\n", d.DuplicateOf, d.Similarity*100)
+		}
+	}
+	if d.Estimate != "" {
+		fmt.Printf("    estimate: %s\n", d.Estimate)
+	}
+}
+
+// routeTriage labels an intake bead as triaged and, when sling is set,
+// slings it to rig.
+func routeTriage(townRoot, bead, rig string, labels []string, note string, sling bool) error {
+	if sling {
+		if err := triageSlingFn(townRoot, []string{bead, rig}); err != nil {
+			return fmt.Errorf("slinging to %s: %w", rig, err)
+		}
+	}
+	add := append(append([]string{}, labels...), triage.LabelRouted)
+	if !sling {
+		add = append(add, "rig:"+rig)
+	}
+	if err := triageUpdateLabelsFn(bead, add, []string{triage.LabelNew, triage.LabelEscalated}); err != nil {
+		return err
+	}
+	return triageCommentFn(bead, "Triage: "+note)
+}
+
+// closeTriageDuplicate closes an intake bead as a duplicate of other.
+func closeTriageDuplicate(bead, other, note string) error {
+	if err := triageCommentFn(bead, fmt.Sprintf("Triage: duplicate of %s (%s)", other, note)); err != nil {
+		return err
+	}
+	if err := triageUpdateLabelsFn(bead, []string{triage.LabelDuplicate}, []string{triage.LabelNew, triage.LabelEscalated}); err != nil {
+		return err
+	}
+	return triageCloseFn(bead, "duplicate of "+other)
+}
+
+// escalateTriage mails the human an intake bead with the triage evidence
+// and takes it out of the queue.
+func escalateTriage(townRoot string, cfg *triage.Config, issue *beads.Issue, d *triage.Decision, question string) error {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Intake bead %s needs a decision.\n\n%s\n", issue.ID, issue.Title)
+	if question != "" {
+		fmt.Fprintf(&body, "\nQuestion: %s\n", question)
+	}
+	fmt.Fprintf(&body, "\nTriage: %s\n", d.Reason)
+	for _, c := range d.Candidates {
+		fmt.Fprintf(&body, "  - %s (score %d: %s)\n", c.Rig, c.Score, strings.Join(c.Matched, ", "))
+	}
+	if d.DuplicateOf != "" {
+		fmt.Fprintf(&body, "Resembles %s (%.0f%% similar)\n", d.DuplicateOf, d.Similarity*100)
+	}
+	if d.Estimate != "" {
+		fmt.Fprintf(&body, "Estimate: %s\n", d.Estimate)
+	}
+	fmt.Fprintf(&body, "\nReply with the rig, or run:\n  %[1]s show %[2]s\n  %[1]s triage route %[2]s <rig>\n  %[1]s triage duplicate %[2]s <bead>\n",
+		cli.Name(), issue.ID)
+
+	msg := mail.NewMessage(triageSenderFn(), cfg.GetEscalateTo(), "Triage: "+issue.Title, body.String())
+	msg.Type = mail.TypeQuestion
+	if err := triageSendMailFn(townRoot, msg); err != nil {
+		return fmt.Errorf("mailing %s: %w", cfg.GetEscalateTo(), err)
+	}
+	if err := triageUpdateLabelsFn(issue.ID, []string{triage.LabelEscalated}, []string{triage.LabelNew}); err != nil {
+		return err
+	}
+	return triageCommentFn(issue.ID, "Triage: escalated to "+cfg.GetEscalateTo())
+}
+
+func runTriageRun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, dcfg, err := loadTriageConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	if !triageNoIntake && !triageDryRun {
+		if n, err := triageIntake(townRoot, cfg); err != nil {
+			fmt.Printf("%s %v\n", style.Warning.Render("⚠"), err)
+		} else if n > 0 {
+			fmt.Printf("Imported %d intake bead(s)\n\n", n)
+		}
+	}
+
+	sum, err := runTriagePass(cmd.Context(), townRoot, cfg, dcfg, triageDryRun, triageEscalate)
+	if sum == nil {
+		return err
+	}
+	if triageDryRun {
+		fmt.Println(style.Dim.Render("\nDry run: nothing changed."))
+		return err
+	}
+	fmt.Printf("\nRouted %d, closed %d duplicate(s), escalated %d, left %d for review\n",
+		sum.Routed, sum.Duplicates, sum.Escalated, sum.Review)
+	if sum.Review > 0 {
+		fmt.Printf("  %s\n", style.Dim.Render(cli.Name()+" triage queue"))
+	}
+	return err
+}
+
+func runTriageQueue(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, dcfg, err := loadTriageConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	pending, err := triageListPendingFn(townRoot)
+	if err != nil {
+		return fmt.Errorf("listing intake: %w", err)
+	}
+	decisions := triageDecide(cmd.Context(), townRoot, cfg, dcfg, pending)
+	if triageJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if decisions == nil {
+			decisions = []*triage.Decision{}
+		}
+		return enc.Encode(decisions)
+	}
+	if len(decisions) == 0 {
+		fmt.Println("Nothing to triage.")
+		return nil
+	}
+	for i, d := range decisions {
+		printTriageDecision(pending[i], d)
+	}
+	return nil
+}
+
+func runTriageRoute(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	bead, rig := args[0], args[1]
+	rigs, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading rigs: %w", err)
+	}
+	if _, ok := rigs.Rigs[rig]; !ok {
+		return fmt.Errorf("rig %q is not registered", rig)
+	}
+	labels := triageLabels
+	if triageEstimate != "" {
+		size := triage.Size(strings.ToLower(triageEstimate))
+		switch size {
+		case triage.SizeS, triage.SizeM, triage.SizeL, triage.SizeXL:
+		default:
+			return fmt.Errorf("invalid --estimate %q: want s, m, l or xl", triageEstimate)
+		}
+		labels = append(labels, size.Label())
+	}
+	if err := routeTriage(townRoot, bead, rig, labels, "Routed to "+rig+" by "+triageSenderFn(), !triageNoSling); err != nil {
+		return err
+	}
+	fmt.Printf("%s Routed %s to %s\n", style.Bold.Render("✓"), bead, rig)
+	return nil
+}
+
+func runTriageDuplicate(cmd *cobra.Command, args []string) error {
+	if _, err := workspace.FindFromCwdOrError(); err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	bead, other := args[0], args[1]
+	if bead == other {
+		return fmt.Errorf("a bead can't duplicate itself")
+	}
+	if _, err := triageShowFn(other); err != nil {
+		return fmt.Errorf("looking up %s: %w", other, err)
+	}
+	if err := closeTriageDuplicate(bead, other, "marked by "+triageSenderFn()); err != nil {
+		return err
+	}
+	fmt.Printf("%s Closed %s as a duplicate of %s\n", style.Bold.Render("✓"), bead, other)
+	return nil
+}
+
+func runTriageEscalate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, dcfg, err := loadTriageConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	issue, err := triageShowFn(args[0])
+	if err != nil {
+		return fmt.Errorf("looking up %s: %w", args[0], err)
+	}
+	d := triageDecide(cmd.Context(), townRoot, cfg, dcfg, []*beads.Issue{issue})[0]
+	if err := escalateTriage(townRoot, cfg, issue, d, triageMessage); err != nil {
+		return err
+	}
+	fmt.Printf("%s Escalated %s to %s\n", style.Bold.Render("✓"), issue.ID, cfg.GetEscalateTo())
+	return nil
+}
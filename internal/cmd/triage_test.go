@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/triage"
+)
+
+// fakeTriage replaces the triage seams with an in-memory bead store.
+type fakeTriage struct {
+	issues   map[string]*beads.Issue
+	comments map[string][]string
+	closed   map[string]string
+	slung    []string
+	mailed   []*mail.Message
+	unread   []*mail.Message
+	read     []string
+	next     int
+}
+
+func newFakeTriage(t *testing.T) *fakeTriage {
+	f := &fakeTriage{
+		issues:   map[string]*beads.Issue{},
+		comments: map[string][]string{},
+		closed:   map[string]string{},
+	}
+	origPending, origCandidates, origShow, origCreate := triageListPendingFn, triageListCandidatesFn, triageShowFn, triageCreateFn
+	origUpdate, origClose, origComment, origSling := triageUpdateLabelsFn, triageCloseFn, triageCommentFn, triageSlingFn
+	origSend, origRead, origMarkRead := triageSendMailFn, triageReadMailFn, triageMarkReadFn
+	origActive, origIssues, origSender := triageActiveTimeFn, triageListIssuesFn, triageSenderFn
+	t.Cleanup(func() {
+		triageListPendingFn, triageListCandidatesFn, triageShowFn, triageCreateFn = origPending, origCandidates, origShow, origCreate
+		triageUpdateLabelsFn, triageCloseFn, triageCommentFn, triageSlingFn = origUpdate, origClose, origComment, origSling
+		triageSendMailFn, triageReadMailFn, triageMarkReadFn = origSend, origRead, origMarkRead
+		triageActiveTimeFn, triageListIssuesFn, triageSenderFn = origActive, origIssues, origSender
+	})
+
+	triageListPendingFn = func(string) ([]*beads.Issue, error) {
+		var out []*beads.Issue
+		for _, issue := range f.sorted() {
+			if issue.Status == "open" && beads.HasLabel(issue, triage.LabelNew) {
+				out = append(out, issue)
+			}
+		}
+		return out, nil
+	}
+	triageListCandidatesFn = func(string, int) ([]*beads.Issue, error) { return f.sorted(), nil }
+	triageShowFn = func(bead string) (*beads.Issue, error) {
+		if issue, ok := f.issues[bead]; ok {
+			return issue, nil
+		}
+		return nil, fmt.Errorf("%s not found", bead)
+	}
+	triageCreateFn = func(_, title, description string, labels []string) (string, error) {
+		f.next++
+		id := fmt.Sprintf("hq-%d", f.next)
+		f.add(&beads.Issue{ID: id, Title: title, Description: description, Labels: labels})
+		return id, nil
+	}
+	triageUpdateLabelsFn = func(bead string, add, remove []string) error {
+		issue := f.issues[bead]
+		var labels []string
+		for _, l := range issue.Labels {
+			if !containsString(remove, l) {
+				labels = append(labels, l)
+			}
+		}
+		issue.Labels = append(labels, add...)
+		return nil
+	}
+	triageCloseFn = func(bead, reason string) error {
+		f.issues[bead].Status = "closed"
+		f.closed[bead] = reason
+		return nil
+	}
+	triageCommentFn = func(bead, text string) error {
+		f.comments[bead] = append(f.comments[bead], text)
+		return nil
+	}
+	triageSlingFn = func(_ string, args []string) error {
+		f.slung = append(f.slung, strings.Join(args, " "))
+		return nil
+	}
+	triageSendMailFn = func(_ string, msg *mail.Message) error {
+		f.mailed = append(f.mailed, msg)
+		return nil
+	}
+	triageReadMailFn = func(string, string) ([]*mail.Message, error) { return f.unread, nil }
+	triageMarkReadFn = func(_, _, id string) error {
+		f.read = append(f.read, id)
+		return nil
+	}
+	triageActiveTimeFn = func(string) map[string]time.Duration { return map[string]time.Duration{"app-1": 3 * time.Hour} }
+	triageListIssuesFn = func(triage.GitHubSource) ([]triage.Issue, error) { return nil, nil }
+	triageSenderFn = func() string { return "triage" }
+	return f
+}
+
+func (f *fakeTriage) add(issue *beads.Issue) {
+	if issue.Status == "" {
+		issue.Status = "open"
+	}
+	if issue.CreatedAt == "" {
+		issue.CreatedAt = fmt.Sprintf("2026-10-01T00:00:%02dZ", len(f.issues))
+	}
+	f.issues[issue.ID] = issue
+}
+
+func (f *fakeTriage) sorted() []*beads.Issue {
+	var out []*beads.Issue
+	for _, issue := range f.issues {
+		out = append(out, issue)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt < out[j].CreatedAt })
+	return out
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTriageIntake(t *testing.T) {
+	f := newFakeTriage(t)
+	town := t.TempDir()
+	triageListIssuesFn = func(src triage.GitHubSource) ([]triage.Issue, error) {
+		return []triage.Issue{{Number: 7, Title: "Cart empties on refresh", URL: "https://github.com/acme/app/issues/7"}}, nil
+	}
+	f.unread = []*mail.Message{
+		{ID: "m1", From: "gastown/witness", Subject: "DNS flaps nightly", Body: "Since Tuesday."},
+		{ID: "m2", From: "overseer", Subject: "Re: Triage", Type: mail.TypeReply, ReplyTo: "m0"},
+	}
+	cfg := &triage.Config{GitHub: []triage.GitHubSource{{Repo: "acme/app"}}, Mail: true}
+
+	n, err := triageIntake(town, cfg)
+	if err != nil || n != 2 {
+		t.Fatalf("triageIntake = %d, %v", n, err)
+	}
+	if issue := f.issues["hq-1"]; issue.Title != "Cart empties on refresh" || !beads.HasLabel(issue, "github:acme/app") || !beads.HasLabel(issue, triage.LabelNew) {
+		t.Errorf("GitHub bead = %+v", issue)
+	}
+	if issue := f.issues["hq-2"]; !beads.HasLabel(issue, "mail:gastown/witness") || !strings.Contains(issue.Description, "Since Tuesday.") {
+		t.Errorf("mail bead = %+v", issue)
+	}
+	if strings.Join(f.read, ",") != "m1" {
+		t.Errorf("marked read = %v; replies should stay for the agent", f.read)
+	}
+
+	// The ledger keeps a second pass from importing the same items.
+	if n, err := triageIntake(town, cfg); err != nil || n != 0 {
+		t.Errorf("second intake = %d, %v", n, err)
+	}
+
+	triageListIssuesFn = func(triage.GitHubSource) ([]triage.Issue, error) { return nil, fmt.Errorf("gh: not logged in") }
+	if _, err := triageIntake(town, cfg); err == nil || !strings.Contains(err.Error(), "acme/app") {
+		t.Errorf("GitHub failure = %v", err)
+	}
+}
+
+func TestRunTriagePass(t *testing.T) {
+	f := newFakeTriage(t)
+	town := t.TempDir()
+	f.add(&beads.Issue{ID: "app-1", Title: "Cart total ignores discount codes", Status: "closed",
+		ClosedAt: time.Now().Add(-24 * time.Hour).Format(time.RFC3339)})
+	f.add(&beads.Issue{ID: "app-2", Title: "Checkout button unresponsive on mobile Safari", Description: "Tapping checkout does nothing."})
+	f.add(&beads.Issue{ID: "hq-1", Title: "Checkout button unresponsive on mobile Safari", Description: "Tapping checkout does nothing.", Labels: []string{triage.LabelNew}})
+	f.add(&beads.Issue{ID: "hq-2", Title: "Cart total wrong when a discount code is applied", Labels: []string{triage.LabelNew, "github:acme/app"}})
+	f.add(&beads.Issue{ID: "hq-3", Title: "Checkout slow after the deploy", Labels: []string{triage.LabelNew}})
+	f.add(&beads.Issue{ID: "hq-4", Title: "Billing export is missing a column", Labels: []string{triage.LabelNew}})
+
+	cfg := &triage.Config{Routes: []triage.Route{
+		{Rig: "app", Keywords: []string{"checkout", "cart"}, Labels: []string{"github:acme/app"}, AddLabels: []string{"area:shop"}},
+		{Rig: "infra", Keywords: []string{"deploy"}},
+	}}
+
+	sum, err := runTriagePass(context.Background(), town, cfg, nil, true, false)
+	if err != nil || sum.Routed != 0 || len(f.slung) != 0 || len(f.closed) != 0 {
+		t.Fatalf("dry run changed things: %+v, %v, slung %v", sum, err, f.slung)
+	}
+
+	sum, err = runTriagePass(context.Background(), town, cfg, nil, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.Duplicates != 1 || f.closed["hq-1"] != "duplicate of app-2" || !beads.HasLabel(f.issues["hq-1"], triage.LabelDuplicate) {
+		t.Errorf("duplicate: %+v, closed %v", sum, f.closed)
+	}
+	if sum.Routed != 1 || strings.Join(f.slung, ";") != "hq-2 app" {
+		t.Errorf("route: %+v, slung %v", sum, f.slung)
+	}
+	labels := f.issues["hq-2"].Labels
+	if beads.HasLabel(f.issues["hq-2"], triage.LabelNew) || !containsString(labels, "area:shop") || !containsString(labels, triage.LabelRouted) {
+		t.Errorf("routed labels = %v", labels)
+	}
+	if sum.Review != 2 || !beads.HasLabel(f.issues["hq-3"], triage.LabelNew) || !beads.HasLabel(f.issues["hq-4"], triage.LabelNew) {
+		t.Errorf("review: %+v", sum)
+	}
+	if len(f.mailed) != 0 {
+		t.Errorf("nothing should be escalated without --escalate: %d mails", len(f.mailed))
+	}
+
+	sum, err = runTriagePass(context.Background(), town, cfg, nil, false, true)
+	if err != nil || sum.Escalated != 2 || len(f.mailed) != 2 {
+		t.Fatalf("escalate: %+v, %v, %d mails", sum, err, len(f.mailed))
+	}
+	msg := f.mailed[0]
+	if msg.To != triage.DefaultEscalateTo || msg.Type != mail.TypeQuestion || !strings.Contains(msg.Body, "triage route hq-3 <rig>") {
+		t.Errorf("escalation mail = %+v", msg)
+	}
+	if !beads.HasLabel(f.issues["hq-3"], triage.LabelEscalated) || beads.HasLabel(f.issues["hq-3"], triage.LabelNew) {
+		t.Errorf("escalated labels = %v", f.issues["hq-3"].Labels)
+	}
+}
+
+func TestRouteTriage_NoSling(t *testing.T) {
+	f := newFakeTriage(t)
+	f.add(&beads.Issue{ID: "hq-1", Title: "Flaky DNS", Labels: []string{triage.LabelEscalated}})
+	if err := routeTriage(t.TempDir(), "hq-1", "infra", []string{"estimate:m"}, "Routed to infra by overseer", false); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.slung) != 0 {
+		t.Errorf("slung %v with sling off", f.slung)
+	}
+	if got := strings.Join(f.issues["hq-1"].Labels, ","); got != "estimate:m,triage:routed,rig:infra" {
+		t.Errorf("labels = %s", got)
+	}
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/undo"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	undoList   bool
+	undoCount  int
+	undoDryRun bool
+	undoJSON   bool
+)
+
+var undoCmd = &cobra.Command{
+	Use:     "undo [operation-id]",
+	GroupID: GroupWorkspace,
+	Short:   "Reverse recent destructive town operations",
+	Long: `Reverse recent destructive town operations, newest first.
+
+These operations are recorded with the state needed to reverse them:
+
+  rig-remove    gt rig remove: the registry entry, bead route and daemon
+                patrol membership are restored (files were never deleted;
+                start the rig again with gt rig start)
+  polecat-nuke  gt polecat nuke: the worktree is recreated on its branch at
+                the commit it had (uncommitted changes are gone)
+  routes        gt doctor --fix route rewrites: routes.jsonl is put back,
+                unless it has changed again since
+  close         gt close: the beads are reopened with their previous
+                status and assignee
+
+The last 50 operations are kept in .runtime/undo/ops.jsonl. Without an
+operation ID, gt undo reverses the newest one not yet undone.
+
+Examples:
+  gt undo --list            # Recent operations
+  gt undo                   # Reverse the newest operation
+  gt undo -n 3              # Reverse the three newest
+  gt undo u-1a2b3c4d        # Reverse a specific operation
+  gt undo --dry-run         # Show what would be reversed`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUndo,
+}
+
+var (
+	// undoReopenBeadFn is a seam for tests. Production reopens the bead with bd
+	// and restores its status.
+	undoReopenBeadFn = func(b undo.ClosedBead) error {
+		dir := resolveBeadDir(b.ID)
+		if err := BdCmd("reopen", b.ID, "--reason=gt undo").Dir(dir).StripBeadsDir().Stderr(io.Discard).Run(); err != nil {
+			return fmt.Errorf("reopening %s: %w", b.ID, err)
+		}
+		if b.Status == "" || b.Status == "open" {
+			return nil
+		}
+		args := []string{"update", b.ID, "--status=" + b.Status}
+		if b.Assignee != "" {
+			args = append(args, "--assignee="+b.Assignee)
+		}
+		return BdCmd(args...).Dir(dir).StripBeadsDir().Run()
+	}
+
+	// undoRestorePolecatFn is a seam for tests. Production re-adds the polecat
+	// at its recorded commit.
+	undoRestorePolecatFn = func(st undo.PolecatNukeState) error {
+		mgr, _, err := getPolecatManager(st.Rig)
+		if err != nil {
+			return err
+		}
+		if existing, err := mgr.Get(st.Polecat); err == nil && existing != nil {
+			return fmt.Errorf("polecat %s/%s exists again", st.Rig, st.Polecat)
+		}
+		_, err = mgr.AddWithOptions(st.Polecat, polecat.AddOptions{BaseBranch: st.Commit, Branch: st.Branch})
+		return err
+	}
+)
+
+func init() {
+	undoCmd.Flags().BoolVar(&undoList, "list", false, "List recent operations")
+	undoCmd.Flags().IntVarP(&undoCount, "count", "n", 1, "Number of operations to reverse")
+	undoCmd.Flags().BoolVar(&undoDryRun, "dry-run", false, "Show what would be reversed")
+	undoCmd.Flags().BoolVar(&undoJSON, "json", false, "Output the list as JSON")
+
+	rootCmd.AddCommand(undoCmd)
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	if undoList {
+		ops, err := undo.List(townRoot)
+		if err != nil {
+			return err
+		}
+		if undoJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(ops)
+		}
+		printUndoList(ops)
+		return nil
+	}
+
+	var ops []undo.Op
+	if len(args) == 1 {
+		op, err := undo.Get(townRoot, args[0])
+		if err != nil {
+			return err
+		}
+		if op.Undone() {
+			return fmt.Errorf("%s was already undone at %s", op.ID, op.UndoneAt.Local().Format(time.DateTime))
+		}
+		ops = []undo.Op{*op}
+	} else {
+		if undoCount < 1 {
+			return fmt.Errorf("--count must be at least 1")
+		}
+		if ops, err = undo.Pending(townRoot, undoCount); err != nil {
+			return err
+		}
+	}
+	if len(ops) == 0 {
+		fmt.Println("Nothing to undo.")
+		return nil
+	}
+
+	for _, op := range ops {
+		if undoDryRun {
+			fmt.Printf("Would undo %s  %s\n", style.Dim.Render(op.ID), op.Summary)
+			continue
+		}
+		if err := undoOperation(townRoot, op); err != nil {
+			return fmt.Errorf("undoing %s (%s): %w", op.ID, op.Summary, err)
+		}
+		if err := undo.MarkUndone(townRoot, op.ID, time.Now()); err != nil {
+			style.PrintWarning("could not mark %s undone: %v", op.ID, err)
+		}
+		fmt.Printf("%s Undid %s\n", style.SuccessPrefix, op.Summary)
+	}
+	return nil
+}
+
+// undoOperation reverses one recorded operation.
+func undoOperation(townRoot string, op undo.Op) error {
+	switch op.Kind {
+	case undo.KindRigRemove:
+		var st undo.RigRemoveState
+		if err := json.Unmarshal(op.State, &st); err != nil {
+			return err
+		}
+		return undoRigRemove(townRoot, st)
+	case undo.KindPolecatNuke:
+		var st undo.PolecatNukeState
+		if err := json.Unmarshal(op.State, &st); err != nil {
+			return err
+		}
+		if st.Commit == "" {
+			return fmt.Errorf("no commit was recorded for %s/%s; nothing to restore", st.Rig, st.Polecat)
+		}
+		if err := undoRestorePolecatFn(st); err != nil {
+			return err
+		}
+		if st.Issue != "" {
+			fmt.Printf("  %s %s/%s was working on %s; re-sling it if needed\n", style.Dim.Render("○"), st.Rig, st.Polecat, st.Issue)
+		}
+		return nil
+	case undo.KindRoutes:
+		var st undo.RoutesState
+		if err := json.Unmarshal(op.State, &st); err != nil {
+			return err
+		}
+		current, err := beads.LoadRoutes(st.BeadsDir)
+		if err != nil {
+			return err
+		}
+		if !slices.Equal(current, st.After) {
+			return fmt.Errorf("routes.jsonl has changed since; fix it by hand")
+		}
+		return beads.WriteRoutes(st.BeadsDir, st.Before)
+	case undo.KindClose:
+		var st undo.CloseState
+		if err := json.Unmarshal(op.State, &st); err != nil {
+			return err
+		}
+		for _, b := range st.Beads {
+			if err := undoReopenBeadFn(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown operation kind %q", op.Kind)
+}
+
+// undoRigRemove puts a removed rig back in the registry, its bead route
+// back in routes.jsonl and the rig back in the daemon's patrols.
+func undoRigRemove(townRoot string, st undo.RigRemoveState) error {
+	rigsPath := filepath.Join(townRoot, "mayor", "rigs.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	if _, ok := rigsConfig.Rigs[st.Rig]; ok {
+		return fmt.Errorf("rig %s is registered again", st.Rig)
+	}
+	var entry config.RigEntry
+	if err := json.Unmarshal(st.Entry, &entry); err != nil {
+		return fmt.Errorf("decoding rig entry: %w", err)
+	}
+	if rigsConfig.Rigs == nil {
+		rigsConfig.Rigs = make(map[string]config.RigEntry)
+	}
+	rigsConfig.Rigs[st.Rig] = entry
+	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+		return fmt.Errorf("saving rigs config: %w", err)
+	}
+	if st.Route != nil {
+		if err := beads.AppendRoute(townRoot, *st.Route); err != nil {
+			style.PrintWarning("could not restore route %s: %v", st.Route.Prefix, err)
+		}
+	}
+	if err := config.AddRigToDaemonPatrols(townRoot, st.Rig); err != nil {
+		style.PrintWarning("could not restore daemon patrols: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(townRoot, st.Rig)); err != nil {
+		style.PrintWarning("rig directory %s is gone; re-add it with gt rig add", filepath.Join(townRoot, st.Rig))
+	} else {
+		fmt.Printf("  %s Start it again with %s\n", style.Dim.Render("○"), style.Bold.Render("gt rig start "+st.Rig))
+	}
+	return nil
+}
+
+// recordUndo logs an operation for gt undo. Failing to record never fails
+// the operation itself.
+func recordUndo(townRoot, kind, summary string, state any) {
+	if _, err := undo.Record(townRoot, kind, summary, detectSender(), state, time.Now()); err != nil {
+		style.PrintWarning("could not record %s for gt undo: %v", kind, err)
+	}
+}
+
+func printUndoList(ops []undo.Op) {
+	if len(ops) == 0 {
+		fmt.Println("No recorded operations.")
+		return
+	}
+	for _, op := range ops {
+		status := ""
+		if op.Undone() {
+			status = style.Dim.Render("(undone)")
+		}
+		fmt.Printf("%s  %s  %-13s %s %s\n", style.Dim.Render(op.ID), op.Time.Local().Format("Jan 02 15:04"), op.Kind, op.Summary, status)
+	}
+	fmt.Printf("\n%s\n", style.Dim.Render("gt undo reverses the newest operation not yet undone."))
+}
+
+// closePriorState returns the status and assignee of beads about to be
+// closed, skipping beads that are already closed or can't be read.
+func closePriorState(ids []string) []undo.ClosedBead {
+	var out []undo.ClosedBead
+	for _, id := range ids {
+		info, err := getBeadInfo(id)
+		if err != nil || info.Status == "closed" {
+			continue
+		}
+		out = append(out, undo.ClosedBead{ID: id, Status: info.Status, Assignee: info.Assignee})
+	}
+	return out
+}
+
+// closeSummary describes a close for the undo log.
+func closeSummary(beads []undo.ClosedBead) string {
+	ids := make([]string, 0, len(beads))
+	for _, b := range beads {
+		ids = append(ids, b.ID)
+	}
+	if len(ids) > 4 {
+		return fmt.Sprintf("close %s and %d more", strings.Join(ids[:3], " "), len(ids)-3)
+	}
+	return "close " + strings.Join(ids, " ")
+}
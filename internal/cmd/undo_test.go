@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/undo"
+)
+
+func TestUndoRigRemove(t *testing.T) {
+	town := t.TempDir()
+	rigsPath := filepath.Join(town, "mayor", "rigs.json")
+	if err := os.MkdirAll(filepath.Dir(rigsPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(town, "alpha"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SaveRigsConfig(rigsPath, &config.RigsConfig{Version: 1, Rigs: map[string]config.RigEntry{}}); err != nil {
+		t.Fatal(err)
+	}
+	beadsDir := filepath.Join(town, ".beads")
+	if err := beads.WriteRoutes(beadsDir, []beads.Route{{Prefix: "hq-", Path: "."}}); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, _ := json.Marshal(config.RigEntry{GitURL: "https://example.com/alpha.git", BeadsConfig: &config.BeadsConfig{Prefix: "al"}})
+	st := undo.RigRemoveState{Rig: "alpha", Entry: entry, Route: &beads.Route{Prefix: "al-", Path: "alpha/mayor/rig"}}
+	op, err := undo.Record(town, undo.KindRigRemove, "rig remove alpha", "", st, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := undoOperation(town, *op); err != nil {
+		t.Fatal(err)
+	}
+	rigs, err := config.LoadRigsConfig(rigsPath)
+	if err != nil || rigs.Rigs["alpha"].GitURL != "https://example.com/alpha.git" {
+		t.Errorf("rigs after undo = %+v, %v", rigs, err)
+	}
+	routes, _ := beads.LoadRoutes(beadsDir)
+	if len(routes) != 2 || routes[1].Prefix != "al-" {
+		t.Errorf("routes after undo = %+v", routes)
+	}
+	if err := undoOperation(town, *op); err == nil {
+		t.Error("restoring a rig that is registered again should fail")
+	}
+}
+
+func TestUndoRoutesRefusesWhenChanged(t *testing.T) {
+	town := t.TempDir()
+	beadsDir := filepath.Join(town, ".beads")
+	before := []beads.Route{{Prefix: "gt-", Path: "gastown"}}
+	after := []beads.Route{{Prefix: "gt-", Path: "gastown/mayor/rig"}}
+	if err := beads.WriteRoutes(beadsDir, after); err != nil {
+		t.Fatal(err)
+	}
+	state, _ := json.Marshal(undo.RoutesState{BeadsDir: beadsDir, Before: before, After: after})
+	op := undo.Op{Kind: undo.KindRoutes, State: state}
+
+	if err := beads.AppendRoute(town, beads.Route{Prefix: "bd-", Path: "beads"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := undoOperation(town, op); err == nil {
+		t.Error("undo should refuse when routes changed since")
+	}
+
+	if err := beads.WriteRoutes(beadsDir, after); err != nil {
+		t.Fatal(err)
+	}
+	if err := undoOperation(town, op); err != nil {
+		t.Fatal(err)
+	}
+	if routes, _ := beads.LoadRoutes(beadsDir); len(routes) != 1 || routes[0].Path != "gastown" {
+		t.Errorf("routes after undo = %+v", routes)
+	}
+}
+
+func TestUndoClose(t *testing.T) {
+	var reopened []undo.ClosedBead
+	orig := undoReopenBeadFn
+	undoReopenBeadFn = func(b undo.ClosedBead) error {
+		reopened = append(reopened, b)
+		return nil
+	}
+	t.Cleanup(func() { undoReopenBeadFn = orig })
+
+	closed := []undo.ClosedBead{{ID: "gt-a", Status: "hooked", Assignee: "gastown/polecats/Toast"}, {ID: "gt-b", Status: "open"}}
+	state, _ := json.Marshal(undo.CloseState{Beads: closed})
+	if err := undoOperation(t.TempDir(), undo.Op{Kind: undo.KindClose, State: state}); err != nil {
+		t.Fatal(err)
+	}
+	if len(reopened) != 2 || reopened[0] != closed[0] {
+		t.Errorf("reopened = %+v", reopened)
+	}
+	if got := closeSummary([]undo.ClosedBead{{ID: "a"}, {ID: "b"}, {ID: "c"}, {ID: "d"}, {ID: "e"}}); got != "close a b c and 2 more" {
+		t.Errorf("closeSummary = %q", got)
+	}
+}
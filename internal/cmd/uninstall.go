@@ -4,11 +4,9 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/shell"
@@ -37,7 +35,8 @@ By default, removes:
 
 The workspace (e.g., ~/gt) is NOT removed unless --workspace is specified.
 
-Use --force to skip confirmation prompts.
+Use --force to skip confirmation prompts, unless the town's confirmation
+policy refuses it (see gt confirm).
 
 Examples:
   gt uninstall                    # Remove Gas Town, keep workspace
@@ -72,16 +71,12 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 		}
 
 		fmt.Println()
-		fmt.Print("Continue? [y/N] ")
-
-		reader := bufio.NewReader(os.Stdin)
-		response, _ := reader.ReadString('\n')
-		response = strings.TrimSpace(strings.ToLower(response))
-
-		if response != "y" && response != "yes" {
-			fmt.Println("Aborted.")
-			return nil
-		}
+	}
+	if proceed, err := confirmDestructive("uninstall", "", uninstallForce); err != nil {
+		return err
+	} else if !proceed {
+		fmt.Println("Aborted.")
+		return nil
 	}
 
 	var errors []string
@@ -39,11 +39,23 @@ a new binary. It orchestrates all migration steps in the right order:
 
 Each step reports what changed. Use --dry-run to preview without modifying.
 
+With --self, gt first updates its own binary from the GitHub releases:
+the archive for this platform is checked against the release's
+checksums.txt (and its ed25519 signature, when the release is signed and
+a key is given with --public-key or GT_UPGRADE_PUBKEY), the new binary
+must be able to read this town's town.json and rigs.json schemas, and if
+gt doctor passed before the swap but fails after, the previous binary is
+restored. The migration steps then run with the new binary.
+
 Examples:
   gt upgrade                  # Run all migration steps
   gt upgrade --dry-run        # Show what would change
   gt upgrade --verbose        # Show detailed output
-  gt upgrade --no-start       # Suppress starting daemon during doctor fix`,
+  gt upgrade --no-start       # Suppress starting daemon during doctor fix
+  gt upgrade --self           # Install the latest release, then migrate
+  gt upgrade --self --to 0.13.0   # Install a specific release
+  gt upgrade --self --check   # Verify the latest release without installing
+  gt upgrade --self --rollback    # Restore the binary from before the last --self`,
 	RunE:         runUpgrade,
 	SilenceUsage: true,
 }
@@ -52,6 +64,14 @@ func init() {
 	upgradeCmd.Flags().BoolVar(&upgradeDryRun, "dry-run", false, "Show what would change without modifying anything")
 	upgradeCmd.Flags().BoolVarP(&upgradeVerbose, "verbose", "v", false, "Show detailed output")
 	upgradeCmd.Flags().BoolVar(&upgradeNoStart, "no-start", false, "Suppress starting daemon/agents during doctor fix")
+	upgradeCmd.Flags().BoolVar(&upgradeSelf, "self", false, "Download and install a release binary before migrating")
+	upgradeCmd.Flags().StringVar(&upgradeTo, "to", "", "Release version to install with --self (default: latest)")
+	upgradeCmd.Flags().BoolVar(&upgradeCheck, "check", false, "With --self: download and verify, but don't install")
+	upgradeCmd.Flags().BoolVar(&upgradeRollback, "rollback", false, "With --self: restore the binary replaced by the last upgrade")
+	upgradeCmd.Flags().BoolVar(&upgradeForce, "force", false, "With --self: reinstall the same version, or replace a package-managed binary")
+	upgradeCmd.Flags().StringVar(&upgradePublicKey, "public-key", "", "Base64 ed25519 key to verify release signatures (default: $GT_UPGRADE_PUBKEY)")
+	upgradeCmd.Flags().BoolVar(&upgradeRequireSig, "require-signature", false, "With --self: refuse releases without a verified signature")
+	upgradeCmd.Flags().BoolVar(&upgradeSkipHealth, "skip-health-check", false, "With --self: don't run gt doctor around the swap")
 	rootCmd.AddCommand(upgradeCmd)
 }
 
@@ -69,6 +89,13 @@ func runUpgrade(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a Gas Town workspace: %w", err)
 	}
 
+	if upgradeSelf {
+		return runSelfUpgrade(townRoot)
+	}
+	if upgradeTo != "" || upgradeCheck || upgradeRollback || upgradeRequireSig {
+		return fmt.Errorf("--to, --check, --rollback and --require-signature require --self")
+	}
+
 	if upgradeDryRun {
 		fmt.Printf("\n%s Dry run — showing what would change\n", style.Bold.Render("gt upgrade"))
 	} else {
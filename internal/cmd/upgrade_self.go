@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deps"
+	"github.com/steveyegge/gastown/internal/selfupdate"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	upgradeSelf       bool
+	upgradeTo         string
+	upgradeCheck      bool
+	upgradeRollback   bool
+	upgradeForce      bool
+	upgradePublicKey  string
+	upgradeRequireSig bool
+	upgradeSkipHealth bool
+)
+
+// managedInstallMarkers identify binaries installed by a package manager,
+// which should be upgraded by that package manager instead.
+var managedInstallMarkers = map[string]string{
+	"/Cellar/":       "brew upgrade gastown",
+	"/node_modules/": "npm install -g @gastown/gt",
+	"/nix/store/":    "your nix configuration",
+}
+
+// runSelfUpgrade downloads, verifies and installs a release binary, checks
+// it against the town, rolls back if it fails its health check, then runs
+// the new binary's post-install migrations.
+func runSelfUpgrade(townRoot string) error {
+	exe, err := currentExecutable()
+	if err != nil {
+		return err
+	}
+	if upgradeRollback {
+		return rollbackSelfUpgrade(exe)
+	}
+	for marker, how := range managedInstallMarkers {
+		if strings.Contains(exe, marker) && !upgradeForce {
+			return fmt.Errorf("%s is managed by a package manager; upgrade with %s (or --force)", exe, how)
+		}
+	}
+
+	ctx := context.Background()
+	client := selfupdate.NewClient()
+	var release *selfupdate.Release
+	if upgradeTo != "" {
+		release, err = client.Tag(ctx, upgradeTo)
+	} else {
+		release, err = client.Latest(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("finding release: %w", err)
+	}
+
+	target := release.Version()
+	cmp := deps.CompareVersions(target, Version)
+	fmt.Printf("\n%s current %s, release %s\n", style.Bold.Render("gt upgrade --self"), Version, target)
+	if cmp == 0 && !upgradeForce {
+		fmt.Printf("  %s Already up to date\n", style.SuccessPrefix)
+		return nil
+	}
+	if cmp < 0 && upgradeTo == "" {
+		fmt.Printf("  %s This build is newer than the latest release; nothing to do\n", style.SuccessPrefix)
+		return nil
+	}
+	if cmp < 0 {
+		fmt.Printf("  %s Downgrading to %s\n", style.WarningPrefix, target)
+	}
+
+	if settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && !settings.GTVersion.Allows(target) {
+		if !upgradeForce {
+			return fmt.Errorf("gt %s is outside the town's version pin (%s); change it with 'gt config version-pin' or use --force", target, settings.GTVersion)
+		}
+		fmt.Printf("  %s gt %s is outside the town's version pin (%s)\n", style.WarningPrefix, target, settings.GTVersion)
+	}
+
+	name := selfupdate.CurrentArchiveName(target)
+	staged, err := fetchVerifiedBinary(ctx, client, release, name, exe)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(staged) }()
+
+	info, err := selfupdate.Inspect(staged)
+	if err != nil {
+		return fmt.Errorf("new binary does not run: %w", err)
+	}
+	townSchema, rigsSchema := selfupdate.TownSchemas(townRoot)
+	if err := selfupdate.CheckCompatibility(info, townSchema, rigsSchema); err != nil {
+		return err
+	}
+	fmt.Printf("  %s Compatible with town (town.json v%d, rigs.json v%d)\n", style.SuccessPrefix, townSchema, rigsSchema)
+
+	if upgradeCheck || upgradeDryRun {
+		fmt.Printf("  %s Would install gt %s at %s\n", style.WarningPrefix, info.Version, exe)
+		return nil
+	}
+
+	// Record whether the town is healthy under the current binary, so a
+	// health failure after the swap is only blamed on the new one if the
+	// old one passed.
+	wasHealthy := upgradeSkipHealth || healthCheck(exe) == nil
+
+	backup, err := selfupdate.Install(exe, staged)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  %s Installed gt %s (previous binary kept at %s)\n", style.SuccessPrefix, info.Version, backup)
+
+	if !upgradeSkipHealth && wasHealthy {
+		if err := healthCheck(exe); err != nil {
+			fmt.Printf("  %s Health check failed: %v\n", style.ErrorPrefix, err)
+			if rbErr := selfupdate.Rollback(exe, backup); rbErr != nil {
+				return fmt.Errorf("health check failed (%v) and rollback failed: %w", err, rbErr)
+			}
+			fmt.Printf("  %s Rolled back to gt %s\n", style.WarningPrefix, Version)
+			return NewSilentExit(1)
+		}
+		fmt.Printf("  %s Health check passed\n", style.SuccessPrefix)
+	}
+
+	// Migrations belong to the new binary: it knows what changed.
+	fmt.Printf("\nRunning post-install migration with gt %s...\n", info.Version)
+	migrate := exec.Command(exe, upgradeMigrateArgs()...) //nolint:gosec // G204: the binary just installed
+	migrate.Stdout = os.Stdout
+	migrate.Stderr = os.Stderr
+	if err := migrate.Run(); err != nil {
+		return fmt.Errorf("post-install migration failed (binary stays upgraded; rerun 'gt upgrade', or 'gt upgrade --self --rollback'): %w", err)
+	}
+	return nil
+}
+
+// fetchVerifiedBinary downloads name from the release, verifies it against
+// checksums.txt (and its signature when available), and stages the gt
+// binary next to exe.
+func fetchVerifiedBinary(ctx context.Context, client *selfupdate.Client, release *selfupdate.Release, name, exe string) (string, error) {
+	archiveAsset, ok := release.Asset(name)
+	if !ok {
+		return "", fmt.Errorf("release %s has no %s", release.Tag, name)
+	}
+	sumsAsset, ok := release.Asset(selfupdate.ChecksumsAsset)
+	if !ok {
+		return "", fmt.Errorf("release %s has no %s; refusing to install an unverified binary", release.Tag, selfupdate.ChecksumsAsset)
+	}
+
+	fmt.Printf("  Downloading %s...\n", name)
+	archive, err := client.Download(ctx, archiveAsset)
+	if err != nil {
+		return "", err
+	}
+	sums, err := client.Download(ctx, sumsAsset)
+	if err != nil {
+		return "", err
+	}
+
+	key := upgradePublicKey
+	if key == "" {
+		key = os.Getenv("GT_UPGRADE_PUBKEY")
+	}
+	sigAsset, signed := release.Asset(selfupdate.SignatureAsset)
+	switch {
+	case signed && key != "":
+		sig, err := client.Download(ctx, sigAsset)
+		if err != nil {
+			return "", err
+		}
+		if err := selfupdate.VerifySignature(sums, sig, key); err != nil {
+			return "", err
+		}
+		fmt.Printf("  %s Signature verified\n", style.SuccessPrefix)
+	case upgradeRequireSig && !signed:
+		return "", fmt.Errorf("%w: release %s is not signed", selfupdate.ErrSignature, release.Tag)
+	case upgradeRequireSig:
+		return "", fmt.Errorf("%w: no public key (--public-key or GT_UPGRADE_PUBKEY)", selfupdate.ErrSignature)
+	}
+
+	if err := selfupdate.VerifyChecksum(archive, sums, name); err != nil {
+		return "", err
+	}
+	fmt.Printf("  %s Checksum verified\n", style.SuccessPrefix)
+
+	bin, err := selfupdate.ExtractBinary(archive, name)
+	if err != nil {
+		return "", err
+	}
+	return selfupdate.Stage(exe, bin)
+}
+
+// healthCheck runs the binary's version and doctor commands.
+func healthCheck(exe string) error {
+	if _, err := selfupdate.Inspect(exe); err != nil {
+		return err
+	}
+	doctor := exec.Command(exe, "doctor") //nolint:gosec // G204: the installed gt binary
+	if out, err := doctor.CombinedOutput(); err != nil {
+		return fmt.Errorf("gt doctor: %w: %s", err, lastStderrLine(string(out)))
+	}
+	return nil
+}
+
+func rollbackSelfUpgrade(exe string) error {
+	backup := exe + ".old"
+	if _, err := os.Stat(backup); err != nil {
+		return fmt.Errorf("no previous binary to roll back to at %s", backup)
+	}
+	version := "the previous version"
+	if info, err := selfupdate.Inspect(backup); err == nil {
+		version = "gt " + info.Version
+	}
+	if err := selfupdate.Rollback(exe, backup); err != nil {
+		return err
+	}
+	fmt.Printf("%s Rolled back to %s\n", style.SuccessPrefix, version)
+	return nil
+}
+
+// upgradeMigrateArgs passes the migration flags on to the new binary.
+func upgradeMigrateArgs() []string {
+	args := []string{"upgrade"}
+	if upgradeVerbose {
+		args = append(args, "--verbose")
+	}
+	if upgradeNoStart {
+		args = append(args, "--no-start")
+	}
+	return args
+}
+
+func currentExecutable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("finding gt binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return exe, nil
+}
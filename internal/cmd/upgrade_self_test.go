@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/selfupdate"
+)
+
+func TestVersionJSON_ReportsSchemas(t *testing.T) {
+	versionJSON = true
+	defer func() { versionJSON = false }()
+
+	out := captureStdout(t, func() { versionCmd.Run(versionCmd, nil) })
+	var info selfupdate.BinaryInfo
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		t.Fatalf("version --json output %q: %v", out, err)
+	}
+	if info.Version != Version || info.TownSchema != config.CurrentTownVersion || info.RigsSchema != config.CurrentRigsVersion {
+		t.Errorf("BinaryInfo = %+v", info)
+	}
+}
+
+func TestUpgrade_SelfOnlyFlagsRequireSelf(t *testing.T) {
+	townRoot := setupTestTownForConfig(t)
+	t.Chdir(townRoot)
+	upgradeTo = "0.13.0"
+	defer func() { upgradeTo = "" }()
+
+	err := runUpgrade(upgradeCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "require --self") {
+		t.Errorf("err = %v, want --self requirement", err)
+	}
+}
+
+func TestUpgradeMigrateArgs(t *testing.T) {
+	upgradeVerbose, upgradeNoStart = true, true
+	defer func() { upgradeVerbose, upgradeNoStart = false, false }()
+
+	got := upgradeMigrateArgs()
+	if !slices.Equal(got, []string{"upgrade", "--verbose", "--no-start"}) {
+		t.Errorf("upgradeMigrateArgs = %v", got)
+	}
+	if slices.Contains(got, "--self") {
+		t.Error("the new binary must not self-upgrade again")
+	}
+}
@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/invariant"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	verifyRepair bool
+	verifyJSON   bool
+)
+
+var verifyCmd = &cobra.Command{
+	Use:     "verify",
+	GroupID: GroupDiag,
+	Short:   "Cross-check sessions, worktrees, hooks and the queue",
+	Long: `Check that the town's state sources agree with each other.
+
+Four sources each hold part of the picture of who is doing what:
+
+  sessions   live tmux sessions of agents
+  worktrees  polecat and crew checkouts (<rig>/polecats/<name>, <rig>/crew/<name>)
+  hooks      beads in hooked status and their assignees
+  queue      beads scheduled for deferred dispatch (open sling contexts)
+
+Violations reported:
+
+  orphaned-hook   bead hooked to a polecat or crew member with no worktree
+  unknown-agent   bead hooked to no one, or to an unregistered rig's agent
+  dead-hook       bead hooked to an agent with no session
+  double-hook     an agent holding more than one hooked bead
+  stray-session   a polecat session with no worktree
+  queued-hooked   a queued bead that is already hooked
+  queued-closed   a queued bead that is already closed
+  queued-no-rig   a bead queued for a rig that isn't registered
+
+With --repair, violations with one safe fix are fixed: orphaned beads are
+reopened and unassigned, stray sessions are killed and stale queue entries
+are closed. The rest are only reported: dead hooks are the deacon's to
+release once it has checked the worktree for unsaved work.
+
+To check state against the event log instead, use gt town rebuild-state.
+
+Exits 1 when violations remain.
+
+Examples:
+  gt verify            # Report violations
+  gt verify --repair   # Fix the safe ones
+  gt verify --json`,
+	Args: cobra.NoArgs,
+	RunE: runVerify,
+}
+
+var (
+	// verifyListSessionsFn is a seam for tests. Production asks tmux.
+	verifyListSessionsFn = func() ([]string, error) { return tmux.NewTmux().ListSessions() }
+
+	// verifyKillSessionFn is a seam for tests. Production kills the session and
+	// its processes over tmux.
+	verifyKillSessionFn = func(name string) error { return tmux.NewTmux().KillSessionWithProcesses(name) }
+
+	// verifyReleaseFn is a seam for tests. Production reopens the bead and
+	// clears its assignee.
+	verifyReleaseFn = func(id string) error {
+		open, empty := "open", ""
+		return beads.New(resolveBeadDir(id)).Update(id, beads.UpdateOptions{Status: &open, Assignee: &empty})
+	}
+)
+
+func init() {
+	verifyCmd.Flags().BoolVar(&verifyRepair, "repair", false, "Fix violations that have a safe repair")
+	verifyCmd.Flags().BoolVar(&verifyJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(verifyCmd)
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	snap, contexts, err := gatherInvariantSnapshot(townRoot)
+	if err != nil {
+		return err
+	}
+	violations := invariant.Check(snap)
+
+	var repaired []invariant.Violation
+	var failed []string
+	if verifyRepair {
+		remaining := violations[:0:0]
+		for _, v := range violations {
+			if !v.Repairable() {
+				remaining = append(remaining, v)
+				continue
+			}
+			if err := repairViolation(townRoot, v, contexts); err != nil {
+				failed = append(failed, fmt.Sprintf("%s %s: %v", v.Kind, v.Subject, err))
+				remaining = append(remaining, v)
+				continue
+			}
+			repaired = append(repaired, v)
+		}
+		violations = remaining
+	}
+
+	if verifyJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(map[string]any{"violations": violations, "repaired": repaired, "failed": failed}); err != nil {
+			return err
+		}
+	} else {
+		printVerify(snap, violations, repaired, failed)
+	}
+	if len(violations) > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// gatherInvariantSnapshot reads every state source, and the sling context
+// holding each queued bead.
+func gatherInvariantSnapshot(townRoot string) (*invariant.Snapshot, map[string]string, error) {
+	snap := &invariant.Snapshot{
+		Rigs:      map[string]bool{},
+		Sessions:  map[string]string{},
+		Worktrees: map[string]bool{},
+		Closed:    map[string]bool{},
+	}
+	if rigs, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json")); err == nil {
+		for name := range rigs.Rigs {
+			snap.Rigs[name] = true
+			for _, role := range []string{"polecats", "crew"} {
+				for _, agent := range listWorktreeAgents(townRoot, name, role) {
+					snap.Worktrees[name+"/"+role+"/"+agent] = true
+				}
+			}
+		}
+	}
+
+	sessions, err := verifyListSessionsFn()
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	for _, name := range sessions {
+		if id, err := session.ParseSessionName(name); err == nil && id.Address() != "" {
+			snap.Sessions[id.Address()] = name
+		}
+	}
+
+	if snap.Hooked, err = townStateListHookedFn(townRoot); err != nil {
+		return nil, nil, err
+	}
+	var contexts map[string]string
+	if snap.Queued, contexts, err = townStateListQueuedFn(townRoot); err != nil {
+		return nil, nil, err
+	}
+	for id := range snap.Queued {
+		if b, ok := townStateBeadFn(id); ok && b.Status == "closed" {
+			snap.Closed[id] = true
+		}
+	}
+	return snap, contexts, nil
+}
+
+// listWorktreeAgents returns the names of a rig's polecats or crew that
+// have a worktree: <role>/<name>/<rig> in the current layout, or
+// <role>/<name> in the old one.
+func listWorktreeAgents(townRoot, rig, role string) []string {
+	entries, err := os.ReadDir(filepath.Join(townRoot, rig, role))
+	if err != nil {
+		return nil
+	}
+	hasGit := func(dir string) bool {
+		_, err := os.Stat(filepath.Join(dir, ".git"))
+		return err == nil
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		dir := filepath.Join(townRoot, rig, role, e.Name())
+		if hasGit(filepath.Join(dir, rig)) || hasGit(dir) {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+// repairViolation applies a violation's repair.
+func repairViolation(townRoot string, v invariant.Violation, contexts map[string]string) error {
+	switch v.Repair {
+	case invariant.RepairRelease:
+		return verifyReleaseFn(v.Subject)
+	case invariant.RepairKill:
+		return verifyKillSessionFn(v.Subject)
+	case invariant.RepairDequeue:
+		return townStateDequeueFn(townRoot, contexts[v.Subject])
+	}
+	return fmt.Errorf("no repair for %s", v.Kind)
+}
+
+func printVerify(snap *invariant.Snapshot, violations, repaired []invariant.Violation, failed []string) {
+	fmt.Printf("Checked %d sessions, %d worktrees, %d hooked beads, %d queued beads\n",
+		len(snap.Sessions), len(snap.Worktrees), len(snap.Hooked), len(snap.Queued))
+
+	for _, v := range repaired {
+		fmt.Printf("  %s %-14s %s: %s (%s)\n", style.Success.Render("✓"), v.Kind, v.Subject, v.Detail, repairVerb(v.Repair))
+	}
+	for _, f := range failed {
+		fmt.Printf("  %s repair failed: %s\n", style.Error.Render("✗"), f)
+	}
+	for _, v := range violations {
+		mark := style.Warning.Render("⚠")
+		if !v.Repairable() {
+			mark = style.Dim.Render("?")
+		}
+		fmt.Printf("  %s %-14s %s: %s\n", mark, v.Kind, v.Subject, v.Detail)
+	}
+
+	repairable := 0
+	for _, v := range violations {
+		if v.Repairable() {
+			repairable++
+		}
+	}
+	switch {
+	case len(violations) == 0 && len(repaired) == 0:
+		fmt.Printf("%s Town state is consistent\n", style.SuccessPrefix)
+	case len(violations) == 0:
+		fmt.Printf("%s Repaired %d violation(s)\n", style.SuccessPrefix, len(repaired))
+	case repairable > 0 && !verifyRepair:
+		fmt.Printf("\n%d violation(s), %d repairable. Run %s to fix them.\n", len(violations), repairable, style.Bold.Render("gt verify --repair"))
+	default:
+		fmt.Printf("\n%d violation(s) need attention.\n", len(violations))
+	}
+}
+
+func repairVerb(repair string) string {
+	switch repair {
+	case invariant.RepairRelease:
+		return "released"
+	case invariant.RepairKill:
+		return "session killed"
+	case invariant.RepairDequeue:
+		return "dequeued"
+	}
+	return repair
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/verify"
+)
+
+var (
+	// verifyShowIssueFn is a seam for tests. Production uses bd show.
+	verifyShowIssueFn = func(dir, id string) (*beads.Issue, error) {
+		return beads.New(dir).Show(id)
+	}
+
+	// verifyDiffFn is a seam for tests. Production diffs the branch against its
+	// base with git.
+	verifyDiffFn = func(dir, base string) (string, error) {
+		out, err := exec.Command("git", "-C", dir, "diff", base+"...HEAD").Output()
+		return string(out), err
+	}
+
+	// verifyRunTestsFn is a seam for tests. Production runs the rig test command
+	// under sh.
+	verifyRunTestsFn = func(dir, command string, timeout time.Duration) (string, bool) {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		c := exec.CommandContext(ctx, "sh", "-c", command)
+		c.Dir = dir
+		out, err := c.CombinedOutput()
+		if ctx.Err() == context.DeadlineExceeded {
+			out = append(out, fmt.Sprintf("\n(timed out after %s)\n", timeout)...)
+		}
+		return string(out), err != nil
+	}
+
+	// verifyAskFn is a seam for tests. Production asks the verifier agent in
+	// print mode.
+	verifyAskFn = func(townRoot, rigPath, dir, agent, prompt string, timeout time.Duration) (string, error) {
+		rc, agentName, err := config.ResolveAgentConfigWithOverride(townRoot, rigPath, agent)
+		if err != nil {
+			return "", fmt.Errorf("resolving agent: %w", err)
+		}
+		inv, err := buildAskInvocation(rc, agentName, prompt, "")
+		if err != nil {
+			return "", err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		c := exec.CommandContext(ctx, inv.Argv[0], inv.Argv[1:]...) //nolint:gosec // G204: argv from trusted agent config
+		c.Dir = dir
+		c.Env = clearClaudeCodeEnv(os.Environ())
+		var stdout bytes.Buffer
+		c.Stdout = &stdout
+		c.Stderr = os.Stderr
+		if err := c.Run(); err != nil {
+			if ctx.Err() == context.DeadlineExceeded {
+				return "", fmt.Errorf("verifier did not answer within %s", timeout)
+			}
+			return "", fmt.Errorf("running verifier agent: %w", err)
+		}
+		if inv.Structured {
+			answer, _, err := parseAskStructuredOutput(stdout.Bytes())
+			return answer, err
+		}
+		return stdout.String(), nil
+	}
+)
+
+// loadVerifyConfig returns the rig's verify_done settings, or nil.
+func loadVerifyConfig(rigPath string) (*verify.Config, error) {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return settings.VerifyDone, nil
+}
+
+// checkVerifyForDone is the gt done acceptance gate. When the rig enables
+// verify_done, a verifier agent checks the branch against the bead's
+// acceptance criteria; a rejection prints the gaps and returns an error so
+// the polecat stays on the work. Problems running the gate itself only
+// warn, like the static analysis gate.
+func checkVerifyForDone(townRoot, rigPath, dir, issueID, base string) error {
+	cfg, err := loadVerifyConfig(rigPath)
+	if err != nil {
+		style.PrintWarning("acceptance verification skipped: %v", err)
+		return nil
+	}
+	if !cfg.IsEnabled() || issueID == "" {
+		return nil
+	}
+
+	issue, err := verifyShowIssueFn(dir, issueID)
+	if err != nil {
+		style.PrintWarning("acceptance verification skipped: %v", err)
+		return nil
+	}
+	req := &verify.Request{
+		BeadID:      issueID,
+		Title:       issue.Title,
+		Criteria:    issue.AcceptanceCriteria,
+		Description: issue.Description,
+	}
+	if !req.HasCriteria() {
+		style.PrintWarning("acceptance verification skipped: %s has no acceptance criteria or description", issueID)
+		return nil
+	}
+	if req.Diff, err = verifyDiffFn(dir, base); err != nil {
+		style.PrintWarning("acceptance verification skipped: diff against %s: %v", base, err)
+		return nil
+	}
+
+	timeout := cfg.GetTimeout()
+	if cfg.Test != "" {
+		fmt.Printf("Running %s for the verifier...\n", cfg.Test)
+		req.TestCommand = cfg.Test
+		req.TestOutput, req.TestFailed = verifyRunTestsFn(dir, cfg.Test, timeout)
+	}
+
+	fmt.Printf("Verifying %s against its acceptance criteria...\n", issueID)
+	answer, err := verifyAskFn(townRoot, rigPath, dir, cfg.Agent, req.Prompt(), timeout)
+	if err != nil {
+		style.PrintWarning("acceptance verification skipped: %v", err)
+		return nil
+	}
+	verdict, err := verify.ParseVerdict(answer)
+	if err != nil {
+		style.PrintWarning("acceptance verification skipped: %v", err)
+		return nil
+	}
+	if verdict.Accepted {
+		fmt.Printf("%s Verifier accepted %s", style.Bold.Render("✓"), issueID)
+		if verdict.Summary != "" {
+			fmt.Printf(": %s", verdict.Summary)
+		}
+		fmt.Println()
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Print(verdict.BounceMessage(issueID))
+	fmt.Println()
+	return fmt.Errorf("cannot complete: acceptance verifier found %d gap(s) in %s", len(verdict.Gaps), issueID)
+}
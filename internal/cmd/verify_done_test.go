@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/verify"
+)
+
+func stubVerify(t *testing.T, answer string, askErr error) *string {
+	t.Helper()
+	origShow, origDiff, origTests, origAsk := verifyShowIssueFn, verifyDiffFn, verifyRunTestsFn, verifyAskFn
+	t.Cleanup(func() {
+		verifyShowIssueFn, verifyDiffFn, verifyRunTestsFn, verifyAskFn = origShow, origDiff, origTests, origAsk
+	})
+
+	verifyShowIssueFn = func(dir, id string) (*beads.Issue, error) {
+		return &beads.Issue{ID: id, Title: "Retry webhooks", AcceptanceCriteria: "- [ ] retries 3 times"}, nil
+	}
+	verifyDiffFn = func(dir, base string) (string, error) { return "diff --git a/hook.go b/hook.go", nil }
+	verifyRunTestsFn = func(dir, command string, timeout time.Duration) (string, bool) { return "ok  hooks", false }
+	var prompt string
+	verifyAskFn = func(townRoot, rigPath, dir, agent, p string, timeout time.Duration) (string, error) {
+		prompt = p
+		return answer, askErr
+	}
+	return &prompt
+}
+
+func writeVerifyRig(t *testing.T, cfg *verify.Config) string {
+	t.Helper()
+	rigPath := t.TempDir()
+	settings := config.NewRigSettings()
+	settings.VerifyDone = cfg
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), settings); err != nil {
+		t.Fatal(err)
+	}
+	return rigPath
+}
+
+func TestCheckVerifyForDone_Disabled(t *testing.T) {
+	prompt := stubVerify(t, "", errors.New("should not run"))
+	if err := checkVerifyForDone("/town", t.TempDir(), "/wt", "gt-42", "origin/main"); err != nil {
+		t.Errorf("rig without settings: %v", err)
+	}
+	if err := checkVerifyForDone("/town", writeVerifyRig(t, &verify.Config{}), "/wt", "gt-42", "origin/main"); err != nil {
+		t.Errorf("disabled gate: %v", err)
+	}
+	if *prompt != "" {
+		t.Error("verifier ran while disabled")
+	}
+}
+
+func TestCheckVerifyForDone_Accepts(t *testing.T) {
+	prompt := stubVerify(t, `{"accepted": true, "summary": "retries covered"}`, nil)
+	rigPath := writeVerifyRig(t, &verify.Config{Enabled: true, Test: "go test ./..."})
+	if err := checkVerifyForDone("/town", rigPath, "/wt", "gt-42", "origin/main"); err != nil {
+		t.Fatalf("accepted work bounced: %v", err)
+	}
+	for _, want := range []string{"retries 3 times", "diff --git a/hook.go", "ok  hooks"} {
+		if !strings.Contains(*prompt, want) {
+			t.Errorf("verifier prompt missing %q", want)
+		}
+	}
+}
+
+func TestCheckVerifyForDone_Bounces(t *testing.T) {
+	stubVerify(t, `{"accepted": false, "gaps": ["retries: only 1 attempt in hook.go"]}`, nil)
+	rigPath := writeVerifyRig(t, &verify.Config{Enabled: true})
+	err := checkVerifyForDone("/town", rigPath, "/wt", "gt-42", "origin/main")
+	if err == nil || !strings.Contains(err.Error(), "1 gap(s)") {
+		t.Errorf("rejected work should block gt done, got %v", err)
+	}
+}
+
+func TestCheckVerifyForDone_VerifierFailureWarns(t *testing.T) {
+	stubVerify(t, "", errors.New("agent crashed"))
+	rigPath := writeVerifyRig(t, &verify.Config{Enabled: true})
+	if err := checkVerifyForDone("/town", rigPath, "/wt", "gt-42", "origin/main"); err != nil {
+		t.Errorf("a broken verifier must not strand work: %v", err)
+	}
+}
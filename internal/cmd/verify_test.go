@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/townstate"
+)
+
+func TestRunVerify_Repair(t *testing.T) {
+	town := t.TempDir()
+	for _, dir := range []string{"mayor", "gastown/polecats/toast/gastown/.git"} {
+		if err := os.MkdirAll(filepath.Join(town, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "mayor", "rigs.json"), []byte(`{"version":1,"rigs":{"gastown":{}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(town)
+
+	origReg := session.DefaultRegistry()
+	reg := session.NewPrefixRegistry()
+	reg.Register("gt", "gastown")
+	session.SetDefaultRegistry(reg)
+
+	origList, origKill, origRelease := verifyListSessionsFn, verifyKillSessionFn, verifyReleaseFn
+	origHooked, origQueued, origBead, origDequeue := townStateListHookedFn, townStateListQueuedFn, townStateBeadFn, townStateDequeueFn
+	origRepair, origJSON := verifyRepair, verifyJSON
+	t.Cleanup(func() {
+		session.SetDefaultRegistry(origReg)
+		verifyListSessionsFn, verifyKillSessionFn, verifyReleaseFn = origList, origKill, origRelease
+		townStateListHookedFn, townStateListQueuedFn, townStateBeadFn, townStateDequeueFn = origHooked, origQueued, origBead, origDequeue
+		verifyRepair, verifyJSON = origRepair, origJSON
+	})
+
+	var repairs []string
+	verifyListSessionsFn = func() ([]string, error) { return []string{"gt-toast", "gt-ghost"}, nil }
+	verifyKillSessionFn = func(name string) error { repairs = append(repairs, "kill "+name); return nil }
+	verifyReleaseFn = func(id string) error { repairs = append(repairs, "release "+id); return nil }
+	townStateListHookedFn = func(string) (map[string]string, error) {
+		return map[string]string{"gt-a": "gastown/polecats/toast", "gt-b": "gastown/polecats/gone", "gt-c": "gastown/crew/max"}, nil
+	}
+	townStateListQueuedFn = func(string) (map[string]string, map[string]string, error) {
+		return map[string]string{"gt-a": "gastown"}, map[string]string{"gt-a": "hq-ctx"}, nil
+	}
+	townStateBeadFn = func(id string) (townstate.Bead, bool) { return townstate.Bead{Status: "hooked"}, true }
+	townStateDequeueFn = func(_, contextID string) error { repairs = append(repairs, "dequeue "+contextID); return nil }
+
+	verifyRepair = true
+	var err error
+	out := captureStdout(t, func() { err = runVerify(nil, nil) })
+
+	want := []string{"release gt-b", "release gt-c", "kill gt-ghost", "dequeue hq-ctx"}
+	if strings.Join(repairs, ",") != strings.Join(want, ",") {
+		t.Errorf("repairs = %q, want %q", repairs, want)
+	}
+	// Nothing left that a repair could fix, so verify passes.
+	if err != nil {
+		t.Errorf("runVerify after repair: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Repaired 4 violation(s)") {
+		t.Errorf("output:\n%s", out)
+	}
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
 	"runtime"
 	"runtime/debug"
@@ -9,6 +11,8 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/selfupdate"
 	"github.com/steveyegge/gastown/internal/version"
 )
 
@@ -27,6 +31,7 @@ var (
 
 var versionVerbose bool
 var versionShort bool
+var versionJSON bool
 
 var versionCmd = &cobra.Command{
 	Use:         "version",
@@ -46,6 +51,18 @@ and the git revision the binary was built from (if available).`,
 		commit := resolveCommitHash()
 		branch := resolveBranch()
 
+		if versionJSON {
+			// Read by gt upgrade --self to check a new binary before installing it.
+			_ = json.NewEncoder(os.Stdout).Encode(selfupdate.BinaryInfo{
+				Version:    Version,
+				Build:      Build,
+				Commit:     commit,
+				TownSchema: config.CurrentTownVersion,
+				RigsSchema: config.CurrentRigsVersion,
+			})
+			return
+		}
+
 		if commit != "" && branch != "" {
 			fmt.Printf("gt version %s (%s: %s@%s)\n", Version, Build, branch, version.ShortCommit(commit))
 		} else if commit != "" {
@@ -65,6 +82,7 @@ func init() {
 	rootCmd.AddCommand(versionCmd)
 	versionCmd.Flags().BoolVarP(&versionVerbose, "verbose", "v", false, "Show extended version info including timestamp")
 	versionCmd.Flags().BoolVar(&versionShort, "short", false, "Output only the version number (e.g., 0.5.0-362)")
+	versionCmd.Flags().BoolVar(&versionJSON, "json", false, "Output version and supported town schema versions as JSON")
 
 	// Pass the build-time commit to the version package for stale binary checks
 	if Commit != "" {
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/views"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	viewJSON   bool
+	viewPanels bool
+)
+
+var viewCmd = &cobra.Command{
+	Use:     "view [name]",
+	GroupID: GroupWork,
+	Short:   "Run a saved bead query across every rig",
+	Long: `Run a named query from the views section of town settings/config.json.
+
+Views search every routed beads database, like gt bead search, with the
+filters saved under a name so nobody re-types them:
+
+  "views": {
+    "ready-frontend":  {"ready": true, "label": "frontend", "panel": true},
+    "stalled-over-2h": {"status": "in_progress", "stale_for": "2h"},
+    "my-escalations":  {"label": "gt:escalation", "status": "open", "assignee": "$me"}
+  }
+
+Filters: query (text), ready (no open blockers), status, label, assignee
+("$me" is whoever runs the view), rigs, stale_for (not updated for at
+least this long) and limit. Views with "panel": true appear on the web
+dashboard, and any view can feed gt sling --interactive --view <name>.
+
+With no name, lists the saved views.
+
+Examples:
+  gt view                      # List views
+  gt view stalled-over-2h
+  gt view my-escalations --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runView,
+}
+
+// viewQueryFn is a seam for tests. Production runs the view through
+// beadSearchSourceFn.
+var viewQueryFn = func(beadsDir string, v *views.View, assignee string) ([]*beads.Issue, error) {
+	if !v.Ready {
+		return beadSearchSourceFn(beadsDir, beads.SearchOptions{
+			Query:    v.Query,
+			Status:   v.Status,
+			Label:    v.Label,
+			Assignee: assignee,
+		})
+	}
+	issues, err := beads.New(beadsDir).Ready()
+	if err != nil {
+		return nil, err
+	}
+	var matched []*beads.Issue
+	for _, issue := range issues {
+		if matchesView(issue, v, assignee) {
+			matched = append(matched, issue)
+		}
+	}
+	return matched, nil
+}
+
+func init() {
+	viewCmd.Flags().BoolVar(&viewJSON, "json", false, "Output as JSON")
+	viewCmd.Flags().BoolVar(&viewPanels, "panels", false, "Run every dashboard panel view (with --json)")
+	_ = viewCmd.Flags().MarkHidden("panels")
+	rootCmd.AddCommand(viewCmd)
+}
+
+// loadViews returns the town's saved views.
+func loadViews(townRoot string) (views.Config, error) {
+	ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if err := ts.Views.Validate(); err != nil {
+		return nil, fmt.Errorf("views: %w", err)
+	}
+	return ts.Views, nil
+}
+
+// lookupView returns the named view or an error listing the known ones.
+func lookupView(townRoot, name string) (*views.View, error) {
+	all, err := loadViews(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	v, ok := all[name]
+	if !ok {
+		if len(all) == 0 {
+			return nil, fmt.Errorf("no view %q: no views are defined in settings/config.json", name)
+		}
+		return nil, fmt.Errorf("no view %q (have: %s)", name, strings.Join(all.Names(), ", "))
+	}
+	return v, nil
+}
+
+// matchesView applies a view's filters locally, for results bd didn't filter.
+func matchesView(issue *beads.Issue, v *views.View, assignee string) bool {
+	if v.Status != "" && v.Status != "all" && issue.Status != v.Status {
+		return false
+	}
+	if v.Label != "" && !beads.HasLabel(issue, v.Label) {
+		return false
+	}
+	if assignee != "" && issue.Assignee != assignee {
+		return false
+	}
+	if q := strings.ToLower(v.Query); q != "" &&
+		!strings.Contains(strings.ToLower(issue.ID), q) &&
+		!strings.Contains(strings.ToLower(issue.Title), q) &&
+		!strings.Contains(strings.ToLower(issue.Description), q) {
+		return false
+	}
+	return true
+}
+
+// runSavedView runs a view across the town's databases. me replaces $me in
+// the assignee filter.
+func runSavedView(townRoot string, v *views.View, me string, now time.Time) (beadSearchResult, error) {
+	all, err := beadSearchDBs(townRoot)
+	if err != nil {
+		return beadSearchResult{}, err
+	}
+	var dbs []beadSearchDB
+	for _, db := range all {
+		if v.IncludesRig(db.Rig) {
+			dbs = append(dbs, db)
+		}
+	}
+
+	assignee := v.ResolveAssignee(me)
+	staleBefore := v.StaleBefore(now)
+	result := searchAllBeads(dbs, func(dir string) ([]*beads.Issue, error) {
+		issues, err := viewQueryFn(dir, v, assignee)
+		if err != nil || staleBefore.IsZero() {
+			return issues, err
+		}
+		var stale []*beads.Issue
+		for _, issue := range issues {
+			if updated, err := time.Parse(time.RFC3339, issue.UpdatedAt); err == nil && updated.Before(staleBefore) {
+				stale = append(stale, issue)
+			}
+		}
+		return stale, nil
+	})
+	if v.Limit > 0 && len(result.Results) > v.Limit {
+		result.Results = result.Results[:v.Limit]
+	}
+	return result, nil
+}
+
+func runView(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if viewPanels {
+		return printViewPanels(townRoot)
+	}
+	if len(args) == 0 {
+		return listViews(townRoot)
+	}
+
+	v, err := lookupView(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+	result, err := runSavedView(townRoot, v, detectSender(), time.Now())
+	if err != nil {
+		return err
+	}
+
+	if viewJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	printBeadSearch(result)
+	for rigName, msg := range result.Errors {
+		style.PrintWarning("%s: %s", rigName, msg)
+	}
+	return nil
+}
+
+func listViews(townRoot string) error {
+	all, err := loadViews(townRoot)
+	if err != nil {
+		return err
+	}
+	if viewJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(all)
+	}
+	if len(all) == 0 {
+		fmt.Println("No views defined. Add a \"views\" section to settings/config.json (see gt view --help).")
+		return nil
+	}
+	for _, name := range all.Names() {
+		v := all[name]
+		line := style.Bold.Render(name)
+		if v.Panel {
+			line += style.Dim.Render(" [panel]")
+		}
+		if v.Description != "" {
+			line += "  " + v.Description
+		}
+		fmt.Println(line)
+		fmt.Printf("  %s\n", style.Dim.Render(v.Describe()))
+	}
+	return nil
+}
+
+// printViewPanels writes the results of every panel view as JSON, for the
+// web dashboard.
+func printViewPanels(townRoot string) error {
+	all, err := loadViews(townRoot)
+	if err != nil {
+		return err
+	}
+	panels := make([]views.Panel, 0)
+	me := detectSender()
+	for _, name := range all.Names() {
+		v := all[name]
+		if !v.Panel {
+			continue
+		}
+		panel := views.Panel{Name: name, Description: v.Description, Results: []views.PanelItem{}}
+		result, err := runSavedView(townRoot, v, me, time.Now())
+		if err != nil {
+			panel.Error = err.Error()
+		}
+		for _, hit := range result.Results {
+			panel.Results = append(panel.Results, views.PanelItem{
+				Rig: hit.Rig, ID: hit.ID, Title: hit.Title, Status: hit.Status,
+				Priority: hit.Priority, Assignee: hit.Assignee,
+			})
+		}
+		panels = append(panels, panel)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(panels)
+}
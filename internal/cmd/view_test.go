@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/views"
+)
+
+func TestRunSavedView(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, dir := range []string{".beads", "greenplace/mayor/rig", "beads/mayor/rig"} {
+		if err := os.MkdirAll(filepath.Join(townRoot, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	routes := `{"prefix": "hq-", "path": "."}
+{"prefix": "gp-", "path": "greenplace/mayor/rig"}
+{"prefix": "bd-", "path": "beads/mayor/rig"}
+`
+	if err := os.WriteFile(filepath.Join(townRoot, ".beads", "routes.jsonl"), []byte(routes), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-3 * time.Hour).Format(time.RFC3339)
+	fresh := now.Add(-10 * time.Minute).Format(time.RFC3339)
+
+	orig := viewQueryFn
+	t.Cleanup(func() { viewQueryFn = orig })
+	var gotAssignee string
+	viewQueryFn = func(dir string, v *views.View, assignee string) ([]*beads.Issue, error) {
+		gotAssignee = assignee
+		switch filepath.Base(filepath.Dir(filepath.Dir(dir))) {
+		case "greenplace":
+			return []*beads.Issue{
+				{ID: "gp-1", UpdatedAt: old},
+				{ID: "gp-2", UpdatedAt: fresh},
+				{ID: "gp-3", UpdatedAt: old},
+			}, nil
+		case "beads":
+			return []*beads.Issue{{ID: "bd-1", UpdatedAt: old}}, nil
+		}
+		return []*beads.Issue{{ID: "hq-1", UpdatedAt: old}}, nil
+	}
+
+	t.Run("stale and rigs", func(t *testing.T) {
+		v := &views.View{Status: "in_progress", StaleFor: "2h", Rigs: []string{"greenplace"}, Assignee: views.Me}
+		result, err := runSavedView(townRoot, v, "greenplace/crew/mel", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, hit := range result.Results {
+			ids = append(ids, hit.ID)
+		}
+		if len(ids) != 2 || ids[0] != "gp-1" || ids[1] != "gp-3" {
+			t.Errorf("results = %v, want [gp-1 gp-3]", ids)
+		}
+		if gotAssignee != "greenplace/crew/mel" {
+			t.Errorf("assignee = %q, want $me resolved", gotAssignee)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		v := &views.View{Status: "open", Limit: 2}
+		result, err := runSavedView(townRoot, v, "mayor", now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Results) != 2 {
+			t.Fatalf("results = %d, want 2", len(result.Results))
+		}
+		if result.Results[0].ID != "hq-1" {
+			t.Errorf("first result = %s, want town bead first", result.Results[0].ID)
+		}
+	})
+}
+
+func TestMatchesView(t *testing.T) {
+	issue := &beads.Issue{ID: "gp-7", Title: "Flaky login test", Status: "open", Assignee: "greenplace/polecats/toast", Labels: []string{"frontend"}}
+	tests := []struct {
+		name     string
+		v        *views.View
+		assignee string
+		want     bool
+	}{
+		{"label", &views.View{Ready: true, Label: "frontend"}, "", true},
+		{"other label", &views.View{Ready: true, Label: "backend"}, "", false},
+		{"status", &views.View{Ready: true, Status: "in_progress"}, "", false},
+		{"assignee", &views.View{Ready: true}, "greenplace/polecats/toast", true},
+		{"other assignee", &views.View{Ready: true}, "mayor", false},
+		{"query", &views.View{Ready: true, Query: "login"}, "", true},
+		{"query miss", &views.View{Ready: true, Query: "deploy"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesView(issue, tt.v, tt.assignee); got != tt.want {
+				t.Errorf("matchesView() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,341 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/watch"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	watchGlob        string
+	watchRig         string
+	watchTitle       string
+	watchDescription string
+	watchLabels      []string
+	watchRecursive   bool
+	watchOnChange    bool
+	watchCatchUp     bool
+	watchRuleDryRun  bool
+)
+
+var watchCmd = &cobra.Command{
+	Use:     "watch [path]",
+	GroupID: GroupWork,
+	Short:   "Create and sling beads when files appear in a folder",
+	Long: `Watch folders and turn new files into slung beads.
+
+Each matching file becomes a bead whose title and description come from
+templates, and the bead is slung to the rule's rig. Drop a spec into
+designs/ and a polecat starts implementing it.
+
+With no path, runs every rule configured with 'gt watch add'. With a path,
+watches just that folder using the flags given (no config needed).
+
+Files already present when the watcher starts are treated as seen; use
+--catch-up to process them too. Each file triggers once per rule (or again
+on content change with --on-change), tracked in .runtime/watch-state.json.
+
+Templates use Go text/template with fields: .Path .RelPath .Name .Stem
+.Dir .Rule .Content (text files, truncated to 16KB).
+
+Examples:
+  gt watch designs/ --glob '*.md' --rig gastown
+  gt watch add specs designs/ --glob '*.md' --rig gastown --title 'Implement {{.Stem}}'
+  gt watch                       # Run all configured rules
+  gt watch --dry-run             # Preview beads for files already present
+  gt watch list
+  gt watch remove specs`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWatch,
+}
+
+var watchAddCmd = &cobra.Command{
+	Use:   "add <name> <path>",
+	Short: "Add a watch rule to town settings",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runWatchAdd,
+}
+
+var watchListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured watch rules",
+	Args:  cobra.NoArgs,
+	RunE:  runWatchList,
+}
+
+var watchRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a watch rule",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runWatchRemove,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{watchCmd, watchAddCmd} {
+		c.Flags().StringVar(&watchGlob, "glob", "", "File name pattern to match (default: *)")
+		c.Flags().StringVar(&watchRig, "rig", "", "Rig to sling created beads to (empty: create only)")
+		c.Flags().StringVar(&watchTitle, "title", "", "Bead title template (default: \""+watch.DefaultTitle+"\")")
+		c.Flags().StringVar(&watchDescription, "description", "", "Bead description template (default: file path and content)")
+		c.Flags().StringArrayVar(&watchLabels, "label", nil, "Label to add to created beads (repeatable)")
+		c.Flags().BoolVar(&watchRecursive, "recursive", false, "Also watch subdirectories")
+		c.Flags().BoolVar(&watchOnChange, "on-change", false, "Trigger again when a file's content changes")
+	}
+	watchCmd.Flags().BoolVar(&watchCatchUp, "catch-up", false, "Process files already present when the watcher starts")
+	watchCmd.Flags().BoolVar(&watchRuleDryRun, "dry-run", false, "Print the beads existing files would create, then exit")
+
+	watchCmd.AddCommand(watchAddCmd, watchListCmd, watchRemoveCmd)
+	rootCmd.AddCommand(watchCmd)
+}
+
+// watchRuleFromFlags builds a rule from the shared flags.
+func watchRuleFromFlags(name, path string) watch.Rule {
+	return watch.Rule{
+		Name:        name,
+		Path:        path,
+		Glob:        watchGlob,
+		Recursive:   watchRecursive,
+		OnChange:    watchOnChange,
+		Rig:         watchRig,
+		Title:       watchTitle,
+		Description: watchDescription,
+		Labels:      watchLabels,
+	}
+}
+
+var watchNameSanitizer = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var rules []watch.Rule
+	if len(args) == 1 {
+		dir, err := filepath.Abs(args[0])
+		if err != nil {
+			return err
+		}
+		name := "cli-" + watchNameSanitizer.ReplaceAllString(strings.TrimPrefix(dir, townRoot), "-")
+		rules = []watch.Rule{watchRuleFromFlags(strings.TrimSuffix(name, "-"), dir)}
+	} else {
+		settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+		if err != nil {
+			return fmt.Errorf("loading town settings: %w", err)
+		}
+		if settings.Watch == nil || len(settings.Watch.Rules) == 0 {
+			return fmt.Errorf("no watch rules configured (add one with: gt watch add <name> <path> --rig <rig>)")
+		}
+		rules = settings.Watch.Rules
+	}
+	if err := (&watch.Config{Rules: rules}).Validate(); err != nil {
+		return err
+	}
+	for _, r := range rules {
+		if r.Rig == "" {
+			continue
+		}
+		if _, _, err := getRig(r.Rig); err != nil {
+			return fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+	}
+
+	state, err := watch.LoadState(townRoot)
+	if err != nil {
+		return err
+	}
+	w := &watch.Watcher{
+		TownRoot: townRoot,
+		Rules:    rules,
+		State:    state,
+		Trigger:  watchTrigger(townRoot, watchRuleDryRun),
+		Logf: func(format string, args ...any) {
+			style.PrintWarning(format, args...)
+		},
+	}
+	if watchRuleDryRun {
+		// Dry runs preview existing files and must not record them as seen.
+		w.State = watch.NewMemoryState()
+	}
+
+	if err := w.Scan(watchCatchUp || watchRuleDryRun); err != nil {
+		return err
+	}
+	if watchRuleDryRun {
+		return nil
+	}
+
+	for _, r := range rules {
+		target := r.Rig
+		if target == "" {
+			target = "(create only)"
+		}
+		fmt.Printf("%s Watching %s for %s → %s\n", style.Success.Render("●"), r.Dir(townRoot), watchGlobLabel(r), target)
+	}
+	fmt.Println(style.Dim.Render("Press Ctrl+C to stop."))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return w.Run(ctx)
+}
+
+func watchGlobLabel(r watch.Rule) string {
+	glob := r.Glob
+	if glob == "" {
+		glob = watch.DefaultGlob
+	}
+	if r.Recursive {
+		glob = "**/" + glob
+	}
+	return glob
+}
+
+// watchTrigger creates a bead for a matched file and slings it to the rule's rig.
+func watchTrigger(townRoot string, dryRun bool) watch.TriggerFunc {
+	return func(rule watch.Rule, f watch.File) error {
+		title, description, err := rule.Render(f)
+		if err != nil {
+			return fmt.Errorf("rendering templates: %w", err)
+		}
+		if dryRun {
+			fmt.Printf("%s %s → %q", style.Dim.Render("[dry-run]"), f.RelPath, title)
+			if rule.Rig != "" {
+				fmt.Printf(" (sling to %s)", rule.Rig)
+			}
+			fmt.Println()
+			return nil
+		}
+
+		beadsDir := beads.ResolveBeadsDir(townRoot)
+		if rule.Rig != "" {
+			beadsDir = beads.ResolveBeadsDir(filepath.Join(townRoot, rule.Rig))
+		}
+		labels := append([]string{"gt:task", "watch:" + rule.Name}, rule.Labels...)
+		issue, err := beads.New(beadsDir).Create(beads.CreateOptions{
+			Title:       title,
+			Labels:      labels,
+			Priority:    -1,
+			Description: description,
+			Actor:       "watch",
+		})
+		if err != nil {
+			return fmt.Errorf("creating bead: %w", err)
+		}
+		fmt.Printf("%s %s → %s %s\n", style.Success.Render("✓"), f.RelPath, style.Bold.Render(issue.ID), title)
+
+		if rule.Rig == "" {
+			return nil
+		}
+		slingCmd := exec.Command("gt", "sling", issue.ID, rule.Rig)
+		slingCmd.Dir = townRoot
+		slingCmd.Stdout = os.Stdout
+		slingCmd.Stderr = os.Stderr
+		if err := slingCmd.Run(); err != nil {
+			// The bead exists; don't re-create it on the next event.
+			style.PrintWarning("created %s but sling to %s failed: %v (retry: gt sling %s %s)",
+				issue.ID, rule.Rig, err, issue.ID, rule.Rig)
+		}
+		return nil
+	}
+}
+
+func runWatchAdd(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	name, path := args[0], args[1]
+	// Store paths inside the town relative to it so the town can move.
+	if abs, err := filepath.Abs(path); err == nil {
+		if rel, err := filepath.Rel(townRoot, abs); err == nil && !strings.HasPrefix(rel, "..") {
+			path = rel
+		} else {
+			path = abs
+		}
+	}
+	if watchRig != "" {
+		if _, _, err := getRig(watchRig); err != nil {
+			return err
+		}
+	}
+
+	settingsPath := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.Watch == nil {
+		settings.Watch = &watch.Config{}
+	}
+	if _, exists := settings.Watch.Find(name); exists {
+		return fmt.Errorf("watch rule %q already exists (remove it first: gt watch remove %s)", name, name)
+	}
+	settings.Watch.Rules = append(settings.Watch.Rules, watchRuleFromFlags(name, path))
+	if err := settings.Watch.Validate(); err != nil {
+		return err
+	}
+	if err := config.SaveTownSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+	fmt.Printf("%s Added watch rule %s (run with: gt watch)\n", style.Success.Render("✓"), name)
+	return nil
+}
+
+func runWatchList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.Watch == nil || len(settings.Watch.Rules) == 0 {
+		fmt.Println("No watch rules configured.")
+		return nil
+	}
+	for _, r := range settings.Watch.Rules {
+		target := r.Rig
+		if target == "" {
+			target = "(create only)"
+		}
+		fmt.Printf("%s  %s/%s → %s\n", style.Bold.Render(r.Name), r.Path, watchGlobLabel(r), target)
+		if r.Title != "" {
+			fmt.Printf("    %s\n", style.Dim.Render("title: "+r.Title))
+		}
+	}
+	return nil
+}
+
+func runWatchRemove(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settingsPath := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if !settings.Watch.Remove(args[0]) {
+		return fmt.Errorf("no watch rule named %q", args[0])
+	}
+	if err := config.SaveTownSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+	fmt.Printf("%s Removed watch rule %s\n", style.Success.Render("✓"), args[0])
+	return nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/watch"
+)
+
+func resetWatchFlags(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		watchGlob, watchRig, watchTitle, watchDescription = "", "", "", ""
+		watchLabels = nil
+		watchRecursive, watchOnChange, watchCatchUp, watchRuleDryRun = false, false, false, false
+	})
+}
+
+func TestWatchAddListRemove(t *testing.T) {
+	townRoot := setupTestTownForTheme(t)
+	t.Chdir(townRoot)
+	resetWatchFlags(t)
+
+	watchGlob = "*.md"
+	watchTitle = "Spec: {{.Stem}}"
+	if err := runWatchAdd(watchAddCmd, []string{"specs", filepath.Join(townRoot, "designs")}); err != nil {
+		t.Fatalf("runWatchAdd() error = %v", err)
+	}
+	if err := runWatchAdd(watchAddCmd, []string{"specs", "designs"}); err == nil {
+		t.Error("expected error adding duplicate rule")
+	}
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, ok := settings.Watch.Find("specs")
+	if !ok {
+		t.Fatal("rule not saved")
+	}
+	if r.Path != "designs" || r.Glob != "*.md" || r.Title != "Spec: {{.Stem}}" {
+		t.Errorf("saved rule = %+v, want town-relative path and flags", r)
+	}
+
+	out := captureStdout(t, func() {
+		if err := runWatchList(watchListCmd, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if !strings.Contains(out, "specs") || !strings.Contains(out, "designs/*.md") {
+		t.Errorf("list output = %q", out)
+	}
+
+	if err := runWatchRemove(watchRemoveCmd, []string{"specs"}); err != nil {
+		t.Fatalf("runWatchRemove() error = %v", err)
+	}
+	if err := runWatchRemove(watchRemoveCmd, []string{"specs"}); err == nil {
+		t.Error("expected error removing missing rule")
+	}
+}
+
+func TestWatchAddRejectsBadTemplate(t *testing.T) {
+	townRoot := setupTestTownForTheme(t)
+	t.Chdir(townRoot)
+	resetWatchFlags(t)
+
+	watchTitle = "{{.Stem"
+	if err := runWatchAdd(watchAddCmd, []string{"bad", "designs"}); err == nil {
+		t.Error("expected template error")
+	}
+}
+
+func TestWatchDryRunPreviewsExistingFiles(t *testing.T) {
+	townRoot := setupTestTownForTheme(t)
+	t.Chdir(townRoot)
+	resetWatchFlags(t)
+
+	spec := filepath.Join(townRoot, "designs", "auth.md")
+	if err := os.MkdirAll(filepath.Dir(spec), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(spec, []byte("OAuth"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	watchGlob = "*.md"
+	watchRuleDryRun = true
+	out := captureStdout(t, func() {
+		if err := runWatch(watchCmd, []string{filepath.Join(townRoot, "designs")}); err != nil {
+			t.Fatalf("runWatch() error = %v", err)
+		}
+	})
+	if !strings.Contains(out, `designs/auth.md → "Implement auth"`) {
+		t.Errorf("dry-run output = %q", out)
+	}
+	if _, err := os.Stat(watch.StatePath(townRoot)); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote state file (err=%v)", err)
+	}
+}
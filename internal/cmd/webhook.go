@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/webhook"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	webhookListen  string
+	webhookEvent   string
+	webhookHeaders []string
+)
+
+var webhookCmd = &cobra.Command{
+	Use:     "webhook",
+	GroupID: GroupServices,
+	Short:   "Inbound webhooks that create and sling beads",
+	Long: `Turn external events (GitHub pushes, CI failures, Sentry alerts) into work.
+
+Endpoints are configured under "webhooks" in settings/config.json and served
+at /hooks/<name>. Each endpoint verifies a shared secret (read from the
+environment variable named by secret_env) and routes payloads through rules:
+the first rule whose "match" conditions hold renders a bead from templates
+and slings it to the rule's rig. A dedup_key template suppresses repeat
+alerts for the same problem for 7 days.
+
+The daemon serves webhooks automatically when endpoints are configured.
+Use 'gt webhook serve' to run the server in the foreground instead.
+
+Example config:
+  "webhooks": {
+    "listen": "127.0.0.1:8790",
+    "endpoints": [{
+      "name": "github",
+      "auth": "github",
+      "secret_env": "GT_GITHUB_WEBHOOK_SECRET",
+      "rules": [{
+        "match": {"header:X-GitHub-Event": "workflow_run", "workflow_run.conclusion": "failure"},
+        "rig": "gastown",
+        "title": "Fix CI: {{get .Payload \"workflow_run.name\"}} on {{get .Payload \"workflow_run.head_branch\"}}",
+        "dedup_key": "{{get .Payload \"workflow_run.head_sha\"}}"
+      }]
+    }]
+  }
+
+Auth modes: github (X-Hub-Signature-256), sentry (Sentry-Hook-Signature),
+token (Authorization: Bearer <secret> or X-Gastown-Token).
+
+Error-tracker intake: set "intake": "sentry" on an endpoint to parse Sentry
+alerts into .Error (title, level, culprit, stack trace, fingerprint). Rules
+then default to a bug-report bead with the stack trace, and repeat events
+for the same fingerprint are grouped onto one bead. max_slings_per_hour caps
+how many reach the on-call rig; beads over the cap are created but unslung:
+  {"name": "sentry", "auth": "sentry", "secret_env": "GT_SENTRY_SECRET",
+   "intake": "sentry",
+   "rules": [{"match": {"data.event.level": "fatal"}, "rig": "oncall", "max_slings_per_hour": 5},
+             {"rig": "oncall", "max_slings_per_hour": 2}]}
+
+Dictation intake: set "intake": "dictation" to accept notes from a phone
+dictation tool, as plain text or JSON with a "text" (or "transcript")
+field. An agent formulates each note into a task title and description
+(gt webhook formulate), the bead is created and slung, and the response
+carries its ID. If the agent fails or takes longer than 45s, the raw text
+is used. A resent note returns the same bead:
+  {"name": "phone", "auth": "token", "secret_env": "GT_DICTATION_TOKEN",
+   "intake": "dictation", "rules": [{"match": {"rig": "gastown"}, "rig": "gastown"}, {"rig": "gastown"}]}`,
+	RunE: requireSubcommand,
+}
+
+var webhookServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the webhook server in the foreground",
+	Long: `Run the inbound webhook server until interrupted.
+
+Don't run this alongside a daemon that already serves webhooks on the same
+address.
+
+Examples:
+  gt webhook serve
+  gt webhook serve --listen 0.0.0.0:8790`,
+	Args: cobra.NoArgs,
+	RunE: runWebhookServe,
+}
+
+var webhookListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List configured webhook endpoints",
+	Args:  cobra.NoArgs,
+	RunE:  runWebhookList,
+}
+
+var webhookTestCmd = &cobra.Command{
+	Use:   "test <endpoint> <payload.json|->",
+	Short: "Show which rule a payload matches and the bead it would create",
+	Long: `Dry-run a payload through an endpoint's rules without creating anything.
+Authentication is skipped. Dictation endpoints still run the formulation
+agent, so the title shown is the one a delivery would get.
+
+Examples:
+  gt webhook test github payload.json --event workflow_run
+  curl -s https://example/payload | gt webhook test sentry - --header Sentry-Hook-Resource=issue`,
+	Args: cobra.ExactArgs(2),
+	RunE: runWebhookTest,
+}
+
+func init() {
+	webhookServeCmd.Flags().StringVar(&webhookListen, "listen", "", "Address to listen on (default: config listen or "+webhook.DefaultListen+")")
+	webhookTestCmd.Flags().StringVar(&webhookEvent, "event", "", "Event name, sent as X-GitHub-Event")
+	webhookTestCmd.Flags().StringArrayVar(&webhookHeaders, "header", nil, "Extra header as Name=Value (repeatable)")
+
+	webhookCmd.AddCommand(webhookServeCmd, webhookListCmd, webhookTestCmd)
+	rootCmd.AddCommand(webhookCmd)
+}
+
+// loadWebhookConfig returns the town's validated webhook config.
+func loadWebhookConfig() (string, *webhook.Config, error) {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return "", nil, fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return "", nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if !settings.Webhooks.IsEnabled() {
+		return townRoot, nil, fmt.Errorf("no webhook endpoints configured (add \"webhooks\" to %s)", config.TownSettingsPath(townRoot))
+	}
+	if err := settings.Webhooks.Validate(); err != nil {
+		return "", nil, err
+	}
+	return townRoot, settings.Webhooks, nil
+}
+
+func runWebhookServe(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadWebhookConfig()
+	if err != nil {
+		return err
+	}
+	if webhookListen != "" {
+		cfg.Listen = webhookListen
+	}
+	for _, ep := range cfg.Endpoints {
+		if _, err := ep.Secret(); err != nil {
+			style.PrintWarning("%v (requests will be rejected)", err)
+		}
+	}
+
+	srv := webhook.NewServer(townRoot, cfg, &webhook.CLIDispatcher{TownRoot: townRoot, RunBD: beads.RunCmdContext}, func(format string, args ...any) {
+		fmt.Printf(format+"\n", args...)
+	})
+	srv.Guard = config.LoadContentGuard(townRoot)
+	srv.Formulator = &webhook.CLIFormulator{TownRoot: townRoot}
+	fmt.Printf("%s Webhook server listening on http://%s\n", style.Success.Render("●"), cfg.ListenAddr())
+	for _, ep := range cfg.Endpoints {
+		fmt.Printf("  POST /hooks/%s  %s\n", ep.Name, style.Dim.Render(fmt.Sprintf("(%s, %d rules)", ep.Auth, len(ep.Rules))))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return srv.ListenAndServe(ctx)
+}
+
+func runWebhookList(cmd *cobra.Command, args []string) error {
+	_, cfg, err := loadWebhookConfig()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Listening on %s\n\n", cfg.ListenAddr())
+	for _, ep := range cfg.Endpoints {
+		secret := style.Success.Render("secret set")
+		if _, err := ep.Secret(); err != nil {
+			secret = style.Warning.Render("$" + ep.SecretEnv + " not set")
+		}
+		intake := ""
+		if ep.Intake != "" {
+			intake = "  intake=" + ep.Intake
+		}
+		fmt.Printf("%s  /hooks/%s  auth=%s%s  %s\n", style.Bold.Render(ep.Name), ep.Name, ep.Auth, intake, secret)
+		for i, r := range ep.Rules {
+			target := r.Rig
+			if target == "" {
+				target = "(town, no sling)"
+			}
+			if r.Rig != "" && r.MaxSlingsPerHour > 0 {
+				target += fmt.Sprintf(" (≤%d/h)", r.MaxSlingsPerHour)
+			}
+			fmt.Printf("    %d. %s → %s\n", i+1, formatWebhookMatch(r.Match), target)
+		}
+	}
+	return nil
+}
+
+func formatWebhookMatch(match map[string]string) string {
+	if len(match) == 0 {
+		return "any payload"
+	}
+	parts := make([]string, 0, len(match))
+	for k, v := range match {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
+
+func runWebhookTest(cmd *cobra.Command, args []string) error {
+	townRoot, cfg, err := loadWebhookConfig()
+	if err != nil {
+		return err
+	}
+	ep, ok := cfg.Find(args[0])
+	if !ok {
+		return fmt.Errorf("no webhook endpoint named %q", args[0])
+	}
+
+	var body []byte
+	if args[1] == "-" {
+		body, err = io.ReadAll(os.Stdin)
+	} else {
+		body, err = os.ReadFile(args[1])
+	}
+	if err != nil {
+		return fmt.Errorf("reading payload: %w", err)
+	}
+
+	header := http.Header{}
+	if webhookEvent != "" {
+		header.Set("X-GitHub-Event", webhookEvent)
+	}
+	for _, h := range webhookHeaders {
+		name, value, ok := strings.Cut(h, "=")
+		if !ok {
+			return fmt.Errorf("invalid --header %q (want Name=Value)", h)
+		}
+		header.Set(name, value)
+	}
+
+	req := ep.Decode(header, body)
+	if ep.Intake == webhook.IntakeDictation && req.Dictation == nil {
+		fmt.Println("No dictated text; the delivery would be acknowledged and ignored.")
+		return nil
+	}
+	if ep.Intake == webhook.IntakeSentry && req.Error == nil {
+		fmt.Printf("Not a %s error event; the delivery would be acknowledged and ignored.\n", ep.Intake)
+		return nil
+	}
+	rule, ok := ep.Match(req)
+	if !ok {
+		fmt.Println("No rule matched; the delivery would be acknowledged and ignored.")
+		return nil
+	}
+	if req.Dictation != nil {
+		title, description, err := formulateDictation(townRoot, rule.Rig, req.Dictation.Text)
+		if err != nil {
+			style.PrintWarning("formulation failed, the raw text would be used: %v", err)
+		} else {
+			req.Dictation.SetTask(title, description)
+		}
+	}
+	rendered, err := rule.Render(req)
+	if err != nil {
+		return err
+	}
+	target := rule.Rig
+	if target == "" {
+		target = "(town, no sling)"
+	}
+	if rule.Rig != "" && rule.MaxSlingsPerHour > 0 {
+		target += fmt.Sprintf(" (at most %d/h)", rule.MaxSlingsPerHour)
+	}
+	fmt.Printf("%s %s → %s\n", style.Bold.Render("Matched:"), formatWebhookMatch(rule.Match), target)
+	fmt.Printf("%s %s\n", style.Bold.Render("Title:"), rendered.Title)
+	if rendered.DedupKey != "" {
+		fmt.Printf("%s %s\n", style.Bold.Render("Dedup key:"), rendered.DedupKey)
+	}
+	fmt.Printf("%s\n%s\n", style.Bold.Render("Description:"), rendered.Description)
+	return nil
+}
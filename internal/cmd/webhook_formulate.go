@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/webhook"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var webhookFormulateRig string
+
+var webhookFormulateCmd = &cobra.Command{
+	Use:   "formulate",
+	Short: "Turn dictated text on stdin into a task title and description",
+	Long: `Ask a read-only agent to turn a dictated note into a task.
+
+Prints {"title": ..., "description": ...} as JSON. Dictation intake
+endpoints run this for every delivery; run it by hand to see what a note
+would become. The agent runs in the rig's checkout with --rig, or the town
+root otherwise.
+
+Examples:
+  echo "the sync job should retry when it gets a five oh three" | gt webhook formulate --rig gastown`,
+	Args: cobra.NoArgs,
+	RunE: runWebhookFormulate,
+}
+
+// webhookFormulateAskFn is a seam for tests. Production asks the rig's agent
+// in print mode.
+var webhookFormulateAskFn = func(townRoot, rigName, prompt string) (string, error) {
+	rigPath, workDir := "", townRoot
+	if rigName != "" {
+		_, r, err := getRig(rigName)
+		if err != nil {
+			return "", err
+		}
+		rigPath, workDir = r.Path, askWorkDir(r.Path)
+	}
+	rc, agentName, err := config.ResolveAgentConfigWithOverride(townRoot, rigPath, "")
+	if err != nil {
+		return "", fmt.Errorf("resolving agent: %w", err)
+	}
+	inv, err := buildAskInvocation(rc, agentName, prompt, "")
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhook.FormulateTimeout)
+	defer cancel()
+	c := exec.CommandContext(ctx, inv.Argv[0], inv.Argv[1:]...) //nolint:gosec // G204: argv from trusted agent config
+	c.Dir = workDir
+	c.Env = clearClaudeCodeEnv(os.Environ())
+	var stdout bytes.Buffer
+	c.Stdout = &stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("formulation agent did not answer within %s", webhook.FormulateTimeout)
+		}
+		return "", fmt.Errorf("running formulation agent: %w", err)
+	}
+	if inv.Structured {
+		answer, _, err := parseAskStructuredOutput(stdout.Bytes())
+		return answer, err
+	}
+	return stdout.String(), nil
+}
+
+func init() {
+	webhookFormulateCmd.Flags().StringVar(&webhookFormulateRig, "rig", "", "Rig the task is for (the agent reads its code)")
+	webhookCmd.AddCommand(webhookFormulateCmd)
+}
+
+func runWebhookFormulate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("reading stdin: %w", err)
+	}
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		return fmt.Errorf("no text on stdin")
+	}
+	title, description, err := formulateDictation(townRoot, webhookFormulateRig, text)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(os.Stdout).Encode(map[string]string{"title": title, "description": description})
+}
+
+// formulateDictation asks the formulation agent for a task.
+func formulateDictation(townRoot, rigName, text string) (string, string, error) {
+	answer, err := webhookFormulateAskFn(townRoot, rigName, buildFormulatePrompt(rigName, text))
+	if err != nil {
+		return "", "", err
+	}
+	return webhook.ParseFormulation(answer)
+}
+
+// buildFormulatePrompt asks a read-only agent to turn a note into a task.
+func buildFormulatePrompt(rigName, text string) string {
+	var b strings.Builder
+	if rigName != "" {
+		fmt.Fprintf(&b, "You are turning a dictated note into a work item for the %q rig in a Gas Town workspace.\n", rigName)
+		b.WriteString("Read the code in your working directory if it helps you be specific.\n")
+	} else {
+		b.WriteString("You are turning a dictated note into a work item in a Gas Town workspace.\n")
+	}
+	b.WriteString("This is read-only: do not modify files, create beads, commit, or run gt/bd commands that write state.\n\n")
+	b.WriteString("The note was spoken into a phone: fix transcription errors, drop filler and asides, and keep every\n")
+	b.WriteString("concrete detail. Do not invent requirements the note does not state.\n")
+	b.WriteString("Reply with ONLY a JSON object, no prose:\n")
+	b.WriteString(`{"title": "imperative summary, under 80 characters", "description": "what to do, the context given, and how to verify it"}`)
+	b.WriteString("\n\nDictated note:\n")
+	b.WriteString(text)
+	b.WriteString("\n")
+	return b.String()
+}
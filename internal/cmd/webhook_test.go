@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/webhook"
+)
+
+func setupWebhookTown(t *testing.T) string {
+	t.Helper()
+	townRoot := setupTestTownForTheme(t)
+	t.Chdir(townRoot)
+
+	settingsPath := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.Webhooks = &webhook.Config{Endpoints: []webhook.Endpoint{{
+		Name:      "sentry",
+		Auth:      webhook.AuthSentry,
+		SecretEnv: "TEST_SENTRY_SECRET",
+		Rules: []webhook.Rule{{
+			Match: map[string]string{"header:Sentry-Hook-Resource": "issue", "action": "created"},
+			Rig:   "gastown",
+			Title: `Sentry: {{get .Payload "data.issue.title"}}`,
+		}},
+	}}}
+	if err := config.SaveTownSettings(settingsPath, settings); err != nil {
+		t.Fatal(err)
+	}
+	return townRoot
+}
+
+func TestWebhookTestCommand(t *testing.T) {
+	townRoot := setupWebhookTown(t)
+	t.Cleanup(func() { webhookEvent, webhookHeaders = "", nil })
+
+	payload := filepath.Join(townRoot, "payload.json")
+	if err := os.WriteFile(payload, []byte(`{"action":"created","data":{"issue":{"title":"NilPointer in auth"}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	webhookHeaders = []string{"Sentry-Hook-Resource=issue"}
+	out := captureStdout(t, func() {
+		if err := runWebhookTest(webhookTestCmd, []string{"sentry", payload}); err != nil {
+			t.Fatalf("runWebhookTest() error = %v", err)
+		}
+	})
+	if !strings.Contains(out, "Sentry: NilPointer in auth") || !strings.Contains(out, "gastown") {
+		t.Errorf("output = %q", out)
+	}
+
+	webhookHeaders = []string{"Sentry-Hook-Resource=event_alert"}
+	out = captureStdout(t, func() {
+		if err := runWebhookTest(webhookTestCmd, []string{"sentry", payload}); err != nil {
+			t.Fatalf("runWebhookTest() error = %v", err)
+		}
+	})
+	if !strings.Contains(out, "No rule matched") {
+		t.Errorf("output = %q, want no match", out)
+	}
+
+	if err := runWebhookTest(webhookTestCmd, []string{"missing", payload}); err == nil {
+		t.Error("expected error for unknown endpoint")
+	}
+}
+
+func TestWebhookListShowsSecretState(t *testing.T) {
+	setupWebhookTown(t)
+	t.Setenv("TEST_SENTRY_SECRET", "")
+
+	out := captureStdout(t, func() {
+		if err := runWebhookList(webhookListCmd, nil); err != nil {
+			t.Fatalf("runWebhookList() error = %v", err)
+		}
+	})
+	if !strings.Contains(out, "/hooks/sentry") || !strings.Contains(out, "$TEST_SENTRY_SECRET not set") {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestWebhookNoConfig(t *testing.T) {
+	townRoot := setupTestTownForTheme(t)
+	t.Chdir(townRoot)
+	if err := runWebhookList(webhookListCmd, nil); err == nil || !strings.Contains(err.Error(), "no webhook endpoints") {
+		t.Errorf("runWebhookList() error = %v, want no-config error", err)
+	}
+}
+
+func TestWebhookTestCommandSentryIntake(t *testing.T) {
+	townRoot := setupWebhookTown(t)
+	t.Cleanup(func() { webhookEvent, webhookHeaders = "", nil })
+
+	settingsPath := config.TownSettingsPath(townRoot)
+	settings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.Webhooks.Endpoints[0].Intake = webhook.IntakeSentry
+	settings.Webhooks.Endpoints[0].Rules = []webhook.Rule{{Rig: "oncall", MaxSlingsPerHour: 3}}
+	if err := config.SaveTownSettings(settingsPath, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	payload := filepath.Join(townRoot, "alert.json")
+	body := `{"data":{"event":{"issue_id":"77","title":"KeyError: 'user'","exception":{"values":[{"type":"KeyError","value":"'user'","stacktrace":{"frames":[{"filename":"views.py","function":"profile","lineno":8}]}}]}}}}`
+	if err := os.WriteFile(payload, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := captureStdout(t, func() {
+		if err := runWebhookTest(webhookTestCmd, []string{"sentry", payload}); err != nil {
+			t.Fatalf("runWebhookTest() error = %v", err)
+		}
+	})
+	for _, want := range []string{"[sentry] KeyError: 'user'", "sentry-issue:77", "at profile (views.py:8)", "oncall (at most 3/h)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	if err := os.WriteFile(payload, []byte(`{"action":"created","data":{"installation":{}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out = captureStdout(t, func() {
+		if err := runWebhookTest(webhookTestCmd, []string{"sentry", payload}); err != nil {
+			t.Fatalf("runWebhookTest() error = %v", err)
+		}
+	})
+	if !strings.Contains(out, "Not a sentry error event") {
+		t.Errorf("output = %q, want ignored", out)
+	}
+}
+
+func TestFormulateDictation(t *testing.T) {
+	orig := webhookFormulateAskFn
+	t.Cleanup(func() { webhookFormulateAskFn = orig })
+
+	var gotRig, gotPrompt string
+	webhookFormulateAskFn = func(_, rigName, prompt string) (string, error) {
+		gotRig, gotPrompt = rigName, prompt
+		return "```json\n{\"title\": \"Retry sync on 503\", \"description\": \"Back off and retry.\"}\n```", nil
+	}
+	title, desc, err := formulateDictation("/town", "gastown", "uh the sync job should retry on five oh three")
+	if err != nil || title != "Retry sync on 503" || desc != "Back off and retry." {
+		t.Errorf("got %q %q %v", title, desc, err)
+	}
+	if gotRig != "gastown" || !strings.Contains(gotPrompt, `"gastown" rig`) || !strings.Contains(gotPrompt, "five oh three") {
+		t.Errorf("rig %q, prompt %q", gotRig, gotPrompt)
+	}
+
+	webhookFormulateAskFn = func(string, string, string) (string, error) { return "I could not parse that.", nil }
+	if _, _, err := formulateDictation("/town", "", "x"); err == nil {
+		t.Error("an answer without a JSON task should fail")
+	}
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var whereamiJSON bool
+
+var whereamiCmd = &cobra.Command{
+	Use:     "whereami",
+	GroupID: GroupDiag,
+	Short:   "Explain which town, rig and role this directory resolves to",
+	Long: `Show how gt resolves the current directory to a town, rig and role.
+
+Town discovery walks up from the current directory looking for
+mayor/town.json (or a bare mayor/ directory). Some layouts make that
+ambiguous, and whereami shows each decision:
+
+  - Town markers inside polecat or crew worktrees (a checked-out repo
+    that carries its own mayor/town.json) are skipped.
+  - Nested towns resolve to the innermost town; enclosing towns are listed.
+  - If the literal path finds no town, its symlink-resolved path is tried.
+  - GT_TOWN_ROOT pins the town when no town is found (bind mounts) or the
+    directory is inside it. A GT_TOWN_ROOT for another town is ignored.
+
+Examples:
+  gt whereami
+  gt whereami --json`,
+	Args: cobra.NoArgs,
+	RunE: runWhereami,
+}
+
+func init() {
+	whereamiCmd.Flags().BoolVar(&whereamiJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(whereamiCmd)
+}
+
+// whereamiReport is the output of gt whereami.
+type whereamiReport struct {
+	*workspace.Resolution
+	Rel  string `json:"rel,omitempty"`
+	Rig  string `json:"rig,omitempty"`
+	Role string `json:"role,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+func runWhereami(cmd *cobra.Command, args []string) error {
+	res, err := workspace.ResolveFromCwd()
+	if err != nil {
+		return err
+	}
+	report := buildWhereamiReport(res)
+
+	if whereamiJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	printWhereami(report)
+	if res.Root == "" {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func buildWhereamiReport(res *workspace.Resolution) whereamiReport {
+	report := whereamiReport{Resolution: res}
+	if res.Root == "" {
+		return report
+	}
+	if rel, err := workspace.RelPath(res.Root, res.Start); err == nil {
+		report.Rel = rel
+	}
+	report.Rig = detectRigFromPath(res.Root, res.Start)
+	if info := detectRole(res.Start, res.Root); info.Role != RoleUnknown {
+		report.Role = string(info.Role)
+		report.Name = info.Polecat
+	}
+	return report
+}
+
+func printWhereami(r whereamiReport) {
+	if r.Root == "" {
+		fmt.Printf("%s Not in a Gas Town workspace\n", style.Warning.Render("⚠"))
+		fmt.Printf("  Searched up from %s\n", r.Start)
+		if r.Physical != "" {
+			fmt.Printf("  and from its physical path %s\n", r.Physical)
+		}
+		for _, note := range r.Notes {
+			fmt.Printf("  %s\n", style.Dim.Render(note))
+		}
+		return
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render("Town:"), r.Root)
+	switch r.Source {
+	case workspace.SourcePhysical:
+		fmt.Printf("  found %s above the physical path %s\n", r.Marker, r.Physical)
+	case workspace.SourceEnv:
+		fmt.Printf("  pinned by %s\n", workspace.EnvTownRoot)
+	default:
+		fmt.Printf("  found %s walking up from %s\n", r.Marker, r.Start)
+		if r.Physical != "" {
+			fmt.Printf("  %s\n", style.Dim.Render("physical path: "+r.Physical))
+		}
+	}
+	for _, dir := range r.Skipped {
+		fmt.Printf("  %s\n", style.Dim.Render("skipped "+dir+" (town marker inside a worktree)"))
+	}
+	for _, dir := range r.Outer {
+		fmt.Printf("  %s\n", style.Dim.Render("nested in town "+dir))
+	}
+	for _, note := range r.Notes {
+		fmt.Printf("  %s\n", style.Dim.Render(note))
+	}
+
+	if r.Rel != "" && r.Rel != "." {
+		fmt.Printf("%s %s\n", style.Bold.Render("Path:"), r.Rel)
+	}
+	if r.Rig != "" {
+		fmt.Printf("%s %s\n", style.Bold.Render("Rig:"), r.Rig)
+	}
+	if r.Role != "" {
+		role := r.Role
+		if r.Name != "" {
+			role += " (" + r.Name + ")"
+		}
+		fmt.Printf("%s %s\n", style.Bold.Render("Role:"), role)
+	}
+}
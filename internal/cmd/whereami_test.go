@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+func TestWhereami_PolecatInNestedTown(t *testing.T) {
+	base, _ := filepath.EvalSymlinks(t.TempDir())
+	inner := filepath.Join(base, "outer", "inner")
+	for _, town := range []string{filepath.Join(base, "outer"), inner} {
+		if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(town, workspace.PrimaryMarker), []byte(`{"type":"town"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	polecat := filepath.Join(inner, "myrig", "polecats", "Toast")
+	if err := os.MkdirAll(polecat, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(inner, "myrig", "config.json"), []byte(`{"type":"rig"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Chdir(polecat)
+	t.Setenv(workspace.EnvTownRoot, "")
+	res, err := workspace.ResolveFromCwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := buildWhereamiReport(res)
+	if report.Root != inner || report.Rig != "myrig" || report.Role != string(RolePolecat) || report.Name != "Toast" {
+		t.Errorf("report = %+v", report)
+	}
+
+	out := captureStdout(t, func() { printWhereami(report) })
+	for _, want := range []string{inner, "nested in town " + filepath.Join(base, "outer"), "myrig/polecats/Toast", "polecat (Toast)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
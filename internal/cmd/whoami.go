@@ -3,9 +3,11 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rbac"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -72,6 +74,14 @@ func runWhoami(cmd *cobra.Command, args []string) error {
 					}
 					fmt.Printf("  %s %s\n", style.Dim.Render("(detected via"), style.Dim.Render(overseerConfig.Source+")"))
 				}
+				if p, ok := currentAccessPrincipal(townRoot); ok {
+					fmt.Printf("\n%s\n", style.Bold.Render("Access:"))
+					fmt.Printf("  Operator: %s\n", p.Name)
+					fmt.Printf("  Role:     %s\n", p.Role)
+					if p.Role == rbac.RoleRigAdmin {
+						fmt.Printf("  Rigs:     %s\n", strings.Join(p.Rigs, ", "))
+					}
+				}
 			}
 		}
 	}
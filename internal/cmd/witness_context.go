@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/contextbudget"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	witnessContextAct  bool
+	witnessContextJSON bool
+)
+
+var witnessContextCmd = &cobra.Command{
+	Use:   "context <rig>[/<polecat>]",
+	Short: "Check polecat context usage and compact or rotate full sessions",
+	Long: `Estimate how full each polecat session's context window is and, with
+--act, compact or rotate sessions before the model degrades.
+
+Usage is read from the session's Claude Code transcript (the token count of
+the last turn, or the transcript size when it has none) and from the
+runtime's own status line in the pane ("Context left until auto-compact:
+12%"). The higher estimate is used.
+
+  compact  usage >= compact_at (default 70%): the runtime's compact command
+           (/compact for Claude) is typed into the session
+  rotate   usage >= rotate_at (default 85%), or compact_at for runtimes
+           without a compact command: the polecat is asked to commit and
+           hand off to a fresh session with gt handoff --cycle
+
+An action is not repeated for the same session within 15 minutes. Configure
+thresholds per runtime in the agent's runtime config:
+
+  "context_budget": {"max_tokens": 200000, "compact_at": 0.7, "rotate_at": 0.85}
+
+Examples:
+  gt witness context greenplace              # All polecats in the rig
+  gt witness context greenplace/Toast        # One polecat
+  gt witness context greenplace --act        # Compact or rotate as needed`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessContext,
+}
+
+var (
+	// witnessContextCaptureFn is a seam for tests. Production captures the last
+	// 30 pane lines over tmux.
+	witnessContextCaptureFn = func(sessionName string) ([]string, error) {
+		return tmux.NewTmux().CapturePaneLines(sessionName, 30)
+	}
+
+	// witnessContextNudgeFn is a seam for tests. Production nudges the session
+	// over tmux.
+	witnessContextNudgeFn = func(sessionName, msg string) error {
+		return tmux.NewTmux().NudgeSession(sessionName, msg)
+	}
+)
+
+func init() {
+	witnessContextCmd.Flags().BoolVar(&witnessContextAct, "act", false, "Compact or rotate sessions over budget")
+	witnessContextCmd.Flags().BoolVar(&witnessContextJSON, "json", false, "Output as JSON")
+
+	witnessCmd.AddCommand(witnessContextCmd)
+}
+
+// contextCheck is the context usage of one polecat session and the action
+// its budget calls for.
+type contextCheck struct {
+	Polecat string              `json:"polecat"`
+	Session string              `json:"session"`
+	Usage   contextbudget.Usage `json:"usage"`
+	Action  string              `json:"action"`
+	Acted   bool                `json:"acted,omitempty"`
+	Skipped string              `json:"skipped,omitempty"` // Why --act didn't act
+	Error   string              `json:"error,omitempty"`
+}
+
+func runWitnessContext(cmd *cobra.Command, args []string) error {
+	rigName, polecatName, _ := strings.Cut(args[0], "/")
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	rc := config.ResolveRoleAgentConfig("polecat", townRoot, r.Path)
+	if err := rc.ContextBudget.Validate(); err != nil {
+		return fmt.Errorf("invalid context_budget for %s: %w", rc.Provider, err)
+	}
+	budget := rc.ContextBudget.Resolve(rc.Provider)
+	if budget.Disabled {
+		fmt.Printf("%s Context budget checks are disabled for %s\n", style.Dim.Render("○"), rc.Provider)
+		return nil
+	}
+
+	polecats := []string{polecatName}
+	if polecatName == "" {
+		if polecats, err = witnessContextPolecats(rigName); err != nil {
+			return err
+		}
+	}
+
+	actions, err := contextbudget.LoadActions(townRoot)
+	if err != nil {
+		style.PrintWarning("could not read context budget state: %v", err)
+		actions = make(map[string]contextbudget.LastAction)
+	}
+	now := time.Now()
+	checks := make([]contextCheck, 0, len(polecats))
+	for _, name := range polecats {
+		c := checkPolecatContext(r.Path, rigName, name, budget)
+		if witnessContextAct && c.Error == "" {
+			actOnContextCheck(&c, budget, actions, now)
+		}
+		checks = append(checks, c)
+	}
+	if witnessContextAct {
+		if err := contextbudget.SaveActions(townRoot, actions); err != nil {
+			style.PrintWarning("could not save context budget state: %v", err)
+		}
+	}
+
+	if witnessContextJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(checks)
+	}
+	printContextChecks(rigName, checks, budget)
+	return nil
+}
+
+// witnessContextPolecats returns the rig's running polecats.
+func witnessContextPolecats(rigName string) ([]string, error) {
+	mgr, _, err := getSessionManager(rigName)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := mgr.List()
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions for %s: %w", rigName, err)
+	}
+	var names []string
+	for _, info := range infos {
+		if info.Polecat == "witness" || info.Polecat == "refinery" || strings.HasPrefix(info.Polecat, "crew-") {
+			continue
+		}
+		names = append(names, info.Polecat)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// checkPolecatContext measures a polecat session's context usage from its
+// newest transcript and its pane.
+func checkPolecatContext(rigPath, rigName, polecatName string, budget contextbudget.Config) contextCheck {
+	c := contextCheck{
+		Polecat: polecatName,
+		Session: session.PolecatSessionName(session.PrefixFor(rigName), polecatName),
+	}
+	pane, err := witnessContextCaptureFn(c.Session)
+	if err != nil {
+		c.Error = fmt.Sprintf("capturing %s: %v", c.Session, err)
+		c.Action = contextbudget.ActionNone
+		return c
+	}
+	var transcriptPath string
+	transcripts, err := agentlog.ClaudeCodeTranscripts(
+		filepath.Join(rigPath, "polecats", polecatName, rigName),
+		filepath.Join(rigPath, "polecats", polecatName),
+	)
+	if err == nil && len(transcripts) > 0 {
+		transcriptPath = transcripts[0].Path
+	}
+	c.Usage = contextbudget.Measure(transcriptPath, pane, budget.MaxTokens)
+	c.Action = budget.Decide(c.Usage)
+	return c
+}
+
+// actOnContextCheck compacts or rotates the session unless the same action
+// was taken on it recently, and records what was done in actions.
+func actOnContextCheck(c *contextCheck, budget contextbudget.Config, actions map[string]contextbudget.LastAction, now time.Time) {
+	if c.Action == contextbudget.ActionNone {
+		return
+	}
+	if !contextbudget.ShouldAct(actions[c.Session], c.Action, now) {
+		c.Skipped = "acted recently"
+		return
+	}
+	msg := budget.CompactCommand
+	if c.Action == contextbudget.ActionRotate {
+		msg = fmt.Sprintf("Witness: your context window is %.0f%% full. Commit your work in progress, then run `gt handoff --cycle --reason context` to continue in a fresh session.", c.Usage.Fraction*100)
+	}
+	if err := witnessContextNudgeFn(c.Session, msg); err != nil {
+		c.Error = fmt.Sprintf("%s failed: %v", c.Action, err)
+		return
+	}
+	c.Acted = true
+	actions[c.Session] = contextbudget.LastAction{Action: c.Action, Fraction: c.Usage.Fraction, At: now.UTC()}
+}
+
+func printContextChecks(rigName string, checks []contextCheck, budget contextbudget.Config) {
+	fmt.Printf("%s Context usage in %s %s\n", style.Bold.Render("🧠"), rigName,
+		style.Dim.Render(fmt.Sprintf("(compact at %.0f%%, rotate at %.0f%% of %dk)", budget.CompactAt*100, budget.RotateAt*100, budget.MaxTokens/1000)))
+	if len(checks) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No running polecats."))
+		return
+	}
+	for _, c := range checks {
+		if c.Error != "" && !c.Acted && c.Usage.Source == "" {
+			fmt.Printf("  %-16s %s\n", c.Polecat, style.Error.Render(c.Error))
+			continue
+		}
+		pct := fmt.Sprintf("%3.0f%%", c.Usage.Fraction*100)
+		switch c.Action {
+		case contextbudget.ActionRotate:
+			pct = style.Error.Render(pct)
+		case contextbudget.ActionCompact:
+			pct = style.Warning.Render(pct)
+		}
+		line := fmt.Sprintf("  %-16s %s  %s", c.Polecat, pct, style.Dim.Render(c.Usage.Source))
+		switch {
+		case c.Action == contextbudget.ActionNone:
+		case c.Acted:
+			line += "  " + c.Action + " " + style.Success.Render("(done)")
+		case c.Error != "":
+			line += "  " + style.Error.Render(c.Error)
+		case c.Skipped != "":
+			line += "  " + c.Action + " " + style.Dim.Render("("+c.Skipped+")")
+		default:
+			line += "  " + c.Action + " " + style.Dim.Render("(use --act to carry out)")
+		}
+		fmt.Println(line)
+	}
+}
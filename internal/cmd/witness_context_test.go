@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/contextbudget"
+)
+
+func TestActOnContextCheck(t *testing.T) {
+	var nudged []string
+	orig := witnessContextNudgeFn
+	witnessContextNudgeFn = func(sessionName, msg string) error {
+		nudged = append(nudged, sessionName+": "+msg)
+		return nil
+	}
+	t.Cleanup(func() { witnessContextNudgeFn = orig })
+
+	budget := (&contextbudget.Config{}).Resolve("claude")
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	actions := map[string]contextbudget.LastAction{}
+
+	compact := contextCheck{Session: "gt-toast", Usage: contextbudget.Usage{Fraction: 0.75}, Action: contextbudget.ActionCompact}
+	actOnContextCheck(&compact, budget, actions, now)
+	if !compact.Acted || len(nudged) != 1 || nudged[0] != "gt-toast: /compact" {
+		t.Fatalf("compact: acted=%v nudged=%q", compact.Acted, nudged)
+	}
+
+	again := contextCheck{Session: "gt-toast", Usage: contextbudget.Usage{Fraction: 0.76}, Action: contextbudget.ActionCompact}
+	actOnContextCheck(&again, budget, actions, now.Add(time.Minute))
+	if again.Acted || again.Skipped == "" || len(nudged) != 1 {
+		t.Errorf("repeat compact within cooldown should be skipped: %+v", again)
+	}
+
+	rotate := contextCheck{Session: "gt-toast", Usage: contextbudget.Usage{Fraction: 0.9}, Action: contextbudget.ActionRotate}
+	actOnContextCheck(&rotate, budget, actions, now.Add(2*time.Minute))
+	if !rotate.Acted || len(nudged) != 2 || !strings.Contains(nudged[1], "gt handoff --cycle") {
+		t.Errorf("rotate: acted=%v nudged=%q", rotate.Acted, nudged)
+	}
+	if actions["gt-toast"].Action != contextbudget.ActionRotate {
+		t.Errorf("last action = %+v, want rotate", actions["gt-toast"])
+	}
+}
+
+func TestActOnContextCheckNudgeFails(t *testing.T) {
+	orig := witnessContextNudgeFn
+	witnessContextNudgeFn = func(string, string) error { return errors.New("no session") }
+	t.Cleanup(func() { witnessContextNudgeFn = orig })
+
+	actions := map[string]contextbudget.LastAction{}
+	c := contextCheck{Session: "gt-nux", Usage: contextbudget.Usage{Fraction: 0.95}, Action: contextbudget.ActionRotate}
+	actOnContextCheck(&c, (&contextbudget.Config{}).Resolve("claude"), actions, time.Now())
+	if c.Acted || c.Error == "" {
+		t.Errorf("failed nudge should be reported: %+v", c)
+	}
+	if _, ok := actions["gt-nux"]; ok {
+		t.Error("failed action should not start a cooldown")
+	}
+}
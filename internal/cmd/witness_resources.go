@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/reslimit"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	witnessResourcesEscalate bool
+	witnessResourcesJSON     bool
+)
+
+var witnessResourcesCmd = &cobra.Command{
+	Use:   "resources <rig>",
+	Short: "Check agent sessions against their CPU and memory limits",
+	Long: `Report the resource usage of the rig's agent sessions and alert on
+sessions that hit their limits.
+
+Limits are set per rig in settings/config.json and apply to each session
+and everything it spawns (builds, tests, language servers):
+
+  "resource_limits": {"memory": "8G", "cpus": 2, "tasks_max": 1024}
+
+On Linux each session then runs in its own systemd scope. This check reads
+the scope's cgroup counters and alerts when:
+
+  oom     the kernel OOM-killed a process in the session
+  memory  the session uses warn_percent (default 90%) of its memory limit
+
+Each OOM kill and each climb past the warning level is alerted once. New
+alerts are posted to the feed; with --escalate they are also escalated.
+Sessions started before limits were configured (or on hosts without
+systemd-run) are listed as unconfined.
+
+Examples:
+  gt witness resources greenplace
+  gt witness resources greenplace --escalate
+  gt witness resources greenplace --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessResources,
+}
+
+var (
+	// witnessResourcesSessionsFn is a seam for tests. Production uses
+	// rigAgentSessions.
+	witnessResourcesSessionsFn = rigAgentSessions
+
+	// witnessResourcesUsageFn is a seam for tests. Production uses
+	// reslimit.ReadUsage.
+	witnessResourcesUsageFn = reslimit.ReadUsage
+
+	// witnessResourcesEscalateFn is a seam for tests. Production files an
+	// escalation through gt escalate.
+	witnessResourcesEscalateFn = func(townRoot, rigName string, a reslimit.Alert) error {
+		severity := "medium"
+		if a.Kind == reslimit.AlertOOM {
+			severity = "high"
+		}
+		return runGtInTown(townRoot, "escalate", "-s", severity,
+			"--source", "resources:"+rigName, "--reason", a.Detail,
+			fmt.Sprintf("Resource limit: %s %s", a.Agent, a.Kind))
+	}
+)
+
+func init() {
+	witnessResourcesCmd.Flags().BoolVar(&witnessResourcesEscalate, "escalate", false, "Escalate new alerts")
+	witnessResourcesCmd.Flags().BoolVar(&witnessResourcesJSON, "json", false, "Output as JSON")
+
+	witnessCmd.AddCommand(witnessResourcesCmd)
+}
+
+// agentSession is a rig agent's tmux session and its pane process.
+type agentSession struct {
+	Session string
+	Agent   string // rig/name, or rig/role for the witness and refinery
+	PID     string
+}
+
+// resourceCheck is one session's usage and the alerts it raised.
+type resourceCheck struct {
+	Session    string           `json:"session"`
+	Agent      string           `json:"agent"`
+	Usage      *reslimit.Usage  `json:"usage,omitempty"`
+	Unconfined bool             `json:"unconfined,omitempty"` // Not in a limited scope
+	Alerts     []reslimit.Alert `json:"alerts,omitempty"`
+	Error      string           `json:"error,omitempty"`
+}
+
+func runWitnessResources(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(r.Path))
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return fmt.Errorf("loading rig settings: %w", err)
+	}
+	var limits *reslimit.Config
+	if settings != nil {
+		limits = settings.ResourceLimits
+	}
+	if !limits.IsEnabled() {
+		fmt.Printf("%s No resource limits configured for %s (set resource_limits in %s)\n",
+			style.Dim.Render("○"), rigName, config.RigSettingsPath(r.Path))
+		return nil
+	}
+
+	sessions, err := witnessResourcesSessionsFn(rigName)
+	if err != nil {
+		return err
+	}
+	state, err := reslimit.LoadState(r.Path)
+	if err != nil {
+		style.PrintWarning("could not read resource alert state: %v", err)
+		state = &reslimit.State{Cgroups: make(map[string]*reslimit.CgroupState)}
+	}
+	checks := checkRigResources(state, sessions, limits.GetWarnPercent(), time.Now())
+	if err := state.Save(r.Path, time.Now()); err != nil {
+		style.PrintWarning("could not save resource alert state: %v", err)
+	}
+
+	for _, c := range checks {
+		for _, a := range c.Alerts {
+			_ = events.LogFeed(events.TypeResourceAlert, "witness",
+				events.ResourceAlertPayload(rigName, a.Agent, a.Kind, a.Detail))
+			if witnessResourcesEscalate {
+				if err := witnessResourcesEscalateFn(townRoot, rigName, a); err != nil {
+					style.PrintWarning("could not escalate: %v", err)
+				}
+			}
+		}
+	}
+
+	if witnessResourcesJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(checks)
+	}
+	printResourceChecks(rigName, checks)
+	return nil
+}
+
+// checkRigResources reads each session's cgroup and returns its usage with
+// any new alerts. Only sessions in their own scope are checked: an
+// unconfined session shares its cgroup with the tmux server.
+func checkRigResources(state *reslimit.State, sessions []agentSession, warnPercent int, now time.Time) []resourceCheck {
+	checks := make([]resourceCheck, 0, len(sessions))
+	for _, s := range sessions {
+		c := resourceCheck{Session: s.Session, Agent: s.Agent}
+		u, err := witnessResourcesUsageFn(s.PID)
+		switch {
+		case err != nil:
+			c.Error = err.Error()
+		case !reslimit.IsSessionScope(u.Cgroup):
+			c.Unconfined = true
+		default:
+			c.Usage = u
+			c.Alerts = state.Check(s.Session, s.Agent, u, warnPercent, now)
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// rigAgentSessions lists the running tmux sessions of a rig's agents.
+func rigAgentSessions(rigName string) ([]agentSession, error) {
+	t := tmux.NewTmux()
+	names, err := t.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	var sessions []agentSession
+	for _, name := range names {
+		id, err := session.ParseSessionName(name)
+		if err != nil || id.Rig != rigName {
+			continue
+		}
+		pid, err := t.GetPanePID(name)
+		if err != nil {
+			continue
+		}
+		agent := id.Name
+		if agent == "" {
+			agent = string(id.Role)
+		}
+		sessions = append(sessions, agentSession{Session: name, Agent: rigName + "/" + agent, PID: pid})
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Agent < sessions[j].Agent })
+	return sessions, nil
+}
+
+func printResourceChecks(rigName string, checks []resourceCheck) {
+	fmt.Printf("%s Resources in %s\n", style.Bold.Render("📊"), rigName)
+	if len(checks) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No agent sessions running."))
+		return
+	}
+	for _, c := range checks {
+		agent := strings.TrimPrefix(c.Agent, rigName+"/")
+		switch {
+		case c.Error != "":
+			fmt.Printf("  %-16s %s\n", agent, style.Dim.Render("? "+c.Error))
+		case c.Unconfined:
+			fmt.Printf("  %-16s %s\n", agent, style.Dim.Render("unconfined (started without limits)"))
+		default:
+			u := c.Usage
+			mem := fmt.Sprintf("%s / %s", reslimit.FormatBytes(u.MemoryCurrent), reslimit.FormatBytes(u.MemoryMax))
+			line := fmt.Sprintf("  %-16s %-18s %3d%%", agent, mem, u.MemoryPercent())
+			if u.OOMKills > 0 {
+				line += " " + style.Error.Render(fmt.Sprintf("%d OOM kill(s)", u.OOMKills))
+			}
+			if u.Throttled > 0 {
+				line += " " + style.Dim.Render("throttled "+u.Throttled.Round(time.Second).String())
+			}
+			fmt.Println(line)
+		}
+		for _, a := range c.Alerts {
+			fmt.Printf("  %s %s\n", style.Warning.Render("⚠ "+a.Kind+":"), a.Detail)
+		}
+	}
+}
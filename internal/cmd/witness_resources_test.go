@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/reslimit"
+)
+
+func TestCheckRigResources(t *testing.T) {
+	usage := map[string]*reslimit.Usage{
+		"100": {Cgroup: "/app.slice/gt-gastown-Toast-1.scope", MemoryCurrent: 950, MemoryMax: 1000, OOMKills: 2},
+		"200": {Cgroup: "/app.slice/tmux-spawn-1.scope", MemoryCurrent: 5000},
+	}
+	orig := witnessResourcesUsageFn
+	witnessResourcesUsageFn = func(pid string) (*reslimit.Usage, error) {
+		if u, ok := usage[pid]; ok {
+			return u, nil
+		}
+		return nil, errors.New("no such process")
+	}
+	t.Cleanup(func() { witnessResourcesUsageFn = orig })
+
+	sessions := []agentSession{
+		{Session: "gt-Toast", Agent: "gastown/Toast", PID: "100"},
+		{Session: "gt-witness", Agent: "gastown/witness", PID: "200"},
+		{Session: "gt-Nux", Agent: "gastown/Nux", PID: "300"},
+	}
+	state := &reslimit.State{Cgroups: make(map[string]*reslimit.CgroupState)}
+	now := time.Now()
+	checks := checkRigResources(state, sessions, 90, now)
+	if len(checks) != 3 {
+		t.Fatalf("got %d checks, want 3", len(checks))
+	}
+	if c := checks[0]; c.Usage == nil || len(c.Alerts) != 2 || c.Alerts[0].Kind != reslimit.AlertOOM || c.Alerts[0].Agent != "gastown/Toast" {
+		t.Errorf("limited session = %+v", c)
+	}
+	if c := checks[1]; !c.Unconfined || c.Usage != nil || len(c.Alerts) != 0 {
+		t.Errorf("unconfined session = %+v", c)
+	}
+	if c := checks[2]; c.Error == "" {
+		t.Errorf("missing process = %+v", c)
+	}
+
+	if checks := checkRigResources(state, sessions, 90, now); len(checks[0].Alerts) != 0 {
+		t.Errorf("second check alerted again: %+v", checks[0].Alerts)
+	}
+}
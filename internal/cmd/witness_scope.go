@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/fsscope"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	witnessScopeSince    time.Duration
+	witnessScopeEscalate bool
+	witnessScopeJSON     bool
+)
+
+var witnessScopeCmd = &cobra.Command{
+	Use:   "scope <rig>[/<polecat>]",
+	Short: "Audit polecats for writes outside their worktrees",
+	Long: `Find writes polecats made outside their own directory.
+
+gt tap guard scope blocks out-of-scope writes before they happen, but only
+for the paths it can see in a tool call. This audit looks afterwards:
+
+  transcript  Each polecat's Claude Code transcripts are read and every
+              Write, Edit and Bash call is checked the way the guard
+              would, against the working directory it ran in
+  diff        The rig's shared clones (mayor/rig, refinery/rig) are
+              checked for uncommitted changes. No agent edits them in
+              place, so any change there was written from somewhere else
+  guard       Writes the guard blocked are listed too
+
+Each finding is recorded once. New findings are posted to the feed; with
+--escalate they are also escalated so a human looks at them.
+
+Examples:
+  gt witness scope greenplace              # Audit the last 24 hours
+  gt witness scope greenplace/Toast        # One polecat
+  gt witness scope greenplace --escalate   # Escalate new findings`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessScope,
+}
+
+// witnessScopeEscalateFn is a seam for tests. Production files a high
+// escalation through gt escalate.
+var witnessScopeEscalateFn = func(townRoot, rigName, summary string) error {
+	return runGtInTown(townRoot, "escalate", "-s", "high",
+		"--source", "scope:"+rigName, "--reason", summary,
+		fmt.Sprintf("Out-of-scope writes in %s", rigName))
+}
+
+func init() {
+	witnessScopeCmd.Flags().DurationVar(&witnessScopeSince, "since", 24*time.Hour, "How far back to read transcripts and guard blocks")
+	witnessScopeCmd.Flags().BoolVar(&witnessScopeEscalate, "escalate", false, "Escalate new findings")
+	witnessScopeCmd.Flags().BoolVar(&witnessScopeJSON, "json", false, "Output as JSON")
+
+	witnessCmd.AddCommand(witnessScopeCmd)
+}
+
+// scopeFinding is a violation and whether this audit found it first.
+type scopeFinding struct {
+	fsscope.Violation
+	New bool `json:"new,omitempty"`
+}
+
+func runWitnessScope(cmd *cobra.Command, args []string) error {
+	rigName, polecatName, _ := strings.Cut(args[0], "/")
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+
+	since := time.Now().Add(-witnessScopeSince)
+	findings, err := auditRigScope(townRoot, r.Path, rigName, polecatName, since)
+	if err != nil {
+		return err
+	}
+
+	var fresh []scopeFinding
+	for _, f := range findings {
+		if f.New {
+			fresh = append(fresh, f)
+			_ = events.LogFeed(events.TypeScopeViolation, "witness",
+				events.ScopeViolationPayload(rigName, f.Polecat, f.Path, f.Source))
+		}
+	}
+	if witnessScopeEscalate && len(fresh) > 0 {
+		if err := witnessScopeEscalateFn(townRoot, rigName, scopeSummary(fresh)); err != nil {
+			style.PrintWarning("could not escalate: %v", err)
+		}
+	}
+
+	if witnessScopeJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(findings)
+	}
+	printScopeFindings(rigName, findings)
+	return nil
+}
+
+// auditRigScope scans the rig's polecat transcripts and shared clones,
+// records what it finds, and returns it with the guard's blocks since the
+// given time, newest first.
+func auditRigScope(townRoot, rigPath, rigName, polecatName string, since time.Time) ([]scopeFinding, error) {
+	polecats := []string{polecatName}
+	if polecatName == "" {
+		polecats = listPolecatDirs(rigPath)
+	}
+
+	var found []fsscope.Violation
+	for _, name := range polecats {
+		scope := fsscope.ForPolecat(townRoot, rigName, name)
+		worktree := filepath.Join(scope.Root, rigName)
+		transcripts, err := agentlog.ClaudeCodeTranscripts(worktree, scope.Root)
+		if err != nil {
+			continue
+		}
+		for _, t := range transcripts {
+			if t.ModTime.Before(since) {
+				continue
+			}
+			vs, err := fsscope.ScanTranscript(t.Path, scope, worktree)
+			if err != nil {
+				style.PrintWarning("reading %s: %v", t.Path, err)
+				continue
+			}
+			for _, v := range vs {
+				if v.Time.IsZero() || !v.Time.Before(since) {
+					v.Rig, v.Polecat = rigName, name
+					found = append(found, v)
+				}
+			}
+		}
+	}
+	if polecatName == "" {
+		for _, clone := range []string{"mayor/rig", "refinery/rig"} {
+			found = append(found, sharedCloneChanges(rigPath, rigName, clone)...)
+		}
+	}
+
+	added, err := fsscope.Record(townRoot, found)
+	if err != nil {
+		return nil, fmt.Errorf("recording scope violations: %w", err)
+	}
+	isNew := make(map[string]bool, len(added))
+	for _, v := range added {
+		isNew[v.Key()] = true
+	}
+
+	findings := make([]scopeFinding, 0, len(found))
+	for _, v := range found {
+		v.Time = v.Time.UTC()
+		findings = append(findings, scopeFinding{Violation: v, New: isNew[v.Key()]})
+	}
+	blocked, err := fsscope.List(townRoot, rigName, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range blocked {
+		if v.Source == fsscope.SourceGuard && (polecatName == "" || v.Polecat == polecatName) {
+			findings = append(findings, scopeFinding{Violation: v})
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Time.After(findings[j].Time) })
+	return findings, nil
+}
+
+// listPolecatDirs returns the names of the rig's polecat directories.
+func listPolecatDirs(rigPath string) []string {
+	return listSubdirs(filepath.Join(rigPath, "polecats"))
+}
+
+// sharedCloneChanges reports uncommitted changes in one of the rig's
+// shared clones. The writer is unknown, so Polecat is empty.
+func sharedCloneChanges(rigPath, rigName, clone string) []fsscope.Violation {
+	dir := filepath.Join(rigPath, filepath.FromSlash(clone))
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return nil
+	}
+	status, err := git.NewGit(dir).Status()
+	if err != nil || status.Clean {
+		return nil
+	}
+	var vs []fsscope.Violation
+	for _, files := range [][]string{status.Modified, status.Added, status.Deleted, status.Untracked} {
+		for _, f := range files {
+			v := fsscope.Violation{Source: fsscope.SourceDiff, Rig: rigName, Tool: "git status", Path: filepath.Join(dir, f), Detail: clone}
+			if info, err := os.Stat(v.Path); err == nil {
+				v.Time = info.ModTime()
+			}
+			vs = append(vs, v)
+		}
+	}
+	return vs
+}
+
+// scopeSummary describes new findings for an escalation.
+func scopeSummary(findings []scopeFinding) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d write(s) outside polecat worktrees:", len(findings))
+	for i, f := range findings {
+		if i == 5 {
+			fmt.Fprintf(&b, " … and %d more (gt witness scope %s)", len(findings)-i, f.Rig)
+			break
+		}
+		who := f.Polecat
+		if who == "" {
+			who = "unknown (" + f.Detail + ")"
+		}
+		fmt.Fprintf(&b, " %s → %s [%s];", who, f.Path, f.Source)
+	}
+	return strings.TrimSuffix(b.String(), ";")
+}
+
+func printScopeFindings(rigName string, findings []scopeFinding) {
+	fmt.Printf("%s Write scope in %s\n", style.Bold.Render("🔒"), rigName)
+	if len(findings) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No writes outside polecat worktrees."))
+		return
+	}
+	for _, f := range findings {
+		who := f.Polecat
+		if who == "" {
+			who = "?"
+		}
+		when := "-"
+		if !f.Time.IsZero() {
+			when = f.Time.Local().Format("Jan 02 15:04")
+		}
+		line := fmt.Sprintf("  %-12s %-14s %-10s %s", when, who, f.Source, style.Error.Render(f.Path))
+		if f.New {
+			line += " " + style.Warning.Render("(new)")
+		}
+		fmt.Println(line)
+		if f.Detail != "" && f.Source == fsscope.SourceTranscript {
+			fmt.Printf("  %s\n", style.Dim.Render("  $ "+truncateStr(f.Detail, 100)))
+		}
+	}
+}
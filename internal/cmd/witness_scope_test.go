@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/fsscope"
+)
+
+func TestAuditRigScope(t *testing.T) {
+	town := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	rigPath := filepath.Join(town, "gastown")
+	worktree := filepath.Join(rigPath, "polecats", "Toast", "gastown")
+	mayorClone := filepath.Join(rigPath, "mayor", "rig")
+	for _, dir := range []string{worktree, mayorClone} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if out, err := exec.Command("git", "init", "-q", mayorClone).CombinedOutput(); err != nil {
+		t.Skipf("git init: %v (%s)", err, out)
+	}
+	if err := os.WriteFile(filepath.Join(mayorClone, "stray.go"), []byte("package x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+	projectDir := filepath.Join(home, ".claude", "projects", strings.ReplaceAll(worktree, "/", "-"))
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	line := `{"type":"assistant","cwd":"` + worktree + `","timestamp":"` + now.Format(time.RFC3339) +
+		`","message":{"content":[{"type":"tool_use","name":"Edit","input":{"file_path":"../../../../beads/mayor/rig/main.go"}}]}}` + "\n"
+	if err := os.WriteFile(filepath.Join(projectDir, "s1.jsonl"), []byte(line), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsscope.Record(town, []fsscope.Violation{{Time: now, Source: fsscope.SourceGuard, Rig: "gastown", Polecat: "Toast", Tool: "Write", Path: "/etc/hosts"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := auditRigScope(town, rigPath, "gastown", "", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bySource := map[string]scopeFinding{}
+	for _, f := range findings {
+		bySource[f.Source] = f
+	}
+	if f := bySource[fsscope.SourceTranscript]; !f.New || f.Polecat != "Toast" || f.Path != filepath.Join(town, "beads", "mayor", "rig", "main.go") {
+		t.Errorf("transcript finding = %+v", f)
+	}
+	if f := bySource[fsscope.SourceDiff]; !f.New || f.Polecat != "" || f.Detail != "mayor/rig" || filepath.Base(f.Path) != "stray.go" {
+		t.Errorf("diff finding = %+v", f)
+	}
+	if f := bySource[fsscope.SourceGuard]; f.Path != "/etc/hosts" || f.New {
+		t.Errorf("guard finding = %+v", f)
+	}
+
+	again, err := auditRigScope(town, rigPath, "gastown", "", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range again {
+		if f.New {
+			t.Errorf("second audit reported %+v as new", f)
+		}
+	}
+	if s := scopeSummary([]scopeFinding{{Violation: fsscope.Violation{Source: fsscope.SourceDiff, Path: "/x", Detail: "mayor/rig"}}}); !strings.Contains(s, "unknown (mayor/rig)") {
+		t.Errorf("summary = %q", s)
+	}
+}
+
+func TestScopeGuardIdentity(t *testing.T) {
+	town := t.TempDir()
+	t.Setenv("GT_ROOT", town)
+	t.Setenv("GT_POLECAT", "")
+	t.Setenv("GT_RIG", "")
+
+	if _, r, p := scopeGuardIdentity(filepath.Join(town, "gastown", "polecats", "Toast", "gastown", "internal")); r != "gastown" || p != "Toast" {
+		t.Errorf("from cwd: rig %q polecat %q", r, p)
+	}
+	if _, _, p := scopeGuardIdentity(filepath.Join(town, "gastown", "crew", "max")); p != "" {
+		t.Errorf("crew cwd: polecat %q, want none", p)
+	}
+	t.Setenv("GT_POLECAT", "Nux")
+	t.Setenv("GT_RIG", "beads")
+	if _, r, p := scopeGuardIdentity(town); r != "beads" || p != "Nux" {
+		t.Errorf("from env: rig %q polecat %q", r, p)
+	}
+}
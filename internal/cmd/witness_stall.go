@@ -0,0 +1,288 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/witness"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	witnessStallAct    bool
+	witnessStallJSON   bool
+	witnessStallsSince time.Duration
+	witnessStallsRig   string
+	witnessStallsJSON  bool
+)
+
+var witnessStallCmd = &cobra.Command{
+	Use:   "stall <rig>/<polecat>",
+	Short: "Classify why a polecat stalled and pick a recovery",
+	Long: `Classify the probable cause of a stalled polecat from its pane.
+
+Causes and the recovery chosen for each:
+  permission-prompt   escalate  Blocked on a tool-permission dialog (answered
+                                instead when the rig's permission_policy
+                                covers it; see gt permissions)
+  rate-limit          wait      Provider throttling; resumes on its own
+  long-computation    wait      A tool or model turn is still running
+  crashed-tool        restart   A tool died and the agent didn't recover
+  human-question      escalate  Waiting for an answer to a question
+  unknown             nudge     Nothing recognizable; ask it to continue
+
+Every classification is appended to logs/stalls.jsonl (see gt witness
+stalls). With --act the recovery is carried out; restarts preserve the
+worktree. Only crashed tools are restarted: the other causes are slow or
+blocked rather than broken, and a blind restart throws away their work.
+
+Examples:
+  gt witness stall greenplace/Toast          # Diagnose only
+  gt witness stall greenplace/Toast --act    # Diagnose and recover`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessStall,
+}
+
+var witnessStallsCmd = &cobra.Command{
+	Use:   "stalls",
+	Short: "Summarize classified stalls by cause",
+	Long: `Summarize the stall log (logs/stalls.jsonl) by cause and action.
+
+Examples:
+  gt witness stalls                  # Last 7 days, all rigs
+  gt witness stalls --since 24h --rig greenplace
+  gt witness stalls --json`,
+	Args: cobra.NoArgs,
+	RunE: runWitnessStalls,
+}
+
+var (
+	// witnessStallCaptureFn is a seam for tests. Production captures the last 50
+	// pane lines over tmux.
+	witnessStallCaptureFn = func(sessionName string) ([]string, error) {
+		return tmux.NewTmux().CapturePaneLines(sessionName, 50)
+	}
+
+	// witnessStallRecoverFn is a seam for tests. Production uses
+	// runStallRecovery.
+	witnessStallRecoverFn = runStallRecovery
+)
+
+func init() {
+	witnessStallCmd.Flags().BoolVar(&witnessStallAct, "act", false, "Carry out the recovery action")
+	witnessStallCmd.Flags().BoolVar(&witnessStallJSON, "json", false, "Output as JSON")
+	witnessStallsCmd.Flags().DurationVar(&witnessStallsSince, "since", 7*24*time.Hour, "Only stalls within this window")
+	witnessStallsCmd.Flags().StringVar(&witnessStallsRig, "rig", "", "Only stalls in this rig")
+	witnessStallsCmd.Flags().BoolVar(&witnessStallsJSON, "json", false, "Output as JSON")
+
+	witnessCmd.AddCommand(witnessStallCmd)
+	witnessCmd.AddCommand(witnessStallsCmd)
+}
+
+func runWitnessStall(cmd *cobra.Command, args []string) error {
+	rigName, polecatName, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	sessionName := session.PolecatSessionName(session.PrefixFor(rigName), polecatName)
+	lines, err := witnessStallCaptureFn(sessionName)
+	if err != nil {
+		return fmt.Errorf("capturing %s: %w", sessionName, err)
+	}
+	record := classifyStallRecord(rigName, polecatName, lines)
+	if activity, err := tmux.NewTmux().GetSessionActivity(sessionName); err == nil {
+		record.Idle = time.Since(activity).Round(time.Second)
+	}
+
+	var actErr error
+	if witnessStallAct {
+		actErr = witnessStallRecoverFn(townRoot, record)
+		record.Acted = actErr == nil
+	}
+	if err := witness.RecordStall(townRoot, record); err != nil {
+		style.PrintWarning("could not record stall: %v", err)
+	}
+	_ = events.LogFeed(events.TypeStallClassified, "witness",
+		events.StallPayload(rigName, polecatName, string(record.Cause), record.Action))
+
+	if witnessStallJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	} else {
+		printStallRecord(record)
+	}
+	if actErr != nil {
+		return fmt.Errorf("%s failed: %w", record.Action, actErr)
+	}
+	return nil
+}
+
+// classifyStallRecord classifies a pane capture into a stall record.
+func classifyStallRecord(rigName, polecatName string, lines []string) *witness.StallRecord {
+	cause, evidence := witness.ClassifyStall(lines)
+	return &witness.StallRecord{
+		Rig:      rigName,
+		Polecat:  polecatName,
+		Cause:    cause,
+		Action:   witness.StallAction(cause),
+		Evidence: evidence,
+	}
+}
+
+// runStallRecovery carries out the recovery action for a classified stall.
+func runStallRecovery(townRoot string, r *witness.StallRecord) error {
+	target := r.Rig + "/" + r.Polecat
+	switch r.Action {
+	case witness.StallActionWait:
+		return nil
+	case witness.StallActionNudge:
+		msg := "Witness: you've been idle for a while. Continue your hooked work; if you're blocked, say so with gt escalate."
+		return tmux.NewTmux().NudgeSession(session.PolecatSessionName(session.PrefixFor(r.Rig), r.Polecat), msg)
+	case witness.StallActionRestart:
+		return runGtInTown(townRoot, "session", "restart", target)
+	case witness.StallActionEscalate:
+		if r.Cause == witness.StallPermissionPrompt && answerStallPrompt(townRoot, r) {
+			return nil
+		}
+		reason := fmt.Sprintf("Witness classified the stall as %s.", r.Cause)
+		if r.Evidence != "" {
+			reason += fmt.Sprintf(" Pane shows: %q", r.Evidence)
+		}
+		return runGtInTown(townRoot, "escalate", "-s", "medium",
+			"--source", "stall:"+target, "--reason", reason,
+			fmt.Sprintf("%s stalled: %s", target, r.Cause))
+	}
+	return fmt.Errorf("unknown action %q", r.Action)
+}
+
+// answerStallPrompt lets the rig's permission policy answer the prompt a
+// polecat is blocked on, so only prompts the policy leaves to a human are
+// escalated. Reports whether the prompt was answered.
+func answerStallPrompt(townRoot string, r *witness.StallRecord) bool {
+	policy, err := loadPermissionPolicy(townRoot, r.Rig)
+	if err != nil || !policy.IsEnabled() {
+		return false
+	}
+	sessionName := session.PolecatSessionName(session.PrefixFor(r.Rig), r.Polecat)
+	a := respondToPermissionPrompt(townRoot, sessionName, r.Rig+"/"+r.Polecat, policy, false)
+	if a == nil || !a.Answered {
+		return false
+	}
+	r.Action = witness.StallActionAnswer
+	return true
+}
+
+func runGtInTown(townRoot string, args ...string) error {
+	c := exec.Command("gt", args...)
+	c.Dir = townRoot
+	if out, err := c.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func printStallRecord(r *witness.StallRecord) {
+	fmt.Printf("%s %s/%s: %s\n", style.Bold.Render("🩺"), r.Rig, r.Polecat, style.Bold.Render(string(r.Cause)))
+	if r.Idle > 0 {
+		fmt.Printf("  Idle:     %s\n", formatDuration(r.Idle))
+	}
+	if r.Evidence != "" {
+		fmt.Printf("  Evidence: %s\n", style.Dim.Render(r.Evidence))
+	}
+	switch {
+	case r.Acted:
+		fmt.Printf("  Action:   %s %s\n", r.Action, style.Success.Render("(done)"))
+	case witnessStallAct:
+		fmt.Printf("  Action:   %s %s\n", r.Action, style.Error.Render("(failed)"))
+	default:
+		fmt.Printf("  Action:   %s %s\n", r.Action, style.Dim.Render("(use --act to carry out)"))
+	}
+}
+
+// stallSummary counts stalls per cause and the actions taken for them.
+type stallSummary struct {
+	Cause   witness.StallCause `json:"cause"`
+	Count   int                `json:"count"`
+	Actions map[string]int     `json:"actions"`
+}
+
+func runWitnessStalls(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	records, err := witness.ReadStalls(townRoot, time.Now().Add(-witnessStallsSince))
+	if err != nil {
+		return fmt.Errorf("reading stall log: %w", err)
+	}
+	summary := summarizeStalls(records, witnessStallsRig)
+
+	if witnessStallsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(summary)
+	}
+	total := 0
+	for _, s := range summary {
+		total += s.Count
+	}
+	fmt.Printf("%s Stalls in the last %s: %d\n", style.Bold.Render("🩺"), formatDuration(witnessStallsSince), total)
+	for _, s := range summary {
+		var actions []string
+		for action, n := range s.Actions {
+			actions = append(actions, fmt.Sprintf("%s %d", action, n))
+		}
+		sort.Strings(actions)
+		fmt.Printf("  %-18s %4d  %s\n", s.Cause, s.Count, style.Dim.Render(strings.Join(actions, ", ")))
+	}
+	return nil
+}
+
+// summarizeStalls groups records by cause, most frequent first. A non-empty
+// rig limits the summary to that rig.
+func summarizeStalls(records []witness.StallRecord, rig string) []stallSummary {
+	byCause := make(map[witness.StallCause]*stallSummary)
+	for _, r := range records {
+		if rig != "" && r.Rig != rig {
+			continue
+		}
+		s, ok := byCause[r.Cause]
+		if !ok {
+			s = &stallSummary{Cause: r.Cause, Actions: make(map[string]int)}
+			byCause[r.Cause] = s
+		}
+		s.Count++
+		if r.Acted {
+			s.Actions[r.Action]++
+		}
+	}
+	summary := make([]stallSummary, 0, len(byCause))
+	for _, s := range byCause {
+		summary = append(summary, *s)
+	}
+	sort.Slice(summary, func(i, j int) bool {
+		if summary[i].Count != summary[j].Count {
+			return summary[i].Count > summary[j].Count
+		}
+		return summary[i].Cause < summary[j].Cause
+	})
+	return summary
+}
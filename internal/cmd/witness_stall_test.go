@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+func TestClassifyStallRecord(t *testing.T) {
+	r := classifyStallRecord("gastown", "Toast", []string{"⎿ Error: 429 Too Many Requests"})
+	if r.Cause != witness.StallRateLimit || r.Action != witness.StallActionWait {
+		t.Errorf("record = %+v, want rate-limit/wait", r)
+	}
+	if r.Rig != "gastown" || r.Polecat != "Toast" || r.Evidence == "" {
+		t.Errorf("record = %+v, want rig, polecat and evidence set", r)
+	}
+}
+
+func TestSummarizeStalls(t *testing.T) {
+	records := []witness.StallRecord{
+		{Rig: "gastown", Cause: witness.StallRateLimit, Action: witness.StallActionWait, Acted: true},
+		{Rig: "gastown", Cause: witness.StallRateLimit, Action: witness.StallActionWait, Acted: true},
+		{Rig: "gastown", Cause: witness.StallCrashedTool, Action: witness.StallActionRestart},
+		{Rig: "beads", Cause: witness.StallCrashedTool, Action: witness.StallActionRestart, Acted: true},
+		{Rig: "beads", Cause: witness.StallHumanQuestion, Action: witness.StallActionEscalate, Acted: true},
+	}
+
+	all := summarizeStalls(records, "")
+	if len(all) != 3 {
+		t.Fatalf("got %d causes, want 3: %+v", len(all), all)
+	}
+	// Ties on count sort by cause name.
+	if all[0].Cause != witness.StallCrashedTool || all[0].Count != 2 || all[0].Actions[witness.StallActionRestart] != 1 {
+		t.Errorf("all[0] = %+v, want crashed-tool x2 with one restart carried out", all[0])
+	}
+	if all[1].Cause != witness.StallRateLimit || all[1].Count != 2 {
+		t.Errorf("all[1] = %+v, want rate-limit x2", all[1])
+	}
+
+	gastown := summarizeStalls(records, "gastown")
+	total := 0
+	for _, s := range gastown {
+		total += s.Count
+	}
+	if total != 3 {
+		t.Errorf("gastown total = %d, want 3", total)
+	}
+}
@@ -0,0 +1,173 @@
+package codeindex
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractSymbols(t *testing.T) {
+	src := "package x\n\ntype Engineer struct{}\n\nfunc (e *Engineer) HandleMRInfoSuccess() {}\n\nfunc newEngineer() *Engineer { return nil }\n"
+	got := ExtractSymbols("go", src)
+	want := []Symbol{{"Engineer", "type", 3}, {"HandleMRInfoSuccess", "method", 5}, {"newEngineer", "func", 7}}
+	if len(got) != len(want) {
+		t.Fatalf("ExtractSymbols = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("symbol %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	md := "# Merge queue\n\n```sh\n# not a heading\n```\n## Batching\n"
+	if got := ExtractSymbols("markdown", md); len(got) != 2 || got[1].Name != "Batching" {
+		t.Errorf("markdown headings = %+v", got)
+	}
+	if got := ExtractSymbols("c", "if (x) {\n"); len(got) != 0 {
+		t.Errorf("control flow caught as a function: %+v", got)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name, lang, content, want string
+	}{
+		{"go package doc", "go", "// Copyright 2024 Acme. License: MIT.\n\n// Package refinery merges\n// polecat branches.\npackage refinery\n", "Package refinery merges polecat branches."},
+		{"build tag", "go", "//go:build linux\n\n// Package proc reads /proc.\npackage proc\n", "Package proc reads /proc."},
+		{"python docstring", "python", "#!/usr/bin/env python\n\"\"\"Sync the mirror.\n\nRuns hourly.\"\"\"\nimport os\n", "Sync the mirror. Runs hourly."},
+		{"markdown", "markdown", "\n# Gas Town reference\n\nText.\n", "Gas Town reference"},
+		{"no comment", "go", "package x\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summarize(tt.lang, tt.content); got != tt.want {
+				t.Errorf("Summarize = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitIdentifiers(t *testing.T) {
+	if got, want := SplitIdentifiers("HandleMRInfo rig_settings"), "HandleMRInfo handle mr info rig_settings rig settings"; got != want {
+		t.Errorf("SplitIdentifiers = %q, want %q", got, want)
+	}
+}
+
+func TestConfigWants(t *testing.T) {
+	cfg := &Config{Enabled: true, Include: []string{"internal/", "*.md"}, Exclude: []string{"*_test.go", "testdata/"}}
+	for file, want := range map[string]bool{
+		"internal/refinery/engineer.go":      true,
+		"internal/refinery/engineer_test.go": false,
+		"internal/x/testdata/in.go":          false,
+		"README.md":                          true,
+		"cmd/gt/main.go":                     false,
+	} {
+		if got := cfg.Wants(file); got != want {
+			t.Errorf("Wants(%q) = %v, want %v", file, got, want)
+		}
+	}
+
+	if err := (&Config{EmbeddingModel: "m"}).Validate(); err == nil {
+		t.Error("embedding_model without an endpoint should be rejected")
+	}
+	if err := (&Config{Exclude: []string{"[bad"}}).Validate(); err == nil {
+		t.Error("malformed pattern should be rejected")
+	}
+}
+
+// fakeEmbedder embeds a text as the counts of a few marker words.
+type fakeEmbedder struct{ calls int }
+
+func (f *fakeEmbedder) Embed(_ context.Context, inputs []string) ([][]float64, error) {
+	f.calls += len(inputs)
+	out := make([][]float64, len(inputs))
+	for i, in := range inputs {
+		in = strings.ToLower(in)
+		out[i] = []float64{float64(strings.Count(in, "merge")), float64(strings.Count(in, "mail")), 0.1}
+	}
+	return out, nil
+}
+
+func TestRefreshAndSearch(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(file, content string) {
+		t.Helper()
+		p := filepath.Join(repo, file)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q")
+	git("config", "user.email", "t@example.com")
+	git("config", "user.name", "t")
+	write("refinery/merge.go", "// Package refinery merges branches.\npackage refinery\n\nfunc MergeBatch() {}\n")
+	write("mail/send.go", "// Package mail delivers mail.\npackage mail\n\nfunc Send() {}\n")
+	write("logo.png", "\x89PNG")
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	cfg := &Config{Enabled: true, EmbeddingEndpoint: "http://embed", EmbeddingModel: "fake"}
+	emb := &fakeEmbedder{}
+	idx := &Index{Files: make(map[string]*File)}
+	stats, err := Refresh(context.Background(), idx, repo, cfg, emb, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Added != 2 || stats.Embedded != 2 || len(idx.Files) != 2 || idx.Commit == "" {
+		t.Fatalf("first refresh: %s, files %d, commit %q", stats, len(idx.Files), idx.Commit)
+	}
+
+	// Round trip through disk.
+	rig := t.TempDir()
+	if err := idx.Save(rig); err != nil {
+		t.Fatal(err)
+	}
+	if idx, err = Load(rig); err != nil || idx.SymbolCount() != 2 || idx.Embedded() != 2 {
+		t.Fatalf("Load = %+v, %v", idx, err)
+	}
+
+	// Only the changed file is re-read and re-embedded.
+	write("mail/send.go", "// Package mail delivers mail.\npackage mail\n\nfunc Send() {}\n\nfunc Forward() {}\n")
+	git("rm", "-q", "refinery/merge.go")
+	write("refinery/queue.go", "// Package refinery merges the queue.\npackage refinery\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "change")
+	emb.calls = 0
+	stats, err = Refresh(context.Background(), idx, repo, cfg, emb, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Added != 1 || stats.Updated != 1 || stats.Removed != 1 || stats.Unchanged != 0 || emb.calls != 2 {
+		t.Errorf("incremental refresh: %s, %d embedded", stats, emb.calls)
+	}
+
+	results, err := Search(context.Background(), idx, "merge", emb, 1)
+	if err != nil || len(results) != 1 || results[0].File.Path != "refinery/queue.go" || results[0].Method != "embedding" {
+		t.Errorf("embedding search = %+v, %v", results, err)
+	}
+	results, err = Search(context.Background(), idx, "Forward mail", nil, 0)
+	if err != nil || len(results) == 0 || results[0].File.Path != "mail/send.go" || len(results[0].Matched) != 1 || results[0].Method != "tfidf" {
+		t.Errorf("tfidf search = %+v, %v", results, err)
+	}
+
+	if got := FindSymbol(idx, "send"); len(got) != 1 || got[0].Path != "mail/send.go" || got[0].Symbol.Line != 4 {
+		t.Errorf("FindSymbol = %+v", got)
+	}
+}
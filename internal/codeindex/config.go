@@ -0,0 +1,125 @@
+package codeindex
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/dedup"
+)
+
+// DefaultMaxFileBytes skips generated blobs and vendored bundles.
+const DefaultMaxFileBytes = 256 * 1024
+
+// Config is the code_index section of a rig's settings/config.json.
+//
+//	"code_index": {
+//	  "enabled": true,
+//	  "include": ["internal/", "cmd/", "docs/"],
+//	  "exclude": ["*_test.go", "testdata/"],
+//	  "embedding_endpoint": "http://localhost:11434/v1/embeddings",
+//	  "embedding_model": "nomic-embed-text"
+//	}
+//
+// Include and exclude patterns are matched like changelog ignores: against
+// each tracked file's path and base name, and a pattern ending in "/"
+// matches everything under a directory of that name. Without an embedding
+// endpoint the index still holds symbols and summaries, and searches fall
+// back to TF-IDF.
+type Config struct {
+	Enabled      bool     `json:"enabled"`
+	Include      []string `json:"include,omitempty"`
+	Exclude      []string `json:"exclude,omitempty"`
+	MaxFileBytes int64    `json:"max_file_bytes,omitempty"`
+
+	// EmbeddingEndpoint is an OpenAI-compatible /embeddings URL.
+	EmbeddingEndpoint string `json:"embedding_endpoint,omitempty"`
+	// EmbeddingModel is the model name sent to EmbeddingEndpoint.
+	EmbeddingModel string `json:"embedding_model,omitempty"`
+	// EmbeddingAPIKeyEnv names the environment variable holding the bearer
+	// token for EmbeddingEndpoint.
+	EmbeddingAPIKeyEnv string `json:"embedding_api_key_env,omitempty"`
+}
+
+// IsEnabled reports whether the rig keeps a code index.
+func (c *Config) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// Validate checks the config. A nil config is valid (no index).
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxFileBytes < 0 {
+		return fmt.Errorf("max_file_bytes must not be negative")
+	}
+	if c.EmbeddingEndpoint == "" && (c.EmbeddingModel != "" || c.EmbeddingAPIKeyEnv != "") {
+		return fmt.Errorf("embedding_model and embedding_api_key_env require embedding_endpoint")
+	}
+	for _, p := range append(append([]string(nil), c.Include...), c.Exclude...) {
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("include and exclude patterns must not be empty")
+		}
+		if _, err := path.Match(strings.TrimSuffix(p, "/"), ""); err != nil {
+			return fmt.Errorf("bad pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// GetMaxFileBytes returns MaxFileBytes or DefaultMaxFileBytes if unset.
+func (c *Config) GetMaxFileBytes() int64 {
+	if c == nil || c.MaxFileBytes <= 0 {
+		return DefaultMaxFileBytes
+	}
+	return c.MaxFileBytes
+}
+
+// Model identifies the embedding model the index's vectors come from, or ""
+// without embeddings. Vectors from a different model are recomputed.
+func (c *Config) Model() string {
+	if c == nil || c.EmbeddingEndpoint == "" {
+		return ""
+	}
+	return c.EmbeddingEndpoint + "#" + c.EmbeddingModel
+}
+
+// Embedder returns the configured embedding provider, or nil without one.
+func (c *Config) Embedder() Embedder {
+	if c == nil || c.EmbeddingEndpoint == "" {
+		return nil
+	}
+	apiKey := ""
+	if c.EmbeddingAPIKeyEnv != "" {
+		apiKey = os.Getenv(c.EmbeddingAPIKeyEnv)
+	}
+	return &dedup.EmbeddingScorer{Endpoint: c.EmbeddingEndpoint, Model: c.EmbeddingModel, APIKey: apiKey}
+}
+
+// Wants reports whether a tracked file belongs in the index.
+func (c *Config) Wants(file string) bool {
+	if c != nil && len(c.Include) > 0 && !matchesAny(file, c.Include) {
+		return false
+	}
+	return c == nil || !matchesAny(file, c.Exclude)
+}
+
+func matchesAny(file string, patterns []string) bool {
+	for _, p := range patterns {
+		if dir, ok := strings.CutSuffix(p, "/"); ok {
+			if file == dir || strings.HasPrefix(file, dir+"/") || strings.Contains(file, "/"+dir+"/") {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(p, file); ok {
+			return true
+		}
+		if ok, _ := path.Match(p, path.Base(file)); ok {
+			return true
+		}
+	}
+	return false
+}
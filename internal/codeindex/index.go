@@ -0,0 +1,312 @@
+// Package codeindex keeps an optional per-rig code index for agent
+// retrieval: each tracked file's symbols, a one-line summary and, when an
+// embedding provider is configured, an embedding of both plus the head of
+// the file. The refinery refreshes the index incrementally after merges
+// (only blobs that changed are re-read and re-embedded); agents query it
+// with gt code search and gt code symbol.
+//
+// The index lives in <rig>/.runtime/code-index.json.
+package codeindex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Embedder turns texts into embedding vectors, one per input, in order.
+type Embedder interface {
+	Embed(ctx context.Context, inputs []string) ([][]float64, error)
+}
+
+// embedBatch bounds the inputs sent in one embedding request.
+const embedBatch = 32
+
+// embedHeadBytes is how much of a file's content goes into its embedding
+// text after the path, summary and symbols.
+const embedHeadBytes = 2000
+
+// File is one indexed file.
+type File struct {
+	Path      string    `json:"path"`
+	Blob      string    `json:"blob"` // Git blob hash the entry was built from
+	Lang      string    `json:"lang"`
+	Lines     int       `json:"lines"`
+	Summary   string    `json:"summary,omitempty"`
+	Symbols   []Symbol  `json:"symbols,omitempty"`
+	Embedding []float64 `json:"embedding,omitempty"`
+}
+
+// Index is a rig's code index.
+type Index struct {
+	Rig       string           `json:"rig"`
+	Commit    string           `json:"commit,omitempty"` // HEAD when last refreshed
+	Model     string           `json:"model,omitempty"`  // Embedding model of the vectors, "" without
+	UpdatedAt time.Time        `json:"updated_at"`
+	Files     map[string]*File `json:"files"`
+}
+
+// Path returns where a rig's code index is kept.
+func Path(rigPath string) string {
+	return filepath.Join(rigPath, constants.DirRuntime, "code-index.json")
+}
+
+// Load reads a rig's index, returning an empty one when none was built yet.
+func Load(rigPath string) (*Index, error) {
+	idx := &Index{Files: make(map[string]*File)}
+	data, err := os.ReadFile(Path(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return idx, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, idx); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", Path(rigPath), err)
+	}
+	if idx.Files == nil {
+		idx.Files = make(map[string]*File)
+	}
+	return idx, nil
+}
+
+// Save writes the index, replacing the old one atomically so concurrent
+// readers never see a torn file.
+func (idx *Index) Save(rigPath string) error {
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	path := Path(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: the index is not sensitive
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// SymbolCount returns the number of symbols across the index.
+func (idx *Index) SymbolCount() int {
+	n := 0
+	for _, f := range idx.Files {
+		n += len(f.Symbols)
+	}
+	return n
+}
+
+// Embedded returns the number of files with an embedding.
+func (idx *Index) Embedded() int {
+	n := 0
+	for _, f := range idx.Files {
+		if len(f.Embedding) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Stats describes what a refresh changed.
+type Stats struct {
+	Added     int
+	Updated   int
+	Removed   int
+	Unchanged int
+	Embedded  int
+	EmbedErr  error // Embedding failed; entries were kept without vectors
+}
+
+func (s Stats) String() string {
+	out := fmt.Sprintf("%d added, %d updated, %d removed, %d unchanged", s.Added, s.Updated, s.Removed, s.Unchanged)
+	if s.Embedded > 0 {
+		out += fmt.Sprintf(", %d embedded", s.Embedded)
+	}
+	return out
+}
+
+// trackedBlob is one entry of git ls-files --stage.
+type trackedBlob struct {
+	path string
+	blob string
+}
+
+// Refresh brings the index in line with the tracked files in repoDir: new
+// and changed blobs are re-read (and re-embedded when embedder is set),
+// deleted files are dropped. full re-reads everything. A change of
+// embedding model re-embeds every file. Embedding failures don't fail the
+// refresh: they are reported in Stats.EmbedErr and retried next time.
+func Refresh(ctx context.Context, idx *Index, repoDir string, cfg *Config, embedder Embedder, full bool) (Stats, error) {
+	var stats Stats
+	tracked, err := lsFiles(ctx, repoDir)
+	if err != nil {
+		return stats, err
+	}
+	model := cfg.Model()
+	if embedder == nil {
+		model = ""
+	}
+	reembed := model != idx.Model
+
+	seen := make(map[string]bool)
+	var toEmbed []*File
+	for _, t := range tracked {
+		lang := Language(t.path)
+		if lang == "" || !cfg.Wants(t.path) {
+			continue
+		}
+		seen[t.path] = true
+		old := idx.Files[t.path]
+		if old != nil && old.Blob == t.blob && !full {
+			stats.Unchanged++
+			if embedder != nil && (reembed || len(old.Embedding) == 0) {
+				toEmbed = append(toEmbed, old)
+			} else if embedder == nil {
+				old.Embedding = nil
+			}
+			continue
+		}
+
+		abs := filepath.Join(repoDir, filepath.FromSlash(t.path))
+		info, err := os.Stat(abs)
+		if err != nil || info.Size() > cfg.GetMaxFileBytes() {
+			delete(idx.Files, t.path)
+			delete(seen, t.path)
+			continue
+		}
+		data, err := os.ReadFile(abs) //nolint:gosec // G304: path is a tracked file in the rig's repo
+		if err != nil || bytes.IndexByte(data, 0) >= 0 {
+			delete(idx.Files, t.path)
+			delete(seen, t.path)
+			continue
+		}
+		content := string(data)
+		f := &File{
+			Path:    t.path,
+			Blob:    t.blob,
+			Lang:    lang,
+			Lines:   strings.Count(content, "\n"),
+			Summary: Summarize(lang, content),
+			Symbols: ExtractSymbols(lang, content),
+		}
+		if old == nil {
+			stats.Added++
+		} else {
+			stats.Updated++
+		}
+		idx.Files[t.path] = f
+		if embedder != nil {
+			f.Embedding = nil
+			toEmbed = append(toEmbed, f)
+		}
+	}
+	for p := range idx.Files {
+		if !seen[p] {
+			delete(idx.Files, p)
+			stats.Removed++
+		}
+	}
+
+	if len(toEmbed) > 0 {
+		stats.Embedded, stats.EmbedErr = embedFiles(ctx, embedder, toEmbed, repoDir)
+	}
+	if stats.EmbedErr == nil {
+		idx.Model = model
+	}
+	if commit, err := gitOutput(ctx, repoDir, "rev-parse", "HEAD"); err == nil {
+		idx.Commit = commit
+	}
+	idx.UpdatedAt = time.Now().UTC()
+	return stats, nil
+}
+
+// embedFiles embeds files in batches, returning how many got a vector.
+func embedFiles(ctx context.Context, embedder Embedder, files []*File, repoDir string) (int, error) {
+	done := 0
+	for start := 0; start < len(files); start += embedBatch {
+		batch := files[start:min(start+embedBatch, len(files))]
+		inputs := make([]string, len(batch))
+		for i, f := range batch {
+			inputs[i] = f.embedText(repoDir)
+		}
+		vecs, err := embedder.Embed(ctx, inputs)
+		if err == nil && len(vecs) != len(batch) {
+			err = fmt.Errorf("embedding provider returned %d vectors for %d inputs", len(vecs), len(batch))
+		}
+		if err != nil {
+			return done, fmt.Errorf("embedding %s: %w", batch[0].Path, err)
+		}
+		for i, f := range batch {
+			f.Embedding = vecs[i]
+		}
+		done += len(batch)
+	}
+	return done, nil
+}
+
+// embedText is what gets embedded for a file: its path, summary, symbol
+// names and the head of its content.
+func (f *File) embedText(repoDir string) string {
+	var b strings.Builder
+	b.WriteString(f.Path + "\n")
+	if f.Summary != "" {
+		b.WriteString(f.Summary + "\n")
+	}
+	for _, s := range f.Symbols {
+		b.WriteString(s.Name + " ")
+	}
+	b.WriteString("\n")
+	data, err := os.ReadFile(filepath.Join(repoDir, filepath.FromSlash(f.Path))) //nolint:gosec // G304: path is a tracked file in the rig's repo
+	if err == nil {
+		if len(data) > embedHeadBytes {
+			data = data[:embedHeadBytes]
+		}
+		b.Write(data)
+	}
+	return b.String()
+}
+
+// lsFiles lists the tracked files in repoDir with their blob hashes.
+func lsFiles(ctx context.Context, repoDir string) ([]trackedBlob, error) {
+	out, err := gitOutput(ctx, repoDir, "ls-files", "--stage", "-z")
+	if err != nil {
+		return nil, err
+	}
+	var files []trackedBlob
+	for _, rec := range strings.Split(out, "\x00") {
+		// <mode> <blob> <stage>\t<path>
+		meta, file, ok := strings.Cut(rec, "\t")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(meta)
+		if len(fields) != 3 || fields[0] == "160000" { // Skip submodules
+			continue
+		}
+		files = append(files, trackedBlob{path: file, blob: fields[1]})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files, nil
+}
+
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
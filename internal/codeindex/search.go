@@ -0,0 +1,172 @@
+package codeindex
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/steveyegge/gastown/internal/dedup"
+)
+
+// symbolBoost is added to a file's score when one of its symbols is named
+// in the query, so an exact identifier beats a loosely related file.
+const symbolBoost = 0.3
+
+// Result is a file matched by a search.
+type Result struct {
+	File    *File    `json:"file"`
+	Score   float64  `json:"score"`
+	Matched []Symbol `json:"matched,omitempty"` // Symbols named in the query
+	Method  string   `json:"method"`            // "embedding" or "tfidf"
+}
+
+// Search ranks the index's files against a natural-language or identifier
+// query, best first. With an embedder and an index built with embeddings
+// it ranks by cosine similarity; otherwise by TF-IDF over paths, summaries
+// and symbol names split into words. Embedding errors fall back to TF-IDF.
+func Search(ctx context.Context, idx *Index, query string, embedder Embedder, limit int) ([]Result, error) {
+	files := idx.sortedFiles()
+	if len(files) == 0 || strings.TrimSpace(query) == "" {
+		return nil, nil
+	}
+
+	var scores []float64
+	method := "tfidf"
+	if embedder != nil && idx.Embedded() > 0 {
+		if vecs, err := embedder.Embed(ctx, []string{query}); err == nil && len(vecs) == 1 {
+			scores = make([]float64, len(files))
+			for i, f := range files {
+				scores[i] = math.Max(0, dedup.CosineDense(vecs[0], f.Embedding))
+			}
+			method = "embedding"
+		}
+	}
+	if scores == nil {
+		docs := make([]dedup.Document, len(files))
+		for i, f := range files {
+			docs[i] = dedup.Document{ID: f.Path, Title: f.searchTitle(), Description: f.Summary}
+		}
+		var err error
+		scores, err = dedup.TFIDFScorer{}.Score(ctx, dedup.Document{Title: SplitIdentifiers(query)}, docs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	terms := make(map[string]bool)
+	for _, t := range strings.FieldsFunc(query, notIdentRune) {
+		terms[strings.ToLower(t)] = true
+	}
+	var results []Result
+	for i, f := range files {
+		var matched []Symbol
+		for _, s := range f.Symbols {
+			if terms[strings.ToLower(s.Name)] {
+				matched = append(matched, s)
+			}
+		}
+		score := scores[i]
+		if len(matched) > 0 {
+			score += symbolBoost
+		}
+		if score <= 0 {
+			continue
+		}
+		results = append(results, Result{File: f, Score: score, Matched: matched, Method: method})
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// SymbolMatch is a symbol definition found by FindSymbol.
+type SymbolMatch struct {
+	Path   string `json:"path"`
+	Symbol Symbol `json:"symbol"`
+}
+
+// FindSymbol returns the definitions named name: exact (case-insensitive)
+// matches first, then names containing it.
+func FindSymbol(idx *Index, name string) []SymbolMatch {
+	want := strings.ToLower(name)
+	var exact, partial []SymbolMatch
+	for _, f := range idx.sortedFiles() {
+		for _, s := range f.Symbols {
+			got := strings.ToLower(s.Name)
+			switch {
+			case got == want:
+				exact = append(exact, SymbolMatch{Path: f.Path, Symbol: s})
+			case strings.Contains(got, want):
+				partial = append(partial, SymbolMatch{Path: f.Path, Symbol: s})
+			}
+		}
+	}
+	return append(exact, partial...)
+}
+
+func (idx *Index) sortedFiles() []*File {
+	files := make([]*File, 0, len(idx.Files))
+	for _, f := range idx.Files {
+		files = append(files, f)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files
+}
+
+// searchTitle is the text TF-IDF weighs most: the path and symbol names,
+// split into words.
+func (f *File) searchTitle() string {
+	var b strings.Builder
+	b.WriteString(SplitIdentifiers(f.Path))
+	for _, s := range f.Symbols {
+		b.WriteString(" " + SplitIdentifiers(s.Name))
+	}
+	return b.String()
+}
+
+// SplitIdentifiers breaks camelCase, snake_case and path identifiers into
+// words, keeping the original identifiers too ("HandleMRInfo" becomes
+// "HandleMRInfo handle mr info").
+func SplitIdentifiers(text string) string {
+	var out []string
+	for _, ident := range strings.FieldsFunc(text, notIdentRune) {
+		out = append(out, ident)
+		var words []string
+		for _, part := range strings.Split(ident, "_") {
+			if part != "" {
+				words = append(words, splitCamel(part)...)
+			}
+		}
+		if len(words) > 1 {
+			out = append(out, words...)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+func notIdentRune(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+}
+
+// splitCamel splits an identifier at lower-to-upper boundaries and before
+// the last capital of an acronym ("MRInfo" → "MR", "Info").
+func splitCamel(ident string) []string {
+	runes := []rune(ident)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		boundary := unicode.IsLower(prev) && unicode.IsUpper(cur) ||
+			unicode.IsLetter(prev) != unicode.IsLetter(cur) ||
+			unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if boundary {
+			words = append(words, strings.ToLower(string(runes[start:i])))
+			start = i
+		}
+	}
+	return append(words, strings.ToLower(string(runes[start:])))
+}
@@ -0,0 +1,238 @@
+package codeindex
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Symbol is a top-level definition in a file.
+type Symbol struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"` // func, method, type, class, heading, ...
+	Line int    `json:"line"` // 1-based
+}
+
+// symbolPattern extracts one kind of symbol: the last non-empty submatch is
+// the name.
+type symbolPattern struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// languages maps file extensions to a language name.
+var languages = map[string]string{
+	".go": "go", ".py": "python", ".rb": "ruby", ".rs": "rust", ".java": "java", ".kt": "kotlin",
+	".js": "javascript", ".jsx": "javascript", ".mjs": "javascript", ".ts": "typescript", ".tsx": "typescript",
+	".c": "c", ".h": "c", ".cc": "cpp", ".cpp": "cpp", ".hpp": "cpp", ".cs": "csharp", ".swift": "swift",
+	".php": "php", ".sh": "shell", ".bash": "shell", ".sql": "sql", ".proto": "protobuf",
+	".md": "markdown", ".markdown": "markdown", ".rst": "rst", ".toml": "toml", ".yaml": "yaml", ".yml": "yaml",
+}
+
+var patterns = map[string][]symbolPattern{
+	"go": {
+		{"method", regexp.MustCompile(`^func\s+\([^)]*\)\s*([A-Za-z_]\w*)`)},
+		{"func", regexp.MustCompile(`^func\s+([A-Za-z_]\w*)`)},
+		{"type", regexp.MustCompile(`^type\s+([A-Za-z_]\w*)`)},
+	},
+	"python": {
+		{"func", regexp.MustCompile(`^(?:async\s+)?def\s+(\w+)`)},
+		{"method", regexp.MustCompile(`^\s+(?:async\s+)?def\s+(\w+)`)},
+		{"class", regexp.MustCompile(`^class\s+(\w+)`)},
+	},
+	"ruby": {
+		{"method", regexp.MustCompile(`^\s*def\s+(?:self\.)?(\w+[?!]?)`)},
+		{"class", regexp.MustCompile(`^\s*(?:class|module)\s+([A-Z]\w*(?:::\w+)*)`)},
+	},
+	"rust": {
+		{"func", regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?(?:async\s+)?(?:unsafe\s+)?fn\s+(\w+)`)},
+		{"type", regexp.MustCompile(`^\s*(?:pub(?:\([^)]*\))?\s+)?(?:struct|enum|trait|type)\s+(\w+)`)},
+	},
+	"javascript": {
+		{"func", regexp.MustCompile(`^(?:export\s+)?(?:default\s+)?(?:async\s+)?function\*?\s+(\w+)`)},
+		{"class", regexp.MustCompile(`^(?:export\s+)?(?:default\s+)?class\s+(\w+)`)},
+		{"const", regexp.MustCompile(`^export\s+(?:const|let)\s+(\w+)`)},
+	},
+	"typescript": {
+		{"func", regexp.MustCompile(`^(?:export\s+)?(?:default\s+)?(?:async\s+)?function\*?\s+(\w+)`)},
+		{"class", regexp.MustCompile(`^(?:export\s+)?(?:default\s+)?(?:abstract\s+)?class\s+(\w+)`)},
+		{"type", regexp.MustCompile(`^(?:export\s+)?(?:declare\s+)?(?:interface|type|enum)\s+(\w+)`)},
+		{"const", regexp.MustCompile(`^export\s+(?:const|let)\s+(\w+)`)},
+	},
+	"java": {
+		{"class", regexp.MustCompile(`^\s*(?:public\s+|protected\s+|private\s+)?(?:abstract\s+|final\s+|static\s+)*(?:class|interface|enum|record)\s+(\w+)`)},
+	},
+	"kotlin": {
+		{"func", regexp.MustCompile(`^\s*(?:(?:public|private|internal|override|suspend)\s+)*fun\s+(?:<[^>]*>\s*)?(?:\w+\.)?(\w+)`)},
+		{"class", regexp.MustCompile(`^\s*(?:(?:public|private|internal|data|sealed|abstract|open)\s+)*(?:class|interface|object)\s+(\w+)`)},
+	},
+	"csharp": {
+		{"class", regexp.MustCompile(`^\s*(?:(?:public|internal|private|protected|static|abstract|sealed|partial)\s+)*(?:class|interface|struct|enum|record)\s+(\w+)`)},
+	},
+	"swift": {
+		{"func", regexp.MustCompile(`^\s*(?:(?:public|private|internal|static)\s+)*func\s+(\w+)`)},
+		{"class", regexp.MustCompile(`^\s*(?:(?:public|private|internal|final)\s+)*(?:class|struct|enum|protocol)\s+(\w+)`)},
+	},
+	"php": {
+		{"func", regexp.MustCompile(`^\s*(?:(?:public|private|protected|static)\s+)*function\s+(\w+)`)},
+		{"class", regexp.MustCompile(`^\s*(?:abstract\s+|final\s+)?(?:class|interface|trait)\s+(\w+)`)},
+	},
+	"c": {
+		{"type", regexp.MustCompile(`^(?:typedef\s+)?(?:struct|enum|union)\s+(\w+)\s*\{`)},
+		{"func", regexp.MustCompile(`^[A-Za-z_][\w\s\*]*?\b(\w+)\s*\([^;]*$`)},
+	},
+	"cpp": {
+		{"class", regexp.MustCompile(`^(?:class|struct)\s+(\w+)`)},
+		{"func", regexp.MustCompile(`^[A-Za-z_][\w\s\*:&<>]*?\b(\w+)\s*\([^;]*$`)},
+	},
+	"shell": {
+		{"func", regexp.MustCompile(`^(?:function\s+)?([A-Za-z_][\w-]*)\s*\(\)\s*\{?`)},
+	},
+	"sql": {
+		{"table", regexp.MustCompile(`(?i)^\s*create\s+(?:or\s+replace\s+)?(?:table|view|function|index)\s+(?:if\s+not\s+exists\s+)?([\w.]+)`)},
+	},
+	"protobuf": {
+		{"type", regexp.MustCompile(`^\s*(?:message|enum|service)\s+(\w+)`)},
+		{"rpc", regexp.MustCompile(`^\s*rpc\s+(\w+)`)},
+	},
+	"markdown": {
+		{"heading", regexp.MustCompile(`^#{1,3}\s+(.+?)\s*#*$`)},
+	},
+}
+
+// Language returns the language of a file by extension, or "" for files the
+// index skips.
+func Language(file string) string {
+	return languages[strings.ToLower(path.Ext(file))]
+}
+
+// maxSymbols bounds the symbols kept per file; huge generated files would
+// otherwise dominate the index.
+const maxSymbols = 200
+
+// ExtractSymbols returns the top-level definitions in content.
+func ExtractSymbols(lang, content string) []Symbol {
+	pats := patterns[lang]
+	if len(pats) == 0 {
+		return nil
+	}
+	var syms []Symbol
+	inFence := false
+	for i, line := range strings.Split(content, "\n") {
+		if lang == "markdown" && strings.HasPrefix(line, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		for _, p := range pats {
+			m := p.re.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			name := ""
+			for j := len(m) - 1; j > 0 && name == ""; j-- {
+				name = m[j]
+			}
+			if name == "" || isKeyword(name) {
+				continue
+			}
+			syms = append(syms, Symbol{Name: name, Kind: p.kind, Line: i + 1})
+			break
+		}
+		if len(syms) == maxSymbols {
+			break
+		}
+	}
+	return syms
+}
+
+// isKeyword filters control-flow words the C-like function patterns catch.
+func isKeyword(name string) bool {
+	switch name {
+	case "if", "for", "while", "switch", "return", "sizeof", "catch", "else":
+		return true
+	}
+	return false
+}
+
+// summaryMaxLines bounds a file summary.
+const summaryMaxLines = 3
+
+// Summarize returns a short description of a file: its first leading
+// comment block (package docs, module docstring), or its first heading for
+// docs. License headers and build directives are skipped.
+func Summarize(lang, content string) string {
+	lines := strings.Split(content, "\n")
+	if lang == "markdown" || lang == "rst" {
+		for _, l := range lines {
+			if t := strings.TrimSpace(strings.TrimLeft(l, "#")); t != "" && !strings.HasPrefix(t, "=") && !strings.HasPrefix(t, "---") {
+				return t
+			}
+		}
+		return ""
+	}
+
+	var block []string
+	license := false
+	flush := func() string {
+		text := ""
+		if !license && len(block) > 0 {
+			if len(block) > summaryMaxLines {
+				block = block[:summaryMaxLines]
+			}
+			text = strings.Join(block, " ")
+		}
+		block, license = nil, false
+		return text
+	}
+	for i := 0; i < len(lines); i++ {
+		t := strings.TrimSpace(lines[i])
+		switch {
+		case strings.HasPrefix(t, "#!"), strings.HasPrefix(t, "//go:build"), strings.HasPrefix(t, "// +build"):
+			continue
+		case strings.HasPrefix(t, `"""`) && len(block) == 0:
+			// Python module docstring
+			body := strings.TrimPrefix(t, `"""`)
+			for {
+				if end, ok := strings.CutSuffix(body, `"""`); ok {
+					block = appendSummary(block, end)
+					break
+				}
+				block = appendSummary(block, body)
+				if i++; i == len(lines) {
+					break
+				}
+				body = strings.TrimSpace(lines[i])
+			}
+			return flush()
+		case strings.HasPrefix(t, "//"), strings.HasPrefix(t, "#"), strings.HasPrefix(t, "/*"), strings.HasPrefix(t, "*"), strings.HasPrefix(t, "--"):
+			text := strings.TrimSpace(strings.TrimLeft(t, "/*#-"))
+			text = strings.TrimSpace(strings.TrimSuffix(text, "*/"))
+			license = license || isLicenseLine(text)
+			block = appendSummary(block, text)
+		case t == "" || strings.HasPrefix(t, "package "):
+			// A blank line or package clause ends a comment block; the
+			// package doc sits right above the clause.
+			if text := flush(); text != "" {
+				return text
+			}
+		default:
+			return flush()
+		}
+	}
+	return flush()
+}
+
+func appendSummary(out []string, text string) []string {
+	if text == "" {
+		return out
+	}
+	return append(out, text)
+}
+
+func isLicenseLine(text string) bool {
+	lower := strings.ToLower(text)
+	return strings.Contains(lower, "copyright") || strings.Contains(lower, "spdx-license") || strings.Contains(lower, "license")
+}
@@ -185,6 +185,11 @@ type NonInteractiveConfig struct {
 
 	// OutputFlag is the flag for structured output (e.g., "--json", "--output-format json").
 	OutputFlag string `json:"output_flag,omitempty"`
+
+	// ReadOnlyFlag makes a non-interactive run unable to write files or run
+	// mutating commands (e.g., "--sandbox read-only" for codex). gt ask
+	// refuses agents without one.
+	ReadOnlyFlag string `json:"read_only_flag,omitempty"`
 }
 
 // AgentRegistry contains all known agent presets.
@@ -263,8 +268,9 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		SupportsHooks:       false, // Use env/files instead
 		SupportsForkSession: false,
 		NonInteractive: &NonInteractiveConfig{
-			Subcommand: "exec",
-			OutputFlag: "--json",
+			Subcommand:   "exec",
+			OutputFlag:   "--json",
+			ReadOnlyFlag: "--sandbox read-only",
 		},
 		// Runtime defaults
 		PromptMode:       "none",
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// Build caches supported by BuildCacheConfig.Caches.
+const (
+	BuildCacheGo     = "go"     // GOMODCACHE and GOCACHE
+	BuildCachePnpm   = "pnpm"   // pnpm content-addressed store (node_modules are hard links into it)
+	BuildCacheNpm    = "npm"    // npm download cache
+	BuildCacheCcache = "ccache" // ccache object cache
+	BuildCachePip    = "pip"    // pip wheel/download cache
+)
+
+// ErrInvalidBuildCache indicates an invalid build_cache configuration.
+var ErrInvalidBuildCache = errors.New("invalid build_cache config")
+
+// buildCacheEnv maps each cache to the variables that point its tool at a
+// subdirectory of the shared cache directory.
+var buildCacheEnv = map[string]map[string]string{
+	BuildCacheGo:     {"GOMODCACHE": "go/mod", "GOCACHE": "go/build"},
+	BuildCachePnpm:   {"npm_config_store_dir": "pnpm-store"},
+	BuildCacheNpm:    {"npm_config_cache": "npm"},
+	BuildCacheCcache: {"CCACHE_DIR": "ccache"},
+	BuildCachePip:    {"PIP_CACHE_DIR": "pip"},
+}
+
+// BuildCacheKinds returns the supported cache names, sorted.
+func BuildCacheKinds() []string {
+	kinds := make([]string, 0, len(buildCacheEnv))
+	for k := range buildCacheEnv {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// ResolveBuildCache loads the build_cache section of a rig's settings.
+// Returns nil if the rig has no settings or no build_cache section.
+func ResolveBuildCache(rigPath string) *BuildCacheConfig {
+	if rigPath == "" {
+		return nil
+	}
+	rigSettings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil || rigSettings == nil {
+		return nil
+	}
+	return rigSettings.BuildCache
+}
+
+// validateBuildCacheConfig validates a BuildCacheConfig.
+func validateBuildCacheConfig(c *BuildCacheConfig) error {
+	for _, kind := range c.Caches {
+		if _, ok := buildCacheEnv[kind]; !ok {
+			return fmt.Errorf("%w: unknown cache %q (supported: %v)", ErrInvalidBuildCache, kind, BuildCacheKinds())
+		}
+	}
+	return nil
+}
+
+// Enabled returns the caches to share; all supported caches if none are listed.
+func (c *BuildCacheConfig) Enabled() []string {
+	if c == nil {
+		return nil
+	}
+	if len(c.Caches) == 0 {
+		return BuildCacheKinds()
+	}
+	kinds := append([]string(nil), c.Caches...)
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Root returns the shared cache directory for the rig at rigPath:
+// Dir expanded (relative to the rig), or <rig>/.cache by default.
+func (c *BuildCacheConfig) Root(rigPath string) string {
+	if c == nil || c.Dir == "" {
+		return filepath.Join(rigPath, ".cache")
+	}
+	dir := expandEnvPath(c.Dir)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(rigPath, dir)
+	}
+	return dir
+}
+
+// Dirs returns each enabled cache's directories, keyed by cache name.
+func (c *BuildCacheConfig) Dirs(rigPath string) map[string][]string {
+	if c == nil {
+		return nil
+	}
+	root := c.Root(rigPath)
+	dirs := make(map[string][]string)
+	for _, kind := range c.Enabled() {
+		for _, sub := range buildCacheEnv[kind] {
+			dirs[kind] = append(dirs[kind], filepath.Join(root, sub))
+		}
+		sort.Strings(dirs[kind])
+	}
+	return dirs
+}
+
+// ApplyEnv points the enabled caches at the rig's shared cache directory.
+// Keys already present in env are left alone, so explicit env.vars win.
+// ccache also gets CCACHE_BASEDIR set to the rig so objects compiled in
+// one worktree hit from another.
+func (c *BuildCacheConfig) ApplyEnv(env map[string]string, rigPath string) {
+	if c == nil {
+		return
+	}
+	root := c.Root(rigPath)
+	set := func(k, v string) {
+		if _, ok := env[k]; !ok {
+			env[k] = v
+		}
+	}
+	for _, kind := range c.Enabled() {
+		for k, sub := range buildCacheEnv[kind] {
+			set(k, filepath.Join(root, sub))
+		}
+		if kind == BuildCacheCcache {
+			set("CCACHE_BASEDIR", rigPath)
+		}
+	}
+}
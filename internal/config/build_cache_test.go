@@ -0,0 +1,79 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildStartupCommand_BuildCache(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	rigSettings := NewRigSettings()
+	rigSettings.Env = &RigEnvConfig{Vars: map[string]string{"GOCACHE": "/fast/gocache"}}
+	rigSettings.BuildCache = &BuildCacheConfig{Caches: []string{BuildCacheGo, BuildCacheCcache}}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), rigSettings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	cmd := BuildStartupCommand(map[string]string{"GT_ROLE": "testrig/polecats/Toast"}, rigPath, "")
+
+	cacheRoot := filepath.Join(rigPath, ".cache")
+	for _, want := range []string{
+		"GOMODCACHE=" + filepath.Join(cacheRoot, "go", "mod"),
+		"GOCACHE=/fast/gocache", // explicit env.vars win
+		"CCACHE_DIR=" + filepath.Join(cacheRoot, "ccache"),
+		"CCACHE_BASEDIR=" + rigPath,
+	} {
+		if !strings.Contains(cmd, want) {
+			t.Errorf("expected %s in command, got: %q", want, cmd)
+		}
+	}
+	if strings.Contains(cmd, "npm_config_store_dir") {
+		t.Errorf("pnpm cache not enabled, got: %q", cmd)
+	}
+}
+
+func TestBuildCacheConfig_Root(t *testing.T) {
+	t.Setenv("CACHE_HOME", "/srv/cache")
+	rigPath := "/town/rig"
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{"", "/town/rig/.cache"},
+		{"../.cache", "/town/.cache"},
+		{"$CACHE_HOME/gt", "/srv/cache/gt"},
+	}
+	for _, tt := range tests {
+		cfg := &BuildCacheConfig{Dir: tt.dir}
+		if got := cfg.Root(rigPath); got != filepath.FromSlash(tt.want) {
+			t.Errorf("Root(dir=%q) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestBuildCacheConfig_Nil(t *testing.T) {
+	var c *BuildCacheConfig
+	env := map[string]string{}
+	c.ApplyEnv(env, "/rig")
+	if len(env) != 0 || c.Enabled() != nil || c.Dirs("/rig") != nil {
+		t.Error("nil BuildCacheConfig should be a no-op")
+	}
+	if got := (&BuildCacheConfig{}).Enabled(); strings.Join(got, ",") != "ccache,go,npm,pip,pnpm" {
+		t.Errorf("empty caches should enable all, got %v", got)
+	}
+}
+
+func TestValidateBuildCacheConfig(t *testing.T) {
+	if err := validateBuildCacheConfig(&BuildCacheConfig{Caches: []string{"go", "pnpm"}}); err != nil {
+		t.Errorf("valid config: %v", err)
+	}
+	err := validateBuildCacheConfig(&BuildCacheConfig{Caches: []string{"gradle"}})
+	if !errors.Is(err, ErrInvalidBuildCache) {
+		t.Errorf("unknown cache error = %v, want ErrInvalidBuildCache", err)
+	}
+}
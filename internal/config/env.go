@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/provenance"
 )
 
 // AgentEnvConfig specifies the configuration for generating agent environment variables.
@@ -265,6 +266,21 @@ func AgentEnv(cfg AgentEnvConfig) map[string]string {
 		}
 	}
 
+	// Commit provenance: sign with the identity's key and route commits
+	// through the stamping hooks. Injected as GIT_CONFIG_* so it covers
+	// plain git commit without touching any clone's config.
+	if cfg.TownRoot != "" && env["BD_ACTOR"] != "" {
+		if prov := LoadProvenance(cfg.TownRoot); prov != nil {
+			hooksDir := provenance.HooksDir(cfg.TownRoot)
+			if prov.Trailers && provenance.EnsureHooks(hooksDir) != nil {
+				hooksDir = ""
+			}
+			for k, v := range provenance.GitConfigEnv(prov.GitConfig(env["BD_ACTOR"], hooksDir)) {
+				env[k] = v
+			}
+		}
+	}
+
 	// Inject Dolt server port so agents' direct bd invocations connect to
 	// gt's central server instead of auto-starting rogue per-rig servers.
 	// Without this, bd falls back to its own discovery (.beads/dolt-server.port
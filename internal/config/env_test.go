@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/provenance"
 )
 
 func TestAgentEnv_Mayor(t *testing.T) {
@@ -1219,3 +1221,28 @@ func TestAgentEnv_NoDoltPortWithoutConfig(t *testing.T) {
 	assertNotSet(t, env, "GT_DOLT_PORT")
 	assertNotSet(t, env, "BEADS_DOLT_PORT")
 }
+
+func TestAgentEnv_Provenance(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	settings := NewTownSettings()
+	settings.Provenance = &provenance.Config{
+		Trailers: true,
+		Signing:  &provenance.SigningConfig{Key: "town.pub", Keys: map[string]string{"myrig/polecats/*": "polecats.pub"}},
+	}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+
+	env := AgentEnv(AgentEnvConfig{Role: "polecat", Rig: "myrig", AgentName: "Toast", TownRoot: townRoot})
+	hooksDir := provenance.HooksDir(townRoot)
+	assertEnv(t, env, "GIT_CONFIG_COUNT", "4")
+	assertEnv(t, env, "GIT_CONFIG_VALUE_0", hooksDir)
+	assertEnv(t, env, "GIT_CONFIG_VALUE_3", "polecats.pub")
+	if _, err := os.Stat(filepath.Join(hooksDir, "commit-msg")); err != nil {
+		t.Errorf("stamping hook not installed: %v", err)
+	}
+
+	env = AgentEnv(AgentEnvConfig{Role: "mayor", TownRoot: t.TempDir()})
+	assertNotSet(t, env, "GIT_CONFIG_COUNT")
+}
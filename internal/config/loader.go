@@ -8,11 +8,20 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/atrest"
+	"github.com/steveyegge/gastown/internal/confirm"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/injectguard"
+	"github.com/steveyegge/gastown/internal/notification"
+	"github.com/steveyegge/gastown/internal/provenance"
+	"github.com/steveyegge/gastown/internal/quiet"
+	"github.com/steveyegge/gastown/internal/reslimit"
+	"github.com/steveyegge/gastown/internal/shard"
 )
 
 // resolveConfigMu serializes agent config resolution across all callers.
@@ -219,6 +228,49 @@ func validateRigSettings(c *RigSettings) error {
 			return err
 		}
 	}
+	if c.Env != nil {
+		if err := validateRigEnvConfig(c.Env); err != nil {
+			return err
+		}
+	}
+	if c.BuildCache != nil {
+		if err := validateBuildCacheConfig(c.BuildCache); err != nil {
+			return err
+		}
+	}
+	if err := c.StaticAnalysis.Validate(); err != nil {
+		return fmt.Errorf("static_analysis: %w", err)
+	}
+	if err := c.PermissionPolicy.Validate(); err != nil {
+		return fmt.Errorf("permission_policy: %w", err)
+	}
+	if err := c.QuietHours.Validate(); err != nil {
+		return fmt.Errorf("quiet_hours: %w", err)
+	}
+	if err := c.DiskQuota.Validate(); err != nil {
+		return fmt.Errorf("disk_quota: %w", err)
+	}
+	if err := c.VerifyDone.Validate(); err != nil {
+		return fmt.Errorf("verify_done: %w", err)
+	}
+	if err := c.DepUpdates.Validate(); err != nil {
+		return fmt.Errorf("dep_updates: %w", err)
+	}
+	if err := c.Release.Validate(); err != nil {
+		return fmt.Errorf("release: %w", err)
+	}
+	if err := c.Changelog.Validate(); err != nil {
+		return fmt.Errorf("changelog: %w", err)
+	}
+	if err := c.Capacity.Validate(); err != nil {
+		return fmt.Errorf("capacity: %w", err)
+	}
+	if err := c.CodeIndex.Validate(); err != nil {
+		return fmt.Errorf("code_index: %w", err)
+	}
+	if err := c.ResourceLimits.Validate(); err != nil {
+		return fmt.Errorf("resource_limits: %w", err)
+	}
 	return nil
 }
 
@@ -931,6 +983,18 @@ func validateMessagingConfig(c *MessagingConfig) error {
 		}
 	}
 
+	// Validate mail rules have a name and at least one action
+	for mailbox, rules := range c.Rules {
+		for i, rule := range rules {
+			if rule.Name == "" {
+				return fmt.Errorf("%w: mail rule %d for '%s' has no name", ErrMissingField, i+1, mailbox)
+			}
+			if !rule.HasAction() {
+				return fmt.Errorf("%w: mail rule '%s' has no action (folder, ack, forward, hook or stop)", ErrMissingField, rule.Name)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -978,6 +1042,92 @@ func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
 	return &settings, nil
 }
 
+// LoadProvenance returns the town's commit provenance settings, or nil when
+// they are off or invalid (gt provenance status reports invalid ones).
+func LoadProvenance(townRoot string) *provenance.Config {
+	ts, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil || !ts.Provenance.Enabled() || ts.Provenance.Validate() != nil {
+		return nil
+	}
+	return ts.Provenance
+}
+
+// LoadEncryption returns the town's at-rest encryption settings, or nil
+// when there are none. Unlike other town sections an invalid one is still
+// returned: sealing then fails loudly instead of falling back to plaintext.
+func LoadEncryption(townRoot string) *atrest.Config {
+	ts, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	return ts.Encryption
+}
+
+// LoadMayorShards returns the town's mayor shards, or nil when the town runs
+// a single Mayor. An invalid shard config is treated as unsharded so mail
+// still reaches the primary Mayor; gt mayor shards reports the error.
+func LoadMayorShards(townRoot string) *shard.Config {
+	ts, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil || !ts.Mayors.Enabled() || ts.Mayors.Validate() != nil {
+		return nil
+	}
+	return ts.Mayors
+}
+
+// LoadQuietHours returns the quiet hours that apply to a rig: the rig's own
+// quiet_hours when set, otherwise the town's. An empty rig name returns the
+// town's. Returns nil when none are configured or the config is invalid
+// (gt quiet reports the error).
+func LoadQuietHours(townRoot, rigName string) *quiet.Config {
+	if rigName != "" {
+		settings, err := LoadRigSettings(RigSettingsPath(filepath.Join(townRoot, rigName)))
+		if err == nil && settings.QuietHours != nil {
+			return settings.QuietHours
+		}
+	}
+	ts, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil || ts.QuietHours.Validate() != nil {
+		return nil
+	}
+	return ts.QuietHours
+}
+
+// LoadNotifications returns the town's notification settings, or nil when
+// none are configured or the config is invalid (gt notifications reports
+// the error).
+func LoadNotifications(townRoot string) *notification.Config {
+	ts, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil || ts.Notifications.Validate() != nil {
+		return nil
+	}
+	return ts.Notifications
+}
+
+// LoadContentGuard returns the town's content guard settings. nil (the
+// built-in defaults) when none are configured or the config is invalid,
+// so a broken config never turns the guard off.
+func LoadContentGuard(townRoot string) *injectguard.Config {
+	ts, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil || ts.ContentGuard.Validate() != nil {
+		return nil
+	}
+	return ts.ContentGuard
+}
+
+// LoadConfirmations returns the town's confirmation policies. Unlike most
+// settings, an invalid config is an error rather than a fallback to the
+// defaults, which may be weaker than what the town asked for.
+func LoadConfirmations(townRoot string) (confirm.Config, error) {
+	ts, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	if err := ts.Confirmations.Validate(); err != nil {
+		return nil, fmt.Errorf("confirmations: %w", err)
+	}
+	return ts.Confirmations, nil
+}
+
 // SaveTownSettings saves town settings to a file.
 func SaveTownSettings(path string, settings *TownSettings) error {
 	if settings.Type != "town-settings" && settings.Type != "" {
@@ -2028,6 +2178,11 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 	// so we resolve process names from both agent name and actual command.
 	processNames := ResolveProcessNames(rc.ResolvedAgent, rc.Command)
 	resolvedEnv["GT_PROCESS_NAMES"] = strings.Join(processNames, ",")
+	// Rig-declared environment (vars, tool pins) fills in anything not
+	// already set; identity vars and agent-specific env take precedence.
+	rigEnv := ResolveRigEnv(rigPath)
+	rigEnv.ApplyVars(resolvedEnv)
+	ResolveBuildCache(rigPath).ApplyEnv(resolvedEnv, rigPath)
 	// Merge agent-specific env vars (e.g., OPENCODE_PERMISSION for yolo mode)
 	for k, v := range rc.Env {
 		resolvedEnv[k] = v
@@ -2043,6 +2198,9 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 
 	// Sort for deterministic output
 	sort.Strings(exports)
+	if pathExport := rigEnv.pathExport(); pathExport != "" {
+		exports = append(exports, pathExport)
+	}
 
 	var cmd string
 	if len(exports) > 0 {
@@ -2053,11 +2211,21 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 		cmd = "exec env " + strings.Join(exports, " ") + " "
 	}
 
+	// Confine the session to the rig's resource limits. Outermost, so the
+	// exec wrapper and everything the agent runs count against them.
+	if limits := resolveResourceWrapper(rigPath, envVars); len(limits) > 0 {
+		cmd += strings.Join(limits, " ") + " "
+	}
+
 	// Insert exec wrapper between env vars and agent command if configured.
 	// Example: exec env VAR=val ... exitbox run --profile=foo -- claude ...
 	if len(rc.ExecWrapper) > 0 {
 		cmd += strings.Join(rc.ExecWrapper, " ") + " "
 	}
+	// Activate pinned tool versions inside the wrapper, next to the agent.
+	if toolWrapper := rigEnv.ToolWrapper(); len(toolWrapper) > 0 {
+		cmd += strings.Join(toolWrapper, " ") + " "
+	}
 
 	// Add runtime command
 	if prompt != "" {
@@ -2218,6 +2386,11 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 	// Set GT_PROCESS_NAMES for accurate liveness detection of custom agents.
 	processNamesOverride := ResolveProcessNames(agentForProcess, rc.Command)
 	resolvedEnv["GT_PROCESS_NAMES"] = strings.Join(processNamesOverride, ",")
+	// Rig-declared environment (vars, tool pins) fills in anything not
+	// already set; identity vars and agent-specific env take precedence.
+	rigEnv := ResolveRigEnv(rigPath)
+	rigEnv.ApplyVars(resolvedEnv)
+	ResolveBuildCache(rigPath).ApplyEnv(resolvedEnv, rigPath)
 	// Merge agent-specific env vars (e.g., OPENCODE_PERMISSION for yolo mode)
 	for k, v := range rc.Env {
 		resolvedEnv[k] = v
@@ -2231,6 +2404,9 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 		exports = append(exports, fmt.Sprintf("%s=%s", k, ShellQuote(v)))
 	}
 	sort.Strings(exports)
+	if pathExport := rigEnv.pathExport(); pathExport != "" {
+		exports = append(exports, pathExport)
+	}
 
 	var cmd string
 	if len(exports) > 0 {
@@ -2241,10 +2417,20 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 		cmd = "exec env " + strings.Join(exports, " ") + " "
 	}
 
+	// Confine the session to the rig's resource limits. Outermost, so the
+	// exec wrapper and everything the agent runs count against them.
+	if limits := resolveResourceWrapper(rigPath, envVars); len(limits) > 0 {
+		cmd += strings.Join(limits, " ") + " "
+	}
+
 	// Insert exec wrapper between env vars and agent command if configured.
 	if len(rc.ExecWrapper) > 0 {
 		cmd += strings.Join(rc.ExecWrapper, " ") + " "
 	}
+	// Activate pinned tool versions inside the wrapper, next to the agent.
+	if toolWrapper := rigEnv.ToolWrapper(); len(toolWrapper) > 0 {
+		cmd += strings.Join(toolWrapper, " ") + " "
+	}
 
 	if prompt != "" {
 		cmd += rc.BuildCommandWithPrompt(prompt)
@@ -2355,6 +2541,13 @@ func BuildCrewStartupCommandWithAgentOverride(rigName, crewName, rigPath, prompt
 	return BuildStartupCommandWithAgentOverride(envVars, rigPath, prompt, agentOverride)
 }
 
+// ResolveExecWrapper returns the rig's exec_wrapper (sandbox/container prefix),
+// or nil if none is configured. Used to run ad-hoc commands the same way the
+// rig's agents are run.
+func ResolveExecWrapper(rigPath string) []string {
+	return resolveExecWrapper(rigPath)
+}
+
 // resolveExecWrapper loads the exec_wrapper from rig settings.
 // ExecWrapper is a deployment-level setting (sandbox/container) that wraps the agent binary.
 // It is independent of agent choice — exitbox wraps Claude, Codex, or any other runtime.
@@ -2369,6 +2562,29 @@ func resolveExecWrapper(rigPath string) []string {
 	return nil
 }
 
+// resolveResourceWrapper returns the prefix that starts a rig agent in a
+// resource-limited scope, or nil when the rig sets no limits for its role
+// or scopes aren't available on this host.
+func resolveResourceWrapper(rigPath string, envVars map[string]string) []string {
+	if rigPath == "" {
+		return nil
+	}
+	rigSettings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil || rigSettings == nil {
+		return nil
+	}
+	role := ExtractSimpleRole(envVars["GT_ROLE"])
+	if !rigSettings.ResourceLimits.AppliesTo(role) || !reslimit.Available() {
+		return nil
+	}
+	agent := role
+	if name := envVars["GT_POLECAT"] + envVars["GT_CREW"]; name != "" {
+		agent = name
+	}
+	unit := reslimit.UnitName(filepath.Base(rigPath), agent, strconv.FormatInt(time.Now().Unix(), 10))
+	return rigSettings.ResourceLimits.Wrapper(unit)
+}
+
 // ExpectedPaneCommands returns tmux pane command names that indicate the runtime is running.
 // Claude can report as "node" (older versions) or "claude" (newer versions).
 // Other runtimes typically report their executable name.
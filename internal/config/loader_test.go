@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/shard"
 )
 
 // skipIfAgentBinaryMissing skips the test if any of the specified agent binaries
@@ -795,6 +796,36 @@ func TestMessagingConfigValidation(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid mail rules",
+			config: &MessagingConfig{
+				Version: 1,
+				Rules: map[string][]MailRule{
+					"mayor/": {{Name: "merges", Subject: "MERGED*", Folder: "merges", Ack: true}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "mail rule without name",
+			config: &MessagingConfig{
+				Version: 1,
+				Rules: map[string][]MailRule{
+					"mayor/": {{Subject: "MERGED*", Ack: true}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "mail rule without action",
+			config: &MessagingConfig{
+				Version: 1,
+				Rules: map[string][]MailRule{
+					"mayor/": {{Name: "noop", Subject: "MERGED*"}},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -5137,3 +5168,30 @@ func TestBuildStartupCommandWithAgentOverride_ExecWrapper(t *testing.T) {
 		t.Errorf("expected wrapper immediately before claude command, got: %q", cmd)
 	}
 }
+
+func TestLoadMayorShards(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	if got := LoadMayorShards(townRoot); got != nil {
+		t.Fatalf("LoadMayorShards with no settings = %+v, want nil", got)
+	}
+
+	settings := NewTownSettings()
+	settings.Mayors = &shard.Config{Shards: []shard.Shard{{Name: "web", Rigs: []string{"frontend"}}}}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+	got := LoadMayorShards(townRoot)
+	if got == nil || got.MayorFor("frontend/witness") != "mayor/web" {
+		t.Fatalf("LoadMayorShards = %+v, want web shard owning frontend", got)
+	}
+
+	// An invalid config falls back to a single Mayor.
+	settings.Mayors.Shards = append(settings.Mayors.Shards, shard.Shard{Name: "dup", Rigs: []string{"frontend"}})
+	if err := SaveTownSettings(TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+	if got := LoadMayorShards(townRoot); got != nil {
+		t.Errorf("LoadMayorShards with invalid shards = %+v, want nil", got)
+	}
+}
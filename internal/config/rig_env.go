@@ -0,0 +1,159 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Tool managers supported by RigEnvConfig.ToolManager.
+const (
+	ToolManagerMise = "mise"
+	ToolManagerAsdf = "asdf"
+)
+
+// ErrInvalidRigEnv indicates an invalid rig env configuration.
+var ErrInvalidRigEnv = errors.New("invalid env config")
+
+var envNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ResolveRigEnv loads the env section of a rig's settings.
+// Returns nil if the rig has no settings or no env section.
+func ResolveRigEnv(rigPath string) *RigEnvConfig {
+	if rigPath == "" {
+		return nil
+	}
+	rigSettings, err := LoadRigSettings(RigSettingsPath(rigPath))
+	if err != nil || rigSettings == nil {
+		return nil
+	}
+	return rigSettings.Env
+}
+
+// validateRigEnvConfig validates a RigEnvConfig.
+func validateRigEnvConfig(c *RigEnvConfig) error {
+	for name := range c.Vars {
+		if !envNameRe.MatchString(name) {
+			return fmt.Errorf("%w: bad variable name %q", ErrInvalidRigEnv, name)
+		}
+		if name == "PATH" {
+			return fmt.Errorf("%w: set PATH additions with \"path\", not \"vars\"", ErrInvalidRigEnv)
+		}
+	}
+	if c.ToolManager != "" && c.ToolManager != ToolManagerMise && c.ToolManager != ToolManagerAsdf {
+		return fmt.Errorf("%w: tool_manager %q, want %q or %q",
+			ErrInvalidRigEnv, c.ToolManager, ToolManagerMise, ToolManagerAsdf)
+	}
+	for tool, version := range c.Tools {
+		if tool == "" || version == "" || strings.ContainsAny(tool+version, " \t@") {
+			return fmt.Errorf("%w: bad tool pin %q=%q", ErrInvalidRigEnv, tool, version)
+		}
+	}
+	return nil
+}
+
+// toolManager returns the configured tool manager, defaulting to mise.
+func (c *RigEnvConfig) toolManager() string {
+	if c.ToolManager == "" {
+		return ToolManagerMise
+	}
+	return c.ToolManager
+}
+
+// ApplyVars adds the rig's variables (and asdf version pins) to env.
+// Keys already present in env are left alone so identity variables win.
+func (c *RigEnvConfig) ApplyVars(env map[string]string) {
+	if c == nil {
+		return
+	}
+	for k, v := range c.Vars {
+		if _, ok := env[k]; !ok {
+			env[k] = v
+		}
+	}
+	if c.toolManager() == ToolManagerAsdf {
+		for tool, version := range c.Tools {
+			key := "ASDF_" + strings.ToUpper(strings.ReplaceAll(tool, "-", "_")) + "_VERSION"
+			if _, ok := env[key]; !ok {
+				env[key] = version
+			}
+		}
+	}
+}
+
+// PathEntries returns the directories to prepend to PATH, expanded.
+func (c *RigEnvConfig) PathEntries() []string {
+	if c == nil {
+		return nil
+	}
+	var entries []string
+	for _, p := range c.Path {
+		if p = expandEnvPath(p); p != "" {
+			entries = append(entries, p)
+		}
+	}
+	if len(c.Tools) > 0 && c.toolManager() == ToolManagerAsdf {
+		dataDir := os.Getenv("ASDF_DATA_DIR")
+		if dataDir == "" {
+			dataDir = expandEnvPath("~/.asdf")
+		}
+		entries = append(entries, filepath.Join(dataDir, "shims"))
+	}
+	return entries
+}
+
+// ExecPath returns current with the rig's PATH entries prepended.
+func (c *RigEnvConfig) ExecPath(current string) string {
+	entries := c.PathEntries()
+	if len(entries) == 0 {
+		return current
+	}
+	if current == "" {
+		return strings.Join(entries, string(os.PathListSeparator))
+	}
+	return strings.Join(entries, string(os.PathListSeparator)) + string(os.PathListSeparator) + current
+}
+
+// pathExport returns a PATH=... assignment for startup commands that keeps
+// the session's own PATH after the rig's entries, or "" if there are none.
+func (c *RigEnvConfig) pathExport() string {
+	entries := c.PathEntries()
+	if len(entries) == 0 {
+		return ""
+	}
+	return "PATH=" + ShellQuote(strings.Join(entries, ":")+":") + `"$PATH"`
+}
+
+// ToolWrapper returns the command prefix that activates pinned tool versions,
+// e.g. ["mise", "exec", "node@20", "--"], or nil when none is needed.
+func (c *RigEnvConfig) ToolWrapper() []string {
+	if c == nil || len(c.Tools) == 0 || c.toolManager() != ToolManagerMise {
+		return nil
+	}
+	tools := make([]string, 0, len(c.Tools))
+	for tool := range c.Tools {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+
+	wrapper := []string{"mise", "exec"}
+	for _, tool := range tools {
+		wrapper = append(wrapper, tool+"@"+c.Tools[tool])
+	}
+	return append(wrapper, "--")
+}
+
+// expandEnvPath expands $VAR references and a leading ~.
+func expandEnvPath(p string) string {
+	p = os.ExpandEnv(strings.TrimSpace(p))
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			p = home + p[1:]
+		}
+	}
+	return p
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildStartupCommand_RigEnv(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	rigSettings := NewRigSettings()
+	rigSettings.Runtime = &RuntimeConfig{
+		Command:     "claude",
+		ExecWrapper: []string{"exitbox", "run", "--"},
+	}
+	rigSettings.Env = &RigEnvConfig{
+		Vars:  map[string]string{"GOFLAGS": "-mod=mod", "GT_ROLE": "hijack"},
+		Path:  []string{"/opt/node20/bin"},
+		Tools: map[string]string{"node": "20.11.0", "go": "1.22"},
+	}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), rigSettings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	cmd := BuildStartupCommand(map[string]string{"GT_ROLE": "testrig/polecats/Toast"}, rigPath, "")
+
+	if !strings.Contains(cmd, "GOFLAGS=-mod=mod") {
+		t.Errorf("expected rig var in command, got: %q", cmd)
+	}
+	if !strings.Contains(cmd, "GT_ROLE=testrig/polecats/Toast") || strings.Contains(cmd, "hijack") {
+		t.Errorf("rig vars must not override identity, got: %q", cmd)
+	}
+	if !strings.Contains(cmd, `PATH=/opt/node20/bin:"$PATH"`) {
+		t.Errorf("expected PATH prepend, got: %q", cmd)
+	}
+	if !strings.Contains(cmd, "exitbox run -- mise exec go@1.22 node@20.11.0 -- claude") {
+		t.Errorf("expected tool wrapper between exec wrapper and agent, got: %q", cmd)
+	}
+}
+
+func TestBuildStartupCommandWithAgentOverride_RigEnv(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	rigSettings := NewRigSettings()
+	rigSettings.Env = &RigEnvConfig{Vars: map[string]string{"NVM_DIR": "/home/x/.nvm"}}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), rigSettings); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	cmd, err := BuildStartupCommandWithAgentOverride(map[string]string{"GT_ROLE": "polecat"}, rigPath, "", "")
+	if err != nil {
+		t.Fatalf("BuildStartupCommandWithAgentOverride: %v", err)
+	}
+	if !strings.Contains(cmd, "NVM_DIR=/home/x/.nvm") {
+		t.Errorf("expected rig var in command, got: %q", cmd)
+	}
+	if strings.Contains(cmd, "PATH=") || strings.Contains(cmd, "mise exec") {
+		t.Errorf("no PATH or tool wrapper expected, got: %q", cmd)
+	}
+}
+
+func TestRigEnvConfig_Asdf(t *testing.T) {
+	t.Setenv("ASDF_DATA_DIR", "/data/asdf")
+	c := &RigEnvConfig{
+		ToolManager: ToolManagerAsdf,
+		Tools:       map[string]string{"nodejs": "20.11.0", "golang-ci": "1.5"},
+		Path:        []string{"$ASDF_DATA_DIR/bin"},
+	}
+
+	env := map[string]string{"ASDF_NODEJS_VERSION": "18.0.0"}
+	c.ApplyVars(env)
+	if env["ASDF_NODEJS_VERSION"] != "18.0.0" {
+		t.Errorf("existing version pin overridden: %q", env["ASDF_NODEJS_VERSION"])
+	}
+	if env["ASDF_GOLANG_CI_VERSION"] != "1.5" {
+		t.Errorf("ASDF_GOLANG_CI_VERSION = %q, want 1.5", env["ASDF_GOLANG_CI_VERSION"])
+	}
+	if w := c.ToolWrapper(); w != nil {
+		t.Errorf("asdf should not wrap the command, got %v", w)
+	}
+	want := []string{"/data/asdf/bin", "/data/asdf/shims"}
+	if got := c.PathEntries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("PathEntries() = %v, want %v", got, want)
+	}
+	if got := c.ExecPath("/usr/bin"); got != "/data/asdf/bin:/data/asdf/shims:/usr/bin" {
+		t.Errorf("ExecPath() = %q", got)
+	}
+}
+
+func TestRigEnvConfig_Nil(t *testing.T) {
+	var c *RigEnvConfig
+	env := map[string]string{}
+	c.ApplyVars(env)
+	if len(env) != 0 || c.ToolWrapper() != nil || c.PathEntries() != nil || c.ExecPath("/bin") != "/bin" {
+		t.Error("nil RigEnvConfig should be a no-op")
+	}
+	if ResolveRigEnv("") != nil {
+		t.Error("ResolveRigEnv(\"\") should be nil")
+	}
+}
+
+func TestValidateRigEnvConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     RigEnvConfig
+		wantErr bool
+	}{
+		{"valid", RigEnvConfig{Vars: map[string]string{"GOPATH": "/go"}, Tools: map[string]string{"node": "20"}}, false},
+		{"bad var name", RigEnvConfig{Vars: map[string]string{"1BAD": "x"}}, true},
+		{"PATH in vars", RigEnvConfig{Vars: map[string]string{"PATH": "/bin"}}, true},
+		{"unknown manager", RigEnvConfig{ToolManager: "nvm"}, true},
+		{"bad tool pin", RigEnvConfig{Tools: map[string]string{"node": "20 --force"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRigEnvConfig(&tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateRigEnvConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidRigEnv) {
+				t.Errorf("error %v should wrap ErrInvalidRigEnv", err)
+			}
+		})
+	}
+}
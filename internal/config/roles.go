@@ -18,12 +18,24 @@ var defaultRolesFS embed.FS
 // RoleDefinition contains all configuration for a role type.
 // This replaces the role bead system with config files.
 type RoleDefinition struct {
-	// Role is the role identifier (mayor, deacon, witness, refinery, polecat, crew, dog).
+	// Role is the role identifier (mayor, deacon, witness, refinery, polecat, crew, dog,
+	// or a custom role defined in <town>/roles/).
 	Role string `toml:"role"`
 
 	// Scope is "town" or "rig" - determines where the agent runs.
 	Scope string `toml:"scope"`
 
+	// Description is a one-line summary shown by gt role list (custom roles).
+	Description string `toml:"description,omitempty"`
+
+	// Permissions caps the commands a custom role's agent may run: "read",
+	// "nudge", "work" or "admin" (see internal/rbac). Empty means no cap.
+	Permissions string `toml:"permissions,omitempty"`
+
+	// Autonomous custom roles execute hooked work immediately on startup,
+	// like polecats; interactive ones wait for instructions, like the mayor.
+	Autonomous bool `toml:"autonomous,omitempty"`
+
 	// Session contains tmux session configuration.
 	Session RoleSessionConfig `toml:"session"`
 
@@ -138,9 +150,14 @@ func isValidRoleName(name string) bool {
 //
 // Each layer merges with (not replaces) the previous. Users only specify
 // fields they want to change.
+//
+// Names that aren't built-in resolve to custom roles (see LoadCustomRole).
 func LoadRoleDefinition(townRoot, rigPath, roleName string) (*RoleDefinition, error) {
 	// Validate role name
 	if !isValidRoleName(roleName) {
+		if townRoot != "" && IsCustomRole(townRoot, roleName) {
+			return LoadCustomRole(townRoot, roleName)
+		}
 		return nil, fmt.Errorf("unknown role %q - valid roles: %v", roleName, AllRoles())
 	}
 
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/rbac"
+)
+
+// Custom roles extend the built-in cast (mayor, witness, polecat, ...) with
+// town-defined agents such as an architect or a release manager. A custom
+// role is a <town>/roles/<name>.toml file whose name isn't a built-in role
+// (built-in names in that directory are overrides, not new roles). Its
+// briefing is a markdown file next to it, named by prompt_template.
+//
+// Addresses: a town-scoped role is "<name>/" in mail and "<name>" as a sling
+// target; a rig-scoped role is "<rig>/<name>" in both.
+
+// reservedRoleNames can't be used for custom roles: they are built-in roles
+// or address segments with a fixed meaning.
+var reservedRoleNames = map[string]bool{
+	"boot": true, "dogs": true, "polecats": true, "overseer": true, "hq": true,
+	"rig": true, "town": true, "roles": true, "settings": true,
+}
+
+var customRoleName = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// CustomRolesDir returns the directory holding role overrides and custom roles.
+func CustomRolesDir(townRoot string) string {
+	return filepath.Join(townRoot, "roles")
+}
+
+// CustomRolePath returns the definition file for a custom role.
+func CustomRolePath(townRoot, name string) string {
+	return filepath.Join(CustomRolesDir(townRoot), name+".toml")
+}
+
+// IsBuiltinRole reports whether name is one of the built-in roles.
+func IsBuiltinRole(name string) bool {
+	return isValidRoleName(name)
+}
+
+// IsCustomRole reports whether the town defines a custom role called name.
+func IsCustomRole(townRoot, name string) bool {
+	if isValidRoleName(name) || !customRoleName.MatchString(name) {
+		return false
+	}
+	_, err := os.Stat(CustomRolePath(townRoot, name))
+	return err == nil
+}
+
+// ValidateCustomRoleName checks that name can be used for a new custom role.
+func ValidateCustomRoleName(name string) error {
+	if !customRoleName.MatchString(name) {
+		return fmt.Errorf("invalid role name %q: use lowercase letters, digits and -", name)
+	}
+	if isValidRoleName(name) || reservedRoleNames[name] {
+		return fmt.Errorf("role name %q is reserved", name)
+	}
+	return nil
+}
+
+// NewCustomRole returns the default definition for a new custom role.
+// Town-scoped roles run in {town}/<name> as session hq-<name>; rig-scoped
+// roles run in {town}/{rig}/<name> as session {prefix}-<name>.
+func NewCustomRole(name, scope string) *RoleDefinition {
+	def := &RoleDefinition{
+		Role:           name,
+		Scope:          scope,
+		Nudge:          "Check your hook and mail, then act accordingly.",
+		PromptTemplate: name + ".md",
+		Health: RoleHealthConfig{
+			PingTimeout:         Duration{30 * time.Second},
+			ConsecutiveFailures: 3,
+			KillCooldown:        Duration{5 * time.Minute},
+			StuckThreshold:      Duration{time.Hour},
+		},
+	}
+	if scope == "rig" {
+		def.Session.Pattern = "{prefix}-{role}"
+		def.Session.WorkDir = "{town}/{rig}/{role}"
+	} else {
+		def.Session.Pattern = "hq-{role}"
+		def.Session.WorkDir = "{town}/{role}"
+	}
+	def.Session.StartCommand = "exec claude --dangerously-skip-permissions"
+	return def
+}
+
+// Validate checks a custom role definition.
+func (d *RoleDefinition) Validate() error {
+	if err := ValidateCustomRoleName(d.Role); err != nil {
+		return err
+	}
+	if d.Scope != "town" && d.Scope != "rig" {
+		return fmt.Errorf("role %s: scope must be \"town\" or \"rig\", got %q", d.Role, d.Scope)
+	}
+	if d.Permissions != "" {
+		if _, err := rbac.ParseLevel(d.Permissions); err != nil {
+			return fmt.Errorf("role %s: permissions: %w", d.Role, err)
+		}
+	}
+	if strings.ContainsAny(d.PromptTemplate, `/\`) {
+		return fmt.Errorf("role %s: prompt_template must be a file name in roles/", d.Role)
+	}
+	return nil
+}
+
+// LoadCustomRole loads a custom role from <town>/roles/<name>.toml. Fields
+// the file leaves out take the NewCustomRole defaults for its scope.
+func LoadCustomRole(townRoot, name string) (*RoleDefinition, error) {
+	path := CustomRolePath(townRoot, name)
+	file, err := loadRoleOverride(path)
+	if err != nil {
+		return nil, err
+	}
+	if file.Role == "" {
+		file.Role = name
+	}
+	if file.Role != name {
+		return nil, fmt.Errorf("%s: role = %q does not match the file name", path, file.Role)
+	}
+	if err := file.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	def := NewCustomRole(name, file.Scope)
+	mergeRoleDefinition(def, file)
+	def.Description = file.Description
+	def.Permissions = file.Permissions
+	def.Autonomous = file.Autonomous
+	return def, nil
+}
+
+// ListCustomRoles returns the town's custom roles sorted by name. Files that
+// fail to load are returned as errors alongside the roles that did.
+func ListCustomRoles(townRoot string) ([]*RoleDefinition, []error) {
+	entries, err := os.ReadDir(CustomRolesDir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, []error{err}
+	}
+	var defs []*RoleDefinition
+	var errs []error
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".toml")
+		if !ok || e.IsDir() || isValidRoleName(name) {
+			continue
+		}
+		def, err := LoadCustomRole(townRoot, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Role < defs[j].Role })
+	return defs, errs
+}
+
+// WriteCustomRole writes def to <town>/roles/<name>.toml. It refuses to
+// overwrite an existing definition.
+func WriteCustomRole(townRoot string, def *RoleDefinition) error {
+	if err := def.Validate(); err != nil {
+		return err
+	}
+	path := CustomRolePath(townRoot, def.Role)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("role %s already exists: %s", def.Role, path)
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s role definition (custom)\n", def.Role)
+	if def.Description != "" {
+		fmt.Fprintf(&buf, "# %s\n", def.Description)
+	}
+	buf.WriteString("\n")
+	if err := toml.NewEncoder(&buf).Encode(def); err != nil {
+		return fmt.Errorf("encoding role %s: %w", def.Role, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating roles directory: %w", err)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// BriefingPath returns the custom role's briefing file, or "" if it has none.
+func (d *RoleDefinition) BriefingPath(townRoot string) string {
+	if d.PromptTemplate == "" {
+		return ""
+	}
+	return filepath.Join(CustomRolesDir(townRoot), d.PromptTemplate)
+}
+
+// Address returns the custom role agent's mail address: "<name>/" for a
+// town-scoped role, "<rig>/<name>" for a rig-scoped one.
+func (d *RoleDefinition) Address(rig string) string {
+	if d.Scope == "rig" {
+		return rig + "/" + d.Role
+	}
+	return d.Role + "/"
+}
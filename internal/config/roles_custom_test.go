@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteAndLoadCustomRole(t *testing.T) {
+	townRoot := t.TempDir()
+
+	def := NewCustomRole("architect", "town")
+	def.Description = "Owns cross-rig design"
+	def.Permissions = "nudge"
+	if err := WriteCustomRole(townRoot, def); err != nil {
+		t.Fatalf("WriteCustomRole: %v", err)
+	}
+	if err := WriteCustomRole(townRoot, def); err == nil {
+		t.Error("WriteCustomRole over an existing role = nil, want error")
+	}
+
+	if !IsCustomRole(townRoot, "architect") {
+		t.Fatal("IsCustomRole(architect) = false after writing it")
+	}
+	got, err := LoadRoleDefinition(townRoot, "", "architect")
+	if err != nil {
+		t.Fatalf("LoadRoleDefinition: %v", err)
+	}
+	if got.Scope != "town" || got.Session.Pattern != "hq-{role}" || got.Session.WorkDir != "{town}/{role}" {
+		t.Errorf("loaded session config = %+v (scope %q)", got.Session, got.Scope)
+	}
+	if got.Description != "Owns cross-rig design" || got.Permissions != "nudge" {
+		t.Errorf("Description/Permissions = %q/%q", got.Description, got.Permissions)
+	}
+	if got.Health.PingTimeout.Duration == 0 {
+		t.Error("health defaults were not round-tripped")
+	}
+	if got.Address("") != "architect/" {
+		t.Errorf("Address() = %q, want architect/", got.Address(""))
+	}
+}
+
+func TestLoadCustomRole_FillsScopeDefaults(t *testing.T) {
+	townRoot := t.TempDir()
+	writeRoleFile(t, townRoot, "security-reviewer", `scope = "rig"
+description = "Reviews diffs for security issues"
+autonomous = true
+`)
+
+	def, err := LoadCustomRole(townRoot, "security-reviewer")
+	if err != nil {
+		t.Fatalf("LoadCustomRole: %v", err)
+	}
+	if def.Role != "security-reviewer" || !def.Autonomous {
+		t.Errorf("Role/Autonomous = %q/%v", def.Role, def.Autonomous)
+	}
+	if def.Session.Pattern != "{prefix}-{role}" || def.Session.WorkDir != "{town}/{rig}/{role}" {
+		t.Errorf("rig defaults not applied: %+v", def.Session)
+	}
+	if def.PromptTemplate != "security-reviewer.md" {
+		t.Errorf("PromptTemplate = %q", def.PromptTemplate)
+	}
+	if def.Address("gastown") != "gastown/security-reviewer" {
+		t.Errorf("Address(gastown) = %q", def.Address("gastown"))
+	}
+}
+
+func TestLoadCustomRole_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"bad-scope":  `scope = "galaxy"`,
+		"bad-perms":  "scope = \"town\"\npermissions = \"root\"",
+		"wrong-name": "role = \"other\"\nscope = \"town\"",
+		"bad-prompt": "scope = \"town\"\nprompt_template = \"../x.md\"",
+	}
+	for name, body := range tests {
+		townRoot := t.TempDir()
+		writeRoleFile(t, townRoot, name, body)
+		if _, err := LoadCustomRole(townRoot, name); err == nil {
+			t.Errorf("%s: LoadCustomRole = nil error, want error", name)
+		}
+	}
+}
+
+func TestListCustomRoles_SkipsOverrides(t *testing.T) {
+	townRoot := t.TempDir()
+	writeRoleFile(t, townRoot, "witness", "[health]\nconsecutive_failures = 5\n")
+	writeRoleFile(t, townRoot, "release-manager", `scope = "town"`)
+	writeRoleFile(t, townRoot, "architect", `scope = "town"`)
+	writeRoleFile(t, townRoot, "broken", `scope = "nowhere"`)
+
+	defs, errs := ListCustomRoles(townRoot)
+	if len(defs) != 2 || defs[0].Role != "architect" || defs[1].Role != "release-manager" {
+		t.Errorf("ListCustomRoles = %v, want [architect release-manager]", roleNames(defs))
+	}
+	if len(errs) != 1 {
+		t.Errorf("errors = %v, want one for broken.toml", errs)
+	}
+	if IsCustomRole(townRoot, "witness") {
+		t.Error("a built-in override must not count as a custom role")
+	}
+}
+
+func TestValidateCustomRoleName(t *testing.T) {
+	for _, name := range []string{"architect", "release-manager", "qa2"} {
+		if err := ValidateCustomRoleName(name); err != nil {
+			t.Errorf("ValidateCustomRoleName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"mayor", "polecats", "overseer", "Architect", "2fast", "a/b", ""} {
+		if err := ValidateCustomRoleName(name); err == nil {
+			t.Errorf("ValidateCustomRoleName(%q) = nil, want error", name)
+		}
+	}
+}
+
+func writeRoleFile(t *testing.T, townRoot, name, body string) {
+	t.Helper()
+	dir := CustomRolesDir(townRoot)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".toml"), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func roleNames(defs []*RoleDefinition) []string {
+	var names []string
+	for _, d := range defs {
+		names = append(names, d.Role)
+	}
+	return names
+}
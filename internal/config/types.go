@@ -9,17 +9,45 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/analyze"
+	"github.com/steveyegge/gastown/internal/atrest"
+	"github.com/steveyegge/gastown/internal/changelog"
+	"github.com/steveyegge/gastown/internal/cifix"
+	"github.com/steveyegge/gastown/internal/codeindex"
+	"github.com/steveyegge/gastown/internal/confirm"
+	"github.com/steveyegge/gastown/internal/contextbudget"
+	"github.com/steveyegge/gastown/internal/dedup"
+	"github.com/steveyegge/gastown/internal/depupdate"
+	"github.com/steveyegge/gastown/internal/diskquota"
+	"github.com/steveyegge/gastown/internal/injectguard"
+	"github.com/steveyegge/gastown/internal/notification"
+	"github.com/steveyegge/gastown/internal/ownership"
+	"github.com/steveyegge/gastown/internal/permprompt"
+	"github.com/steveyegge/gastown/internal/provenance"
+	"github.com/steveyegge/gastown/internal/quiet"
+	"github.com/steveyegge/gastown/internal/rbac"
+	"github.com/steveyegge/gastown/internal/release"
+	"github.com/steveyegge/gastown/internal/reslimit"
 	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+	"github.com/steveyegge/gastown/internal/shard"
+	"github.com/steveyegge/gastown/internal/stalehook"
+	"github.com/steveyegge/gastown/internal/townlock"
+	"github.com/steveyegge/gastown/internal/triage"
+	"github.com/steveyegge/gastown/internal/verify"
+	"github.com/steveyegge/gastown/internal/views"
+	"github.com/steveyegge/gastown/internal/watch"
+	"github.com/steveyegge/gastown/internal/webhook"
 )
 
 // TownConfig represents the main town identity (mayor/town.json).
 type TownConfig struct {
-	Type       string    `json:"type"`                  // "town"
-	Version    int       `json:"version"`               // schema version
-	Name       string    `json:"name"`                  // town identifier (internal)
-	Owner      string    `json:"owner,omitempty"`       // owner email (entity identity)
-	PublicName string    `json:"public_name,omitempty"` // public display name
-	CreatedAt  time.Time `json:"created_at"`
+	Type        string    `json:"type"`                  // "town"
+	Version     int       `json:"version"`               // schema version
+	Name        string    `json:"name"`                  // town identifier (internal)
+	Owner       string    `json:"owner,omitempty"`       // owner email (entity identity)
+	PublicName  string    `json:"public_name,omitempty"` // public display name
+	CreatedAt   time.Time `json:"created_at"`
+	CreatedWith string    `json:"created_with,omitempty"` // gt version that ran gt install
 }
 
 // MayorConfig represents town-level behavioral configuration (mayor/config.json).
@@ -48,6 +76,11 @@ type TownSettings struct {
 	// Can be overridden by GT_THEME environment variable.
 	CLITheme string `json:"cli_theme,omitempty"`
 
+	// Locale is the language gt uses for operator output and agent
+	// briefings (e.g. "ja"), with translations under
+	// settings/locales/<locale>/. Default: "en". GT_LOCALE overrides it.
+	Locale string `json:"locale,omitempty"`
+
 	// DefaultAgent is the name of the agent preset to use by default.
 	// Can be a built-in preset ("claude", "gemini", "codex", "cursor", "auggie", "amp", "opencode", "copilot")
 	// or a custom agent name defined in settings/agents.json.
@@ -73,6 +106,10 @@ type TownSettings struct {
 	// Example: {"bob": "codex", "alice": "claude"}
 	CrewAgents map[string]string `json:"crew_agents,omitempty"`
 
+	// PolecatFormula replaces mol-polecat-work as the formula applied to
+	// beads slung to polecats without --formula. Set by gt config promote.
+	PolecatFormula string `json:"polecat_formula,omitempty"`
+
 	// AgentEmailDomain is the domain used for agent git identity emails.
 	// Agent addresses like "gastown/crew/jack" become "gastown.crew.jack@{domain}".
 	// Default: "gastown.local"
@@ -102,6 +139,112 @@ type TownSettings struct {
 	// These were previously hardcoded as Go constants throughout the codebase.
 	// All values are optional — omitted values use compiled-in defaults.
 	Operational *OperationalConfig `json:"operational,omitempty"`
+
+	// Dedup configures duplicate-bead detection on sling.
+	// nil/absent = enabled with TF-IDF scoring and default thresholds.
+	Dedup *dedup.Config `json:"dedup,omitempty"`
+
+	// Access is the RBAC policy for human operators sharing the town.
+	// nil/absent or no principals = no enforcement.
+	Access *rbac.Config `json:"access,omitempty"`
+
+	// Watch holds file-triggered sling rules run by gt watch.
+	Watch *watch.Config `json:"watch,omitempty"`
+
+	// Webhooks configures the inbound webhook server run by the daemon.
+	// nil/absent or no endpoints = server not started.
+	Webhooks *webhook.Config `json:"webhooks,omitempty"`
+
+	// CIRemediation configures follow-ups slung to polecats whose branches
+	// fail CI (gt ci). nil/absent = defaults (2 attempts, 200 log lines).
+	CIRemediation *cifix.Config `json:"ci_remediation,omitempty"`
+
+	// Mayors shards coordination across several mayors, each owning a
+	// subset of rigs. nil/absent = a single Mayor for the whole town.
+	Mayors *shard.Config `json:"mayors,omitempty"`
+
+	// GTVersion pins the gt versions allowed to run against the town,
+	// checked at command startup. nil/absent = any version.
+	GTVersion *VersionPin `json:"gt_version,omitempty"`
+
+	// Features enables experimental subsystems by name (see
+	// ExperimentalFeatures). Absent = off.
+	Features map[string]bool `json:"features,omitempty"`
+
+	// QuietHours pauses dispatch, parks idle agents and holds non-urgent
+	// mail notifications during a daily window. Rigs may override it.
+	QuietHours *quiet.Config `json:"quiet_hours,omitempty"`
+
+	// Notifications raises desktop notifications for escalations, approval
+	// requests, landed convoys and budget thresholds. nil/absent = off.
+	Notifications *notification.Config `json:"notifications,omitempty"`
+
+	// ContentGuard scans webhook-created beads, slung beads and mail from
+	// outside agent sessions for prompt injection, quarantining suspicious
+	// material for review. nil/absent = on with built-in patterns.
+	ContentGuard *injectguard.Config `json:"content_guard,omitempty"`
+
+	// Confirmations sets how destructive commands are confirmed (typed
+	// name, second operator, delay) and whether --yes may skip that.
+	// Absent commands keep their built-in confirmation.
+	Confirmations confirm.Config `json:"confirmations,omitempty"`
+
+	// Views are saved bead queries run with gt view, shown on the dashboard
+	// and offered by gt sling --interactive.
+	Views views.Config `json:"views,omitempty"`
+
+	// LabelRules act on beads when they gain a label (gt bead rules).
+	LabelRules []LabelRule `json:"label_rules,omitempty"`
+
+	// Provenance stamps agent commits with Gt-* trailers, signs them, and
+	// has the merge queue verify both. nil/absent = off.
+	Provenance *provenance.Config `json:"provenance,omitempty"`
+
+	// Triage configures the triage agent's intake sources and routes
+	// (gt triage). nil/absent = every intake bead goes to review.
+	Triage *triage.Config `json:"triage,omitempty"`
+
+	// Encryption seals mail bodies and idle agent transcripts at rest with
+	// a key from the OS keychain (gt crypt). nil/absent = plaintext.
+	Encryption *atrest.Config `json:"encryption,omitempty"`
+
+	// StaleHooks sets when a hooked bead with no sign of work is stale
+	// (gt hook list --stale) and whether gt hook sweep unhooks and requeues
+	// it. nil/absent = stale after 6h, listed only.
+	StaleHooks *stalehook.Config `json:"stale_hooks,omitempty"`
+
+	// TownLock sets what other operators' mutating commands do while an
+	// operator holds the town lock (gt town lock). nil/absent = block.
+	TownLock *townlock.Config `json:"town_lock,omitempty"`
+}
+
+// LabelRule acts on a bead once when it gains Label. Removing the label and
+// adding it again fires the rule again.
+type LabelRule struct {
+	// Name identifies the rule in gt bead rules output.
+	Name string `json:"name"`
+
+	// Label is the label that triggers the rule. Example: "urgent"
+	Label string `json:"label"`
+
+	// Priority raises the bead to this priority (0 = critical). Beads
+	// already at or above it are left alone.
+	Priority *int `json:"priority,omitempty"`
+
+	// Nudge nudges the bead's assignee, if it has one.
+	Nudge bool `json:"nudge,omitempty"`
+
+	// Notify mails each address. Example: ["mayor/"]
+	Notify []string `json:"notify,omitempty"`
+
+	// Sling slings the bead to this target (any gt sling target).
+	// Example: "greenplace/crew/architect"
+	Sling string `json:"sling,omitempty"`
+}
+
+// HasAction reports whether the rule does anything when it fires.
+func (r LabelRule) HasAction() bool {
+	return r.Priority != nil || r.Nudge || len(r.Notify) > 0 || r.Sling != ""
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -626,6 +769,59 @@ type RigSettings struct {
 	Crew       *CrewConfig       `json:"crew,omitempty"`        // crew startup settings
 	Workflow   *WorkflowConfig   `json:"workflow,omitempty"`    // workflow settings
 	Runtime    *RuntimeConfig    `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
+	Env        *RigEnvConfig     `json:"env,omitempty"`         // environment applied to every session/exec
+
+	// BuildCache shares build and package caches across the rig's worktrees.
+	BuildCache *BuildCacheConfig `json:"build_cache,omitempty"`
+
+	// StaticAnalysis configures the analyzers gt done runs on a polecat's
+	// diff before the branch is submitted.
+	StaticAnalysis *analyze.Config `json:"static_analysis,omitempty"`
+
+	// PermissionPolicy decides which runtime permission prompts in the
+	// rig's agent sessions gt answers automatically.
+	PermissionPolicy *permprompt.Config `json:"permission_policy,omitempty"`
+
+	// QuietHours replaces the town's quiet_hours for this rig.
+	QuietHours *quiet.Config `json:"quiet_hours,omitempty"`
+
+	// DiskQuota caps the disk used by the rig's worktrees, transcripts and
+	// artifacts. Over the limit, new polecat spawns are refused.
+	DiskQuota *diskquota.Config `json:"disk_quota,omitempty"`
+
+	// VerifyDone has a verifier agent check a polecat's work against the
+	// bead's acceptance criteria before gt done submits it.
+	VerifyDone *verify.Config `json:"verify_done,omitempty"`
+
+	// DepUpdates configures the dependency update sweep, which files (and
+	// optionally slings) beads for the rig's outdated dependencies.
+	DepUpdates *depupdate.Config `json:"dep_updates,omitempty"`
+
+	// Release configures gt release: the release formula, tag prefix and
+	// which checklist steps need approval.
+	Release *release.Config `json:"release,omitempty"`
+
+	// Changelog configures changelog fragments: whether gt done requires one
+	// for user-facing changes, and where fragments and the changelog live.
+	Changelog *changelog.Config `json:"changelog,omitempty"`
+
+	// Capacity caps the rig's concurrent polecats and lists its
+	// capabilities, so a sling to a full rig can be queued, rerouted to a
+	// capable rig or scaled up within burst.
+	Capacity *capacity.RigConfig `json:"capacity,omitempty"`
+
+	// CodeIndex keeps a symbol, summary and embedding index of the rig's
+	// code, refreshed after merges, for agents to query.
+	CodeIndex *codeindex.Config `json:"code_index,omitempty"`
+
+	// ResourceLimits caps the CPU and memory of each of the rig's agent
+	// sessions and everything they run.
+	ResourceLimits *reslimit.Config `json:"resource_limits,omitempty"`
+
+	// Ownership maps CODEOWNERS owners to agents, so beads touching owned
+	// paths are slung to their owners and the merge gate can require the
+	// owners' review.
+	Ownership *ownership.Config `json:"ownership,omitempty"`
 
 	// Agent selects which agent preset to use for this rig.
 	// Can be a built-in preset ("claude", "gemini", "codex", "cursor", "auggie", "amp", "opencode", "copilot")
@@ -653,6 +849,42 @@ type RigSettings struct {
 	WorkerAgents map[string]string `json:"worker_agents,omitempty"`
 }
 
+// BuildCacheConfig shares build and package caches (Go, pnpm, npm, ccache,
+// pip) across every session in a rig, so polecats don't each download and
+// rebuild the same dependencies in their own worktree.
+type BuildCacheConfig struct {
+	// Caches lists the caches to share; empty shares all supported caches.
+	Caches []string `json:"caches,omitempty"`
+
+	// Dir is the shared cache directory. Relative paths are relative to the
+	// rig; $VAR and ~ are expanded. Default: <rig>/.cache. Point several
+	// rigs at one directory to share across rigs.
+	Dir string `json:"dir,omitempty"`
+}
+
+// RigEnvConfig declares the environment a rig's tools need. It is applied to
+// every agent session started in the rig and to gt polecat exec, so agents
+// don't depend on interactive shell setup (nvm, pyenv, ...) having run.
+type RigEnvConfig struct {
+	// Vars are extra environment variables. Gas Town identity variables
+	// (GT_ROLE, BD_ACTOR, ...) always take precedence.
+	Vars map[string]string `json:"vars,omitempty"`
+
+	// Path entries are prepended to PATH, in order. $VAR references and a
+	// leading ~ are expanded when the session is started.
+	// Example: ["~/.nvm/versions/node/v20.11.0/bin", "$HOME/go/bin"]
+	Path []string `json:"path,omitempty"`
+
+	// Tools pins tool versions via a version manager.
+	// Example: {"node": "20.11.0", "python": "3.12"}
+	Tools map[string]string `json:"tools,omitempty"`
+
+	// ToolManager selects how Tools are activated: "mise" (default) wraps
+	// the command in 'mise exec', "asdf" sets ASDF_<TOOL>_VERSION and puts
+	// the asdf shims on PATH.
+	ToolManager string `json:"tool_manager,omitempty"`
+}
+
 // CrewConfig represents crew workspace settings for a rig.
 type CrewConfig struct {
 	// Startup is a natural language instruction for which crew to start on boot.
@@ -722,6 +954,11 @@ type RuntimeConfig struct {
 	// Produces: exec env VAR=val ... exitbox run --profile=gastown-polecat -- claude ...
 	ExecWrapper []string `json:"exec_wrapper,omitempty"`
 
+	// ContextBudget sets when the witness compacts or rotates a session of
+	// this runtime before its context window fills (see gt witness context).
+	// If nil, the defaults for the provider apply.
+	ContextBudget *contextbudget.Config `json:"context_budget,omitempty"`
+
 	// ResolvedAgent is the agent name that was resolved during config lookup.
 	// Set by ResolveRoleAgentConfig / resolveAgentConfigInternal so that
 	// BuildStartupCommand can export GT_AGENT for process detection.
@@ -872,6 +1109,10 @@ func normalizeRuntimeConfig(rc *RuntimeConfig) *RuntimeConfig {
 		i := *rc.Instructions
 		rc.Instructions = &i
 	}
+	if rc.ContextBudget != nil {
+		b := *rc.ContextBudget
+		rc.ContextBudget = &b
+	}
 
 	if rc.Provider == "" {
 		rc.Provider = "claude"
@@ -1381,6 +1622,51 @@ type MessagingConfig struct {
 	// Like mailing lists but for tmux send-keys instead of durable mail.
 	// Example: {"workers": ["gastown/polecats/*", "gastown/crew/*"], "witnesses": ["*/witness"]}
 	NudgeChannels map[string][]string `json:"nudge_channels,omitempty"`
+
+	// Rules triage mail as it arrives, keyed by mailbox address. The Deacon
+	// applies them each patrol (gt mail rules run --all); rules are tried in
+	// order and every matching rule's actions run unless one sets stop.
+	// Example: {"mayor/": [{"name": "done", "subject": "POLECAT_DONE*", "folder": "done", "ack": true}]}
+	Rules map[string][]MailRule `json:"rules,omitempty"`
+}
+
+// MailRule matches incoming messages and acts on them. Every set match field
+// must match; a rule with no match fields matches every message.
+type MailRule struct {
+	// Name identifies the rule in gt mail rules output.
+	Name string `json:"name"`
+
+	// From is a sender address glob ("*" matches any run of characters).
+	// Example: "*/witness"
+	From string `json:"from,omitempty"`
+
+	// Subject is a case-insensitive subject glob. Example: "MERGE_FAILED*"
+	Subject string `json:"subject,omitempty"`
+
+	// Labels lists labels the message must carry.
+	Labels []string `json:"labels,omitempty"`
+
+	// Folder files the message into a folder, hiding it from the default
+	// inbox view (gt mail inbox --folder <name> shows it).
+	Folder string `json:"folder,omitempty"`
+
+	// Ack marks the message read.
+	Ack bool `json:"ack,omitempty"`
+
+	// Forward sends a copy to each address.
+	Forward []string `json:"forward,omitempty"`
+
+	// Hook attaches the message to an agent's hook. "self" means the
+	// mailbox owner.
+	Hook string `json:"hook,omitempty"`
+
+	// Stop skips the rules after this one when it matches.
+	Stop bool `json:"stop,omitempty"`
+}
+
+// HasAction reports whether the rule does anything when it matches.
+func (r MailRule) HasAction() bool {
+	return r.Folder != "" || r.Ack || len(r.Forward) > 0 || r.Hook != "" || r.Stop
 }
 
 // QueueConfig represents a work queue configuration.
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/deps"
+)
+
+// Experimental features gated by TownSettings.Features. They are off unless
+// the town enables them, so a new subsystem can't touch shared state until
+// the town opts in.
+const (
+	FeatureExperiments = "experiments" // gt experiment, gt sling --experiment
+	FeatureReplay      = "replay"      // gt replay
+	FeatureSimulate    = "simulate"    // gt town simulate
+	FeatureChaos       = "chaos"       // gt town chaos
+)
+
+// EnvIgnoreVersionPin bypasses the town's gt version pin (with a warning).
+const EnvIgnoreVersionPin = "GT_IGNORE_VERSION_PIN"
+
+// ErrVersionPinned indicates the running gt is outside the town's version pin.
+var ErrVersionPinned = errors.New("gt version not allowed by town")
+
+// ErrUnknownFeature indicates a feature name that isn't in the registry.
+var ErrUnknownFeature = errors.New("unknown feature")
+
+// experimentalFeatures describes each gated feature.
+var experimentalFeatures = map[string]string{
+	FeatureExperiments: "A/B experiments across prompts, models and formulas",
+	FeatureReplay:      "Re-running beads from their captured starting state",
+	FeatureSimulate:    "End-to-end town simulation with scripted agents",
+	FeatureChaos:       "Fault injection to test recovery (test towns only)",
+}
+
+var pinVersionPattern = regexp.MustCompile(`^v?\d+(\.\d+){0,2}$`)
+
+// VersionPin bounds the gt versions allowed to operate on a town. Both ends
+// are inclusive; an empty bound is open.
+type VersionPin struct {
+	Min string `json:"min,omitempty"` // oldest gt allowed, e.g. "0.12.0"
+	Max string `json:"max,omitempty"` // newest gt allowed, e.g. "0.13.4"
+}
+
+// Validate checks that the bounds parse and are ordered.
+func (p *VersionPin) Validate() error {
+	if p == nil {
+		return nil
+	}
+	for _, v := range []string{p.Min, p.Max} {
+		if v != "" && !pinVersionPattern.MatchString(v) {
+			return fmt.Errorf("invalid gt_version bound %q (want X.Y.Z)", v)
+		}
+	}
+	if p.Min != "" && p.Max != "" && compareGTVersions(p.Min, p.Max) > 0 {
+		return fmt.Errorf("invalid gt_version: min %s is newer than max %s", p.Min, p.Max)
+	}
+	return nil
+}
+
+// Allows reports whether version satisfies the pin. Build suffixes
+// ("0.12.1-42-gabc", "0.12.1+dirty") are ignored.
+func (p *VersionPin) Allows(version string) bool {
+	if p == nil {
+		return true
+	}
+	if p.Min != "" && compareGTVersions(version, p.Min) < 0 {
+		return false
+	}
+	if p.Max != "" && compareGTVersions(version, p.Max) > 0 {
+		return false
+	}
+	return true
+}
+
+// Check returns ErrVersionPinned when version is outside the pin.
+func (p *VersionPin) Check(version string) error {
+	if p.Allows(version) {
+		return nil
+	}
+	return fmt.Errorf("%w: town requires gt %s, this is gt %s", ErrVersionPinned, p, version)
+}
+
+// Nearest returns the bound version crossed: Min when version is too old,
+// Max when it is too new, or version itself when the pin allows it.
+func (p *VersionPin) Nearest(version string) string {
+	switch {
+	case p.Allows(version):
+		return version
+	case p.Min != "" && compareGTVersions(version, p.Min) < 0:
+		return strings.TrimPrefix(p.Min, "v")
+	default:
+		return strings.TrimPrefix(p.Max, "v")
+	}
+}
+
+// String renders the pin as a range, e.g. ">=0.12.0, <=0.13.4".
+func (p *VersionPin) String() string {
+	if p == nil || (p.Min == "" && p.Max == "") {
+		return "any"
+	}
+	var parts []string
+	if p.Min != "" {
+		parts = append(parts, ">="+strings.TrimPrefix(p.Min, "v"))
+	}
+	if p.Max != "" {
+		parts = append(parts, "<="+strings.TrimPrefix(p.Max, "v"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// compareGTVersions compares two gt versions, ignoring a leading "v" and
+// any pre-release or build suffix.
+func compareGTVersions(a, b string) int {
+	return deps.CompareVersions(coreVersion(a), coreVersion(b))
+}
+
+func coreVersion(v string) string {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+ "); i >= 0 {
+		v = v[:i]
+	}
+	return v
+}
+
+// ExperimentalFeatures returns the gated feature names, sorted.
+func ExperimentalFeatures() []string {
+	names := make([]string, 0, len(experimentalFeatures))
+	for name := range experimentalFeatures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FeatureDescription returns a one-line description of a gated feature.
+func FeatureDescription(name string) string {
+	return experimentalFeatures[name]
+}
+
+// ValidateFeature returns ErrUnknownFeature for names not in the registry.
+func ValidateFeature(name string) error {
+	if _, ok := experimentalFeatures[name]; !ok {
+		return fmt.Errorf("%w %q (known: %s)", ErrUnknownFeature, name, strings.Join(ExperimentalFeatures(), ", "))
+	}
+	return nil
+}
+
+// FeatureEnabled reports whether a gated feature is on for the town.
+// GT_FEATURE_<NAME>=1 or 0 overrides the town setting for one process.
+func (s *TownSettings) FeatureEnabled(name string) bool {
+	switch os.Getenv(FeatureEnvVar(name)) {
+	case "1", "true", "on":
+		return true
+	case "0", "false", "off":
+		return false
+	}
+	return s != nil && s.Features[name]
+}
+
+// FeatureEnvVar returns the environment variable that overrides a feature.
+func FeatureEnvVar(name string) string {
+	return "GT_FEATURE_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
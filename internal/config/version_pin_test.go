@@ -0,0 +1,90 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVersionPin_Allows(t *testing.T) {
+	pin := &VersionPin{Min: "0.12.0", Max: "v0.13.4"}
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"0.11.9", false},
+		{"0.12.0", true},
+		{"0.12.1-42-gabc123", true},
+		{"v0.13.4", true},
+		{"0.13.4+dirty", true},
+		{"0.13.5", false},
+		{"1.0.0", false},
+	}
+	for _, tt := range tests {
+		if got := pin.Allows(tt.version); got != tt.want {
+			t.Errorf("Allows(%q) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+
+	var none *VersionPin
+	if !none.Allows("0.0.1") || none.Check("9.9.9") != nil {
+		t.Error("a nil pin allows any version")
+	}
+	if !(&VersionPin{Max: "0.13"}).Allows("0.12.7") {
+		t.Error("a min-only or max-only pin is open at the other end")
+	}
+}
+
+func TestVersionPin_Check(t *testing.T) {
+	pin := &VersionPin{Min: "0.12.0", Max: "0.13.4"}
+	if err := pin.Check("0.11.0"); !errors.Is(err, ErrVersionPinned) {
+		t.Errorf("Check(0.11.0) = %v, want ErrVersionPinned", err)
+	}
+	if got := pin.Nearest("0.11.0"); got != "0.12.0" {
+		t.Errorf("Nearest(too old) = %q, want min", got)
+	}
+	if got := pin.Nearest("0.14.0"); got != "0.13.4" {
+		t.Errorf("Nearest(too new) = %q, want max", got)
+	}
+	if got := pin.String(); got != ">=0.12.0, <=0.13.4" {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestVersionPin_Validate(t *testing.T) {
+	if err := (&VersionPin{Min: "0.12.0", Max: "0.12.0"}).Validate(); err != nil {
+		t.Errorf("equal bounds: %v", err)
+	}
+	for _, bad := range []*VersionPin{
+		{Min: "latest"},
+		{Max: "0.12.x"},
+		{Min: "0.13.0", Max: "0.12.0"},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v) should fail", *bad)
+		}
+	}
+}
+
+func TestFeatureEnabled(t *testing.T) {
+	settings := NewTownSettings()
+	if settings.FeatureEnabled(FeatureReplay) {
+		t.Error("experimental features are off by default")
+	}
+	settings.Features = map[string]bool{FeatureReplay: true}
+	if !settings.FeatureEnabled(FeatureReplay) {
+		t.Error("enabled feature reported off")
+	}
+
+	t.Setenv(FeatureEnvVar(FeatureReplay), "0")
+	if settings.FeatureEnabled(FeatureReplay) {
+		t.Error("GT_FEATURE_REPLAY=0 should override the town setting")
+	}
+	t.Setenv(FeatureEnvVar(FeatureSimulate), "1")
+	if !settings.FeatureEnabled(FeatureSimulate) {
+		t.Error("GT_FEATURE_SIMULATE=1 should enable the feature")
+	}
+
+	if err := ValidateFeature("teleport"); !errors.Is(err, ErrUnknownFeature) {
+		t.Errorf("ValidateFeature(teleport) = %v, want ErrUnknownFeature", err)
+	}
+}
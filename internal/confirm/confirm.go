@@ -0,0 +1,128 @@
+// Package confirm decides how a destructive command must be confirmed
+// before it runs.
+//
+// Each guarded command ("rig remove", "polecat nuke", "close", "bulk",
+// "shutdown", "uninstall") has a policy. The mode says what the operator
+// has to do:
+//
+//	none             run without asking
+//	prompt           answer y at a [y/N] prompt
+//	typed            type the target's name
+//	second-operator  another operator approves with gt confirm approve
+//	delay            re-run the command after a waiting period
+//
+// --yes satisfies a policy only when the policy allows it, so automation
+// can run a command non-interactively only where the town has said so.
+// Commands that are not configured keep their built-in policy, which
+// matches how they behaved before policies existed.
+//
+// Configured in town settings:
+//
+//	"confirmations": {
+//	  "rig remove":   {"mode": "typed"},
+//	  "polecat nuke": {"mode": "prompt", "allow_yes": true},
+//	  "uninstall":    {"mode": "second-operator"},
+//	  "shutdown":     {"mode": "delay", "delay": "2m"}
+//	}
+package confirm
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Confirmation modes.
+const (
+	ModeNone           = "none"
+	ModePrompt         = "prompt"
+	ModeTyped          = "typed"
+	ModeSecondOperator = "second-operator"
+	ModeDelay          = "delay"
+)
+
+// DefaultDelay is the waiting period of a delay policy that sets none.
+const DefaultDelay = time.Minute
+
+// Policy is how one command must be confirmed.
+type Policy struct {
+	Mode string `json:"mode"`
+
+	// AllowYes lets --yes satisfy the policy. Unset means allowed for
+	// none and prompt, and refused for the stricter modes.
+	AllowYes *bool `json:"allow_yes,omitempty"`
+
+	// Delay is the waiting period for the delay mode, e.g. "2m".
+	Delay string `json:"delay,omitempty"`
+}
+
+// YesAllowed reports whether --yes satisfies the policy.
+func (p Policy) YesAllowed() bool {
+	if p.AllowYes != nil {
+		return *p.AllowYes
+	}
+	return p.Mode == ModeNone || p.Mode == ModePrompt
+}
+
+// DelayDuration returns the delay mode's waiting period.
+func (p Policy) DelayDuration() time.Duration {
+	if d, err := time.ParseDuration(p.Delay); err == nil && d > 0 {
+		return d
+	}
+	return DefaultDelay
+}
+
+// Config maps guarded command paths ("rig remove") to their policies.
+type Config map[string]Policy
+
+// Defaults are the built-in policies. Commands that asked before keep
+// asking; the rest run unconfirmed until a town configures them.
+var Defaults = Config{
+	"rig remove":   {Mode: ModeNone},
+	"polecat nuke": {Mode: ModeNone},
+	"close":        {Mode: ModeNone},
+	"bulk":         {Mode: ModePrompt},
+	"shutdown":     {Mode: ModePrompt},
+	"uninstall":    {Mode: ModePrompt},
+}
+
+// Commands returns the guarded command paths, sorted.
+func Commands() []string {
+	out := make([]string, 0, len(Defaults))
+	for name := range Defaults {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// PolicyFor returns the policy for a guarded command: the configured one,
+// else the built-in one. A nil config uses the built-in policies.
+func (c Config) PolicyFor(command string) Policy {
+	if p, ok := c[command]; ok && p.Mode != "" {
+		return p
+	}
+	return Defaults[command]
+}
+
+// Validate checks that every configured command is guarded and every mode
+// and delay is valid. A nil config is valid.
+func (c Config) Validate() error {
+	for name, p := range c {
+		if _, ok := Defaults[name]; !ok {
+			return fmt.Errorf("%q: not a guarded command (guarded: %v)", name, Commands())
+		}
+		switch p.Mode {
+		case ModeNone, ModePrompt, ModeTyped, ModeSecondOperator:
+		case ModeDelay:
+			if p.Delay != "" {
+				if d, err := time.ParseDuration(p.Delay); err != nil || d <= 0 {
+					return fmt.Errorf("%q: invalid delay %q", name, p.Delay)
+				}
+			}
+		default:
+			return fmt.Errorf("%q: unknown mode %q", name, p.Mode)
+		}
+	}
+	return nil
+}
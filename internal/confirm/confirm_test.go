@@ -0,0 +1,92 @@
+package confirm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPolicyFor(t *testing.T) {
+	yes := true
+	cfg := Config{"rig remove": {Mode: ModeTyped}, "shutdown": {Mode: ModeSecondOperator, AllowYes: &yes}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if p := cfg.PolicyFor("rig remove"); p.Mode != ModeTyped || p.YesAllowed() {
+		t.Errorf("rig remove = %+v, yes allowed %v", p, p.YesAllowed())
+	}
+	if p := cfg.PolicyFor("shutdown"); !p.YesAllowed() {
+		t.Error("shutdown should allow --yes when configured to")
+	}
+	if p := Config(nil).PolicyFor("uninstall"); p.Mode != ModePrompt || !p.YesAllowed() {
+		t.Errorf("default uninstall = %+v", p)
+	}
+	if d := (Policy{Mode: ModeDelay, Delay: "90s"}).DelayDuration(); d != 90*time.Second {
+		t.Errorf("delay = %v", d)
+	}
+
+	for _, bad := range []Config{
+		{"rig add": {Mode: ModePrompt}},
+		{"close": {Mode: "maybe"}},
+		{"close": {Mode: ModeDelay, Delay: "soon"}},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%v) should fail", bad)
+		}
+	}
+}
+
+func TestSecondOperatorRequest(t *testing.T) {
+	town := t.TempDir()
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	req, err := Open(town, "rig remove", "alpha", ModeSecondOperator, "alice", now, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := Open(town, "rig remove", "alpha", ModeSecondOperator, "alice", now, now.Add(time.Minute))
+	if again.ID != req.ID || again.Ready(now) {
+		t.Fatalf("re-open = %+v, want the same pending request", again)
+	}
+	if _, err := Approve(town, req.ID, "alice", now); err == nil {
+		t.Error("requester must not approve their own request")
+	}
+	if _, err := Approve(town, req.ID, "bob", now); err != nil {
+		t.Fatal(err)
+	}
+	ready, _ := Open(town, "rig remove", "alpha", ModeSecondOperator, "alice", now, now.Add(2*time.Minute))
+	if !ready.Ready(now) || ready.ApprovedBy != "bob" {
+		t.Fatalf("after approval = %+v", ready)
+	}
+	if err := Use(town, req.ID, now); err != nil {
+		t.Fatal(err)
+	}
+	if reqs, _ := List(town, now); len(reqs) != 0 {
+		t.Errorf("used request still listed: %+v", reqs)
+	}
+	next, _ := Open(town, "rig remove", "alpha", ModeSecondOperator, "alice", now, now)
+	if next.ID == req.ID {
+		t.Error("a used request must not confirm another run")
+	}
+}
+
+func TestDelayRequest(t *testing.T) {
+	town := t.TempDir()
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	req, err := Open(town, "shutdown", "", ModeDelay, "alice", now.Add(time.Minute), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Ready(now.Add(30 * time.Second)) {
+		t.Error("ready before the delay passed")
+	}
+	if !req.Ready(now.Add(time.Minute)) {
+		t.Error("not ready after the delay")
+	}
+	if req.Ready(now.Add(RequestTTL)) {
+		t.Error("ready after expiry")
+	}
+	if _, err := Approve(town, req.ID, "bob", now); err == nil {
+		t.Error("a delay request can't be approved")
+	}
+}
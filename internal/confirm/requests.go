@@ -0,0 +1,236 @@
+package confirm
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// RequestTTL is how long a pending confirmation request stays usable.
+const RequestTTL = 24 * time.Hour
+
+// Request is a destructive command waiting on a second operator or a
+// delay. The command that opened it proceeds when re-run by the same
+// requester once the request is Ready, and the request is then used up.
+type Request struct {
+	ID         string    `json:"id"`
+	Command    string    `json:"command"`
+	Target     string    `json:"target"`
+	Mode       string    `json:"mode"`
+	Requester  string    `json:"requester"`
+	Created    time.Time `json:"created"`
+	NotBefore  time.Time `json:"not_before,omitempty"` // Delay mode
+	ApprovedBy string    `json:"approved_by,omitempty"`
+	ApprovedAt time.Time `json:"approved_at,omitempty"`
+	UsedAt     time.Time `json:"used_at,omitempty"`
+}
+
+// Live reports whether the request can still be approved or used.
+func (r Request) Live(now time.Time) bool {
+	return r.UsedAt.IsZero() && now.Before(r.Created.Add(RequestTTL))
+}
+
+// Ready reports whether the command may now proceed.
+func (r Request) Ready(now time.Time) bool {
+	if !r.Live(now) {
+		return false
+	}
+	switch r.Mode {
+	case ModeSecondOperator:
+		return r.ApprovedBy != ""
+	case ModeDelay:
+		return !now.Before(r.NotBefore)
+	}
+	return false
+}
+
+// Dir returns the directory holding confirmation requests.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "confirm")
+}
+
+func requestsPath(townRoot string) string { return filepath.Join(Dir(townRoot), "requests.jsonl") }
+
+// Open returns the live request for command and target by requester,
+// creating one if there is none. notBefore is only used for new requests.
+func Open(townRoot, command, target, mode, requester string, notBefore, now time.Time) (*Request, error) {
+	var out Request
+	err := withLock(townRoot, func(path string) error {
+		reqs, err := readRequests(path)
+		if err != nil {
+			return err
+		}
+		reqs = prune(reqs, now)
+		for _, r := range reqs {
+			if r.Live(now) && r.Command == command && r.Target == target && r.Mode == mode && r.Requester == requester {
+				out = r
+				return writeRequests(path, reqs)
+			}
+		}
+		out = Request{
+			ID:        newID(),
+			Command:   command,
+			Target:    target,
+			Mode:      mode,
+			Requester: requester,
+			Created:   now.UTC(),
+		}
+		if mode == ModeDelay {
+			out.NotBefore = notBefore.UTC()
+		}
+		return writeRequests(path, append(reqs, out))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Approve records a second operator's approval. The requester cannot
+// approve their own request.
+func Approve(townRoot, id, approver string, now time.Time) (*Request, error) {
+	var out Request
+	err := withLock(townRoot, func(path string) error {
+		reqs, err := readRequests(path)
+		if err != nil {
+			return err
+		}
+		for i := range reqs {
+			r := &reqs[i]
+			if r.ID != id {
+				continue
+			}
+			switch {
+			case !r.Live(now):
+				return fmt.Errorf("%s has expired or was already used", id)
+			case r.Mode != ModeSecondOperator:
+				return fmt.Errorf("%s is waiting on a delay, not an approval", id)
+			case approver == "" || strings.EqualFold(approver, r.Requester):
+				return fmt.Errorf("%s must be approved by an operator other than %s", id, r.Requester)
+			case r.ApprovedBy != "":
+				return fmt.Errorf("%s was already approved by %s", id, r.ApprovedBy)
+			}
+			r.ApprovedBy = approver
+			r.ApprovedAt = now.UTC()
+			out = *r
+			return writeRequests(path, reqs)
+		}
+		return fmt.Errorf("no confirmation request %q", id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Use marks a ready request as used so it cannot confirm another run.
+func Use(townRoot, id string, now time.Time) error {
+	return withLock(townRoot, func(path string) error {
+		reqs, err := readRequests(path)
+		if err != nil {
+			return err
+		}
+		for i := range reqs {
+			if reqs[i].ID != id {
+				continue
+			}
+			if !reqs[i].Ready(now) {
+				return fmt.Errorf("%s is not ready", id)
+			}
+			reqs[i].UsedAt = now.UTC()
+			return writeRequests(path, reqs)
+		}
+		return fmt.Errorf("no confirmation request %q", id)
+	})
+}
+
+// List returns the live requests, oldest first.
+func List(townRoot string, now time.Time) ([]Request, error) {
+	reqs, err := readRequests(requestsPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	var out []Request
+	for _, r := range reqs {
+		if r.Live(now) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+// prune drops requests that can no longer be used.
+func prune(reqs []Request, now time.Time) []Request {
+	out := reqs[:0]
+	for _, r := range reqs {
+		if r.Live(now) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func newID() string {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return "c-" + hex.EncodeToString(b[:])
+}
+
+func withLock(townRoot string, fn func(path string) error) error {
+	path := requestsPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating confirm directory: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking confirmation requests: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+	return fn(path)
+}
+
+func readRequests(path string) ([]Request, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var reqs []Request
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Request
+		if err := json.Unmarshal(scanner.Bytes(), &r); err == nil {
+			reqs = append(reqs, r)
+		}
+	}
+	return reqs, scanner.Err()
+}
+
+func writeRequests(path string, reqs []Request) error {
+	var b strings.Builder
+	for _, r := range reqs {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil { //nolint:gosec // G306: runtime state
+		return err
+	}
+	return os.Rename(tmp, path)
+}
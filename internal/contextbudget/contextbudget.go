@@ -0,0 +1,262 @@
+// Package contextbudget estimates how full an agent session's context
+// window is and decides when to compact or rotate it, so agents get a fresh
+// window before the model degrades mid-task rather than after.
+//
+// Usage comes from the session's transcript (the token counts of the last
+// assistant turn, or the transcript size when it has none) and from the
+// runtime's own pane signals such as Claude Code's "Context left until
+// auto-compact: 12%". The higher estimate wins.
+//
+// Thresholds are set per runtime in the agent's runtime config:
+//
+//	"context_budget": {
+//	  "max_tokens": 200000,
+//	  "compact_at": 0.70,
+//	  "rotate_at": 0.85,
+//	  "compact_command": "/compact"
+//	}
+package contextbudget
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+)
+
+// Defaults used when a runtime sets no context_budget.
+const (
+	DefaultMaxTokens = 200000
+	DefaultCompactAt = 0.70
+	DefaultRotateAt  = 0.85
+
+	// claudeCompactCommand compacts a Claude Code session in place.
+	claudeCompactCommand = "/compact"
+)
+
+// transcriptBytesPerToken converts a transcript's size to a rough token
+// count. Transcripts are JSON with tool metadata around the text, so this is
+// well above the ~4 characters per token of plain text.
+const transcriptBytesPerToken = 8
+
+// Actions chosen for a session.
+const (
+	ActionNone    = "none"    // Under budget
+	ActionCompact = "compact" // Compact the context in place
+	ActionRotate  = "rotate"  // Hand off to a fresh session
+)
+
+// Config is a runtime's context budget.
+type Config struct {
+	// Disabled turns context budget checks off for the runtime.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// MaxTokens is the runtime's context window (default 200000).
+	MaxTokens int `json:"max_tokens,omitempty"`
+
+	// CompactAt is the fraction of the window at which the session is
+	// compacted (default 0.70).
+	CompactAt float64 `json:"compact_at,omitempty"`
+
+	// RotateAt is the fraction at which the session hands off to a fresh
+	// one (default 0.85).
+	RotateAt float64 `json:"rotate_at,omitempty"`
+
+	// CompactCommand is typed into the session to compact it. Defaults to
+	// /compact for Claude; runtimes without one rotate instead of compacting.
+	CompactCommand string `json:"compact_command,omitempty"`
+}
+
+// Resolve returns c with defaults filled in for the runtime provider. A nil
+// config gets all defaults.
+func (c *Config) Resolve(provider string) Config {
+	var r Config
+	if c != nil {
+		r = *c
+	}
+	if r.MaxTokens <= 0 {
+		r.MaxTokens = DefaultMaxTokens
+	}
+	if r.CompactAt <= 0 {
+		r.CompactAt = DefaultCompactAt
+	}
+	if r.RotateAt <= 0 {
+		r.RotateAt = DefaultRotateAt
+	}
+	if r.CompactCommand == "" && (provider == "" || provider == "claude") {
+		r.CompactCommand = claudeCompactCommand
+	}
+	return r
+}
+
+// Validate checks the thresholds. A nil config is valid.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens must not be negative")
+	}
+	for name, v := range map[string]float64{"compact_at": c.CompactAt, "rotate_at": c.RotateAt} {
+		if v < 0 || v > 1 {
+			return fmt.Errorf("%s must be a fraction between 0 and 1, got %v", name, v)
+		}
+	}
+	if c.CompactAt > 0 && c.RotateAt > 0 && c.CompactAt >= c.RotateAt {
+		return fmt.Errorf("compact_at (%v) must be below rotate_at (%v)", c.CompactAt, c.RotateAt)
+	}
+	return nil
+}
+
+// Decide picks the action for a measured usage. Without a compact command
+// the compact threshold rotates too.
+func (c Config) Decide(u Usage) string {
+	switch {
+	case c.Disabled:
+		return ActionNone
+	case u.Fraction >= c.RotateAt:
+		return ActionRotate
+	case u.Fraction >= c.CompactAt && c.CompactCommand == "":
+		return ActionRotate
+	case u.Fraction >= c.CompactAt:
+		return ActionCompact
+	}
+	return ActionNone
+}
+
+// Usage is a session's estimated context use.
+type Usage struct {
+	Tokens    int     `json:"tokens,omitempty"`
+	MaxTokens int     `json:"max_tokens"`
+	Fraction  float64 `json:"fraction"`
+	Source    string  `json:"source"` // "transcript", "transcript-size", "pane" or "none"
+}
+
+// Usage sources.
+const (
+	SourceTranscript     = "transcript"
+	SourceTranscriptSize = "transcript-size"
+	SourcePane           = "pane"
+	SourceNone           = "none"
+)
+
+// paneContextRe matches runtime status lines reporting the context left,
+// e.g. "Context left until auto-compact: 12%" or "Context low (8% remaining)".
+var paneContextRe = regexp.MustCompile(`(?i)context (?:left until auto-compact|low|remaining)\D{0,20}?(\d{1,3})%`)
+
+// FromPane returns the used fraction of the window reported in a pane
+// capture, using the last matching line.
+func FromPane(lines []string) (float64, bool) {
+	for i := len(lines) - 1; i >= 0; i-- {
+		if m := paneContextRe.FindStringSubmatch(lines[i]); m != nil {
+			left, err := strconv.Atoi(m[1])
+			if err != nil || left > 100 {
+				continue
+			}
+			return 1 - float64(left)/100, true
+		}
+	}
+	return 0, false
+}
+
+// FromEvents returns the context size of the last assistant turn: every
+// input token it was sent (fresh and cached) plus what it wrote.
+func FromEvents(events []agentlog.AgentEvent) (int, bool) {
+	for i := len(events) - 1; i >= 0; i-- {
+		ev := events[i]
+		if ev.EventType != "usage" {
+			continue
+		}
+		return ev.InputTokens + ev.CacheReadTokens + ev.CacheCreationTokens + ev.OutputTokens, true
+	}
+	return 0, false
+}
+
+// Measure estimates usage from a transcript (empty path: none) and a pane
+// capture, keeping the higher estimate.
+func Measure(transcriptPath string, pane []string, maxTokens int) Usage {
+	u := Usage{MaxTokens: maxTokens, Source: SourceNone}
+	if transcriptPath != "" {
+		if events, err := agentlog.ReadClaudeCodeTranscript(transcriptPath, ""); err == nil {
+			if tokens, ok := FromEvents(events); ok {
+				u.Tokens, u.Source = tokens, SourceTranscript
+			}
+		}
+		if u.Source == SourceNone {
+			if info, err := os.Stat(transcriptPath); err == nil {
+				u.Tokens, u.Source = int(info.Size()/transcriptBytesPerToken), SourceTranscriptSize
+			}
+		}
+		if maxTokens > 0 {
+			u.Fraction = float64(u.Tokens) / float64(maxTokens)
+		}
+	}
+	if f, ok := FromPane(pane); ok && f > u.Fraction {
+		u.Fraction, u.Source = f, SourcePane
+		u.Tokens = int(f * float64(maxTokens))
+	}
+	return u
+}
+
+// Cooldown is how long after acting on a session the same action is not
+// repeated: the transcript only reflects a compaction after the agent's
+// next turn, and a rotation takes the agent a while to wrap up.
+const Cooldown = 15 * time.Minute
+
+// LastAction is the last action taken on a session.
+type LastAction struct {
+	Action   string    `json:"action"`
+	Fraction float64   `json:"fraction"`
+	At       time.Time `json:"at"`
+}
+
+func statePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "context-budget", "actions.json")
+}
+
+// LoadActions returns the last action per session.
+func LoadActions(townRoot string) (map[string]LastAction, error) {
+	actions := make(map[string]LastAction)
+	data, err := os.ReadFile(statePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return actions, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &actions); err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
+
+// SaveActions writes the last action per session.
+func SaveActions(townRoot string, actions map[string]LastAction) error {
+	data, err := json.MarshalIndent(actions, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := statePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644) //nolint:gosec // G306: runtime state
+}
+
+// ShouldAct reports whether action should be taken on a session given its
+// last action: not when the same action was taken within the cooldown, but
+// always when escalating from compact to rotate.
+func ShouldAct(last LastAction, action string, now time.Time) bool {
+	if action == ActionNone {
+		return false
+	}
+	if last.Action != action {
+		return true
+	}
+	return now.Sub(last.At) >= Cooldown
+}
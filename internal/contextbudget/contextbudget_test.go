@@ -0,0 +1,171 @@
+package contextbudget
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+)
+
+func TestResolveDefaults(t *testing.T) {
+	var nilCfg *Config
+	c := nilCfg.Resolve("claude")
+	if c.MaxTokens != DefaultMaxTokens || c.CompactAt != DefaultCompactAt || c.RotateAt != DefaultRotateAt {
+		t.Errorf("defaults = %+v", c)
+	}
+	if c.CompactCommand != "/compact" {
+		t.Errorf("claude compact command = %q, want /compact", c.CompactCommand)
+	}
+	if got := (&Config{}).Resolve("codex").CompactCommand; got != "" {
+		t.Errorf("codex compact command = %q, want none", got)
+	}
+	custom := (&Config{MaxTokens: 1000000, RotateAt: 0.9}).Resolve("claude")
+	if custom.MaxTokens != 1000000 || custom.RotateAt != 0.9 || custom.CompactAt != DefaultCompactAt {
+		t.Errorf("custom = %+v", custom)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		cfg     *Config
+		wantErr bool
+	}{
+		{nil, false},
+		{&Config{CompactAt: 0.6, RotateAt: 0.8}, false},
+		{&Config{CompactAt: 0.9, RotateAt: 0.8}, true},
+		{&Config{RotateAt: 1.5}, true},
+		{&Config{MaxTokens: -1}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) = %v, wantErr %v", tt.cfg, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDecide(t *testing.T) {
+	claude := (&Config{}).Resolve("claude")
+	other := (&Config{}).Resolve("codex")
+	tests := []struct {
+		cfg      Config
+		fraction float64
+		want     string
+	}{
+		{claude, 0.5, ActionNone},
+		{claude, 0.72, ActionCompact},
+		{claude, 0.9, ActionRotate},
+		{other, 0.72, ActionRotate},
+		{Config{Disabled: true, CompactAt: 0.7, RotateAt: 0.85}, 0.99, ActionNone},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.Decide(Usage{Fraction: tt.fraction}); got != tt.want {
+			t.Errorf("Decide(%v) with %+v = %s, want %s", tt.fraction, tt.cfg, got, tt.want)
+		}
+	}
+}
+
+func TestFromPane(t *testing.T) {
+	tests := []struct {
+		lines []string
+		want  float64
+		ok    bool
+	}{
+		{[]string{"> ", "  Context left until auto-compact: 12%"}, 0.88, true},
+		{[]string{"Context low (8% remaining) · Run /compact to compact & continue"}, 0.92, true},
+		{[]string{"Context left until auto-compact: 40%", "", "Context left until auto-compact: 20%"}, 0.80, true},
+		{[]string{"● Running tests", "> "}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := FromPane(tt.lines)
+		if ok != tt.ok || (ok && (got < tt.want-0.001 || got > tt.want+0.001)) {
+			t.Errorf("FromPane(%q) = %v, %v; want %v, %v", tt.lines, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFromEventsUsesLastTurn(t *testing.T) {
+	events := []agentlog.AgentEvent{
+		{EventType: "usage", InputTokens: 10, CacheReadTokens: 1000},
+		{EventType: "text", Content: "hi"},
+		{EventType: "usage", InputTokens: 5, CacheReadTokens: 90000, CacheCreationTokens: 4000, OutputTokens: 995},
+		{EventType: "tool_use"},
+	}
+	got, ok := FromEvents(events)
+	if !ok || got != 95000 {
+		t.Errorf("FromEvents = %d, %v; want 95000, true", got, ok)
+	}
+	if _, ok := FromEvents([]agentlog.AgentEvent{{EventType: "text"}}); ok {
+		t.Error("FromEvents without usage should report no usage")
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	dir := t.TempDir()
+	transcript := filepath.Join(dir, "session.jsonl")
+	line := `{"type":"assistant","timestamp":"2026-01-02T03:04:05Z","message":{"role":"assistant","content":[{"type":"text","text":"ok"}],"usage":{"input_tokens":10,"cache_read_input_tokens":99990,"output_tokens":0}}}` + "\n"
+	if err := os.WriteFile(transcript, []byte(line), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	u := Measure(transcript, nil, 200000)
+	if u.Source != SourceTranscript || u.Tokens != 100000 || u.Fraction != 0.5 {
+		t.Errorf("transcript usage = %+v", u)
+	}
+
+	u = Measure(transcript, []string{"Context left until auto-compact: 10%"}, 200000)
+	if u.Source != SourcePane || u.Fraction < 0.89 {
+		t.Errorf("pane should win when higher: %+v", u)
+	}
+
+	noUsage := filepath.Join(dir, "empty.jsonl")
+	if err := os.WriteFile(noUsage, make([]byte, 800), 0644); err != nil {
+		t.Fatal(err)
+	}
+	u = Measure(noUsage, nil, 200000)
+	if u.Source != SourceTranscriptSize || u.Tokens != 100 {
+		t.Errorf("size fallback = %+v", u)
+	}
+
+	if u := Measure("", nil, 200000); u.Source != SourceNone || u.Fraction != 0 {
+		t.Errorf("no signals = %+v", u)
+	}
+}
+
+func TestShouldAct(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	recent := LastAction{Action: ActionCompact, At: now.Add(-5 * time.Minute)}
+	if ShouldAct(recent, ActionCompact, now) {
+		t.Error("should not repeat a compact within the cooldown")
+	}
+	if !ShouldAct(recent, ActionRotate, now) {
+		t.Error("should escalate from compact to rotate")
+	}
+	if !ShouldAct(LastAction{Action: ActionCompact, At: now.Add(-Cooldown)}, ActionCompact, now) {
+		t.Error("should act again after the cooldown")
+	}
+	if ShouldAct(LastAction{}, ActionNone, now) {
+		t.Error("should never act on none")
+	}
+}
+
+func TestActionsRoundTrip(t *testing.T) {
+	town := t.TempDir()
+	actions, err := LoadActions(town)
+	if err != nil || len(actions) != 0 {
+		t.Fatalf("LoadActions on empty town = %v, %v", actions, err)
+	}
+	at := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	actions["gt-toast"] = LastAction{Action: ActionRotate, Fraction: 0.9, At: at}
+	if err := SaveActions(town, actions); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadActions(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := got["gt-toast"]; a.Action != ActionRotate || !a.At.Equal(at) {
+		t.Errorf("round trip = %+v", a)
+	}
+}
@@ -0,0 +1,290 @@
+// Package contextpack attaches curated context to beads: the files, docs
+// and prior beads a polecat should read before starting. Packs are kept in
+// the town's state file; at sling time the pack is materialized into a
+// briefing that gt prime shows the polecat alongside its hooked work, so it
+// doesn't spend its first minutes rediscovering where to look.
+package contextpack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Entry kinds.
+const (
+	KindFile = "file" // Repo-relative path
+	KindDoc  = "doc"  // Documentation path or URL
+	KindBead = "bead" // Prior bead whose outcome is relevant
+)
+
+// Entry is one item in a context pack.
+type Entry struct {
+	Kind string    `json:"kind"`
+	Ref  string    `json:"ref"`
+	Note string    `json:"note,omitempty"` // Why it matters
+	By   string    `json:"by,omitempty"`
+	At   time.Time `json:"at"`
+}
+
+// Pack is the curated context for one bead.
+type Pack struct {
+	Bead    string   `json:"bead"`
+	Entries []*Entry `json:"entries"`
+}
+
+// Add adds entries, replacing any entry with the same ref. It returns the
+// number of entries that were new.
+func (p *Pack) Add(entries ...*Entry) int {
+	added := 0
+	for _, e := range entries {
+		if i := p.index(e.Ref); i >= 0 {
+			p.Entries[i] = e
+			continue
+		}
+		p.Entries = append(p.Entries, e)
+		added++
+	}
+	return added
+}
+
+// Remove drops the entry with ref, reporting whether there was one.
+func (p *Pack) Remove(ref string) bool {
+	i := p.index(ref)
+	if i < 0 {
+		return false
+	}
+	p.Entries = append(p.Entries[:i], p.Entries[i+1:]...)
+	return true
+}
+
+func (p *Pack) index(ref string) int {
+	for i, e := range p.Entries {
+		if e.Ref == ref {
+			return i
+		}
+	}
+	return -1
+}
+
+// Classify guesses an entry's kind from its ref: URLs and documentation
+// files are docs, a ref that looksLikeBead says is a bead ID (and has no
+// path separator or file extension) is a bead, and anything else is a file.
+func Classify(ref string, looksLikeBead func(string) bool) string {
+	if strings.HasPrefix(ref, "http://") || strings.HasPrefix(ref, "https://") {
+		return KindDoc
+	}
+	ext := strings.ToLower(filepath.Ext(ref))
+	if !strings.Contains(ref, "/") && !isFileExt(ext) && looksLikeBead(ref) {
+		return KindBead
+	}
+	switch ext {
+	case ".md", ".markdown", ".rst", ".adoc", ".txt":
+		return KindDoc
+	}
+	if strings.HasPrefix(ref, "docs/") || strings.HasPrefix(ref, "doc/") {
+		return KindDoc
+	}
+	return KindFile
+}
+
+// isFileExt reports whether ext looks like a file extension rather than a
+// child bead suffix (gt-abc.1).
+func isFileExt(ext string) bool {
+	if len(ext) < 2 {
+		return false
+	}
+	for _, c := range ext[1:] {
+		if c < '0' || c > '9' {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidKind reports whether kind is a known entry kind.
+func ValidKind(kind string) bool {
+	return kind == KindFile || kind == KindDoc || kind == KindBead
+}
+
+// State holds the context packs, by bead ID.
+type State struct {
+	Packs map[string]*Pack `json:"packs"`
+}
+
+// StatePath returns the path of the context pack state file.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "context-packs.json")
+}
+
+// LoadState reads the state, returning an empty state when the file doesn't
+// exist yet.
+func LoadState(townRoot string) (*State, error) {
+	state := &State{Packs: make(map[string]*Pack)}
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Packs == nil {
+		state.Packs = make(map[string]*Pack)
+	}
+	return state, nil
+}
+
+// Save writes the state, dropping empty packs.
+func (s *State) Save(townRoot string) error {
+	for id, p := range s.Packs {
+		if len(p.Entries) == 0 {
+			delete(s.Packs, id)
+		}
+	}
+	return util.EnsureDirAndWriteJSON(StatePath(townRoot), s)
+}
+
+// Pack returns the bead's pack, creating an empty one if needed.
+func (s *State) Pack(bead string) *Pack {
+	p := s.Packs[bead]
+	if p == nil {
+		p = &Pack{Bead: bead}
+		s.Packs[bead] = p
+	}
+	return p
+}
+
+// BeadInfo is what a briefing shows of a referenced bead.
+type BeadInfo struct {
+	Title   string
+	Status  string
+	Summary string // Description, quoted in part
+}
+
+// Resolver looks up what a briefing shows for each entry. Nil funcs skip
+// the lookup.
+type Resolver struct {
+	// FileLines returns the line count of a repo-relative file, or an error
+	// when it doesn't exist.
+	FileLines func(path string) (int, error)
+	// Bead returns a referenced bead.
+	Bead func(id string) (*BeadInfo, error)
+}
+
+// summaryMaxLines bounds how much of a referenced bead a briefing quotes.
+const summaryMaxLines = 6
+
+// Materialize renders the pack as the markdown briefing a polecat reads at
+// prime time: files first, then docs, then prior beads with their outcome.
+// Refs that no longer resolve are kept and marked, so the dispatcher's
+// intent survives a rename.
+func Materialize(p *Pack, r Resolver) string {
+	var files, docs, beads []string
+	for _, e := range p.Entries {
+		line := "- " + e.Ref
+		switch e.Kind {
+		case KindBead:
+			var info *BeadInfo
+			if r.Bead != nil {
+				var err error
+				if info, err = r.Bead(e.Ref); err != nil {
+					line += " (not found)"
+				} else {
+					line += fmt.Sprintf(": %s [%s]", info.Title, info.Status)
+				}
+			}
+			line += noteSuffix(e.Note)
+			if info != nil {
+				for _, l := range summaryLines(info.Summary) {
+					line += "\n    " + l
+				}
+			}
+			beads = append(beads, line)
+		default:
+			if r.FileLines != nil && !strings.Contains(e.Ref, "://") {
+				if n, err := r.FileLines(e.Ref); err != nil {
+					line += " (missing)"
+				} else {
+					line += fmt.Sprintf(" (%d lines)", n)
+				}
+			}
+			line += noteSuffix(e.Note)
+			if e.Kind == KindDoc {
+				docs = append(docs, line)
+			} else {
+				files = append(files, line)
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, section := range []struct {
+		title string
+		lines []string
+	}{{"Files", files}, {"Docs", docs}, {"Prior beads", beads}} {
+		if len(section.lines) == 0 {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(section.title + ":\n")
+		for _, l := range section.lines {
+			b.WriteString(l + "\n")
+		}
+	}
+	return b.String()
+}
+
+func noteSuffix(note string) string {
+	if note == "" {
+		return ""
+	}
+	return " — " + note
+}
+
+func summaryLines(s string) []string {
+	var lines []string
+	for _, l := range strings.Split(strings.TrimSpace(s), "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	if len(lines) > summaryMaxLines {
+		lines = append(lines[:summaryMaxLines], "...")
+	}
+	return lines
+}
+
+// BriefingPath returns where the materialized briefing for a hooked bead
+// is written.
+func BriefingPath(townRoot, bead string) string {
+	return filepath.Join(townRoot, ".runtime", "context-packs", bead+".md")
+}
+
+// WriteBriefing writes a materialized briefing for the hooked bead.
+func WriteBriefing(townRoot, bead, briefing string) error {
+	path := BriefingPath(townRoot, bead)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(briefing), 0644) //nolint:gosec // G306: briefing is not sensitive
+}
+
+// ReadBriefing returns the materialized briefing for the hooked bead, or ""
+// when there is none.
+func ReadBriefing(townRoot, bead string) string {
+	data, err := os.ReadFile(BriefingPath(townRoot, bead)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
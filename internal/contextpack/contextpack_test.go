@@ -0,0 +1,123 @@
+package contextpack
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func beadLike(s string) bool { return strings.HasPrefix(s, "gt-") }
+
+func TestClassify(t *testing.T) {
+	tests := map[string]string{
+		"https://go.dev/doc/effective_go":   KindDoc,
+		"docs/reference.md":                 KindDoc,
+		"README.md":                         KindDoc,
+		"docs/diagrams/flow.svg":            KindDoc,
+		"internal/refinery/engineer.go":     KindFile,
+		"Makefile":                          KindFile,
+		"gt-abc":                            KindBead,
+		"gt-abc.1":                          KindBead,
+		"gt-config.go":                      KindFile,
+		"internal/gt-abc":                   KindFile,
+		"notes.txt":                         KindDoc,
+		"internal/refinery/testdata/a.json": KindFile,
+	}
+	for ref, want := range tests {
+		if got := Classify(ref, beadLike); got != want {
+			t.Errorf("Classify(%q) = %s, want %s", ref, got, want)
+		}
+	}
+}
+
+func TestPackAddRemove(t *testing.T) {
+	p := &Pack{Bead: "gt-abc"}
+	if n := p.Add(&Entry{Kind: KindFile, Ref: "a.go"}, &Entry{Kind: KindBead, Ref: "gt-old"}); n != 2 {
+		t.Fatalf("Add = %d, want 2", n)
+	}
+	if n := p.Add(&Entry{Kind: KindFile, Ref: "a.go", Note: "entry point"}); n != 0 || len(p.Entries) != 2 || p.Entries[0].Note != "entry point" {
+		t.Errorf("re-adding should replace in place: n=%d, entries %+v", n, p.Entries)
+	}
+	if !p.Remove("gt-old") || p.Remove("gt-old") || len(p.Entries) != 1 {
+		t.Errorf("Remove: entries %+v", p.Entries)
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	town := t.TempDir()
+	state, err := LoadState(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Pack("gt-abc").Add(&Entry{Kind: KindFile, Ref: "a.go"})
+	state.Pack("gt-empty")
+	if err := state.Save(town); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadState(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Packs) != 1 || loaded.Packs["gt-abc"].Entries[0].Ref != "a.go" {
+		t.Errorf("loaded = %+v; empty packs should be dropped", loaded.Packs)
+	}
+}
+
+func TestMaterialize(t *testing.T) {
+	p := &Pack{Bead: "gt-abc", Entries: []*Entry{
+		{Kind: KindBead, Ref: "gt-old", Note: "same bug in the witness"},
+		{Kind: KindFile, Ref: "internal/refinery/engineer.go", Note: "merge loop"},
+		{Kind: KindDoc, Ref: "https://example.com/design"},
+		{Kind: KindFile, Ref: "internal/gone.go"},
+		{Kind: KindBead, Ref: "gt-missing"},
+	}}
+	r := Resolver{
+		FileLines: func(path string) (int, error) {
+			if path == "internal/gone.go" {
+				return 0, fmt.Errorf("not found")
+			}
+			return 120, nil
+		},
+		Bead: func(id string) (*BeadInfo, error) {
+			if id == "gt-missing" {
+				return nil, fmt.Errorf("not found")
+			}
+			return &BeadInfo{Title: "Witness double-nudges", Status: "closed", Summary: "Fixed by\n\ndebouncing nudges."}, nil
+		},
+	}
+
+	want := `Files:
+- internal/refinery/engineer.go (120 lines) — merge loop
+- internal/gone.go (missing)
+
+Docs:
+- https://example.com/design
+
+Prior beads:
+- gt-old: Witness double-nudges [closed] — same bug in the witness
+    Fixed by
+    debouncing nudges.
+- gt-missing (not found)
+`
+	if got := Materialize(p, r); got != want {
+		t.Errorf("Materialize =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := Materialize(p, Resolver{}); !strings.Contains(got, "- internal/gone.go\n") || !strings.Contains(got, "- gt-old — same bug") {
+		t.Errorf("without a resolver refs should be listed as-is:\n%s", got)
+	}
+}
+
+func TestBriefing(t *testing.T) {
+	town := t.TempDir()
+	if got := ReadBriefing(town, "gt-abc"); got != "" {
+		t.Errorf("ReadBriefing before write = %q", got)
+	}
+	if err := WriteBriefing(town, "gt-abc", "Files:\n- a.go\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got := ReadBriefing(town, "gt-abc"); got != "Files:\n- a.go\n" {
+		t.Errorf("ReadBriefing = %q", got)
+	}
+}
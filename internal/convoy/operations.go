@@ -288,14 +288,21 @@ func feedNextReadyIssue(ctx context.Context, store beadsdk.Storage, townRoot, co
 		return
 	}
 
-	// Extract base_branch from convoy description fields
+	// Extract base_branch and dispatch ordering from convoy description fields
 	var baseBranch string
+	var fields *beads.ConvoyFields
 	if convoy, err := store.GetIssue(ctx, convoyID); err == nil && convoy != nil {
-		if cf := beads.ParseConvoyFields(&beads.Issue{Description: convoy.Description}); cf != nil {
-			baseBranch = cf.BaseBranch
+		if fields = beads.ParseConvoyFields(&beads.Issue{Description: convoy.Description}); fields != nil {
+			baseBranch = fields.BaseBranch
 		}
 	}
 
+	gate := newStoreGate(ctx, store, fields, tracked)
+	if len(gate.waitConvoys) > 0 {
+		logger("%s: convoy %s: waiting on %s, not feeding", caller, convoyID, gate.WaitingOn())
+		return
+	}
+
 	// Sort by priority (lower = higher) then by ID for deterministic tie-breaking.
 	sort.Slice(tracked, func(i, j int) bool {
 		if tracked[i].Priority != tracked[j].Priority {
@@ -318,6 +325,11 @@ func feedNextReadyIssue(ctx context.Context, store beadsdk.Storage, townRoot, co
 			continue
 		}
 
+		if !gate.Allows(issue.ID) {
+			logger("%s: convoy %s: %s is in a later phase (waiting on %s), skipping", caller, convoyID, issue.ID, gate.WaitingOn())
+			continue
+		}
+
 		// Check blocking dependencies: blocks and conditional-blocks with
 		// non-closed targets prevent dispatch. parent-child is NOT treated
 		// as blocking (consistent with molecule step behavior).
@@ -349,6 +361,23 @@ func feedNextReadyIssue(ctx context.Context, store beadsdk.Storage, townRoot, co
 	logger("%s: convoy %s: no ready issues to feed", caller, convoyID)
 }
 
+// newStoreGate builds a convoy's dispatch Gate from fresh tracked-issue
+// status and the hq store, where prerequisite convoys live.
+func newStoreGate(ctx context.Context, store beadsdk.Storage, fields *beads.ConvoyFields, tracked []trackedIssue) *Gate {
+	statusByID := make(map[string]string, len(tracked))
+	for _, t := range tracked {
+		statusByID[t.ID] = t.Status
+	}
+	convoyStatus := func(id string) string {
+		issue, err := store.GetIssue(ctx, id)
+		if err != nil || issue == nil {
+			return ""
+		}
+		return string(issue.Status)
+	}
+	return NewGate(fields, func(id string) string { return statusByID[id] }, convoyStatus)
+}
+
 // getConvoyTrackedIssues returns issues tracked by a convoy with fresh status.
 // Uses SDK GetDependenciesWithMetadata filtered by tracks, then GetIssuesByIDs for current status.
 // When a StoreResolver is provided, cross-rig beads are resolved via direct store queries.
@@ -492,7 +521,7 @@ func fetchCrossRigBeadStatus(townRoot string, ids []string) map[string]*beadsdk.
 		args := append([]string{"show", "--json"}, prefixIDs...)
 		cmd := exec.Command("bd", args...)
 		cmd.Dir = rigPath
-		out, err := cmd.Output()
+		out, err := beads.CmdOutput(cmd)
 		if err != nil {
 			continue
 		}
@@ -0,0 +1,102 @@
+package convoy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// Gate decides which of a convoy's tracked issues may be dispatched now,
+// given the ordering the convoy declares in its description fields:
+//
+//   - after: convoys that must close before anything in this convoy dispatches
+//   - phase_N: waves of tracked issues; phase N+1 is held until every issue
+//     in phases 1..N is finished
+//
+// Unphased issues are never held by phases. An issue counts as finished once
+// it is closed; under the mr strategy the refinery only closes a bead after
+// merging it, so "closed" means "merged" there. Issues or convoys whose status
+// is unknown (e.g. split out of the convoy, or deleted) do not hold anything.
+type Gate struct {
+	fields      *beads.ConvoyFields
+	waitConvoys []string // after: convoys still open
+	current     int      // first phase with unfinished issues; 0 = all done
+	unfinished  int      // unfinished issues in the current phase
+}
+
+// NewGate evaluates a convoy's ordering. issueStatus returns the status of a
+// tracked issue ("" if not tracked); convoyStatus returns the status of
+// another convoy ("" if unknown). Either may be nil.
+func NewGate(fields *beads.ConvoyFields, issueStatus, convoyStatus func(id string) string) *Gate {
+	g := &Gate{fields: fields}
+	if fields == nil {
+		return g
+	}
+	if convoyStatus != nil {
+		for _, id := range fields.After {
+			if s := convoyStatus(id); s != "" && !isFinishedStatus(s) {
+				g.waitConvoys = append(g.waitConvoys, id)
+			}
+		}
+	}
+	for i, ids := range fields.Phases {
+		n := 0
+		for _, id := range ids {
+			if issueStatus == nil {
+				n++
+				continue
+			}
+			if s := issueStatus(id); s != "" && !isFinishedStatus(s) {
+				n++
+			}
+		}
+		if n > 0 {
+			g.current, g.unfinished = i+1, n
+			break
+		}
+	}
+	return g
+}
+
+func isFinishedStatus(status string) bool {
+	return status == "closed" || status == "tombstone"
+}
+
+// Allows reports whether issueID may be dispatched now.
+func (g *Gate) Allows(issueID string) bool {
+	if g == nil {
+		return true
+	}
+	if len(g.waitConvoys) > 0 {
+		return false
+	}
+	if g.current == 0 {
+		return true
+	}
+	return g.fields.PhaseOf(issueID) <= g.current
+}
+
+// CurrentPhase returns the 1-based phase being worked, or 0 when the convoy
+// has no phases or every phase is finished.
+func (g *Gate) CurrentPhase() int {
+	if g == nil {
+		return 0
+	}
+	return g.current
+}
+
+// WaitingOn describes what is holding dispatch back, or "" when the gate
+// holds nothing: open prerequisite convoys take precedence over phases.
+func (g *Gate) WaitingOn() string {
+	if g == nil {
+		return ""
+	}
+	if len(g.waitConvoys) > 0 {
+		return "convoy " + strings.Join(g.waitConvoys, ", ")
+	}
+	if g.current > 0 && g.current < len(g.fields.Phases) {
+		return fmt.Sprintf("phase %d (%d unfinished)", g.current, g.unfinished)
+	}
+	return ""
+}
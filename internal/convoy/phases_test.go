@@ -0,0 +1,181 @@
+package convoy
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	beadsdk "github.com/steveyegge/beads"
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func statusLookup(m map[string]string) func(string) string {
+	return func(id string) string { return m[id] }
+}
+
+func TestGate_NoFieldsAllowsEverything(t *testing.T) {
+	g := NewGate(nil, nil, nil)
+	if !g.Allows("gt-a") || g.CurrentPhase() != 0 || g.WaitingOn() != "" {
+		t.Errorf("nil fields should not gate: current=%d waiting=%q", g.CurrentPhase(), g.WaitingOn())
+	}
+}
+
+func TestGate_Phases(t *testing.T) {
+	fields := &beads.ConvoyFields{Phases: [][]string{{"gt-a", "gt-b"}, {"gt-c"}, {"gt-d"}}}
+
+	tests := []struct {
+		name        string
+		status      map[string]string
+		wantCurrent int
+		allowed     []string
+		held        []string
+		wantWaiting string
+	}{
+		{
+			name:        "phase 1 in flight",
+			status:      map[string]string{"gt-a": "closed", "gt-b": "in_progress", "gt-c": "open", "gt-d": "open", "gt-x": "open"},
+			wantCurrent: 1,
+			allowed:     []string{"gt-a", "gt-b", "gt-x"},
+			held:        []string{"gt-c", "gt-d"},
+			wantWaiting: "phase 1 (1 unfinished)",
+		},
+		{
+			name:        "phase 1 merged",
+			status:      map[string]string{"gt-a": "closed", "gt-b": "tombstone", "gt-c": "open", "gt-d": "open"},
+			wantCurrent: 2,
+			allowed:     []string{"gt-c"},
+			held:        []string{"gt-d"},
+			wantWaiting: "phase 2 (1 unfinished)",
+		},
+		{
+			name:        "last phase",
+			status:      map[string]string{"gt-a": "closed", "gt-b": "closed", "gt-c": "closed", "gt-d": "open"},
+			wantCurrent: 3,
+			allowed:     []string{"gt-d"},
+		},
+		{
+			name:        "untracked issues do not hold their phase",
+			status:      map[string]string{"gt-c": "open", "gt-d": "open"},
+			wantCurrent: 2,
+			allowed:     []string{"gt-c"},
+			held:        []string{"gt-d"},
+			wantWaiting: "phase 2 (1 unfinished)",
+		},
+		{
+			name:    "all done",
+			status:  map[string]string{"gt-a": "closed", "gt-b": "closed", "gt-c": "closed", "gt-d": "closed"},
+			allowed: []string{"gt-a", "gt-d"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGate(fields, statusLookup(tt.status), nil)
+			if g.CurrentPhase() != tt.wantCurrent {
+				t.Errorf("CurrentPhase() = %d, want %d", g.CurrentPhase(), tt.wantCurrent)
+			}
+			for _, id := range tt.allowed {
+				if !g.Allows(id) {
+					t.Errorf("Allows(%s) = false, want true", id)
+				}
+			}
+			for _, id := range tt.held {
+				if g.Allows(id) {
+					t.Errorf("Allows(%s) = true, want false", id)
+				}
+			}
+			if got := g.WaitingOn(); got != tt.wantWaiting {
+				t.Errorf("WaitingOn() = %q, want %q", got, tt.wantWaiting)
+			}
+		})
+	}
+}
+
+func TestGate_AfterConvoys(t *testing.T) {
+	fields := &beads.ConvoyFields{After: []string{"hq-cv-schema", "hq-cv-gone", "hq-cv-auth"}}
+	convoys := map[string]string{"hq-cv-schema": "open", "hq-cv-auth": "closed"}
+
+	g := NewGate(fields, nil, statusLookup(convoys))
+	if g.Allows("gt-a") {
+		t.Error("open prerequisite convoy should hold all dispatch")
+	}
+	if got := g.WaitingOn(); got != "convoy hq-cv-schema" {
+		t.Errorf("WaitingOn() = %q, want only the open prerequisite", got)
+	}
+
+	convoys["hq-cv-schema"] = "closed"
+	g = NewGate(fields, nil, statusLookup(convoys))
+	if !g.Allows("gt-a") || g.WaitingOn() != "" {
+		t.Errorf("closed prerequisites should release the convoy, waiting=%q", g.WaitingOn())
+	}
+}
+
+func TestFeedNextReadyIssue_HoldsLaterPhase(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows")
+	}
+
+	store, cleanup := setupTestStore(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	// Phase 1: an in-flight issue. Phase 2: a ready issue that must wait,
+	// ahead of the unphased one on priority.
+	newIssue := func(id string, status beadsdk.Status, priority int, assignee, desc string) *beadsdk.Issue {
+		return &beadsdk.Issue{
+			ID:          id,
+			Title:       id,
+			Description: desc,
+			Status:      status,
+			Assignee:    assignee,
+			Priority:    priority,
+			IssueType:   beadsdk.TypeTask,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+	}
+	convoy := newIssue("test-phased", beadsdk.StatusOpen, 2, "", "phase_1: test-p1\nphase_2: test-p2")
+	p1 := newIssue("test-p1", beadsdk.StatusInProgress, 2, "gastown/polecats/alpha", "")
+	p2 := newIssue("test-p2", beadsdk.StatusOpen, 0, "", "")
+	free := newIssue("test-free", beadsdk.StatusOpen, 3, "", "")
+
+	for _, iss := range []*beadsdk.Issue{convoy, p1, p2, free} {
+		if err := store.CreateIssue(ctx, iss, "test"); err != nil {
+			t.Fatalf("CreateIssue %s: %v", iss.ID, err)
+		}
+	}
+	for _, trackedID := range []string{p1.ID, p2.ID, free.ID} {
+		dep := &beadsdk.Dependency{
+			IssueID:     convoy.ID,
+			DependsOnID: trackedID,
+			Type:        beadsdk.DependencyType("tracks"),
+			CreatedAt:   now,
+			CreatedBy:   "test",
+		}
+		if err := store.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("AddDependency %s: %v", trackedID, err)
+		}
+	}
+
+	townRoot := setupTownRoot(t)
+	gtPath, logPath := makeGTStub(t, 0)
+	logger, _ := makeLogger()
+
+	feedNextReadyIssue(ctx, store, townRoot, convoy.ID, "test", logger, gtPath, func(string) bool { return false }, nil)
+
+	logData, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("gt stub was not called (no log file): %v", err)
+	}
+	logStr := strings.TrimSpace(string(logData))
+	if strings.Contains(logStr, "test-p2") {
+		t.Errorf("phase 2 issue dispatched while phase 1 is in flight: %q", logStr)
+	}
+	if !strings.Contains(logStr, "sling test-free testrig --no-boot") {
+		t.Errorf("expected the unphased issue to be fed, got %q", logStr)
+	}
+}
@@ -0,0 +1,64 @@
+package daemon
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultCIRemediationInterval is how often open MR branches are checked
+	// for CI failures. Follow-ups aren't latency-sensitive; CI itself takes
+	// minutes, so polling faster than this gains little.
+	defaultCIRemediationInterval = 10 * time.Minute
+
+	// ciRemediationTimeout bounds one gt ci check run (gh calls per MR).
+	ciRemediationTimeout = 5 * time.Minute
+)
+
+// CIRemediationConfig holds configuration for the ci_remediation patrol,
+// which runs `gt ci check` to sling CI failures back to their polecats.
+// Attempt limits live in town settings ("ci_remediation"), not here.
+type CIRemediationConfig struct {
+	// Enabled controls whether the patrol runs. Default: off (requires gh).
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to check (default 10m).
+	IntervalStr string `json:"interval,omitempty"`
+}
+
+// ciRemediationInterval returns the configured interval, or the default (10m).
+func ciRemediationInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.CIRemediation != nil {
+		if config.Patrols.CIRemediation.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.CIRemediation.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultCIRemediationInterval
+}
+
+// runCIRemediation runs `gt ci check` across all rigs.
+func (d *Daemon) runCIRemediation() {
+	if !IsPatrolEnabled(d.patrolConfig, "ci_remediation") {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, ciRemediationTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.gtPath, "ci", "check")
+	cmd.Dir = d.config.TownRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("ci_remediation: gt ci check failed: %v\nOutput: %s", err, string(output))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			d.logger.Printf("ci_remediation: %s", line)
+		}
+	}
+}
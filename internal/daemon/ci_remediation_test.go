@@ -0,0 +1,37 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCIRemediationPatrolOptIn(t *testing.T) {
+	if IsPatrolEnabled(nil, "ci_remediation") {
+		t.Error("ci_remediation should be disabled without config")
+	}
+	if IsPatrolEnabled(&DaemonPatrolConfig{Patrols: &PatrolsConfig{}}, "ci_remediation") {
+		t.Error("ci_remediation should be disabled when not configured")
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{CIRemediation: &CIRemediationConfig{Enabled: true}}}
+	if !IsPatrolEnabled(cfg, "ci_remediation") {
+		t.Error("ci_remediation should be enabled when opted in")
+	}
+}
+
+func TestCIRemediationInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *DaemonPatrolConfig
+		want time.Duration
+	}{
+		{"nil config", nil, defaultCIRemediationInterval},
+		{"unset", &DaemonPatrolConfig{Patrols: &PatrolsConfig{CIRemediation: &CIRemediationConfig{Enabled: true}}}, defaultCIRemediationInterval},
+		{"custom", &DaemonPatrolConfig{Patrols: &PatrolsConfig{CIRemediation: &CIRemediationConfig{IntervalStr: "3m"}}}, 3 * time.Minute},
+		{"invalid", &DaemonPatrolConfig{Patrols: &PatrolsConfig{CIRemediation: &CIRemediationConfig{IntervalStr: "soon"}}}, defaultCIRemediationInterval},
+	}
+	for _, tt := range tests {
+		if got := ciRemediationInterval(tt.cfg); got != tt.want {
+			t.Errorf("%s: ciRemediationInterval() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
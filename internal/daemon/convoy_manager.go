@@ -35,6 +35,7 @@ type strandedConvoyInfo struct {
 	ReadyIssues  []string  `json:"ready_issues"`
 	CreatedAt    time.Time `json:"created_at"`
 	BaseBranch   string    `json:"base_branch,omitempty"`
+	WaitingOn    string    `json:"waiting_on,omitempty"`
 }
 
 // ConvoyManager monitors beads events for issue closes and periodically scans for stranded convoys.
@@ -369,6 +370,8 @@ func (m *ConvoyManager) scan() {
 				continue
 			}
 			m.closeEmptyConvoy(c.ID)
+		} else if c.WaitingOn != "" {
+			m.logger("Convoy %s: %d tracked issues, waiting on %s", c.ID, c.TrackedCount, c.WaitingOn)
 		} else {
 			// Tracked issues exist but none are ready. This requires agent
 			// judgment (the deacon decides what to do). Log for visibility.
@@ -71,6 +71,10 @@ type Daemon struct {
 	// Only accessed from heartbeat loop goroutine - no sync needed.
 	syncFailures map[string]int
 
+	// activitySeen holds the last tmux activity (unix seconds) recorded per
+	// session, so idle sessions don't add signals. Heartbeat goroutine only.
+	activitySeen map[string]int64
+
 	// PATCH-006: Resolved binary paths to avoid PATH issues in subprocesses.
 	gtPath string
 	bdPath string
@@ -302,12 +306,21 @@ func (d *Daemon) Run() error {
 	}
 	defer func() { _ = os.Remove(d.config.PidFile) }() // best-effort cleanup
 
-	// Update state
+	// Update state. A previous run whose state still says running never
+	// shut down cleanly (the lock above guarantees it is gone now).
 	state := &State{
 		Running:   true,
 		PID:       os.Getpid(),
 		StartedAt: time.Now(),
 	}
+	uncleanExit := false
+	if prev, err := LoadState(d.config.TownRoot); err == nil {
+		uncleanExit = carryOverUncleanExit(prev, state)
+		if uncleanExit {
+			d.logger.Printf("Previous daemon (PID %d) exited without a clean shutdown; last seen %s",
+				prev.PID, state.LastUncleanExit.Format(time.RFC3339))
+		}
+	}
 	if err := SaveState(d.config.TownRoot, state); err != nil {
 		d.logger.Printf("Warning: failed to save state: %v", err)
 	}
@@ -335,9 +348,11 @@ func (d *Daemon) Run() error {
 	// Try opening beads stores eagerly; if Dolt isn't ready yet,
 	// pass the opener as a callback for lazy retry on each poll tick.
 	d.beadsStores = d.openBeadsStores()
+	// Rigs in quiet hours are treated as parked so the convoy manager
+	// feeds them no new work until the window ends.
 	isRigParked := func(rigName string) bool {
 		ok, _ := d.isRigOperational(rigName)
-		return !ok
+		return !ok || d.quietHoursActive(rigName)
 	}
 	var storeOpener func() map[string]beadsdk.Storage
 	if len(d.beadsStores) == 0 {
@@ -374,6 +389,9 @@ func (d *Daemon) Run() error {
 		}
 	}
 
+	// Start inbound webhook server if endpoints are configured in town settings.
+	d.startWebhookServer()
+
 	// Start dedicated Dolt health check ticker if Dolt server is configured.
 	// This runs at a much higher frequency (default 30s) than the general
 	// heartbeat (3 min) so Dolt crashes are detected quickly.
@@ -474,10 +492,129 @@ func (d *Daemon) Run() error {
 		d.logger.Printf("Scheduled maintenance ticker started (check interval %v, window %s)", interval, window)
 	}
 
+	// Start CI remediation ticker if configured.
+	// Runs `gt ci check`, which slings CI failures back to their polecats.
+	var ciRemediationTicker *time.Ticker
+	var ciRemediationChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "ci_remediation") {
+		interval := ciRemediationInterval(d.patrolConfig)
+		ciRemediationTicker = time.NewTicker(interval)
+		ciRemediationChan = ciRemediationTicker.C
+		defer ciRemediationTicker.Stop()
+		d.logger.Printf("CI remediation ticker started (interval %v)", interval)
+	}
+
+	// Start Mayor rotation ticker if configured.
+	// Runs `gt mayor rotate --if-older`, which refreshes a long-running
+	// Mayor session with a carryover summary.
+	var mayorRotationTicker *time.Ticker
+	var mayorRotationChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "mayor_rotation") {
+		interval := mayorRotationInterval(d.patrolConfig)
+		mayorRotationTicker = time.NewTicker(interval)
+		mayorRotationChan = mayorRotationTicker.C
+		defer mayorRotationTicker.Stop()
+		d.logger.Printf("Mayor rotation ticker started (check interval %v, max age %v)", interval, mayorRotationMaxAge(d.patrolConfig))
+	}
+
+	// Start standup ticker if configured.
+	// Runs `gt standup tick`, which prompts workers for progress notes and
+	// publishes the daily standup report at the configured time.
+	var standupTicker *time.Ticker
+	var standupChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "standup") {
+		standupTicker = time.NewTicker(standupCheckInterval)
+		standupChan = standupTicker.C
+		defer standupTicker.Stop()
+		d.logger.Printf("Standup ticker started (publish at %s, prompt %v before)", standupAt(d.patrolConfig), standupLead(d.patrolConfig))
+	}
+
+	// Start permission responder ticker if configured.
+	// Runs `gt permissions respond`, which answers tool-permission prompts
+	// in agent sessions according to each rig's permission_policy.
+	var permissionResponderTicker *time.Ticker
+	var permissionResponderChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "permission_responder") {
+		interval := permissionResponderInterval(d.patrolConfig)
+		permissionResponderTicker = time.NewTicker(interval)
+		permissionResponderChan = permissionResponderTicker.C
+		defer permissionResponderTicker.Stop()
+		d.logger.Printf("Permission responder ticker started (interval %v)", interval)
+	}
+
+	// Start disk quota ticker if configured.
+	// Runs `gt disk check`, which measures rigs against their disk_quota,
+	// alerts the mayor and removes idle polecat worktrees from rigs over it.
+	var diskQuotaTicker *time.Ticker
+	var diskQuotaChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "disk_quota") {
+		interval := diskQuotaInterval(d.patrolConfig)
+		diskQuotaTicker = time.NewTicker(interval)
+		diskQuotaChan = diskQuotaTicker.C
+		defer diskQuotaTicker.Stop()
+		d.logger.Printf("Disk quota ticker started (interval %v)", interval)
+	}
+
+	// Start label rules ticker if configured.
+	// Runs `gt bead rules run`, which acts on beads that gained a rule's label.
+	var labelRulesTicker *time.Ticker
+	var labelRulesChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "label_rules") {
+		interval := labelRulesInterval(d.patrolConfig)
+		labelRulesTicker = time.NewTicker(interval)
+		labelRulesChan = labelRulesTicker.C
+		defer labelRulesTicker.Stop()
+		d.logger.Printf("Label rules ticker started (interval %v)", interval)
+	}
+
+	// Start dependency update ticker if configured.
+	// Runs `gt deps sweep`, which files (and may sling) update beads for
+	// rigs whose dep_updates check is due.
+	var depUpdatesTicker *time.Ticker
+	var depUpdatesChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "dep_updates") {
+		interval := depUpdatesInterval(d.patrolConfig)
+		depUpdatesTicker = time.NewTicker(interval)
+		depUpdatesChan = depUpdatesTicker.C
+		defer depUpdatesTicker.Stop()
+		d.logger.Printf("Dependency updates ticker started (interval %v)", interval)
+	}
+
+	// Start transcript sealing ticker if configured.
+	// Runs `gt crypt seal`, which encrypts transcripts idle past seal_after.
+	var sealTranscriptsTicker *time.Ticker
+	var sealTranscriptsChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "seal_transcripts") {
+		interval := sealTranscriptsInterval(d.patrolConfig)
+		sealTranscriptsTicker = time.NewTicker(interval)
+		sealTranscriptsChan = sealTranscriptsTicker.C
+		defer sealTranscriptsTicker.Stop()
+		d.logger.Printf("Seal transcripts ticker started (interval %v)", interval)
+	}
+
+	// Start stale hooks ticker if configured.
+	// Runs `gt hook sweep`, which flags hooks with no sign of work and
+	// unhooks and requeues them per the town's stale_hooks policy.
+	var staleHooksTicker *time.Ticker
+	var staleHooksChan <-chan time.Time
+	if IsPatrolEnabled(d.patrolConfig, "stale_hooks") {
+		interval := staleHooksInterval(d.patrolConfig)
+		staleHooksTicker = time.NewTicker(interval)
+		staleHooksChan = staleHooksTicker.C
+		defer staleHooksTicker.Stop()
+		d.logger.Printf("Stale hooks ticker started (interval %v)", interval)
+	}
+
 	// Note: PATCH-010 uses per-session hooks in deacon/manager.go (SetAutoRespawnHook).
 	// Global pane-died hooks don't fire reliably in tmux 3.2a, so we rely on the
 	// per-session approach which has been tested to work for continuous recovery.
 
+	// Reconcile state the crashed run may have left half-written before
+	// the first heartbeat dispatches work from it.
+	if uncleanExit {
+		d.reconcileAfterUncleanExit(state)
+	}
+
 	// Initial heartbeat
 	d.heartbeat(state)
 
@@ -561,6 +698,61 @@ func (d *Daemon) Run() error {
 				d.runScheduledMaintenance()
 			}
 
+		case <-ciRemediationChan:
+			// CI remediation — slings follow-ups for failing polecat branches.
+			if !d.isShutdownInProgress() {
+				d.runCIRemediation()
+			}
+
+		case <-mayorRotationChan:
+			// Mayor rotation — fresh session with carryover once the
+			// current context is older than max_age.
+			if !d.isShutdownInProgress() {
+				d.runMayorRotation()
+			}
+
+		case <-standupChan:
+			// Standup — prompt workers for notes, then publish the report.
+			if !d.isShutdownInProgress() {
+				d.runStandup()
+			}
+
+		case <-permissionResponderChan:
+			// Permission responder — answer prompts the rig policy covers.
+			if !d.isShutdownInProgress() {
+				d.runPermissionResponder()
+			}
+
+		case <-diskQuotaChan:
+			// Disk quota — measure rigs, alert and gc rigs over their limit.
+			if !d.isShutdownInProgress() {
+				d.runDiskQuota()
+			}
+
+		case <-labelRulesChan:
+			// Label rules — act on beads that gained a rule's label.
+			if !d.isShutdownInProgress() {
+				d.runLabelRules()
+			}
+
+		case <-depUpdatesChan:
+			// Dependency updates — file update beads for outdated dependencies.
+			if !d.isShutdownInProgress() {
+				d.runDepUpdates()
+			}
+
+		case <-sealTranscriptsChan:
+			// Seal transcripts — encrypt idle agent transcripts at rest.
+			if !d.isShutdownInProgress() {
+				d.runSealTranscripts()
+			}
+
+		case <-staleHooksChan:
+			// Stale hooks — flag, unhook and requeue zombie hooks.
+			if !d.isShutdownInProgress() {
+				d.runStaleHooks()
+			}
+
 		case <-timer.C:
 			d.heartbeat(state)
 
@@ -710,6 +902,10 @@ func (d *Daemon) heartbeat(state *State) {
 	// branches persist indefinitely. This cleans them up periodically.
 	d.pruneStaleBranches()
 
+	// 13b. Apply quiet hours: park idle crew when a window opens, restart
+	// them and release held mail notifications when it closes.
+	d.runQuietHours()
+
 	// 14. Dispatch scheduled work (capacity-controlled polecat dispatch).
 	// Shells out to `gt scheduler run` to avoid circular import between daemon and cmd.
 	// Pressure-gated: polecats are the primary resource consumers.
@@ -719,6 +915,9 @@ func (d *Daemon) heartbeat(state *State) {
 		d.dispatchQueuedWork()
 	}
 
+	// 14b. Sample agent session activity for gt time report.
+	d.sampleActivity()
+
 	// 15. Rotate oversized Dolt logs (copytruncate for child process fds).
 	// daemon.log uses lumberjack for automatic rotation; this handles Dolt server logs.
 	d.rotateOversizedLogs()
@@ -2066,7 +2265,7 @@ func (d *Daemon) reapIdlePolecats() {
 
 	rigs := d.getKnownRigs()
 	for _, rigName := range rigs {
-		d.reapRigIdlePolecats(rigName, timeout)
+		d.reapRigIdlePolecats(rigName, d.idleTimeoutForRig(rigName, timeout))
 	}
 }
 
@@ -0,0 +1,65 @@
+package daemon
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultDepUpdatesInterval is how often gt deps sweep runs. Each rig's
+	// own dep_updates.every decides when it is actually checked; the hourly
+	// run also lets the quiet_hours sling policy catch the quiet window.
+	defaultDepUpdatesInterval = time.Hour
+
+	// depUpdatesTimeout bounds one gt deps sweep run across all rigs.
+	depUpdatesTimeout = 30 * time.Minute
+)
+
+// DepUpdatesConfig holds configuration for the dep_updates patrol, which
+// checks rigs with dep_updates enabled for outdated dependencies, files
+// update beads and slings them per the rig's policy (gt deps sweep).
+type DepUpdatesConfig struct {
+	// Enabled controls whether the patrol runs. Default: off.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to sweep (default 1h).
+	IntervalStr string `json:"interval,omitempty"`
+}
+
+// depUpdatesInterval returns the configured sweep interval, or the default (1h).
+func depUpdatesInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.DepUpdates != nil {
+		if config.Patrols.DepUpdates.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.DepUpdates.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultDepUpdatesInterval
+}
+
+// runDepUpdates sweeps the rigs for outdated dependencies and relays what
+// gt deps sweep filed and slung.
+func (d *Daemon) runDepUpdates() {
+	if !IsPatrolEnabled(d.patrolConfig, "dep_updates") {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, depUpdatesTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.gtPath, "deps", "sweep")
+	cmd.Dir = d.config.TownRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("dep_updates: gt deps sweep failed: %v\nOutput: %s", err, string(output))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			d.logger.Printf("dep_updates: %s", line)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDepUpdatesPatrolOptIn(t *testing.T) {
+	if IsPatrolEnabled(nil, "dep_updates") {
+		t.Error("dep_updates should be disabled without config")
+	}
+	if IsPatrolEnabled(&DaemonPatrolConfig{Patrols: &PatrolsConfig{}}, "dep_updates") {
+		t.Error("dep_updates should be disabled when not configured")
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{DepUpdates: &DepUpdatesConfig{Enabled: true}}}
+	if !IsPatrolEnabled(cfg, "dep_updates") {
+		t.Error("dep_updates should be enabled when opted in")
+	}
+}
+
+func TestDepUpdatesInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *DaemonPatrolConfig
+		want time.Duration
+	}{
+		{"nil config", nil, defaultDepUpdatesInterval},
+		{"unset", &DaemonPatrolConfig{Patrols: &PatrolsConfig{DepUpdates: &DepUpdatesConfig{Enabled: true}}}, defaultDepUpdatesInterval},
+		{"custom", &DaemonPatrolConfig{Patrols: &PatrolsConfig{DepUpdates: &DepUpdatesConfig{IntervalStr: "6h"}}}, 6 * time.Hour},
+		{"invalid", &DaemonPatrolConfig{Patrols: &PatrolsConfig{DepUpdates: &DepUpdatesConfig{IntervalStr: "often"}}}, defaultDepUpdatesInterval},
+	}
+	for _, tt := range tests {
+		if got := depUpdatesInterval(tt.cfg); got != tt.want {
+			t.Errorf("%s: depUpdatesInterval() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
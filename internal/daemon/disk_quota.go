@@ -0,0 +1,65 @@
+package daemon
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultDiskQuotaInterval is how often rigs are measured against their
+	// disk_quota. Measuring walks every worktree, so this stays infrequent.
+	defaultDiskQuotaInterval = 30 * time.Minute
+
+	// diskQuotaTimeout bounds one gt disk check run, including gc.
+	diskQuotaTimeout = 10 * time.Minute
+)
+
+// DiskQuotaConfig holds configuration for the disk_quota patrol, which
+// measures each rig with a disk_quota, mails the mayor when a rig crosses a
+// threshold and removes idle polecat worktrees from rigs over their limit
+// (gt disk check).
+type DiskQuotaConfig struct {
+	// Enabled controls whether the patrol runs. Default: off.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to check (default 30m).
+	IntervalStr string `json:"interval,omitempty"`
+}
+
+// diskQuotaInterval returns the configured check interval, or the default (30m).
+func diskQuotaInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.DiskQuota != nil {
+		if config.Patrols.DiskQuota.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.DiskQuota.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultDiskQuotaInterval
+}
+
+// runDiskQuota checks every rig's disk usage. gt disk check mails the mayor
+// and runs gc itself; here we only relay its summary.
+func (d *Daemon) runDiskQuota() {
+	if !IsPatrolEnabled(d.patrolConfig, "disk_quota") {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, diskQuotaTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.gtPath, "disk", "check")
+	cmd.Dir = d.config.TownRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("disk_quota: gt disk check failed: %v\nOutput: %s", err, string(output))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			d.logger.Printf("disk_quota: %s", line)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDiskQuotaPatrolOptIn(t *testing.T) {
+	if IsPatrolEnabled(nil, "disk_quota") {
+		t.Error("disk_quota should be disabled without config")
+	}
+	if IsPatrolEnabled(&DaemonPatrolConfig{Patrols: &PatrolsConfig{}}, "disk_quota") {
+		t.Error("disk_quota should be disabled when not configured")
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{DiskQuota: &DiskQuotaConfig{Enabled: true}}}
+	if !IsPatrolEnabled(cfg, "disk_quota") {
+		t.Error("disk_quota should be enabled when opted in")
+	}
+}
+
+func TestDiskQuotaInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *DaemonPatrolConfig
+		want time.Duration
+	}{
+		{"nil config", nil, defaultDiskQuotaInterval},
+		{"unset", &DaemonPatrolConfig{Patrols: &PatrolsConfig{DiskQuota: &DiskQuotaConfig{Enabled: true}}}, defaultDiskQuotaInterval},
+		{"custom", &DaemonPatrolConfig{Patrols: &PatrolsConfig{DiskQuota: &DiskQuotaConfig{IntervalStr: "1h"}}}, time.Hour},
+		{"invalid", &DaemonPatrolConfig{Patrols: &PatrolsConfig{DiskQuota: &DiskQuotaConfig{IntervalStr: "often"}}}, defaultDiskQuotaInterval},
+	}
+	for _, tt := range tests {
+		if got := diskQuotaInterval(tt.cfg); got != tt.want {
+			t.Errorf("%s: diskQuotaInterval() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
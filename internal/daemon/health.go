@@ -0,0 +1,132 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Daemon health statuses.
+const (
+	HealthOK      = "ok"      // Running and heartbeating
+	HealthStale   = "stale"   // Running, but heartbeats stopped
+	HealthDead    = "dead"    // Not running, and never shut down cleanly
+	HealthStopped = "stopped" // Not running after a clean shutdown
+)
+
+const (
+	// staleHeartbeats is how many heartbeat intervals may pass without a
+	// heartbeat before a running daemon counts as stale. One heartbeat can
+	// run long (Dolt restarts, slow bd calls); three in a row cannot.
+	staleHeartbeats = 3
+
+	// reconcileTimeout bounds the gt town rebuild-state run after an
+	// unclean exit.
+	reconcileTimeout = 5 * time.Minute
+)
+
+// Health is the daemon's health as read from outside the process.
+type Health struct {
+	Status        string    `json:"status"`
+	PID           int       `json:"pid,omitempty"`
+	LastHeartbeat time.Time `json:"last_heartbeat,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+}
+
+// CheckHealth judges daemon health from its state file, whether its
+// process is alive, and the heartbeat interval. A daemon that died without
+// a clean shutdown leaves Running set in its state file, which is how a
+// silent death is told apart from gt daemon stop.
+func CheckHealth(state *State, running bool, pid int, interval time.Duration, now time.Time) Health {
+	h := Health{PID: pid, LastHeartbeat: state.LastHeartbeat}
+	switch {
+	case !running && state.Running:
+		h.Status = HealthDead
+		h.Reason = "exited without a clean shutdown"
+		if last := lastSignOfLife(state); !last.IsZero() {
+			h.Reason += "; last seen " + now.Sub(last).Round(time.Second).String() + " ago"
+		}
+	case !running:
+		h.Status = HealthStopped
+	default:
+		h.Status = HealthOK
+		last := lastSignOfLife(state)
+		if limit := staleHeartbeats * interval; !last.IsZero() && now.Sub(last) > limit {
+			h.Status = HealthStale
+			h.Reason = "no heartbeat for " + now.Sub(last).Round(time.Second).String() + " (limit " + limit.String() + ")"
+		}
+	}
+	return h
+}
+
+// ReadHealth checks the health of the town's daemon.
+func ReadHealth(townRoot string, now time.Time) (Health, *State, error) {
+	running, pid, err := IsRunning(townRoot)
+	if err != nil {
+		return Health{}, nil, err
+	}
+	state, err := LoadState(townRoot)
+	if err != nil {
+		return Health{}, nil, err
+	}
+	interval := config.LoadOperationalConfig(townRoot).GetDaemonConfig().RecoveryHeartbeatIntervalD()
+	return CheckHealth(state, running, pid, interval, now), state, nil
+}
+
+// lastSignOfLife returns the last heartbeat, or the start time before the
+// first heartbeat.
+func lastSignOfLife(state *State) time.Time {
+	if !state.LastHeartbeat.IsZero() {
+		return state.LastHeartbeat
+	}
+	return state.StartedAt
+}
+
+// carryOverUncleanExit starts a run's state from the previous run's. It
+// reports whether the previous run ended without a clean shutdown, in
+// which case the exit is counted.
+func carryOverUncleanExit(prev, state *State) bool {
+	state.UncleanExits = prev.UncleanExits
+	state.LastUncleanExit = prev.LastUncleanExit
+	state.ReconciledAt = prev.ReconciledAt
+	if !prev.Running {
+		return false
+	}
+	state.UncleanExits++
+	state.LastUncleanExit = lastSignOfLife(prev)
+	return true
+}
+
+// reconcileAfterUncleanExit repairs town state a crashed run may have left
+// half-written (hooks, the scheduler queue, freezes) by replaying the
+// event log with gt town rebuild-state --repair. Drift the log can't
+// explain is logged for a human to look at.
+func (d *Daemon) reconcileAfterUncleanExit(state *State) {
+	ctx, cancel := context.WithTimeout(d.ctx, reconcileTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.gtPath, "town", "rebuild-state", "--repair") //nolint:gosec // G204: args are constructed internally
+	cmd.Dir = d.config.TownRoot
+	output, err := cmd.CombinedOutput()
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			d.logger.Printf("reconcile: %s", line)
+		}
+	}
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		d.logger.Printf("reconcile: drift remains after repair; run 'gt town rebuild-state' to review")
+	case err != nil:
+		d.logger.Printf("reconcile: gt town rebuild-state failed: %v", err)
+		return
+	}
+	state.ReconciledAt = time.Now()
+	if err := SaveState(d.config.TownRoot, state); err != nil {
+		d.logger.Printf("Warning: failed to save state: %v", err)
+	}
+}
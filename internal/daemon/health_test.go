@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckHealth(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	interval := 3 * time.Minute
+
+	tests := []struct {
+		name    string
+		state   State
+		running bool
+		want    string
+	}{
+		{"heartbeating", State{Running: true, LastHeartbeat: now.Add(-2 * time.Minute)}, true, HealthOK},
+		{"slow heartbeat within limit", State{Running: true, LastHeartbeat: now.Add(-8 * time.Minute)}, true, HealthOK},
+		{"heartbeats stopped", State{Running: true, LastHeartbeat: now.Add(-10 * time.Minute)}, true, HealthStale},
+		{"stuck before first heartbeat", State{Running: true, StartedAt: now.Add(-time.Hour)}, true, HealthStale},
+		{"died uncleanly", State{Running: true, LastHeartbeat: now.Add(-time.Hour)}, false, HealthDead},
+		{"stopped cleanly", State{Running: false, LastHeartbeat: now.Add(-time.Hour)}, false, HealthStopped},
+		{"never started", State{}, false, HealthStopped},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := CheckHealth(&tt.state, tt.running, 42, interval, now)
+			if h.Status != tt.want {
+				t.Errorf("status = %q, want %q (reason %q)", h.Status, tt.want, h.Reason)
+			}
+			if (h.Status == HealthStale || h.Status == HealthDead) && h.Reason == "" {
+				t.Error("expected a reason")
+			}
+		})
+	}
+}
+
+func TestCarryOverUncleanExit(t *testing.T) {
+	started := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	beat := started.Add(time.Hour)
+
+	t.Run("clean shutdown", func(t *testing.T) {
+		prev := &State{Running: false, UncleanExits: 2, LastUncleanExit: started}
+		state := &State{Running: true}
+		if carryOverUncleanExit(prev, state) {
+			t.Fatal("clean shutdown reported as unclean")
+		}
+		if state.UncleanExits != 2 || !state.LastUncleanExit.Equal(started) {
+			t.Errorf("history not carried over: %+v", state)
+		}
+	})
+
+	t.Run("crash", func(t *testing.T) {
+		prev := &State{Running: true, StartedAt: started, LastHeartbeat: beat, UncleanExits: 2}
+		state := &State{Running: true}
+		if !carryOverUncleanExit(prev, state) {
+			t.Fatal("crash not detected")
+		}
+		if state.UncleanExits != 3 || !state.LastUncleanExit.Equal(beat) {
+			t.Errorf("got %d exits, last %v; want 3, %v", state.UncleanExits, state.LastUncleanExit, beat)
+		}
+	})
+
+	t.Run("crash before first heartbeat", func(t *testing.T) {
+		prev := &State{Running: true, StartedAt: started}
+		state := &State{Running: true}
+		carryOverUncleanExit(prev, state)
+		if !state.LastUncleanExit.Equal(started) {
+			t.Errorf("last unclean exit = %v, want start time %v", state.LastUncleanExit, started)
+		}
+	})
+}
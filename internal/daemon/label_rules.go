@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultLabelRulesInterval is how often label rules are applied. Rules
+	// react to labels a person or agent just added, so this runs often.
+	defaultLabelRulesInterval = 2 * time.Minute
+
+	// labelRulesTimeout bounds one gt bead rules run, including slings.
+	labelRulesTimeout = 5 * time.Minute
+)
+
+// LabelRulesConfig holds configuration for the label_rules patrol, which
+// applies the town's label_rules to newly labeled beads (gt bead rules run).
+type LabelRulesConfig struct {
+	// Enabled controls whether the patrol runs. Default: off.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to apply the rules (default 2m).
+	IntervalStr string `json:"interval,omitempty"`
+}
+
+// labelRulesInterval returns the configured interval, or the default (2m).
+func labelRulesInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.LabelRules != nil {
+		if config.Patrols.LabelRules.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.LabelRules.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultLabelRulesInterval
+}
+
+// runLabelRules applies label rules and logs each action gt bead rules run
+// reports.
+func (d *Daemon) runLabelRules() {
+	if !IsPatrolEnabled(d.patrolConfig, "label_rules") {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, labelRulesTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.gtPath, "bead", "rules", "run")
+	cmd.Dir = d.config.TownRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("label_rules: gt bead rules run failed: %v\nOutput: %s", err, string(output))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			d.logger.Printf("label_rules: %s", line)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLabelRulesPatrolOptIn(t *testing.T) {
+	if IsPatrolEnabled(nil, "label_rules") {
+		t.Error("label_rules should be disabled without config")
+	}
+	if IsPatrolEnabled(&DaemonPatrolConfig{Patrols: &PatrolsConfig{}}, "label_rules") {
+		t.Error("label_rules should be disabled when not configured")
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{LabelRules: &LabelRulesConfig{Enabled: true}}}
+	if !IsPatrolEnabled(cfg, "label_rules") {
+		t.Error("label_rules should be enabled when opted in")
+	}
+}
+
+func TestLabelRulesInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *DaemonPatrolConfig
+		want time.Duration
+	}{
+		{"nil config", nil, defaultLabelRulesInterval},
+		{"unset", &DaemonPatrolConfig{Patrols: &PatrolsConfig{LabelRules: &LabelRulesConfig{Enabled: true}}}, defaultLabelRulesInterval},
+		{"custom", &DaemonPatrolConfig{Patrols: &PatrolsConfig{LabelRules: &LabelRulesConfig{IntervalStr: "30s"}}}, 30 * time.Second},
+		{"invalid", &DaemonPatrolConfig{Patrols: &PatrolsConfig{LabelRules: &LabelRulesConfig{IntervalStr: "often"}}}, defaultLabelRulesInterval},
+	}
+	for _, tt := range tests {
+		if got := labelRulesInterval(tt.cfg); got != tt.want {
+			t.Errorf("%s: labelRulesInterval() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
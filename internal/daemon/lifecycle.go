@@ -788,7 +788,7 @@ func (d *Daemon) getAgentBeadInfo(agentBeadID string) (*AgentBeadInfo, error) {
 	cmd.Dir = d.config.TownRoot
 	cmd.Env = os.Environ() // Inherit PATH to find bd executable
 
-	output, err := cmd.Output()
+	output, err := beads.CmdOutput(cmd)
 	if err != nil {
 		return nil, fmt.Errorf("bd show %s: %w", agentBeadID, err)
 	}
@@ -856,7 +856,7 @@ func (d *Daemon) getAgentHookBead(agentBeadID string) string {
 	cmd.Dir = d.config.TownRoot
 	cmd.Env = os.Environ()
 
-	output, err := cmd.Output()
+	output, err := beads.CmdOutput(cmd)
 	if err != nil {
 		return ""
 	}
@@ -947,14 +947,14 @@ func (d *Daemon) listAgentBeadsJSON(dest interface{}) error {
 	cmd.Dir = d.config.TownRoot
 	cmd.Env = os.Environ()
 
-	issuesOutput, issuesErr := cmd.Output()
+	issuesOutput, issuesErr := beads.CmdOutput(cmd)
 
 	// Query wisps table (primary source after agent bead migration)
 	wispCmd := exec.Command(d.bdPath, "mol", "wisp", "list", "--json") //nolint:gosec // G204: bd is a trusted internal tool
 	wispCmd.Dir = d.config.TownRoot
 	wispCmd.Env = os.Environ()
 
-	wispOutput, _ := wispCmd.Output() // Best-effort: wisps table may not exist
+	wispOutput, _ := beads.CmdOutput(wispCmd) // Best-effort: wisps table may not exist
 
 	// Merge results: parse wisps first, then issues (wisps take precedence)
 	combined := mergeAgentBeadJSON(wispOutput, issuesOutput)
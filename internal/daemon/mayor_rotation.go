@@ -0,0 +1,86 @@
+package daemon
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultMayorRotationInterval is how often the daemon checks the Mayor's
+	// context age. Rotation itself happens at most once per max_age.
+	defaultMayorRotationInterval = 15 * time.Minute
+
+	// defaultMayorRotationMaxAge is how long a Mayor context runs before it
+	// is rotated — roughly a working day.
+	defaultMayorRotationMaxAge = 8 * time.Hour
+
+	// mayorRotationTimeout bounds one gt mayor rotate run.
+	mayorRotationTimeout = 2 * time.Minute
+)
+
+// MayorRotationConfig holds configuration for the mayor_rotation patrol,
+// which runs `gt mayor rotate --if-older <max_age>` so a long-running Mayor
+// is refreshed with a carryover summary instead of degrading as its context
+// fills. Rotation is deferred while a terminal is attached to the Mayor.
+type MayorRotationConfig struct {
+	// Enabled controls whether the patrol runs. Default: off.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to check the Mayor's context age (default 15m).
+	IntervalStr string `json:"interval,omitempty"`
+
+	// MaxAgeStr is how old the Mayor's context may get before rotation
+	// (default 8h).
+	MaxAgeStr string `json:"max_age,omitempty"`
+}
+
+// mayorRotationInterval returns the configured check interval, or the default (15m).
+func mayorRotationInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.MayorRotation != nil {
+		if config.Patrols.MayorRotation.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.MayorRotation.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultMayorRotationInterval
+}
+
+// mayorRotationMaxAge returns the configured max context age, or the default (8h).
+func mayorRotationMaxAge(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.MayorRotation != nil {
+		if config.Patrols.MayorRotation.MaxAgeStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.MayorRotation.MaxAgeStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultMayorRotationMaxAge
+}
+
+// runMayorRotation rotates the Mayor if its context is older than max_age.
+func (d *Daemon) runMayorRotation() {
+	if !IsPatrolEnabled(d.patrolConfig, "mayor_rotation") {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, mayorRotationTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.gtPath, "mayor", "rotate",
+		"--if-older", mayorRotationMaxAge(d.patrolConfig).String(),
+		"--reason", "scheduled")
+	cmd.Dir = d.config.TownRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("mayor_rotation: gt mayor rotate failed: %v\nOutput: %s", err, string(output))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			d.logger.Printf("mayor_rotation: %s", line)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMayorRotationPatrolOptIn(t *testing.T) {
+	if IsPatrolEnabled(nil, "mayor_rotation") {
+		t.Error("mayor_rotation should be disabled without config")
+	}
+	if IsPatrolEnabled(&DaemonPatrolConfig{Patrols: &PatrolsConfig{}}, "mayor_rotation") {
+		t.Error("mayor_rotation should be disabled when not configured")
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{MayorRotation: &MayorRotationConfig{Enabled: true}}}
+	if !IsPatrolEnabled(cfg, "mayor_rotation") {
+		t.Error("mayor_rotation should be enabled when opted in")
+	}
+}
+
+func TestMayorRotationDurations(t *testing.T) {
+	withRotation := func(c MayorRotationConfig) *DaemonPatrolConfig {
+		return &DaemonPatrolConfig{Patrols: &PatrolsConfig{MayorRotation: &c}}
+	}
+	tests := []struct {
+		name         string
+		cfg          *DaemonPatrolConfig
+		wantInterval time.Duration
+		wantMaxAge   time.Duration
+	}{
+		{"nil config", nil, defaultMayorRotationInterval, defaultMayorRotationMaxAge},
+		{"unset", withRotation(MayorRotationConfig{Enabled: true}), defaultMayorRotationInterval, defaultMayorRotationMaxAge},
+		{"custom", withRotation(MayorRotationConfig{IntervalStr: "5m", MaxAgeStr: "12h"}), 5 * time.Minute, 12 * time.Hour},
+		{"invalid", withRotation(MayorRotationConfig{IntervalStr: "often", MaxAgeStr: "-1h"}), defaultMayorRotationInterval, defaultMayorRotationMaxAge},
+	}
+	for _, tt := range tests {
+		if got := mayorRotationInterval(tt.cfg); got != tt.wantInterval {
+			t.Errorf("%s: mayorRotationInterval() = %v, want %v", tt.name, got, tt.wantInterval)
+		}
+		if got := mayorRotationMaxAge(tt.cfg); got != tt.wantMaxAge {
+			t.Errorf("%s: mayorRotationMaxAge() = %v, want %v", tt.name, got, tt.wantMaxAge)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultPermissionResponderInterval is how often agent panes are
+	// scanned for permission prompts. Prompts block the agent outright, so
+	// this runs far more often than the other opt-in patrols.
+	defaultPermissionResponderInterval = 1 * time.Minute
+
+	// permissionResponderTimeout bounds one gt permissions respond run.
+	permissionResponderTimeout = 2 * time.Minute
+)
+
+// PermissionResponderConfig holds configuration for the permission_responder
+// patrol, which answers runtime permission prompts in polecat and crew
+// sessions according to each rig's permission_policy (gt permissions respond).
+type PermissionResponderConfig struct {
+	// Enabled controls whether the patrol runs. Default: off.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to scan (default 1m).
+	IntervalStr string `json:"interval,omitempty"`
+}
+
+// permissionResponderInterval returns the configured scan interval, or the default (1m).
+func permissionResponderInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.PermissionResponder != nil {
+		if config.Patrols.PermissionResponder.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.PermissionResponder.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultPermissionResponderInterval
+}
+
+// runPermissionResponder answers pending permission prompts in every rig
+// with a policy enabled. gt permissions respond records each answer in the
+// audit log; here we only relay its summary.
+func (d *Daemon) runPermissionResponder() {
+	if !IsPatrolEnabled(d.patrolConfig, "permission_responder") {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, permissionResponderTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.gtPath, "permissions", "respond", "--quiet")
+	cmd.Dir = d.config.TownRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("permission_responder: gt permissions respond failed: %v\nOutput: %s", err, string(output))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			d.logger.Printf("permission_responder: %s", line)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPermissionResponderPatrolOptIn(t *testing.T) {
+	if IsPatrolEnabled(nil, "permission_responder") {
+		t.Error("permission_responder should be disabled without config")
+	}
+	if IsPatrolEnabled(&DaemonPatrolConfig{Patrols: &PatrolsConfig{}}, "permission_responder") {
+		t.Error("permission_responder should be disabled when not configured")
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{PermissionResponder: &PermissionResponderConfig{Enabled: true}}}
+	if !IsPatrolEnabled(cfg, "permission_responder") {
+		t.Error("permission_responder should be enabled when opted in")
+	}
+}
+
+func TestPermissionResponderInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *DaemonPatrolConfig
+		want time.Duration
+	}{
+		{"nil config", nil, defaultPermissionResponderInterval},
+		{"unset", &DaemonPatrolConfig{Patrols: &PatrolsConfig{PermissionResponder: &PermissionResponderConfig{Enabled: true}}}, defaultPermissionResponderInterval},
+		{"custom", &DaemonPatrolConfig{Patrols: &PatrolsConfig{PermissionResponder: &PermissionResponderConfig{IntervalStr: "30s"}}}, 30 * time.Second},
+		{"invalid", &DaemonPatrolConfig{Patrols: &PatrolsConfig{PermissionResponder: &PermissionResponderConfig{IntervalStr: "soon"}}}, defaultPermissionResponderInterval},
+	}
+	for _, tt := range tests {
+		if got := permissionResponderInterval(tt.cfg); got != tt.want {
+			t.Errorf("%s: permissionResponderInterval() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
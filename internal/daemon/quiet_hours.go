@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/quiet"
+)
+
+// quietHoursTimeout bounds one gt quiet tick run (crew stops and restarts
+// plus held-mail nudges).
+const quietHoursTimeout = 3 * time.Minute
+
+// quietHoursActive reports whether a rig is inside its quiet hours (the
+// rig's own quiet_hours, or the town's).
+func (d *Daemon) quietHoursActive(rigName string) bool {
+	return config.LoadQuietHours(d.config.TownRoot, rigName).Active(time.Now())
+}
+
+// quietHoursConfigured reports whether the town or any rig has quiet hours
+// enabled, so towns without them never pay for a gt quiet tick.
+func (d *Daemon) quietHoursConfigured() bool {
+	if config.LoadQuietHours(d.config.TownRoot, "").IsEnabled() {
+		return true
+	}
+	for _, rigName := range d.getKnownRigs() {
+		if config.LoadQuietHours(d.config.TownRoot, rigName).IsEnabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// idleTimeoutForRig returns the idle-polecat reap threshold for a rig:
+// the configured timeout, shortened to park_after during quiet hours so
+// idle polecats are parked instead of waiting for the next assignment.
+func (d *Daemon) idleTimeoutForRig(rigName string, timeout time.Duration) time.Duration {
+	qh := config.LoadQuietHours(d.config.TownRoot, rigName)
+	if qh.Active(time.Now()) && qh.ParkAfter() < timeout {
+		return qh.ParkAfter()
+	}
+	return timeout
+}
+
+// runQuietHours applies quiet-hours transitions via gt quiet tick: parking
+// idle crew when a window starts, restarting them and releasing held mail
+// notifications when it ends. Dispatch gating happens where work is
+// dispatched (gt scheduler run and the convoy manager).
+func (d *Daemon) runQuietHours() {
+	// Also runs after a window closes, so parked crew and held mail are
+	// released even when quiet hours were disabled mid-window.
+	if !d.quietHoursConfigured() && !quiet.Pending(d.config.TownRoot) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, quietHoursTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.gtPath, "quiet", "tick")
+	cmd.Dir = d.config.TownRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("quiet_hours: gt quiet tick failed: %v\nOutput: %s", err, string(output))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			d.logger.Printf("quiet_hours: %s", line)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package daemon
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultSealTranscriptsInterval is how often idle transcripts are
+	// sealed. Transcripts wait seal_after (default 1h) before sealing, so
+	// checking more often gains little.
+	defaultSealTranscriptsInterval = 15 * time.Minute
+
+	// sealTranscriptsTimeout bounds one gt crypt seal run.
+	sealTranscriptsTimeout = 5 * time.Minute
+)
+
+// SealTranscriptsConfig holds configuration for the seal_transcripts
+// patrol, which encrypts agent transcripts once they have been idle for the
+// town's encryption seal_after (gt crypt seal).
+type SealTranscriptsConfig struct {
+	// Enabled controls whether the patrol runs. Default: off.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to seal (default 15m).
+	IntervalStr string `json:"interval,omitempty"`
+}
+
+// sealTranscriptsInterval returns the configured interval, or the default (15m).
+func sealTranscriptsInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.SealTranscripts != nil {
+		if config.Patrols.SealTranscripts.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.SealTranscripts.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultSealTranscriptsInterval
+}
+
+// runSealTranscripts seals idle transcripts. gt crypt seal reads the town's
+// encryption settings and key; here we only relay its summary.
+func (d *Daemon) runSealTranscripts() {
+	if !IsPatrolEnabled(d.patrolConfig, "seal_transcripts") {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, sealTranscriptsTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.gtPath, "crypt", "seal")
+	cmd.Dir = d.config.TownRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("seal_transcripts: gt crypt seal failed: %v\nOutput: %s", err, string(output))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			d.logger.Printf("seal_transcripts: %s", line)
+		}
+	}
+}
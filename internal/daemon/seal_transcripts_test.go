@@ -0,0 +1,23 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSealTranscriptsPatrolOptIn(t *testing.T) {
+	if IsPatrolEnabled(nil, "seal_transcripts") {
+		t.Error("seal_transcripts should be disabled without config")
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{SealTranscripts: &SealTranscriptsConfig{Enabled: true}}}
+	if !IsPatrolEnabled(cfg, "seal_transcripts") {
+		t.Error("seal_transcripts should be enabled when opted in")
+	}
+	if got := sealTranscriptsInterval(cfg); got != defaultSealTranscriptsInterval {
+		t.Errorf("sealTranscriptsInterval() = %v, want default", got)
+	}
+	cfg.Patrols.SealTranscripts.IntervalStr = "5m"
+	if got := sealTranscriptsInterval(cfg); got != 5*time.Minute {
+		t.Errorf("sealTranscriptsInterval() = %v, want 5m", got)
+	}
+}
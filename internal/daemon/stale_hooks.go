@@ -0,0 +1,64 @@
+package daemon
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultStaleHooksInterval is how often the stale hook policy is
+	// applied. Hooks go stale over hours, so a coarse interval is enough.
+	defaultStaleHooksInterval = 15 * time.Minute
+
+	// staleHooksTimeout bounds one gt hook sweep run, including requeues.
+	staleHooksTimeout = 5 * time.Minute
+)
+
+// StaleHooksConfig holds configuration for the stale_hooks patrol, which
+// flags hooks with no sign of work and, per the town's stale_hooks
+// settings, unhooks and requeues them (gt hook sweep).
+type StaleHooksConfig struct {
+	// Enabled controls whether the patrol runs. Default: off.
+	Enabled bool `json:"enabled"`
+
+	// IntervalStr is how often to sweep (default 15m).
+	IntervalStr string `json:"interval,omitempty"`
+}
+
+// staleHooksInterval returns the configured interval, or the default (15m).
+func staleHooksInterval(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.StaleHooks != nil {
+		if config.Patrols.StaleHooks.IntervalStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.StaleHooks.IntervalStr); err == nil && d > 0 {
+				return d
+			}
+		}
+	}
+	return defaultStaleHooksInterval
+}
+
+// runStaleHooks applies the stale hook policy and logs each hook gt hook
+// sweep flags or unhooks.
+func (d *Daemon) runStaleHooks() {
+	if !IsPatrolEnabled(d.patrolConfig, "stale_hooks") {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, staleHooksTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.gtPath, "hook", "sweep")
+	cmd.Dir = d.config.TownRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("stale_hooks: gt hook sweep failed: %v\nOutput: %s", err, string(output))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			d.logger.Printf("stale_hooks: %s", line)
+		}
+	}
+}
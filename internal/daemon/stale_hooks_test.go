@@ -0,0 +1,37 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStaleHooksPatrolOptIn(t *testing.T) {
+	if IsPatrolEnabled(nil, "stale_hooks") {
+		t.Error("stale_hooks should be disabled without config")
+	}
+	if IsPatrolEnabled(&DaemonPatrolConfig{Patrols: &PatrolsConfig{}}, "stale_hooks") {
+		t.Error("stale_hooks should be disabled when not configured")
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{StaleHooks: &StaleHooksConfig{Enabled: true}}}
+	if !IsPatrolEnabled(cfg, "stale_hooks") {
+		t.Error("stale_hooks should be enabled when opted in")
+	}
+}
+
+func TestStaleHooksInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *DaemonPatrolConfig
+		want time.Duration
+	}{
+		{"nil config", nil, defaultStaleHooksInterval},
+		{"unset", &DaemonPatrolConfig{Patrols: &PatrolsConfig{StaleHooks: &StaleHooksConfig{Enabled: true}}}, defaultStaleHooksInterval},
+		{"custom", &DaemonPatrolConfig{Patrols: &PatrolsConfig{StaleHooks: &StaleHooksConfig{IntervalStr: "5m"}}}, 5 * time.Minute},
+		{"invalid", &DaemonPatrolConfig{Patrols: &PatrolsConfig{StaleHooks: &StaleHooksConfig{IntervalStr: "often"}}}, defaultStaleHooksInterval},
+	}
+	for _, tt := range tests {
+		if got := staleHooksInterval(tt.cfg); got != tt.want {
+			t.Errorf("%s: staleHooksInterval() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
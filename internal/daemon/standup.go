@@ -0,0 +1,101 @@
+package daemon
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	// standupCheckInterval is how often the daemon runs gt standup tick.
+	// The prompt and publish each happen once per day; the tick only has to
+	// be frequent enough not to miss the configured time by much.
+	standupCheckInterval = 5 * time.Minute
+
+	// defaultStandupAt is the default publish time of day.
+	defaultStandupAt = "09:00"
+
+	// defaultStandupLead is how long before publishing workers are prompted.
+	defaultStandupLead = 30 * time.Minute
+
+	// standupTimeout bounds one gt standup tick run (nudges plus report).
+	standupTimeout = 3 * time.Minute
+)
+
+// StandupConfig holds configuration for the standup patrol, which prompts
+// running polecats and crew for a progress note and then publishes the
+// town standup report (gt standup) to the overseer once a day.
+type StandupConfig struct {
+	// Enabled controls whether the patrol runs. Default: off.
+	Enabled bool `json:"enabled"`
+
+	// At is the publish time of day, HH:MM in local time (default "09:00").
+	At string `json:"at,omitempty"`
+
+	// LeadStr is how long before At workers are prompted (default 30m).
+	LeadStr string `json:"lead,omitempty"`
+
+	// To is the address the report is mailed to (default "overseer").
+	To string `json:"to,omitempty"`
+}
+
+// standupAt returns the configured publish time, or the default (09:00).
+func standupAt(config *DaemonPatrolConfig) string {
+	if config != nil && config.Patrols != nil && config.Patrols.Standup != nil {
+		if at := config.Patrols.Standup.At; at != "" {
+			if _, _, err := parseWindowTime(at); err == nil {
+				return at
+			}
+		}
+	}
+	return defaultStandupAt
+}
+
+// standupLead returns the configured prompt lead time, or the default (30m).
+func standupLead(config *DaemonPatrolConfig) time.Duration {
+	if config != nil && config.Patrols != nil && config.Patrols.Standup != nil {
+		if config.Patrols.Standup.LeadStr != "" {
+			if d, err := time.ParseDuration(config.Patrols.Standup.LeadStr); err == nil && d >= 0 {
+				return d
+			}
+		}
+	}
+	return defaultStandupLead
+}
+
+// standupTo returns the configured report recipient, or the default (overseer).
+func standupTo(config *DaemonPatrolConfig) string {
+	if config != nil && config.Patrols != nil && config.Patrols.Standup != nil {
+		if config.Patrols.Standup.To != "" {
+			return config.Patrols.Standup.To
+		}
+	}
+	return "overseer"
+}
+
+// runStandup prompts workers or publishes the standup when either is due.
+func (d *Daemon) runStandup() {
+	if !IsPatrolEnabled(d.patrolConfig, "standup") {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(d.ctx, standupTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, d.gtPath, "standup", "tick",
+		"--at", standupAt(d.patrolConfig),
+		"--lead", standupLead(d.patrolConfig).String(),
+		"--to", standupTo(d.patrolConfig))
+	cmd.Dir = d.config.TownRoot
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		d.logger.Printf("standup: gt standup tick failed: %v\nOutput: %s", err, string(output))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			d.logger.Printf("standup: %s", line)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStandupPatrolOptIn(t *testing.T) {
+	if IsPatrolEnabled(nil, "standup") {
+		t.Error("standup should be disabled without config")
+	}
+	if IsPatrolEnabled(&DaemonPatrolConfig{Patrols: &PatrolsConfig{}}, "standup") {
+		t.Error("standup should be disabled when not configured")
+	}
+	cfg := &DaemonPatrolConfig{Patrols: &PatrolsConfig{Standup: &StandupConfig{Enabled: true}}}
+	if !IsPatrolEnabled(cfg, "standup") {
+		t.Error("standup should be enabled when opted in")
+	}
+}
+
+func TestStandupSettings(t *testing.T) {
+	withStandup := func(c StandupConfig) *DaemonPatrolConfig {
+		return &DaemonPatrolConfig{Patrols: &PatrolsConfig{Standup: &c}}
+	}
+	tests := []struct {
+		name     string
+		cfg      *DaemonPatrolConfig
+		wantAt   string
+		wantLead time.Duration
+		wantTo   string
+	}{
+		{"nil config", nil, defaultStandupAt, defaultStandupLead, "overseer"},
+		{"unset", withStandup(StandupConfig{Enabled: true}), defaultStandupAt, defaultStandupLead, "overseer"},
+		{"custom", withStandup(StandupConfig{At: "08:30", LeadStr: "1h", To: "mayor/"}), "08:30", time.Hour, "mayor/"},
+		{"invalid", withStandup(StandupConfig{At: "9am", LeadStr: "-5m"}), defaultStandupAt, defaultStandupLead, "overseer"},
+	}
+	for _, tt := range tests {
+		if got := standupAt(tt.cfg); got != tt.wantAt {
+			t.Errorf("%s: standupAt() = %q, want %q", tt.name, got, tt.wantAt)
+		}
+		if got := standupLead(tt.cfg); got != tt.wantLead {
+			t.Errorf("%s: standupLead() = %v, want %v", tt.name, got, tt.wantLead)
+		}
+		if got := standupTo(tt.cfg); got != tt.wantTo {
+			t.Errorf("%s: standupTo() = %q, want %q", tt.name, got, tt.wantTo)
+		}
+	}
+}
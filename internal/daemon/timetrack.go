@@ -0,0 +1,49 @@
+package daemon
+
+import (
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/timetrack"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// sampleActivity records the last tmux activity of every agent session
+// whose activity has moved since the previous heartbeat. gt time report
+// turns these signals into active-work time per bead.
+func (d *Daemon) sampleActivity() {
+	t := tmux.NewTmux()
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return
+	}
+	if d.activitySeen == nil {
+		d.activitySeen = make(map[string]int64)
+	}
+
+	var signals []timetrack.Signal
+	live := make(map[string]bool, len(sessions))
+	for _, name := range sessions {
+		if !session.IsKnownSession(name) {
+			continue
+		}
+		identity, err := session.ParseSessionName(name)
+		if err != nil || identity.Address() == "" {
+			continue
+		}
+		live[name] = true
+		activity, err := t.GetSessionActivity(name)
+		if err != nil || activity.IsZero() || d.activitySeen[name] == activity.Unix() {
+			continue
+		}
+		d.activitySeen[name] = activity.Unix()
+		signals = append(signals, timetrack.Signal{Agent: identity.Address(), At: activity.UTC()})
+	}
+	for name := range d.activitySeen {
+		if !live[name] {
+			delete(d.activitySeen, name)
+		}
+	}
+
+	if err := timetrack.Record(d.config.TownRoot, signals); err != nil {
+		d.logger.Printf("timetrack: recording activity: %v", err)
+	}
+}
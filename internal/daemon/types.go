@@ -61,6 +61,18 @@ type State struct {
 
 	// HeartbeatCount is how many heartbeats have completed.
 	HeartbeatCount int64 `json:"heartbeat_count"`
+
+	// UncleanExits counts daemon runs that ended without a clean shutdown
+	// (crash, kill -9, OOM), carried over from run to run.
+	UncleanExits int `json:"unclean_exits,omitempty"`
+
+	// LastUncleanExit is the last sign of life from the most recent run
+	// that ended uncleanly: its last heartbeat, or its start time.
+	LastUncleanExit time.Time `json:"last_unclean_exit,omitempty"`
+
+	// ReconciledAt is when town state was last reconciled after an
+	// unclean exit.
+	ReconciledAt time.Time `json:"reconciled_at,omitempty"`
 }
 
 // StateFile returns the path to the state file.
@@ -128,6 +140,15 @@ type PatrolsConfig struct {
 	CompactorDog           *CompactorDogConfig            `json:"compactor_dog,omitempty"`
 	ScheduledMaintenance   *ScheduledMaintenanceConfig    `json:"scheduled_maintenance,omitempty"`
 	RestartTracker         *RestartTrackerConfig          `json:"restart_tracker,omitempty"`
+	CIRemediation          *CIRemediationConfig           `json:"ci_remediation,omitempty"`
+	MayorRotation          *MayorRotationConfig           `json:"mayor_rotation,omitempty"`
+	Standup                *StandupConfig                 `json:"standup,omitempty"`
+	PermissionResponder    *PermissionResponderConfig     `json:"permission_responder,omitempty"`
+	DiskQuota              *DiskQuotaConfig               `json:"disk_quota,omitempty"`
+	LabelRules             *LabelRulesConfig              `json:"label_rules,omitempty"`
+	DepUpdates             *DepUpdatesConfig              `json:"dep_updates,omitempty"`
+	SealTranscripts        *SealTranscriptsConfig         `json:"seal_transcripts,omitempty"`
+	StaleHooks             *StaleHooksConfig              `json:"stale_hooks,omitempty"`
 }
 
 // DoltRemotesConfig holds configuration for the dolt_remotes patrol.
@@ -287,6 +308,60 @@ func IsPatrolEnabled(config *DaemonPatrolConfig, patrol string) bool {
 		}
 		return config.Patrols.ScheduledMaintenance.Enabled
 	}
+	if patrol == "ci_remediation" {
+		if config == nil || config.Patrols == nil || config.Patrols.CIRemediation == nil {
+			return false
+		}
+		return config.Patrols.CIRemediation.Enabled
+	}
+	if patrol == "mayor_rotation" {
+		if config == nil || config.Patrols == nil || config.Patrols.MayorRotation == nil {
+			return false
+		}
+		return config.Patrols.MayorRotation.Enabled
+	}
+	if patrol == "standup" {
+		if config == nil || config.Patrols == nil || config.Patrols.Standup == nil {
+			return false
+		}
+		return config.Patrols.Standup.Enabled
+	}
+	if patrol == "permission_responder" {
+		if config == nil || config.Patrols == nil || config.Patrols.PermissionResponder == nil {
+			return false
+		}
+		return config.Patrols.PermissionResponder.Enabled
+	}
+	if patrol == "disk_quota" {
+		if config == nil || config.Patrols == nil || config.Patrols.DiskQuota == nil {
+			return false
+		}
+		return config.Patrols.DiskQuota.Enabled
+	}
+	if patrol == "label_rules" {
+		if config == nil || config.Patrols == nil || config.Patrols.LabelRules == nil {
+			return false
+		}
+		return config.Patrols.LabelRules.Enabled
+	}
+	if patrol == "dep_updates" {
+		if config == nil || config.Patrols == nil || config.Patrols.DepUpdates == nil {
+			return false
+		}
+		return config.Patrols.DepUpdates.Enabled
+	}
+	if patrol == "seal_transcripts" {
+		if config == nil || config.Patrols == nil || config.Patrols.SealTranscripts == nil {
+			return false
+		}
+		return config.Patrols.SealTranscripts.Enabled
+	}
+	if patrol == "stale_hooks" {
+		if config == nil || config.Patrols == nil || config.Patrols.StaleHooks == nil {
+			return false
+		}
+		return config.Patrols.StaleHooks.Enabled
+	}
 
 	if config == nil || config.Patrols == nil {
 		return true // Default: enabled
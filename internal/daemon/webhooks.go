@@ -0,0 +1,32 @@
+package daemon
+
+import (
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/webhook"
+)
+
+// startWebhookServer serves inbound webhooks (settings/config.json "webhooks")
+// for the daemon's lifetime. Config errors are logged; the daemon keeps running.
+func (d *Daemon) startWebhookServer() {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(d.config.TownRoot))
+	if err != nil || !settings.Webhooks.IsEnabled() {
+		return
+	}
+	if err := settings.Webhooks.Validate(); err != nil {
+		d.logger.Printf("Warning: webhook server not started: %v", err)
+		return
+	}
+
+	dispatcher := &webhook.CLIDispatcher{TownRoot: d.config.TownRoot, GTPath: d.gtPath, BDPath: d.bdPath, RunBD: beads.RunCmdContext}
+	srv := webhook.NewServer(d.config.TownRoot, settings.Webhooks, dispatcher, d.logger.Printf)
+	srv.Guard = config.LoadContentGuard(d.config.TownRoot)
+	srv.Formulator = &webhook.CLIFormulator{TownRoot: d.config.TownRoot, GTPath: d.gtPath}
+	go func() {
+		if err := srv.ListenAndServe(d.ctx); err != nil {
+			d.logger.Printf("Warning: webhook server stopped: %v", err)
+		}
+	}()
+	d.logger.Printf("Webhook server listening on %s (%d endpoints)",
+		settings.Webhooks.ListenAddr(), len(settings.Webhooks.Endpoints))
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/constants"
 )
 
@@ -53,6 +54,7 @@ type StrandedConvoy struct {
 	TrackedCount int      `json:"tracked_count"`
 	ReadyCount   int      `json:"ready_count"`
 	ReadyIssues  []string `json:"ready_issues"`
+	WaitingOn    string   `json:"waiting_on,omitempty"` // Held by the convoy's after:/phase_N: ordering
 }
 
 // FeedResult describes the outcome of a feed-stranded invocation.
@@ -71,6 +73,10 @@ type FeedResult struct {
 	// not classify or act on them.
 	NeedsAttention int `json:"needs_attention"`
 
+	// Waiting is the number of convoys holding dispatch on purpose: they wait
+	// on prerequisite convoys or an earlier phase. No action is needed.
+	Waiting int `json:"waiting"`
+
 	// Errors is the number of convoys that failed to process.
 	Errors int `json:"errors"`
 
@@ -81,7 +87,7 @@ type FeedResult struct {
 // FeedConvoyResult describes the outcome for a single convoy.
 type FeedConvoyResult struct {
 	ConvoyID     string `json:"convoy_id"`
-	Action       string `json:"action"` // "fed", "closed", "cooldown", "error", "limit", "needs_attention"
+	Action       string `json:"action"` // "fed", "closed", "cooldown", "error", "limit", "needs_attention", "waiting"
 	Message      string `json:"message"`
 	TrackedCount int    `json:"tracked_count,omitempty"` // Raw data for agent inspection
 	ReadyCount   int    `json:"ready_count,omitempty"`   // Raw data for agent inspection
@@ -242,6 +248,20 @@ func FeedStranded(townRoot string, maxPerCycle int, cooldown time.Duration) *Fee
 	for _, convoy := range stranded {
 		// Handle convoys with no ready issues.
 		if convoy.ReadyCount == 0 {
+			// Convoy ordering (prerequisite convoys or phases) is holding
+			// dispatch on purpose. It resumes on its own once the blocking
+			// work closes, so there's nothing for the agent to decide.
+			if convoy.WaitingOn != "" {
+				result.Waiting++
+				result.Details = append(result.Details, FeedConvoyResult{
+					ConvoyID:     convoy.ID,
+					Action:       "waiting",
+					Message:      "waiting on " + convoy.WaitingOn,
+					TrackedCount: convoy.TrackedCount,
+				})
+				continue
+			}
+
 			// Convoy has tracked issues but none are ready — surface raw data
 			// for the deacon agent to inspect. Go does not classify WHY issues
 			// aren't ready (dependency resolution, external block, etc.).
@@ -381,7 +401,7 @@ func getConvoyStatus(townRoot, convoyID string) string {
 	cmd := exec.Command("bd", "show", convoyID, "--json")
 	cmd.Dir = townRoot
 
-	output, err := cmd.Output()
+	output, err := beads.CmdOutput(cmd)
 	if err != nil {
 		return ""
 	}
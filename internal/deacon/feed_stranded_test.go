@@ -3,6 +3,7 @@ package deacon
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 )
@@ -219,3 +220,31 @@ func TestSaveFeedStrandedState_CreatesDirectory(t *testing.T) {
 		t.Fatal("state file not created")
 	}
 }
+
+func TestFeedStranded_WaitingConvoyIsNotNeedsAttention(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("skipping on windows — shell stubs")
+	}
+	townRoot := t.TempDir()
+	binDir := t.TempDir()
+	script := `#!/bin/sh
+cat <<'JSON'
+[{"id":"hq-cv-api","title":"API","tracked_count":2,"ready_count":0,"ready_issues":[],"waiting_on":"convoy hq-cv-schema"},
+ {"id":"hq-cv-odd","title":"Odd","tracked_count":1,"ready_count":0,"ready_issues":[]}]
+JSON
+`
+	if err := os.WriteFile(filepath.Join(binDir, "gt"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	result := FeedStranded(townRoot, 0, 0)
+	if result.Waiting != 1 || result.NeedsAttention != 1 {
+		t.Fatalf("Waiting=%d NeedsAttention=%d, want 1 and 1: %+v", result.Waiting, result.NeedsAttention, result.Details)
+	}
+	for _, d := range result.Details {
+		if d.ConvoyID == "hq-cv-api" && (d.Action != "waiting" || d.Message != "waiting on convoy hq-cv-schema") {
+			t.Errorf("hq-cv-api detail = %+v", d)
+		}
+	}
+}
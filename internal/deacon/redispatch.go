@@ -334,7 +334,7 @@ func getBeadStatusForRedispatch(townRoot, beadID string) string {
 	cmd := exec.Command("bd", "show", beadID, "--json")
 	cmd.Dir = townRoot
 
-	output, err := cmd.Output()
+	output, err := beads.CmdOutput(cmd)
 	if err != nil {
 		return ""
 	}
@@ -11,6 +11,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/auditlog"
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -158,7 +160,7 @@ func listHookedBeads(townRoot string) ([]*HookedBead, error) {
 	cmd := exec.Command("bd", "list", "--status=hooked", "--json", "--flat", "--limit=0")
 	cmd.Dir = townRoot
 
-	output, err := cmd.Output()
+	output, err := beads.CmdOutput(cmd)
 	if err != nil {
 		// No hooked beads is not an error
 		if strings.Contains(string(output), "no issues found") {
@@ -249,8 +251,24 @@ func assigneeToWorktreePath(townRoot, assignee string) string {
 }
 
 // unhookBead sets a bead's status back to 'open'.
+// The bd call bypasses gt, so it is recorded in the audit log here.
 func unhookBead(townRoot, beadID string) error {
+	start := time.Now()
 	cmd := exec.Command("bd", "update", beadID, "--status=open")
 	cmd.Dir = townRoot
-	return cmd.Run()
+	err := beads.RunCmd(cmd)
+
+	entry := &auditlog.Entry{
+		Source:     auditlog.SourceDeacon,
+		Actor:      "deacon",
+		Command:    "unhook stale",
+		Args:       []string{beadID},
+		Result:     auditlog.ResultOK,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		entry.Result, entry.Error = auditlog.ResultError, err.Error()
+	}
+	_ = auditlog.Record(townRoot, entry)
+	return err
 }
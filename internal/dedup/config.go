@@ -0,0 +1,97 @@
+// Package dedup detects likely duplicate beads before work is dispatched.
+//
+// Slinging a bead that restates already-finished (or already in-flight) work
+// wastes an entire polecat session. This package scores a bead's title and
+// description against recent beads and reports the closest matches so the
+// dispatcher can warn before spawning.
+//
+// Scoring uses TF-IDF cosine similarity by default. When an embedding endpoint
+// is configured, embeddings are used instead and TF-IDF is the fallback when
+// the endpoint is unreachable.
+package dedup
+
+import "time"
+
+// Default tuning values used when Config fields are unset.
+const (
+	DefaultThreshold     = 0.45
+	DefaultMaxResults    = 3
+	DefaultLookback      = 14 * 24 * time.Hour
+	DefaultCandidateScan = 500
+)
+
+// Config configures duplicate-bead detection (settings/config.json "dedup").
+// All fields are optional; a nil Config means defaults (enabled, TF-IDF).
+type Config struct {
+	// Enabled toggles the check. nil/absent = enabled.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Threshold is the minimum similarity (0-1) for a bead to be reported.
+	// Default: 0.45.
+	Threshold float64 `json:"threshold,omitempty"`
+
+	// MaxResults caps how many likely duplicates are reported. Default: 3.
+	MaxResults int `json:"max_results,omitempty"`
+
+	// Lookback limits closed candidates to beads closed within this window.
+	// In-flight beads are always considered. Default: "336h" (14 days).
+	Lookback string `json:"lookback,omitempty"`
+
+	// CandidateScan caps how many beads are fetched for comparison. Default: 500.
+	CandidateScan int `json:"candidate_scan,omitempty"`
+
+	// EmbeddingEndpoint is an OpenAI-compatible /embeddings URL. When set,
+	// embeddings replace TF-IDF for scoring.
+	EmbeddingEndpoint string `json:"embedding_endpoint,omitempty"`
+
+	// EmbeddingModel is the model name sent to EmbeddingEndpoint.
+	EmbeddingModel string `json:"embedding_model,omitempty"`
+
+	// EmbeddingAPIKeyEnv names the environment variable holding the bearer
+	// token for EmbeddingEndpoint. The key itself is never stored in config.
+	EmbeddingAPIKeyEnv string `json:"embedding_api_key_env,omitempty"`
+}
+
+// IsEnabled reports whether duplicate detection should run.
+func (c *Config) IsEnabled() bool {
+	if c == nil || c.Enabled == nil {
+		return true
+	}
+	return *c.Enabled
+}
+
+// GetThreshold returns Threshold or DefaultThreshold if unset or out of range.
+func (c *Config) GetThreshold() float64 {
+	if c == nil || c.Threshold <= 0 || c.Threshold > 1 {
+		return DefaultThreshold
+	}
+	return c.Threshold
+}
+
+// GetMaxResults returns MaxResults or DefaultMaxResults if unset.
+func (c *Config) GetMaxResults() int {
+	if c == nil || c.MaxResults <= 0 {
+		return DefaultMaxResults
+	}
+	return c.MaxResults
+}
+
+// GetLookback returns Lookback as a duration, defaulting to DefaultLookback.
+func (c *Config) GetLookback() time.Duration {
+	if c == nil || c.Lookback == "" {
+		return DefaultLookback
+	}
+	d, err := time.ParseDuration(c.Lookback)
+	if err != nil || d <= 0 {
+		return DefaultLookback
+	}
+	return d
+}
+
+// GetCandidateScan returns CandidateScan or DefaultCandidateScan if unset.
+func (c *Config) GetCandidateScan() int {
+	if c == nil || c.CandidateScan <= 0 {
+		return DefaultCandidateScan
+	}
+	return c.CandidateScan
+}
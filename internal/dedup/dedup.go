@@ -0,0 +1,177 @@
+package dedup
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Document is a bead reduced to the fields used for similarity scoring.
+type Document struct {
+	ID          string
+	Title       string
+	Description string
+	Status      string
+	ClosedAt    time.Time // zero for beads that are not closed
+}
+
+// Text returns the text used for scoring. The title is repeated so it
+// outweighs long descriptions that share boilerplate.
+func (d Document) Text() string {
+	return d.Title + "\n" + d.Title + "\n" + d.Description
+}
+
+// Match is a candidate judged similar to the query document.
+type Match struct {
+	Document
+	Score float64
+}
+
+// Scorer computes similarity between a query and each candidate.
+// The returned slice is parallel to candidates; values are in [0, 1].
+type Scorer interface {
+	Score(ctx context.Context, query Document, candidates []Document) ([]float64, error)
+}
+
+// FindDuplicates scores candidates against query and returns the matches at
+// or above threshold, best first, capped at maxResults. The query itself
+// (same ID) is never reported.
+func FindDuplicates(ctx context.Context, scorer Scorer, query Document, candidates []Document, threshold float64, maxResults int) ([]Match, error) {
+	var pool []Document
+	for _, c := range candidates {
+		if c.ID == query.ID {
+			continue
+		}
+		pool = append(pool, c)
+	}
+	if len(pool) == 0 || maxResults <= 0 {
+		return nil, nil
+	}
+
+	scores, err := scorer.Score(ctx, query, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Match
+	for i, s := range scores {
+		if s >= threshold {
+			matches = append(matches, Match{Document: pool[i], Score: s})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > maxResults {
+		matches = matches[:maxResults]
+	}
+	return matches, nil
+}
+
+// FilterCandidates keeps in-flight beads and beads closed within lookback of now.
+// Open (not yet started) beads are excluded: they are triage noise, not wasted work.
+func FilterCandidates(docs []Document, now time.Time, lookback time.Duration) []Document {
+	var out []Document
+	for _, d := range docs {
+		switch d.Status {
+		case "in_progress", "hooked", "pinned":
+			out = append(out, d)
+		case "closed":
+			if !d.ClosedAt.IsZero() && now.Sub(d.ClosedAt) <= lookback {
+				out = append(out, d)
+			}
+		}
+	}
+	return out
+}
+
+// TFIDFScorer scores documents by TF-IDF cosine similarity, using the query
+// plus candidates as the corpus for document frequencies.
+type TFIDFScorer struct{}
+
+// Score implements Scorer.
+func (TFIDFScorer) Score(_ context.Context, query Document, candidates []Document) ([]float64, error) {
+	docs := make([][]string, 0, len(candidates)+1)
+	docs = append(docs, Tokenize(query.Text()))
+	for _, c := range candidates {
+		docs = append(docs, Tokenize(c.Text()))
+	}
+
+	df := make(map[string]int)
+	for _, toks := range docs {
+		seen := make(map[string]bool)
+		for _, t := range toks {
+			if !seen[t] {
+				seen[t] = true
+				df[t]++
+			}
+		}
+	}
+
+	n := float64(len(docs))
+	vec := func(toks []string) map[string]float64 {
+		tf := make(map[string]float64)
+		for _, t := range toks {
+			tf[t]++
+		}
+		for t, f := range tf {
+			// Smoothed IDF keeps terms present in every document non-zero,
+			// which matters for tiny corpora (one query, one candidate).
+			tf[t] = f * (math.Log((1+n)/(1+float64(df[t]))) + 1)
+		}
+		return tf
+	}
+
+	q := vec(docs[0])
+	scores := make([]float64, len(candidates))
+	for i := range candidates {
+		scores[i] = cosineSparse(q, vec(docs[i+1]))
+	}
+	return scores, nil
+}
+
+func cosineSparse(a, b map[string]float64) float64 {
+	var dot, na, nb float64
+	for t, va := range a {
+		na += va * va
+		if vb, ok := b[t]; ok {
+			dot += va * vb
+		}
+	}
+	for _, vb := range b {
+		nb += vb * vb
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// stopwords are dropped during tokenization. The list is short on purpose:
+// TF-IDF already discounts common terms, this only removes glue words that
+// dominate short titles.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "so": true, "that": true,
+	"the": true, "this": true, "to": true, "we": true, "when": true, "with": true,
+}
+
+// Tokenize lowercases text and splits it into alphanumeric terms, dropping
+// stopwords and single-character tokens.
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := fields[:0]
+	for _, f := range fields {
+		if len(f) < 2 || stopwords[f] {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
@@ -0,0 +1,188 @@
+package dedup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Fix the Race-condition in file_watcher (v2) a")
+	want := []string{"fix", "race", "condition", "file", "watcher", "v2"}
+	if len(got) != len(want) {
+		t.Fatalf("Tokenize() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Tokenize()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestFindDuplicates_TFIDF(t *testing.T) {
+	query := Document{ID: "gt-new", Title: "Fix race condition in file watcher initialization"}
+	candidates := []Document{
+		{ID: "gt-new", Title: "Fix race condition in file watcher initialization"},
+		{ID: "gt-dup", Title: "Race condition during file watcher init", Description: "watcher initialization races"},
+		{ID: "gt-other", Title: "Add dark mode to dashboard"},
+		{ID: "gt-meh", Title: "Refactor config loader"},
+	}
+
+	matches, err := FindDuplicates(context.Background(), TFIDFScorer{}, query, candidates, 0.3, 3)
+	if err != nil {
+		t.Fatalf("FindDuplicates() error = %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("FindDuplicates() returned %d matches, want 1: %+v", len(matches), matches)
+	}
+	if matches[0].ID != "gt-dup" {
+		t.Errorf("top match = %s, want gt-dup", matches[0].ID)
+	}
+}
+
+func TestFindDuplicates_RespectsMaxResults(t *testing.T) {
+	query := Document{ID: "q", Title: "update dependency versions"}
+	candidates := []Document{
+		{ID: "a", Title: "update dependency versions"},
+		{ID: "b", Title: "update dependency versions now"},
+		{ID: "c", Title: "update all dependency versions"},
+	}
+	matches, err := FindDuplicates(context.Background(), TFIDFScorer{}, query, candidates, 0.1, 2)
+	if err != nil {
+		t.Fatalf("FindDuplicates() error = %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("got %d matches, want 2", len(matches))
+	}
+	if matches[0].ID != "a" {
+		t.Errorf("best match = %s, want exact title match a", matches[0].ID)
+	}
+	if matches[0].Score < matches[1].Score {
+		t.Errorf("matches not sorted by score: %v", matches)
+	}
+}
+
+func TestFilterCandidates(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	docs := []Document{
+		{ID: "open", Status: "open"},
+		{ID: "wip", Status: "in_progress"},
+		{ID: "hooked", Status: "hooked"},
+		{ID: "recent", Status: "closed", ClosedAt: now.Add(-24 * time.Hour)},
+		{ID: "old", Status: "closed", ClosedAt: now.Add(-30 * 24 * time.Hour)},
+		{ID: "nodate", Status: "closed"},
+	}
+	got := FilterCandidates(docs, now, 7*24*time.Hour)
+	ids := make(map[string]bool)
+	for _, d := range got {
+		ids[d.ID] = true
+	}
+	for _, id := range []string{"wip", "hooked", "recent"} {
+		if !ids[id] {
+			t.Errorf("expected %s to be kept", id)
+		}
+	}
+	for _, id := range []string{"open", "old", "nodate"} {
+		if ids[id] {
+			t.Errorf("expected %s to be filtered out", id)
+		}
+	}
+}
+
+func TestConfigDefaults(t *testing.T) {
+	var c *Config
+	if !c.IsEnabled() {
+		t.Error("nil config should be enabled")
+	}
+	if c.GetThreshold() != DefaultThreshold {
+		t.Errorf("GetThreshold() = %v, want %v", c.GetThreshold(), DefaultThreshold)
+	}
+	if c.GetLookback() != DefaultLookback {
+		t.Errorf("GetLookback() = %v, want %v", c.GetLookback(), DefaultLookback)
+	}
+
+	off := false
+	c = &Config{Enabled: &off, Threshold: 2, Lookback: "bogus", MaxResults: 5}
+	if c.IsEnabled() {
+		t.Error("explicit false should disable")
+	}
+	if c.GetThreshold() != DefaultThreshold {
+		t.Errorf("out-of-range threshold should fall back to default, got %v", c.GetThreshold())
+	}
+	if c.GetLookback() != DefaultLookback {
+		t.Errorf("invalid lookback should fall back to default, got %v", c.GetLookback())
+	}
+	if c.GetMaxResults() != 5 {
+		t.Errorf("GetMaxResults() = %d, want 5", c.GetMaxResults())
+	}
+}
+
+func TestEmbeddingScorer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q, want Bearer secret", got)
+		}
+		var req embeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		if len(req.Input) != 3 {
+			t.Fatalf("got %d inputs, want 3", len(req.Input))
+		}
+		// Query and first candidate point the same way; second is orthogonal.
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{
+				{"index": 0, "embedding": []float64{1, 0}},
+				{"index": 1, "embedding": []float64{0.9, 0.1}},
+				{"index": 2, "embedding": []float64{0, 1}},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	s := &EmbeddingScorer{Endpoint: srv.URL, APIKey: "secret"}
+	scores, err := s.Score(context.Background(), Document{Title: "q"}, []Document{{Title: "a"}, {Title: "b"}})
+	if err != nil {
+		t.Fatalf("Score() error = %v", err)
+	}
+	if scores[0] < 0.9 {
+		t.Errorf("similar vector scored %v, want > 0.9", scores[0])
+	}
+	if scores[1] != 0 {
+		t.Errorf("orthogonal vector scored %v, want 0", scores[1])
+	}
+}
+
+type failingScorer struct{}
+
+func (failingScorer) Score(context.Context, Document, []Document) ([]float64, error) {
+	return nil, errors.New("endpoint down")
+}
+
+func TestFallbackScorer(t *testing.T) {
+	var fellBack error
+	s := fallbackScorer{primary: failingScorer{}, secondary: TFIDFScorer{}, onError: func(err error) { fellBack = err }}
+	scores, err := s.Score(context.Background(), Document{Title: "same words"}, []Document{{Title: "same words"}})
+	if err != nil {
+		t.Fatalf("Score() error = %v", err)
+	}
+	if fellBack == nil {
+		t.Error("expected onError to be called")
+	}
+	if scores[0] < 0.99 {
+		t.Errorf("identical text scored %v via fallback, want ~1", scores[0])
+	}
+}
+
+func TestNewScorer(t *testing.T) {
+	if _, ok := NewScorer(nil, nil).(TFIDFScorer); !ok {
+		t.Error("nil config should produce TFIDFScorer")
+	}
+	if _, ok := NewScorer(&Config{EmbeddingEndpoint: "http://x"}, nil).(fallbackScorer); !ok {
+		t.Error("configured endpoint should produce fallbackScorer")
+	}
+}
@@ -0,0 +1,173 @@
+package dedup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"time"
+)
+
+// embeddingTimeout bounds a single embedding request. Duplicate detection is
+// advisory, so a slow endpoint must not stall dispatch.
+const embeddingTimeout = 10 * time.Second
+
+// EmbeddingScorer scores documents by cosine similarity of embeddings from an
+// OpenAI-compatible /embeddings endpoint.
+type EmbeddingScorer struct {
+	Endpoint string
+	Model    string
+	APIKey   string
+	Client   *http.Client
+}
+
+type embeddingRequest struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+type embeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+}
+
+// Score implements Scorer.
+func (s *EmbeddingScorer) Score(ctx context.Context, query Document, candidates []Document) ([]float64, error) {
+	inputs := make([]string, 0, len(candidates)+1)
+	inputs = append(inputs, query.Text())
+	for _, c := range candidates {
+		inputs = append(inputs, c.Text())
+	}
+
+	vecs, err := s.embed(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	if len(vecs) != len(inputs) {
+		return nil, fmt.Errorf("embedding endpoint returned %d vectors for %d inputs", len(vecs), len(inputs))
+	}
+
+	scores := make([]float64, len(candidates))
+	for i := range candidates {
+		// Clamp: cosine of embeddings can be negative, Score contract is [0, 1].
+		scores[i] = math.Max(0, CosineDense(vecs[0], vecs[i+1]))
+	}
+	return scores, nil
+}
+
+// Embed returns one embedding per input, in input order.
+func (s *EmbeddingScorer) Embed(ctx context.Context, inputs []string) ([][]float64, error) {
+	return s.embed(ctx, inputs)
+}
+
+func (s *EmbeddingScorer) embed(ctx context.Context, inputs []string) ([][]float64, error) {
+	body, err := json.Marshal(embeddingRequest{Model: s.Model, Input: inputs})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, embeddingTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("building embedding request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.APIKey)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling embedding endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("embedding endpoint returned %s: %s", resp.Status, bytes.TrimSpace(snippet))
+	}
+
+	var parsed embeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("parsing embedding response: %w", err)
+	}
+
+	vecs := make([][]float64, len(parsed.Data))
+	for i, d := range parsed.Data {
+		idx := d.Index
+		if idx < 0 || idx >= len(vecs) {
+			idx = i
+		}
+		vecs[idx] = d.Embedding
+	}
+	return vecs, nil
+}
+
+// CosineDense returns the cosine similarity of two dense vectors, or 0 when
+// they differ in length or either is zero.
+func CosineDense(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// fallbackScorer tries primary and falls back to secondary on error.
+type fallbackScorer struct {
+	primary   Scorer
+	secondary Scorer
+	onError   func(error)
+}
+
+func (f fallbackScorer) Score(ctx context.Context, query Document, candidates []Document) ([]float64, error) {
+	scores, err := f.primary.Score(ctx, query, candidates)
+	if err == nil {
+		return scores, nil
+	}
+	if f.onError != nil {
+		f.onError(err)
+	}
+	return f.secondary.Score(ctx, query, candidates)
+}
+
+// NewScorer returns the scorer described by cfg: embeddings with TF-IDF
+// fallback when an endpoint is configured, otherwise plain TF-IDF.
+// onFallback, if non-nil, is called when the embedding endpoint fails.
+func NewScorer(cfg *Config, onFallback func(error)) Scorer {
+	if cfg == nil || cfg.EmbeddingEndpoint == "" {
+		return TFIDFScorer{}
+	}
+	apiKey := ""
+	if cfg.EmbeddingAPIKeyEnv != "" {
+		apiKey = os.Getenv(cfg.EmbeddingAPIKeyEnv)
+	}
+	return fallbackScorer{
+		primary: &EmbeddingScorer{
+			Endpoint: cfg.EmbeddingEndpoint,
+			Model:    cfg.EmbeddingModel,
+			APIKey:   apiKey,
+		},
+		secondary: TFIDFScorer{},
+		onError:   onFallback,
+	}
+}
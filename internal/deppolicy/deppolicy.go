@@ -0,0 +1,263 @@
+// Package deppolicy checks dependency changes against a rig's license and
+// package policy. The refinery runs it on each merged tree so a branch
+// that pulls in a denied package or license never reaches the target
+// branch.
+package deppolicy
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Unknown-license handling.
+const (
+	UnknownWarn  = "warn"  // Report but allow (default)
+	UnknownBlock = "block" // Treat as a violation
+	UnknownAllow = "allow" // Ignore silently
+)
+
+// Config is the merge_queue.dependency_policy section of a rig's config.json.
+//
+//	"dependency_policy": {
+//	  "deny_licenses": ["GPL-*", "AGPL-*", "SSPL-*"],
+//	  "deny_packages": ["github.com/evil/*", "left-pad"],
+//	  "allow_packages": ["github.com/ourco/*"],
+//	  "licenses": {"github.com/vendor/sdk": "MIT"},
+//	  "unknown": "block"
+//	}
+type Config struct {
+	// AllowLicenses, when set, is the complete list of acceptable
+	// licenses (SPDX IDs, glob patterns allowed). Anything else is denied.
+	AllowLicenses []string `json:"allow_licenses,omitempty"`
+
+	// DenyLicenses are rejected even if allowed above.
+	DenyLicenses []string `json:"deny_licenses,omitempty"`
+
+	// AllowPackages are exempt from license checks (e.g. internal modules).
+	AllowPackages []string `json:"allow_packages,omitempty"`
+
+	// DenyPackages are rejected regardless of license.
+	DenyPackages []string `json:"deny_packages,omitempty"`
+
+	// Licenses overrides detection for packages whose license can't be
+	// found or is detected wrongly. Keys are package names.
+	Licenses map[string]string `json:"licenses,omitempty"`
+
+	// Unknown is what to do when a license can't be determined:
+	// warn (default), block, or allow.
+	Unknown string `json:"unknown,omitempty"`
+}
+
+// IsEnabled reports whether any policy is configured.
+func (c *Config) IsEnabled() bool {
+	return c != nil && (len(c.AllowLicenses) > 0 || len(c.DenyLicenses) > 0 || len(c.DenyPackages) > 0)
+}
+
+// GetUnknown returns the unknown-license mode, defaulting to warn.
+func (c *Config) GetUnknown() string {
+	if c == nil || c.Unknown == "" {
+		return UnknownWarn
+	}
+	return c.Unknown
+}
+
+// Validate checks the policy's patterns and modes.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.GetUnknown() {
+	case UnknownWarn, UnknownBlock, UnknownAllow:
+	default:
+		return fmt.Errorf("invalid unknown mode %q (use warn, block, or allow)", c.Unknown)
+	}
+	for _, list := range [][]string{c.AllowLicenses, c.DenyLicenses, c.AllowPackages, c.DenyPackages} {
+		for _, p := range list {
+			if _, err := path.Match(strings.ToLower(p), ""); err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", p, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Dependency is one added or upgraded dependency.
+type Dependency struct {
+	Ecosystem string `json:"ecosystem"` // gomod, npm, pypi, cargo
+	Name      string `json:"name"`
+	Version   string `json:"version,omitempty"`
+	Previous  string `json:"previous,omitempty"` // Version before the change; empty if newly added
+	Manifest  string `json:"manifest"`
+}
+
+// String formats name@version.
+func (d Dependency) String() string {
+	if d.Version == "" {
+		return d.Name
+	}
+	return d.Name + "@" + d.Version
+}
+
+// Verdicts.
+const (
+	Allowed = "allowed"
+	Denied  = "denied"
+	Unknown = "unknown"
+)
+
+// Finding is the policy verdict for one dependency.
+type Finding struct {
+	Dependency Dependency `json:"dependency"`
+	License    string     `json:"license,omitempty"`
+	Verdict    string     `json:"verdict"`
+	Reason     string     `json:"reason,omitempty"`
+	Blocking   bool       `json:"blocking"`
+}
+
+// Report is the policy check of a set of dependency changes.
+type Report struct {
+	Findings []Finding `json:"findings"`
+}
+
+// Violations returns the findings that block the merge.
+func (r *Report) Violations() []Finding {
+	var out []Finding
+	for _, f := range r.Findings {
+		if f.Blocking {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// Warnings returns non-blocking unknown-license findings.
+func (r *Report) Warnings() []Finding {
+	var out []Finding
+	for _, f := range r.Findings {
+		if !f.Blocking && f.Verdict == Unknown {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// Summary is a one-line description of the violations.
+func (r *Report) Summary() string {
+	v := r.Violations()
+	if len(v) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(v))
+	for _, f := range v {
+		parts = append(parts, fmt.Sprintf("%s (%s)", f.Dependency, f.Reason))
+	}
+	return fmt.Sprintf("dependency policy: %d violation(s): %s", len(v), strings.Join(parts, "; "))
+}
+
+// Check applies cfg to deps. resolve returns a dependency's license
+// expression, or "" if unknown; cfg.Licenses overrides take precedence.
+func Check(cfg *Config, deps []Dependency, resolve func(Dependency) string) *Report {
+	report := &Report{}
+	for _, d := range deps {
+		report.Findings = append(report.Findings, checkOne(cfg, d, resolve))
+	}
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		return report.Findings[i].Blocking && !report.Findings[j].Blocking
+	})
+	return report
+}
+
+func checkOne(cfg *Config, d Dependency, resolve func(Dependency) string) Finding {
+	f := Finding{Dependency: d}
+	if p := matchAny(cfg.DenyPackages, d.Name); p != "" {
+		f.Verdict, f.Reason, f.Blocking = Denied, "package denied by "+p, true
+		return f
+	}
+	if p := matchAny(cfg.AllowPackages, d.Name); p != "" {
+		f.Verdict, f.Reason = Allowed, "package allowed by "+p
+		return f
+	}
+
+	f.License = cfg.Licenses[d.Name]
+	if f.License == "" && resolve != nil {
+		f.License = resolve(d)
+	}
+	if f.License == "" {
+		f.Verdict, f.Reason = Unknown, "license not found"
+		f.Blocking = cfg.GetUnknown() == UnknownBlock
+		return f
+	}
+	if ok, reason := cfg.licenseAllowed(f.License); !ok {
+		f.Verdict, f.Reason, f.Blocking = Denied, reason, true
+		return f
+	}
+	f.Verdict = Allowed
+	return f
+}
+
+// licenseAllowed evaluates an SPDX-style expression. For "A OR B" one
+// acceptable choice is enough; for "A AND B" every part must be acceptable.
+func (c *Config) licenseAllowed(expr string) (bool, string) {
+	expr = strings.Trim(strings.TrimSpace(expr), "()")
+	var lastReason string
+	for _, choice := range splitExpr(expr, " OR ", "/") {
+		ok, reason := true, ""
+		for _, part := range splitExpr(choice, " AND ") {
+			if partOK, partReason := c.singleLicenseAllowed(part); !partOK {
+				ok, reason = false, partReason
+				break
+			}
+		}
+		if ok {
+			return true, ""
+		}
+		lastReason = reason
+	}
+	return false, lastReason
+}
+
+func (c *Config) singleLicenseAllowed(id string) (bool, string) {
+	id = strings.Trim(strings.TrimSpace(id), "()")
+	if p := matchAny(c.DenyLicenses, id); p != "" {
+		return false, fmt.Sprintf("license %s denied by %s", id, p)
+	}
+	if len(c.AllowLicenses) > 0 && matchAny(c.AllowLicenses, id) == "" {
+		return false, fmt.Sprintf("license %s not in allow list", id)
+	}
+	return true, ""
+}
+
+func splitExpr(s string, seps ...string) []string {
+	parts := []string{s}
+	for _, sep := range seps {
+		var next []string
+		for _, p := range parts {
+			for _, q := range strings.Split(p, sep) {
+				if q = strings.TrimSpace(q); q != "" {
+					next = append(next, q)
+				}
+			}
+		}
+		parts = next
+	}
+	return parts
+}
+
+// matchAny returns the first pattern matching name (case-insensitive), or "".
+// A trailing "/*" also matches nested paths, so github.com/org/* covers
+// github.com/org/repo/v2.
+func matchAny(patterns []string, name string) string {
+	lname := strings.ToLower(name)
+	for _, p := range patterns {
+		lp := strings.ToLower(p)
+		if ok, _ := path.Match(lp, lname); ok {
+			return p
+		}
+		if prefix, found := strings.CutSuffix(lp, "/*"); found && strings.HasPrefix(lname, prefix+"/") {
+			return p
+		}
+	}
+	return ""
+}
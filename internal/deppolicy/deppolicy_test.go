@@ -0,0 +1,225 @@
+package deppolicy
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	cfg := &Config{
+		DenyLicenses:  []string{"GPL-*", "AGPL-*"},
+		DenyPackages:  []string{"github.com/evil/*", "left-pad"},
+		AllowPackages: []string{"github.com/ourco/*"},
+		Licenses:      map[string]string{"github.com/vendor/sdk": "MIT"},
+	}
+	licenses := map[string]string{
+		"github.com/ok/lib":        "MIT",
+		"github.com/copyleft/lib":  "GPL-3.0",
+		"github.com/dual/lib":      "MIT OR GPL-2.0",
+		"github.com/both/lib":      "Apache-2.0 AND AGPL-3.0",
+		"github.com/ourco/private": "GPL-3.0",
+		"github.com/vendor/sdk":    "GPL-3.0", // Overridden by config
+	}
+	resolve := func(d Dependency) string { return licenses[d.Name] }
+
+	tests := []struct {
+		name     string
+		verdict  string
+		blocking bool
+	}{
+		{"github.com/ok/lib", Allowed, false},
+		{"github.com/copyleft/lib", Denied, true},
+		{"github.com/dual/lib", Allowed, false},
+		{"github.com/both/lib", Denied, true},
+		{"github.com/ourco/private", Allowed, false},
+		{"github.com/vendor/sdk", Allowed, false},
+		{"github.com/evil/tool/v2", Denied, true},
+		{"left-pad", Denied, true},
+		{"github.com/mystery/lib", Unknown, false},
+	}
+	for _, tt := range tests {
+		report := Check(cfg, []Dependency{{Ecosystem: EcosystemGo, Name: tt.name, Version: "v1.0.0"}}, resolve)
+		f := report.Findings[0]
+		if f.Verdict != tt.verdict || f.Blocking != tt.blocking {
+			t.Errorf("%s: verdict=%s blocking=%v (%s), want %s blocking=%v", tt.name, f.Verdict, f.Blocking, f.Reason, tt.verdict, tt.blocking)
+		}
+	}
+
+	cfg.Unknown = UnknownBlock
+	if f := Check(cfg, []Dependency{{Name: "github.com/mystery/lib"}}, resolve).Findings[0]; !f.Blocking {
+		t.Error("unknown: block should make unknown licenses blocking")
+	}
+}
+
+func TestCheckAllowList(t *testing.T) {
+	cfg := &Config{AllowLicenses: []string{"MIT", "Apache-2.0", "BSD-*"}}
+	resolve := func(d Dependency) string { return d.Version }
+	for license, ok := range map[string]bool{
+		"MIT":                   true,
+		"mit":                   true,
+		"BSD-3-Clause":          true,
+		"MPL-2.0":               false,
+		"(MIT OR Apache-2.0)":   true,
+		"MIT AND MPL-2.0":       false,
+		"Apache-2.0/MIT":        true,
+		"WTFPL OR BSD-2-Clause": true,
+	} {
+		report := Check(cfg, []Dependency{{Name: "pkg", Version: license}}, resolve)
+		if got := len(report.Violations()) == 0; got != ok {
+			t.Errorf("license %q allowed = %v, want %v (%s)", license, got, ok, report.Findings[0].Reason)
+		}
+	}
+}
+
+func TestReportSummary(t *testing.T) {
+	cfg := &Config{DenyLicenses: []string{"GPL-3.0"}}
+	deps := []Dependency{{Name: "a", Version: "1"}, {Name: "b", Version: "2"}}
+	report := Check(cfg, deps, func(d Dependency) string {
+		if d.Name == "b" {
+			return "GPL-3.0"
+		}
+		return "MIT"
+	})
+	if report.Findings[0].Dependency.Name != "b" {
+		t.Error("blocking findings should sort first")
+	}
+	if got := report.Summary(); !strings.Contains(got, "1 violation") || !strings.Contains(got, "b@2") || !strings.Contains(got, "GPL-3.0") {
+		t.Errorf("Summary() = %q", got)
+	}
+	if (&Report{}).Summary() != "" {
+		t.Error("empty report should have empty summary")
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	var nilCfg *Config
+	if nilCfg.IsEnabled() || nilCfg.Validate() != nil {
+		t.Error("nil config should be disabled and valid")
+	}
+	if (&Config{Licenses: map[string]string{"x": "MIT"}}).IsEnabled() {
+		t.Error("overrides alone should not enable the policy")
+	}
+	if err := (&Config{DenyLicenses: []string{"GPL-*"}, Unknown: "block"}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := (&Config{Unknown: "sometimes"}).Validate(); err == nil {
+		t.Error("expected error for invalid unknown mode")
+	}
+	if err := (&Config{DenyPackages: []string{"[bad"}}).Validate(); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestClassifyLicense(t *testing.T) {
+	tests := map[string]string{
+		"MIT License\n\nPermission is hereby granted, free of charge, to any person":            "MIT",
+		"Apache License\n                           Version 2.0, January 2004":                  "Apache-2.0",
+		"GNU GENERAL PUBLIC LICENSE\n Version 3, 29 June 2007":                                  "GPL-3.0",
+		"GNU GENERAL PUBLIC LICENSE\n Version 2, June 1991":                                     "GPL-2.0",
+		"GNU LESSER GENERAL PUBLIC LICENSE Version 3":                                           "LGPL-3.0",
+		"GNU AFFERO GENERAL PUBLIC LICENSE Version 3, 19 November 2007":                         "AGPL-3.0",
+		"Redistribution and use in source and binary forms ... Neither the name of Google Inc.": "BSD-3-Clause",
+		"Redistribution and use in source and binary forms, with or without modification":       "BSD-2-Clause",
+		"Mozilla Public License Version 2.0":                                                    "MPL-2.0",
+		"// SPDX-License-Identifier: Apache-2.0 OR MIT":                                         "Apache-2.0 OR MIT",
+		"All rights reserved. Do not copy.":                                                     "",
+	}
+	for text, want := range tests {
+		if got := ClassifyLicense(text); got != want {
+			t.Errorf("ClassifyLicense(%.40q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestResolver(t *testing.T) {
+	cache := t.TempDir()
+	modDir := filepath.Join(cache, "github.com", "!burnt!sushi", "toml@v1.3.2")
+	if err := os.MkdirAll(modDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(modDir, "COPYING"), []byte("The MIT License\nPermission is hereby granted, free of charge"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repo := t.TempDir()
+	for name, pkgJSON := range map[string]string{
+		"left":        `{"name": "left", "license": "WTFPL"}`,
+		"@scope/old":  `{"name": "@scope/old", "license": {"type": "BSD-2-Clause"}}`,
+		"legacy-list": `{"name": "legacy-list", "licenses": [{"type": "MIT"}, {"type": "Apache-2.0"}]}`,
+	} {
+		dir := filepath.Join(repo, "web", "node_modules", filepath.FromSlash(name))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(pkgJSON), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := &Resolver{Dir: repo, GoModCache: cache, CargoHome: t.TempDir()}
+	tests := []struct {
+		dep  Dependency
+		want string
+	}{
+		{Dependency{Ecosystem: EcosystemGo, Name: "github.com/BurntSushi/toml", Version: "v1.3.2"}, "MIT"},
+		{Dependency{Ecosystem: EcosystemGo, Name: "github.com/BurntSushi/toml", Version: "v9.9.9"}, ""},
+		{Dependency{Ecosystem: EcosystemNPM, Name: "left", Manifest: "web/package.json"}, "WTFPL"},
+		{Dependency{Ecosystem: EcosystemNPM, Name: "@scope/old", Manifest: "web/package.json"}, "BSD-2-Clause"},
+		{Dependency{Ecosystem: EcosystemNPM, Name: "legacy-list", Manifest: "web/package.json"}, "MIT OR Apache-2.0"},
+		{Dependency{Ecosystem: EcosystemPyPI, Name: "requests"}, ""},
+	}
+	for _, tt := range tests {
+		if got := r.Resolve(tt.dep); got != tt.want {
+			t.Errorf("Resolve(%s) = %q, want %q", tt.dep, got, tt.want)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q", "-b", "main")
+	write("go.mod", "module example.com/app\n\nrequire github.com/ok/lib v1.0.0\n")
+	write("README.md", "hi\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "base")
+	git("checkout", "-q", "-b", "work")
+	write("go.mod", "module example.com/app\n\nrequire (\n\tgithub.com/ok/lib v1.1.0\n\tgithub.com/new/dep v0.2.0 // indirect\n)\n")
+	write("web/package.json", `{"dependencies": {"left-pad": "^1.3.0"}}`)
+	write("README.md", "changed\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "deps")
+
+	deps, err := Scan(dir, "main")
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	var got []string
+	for _, d := range deps {
+		got = append(got, d.Ecosystem+":"+d.String()+"<"+d.Previous)
+	}
+	want := "gomod:github.com/new/dep@v0.2.0<,gomod:github.com/ok/lib@v1.1.0<v1.0.0,npm:left-pad@^1.3.0<"
+	if strings.Join(got, ",") != want {
+		t.Errorf("Scan() = %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,193 @@
+package deppolicy
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// licenseFiles are the file names checked for license text, in order.
+var licenseFiles = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "COPYING", "LICENSE-MIT", "LICENSE-APACHE"}
+
+// Resolver finds dependency licenses from what's installed locally: the Go
+// module cache, node_modules in the repo, and the Cargo registry. PyPI
+// packages aren't installed per-repo, so they resolve only via overrides.
+type Resolver struct {
+	Dir        string // Repo root (for node_modules)
+	GoModCache string // Defaults to `go env GOMODCACHE`
+	CargoHome  string // Defaults to $CARGO_HOME or ~/.cargo
+}
+
+// Resolve returns the license of d, or "" if it can't be determined.
+func (r *Resolver) Resolve(d Dependency) string {
+	switch d.Ecosystem {
+	case EcosystemGo:
+		return r.resolveGo(d)
+	case EcosystemNPM:
+		return r.resolveNPM(d)
+	case EcosystemCargo:
+		return r.resolveCargo(d)
+	}
+	return ""
+}
+
+func (r *Resolver) resolveGo(d Dependency) string {
+	cache := r.GoModCache
+	if cache == "" {
+		out, err := exec.Command("go", "env", "GOMODCACHE").Output()
+		if err != nil {
+			return ""
+		}
+		cache = strings.TrimSpace(string(out))
+		r.GoModCache = cache
+	}
+	if cache == "" || d.Version == "" {
+		return ""
+	}
+	return licenseInDir(filepath.Join(cache, filepath.FromSlash(escapeModulePath(d.Name))+"@"+d.Version))
+}
+
+func (r *Resolver) resolveNPM(d Dependency) string {
+	moduleDir := filepath.Join(r.Dir, filepath.Dir(filepath.FromSlash(d.Manifest)), "node_modules", filepath.FromSlash(d.Name))
+	data, err := os.ReadFile(filepath.Join(moduleDir, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		License  json.RawMessage `json:"license"`
+		Licenses []struct {
+			Type string `json:"type"`
+		} `json:"licenses"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(pkg.License, &s) == nil && s != "" {
+		return s
+	}
+	var obj struct {
+		Type string `json:"type"`
+	}
+	if json.Unmarshal(pkg.License, &obj) == nil && obj.Type != "" {
+		return obj.Type
+	}
+	var types []string
+	for _, l := range pkg.Licenses {
+		if l.Type != "" {
+			types = append(types, l.Type)
+		}
+	}
+	if len(types) > 0 {
+		return strings.Join(types, " OR ")
+	}
+	return licenseInDir(moduleDir)
+}
+
+var cargoLicenseRe = regexp.MustCompile(`(?m)^license\s*=\s*"([^"]+)"`)
+
+func (r *Resolver) resolveCargo(d Dependency) string {
+	home := r.CargoHome
+	if home == "" {
+		home = os.Getenv("CARGO_HOME")
+	}
+	if home == "" {
+		userHome, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		home = filepath.Join(userHome, ".cargo")
+	}
+	version := strings.TrimLeft(d.Version, "=^~ ")
+	dirs, _ := filepath.Glob(filepath.Join(home, "registry", "src", "*", d.Name+"-"+version))
+	for _, dir := range dirs {
+		if data, err := os.ReadFile(filepath.Join(dir, "Cargo.toml")); err == nil {
+			if m := cargoLicenseRe.FindSubmatch(data); m != nil {
+				return string(m[1])
+			}
+		}
+		if l := licenseInDir(dir); l != "" {
+			return l
+		}
+	}
+	return ""
+}
+
+func licenseInDir(dir string) string {
+	for _, name := range licenseFiles {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		if id := ClassifyLicense(string(data)); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// escapeModulePath applies the module cache's case encoding: each upper-case
+// letter becomes '!' followed by its lower-case form.
+func escapeModulePath(p string) string {
+	var b strings.Builder
+	for _, r := range p {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+var spdxTagRe = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9.+\- ()]+)`)
+
+// ClassifyLicense maps license text to an SPDX identifier, or "" if the
+// text isn't recognized. Copyleft families are checked first since their
+// texts quote permissive phrases.
+func ClassifyLicense(text string) string {
+	if m := spdxTagRe.FindStringSubmatch(text); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	t := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	v3 := strings.Contains(t, "version 3")
+	switch {
+	case strings.Contains(t, "gnu affero general public license"):
+		return "AGPL-3.0"
+	case strings.Contains(t, "gnu lesser general public license"), strings.Contains(t, "gnu library general public license"):
+		if v3 {
+			return "LGPL-3.0"
+		}
+		return "LGPL-2.1"
+	case strings.Contains(t, "gnu general public license"):
+		if v3 {
+			return "GPL-3.0"
+		}
+		return "GPL-2.0"
+	case strings.Contains(t, "server side public license"):
+		return "SSPL-1.0"
+	case strings.Contains(t, "mozilla public license") && strings.Contains(t, "2.0"):
+		return "MPL-2.0"
+	case strings.Contains(t, "eclipse public license"):
+		return "EPL-2.0"
+	case strings.Contains(t, "apache license") && strings.Contains(t, "version 2.0"):
+		return "Apache-2.0"
+	case strings.Contains(t, "permission is hereby granted, free of charge"):
+		return "MIT"
+	case strings.Contains(t, "redistribution and use in source and binary forms"):
+		if strings.Contains(t, "neither the name") || strings.Contains(t, "names of its contributors") {
+			return "BSD-3-Clause"
+		}
+		return "BSD-2-Clause"
+	case strings.Contains(t, "permission to use, copy, modify, and/or distribute"), strings.Contains(t, "permission to use, copy, modify, and distribute this software for any purpose"):
+		return "ISC"
+	case strings.Contains(t, "free and unencumbered software released into the public domain"):
+		return "Unlicense"
+	}
+	return ""
+}
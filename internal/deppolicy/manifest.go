@@ -0,0 +1,186 @@
+package deppolicy
+
+import (
+	"bufio"
+	"encoding/json"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Ecosystems.
+const (
+	EcosystemGo    = "gomod"
+	EcosystemNPM   = "npm"
+	EcosystemPyPI  = "pypi"
+	EcosystemCargo = "cargo"
+)
+
+// ManifestEcosystem returns the ecosystem of a manifest file path, or "" if
+// the file isn't a recognized manifest.
+func ManifestEcosystem(file string) string {
+	base := path.Base(file)
+	switch {
+	case base == "go.mod":
+		return EcosystemGo
+	case base == "package.json":
+		if strings.Contains("/"+file, "/node_modules/") {
+			return ""
+		}
+		return EcosystemNPM
+	case base == "Cargo.toml":
+		return EcosystemCargo
+	case strings.HasPrefix(base, "requirements") && strings.HasSuffix(base, ".txt"):
+		return EcosystemPyPI
+	}
+	return ""
+}
+
+// ParseManifest returns name → version for the dependencies declared in a
+// manifest. Unparseable content yields an empty map.
+func ParseManifest(file, content string) map[string]string {
+	switch ManifestEcosystem(file) {
+	case EcosystemGo:
+		return parseGoMod(content)
+	case EcosystemNPM:
+		return parsePackageJSON(content)
+	case EcosystemCargo:
+		return parseCargoToml(content)
+	case EcosystemPyPI:
+		return parseRequirements(content)
+	}
+	return map[string]string{}
+}
+
+// Changes returns dependencies added or re-versioned in file between the
+// before and after contents.
+func Changes(file, before, after string) []Dependency {
+	eco := ManifestEcosystem(file)
+	prev := ParseManifest(file, before)
+	next := ParseManifest(file, after)
+	var out []Dependency
+	for name, ver := range next {
+		old, existed := prev[name]
+		if existed && old == ver {
+			continue
+		}
+		d := Dependency{Ecosystem: eco, Name: name, Version: ver, Manifest: file}
+		if existed {
+			d.Previous = old
+		}
+		out = append(out, d)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func parseGoMod(content string) map[string]string {
+	deps := map[string]string{}
+	inRequire := false
+	sc := bufio.NewScanner(strings.NewReader(content))
+	for sc.Scan() {
+		line := sc.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inRequire && fields[0] == ")":
+			inRequire = false
+			continue
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequire = true
+			continue
+		case fields[0] == "require" && len(fields) >= 3:
+			deps[fields[1]] = fields[2]
+		case inRequire && len(fields) >= 2:
+			deps[fields[0]] = fields[1]
+		}
+	}
+	return deps
+}
+
+func parsePackageJSON(content string) map[string]string {
+	var pkg struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+	}
+	deps := map[string]string{}
+	if err := json.Unmarshal([]byte(content), &pkg); err != nil {
+		return deps
+	}
+	for _, m := range []map[string]string{pkg.PeerDependencies, pkg.OptionalDependencies, pkg.DevDependencies, pkg.Dependencies} {
+		for name, ver := range m {
+			deps[name] = ver
+		}
+	}
+	return deps
+}
+
+// requirementRe matches "name[extras] <op> version" in requirements files.
+var requirementRe = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(?:\[[^\]]*\])?\s*(?:(==|>=|<=|~=|!=|>|<|===)\s*([^\s;,#]+))?`)
+
+func parseRequirements(content string) map[string]string {
+	deps := map[string]string{}
+	sc := bufio.NewScanner(strings.NewReader(content))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		m := requirementRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		// PyPI names are case-insensitive and treat -, _ and . alike.
+		name := strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(m[1]))
+		deps[name] = m[3]
+	}
+	return deps
+}
+
+var (
+	cargoSectionRe = regexp.MustCompile(`^\[(?:target\..+\.)?(dependencies|dev-dependencies|build-dependencies)\]$`)
+	cargoInlineRe  = regexp.MustCompile(`version\s*=\s*"([^"]*)"`)
+)
+
+func parseCargoToml(content string) map[string]string {
+	deps := map[string]string{}
+	inDeps := false
+	sc := bufio.NewScanner(strings.NewReader(content))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			inDeps = cargoSectionRe.MatchString(line)
+			continue
+		}
+		if !inDeps {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		name = strings.Trim(strings.TrimSpace(name), `"`)
+		value = strings.TrimSpace(value)
+		switch {
+		case strings.HasPrefix(value, `"`):
+			deps[name] = strings.Trim(value, `"`)
+		case strings.HasPrefix(value, "{"):
+			ver := ""
+			if m := cargoInlineRe.FindStringSubmatch(value); m != nil {
+				ver = m[1]
+			}
+			deps[name] = ver
+		}
+	}
+	return deps
+}
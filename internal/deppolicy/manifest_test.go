@@ -0,0 +1,125 @@
+package deppolicy
+
+import "testing"
+
+func TestManifestEcosystem(t *testing.T) {
+	for file, want := range map[string]string{
+		"go.mod":                          EcosystemGo,
+		"tools/go.mod":                    EcosystemGo,
+		"web/package.json":                EcosystemNPM,
+		"web/node_modules/x/package.json": "",
+		"requirements.txt":                EcosystemPyPI,
+		"requirements-dev.txt":            EcosystemPyPI,
+		"crates/core/Cargo.toml":          EcosystemCargo,
+		"go.sum":                          "",
+		"README.md":                       "",
+	} {
+		if got := ManifestEcosystem(file); got != want {
+			t.Errorf("ManifestEcosystem(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestParseGoMod(t *testing.T) {
+	deps := ParseManifest("go.mod", `module example.com/app
+
+go 1.22
+
+require github.com/single/dep v1.0.0
+
+require (
+	github.com/a/b v0.1.0
+	github.com/c/d v2.0.0+incompatible // indirect
+)
+
+replace github.com/a/b => ../b
+`)
+	want := map[string]string{"github.com/single/dep": "v1.0.0", "github.com/a/b": "v0.1.0", "github.com/c/d": "v2.0.0+incompatible"}
+	if len(deps) != len(want) {
+		t.Fatalf("deps = %v", deps)
+	}
+	for name, ver := range want {
+		if deps[name] != ver {
+			t.Errorf("%s = %q, want %q", name, deps[name], ver)
+		}
+	}
+}
+
+func TestParsePackageJSON(t *testing.T) {
+	deps := ParseManifest("package.json", `{
+  "name": "app",
+  "dependencies": {"react": "^18.2.0"},
+  "devDependencies": {"eslint": "8.0.0", "react": "^17.0.0"}
+}`)
+	if deps["react"] != "^18.2.0" || deps["eslint"] != "8.0.0" || len(deps) != 2 {
+		t.Errorf("deps = %v", deps)
+	}
+	if got := ParseManifest("package.json", "{not json"); len(got) != 0 {
+		t.Errorf("invalid JSON = %v, want empty", got)
+	}
+}
+
+func TestParseRequirements(t *testing.T) {
+	deps := ParseManifest("requirements.txt", `# deps
+requests==2.31.0
+Flask_Login>=0.6 ; python_version > "3.8"
+uvicorn[standard]~=0.23.0
+numpy
+-r base.txt
+--index-url https://example.com
+`)
+	want := map[string]string{"requests": "2.31.0", "flask-login": "0.6", "uvicorn": "0.23.0", "numpy": ""}
+	if len(deps) != len(want) {
+		t.Fatalf("deps = %v", deps)
+	}
+	for name, ver := range want {
+		if v, ok := deps[name]; !ok || v != ver {
+			t.Errorf("%s = %q, want %q", name, v, ver)
+		}
+	}
+}
+
+func TestParseCargoToml(t *testing.T) {
+	deps := ParseManifest("Cargo.toml", `[package]
+name = "app"
+version = "0.1.0"
+
+[dependencies]
+serde = "1.0"
+tokio = { version = "1.32", features = ["full"] }
+local = { path = "../local" }
+
+[target.'cfg(unix)'.dependencies]
+libc = "0.2"
+
+[dev-dependencies]
+insta = "1.34"
+
+[features]
+default = []
+`)
+	want := map[string]string{"serde": "1.0", "tokio": "1.32", "local": "", "libc": "0.2", "insta": "1.34"}
+	if len(deps) != len(want) {
+		t.Fatalf("deps = %v", deps)
+	}
+	for name, ver := range want {
+		if v, ok := deps[name]; !ok || v != ver {
+			t.Errorf("%s = %q, want %q", name, v, ver)
+		}
+	}
+}
+
+func TestChanges(t *testing.T) {
+	before := `{"dependencies": {"a": "1.0.0", "b": "1.0.0", "gone": "1.0.0"}}`
+	after := `{"dependencies": {"a": "1.0.0", "b": "2.0.0", "c": "0.1.0"}}`
+	changes := Changes("package.json", before, after)
+	if len(changes) != 2 {
+		t.Fatalf("Changes() = %+v", changes)
+	}
+	if c := changes[0]; c.Name != "b" || c.Version != "2.0.0" || c.Previous != "1.0.0" || c.Ecosystem != EcosystemNPM {
+		t.Errorf("changes[0] = %+v", c)
+	}
+	if c := changes[1]; c.Name != "c" || c.Previous != "" {
+		t.Errorf("changes[1] = %+v", c)
+	}
+}
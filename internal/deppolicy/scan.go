@@ -0,0 +1,39 @@
+package deppolicy
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Scan returns the dependencies added or upgraded in dir between base and
+// HEAD, across every manifest the diff touches.
+func Scan(dir, base string) ([]Dependency, error) {
+	out, err := git(dir, "diff", "--name-only", "--no-renames", base, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	var deps []Dependency
+	for _, file := range strings.Split(strings.TrimSpace(out), "\n") {
+		if file == "" || ManifestEcosystem(file) == "" {
+			continue
+		}
+		before, _ := git(dir, "show", base+":"+file) // Missing = newly added manifest
+		after, _ := git(dir, "show", "HEAD:"+file)   // Missing = deleted manifest
+		deps = append(deps, Changes(file, before, after)...)
+	}
+	return deps, nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,234 @@
+package depupdate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Checker finds a dependency manager's outdated direct dependencies.
+// Implement it (and Register it) to support another ecosystem.
+type Checker interface {
+	// Name identifies the checker in config ("go", "npm") and in updates.
+	Name() string
+
+	// Detect reports whether dir has this checker's manifest.
+	Detect(dir string) bool
+
+	// Outdated returns the outdated direct dependencies in dir.
+	Outdated(ctx context.Context, dir string) ([]Update, error)
+}
+
+var builtins = []Checker{goChecker{}, npmChecker{}}
+
+// Register adds a built-in checker.
+func Register(c Checker) {
+	builtins = append(builtins, c)
+}
+
+// BuiltinNames returns the names of the built-in checkers.
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtins))
+	for _, c := range builtins {
+		names = append(names, c.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func builtinChecker(name string) Checker {
+	for _, c := range builtins {
+		if c.Name() == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Selected returns the checkers the config selects: the named built-ins
+// (all of them when none are named) and the custom commands.
+func (c *Config) Selected() []Checker {
+	var checkers []Checker
+	if c == nil || len(c.Checkers) == 0 {
+		checkers = append(checkers, builtins...)
+	} else {
+		for _, name := range c.Checkers {
+			if b := builtinChecker(name); b != nil {
+				checkers = append(checkers, b)
+			}
+		}
+	}
+	if c != nil {
+		for _, cc := range c.Commands {
+			checkers = append(checkers, cc)
+		}
+	}
+	return checkers
+}
+
+// Check runs every detected checker in each configured dir of the
+// checkout and returns the updates that aren't ignored. A failing checker
+// doesn't stop the others; its error is returned alongside the updates.
+func Check(ctx context.Context, checkout string, cfg *Config) ([]Update, error) {
+	var updates []Update
+	var errs []error
+	for _, rel := range cfg.GetDirs() {
+		dir := filepath.Join(checkout, rel)
+		for _, c := range cfg.Selected() {
+			if !c.Detect(dir) {
+				continue
+			}
+			found, err := c.Outdated(ctx, dir)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s in %s: %w", c.Name(), rel, err))
+				continue
+			}
+			for _, u := range found {
+				if cfg.Ignored(u.Name) {
+					continue
+				}
+				u.Ecosystem, u.Dir = c.Name(), rel
+				updates = append(updates, u)
+			}
+		}
+	}
+	return updates, errors.Join(errs...)
+}
+
+// runToolFn is a seam for tests. Production runs the tool in dir and returns
+// its stdout.
+var runToolFn = func(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...) //nolint:gosec // G204: tool and args from checker or rig config
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return stdout.Bytes(), fmt.Errorf("%s: %v: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// goChecker lists Go modules with newer versions (go list -m -u).
+type goChecker struct{}
+
+func (goChecker) Name() string { return "go" }
+
+func (goChecker) Detect(dir string) bool { return fileExists(filepath.Join(dir, "go.mod")) }
+
+func (goChecker) Outdated(ctx context.Context, dir string) ([]Update, error) {
+	out, err := runToolFn(ctx, dir, "go", "list", "-m", "-u", "-json", "all")
+	if err != nil {
+		return nil, err
+	}
+	return parseGoList(out)
+}
+
+// parseGoList reads the JSON stream of go list -m -u -json, keeping direct
+// dependencies that have an update.
+func parseGoList(out []byte) ([]Update, error) {
+	var updates []Update
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var m struct {
+			Path     string
+			Version  string
+			Main     bool
+			Indirect bool
+			Update   *struct{ Version string }
+		}
+		if err := dec.Decode(&m); err != nil {
+			if err == io.EOF {
+				return updates, nil
+			}
+			return nil, fmt.Errorf("parsing go list output: %w", err)
+		}
+		if m.Main || m.Indirect || m.Update == nil {
+			continue
+		}
+		updates = append(updates, Update{Name: m.Path, Current: m.Version, Latest: m.Update.Version})
+	}
+}
+
+// npmChecker lists outdated packages (npm outdated).
+type npmChecker struct{}
+
+func (npmChecker) Name() string { return "npm" }
+
+func (npmChecker) Detect(dir string) bool { return fileExists(filepath.Join(dir, "package.json")) }
+
+func (npmChecker) Outdated(ctx context.Context, dir string) ([]Update, error) {
+	out, err := runToolFn(ctx, dir, "npm", "outdated", "--json")
+	// npm outdated exits 1 when anything is outdated.
+	if err != nil && len(bytes.TrimSpace(out)) == 0 {
+		return nil, err
+	}
+	return parseNPMOutdated(out)
+}
+
+// parseNPMOutdated reads npm outdated --json. Packages that aren't
+// installed (no current version) are skipped.
+func parseNPMOutdated(out []byte) ([]Update, error) {
+	if len(bytes.TrimSpace(out)) == 0 {
+		return nil, nil
+	}
+	var pkgs map[string]struct {
+		Current string `json:"current"`
+		Latest  string `json:"latest"`
+	}
+	if err := json.Unmarshal(out, &pkgs); err != nil {
+		return nil, fmt.Errorf("parsing npm outdated output: %w", err)
+	}
+	var updates []Update
+	for name, p := range pkgs {
+		if p.Current == "" || p.Latest == "" || p.Current == p.Latest {
+			continue
+		}
+		updates = append(updates, Update{Name: name, Current: p.Current, Latest: p.Latest})
+	}
+	sort.Slice(updates, func(i, j int) bool { return updates[i].Name < updates[j].Name })
+	return updates, nil
+}
+
+// CommandChecker is a custom checker from rig config: when Manifest exists
+// in a dir (or Manifest is empty), Command runs there through sh and prints
+// a JSON array of {"name", "current", "latest"}.
+type CommandChecker struct {
+	CheckerName string `json:"name"`
+	Manifest    string `json:"manifest,omitempty"`
+	Command     string `json:"command"`
+}
+
+// Name returns the checker's configured name.
+func (c CommandChecker) Name() string { return c.CheckerName }
+
+// Detect reports whether the manifest exists in dir.
+func (c CommandChecker) Detect(dir string) bool {
+	return c.Manifest == "" || fileExists(filepath.Join(dir, c.Manifest))
+}
+
+// Outdated runs the command and parses its JSON.
+func (c CommandChecker) Outdated(ctx context.Context, dir string) ([]Update, error) {
+	out, err := runToolFn(ctx, dir, "sh", "-c", c.Command)
+	if err != nil {
+		return nil, err
+	}
+	var updates []Update
+	if err := json.Unmarshal(out, &updates); err != nil {
+		return nil, fmt.Errorf("parsing %s output: %w", c.CheckerName, err)
+	}
+	return updates, nil
+}
@@ -0,0 +1,350 @@
+// Package depupdate implements the dependency update sweep: per rig, it
+// asks pluggable checkers (go.mod, package.json, custom commands) which
+// direct dependencies are outdated, groups the updates into beads and
+// remembers which beads it filed so a sweep never files the same update
+// twice while its bead is open.
+package depupdate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Grouping modes.
+const (
+	GroupEcosystem  = "ecosystem"  // One bead per ecosystem and dir; majors get their own
+	GroupDependency = "dependency" // One bead per dependency
+	GroupRig        = "rig"        // One bead for everything
+)
+
+// Sling policies.
+const (
+	SlingNever      = "never"       // File beads only
+	SlingQuietHours = "quiet_hours" // Sling while the rig is in quiet hours
+	SlingImmediate  = "immediately" // Sling as soon as they are filed
+)
+
+// DefaultEvery is how often a rig's dependencies are checked.
+const DefaultEvery = 24 * time.Hour
+
+// Config is the dep_updates section of a rig's settings/config.json.
+//
+//	"dep_updates": {
+//	  "enabled": true,
+//	  "every": "7d",
+//	  "checkers": ["go", "npm"],
+//	  "dirs": [".", "web"],
+//	  "group_by": "ecosystem",
+//	  "ignore": ["github.com/aws/"],
+//	  "sling": "quiet_hours",
+//	  "commands": [{"name": "pip", "manifest": "requirements.txt", "command": "./scripts/outdated.sh"}]
+//	}
+//
+// Checkers defaults to every built-in checker whose manifest is present;
+// commands add custom checkers. Dirs are relative to the rig's checkout
+// (default "."). Ignore entries match a dependency name or name prefix.
+type Config struct {
+	Enabled  bool             `json:"enabled"`
+	Every    string           `json:"every,omitempty"`
+	Checkers []string         `json:"checkers,omitempty"`
+	Commands []CommandChecker `json:"commands,omitempty"`
+	Dirs     []string         `json:"dirs,omitempty"`
+	GroupBy  string           `json:"group_by,omitempty"`
+	Ignore   []string         `json:"ignore,omitempty"`
+	Sling    string           `json:"sling,omitempty"`
+}
+
+// IsEnabled reports whether the sweep is configured and on.
+func (c *Config) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// Validate checks the config. A nil config is valid (no sweep).
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Every != "" {
+		if _, err := parseEvery(c.Every); err != nil {
+			return fmt.Errorf("every: %w", err)
+		}
+	}
+	for _, name := range c.Checkers {
+		if builtinChecker(name) == nil {
+			return fmt.Errorf("unknown checker %q (built in: %s)", name, strings.Join(BuiltinNames(), ", "))
+		}
+	}
+	for i, cc := range c.Commands {
+		if cc.CheckerName == "" || cc.Command == "" {
+			return fmt.Errorf("commands[%d]: name and command are required", i)
+		}
+	}
+	switch c.GroupBy {
+	case "", GroupEcosystem, GroupDependency, GroupRig:
+	default:
+		return fmt.Errorf("group_by %q: use %s, %s or %s", c.GroupBy, GroupEcosystem, GroupDependency, GroupRig)
+	}
+	switch c.Sling {
+	case "", SlingNever, SlingQuietHours, SlingImmediate:
+	default:
+		return fmt.Errorf("sling %q: use %s, %s or %s", c.Sling, SlingNever, SlingQuietHours, SlingImmediate)
+	}
+	return nil
+}
+
+// GetEvery returns the check interval or the default.
+func (c *Config) GetEvery() time.Duration {
+	if c != nil && c.Every != "" {
+		if d, err := parseEvery(c.Every); err == nil {
+			return d
+		}
+	}
+	return DefaultEvery
+}
+
+// GetDirs returns the dirs to check, relative to the checkout.
+func (c *Config) GetDirs() []string {
+	if c == nil || len(c.Dirs) == 0 {
+		return []string{"."}
+	}
+	return c.Dirs
+}
+
+// GetSling returns the sling policy or the default (never).
+func (c *Config) GetSling() string {
+	if c == nil || c.Sling == "" {
+		return SlingNever
+	}
+	return c.Sling
+}
+
+// Ignored reports whether a dependency is on the ignore list.
+func (c *Config) Ignored(name string) bool {
+	if c == nil {
+		return false
+	}
+	for _, prefix := range c.Ignore {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseEvery parses a Go duration or a number of days ("7d").
+func parseEvery(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
+
+// Update is one outdated direct dependency.
+type Update struct {
+	Ecosystem string `json:"ecosystem"`
+	Dir       string `json:"dir"` // Relative to the checkout
+	Name      string `json:"name"`
+	Current   string `json:"current"`
+	Latest    string `json:"latest"`
+}
+
+// Major reports whether the update crosses a major version (or a minor
+// version below 1.0), where breaking changes are expected.
+func (u Update) Major() bool {
+	cur, latest := versionParts(u.Current), versionParts(u.Latest)
+	if len(cur) == 0 || len(latest) == 0 {
+		return false
+	}
+	if cur[0] != latest[0] {
+		return true
+	}
+	return cur[0] == 0 && len(cur) > 1 && len(latest) > 1 && cur[1] != latest[1]
+}
+
+// versionParts returns the leading numeric components of a version.
+func versionParts(v string) []int {
+	v = strings.TrimLeft(strings.TrimSpace(v), "v^~=")
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		end := 0
+		for end < len(p) && p[end] >= '0' && p[end] <= '9' {
+			end++
+		}
+		if end == 0 {
+			break
+		}
+		n, _ := strconv.Atoi(p[:end])
+		parts = append(parts, n)
+		if end < len(p) {
+			break
+		}
+	}
+	return parts
+}
+
+// Batch is a group of updates filed as one bead.
+type Batch struct {
+	Key     string   `json:"key"`
+	Updates []Update `json:"updates"`
+}
+
+// Group splits updates into batches. Keys are stable across sweeps so an
+// open bead is recognized as covering its batch.
+func Group(updates []Update, by string) []Batch {
+	index := make(map[string]int)
+	var batches []Batch
+	for _, u := range updates {
+		var key string
+		switch by {
+		case GroupRig:
+			key = "all"
+		case GroupDependency:
+			key = u.Ecosystem + ":" + dirKey(u.Dir) + u.Name
+		default:
+			key = u.Ecosystem + ":" + dirKey(u.Dir)
+			if u.Major() {
+				key += u.Name + "@major"
+			} else {
+				key = strings.TrimSuffix(key, ":")
+			}
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(batches)
+			index[key] = i
+			batches = append(batches, Batch{Key: key})
+		}
+		batches[i].Updates = append(batches[i].Updates, u)
+	}
+	sort.Slice(batches, func(i, j int) bool { return batches[i].Key < batches[j].Key })
+	return batches
+}
+
+func dirKey(dir string) string {
+	if dir == "" || dir == "." {
+		return ""
+	}
+	return dir + ":"
+}
+
+// Title is the bead title for the batch.
+func (b Batch) Title() string {
+	if len(b.Updates) == 1 {
+		u := b.Updates[0]
+		title := fmt.Sprintf("Update %s to %s (%s", u.Name, u.Latest, u.Ecosystem)
+		if u.Major() {
+			title += ", major"
+		}
+		return title + ")"
+	}
+	eco := b.Updates[0].Ecosystem
+	for _, u := range b.Updates {
+		if u.Ecosystem != eco {
+			return fmt.Sprintf("Update %d dependencies", len(b.Updates))
+		}
+	}
+	title := fmt.Sprintf("Update %d %s dependencies", len(b.Updates), eco)
+	if dir := b.Updates[0].Dir; dir != "" && dir != "." {
+		title += " in " + dir
+	}
+	return title
+}
+
+// Description is the bead description: the updates and what to do.
+func (b Batch) Description() string {
+	var sb strings.Builder
+	sb.WriteString("Outdated dependencies found by the dependency update sweep:\n\n")
+	major := false
+	for _, u := range b.Updates {
+		fmt.Fprintf(&sb, "- %s %s → %s (%s", u.Name, u.Current, u.Latest, u.Ecosystem)
+		if u.Dir != "" && u.Dir != "." {
+			sb.WriteString(", " + u.Dir)
+		}
+		if u.Major() {
+			sb.WriteString(", major")
+			major = true
+		}
+		sb.WriteString(")\n")
+	}
+	sb.WriteString("\nUpdate them, run the build and tests, and fix what breaks.")
+	if major {
+		sb.WriteString(" Major versions can have breaking changes: read the changelog or migration guide first.")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// Filed is a bead the sweep filed for a batch.
+type Filed struct {
+	Bead    string    `json:"bead"`
+	Title   string    `json:"title"`
+	FiledAt time.Time `json:"filed_at"`
+	Slung   bool      `json:"slung,omitempty"`
+}
+
+// State is a rig's sweep state.
+type State struct {
+	LastCheck time.Time         `json:"last_check,omitzero"`
+	Filed     map[string]*Filed `json:"filed"` // Batch key → bead
+}
+
+// StatePath returns the path of a rig's sweep state.
+func StatePath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "dep-updates.json")
+}
+
+// LoadState reads a rig's state, returning an empty state when the file
+// doesn't exist yet.
+func LoadState(rigPath string) (*State, error) {
+	state := &State{Filed: make(map[string]*Filed)}
+	data, err := os.ReadFile(StatePath(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Filed == nil {
+		state.Filed = make(map[string]*Filed)
+	}
+	return state, nil
+}
+
+// Save writes the state.
+func (s *State) Save(rigPath string) error {
+	path := StatePath(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: runtime state
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Due reports whether the rig's dependencies should be checked again.
+func (s *State) Due(now time.Time, every time.Duration) bool {
+	return s.LastCheck.IsZero() || now.Sub(s.LastCheck) >= every
+}
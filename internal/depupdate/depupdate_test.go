@@ -0,0 +1,183 @@
+package depupdate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	valid := []*Config{
+		nil,
+		{Enabled: true},
+		{Every: "7d", Checkers: []string{"go"}, GroupBy: GroupDependency, Sling: SlingQuietHours},
+		{Commands: []CommandChecker{{CheckerName: "pip", Command: "./outdated.sh"}}},
+	}
+	for _, c := range valid {
+		if err := c.Validate(); err != nil {
+			t.Errorf("Validate(%+v) = %v", c, err)
+		}
+	}
+	invalid := []*Config{
+		{Every: "weekly"},
+		{Checkers: []string{"cargo"}},
+		{Commands: []CommandChecker{{CheckerName: "pip"}}},
+		{GroupBy: "team"},
+		{Sling: "nightly"},
+	}
+	for _, c := range invalid {
+		if err := c.Validate(); err == nil {
+			t.Errorf("Validate(%+v) should fail", c)
+		}
+	}
+	if got := (&Config{Every: "7d"}).GetEvery(); got != 7*24*time.Hour {
+		t.Errorf("GetEvery() = %v", got)
+	}
+}
+
+func TestMajor(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"v1.2.3", "v1.4.0", false},
+		{"v1.2.3", "v2.0.0", true},
+		{"0.3.1", "0.4.0", true},
+		{"0.3.1", "0.3.9", false},
+		{"v0.0.0-20240101000000-abcdef", "v0.1.0", true},
+		{"latest", "v2.0.0", false},
+	}
+	for _, tt := range tests {
+		if got := (Update{Current: tt.current, Latest: tt.latest}).Major(); got != tt.want {
+			t.Errorf("Major(%s → %s) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestGroup(t *testing.T) {
+	updates := []Update{
+		{Ecosystem: "go", Dir: ".", Name: "golang.org/x/net", Current: "v0.20.0", Latest: "v0.20.1"},
+		{Ecosystem: "go", Dir: ".", Name: "github.com/spf13/cobra", Current: "v1.8.0", Latest: "v1.9.1"},
+		{Ecosystem: "go", Dir: ".", Name: "github.com/google/go-github", Current: "v1.0.0", Latest: "v2.0.0"},
+		{Ecosystem: "npm", Dir: "web", Name: "react", Current: "18.2.0", Latest: "18.3.1"},
+	}
+
+	batches := Group(updates, GroupEcosystem)
+	var keys []string
+	for _, b := range batches {
+		keys = append(keys, b.Key)
+	}
+	if got := strings.Join(keys, " "); got != "go go:github.com/google/go-github@major npm:web" {
+		t.Fatalf("ecosystem keys = %q", got)
+	}
+	if got := batches[0].Title(); got != "Update 2 go dependencies" {
+		t.Errorf("Title() = %q", got)
+	}
+	if got := batches[1].Title(); got != "Update github.com/google/go-github to v2.0.0 (go, major)" {
+		t.Errorf("Title() = %q", got)
+	}
+	if desc := batches[1].Description(); !strings.Contains(desc, "v1.0.0 → v2.0.0") || !strings.Contains(desc, "breaking changes") {
+		t.Errorf("Description() = %q", desc)
+	}
+
+	if got := len(Group(updates, GroupDependency)); got != 4 {
+		t.Errorf("dependency grouping made %d batches, want 4", got)
+	}
+	rig := Group(updates, GroupRig)
+	if len(rig) != 1 || rig[0].Title() != "Update 4 dependencies" {
+		t.Errorf("rig grouping = %+v", rig)
+	}
+}
+
+func TestParseGoList(t *testing.T) {
+	out := `{"Path": "example.com/app", "Main": true}
+{"Path": "github.com/spf13/cobra", "Version": "v1.8.0", "Update": {"Version": "v1.9.1"}}
+{"Path": "golang.org/x/sys", "Version": "v0.1.0", "Indirect": true, "Update": {"Version": "v0.2.0"}}
+{"Path": "github.com/BurntSushi/toml", "Version": "v1.5.0"}
+`
+	updates, err := parseGoList([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 1 || updates[0].Name != "github.com/spf13/cobra" || updates[0].Latest != "v1.9.1" {
+		t.Errorf("updates = %+v", updates)
+	}
+}
+
+func TestParseNPMOutdated(t *testing.T) {
+	out := `{
+  "react": {"current": "18.2.0", "wanted": "18.2.0", "latest": "18.3.1"},
+  "left-pad": {"wanted": "1.3.0", "latest": "1.3.0"},
+  "eslint": {"current": "8.57.0", "wanted": "8.57.1", "latest": "9.9.0"}
+}`
+	updates, err := parseNPMOutdated([]byte(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 2 || updates[0].Name != "eslint" || updates[1].Name != "react" {
+		t.Errorf("updates = %+v", updates)
+	}
+	if updates, err := parseNPMOutdated(nil); err != nil || len(updates) != 0 {
+		t.Errorf("empty output: %+v, %v", updates, err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	orig := runToolFn
+	t.Cleanup(func() { runToolFn = orig })
+
+	checkout := t.TempDir()
+	if err := os.WriteFile(filepath.Join(checkout, "requirements.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	runToolFn = func(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+		if name != "sh" || args[1] != "./outdated.sh" {
+			t.Errorf("ran %s %v", name, args)
+		}
+		return []byte(`[{"name": "requests", "current": "2.31.0", "latest": "2.32.3"}, {"name": "boto3", "current": "1.0", "latest": "1.1"}]`), nil
+	}
+
+	// No go.mod or package.json: only the custom checker detects its manifest.
+	cfg := &Config{
+		Commands: []CommandChecker{{CheckerName: "pip", Manifest: "requirements.txt", Command: "./outdated.sh"}},
+		Ignore:   []string{"boto"},
+	}
+	updates, err := Check(context.Background(), checkout, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 1 || updates[0].Name != "requests" || updates[0].Ecosystem != "pip" || updates[0].Dir != "." {
+		t.Errorf("updates = %+v", updates)
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	rig := t.TempDir()
+	state, err := LoadState(rig)
+	if err != nil || len(state.Filed) != 0 {
+		t.Fatalf("empty state: %+v, %v", state, err)
+	}
+	now := time.Date(2026, 3, 1, 4, 0, 0, 0, time.UTC)
+	if !state.Due(now, DefaultEvery) {
+		t.Error("a rig never checked is due")
+	}
+	state.LastCheck = now
+	state.Filed["go"] = &Filed{Bead: "gt-abc", Title: "Update 2 go dependencies", FiledAt: now, Slung: true}
+	if err := state.Save(rig); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadState(rig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := loaded.Filed["go"]; f == nil || f.Bead != "gt-abc" || !f.Slung {
+		t.Errorf("loaded = %+v", loaded.Filed)
+	}
+	if loaded.Due(now.Add(time.Hour), DefaultEvery) || !loaded.Due(now.Add(DefaultEvery), DefaultEvery) {
+		t.Error("Due should follow the check interval")
+	}
+}
@@ -0,0 +1,297 @@
+// Package diskquota measures how much disk a rig uses (worktrees, agent
+// transcripts and build artifacts) and compares it against the rig's
+// disk_quota, so one runaway agent can't fill the disk.
+//
+// Measuring walks every file under the rig, so measurements are saved in
+// the rig's .runtime directory and reused until they are MaxAge old.
+package diskquota
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxAge is how long a saved measurement is reused.
+const MaxAge = 15 * time.Minute
+
+// Quota levels.
+const (
+	LevelOK   = "ok"
+	LevelWarn = "warn" // Over the warn threshold: new spawns still allowed
+	LevelOver = "over" // Over the limit: spawns blocked until gc frees space
+)
+
+// defaultWarnPercent is the warn threshold, as a percentage of the limit,
+// when warn is not set.
+const defaultWarnPercent = 80
+
+// Config is the disk_quota section of a rig's settings/config.json.
+//
+//	"disk_quota": {
+//	  "enabled": true,
+//	  "limit": "40GB",
+//	  "warn": "30GB"
+//	}
+//
+// Sizes take a B, KB, MB, GB or TB suffix (powers of 1024). Warn defaults
+// to 80% of the limit.
+type Config struct {
+	Enabled bool   `json:"enabled"`
+	Limit   string `json:"limit"`
+	Warn    string `json:"warn,omitempty"`
+}
+
+// IsEnabled reports whether the quota is configured and on.
+func (c *Config) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// Validate checks the sizes. A nil config is valid (no quota).
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	limit, err := ParseSize(c.Limit)
+	if err != nil {
+		return fmt.Errorf("limit: %w", err)
+	}
+	if c.Warn != "" {
+		warn, err := ParseSize(c.Warn)
+		if err != nil {
+			return fmt.Errorf("warn: %w", err)
+		}
+		if warn > limit {
+			return fmt.Errorf("warn (%s) is above limit (%s)", c.Warn, c.Limit)
+		}
+	}
+	return nil
+}
+
+// LimitBytes returns the limit in bytes, or 0 if it is unset or invalid.
+func (c *Config) LimitBytes() int64 {
+	if c == nil {
+		return 0
+	}
+	n, _ := ParseSize(c.Limit)
+	return n
+}
+
+// WarnBytes returns the warn threshold in bytes.
+func (c *Config) WarnBytes() int64 {
+	if c == nil {
+		return 0
+	}
+	if n, err := ParseSize(c.Warn); err == nil {
+		return n
+	}
+	return c.LimitBytes() * defaultWarnPercent / 100
+}
+
+// Level returns the quota level for a usage total.
+func (c *Config) Level(bytes int64) string {
+	if !c.IsEnabled() || c.LimitBytes() == 0 {
+		return LevelOK
+	}
+	switch {
+	case bytes >= c.LimitBytes():
+		return LevelOver
+	case bytes >= c.WarnBytes():
+		return LevelWarn
+	}
+	return LevelOK
+}
+
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1},
+}
+
+// ParseSize parses a size such as "40GB", "512MB" or "1.5TB".
+func ParseSize(s string) (int64, error) {
+	num := strings.ToUpper(strings.TrimSpace(s))
+	if num == "" {
+		return 0, fmt.Errorf("size is required")
+	}
+	mult := int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(num, u.suffix) {
+			num, mult = strings.TrimSpace(strings.TrimSuffix(num, u.suffix)), u.bytes
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q (e.g. 40GB, 512MB)", s)
+	}
+	return int64(n * float64(mult)), nil
+}
+
+// Dir is one measured directory.
+type Dir struct {
+	Path  string `json:"path"`
+	Bytes int64  `json:"bytes"`
+}
+
+// Usage is a measurement of one rig.
+type Usage struct {
+	Worktrees   int64     `json:"worktrees"`   // Polecat, crew and refinery checkouts
+	Transcripts int64     `json:"transcripts"` // Agent session transcripts
+	Artifacts   int64     `json:"artifacts"`   // Shared build caches and rig runtime files
+	Total       int64     `json:"total"`
+	Polecats    []Dir     `json:"polecats,omitempty"` // Largest first
+	MeasuredAt  time.Time `json:"measured_at"`
+}
+
+// worktreeDirs are the rig subdirectories holding agent checkouts.
+var worktreeDirs = []string{"polecats", "crew", "refinery", "witness", "mayor"}
+
+// Measure walks the rig at rigPath. artifactDirs are extra directories
+// counted as artifacts (shared build caches); those inside a worktree
+// directory are not counted twice.
+func Measure(rigPath string, artifactDirs []string) *Usage {
+	u := &Usage{MeasuredAt: time.Now()}
+	for _, sub := range worktreeDirs {
+		entries, err := os.ReadDir(filepath.Join(rigPath, sub))
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			path := filepath.Join(rigPath, sub, e.Name())
+			n := dirSize(path)
+			u.Worktrees += n
+			if sub == "polecats" && e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+				u.Polecats = append(u.Polecats, Dir{Path: path, Bytes: n})
+			}
+		}
+	}
+	sort.Slice(u.Polecats, func(i, j int) bool { return u.Polecats[i].Bytes > u.Polecats[j].Bytes })
+
+	for _, dir := range TranscriptDirs(rigPath) {
+		u.Transcripts += dirSize(dir)
+	}
+
+	seen := make(map[string]bool)
+	for _, dir := range append([]string{filepath.Join(rigPath, ".runtime")}, artifactDirs...) {
+		if dir == "" || seen[dir] || insideWorktree(rigPath, dir) {
+			continue
+		}
+		seen[dir] = true
+		u.Artifacts += dirSize(dir)
+	}
+
+	u.Total = u.Worktrees + u.Transcripts + u.Artifacts
+	return u
+}
+
+// TranscriptDirs returns the agent transcript directories for sessions run
+// in the rig. Claude Code keeps transcripts in ~/.claude/projects/ under the
+// working directory with "/" replaced by "-".
+func TranscriptDirs(rigPath string) []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	projects := filepath.Join(home, ".claude", "projects")
+	entries, err := os.ReadDir(projects)
+	if err != nil {
+		return nil
+	}
+	prefix := strings.ReplaceAll(filepath.Clean(rigPath), "/", "-") + "-"
+	var dirs []string
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(name, prefix)
+		for _, sub := range worktreeDirs {
+			if rest == sub || strings.HasPrefix(rest, sub+"-") {
+				dirs = append(dirs, filepath.Join(projects, name))
+				break
+			}
+		}
+	}
+	return dirs
+}
+
+func insideWorktree(rigPath, dir string) bool {
+	for _, sub := range worktreeDirs {
+		rel, err := filepath.Rel(filepath.Join(rigPath, sub), dir)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// dirSize sums the sizes of regular files under path, without following
+// symlinks. Missing paths count as empty.
+func dirSize(path string) int64 {
+	var total int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
+
+func usagePath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "disk-usage.json")
+}
+
+// Load returns the saved measurement if it is fresh, otherwise measures and
+// saves. refresh forces a new measurement.
+func Load(rigPath string, artifactDirs []string, refresh bool) *Usage {
+	if !refresh {
+		if data, err := os.ReadFile(usagePath(rigPath)); err == nil { //nolint:gosec // G304: path is constructed internally
+			var saved Usage
+			if json.Unmarshal(data, &saved) == nil && time.Since(saved.MeasuredAt) < MaxAge {
+				return &saved
+			}
+		}
+	}
+
+	u := Measure(rigPath, artifactDirs)
+	if data, err := json.MarshalIndent(u, "", "  "); err == nil {
+		if err := os.MkdirAll(filepath.Dir(usagePath(rigPath)), 0755); err == nil {
+			_ = os.WriteFile(usagePath(rigPath), data, 0644) //nolint:gosec // G306: runtime state
+		}
+	}
+	return u
+}
+
+func levelPath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "disk-quota-level")
+}
+
+// LastLevel returns the level recorded by the previous check, so callers
+// only alert when a rig crosses a threshold.
+func LastLevel(rigPath string) string {
+	data, err := os.ReadFile(levelPath(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return LevelOK
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// SetLastLevel records the level seen by a check.
+func SetLastLevel(rigPath, level string) error {
+	if err := os.MkdirAll(filepath.Dir(levelPath(rigPath)), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(levelPath(rigPath), []byte(level+"\n"), 0644) //nolint:gosec // G306: runtime state
+}
@@ -0,0 +1,154 @@
+package diskquota
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+		ok   bool
+	}{
+		{"40GB", 40 << 30, true},
+		{"512mb", 512 << 20, true},
+		{"1.5TB", 3 << 39, true},
+		{"10 KB", 10 << 10, true},
+		{"2048", 2048, true},
+		{"", 0, false},
+		{"lots", 0, false},
+		{"-1GB", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d, ok=%v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	var nilCfg *Config
+	if err := nilCfg.Validate(); err != nil {
+		t.Errorf("nil config: %v", err)
+	}
+	if err := (&Config{Enabled: true, Limit: "10GB", Warn: "8GB"}).Validate(); err != nil {
+		t.Errorf("valid config: %v", err)
+	}
+	for _, c := range []*Config{
+		{Enabled: true},
+		{Enabled: true, Limit: "big"},
+		{Enabled: true, Limit: "10GB", Warn: "20GB"},
+	} {
+		if err := c.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", c)
+		}
+	}
+}
+
+func TestConfigLevel(t *testing.T) {
+	c := &Config{Enabled: true, Limit: "100KB"}
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{0, LevelOK},
+		{79 << 10, LevelOK},
+		{80 << 10, LevelWarn}, // Default warn is 80% of the limit
+		{100 << 10, LevelOver},
+	}
+	for _, tt := range tests {
+		if got := c.Level(tt.bytes); got != tt.want {
+			t.Errorf("Level(%d) = %s, want %s", tt.bytes, got, tt.want)
+		}
+	}
+
+	if got := (&Config{Enabled: false, Limit: "1KB"}).Level(1 << 20); got != LevelOK {
+		t.Errorf("disabled quota level = %s, want ok", got)
+	}
+	if got := (&Config{Enabled: true, Limit: "100KB", Warn: "50KB"}).Level(60 << 10); got != LevelWarn {
+		t.Errorf("explicit warn level = %s, want warn", got)
+	}
+}
+
+func writeSized(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	rigPath := filepath.Join(t.TempDir(), "greenplace")
+
+	writeSized(t, filepath.Join(rigPath, "polecats", "toast", "greenplace", "node_modules", "big.js"), 3000)
+	writeSized(t, filepath.Join(rigPath, "polecats", "nux", "greenplace", "main.go"), 1000)
+	writeSized(t, filepath.Join(rigPath, "crew", "max", "README.md"), 500)
+	writeSized(t, filepath.Join(rigPath, ".cache", "go", "mod", "pkg.zip"), 700)
+	writeSized(t, filepath.Join(rigPath, ".runtime", "state.json"), 100)
+
+	projects := filepath.Join(home, ".claude", "projects")
+	encoded := strings.ReplaceAll(rigPath, "/", "-")
+	writeSized(t, filepath.Join(projects, encoded+"-polecats-toast-greenplace", "s.jsonl"), 400)
+	// A different rig whose name shares the prefix is not counted.
+	writeSized(t, filepath.Join(projects, encoded+"-extra-polecats-a", "s.jsonl"), 9999)
+
+	u := Measure(rigPath, []string{filepath.Join(rigPath, ".cache"), filepath.Join(rigPath, "polecats", "toast")})
+
+	if u.Worktrees != 4500 {
+		t.Errorf("Worktrees = %d, want 4500", u.Worktrees)
+	}
+	if u.Transcripts != 400 {
+		t.Errorf("Transcripts = %d, want 400", u.Transcripts)
+	}
+	if u.Artifacts != 800 {
+		t.Errorf("Artifacts = %d, want 800 (cache + runtime, worktree dirs not double counted)", u.Artifacts)
+	}
+	if u.Total != 5700 {
+		t.Errorf("Total = %d, want 5700", u.Total)
+	}
+	if len(u.Polecats) != 2 || filepath.Base(u.Polecats[0].Path) != "toast" {
+		t.Errorf("Polecats = %+v, want toast first", u.Polecats)
+	}
+}
+
+func TestLoadReusesFreshMeasurement(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	rigPath := t.TempDir()
+	writeSized(t, filepath.Join(rigPath, "polecats", "toast", "a"), 100)
+
+	first := Load(rigPath, nil, false)
+	writeSized(t, filepath.Join(rigPath, "polecats", "toast", "b"), 100)
+
+	if got := Load(rigPath, nil, false); got.Worktrees != first.Worktrees {
+		t.Errorf("cached Worktrees = %d, want %d", got.Worktrees, first.Worktrees)
+	}
+	if got := Load(rigPath, nil, true); got.Worktrees != 200 {
+		t.Errorf("refreshed Worktrees = %d, want 200", got.Worktrees)
+	}
+	if time.Since(first.MeasuredAt) > time.Minute {
+		t.Errorf("MeasuredAt = %v, want now", first.MeasuredAt)
+	}
+}
+
+func TestLastLevel(t *testing.T) {
+	rigPath := t.TempDir()
+	if got := LastLevel(rigPath); got != LevelOK {
+		t.Errorf("LastLevel() = %s, want ok when never checked", got)
+	}
+	if err := SetLastLevel(rigPath, LevelOver); err != nil {
+		t.Fatal(err)
+	}
+	if got := LastLevel(rigPath); got != LevelOver {
+		t.Errorf("LastLevel() = %s, want over", got)
+	}
+}
@@ -0,0 +1,186 @@
+package diskquota
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Storage categories, in report order.
+const (
+	CategoryWorktrees   = "worktrees"   // Polecat, crew and refinery checkouts
+	CategoryTranscripts = "transcripts" // Agent session transcripts
+	CategoryArtifacts   = "artifacts"   // Rig runtime files, logs and anything else in the rig
+	CategoryBeads       = "beads"       // Beads databases (.beads directories)
+	CategoryCaches      = "caches"      // Shared build caches
+)
+
+// Categories lists the storage categories in report order.
+var Categories = []string{CategoryWorktrees, CategoryTranscripts, CategoryArtifacts, CategoryBeads, CategoryCaches}
+
+// AgeBuckets are the upper bounds of the age histogram's buckets, by last
+// modification; files older than the last bound fall in a final bucket.
+var AgeBuckets = []time.Duration{24 * time.Hour, 7 * 24 * time.Hour, 30 * 24 * time.Hour}
+
+// AgeLabels name the histogram buckets, one more than AgeBuckets.
+var AgeLabels = []string{"<1d", "1-7d", "7-30d", ">30d"}
+
+// Category is the storage one category takes in a rig.
+type Category struct {
+	Name  string  `json:"name"`
+	Bytes int64   `json:"bytes"`
+	Files int     `json:"files"`
+	Ages  []int64 `json:"ages"` // Bytes per AgeLabels bucket
+}
+
+// Worktree is one agent checkout and when anything in it last changed.
+type Worktree struct {
+	Path         string    `json:"path"`
+	Kind         string    `json:"kind"` // polecats, crew, refinery, witness or mayor
+	Bytes        int64     `json:"bytes"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+// Storage is a rig's storage broken down by category and age.
+type Storage struct {
+	Categories []*Category `json:"categories"`
+	Worktrees  []Worktree  `json:"worktrees"` // Largest first
+	Total      int64       `json:"total"`
+	MeasuredAt time.Time   `json:"measured_at"`
+}
+
+// Category returns the named category.
+func (s *Storage) Category(name string) *Category {
+	for _, c := range s.Categories {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// MeasureStorage walks the rig at rigPath and its transcripts and sorts
+// every file into a category. cacheDirs are the rig's shared build caches,
+// inside the rig or not. Unlike Measure it is not saved: it is for a human
+// deciding what to prune, not for quota checks.
+func MeasureStorage(rigPath string, cacheDirs []string, now time.Time) *Storage {
+	s := &Storage{MeasuredAt: now}
+	byName := make(map[string]*Category)
+	for _, name := range Categories {
+		c := &Category{Name: name, Ages: make([]int64, len(AgeLabels))}
+		s.Categories = append(s.Categories, c)
+		byName[name] = c
+	}
+	add := func(category string, info fs.FileInfo) {
+		c := byName[category]
+		c.Bytes += info.Size()
+		c.Files++
+		c.Ages[ageBucket(now.Sub(info.ModTime()))] += info.Size()
+		s.Total += info.Size()
+	}
+
+	var caches []string
+	for _, dir := range cacheDirs {
+		if dir != "" {
+			caches = append(caches, filepath.Clean(dir))
+		}
+	}
+	worktrees := make(map[string]*Worktree)
+	_ = filepath.WalkDir(rigPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			return nil
+		}
+		category, worktree := classify(rigPath, path, caches)
+		add(category, info)
+		if worktree != "" {
+			w := worktrees[worktree]
+			if w == nil {
+				w = &Worktree{Path: worktree, Kind: filepath.Base(filepath.Dir(worktree))}
+				worktrees[worktree] = w
+			}
+			w.Bytes += info.Size()
+			if info.ModTime().After(w.LastModified) {
+				w.LastModified = info.ModTime()
+			}
+		}
+		return nil
+	})
+	for _, dir := range caches {
+		if !isWithin(rigPath, dir) {
+			walkFiles(dir, func(info fs.FileInfo) { add(CategoryCaches, info) })
+		}
+	}
+	for _, dir := range TranscriptDirs(rigPath) {
+		walkFiles(dir, func(info fs.FileInfo) { add(CategoryTranscripts, info) })
+	}
+
+	for _, w := range worktrees {
+		s.Worktrees = append(s.Worktrees, *w)
+	}
+	sort.Slice(s.Worktrees, func(i, j int) bool { return s.Worktrees[i].Bytes > s.Worktrees[j].Bytes })
+	return s
+}
+
+// classify returns a rig file's category and, for files in an agent
+// checkout, the checkout's directory. Beads databases and caches inside a
+// checkout count as beads and caches, not as the worktree.
+func classify(rigPath, path string, caches []string) (category, worktree string) {
+	for _, dir := range caches {
+		if isWithin(dir, path) {
+			return CategoryCaches, ""
+		}
+	}
+	rel, err := filepath.Rel(rigPath, path)
+	if err != nil {
+		return CategoryArtifacts, ""
+	}
+	parts := strings.Split(rel, string(filepath.Separator))
+	for _, p := range parts[:len(parts)-1] {
+		if p == ".beads" {
+			return CategoryBeads, ""
+		}
+	}
+	if len(parts) > 2 {
+		for _, sub := range worktreeDirs {
+			if parts[0] == sub {
+				return CategoryWorktrees, filepath.Join(rigPath, parts[0], parts[1])
+			}
+		}
+	}
+	return CategoryArtifacts, ""
+}
+
+// isWithin reports whether path is dir or inside it.
+func isWithin(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func ageBucket(age time.Duration) int {
+	for i, bound := range AgeBuckets {
+		if age < bound {
+			return i
+		}
+	}
+	return len(AgeBuckets)
+}
+
+// walkFiles calls fn for each regular file under dir, without following
+// symlinks.
+func walkFiles(dir string, fn func(fs.FileInfo)) {
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+			fn(info)
+		}
+		return nil
+	})
+}
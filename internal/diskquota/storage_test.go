@@ -0,0 +1,60 @@
+package diskquota
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMeasureStorage(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	rigPath := filepath.Join(t.TempDir(), "greenplace")
+	sharedCache := filepath.Join(t.TempDir(), "gocache")
+	now := time.Now()
+
+	writeSized(t, filepath.Join(rigPath, "polecats", "toast", "greenplace", "main.go"), 3000)
+	writeSized(t, filepath.Join(rigPath, "polecats", "toast", "greenplace", ".beads", "redirect"), 10)
+	writeSized(t, filepath.Join(rigPath, "polecats", "nux", "greenplace", "main.go"), 1000)
+	writeSized(t, filepath.Join(rigPath, "mayor", "rig", ".beads", "beads.db"), 600)
+	writeSized(t, filepath.Join(rigPath, ".cache", "go", "pkg.zip"), 700)
+	writeSized(t, filepath.Join(sharedCache, "a"), 50)
+	writeSized(t, filepath.Join(rigPath, ".runtime", "state.json"), 100)
+	writeSized(t, filepath.Join(home, ".claude", "projects", strings.ReplaceAll(rigPath, "/", "-")+"-crew-max", "s.jsonl"), 400)
+
+	// nux was last touched 10 days ago.
+	old := now.Add(-10 * 24 * time.Hour)
+	if err := os.Chtimes(filepath.Join(rigPath, "polecats", "nux", "greenplace", "main.go"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	s := MeasureStorage(rigPath, []string{filepath.Join(rigPath, ".cache"), sharedCache}, now)
+	want := map[string]int64{
+		CategoryWorktrees:   4000,
+		CategoryTranscripts: 400,
+		CategoryArtifacts:   100,
+		CategoryBeads:       610,
+		CategoryCaches:      750,
+	}
+	for name, bytes := range want {
+		if got := s.Category(name).Bytes; got != bytes {
+			t.Errorf("%s = %d, want %d", name, got, bytes)
+		}
+	}
+	if s.Total != 5860 {
+		t.Errorf("Total = %d, want 5860", s.Total)
+	}
+	if ages := s.Category(CategoryWorktrees).Ages; ages[0] != 3000 || ages[2] != 1000 {
+		t.Errorf("worktree ages = %v, want 3000 under a day and 1000 at 7-30d", ages)
+	}
+
+	// mayor/rig holds only its beads database, so it is not listed.
+	if len(s.Worktrees) != 2 || filepath.Base(s.Worktrees[0].Path) != "toast" || s.Worktrees[0].Kind != "polecats" {
+		t.Fatalf("Worktrees = %+v, want toast then nux", s.Worktrees)
+	}
+	if nux := s.Worktrees[1]; now.Sub(nux.LastModified) < 9*24*time.Hour {
+		t.Errorf("nux LastModified = %v, want 10 days ago", nux.LastModified)
+	}
+}
@@ -446,7 +446,7 @@ func verifyLabelAdded(workDir, beadID, label string) bool {
 	query := fmt.Sprintf("SELECT 1 FROM labels WHERE issue_id = '%s' AND label = '%s' LIMIT 1", escapedID, escapedLabel)
 	cmd := exec.Command("bd", "sql", query) //nolint:gosec // G204: query uses escaped internal values
 	cmd.Dir = workDir
-	output, err := cmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(cmd)
 	if err != nil {
 		return false
 	}
@@ -295,7 +295,7 @@ type realDBPrefixGetter struct{}
 func (r *realDBPrefixGetter) GetDBPrefix(rigPath string) (string, error) {
 	cmd := exec.Command("bd", "config", "get", "issue_prefix")
 	cmd.Dir = rigPath
-	output, err := cmd.Output()
+	output, err := beads.CmdOutput(cmd)
 	if err != nil {
 		return "", err
 	}
@@ -467,7 +467,7 @@ func (c *DatabasePrefixCheck) Fix(ctx *CheckContext) error {
 
 		cmd := exec.Command("bd", "config", "set", "issue_prefix", m.routesPrefix)
 		cmd.Dir = filepath.Join(ctx.TownRoot, m.rigPath)
-		if output, err := cmd.CombinedOutput(); err != nil {
+		if output, err := beads.CmdCombinedOutput(cmd); err != nil {
 			return fmt.Errorf("updating %s: %s", m.rigPath, strings.TrimSpace(string(output)))
 		}
 	}
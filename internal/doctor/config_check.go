@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/constants"
 )
 
@@ -647,7 +648,7 @@ func (c *CustomTypesCheck) Run(ctx *CheckContext) *CheckResult {
 	// Use Output() not CombinedOutput() to avoid capturing bd's stderr messages
 	cmd := exec.Command("bd", "config", "get", "types.custom")
 	cmd.Dir = ctx.TownRoot
-	output, err := cmd.Output()
+	output, err := beads.CmdOutput(cmd)
 	if err != nil {
 		// If config key doesn't exist, types are not configured
 		c.townRoot = ctx.TownRoot
@@ -720,7 +721,7 @@ func parseConfigOutput(output []byte) string {
 func (c *CustomTypesCheck) Fix(ctx *CheckContext) error {
 	cmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
 	cmd.Dir = c.townRoot
-	output, err := cmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("bd config set types.custom: %s", strings.TrimSpace(string(output)))
 	}
@@ -769,7 +770,7 @@ func (c *CustomStatusesCheck) Run(ctx *CheckContext) *CheckResult {
 	// Get current custom statuses configuration
 	cmd := exec.Command("bd", "config", "get", "status.custom")
 	cmd.Dir = ctx.TownRoot
-	output, err := cmd.Output()
+	output, err := beads.CmdOutput(cmd)
 	if err != nil {
 		c.townRoot = ctx.TownRoot
 		c.missingStatuses = constants.BeadsCustomStatusesList()
@@ -827,7 +828,7 @@ func (c *CustomStatusesCheck) Fix(ctx *CheckContext) error {
 	// Read existing statuses
 	getCmd := exec.Command("bd", "config", "get", "status.custom")
 	getCmd.Dir = c.townRoot
-	existingOutput, _ := getCmd.Output()
+	existingOutput, _ := beads.CmdOutput(getCmd)
 
 	// Build merged set
 	statusSet := make(map[string]bool)
@@ -851,7 +852,7 @@ func (c *CustomStatusesCheck) Fix(ctx *CheckContext) error {
 
 	cmd := exec.Command("bd", "config", "set", "status.custom", strings.Join(merged, ","))
 	cmd.Dir = c.townRoot
-	output, err := cmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("bd config set status.custom: %s", strings.TrimSpace(string(output)))
 	}
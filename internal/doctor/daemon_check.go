@@ -1,6 +1,7 @@
 package doctor
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"time"
@@ -26,9 +27,9 @@ func NewDaemonCheck() *DaemonCheck {
 	}
 }
 
-// Run checks if the daemon is running.
+// Run checks if the daemon is running and heartbeating.
 func (c *DaemonCheck) Run(ctx *CheckContext) *CheckResult {
-	running, pid, err := daemon.IsRunning(ctx.TownRoot)
+	health, state, err := daemon.ReadHealth(ctx.TownRoot, time.Now())
 	if err != nil {
 		return &CheckResult{
 			Name:    c.Name(),
@@ -38,24 +39,42 @@ func (c *DaemonCheck) Run(ctx *CheckContext) *CheckResult {
 		}
 	}
 
-	if running {
+	switch health.Status {
+	case daemon.HealthOK, daemon.HealthStale:
 		// Get more info about daemon state
-		state, err := daemon.LoadState(ctx.TownRoot)
 		details := []string{}
-		if err == nil && !state.StartedAt.IsZero() {
+		if !state.StartedAt.IsZero() {
 			uptime := time.Since(state.StartedAt).Round(time.Second)
 			details = append(details, "Uptime: "+uptime.String())
 			if state.HeartbeatCount > 0 {
-				details = append(details, "Heartbeats: "+string(rune(state.HeartbeatCount)))
+				details = append(details, "Heartbeats: "+itoa(int(state.HeartbeatCount)))
+			}
+		}
+		if health.Status == daemon.HealthStale {
+			return &CheckResult{
+				Name:    c.Name(),
+				Status:  StatusWarning,
+				Message: "Daemon is running (PID " + itoa(health.PID) + ") but its heartbeat is stale: " + health.Reason,
+				Details: details,
+				FixHint: "Restart it with 'gt daemon stop && gt daemon start'",
 			}
 		}
 
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusOK,
-			Message: "Daemon is running (PID " + itoa(pid) + ")",
+			Message: "Daemon is running (PID " + itoa(health.PID) + ")",
 			Details: details,
 		}
+
+	case daemon.HealthDead:
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Daemon is dead: " + health.Reason,
+			Details: []string{"Unclean exits so far: " + itoa(state.UncleanExits)},
+			FixHint: "Run 'gt doctor --fix', and 'gt town install-service' to have it restarted automatically",
+		}
 	}
 
 	return &CheckResult{
@@ -72,6 +91,11 @@ func (c *DaemonCheck) Fix(ctx *CheckContext) error {
 		return ErrSkippedNoStart
 	}
 
+	// A stale daemon still holds the lock; a second one would refuse to start.
+	if running, _, err := daemon.IsRunning(ctx.TownRoot); err == nil && running {
+		return fmt.Errorf("daemon is running but not heartbeating; restart it with 'gt daemon stop && gt daemon start'")
+	}
+
 	// Find gt executable
 	gtPath, err := os.Executable()
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/doltserver"
 )
 
@@ -147,7 +148,7 @@ func (c *NullAssigneeCheck) Fix(ctx *CheckContext) error {
 func queryNullAssigneeBeads(rigDir string) ([]nullAssigneeRow, error) {
 	cmd := exec.Command("bd", "sql", "--csv", nullAssigneeSelectQuery) //nolint:gosec // G204: args are constants
 	cmd.Dir = rigDir
-	output, err := cmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(cmd)
 	if err != nil {
 		return nil, fmt.Errorf("bd sql: %w", err)
 	}
@@ -176,7 +177,7 @@ func queryNullAssigneeBeads(rigDir string) ([]nullAssigneeRow, error) {
 func execBdSQLWrite(rigDir, query string) error {
 	cmd := exec.Command("bd", "sql", query) //nolint:gosec // G204: query is a constant
 	cmd.Dir = rigDir
-	output, err := cmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("%s: %w", strings.TrimSpace(string(output)), err)
 	}
@@ -840,7 +840,7 @@ func (c *BeadsConfigValidCheck) Run(ctx *CheckContext) *CheckResult {
 	// Check if bd command works
 	cmd := exec.Command("bd", "stats", "--json")
 	cmd.Dir = c.rigPath
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		return &CheckResult{
 			Name:    c.Name(),
 			Status:  StatusError,
@@ -1030,7 +1030,7 @@ func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 		initArgs = append(initArgs, "--server")
 		cmd := exec.Command("bd", initArgs...)
 		cmd.Dir = rigPath
-		if output, err := cmd.CombinedOutput(); err != nil {
+		if output, err := beads.CmdCombinedOutput(cmd); err != nil {
 			// bd might not be installed — create config.yaml via shared helper.
 			if writeErr := beads.EnsureConfigYAML(rigBeadsDir, prefix); writeErr != nil {
 				return fmt.Errorf("bd init failed (%v) and fallback config creation failed: %w", err, writeErr)
@@ -1041,7 +1041,7 @@ func (c *BeadsRedirectCheck) Fix(ctx *CheckContext) error {
 			// Configure custom types for Gas Town (beads v0.46.0+)
 			configCmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
 			configCmd.Dir = rigPath
-			_, _ = configCmd.CombinedOutput() // Ignore errors - older beads don't need this
+			_, _ = beads.CmdCombinedOutput(configCmd) // Ignore errors - older beads don't need this
 		}
 		return nil
 	}
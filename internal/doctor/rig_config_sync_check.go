@@ -337,7 +337,7 @@ func (c *RigConfigSyncCheck) Fix(ctx *CheckContext) error {
 		// Run bd init --prefix <prefix> --force to create the database
 		cmd := exec.Command("bd", "init", "--prefix", entry.BeadsConfig.Prefix, "--force")
 		cmd.Dir = mayorRigPath
-		if output, err := cmd.CombinedOutput(); err != nil {
+		if output, err := beads.CmdCombinedOutput(cmd); err != nil {
 			return fmt.Errorf("could not initialize Dolt DB for %s: %w\n%s", rigName, err, string(output))
 		}
 	}
@@ -426,7 +426,7 @@ func (c *RigConfigSyncCheck) Fix(ctx *CheckContext) error {
 		rigBeadID := fmt.Sprintf("%s-rig-%s", info.prefix, info.rigName)
 		cmd := exec.Command("bd", "label", rigBeadID, "--add", "status:docked")
 		cmd.Dir = mayorRigPath
-		_ = cmd.Run() // Best effort - ignore errors
+		_ = beads.RunCmd(cmd) // Best effort - ignore errors
 	}
 
 	return nil
@@ -455,7 +455,7 @@ func (c *RigConfigSyncCheck) rigBeadExists(rigBeadID, rigPath string) bool {
 	// Try to show the bead using bd
 	cmd := exec.Command("bd", "show", rigBeadID, "--json")
 	cmd.Dir = mayorRigPath
-	output, err := cmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(cmd)
 	if err != nil {
 		return false
 	}
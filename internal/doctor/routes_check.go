@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/undo"
 )
 
 // determineRigBeadsPath returns the correct route path for a rig based on its actual layout.
@@ -314,6 +317,11 @@ func (c *RoutesCheck) Fix(ctx *CheckContext) error {
 	if err != nil {
 		routes = []beads.Route{} // Start fresh if can't load
 	}
+	// Keep the original for gt undo (only when it could be read)
+	var before []beads.Route
+	if err == nil {
+		before = slices.Clone(routes)
+	}
 
 	// Build map of existing prefixes to route index for fast lookup.
 	// NOTE: routeMap indices are only valid as long as routes is append-only
@@ -346,7 +354,7 @@ func (c *RoutesCheck) Fix(ctx *CheckContext) error {
 	if err != nil {
 		// No rigs config - just write town root route if we added it
 		if modified {
-			return beads.WriteRoutes(beadsDir, routes)
+			return writeFixedRoutes(ctx.TownRoot, beadsDir, before, routes)
 		}
 		return nil
 	}
@@ -413,8 +421,22 @@ func (c *RoutesCheck) Fix(ctx *CheckContext) error {
 	}
 
 	if modified {
-		return beads.WriteRoutes(beadsDir, routes)
+		return writeFixedRoutes(ctx.TownRoot, beadsDir, before, routes)
 	}
 
 	return nil
 }
+
+// writeFixedRoutes writes the fixed routes and records the rewrite so
+// gt undo can put the old routes back. A nil before means the old routes
+// couldn't be read, so there is nothing to restore.
+func writeFixedRoutes(townRoot, beadsDir string, before, after []beads.Route) error {
+	if err := beads.WriteRoutes(beadsDir, after); err != nil {
+		return err
+	}
+	if before != nil {
+		state := undo.RoutesState{BeadsDir: beadsDir, Before: before, After: after}
+		_, _ = undo.Record(townRoot, undo.KindRoutes, "doctor routes fix", "doctor", state, time.Now())
+	}
+	return nil
+}
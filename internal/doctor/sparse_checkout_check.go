@@ -160,8 +160,9 @@ func (c *SparseCheckoutCheck) checkRig(rigPath string) {
 			continue
 		}
 
-		// Check if sparse checkout is configured (legacy configuration to remove)
-		if git.IsSparseCheckoutConfigured(repoPath) {
+		// Check if sparse checkout is configured (legacy configuration to remove).
+		// Cone mode is a deliberate sparse profile, not the legacy exclusion.
+		if git.IsSparseCheckoutConfigured(repoPath) && !git.IsSparseCheckoutCone(repoPath) {
 			c.affectedRepos = append(c.affectedRepos, repoPath)
 		}
 	}
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
 )
 
 func TestNewSparseCheckoutCheck(t *testing.T) {
@@ -482,3 +484,25 @@ func TestSparseCheckoutCheck_FixRestoresFiles(t *testing.T) {
 		t.Error("expected .claude/settings.json to be restored after fix")
 	}
 }
+
+func TestSparseCheckoutCheck_ConeModeProfileIgnored(t *testing.T) {
+	tmpDir := t.TempDir()
+	rigName := "testrig"
+	rigDir := filepath.Join(tmpDir, rigName)
+
+	// A polecat worktree with a deliberate cone-mode sparse profile
+	worktree := filepath.Join(rigDir, "polecats", "toast", rigName)
+	initGitRepo(t, worktree)
+	if err := os.MkdirAll(filepath.Join(worktree, "services", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := git.InitSparseCheckout(worktree, []string{"services/api"}); err != nil {
+		t.Fatalf("InitSparseCheckout: %v", err)
+	}
+
+	check := NewSparseCheckoutCheck()
+	result := check.Run(&CheckContext{TownRoot: tmpDir, RigName: rigName})
+	if result.Status != StatusOK {
+		t.Errorf("expected StatusOK for cone-mode sparse profile, got %v: %s", result.Status, result.Message)
+	}
+}
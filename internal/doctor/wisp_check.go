@@ -6,6 +6,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 // WispGCCheck detects and cleans orphaned wisps that are older than a threshold.
@@ -91,7 +93,7 @@ func (c *WispGCCheck) countAbandonedWisps(rigPath string) int {
 	cmd := exec.Command("bd", "mol", "wisp", "list", "--json")
 	cmd.Dir = rigPath
 
-	output, err := cmd.Output()
+	output, err := beads.CmdOutput(cmd)
 	if err != nil {
 		// Dolt is the only supported backend — no wisps table means 0 abandoned wisps.
 		return 0
@@ -135,7 +137,7 @@ func (c *WispGCCheck) Fix(ctx *CheckContext) error {
 		// Run bd mol wisp gc
 		cmd := exec.Command("bd", "mol", "wisp", "gc")
 		cmd.Dir = rigPath
-		if output, err := cmd.CombinedOutput(); err != nil {
+		if output, err := beads.CmdCombinedOutput(cmd); err != nil {
 			lastErr = fmt.Errorf("%s: %v (%s)", rigName, err, string(output))
 		}
 	}
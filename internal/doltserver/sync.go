@@ -436,7 +436,7 @@ func PurgeClosedEphemerals(townRoot, dbName string, dryRun bool) (int, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err := beads.RunCmdContext(ctx, cmd)
 	if ctx.Err() == context.DeadlineExceeded {
 		return 0, fmt.Errorf("bd purge for %s: timed out after 60s", dbName)
 	}
@@ -25,6 +25,8 @@ import (
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+
+	"github.com/steveyegge/gastown/internal/beads"
 )
 
 // MigrateWispsResult holds migration statistics.
@@ -119,7 +121,7 @@ func MigrateAgentBeadsToWisps(townRoot, workDir string, dryRun bool) (*MigrateWi
 func bdSQL(workDir, query string) error {
 	cmd := exec.Command("bd", "sql", query)
 	cmd.Dir = workDir
-	output, err := cmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("bd sql: %s: %w", strings.TrimSpace(string(output)), err)
 	}
@@ -130,7 +132,7 @@ func bdSQL(workDir, query string) error {
 func bdSQLCSV(workDir, query string) (string, error) {
 	cmd := exec.Command("bd", "sql", "--csv", query)
 	cmd.Dir = workDir
-	output, err := cmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(cmd)
 	if err != nil {
 		return "", fmt.Errorf("bd sql: %s: %w", strings.TrimSpace(string(output)), err)
 	}
@@ -141,7 +143,7 @@ func bdSQLCSV(workDir, query string) (string, error) {
 func bdExec(workDir string, args ...string) error {
 	cmd := exec.Command("bd", args...)
 	cmd.Dir = workDir
-	output, err := cmd.CombinedOutput()
+	output, err := beads.CmdCombinedOutput(cmd)
 	if err != nil {
 		return fmt.Errorf("bd %s: %s: %w", strings.Join(args, " "), strings.TrimSpace(string(output)), err)
 	}
@@ -0,0 +1,236 @@
+// Package errcode gives gt's errors stable codes and remediation hints, so
+// a person gets told what to do next and a script can tell "bead not
+// found" from "routing broken" from "bd missing" without parsing messages.
+//
+// An error gets a code one of two ways: it is (or wraps) an *Error built
+// with New, or it matches a sentinel registered with Register. Codes are
+// part of gt's interface: once published, a code keeps its meaning.
+package errcode
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/i18n"
+)
+
+// Code identifies a kind of failure. Codes are lowercase and hyphenated.
+type Code string
+
+// Codes.
+const (
+	NotInTown        Code = "not-in-town"
+	BDMissing        Code = "bd-missing"
+	BeadNotFound     Code = "bead-not-found"
+	RouteMissing     Code = "route-missing"
+	RigNotFound      Code = "rig-not-found"
+	UnknownRecipient Code = "unknown-recipient"
+	VersionPinned    Code = "version-pinned"
+	FeatureDisabled  Code = "feature-disabled"
+	UnknownFeature   Code = "unknown-feature"
+	AccessDenied     Code = "access-denied"
+	TownLocked       Code = "town-locked"
+)
+
+// Entry documents a code.
+type Entry struct {
+	Code    Code   `json:"code"`
+	Summary string `json:"summary"`
+	Explain string `json:"explain"`
+	Hint    string `json:"hint"` // What to try first
+}
+
+// Catalog documents every code, in the order gt explain lists them.
+var Catalog = []Entry{
+	{
+		Code:    NotInTown,
+		Summary: "Not in a Gas Town workspace",
+		Explain: "The command needs a town, and none was found above the current directory.\n" +
+			"gt finds the town by walking up to a directory holding mayor/town.json,\n" +
+			"falling back to $GT_TOWN_ROOT.",
+		Hint: "cd into your town (or one of its rigs), or set GT_TOWN_ROOT; gt whereami shows what gt sees",
+	},
+	{
+		Code:    BDMissing,
+		Summary: "The bd (beads) CLI isn't installed",
+		Explain: "gt stores all work in beads and shells out to bd for every read and write.\n" +
+			"bd wasn't found on PATH.",
+		Hint: "install beads and make sure bd is on PATH, then run gt doctor",
+	},
+	{
+		Code:    BeadNotFound,
+		Summary: "No bead with that ID",
+		Explain: "The bead's prefix routes to a beads database, but that database has no bead\n" +
+			"with the ID. It may be mistyped, or it may have been deleted or compacted.",
+		Hint: "check the ID with bd list or bd search from the town root",
+	},
+	{
+		Code:    RouteMissing,
+		Summary: "The bead's prefix doesn't route to any rig",
+		Explain: "Bead IDs start with a prefix (gt-, hq-) that .beads/routes.jsonl maps to the\n" +
+			"rig holding the bead. This prefix has no route, so gt looked in the wrong\n" +
+			"database. Either the ID is mistyped or routing is broken, typically after a\n" +
+			"rig was added or renamed by hand.",
+		Hint: "run gt doctor --fix to repair routes.jsonl; gt rig list shows each rig's prefix",
+	},
+	{
+		Code:    RigNotFound,
+		Summary: "No rig with that name",
+		Explain: "The rig isn't registered in mayor/rigs.json, or its directory is missing.",
+		Hint:    "gt rig list shows the registered rigs; gt rig add registers a new one",
+	},
+	{
+		Code:    UnknownRecipient,
+		Summary: "Mail address doesn't name an agent, list, queue or channel",
+		Explain: "Mail addresses look like mayor/, gastown/witness, gastown/polecats/toast,\n" +
+			"list:<name>, queue:<name> or announce:<name>.",
+		Hint: "check the address; gt mail directory lists the addresses that resolve",
+	},
+	{
+		Code:    VersionPinned,
+		Summary: "This gt version isn't allowed by the town",
+		Explain: "The town pins the gt versions it accepts (gt config version-pin), and this\n" +
+			"binary is outside the pin.",
+		Hint: "gt upgrade --self --to <allowed version>, or GT_IGNORE_VERSION_PIN=1 for one command",
+	},
+	{
+		Code:    FeatureDisabled,
+		Summary: "The command is part of an experimental feature that's off",
+		Explain: "Experimental commands and flags are off until the town enables their\n" +
+			"feature.",
+		Hint: "gt config feature <name> on enables it for the town",
+	},
+	{
+		Code:    UnknownFeature,
+		Summary: "No experimental feature with that name",
+		Explain: "The feature name isn't one this gt version knows.",
+		Hint:    "gt config feature lists the features and whether each is on",
+	},
+	{
+		Code:    AccessDenied,
+		Summary: "The town's access policy doesn't allow this",
+		Explain: "The town enforces role-based access (the access section of\n" +
+			"settings/config.json), and your principal lacks the level the command needs.",
+		Hint: "ask an operator with admin access, or run gt whoami to check who gt thinks you are",
+	},
+	{
+		Code:    TownLocked,
+		Summary: "Another operator holds the town lock",
+		Explain: "An operator locked the town for a maintenance window (gt town lock), and\n" +
+			"the town's town_lock policy refuses other operators' mutating commands\n" +
+			"until the lock is released or expires.",
+		Hint: "gt town lock status shows who holds it and until when; GT_IGNORE_TOWN_LOCK=1 runs one command anyway",
+	},
+}
+
+// Catalog text is translatable under errcode.<code>.summary, .explain and
+// .hint.
+func init() {
+	for _, e := range Catalog {
+		i18n.New(textKey(e.Code, "summary"), e.Summary)
+		i18n.New(textKey(e.Code, "explain"), e.Explain)
+		i18n.New(textKey(e.Code, "hint"), e.Hint)
+	}
+}
+
+func textKey(code Code, field string) string {
+	return "errcode." + string(code) + "." + field
+}
+
+// Lookup returns the catalog entry for code, in the active locale.
+func Lookup(code Code) (Entry, bool) {
+	for _, e := range Catalog {
+		if e.Code == code {
+			e.Summary = i18n.Text(textKey(code, "summary"), e.Summary)
+			e.Explain = i18n.Text(textKey(code, "explain"), e.Explain)
+			e.Hint = i18n.Text(textKey(code, "hint"), e.Hint)
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Codes returns every catalogued code, sorted.
+func Codes() []Code {
+	codes := make([]Code, 0, len(Catalog))
+	for _, e := range Catalog {
+		codes = append(codes, e.Code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+	return codes
+}
+
+// Error is an error with a code.
+type Error struct {
+	Code Code
+	Err  error
+	Hint string // Overrides the catalog hint when the caller knows better
+}
+
+// New attaches code to err.
+func New(code Code, err error) *Error {
+	return &Error{Code: code, Err: err}
+}
+
+// WithHint sets a hint specific to this failure.
+func (e *Error) WithHint(hint string) *Error {
+	e.Hint = hint
+	return e
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+func (e *Error) Unwrap() error { return e.Err }
+
+type matcher struct {
+	code  Code
+	match func(error) bool
+}
+
+var matchers []matcher
+
+// Register gives errors that satisfy match the code, when they don't carry
+// one themselves. Matchers are tried in registration order.
+func Register(code Code, match func(error) bool) {
+	matchers = append(matchers, matcher{code, match})
+}
+
+// Is returns a matcher for errors that wrap target.
+func Is(target error) func(error) bool {
+	return func(err error) bool { return errors.Is(err, target) }
+}
+
+// Of returns err's code, or "" if it has none.
+func Of(err error) Code {
+	if err == nil {
+		return ""
+	}
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	for _, m := range matchers {
+		if m.match(err) {
+			return m.code
+		}
+	}
+	return ""
+}
+
+// Report is an error as gt reports it in --json output.
+type Report struct {
+	Code    Code   `json:"code,omitempty"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// Describe returns err's report.
+func Describe(err error) Report {
+	r := Report{Code: Of(err), Message: err.Error()}
+	var coded *Error
+	if errors.As(err, &coded) && coded.Hint != "" {
+		r.Hint = coded.Hint
+	} else if e, ok := Lookup(r.Code); ok {
+		r.Hint = e.Hint
+	}
+	return r
+}
@@ -0,0 +1,62 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	valid := regexp.MustCompile(`^[a-z]+(-[a-z]+)*$`)
+	seen := map[Code]bool{}
+	for _, e := range Catalog {
+		if !valid.MatchString(string(e.Code)) || seen[e.Code] {
+			t.Errorf("code %q is malformed or repeated", e.Code)
+		}
+		seen[e.Code] = true
+		if e.Summary == "" || e.Explain == "" || e.Hint == "" {
+			t.Errorf("%s: summary, explanation and hint are all required", e.Code)
+		}
+	}
+	if len(Codes()) != len(Catalog) {
+		t.Errorf("Codes() = %d, catalog has %d", len(Codes()), len(Catalog))
+	}
+}
+
+func TestOf(t *testing.T) {
+	errGone := errors.New("gone")
+	prev := matchers
+	t.Cleanup(func() { matchers = prev })
+	Register(BeadNotFound, Is(errGone))
+
+	tests := []struct {
+		err  error
+		want Code
+	}{
+		{nil, ""},
+		{errors.New("plain"), ""},
+		{fmt.Errorf("show: %w", errGone), BeadNotFound},
+		{New(RouteMissing, errGone), RouteMissing}, // An explicit code beats a matcher
+		{fmt.Errorf("sling: %w", New(RigNotFound, errors.New("no rig"))), RigNotFound},
+	}
+	for _, tt := range tests {
+		if got := Of(tt.err); got != tt.want {
+			t.Errorf("Of(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	r := Describe(New(RigNotFound, errors.New("rig 'x' not found")))
+	if r.Code != RigNotFound || r.Message != "rig 'x' not found" || r.Hint == "" {
+		t.Errorf("Describe = %+v", r)
+	}
+	r = Describe(New(FeatureDisabled, errors.New("off")).WithHint("gt config feature chaos on"))
+	if r.Hint != "gt config feature chaos on" {
+		t.Errorf("hint override lost: %+v", r)
+	}
+	if r := Describe(errors.New("plain")); r.Code != "" || r.Hint != "" {
+		t.Errorf("uncoded Describe = %+v", r)
+	}
+}
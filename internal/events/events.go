@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/shift"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -23,6 +24,7 @@ type Event struct {
 	Actor      string                 `json:"actor"`
 	Payload    map[string]interface{} `json:"payload,omitempty"`
 	Visibility string                 `json:"visibility"`
+	Operator   string                 `json:"operator,omitempty"` // Human on shift, for events issued outside agent sessions
 }
 
 // Visibility levels for events.
@@ -46,6 +48,10 @@ const (
 	TypeBoot    = "boot"
 	TypeHalt    = "halt"
 
+	// Operator shift events (human handoffs)
+	TypeShiftStart = "shift_start"
+	TypeShiftEnd   = "shift_end"
+
 	// Session events (for seance discovery)
 	TypeSessionStart = "session_start"
 	TypeSessionEnd   = "session_end"
@@ -55,9 +61,10 @@ const (
 	TypeMassDeath    = "mass_death"    // Multiple sessions died in short window
 
 	// Witness patrol events
-	TypePatrolStarted   = "patrol_started"
-	TypePolecatChecked  = "polecat_checked"
-	TypePolecatNudged   = "polecat_nudged"
+	TypePatrolStarted    = "patrol_started"
+	TypePolecatChecked   = "polecat_checked"
+	TypePolecatNudged    = "polecat_nudged"
+	TypeStallClassified  = "stall_classified"
 	TypeEscalationSent   = "escalation_sent"
 	TypeEscalationAcked  = "escalation_acked"
 	TypeEscalationClosed = "escalation_closed"
@@ -74,6 +81,13 @@ const (
 	TypeSchedulerDispatch       = "scheduler_dispatch"        // Bead dispatched from scheduler
 	TypeSchedulerDispatchFailed = "scheduler_dispatch_failed" // Bead dispatch failed (requeued)
 	TypeSchedulerCloseRetry     = "scheduler_close_retry"     // Context close needed last-resort attempt
+	TypeQuietHours              = "quiet_hours"               // Rig or town entered/left quiet hours
+	TypeFreeze                  = "freeze"                    // Rig change freeze set or lifted
+	TypeScopeViolation          = "scope_violation"           // Polecat wrote (or tried to) outside its worktree
+	TypeResourceAlert           = "resource_alert"            // Agent session OOM-killed or near its memory limit
+	TypeChaos                   = "chaos"                     // Fault injected by gt town chaos
+	TypeTownLock                = "town_lock"                 // Town locked or unlocked for maintenance
+	TypeBeadLink                = "bead_link"                 // Typed link between beads set or removed
 )
 
 // EventsFile is the name of the raw events log.
@@ -115,6 +129,12 @@ func write(event Event) error {
 		return nil
 	}
 
+	// Attribute human-issued events (no agent role) to the operator on shift
+	// so shared towns can tell who approved or nudged what.
+	if event.Operator == "" && os.Getenv("GT_ROLE") == "" {
+		event.Operator = shift.CurrentOperator(townRoot)
+	}
+
 	eventsPath := filepath.Join(townRoot, EventsFile)
 
 	// Marshal event to JSON
@@ -259,6 +279,16 @@ func NudgePayload(rig, target, reason string) map[string]interface{} {
 	}
 }
 
+// StallPayload creates a payload for stall classification events.
+func StallPayload(rig, polecat, cause, action string) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":     rig,
+		"polecat": polecat,
+		"cause":   cause,
+		"action":  action,
+	}
+}
+
 // EscalationPayload creates a payload for escalation events.
 func EscalationPayload(rig, target, to, reason string) map[string]interface{} {
 	return map[string]interface{}{
@@ -360,6 +390,37 @@ func SchedulerDispatchPayload(beadID, rig, polecat string) map[string]interface{
 	}
 }
 
+// QuietHoursPayload creates a payload for quiet-hours transitions. until is
+// the window end when entering and zero when leaving.
+func QuietHoursPayload(scope string, quiet bool, until time.Time) map[string]interface{} {
+	p := map[string]interface{}{
+		"scope": scope,
+		"quiet": quiet,
+	}
+	if !until.IsZero() {
+		p["until"] = until.UTC().Format(time.RFC3339)
+	}
+	return p
+}
+
+// FreezePayload creates a payload for change freeze events.
+func FreezePayload(rig string, frozen bool, reason string, from, until time.Time) map[string]interface{} {
+	p := map[string]interface{}{
+		"rig":    rig,
+		"frozen": frozen,
+	}
+	if reason != "" {
+		p["reason"] = reason
+	}
+	if !from.IsZero() {
+		p["from"] = from.UTC().Format(time.RFC3339)
+	}
+	if !until.IsZero() {
+		p["until"] = until.UTC().Format(time.RFC3339)
+	}
+	return p
+}
+
 // SchedulerDispatchFailedPayload creates a payload for scheduler dispatch failure events.
 func SchedulerDispatchFailedPayload(beadID, rig, errMsg string) map[string]interface{} {
 	return map[string]interface{}{
@@ -368,3 +429,25 @@ func SchedulerDispatchFailedPayload(beadID, rig, errMsg string) map[string]inter
 		"error": errMsg,
 	}
 }
+
+// ScopeViolationPayload creates a payload for out-of-scope writes. source
+// is where it was found: guard, transcript or diff.
+func ScopeViolationPayload(rig, polecat, path, source string) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":     rig,
+		"polecat": polecat,
+		"path":    path,
+		"source":  source,
+	}
+}
+
+// ResourceAlertPayload creates a payload for a session's resource alert.
+// kind is oom or memory.
+func ResourceAlertPayload(rig, agent, kind, detail string) map[string]interface{} {
+	return map[string]interface{}{
+		"rig":    rig,
+		"agent":  agent,
+		"kind":   kind,
+		"detail": detail,
+	}
+}
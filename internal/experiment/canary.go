@@ -0,0 +1,158 @@
+package experiment
+
+import (
+	"fmt"
+	"time"
+)
+
+// StatusPromoted marks a canary whose candidate arm was rolled out.
+const StatusPromoted = "promoted"
+
+// Canary arm names. A canary experiment has exactly these two arms.
+const (
+	BaselineArm = "baseline"
+	CanaryArm   = "canary"
+)
+
+// Promotion thresholds: the canary may be this much worse than baseline
+// and still pass.
+const (
+	maxCycleRatio     = 1.5  // Median cycle time
+	maxCostRatio      = 1.25 // Cost per bead
+	maxRejectionDelta = 0.25 // Review rejections per bead
+)
+
+// Canary restricts an experiment to trialing a configuration change on a
+// single polecat in one rig before it is promoted town-wide. Slings to the
+// rig run the canary arm on one bead at a time, up to Beads beads; every
+// other bead runs the baseline arm and serves as the comparison.
+type Canary struct {
+	Rig   string `json:"rig"`
+	Beads int    `json:"beads"`
+}
+
+// NewCanary returns a canary experiment trialing candidate in rig for n
+// beads.
+func NewCanary(name, rig string, n int, candidate Arm) *Experiment {
+	candidate.Name = CanaryArm
+	return &Experiment{
+		Name:   name,
+		Arms:   []Arm{{Name: BaselineArm}, candidate},
+		Canary: &Canary{Rig: rig, Beads: n},
+	}
+}
+
+// validateCanary checks a canary's settings and arms.
+func (e *Experiment) validateCanary() error {
+	c := e.Canary
+	if c.Rig == "" {
+		return fmt.Errorf("canary %s needs a rig", e.Name)
+	}
+	if c.Beads < 1 {
+		return fmt.Errorf("canary %s needs at least one bead", e.Name)
+	}
+	if len(e.Arms) != 2 || e.Arms[0].Name != BaselineArm || e.Arms[1].Name != CanaryArm {
+		return fmt.Errorf("canary %s must have arms %s and %s", e.Name, BaselineArm, CanaryArm)
+	}
+	if b := e.Arms[0]; b.Formula != "" || b.Agent != "" || b.Prompt != "" {
+		return fmt.Errorf("canary %s: the %s arm must not override anything", e.Name, BaselineArm)
+	}
+	if c := e.Arms[1]; c.Formula == "" && c.Agent == "" && c.Prompt == "" {
+		return fmt.Errorf("canary %s changes nothing: set a formula, agent or prompt", e.Name)
+	}
+	return nil
+}
+
+// PickCanary chooses a bead's arm in a canary experiment. A bead that is
+// already assigned keeps its arm. Otherwise it becomes the canary if fewer
+// than Beads beads have run it and none is still open, so only one polecat
+// runs the change at a time.
+func (e *Experiment) PickCanary(bead string, assignments []Assignment, open func(bead string) bool) *Arm {
+	canaries := 0
+	for _, a := range assignments {
+		if a.Bead == bead {
+			if arm := e.Arm(a.Arm); arm != nil {
+				return arm
+			}
+		}
+		if a.Arm != CanaryArm {
+			continue
+		}
+		canaries++
+		if open(a.Bead) {
+			return e.Arm(BaselineArm)
+		}
+	}
+	if canaries < e.Canary.Beads {
+		return e.Arm(CanaryArm)
+	}
+	return e.Arm(BaselineArm)
+}
+
+// AssignCanary picks bead's arm with PickCanary and records it.
+func AssignCanary(townRoot string, e *Experiment, bead string, open func(bead string) bool) (*Arm, error) {
+	if e.Status != StatusActive {
+		return nil, fmt.Errorf("experiment %s is %s", e.Name, e.Status)
+	}
+	assignments, err := Assignments(townRoot, e.Name)
+	if err != nil {
+		return nil, err
+	}
+	arm := e.PickCanary(bead, assignments, open)
+	for _, a := range assignments {
+		if a.Bead == bead {
+			return arm, nil
+		}
+	}
+	err = appendJSONL(assignmentsPath(townRoot, e.Name), Assignment{Bead: bead, Arm: arm.Name, At: time.Now().UTC()})
+	return arm, err
+}
+
+// ActiveCanary returns the active canary for rig, or nil.
+func ActiveCanary(townRoot, rig string) (*Experiment, error) {
+	experiments, err := List(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range experiments {
+		if e.Canary != nil && e.Canary.Rig == rig && e.Status == StatusActive {
+			return e, nil
+		}
+	}
+	return nil, nil
+}
+
+// CanaryVerdict compares the canary arm against baseline. It returns the
+// reasons the canary can't be promoted yet: too few closed canary beads,
+// or outcomes worse than baseline beyond the promotion thresholds.
+func CanaryVerdict(e *Experiment, stats []ArmStats) []string {
+	var base, canary ArmStats
+	for _, s := range stats {
+		switch s.Arm {
+		case BaselineArm:
+			base = s
+		case CanaryArm:
+			canary = s
+		}
+	}
+
+	var problems []string
+	if canary.Closed < e.Canary.Beads {
+		problems = append(problems, fmt.Sprintf("only %d of %d canary beads closed", canary.Closed, e.Canary.Beads))
+		return problems
+	}
+	if base.Closed == 0 {
+		problems = append(problems, "no closed baseline beads to compare against")
+		return problems
+	}
+	if base.MedianCycle > 0 && float64(canary.MedianCycle) > maxCycleRatio*float64(base.MedianCycle) {
+		problems = append(problems, fmt.Sprintf("median cycle %s vs baseline %s", canary.MedianCycle.Round(time.Minute), base.MedianCycle.Round(time.Minute)))
+	}
+	if canary.RejectionRate() > base.RejectionRate()+maxRejectionDelta {
+		problems = append(problems, fmt.Sprintf("%.2f review rejections per bead vs baseline %.2f", canary.RejectionRate(), base.RejectionRate()))
+	}
+	if base.CostPerBead() > 0 && canary.CostPerBead() > maxCostRatio*base.CostPerBead() {
+		problems = append(problems, fmt.Sprintf("$%.2f per bead vs baseline $%.2f", canary.CostPerBead(), base.CostPerBead()))
+	}
+	return problems
+}
@@ -0,0 +1,118 @@
+package experiment
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func testCanary() *Experiment {
+	return NewCanary("terse", "gastown", 2, Arm{Prompt: "Be terse."})
+}
+
+func TestCanaryValidate(t *testing.T) {
+	if err := testCanary().Validate(); err != nil {
+		t.Errorf("valid canary: %v", err)
+	}
+	if err := NewCanary("noop", "gastown", 2, Arm{}).Validate(); err == nil {
+		t.Error("canary that changes nothing should be invalid")
+	}
+	if err := NewCanary("norig", "", 2, Arm{Agent: "gemini"}).Validate(); err == nil {
+		t.Error("canary without a rig should be invalid")
+	}
+	if err := NewCanary("zero", "gastown", 0, Arm{Agent: "gemini"}).Validate(); err == nil {
+		t.Error("canary with zero beads should be invalid")
+	}
+}
+
+func TestPickCanary_OneAtATime(t *testing.T) {
+	e := testCanary()
+	open := map[string]bool{}
+	isOpen := func(b string) bool { return open[b] }
+	var assignments []Assignment
+	pick := func(bead string) string {
+		arm := e.PickCanary(bead, assignments, isOpen)
+		assignments = append(assignments, Assignment{Bead: bead, Arm: arm.Name})
+		open[bead] = true
+		return arm.Name
+	}
+
+	if got := pick("gt-a"); got != CanaryArm {
+		t.Fatalf("first bead = %s, want canary", got)
+	}
+	if got := pick("gt-b"); got != BaselineArm {
+		t.Errorf("bead while canary in flight = %s, want baseline", got)
+	}
+	if got := e.PickCanary("gt-a", assignments, isOpen).Name; got != CanaryArm {
+		t.Errorf("re-slung canary bead = %s, want sticky canary", got)
+	}
+
+	open["gt-a"] = false
+	if got := pick("gt-c"); got != CanaryArm {
+		t.Errorf("bead after canary closed = %s, want canary", got)
+	}
+	open["gt-c"] = false
+	if got := pick("gt-d"); got != BaselineArm {
+		t.Errorf("bead after %d canaries = %s, want baseline", e.Canary.Beads, got)
+	}
+}
+
+func TestActiveCanary(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := Create(townRoot, testExperiment()); err != nil {
+		t.Fatal(err)
+	}
+	if e, err := ActiveCanary(townRoot, "gastown"); err != nil || e != nil {
+		t.Errorf("no canary yet: %v, %v", e, err)
+	}
+	c := testCanary()
+	if err := Create(townRoot, c); err != nil {
+		t.Fatal(err)
+	}
+	if e, err := ActiveCanary(townRoot, "gastown"); err != nil || e == nil || e.Name != "terse" {
+		t.Errorf("ActiveCanary = %v, %v", e, err)
+	}
+	if e, _ := ActiveCanary(townRoot, "beads"); e != nil {
+		t.Errorf("other rig has canary %s", e.Name)
+	}
+
+	arm, err := AssignCanary(townRoot, c, "gt-a", func(string) bool { return true })
+	if err != nil || arm.Name != CanaryArm {
+		t.Fatalf("AssignCanary = %v, %v", arm, err)
+	}
+	arm, err = AssignCanary(townRoot, c, "gt-b", func(string) bool { return true })
+	if err != nil || arm.Name != BaselineArm {
+		t.Errorf("second AssignCanary = %v, %v; want baseline", arm, err)
+	}
+}
+
+func TestCanaryVerdict(t *testing.T) {
+	e := testCanary()
+	base := ArmStats{Arm: BaselineArm, Beads: 4, Closed: 4, MedianCycle: 2 * time.Hour, Rejections: 1, CostUSD: 8}
+
+	tests := []struct {
+		name   string
+		canary ArmStats
+		want   string
+	}{
+		{"good", ArmStats{Arm: CanaryArm, Beads: 2, Closed: 2, MedianCycle: 2 * time.Hour, CostUSD: 4}, ""},
+		{"unfinished", ArmStats{Arm: CanaryArm, Beads: 2, Closed: 1}, "only 1 of 2"},
+		{"slow", ArmStats{Arm: CanaryArm, Beads: 2, Closed: 2, MedianCycle: 4 * time.Hour, CostUSD: 4}, "median cycle"},
+		{"rejected", ArmStats{Arm: CanaryArm, Beads: 2, Closed: 2, MedianCycle: time.Hour, Rejections: 2, CostUSD: 4}, "rejections"},
+		{"expensive", ArmStats{Arm: CanaryArm, Beads: 2, Closed: 2, MedianCycle: time.Hour, CostUSD: 6}, "per bead"},
+	}
+	for _, tt := range tests {
+		problems := CanaryVerdict(e, []ArmStats{base, tt.canary})
+		got := strings.Join(problems, "; ")
+		if tt.want == "" && got != "" {
+			t.Errorf("%s: problems = %q, want none", tt.name, got)
+		}
+		if tt.want != "" && !strings.Contains(got, tt.want) {
+			t.Errorf("%s: problems = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if problems := CanaryVerdict(e, []ArmStats{{Arm: BaselineArm}, {Arm: CanaryArm, Beads: 2, Closed: 2}}); len(problems) == 0 {
+		t.Error("no baseline beads should block promotion")
+	}
+}
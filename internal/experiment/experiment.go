@@ -0,0 +1,414 @@
+// Package experiment splits comparable beads between variants of how work is
+// dispatched — formula, agent/model, or an extra prompt — and records enough
+// to compare the arms afterwards.
+//
+// An experiment lives in <town>/experiments/<name>/:
+//
+//	experiment.json    definition and arms
+//	assignments.jsonl  one line per bead assigned to an arm (append-only)
+//	costs.jsonl        session costs attributed to assigned beads
+//
+// Assignment is sticky (a re-slung bead keeps its arm) and balanced: a new
+// bead goes to the arm with the fewest beads so far, ties broken by a hash
+// of the bead ID so the split doesn't depend on sling order alone.
+package experiment
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Experiment statuses.
+const (
+	StatusActive  = "active"
+	StatusStopped = "stopped"
+)
+
+// ErrExists is returned by Create when the experiment already exists.
+var ErrExists = errors.New("experiment already exists")
+
+// Experiment is an A/B (or A/B/n) comparison of dispatch variants.
+type Experiment struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Arms        []Arm      `json:"arms"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	StoppedAt   *time.Time `json:"stopped_at,omitempty"`
+
+	// Canary, when set, makes this a canary rollout rather than an open
+	// A/B split. See Canary.
+	Canary *Canary `json:"canary,omitempty"`
+}
+
+// Arm is one variant. Empty fields leave the sling's own value in place, so
+// a control arm is usually just a name.
+type Arm struct {
+	Name    string `json:"name"`
+	Formula string `json:"formula,omitempty"`
+	Agent   string `json:"agent,omitempty"`
+
+	// Prompt is appended to the sling's --args, the executor instructions
+	// the agent sees on its hook.
+	Prompt string `json:"prompt,omitempty"`
+}
+
+// Assignment records that a bead was dispatched under an arm.
+type Assignment struct {
+	Bead string    `json:"bead"`
+	Arm  string    `json:"arm"`
+	At   time.Time `json:"at"`
+}
+
+// CostEntry attributes a session's cost to an assigned bead. Sessions
+// report their running total on every stop, so only the latest entry per
+// session counts.
+type CostEntry struct {
+	Bead    string    `json:"bead"`
+	Session string    `json:"session"`
+	CostUSD float64   `json:"cost_usd"`
+	At      time.Time `json:"at"`
+}
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// ValidateName checks an experiment or arm name.
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid name %q: use lowercase letters, digits and dashes", name)
+	}
+	return nil
+}
+
+// Validate checks the definition: at least two uniquely named arms.
+func (e *Experiment) Validate() error {
+	if err := ValidateName(e.Name); err != nil {
+		return err
+	}
+	if len(e.Arms) < 2 {
+		return fmt.Errorf("experiment %s needs at least two arms", e.Name)
+	}
+	seen := map[string]bool{}
+	for _, a := range e.Arms {
+		if err := ValidateName(a.Name); err != nil {
+			return fmt.Errorf("arm: %w", err)
+		}
+		if seen[a.Name] {
+			return fmt.Errorf("duplicate arm %q", a.Name)
+		}
+		seen[a.Name] = true
+	}
+	if e.Canary != nil {
+		return e.validateCanary()
+	}
+	return nil
+}
+
+// Arm returns the named arm, or nil.
+func (e *Experiment) Arm(name string) *Arm {
+	for i := range e.Arms {
+		if e.Arms[i].Name == name {
+			return &e.Arms[i]
+		}
+	}
+	return nil
+}
+
+// Varies reports which sling settings at least one arm overrides.
+func (e *Experiment) Varies() (formula, agent, prompt bool) {
+	for _, a := range e.Arms {
+		formula = formula || a.Formula != ""
+		agent = agent || a.Agent != ""
+		prompt = prompt || a.Prompt != ""
+	}
+	return formula, agent, prompt
+}
+
+// Pick chooses the arm for bead given the assignments so far. A bead that
+// is already assigned keeps its arm.
+func (e *Experiment) Pick(bead string, assignments []Assignment) *Arm {
+	counts := map[string]int{}
+	for _, a := range assignments {
+		if a.Bead == bead {
+			if arm := e.Arm(a.Arm); arm != nil {
+				return arm
+			}
+		}
+		counts[a.Arm]++
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(e.Name + "/" + bead))
+	offset := int(h.Sum32() % uint32(len(e.Arms)))
+
+	var best *Arm
+	for i := range e.Arms {
+		arm := &e.Arms[(offset+i)%len(e.Arms)]
+		if best == nil || counts[arm.Name] < counts[best.Name] {
+			best = arm
+		}
+	}
+	return best
+}
+
+// ParseArm parses an arm spec: "name" or "name:key=value,key=value" with
+// keys formula, agent and prompt. A prompt value starting with @ is read
+// from that file.
+func ParseArm(spec string) (Arm, error) {
+	name, rest, _ := strings.Cut(spec, ":")
+	arm := Arm{Name: strings.TrimSpace(name)}
+	if err := ValidateName(arm.Name); err != nil {
+		return arm, err
+	}
+	if rest == "" {
+		return arm, nil
+	}
+	for _, kv := range strings.Split(rest, ",") {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			return arm, fmt.Errorf("arm %s: expected key=value, got %q", arm.Name, kv)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "formula":
+			arm.Formula = value
+		case "agent":
+			arm.Agent = value
+		case "prompt":
+			if path, isFile := strings.CutPrefix(value, "@"); isFile {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return arm, fmt.Errorf("arm %s: reading prompt: %w", arm.Name, err)
+				}
+				value = strings.TrimSpace(string(data))
+			}
+			arm.Prompt = value
+		default:
+			return arm, fmt.Errorf("arm %s: unknown key %q (want formula, agent or prompt)", arm.Name, key)
+		}
+	}
+	return arm, nil
+}
+
+// Dir returns the directory holding all experiments.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, "experiments")
+}
+
+func expDir(townRoot, name string) string {
+	return filepath.Join(Dir(townRoot), name)
+}
+
+func defPath(townRoot, name string) string {
+	return filepath.Join(expDir(townRoot, name), "experiment.json")
+}
+
+func assignmentsPath(townRoot, name string) string {
+	return filepath.Join(expDir(townRoot, name), "assignments.jsonl")
+}
+
+func costsPath(townRoot, name string) string {
+	return filepath.Join(expDir(townRoot, name), "costs.jsonl")
+}
+
+// Create writes a new experiment. It refuses to overwrite an existing one.
+func Create(townRoot string, e *Experiment) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	if _, err := os.Stat(defPath(townRoot, e.Name)); err == nil {
+		return fmt.Errorf("%s: %w", e.Name, ErrExists)
+	}
+	if e.Status == "" {
+		e.Status = StatusActive
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+	return Save(townRoot, e)
+}
+
+// Save writes an experiment's definition.
+func Save(townRoot string, e *Experiment) error {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(expDir(townRoot, e.Name), 0755); err != nil {
+		return fmt.Errorf("creating experiment directory: %w", err)
+	}
+	return os.WriteFile(defPath(townRoot, e.Name), append(data, '\n'), 0644) //nolint:gosec // G306: not sensitive
+}
+
+// Load reads an experiment by name.
+func Load(townRoot, name string) (*Experiment, error) {
+	if err := ValidateName(name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(defPath(townRoot, name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("experiment %q not found", name)
+		}
+		return nil, err
+	}
+	var e Experiment
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, fmt.Errorf("parsing experiment %s: %w", name, err)
+	}
+	return &e, nil
+}
+
+// List returns all experiments sorted by name. Unreadable ones are skipped.
+func List(townRoot string) ([]*Experiment, error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []*Experiment
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if e, err := Load(townRoot, entry.Name()); err == nil {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Assignments returns the recorded assignments, oldest first.
+func Assignments(townRoot, name string) ([]Assignment, error) {
+	var out []Assignment
+	err := readJSONL(assignmentsPath(townRoot, name), func(line []byte) error {
+		var a Assignment
+		if err := json.Unmarshal(line, &a); err != nil {
+			return err
+		}
+		out = append(out, a)
+		return nil
+	})
+	return out, err
+}
+
+// Assign picks bead's arm and records the assignment. A bead that is
+// already assigned keeps its arm and nothing new is recorded.
+func Assign(townRoot string, e *Experiment, bead string) (*Arm, error) {
+	if e.Status != StatusActive {
+		return nil, fmt.Errorf("experiment %s is %s", e.Name, e.Status)
+	}
+	assignments, err := Assignments(townRoot, e.Name)
+	if err != nil {
+		return nil, err
+	}
+	arm := e.Pick(bead, assignments)
+	for _, a := range assignments {
+		if a.Bead == bead {
+			return arm, nil
+		}
+	}
+	err = appendJSONL(assignmentsPath(townRoot, e.Name), Assignment{Bead: bead, Arm: arm.Name, At: time.Now().UTC()})
+	return arm, err
+}
+
+// RecordCost attributes a session's running cost to bead in every
+// experiment that assigned it. Beads outside any experiment are ignored.
+func RecordCost(townRoot, bead, session string, costUSD float64) error {
+	experiments, err := List(townRoot)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, e := range experiments {
+		assignments, err := Assignments(townRoot, e.Name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, a := range assignments {
+			if a.Bead == bead {
+				entry := CostEntry{Bead: bead, Session: session, CostUSD: costUSD, At: time.Now().UTC()}
+				if err := appendJSONL(costsPath(townRoot, e.Name), entry); err != nil {
+					errs = append(errs, err)
+				}
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Costs returns the total cost per bead: the latest total of each session
+// that worked on it, summed.
+func Costs(townRoot, name string) (map[string]float64, error) {
+	type key struct{ bead, session string }
+	latest := map[key]float64{}
+	err := readJSONL(costsPath(townRoot, name), func(line []byte) error {
+		var c CostEntry
+		if err := json.Unmarshal(line, &c); err != nil {
+			return err
+		}
+		latest[key{c.Bead, c.Session}] = c.CostUSD
+		return nil
+	})
+	out := map[string]float64{}
+	for k, cost := range latest {
+		out[k.bead] += cost
+	}
+	return out, err
+}
+
+func appendJSONL(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: not sensitive
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func readJSONL(path string, fn func([]byte) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return fmt.Errorf("parsing %s: %w", path, err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,153 @@
+package experiment
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testExperiment() *Experiment {
+	return &Experiment{
+		Name: "terse-prompt",
+		Arms: []Arm{{Name: "control"}, {Name: "terse", Prompt: "Be terse."}},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		e    Experiment
+		ok   bool
+	}{
+		{"valid", *testExperiment(), true},
+		{"one arm", Experiment{Name: "x", Arms: []Arm{{Name: "a"}}}, false},
+		{"duplicate arm", Experiment{Name: "x", Arms: []Arm{{Name: "a"}, {Name: "a"}}}, false},
+		{"bad name", Experiment{Name: "Bad Name", Arms: []Arm{{Name: "a"}, {Name: "b"}}}, false},
+	}
+	for _, tt := range tests {
+		if err := tt.e.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%s: Validate() = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestPick_BalancedAndSticky(t *testing.T) {
+	e := testExperiment()
+	var assignments []Assignment
+	for _, bead := range []string{"gt-a", "gt-b", "gt-c", "gt-d", "gt-e", "gt-f"} {
+		arm := e.Pick(bead, assignments)
+		assignments = append(assignments, Assignment{Bead: bead, Arm: arm.Name})
+	}
+	counts := map[string]int{}
+	for _, a := range assignments {
+		counts[a.Arm]++
+	}
+	if counts["control"] != 3 || counts["terse"] != 3 {
+		t.Errorf("split = %v, want 3/3", counts)
+	}
+
+	for _, a := range assignments {
+		if got := e.Pick(a.Bead, assignments); got.Name != a.Arm {
+			t.Errorf("re-pick %s = %s, want sticky %s", a.Bead, got.Name, a.Arm)
+		}
+	}
+}
+
+func TestParseArm(t *testing.T) {
+	dir := t.TempDir()
+	promptFile := filepath.Join(dir, "prompt.md")
+	if err := os.WriteFile(promptFile, []byte("Write tests first.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	arm, err := ParseArm("tdd:formula=mol-tdd,agent=gemini,prompt=@" + promptFile)
+	if err != nil {
+		t.Fatalf("ParseArm: %v", err)
+	}
+	want := Arm{Name: "tdd", Formula: "mol-tdd", Agent: "gemini", Prompt: "Write tests first."}
+	if arm != want {
+		t.Errorf("ParseArm = %+v, want %+v", arm, want)
+	}
+
+	if arm, err := ParseArm("control"); err != nil || arm != (Arm{Name: "control"}) {
+		t.Errorf("ParseArm(control) = %+v, %v", arm, err)
+	}
+	for _, bad := range []string{"", "x:model=opus", "x:formula", "Bad"} {
+		if _, err := ParseArm(bad); err == nil {
+			t.Errorf("ParseArm(%q) = nil error", bad)
+		}
+	}
+}
+
+func TestAssignAndCosts(t *testing.T) {
+	townRoot := t.TempDir()
+	e := testExperiment()
+	if err := Create(townRoot, e); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := Create(townRoot, testExperiment()); !errors.Is(err, ErrExists) {
+		t.Errorf("second Create = %v, want ErrExists", err)
+	}
+
+	first, err := Assign(townRoot, e, "gt-a")
+	if err != nil {
+		t.Fatalf("Assign: %v", err)
+	}
+	again, err := Assign(townRoot, e, "gt-a")
+	if err != nil || again.Name != first.Name {
+		t.Errorf("re-Assign = %v, %v; want %s", again, err, first.Name)
+	}
+	if got, _ := Assignments(townRoot, e.Name); len(got) != 1 {
+		t.Errorf("assignments = %v, want one", got)
+	}
+
+	// Running totals: the first session ends at 1.25, a second adds 0.75.
+	for _, c := range []struct {
+		bead, session string
+		cost          float64
+	}{
+		{"gt-a", "gt-nux", 0.5},
+		{"gt-a", "gt-nux", 1.25},
+		{"gt-a", "gt-toast", 0.75},
+		{"gt-other", "gt-max", 9},
+	} {
+		if err := RecordCost(townRoot, c.bead, c.session, c.cost); err != nil {
+			t.Fatal(err)
+		}
+	}
+	costs, err := Costs(townRoot, e.Name)
+	if err != nil || costs["gt-a"] != 2 || len(costs) != 1 {
+		t.Errorf("Costs = %v, %v", costs, err)
+	}
+
+	e.Status = StatusStopped
+	if _, err := Assign(townRoot, e, "gt-b"); err == nil {
+		t.Error("Assign on stopped experiment = nil error")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	e := testExperiment()
+	outcomes := []Outcome{
+		{Bead: "a", Arm: "control", Closed: true, CycleTime: time.Hour, Rejections: 1, CostUSD: 2},
+		{Bead: "b", Arm: "control", Closed: true, CycleTime: 3 * time.Hour, CostUSD: 4},
+		{Bead: "c", Arm: "control"},
+		{Bead: "d", Arm: "terse", Closed: true, CycleTime: 30 * time.Minute, CostUSD: 1},
+	}
+	stats := Summarize(e, outcomes)
+	if len(stats) != 2 || stats[0].Arm != "control" || stats[1].Arm != "terse" {
+		t.Fatalf("stats = %+v", stats)
+	}
+	c := stats[0]
+	if c.Beads != 3 || c.Closed != 2 || c.MedianCycle != 2*time.Hour || c.Rejections != 1 || c.CostUSD != 6 {
+		t.Errorf("control = %+v", c)
+	}
+	if c.CostPerBead() != 2 {
+		t.Errorf("CostPerBead = %v, want 2", c.CostPerBead())
+	}
+	if stats[1].MedianCycle != 30*time.Minute || stats[1].RejectionRate() != 0 {
+		t.Errorf("terse = %+v", stats[1])
+	}
+}
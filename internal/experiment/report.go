@@ -0,0 +1,83 @@
+package experiment
+
+import (
+	"sort"
+	"time"
+)
+
+// Outcome is what happened to one assigned bead.
+type Outcome struct {
+	Bead       string        `json:"bead"`
+	Arm        string        `json:"arm"`
+	Closed     bool          `json:"closed"`
+	CycleTime  time.Duration `json:"cycle_time,omitempty"` // Assignment to close; zero while open
+	Rejections int           `json:"rejections"`           // Merge requests rejected in review
+	CostUSD    float64       `json:"cost_usd"`
+}
+
+// ArmStats summarizes one arm's outcomes.
+type ArmStats struct {
+	Arm         string        `json:"arm"`
+	Beads       int           `json:"beads"`
+	Closed      int           `json:"closed"`
+	MedianCycle time.Duration `json:"median_cycle,omitempty"`
+	MeanCycle   time.Duration `json:"mean_cycle,omitempty"`
+	Rejections  int           `json:"rejections"`
+	CostUSD     float64       `json:"cost_usd"`
+}
+
+// RejectionRate returns review rejections per bead.
+func (s ArmStats) RejectionRate() float64 {
+	if s.Beads == 0 {
+		return 0
+	}
+	return float64(s.Rejections) / float64(s.Beads)
+}
+
+// CostPerBead returns the mean cost of the arm's beads.
+func (s ArmStats) CostPerBead() float64 {
+	if s.Beads == 0 {
+		return 0
+	}
+	return s.CostUSD / float64(s.Beads)
+}
+
+// Summarize groups outcomes by arm, in the experiment's arm order. Cycle
+// times cover closed beads only.
+func Summarize(e *Experiment, outcomes []Outcome) []ArmStats {
+	byArm := map[string][]Outcome{}
+	for _, o := range outcomes {
+		byArm[o.Arm] = append(byArm[o.Arm], o)
+	}
+
+	stats := make([]ArmStats, 0, len(e.Arms))
+	for _, arm := range e.Arms {
+		s := ArmStats{Arm: arm.Name}
+		var cycles []time.Duration
+		for _, o := range byArm[arm.Name] {
+			s.Beads++
+			s.Rejections += o.Rejections
+			s.CostUSD += o.CostUSD
+			if o.Closed {
+				s.Closed++
+				cycles = append(cycles, o.CycleTime)
+			}
+		}
+		if len(cycles) > 0 {
+			sort.Slice(cycles, func(i, j int) bool { return cycles[i] < cycles[j] })
+			var total time.Duration
+			for _, c := range cycles {
+				total += c
+			}
+			s.MeanCycle = total / time.Duration(len(cycles))
+			mid := len(cycles) / 2
+			if len(cycles)%2 == 0 {
+				s.MedianCycle = (cycles[mid-1] + cycles[mid]) / 2
+			} else {
+				s.MedianCycle = cycles[mid]
+			}
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
@@ -0,0 +1,203 @@
+// Package freeze implements change freezes: a per-rig switch, optionally
+// scheduled, that stops the merge queue and the dispatch of code-changing
+// beads to the rig while leaving read-only work alone.
+package freeze
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ReadOnlyLabels mark beads that don't change code (analysis, review,
+// research). They may still be dispatched to a frozen rig.
+var ReadOnlyLabels = []string{"read-only", "analysis"}
+
+// Freeze is a change freeze on one rig.
+type Freeze struct {
+	Rig       string    `json:"rig"`
+	Reason    string    `json:"reason,omitempty"`
+	By        string    `json:"by,omitempty"`
+	From      time.Time `json:"from"`            // Freeze starts
+	Until     time.Time `json:"until,omitempty"` // Scheduled thaw; zero = until gt thaw
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Active reports whether the freeze is in force at now.
+func (f *Freeze) Active(now time.Time) bool {
+	if f == nil || now.Before(f.From) {
+		return false
+	}
+	return f.Until.IsZero() || now.Before(f.Until)
+}
+
+// Expired reports whether the freeze's scheduled thaw has passed.
+func (f *Freeze) Expired(now time.Time) bool {
+	return f != nil && !f.Until.IsZero() && !now.Before(f.Until)
+}
+
+// State describes the freeze at now: "scheduled", "active" or "expired".
+func (f *Freeze) State(now time.Time) string {
+	switch {
+	case f.Expired(now):
+		return "expired"
+	case f.Active(now):
+		return "active"
+	default:
+		return "scheduled"
+	}
+}
+
+// Dir returns the directory holding freeze state.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "freeze")
+}
+
+func path(townRoot, rig string) string {
+	return filepath.Join(Dir(townRoot), rig+".json")
+}
+
+// Load returns the rig's freeze, or nil if it has none.
+func Load(townRoot, rig string) (*Freeze, error) {
+	data, err := os.ReadFile(path(townRoot, rig)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	f := &Freeze{}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("parsing freeze for %s: %w", rig, err)
+	}
+	return f, nil
+}
+
+// Save writes the freeze, replacing any existing one for the rig.
+func Save(townRoot string, f *Freeze) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path(townRoot, f.Rig), data, 0644) //nolint:gosec // G306: runtime state
+}
+
+// Remove thaws a rig. Removing a rig with no freeze is not an error.
+func Remove(townRoot, rig string) error {
+	if err := os.Remove(path(townRoot, rig)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// List returns every recorded freeze, sorted by rig.
+func List(townRoot string) ([]*Freeze, error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var freezes []*Freeze
+	for _, e := range entries {
+		rig, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		f, err := Load(townRoot, rig)
+		if err != nil {
+			return nil, err
+		}
+		if f != nil {
+			freezes = append(freezes, f)
+		}
+	}
+	sort.Slice(freezes, func(i, j int) bool { return freezes[i].Rig < freezes[j].Rig })
+	return freezes, nil
+}
+
+// Check returns the rig's freeze if it is in force at now. Unreadable
+// freeze state counts as frozen: a release manager's stop switch should
+// fail closed.
+func Check(townRoot, rig string, now time.Time) *Freeze {
+	f, err := Load(townRoot, rig)
+	if err != nil {
+		return &Freeze{Rig: rig, Reason: fmt.Sprintf("freeze state unreadable: %v", err)}
+	}
+	if !f.Active(now) {
+		return nil
+	}
+	return f
+}
+
+// IsReadOnly reports whether a bead with these labels may be dispatched
+// to a frozen rig.
+func IsReadOnly(labels []string) bool {
+	for _, l := range labels {
+		for _, ro := range ReadOnlyLabels {
+			if l == ro {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ParseTime parses a freeze or thaw time relative to now: a duration
+// ("2h", "90m"), a time of day ("18:00", the next occurrence), a local
+// date and time ("2026-03-01 18:00") or RFC 3339.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("duration %q must be positive", s)
+		}
+		return now.Add(d), nil
+	}
+	if t, err := time.ParseInLocation("15:04", s, now.Location()); err == nil {
+		at := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, now.Location())
+		if !at.After(now) {
+			at = at.AddDate(0, 0, 1)
+		}
+		return at, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use a duration (2h), HH:MM, \"YYYY-MM-DD HH:MM\" or RFC 3339", s)
+}
+
+// Describe renders the freeze window, e.g. "since 18:00 until 2026-03-02 09:00".
+func (f *Freeze) Describe(now time.Time) string {
+	var parts []string
+	if now.Before(f.From) {
+		parts = append(parts, "from "+formatTime(f.From, now))
+	} else {
+		parts = append(parts, "since "+formatTime(f.From, now))
+	}
+	if f.Until.IsZero() {
+		parts = append(parts, "until thawed")
+	} else {
+		parts = append(parts, "until "+formatTime(f.Until, now))
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatTime(t, now time.Time) string {
+	t = t.In(now.Location())
+	if t.Year() == now.Year() && t.YearDay() == now.YearDay() {
+		return t.Format("15:04")
+	}
+	return t.Format("2006-01-02 15:04")
+}
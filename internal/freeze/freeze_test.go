@@ -0,0 +1,113 @@
+package freeze
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestActive(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		f     *Freeze
+		want  bool
+		state string
+	}{
+		{"open-ended", &Freeze{From: now.Add(-time.Hour)}, true, "active"},
+		{"scheduled", &Freeze{From: now.Add(time.Hour)}, false, "scheduled"},
+		{"windowed", &Freeze{From: now.Add(-time.Hour), Until: now.Add(time.Hour)}, true, "active"},
+		{"thawed", &Freeze{From: now.Add(-2 * time.Hour), Until: now}, false, "expired"},
+	}
+	for _, tt := range tests {
+		if got := tt.f.Active(now); got != tt.want {
+			t.Errorf("%s: Active = %v, want %v", tt.name, got, tt.want)
+		}
+		if got := tt.f.State(now); got != tt.state {
+			t.Errorf("%s: State = %q, want %q", tt.name, got, tt.state)
+		}
+	}
+	var nilFreeze *Freeze
+	if nilFreeze.Active(now) {
+		t.Error("nil freeze should not be active")
+	}
+}
+
+func TestSaveLoadList(t *testing.T) {
+	town := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if f, err := Load(town, "gastown"); err != nil || f != nil {
+		t.Fatalf("Load with no freeze = %v, %v", f, err)
+	}
+	for _, rig := range []string{"gastown", "beads"} {
+		if err := Save(town, &Freeze{Rig: rig, Reason: "release", From: now}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, err := List(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || all[0].Rig != "beads" || all[1].Reason != "release" {
+		t.Errorf("List = %+v", all)
+	}
+	if Check(town, "gastown", now) == nil {
+		t.Error("gastown should be frozen")
+	}
+
+	if err := Remove(town, "gastown"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Remove(town, "gastown"); err != nil {
+		t.Errorf("second Remove: %v", err)
+	}
+	if Check(town, "gastown", now) != nil {
+		t.Error("gastown should be thawed")
+	}
+}
+
+func TestCheckFailsClosed(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(Dir(town), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(Dir(town), "gastown.json"), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if f := Check(town, "gastown", time.Now()); f == nil {
+		t.Error("unreadable freeze state should count as frozen")
+	}
+}
+
+func TestIsReadOnly(t *testing.T) {
+	if !IsReadOnly([]string{"gt:task", "analysis"}) {
+		t.Error("analysis bead should be read-only")
+	}
+	if IsReadOnly([]string{"gt:task"}) || IsReadOnly(nil) {
+		t.Error("unlabeled bead should not be read-only")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		"2h":                   now.Add(2 * time.Hour),
+		"18:00":                time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC),
+		"09:00":                time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+		"2026-03-05 08:30":     time.Date(2026, 3, 5, 8, 30, 0, 0, time.UTC),
+		"2026-03-05T08:30:00Z": time.Date(2026, 3, 5, 8, 30, 0, 0, time.UTC),
+	}
+	for in, want := range tests {
+		got, err := ParseTime(in, now)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("ParseTime(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "-1h", "tomorrow", "25:00"} {
+		if _, err := ParseTime(bad, now); err == nil {
+			t.Errorf("ParseTime(%q) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package fsscope
+
+import (
+	"bufio"
+	"encoding/json"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+)
+
+// transcriptEntry is the part of a Claude Code transcript line the audit
+// reads: the working directory and any tool calls.
+type transcriptEntry struct {
+	Type      string `json:"type"`
+	Cwd       string `json:"cwd"`
+	Timestamp string `json:"timestamp"`
+	Message   *struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+type toolUse struct {
+	Type  string          `json:"type"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+}
+
+// ScanTranscript returns the tool calls in a Claude Code transcript that
+// wrote outside scope. Calls are resolved against the working directory
+// recorded with each entry, or cwd when an entry has none. Rig and Polecat
+// are left for the caller to fill in.
+func ScanTranscript(path string, scope Scope, cwd string) ([]Violation, error) {
+	f, err := agentlog.OpenTranscript(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var vs []Violation
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 256*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry transcriptEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Type != "assistant" || entry.Message == nil {
+			continue
+		}
+		var blocks []toolUse
+		if err := json.Unmarshal(entry.Message.Content, &blocks); err != nil {
+			continue
+		}
+		dir := cwd
+		if entry.Cwd != "" {
+			dir = entry.Cwd
+		}
+		ts, _ := time.Parse(time.RFC3339, entry.Timestamp)
+		for _, b := range blocks {
+			if b.Type != "tool_use" {
+				continue
+			}
+			for _, p := range scope.Outside(ToolTargets(dir, b.Name, b.Input)) {
+				v := Violation{Time: ts, Source: SourceTranscript, Tool: b.Name, Path: p}
+				if b.Name == "Bash" {
+					var in struct {
+						Command string `json:"command"`
+					}
+					_ = json.Unmarshal(b.Input, &in)
+					v.Detail = in.Command
+				}
+				vs = append(vs, v)
+			}
+		}
+	}
+	return vs, scanner.Err()
+}
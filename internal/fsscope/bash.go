@@ -0,0 +1,384 @@
+package fsscope
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// word is one shell word or operator.
+type word struct {
+	text string
+	op   bool // Separator (;, &&, ||, |, &, newline) or redirection (>, >>, &>)
+}
+
+// BashTargets returns the absolute paths a shell command would write to,
+// as far as they can be told from the command text: redirections, the
+// operands of commands that create, change or remove files, and the
+// repository a mutating git command runs in. cd within the command is
+// followed. Paths that can't be resolved statically are skipped.
+//
+// This is a guard against mistakes, not a sandbox: a determined command
+// (an interpreter one-liner, a script) can write anywhere.
+func BashTargets(cwd, command string) []string {
+	var targets []string
+	dir := cwd
+	add := func(p string) {
+		if p = Resolve(dir, p); p != "" {
+			targets = append(targets, p)
+		}
+	}
+
+	var args []string
+	flush := func() {
+		if len(args) > 0 {
+			if next, ok := changeDir(dir, args); ok {
+				dir = next
+			} else {
+				for _, p := range commandTargets(args) {
+					add(p)
+				}
+				if repo, ok := gitWriteDir(args); ok {
+					add(repo)
+				}
+			}
+		}
+		args = nil
+	}
+
+	words := split(command)
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		if !w.op {
+			args = append(args, w.text)
+			continue
+		}
+		if isRedirect(w.text) {
+			if i+1 < len(words) && !words[i+1].op {
+				add(words[i+1].text)
+				i++
+			}
+			continue
+		}
+		flush()
+	}
+	flush()
+	return targets
+}
+
+// changeDir follows cd and pushd.
+func changeDir(dir string, args []string) (string, bool) {
+	if args[0] != "cd" && args[0] != "pushd" {
+		return "", false
+	}
+	target := "~"
+	for _, a := range args[1:] {
+		if a == "-" {
+			return dir, true // Previous directory: unknown, stay put
+		}
+		if !strings.HasPrefix(a, "-") {
+			target = a
+			break
+		}
+	}
+	if next := Resolve(dir, target); next != "" {
+		return next, true
+	}
+	return dir, true
+}
+
+// commandTargets returns the file operands of a command that writes them.
+func commandTargets(args []string) []string {
+	args = stripPrefix(args)
+	if len(args) == 0 {
+		return nil
+	}
+	name := filepath.Base(args[0])
+	takesTargetDir := name == "cp" || name == "mv" || name == "install" || name == "ln"
+	var operands []string
+	var targetDir string // -t DIR: every operand is a source
+	for i := 1; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "--":
+			operands = append(operands, args[i+1:]...)
+			i = len(args)
+		case name == "truncate" && (a == "-s" || a == "--size"):
+			i++ // Skip the size value
+		case takesTargetDir && strings.HasPrefix(a, "--target-directory="):
+			targetDir = strings.TrimPrefix(a, "--target-directory=")
+		case takesTargetDir && a == "--target-directory":
+			if i+1 < len(args) {
+				targetDir = args[i+1]
+				i++
+			}
+		case takesTargetDir && !strings.HasPrefix(a, "--") && strings.HasPrefix(a, "-") && strings.Contains(a, "t"):
+			// -t may close a cluster of short flags (-rt DIR) or carry its
+			// value (-tDIR).
+			if v := a[strings.IndexByte(a, 't')+1:]; v != "" {
+				targetDir = v
+			} else if i+1 < len(args) {
+				targetDir = args[i+1]
+				i++
+			}
+		case strings.HasPrefix(a, "-"):
+		default:
+			operands = append(operands, a)
+		}
+	}
+	switch name {
+	case "rm", "rmdir", "unlink", "shred", "touch", "mkdir", "truncate", "tee":
+		return operands
+	case "chmod", "chown", "chgrp":
+		if len(operands) > 1 {
+			return operands[1:]
+		}
+	case "cp", "mv", "install", "ln", "rsync":
+		if targetDir != "" {
+			return []string{targetDir}
+		}
+		if len(operands) > 1 {
+			return operands[len(operands)-1:]
+		}
+	case "sed":
+		if hasInPlace(args[1:]) && len(operands) > 1 {
+			return operands[1:]
+		}
+	case "dd":
+		var out []string
+		for _, a := range args[1:] {
+			if strings.HasPrefix(a, "of=") {
+				out = append(out, strings.TrimPrefix(a, "of="))
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// gitWriteSubcommands change the working tree or the repository.
+var gitWriteSubcommands = map[string]bool{
+	"add": true, "am": true, "apply": true, "checkout": true, "cherry-pick": true,
+	"clean": true, "commit": true, "merge": true, "mv": true, "pull": true,
+	"rebase": true, "reset": true, "restore": true, "revert": true, "rm": true,
+	"stash": true, "switch": true,
+}
+
+// gitWriteDir returns the directory a mutating git command runs in: its
+// -C directory, or "." for the current one.
+func gitWriteDir(args []string) (string, bool) {
+	args = stripPrefix(args)
+	if len(args) == 0 || filepath.Base(args[0]) != "git" {
+		return "", false
+	}
+	dir := "."
+	for i := 1; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "-C" && i+1 < len(args):
+			dir = filepath.Join(dir, args[i+1])
+			if filepath.IsAbs(args[i+1]) {
+				dir = args[i+1]
+			}
+			i++
+		case a == "-c" && i+1 < len(args):
+			i++
+		case strings.HasPrefix(a, "-"):
+		default:
+			return dir, gitWriteSubcommands[a]
+		}
+	}
+	return "", false
+}
+
+// stripPrefix drops leading variable assignments and sudo/env/command.
+func stripPrefix(args []string) []string {
+	for len(args) > 0 {
+		a := args[0]
+		if a == "sudo" || a == "env" || a == "command" || a == "builtin" ||
+			(strings.Contains(a, "=") && !strings.HasPrefix(a, "=") && !strings.HasPrefix(a, "-")) {
+			args = args[1:]
+			continue
+		}
+		break
+	}
+	return args
+}
+
+func hasInPlace(args []string) bool {
+	for _, a := range args {
+		if a == "-i" || a == "--in-place" || strings.HasPrefix(a, "--in-place=") ||
+			(strings.HasPrefix(a, "-i") && !strings.HasPrefix(a, "--")) {
+			return true
+		}
+	}
+	return false
+}
+
+func isRedirect(op string) bool {
+	return op == ">" || op == ">>" || op == "&>" || op == "&>>" || op == ">|"
+}
+
+// split breaks a command into words and operators, honouring quotes and
+// backslash escapes. Input redirections, fd duplications (2>&1) and
+// here-document bodies are dropped.
+func split(command string) []word {
+	var words []word
+	var heredocs []string // Delimiters of here-documents whose bodies follow the current line
+	var cur strings.Builder
+	inWord := false
+	emit := func() {
+		if inWord {
+			words = append(words, word{text: cur.String()})
+			cur.Reset()
+			inWord = false
+		}
+	}
+	op := func(s string) {
+		emit()
+		words = append(words, word{text: s, op: true})
+	}
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case c == '\'':
+			inWord = true
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end < 0 {
+				cur.WriteString(command[i+1:])
+				i = len(command)
+				continue
+			}
+			cur.WriteString(command[i+1 : i+1+end])
+			i += end + 1
+		case c == '"':
+			inWord = true
+			for i++; i < len(command) && command[i] != '"'; i++ {
+				if command[i] == '\\' && i+1 < len(command) {
+					i++
+				}
+				cur.WriteByte(command[i])
+			}
+		case c == '\\' && i+1 < len(command):
+			if command[i+1] != '\n' {
+				inWord = true
+				cur.WriteByte(command[i+1])
+			}
+			i++
+		case c == ' ' || c == '\t':
+			emit()
+		case c == '\n' && len(heredocs) > 0:
+			op(";")
+			i = skipHeredocs(command, i+1, heredocs) - 1
+			heredocs = nil
+		case c == '\n' || c == ';':
+			op(";")
+		case c == '&' && strings.HasPrefix(command[i:], "&&"):
+			op("&&")
+			i++
+		case c == '|' && strings.HasPrefix(command[i:], "||"):
+			op("||")
+			i++
+		case c == '|':
+			op("|")
+		case c == '&' && strings.HasPrefix(command[i:], "&>>"):
+			op("&>>")
+			i += 2
+		case c == '&' && strings.HasPrefix(command[i:], "&>"):
+			op("&>")
+			i++
+		case c == '&':
+			op("&")
+		case c == '>' || c == '<':
+			// A word of digits right before the operator is a file
+			// descriptor, not an argument.
+			if inWord && isDigits(cur.String()) {
+				cur.Reset()
+				inWord = false
+			}
+			emit()
+			s := string(c)
+			if i+1 < len(command) && (command[i+1] == '>' || command[i+1] == '|') && c == '>' {
+				s += string(command[i+1])
+				i++
+			}
+			if i+1 < len(command) && command[i+1] == '&' {
+				// fd duplication: 2>&1, >&2
+				for i += 2; i < len(command) && command[i] >= '0' && command[i] <= '9'; i++ {
+				}
+				i--
+				continue
+			}
+			if c == '<' && strings.HasPrefix(command[i:], "<<") && !strings.HasPrefix(command[i:], "<<<") {
+				delim, end := heredocDelimiter(command, i+2)
+				if delim != "" {
+					heredocs = append(heredocs, delim)
+				}
+				i = end - 1
+				continue
+			}
+			if c == '<' {
+				// Input redirection: drop the operator and its file.
+				for i++; i < len(command) && (command[i] == ' ' || command[i] == '\t'); i++ {
+				}
+				for ; i < len(command) && !strings.ContainsRune(" \t\n;&|<>", rune(command[i])); i++ {
+				}
+				i--
+				continue
+			}
+			op(s)
+		default:
+			inWord = true
+			cur.WriteByte(c)
+		}
+	}
+	emit()
+	return words
+}
+
+// heredocDelimiter reads the delimiter word of a here-document starting at
+// i (just past "<<") and returns it unquoted with the index after it.
+func heredocDelimiter(command string, i int) (string, int) {
+	if i < len(command) && command[i] == '-' {
+		i++
+	}
+	for ; i < len(command) && (command[i] == ' ' || command[i] == '\t'); i++ {
+	}
+	start := i
+	for ; i < len(command) && !strings.ContainsRune(" \t\n;&|<>", rune(command[i])); i++ {
+	}
+	return strings.Trim(command[start:i], `'"\`), i
+}
+
+// skipHeredocs returns the index just past the bodies of the given
+// here-documents, which start at i.
+func skipHeredocs(command string, i int, delims []string) int {
+	for _, delim := range delims {
+		for i < len(command) {
+			end := strings.IndexByte(command[i:], '\n')
+			line := command[i:]
+			if end >= 0 {
+				line = command[i : i+end]
+				i += end + 1
+			} else {
+				i = len(command)
+			}
+			if strings.TrimLeft(line, "\t") == delim {
+				break
+			}
+		}
+	}
+	return i
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+// Package fsscope keeps a polecat's file writes inside its own directory.
+//
+// A polecat works in <town>/<rig>/polecats/<name>/<rig>; everything it has
+// any business writing lives under <town>/<rig>/polecats/<name>. Sibling
+// polecats, other rigs, the shared clones and the town root are out of
+// scope. Writes are checked twice: before a tool runs (gt tap guard scope,
+// a PreToolUse hook) and after the fact, by reading the session transcript
+// and the shared clones' git status (gt witness scope).
+//
+// Path checks work on resolved paths: relative paths are joined to the
+// working directory, cd within a shell command is followed, and symlinks
+// are evaluated, so ../../other-rig/file and a symlink pointing out of the
+// worktree are both caught.
+package fsscope
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Scope is the set of directories a polecat may write to.
+type Scope struct {
+	// Root is the polecat's directory.
+	Root string
+
+	// Town is the town root. Paths in the town but outside Root are out
+	// of scope even when Extra would allow them (a town under /tmp).
+	Town string
+
+	// Extra are other directories writes may go to: the system temp
+	// directory and /dev (for /dev/null and friends).
+	Extra []string
+}
+
+// ForPolecat returns the scope of a polecat.
+func ForPolecat(townRoot, rig, polecat string) Scope {
+	extra := []string{os.TempDir(), "/tmp", "/dev"}
+	return Scope{Root: filepath.Join(townRoot, rig, "polecats", polecat), Town: townRoot, Extra: extra}
+}
+
+// Contains reports whether the absolute path is inside the scope.
+func (s Scope) Contains(path string) bool {
+	p := realPath(path)
+	if within(realPath(s.Root), p) {
+		return true
+	}
+	if s.Town != "" && within(realPath(s.Town), p) {
+		return false
+	}
+	for _, dir := range s.Extra {
+		if within(realPath(dir), p) {
+			return true
+		}
+	}
+	return false
+}
+
+// Outside returns the targets that are not inside the scope.
+func (s Scope) Outside(targets []string) []string {
+	var out []string
+	for _, t := range targets {
+		if !s.Contains(t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// realPath evaluates symlinks in the longest existing prefix of path, so a
+// file that doesn't exist yet is still checked against where its parent
+// really is.
+func realPath(path string) string {
+	path = filepath.Clean(path)
+	var rest []string
+	for p := path; ; p = filepath.Dir(p) {
+		if resolved, err := filepath.EvalSymlinks(p); err == nil {
+			return filepath.Join(append([]string{resolved}, rest...)...)
+		}
+		if filepath.Dir(p) == p {
+			return path
+		}
+		rest = append([]string{filepath.Base(p)}, rest...)
+	}
+}
+
+// Resolve returns p as an absolute path, relative to cwd, with ~ and $HOME
+// expanded. It returns "" for paths that depend on other variables or
+// command substitution and so can't be resolved statically.
+func Resolve(cwd, p string) string {
+	home, _ := os.UserHomeDir()
+	switch {
+	case p == "~":
+		p = home
+	case strings.HasPrefix(p, "~/"):
+		p = filepath.Join(home, p[2:])
+	case p == "$HOME" || strings.HasPrefix(p, "$HOME/"):
+		p = home + p[len("$HOME"):]
+	case strings.HasPrefix(p, "${HOME}"):
+		p = home + p[len("${HOME}"):]
+	}
+	if p == "" || strings.ContainsAny(p, "$`") {
+		return ""
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(cwd, p)
+	}
+	return filepath.Clean(p)
+}
+
+// ToolTargets returns the absolute paths a tool call would write to. Tools
+// that don't write return nothing.
+func ToolTargets(cwd, tool string, input json.RawMessage) []string {
+	var in struct {
+		FilePath     string `json:"file_path"`
+		NotebookPath string `json:"notebook_path"`
+		Command      string `json:"command"`
+	}
+	if err := json.Unmarshal(input, &in); err != nil {
+		return nil
+	}
+	var p string
+	switch tool {
+	case "Write", "Edit", "MultiEdit":
+		p = in.FilePath
+	case "NotebookEdit":
+		p = in.NotebookPath
+	case "Bash":
+		return BashTargets(cwd, in.Command)
+	}
+	if p = Resolve(cwd, p); p != "" {
+		return []string{p}
+	}
+	return nil
+}
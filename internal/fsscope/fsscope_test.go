@@ -0,0 +1,138 @@
+package fsscope
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScopeContains(t *testing.T) {
+	town := t.TempDir()
+	s := Scope{Root: filepath.Join(town, "gastown", "polecats", "Toast")}
+	worktree := filepath.Join(s.Root, "gastown")
+	if err := os.MkdirAll(worktree, 0755); err != nil {
+		t.Fatal(err)
+	}
+	sibling := filepath.Join(town, "beads", "mayor", "rig")
+	if err := os.MkdirAll(sibling, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(sibling, filepath.Join(worktree, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{filepath.Join(worktree, "main.go"), true},
+		{filepath.Join(worktree, "new", "dir", "file.go"), true},
+		{Resolve(worktree, "../../../../beads/mayor/rig/README.md"), false},
+		{filepath.Join(town, "gastown", "polecats", "Toastie", "x"), false},
+		{filepath.Join(worktree, "escape", "README.md"), false},
+		{filepath.Join(town, "settings.json"), false},
+	}
+	for _, tt := range tests {
+		if got := s.Contains(tt.path); got != tt.want {
+			t.Errorf("Contains(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestBashTargets(t *testing.T) {
+	cwd := "/town/gastown/polecats/Toast/gastown"
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+	}{
+		{"redirect", "echo hi > ../../../../beads/notes.md", []string{"/town/beads/notes.md"}},
+		{"append quoted", `printf x >> "out file.txt"`, []string{cwd + "/out file.txt"}},
+		{"fd dup ignored", "go test ./... 2>&1 | tee /tmp/test.log", []string{"/tmp/test.log"}},
+		{"cd followed", "cd ../../../../beads && sed -i 's/a/b/' main.go", []string{"/town/beads/main.go"}},
+		{"cp dest", "cp -r internal /town/beads/internal", []string{"/town/beads/internal"}},
+		{"cp -t dest", "cp -t /town/other/ a.txt b.txt", []string{"/town/other"}},
+		{"mv target-directory", "mv --target-directory=../../../../beads a.txt", []string{"/town/beads"}},
+		{"cp clustered -t", "cp -rt /town/other internal", []string{"/town/other"}},
+		{"rm all", "rm -f a.txt /town/x.txt", []string{cwd + "/a.txt", "/town/x.txt"}},
+		{"chmod skips mode", "chmod +x scripts/run.sh", []string{cwd + "/scripts/run.sh"}},
+		{"git -C write", "git -C /town/beads/mayor/rig commit -am wip", []string{"/town/beads/mayor/rig"}},
+		{"git read", "git -C /town/beads/mayor/rig status", nil},
+		{"git here", "git add . && git commit -m 'fix'", []string{cwd, cwd}},
+		{"read only", "grep -rn foo ../.. | head -5", nil},
+		{"input redirect", "wc -l < /town/beads/file", nil},
+		{"heredoc body skipped", "cat <<'EOF' > notes.md\nrm -rf /town\nEOF\necho done", []string{cwd + "/notes.md"}},
+		{"unresolvable", "echo x > $OUT/file", nil},
+		{"env prefix", "FOO=1 touch ~/marker", []string{home(t) + "/marker"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BashTargets(cwd, tt.command)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("BashTargets(%q) = %q, want %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+func home(t *testing.T) string {
+	h, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	return h
+}
+
+func TestToolTargets(t *testing.T) {
+	cwd := "/town/gastown/polecats/Toast/gastown"
+	if got := ToolTargets(cwd, "Edit", json.RawMessage(`{"file_path":"../../Nux/gastown/x.go"}`)); !slices.Equal(got, []string{"/town/gastown/polecats/Nux/gastown/x.go"}) {
+		t.Errorf("Edit = %q", got)
+	}
+	if got := ToolTargets(cwd, "Read", json.RawMessage(`{"file_path":"/etc/passwd"}`)); got != nil {
+		t.Errorf("Read = %q, want nothing", got)
+	}
+}
+
+func TestScanTranscriptAndRecord(t *testing.T) {
+	town := t.TempDir()
+	s := ForPolecat(town, "gastown", "Toast")
+	worktree := filepath.Join(s.Root, "gastown")
+	lines := []string{
+		`{"type":"assistant","cwd":"` + worktree + `","timestamp":"2026-01-02T10:00:00Z","message":{"content":[{"type":"tool_use","name":"Write","input":{"file_path":"notes.md"}}]}}`,
+		`{"type":"assistant","cwd":"` + worktree + `","timestamp":"2026-01-02T10:01:00Z","message":{"content":[{"type":"tool_use","name":"Edit","input":{"file_path":"../../../../beads/mayor/rig/main.go"}}]}}`,
+		`{"type":"user","message":{"content":"hi"}}`,
+		`{"type":"assistant","timestamp":"2026-01-02T10:02:00Z","message":{"content":[{"type":"text","text":"done"},{"type":"tool_use","name":"Bash","input":{"command":"echo x > ` + town + `/settings.json"}}]}}`,
+	}
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	vs, err := ScanTranscript(path, s, worktree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vs) != 2 || vs[0].Tool != "Edit" || vs[1].Tool != "Bash" || vs[1].Detail == "" {
+		t.Fatalf("violations = %+v", vs)
+	}
+	if want := filepath.Join(town, "beads", "mayor", "rig", "main.go"); vs[0].Path != want {
+		t.Errorf("path = %s, want %s", vs[0].Path, want)
+	}
+
+	added, err := Record(town, vs)
+	if err != nil || len(added) != 2 {
+		t.Fatalf("first record: %d added, %v", len(added), err)
+	}
+	added, err = Record(town, vs)
+	if err != nil || len(added) != 0 {
+		t.Errorf("second record: %d added, %v; want duplicates dropped", len(added), err)
+	}
+	listed, _ := List(town, "", time.Date(2026, 1, 2, 10, 1, 30, 0, time.UTC))
+	if len(listed) != 1 || listed[0].Tool != "Bash" {
+		t.Errorf("List since = %+v", listed)
+	}
+}
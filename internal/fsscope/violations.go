@@ -0,0 +1,132 @@
+package fsscope
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// Where a violation was found.
+const (
+	SourceGuard      = "guard"      // Blocked before the tool ran
+	SourceTranscript = "transcript" // Found in the session transcript afterwards
+	SourceDiff       = "diff"       // Uncommitted change in a shared clone
+)
+
+// Violation is one out-of-scope write.
+type Violation struct {
+	Time    time.Time `json:"time"`
+	Source  string    `json:"source"`
+	Rig     string    `json:"rig"`
+	Polecat string    `json:"polecat,omitempty"` // Empty when the writer is unknown (diff)
+	Tool    string    `json:"tool,omitempty"`
+	Path    string    `json:"path"`
+	Detail  string    `json:"detail,omitempty"` // The command or clone the write was found in
+}
+
+// Key identifies the violation, so the same write found twice is recorded once.
+func (v Violation) Key() string {
+	return strings.Join([]string{v.Source, v.Rig, v.Polecat, v.Tool, v.Path, v.Time.UTC().Format(time.RFC3339Nano)}, "\x00")
+}
+
+// Dir returns the directory holding the violation log.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "scope")
+}
+
+func logPath(townRoot string) string { return filepath.Join(Dir(townRoot), "violations.jsonl") }
+
+// Record appends the violations not already in the log and returns them.
+// An audit that finds the same write again does not report it twice.
+func Record(townRoot string, vs []Violation) ([]Violation, error) {
+	path := logPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating scope directory: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return nil, fmt.Errorf("locking scope log: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	existing, err := readViolations(path)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, v := range existing {
+		seen[v.Key()] = true
+	}
+	var added []Violation
+	var b strings.Builder
+	for _, v := range vs {
+		v.Time = v.Time.UTC()
+		if seen[v.Key()] {
+			continue
+		}
+		seen[v.Key()] = true
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+		added = append(added, v)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: runtime state
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if _, err := f.WriteString(b.String()); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// List returns recorded violations at or after since, newest first. A
+// non-empty rig limits the list to that rig.
+func List(townRoot, rig string, since time.Time) ([]Violation, error) {
+	vs, err := readViolations(logPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Violation, 0, len(vs))
+	for i := len(vs) - 1; i >= 0; i-- {
+		if (rig == "" || vs[i].Rig == rig) && !vs[i].Time.Before(since) {
+			out = append(out, vs[i])
+		}
+	}
+	return out, nil
+}
+
+func readViolations(path string) ([]Violation, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var vs []Violation
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var v Violation
+		if err := json.Unmarshal(scanner.Bytes(), &v); err == nil {
+			vs = append(vs, v)
+		}
+	}
+	return vs, scanner.Err()
+}
@@ -630,6 +630,12 @@ func (g *Git) SetRemoteURL(name, url string) (string, error) {
 	return g.run("remote", "set-url", name, url)
 }
 
+// RemoveRemote removes a remote and its remote-tracking branches.
+func (g *Git) RemoveRemote(name string) error {
+	_, err := g.run("remote", "remove", name)
+	return err
+}
+
 // AddUpstreamRemote adds or updates the 'upstream' git remote.
 // This is idempotent - if the remote already exists with the same URL, it's a no-op.
 // If the remote exists with a different URL, it's updated.
@@ -1042,6 +1048,11 @@ func (g *Git) Rev(ref string) (string, error) {
 	return g.run("rev-parse", ref)
 }
 
+// MergeBase returns the best common ancestor of two refs.
+func (g *Git) MergeBase(a, b string) (string, error) {
+	return g.run("merge-base", a, b)
+}
+
 // IsAncestor checks if ancestor is an ancestor of descendant.
 func (g *Git) IsAncestor(ancestor, descendant string) (bool, error) {
 	_, err := g.run("merge-base", "--is-ancestor", ancestor, descendant)
@@ -1083,6 +1094,23 @@ func (g *Git) WorktreeAddFromRef(path, branch, startPoint string) error {
 	return InitSubmodules(path, g.submoduleReferencePath())
 }
 
+// WorktreeAddSparseFromRef is WorktreeAddFromRef with a cone-mode sparse
+// checkout of paths. The worktree is created without a checkout and only
+// populated once the sparse paths are set, so files outside them are never
+// written to disk.
+func (g *Git) WorktreeAddSparseFromRef(path, branch, startPoint string, paths []string) error {
+	if _, err := g.run("worktree", "add", "--no-checkout", "-b", branch, path, startPoint); err != nil {
+		return err
+	}
+	if err := InitSparseCheckout(path, paths); err != nil {
+		return err
+	}
+	if _, err := NewGit(path).runWithEnv([]string{"checkout"}, []string{"GIT_LFS_SKIP_SMUDGE=1"}); err != nil {
+		return fmt.Errorf("populating sparse worktree: %w", err)
+	}
+	return InitSubmodules(path, g.submoduleReferencePath())
+}
+
 // WorktreeAddDetached creates a new worktree at the given path with a detached HEAD.
 // Skips LFS smudge filter during checkout (see WorktreeAddFromRef).
 func (g *Git) WorktreeAddDetached(path, ref string) error {
@@ -1180,6 +1208,15 @@ func IsSparseCheckoutConfigured(repoPath string) bool {
 	return err == nil && strings.TrimSpace(string(output)) == "true"
 }
 
+// IsSparseCheckoutCone reports whether a repo/worktree uses cone-mode sparse
+// checkout, which gt configures deliberately (rig add --sparse-checkout,
+// sparse worktree profiles). The legacy .claude/ exclusion used patterns.
+func IsSparseCheckoutCone(repoPath string) bool {
+	cmd := exec.Command("git", "-C", repoPath, "config", "core.sparseCheckoutCone")
+	output, err := cmd.Output()
+	return err == nil && strings.TrimSpace(string(output)) == "true"
+}
+
 // RemoveSparseCheckout disables sparse checkout for a repo/worktree and restores all files.
 // This is used by doctor to clean up legacy sparse checkout configurations.
 func RemoveSparseCheckout(repoPath string) error {
@@ -1211,6 +1248,14 @@ func (g *Git) WorktreeMove(oldPath, newPath string) error {
 	return err
 }
 
+// WorktreeRepair reconnects worktrees with the repository after either was
+// moved without git (e.g. the directory holding both was renamed). paths are
+// the worktrees' new locations.
+func (g *Git) WorktreeRepair(paths ...string) error {
+	_, err := g.run(append([]string{"worktree", "repair"}, paths...)...)
+	return err
+}
+
 // WorktreePrune removes worktree entries for deleted paths.
 func (g *Git) WorktreePrune() error {
 	_, err := g.run("worktree", "prune")
@@ -1317,6 +1362,84 @@ func (g *Git) CommitsAhead(base, branch string) (int, error) {
 	return count, nil
 }
 
+// DiffStat returns "git diff --stat" output for the changes on branch since it
+// diverged from base (three-dot range), e.g. DiffStat("origin/main", "HEAD").
+func (g *Git) DiffStat(base, branch string) (string, error) {
+	return g.run("diff", "--stat", base+"..."+branch)
+}
+
+// DiffNameOnly returns the files changed on branch since it diverged from
+// base, repo-relative and slash-separated.
+func (g *Git) DiffNameOnly(base, branch string) ([]string, error) {
+	out, err := g.run("diff", "--name-only", base+"..."+branch)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// ShowFile returns the contents of a repo-relative path as of ref.
+func (g *Git) ShowFile(ref, path string) ([]byte, error) {
+	out, err := g.run("show", ref+":"+path)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(out), nil
+}
+
+// CommitSubjects returns the subject lines of commits on branch that are not
+// on base, oldest first.
+func (g *Git) CommitSubjects(base, branch string) ([]string, error) {
+	out, err := g.run("log", "--reverse", "--format=%s", base+".."+branch)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// LoggedCommit is a commit as reported by CommitLog.
+type LoggedCommit struct {
+	SHA       string
+	Message   string // Full message, trailers included
+	Signature string // git's %G? status: G good, U good but untrusted, N unsigned, B bad, ...
+	Signer    string
+}
+
+// CommitLog returns the commits on branch that are not on base, oldest first,
+// with their signature status. Each config entry ("key=value") is passed to
+// git with -c, e.g. to name the SSH allowed-signers file.
+func (g *Git) CommitLog(base, branch string, config ...string) ([]LoggedCommit, error) {
+	var args []string
+	for _, c := range config {
+		args = append(args, "-c", c)
+	}
+	args = append(args, "log", "--reverse", "--format=%H%x1f%G?%x1f%GS%x1f%B%x1e", base+".."+branch)
+	out, err := g.run(args...)
+	if err != nil {
+		return nil, err
+	}
+	var commits []LoggedCommit
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		commits = append(commits, LoggedCommit{
+			SHA:       fields[0],
+			Signature: fields[1],
+			Signer:    fields[2],
+			Message:   strings.TrimSpace(fields[3]),
+		})
+	}
+	return commits, nil
+}
+
 // CountCommitsBehind returns the number of commits that HEAD is behind the given ref.
 // For example, CountCommitsBehind("origin/main") returns how many commits
 // are on origin/main that are not on the current HEAD.
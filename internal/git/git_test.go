@@ -1758,3 +1758,102 @@ func TestCheckBranchContamination(t *testing.T) {
 		t.Errorf("Ahead (from main) = %d, want 5", contam.Ahead)
 	}
 }
+
+func TestDiffStatAndCommitSubjects(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+
+	base, err := g.CurrentBranch()
+	if err != nil {
+		t.Fatalf("CurrentBranch: %v", err)
+	}
+	if err := g.CreateBranch("feature"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := g.Checkout("feature"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+
+	for i, name := range []string{"a.go", "b.go"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("package x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Add(name); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		if err := g.Commit([]string{"add a", "add b"}[i]); err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+	}
+
+	subjects, err := g.CommitSubjects(base, "feature")
+	if err != nil {
+		t.Fatalf("CommitSubjects: %v", err)
+	}
+	if strings.Join(subjects, ",") != "add a,add b" {
+		t.Errorf("CommitSubjects = %v, want [add a add b]", subjects)
+	}
+
+	stat, err := g.DiffStat(base, "feature")
+	if err != nil {
+		t.Fatalf("DiffStat: %v", err)
+	}
+	if !strings.Contains(stat, "a.go") || !strings.Contains(stat, "2 files changed") {
+		t.Errorf("DiffStat = %q, want both files listed", stat)
+	}
+
+	files, err := g.DiffNameOnly(base, "feature")
+	if err != nil {
+		t.Fatalf("DiffNameOnly: %v", err)
+	}
+	if strings.Join(files, ",") != "a.go,b.go" {
+		t.Errorf("DiffNameOnly = %v, want [a.go b.go]", files)
+	}
+
+	none, err := g.CommitSubjects("feature", "feature")
+	if err != nil || len(none) != 0 {
+		t.Errorf("CommitSubjects on empty range = %v, %v", none, err)
+	}
+}
+
+func TestWorktreeAddSparseFromRef(t *testing.T) {
+	dir := initTestRepo(t)
+	g := NewGit(dir)
+	for _, name := range []string{"app/main.go", "docs/guide.md"} {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Add(name); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	if err := g.Commit("add app and docs"); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	wt := filepath.Join(t.TempDir(), "wt")
+	if err := g.WorktreeAddSparseFromRef(wt, "sparse-branch", "HEAD", []string{"app"}); err != nil {
+		t.Fatalf("WorktreeAddSparseFromRef: %v", err)
+	}
+	for _, name := range []string{"README.md", "app/main.go"} {
+		if _, err := os.Stat(filepath.Join(wt, name)); err != nil {
+			t.Errorf("%s not checked out: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(wt, "docs")); !os.IsNotExist(err) {
+		t.Errorf("docs/ should be outside the sparse checkout, stat err = %v", err)
+	}
+	if !IsSparseCheckoutCone(wt) {
+		t.Error("expected cone-mode sparse checkout")
+	}
+	wtGit := NewGit(wt)
+	if branch, _ := wtGit.CurrentBranch(); branch != "sparse-branch" {
+		t.Errorf("branch = %q, want sparse-branch", branch)
+	}
+	if status, err := wtGit.Status(); err != nil || !status.Clean {
+		t.Errorf("sparse worktree should be clean: %+v, %v", status, err)
+	}
+}
@@ -223,6 +223,12 @@ func DefaultOverrides() map[string]*HooksConfig {
 				},
 			},
 		},
+		// Polecats: write-scope guard. A polecat may only write inside its
+		// own directory; writes to sibling polecats, other rigs or the town
+		// root are blocked and recorded for gt witness scope.
+		"polecats": {
+			PreToolUse: scopeGuardEntries(pathSetup),
+		},
 		// Witness roles: patrol-formula-guard (gt-e47hxn).
 		// Blocks patrol formulas from using persistent molecules — must use wisps.
 		// Without this, witnesses could accidentally create permanent patrol molecules
@@ -330,6 +336,21 @@ func DefaultOverrides() map[string]*HooksConfig {
 	}
 }
 
+// scopeGuardEntries runs gt tap guard scope before every tool that writes files.
+func scopeGuardEntries(pathSetup string) []HookEntry {
+	var entries []HookEntry
+	for _, tool := range []string{"Write", "Edit", "MultiEdit", "NotebookEdit", "Bash"} {
+		entries = append(entries, HookEntry{
+			Matcher: tool,
+			Hooks: []Hook{{
+				Type:    "command",
+				Command: fmt.Sprintf("%s && gt tap guard scope", pathSetup),
+			}},
+		})
+	}
+	return entries
+}
+
 // ComputeExpected computes the expected HooksConfig for a target by loading
 // the base config and applying all applicable overrides in order of specificity.
 // If no base config exists, uses DefaultBase().
@@ -577,6 +577,25 @@ func TestComputeExpectedNoBase(t *testing.T) {
 		t.Error("expected crew to inherit SessionStart from DefaultBase")
 	}
 
+	// Polecats get the write-scope guard on every file-writing tool
+	polecats, err := ComputeExpected("gastown/polecats")
+	if err != nil {
+		t.Fatalf("ComputeExpected(gastown/polecats) failed: %v", err)
+	}
+	scopeGuarded := map[string]bool{}
+	for _, entry := range polecats.PreToolUse {
+		for _, h := range entry.Hooks {
+			if strings.HasSuffix(h.Command, "gt tap guard scope") {
+				scopeGuarded[entry.Matcher] = true
+			}
+		}
+	}
+	for _, tool := range []string{"Write", "Edit", "MultiEdit", "NotebookEdit", "Bash"} {
+		if !scopeGuarded[tool] {
+			t.Errorf("expected polecats to guard %s with gt tap guard scope", tool)
+		}
+	}
+
 	// Witness should get DefaultBase + built-in patrol-formula-guard (gt-e47hxn)
 	witness, err := ComputeExpected("witness")
 	if err != nil {
@@ -0,0 +1,256 @@
+// Package i18n lets a town run gt in its own language without forking
+// gt's prompts.
+//
+// Two kinds of text are localized:
+//
+//   - Messages: short strings gt prints to operators or injects into agent
+//     sessions (startup beacons, error hints). Each is declared once with New,
+//     carrying its English text, and looked up by key in the town's
+//     translation file.
+//   - Templates: the role briefings gt prime renders and the message
+//     templates. A translated copy of an embedded template replaces it.
+//
+// Translations live in the town, under settings/locales/<locale>/:
+//
+//	messages.json           {"beacon.prime": "`%s prime` を実行して...", ...}
+//	roles/polecat.md.tmpl   replaces the embedded polecat briefing
+//	messages/nudge.md.tmpl  replaces the embedded nudge message
+//
+// Anything without a translation falls back to English, so a locale can be
+// translated a piece at a time. gt locale export writes the English
+// originals there as a starting point.
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is gt's own language.
+const DefaultLocale = "en"
+
+// EnvLocale overrides the town's locale for one process.
+const EnvLocale = "GT_LOCALE"
+
+// MessagesFile is the translation file in a locale directory.
+const MessagesFile = "messages.json"
+
+// Msg is a localizable message: a stable key and its English text, which
+// may hold fmt verbs.
+type Msg struct {
+	Key     string
+	Default string
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]string{} // Key -> English, for export
+	active   = state{locale: DefaultLocale}
+)
+
+type state struct {
+	locale string
+	dir    string            // Locale directory; "" when none is in use
+	texts  map[string]string // Key -> translation
+}
+
+// New declares a message. Keys are dotted and unique across gt
+// ("beacon.prime"); declare messages as package-level variables.
+func New(key, english string) Msg {
+	mu.Lock()
+	defer mu.Unlock()
+	if prev, dup := registry[key]; dup && prev != english {
+		panic(fmt.Sprintf("i18n: message %q declared twice", key))
+	}
+	registry[key] = english
+	return Msg{Key: key, Default: english}
+}
+
+// T returns the message in the active locale, formatted with args.
+func (m Msg) T(args ...any) string {
+	return Text(m.Key, m.Default, args...)
+}
+
+// Text returns key's translation in the active locale, or def, formatted
+// with args. Use it for messages whose keys are built at run time, and
+// declare those keys with New so they are exported.
+func Text(key, def string, args ...any) string {
+	mu.RLock()
+	text, ok := active.texts[key]
+	mu.RUnlock()
+	if !ok || text == "" {
+		text = def
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// Valid reports whether tag looks like a language tag ("ja", "pt-BR").
+// Tags name directories, so nothing else is accepted.
+func Valid(tag string) bool {
+	return localeTag.MatchString(tag)
+}
+
+var localeTag = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// Dir returns a town's directory for locale.
+func Dir(townRoot, locale string) string {
+	return filepath.Join(townRoot, "settings", "locales", locale)
+}
+
+// Resolve picks the locale: GT_LOCALE, then the town's setting, then
+// English.
+func Resolve(configured string) string {
+	if env := strings.TrimSpace(os.Getenv(EnvLocale)); Valid(env) {
+		return env
+	}
+	if Valid(configured) {
+		return configured
+	}
+	return DefaultLocale
+}
+
+// Use makes locale active, loading the town's translations for it. A
+// locale with no directory is active with English text; a malformed
+// translation file is an error and leaves English active.
+func Use(townRoot, locale string) error {
+	next := state{locale: locale}
+	if locale != DefaultLocale && townRoot != "" {
+		dir := Dir(townRoot, locale)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			texts, err := loadMessages(filepath.Join(dir, MessagesFile))
+			if err != nil {
+				return err
+			}
+			next.dir, next.texts = dir, texts
+		}
+	}
+	mu.Lock()
+	active = next
+	mu.Unlock()
+	return nil
+}
+
+// Reset makes English active again.
+func Reset() {
+	mu.Lock()
+	active = state{locale: DefaultLocale}
+	mu.Unlock()
+}
+
+// Locale returns the active locale.
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return active.locale
+}
+
+// ActiveDir returns the active locale's directory, or "" when no
+// translations are in use.
+func ActiveDir() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return active.dir
+}
+
+// Translated returns how many declared messages the active locale
+// translates.
+func Translated() int {
+	mu.RLock()
+	defer mu.RUnlock()
+	n := 0
+	for key := range registry {
+		if active.texts[key] != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func loadMessages(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is under the town's settings
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var texts map[string]string
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return texts, nil
+}
+
+// Messages returns every declared message key with its English text.
+func Messages() map[string]string {
+	mu.RLock()
+	defer mu.RUnlock()
+	out := make(map[string]string, len(registry))
+	for k, v := range registry {
+		out[k] = v
+	}
+	return out
+}
+
+// Export writes every declared message to locale's messages.json in the
+// town, keeping translations already there. Keys gt no longer declares
+// are dropped. It returns how many messages still need translating.
+func Export(townRoot, locale string) (untranslated int, err error) {
+	dir := Dir(townRoot, locale)
+	path := filepath.Join(dir, MessagesFile)
+	existing, err := loadMessages(path)
+	if err != nil {
+		return 0, err
+	}
+	out := Messages()
+	for key := range out {
+		if t, ok := existing[key]; ok && t != "" {
+			out[key] = t
+		} else {
+			untranslated++
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return 0, err
+	}
+	return untranslated, os.WriteFile(path, marshalSorted(out), 0644) //nolint:gosec // G306: translations are not secret
+}
+
+// marshalSorted writes one key per line in key order, so translation files
+// diff cleanly.
+func marshalSorted(m map[string]string) []byte {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString("{\n")
+	for i, k := range keys {
+		fmt.Fprintf(&b, "  %s: %s", jsonString(k), jsonString(m[k]))
+		if i < len(keys)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}\n")
+	return []byte(b.String())
+}
+
+// jsonString quotes s for JSON, leaving <, > and & readable.
+func jsonString(s string) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,134 @@
+package i18n
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var (
+	testGreeting = New("test.greeting", "Hello, %s.")
+	testFarewell = New("test.farewell", "Goodbye.")
+)
+
+func writeMessages(t *testing.T, town, locale, body string) {
+	t.Helper()
+	dir := Dir(town, locale)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, MessagesFile), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUse_TranslatesAndFallsBack(t *testing.T) {
+	t.Cleanup(Reset)
+	town := t.TempDir()
+	writeMessages(t, town, "ja", `{"test.greeting": "こんにちは、%sさん。"}`)
+
+	if err := Use(town, "ja"); err != nil {
+		t.Fatal(err)
+	}
+	if got := testGreeting.T("toast"); got != "こんにちは、toastさん。" {
+		t.Errorf("greeting = %q", got)
+	}
+	if got := testFarewell.T(); got != "Goodbye." {
+		t.Errorf("untranslated message = %q, want English", got)
+	}
+	if Locale() != "ja" || ActiveDir() != Dir(town, "ja") {
+		t.Errorf("active = %q in %q", Locale(), ActiveDir())
+	}
+
+	Reset()
+	if got := testGreeting.T("toast"); got != "Hello, toast." {
+		t.Errorf("after Reset = %q", got)
+	}
+}
+
+func TestUse_NoLocaleDir(t *testing.T) {
+	t.Cleanup(Reset)
+	if err := Use(t.TempDir(), "fr"); err != nil {
+		t.Fatal(err)
+	}
+	if Locale() != "fr" || ActiveDir() != "" {
+		t.Errorf("active = %q in %q", Locale(), ActiveDir())
+	}
+	if got := testFarewell.T(); got != "Goodbye." {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestUse_MalformedKeepsEnglish(t *testing.T) {
+	t.Cleanup(Reset)
+	town := t.TempDir()
+	writeMessages(t, town, "ja", `{not json`)
+	if err := Use(town, "ja"); err == nil {
+		t.Fatal("expected error for malformed messages.json")
+	}
+	if Locale() != DefaultLocale {
+		t.Errorf("locale = %q, want %q", Locale(), DefaultLocale)
+	}
+}
+
+func TestExport_KeepsTranslations(t *testing.T) {
+	town := t.TempDir()
+	writeMessages(t, town, "ja", `{"test.greeting": "こんにちは、%sさん。", "test.removed": "古い"}`)
+
+	untranslated, err := Export(town, "ja")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := len(Messages()) - 1; untranslated != want {
+		t.Errorf("untranslated = %d, want %d", untranslated, want)
+	}
+
+	data, err := os.ReadFile(filepath.Join(Dir(town, "ja"), MessagesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("exported file isn't JSON: %v\n%s", err, data)
+	}
+	if got["test.greeting"] != "こんにちは、%sさん。" {
+		t.Errorf("translation lost: %q", got["test.greeting"])
+	}
+	if got["test.farewell"] != "Goodbye." {
+		t.Errorf("new message = %q, want English", got["test.farewell"])
+	}
+	if _, ok := got["test.removed"]; ok {
+		t.Error("undeclared key kept")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv(EnvLocale, "")
+	if got := Resolve("ja"); got != "ja" {
+		t.Errorf("Resolve(ja) = %q", got)
+	}
+	if got := Resolve(""); got != DefaultLocale {
+		t.Errorf("Resolve(\"\") = %q", got)
+	}
+	if got := Resolve("../etc"); got != DefaultLocale {
+		t.Errorf("Resolve(../etc) = %q", got)
+	}
+	t.Setenv(EnvLocale, "pt-BR")
+	if got := Resolve("ja"); got != "pt-BR" {
+		t.Errorf("GT_LOCALE ignored: %q", got)
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, tag := range []string{"en", "ja", "pt-BR", "zh-Hant-TW"} {
+		if !Valid(tag) {
+			t.Errorf("Valid(%q) = false", tag)
+		}
+	}
+	for _, tag := range []string{"", "j", "japanese", "ja/..", "../ja", "ja_JP"} {
+		if Valid(tag) {
+			t.Errorf("Valid(%q) = true", tag)
+		}
+	}
+}
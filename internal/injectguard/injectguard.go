@@ -0,0 +1,168 @@
+// Package injectguard scans material from outside the town for
+// prompt-injection and other hostile content before it reaches an agent
+// session, and keeps suspicious material in quarantine until a human
+// reviews it.
+//
+// Outside material is anything an agent would read that its author never
+// had to be trusted to write: beads created by inbound webhooks (GitHub
+// issues, error reports), beads being slung to an agent, and mail sent
+// from outside an agent session. A bead in quarantine carries the
+// gt:quarantined label and cannot be slung; held mail is not delivered.
+// gt quarantine lists what is held and releases or rejects it.
+//
+// The guard is on by default. Configured in town settings:
+//
+//	"content_guard": {
+//	  "warn_only": false,
+//	  "patterns": ["(?i)wire the funds"]
+//	}
+package injectguard
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Bead labels.
+const (
+	// LabelQuarantined marks a bead held for review. Sling refuses it.
+	LabelQuarantined = "gt:quarantined"
+
+	// LabelReviewed marks a bead a human released from quarantine, so it
+	// is not scanned (and quarantined) again.
+	LabelReviewed = "gt:content-reviewed"
+)
+
+// maxExcerpt bounds the matched text kept with a finding.
+const maxExcerpt = 80
+
+// Config is the town's content guard settings. A nil config is the
+// default: on, built-in patterns only.
+type Config struct {
+	// Disabled turns scanning off entirely.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// WarnOnly reports suspicious material without quarantining it.
+	WarnOnly bool `json:"warn_only,omitempty"`
+
+	// Patterns are extra regular expressions that mark material as
+	// suspicious, in addition to the built-in ones.
+	Patterns []string `json:"patterns,omitempty"`
+}
+
+// IsEnabled reports whether scanning is on.
+func (c *Config) IsEnabled() bool {
+	return c == nil || !c.Disabled
+}
+
+// Quarantines reports whether suspicious material is held rather than
+// only reported.
+func (c *Config) Quarantines() bool {
+	return c.IsEnabled() && (c == nil || !c.WarnOnly)
+}
+
+// Validate checks that the extra patterns compile. A nil config is valid.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	for _, p := range c.Patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("patterns: %q: %v", p, err)
+		}
+	}
+	return nil
+}
+
+// rule is a named pattern for one kind of hostile content.
+type rule struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// rules are the built-in patterns. They look for text addressed to the
+// model rather than to a human reader: attempts to replace its
+// instructions, fake conversation turns, requests to leak secrets or run
+// fetched code, and text hidden from human reviewers.
+var rules = []rule{
+	{"ignore-instructions", regexp.MustCompile(`(?is)\b(ignore|disregard|forget|override)\b.{0,30}\b(previous|prior|above|earlier|preceding|system|your|all)\b.{0,30}\b(instructions?|prompts?|directives|guidelines|guardrails)\b`)},
+	{"role-override", regexp.MustCompile(`(?i)\b(you are now|from now on,? you (are|will)|act as an? (unrestricted|unfiltered|jailbroken)|developer mode|jailbreak|DAN mode)\b`)},
+	{"prompt-leak", regexp.MustCompile(`(?is)\b(reveal|print|show|repeat|output|dump)\b.{0,30}\b(system prompt|your (instructions|prompt)|hidden instructions|CLAUDE\.md)`)},
+	{"fake-turn", regexp.MustCompile(`(?im)(^\s*(human|assistant)\s*:\s*\S|</?(system|system-reminder|assistant|user|instructions?|tool_result|function_results)>|\[/?INST\]|<\|im_(start|end)\|>)`)},
+	{"exfiltration", regexp.MustCompile(`(?is)\b(send|post|upload|exfiltrate|leak|email|paste)\b.{0,60}\b(api[ _-]?keys?|access tokens?|secrets?|credentials|passwords?|\.env\b|ssh keys?|env(ironment)? var(iable)?s?)`)},
+	{"remote-exec", regexp.MustCompile(`(?i)\b(curl|wget)\b[^\n|]{0,200}\|\s*(sudo\s+)?(ba|z)?sh\b`)},
+	{"hidden-text", regexp.MustCompile(`[\x{200B}-\x{200F}\x{202A}-\x{202E}\x{2060}-\x{2064}\x{2066}-\x{2069}\x{E0000}-\x{E007F}]`)},
+	{"hidden-comment", regexp.MustCompile(`(?is)<!--.{0,1000}?(\b(ai|llm|agents?|assistants?|claude|copilot|codex|gemini)\s*[:,]|\byou (must|should|need to|will) (now |also )?(run|execute|add|remove|delete|push|commit|send|ignore)\b).{0,1000}?-->`)},
+}
+
+// Finding is one match of a pattern.
+type Finding struct {
+	Rule    string `json:"rule"`
+	Excerpt string `json:"excerpt"`
+}
+
+// Scan checks text against the built-in patterns and cfg's extra ones and
+// returns what matched. Nothing is found when the guard is disabled.
+// Invalid extra patterns are skipped (Validate reports them).
+func Scan(cfg *Config, text string) []Finding {
+	if !cfg.IsEnabled() || text == "" {
+		return nil
+	}
+	var findings []Finding
+	for _, r := range rules {
+		if loc := r.re.FindStringIndex(text); loc != nil {
+			findings = append(findings, Finding{Rule: r.name, Excerpt: excerpt(text, loc)})
+		}
+	}
+	if cfg != nil {
+		for _, p := range cfg.Patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				continue
+			}
+			if loc := re.FindStringIndex(text); loc != nil {
+				findings = append(findings, Finding{Rule: "custom", Excerpt: excerpt(text, loc)})
+			}
+		}
+	}
+	return findings
+}
+
+// Summary describes findings in one line, e.g. "ignore-instructions, fake-turn".
+func Summary(findings []Finding) string {
+	names := make([]string, 0, len(findings))
+	for _, f := range findings {
+		names = append(names, f.Rule)
+	}
+	return strings.Join(names, ", ")
+}
+
+// excerpt returns the matched text, with invisible characters made visible
+// and long matches shortened, so a reviewer sees what tripped the rule.
+func excerpt(text string, loc []int) string {
+	s := text[loc[0]:loc[1]]
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteByte(' ')
+		case isInvisible(r):
+			fmt.Fprintf(&b, "<U+%04X>", r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	out := b.String()
+	if utf8.RuneCountInString(out) > maxExcerpt {
+		out = string([]rune(out)[:maxExcerpt-1]) + "…"
+	}
+	return out
+}
+
+func isInvisible(r rune) bool {
+	return (r >= 0x200B && r <= 0x200F) || (r >= 0x202A && r <= 0x202E) ||
+		(r >= 0x2060 && r <= 0x2064) || (r >= 0x2066 && r <= 0x2069) ||
+		(r >= 0xE0000 && r <= 0xE007F)
+}
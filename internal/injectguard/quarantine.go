@@ -0,0 +1,200 @@
+package injectguard
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// Kinds of quarantined material.
+const (
+	KindBead = "bead"
+	KindMail = "mail"
+)
+
+// Quarantine statuses.
+const (
+	StatusHeld     = "held"
+	StatusReleased = "released"
+	StatusRejected = "rejected"
+)
+
+// Item is one piece of material held for review.
+type Item struct {
+	ID       string    `json:"id"`
+	Kind     string    `json:"kind"`
+	Source   string    `json:"source"` // Where it came from, e.g. "webhook:github", "sling", "mail"
+	Title    string    `json:"title"`
+	Findings []Finding `json:"findings"`
+	Time     time.Time `json:"time"`
+	Status   string    `json:"status"`
+
+	// BeadID is the quarantined bead (bead items).
+	BeadID string `json:"bead_id,omitempty"`
+	// Rig is where the bead was being slung, so release can sling it.
+	Rig string `json:"rig,omitempty"`
+	// Message is the held mail, delivered on release (mail items).
+	Message json.RawMessage `json:"message,omitempty"`
+
+	ReviewedAt time.Time `json:"reviewed_at,omitempty"`
+	ReviewedBy string    `json:"reviewed_by,omitempty"`
+}
+
+// Dir returns the directory holding the quarantine log.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "quarantine")
+}
+
+func logPath(townRoot string) string { return filepath.Join(Dir(townRoot), "items.jsonl") }
+
+// Hold adds item to quarantine and returns it with its ID, time and status
+// set. A bead already held is not added twice.
+func Hold(townRoot string, item Item, now time.Time) (*Item, error) {
+	var held *Item
+	err := withLock(townRoot, func(path string) error {
+		items, err := readItems(path)
+		if err != nil {
+			return err
+		}
+		if item.BeadID != "" {
+			for i := range items {
+				if items[i].BeadID == item.BeadID && items[i].Status == StatusHeld {
+					held = &items[i]
+					return nil
+				}
+			}
+		}
+		item.ID = newID()
+		item.Time = now.UTC()
+		item.Status = StatusHeld
+		held = &item
+		return writeItems(path, append(items, item))
+	})
+	return held, err
+}
+
+// List returns quarantined items, newest first. A non-empty status limits
+// the list to items with that status.
+func List(townRoot, status string) ([]Item, error) {
+	items, err := readItems(logPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Item, 0, len(items))
+	for i := len(items) - 1; i >= 0; i-- {
+		if status == "" || items[i].Status == status {
+			out = append(out, items[i])
+		}
+	}
+	return out, nil
+}
+
+// Get returns the item with the given ID, or the held item for a bead ID.
+func Get(townRoot, id string) (*Item, error) {
+	items, err := readItems(logPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	for i := len(items) - 1; i >= 0; i-- {
+		if items[i].ID == id || (items[i].BeadID == id && items[i].Status == StatusHeld) {
+			return &items[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no quarantined item %q", id)
+}
+
+// Review marks a held item released or rejected.
+func Review(townRoot, id, status, reviewer string, now time.Time) (*Item, error) {
+	if status != StatusReleased && status != StatusRejected {
+		return nil, fmt.Errorf("invalid review status %q", status)
+	}
+	var reviewed *Item
+	err := withLock(townRoot, func(path string) error {
+		items, err := readItems(path)
+		if err != nil {
+			return err
+		}
+		for i := len(items) - 1; i >= 0; i-- {
+			it := &items[i]
+			if it.ID != id && (it.BeadID != id || it.Status != StatusHeld) {
+				continue
+			}
+			if it.Status != StatusHeld {
+				return fmt.Errorf("%s was already %s", it.ID, it.Status)
+			}
+			it.Status = status
+			it.ReviewedAt = now.UTC()
+			it.ReviewedBy = reviewer
+			reviewed = it
+			return writeItems(path, items)
+		}
+		return fmt.Errorf("no quarantined item %q", id)
+	})
+	return reviewed, err
+}
+
+func newID() string {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return "q-" + hex.EncodeToString(b[:])
+}
+
+func withLock(townRoot string, fn func(path string) error) error {
+	path := logPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating quarantine directory: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking quarantine: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+	return fn(path)
+}
+
+func readItems(path string) ([]Item, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var items []Item
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var it Item
+		if err := json.Unmarshal(scanner.Bytes(), &it); err == nil {
+			items = append(items, it)
+		}
+	}
+	return items, scanner.Err()
+}
+
+func writeItems(path string, items []Item) error {
+	var b strings.Builder
+	for _, it := range items {
+		data, err := json.Marshal(it)
+		if err != nil {
+			return err
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil { //nolint:gosec // G306: runtime state
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,152 @@
+// Package invariant cross-checks the town's live state sources against each
+// other. Agent sessions, polecat and crew worktrees, hooked beads and the
+// scheduler queue each hold part of the picture of who is doing what, and
+// nothing forces them to agree: a session killed mid-sling, a worktree
+// removed by hand or a crashed dispatch leaves them disagreeing until
+// something misbehaves.
+//
+// Check takes a Snapshot the caller gathered and reports each disagreement
+// as a Violation. Violations with one safe fix carry a Repair; the rest
+// need a human (or the witness or deacon, which own those recoveries).
+package invariant
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Snapshot is the town's state, gathered by the caller. Agents are keyed
+// by address ("gastown/polecats/toast", "gastown/crew/max", "mayor"). Hooked
+// assignees may carry a trailing slash ("mayor/").
+type Snapshot struct {
+	Rigs      map[string]bool   // Registered rigs
+	Sessions  map[string]string // Agent address -> tmux session, for live sessions
+	Worktrees map[string]bool   // Polecat and crew addresses with a worktree
+	Hooked    map[string]string // Hooked bead -> assignee
+	Queued    map[string]string // Queued bead -> target rig
+	Closed    map[string]bool   // Queued beads that are closed
+}
+
+// Violation kinds.
+const (
+	KindOrphanedHook = "orphaned-hook" // Hooked to a polecat or crew member with no worktree
+	KindUnknownAgent = "unknown-agent" // Hooked to an address that names no agent
+	KindDeadHook     = "dead-hook"     // Hooked to an agent with no session
+	KindDoubleHook   = "double-hook"   // One agent holds more than one hooked bead
+	KindStraySession = "stray-session" // A polecat session with no worktree
+	KindQueuedHooked = "queued-hooked" // Queued bead already hooked
+	KindQueuedClosed = "queued-closed" // Queued bead already closed
+	KindQueuedNoRig  = "queued-no-rig" // Queued for a rig that isn't registered
+)
+
+// Repairs a violation can be fixed with.
+const (
+	RepairNone    = ""
+	RepairRelease = "release" // Reopen the bead and clear its assignee
+	RepairKill    = "kill"    // Kill the session
+	RepairDequeue = "dequeue" // Close the bead's sling context
+)
+
+// Violation is one disagreement between state sources.
+type Violation struct {
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"` // Bead ID or session name
+	Detail  string `json:"detail"`
+	Repair  string `json:"repair,omitempty"`
+}
+
+// Repairable reports whether the violation has a safe fix.
+func (v Violation) Repairable() bool { return v.Repair != RepairNone }
+
+// Check reports every violation in s, grouped by kind and sorted by
+// subject within each kind.
+func Check(s *Snapshot) []Violation {
+	var out []Violation
+
+	held := map[string][]string{}
+	for _, bead := range sortedKeys(s.Hooked) {
+		assignee := s.Hooked[bead]
+		held[assignee] = append(held[assignee], bead)
+		rig, role, town := splitAddress(assignee)
+		switch {
+		case assignee == "":
+			out = append(out, Violation{Kind: KindUnknownAgent, Subject: bead, Detail: "hooked with no assignee", Repair: RepairRelease})
+		case !town && rig == "":
+			out = append(out, Violation{Kind: KindUnknownAgent, Subject: bead, Detail: fmt.Sprintf("hooked to %s, which isn't an agent address", assignee)})
+		case rig != "" && !s.Rigs[rig]:
+			out = append(out, Violation{Kind: KindUnknownAgent, Subject: bead, Detail: fmt.Sprintf("hooked to %s, but rig %s isn't registered", assignee, rig), Repair: RepairRelease})
+		case hasWorktree(role) && !s.Worktrees[assignee]:
+			out = append(out, Violation{Kind: KindOrphanedHook, Subject: bead, Detail: fmt.Sprintf("hooked to %s, which has no worktree", assignee), Repair: RepairRelease})
+		case s.Sessions[strings.TrimSuffix(assignee, "/")] == "":
+			out = append(out, Violation{Kind: KindDeadHook, Subject: bead, Detail: fmt.Sprintf("hooked to %s, which has no session (the deacon releases these)", assignee)})
+		}
+	}
+	for _, assignee := range sortedKeys(held) {
+		if beads := held[assignee]; assignee != "" && len(beads) > 1 {
+			out = append(out, Violation{Kind: KindDoubleHook, Subject: assignee, Detail: "holds " + strings.Join(beads, ", ")})
+		}
+	}
+
+	for _, addr := range sortedKeys(s.Sessions) {
+		if _, role, _ := splitAddress(addr); role == "polecats" && !s.Worktrees[addr] {
+			out = append(out, Violation{Kind: KindStraySession, Subject: s.Sessions[addr], Detail: addr + " has no worktree", Repair: RepairKill})
+		}
+	}
+
+	for _, bead := range sortedKeys(s.Queued) {
+		rig := s.Queued[bead]
+		switch {
+		case s.Hooked[bead] != "":
+			out = append(out, Violation{Kind: KindQueuedHooked, Subject: bead, Detail: fmt.Sprintf("queued for %s but hooked to %s", rig, s.Hooked[bead]), Repair: RepairDequeue})
+		case s.Closed[bead]:
+			out = append(out, Violation{Kind: KindQueuedClosed, Subject: bead, Detail: fmt.Sprintf("queued for %s but closed", rig), Repair: RepairDequeue})
+		case !s.Rigs[rig]:
+			out = append(out, Violation{Kind: KindQueuedNoRig, Subject: bead, Detail: fmt.Sprintf("queued for %s, which isn't registered", rig)})
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return kindOrder[out[i].Kind] < kindOrder[out[j].Kind] })
+	return out
+}
+
+var kindOrder = map[string]int{
+	KindOrphanedHook: 0, KindUnknownAgent: 1, KindDeadHook: 2, KindDoubleHook: 3,
+	KindStraySession: 4, KindQueuedHooked: 5, KindQueuedClosed: 6, KindQueuedNoRig: 7,
+}
+
+// splitAddress returns the rig and role of a rig agent's address
+// ("gastown/polecats/toast" → gastown, polecats), or whether it is a
+// town-level agent's ("mayor", "deacon/dogs/alpha").
+func splitAddress(addr string) (rig, role string, town bool) {
+	parts := strings.Split(strings.TrimSuffix(addr, "/"), "/")
+	switch parts[0] {
+	case "mayor", "deacon", "overseer":
+		return "", "", true
+	}
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return "", "", false
+	}
+	switch parts[1] {
+	case "witness", "refinery":
+		if len(parts) == 2 {
+			return parts[0], parts[1], false
+		}
+	case "polecats", "crew":
+		if len(parts) == 3 && parts[2] != "" {
+			return parts[0], parts[1], false
+		}
+	}
+	return "", "", false
+}
+
+func hasWorktree(role string) bool { return role == "polecats" || role == "crew" }
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,83 @@
+package invariant
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheck_Consistent(t *testing.T) {
+	s := &Snapshot{
+		Rigs:      map[string]bool{"gastown": true},
+		Sessions:  map[string]string{"gastown/polecats/toast": "gt-toast", "mayor": "hq-mayor", "gastown/witness": "gt-witness"},
+		Worktrees: map[string]bool{"gastown/polecats/toast": true},
+		Hooked:    map[string]string{"gt-abc": "gastown/polecats/toast", "hq-1": "mayor/"},
+		Queued:    map[string]string{"gt-def": "gastown"},
+	}
+	if got := Check(s); len(got) != 0 {
+		t.Errorf("consistent town reported %+v", got)
+	}
+}
+
+func TestCheck_Violations(t *testing.T) {
+	s := &Snapshot{
+		Rigs: map[string]bool{"gastown": true},
+		Sessions: map[string]string{
+			"gastown/polecats/toast": "gt-toast",
+			"gastown/polecats/ghost": "gt-ghost",
+		},
+		Worktrees: map[string]bool{"gastown/polecats/toast": true, "gastown/polecats/nux": true},
+		Hooked: map[string]string{
+			"gt-a": "gastown/polecats/toast",
+			"gt-b": "gastown/polecats/toast",
+			"gt-c": "gastown/polecats/gone",
+			"gt-d": "gastown/polecats/nux",
+			"gt-e": "oldrig/polecats/toast",
+			"gt-f": "",
+			"gt-g": "steve",
+		},
+		Queued: map[string]string{"gt-a": "gastown", "gt-h": "gastown", "gt-i": "oldrig"},
+		Closed: map[string]bool{"gt-h": true},
+	}
+
+	var got []string
+	for _, v := range Check(s) {
+		got = append(got, v.Kind+" "+v.Subject+" "+v.Repair)
+	}
+	want := []string{
+		"orphaned-hook gt-c release",
+		"unknown-agent gt-e release",
+		"unknown-agent gt-f release",
+		"unknown-agent gt-g ",
+		"dead-hook gt-d ",
+		"double-hook gastown/polecats/toast ",
+		"stray-session gt-ghost kill",
+		"queued-hooked gt-a dequeue",
+		"queued-closed gt-h dequeue",
+		"queued-no-rig gt-i ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("violations:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestSplitAddress(t *testing.T) {
+	tests := []struct {
+		addr      string
+		rig, role string
+		town      bool
+	}{
+		{"gastown/polecats/toast", "gastown", "polecats", false},
+		{"gastown/crew/max", "gastown", "crew", false},
+		{"gastown/witness", "gastown", "witness", false},
+		{"mayor/", "", "", true},
+		{"deacon/dogs/alpha", "", "", true},
+		{"gastown/polecats", "", "", false},
+		{"steve", "", "", false},
+	}
+	for _, tt := range tests {
+		rig, role, town := splitAddress(tt.addr)
+		if rig != tt.rig || role != tt.role || town != tt.town {
+			t.Errorf("splitAddress(%q) = %q, %q, %v", tt.addr, rig, role, town)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+// Package journal records gt commands that could not run because bd, the
+// network or the agent runtime was unavailable, so they can be applied
+// later with gt sync.
+//
+// Commands opt in to journaling. When one fails with an offline error (see
+// IsOffline), or GT_OFFLINE=1 is set, its arguments, working directory and
+// gt/bd environment are appended to <town>/.runtime/journal.jsonl instead
+// of failing. gt sync replays the entries oldest first and removes each one
+// that succeeds.
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// FileName is the journal file in the town's .runtime directory.
+const FileName = "journal.jsonl"
+
+// EnvOffline forces journaling without trying the command.
+const EnvOffline = "GT_OFFLINE"
+
+// EnvReplay is set while gt sync replays an entry, so a replayed command
+// that is still offline fails instead of journaling itself again.
+const EnvReplay = "GT_JOURNAL_REPLAY"
+
+// Entry is one journaled gt command.
+type Entry struct {
+	ID        string            `json:"id"`
+	Time      time.Time         `json:"time"`
+	Args      []string          `json:"args"`                 // gt arguments, without the program name
+	Dir       string            `json:"dir"`                  // Working directory the command ran in
+	Env       map[string]string `json:"env,omitempty"`        // GT_*, BD_* and BEADS_* variables
+	Reason    string            `json:"reason"`               // Why the command was journaled
+	Attempts  int               `json:"attempts,omitempty"`   // Failed replays
+	LastError string            `json:"last_error,omitempty"` // Error from the last failed replay
+}
+
+// Command renders the entry as a gt command line.
+func (e Entry) Command() string {
+	parts := []string{"gt"}
+	for _, a := range e.Args {
+		if a == "" || strings.ContainsAny(a, " \t\n\"'$`\\") {
+			a = fmt.Sprintf("%q", a)
+		}
+		parts = append(parts, a)
+	}
+	return strings.Join(parts, " ")
+}
+
+// offlineMarkers are error fragments meaning a dependency was unreachable
+// rather than that the command was wrong.
+var offlineMarkers = []string{
+	"connection refused",
+	"no such host",
+	"network is unreachable",
+	"no route to host",
+	"temporary failure in name resolution",
+	"i/o timeout",
+	"dial tcp",
+	"could not connect",
+	"dolt server is not running",
+	"no server running on", // tmux
+}
+
+// offlineError carries the stderr that showed a subprocess failure was an
+// outage, for commands whose error is only an exit status.
+type offlineError struct {
+	err    error
+	detail string
+}
+
+func (e *offlineError) Error() string { return e.err.Error() }
+func (e *offlineError) Unwrap() error { return e.err }
+
+// IsOffline reports whether err means bd, the network or the runtime is
+// unavailable.
+func IsOffline(err error) bool {
+	if err == nil {
+		return false
+	}
+	var oe *offlineError
+	if errors.As(err, &oe) {
+		return true
+	}
+	if errors.Is(err, beads.ErrNotInstalled) || errors.Is(err, exec.ErrNotFound) {
+		return true
+	}
+	return hasOfflineMarker(err.Error())
+}
+
+// Offline marks err as an outage if the subprocess stderr that went with
+// it says so; otherwise err is returned unchanged.
+func Offline(err error, stderr string) error {
+	if err == nil || !hasOfflineMarker(stderr) {
+		return err
+	}
+	return &offlineError{err: err, detail: strings.TrimSpace(stderr)}
+}
+
+// Reason describes an offline error for the journal entry.
+func Reason(err error) string {
+	var oe *offlineError
+	if errors.As(err, &oe) {
+		return lastLine(oe.detail)
+	}
+	return lastLine(err.Error())
+}
+
+func hasOfflineMarker(msg string) bool {
+	msg = strings.ToLower(msg)
+	for _, m := range offlineMarkers {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func lastLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		s = strings.TrimSpace(s[i+1:])
+	}
+	return s
+}
+
+// Path returns the journal file for a town.
+func Path(townRoot string) string {
+	return filepath.Join(constants.TownRuntimePath(townRoot), FileName)
+}
+
+// NewEntry builds an entry for a command run with args in dir, capturing
+// the environment that identifies the caller and its database.
+func NewEntry(args []string, dir, reason string) Entry {
+	now := time.Now().UTC()
+	e := Entry{
+		ID:     now.Format("20060102T150405.000000000"),
+		Time:   now,
+		Args:   append([]string(nil), args...),
+		Dir:    dir,
+		Reason: reason,
+	}
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		if k == EnvOffline || k == EnvReplay {
+			continue
+		}
+		if strings.HasPrefix(k, "GT_") || strings.HasPrefix(k, "BD_") || strings.HasPrefix(k, "BEADS_") {
+			if e.Env == nil {
+				e.Env = make(map[string]string)
+			}
+			e.Env[k] = v
+		}
+	}
+	return e
+}
+
+// Append adds an entry to the town's journal.
+func Append(townRoot string, e Entry) error {
+	return update(townRoot, func(entries []Entry) []Entry {
+		return append(entries, e)
+	})
+}
+
+// List returns the town's journaled entries, oldest first.
+func List(townRoot string) ([]Entry, error) {
+	return read(Path(townRoot))
+}
+
+// Remove deletes the entries with the given IDs.
+func Remove(townRoot string, ids ...string) error {
+	drop := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		drop[id] = true
+	}
+	return update(townRoot, func(entries []Entry) []Entry {
+		kept := entries[:0]
+		for _, e := range entries {
+			if !drop[e.ID] {
+				kept = append(kept, e)
+			}
+		}
+		return kept
+	})
+}
+
+// RecordFailure notes a failed replay of the entry with the given ID.
+func RecordFailure(townRoot, id string, replayErr error) error {
+	return update(townRoot, func(entries []Entry) []Entry {
+		for i := range entries {
+			if entries[i].ID == id {
+				entries[i].Attempts++
+				entries[i].LastError = replayErr.Error()
+			}
+		}
+		return entries
+	})
+}
+
+// update rewrites the journal under a file lock, so commands journaling
+// concurrently with gt sync don't lose entries.
+func update(townRoot string, fn func([]Entry) []Entry) error {
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating journal directory: %w", err)
+	}
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking journal: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	entries, err := read(path)
+	if err != nil {
+		return err
+	}
+	entries = fn(entries)
+	if len(entries) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing journal: %w", err)
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("encoding journal entry: %w", err)
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("writing journal: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+func read(path string) ([]Entry, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading journal: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return nil, fmt.Errorf("parsing journal %s: %w", path, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
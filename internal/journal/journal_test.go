@@ -0,0 +1,141 @@
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestIsOffline(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"bd not installed", beads.ErrNotInstalled, true},
+		{"exec not found", &exec.Error{Name: "bd", Err: exec.ErrNotFound}, true},
+		{"dolt refused", errors.New("bd create: dial tcp 127.0.0.1:3307: connect: connection refused"), true},
+		{"dns", fmt.Errorf("push: %w", errors.New("lookup github.com: no such host")), true},
+		{"wrapped offline", fmt.Errorf("closing: %w", Offline(errors.New("exit status 1"), "Error: dial tcp: i/o timeout")), true},
+		{"user error", errors.New("bd close: issue gt-x not found"), false},
+		{"exit status", errors.New("exit status 1"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsOffline(tt.err); got != tt.want {
+				t.Errorf("IsOffline(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOffline(t *testing.T) {
+	base := errors.New("exit status 1")
+	if got := Offline(base, "Error: invalid status"); got != base {
+		t.Errorf("non-outage stderr should leave err unchanged, got %v", got)
+	}
+	if Offline(nil, "connection refused") != nil {
+		t.Error("Offline(nil) should be nil")
+	}
+	err := Offline(base, "warning: x\nError: dial tcp 127.0.0.1:3307: connection refused\n")
+	if !errors.Is(err, base) {
+		t.Error("offline error should wrap the original")
+	}
+	if err.Error() != base.Error() {
+		t.Errorf("Error() = %q, want the original message", err.Error())
+	}
+	if got := Reason(err); got != "Error: dial tcp 127.0.0.1:3307: connection refused" {
+		t.Errorf("Reason = %q, want last stderr line", got)
+	}
+}
+
+func TestJournal_AppendListRemove(t *testing.T) {
+	town := t.TempDir()
+	if entries, err := List(town); err != nil || len(entries) != 0 {
+		t.Fatalf("empty journal: %v, %v", entries, err)
+	}
+
+	a := NewEntry([]string{"close", "gt-1"}, "/town/rig", "connection refused")
+	a.ID = "a"
+	b := NewEntry([]string{"mail", "send", "mayor/", "-s", "hi there"}, "/town", "GT_OFFLINE is set")
+	b.ID = "b"
+	for _, e := range []Entry{a, b} {
+		if err := Append(town, e); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	entries, err := List(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID != "a" || entries[1].ID != "b" {
+		t.Fatalf("entries = %+v, want a then b", entries)
+	}
+	if entries[1].Command() != `gt mail send mayor/ -s "hi there"` {
+		t.Errorf("Command() = %q", entries[1].Command())
+	}
+
+	if err := RecordFailure(town, "a", errors.New("still down")); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ = List(town)
+	if entries[0].Attempts != 1 || entries[0].LastError != "still down" {
+		t.Errorf("after failure: %+v", entries[0])
+	}
+
+	if err := Remove(town, "a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(Path(town)); !os.IsNotExist(err) {
+		t.Errorf("empty journal file should be removed, stat err = %v", err)
+	}
+}
+
+func TestJournal_ConcurrentAppend(t *testing.T) {
+	town := t.TempDir()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			e := NewEntry([]string{"close", fmt.Sprintf("gt-%d", i)}, town, "offline")
+			e.ID = fmt.Sprintf("%02d", i)
+			if err := Append(town, e); err != nil {
+				t.Errorf("Append: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	entries, err := List(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 20 {
+		t.Errorf("got %d entries, want 20", len(entries))
+	}
+}
+
+func TestNewEntry_CapturesAgentEnv(t *testing.T) {
+	t.Setenv("GT_ROLE", "gastown/crew/max")
+	t.Setenv("BD_ACTOR", "max")
+	t.Setenv(EnvOffline, "1")
+	t.Setenv("HOME_IS_NOT_CAPTURED", "x")
+
+	e := NewEntry([]string{"close", "gt-1"}, "/town", "offline")
+	if e.Env["GT_ROLE"] != "gastown/crew/max" || e.Env["BD_ACTOR"] != "max" {
+		t.Errorf("Env = %v, want GT_ROLE and BD_ACTOR", e.Env)
+	}
+	if _, ok := e.Env[EnvOffline]; ok {
+		t.Error("GT_OFFLINE must not be captured, or the replay would journal again")
+	}
+	if _, ok := e.Env["HOME_IS_NOT_CAPTURED"]; ok {
+		t.Error("unrelated variables should not be captured")
+	}
+}
@@ -0,0 +1,159 @@
+// Package knowledge stores per-rig knowledge artifacts: answers produced by
+// "gt ask", solutions distilled from completed beads, and other durable,
+// non-bead records agents can consult later.
+//
+// Artifacts live in <rig>/knowledge/artifacts.jsonl, one JSON object per line.
+// The file is append-only; readers tolerate (and skip) malformed lines so a
+// torn write never hides the rest of the history.
+package knowledge
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// Artifact kinds.
+const (
+	KindAsk      = "ask"      // Question answered by the rig's resident expert (gt ask)
+	KindSolution = "solution" // Completed bead: problem, approach, and diff summary
+)
+
+const (
+	dirName      = "knowledge"
+	artifactFile = "artifacts.jsonl"
+	lockTimeout  = 5 * time.Second
+)
+
+// Artifact is a single knowledge record.
+type Artifact struct {
+	ID       string `json:"id"`
+	Kind     string `json:"kind"`
+	Rig      string `json:"rig"`
+	Question string `json:"question,omitempty"`
+	Answer   string `json:"answer,omitempty"`
+
+	// Solution fields (KindSolution)
+	BeadID      string `json:"bead_id,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Problem     string `json:"problem,omitempty"`      // Bead description at completion
+	Approach    string `json:"approach,omitempty"`     // Commit subjects, in order
+	DiffSummary string `json:"diff_summary,omitempty"` // git diff --stat against the base branch
+
+	Agent     string    `json:"agent,omitempty"`      // Runtime that produced the answer (e.g., "claude")
+	SessionID string    `json:"session_id,omitempty"` // Runtime session, for follow-ups
+	Author    string    `json:"author,omitempty"`     // Who asked, or who completed the bead
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Text returns the searchable text of an artifact. Titles and questions are
+// repeated so they outweigh long diff summaries.
+func (a Artifact) Text() string {
+	head := a.Title
+	if head == "" {
+		head = a.Question
+	}
+	return head + "\n" + head + "\n" + a.Problem + "\n" + a.Approach + "\n" + a.Answer + "\n" + a.DiffSummary
+}
+
+// Dir returns the knowledge directory for a rig.
+func Dir(rigPath string) string {
+	return filepath.Join(rigPath, dirName)
+}
+
+// Path returns the artifacts file for a rig.
+func Path(rigPath string) string {
+	return filepath.Join(Dir(rigPath), artifactFile)
+}
+
+// NewID returns a short random artifact ID with a "kn-" prefix.
+func NewID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("kn-%x", time.Now().UnixNano())
+	}
+	return "kn-" + hex.EncodeToString(b)
+}
+
+// Append records an artifact for the rig, filling ID and CreatedAt if unset.
+func Append(rigPath string, a *Artifact) error {
+	if a.ID == "" {
+		a.ID = NewID()
+	}
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("encoding artifact: %w", err)
+	}
+
+	path := Path(rigPath)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating knowledge directory: %w", err)
+	}
+
+	lock := flock.New(path + ".lock")
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 50*time.Millisecond)
+	if err != nil {
+		return fmt.Errorf("acquiring knowledge lock: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("timeout waiting for knowledge lock")
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: knowledge is shared with agents
+	if err != nil {
+		return fmt.Errorf("opening knowledge file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing artifact: %w", err)
+	}
+	return nil
+}
+
+// List returns all artifacts for the rig in file order (oldest first).
+// A missing file yields an empty list.
+func List(rigPath string) ([]Artifact, error) {
+	f, err := os.Open(Path(rigPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening knowledge file: %w", err)
+	}
+	defer f.Close()
+
+	var out []Artifact
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var a Artifact
+		if err := json.Unmarshal(line, &a); err != nil {
+			continue
+		}
+		out = append(out, a)
+	}
+	if err := scanner.Err(); err != nil {
+		return out, fmt.Errorf("reading knowledge file: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,104 @@
+package knowledge
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestAppendAndList(t *testing.T) {
+	rigPath := t.TempDir()
+
+	if got, err := List(rigPath); err != nil || len(got) != 0 {
+		t.Fatalf("List() on empty rig = %v, %v; want empty, nil", got, err)
+	}
+
+	first := &Artifact{Kind: KindAsk, Rig: "gastown", Question: "Where is the sling lock?", Answer: "internal/cmd/sling_dispatch.go"}
+	if err := Append(rigPath, first); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if !strings.HasPrefix(first.ID, "kn-") {
+		t.Errorf("ID = %q, want kn- prefix", first.ID)
+	}
+	if first.CreatedAt.IsZero() {
+		t.Error("CreatedAt should be filled")
+	}
+	if err := Append(rigPath, &Artifact{Kind: KindAsk, Rig: "gastown", Answer: "second"}); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	got, err := List(rigPath)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("List() returned %d artifacts, want 2", len(got))
+	}
+	if got[0].ID != first.ID || got[0].Question != first.Question {
+		t.Errorf("first artifact = %+v, want %+v", got[0], first)
+	}
+	if got[1].Answer != "second" {
+		t.Errorf("second artifact answer = %q", got[1].Answer)
+	}
+}
+
+func TestListSkipsMalformedLines(t *testing.T) {
+	rigPath := t.TempDir()
+	if err := os.MkdirAll(Dir(rigPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `{"id":"kn-1","kind":"ask","answer":"ok"}
+{not json
+{"id":"kn-2","kind":"ask","answer":"also ok"}
+`
+	if err := os.WriteFile(Path(rigPath), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := List(rigPath)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(got) != 2 || got[0].ID != "kn-1" || got[1].ID != "kn-2" {
+		t.Errorf("List() = %+v, want kn-1 and kn-2", got)
+	}
+}
+
+func TestSearchRanksSolutions(t *testing.T) {
+	rigPath := t.TempDir()
+	for _, a := range []*Artifact{
+		{Kind: KindSolution, BeadID: "gt-1", Title: "Retry webhook delivery on 5xx", Approach: "Add exponential backoff to webhook sender"},
+		{Kind: KindSolution, BeadID: "gt-2", Title: "Dark mode for dashboard", Approach: "CSS variables"},
+		{Kind: KindAsk, Question: "How are webhook retries configured?", Answer: "See webhook.go"},
+	} {
+		if err := Append(rigPath, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := Search(rigPath, KindSolution, "webhook delivery retries failing", 0.1, 5)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 1 || results[0].BeadID != "gt-1" {
+		t.Fatalf("Search() = %+v, want only gt-1", results)
+	}
+
+	all, err := Search(rigPath, "", "webhook retries", 0.1, 5)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Search() across kinds returned %d results, want 2", len(all))
+	}
+}
+
+func TestHasSolution(t *testing.T) {
+	artifacts := []Artifact{{Kind: KindAsk, BeadID: "gt-1"}, {Kind: KindSolution, BeadID: "gt-2"}}
+	if HasSolution(artifacts, "gt-1") {
+		t.Error("ask artifact should not count as a solution")
+	}
+	if !HasSolution(artifacts, "gt-2") {
+		t.Error("expected gt-2 to be found")
+	}
+}
@@ -0,0 +1,60 @@
+package knowledge
+
+import (
+	"context"
+
+	"github.com/steveyegge/gastown/internal/dedup"
+)
+
+// Result is an artifact ranked against a search query.
+type Result struct {
+	Artifact
+	Score float64
+}
+
+// Search ranks the rig's artifacts of the given kind ("" = all kinds) against
+// query using TF-IDF similarity and returns those scoring at least threshold,
+// best first, capped at max.
+func Search(rigPath, kind, query string, threshold float64, max int) ([]Result, error) {
+	all, err := List(rigPath)
+	if err != nil {
+		return nil, err
+	}
+	return Rank(all, kind, query, threshold, max)
+}
+
+// Rank is Search over an already-loaded artifact list.
+func Rank(artifacts []Artifact, kind, query string, threshold float64, max int) ([]Result, error) {
+	byID := make(map[string]Artifact)
+	var docs []dedup.Document
+	for _, a := range artifacts {
+		if kind != "" && a.Kind != kind {
+			continue
+		}
+		byID[a.ID] = a
+		docs = append(docs, dedup.Document{ID: a.ID, Title: a.Text()})
+	}
+
+	// The query uses an ID no artifact can have so it is never filtered out.
+	q := dedup.Document{ID: "\x00query", Title: query}
+	matches, err := dedup.FindDuplicates(context.Background(), dedup.TFIDFScorer{}, q, docs, threshold, max)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(matches))
+	for _, m := range matches {
+		results = append(results, Result{Artifact: byID[m.ID], Score: m.Score})
+	}
+	return results, nil
+}
+
+// HasSolution reports whether a solution for beadID is already recorded.
+func HasSolution(artifacts []Artifact, beadID string) bool {
+	for _, a := range artifacts {
+		if a.Kind == KindSolution && a.BeadID == beadID {
+			return true
+		}
+	}
+	return false
+}
@@ -80,6 +80,11 @@ func DefaultConfig() *Config {
 
 			// Merge events - important for audit
 			"merge_*":       30 * 24 * time.Hour, // 30 days
+
+			// State-bearing events - gt town rebuild-state replays these
+			"scheduler_enqueue":  30 * 24 * time.Hour, // 30 days
+			"scheduler_dispatch": 30 * 24 * time.Hour, // 30 days
+			"freeze":             90 * 24 * time.Hour, // 90 days
 		},
 	}
 }
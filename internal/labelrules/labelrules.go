@@ -0,0 +1,126 @@
+// Package labelrules applies the label_rules from town settings: when a bead
+// gains a rule's label, the rule raises its priority, nudges its assignee,
+// mails addresses and/or slings it. Each rule fires once per labeling; the
+// state file remembers which beads a rule has already acted on.
+package labelrules
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Validate checks a set of rules: names are required and unique, every rule
+// has a label and at least one action, and priorities are 0-4.
+func Validate(rules []config.LabelRule) error {
+	seen := make(map[string]bool, len(rules))
+	for i, r := range rules {
+		if r.Name == "" {
+			return fmt.Errorf("label_rules[%d]: name is required", i)
+		}
+		if seen[r.Name] {
+			return fmt.Errorf("label_rules: duplicate rule name %q", r.Name)
+		}
+		seen[r.Name] = true
+		if r.Label == "" {
+			return fmt.Errorf("label rule %q: label is required", r.Name)
+		}
+		if !r.HasAction() {
+			return fmt.Errorf("label rule %q: set at least one of priority, nudge, notify, sling", r.Name)
+		}
+		if r.Priority != nil && (*r.Priority < 0 || *r.Priority > 4) {
+			return fmt.Errorf("label rule %q: priority must be 0-4", r.Name)
+		}
+	}
+	return nil
+}
+
+// RaisesPriority reports whether the rule would raise a bead at current
+// priority (lower numbers are more urgent).
+func RaisesPriority(r config.LabelRule, current int) bool {
+	return r.Priority != nil && current > *r.Priority
+}
+
+// State records, per rule, the beads the rule has fired for.
+type State struct {
+	Fired map[string]map[string]time.Time `json:"fired"` // rule name → bead ID → when
+}
+
+// StatePath returns the path of the label rules state file.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "label-rules.json")
+}
+
+// LoadState reads the state, returning an empty state when the file doesn't
+// exist yet.
+func LoadState(townRoot string) (*State, error) {
+	state := &State{Fired: make(map[string]map[string]time.Time)}
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Fired == nil {
+		state.Fired = make(map[string]map[string]time.Time)
+	}
+	return state, nil
+}
+
+// Save writes the state.
+func (s *State) Save(townRoot string) error {
+	return util.EnsureDirAndWriteJSON(StatePath(townRoot), s)
+}
+
+// HasFired reports whether the rule already acted on the bead.
+func (s *State) HasFired(rule, id string) bool {
+	_, ok := s.Fired[rule][id]
+	return ok
+}
+
+// MarkFired records that the rule acted on the bead.
+func (s *State) MarkFired(rule, id string, now time.Time) {
+	if s.Fired[rule] == nil {
+		s.Fired[rule] = make(map[string]time.Time)
+	}
+	s.Fired[rule][id] = now
+}
+
+// Forget drops the rule's beads that no longer carry its label, so labeling
+// them again fires the rule again.
+func (s *State) Forget(rule string, labeled []string) {
+	live := make(map[string]bool, len(labeled))
+	for _, id := range labeled {
+		live[id] = true
+	}
+	for id := range s.Fired[rule] {
+		if !live[id] {
+			delete(s.Fired[rule], id)
+		}
+	}
+	if len(s.Fired[rule]) == 0 {
+		delete(s.Fired, rule)
+	}
+}
+
+// Prune drops state for rules that are no longer configured.
+func (s *State) Prune(rules []config.LabelRule) {
+	names := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		names[r.Name] = true
+	}
+	for name := range s.Fired {
+		if !names[name] {
+			delete(s.Fired, name)
+		}
+	}
+}
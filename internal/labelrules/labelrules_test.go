@@ -0,0 +1,95 @@
+package labelrules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func intPtr(n int) *int { return &n }
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []config.LabelRule
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"ok", []config.LabelRule{
+			{Name: "urgent", Label: "urgent", Priority: intPtr(0), Nudge: true},
+			{Name: "blocked", Label: "blocked", Notify: []string{"mayor/"}},
+		}, false},
+		{"no name", []config.LabelRule{{Label: "urgent", Nudge: true}}, true},
+		{"duplicate", []config.LabelRule{
+			{Name: "a", Label: "x", Nudge: true},
+			{Name: "a", Label: "y", Nudge: true},
+		}, true},
+		{"no label", []config.LabelRule{{Name: "a", Nudge: true}}, true},
+		{"no action", []config.LabelRule{{Name: "a", Label: "x"}}, true},
+		{"bad priority", []config.LabelRule{{Name: "a", Label: "x", Priority: intPtr(5)}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.rules); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRaisesPriority(t *testing.T) {
+	r := config.LabelRule{Priority: intPtr(1)}
+	if !RaisesPriority(r, 2) {
+		t.Error("P2 bead should be raised to P1")
+	}
+	if RaisesPriority(r, 1) || RaisesPriority(r, 0) {
+		t.Error("beads at or above the rule priority should be left alone")
+	}
+	if RaisesPriority(config.LabelRule{}, 4) {
+		t.Error("rule without priority should never raise")
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	state, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	state.MarkFired("urgent", "gp-1", now)
+	if err := state.Save(townRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.HasFired("urgent", "gp-1") {
+		t.Error("fired bead lost on reload")
+	}
+	if loaded.HasFired("urgent", "gp-2") || loaded.HasFired("blocked", "gp-1") {
+		t.Error("unexpected fired entries")
+	}
+}
+
+func TestStateForgetAndPrune(t *testing.T) {
+	state := &State{Fired: make(map[string]map[string]time.Time)}
+	now := time.Now()
+	state.MarkFired("urgent", "gp-1", now)
+	state.MarkFired("urgent", "gp-2", now)
+	state.MarkFired("old", "gp-3", now)
+
+	// gp-2 lost the label: labeling it again must fire again.
+	state.Forget("urgent", []string{"gp-1"})
+	if !state.HasFired("urgent", "gp-1") || state.HasFired("urgent", "gp-2") {
+		t.Errorf("Forget kept wrong beads: %v", state.Fired["urgent"])
+	}
+
+	state.Prune([]config.LabelRule{{Name: "urgent"}})
+	if _, ok := state.Fired["old"]; ok {
+		t.Error("Prune should drop rules no longer configured")
+	}
+}
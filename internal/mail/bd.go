@@ -74,7 +74,7 @@ func runBdCommand(ctx context.Context, args []string, workDir, beadsDir string,
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	runErr := cmd.Run()
+	runErr := beads.RunCmdContext(ctx, cmd)
 
 	if runErr != nil {
 		return nil, &bdError{
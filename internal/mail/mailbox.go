@@ -624,6 +624,71 @@ func (m *Mailbox) markUnreadOnlyBeads(id string) error {
 	return nil
 }
 
+// FileToFolder files a message into a folder (see MailRule.Folder).
+// For beads mode, this adds a "folder:<name>" label.
+// For legacy mode, this sets the Folder field.
+func (m *Mailbox) FileToFolder(id, folder string) error {
+	if m.legacy {
+		return m.fileToFolderLegacy(id, folder)
+	}
+
+	return m.addLabel(id, FolderLabelPrefix+folder)
+}
+
+// LinkBead records on a message that it was filed as a bead (see
+// Message.LinkedBeads). Legacy mailboxes have no labels, so there the link
+// lives only in the bead.
+func (m *Mailbox) LinkBead(id, beadID string) error {
+	if m.legacy {
+		return nil
+	}
+	return m.addLabel(id, BeadLabelPrefix+beadID)
+}
+
+// addLabel adds a label to a message bead.
+func (m *Mailbox) addLabel(id, label string) error {
+	args := []string{"label", "add", id, label}
+
+	ctx, cancel := bdWriteCtx()
+	defer cancel()
+	_, err := runBdCommand(ctx, args, m.workDir, beads.ResolveBeadsDirForID(m.beadsDir, id))
+	if err != nil {
+		if bdErr, ok := err.(*bdError); ok && bdErr.ContainsError("not found") {
+			return ErrMessageNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (m *Mailbox) fileToFolderLegacy(id, folder string) error {
+	fl, err := m.lockLegacy()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	messages, err := m.List()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, msg := range messages {
+		if msg.ID == id {
+			msg.Folder = folder
+			found = true
+		}
+	}
+
+	if !found {
+		return ErrMessageNotFound
+	}
+
+	return m.rewriteLegacy(messages)
+}
+
 // MarkUnread marks a message as unread (reopens in beads).
 func (m *Mailbox) MarkUnread(id string) error {
 	if m.legacy {
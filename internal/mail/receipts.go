@@ -0,0 +1,119 @@
+package mail
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// AckRequiredLabel marks a message whose sender wants it read before
+	// its ack deadline (gt mail send --require-ack).
+	AckRequiredLabel = "ack-required"
+	// AckDeadlineLabelPrefix carries the RFC3339 ack deadline.
+	AckDeadlineLabelPrefix = "ack-deadline:"
+	// AckEscalatedLabel records that a missed ack was escalated, so the
+	// Deacon escalates each message once.
+	AckEscalatedLabel = "ack-escalated"
+)
+
+// Receipt states reported by ReceiptState, in delivery order.
+const (
+	ReceiptPending   = "pending"   // Written, recipient hasn't listed their inbox
+	ReceiptDelivered = "delivered" // Recipient's inbox showed it, not yet read
+	ReceiptRead      = "read"      // Recipient read (acknowledged) it
+	ReceiptOverdue   = "overdue"   // Unread past its ack deadline
+)
+
+// AckRequestLabels returns the labels written at send time for a message
+// that must be acknowledged by deadline.
+func AckRequestLabels(deadline time.Time) []string {
+	return []string{
+		AckRequiredLabel,
+		AckDeadlineLabelPrefix + deadline.UTC().Format(time.RFC3339),
+	}
+}
+
+// ParseAckLabels derives ack-request metadata from labels.
+func ParseAckLabels(labels []string) (required bool, deadline *time.Time, escalated bool) {
+	for _, label := range labels {
+		switch {
+		case label == AckRequiredLabel:
+			required = true
+		case label == AckEscalatedLabel:
+			escalated = true
+		case strings.HasPrefix(label, AckDeadlineLabelPrefix):
+			if t, err := time.Parse(time.RFC3339, strings.TrimPrefix(label, AckDeadlineLabelPrefix)); err == nil {
+				deadline = &t
+			}
+		}
+	}
+	return required, deadline, escalated
+}
+
+// ReceiptState reports how far a message got: pending, delivered or read,
+// or overdue when an ack was required and the deadline passed unread.
+func ReceiptState(msg *Message, now time.Time) string {
+	switch {
+	case msg.Read:
+		return ReceiptRead
+	case msg.AckRequired && msg.AckDeadline != nil && now.After(*msg.AckDeadline):
+		return ReceiptOverdue
+	case msg.DeliveryState == DeliveryStateAcked:
+		return ReceiptDelivered
+	default:
+		return ReceiptPending
+	}
+}
+
+// ListAckRequired returns messages sent with --require-ack. Read messages
+// are closed beads and only included when includeRead is set. A non-empty
+// from limits the result to that sender.
+func (r *Router) ListAckRequired(from string, includeRead bool) ([]*Message, error) {
+	beadsDir := r.resolveBeadsDir()
+	labels := "gt:message," + AckRequiredLabel
+	if from != "" {
+		labels += ",from:" + from
+	}
+	args := []string{"list",
+		"--labels=" + labels,
+		"--json",
+		"--limit=0",
+		"--sort=created",
+		"--asc",
+	}
+	if includeRead {
+		args = append(args, "--all")
+	}
+
+	ctx, cancel := bdReadCtx()
+	defer cancel()
+	stdout, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	if err != nil {
+		return nil, fmt.Errorf("querying ack-required messages: %w", err)
+	}
+	if !isJSON(stdout) {
+		return nil, nil
+	}
+	var beadsMsgs []BeadsMessage
+	if err := json.Unmarshal(stdout, &beadsMsgs); err != nil {
+		return nil, fmt.Errorf("parsing ack-required messages: %w", err)
+	}
+
+	messages := make([]*Message, 0, len(beadsMsgs))
+	for i := range beadsMsgs {
+		messages = append(messages, beadsMsgs[i].ToMessage())
+	}
+	return messages, nil
+}
+
+// MarkAckEscalated records that a missed ack has been escalated.
+func (r *Router) MarkAckEscalated(id string) error {
+	beadsDir := r.resolveBeadsDir()
+	ctx, cancel := bdWriteCtx()
+	defer cancel()
+	_, err := runBdCommand(ctx, []string{"label", "add", id, AckEscalatedLabel}, filepath.Dir(beadsDir), beadsDir)
+	return err
+}
@@ -0,0 +1,64 @@
+package mail
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAckLabelsRoundTrip(t *testing.T) {
+	deadline := time.Date(2026, 10, 14, 12, 30, 0, 0, time.UTC)
+	bm := &BeadsMessage{
+		ID:     "hq-1",
+		Status: "open",
+		Labels: append([]string{"from:mayor/", DeliveryLabelPending}, AckRequestLabels(deadline)...),
+	}
+	msg := bm.ToMessage()
+	if !msg.AckRequired {
+		t.Fatal("AckRequired = false, want true")
+	}
+	if msg.AckDeadline == nil || !msg.AckDeadline.Equal(deadline) {
+		t.Fatalf("AckDeadline = %v, want %v", msg.AckDeadline, deadline)
+	}
+	if msg.AckEscalated {
+		t.Fatal("AckEscalated = true before escalation")
+	}
+
+	bm.Labels = append(bm.Labels, AckEscalatedLabel)
+	if !bm.ToMessage().AckEscalated {
+		t.Fatal("AckEscalated = false after ack-escalated label")
+	}
+}
+
+func TestReceiptState(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	tests := []struct {
+		name string
+		msg  Message
+		want string
+	}{
+		{"pending", Message{DeliveryState: DeliveryStatePending}, ReceiptPending},
+		{"delivered", Message{DeliveryState: DeliveryStateAcked}, ReceiptDelivered},
+		{"read", Message{Read: true, AckRequired: true, AckDeadline: &past}, ReceiptRead},
+		{"overdue", Message{DeliveryState: DeliveryStateAcked, AckRequired: true, AckDeadline: &past}, ReceiptOverdue},
+		{"not yet due", Message{AckRequired: true, AckDeadline: &future}, ReceiptPending},
+		{"deadline without ack request", Message{AckDeadline: &past}, ReceiptPending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReceiptState(&tt.msg, now); got != tt.want {
+				t.Errorf("ReceiptState() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShouldBeWisp_AckRequired(t *testing.T) {
+	r := &Router{}
+	msg := &Message{Subject: "MERGED gt-abc", Wisp: true, AckRequired: true}
+	if r.shouldBeWisp(msg) {
+		t.Error("ack-required message must not be a wisp")
+	}
+}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/renames"
 )
 
 // ErrUnknownRecipient indicates the address does not match any known agent.
@@ -114,7 +115,11 @@ func (r *Resolver) resolveAgentAddress(address string) ([]Recipient, error) {
 	// silently deliver to a dead inbox with no error.
 	// See: https://github.com/steveyegge/gastown/issues/2038
 	if err := r.validateAgentAddress(address); err != nil {
-		return nil, err
+		forwarded, ok := r.forwardRenamed(address)
+		if !ok {
+			return nil, err
+		}
+		address = forwarded
 	}
 
 	// Direct address - single recipient
@@ -197,6 +202,24 @@ func (r *Resolver) validateAgentAddress(address string) error {
 	return fmt.Errorf("%w: %s (no matching agent or workspace found)", ErrUnknownRecipient, address)
 }
 
+// forwardRenamed returns the current address of a renamed agent, for
+// mail sent to its old address. Only addresses nothing answers to any more
+// are forwarded: a reused name gets its own mail.
+func (r *Resolver) forwardRenamed(address string) (string, bool) {
+	if r.townRoot == "" {
+		return "", false
+	}
+	ledger, err := renames.Load(r.townRoot)
+	if err != nil {
+		return "", false
+	}
+	current := ledger.Current(address)
+	if current == address || r.validateAgentAddress(current) != nil {
+		return "", false
+	}
+	return current, true
+}
+
 // dirExistsAt returns true if path exists and is a directory.
 func dirExistsAt(path string) bool {
 	info, err := os.Stat(path)
@@ -1,9 +1,13 @@
 package mail
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/renames"
 )
 
 func TestMatchPattern(t *testing.T) {
@@ -167,6 +171,42 @@ func TestResolverResolve_UnknownName(t *testing.T) {
 	}
 }
 
+func TestResolverResolve_ForwardsRenamedAgent(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "gastown", "polecats", "Imperator"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	ledger := &renames.Ledger{}
+	ledger.Record(renames.KindAgent, "gastown/polecats/Toast", "gastown/polecats/Imperator")
+	if err := ledger.Save(town); err != nil {
+		t.Fatal(err)
+	}
+	resolver := NewResolver(nil, town)
+
+	for _, addr := range []string{"gastown/polecats/Toast", "gastown/Toast"} {
+		got, err := resolver.Resolve(addr)
+		if err != nil {
+			t.Fatalf("Resolve(%q): %v", addr, err)
+		}
+		if len(got) != 1 || (got[0].Address != "gastown/polecats/Imperator" && got[0].Address != "gastown/Imperator") {
+			t.Errorf("Resolve(%q) = %+v, want forwarded to Imperator", addr, got)
+		}
+	}
+
+	// Once the old name is reused, its mail is its own.
+	if err := os.MkdirAll(filepath.Join(town, "gastown", "polecats", "Toast"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	got, err := resolver.Resolve("gastown/polecats/Toast")
+	if err != nil || got[0].Address != "gastown/polecats/Toast" {
+		t.Errorf("reused name = %+v, %v", got, err)
+	}
+
+	if _, err := resolver.Resolve("gastown/polecats/ghost"); !errors.Is(err, ErrUnknownRecipient) {
+		t.Errorf("unrenamed unknown agent: err = %v", err)
+	}
+}
+
 // Regression test for gt-64wh5: cycle detection bypassed through Resolve fallback.
 // Before the fix, resolveMemberWithVisited called r.Resolve() for @-prefixed and
 // /-containing members, which created a fresh visited map and lost cycle detection.
@@ -11,11 +11,15 @@ import (
 	"sync"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/atrest"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/chaos"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/nudge"
+	"github.com/steveyegge/gastown/internal/quiet"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/shard"
 	"github.com/steveyegge/gastown/internal/telemetry"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -788,7 +792,13 @@ func (r *Router) queryAgentsFromDir(beadsDir string) ([]*agentBead, error) {
 // Returns true if:
 // - Message.Wisp is explicitly set
 // - Subject matches lifecycle message patterns (POLECAT_*, NUDGE, etc.)
+//
+// Ack-required messages are never wisps: they must stay durable and
+// visible to ListAckRequired.
 func (r *Router) shouldBeWisp(msg *Message) bool {
+	if msg.AckRequired {
+		return false
+	}
 	if msg.Wisp {
 		return true
 	}
@@ -887,6 +897,17 @@ func (r *Router) sendToGroup(msg *Message) error {
 	return nil
 }
 
+// routeMayorShard readdresses mail for the primary Mayor to the shard mayor
+// owning the sender's rig, when the town shards its mayors. Mail from
+// unsharded rigs, town-level agents and shard mayors stays with the primary.
+// msg.To is updated so callers and notification see the actual recipient.
+func (r *Router) routeMayorShard(msg *Message) {
+	if r.townRoot == "" || AddressToIdentity(msg.To) != shard.PrimaryAddress {
+		return
+	}
+	msg.To = config.LoadMayorShards(r.townRoot).MayorFor(msg.From)
+}
+
 // validateRecipient checks that the recipient identity corresponds to an existing agent.
 // Returns an error if the recipient is invalid or doesn't exist.
 // Queries agents from town-level beads AND all rig-level beads via routes.jsonl.
@@ -902,6 +923,18 @@ func (r *Router) validateRecipient(identity string) error {
 		return nil
 	}
 
+	// Configured shard mayors: mayor/<shard>
+	if name, ok := strings.CutPrefix(identity, "mayor/"); ok && r.townRoot != "" {
+		if config.LoadMayorShards(r.townRoot).Get(name) != nil {
+			return nil
+		}
+	}
+
+	// Custom roles defined in <town>/roles/: <name> or <rig>/<name>
+	if r.townRoot != "" && isCustomRoleAddress(r.townRoot, identity) {
+		return nil
+	}
+
 	// Well-known rig-level singletons (rig/witness, rig/refinery) always
 	// valid — these agents are ephemeral and may not have an active session,
 	// but mail queues for the next session that starts.
@@ -960,6 +993,29 @@ func (r *Router) validateRecipient(identity string) error {
 	return fmt.Errorf("no agent found")
 }
 
+// isCustomRoleAddress reports whether identity addresses a custom role's
+// agent: "<name>" for a town-scoped role, "<rig>/<name>" for a rig-scoped one.
+func isCustomRoleAddress(townRoot, identity string) bool {
+	return customRoleSessionID(townRoot, identity) != ""
+}
+
+// customRoleSessionID returns the tmux session of the custom role agent at
+// address, or "" if address isn't a custom role's.
+func customRoleSessionID(townRoot, address string) string {
+	rig, name, found := strings.Cut(strings.TrimSuffix(address, "/"), "/")
+	if !found {
+		rig, name = "", rig
+	}
+	if strings.Contains(name, "/") || !config.IsCustomRole(townRoot, name) {
+		return ""
+	}
+	def, err := config.LoadCustomRole(townRoot, name)
+	if err != nil || (def.Scope == "rig") != (rig != "") {
+		return ""
+	}
+	return config.ExpandPattern(def.Session.Pattern, townRoot, rig, "", name, session.PrefixFor(rig))
+}
+
 // validateAgentWorkspace checks if an agent's workspace directory exists on disk.
 // Used as a fallback when the agent isn't found in the bead registry.
 func (r *Router) validateAgentWorkspace(identity string) bool {
@@ -1054,6 +1110,15 @@ func (r *Router) resolveCrewShorthand(identity string) string {
 	return identity // Ambiguous or not found: let validation handle it
 }
 
+// typeLabels returns the msg-type label for typed messages. Notifications,
+// the default, carry no label.
+func typeLabels(msg *Message) []string {
+	if msg.Type == "" || msg.Type == TypeNotification {
+		return nil
+	}
+	return []string{"msg-type:" + string(msg.Type)}
+}
+
 // sendToSingle sends a message to a single recipient.
 func (r *Router) sendToSingle(msg *Message) error {
 	// Ensure message has an ID for in-memory tracking (notifications, logging).
@@ -1067,6 +1132,8 @@ func (r *Router) sendToSingle(msg *Message) error {
 		return fmt.Errorf("invalid message: %w", err)
 	}
 
+	r.routeMayorShard(msg)
+
 	// Convert addresses to beads identities
 	toIdentity := AddressToIdentity(msg.To)
 	// Expand crew/polecats shorthand (e.g., "crew/bob" → "pata/bob")
@@ -1077,10 +1144,16 @@ func (r *Router) sendToSingle(msg *Message) error {
 		return fmt.Errorf("invalid recipient %q: %w", msg.To, err)
 	}
 
+	// gt town chaos may be dropping mail to test recovery.
+	if chaos.DropMail(r.townRoot, msg.From, msg.To, msg.Subject) {
+		return nil
+	}
+
 	// Build labels for type, from/thread/reply-to/cc
 	var labels []string
 	labels = append(labels, "gt:message")
 	labels = append(labels, "from:"+msg.From)
+	labels = append(labels, typeLabels(msg)...)
 	labels = append(labels, DeliverySendLabels()...)
 	if msg.ThreadID != "" {
 		labels = append(labels, "thread:"+msg.ThreadID)
@@ -1093,6 +1166,14 @@ func (r *Router) sendToSingle(msg *Message) error {
 		ccIdentity := AddressToIdentity(cc)
 		labels = append(labels, "cc:"+ccIdentity)
 	}
+	if msg.AckRequired && msg.AckDeadline != nil {
+		labels = append(labels, AckRequestLabels(*msg.AckDeadline)...)
+	}
+
+	body, err := r.storedBody(msg)
+	if err != nil {
+		return err
+	}
 
 	// Build command: bd create --assignee=<recipient> -d <body> --labels=gt:message,... -- <subject>
 	// Flags go first, then -- to end flag parsing, then the positional subject.
@@ -1100,7 +1181,7 @@ func (r *Router) sendToSingle(msg *Message) error {
 	// Let bd auto-generate the ID with the correct database prefix.
 	args := []string{"create",
 		"--assignee", toIdentity,
-		"-d", msg.Body,
+		"-d", body,
 	}
 
 	// Add priority flag
@@ -1135,13 +1216,13 @@ func (r *Router) sendToSingle(msg *Message) error {
 	}
 	ctx, cancel := bdWriteCtx()
 	defer cancel()
-	_, err := runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
+	_, err = runBdCommand(ctx, args, filepath.Dir(beadsDir), beadsDir)
 	telemetry.RecordMailMessage(context.Background(), "send", telemetry.MailMessageInfo{
 		ID:       msg.ID,
 		From:     msg.From,
 		To:       msg.To,
 		Subject:  msg.Subject,
-		Body:     msg.Body,
+		Body:     body,
 		ThreadID: msg.ThreadID,
 		Priority: string(msg.Priority),
 		MsgType:  string(msg.Type),
@@ -1156,7 +1237,7 @@ func (r *Router) sendToSingle(msg *Message) error {
 	// Notification is async: the durable write is complete, so the caller
 	// doesn't block on idle probing (up to 1s per recipient in fan-out).
 	// Callers that exit soon after Send should call WaitPendingNotifications.
-	if !msg.SuppressNotify && !isSelfMail(msg.From, msg.To) {
+	if !msg.SuppressNotify && !isSelfMail(msg.From, msg.To) && !r.holdForQuietHours(msg) {
 		msgCopy := *msg // copy to avoid data race if caller mutates msg
 		r.notifyWg.Add(1)
 		go func() {
@@ -1168,6 +1249,34 @@ func (r *Router) sendToSingle(msg *Message) error {
 	return nil
 }
 
+// storedBody returns a message's body as written to beads: sealed when the
+// town encrypts mail at rest. Without the key sending fails rather than
+// storing the body in the clear.
+func (r *Router) storedBody(msg *Message) (string, error) {
+	return StoredBody(r.townRoot, msg.Body)
+}
+
+// StoredBody returns body as a message bead in townRoot stores it: sealed
+// when the town encrypts mail at rest. Code that creates message beads
+// without the router (handoff mail) must use it too.
+func StoredBody(townRoot, body string) (string, error) {
+	if townRoot == "" {
+		return body, nil
+	}
+	enc := config.LoadEncryption(townRoot)
+	if enc == nil || !enc.Mail {
+		return body, nil
+	}
+	if err := enc.Validate(); err != nil {
+		return "", fmt.Errorf("mail encryption: %w", err)
+	}
+	sealed, err := atrest.SealString(enc.KeyID, body)
+	if err != nil {
+		return "", fmt.Errorf("sealing message body: %w", err)
+	}
+	return sealed, nil
+}
+
 // sendToList expands a mailing list and sends individual copies to each recipient.
 // Each recipient gets their own message copy with the same content.
 // Collects all delivery errors and reports partial failures.
@@ -1225,6 +1334,7 @@ func (r *Router) sendToQueue(msg *Message) error {
 	var labels []string
 	labels = append(labels, "gt:message")
 	labels = append(labels, "from:"+msg.From)
+	labels = append(labels, typeLabels(msg)...)
 	labels = append(labels, "queue:"+queueName)
 	labels = append(labels, DeliverySendLabels()...)
 	if msg.ThreadID != "" {
@@ -1238,13 +1348,18 @@ func (r *Router) sendToQueue(msg *Message) error {
 		labels = append(labels, "cc:"+ccIdentity)
 	}
 
+	body, err := r.storedBody(msg)
+	if err != nil {
+		return err
+	}
+
 	// Build command: bd create --assignee=queue:<name> -d <body> ... -- <subject>
 	// Flags go first, then -- to end flag parsing, then the positional subject.
 	// This prevents subjects like "--help" from being parsed as flags.
 	// Use queue:<name> as assignee so inbox queries can filter by queue
 	args := []string{"create",
 		"--assignee", msg.To, // queue:name
-		"-d", msg.Body,
+		"-d", body,
 	}
 
 	// Add priority flag
@@ -1310,6 +1425,7 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 	var labels []string
 	labels = append(labels, "gt:message")
 	labels = append(labels, "from:"+msg.From)
+	labels = append(labels, typeLabels(msg)...)
 	labels = append(labels, "announce:"+announceName)
 	if msg.ThreadID != "" {
 		labels = append(labels, "thread:"+msg.ThreadID)
@@ -1322,13 +1438,18 @@ func (r *Router) sendToAnnounce(msg *Message) error {
 		labels = append(labels, "cc:"+ccIdentity)
 	}
 
+	body, err := r.storedBody(msg)
+	if err != nil {
+		return err
+	}
+
 	// Build command: bd create --assignee=announce:<name> -d <body> ... -- <subject>
 	// Flags go first, then -- to end flag parsing, then the positional subject.
 	// This prevents subjects like "--help" from being parsed as flags.
 	// Use announce:<name> as assignee so queries can filter by channel
 	args := []string{"create",
 		"--assignee", msg.To, // announce:name
-		"-d", msg.Body,
+		"-d", body,
 	}
 
 	// Add priority flag
@@ -1396,6 +1517,7 @@ func (r *Router) sendToChannel(msg *Message) error {
 	var labels []string
 	labels = append(labels, "gt:message")
 	labels = append(labels, "from:"+msg.From)
+	labels = append(labels, typeLabels(msg)...)
 	labels = append(labels, "channel:"+channelName)
 	if msg.ThreadID != "" {
 		labels = append(labels, "thread:"+msg.ThreadID)
@@ -1408,13 +1530,18 @@ func (r *Router) sendToChannel(msg *Message) error {
 		labels = append(labels, "cc:"+ccIdentity)
 	}
 
+	body, err := r.storedBody(msg)
+	if err != nil {
+		return err
+	}
+
 	// Build command: bd create --assignee=channel:<name> -d <body> ... -- <subject>
 	// Flags go first, then -- to end flag parsing, then the positional subject.
 	// This prevents subjects like "--help" from being parsed as flags.
 	// Use channel:<name> as assignee so queries can filter by channel
 	args := []string{"create",
 		"--assignee", msg.To, // channel:name
-		"-d", msg.Body,
+		"-d", body,
 	}
 
 	// Add priority flag
@@ -1540,6 +1667,38 @@ func isSelfMail(from, to string) bool {
 	return AddressToIdentity(from) == AddressToIdentity(to)
 }
 
+// holdForQuietHours defers the recipient's notification while its rig (or
+// the town, for mayor, deacon and overseer) is in quiet hours. The message
+// is already delivered; gt quiet tick sends one summary nudge per recipient
+// when the window ends. High and urgent mail always notifies.
+func (r *Router) holdForQuietHours(msg *Message) bool {
+	if r.townRoot == "" || msg.Priority == PriorityHigh || msg.Priority == PriorityUrgent {
+		return false
+	}
+	rigName := addressRig(msg.To)
+	if !config.LoadQuietHours(r.townRoot, rigName).Active(time.Now()) {
+		return false
+	}
+	scope := rigName
+	if scope == "" {
+		scope = quiet.TownScope
+	}
+	return quiet.Hold(r.townRoot, quiet.Held{Scope: scope, To: msg.To, From: msg.From, Subject: msg.Subject}) == nil
+}
+
+// addressRig returns the rig an address belongs to, or "" for town-level
+// addresses (mayor, deacon, overseer).
+func addressRig(address string) string {
+	if address == "overseer" || strings.HasPrefix(address, constants.RoleMayor) || strings.HasPrefix(address, constants.RoleDeacon) {
+		return ""
+	}
+	rig, _, ok := strings.Cut(address, "/")
+	if !ok {
+		return ""
+	}
+	return rig
+}
+
 // GetMailbox returns a Mailbox for the given address.
 // Routes to the correct beads database based on the address.
 func (r *Router) GetMailbox(address string) (*Mailbox, error) {
@@ -1571,6 +1730,11 @@ func (r *Router) notifyRecipient(msg *Message) error {
 	}
 
 	sessionIDs := AddressToSessionIDs(msg.To)
+	if r.townRoot != "" {
+		if id := customRoleSessionID(r.townRoot, msg.To); id != "" {
+			sessionIDs = []string{id}
+		}
+	}
 	if len(sessionIDs) == 0 {
 		return nil // Unable to determine session ID
 	}
@@ -1713,6 +1877,9 @@ func addressToAgentBeadID(address string) string {
 	case address == "overseer":
 		return "" // Overseer is a human, no agent bead
 	case strings.HasPrefix(address, constants.RoleMayor):
+		if name := strings.TrimPrefix(address, "mayor/"); name != address && name != "" {
+			return session.MayorShardSessionName(name)
+		}
 		return session.MayorSessionName()
 	case strings.HasPrefix(address, constants.RoleDeacon):
 		return session.DeaconSessionName()
@@ -1757,8 +1924,11 @@ func AddressToSessionIDs(address string) []string {
 		return []string{session.OverseerSessionName()}
 	}
 
-	// Mayor address: "mayor/" or "mayor"
+	// Mayor address: "mayor/" or "mayor"; shard mayors are "mayor/<shard>"
 	if strings.HasPrefix(address, constants.RoleMayor) {
+		if name := strings.TrimPrefix(address, "mayor/"); name != address && name != "" {
+			return []string{session.MayorShardSessionName(name)}
+		}
 		return []string{session.MayorSessionName()}
 	}
 
@@ -14,7 +14,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/atrest"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/nudge"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/testutil"
@@ -121,6 +123,7 @@ func TestAddressToSessionIDs(t *testing.T) {
 		// Town-level addresses - single session
 		{"mayor", []string{"hq-mayor"}},
 		{"mayor/", []string{"hq-mayor"}},
+		{"mayor/west", []string{"hq-mayor-west"}},
 		{"deacon", []string{"hq-deacon"}},
 
 		// Rig singletons - single session (no crew/polecat ambiguity)
@@ -1284,6 +1287,11 @@ func TestAddressToAgentBeadID(t *testing.T) {
 			address:  "mayor",
 			expected: "hq-mayor",
 		},
+		{
+			name:     "shard mayor",
+			address:  "mayor/west",
+			expected: "hq-mayor-west",
+		},
 		{
 			name:     "deacon",
 			address:  "deacon/",
@@ -1422,6 +1430,82 @@ func TestResolveCrewShorthand(t *testing.T) {
 	}
 }
 
+func TestRouteMayorShard(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := `{"mayors": {"shards": [{"name": "west", "rigs": ["frontend"]}]}}`
+	if err := os.MkdirAll(filepath.Join(townRoot, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+
+	tests := []struct {
+		from, to, want string
+	}{
+		{"frontend/witness", "mayor/", "mayor/west"},
+		{"frontend/crew/max", "mayor", "mayor/west"},
+		{"gastown/witness", "mayor/", "mayor/"},          // unsharded rig
+		{"mayor/west", "mayor/", "mayor/"},               // shard mayor to primary
+		{"frontend/witness", "deacon/", "deacon/"},       // only mayor mail is rerouted
+		{"frontend/witness", "mayor/west", "mayor/west"}, // explicit shard address
+	}
+	for _, tt := range tests {
+		msg := &Message{From: tt.from, To: tt.to}
+		r.routeMayorShard(msg)
+		if msg.To != tt.want {
+			t.Errorf("routeMayorShard(from %q, to %q) = %q, want %q", tt.from, tt.to, msg.To, tt.want)
+		}
+	}
+
+	if err := r.validateRecipient("mayor/west"); err != nil {
+		t.Errorf("validateRecipient(mayor/west) = %v, want nil for a configured shard", err)
+	}
+
+	// Without shards, mayor mail is untouched.
+	plain := NewRouterWithTownRoot(t.TempDir(), t.TempDir())
+	msg := &Message{From: "frontend/witness", To: "mayor/"}
+	plain.routeMayorShard(msg)
+	if msg.To != "mayor/" {
+		t.Errorf("unsharded routeMayorShard = %q, want mayor/", msg.To)
+	}
+}
+
+func TestCustomRoleRecipients(t *testing.T) {
+	townRoot := t.TempDir()
+	for _, def := range []*config.RoleDefinition{
+		config.NewCustomRole("architect", "town"),
+		config.NewCustomRole("security-reviewer", "rig"),
+	} {
+		if err := config.WriteCustomRole(townRoot, def); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := NewRouterWithTownRoot(townRoot, townRoot)
+
+	for _, identity := range []string{"architect", "gastown/security-reviewer"} {
+		if err := r.validateRecipient(identity); err != nil {
+			t.Errorf("validateRecipient(%q) = %v, want nil for a custom role", identity, err)
+		}
+	}
+
+	tests := []struct {
+		address, want string
+	}{
+		{"architect/", "hq-architect"},
+		{"gastown/security-reviewer", session.PrefixFor("gastown") + "-security-reviewer"},
+		{"gastown/architect", ""},  // town role has no rig form
+		{"security-reviewer/", ""}, // rig role needs a rig
+		{"gastown/nux", ""},
+	}
+	for _, tt := range tests {
+		if got := customRoleSessionID(townRoot, tt.address); got != tt.want {
+			t.Errorf("customRoleSessionID(%q) = %q, want %q", tt.address, got, tt.want)
+		}
+	}
+}
+
 func TestValidateRecipientFilesystemFallback(t *testing.T) {
 	// Create a realistic town directory structure without any agent beads
 	tmpDir := t.TempDir()
@@ -1752,3 +1836,47 @@ func TestEnqueueReplyReminder_DisabledByConfig(t *testing.T) {
 	}
 }
 
+
+func TestAddressRig(t *testing.T) {
+	tests := map[string]string{
+		"overseer":              "",
+		"mayor/":                "",
+		"mayor/west":            "",
+		"deacon/":               "",
+		"greenplace/witness":    "greenplace",
+		"greenplace/Toast":      "greenplace",
+		"greenplace/crew/max":   "greenplace",
+		"greenplace/polecats/x": "greenplace",
+	}
+	for address, want := range tests {
+		if got := addressRig(address); got != want {
+			t.Errorf("addressRig(%q) = %q, want %q", address, got, want)
+		}
+	}
+}
+
+func TestStoredBody(t *testing.T) {
+	t.Setenv(atrest.EnvKey, "mailtest-1:"+strings.Repeat("A", 43)+"=")
+	townRoot := t.TempDir()
+	if got, err := StoredBody(townRoot, "plain"); err != nil || got != "plain" {
+		t.Fatalf("without encryption: %q, %v", got, err)
+	}
+
+	settings := `{"type": "town-settings", "version": 1, "encryption": {"key_id": "mailtest-1", "mail": true}}`
+	if err := os.MkdirAll(filepath.Join(townRoot, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "settings", "config.json"), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := StoredBody(townRoot, "handoff notes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sealed == "handoff notes" {
+		t.Fatal("body stored in the clear with mail encryption on")
+	}
+	if opened, err := atrest.OpenString(sealed); err != nil || opened != "handoff notes" {
+		t.Errorf("opened = %q, %v", opened, err)
+	}
+}
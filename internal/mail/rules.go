@@ -0,0 +1,151 @@
+package mail
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// FolderLabelPrefix marks the folder a mail rule filed a message into.
+const FolderLabelPrefix = "folder:"
+
+// ForwardSubjectPrefix starts the subject of a message forwarded by a rule.
+// Forwards are never forwarded again, so two mailboxes forwarding to each
+// other can't loop.
+const ForwardSubjectPrefix = "Fwd: "
+
+// MatchRule reports whether a message satisfies every match field of rule.
+func MatchRule(rule config.MailRule, msg *Message) bool {
+	if rule.From != "" && !globMatch(AddressToIdentity(rule.From), AddressToIdentity(msg.From), false) {
+		return false
+	}
+	if rule.Subject != "" && !globMatch(rule.Subject, msg.Subject, true) {
+		return false
+	}
+	for _, label := range rule.Labels {
+		if !slices.Contains(msg.Labels, label) {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchingRules returns the rules that apply to msg, in order, stopping
+// after the first matching rule with Stop set.
+func MatchingRules(rules []config.MailRule, msg *Message) []config.MailRule {
+	var matched []config.MailRule
+	for _, rule := range rules {
+		if !MatchRule(rule, msg) {
+			continue
+		}
+		matched = append(matched, rule)
+		if rule.Stop {
+			break
+		}
+	}
+	return matched
+}
+
+// RulesForMailbox returns the rules configured for address, accepting any
+// spelling of the address ("mayor", "mayor/").
+func RulesForMailbox(cfg *config.MessagingConfig, address string) []config.MailRule {
+	if cfg == nil {
+		return nil
+	}
+	want := AddressToIdentity(address)
+	for key, rules := range cfg.Rules {
+		if AddressToIdentity(key) == want {
+			return rules
+		}
+	}
+	return nil
+}
+
+// globMatch matches s against a pattern where "*" matches any run of
+// characters (including "/") and "?" matches one character.
+func globMatch(pattern, s string, foldCase bool) bool {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	if foldCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile("^" + expr + "$")
+	return err == nil && re.MatchString(s)
+}
+
+// RulesState records which messages each mailbox's rules have already
+// processed, so a patrol doesn't forward or hook the same message twice.
+type RulesState struct {
+	Processed map[string][]string `json:"processed"` // mailbox identity → message IDs
+}
+
+// RulesStatePath returns the path of the mail rules state file.
+func RulesStatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "mail-rules.json")
+}
+
+// LoadRulesState reads the rules state, returning an empty state when the
+// file doesn't exist yet.
+func LoadRulesState(townRoot string) (*RulesState, error) {
+	state := &RulesState{Processed: make(map[string][]string)}
+	data, err := os.ReadFile(RulesStatePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Processed == nil {
+		state.Processed = make(map[string][]string)
+	}
+	return state, nil
+}
+
+// Save writes the rules state.
+func (s *RulesState) Save(townRoot string) error {
+	return util.EnsureDirAndWriteJSON(RulesStatePath(townRoot), s)
+}
+
+// Seen reports whether the mailbox's rules already processed a message.
+func (s *RulesState) Seen(address, id string) bool {
+	return slices.Contains(s.Processed[AddressToIdentity(address)], id)
+}
+
+// MarkSeen records that the mailbox's rules processed a message.
+func (s *RulesState) MarkSeen(address, id string) {
+	key := AddressToIdentity(address)
+	if !slices.Contains(s.Processed[key], id) {
+		s.Processed[key] = append(s.Processed[key], id)
+	}
+}
+
+// Prune forgets processed IDs that are no longer in the mailbox, keeping
+// the state file proportional to the inbox.
+func (s *RulesState) Prune(address string, messages []*Message) {
+	key := AddressToIdentity(address)
+	live := make(map[string]bool, len(messages))
+	for _, msg := range messages {
+		live[msg.ID] = true
+	}
+	kept := s.Processed[key][:0]
+	for _, id := range s.Processed[key] {
+		if live[id] {
+			kept = append(kept, id)
+		}
+	}
+	if len(kept) == 0 {
+		delete(s.Processed, key)
+		return
+	}
+	s.Processed[key] = kept
+}
@@ -0,0 +1,129 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestMatchRule(t *testing.T) {
+	msg := &Message{From: "gastown/refinery", Subject: "MERGED gt-abc", Labels: []string{"from:gastown/refinery", "urgent"}}
+	tests := []struct {
+		name string
+		rule config.MailRule
+		want bool
+	}{
+		{"no match fields", config.MailRule{}, true},
+		{"sender glob", config.MailRule{From: "*/refinery"}, true},
+		{"sender glob spans slashes", config.MailRule{From: "gas*"}, true},
+		{"other sender", config.MailRule{From: "*/witness"}, false},
+		{"subject glob ignores case", config.MailRule{Subject: "merged *"}, true},
+		{"subject must match whole", config.MailRule{Subject: "MERGED"}, false},
+		{"labels all present", config.MailRule{Labels: []string{"urgent"}}, true},
+		{"label missing", config.MailRule{Labels: []string{"urgent", "security"}}, false},
+		{"every field must match", config.MailRule{From: "*/refinery", Subject: "HELP*"}, false},
+	}
+	for _, tt := range tests {
+		if got := MatchRule(tt.rule, msg); got != tt.want {
+			t.Errorf("%s: MatchRule = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Town-level addresses match with or without the trailing slash.
+	if !MatchRule(config.MailRule{From: "mayor"}, &Message{From: "mayor/"}) {
+		t.Error("from: mayor should match mayor/")
+	}
+}
+
+func TestMatchingRules_Stop(t *testing.T) {
+	rules := []config.MailRule{
+		{Name: "file", Subject: "HELP*", Folder: "help"},
+		{Name: "forward", Subject: "HELP*", Forward: []string{"overseer"}, Stop: true},
+		{Name: "ack-all", Ack: true},
+	}
+	got := MatchingRules(rules, &Message{Subject: "HELP: stuck"})
+	if len(got) != 2 || got[0].Name != "file" || got[1].Name != "forward" {
+		t.Errorf("MatchingRules = %+v, want file then forward", got)
+	}
+	got = MatchingRules(rules, &Message{Subject: "POLECAT_DONE"})
+	if len(got) != 1 || got[0].Name != "ack-all" {
+		t.Errorf("MatchingRules = %+v, want ack-all", got)
+	}
+}
+
+func TestRulesForMailbox(t *testing.T) {
+	cfg := &config.MessagingConfig{Rules: map[string][]config.MailRule{
+		"mayor/": {{Name: "a", Ack: true}},
+	}}
+	if got := RulesForMailbox(cfg, "mayor"); len(got) != 1 {
+		t.Errorf("RulesForMailbox(mayor) = %+v", got)
+	}
+	if got := RulesForMailbox(cfg, "gastown/witness"); got != nil {
+		t.Errorf("RulesForMailbox(witness) = %+v, want nil", got)
+	}
+}
+
+func TestRulesState(t *testing.T) {
+	town := t.TempDir()
+	state, err := LoadRulesState(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.MarkSeen("mayor", "hq-1")
+	state.MarkSeen("mayor/", "hq-2")
+	state.MarkSeen("mayor/", "hq-2")
+	if err := state.Save(town); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err = LoadRulesState(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.Seen("mayor/", "hq-1") || !state.Seen("mayor", "hq-2") || state.Seen("mayor/", "hq-3") {
+		t.Errorf("Processed = %+v", state.Processed)
+	}
+	if len(state.Processed["mayor/"]) != 2 {
+		t.Errorf("duplicate IDs recorded: %+v", state.Processed)
+	}
+
+	state.Prune("mayor/", []*Message{{ID: "hq-2"}})
+	if state.Seen("mayor/", "hq-1") || !state.Seen("mayor/", "hq-2") {
+		t.Errorf("after Prune: %+v", state.Processed)
+	}
+	state.Prune("mayor/", nil)
+	if _, ok := state.Processed["mayor/"]; ok {
+		t.Error("empty mailboxes should be dropped from the state")
+	}
+}
+
+func TestBeadsMessage_Folder(t *testing.T) {
+	bm := &BeadsMessage{ID: "hq-1", Labels: []string{"from:mayor/", FolderLabelPrefix + "merges"}}
+	msg := bm.ToMessage()
+	if msg.Folder != "merges" {
+		t.Errorf("Folder = %q, want merges", msg.Folder)
+	}
+	if len(msg.Labels) != 2 {
+		t.Errorf("Labels = %v", msg.Labels)
+	}
+}
+
+func TestFileToFolder_Legacy(t *testing.T) {
+	m := NewMailbox(t.TempDir())
+	if err := m.Append(&Message{ID: "msg-1", Subject: "MERGED"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.FileToFolder("msg-1", "merges"); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := m.Get("msg-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Folder != "merges" {
+		t.Errorf("Folder = %q, want merges", msg.Folder)
+	}
+	if err := m.FileToFolder("msg-missing", "merges"); err != ErrMessageNotFound {
+		t.Errorf("missing message: err = %v, want ErrMessageNotFound", err)
+	}
+}
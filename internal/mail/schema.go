@@ -0,0 +1,165 @@
+package mail
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// MessageSchema describes the structured fields of a message type. Fields
+// are written as "key: value" lines at the top of the body, followed by a
+// blank line and free text, so typed mail stays readable as plain mail.
+type MessageSchema struct {
+	// Required fields must be present and non-empty.
+	Required []string
+	// Optional fields are recognized but may be omitted.
+	Optional []string
+	// Values restricts a field to a fixed set of values.
+	Values map[string][]string
+}
+
+// Field names shared by several schemas.
+const (
+	FieldBead     = "bead"
+	FieldStatus   = "status"
+	FieldOptions  = "options"
+	FieldAction   = "action"
+	FieldDecision = "decision"
+	FieldApproval = "approval"
+)
+
+// Decisions recorded on the reply to an approval request.
+const (
+	DecisionApproved = "approved"
+	DecisionRejected = "rejected"
+)
+
+// MessageSchemas maps each structured message type to its schema. Types
+// without an entry (notification, scavenge) carry only free text.
+var MessageSchemas = map[MessageType]MessageSchema{
+	TypeTask: {
+		Optional: []string{FieldBead, "due"},
+	},
+	TypeQuestion: {
+		// Options lists suggested answers separated by "|".
+		Optional: []string{FieldBead, FieldOptions},
+	},
+	TypeReport: {
+		Required: []string{FieldStatus},
+		Optional: []string{FieldBead},
+		Values:   map[string][]string{FieldStatus: {"progress", "done", "blocked", "failed"}},
+	},
+	TypeApproval: {
+		Required: []string{FieldAction},
+		Optional: []string{FieldBead},
+	},
+	TypeReply: {
+		// Decision and approval are set when the reply answers an approval.
+		// Decision isn't restricted here: free-text replies may happen to
+		// start with "Decision:".
+		Optional: []string{FieldDecision, FieldApproval},
+	},
+}
+
+// fields returns every field name the schema recognizes.
+func (s MessageSchema) fields() []string {
+	return append(append([]string{}, s.Required...), s.Optional...)
+}
+
+// NeedsHuman reports whether a message type asks its recipient for a
+// decision or answer (question, approval).
+func NeedsHuman(t MessageType) bool {
+	return t == TypeQuestion || t == TypeApproval
+}
+
+// FormatMessageBody writes fields as "key: value" header lines (sorted by
+// key) followed by a blank line and text.
+func FormatMessageBody(fields map[string]string, text string) string {
+	if len(fields) == 0 {
+		return text
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s: %s\n", k, fields[k])
+	}
+	if text != "" {
+		b.WriteString("\n")
+		b.WriteString(text)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// ParseMessageFields reads the header fields of a typed message body. Only
+// fields the type's schema recognizes are returned, and parsing stops at
+// the first line that isn't one, so free text is never mistaken for a
+// field. Returns nil for types without a schema.
+func ParseMessageFields(t MessageType, body string) map[string]string {
+	schema, ok := MessageSchemas[t]
+	if !ok {
+		return nil
+	}
+	known := make(map[string]bool)
+	for _, f := range schema.fields() {
+		known[f] = true
+	}
+
+	var fields map[string]string
+	for _, line := range strings.Split(body, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !found || !known[key] {
+			break
+		}
+		if fields == nil {
+			fields = make(map[string]string)
+		}
+		fields[key] = strings.TrimSpace(value)
+	}
+	return fields
+}
+
+// ValidateMessageFields checks fields against the type's schema.
+func ValidateMessageFields(t MessageType, fields map[string]string) error {
+	schema, ok := MessageSchemas[t]
+	if !ok {
+		return nil
+	}
+	for _, f := range schema.Required {
+		if fields[f] == "" {
+			return fmt.Errorf("%s message requires field %q", t, f)
+		}
+	}
+	for f, allowed := range schema.Values {
+		if v, ok := fields[f]; ok && !slices.Contains(allowed, v) {
+			return fmt.Errorf("%s message field %q must be one of %s, got %q", t, f, strings.Join(allowed, ", "), v)
+		}
+	}
+	return nil
+}
+
+// CheckMessageFields rejects field names the type's schema doesn't know.
+// Used by senders before formatting a body, where a typo would otherwise
+// turn a field into free text.
+func CheckMessageFields(t MessageType, fields map[string]string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	schema, ok := MessageSchemas[t]
+	if !ok {
+		return fmt.Errorf("%s messages have no fields", t)
+	}
+	known := schema.fields()
+	for f := range fields {
+		if !slices.Contains(known, f) {
+			return fmt.Errorf("unknown field %q for %s message (known: %s)", f, t, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
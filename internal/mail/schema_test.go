@@ -0,0 +1,95 @@
+package mail
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormatAndParseMessageFields(t *testing.T) {
+	fields := map[string]string{FieldAction: "deploy gt-abc", FieldBead: "gt-abc"}
+	body := FormatMessageBody(fields, "Staging is green.\nstatus: not a field")
+
+	want := "action: deploy gt-abc\nbead: gt-abc\n\nStaging is green.\nstatus: not a field"
+	if body != want {
+		t.Fatalf("FormatMessageBody() = %q, want %q", body, want)
+	}
+	if got := ParseMessageFields(TypeApproval, body); !reflect.DeepEqual(got, fields) {
+		t.Errorf("ParseMessageFields() = %v, want %v", got, fields)
+	}
+}
+
+func TestParseMessageFields_StopsAtFreeText(t *testing.T) {
+	got := ParseMessageFields(TypeReport, "Note: this is prose\nstatus: done")
+	if got != nil {
+		t.Errorf("ParseMessageFields() = %v, want nil (first line isn't a field)", got)
+	}
+	if got := ParseMessageFields(TypeNotification, "status: done"); got != nil {
+		t.Errorf("untyped message fields = %v, want nil", got)
+	}
+}
+
+func TestValidateMessageFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     MessageType
+		fields  map[string]string
+		wantErr bool
+	}{
+		{"report with status", TypeReport, map[string]string{FieldStatus: "done"}, false},
+		{"report missing status", TypeReport, nil, true},
+		{"report bad status", TypeReport, map[string]string{FieldStatus: "meh"}, true},
+		{"approval needs action", TypeApproval, map[string]string{FieldBead: "gt-1"}, true},
+		{"question without fields", TypeQuestion, nil, false},
+		{"notification", TypeNotification, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMessageFields(tt.typ, tt.fields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateMessageFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckMessageFields(t *testing.T) {
+	if err := CheckMessageFields(TypeApproval, map[string]string{"acton": "x"}); err == nil {
+		t.Error("expected error for unknown field")
+	}
+	if err := CheckMessageFields(TypeNotification, map[string]string{"bead": "x"}); err == nil {
+		t.Error("expected error for fields on an untyped message")
+	}
+	if err := CheckMessageFields(TypeTask, map[string]string{FieldBead: "gt-1"}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTypedMessageRoundTrip(t *testing.T) {
+	bm := &BeadsMessage{
+		ID:          "hq-1",
+		Title:       "Deploy?",
+		Description: "action: deploy gt-abc\n\nPlease confirm.",
+		Status:      "open",
+		Labels:      []string{"from:gastown/Toast", "msg-type:approval"},
+	}
+	msg := bm.ToMessage()
+	if msg.Type != TypeApproval {
+		t.Fatalf("Type = %q, want approval", msg.Type)
+	}
+	if msg.Fields[FieldAction] != "deploy gt-abc" {
+		t.Errorf("Fields = %v, want action", msg.Fields)
+	}
+	if !NeedsHuman(msg.Type) {
+		t.Error("approval should need a human")
+	}
+}
+
+func TestTypeLabels(t *testing.T) {
+	if got := typeLabels(&Message{Type: TypeNotification}); got != nil {
+		t.Errorf("typeLabels(notification) = %v, want nil", got)
+	}
+	want := []string{"msg-type:question"}
+	if got := typeLabels(&Message{Type: TypeQuestion}); !reflect.DeepEqual(got, want) {
+		t.Errorf("typeLabels(question) = %v, want %v", got, want)
+	}
+}
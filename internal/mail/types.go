@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/atrest"
 )
 
 // Priority levels for messages.
@@ -41,6 +43,16 @@ const (
 
 	// TypeReply is a response to another message.
 	TypeReply MessageType = "reply"
+
+	// TypeQuestion asks the recipient for an answer (see MessageSchemas).
+	TypeQuestion MessageType = "question"
+
+	// TypeReport reports progress or an outcome (see MessageSchemas).
+	TypeReport MessageType = "report"
+
+	// TypeApproval asks the recipient to approve or reject an action
+	// (see MessageSchemas).
+	TypeApproval MessageType = "approval"
 )
 
 // Delivery specifies how a message is delivered to the recipient.
@@ -130,6 +142,25 @@ type Message struct {
 	// DeliveryAckedAt is when receipt was acknowledged.
 	DeliveryAckedAt *time.Time `json:"delivery_acked_at,omitempty"`
 
+	// Folder is where a mail rule filed the message. Filed messages are
+	// hidden from the default inbox view.
+	Folder string `json:"folder,omitempty"`
+
+	// Fields are the "key: value" header lines of a typed message's body
+	// (see MessageSchemas). Nil for untyped messages.
+	Fields map[string]string `json:"fields,omitempty"`
+
+	// Labels are the raw bead labels (beads mode only), matched by mail rules.
+	Labels []string `json:"labels,omitempty"`
+
+	// AckRequired asks the recipient to read the message by AckDeadline;
+	// the Deacon escalates messages still unread after it.
+	AckRequired bool `json:"ack_required,omitempty"`
+	// AckDeadline is when an unread ack-required message becomes overdue.
+	AckDeadline *time.Time `json:"ack_deadline,omitempty"`
+	// AckEscalated records that the missed ack was already escalated.
+	AckEscalated bool `json:"ack_escalated,omitempty"`
+
 	// SuppressNotify tells the router to skip all recipient notification
 	// (no nudge, no banner). Set by the CLI when --no-notify is passed.
 	// In-memory only — not serialized.
@@ -223,6 +254,20 @@ func (m *Message) IsClaimed() bool {
 	return m.ClaimedBy != ""
 }
 
+// BeadLabelPrefix marks a bead a message was filed as (gt mail to-bead).
+const BeadLabelPrefix = "bead:"
+
+// LinkedBeads returns the beads the message was filed as.
+func (m *Message) LinkedBeads() []string {
+	var ids []string
+	for _, l := range m.Labels {
+		if id, ok := strings.CutPrefix(l, BeadLabelPrefix); ok && id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 // Validate checks that the message has valid required fields and routing configuration.
 // Returns an error if required fields are missing or routing targets are not mutually exclusive.
 func (m *Message) Validate() error {
@@ -264,6 +309,11 @@ func (m *Message) Validate() error {
 		return fmt.Errorf("claimed_at is only valid for queue messages")
 	}
 
+	// Typed messages must carry their schema's required fields
+	if err := ValidateMessageFields(m.Type, ParseMessageFields(m.Type, m.Body)); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -301,7 +351,7 @@ type BeadsMessage struct {
 	Priority    int       `json:"priority"`    // 0=urgent, 1=high, 2=normal, 3=low
 	Status      string    `json:"status"`      // open=unread, closed=read
 	CreatedAt   time.Time `json:"created_at"`
-	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, queue:X, channel:X, claimed-by:X, claimed-at:X)
+	Labels      []string  `json:"labels"` // Metadata labels (from:X, thread:X, reply-to:X, msg-type:X, cc:X, queue:X, channel:X, claimed-by:X, claimed-at:X, folder:X, ack-required, ack-deadline:X)
 	Pinned      bool      `json:"pinned,omitempty"`
 	Wisp        bool      `json:"wisp,omitempty"` // Ephemeral message (not synced to git)
 
@@ -315,6 +365,7 @@ type BeadsMessage struct {
 	channel   string     // Channel name (for broadcast messages)
 	claimedBy string     // Who claimed the queue message
 	claimedAt *time.Time // When the queue message was claimed
+	folder    string     // Folder a mail rule filed the message into
 	// Two-phase delivery metadata
 	deliveryState   string
 	deliveryAckedBy string
@@ -333,6 +384,7 @@ func (bm *BeadsMessage) ParseLabels() {
 	bm.channel = ""
 	bm.claimedBy = ""
 	bm.claimedAt = nil
+	bm.folder = ""
 	bm.deliveryState = ""
 	bm.deliveryAckedBy = ""
 	bm.deliveryAckedAt = nil
@@ -359,6 +411,8 @@ func (bm *BeadsMessage) ParseLabels() {
 			if t, err := time.Parse(time.RFC3339, ts); err == nil {
 				bm.claimedAt = &t
 			}
+		} else if strings.HasPrefix(label, FolderLabelPrefix) {
+			bm.folder = strings.TrimPrefix(label, FolderLabelPrefix)
 		}
 	}
 
@@ -399,11 +453,7 @@ func (bm *BeadsMessage) ToMessage() *Message {
 	}
 
 	// Convert message type, default to notification
-	msgType := TypeNotification
-	switch MessageType(bm.msgType) {
-	case TypeTask, TypeScavenge, TypeReply:
-		msgType = MessageType(bm.msgType)
-	}
+	msgType := ParseMessageType(bm.msgType)
 
 	// Convert CC identities to addresses
 	var ccAddrs []string
@@ -411,12 +461,21 @@ func (bm *BeadsMessage) ToMessage() *Message {
 		ccAddrs = append(ccAddrs, identityToAddress(cc))
 	}
 
+	ackRequired, ackDeadline, ackEscalated := ParseAckLabels(bm.Labels)
+
+	// Bodies sealed at rest are opened with the town key; without it the
+	// message is still listed, with a placeholder body.
+	body, err := atrest.OpenString(bm.Description)
+	if err != nil {
+		body = fmt.Sprintf("[encrypted message: %v]", err)
+	}
+
 	return &Message{
 		ID:              bm.ID,
 		From:            identityToAddress(bm.sender),
 		To:              identityToAddress(bm.Assignee),
 		Subject:         bm.Title,
-		Body:            bm.Description,
+		Body:            body,
 		Timestamp:       bm.CreatedAt,
 		Read:            bm.Status == "closed" || bm.HasLabel("read"),
 		Priority:        priority,
@@ -432,6 +491,12 @@ func (bm *BeadsMessage) ToMessage() *Message {
 		DeliveryState:   bm.deliveryState,
 		DeliveryAckedBy: bm.deliveryAckedBy,
 		DeliveryAckedAt: bm.deliveryAckedAt,
+		Folder:          bm.folder,
+		Fields:          ParseMessageFields(msgType, body),
+		Labels:          bm.Labels,
+		AckRequired:     ackRequired,
+		AckDeadline:     ackDeadline,
+		AckEscalated:    ackEscalated,
 	}
 }
 
@@ -529,7 +594,7 @@ func PriorityFromInt(p int) Priority {
 // ParseMessageType parses a message type string, returning TypeNotification for invalid values.
 func ParseMessageType(s string) MessageType {
 	switch MessageType(s) {
-	case TypeTask, TypeScavenge, TypeNotification, TypeReply:
+	case TypeTask, TypeScavenge, TypeNotification, TypeReply, TypeQuestion, TypeReport, TypeApproval:
 		return MessageType(s)
 	default:
 		return TypeNotification
@@ -2,8 +2,11 @@ package mail
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/atrest"
 )
 
 func TestAddressToIdentity(t *testing.T) {
@@ -351,6 +354,24 @@ func TestBeadsMessageToMessageEmptyLabels(t *testing.T) {
 	}
 }
 
+func TestBeadsMessageToMessageSealedBody(t *testing.T) {
+	t.Setenv(atrest.EnvKey, "mailtest-1:"+strings.Repeat("A", 43)+"=")
+	sealed, err := atrest.SealString("mailtest-1", "Deploy window moved to 14:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bm := BeadsMessage{ID: "hq-sealed", Title: "Deploy", Description: sealed, Assignee: "mayor/"}
+	if msg := bm.ToMessage(); msg.Body != "Deploy window moved to 14:00" {
+		t.Errorf("Body = %q, want the opened body", msg.Body)
+	}
+
+	bm.Description = strings.Replace(sealed, "mailtest-1", "mailtest-missing", 1)
+	if msg := bm.ToMessage(); !strings.HasPrefix(msg.Body, "[encrypted message:") || msg.Subject != "Deploy" {
+		t.Errorf("without the key: Subject = %q, Body = %q", msg.Subject, msg.Body)
+	}
+}
+
 func TestNewQueueMessage(t *testing.T) {
 	msg := NewQueueMessage("mayor/", "work-requests", "New Task", "Please process this")
 
@@ -926,3 +947,14 @@ func TestNewMessageFanOutCopiesGetUniqueIDs(t *testing.T) {
 		t.Error("copy with empty ID should fail validation before sendToSingle regenerates it")
 	}
 }
+
+func TestLinkedBeads(t *testing.T) {
+	msg := &Message{Labels: []string{"from:mayor", BeadLabelPrefix + "gt-abc", "folder:ideas", BeadLabelPrefix}}
+	got := msg.LinkedBeads()
+	if len(got) != 1 || got[0] != "gt-abc" {
+		t.Errorf("LinkedBeads() = %v, want [gt-abc]", got)
+	}
+	if (&Message{}).LinkedBeads() != nil {
+		t.Error("unlabeled message should have no linked beads")
+	}
+}
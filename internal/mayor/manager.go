@@ -46,6 +46,7 @@ type MayorStatus struct {
 // Manager handles mayor lifecycle operations.
 type Manager struct {
 	townRoot string
+	shard    string // empty for the primary Mayor
 }
 
 // CombinedStatus returns the combined status of the mayor across all modes.
@@ -65,8 +66,8 @@ func (m *Manager) CombinedStatus() (*MayorStatus, error) {
 		}
 	}
 
-	// Check ACP
-	if IsACPActive(m.townRoot) {
+	// Check ACP (primary Mayor only)
+	if m.shard == "" && IsACPActive(m.townRoot) {
 		status.Active = true
 		if status.Mode == ModeTMUX {
 			status.Mode = ModeBoth
@@ -93,6 +94,29 @@ func NewManager(townRoot string) *Manager {
 	}
 }
 
+// NewShardManager creates a manager for a shard mayor, which coordinates a
+// subset of the town's rigs (see package shard). Shard mayors run in tmux
+// only; ACP applies to the primary Mayor.
+func NewShardManager(townRoot, shard string) *Manager {
+	return &Manager{
+		townRoot: townRoot,
+		shard:    shard,
+	}
+}
+
+// Shard returns the shard this manager controls, or "" for the primary Mayor.
+func (m *Manager) Shard() string {
+	return m.shard
+}
+
+// Address returns the mail address of the managed mayor.
+func (m *Manager) Address() string {
+	if m.shard != "" {
+		return "mayor/" + m.shard
+	}
+	return "mayor/"
+}
+
 // SessionName returns the tmux session name for the mayor.
 // This is a package-level function for convenience.
 func SessionName() string {
@@ -101,11 +125,19 @@ func SessionName() string {
 
 // SessionName returns the tmux session name for the mayor.
 func (m *Manager) SessionName() string {
-	return SessionName()
+	return m.identity().SessionName()
+}
+
+func (m *Manager) identity() *session.AgentIdentity {
+	return &session.AgentIdentity{Role: session.RoleMayor, Name: m.shard}
 }
 
 // mayorDir returns the working directory for the mayor.
+// Shard mayors work from mayor/shards/<shard>.
 func (m *Manager) mayorDir() string {
+	if m.shard != "" {
+		return filepath.Join(m.townRoot, "mayor", "shards", m.shard)
+	}
 	return filepath.Join(m.townRoot, "mayor")
 }
 
@@ -113,6 +145,9 @@ func (m *Manager) mayorDir() string {
 // It checks both TMUX and ACP modes and returns ErrAlreadyRunning if active.
 // agentOverride optionally specifies a different agent alias to use.
 func (m *Manager) Start(agentOverride string) error {
+	if m.shard != "" {
+		return m.StartTMUX(agentOverride)
+	}
 	status, err := m.CombinedStatus()
 	if err == nil && status.Active {
 		// If ACP is active, return ErrACPActive so callers can distinguish
@@ -129,7 +164,7 @@ func (m *Manager) Start(agentOverride string) error {
 // StartTMUX starts the mayor session in TMUX mode.
 // agentOverride optionally specifies a different agent alias to use.
 func (m *Manager) StartTMUX(agentOverride string) error {
-	if IsACPActive(m.townRoot) {
+	if m.shard == "" && IsACPActive(m.townRoot) {
 		return ErrAlreadyRunning
 	}
 
@@ -149,6 +184,13 @@ func (m *Manager) StartTMUX(agentOverride string) error {
 		return fmt.Errorf("creating mayor directory: %w", err)
 	}
 
+	agentName := "Mayor"
+	var extraEnv map[string]string
+	if m.shard != "" {
+		agentName = "Mayor " + m.shard
+		extraEnv = map[string]string{"GT_MAYOR_SHARD": m.shard}
+	}
+
 	// Use unified session lifecycle for config → settings → command → create → env → theme → wait.
 	theme := tmux.MayorTheme()
 	_, err = session.StartSession(t, session.SessionConfig{
@@ -156,12 +198,13 @@ func (m *Manager) StartTMUX(agentOverride string) error {
 		WorkDir:   mayorDir,
 		Role:      "mayor",
 		TownRoot:  m.townRoot,
-		AgentName: "Mayor",
+		AgentName: agentName,
 		Beacon: session.BeaconConfig{
-			Recipient: "mayor",
+			Recipient: m.identity().BeaconAddress(),
 			Sender:    "human",
 			Topic:     "cold-start",
 		},
+		ExtraEnv:      extraEnv,
 		AgentOverride: agentOverride,
 		Theme:         &theme,
 		WaitForAgent:  true,
@@ -181,6 +224,9 @@ func (m *Manager) StartTMUX(agentOverride string) error {
 // StartACP starts the mayor session in ACP mode.
 // This handles the transition from TMUX to ACP mode.
 func (m *Manager) StartACP(ctx context.Context, agentOverride, rigName string) error {
+	if m.shard != "" {
+		return fmt.Errorf("ACP is not supported for shard mayors")
+	}
 	// Check if an ACP session is already running - only one ACP session is allowed
 	// because they share the same PID file. Starting a second one would overwrite
 	// the PID file, causing the first session's proxy to detect "PID file removed"
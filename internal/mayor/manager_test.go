@@ -48,6 +48,22 @@ func TestManager_SessionName_MatchesPackageFunc(t *testing.T) {
 	}
 }
 
+func TestShardManager(t *testing.T) {
+	m := NewShardManager("/tmp/test-town", "west")
+	if got := m.SessionName(); got != "hq-mayor-west" {
+		t.Errorf("SessionName() = %q, want %q", got, "hq-mayor-west")
+	}
+	if got := m.Address(); got != "mayor/west" {
+		t.Errorf("Address() = %q, want %q", got, "mayor/west")
+	}
+	if got, want := m.mayorDir(), filepath.Join("/tmp/test-town", "mayor", "shards", "west"); got != want {
+		t.Errorf("mayorDir() = %q, want %q", got, want)
+	}
+	if got := NewManager("/tmp/test-town").Address(); got != "mayor/" {
+		t.Errorf("primary Address() = %q, want %q", got, "mayor/")
+	}
+}
+
 func TestManager_Errors(t *testing.T) {
 	if ErrNotRunning.Error() != "mayor not running" {
 		t.Errorf("ErrNotRunning = %q", ErrNotRunning)
@@ -0,0 +1,89 @@
+package mayor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+const rotationFileName = "rotation.json"
+
+// Rotation records the last time the Mayor's session was rotated: replaced
+// with a fresh session that picks up a carryover summary from its hook.
+type Rotation struct {
+	At        time.Time `json:"at"`
+	Reason    string    `json:"reason,omitempty"`    // "manual", "scheduled", ...
+	Carryover string    `json:"carryover,omitempty"` // handoff mail bead ID
+
+	// Requested is when a scheduled rotation asked the Mayor to hand off
+	// itself. The Mayor's own rotation replaces the record, clearing it.
+	Requested time.Time `json:"requested,omitzero"`
+}
+
+// RotationFilePath returns the path of the rotation record for the primary
+// Mayor (shard "") or a shard mayor.
+func RotationFilePath(townRoot, shard string) string {
+	if shard != "" {
+		return filepath.Join(townRoot, "mayor", "shards", shard, constants.DirRuntime, rotationFileName)
+	}
+	return filepath.Join(townRoot, "mayor", constants.DirRuntime, rotationFileName)
+}
+
+// RecordRotation persists the latest rotation.
+func RecordRotation(townRoot, shard string, r Rotation) error {
+	path := RotationFilePath(townRoot, shard)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing rotation record: %w", err)
+	}
+	return nil
+}
+
+// LastRotation returns the latest rotation, or nil if the Mayor has never
+// been rotated (or the record is unreadable).
+func LastRotation(townRoot, shard string) *Rotation {
+	data, err := os.ReadFile(RotationFilePath(townRoot, shard))
+	if err != nil {
+		return nil
+	}
+	var r Rotation
+	if err := json.Unmarshal(data, &r); err != nil || (r.At.IsZero() && r.Requested.IsZero()) {
+		return nil
+	}
+	return &r
+}
+
+// RecordRotationRequest notes that the Mayor was asked to rotate itself,
+// keeping the last rotation.
+func RecordRotationRequest(townRoot, shard string, at time.Time) error {
+	var r Rotation
+	if last := LastRotation(townRoot, shard); last != nil {
+		r = *last
+	}
+	r.Requested = at
+	return RecordRotation(townRoot, shard, r)
+}
+
+// SessionAge returns how long the current Mayor context has been running:
+// time since the later of the tmux session's creation and the last rotation.
+// A zero sessionStart is treated as unknown and only the rotation counts.
+func SessionAge(now, sessionStart time.Time, last *Rotation) time.Duration {
+	start := sessionStart
+	if last != nil && last.At.After(start) {
+		start = last.At
+	}
+	if start.IsZero() {
+		return 0
+	}
+	return now.Sub(start)
+}
@@ -0,0 +1,98 @@
+package mayor
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRecordAndLastRotation(t *testing.T) {
+	townRoot := t.TempDir()
+
+	if got := LastRotation(townRoot, ""); got != nil {
+		t.Fatalf("LastRotation with no record = %+v, want nil", got)
+	}
+
+	at := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	if err := RecordRotation(townRoot, "", Rotation{At: at, Reason: "scheduled", Carryover: "hq-abc"}); err != nil {
+		t.Fatalf("RecordRotation: %v", err)
+	}
+	got := LastRotation(townRoot, "")
+	if got == nil || !got.At.Equal(at) || got.Reason != "scheduled" || got.Carryover != "hq-abc" {
+		t.Errorf("LastRotation = %+v", got)
+	}
+}
+
+func TestRotation_PerShard(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := RecordRotation(townRoot, "west", Rotation{At: time.Now(), Reason: "manual"}); err != nil {
+		t.Fatalf("RecordRotation: %v", err)
+	}
+	if got := LastRotation(townRoot, "west"); got == nil {
+		t.Error("LastRotation(west) = nil, want the shard's record")
+	}
+	if got := LastRotation(townRoot, ""); got != nil {
+		t.Errorf("LastRotation(primary) = %+v, want nil: shard rotations are separate", got)
+	}
+}
+
+func TestLastRotation_Corrupt(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := RecordRotation(townRoot, "", Rotation{At: time.Now()}); err != nil {
+		t.Fatalf("RecordRotation: %v", err)
+	}
+	if err := os.WriteFile(RotationFilePath(townRoot, ""), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := LastRotation(townRoot, ""); got != nil {
+		t.Errorf("LastRotation with corrupt record = %+v, want nil", got)
+	}
+}
+
+func TestSessionAge(t *testing.T) {
+	now := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	created := now.Add(-10 * time.Hour)
+
+	tests := []struct {
+		name  string
+		start time.Time
+		last  *Rotation
+		want  time.Duration
+	}{
+		{"never rotated", created, nil, 10 * time.Hour},
+		{"rotated since start", created, &Rotation{At: now.Add(-2 * time.Hour)}, 2 * time.Hour},
+		{"rotation predates session", created, &Rotation{At: now.Add(-30 * time.Hour)}, 10 * time.Hour},
+		{"unknown start", time.Time{}, &Rotation{At: now.Add(-time.Hour)}, time.Hour},
+		{"nothing known", time.Time{}, nil, 0},
+	}
+	for _, tt := range tests {
+		if got := SessionAge(now, tt.start, tt.last); got != tt.want {
+			t.Errorf("%s: SessionAge() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRecordRotationRequest(t *testing.T) {
+	townRoot := t.TempDir()
+	at := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	if err := RecordRotation(townRoot, "", Rotation{At: at, Reason: "manual"}); err != nil {
+		t.Fatalf("RecordRotation: %v", err)
+	}
+
+	asked := at.Add(8 * time.Hour)
+	if err := RecordRotationRequest(townRoot, "", asked); err != nil {
+		t.Fatalf("RecordRotationRequest: %v", err)
+	}
+	got := LastRotation(townRoot, "")
+	if got == nil || !got.At.Equal(at) || !got.Requested.Equal(asked) {
+		t.Errorf("LastRotation = %+v, want the rotation kept and the request added", got)
+	}
+
+	// The Mayor's own rotation clears the request.
+	if err := RecordRotation(townRoot, "", Rotation{At: asked.Add(time.Minute)}); err != nil {
+		t.Fatalf("RecordRotation: %v", err)
+	}
+	if got := LastRotation(townRoot, ""); got == nil || !got.Requested.IsZero() {
+		t.Errorf("LastRotation = %+v, want no pending request", got)
+	}
+}
@@ -0,0 +1,219 @@
+// Package microtask tracks micro-task queues: a handful of tiny beads (doc
+// fixes, one-liners) worked one after another by a single polecat session
+// on one branch, instead of a spawn, worktree and merge request per bead.
+//
+// The first bead of a queue is the head: it is hooked to the polecat and
+// its merge request carries the whole batch. The polecat finishes each task
+// with gt micro next, which records the commit and starts the next one.
+package microtask
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxTasks caps a queue. Micro-tasks share one session's context, so a long
+// queue defeats the point.
+const MaxTasks = 8
+
+// Task statuses.
+const (
+	StatusPending  = "pending"  // Waiting its turn
+	StatusActive   = "active"   // Being worked
+	StatusDone     = "done"     // Committed on the batch branch
+	StatusSkipped  = "skipped"  // Given back by the polecat
+	StatusReturned = "returned" // Unfinished when the session ended
+)
+
+// ErrEmpty is returned when a queue has no active task.
+var ErrEmpty = errors.New("micro-task queue is finished")
+
+// Task is one bead in a queue.
+type Task struct {
+	Bead     string    `json:"bead"`
+	Title    string    `json:"title,omitempty"`
+	Status   string    `json:"status"`
+	Commit   string    `json:"commit,omitempty"` // HEAD when the task was finished
+	Note     string    `json:"note,omitempty"`
+	Started  time.Time `json:"started,omitempty"`
+	Finished time.Time `json:"finished,omitempty"`
+}
+
+// Queue is a polecat's micro-task queue, keyed by its head bead.
+type Queue struct {
+	Head    string    `json:"head"`
+	Rig     string    `json:"rig"`
+	Polecat string    `json:"polecat,omitempty"` // Filled in when the polecat first reports
+	Created time.Time `json:"created"`
+	Tasks   []*Task   `json:"tasks"`
+}
+
+// New returns a queue for beads (with their titles) in rig. The first bead
+// is the head and starts active.
+func New(rig string, beads, titles []string, now time.Time) (*Queue, error) {
+	if len(beads) < 2 {
+		return nil, fmt.Errorf("a micro-task queue needs at least 2 beads")
+	}
+	if len(beads) > MaxTasks {
+		return nil, fmt.Errorf("%d beads is too many for one micro-task queue (max %d)", len(beads), MaxTasks)
+	}
+	q := &Queue{Head: beads[0], Rig: rig, Created: now}
+	seen := make(map[string]bool, len(beads))
+	for i, id := range beads {
+		if seen[id] {
+			return nil, fmt.Errorf("%s is queued twice", id)
+		}
+		seen[id] = true
+		t := &Task{Bead: id, Status: StatusPending}
+		if i < len(titles) {
+			t.Title = titles[i]
+		}
+		q.Tasks = append(q.Tasks, t)
+	}
+	q.Tasks[0].Status, q.Tasks[0].Started = StatusActive, now
+	return q, nil
+}
+
+// Current returns the active task, or nil when the queue is finished.
+func (q *Queue) Current() *Task {
+	for _, t := range q.Tasks {
+		if t.Status == StatusActive {
+			return t
+		}
+	}
+	return nil
+}
+
+// Advance finishes the active task with status (done or skipped), commit
+// and note, and starts the next pending task. It returns the finished task
+// and the new active one, nil when the queue is finished.
+func (q *Queue) Advance(status, commit, note string, now time.Time) (finished, next *Task, err error) {
+	if status != StatusDone && status != StatusSkipped {
+		return nil, nil, fmt.Errorf("invalid status %q", status)
+	}
+	finished = q.Current()
+	if finished == nil {
+		return nil, nil, ErrEmpty
+	}
+	finished.Status, finished.Commit, finished.Note, finished.Finished = status, commit, note, now
+	for _, t := range q.Tasks {
+		if t.Status == StatusPending {
+			t.Status, t.Started = StatusActive, now
+			return finished, t, nil
+		}
+	}
+	return finished, nil, nil
+}
+
+// Release marks every unfinished task other than the head returned and
+// returns them; the head is finished by its own merge request.
+func (q *Queue) Release(now time.Time) []*Task {
+	var returned []*Task
+	for _, t := range q.Tasks {
+		if t.Bead == q.Head || (t.Status != StatusPending && t.Status != StatusActive) {
+			continue
+		}
+		t.Status, t.Finished = StatusReturned, now
+		returned = append(returned, t)
+	}
+	return returned
+}
+
+// Count returns how many tasks have status.
+func (q *Queue) Count(status string) int {
+	n := 0
+	for _, t := range q.Tasks {
+		if t.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Progress summarizes the queue ("2/5 done, 1 skipped").
+func (q *Queue) Progress() string {
+	s := fmt.Sprintf("%d/%d done", q.Count(StatusDone), len(q.Tasks))
+	for _, status := range []string{StatusSkipped, StatusReturned} {
+		if n := q.Count(status); n > 0 {
+			s += fmt.Sprintf(", %d %s", n, status)
+		}
+	}
+	return s
+}
+
+// Dir returns the directory holding the town's queues.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "microtasks")
+}
+
+func path(townRoot, head string) string {
+	return filepath.Join(Dir(townRoot), head+".json")
+}
+
+// Load returns the queue headed by head, or nil if there is none.
+func Load(townRoot, head string) (*Queue, error) {
+	if head == "" || strings.ContainsAny(head, `/\`) {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path(townRoot, head)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	q := &Queue{}
+	if err := json.Unmarshal(data, q); err != nil {
+		return nil, fmt.Errorf("parsing micro-task queue %s: %w", head, err)
+	}
+	return q, nil
+}
+
+// Save writes the queue.
+func Save(townRoot string, q *Queue) error {
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path(townRoot, q.Head), data, 0644) //nolint:gosec // G306: runtime state
+}
+
+// Remove deletes the queue headed by head.
+func Remove(townRoot, head string) error {
+	if err := os.Remove(path(townRoot, head)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// List returns the town's queues, oldest first. Unreadable files are
+// skipped.
+func List(townRoot string) ([]*Queue, error) {
+	entries, err := os.ReadDir(Dir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var queues []*Queue
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		if q, err := Load(townRoot, strings.TrimSuffix(e.Name(), ".json")); err == nil && q != nil {
+			queues = append(queues, q)
+		}
+	}
+	sort.Slice(queues, func(i, j int) bool { return queues[i].Created.Before(queues[j].Created) })
+	return queues, nil
+}
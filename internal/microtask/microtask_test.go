@@ -0,0 +1,108 @@
+package microtask
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	now := time.Now()
+	if _, err := New("gastown", []string{"gt-1"}, nil, now); err == nil {
+		t.Error("a single bead should not make a queue")
+	}
+	if _, err := New("gastown", []string{"gt-1", "gt-2", "gt-1"}, nil, now); err == nil {
+		t.Error("duplicate beads should be refused")
+	}
+	many := make([]string, MaxTasks+1)
+	for i := range many {
+		many[i] = string(rune('a' + i))
+	}
+	if _, err := New("gastown", many, nil, now); err == nil {
+		t.Error("an oversized queue should be refused")
+	}
+
+	q, err := New("gastown", []string{"gt-1", "gt-2"}, []string{"Fix typo", "Bump doc"}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Head != "gt-1" || q.Current().Bead != "gt-1" || q.Tasks[1].Title != "Bump doc" {
+		t.Errorf("queue = %+v", q)
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	now := time.Now()
+	q, err := New("gastown", []string{"gt-1", "gt-2", "gt-3"}, nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done, next, err := q.Advance(StatusDone, "abc123", "", now)
+	if err != nil || done.Bead != "gt-1" || done.Commit != "abc123" || next.Bead != "gt-2" {
+		t.Fatalf("Advance = %+v, %+v, %v", done, next, err)
+	}
+	skipped, next, err := q.Advance(StatusSkipped, "", "needs design", now)
+	if err != nil || skipped.Status != StatusSkipped || skipped.Note != "needs design" || next.Bead != "gt-3" {
+		t.Fatalf("skip = %+v, %+v, %v", skipped, next, err)
+	}
+	if _, next, err = q.Advance(StatusDone, "def456", "", now); err != nil || next != nil {
+		t.Fatalf("last Advance = %+v, %v", next, err)
+	}
+	if _, _, err := q.Advance(StatusDone, "", "", now); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Advance on a finished queue = %v, want ErrEmpty", err)
+	}
+	if got := q.Progress(); got != "2/3 done, 1 skipped" {
+		t.Errorf("Progress = %q", got)
+	}
+}
+
+func TestRelease(t *testing.T) {
+	now := time.Now()
+	q, err := New("gastown", []string{"gt-1", "gt-2", "gt-3"}, nil, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := q.Advance(StatusDone, "abc123", "", now); err != nil {
+		t.Fatal(err)
+	}
+	returned := q.Release(now)
+	if len(returned) != 2 || returned[0].Bead != "gt-2" || q.Tasks[0].Status != StatusDone {
+		t.Errorf("Release = %+v", returned)
+	}
+
+	// The head is never returned: its merge request finishes it.
+	q, _ = New("gastown", []string{"gt-1", "gt-2"}, nil, now)
+	if returned := q.Release(now); len(returned) != 1 || returned[0].Bead != "gt-2" {
+		t.Errorf("Release with active head = %+v", returned)
+	}
+}
+
+func TestSaveLoadList(t *testing.T) {
+	town := t.TempDir()
+	if q, err := Load(town, "gt-1"); q != nil || err != nil {
+		t.Fatalf("Load of missing queue = %+v, %v", q, err)
+	}
+	now := time.Now()
+	a, _ := New("gastown", []string{"gt-1", "gt-2"}, nil, now)
+	b, _ := New("beads", []string{"bd-1", "bd-2"}, nil, now.Add(-time.Hour))
+	for _, q := range []*Queue{a, b} {
+		if err := Save(town, q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := Load(town, "gt-1")
+	if err != nil || got.Rig != "gastown" || len(got.Tasks) != 2 {
+		t.Fatalf("Load = %+v, %v", got, err)
+	}
+	all, err := List(town)
+	if err != nil || len(all) != 2 || all[0].Head != "bd-1" {
+		t.Fatalf("List = %+v, %v", all, err)
+	}
+	if err := Remove(town, "gt-1"); err != nil {
+		t.Fatal(err)
+	}
+	if all, _ := List(town); len(all) != 1 {
+		t.Errorf("List after Remove = %d queues", len(all))
+	}
+}
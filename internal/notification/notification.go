@@ -0,0 +1,393 @@
+// Package notification raises desktop notifications for events an operator
+// shouldn't miss in a terminal scrollback: escalations, approval requests,
+// convoys landing and spend crossing the daily budget.
+//
+// Every notification raised while notifications are enabled is kept in the
+// notification center (a log under .runtime/notifications), including the
+// ones that were not shown because of do-not-disturb, quiet hours or the
+// minimum severity, so they can be reviewed later with gt notifications.
+//
+// Configured in town settings:
+//
+//	"notifications": {
+//	  "enabled": true,
+//	  "min_severity": "normal",
+//	  "events": {"convoy_landed": "off", "approval": "critical"},
+//	  "daily_budget_usd": 200
+//	}
+package notification
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// Events that raise notifications.
+const (
+	EventEscalation   = "escalation"    // gt escalate
+	EventApproval     = "approval"      // Mail sent with --type approval
+	EventConvoyLanded = "convoy_landed" // All of a convoy's issues closed
+	EventBudget       = "budget"        // Today's spend crossed the daily budget
+	EventTest         = "test"          // gt notifications test
+)
+
+// Events lists the configurable events.
+var Events = []string{EventEscalation, EventApproval, EventConvoyLanded, EventBudget}
+
+// Severities, lowest first. Critical notifications break through
+// do-not-disturb and quiet hours; SeverityOff silences an event.
+const (
+	SeverityOff      = "off"
+	SeverityLow      = "low"
+	SeverityNormal   = "normal"
+	SeverityCritical = "critical"
+)
+
+var severityRank = map[string]int{SeverityLow: 1, SeverityNormal: 2, SeverityCritical: 3}
+
+// Reasons a notification was recorded but not shown.
+const (
+	HeldBelowMin = "below min severity"
+	HeldDND      = "do not disturb"
+	HeldQuiet    = "quiet hours"
+)
+
+// maxRecords is how many notifications the center keeps.
+const maxRecords = 500
+
+// ErrUnsupported is returned when there is no desktop notifier for this
+// platform.
+var ErrUnsupported = errors.New("desktop notifications need osascript (macOS) or notify-send (Linux)")
+
+// Config is the town's notification settings.
+type Config struct {
+	Enabled bool `json:"enabled"`
+
+	// MinSeverity is the lowest severity shown on the desktop (default low).
+	MinSeverity string `json:"min_severity,omitempty"`
+
+	// Events overrides the severity an event is raised with, by event
+	// name. "off" silences the event entirely.
+	Events map[string]string `json:"events,omitempty"`
+
+	// DailyBudgetUSD raises budget notifications when the day's recorded
+	// session costs reach 80% and 100% of it. Zero disables them.
+	DailyBudgetUSD float64 `json:"daily_budget_usd,omitempty"`
+}
+
+// IsEnabled reports whether notifications are configured and on.
+func (c *Config) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// Validate checks severities and event names. A nil config is valid.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MinSeverity != "" && severityRank[c.MinSeverity] == 0 {
+		return fmt.Errorf("min_severity: invalid severity %q (use low, normal or critical)", c.MinSeverity)
+	}
+	for event, sev := range c.Events {
+		if !isEvent(event) {
+			return fmt.Errorf("events: unknown event %q (known: %s)", event, strings.Join(Events, ", "))
+		}
+		if sev != SeverityOff && severityRank[sev] == 0 {
+			return fmt.Errorf("events.%s: invalid severity %q (use off, low, normal or critical)", event, sev)
+		}
+	}
+	if c.DailyBudgetUSD < 0 {
+		return fmt.Errorf("daily_budget_usd: must not be negative")
+	}
+	return nil
+}
+
+// SeverityFor returns the severity event is raised with: the configured
+// override, or def.
+func (c *Config) SeverityFor(event, def string) string {
+	if c != nil {
+		if sev, ok := c.Events[event]; ok {
+			return sev
+		}
+	}
+	return def
+}
+
+func isEvent(event string) bool {
+	for _, e := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification is one event to tell the operator about.
+type Notification struct {
+	Event    string `json:"event"`
+	Severity string `json:"severity"` // Default for the event; config may override
+	Title    string `json:"title"`
+	Body     string `json:"body,omitempty"`
+	// Key, when set, makes the notification one-shot: a later one with the
+	// same key is dropped (e.g. one budget warning per day).
+	Key string `json:"key,omitempty"`
+}
+
+// Record is a notification as kept in the center.
+type Record struct {
+	Notification
+	Time      time.Time `json:"time"`
+	Delivered bool      `json:"delivered"`
+	Held      string    `json:"held,omitempty"` // Why it wasn't shown
+}
+
+// deliverFn is a seam for tests. Production uses Desktop.
+var deliverFn = Desktop
+
+// Raise records n in the center and shows it on the desktop unless it is
+// below the minimum severity, or do-not-disturb or quiet hours are on and
+// it isn't critical. Returns nil when notifications are disabled, the
+// event is off, or n's key was already raised.
+func Raise(townRoot string, cfg *Config, quietActive bool, n Notification, now time.Time) (*Record, error) {
+	if !cfg.IsEnabled() {
+		return nil, nil
+	}
+	n.Severity = cfg.SeverityFor(n.Event, n.Severity)
+	if n.Severity == SeverityOff {
+		return nil, nil
+	}
+	if severityRank[n.Severity] == 0 {
+		n.Severity = SeverityNormal
+	}
+
+	var rec *Record
+	err := withLock(townRoot, func(path string) error {
+		records, err := readRecords(path)
+		if err != nil {
+			return err
+		}
+		if n.Key != "" {
+			for _, r := range records {
+				if r.Key == n.Key {
+					return nil
+				}
+			}
+		}
+
+		rec = &Record{Notification: n, Time: now.UTC()}
+		dnd, _ := DNDActive(townRoot, now)
+		minSev := cfg.MinSeverity
+		if minSev == "" {
+			minSev = SeverityLow
+		}
+		switch {
+		case severityRank[n.Severity] < severityRank[minSev]:
+			rec.Held = HeldBelowMin
+		case n.Severity != SeverityCritical && dnd:
+			rec.Held = HeldDND
+		case n.Severity != SeverityCritical && quietActive:
+			rec.Held = HeldQuiet
+		default:
+			if err := deliverFn(n); err != nil {
+				rec.Held = err.Error()
+			} else {
+				rec.Delivered = true
+			}
+		}
+
+		records = append(records, *rec)
+		if len(records) > maxRecords {
+			records = records[len(records)-maxRecords:]
+		}
+		return writeRecords(path, records)
+	})
+	return rec, err
+}
+
+// List returns up to limit recorded notifications, newest first. A limit of
+// zero or less returns them all.
+func List(townRoot string, limit int) ([]Record, error) {
+	records, err := readRecords(logPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Record, 0, len(records))
+	for i := len(records) - 1; i >= 0; i-- {
+		out = append(out, records[i])
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+// Desktop shows n with the platform's notifier.
+func Desktop(n Notification) error {
+	name, args, err := desktopCommand(runtime.GOOS, n)
+	if err != nil {
+		return err
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return ErrUnsupported
+	}
+	out, err := exec.Command(name, args...).CombinedOutput() //nolint:gosec // G204: fixed notifier binary
+	if err != nil {
+		return fmt.Errorf("%s: %v: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Notifier returns the desktop notifier this platform would use, or "" if
+// there is none.
+func Notifier() string {
+	name, _, err := desktopCommand(runtime.GOOS, Notification{})
+	if err != nil {
+		return ""
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return ""
+	}
+	return name
+}
+
+// desktopCommand builds the notifier invocation for goos.
+func desktopCommand(goos string, n Notification) (string, []string, error) {
+	title := "Gas Town: " + n.Title
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s subtitle %s",
+			appleScriptString(n.Body), appleScriptString(title), appleScriptString(n.Event))
+		if n.Severity == SeverityCritical {
+			script += ` sound name "Basso"`
+		}
+		return "osascript", []string{"-e", script}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		urgency := n.Severity
+		if urgency == "" {
+			urgency = SeverityNormal
+		}
+		return "notify-send", []string{"--app-name=Gas Town", "--urgency=" + urgency, "--category=" + n.Event, title, n.Body}, nil
+	default:
+		return "", nil, ErrUnsupported
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Dir returns the directory holding the notification center.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "notifications")
+}
+
+func logPath(townRoot string) string { return filepath.Join(Dir(townRoot), "log.jsonl") }
+func dndPath(townRoot string) string { return filepath.Join(Dir(townRoot), "dnd.json") }
+
+func withLock(townRoot string, fn func(path string) error) error {
+	path := logPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating notifications directory: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking notifications: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+	return fn(path)
+}
+
+func readRecords(path string) ([]Record, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err == nil {
+			records = append(records, r)
+		}
+	}
+	return records, scanner.Err()
+}
+
+func writeRecords(path string, records []Record) error {
+	var b strings.Builder
+	for _, r := range records {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil { //nolint:gosec // G306: runtime state
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// dndState is the do-not-disturb switch. A zero Until means on until
+// turned off.
+type dndState struct {
+	On    bool      `json:"on"`
+	Until time.Time `json:"until,omitempty"`
+}
+
+// SetDND turns do-not-disturb on, until the given time or (zero) until
+// ClearDND.
+func SetDND(townRoot string, until time.Time) error {
+	data, err := json.MarshalIndent(dndState{On: true, Until: until}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dndPath(townRoot), data, 0644) //nolint:gosec // G306: runtime state
+}
+
+// ClearDND turns do-not-disturb off.
+func ClearDND(townRoot string) error {
+	if err := os.Remove(dndPath(townRoot)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// DNDActive reports whether do-not-disturb is on at now, and when it ends
+// (zero when it lasts until turned off).
+func DNDActive(townRoot string, now time.Time) (bool, time.Time) {
+	data, err := os.ReadFile(dndPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return false, time.Time{}
+	}
+	var s dndState
+	if json.Unmarshal(data, &s) != nil || !s.On {
+		return false, time.Time{}
+	}
+	if !s.Until.IsZero() && !now.Before(s.Until) {
+		return false, time.Time{}
+	}
+	return true, s.Until
+}
@@ -0,0 +1,194 @@
+package notification
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func stubDeliver(t *testing.T, err error) *[]Notification {
+	t.Helper()
+	orig := deliverFn
+	t.Cleanup(func() { deliverFn = orig })
+	var shown []Notification
+	deliverFn = func(n Notification) error {
+		if err != nil {
+			return err
+		}
+		shown = append(shown, n)
+		return nil
+	}
+	return &shown
+}
+
+func TestRaise(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+	cfg := &Config{
+		Enabled:     true,
+		MinSeverity: SeverityNormal,
+		Events:      map[string]string{EventConvoyLanded: SeverityOff, EventApproval: SeverityCritical},
+	}
+
+	tests := []struct {
+		name      string
+		cfg       *Config
+		quiet     bool
+		dnd       bool
+		n         Notification
+		wantNil   bool
+		delivered bool
+		held      string
+		severity  string
+	}{
+		{name: "disabled", cfg: &Config{}, n: Notification{Event: EventEscalation, Severity: SeverityCritical}, wantNil: true},
+		{name: "event off", cfg: cfg, n: Notification{Event: EventConvoyLanded, Severity: SeverityLow}, wantNil: true},
+		{name: "delivered", cfg: cfg, n: Notification{Event: EventEscalation, Severity: SeverityNormal}, delivered: true, severity: SeverityNormal},
+		{name: "below min", cfg: cfg, n: Notification{Event: EventEscalation, Severity: SeverityLow}, held: HeldBelowMin, severity: SeverityLow},
+		{name: "dnd holds normal", cfg: cfg, dnd: true, n: Notification{Event: EventBudget, Severity: SeverityNormal}, held: HeldDND, severity: SeverityNormal},
+		{name: "quiet holds normal", cfg: cfg, quiet: true, n: Notification{Event: EventBudget, Severity: SeverityNormal}, held: HeldQuiet, severity: SeverityNormal},
+		{name: "override breaks through dnd", cfg: cfg, dnd: true, n: Notification{Event: EventApproval, Severity: SeverityNormal}, delivered: true, severity: SeverityCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			town := t.TempDir()
+			shown := stubDeliver(t, nil)
+			if tt.dnd {
+				if err := SetDND(town, time.Time{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			rec, err := Raise(town, tt.cfg, tt.quiet, tt.n, now)
+			if err != nil {
+				t.Fatalf("Raise: %v", err)
+			}
+			if tt.wantNil {
+				if rec != nil {
+					t.Fatalf("expected no record, got %+v", rec)
+				}
+				return
+			}
+			if rec.Delivered != tt.delivered || rec.Held != tt.held || rec.Severity != tt.severity {
+				t.Errorf("record = delivered %v held %q severity %q; want %v %q %q", rec.Delivered, rec.Held, rec.Severity, tt.delivered, tt.held, tt.severity)
+			}
+			if got := len(*shown); got != map[bool]int{true: 1, false: 0}[tt.delivered] {
+				t.Errorf("shown %d notifications", got)
+			}
+			records, _ := List(town, 0)
+			if len(records) != 1 {
+				t.Errorf("center has %d records, want 1", len(records))
+			}
+		})
+	}
+}
+
+func TestRaiseKeyAndDeliveryError(t *testing.T) {
+	town := t.TempDir()
+	now := time.Now()
+	cfg := &Config{Enabled: true}
+	stubDeliver(t, errors.New("no display"))
+
+	n := Notification{Event: EventBudget, Severity: SeverityNormal, Title: "80%", Key: "budget-80-2026-01-02"}
+	rec, err := Raise(town, cfg, false, n, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Delivered || rec.Held != "no display" {
+		t.Errorf("failed delivery should be recorded as held, got %+v", rec)
+	}
+	if rec, _ := Raise(town, cfg, false, n, now); rec != nil {
+		t.Errorf("same key raised twice: %+v", rec)
+	}
+	records, _ := List(town, 0)
+	if len(records) != 1 {
+		t.Errorf("center has %d records, want 1", len(records))
+	}
+}
+
+func TestListNewestFirstAndTrimmed(t *testing.T) {
+	town := t.TempDir()
+	stubDeliver(t, nil)
+	cfg := &Config{Enabled: true}
+	for i := 0; i < maxRecords+5; i++ {
+		if _, err := Raise(town, cfg, false, Notification{Event: EventTest, Title: "n" + string(rune('a'+i%26))}, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, _ := List(town, 0)
+	if len(all) != maxRecords {
+		t.Errorf("kept %d records, want %d", len(all), maxRecords)
+	}
+	recent, _ := List(town, 2)
+	if len(recent) != 2 || recent[0].Title != "n"+string(rune('a'+(maxRecords+4)%26)) {
+		t.Errorf("List(2) = %+v", recent)
+	}
+}
+
+func TestDND(t *testing.T) {
+	town := t.TempDir()
+	now := time.Now()
+	if on, _ := DNDActive(town, now); on {
+		t.Fatal("DND on by default")
+	}
+	until := now.Add(time.Hour)
+	if err := SetDND(town, until); err != nil {
+		t.Fatal(err)
+	}
+	if on, u := DNDActive(town, now); !on || !u.Equal(until) {
+		t.Errorf("DNDActive = %v, %v; want on until %v", on, u, until)
+	}
+	if on, _ := DNDActive(town, until); on {
+		t.Error("DND should expire at its end time")
+	}
+	if err := ClearDND(town); err != nil {
+		t.Fatal(err)
+	}
+	if err := ClearDND(town); err != nil {
+		t.Errorf("clearing twice: %v", err)
+	}
+}
+
+func TestDesktopCommand(t *testing.T) {
+	n := Notification{Event: EventEscalation, Severity: SeverityCritical, Title: `Build "broke"`, Body: "gt-123"}
+
+	name, args, err := desktopCommand("linux", n)
+	if err != nil || name != "notify-send" {
+		t.Fatalf("linux: %s %v %v", name, args, err)
+	}
+	if got := strings.Join(args, " "); !strings.Contains(got, "--urgency=critical") || !strings.HasSuffix(got, `Gas Town: Build "broke" gt-123`) {
+		t.Errorf("notify-send args = %q", got)
+	}
+
+	name, args, err = desktopCommand("darwin", n)
+	if err != nil || name != "osascript" {
+		t.Fatalf("darwin: %s %v %v", name, args, err)
+	}
+	if want := `with title "Gas Town: Build \"broke\""`; !strings.Contains(args[1], want) {
+		t.Errorf("osascript script %q missing %q", args[1], want)
+	}
+
+	if _, _, err := desktopCommand("windows", n); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("windows: err = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	var nilCfg *Config
+	if err := nilCfg.Validate(); err != nil {
+		t.Errorf("nil config: %v", err)
+	}
+	valid := &Config{Enabled: true, MinSeverity: SeverityNormal, Events: map[string]string{EventBudget: SeverityOff}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("valid config: %v", err)
+	}
+	for _, c := range []*Config{
+		{MinSeverity: "loud"},
+		{Events: map[string]string{"deploy": SeverityLow}},
+		{Events: map[string]string{EventApproval: "urgent"}},
+		{DailyBudgetUSD: -1},
+	} {
+		if err := c.Validate(); err == nil {
+			t.Errorf("expected error for %+v", c)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Package observe builds the read-only observer view of a rig: the feed
+// events of the rig and the transcripts of its agents, merged in time order.
+//
+// An observer only reads files the agents and gt already write. It never
+// attaches to an agent's tmux session and runs no agent of its own, so
+// watching costs no tokens and cannot interfere with the work.
+package observe
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/tui/feed"
+)
+
+// KindEvent marks entries from the town event log. Transcript entries use
+// the transcript event type ("text", "tool_use", "tool_result", "thinking").
+const KindEvent = "event"
+
+// Entry is one item of the observer view.
+type Entry struct {
+	At    time.Time `json:"ts"`
+	Agent string    `json:"agent"`
+	Kind  string    `json:"kind"`
+	Role  string    `json:"role,omitempty"` // Transcript entries: "assistant" or "user"
+	Text  string    `json:"text"`
+}
+
+// Observer follows one rig. Each Poll returns what was written since the
+// previous one.
+type Observer struct {
+	townRoot string
+	rigName  string
+	rigPath  string
+	since    time.Time
+
+	offsets      map[string]int64 // Transcript path → bytes read
+	eventsOffset int64
+}
+
+// New returns an observer of the rig at rigPath that starts with what was
+// written at or after since.
+func New(townRoot, rigName, rigPath string, since time.Time) *Observer {
+	return &Observer{
+		townRoot: townRoot,
+		rigName:  rigName,
+		rigPath:  rigPath,
+		since:    since,
+		offsets:  make(map[string]int64),
+	}
+}
+
+// Poll returns the entries written since the last poll, oldest first.
+// Agents that appear later (a new polecat, a new session) are picked up
+// on the poll after they first write.
+func (o *Observer) Poll() ([]Entry, error) {
+	var out []Entry
+	for agent, dirs := range AgentWorkDirs(o.rigName, o.rigPath) {
+		transcripts, err := agentlog.ClaudeCodeTranscripts(dirs...)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range transcripts {
+			offset, seen := o.offsets[t.Path]
+			if (!seen && t.ModTime.Before(o.since)) || (seen && t.Size <= offset) {
+				continue
+			}
+			evs, next, err := agentlog.ReadClaudeCodeTranscriptFrom(t.Path, agent, offset)
+			if err != nil {
+				continue // Transcript removed or unreadable; try again next poll
+			}
+			o.offsets[t.Path] = next
+			for _, ev := range evs {
+				if ev.EventType == "usage" || ev.Timestamp.Before(o.since) {
+					continue
+				}
+				out = append(out, Entry{At: ev.Timestamp, Agent: agent, Kind: ev.EventType, Role: ev.Role, Text: ev.Content})
+			}
+		}
+	}
+
+	evs, err := o.readEvents()
+	if err != nil {
+		return nil, err
+	}
+	out = append(out, evs...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return out, nil
+}
+
+// readEvents reads the rig's feed events appended to the town event log
+// since the last poll. A partly written last line is left for the next one.
+func (o *Observer) readEvents() ([]Entry, error) {
+	f, err := os.Open(filepath.Join(o.townRoot, events.EventsFile)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.Size() < o.eventsOffset {
+		o.eventsOffset = 0 // Log was rotated
+	}
+	if _, err := f.Seek(o.eventsOffset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var out []Entry
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				return out, nil
+			}
+			return out, err
+		}
+		o.eventsOffset += int64(len(line))
+		ev := feed.ParseGtEventLine(strings.TrimRight(line, "\r\n"))
+		if ev == nil || ev.Rig != o.rigName || ev.Time.Before(o.since) {
+			continue
+		}
+		actor := ev.Actor
+		if actor == "" {
+			actor = "system"
+		}
+		out = append(out, Entry{At: ev.Time, Agent: actor, Kind: KindEvent, Text: ev.Message})
+	}
+}
+
+// AgentWorkDirs returns the work dirs each of the rig's agents has run in,
+// keyed by agent address. Polecats have used both polecats/<name>/<rig> and
+// the older polecats/<name>, so both are listed.
+func AgentWorkDirs(rigName, rigPath string) map[string][]string {
+	dirs := map[string][]string{
+		rigName + "/witness":  {filepath.Join(rigPath, "witness", "rig"), filepath.Join(rigPath, "witness")},
+		rigName + "/refinery": {filepath.Join(rigPath, "refinery", "rig")},
+	}
+	for _, name := range subdirs(filepath.Join(rigPath, "polecats")) {
+		dirs[rigName+"/"+name] = []string{
+			filepath.Join(rigPath, "polecats", name, rigName),
+			filepath.Join(rigPath, "polecats", name),
+		}
+	}
+	for _, name := range subdirs(filepath.Join(rigPath, "crew")) {
+		dirs[rigName+"/crew/"+name] = []string{filepath.Join(rigPath, "crew", name)}
+	}
+	return dirs
+}
+
+func subdirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
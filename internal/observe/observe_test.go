@@ -0,0 +1,87 @@
+package observe
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// projectDir mirrors where Claude Code keeps the transcripts of workDir.
+func projectDir(t *testing.T, home, workDir string) string {
+	t.Helper()
+	dir := filepath.Join(home, ".claude", "projects", strings.ReplaceAll(workDir, "/", "-"))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func appendFile(t *testing.T, path, data string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestObserverPoll(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	town := t.TempDir()
+	rigPath := filepath.Join(town, "gastown")
+	if err := os.MkdirAll(filepath.Join(rigPath, "polecats", "Toast", "gastown"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	since := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	toast := filepath.Join(projectDir(t, home, filepath.Join(rigPath, "polecats", "Toast", "gastown")), "s1.jsonl")
+	appendFile(t, toast, `{"type":"user","timestamp":"2026-01-02T09:00:00Z","message":{"role":"user","content":"old prompt"}}
+{"type":"assistant","timestamp":"2026-01-02T10:00:05Z","message":{"role":"assistant","content":[{"type":"text","text":"Looking at the test"}]}}
+`)
+	eventsPath := filepath.Join(town, ".events.jsonl")
+	appendFile(t, eventsPath, `{"ts":"2026-01-02T10:00:01Z","type":"sling","actor":"mayor","payload":{"bead":"gt-1","target":"gastown/Toast","rig":"gastown"},"visibility":"feed"}
+{"ts":"2026-01-02T10:00:02Z","type":"sling","actor":"mayor","payload":{"bead":"bd-2","rig":"beads"},"visibility":"feed"}
+{"ts":"2026-01-02T10:00:03Z","type":"nudge","actor":"gastown/witness","payload":{},"visibility":"audit"}
+`)
+
+	o := New(town, "gastown", rigPath, since)
+	entries, err := o.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v, want the rig's sling and Toast's reply", entries)
+	}
+	if entries[0].Kind != KindEvent || entries[0].Agent != "mayor" {
+		t.Errorf("first entry = %+v", entries[0])
+	}
+	if entries[1].Agent != "gastown/Toast" || entries[1].Kind != "text" || entries[1].Text != "Looking at the test" {
+		t.Errorf("second entry = %+v", entries[1])
+	}
+
+	// Nothing new: nothing returned. A new crew member's session is picked up.
+	if entries, _ := o.Poll(); len(entries) != 0 {
+		t.Errorf("repeat poll = %+v", entries)
+	}
+	if err := os.MkdirAll(filepath.Join(rigPath, "crew", "max"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(t, filepath.Join(projectDir(t, home, filepath.Join(rigPath, "crew", "max")), "s2.jsonl"),
+		`{"type":"assistant","timestamp":"2026-01-02T10:01:00Z","message":{"role":"assistant","content":[{"type":"tool_use","name":"Bash","input":{"command":"go test"}}]}}
+`)
+	appendFile(t, toast, `{"type":"assistant","timestamp":"2026-01-02T10:00:30Z","message":{"role":"assistant","content":[{"type":"text","text":"Fixed"}]}}
+{"type":"assistant","timest`)
+	entries, err = o.Poll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Text != "Fixed" || entries[1].Agent != "gastown/crew/max" || entries[1].Kind != "tool_use" {
+		t.Errorf("follow-up poll = %+v", entries)
+	}
+}
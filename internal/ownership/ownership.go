@@ -0,0 +1,318 @@
+// Package ownership reads who owns which paths of a rig's repository, so gt
+// can route work to the agents that own the code it touches and hold merges
+// until those owners have reviewed them.
+//
+// Ownership comes from the rig's settings/OWNERS when present, otherwise
+// from the repository's CODEOWNERS. Both use CODEOWNERS syntax: a pattern
+// followed by owners, the last matching line deciding. In settings/OWNERS
+// owners are agent addresses (crew/max, gastown/polecats/Toast); CODEOWNERS
+// owners (@alice, @org/team, emails) name agents through the aliases in the
+// rig's ownership settings, and owners without an alias are ignored.
+//
+// Example (settings/config.json):
+//
+//	"ownership": {
+//	  "aliases": {"@alice": "crew/alice", "@acme/infra": "crew/ops"},
+//	  "require_review": true
+//	}
+package ownership
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// RigFile is the gt ownership file in a rig's settings directory.
+const RigFile = "OWNERS"
+
+// CodeownersPaths are where a repository's CODEOWNERS may live, in the
+// order they are looked up.
+var CodeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// ApprovalLabelPrefix starts the label an owner's approval adds to a merge
+// request: gt:approved-by:<agent address>.
+const ApprovalLabelPrefix = "gt:approved-by:"
+
+// Config is the ownership section of a rig's settings.
+type Config struct {
+	// Aliases maps CODEOWNERS owners to agents (crew/alice or a full
+	// address).
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// Route slings beads that name owned paths to their owner instead of
+	// a fresh polecat. On by default.
+	Route *bool `json:"route,omitempty"`
+	// RequireReview holds merge requests that change owned paths until
+	// every owning agent other than the author has approved.
+	RequireReview bool `json:"require_review,omitempty"`
+}
+
+// Routes reports whether slings are routed to owners.
+func (c *Config) Routes() bool {
+	return c == nil || c.Route == nil || *c.Route
+}
+
+// RequiresReview reports whether the merge gate requires owner approval.
+func (c *Config) RequiresReview() bool {
+	return c != nil && c.RequireReview
+}
+
+// Agent returns the address of the agent owner stands for in rig, or ""
+// when it names no agent.
+func (c *Config) Agent(rig, owner string) string {
+	addr := owner
+	if c != nil {
+		if alias, ok := c.Aliases[owner]; ok {
+			addr = alias
+		}
+	}
+	if strings.Contains(addr, "@") {
+		return "" // Unaliased GitHub handle, team or email
+	}
+	parts := strings.Split(strings.Trim(addr, "/"), "/")
+	switch {
+	case len(parts) == 2 && (parts[0] == "crew" || parts[0] == "polecats") && parts[1] != "":
+		return rig + "/" + parts[0] + "/" + parts[1]
+	case len(parts) == 3 && (parts[1] == "crew" || parts[1] == "polecats") && parts[0] != "" && parts[2] != "":
+		return strings.Join(parts, "/")
+	}
+	return ""
+}
+
+// Rule is one line of an ownership file.
+type Rule struct {
+	Pattern string
+	Owners  []string // Empty: the paths are explicitly unowned
+	Line    int
+}
+
+// File is a parsed ownership file.
+type File struct {
+	Path  string // Where it was read from, for messages
+	Rules []Rule
+}
+
+// Parse reads CODEOWNERS syntax. Comments, blank lines and GitLab section
+// headers are skipped.
+func Parse(source string, data []byte) *File {
+	f := &File{Path: source}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(text, " #"); i >= 0 {
+			text = strings.TrimSpace(text[:i])
+		}
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "[") || strings.HasPrefix(text, "^[") {
+			continue
+		}
+		fields := strings.Fields(text)
+		f.Rules = append(f.Rules, Rule{Pattern: fields[0], Owners: fields[1:], Line: line})
+	}
+	return f
+}
+
+// Load reads a rig's ownership: settings/OWNERS when it exists, otherwise
+// the first CODEOWNERS that readRepo returns. readRepo reads a
+// repository-relative path; any error counts as absent. Load returns nil
+// when the rig has no ownership file.
+func Load(rigPath string, readRepo func(path string) ([]byte, error)) (*File, error) {
+	rigFile := filepath.Join(rigPath, "settings", RigFile)
+	data, err := os.ReadFile(rigFile) //nolint:gosec // G304: path is in the rig
+	if err == nil {
+		return Parse(rigFile, data), nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading %s: %w", rigFile, err)
+	}
+	if readRepo == nil {
+		return nil, nil
+	}
+	for _, p := range CodeownersPaths {
+		if data, err := readRepo(p); err == nil {
+			return Parse(p, data), nil
+		}
+	}
+	return nil, nil
+}
+
+// Owners returns the owners of a repository-relative path, or nil when no
+// rule owns it.
+func (f *File) Owners(file string) []string {
+	if f == nil {
+		return nil
+	}
+	for i := len(f.Rules) - 1; i >= 0; i-- {
+		if Match(f.Rules[i].Pattern, file) {
+			return f.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// Agents returns the agents in rig that own file.
+func (f *File) Agents(rig string, c *Config, file string) []string {
+	var agents []string
+	for _, owner := range f.Owners(file) {
+		if a := c.Agent(rig, owner); a != "" && !contains(agents, a) {
+			agents = append(agents, a)
+		}
+	}
+	return agents
+}
+
+// Route picks the agent that owns the most of paths (the first one seen on
+// a tie) and returns it with the paths it owns. It returns "" when no
+// agent owns any of them.
+func (f *File) Route(rig string, c *Config, paths []string) (string, []string) {
+	owned := make(map[string][]string)
+	var order []string
+	for _, p := range paths {
+		for _, a := range f.Agents(rig, c, p) {
+			if _, seen := owned[a]; !seen {
+				order = append(order, a)
+			}
+			owned[a] = append(owned[a], p)
+		}
+	}
+	best := ""
+	for _, a := range order {
+		if best == "" || len(owned[a]) > len(owned[best]) {
+			best = a
+		}
+	}
+	return best, owned[best]
+}
+
+// Reviewers returns the agents that must approve a change to files: every
+// owning agent except the author, in the order first seen.
+func (f *File) Reviewers(rig string, c *Config, files []string, author string) []string {
+	var reviewers []string
+	for _, file := range files {
+		for _, a := range f.Agents(rig, c, file) {
+			if a != author && !contains(reviewers, a) {
+				reviewers = append(reviewers, a)
+			}
+		}
+	}
+	return reviewers
+}
+
+// ApprovalLabel is the label recording agent's approval.
+func ApprovalLabel(agent string) string {
+	return ApprovalLabelPrefix + agent
+}
+
+// Pending returns the reviewers whose approval labels are missing.
+func Pending(reviewers, labels []string) []string {
+	var pending []string
+	for _, r := range reviewers {
+		if !contains(labels, ApprovalLabel(r)) {
+			pending = append(pending, r)
+		}
+	}
+	return pending
+}
+
+// Match reports whether a CODEOWNERS pattern matches a repository-relative
+// file. As in gitignore, a pattern with a leading or inner slash is anchored
+// to the root, other patterns match at any depth, a trailing slash matches
+// directories only, a match on a directory covers everything under it, "*"
+// stays within a path segment and "**" spans segments.
+func Match(pattern, file string) bool {
+	anchored := strings.HasPrefix(pattern, "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return false
+	}
+	if strings.Contains(pattern, "/") {
+		anchored = true
+	}
+	pat := strings.Split(pattern, "/")
+	if !anchored {
+		pat = append([]string{"**"}, pat...)
+	}
+	return matchSegments(pat, strings.Split(strings.Trim(file, "/"), "/"), dirOnly)
+}
+
+// matchSegments matches pattern segments against a prefix of the path's
+// segments. A dirOnly pattern must leave at least one segment under it.
+func matchSegments(pat, segs []string, dirOnly bool) bool {
+	if len(pat) == 0 {
+		return !dirOnly || len(segs) > 0
+	}
+	if pat[0] == "**" {
+		for i := 0; i <= len(segs); i++ {
+			if matchSegments(pat[1:], segs[i:], dirOnly) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segs) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pat[0], segs[0]); !ok {
+		return false
+	}
+	return matchSegments(pat[1:], segs[1:], dirOnly)
+}
+
+// Paths returns the repository paths text mentions: words with a slash or a
+// file extension, stripped of quoting, punctuation, a leading ./ and
+// :line suffixes. URLs and @handles are skipped.
+func Paths(text string) []string {
+	var paths []string
+	for _, word := range strings.Fields(text) {
+		word = strings.TrimRight(strings.TrimLeft(word, "`'\"([{<"), "`'\")]}>,;.:")
+		if word == "" || strings.Contains(word, "://") || strings.HasPrefix(word, "@") {
+			continue
+		}
+		word = stripLineSuffix(word)
+		word = strings.TrimPrefix(word, "./")
+		if !looksLikePath(word) || contains(paths, word) {
+			continue
+		}
+		paths = append(paths, word)
+	}
+	return paths
+}
+
+// stripLineSuffix drops a trailing :line or :line:col.
+func stripLineSuffix(word string) string {
+	for {
+		i := strings.LastIndex(word, ":")
+		if i < 0 || i == len(word)-1 || strings.Trim(word[i+1:], "0123456789") != "" {
+			return word
+		}
+		word = word[:i]
+	}
+}
+
+// looksLikePath reports whether word has a slash, or a file extension that
+// isn't all digits (v1.2, gt-abc.1).
+func looksLikePath(word string) bool {
+	if strings.Contains(word, "/") {
+		return !strings.HasPrefix(word, "/") && !strings.Contains(word, "..")
+	}
+	ext := path.Ext(word)
+	if len(ext) < 2 || len(ext) == len(word) {
+		return false
+	}
+	return strings.Trim(ext[1:], "0123456789") != ""
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package ownership
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern, file string
+		want          bool
+	}{
+		{"*", "README.md", true},
+		{"*", "internal/cmd/sling.go", true},
+		{"*.go", "internal/cmd/sling.go", true},
+		{"*.go", "docs/reference.md", false},
+		{"/docs/", "docs/reference.md", true},
+		{"/docs/", "internal/docs/x.md", false},
+		{"docs/", "internal/docs/x.md", true}, // A trailing slash doesn't anchor
+		{"docs", "internal/docs/x.md", true},
+		{"docs/", "docs", false},
+		{"internal/refinery", "internal/refinery/engineer.go", true},
+		{"internal/refinery", "x/internal/refinery/engineer.go", false},
+		{"internal/*/manager.go", "internal/rig/manager.go", true},
+		{"internal/*/manager.go", "internal/rig/sub/manager.go", false},
+		{"internal/**/manager.go", "internal/rig/sub/manager.go", true},
+		{"**/testdata", "internal/git/testdata/a", true},
+		{"/Makefile", "Makefile", true},
+		{"/Makefile", "sub/Makefile", false},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestOwners_LastRuleWins(t *testing.T) {
+	f := Parse("CODEOWNERS", []byte(`# Default owners
+*                      @alice
+/internal/refinery/    @bob crew/max   # merge queue
+/internal/refinery/score.go
+[Docs]
+docs/                  @acme/docs
+`))
+	if got := f.Owners("internal/cmd/sling.go"); !reflect.DeepEqual(got, []string{"@alice"}) {
+		t.Errorf("default owners = %q", got)
+	}
+	if got := f.Owners("internal/refinery/engineer.go"); !reflect.DeepEqual(got, []string{"@bob", "crew/max"}) {
+		t.Errorf("refinery owners = %q", got)
+	}
+	if got := f.Owners("internal/refinery/score.go"); len(got) != 0 {
+		t.Errorf("unowned file has owners %q", got)
+	}
+	if got := f.Owners("docs/reference.md"); !reflect.DeepEqual(got, []string{"@acme/docs"}) {
+		t.Errorf("docs owners = %q", got)
+	}
+}
+
+func TestConfigAgent(t *testing.T) {
+	c := &Config{Aliases: map[string]string{"@alice": "crew/alice", "@acme/infra": "beads/crew/ops"}}
+	tests := map[string]string{
+		"@alice":                 "gastown/crew/alice",
+		"@acme/infra":            "beads/crew/ops",
+		"@bob":                   "",
+		"bob@example.com":        "",
+		"crew/max":               "gastown/crew/max",
+		"polecats/Toast":         "gastown/polecats/Toast",
+		"gastown/polecats/Toast": "gastown/polecats/Toast",
+		"max":                    "",
+		"gastown/witness":        "",
+	}
+	for owner, want := range tests {
+		if got := c.Agent("gastown", owner); got != want {
+			t.Errorf("Agent(%q) = %q, want %q", owner, got, want)
+		}
+	}
+	var none *Config
+	if got := none.Agent("gastown", "crew/max"); got != "gastown/crew/max" {
+		t.Errorf("nil config Agent = %q", got)
+	}
+	if !none.Routes() || none.RequiresReview() {
+		t.Error("nil config: routing should default on and review off")
+	}
+}
+
+func TestRouteAndReviewers(t *testing.T) {
+	f := Parse("OWNERS", []byte(`
+internal/refinery/  crew/max
+internal/mail/      crew/max polecats/Toast
+docs/               crew/writer
+`))
+	agent, owned := f.Route("gastown", nil, []string{"docs/a.md", "internal/refinery/engineer.go", "internal/mail/router.go"})
+	if agent != "gastown/crew/max" || len(owned) != 2 {
+		t.Errorf("Route = %q %q", agent, owned)
+	}
+	if agent, _ := f.Route("gastown", nil, []string{"go.mod"}); agent != "" {
+		t.Errorf("Route(unowned) = %q", agent)
+	}
+
+	got := f.Reviewers("gastown", nil, []string{"internal/mail/router.go", "docs/a.md", "go.mod"}, "gastown/polecats/Toast")
+	want := []string{"gastown/crew/max", "gastown/crew/writer"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Reviewers = %q, want %q", got, want)
+	}
+	labels := []string{"gt:merge-request", ApprovalLabel("gastown/crew/writer")}
+	if got := Pending(want, labels); !reflect.DeepEqual(got, []string{"gastown/crew/max"}) {
+		t.Errorf("Pending = %q", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	rig := t.TempDir()
+	repo := map[string]string{"CODEOWNERS": "* @alice\n"}
+	read := func(p string) ([]byte, error) {
+		if s, ok := repo[p]; ok {
+			return []byte(s), nil
+		}
+		return nil, errors.New("not found")
+	}
+
+	f, err := Load(rig, read)
+	if err != nil || f == nil || f.Path != "CODEOWNERS" {
+		t.Fatalf("Load = %+v, %v; want the repo's CODEOWNERS", f, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(rig, "settings"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(rig, "settings", RigFile), []byte("* crew/max\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err = Load(rig, read)
+	if err != nil || f == nil || !reflect.DeepEqual(f.Owners("x.go"), []string{"crew/max"}) {
+		t.Fatalf("Load = %+v, %v; want settings/OWNERS to win", f, err)
+	}
+
+	if f, err := Load(t.TempDir(), read); err != nil || f == nil {
+		t.Fatalf("Load = %+v, %v", f, err)
+	}
+	if f, err := Load(t.TempDir(), nil); err != nil || f != nil {
+		t.Fatalf("Load without files = %+v, %v; want nil", f, err)
+	}
+}
+
+func TestPaths(t *testing.T) {
+	text := "Fix the retry loop in `internal/refinery/engineer.go:1740` (see ./docs/reference.md).\n" +
+		"Also touches Makefile and go.mod; see https://example.com/a/b, gt-abc.1, v1.2 and @alice."
+	got := Paths(text)
+	want := []string{"internal/refinery/engineer.go", "docs/reference.md", "go.mod"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Paths = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,317 @@
+// Package permprompt detects tool-permission prompts in agent panes and
+// decides them against a rig's permission policy, so agents don't sit on a
+// confirmation the operator would always approve.
+package permprompt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Prompt kinds.
+const (
+	KindCommand = "command" // Shell command execution
+	KindWrite   = "write"   // File create or edit
+	KindTool    = "tool"    // Any other tool (fetch, MCP, ...)
+)
+
+// Decisions.
+const (
+	Approve = "approve"
+	Deny    = "deny"
+	Ask     = "ask" // Leave the prompt for a human
+)
+
+// Prompt is a permission dialog found in a pane.
+type Prompt struct {
+	Kind     string `json:"kind"`
+	Subject  string `json:"subject"`  // Command line(s), file path or tool name
+	Question string `json:"question"` // The dialog's question line
+}
+
+// questionRe matches the line that asks for confirmation.
+var questionRe = regexp.MustCompile(`(?i)do you want to (proceed|make this edit to|create|allow|run|fetch|use)\b`)
+
+// targetRe extracts the file from "make this edit to foo.go?" / "create foo.go?".
+var targetRe = regexp.MustCompile(`(?i)do you want to (?:make this edit to|create) (.+?)\?\s*$`)
+
+// promptTail is how many trailing pane lines Detect inspects.
+const promptTail = 40
+
+// Detect finds an open permission prompt in the pane tail. The dialog must
+// still be on screen: a question with a "1. Yes" option after it.
+func Detect(lines []string) (*Prompt, bool) {
+	if len(lines) > promptTail {
+		lines = lines[len(lines)-promptTail:]
+	}
+	clean := make([]string, len(lines))
+	for i, l := range lines {
+		clean[i] = strings.TrimSpace(strings.Trim(strings.TrimSpace(l), "│"))
+	}
+
+	q := -1
+	for i := len(clean) - 1; i >= 0; i-- {
+		if questionRe.MatchString(clean[i]) {
+			q = i
+			break
+		}
+	}
+	if q < 0 || !hasYesOption(clean[q+1:]) {
+		return nil, false
+	}
+
+	p := &Prompt{Kind: KindTool, Question: clean[q]}
+	if m := targetRe.FindStringSubmatch(clean[q]); m != nil {
+		p.Kind, p.Subject = KindWrite, strings.TrimSpace(m[1])
+		return p, true
+	}
+
+	// Walk up to the dialog header to find what is being asked about.
+	for i := q - 1; i >= 0 && i >= q-15; i-- {
+		header := strings.ToLower(clean[i])
+		switch {
+		case header == "bash command" || strings.HasPrefix(header, "bash("):
+			p.Kind = KindCommand
+			p.Subject = commandBody(clean[i+1 : q])
+			return p, p.Subject != ""
+		case header == "edit file" || header == "create file" || header == "write file":
+			p.Kind = KindWrite
+			p.Subject = firstBodyLine(clean[i+1 : q])
+			return p, p.Subject != ""
+		case header == "tool use" || header == "fetch" || strings.HasSuffix(header, "(mcp)"):
+			// "Tool use" puts the call on the next line; the others name
+			// the tool in the header itself.
+			call := clean[i]
+			if header == "tool use" {
+				call = firstBodyLine(clean[i+1 : q])
+			}
+			if j := strings.IndexAny(call, "( "); j > 0 {
+				call = call[:j]
+			}
+			p.Subject = call
+			return p, p.Subject != ""
+		}
+	}
+	return nil, false
+}
+
+func hasYesOption(lines []string) bool {
+	for _, l := range lines {
+		l = strings.TrimLeft(l, "❯> ")
+		if strings.HasPrefix(l, "1. Yes") {
+			return true
+		}
+	}
+	return false
+}
+
+func firstBodyLine(lines []string) string {
+	for _, l := range lines {
+		if l = strings.TrimSpace(l); l != "" && strings.Trim(l, "─╭╮╰╯ ") != "" {
+			return l
+		}
+	}
+	return ""
+}
+
+// commandBody returns the command shown in a Bash dialog body: every line
+// but the trailing description, joined by newlines. A command that may run
+// on into the following line keeps that line too, so Decide sees it as
+// multi-line rather than mistaking the rest of it for the description.
+func commandBody(lines []string) string {
+	var body []string
+	for _, l := range lines {
+		if l = strings.TrimSpace(l); l != "" && strings.Trim(l, "─╭╮╰╯ ") != "" {
+			body = append(body, l)
+		}
+	}
+	if len(body) == 2 && !continuesLine(body[0]) {
+		body = body[:1]
+	} else if len(body) > 2 {
+		body = body[:len(body)-1]
+	}
+	return strings.Join(body, "\n")
+}
+
+// continuesLine reports whether a shell line carries on into the next: it
+// ends in an operator or backslash, or leaves a quote open.
+func continuesLine(line string) bool {
+	for _, op := range []string{"&&", "||", "|", "\\", "&", ";", ">", "<"} {
+		if strings.HasSuffix(line, op) {
+			return true
+		}
+	}
+	return strings.Count(line, `"`)%2 == 1 || strings.Count(line, "'")%2 == 1
+}
+
+// Config is the permission_policy section of a rig's settings/config.json.
+//
+//	"permission_policy": {
+//	  "enabled": true,
+//	  "allow_commands": ["go test *", "go build *", "make lint"],
+//	  "deny_commands": ["git push --force*", "rm -rf *"],
+//	  "allow_writes": ["*"],
+//	  "deny_writes": [".github/*", "*.env"],
+//	  "allow_tools": ["WebFetch"],
+//	  "unmatched": "ask"
+//	}
+//
+// Patterns are globs where * matches any run of characters (including /).
+// Deny rules win over allow rules; anything unmatched is left for a human
+// ("ask", the default) or rejected ("deny").
+type Config struct {
+	Enabled       bool     `json:"enabled"`
+	AllowCommands []string `json:"allow_commands,omitempty"`
+	DenyCommands  []string `json:"deny_commands,omitempty"`
+	AllowWrites   []string `json:"allow_writes,omitempty"`
+	DenyWrites    []string `json:"deny_writes,omitempty"`
+	AllowTools    []string `json:"allow_tools,omitempty"`
+	Unmatched     string   `json:"unmatched,omitempty"`
+}
+
+// Validate checks the policy. A nil policy is valid (disabled).
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Unmatched {
+	case "", Ask, Deny:
+	default:
+		return fmt.Errorf("unmatched must be %q or %q, got %q", Ask, Deny, c.Unmatched)
+	}
+	return nil
+}
+
+// IsEnabled reports whether prompts should be answered automatically.
+func (c *Config) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// shellMetaRe matches command substitution, which can hide anything.
+var shellMetaRe = regexp.MustCompile("`|\\$\\(|<\\(")
+
+// segmentSplitRe splits a command line on shell control operators so each
+// piece is checked separately ("go test && rm -rf /" is two commands).
+var segmentSplitRe = regexp.MustCompile(`&&|\|\||[;|&\n]`)
+
+// fdDupRe matches descriptor duplication ("2>&1"), which writes no file.
+var fdDupRe = regexp.MustCompile(`\d*[<>]&\d+`)
+
+// redirectRe matches a redirection and its target. Redirections end a
+// segment, so "go test * " cannot swallow "> ~/.bashrc".
+var redirectRe = regexp.MustCompile(`(?:\d*|&)(>>|>|<)\s*([^\s;&|<>]*)`)
+
+// Decide returns the decision for a prompt and the rule that produced it.
+func (c *Config) Decide(p *Prompt) (decision, rule string) {
+	if !c.IsEnabled() || p == nil {
+		return Ask, ""
+	}
+	switch p.Kind {
+	case KindCommand:
+		return c.decideCommand(p.Subject)
+	case KindWrite:
+		return c.decideWrite(p.Subject)
+	default:
+		return c.decideList(p.Subject, c.AllowTools, nil)
+	}
+}
+
+func (c *Config) decideCommand(command string) (string, string) {
+	if shellMetaRe.MatchString(command) {
+		// Never auto-approve what we can't see, but explicit denies still apply.
+		if d, rule := c.decideList(command, nil, c.DenyCommands); d == Deny {
+			return d, rule
+		}
+		return Ask, "command substitution"
+	}
+	if strings.Contains(command, "\n") {
+		// The dialog can't show where the command ends and its description
+		// begins, so a later line may be a command nobody checked.
+		for _, seg := range segmentSplitRe.Split(command, -1) {
+			if d, rule := c.decideList(strings.TrimSpace(seg), nil, c.DenyCommands); d == Deny {
+				return d, rule
+			}
+		}
+		return Ask, "multi-line command"
+	}
+
+	var rules []string
+	command = fdDupRe.ReplaceAllString(command, "")
+	for _, m := range redirectRe.FindAllStringSubmatch(command, -1) {
+		if m[1] == "<" {
+			continue
+		}
+		target := m[2]
+		if target == "" {
+			return Ask, "redirection without a target"
+		}
+		if target == "/dev/null" {
+			continue
+		}
+		d, rule := c.decideWrite(target)
+		if d != Approve {
+			return d, rule
+		}
+		rules = append(rules, rule)
+	}
+	command = redirectRe.ReplaceAllString(command, "\n")
+
+	for _, seg := range segmentSplitRe.Split(command, -1) {
+		seg = strings.TrimSpace(seg)
+		if seg == "" {
+			continue
+		}
+		d, rule := c.decideList(seg, c.AllowCommands, c.DenyCommands)
+		if d != Approve {
+			return d, rule
+		}
+		rules = append(rules, rule)
+	}
+	if len(rules) == 0 {
+		return c.unmatched(), ""
+	}
+	return Approve, strings.Join(rules, ", ")
+}
+
+// decideWrite decides a write to path, which must stay in the worktree.
+func (c *Config) decideWrite(path string) (string, string) {
+	if strings.Contains(path, "..") || strings.HasPrefix(path, "/") || strings.HasPrefix(path, "~") || strings.HasPrefix(path, "$") {
+		return Ask, "path escapes worktree"
+	}
+	return c.decideList(path, c.AllowWrites, c.DenyWrites)
+}
+
+func (c *Config) decideList(subject string, allow, deny []string) (string, string) {
+	for _, pattern := range deny {
+		if Match(pattern, subject) {
+			return Deny, pattern
+		}
+	}
+	for _, pattern := range allow {
+		if Match(pattern, subject) {
+			return Approve, pattern
+		}
+	}
+	return c.unmatched(), ""
+}
+
+func (c *Config) unmatched() string {
+	if c.Unmatched == Deny {
+		return Deny
+	}
+	return Ask
+}
+
+// Match reports whether s matches a glob where * matches any characters.
+// Whitespace runs compare equal so "go  test" matches "go test *".
+func Match(pattern, s string) bool {
+	pattern = strings.Join(strings.Fields(pattern), " ")
+	s = strings.Join(strings.Fields(s), " ")
+	parts := strings.Split(pattern, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	re, err := regexp.Compile("^" + strings.Join(parts, ".*") + "$")
+	return err == nil && re.MatchString(s)
+}
@@ -0,0 +1,70 @@
+package persona
+
+import "strings"
+
+// DiffLine is one line of a line-level diff.
+type DiffLine struct {
+	Op   byte // ' ' unchanged, '-' removed, '+' added
+	Text string
+}
+
+// Diff returns a line diff turning a into b (longest common subsequence).
+// Personas are short, so the quadratic table is fine.
+func Diff(a, b string) []DiffLine {
+	al, bl := splitLines(a), splitLines(b)
+	n, m := len(al), len(bl)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if al[i] == bl[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []DiffLine
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case al[i] == bl[j]:
+			out = append(out, DiffLine{' ', al[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, DiffLine{'-', al[i]})
+			i++
+		default:
+			out = append(out, DiffLine{'+', bl[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		out = append(out, DiffLine{'-', al[i]})
+	}
+	for ; j < m; j++ {
+		out = append(out, DiffLine{'+', bl[j]})
+	}
+	return out
+}
+
+// Changed reports whether a diff has any added or removed lines.
+func Changed(lines []DiffLine) bool {
+	for _, l := range lines {
+		if l.Op != ' ' {
+			return true
+		}
+	}
+	return false
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
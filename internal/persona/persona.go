@@ -0,0 +1,180 @@
+// Package persona stores versioned per-role persona prompts.
+//
+// A persona is operator-authored guidance layered on top of a role's built-in
+// briefing: tone, priorities, house rules. Before personas, these tweaks were
+// scattered across CLAUDE.md files with no record of who changed what. Each
+// role's persona now lives under <town>/roles/personas/<role>/ as numbered
+// versions (v1.md, v2.md, ...) plus an append-only history.jsonl. The latest
+// version is the active persona; rolling back appends a new version with the
+// old content, so the history is never rewritten.
+package persona
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrUnchanged is returned by Save when content matches the active version.
+var ErrUnchanged = errors.New("persona unchanged")
+
+// Version describes one saved persona version.
+type Version struct {
+	Version int       `json:"version"`
+	At      time.Time `json:"at"`
+	Author  string    `json:"author,omitempty"`
+	Note    string    `json:"note,omitempty"`
+	SHA256  string    `json:"sha256"`
+
+	// RollbackOf is the version whose content this one restores.
+	RollbackOf int `json:"rollback_of,omitempty"`
+}
+
+// Store reads and writes personas for one town.
+type Store struct {
+	townRoot string
+	now      func() time.Time
+}
+
+// NewStore returns the persona store for townRoot.
+func NewStore(townRoot string) *Store {
+	return &Store{townRoot: townRoot, now: time.Now}
+}
+
+var validRole = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// Dir returns the directory holding role's persona versions.
+func (s *Store) Dir(role string) string {
+	return filepath.Join(s.townRoot, "roles", "personas", role)
+}
+
+func (s *Store) versionPath(role string, v int) string {
+	return filepath.Join(s.Dir(role), fmt.Sprintf("v%d.md", v))
+}
+
+func (s *Store) historyPath(role string) string {
+	return filepath.Join(s.Dir(role), "history.jsonl")
+}
+
+// History returns role's versions, oldest first. A role without a persona
+// has an empty history.
+func (s *Store) History(role string) ([]Version, error) {
+	if !validRole.MatchString(role) {
+		return nil, fmt.Errorf("invalid role name %q", role)
+	}
+	f, err := os.Open(s.historyPath(role))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var versions []Version
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var v Version
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", s.historyPath(role), err)
+		}
+		versions = append(versions, v)
+	}
+	return versions, scanner.Err()
+}
+
+// Current returns the active version and its content, or nil and "" if the
+// role has no persona.
+func (s *Store) Current(role string) (*Version, string, error) {
+	versions, err := s.History(role)
+	if err != nil || len(versions) == 0 {
+		return nil, "", err
+	}
+	v := versions[len(versions)-1]
+	content, err := s.Content(role, v.Version)
+	if err != nil {
+		return nil, "", err
+	}
+	return &v, content, nil
+}
+
+// Content returns the text of a saved version.
+func (s *Store) Content(role string, version int) (string, error) {
+	data, err := os.ReadFile(s.versionPath(role, version))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%s persona has no version %d", role, version)
+		}
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Save records content as the next version of role's persona. It returns
+// ErrUnchanged if content matches the active version.
+func (s *Store) Save(role, content, author, note string) (*Version, error) {
+	return s.save(role, content, author, note, 0)
+}
+
+// Rollback restores the content of version as a new version.
+func (s *Store) Rollback(role string, version int, author string) (*Version, error) {
+	content, err := s.Content(role, version)
+	if err != nil {
+		return nil, err
+	}
+	return s.save(role, content, author, fmt.Sprintf("rollback to v%d", version), version)
+}
+
+func (s *Store) save(role, content, author, note string, rollbackOf int) (*Version, error) {
+	versions, err := s.History(role)
+	if err != nil {
+		return nil, err
+	}
+	sum := checksum(content)
+	next := 1
+	if n := len(versions); n > 0 {
+		if versions[n-1].SHA256 == sum {
+			return nil, ErrUnchanged
+		}
+		next = versions[n-1].Version + 1
+	}
+
+	if err := os.MkdirAll(s.Dir(role), 0755); err != nil {
+		return nil, fmt.Errorf("creating persona directory: %w", err)
+	}
+	if err := os.WriteFile(s.versionPath(role, next), []byte(content), 0644); err != nil {
+		return nil, fmt.Errorf("writing persona v%d: %w", next, err)
+	}
+
+	v := Version{Version: next, At: s.now().UTC(), Author: author, Note: note, SHA256: sum, RollbackOf: rollbackOf}
+	line, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(s.historyPath(role), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening persona history: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("writing persona history: %w", err)
+	}
+	return &v, nil
+}
+
+func checksum(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,110 @@
+package persona
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSaveHistoryAndCurrent(t *testing.T) {
+	s := NewStore(t.TempDir())
+	s.now = func() time.Time { return time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	if v, content, err := s.Current("mayor"); err != nil || v != nil || content != "" {
+		t.Fatalf("Current with no persona = %v, %q, %v", v, content, err)
+	}
+
+	if _, err := s.Save("mayor", "Be terse.\n", "alice", "initial"); err != nil {
+		t.Fatalf("Save v1: %v", err)
+	}
+	if _, err := s.Save("mayor", "Be terse.\n", "alice", ""); !errors.Is(err, ErrUnchanged) {
+		t.Errorf("Save unchanged = %v, want ErrUnchanged", err)
+	}
+	v2, err := s.Save("mayor", "Be terse.\nPrefer convoys.\n", "bob", "convoys")
+	if err != nil {
+		t.Fatalf("Save v2: %v", err)
+	}
+	if v2.Version != 2 || v2.Author != "bob" {
+		t.Errorf("v2 = %+v", v2)
+	}
+
+	cur, content, err := s.Current("mayor")
+	if err != nil || cur.Version != 2 || content != "Be terse.\nPrefer convoys.\n" {
+		t.Errorf("Current = %+v, %q, %v", cur, content, err)
+	}
+	history, err := s.History("mayor")
+	if err != nil || len(history) != 2 || history[0].Note != "initial" {
+		t.Errorf("History = %+v, %v", history, err)
+	}
+}
+
+func TestRollbackAppendsVersion(t *testing.T) {
+	s := NewStore(t.TempDir())
+	for _, c := range []string{"one\n", "two\n"} {
+		if _, err := s.Save("witness", c, "alice", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	v, err := s.Rollback("witness", 1, "carol")
+	if err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if v.Version != 3 || v.RollbackOf != 1 {
+		t.Errorf("rollback version = %+v, want v3 restoring v1", v)
+	}
+	if _, content, _ := s.Current("witness"); content != "one\n" {
+		t.Errorf("content after rollback = %q, want v1's", content)
+	}
+	// History is append-only: v2 is still readable.
+	if content, err := s.Content("witness", 2); err != nil || content != "two\n" {
+		t.Errorf("Content(v2) = %q, %v", content, err)
+	}
+
+	if _, err := s.Rollback("witness", 9, "carol"); err == nil {
+		t.Error("Rollback to a missing version = nil error")
+	}
+	if _, err := s.Rollback("witness", 3, "carol"); !errors.Is(err, ErrUnchanged) {
+		t.Errorf("Rollback to the active content = %v, want ErrUnchanged", err)
+	}
+}
+
+func TestHistory_InvalidRole(t *testing.T) {
+	s := NewStore(t.TempDir())
+	if _, err := s.History("../etc"); err == nil {
+		t.Error("History(../etc) = nil error, want error")
+	}
+}
+
+func TestHistory_Corrupt(t *testing.T) {
+	s := NewStore(t.TempDir())
+	if _, err := s.Save("crew", "x\n", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(s.historyPath("crew"), []byte("{broken\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.History("crew"); err == nil {
+		t.Error("History with corrupt log = nil error")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	got := Diff("a\nb\nc\n", "a\nc\nd\n")
+	want := []DiffLine{{' ', "a"}, {'-', "b"}, {' ', "c"}, {'+', "d"}}
+	if len(got) != len(want) {
+		t.Fatalf("Diff = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Diff[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if !Changed(got) {
+		t.Error("Changed = false for differing text")
+	}
+	if Changed(Diff("same\n", "same\n")) {
+		t.Error("Changed = true for identical text")
+	}
+}
@@ -0,0 +1,285 @@
+// Package ping records responsiveness probes sent to agent sessions.
+//
+// gt ping nudges an agent with a token and waits for the agent to run
+// gt ping ack <token>. Each probe is kept in .runtime/ping/pings.jsonl
+// with its outcome, so round-trip latency can be summarized per agent and
+// a slow model told apart from a session that is gone or wedged.
+package ping
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// Retention is how long probe records are kept.
+const Retention = 7 * 24 * time.Hour
+
+// Probe outcomes.
+const (
+	ResultPending   = ""           // Sent, not yet acknowledged or timed out
+	ResultOK        = "ok"         // Acknowledged
+	ResultTimeout   = "timeout"    // Session and agent alive, no ack in time
+	ResultNoSession = "no-session" // No tmux session
+	ResultNoAgent   = "no-agent"   // Session exists, agent process is gone
+)
+
+// Probe is one ping sent to an agent.
+type Probe struct {
+	ID      string    `json:"id"`
+	Agent   string    `json:"agent"`
+	Sent    time.Time `json:"sent"`
+	AckedAt time.Time `json:"acked_at,omitempty"`
+	Result  string    `json:"result,omitempty"`
+}
+
+// Latency returns the round-trip time of an acknowledged probe.
+func (p Probe) Latency() time.Duration {
+	if p.AckedAt.IsZero() {
+		return 0
+	}
+	return p.AckedAt.Sub(p.Sent)
+}
+
+// Dir returns the directory holding probe records.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "ping")
+}
+
+func probesPath(townRoot string) string { return filepath.Join(Dir(townRoot), "pings.jsonl") }
+
+// Start records a new pending probe to agent and returns it.
+func Start(townRoot, agent string, now time.Time) (*Probe, error) {
+	p := Probe{ID: newID(), Agent: agent, Sent: now.UTC()}
+	err := withLock(townRoot, func(path string) error {
+		probes, err := readProbes(path)
+		if err != nil {
+			return err
+		}
+		return writeProbes(path, append(prune(probes, now), p))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Ack records an agent's acknowledgement of a probe. Late acks, after the
+// probe timed out, are still recorded so the latency isn't lost.
+func Ack(townRoot, id string, now time.Time) (*Probe, error) {
+	var out Probe
+	err := withLock(townRoot, func(path string) error {
+		probes, err := readProbes(path)
+		if err != nil {
+			return err
+		}
+		for i := range probes {
+			p := &probes[i]
+			if p.ID != id {
+				continue
+			}
+			if !p.AckedAt.IsZero() {
+				out = *p
+				return nil
+			}
+			p.AckedAt = now.UTC()
+			p.Result = ResultOK
+			out = *p
+			return writeProbes(path, probes)
+		}
+		return fmt.Errorf("no ping %q", id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Finish records the outcome of a probe that was not acknowledged. An
+// ack that raced in first wins.
+func Finish(townRoot, id, result string) error {
+	return withLock(townRoot, func(path string) error {
+		probes, err := readProbes(path)
+		if err != nil {
+			return err
+		}
+		for i := range probes {
+			if probes[i].ID == id && probes[i].AckedAt.IsZero() {
+				probes[i].Result = result
+				return writeProbes(path, probes)
+			}
+		}
+		return nil
+	})
+}
+
+// Get returns a probe by ID, or nil if there is none.
+func Get(townRoot, id string) (*Probe, error) {
+	probes, err := readProbes(probesPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range probes {
+		if p.ID == id {
+			return &p, nil
+		}
+	}
+	return nil, nil
+}
+
+// List returns the probes sent since the given time, oldest first.
+func List(townRoot string, since time.Time) ([]Probe, error) {
+	probes, err := readProbes(probesPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	var out []Probe
+	for _, p := range probes {
+		if !p.Sent.Before(since) {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+// Stats summarizes an agent's probes.
+type Stats struct {
+	Agent     string        `json:"agent"`
+	Probes    int           `json:"probes"`
+	Acked     int           `json:"acked"`
+	Timeouts  int           `json:"timeouts"`
+	Dead      int           `json:"dead"` // No session or no agent process
+	P50       time.Duration `json:"p50"`
+	P90       time.Duration `json:"p90"`
+	P99       time.Duration `json:"p99"`
+	LastProbe time.Time     `json:"last_probe"`
+	Last      string        `json:"last_result"`
+}
+
+// Summarize computes per-agent stats. Pending probes are skipped. Latency
+// percentiles cover acknowledged probes only.
+func Summarize(probes []Probe) map[string]*Stats {
+	out := map[string]*Stats{}
+	latencies := map[string][]time.Duration{}
+	for _, p := range probes {
+		if p.Result == ResultPending {
+			continue
+		}
+		s := out[p.Agent]
+		if s == nil {
+			s = &Stats{Agent: p.Agent}
+			out[p.Agent] = s
+		}
+		s.Probes++
+		switch p.Result {
+		case ResultOK:
+			s.Acked++
+			latencies[p.Agent] = append(latencies[p.Agent], p.Latency())
+		case ResultTimeout:
+			s.Timeouts++
+		case ResultNoSession, ResultNoAgent:
+			s.Dead++
+		}
+		if !p.Sent.Before(s.LastProbe) {
+			s.LastProbe = p.Sent
+			s.Last = p.Result
+		}
+	}
+	for agent, ls := range latencies {
+		sort.Slice(ls, func(i, j int) bool { return ls[i] < ls[j] })
+		s := out[agent]
+		s.P50 = percentile(ls, 50)
+		s.P90 = percentile(ls, 90)
+		s.P99 = percentile(ls, 99)
+	}
+	return out
+}
+
+// percentile returns the nearest-rank percentile of sorted durations.
+func percentile(sorted []time.Duration, pct int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (pct*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// prune drops probes past retention.
+func prune(probes []Probe, now time.Time) []Probe {
+	out := probes[:0]
+	for _, p := range probes {
+		if now.Sub(p.Sent) < Retention {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func newID() string {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return "p-" + hex.EncodeToString(b[:])
+}
+
+func withLock(townRoot string, fn func(path string) error) error {
+	path := probesPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating ping directory: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking ping records: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+	return fn(path)
+}
+
+func readProbes(path string) ([]Probe, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var probes []Probe
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var p Probe
+		if err := json.Unmarshal(scanner.Bytes(), &p); err == nil {
+			probes = append(probes, p)
+		}
+	}
+	return probes, scanner.Err()
+}
+
+func writeProbes(path string, probes []Probe) error {
+	var b strings.Builder
+	for _, p := range probes {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil { //nolint:gosec // G306: runtime state
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,101 @@
+package ping
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStartAckFinish(t *testing.T) {
+	town := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	p, err := Start(town, "gastown/witness", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acked, err := Ack(town, p.ID, now.Add(3*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acked.Result != ResultOK || acked.Latency() != 3*time.Second {
+		t.Errorf("ack = %+v, latency %v", acked, acked.Latency())
+	}
+
+	// A timeout recorded after the ack doesn't overwrite it.
+	if err := Finish(town, p.ID, ResultTimeout); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Get(town, p.ID); got == nil || got.Result != ResultOK {
+		t.Errorf("after late Finish: %+v", got)
+	}
+
+	q, _ := Start(town, "gastown/refinery", now)
+	if err := Finish(town, q.ID, ResultTimeout); err != nil {
+		t.Fatal(err)
+	}
+	// A late ack still records the latency.
+	late, err := Ack(town, q.ID, now.Add(90*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if late.Result != ResultOK || late.Latency() != 90*time.Second {
+		t.Errorf("late ack = %+v", late)
+	}
+
+	if _, err := Ack(town, "p-missing", now); err == nil {
+		t.Error("acking an unknown ping should fail")
+	}
+}
+
+func TestStartPrunesOldProbes(t *testing.T) {
+	town := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if _, err := Start(town, "mayor", now.Add(-Retention-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Start(town, "deacon", now); err != nil {
+		t.Fatal(err)
+	}
+	probes, err := List(town, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(probes) != 1 || probes[0].Agent != "deacon" {
+		t.Errorf("probes = %+v", probes)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var probes []Probe
+	for i := 1; i <= 10; i++ {
+		sent := t0.Add(time.Duration(i) * time.Minute)
+		probes = append(probes, Probe{Agent: "gastown/witness", Sent: sent, AckedAt: sent.Add(time.Duration(i) * time.Second), Result: ResultOK})
+	}
+	probes = append(probes,
+		Probe{Agent: "gastown/witness", Sent: t0.Add(time.Hour), Result: ResultTimeout},
+		Probe{Agent: "gastown/polecats/Toast", Sent: t0, Result: ResultNoSession},
+		Probe{Agent: "gastown/polecats/Nux", Sent: t0, Result: ResultPending},
+	)
+
+	stats := Summarize(probes)
+	w := stats["gastown/witness"]
+	if w == nil {
+		t.Fatal("no witness stats")
+	}
+	if w.Probes != 11 || w.Acked != 10 || w.Timeouts != 1 {
+		t.Errorf("counts = %+v", w)
+	}
+	if w.P50 != 5*time.Second || w.P90 != 9*time.Second || w.P99 != 10*time.Second {
+		t.Errorf("percentiles = %v %v %v", w.P50, w.P90, w.P99)
+	}
+	if w.Last != ResultTimeout {
+		t.Errorf("last = %q, want timeout", w.Last)
+	}
+	if s := stats["gastown/polecats/Toast"]; s == nil || s.Dead != 1 || s.P50 != 0 {
+		t.Errorf("dead agent stats = %+v", s)
+	}
+	if _, ok := stats["gastown/polecats/Nux"]; ok {
+		t.Error("pending probes should not be summarized")
+	}
+}
@@ -90,7 +90,7 @@ func (r *Recorder) RecordRun(record PluginRunRecord) (string, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmdContext(ctx, cmd); err != nil {
 		return "", fmt.Errorf("creating plugin run bead: %s: %w", stderr.String(), err)
 	}
 
@@ -161,7 +161,7 @@ func (r *Recorder) queryRuns(pluginName string, limit int, since string) ([]*Plu
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmdContext(ctx, cmd); err != nil {
 		// Empty result is OK (no runs found)
 		if stderr.Len() == 0 || stdout.String() == "[]\n" {
 			return nil, nil
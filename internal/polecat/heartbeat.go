@@ -64,6 +64,11 @@ func heartbeatFile(townRoot, sessionName string) string {
 	return filepath.Join(heartbeatsDir(townRoot), sessionName+".json")
 }
 
+// SessionHeartbeatPath returns the path of a session's heartbeat file.
+func SessionHeartbeatPath(townRoot, sessionName string) string {
+	return heartbeatFile(townRoot, sessionName)
+}
+
 // TouchSessionHeartbeat writes or updates the heartbeat file for a polecat session.
 // Writes state="working" by default (heartbeat v2, gt-3vr5).
 // This is best-effort: errors are silently ignored because heartbeat signals
@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"syscall"
@@ -501,6 +502,23 @@ func (m *Manager) exists(name string) bool {
 type AddOptions struct {
 	HookBead   string // Bead ID to set as hook_bead at spawn time (atomic assignment)
 	BaseBranch string // Override base branch for worktree (e.g., "origin/integration/gt-epic")
+	Branch     string // Override branch name (e.g., a linked convoy's shared branch)
+}
+
+// spawnBranchName returns the branch for a polecat's new work: opts.Branch
+// when set, otherwise the configured template. An override that already
+// exists (left over from an earlier attempt) gets a timestamp suffix so the
+// worktree can still be created.
+func (m *Manager) spawnBranchName(name string, opts AddOptions) string {
+	if opts.Branch == "" {
+		return m.buildBranchName(name, opts.HookBead)
+	}
+	if repoGit, err := m.repoBase(); err == nil {
+		if exists, _ := repoGit.BranchExists(opts.Branch); exists {
+			return opts.Branch + "@" + strconv.FormatInt(time.Now().UnixMilli(), 36)
+		}
+	}
+	return opts.Branch
 }
 
 // Add creates a new polecat as a git worktree from the repo base.
@@ -691,7 +709,7 @@ func (m *Manager) addWithOptionsLocked(name string, opts AddOptions, polecatDir
 	defer func() { telemetry.RecordPolecatSpawn(context.Background(), name, retErr) }()
 
 	clonePath := filepath.Join(polecatDir, m.rig.Name)
-	branchName := m.buildBranchName(name, opts.HookBead)
+	branchName := m.spawnBranchName(name, opts)
 
 	// Track resources created for rollback on error.
 	var worktreeCreated bool
@@ -746,7 +764,7 @@ func (m *Manager) addWithOptionsLocked(name string, opts AddOptions, polecatDir
 			startPoint, m.rig.Path, filepath.Join(m.rig.Path, ".repo.git"))
 	}
 
-	if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
+	if err := m.addWorktree(repoGit, clonePath, branchName, startPoint, opts.HookBead); err != nil {
 		cleanupOnError()
 		return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
 	}
@@ -827,7 +845,7 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (_ *Polecat, retE
 	clonePath := filepath.Join(polecatDir, m.rig.Name)
 
 	// Build branch name using configured template or default format
-	branchName := m.buildBranchName(name, opts.HookBead)
+	branchName := m.spawnBranchName(name, opts)
 
 	// Create polecat directory (polecats/<name>/)
 	if err := os.MkdirAll(polecatDir, 0755); err != nil {
@@ -911,7 +929,7 @@ func (m *Manager) AddWithOptions(name string, opts AddOptions) (_ *Polecat, retE
 	// Always create fresh branch - unique name guarantees no collision
 	// git worktree add -b polecat/<name>-<timestamp> <path> <startpoint>
 	// Worktree goes in polecats/<name>/<rigname>/ for LLM ergonomics
-	if err := repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint); err != nil {
+	if err := m.addWorktree(repoGit, clonePath, branchName, startPoint, opts.HookBead); err != nil {
 		cleanupOnError()
 		return nil, fmt.Errorf("creating worktree from %s: %w", startPoint, err)
 	}
@@ -1302,6 +1320,141 @@ func (m *Manager) ReleaseName(name string) {
 	_ = m.namePool.Save() // non-fatal: state file update
 }
 
+// validPolecatNameRe matches names usable as a polecat directory, session
+// suffix and mail address segment.
+var validPolecatNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// renamePolecatEntry moves a home-directory entry during Rename. It is a
+// seam for tests; production uses os.Rename.
+var renamePolecatEntry = os.Rename
+
+// Rename moves a polecat from oldName to newName: its home directory, its
+// worktree (moved with git, so the repository follows it) and its name pool
+// slot. The branch keeps its name. Moving the identity bead, assigned work
+// and mail is the caller's job (see gt polecat rename), and the polecat's
+// session must not be running.
+func (m *Manager) Rename(oldName, newName string) (retErr error) {
+	if !validPolecatNameRe.MatchString(newName) || ReservedInfraAgentNames[newName] {
+		return fmt.Errorf("invalid polecat name %q", newName)
+	}
+	// Lock both names in alphabetical order to prevent deadlock.
+	first, second := oldName, newName
+	if first > second {
+		first, second = second, first
+	}
+	fl1, err := m.lockPolecat(first)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl1.Unlock() }()
+	fl2, err := m.lockPolecat(second)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl2.Unlock() }()
+	if !m.exists(oldName) {
+		return ErrPolecatNotFound
+	}
+	if m.exists(newName) {
+		return ErrPolecatExists
+	}
+
+	oldDir, newDir := m.polecatDir(oldName), m.polecatDir(newName)
+	oldClone := m.clonePath(oldName)
+
+	// Moves made so far, undone in reverse if a later step fails so the
+	// polecat is never left split between its old and new homes.
+	type move struct {
+		from, to string
+		worktree bool
+	}
+	var moves []move
+	createdDir := false
+	defer func() {
+		if retErr == nil {
+			return
+		}
+		for i := len(moves) - 1; i >= 0; i-- {
+			mv := moves[i]
+			undo := renamePolecatEntry
+			if mv.worktree {
+				undo = m.moveWorktree
+			}
+			if err := undo(mv.to, mv.from); err != nil {
+				retErr = fmt.Errorf("%w (undoing the rename also failed: %v; fix %s by hand)", retErr, err, mv.to)
+				return
+			}
+		}
+		if createdDir {
+			_ = os.Remove(newDir)
+		}
+	}()
+
+	if oldClone == oldDir {
+		// Old structure: the home directory is the worktree.
+		if err := m.moveWorktree(oldDir, newDir); err != nil {
+			return err
+		}
+		moves = append(moves, move{oldDir, newDir, true})
+	} else {
+		if err := os.MkdirAll(newDir, 0755); err != nil {
+			return fmt.Errorf("creating polecat dir: %w", err)
+		}
+		createdDir = true
+		if _, err := os.Stat(oldClone); err == nil {
+			newClone := filepath.Join(newDir, m.rig.Name)
+			if err := m.moveWorktree(oldClone, newClone); err != nil {
+				return err
+			}
+			moves = append(moves, move{oldClone, newClone, true})
+		}
+		// The rest of the home directory (agent settings, runtime state)
+		// moves as is.
+		entries, err := os.ReadDir(oldDir)
+		if err != nil {
+			return fmt.Errorf("reading polecat dir: %w", err)
+		}
+		for _, e := range entries {
+			from, to := filepath.Join(oldDir, e.Name()), filepath.Join(newDir, e.Name())
+			if err := renamePolecatEntry(from, to); err != nil {
+				return fmt.Errorf("moving %s: %w", e.Name(), err)
+			}
+			moves = append(moves, move{from, to, false})
+		}
+		if err := os.Remove(oldDir); err != nil {
+			return fmt.Errorf("removing old polecat dir: %w", err)
+		}
+	}
+
+	fl, err := m.lockPool()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fl.Unlock() }()
+	m.namePool.Release(oldName)
+	m.namePool.MarkInUse(newName)
+	if err := m.namePool.Save(); err != nil {
+		return fmt.Errorf("saving pool state: %w", err)
+	}
+	return nil
+}
+
+// moveWorktree moves a polecat's worktree with git so its registration in
+// the repository follows it. A directory that isn't a worktree is renamed.
+func (m *Manager) moveWorktree(from, to string) error {
+	if _, err := os.Stat(filepath.Join(from, ".git")); err != nil {
+		return os.Rename(from, to)
+	}
+	repoGit, err := m.repoBase()
+	if err != nil {
+		return err
+	}
+	if err := repoGit.WorktreeMove(from, to); err != nil {
+		return fmt.Errorf("moving worktree: %w", err)
+	}
+	return nil
+}
+
 // RepairWorktree repairs a stale polecat by removing it and creating a fresh worktree.
 // This is NOT for normal operation - it handles reconciliation when AllocateName
 // returns a name that unexpectedly already exists (stale state recovery).
@@ -1389,10 +1542,10 @@ func (m *Manager) RepairWorktreeWithOptions(name string, force bool, opts AddOpt
 
 	// Create fresh worktree to a temporary path first, so we can roll back if it fails.
 	// This prevents destroying the old worktree before the new one is confirmed working.
-	branchName := m.buildBranchName(name, opts.HookBead)
+	branchName := m.spawnBranchName(name, opts)
 	tmpClonePath := newClonePath + ".repair-tmp"
 	_ = os.RemoveAll(tmpClonePath) // clean up any leftover temp dir
-	if err := repoGit.WorktreeAddFromRef(tmpClonePath, branchName, startPoint); err != nil {
+	if err := m.addWorktree(repoGit, tmpClonePath, branchName, startPoint, opts.HookBead); err != nil {
 		return nil, fmt.Errorf("creating fresh worktree from %s: %w", startPoint, err)
 	}
 
@@ -1551,7 +1704,7 @@ func (m *Manager) ReuseIdlePolecat(name string, opts AddOptions) (*Polecat, erro
 	_ = polecatGit.ResetHard("HEAD")
 
 	// Create fresh branch from start point (branch-only, no worktree add/remove)
-	branchName := m.buildBranchName(name, opts.HookBead)
+	branchName := m.spawnBranchName(name, opts)
 	if err := polecatGit.CheckoutNewBranch(branchName, startPoint); err != nil {
 		// checkout -b fails if we're in detached HEAD or branch already exists.
 		// Fall back to: create branch separately, then checkout.
@@ -1566,6 +1719,11 @@ func (m *Manager) ReuseIdlePolecat(name string, opts AddOptions) (*Polecat, erro
 		return nil, fmt.Errorf("branch mismatch after checkout: expected %s, got %s", branchName, actual)
 	}
 
+	// The previous bead may have used a different sparse profile
+	if err := m.applySparseProfile(clonePath, opts.HookBead); err != nil {
+		style.PrintWarning("could not apply sparse checkout profile: %v", err)
+	}
+
 	// Reset agent bead for reuse
 	agentID := m.agentBeadID(name)
 	if err := m.beads.ResetAgentBeadForReuse(agentID, "idle polecat reuse"); err != nil {
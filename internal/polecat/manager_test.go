@@ -1,6 +1,7 @@
 package polecat
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -866,6 +867,36 @@ func TestBuildBranchName(t *testing.T) {
 	}
 }
 
+func TestSpawnBranchName(t *testing.T) {
+	tmpDir := t.TempDir()
+	mayorRig := filepath.Join(tmpDir, "mayor", "rig")
+	if err := os.MkdirAll(mayorRig, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=t", "-c", "user.email=t@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+		{"branch", "feat/taken"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = mayorRig
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	m := NewManager(&rig.Rig{Name: "test-rig", Path: tmpDir}, git.NewGit(tmpDir), nil)
+
+	if got := m.spawnBranchName("alpha", AddOptions{HookBead: "gt-1"}); !strings.HasPrefix(got, "polecat/alpha/gt-1@") {
+		t.Errorf("without override = %q, want the template branch", got)
+	}
+	if got := m.spawnBranchName("alpha", AddOptions{Branch: "feat/api-v2"}); got != "feat/api-v2" {
+		t.Errorf("override = %q, want feat/api-v2", got)
+	}
+	if got := m.spawnBranchName("alpha", AddOptions{Branch: "feat/taken"}); !strings.HasPrefix(got, "feat/taken@") {
+		t.Errorf("existing override = %q, want a suffixed feat/taken", got)
+	}
+}
+
 func TestAddWithOptions_NoPrimeMDCreatedLocally(t *testing.T) {
 	// This test verifies that ProvisionPrimeMDForWorktree does NOT create
 	// a local .beads/PRIME.md in the worktree when there's no tracked one.
@@ -1696,3 +1727,137 @@ func TestAllocateAndAdd_NoDuplicateNames(t *testing.T) {
 		}
 	}
 }
+
+// setupRenameRig creates a rig whose polecat toast has a worktree and agent
+// settings in its home directory.
+func setupRenameRig(t *testing.T) (root string, mayorGit *git.Git) {
+	t.Helper()
+	for _, kv := range [][2]string{{"GIT_AUTHOR_NAME", "test"}, {"GIT_AUTHOR_EMAIL", "test@example.com"}, {"GIT_COMMITTER_NAME", "test"}, {"GIT_COMMITTER_EMAIL", "test@example.com"}} {
+		t.Setenv(kv[0], kv[1])
+	}
+	root = t.TempDir()
+	mayorRig := filepath.Join(root, "mayor", "rig")
+	if err := os.MkdirAll(mayorRig, 0755); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("git", "init")
+	cmd.Dir = mayorRig
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	if err := os.WriteFile(filepath.Join(mayorRig, "README.md"), []byte("hi\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mayorGit = git.NewGit(mayorRig)
+	if err := mayorGit.Add("README.md"); err != nil {
+		t.Fatalf("git add: %v", err)
+	}
+	if err := mayorGit.Commit("init"); err != nil {
+		t.Fatalf("git commit: %v", err)
+	}
+
+	oldClone := filepath.Join(root, "polecats", "toast", "rig")
+	if err := mayorGit.WorktreeAdd(oldClone, "polecat/toast"); err != nil {
+		t.Fatalf("worktree add: %v", err)
+	}
+	settings := filepath.Join(root, "polecats", "toast", ".claude", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(settings), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(settings, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return root, mayorGit
+}
+
+func TestRename(t *testing.T) {
+	root, mayorGit := setupRenameRig(t)
+	r := &rig.Rig{Name: "rig", Path: root}
+	m := NewManager(r, git.NewGit(root), nil)
+	if err := m.Rename("toast", "furiosa"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "polecats", "toast")); !os.IsNotExist(err) {
+		t.Errorf("old polecat dir still exists: %v", err)
+	}
+	newClone := filepath.Join(root, "polecats", "furiosa", "rig")
+	if m.ClonePath("furiosa") != newClone {
+		t.Errorf("ClonePath(furiosa) = %s", m.ClonePath("furiosa"))
+	}
+	if _, err := os.Stat(filepath.Join(root, "polecats", "furiosa", ".claude", "settings.json")); err != nil {
+		t.Errorf("settings not moved: %v", err)
+	}
+	worktrees, err := mayorGit.WorktreeList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, wt := range worktrees {
+		if resolved, _ := filepath.EvalSymlinks(wt.Path); resolved == mustEvalSymlinks(t, newClone) {
+			found = wt.Branch == "polecat/toast"
+		}
+	}
+	if !found {
+		t.Errorf("worktree not registered at %s: %+v", newClone, worktrees)
+	}
+
+	if err := m.Rename("furiosa", "../escape"); err == nil {
+		t.Error("Rename accepted a path as the new name")
+	}
+	if err := m.Rename("toast", "nux"); err != ErrPolecatNotFound {
+		t.Errorf("Rename(missing) = %v, want ErrPolecatNotFound", err)
+	}
+}
+
+func TestRename_UndoesOnFailure(t *testing.T) {
+	root, mayorGit := setupRenameRig(t)
+	orig := renamePolecatEntry
+	t.Cleanup(func() { renamePolecatEntry = orig })
+	renamePolecatEntry = func(from, to string) error {
+		if filepath.Base(from) == ".claude" {
+			return errors.New("disk full")
+		}
+		return orig(from, to)
+	}
+
+	r := &rig.Rig{Name: "rig", Path: root}
+	m := NewManager(r, git.NewGit(root), nil)
+	if err := m.Rename("toast", "furiosa"); err == nil {
+		t.Fatal("Rename succeeded despite the failed move")
+	}
+
+	oldClone := filepath.Join(root, "polecats", "toast", "rig")
+	if _, err := os.Stat(filepath.Join(oldClone, "README.md")); err != nil {
+		t.Errorf("worktree not moved back: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "polecats", "toast", ".claude", "settings.json")); err != nil {
+		t.Errorf("settings not left in place: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "polecats", "furiosa")); !os.IsNotExist(err) {
+		t.Errorf("new polecat dir left behind: %v", err)
+	}
+	worktrees, err := mayorGit.WorktreeList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, wt := range worktrees {
+		if resolved, _ := filepath.EvalSymlinks(wt.Path); resolved == mustEvalSymlinks(t, oldClone) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("worktree not registered back at %s: %+v", oldClone, worktrees)
+	}
+}
+
+func mustEvalSymlinks(t *testing.T, path string) string {
+	t.Helper()
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resolved
+}
@@ -755,7 +755,7 @@ func (m *SessionManager) validateIssue(issueID, workDir string) error {
 	defer cancel()
 	cmd := exec.CommandContext(ctx, "bd", "show", issueID, "--json") //nolint:gosec // G204: bd is a trusted internal tool
 	cmd.Dir = bdWorkDir
-	output, err := cmd.Output()
+	output, err := beads.CmdOutputContext(ctx, cmd)
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrIssueInvalid, issueID)
 	}
@@ -836,7 +836,7 @@ func (m *SessionManager) hookIssue(issueID, agentID, workDir string) error {
 	cmd := exec.CommandContext(ctx, "bd", "update", issueID, "--status=hooked", "--assignee="+agentID) //nolint:gosec // G204: bd is a trusted internal tool
 	cmd.Dir = bdWorkDir
 	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmdContext(ctx, cmd); err != nil {
 		return fmt.Errorf("bd update failed: %w", err)
 	}
 	fmt.Printf("✓ Hooked issue %s to %s\n", issueID, agentID)
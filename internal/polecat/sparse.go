@@ -0,0 +1,54 @@
+package polecat
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// sparsePaths resolves the sparse checkout paths for a polecat working on
+// hookBead from the rig's sparse profiles and the bead's sparse: labels.
+// nil means a full checkout.
+func (m *Manager) sparsePaths(hookBead string) []string {
+	rigCfg, err := rig.LoadRigConfig(m.rig.Path)
+	if err != nil || (rigCfg.Sparse == nil && rigCfg.Monorepo == nil) {
+		return nil
+	}
+
+	var labels []string
+	if hookBead != "" && m.beads != nil {
+		if issue, err := m.beads.Show(hookBead); err == nil {
+			labels = issue.Labels
+		}
+	}
+	paths, unknown := rigCfg.SparsePaths(labels)
+	for _, label := range unknown {
+		style.PrintWarning("%s: label %s names no profile in %s/config.json sparse.profiles", hookBead, label, m.rig.Name)
+	}
+	return paths
+}
+
+// addWorktree creates a polecat worktree, sparse when a profile applies.
+func (m *Manager) addWorktree(repoGit *git.Git, clonePath, branchName, startPoint, hookBead string) error {
+	paths := m.sparsePaths(hookBead)
+	if len(paths) == 0 {
+		return repoGit.WorktreeAddFromRef(clonePath, branchName, startPoint)
+	}
+	fmt.Printf("  Sparse checkout: %v\n", paths)
+	return repoGit.WorktreeAddSparseFromRef(clonePath, branchName, startPoint, paths)
+}
+
+// applySparseProfile updates a reused worktree to the profile for its new
+// bead, widening to a full checkout when none applies.
+func (m *Manager) applySparseProfile(clonePath, hookBead string) error {
+	paths := m.sparsePaths(hookBead)
+	if len(paths) == 0 {
+		if git.IsSparseCheckoutConfigured(clonePath) {
+			return git.RemoveSparseCheckout(clonePath)
+		}
+		return nil
+	}
+	return git.InitSparseCheckout(clonePath, paths)
+}
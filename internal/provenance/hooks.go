@@ -0,0 +1,111 @@
+package provenance
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// forwardedHooks are the client-side hooks the stamping hooks directory
+// passes on to the repository's own hooks. reference-transaction and
+// similar per-ref hooks are left out: forwarding them would fork a shell
+// for every ref update.
+var forwardedHooks = []string{
+	"applypatch-msg", "pre-applypatch", "post-applypatch",
+	"pre-commit", "pre-merge-commit", "prepare-commit-msg", "commit-msg", "post-commit",
+	"pre-rebase", "post-checkout", "post-merge", "pre-push", "post-rewrite", "pre-auto-gc",
+}
+
+// forwardScript runs the repository's own hook of the same name: the
+// checked-in .githooks first (gt's usual core.hooksPath), else the hooks
+// directory of the common git dir.
+const forwardScript = `name=$(basename "$0")
+top=$(git rev-parse --show-toplevel 2>/dev/null)
+if [ -n "$top" ] && [ -x "$top/.githooks/$name" ]; then
+	exec "$top/.githooks/$name" "$@"
+fi
+common=$(git rev-parse --git-common-dir 2>/dev/null)
+if [ -n "$common" ] && [ -x "$common/hooks/$name" ]; then
+	exec "$common/hooks/$name" "$@"
+fi
+exit 0
+`
+
+// HooksDir is where a town's stamping hooks live.
+func HooksDir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "provenance", "hooks")
+}
+
+// hookScript returns the script installed for a hook.
+func hookScript(name string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n# Installed by gt (provenance). Do not edit.\n")
+	if name == "commit-msg" {
+		b.WriteString(`gt provenance stamp "$1" || echo "gt: could not stamp provenance trailers" >&2` + "\n")
+	}
+	b.WriteString(forwardScript)
+	return b.String()
+}
+
+// EnsureHooks writes the stamping hooks into dir, rewriting only the ones
+// that changed. Agent sessions point core.hooksPath here, so commit-msg
+// stamps each commit and every hook still reaches the repository's own.
+func EnsureHooks(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, name := range forwardedHooks {
+		p := filepath.Join(dir, name)
+		script := []byte(hookScript(name))
+		if existing, err := os.ReadFile(p); err == nil && bytes.Equal(existing, script) { //nolint:gosec // G304: path is constructed internally
+			continue
+		}
+		if err := os.WriteFile(p, script, 0755); err != nil { //nolint:gosec // G306: hooks must be executable
+			return fmt.Errorf("writing %s hook: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// StampFile adds the stamp's trailers to a commit message file, leaving
+// trailers the message already has. Messages with nothing but comments are
+// left alone so git still aborts the empty commit.
+func StampFile(msgFile string, s Stamp) error {
+	data, err := os.ReadFile(msgFile) //nolint:gosec // G304: path comes from git
+	if err != nil {
+		return err
+	}
+	trailers := s.Trailers()
+	if len(trailers) == 0 || !hasContent(string(data)) {
+		return nil
+	}
+	args := []string{"interpret-trailers", "--in-place", "--if-exists", "doNothing"}
+	for _, t := range trailers {
+		args = append(args, "--trailer", t)
+	}
+	args = append(args, msgFile)
+	if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("git interpret-trailers: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// hasContent reports whether a commit message has text besides comments,
+// stopping at the scissors line of git commit --verbose.
+func hasContent(msg string) bool {
+	for _, line := range strings.Split(msg, "\n") {
+		if strings.HasPrefix(line, "# ------------------------ >8") {
+			return false
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,291 @@
+// Package provenance records who made an agent commit and why, and lets the
+// merge queue check it.
+//
+// Agent commits carry Gt-* trailers naming the agent, the bead it was
+// working, the gt version and the session, and can be signed with an SSH or
+// OpenPGP key chosen per agent identity. With verification on, the refinery
+// refuses branches whose commits lack the trailers or a good signature.
+package provenance
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Trailer keys stamped on agent commits.
+const (
+	TrailerAgent   = "Gt-Agent"
+	TrailerBead    = "Gt-Bead"
+	TrailerVersion = "Gt-Version"
+	TrailerSession = "Gt-Session"
+)
+
+// Signature formats, as git's gpg.format.
+const (
+	FormatSSH     = "ssh"
+	FormatOpenPGP = "openpgp"
+)
+
+// Config configures commit provenance (settings/config.json "provenance").
+type Config struct {
+	// Trailers stamps Gt-* trailers on every commit made in an agent session.
+	Trailers bool `json:"trailers,omitempty"`
+
+	// Signing signs agent commits. nil = unsigned.
+	Signing *SigningConfig `json:"signing,omitempty"`
+
+	// Verify makes the merge queue refuse branches with a commit that lacks
+	// the trailers (when Trailers is on) or a good signature (when Signing
+	// is set).
+	Verify bool `json:"verify,omitempty"`
+}
+
+// SigningConfig picks the key agent commits are signed with.
+type SigningConfig struct {
+	// Format is "ssh" (default) or "openpgp".
+	Format string `json:"format,omitempty"`
+
+	// Key signs commits of agents without an entry in Keys: a key file or
+	// "key::" literal for ssh, a key ID for openpgp (git's user.signingkey).
+	Key string `json:"key,omitempty"`
+
+	// Keys gives agent identities their own keys. Identities are BD_ACTOR
+	// addresses and may be patterns: {"gastown/polecats/*": "~/.ssh/polecats.pub"}.
+	Keys map[string]string `json:"keys,omitempty"`
+
+	// AllowedSigners is the ssh allowed-signers file the merge queue checks
+	// signatures against. Required to verify ssh signatures.
+	AllowedSigners string `json:"allowed_signers,omitempty"`
+}
+
+// Enabled reports whether agent commits are stamped or signed.
+func (c *Config) Enabled() bool {
+	return c != nil && (c.Trailers || c.Signing != nil)
+}
+
+// Verifies reports whether the merge queue checks provenance.
+func (c *Config) Verifies() bool {
+	return c.Enabled() && c.Verify
+}
+
+// Validate checks the signing format and that verification has something
+// to verify.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if s := c.Signing; s != nil {
+		if s.Format != "" && s.Format != FormatSSH && s.Format != FormatOpenPGP {
+			return fmt.Errorf("provenance.signing.format %q: want %s or %s", s.Format, FormatSSH, FormatOpenPGP)
+		}
+		if s.Key == "" && len(s.Keys) == 0 {
+			return fmt.Errorf("provenance.signing needs a key or keys")
+		}
+		for pattern := range s.Keys {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("provenance.signing.keys: bad pattern %q", pattern)
+			}
+		}
+		if c.Verify && s.format() == FormatSSH && s.AllowedSigners == "" {
+			return fmt.Errorf("provenance.verify with ssh signing needs signing.allowed_signers")
+		}
+	}
+	if c.Verify && !c.Enabled() {
+		return fmt.Errorf("provenance.verify needs trailers or signing")
+	}
+	return nil
+}
+
+func (s *SigningConfig) format() string {
+	if s.Format == "" {
+		return FormatSSH
+	}
+	return s.Format
+}
+
+// KeyFor returns the signing key for an agent identity: an exact entry in
+// Keys, else the longest matching pattern, else Key.
+func (s *SigningConfig) KeyFor(identity string) string {
+	if s == nil {
+		return ""
+	}
+	if key, ok := s.Keys[identity]; ok {
+		return key
+	}
+	best, key := "", s.Key
+	for pattern, k := range s.Keys {
+		if ok, _ := path.Match(pattern, identity); ok && len(pattern) > len(best) {
+			best, key = pattern, k
+		}
+	}
+	return key
+}
+
+// GitConfig returns the git config ("key=value") an agent session commits
+// with. hooksDir holds the stamping hook (see EnsureHooks); it is used only
+// when trailers are on.
+func (c *Config) GitConfig(identity, hooksDir string) []string {
+	if !c.Enabled() {
+		return nil
+	}
+	var cfg []string
+	if c.Trailers && hooksDir != "" {
+		cfg = append(cfg, "core.hooksPath="+hooksDir)
+	}
+	if key := c.Signing.KeyFor(identity); key != "" {
+		cfg = append(cfg,
+			"commit.gpgsign=true",
+			"gpg.format="+c.Signing.format(),
+			"user.signingkey="+key,
+		)
+	}
+	return cfg
+}
+
+// VerifyGitConfig returns the git config signatures are checked with.
+func (c *Config) VerifyGitConfig() []string {
+	if c == nil || c.Signing == nil || c.Signing.AllowedSigners == "" {
+		return nil
+	}
+	return []string{"gpg.ssh.allowedSignersFile=" + c.Signing.AllowedSigners}
+}
+
+// GitConfigEnv turns git config entries into GIT_CONFIG_COUNT/KEY/VALUE
+// environment variables, which git (2.31+) applies to every command.
+func GitConfigEnv(cfg []string) map[string]string {
+	if len(cfg) == 0 {
+		return nil
+	}
+	env := map[string]string{"GIT_CONFIG_COUNT": fmt.Sprint(len(cfg))}
+	for i, entry := range cfg {
+		key, value, _ := strings.Cut(entry, "=")
+		env[fmt.Sprintf("GIT_CONFIG_KEY_%d", i)] = key
+		env[fmt.Sprintf("GIT_CONFIG_VALUE_%d", i)] = value
+	}
+	return env
+}
+
+// Stamp is the provenance recorded on a commit.
+type Stamp struct {
+	Agent   string
+	Bead    string
+	Version string
+	Session string
+}
+
+// Trailers returns the stamp's non-empty fields as "Key: value" trailers.
+func (s Stamp) Trailers() []string {
+	var out []string
+	for _, t := range [][2]string{
+		{TrailerAgent, s.Agent},
+		{TrailerBead, s.Bead},
+		{TrailerVersion, s.Version},
+		{TrailerSession, s.Session},
+	} {
+		if t[1] != "" {
+			out = append(out, t[0]+": "+t[1])
+		}
+	}
+	return out
+}
+
+// ParseStamp reads the Gt-* trailers from a commit message's last paragraph.
+func ParseStamp(message string) Stamp {
+	message = strings.TrimSpace(message)
+	if i := strings.LastIndex(message, "\n\n"); i >= 0 {
+		message = message[i+2:]
+	}
+	var s Stamp
+	for _, line := range strings.Split(message, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case TrailerAgent:
+			s.Agent = value
+		case TrailerBead:
+			s.Bead = value
+		case TrailerVersion:
+			s.Version = value
+		case TrailerSession:
+			s.Session = value
+		}
+	}
+	return s
+}
+
+// Problem is a commit that fails verification.
+type Problem struct {
+	SHA    string
+	Reason string
+}
+
+func (p Problem) String() string {
+	sha := p.SHA
+	if len(sha) > 8 {
+		sha = sha[:8]
+	}
+	return sha + ": " + p.Reason
+}
+
+// Check verifies commits against the config. The agent and version
+// trailers are required; bead and session are recorded when known.
+func (c *Config) Check(commits []git.LoggedCommit) []Problem {
+	if !c.Verifies() {
+		return nil
+	}
+	var problems []Problem
+	for _, commit := range commits {
+		if c.Trailers {
+			s := ParseStamp(commit.Message)
+			var missing []string
+			if s.Agent == "" {
+				missing = append(missing, TrailerAgent)
+			}
+			if s.Version == "" {
+				missing = append(missing, TrailerVersion)
+			}
+			if len(missing) > 0 {
+				problems = append(problems, Problem{commit.SHA, "missing " + strings.Join(missing, ", ") + " trailer"})
+			}
+		}
+		if c.Signing != nil {
+			if reason := signatureProblem(commit.Signature); reason != "" {
+				problems = append(problems, Problem{commit.SHA, reason})
+			}
+		}
+	}
+	return problems
+}
+
+// signatureProblem describes a %G? status that doesn't prove authorship.
+func signatureProblem(status string) string {
+	switch status {
+	case "G", "U":
+		return ""
+	case "N", "":
+		return "unsigned"
+	case "B":
+		return "bad signature"
+	case "X", "Y":
+		return "signed with an expired key"
+	case "R":
+		return "signed with a revoked key"
+	default:
+		return "signature could not be checked (unknown signer?)"
+	}
+}
+
+// Describe summarizes problems for a merge failure message.
+func Describe(problems []Problem) string {
+	parts := make([]string, 0, len(problems))
+	for _, p := range problems {
+		parts = append(parts, p.String())
+	}
+	return fmt.Sprintf("%d commit(s) fail provenance checks: %s", len(problems), strings.Join(parts, "; "))
+}
@@ -0,0 +1,183 @@
+package provenance
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr string
+	}{
+		{"nil", nil, ""},
+		{"trailers", &Config{Trailers: true, Verify: true}, ""},
+		{"openpgp verify", &Config{Signing: &SigningConfig{Format: FormatOpenPGP, Key: "ABCD"}, Verify: true}, ""},
+		{"bad format", &Config{Signing: &SigningConfig{Format: "x509", Key: "k"}}, "format"},
+		{"no key", &Config{Signing: &SigningConfig{}}, "needs a key"},
+		{"bad pattern", &Config{Signing: &SigningConfig{Keys: map[string]string{"gastown/[": "k"}}}, "bad pattern"},
+		{"ssh verify without signers", &Config{Signing: &SigningConfig{Key: "k"}, Verify: true}, "allowed_signers"},
+		{"verify nothing", &Config{Verify: true}, "needs trailers or signing"},
+	}
+	for _, tt := range tests {
+		err := tt.cfg.Validate()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: err = %v, want containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSigningConfig_KeyFor(t *testing.T) {
+	s := &SigningConfig{
+		Key: "town.pub",
+		Keys: map[string]string{
+			"gastown/*/*":        "rig.pub",
+			"gastown/polecats/*": "polecats.pub",
+			"gastown/crew/jack":  "jack.pub",
+		},
+	}
+	for identity, want := range map[string]string{
+		"gastown/crew/jack":    "jack.pub",
+		"gastown/polecats/nux": "polecats.pub",
+		"gastown/crew/max":     "rig.pub",
+		"mayor":                "town.pub",
+	} {
+		if got := s.KeyFor(identity); got != want {
+			t.Errorf("KeyFor(%s) = %s, want %s", identity, got, want)
+		}
+	}
+}
+
+func TestGitConfigEnv(t *testing.T) {
+	cfg := &Config{Trailers: true, Signing: &SigningConfig{Key: "town.pub"}}
+	env := GitConfigEnv(cfg.GitConfig("mayor", "/town/.runtime/provenance/hooks"))
+	want := map[string]string{
+		"GIT_CONFIG_COUNT":   "4",
+		"GIT_CONFIG_KEY_0":   "core.hooksPath",
+		"GIT_CONFIG_VALUE_0": "/town/.runtime/provenance/hooks",
+		"GIT_CONFIG_KEY_1":   "commit.gpgsign",
+		"GIT_CONFIG_KEY_2":   "gpg.format",
+		"GIT_CONFIG_VALUE_2": "ssh",
+		"GIT_CONFIG_KEY_3":   "user.signingkey",
+		"GIT_CONFIG_VALUE_3": "town.pub",
+	}
+	for k, v := range want {
+		if env[k] != v {
+			t.Errorf("%s = %q, want %q", k, env[k], v)
+		}
+	}
+
+	if env := GitConfigEnv((&Config{}).GitConfig("mayor", "/hooks")); env != nil {
+		t.Errorf("disabled config env = %v", env)
+	}
+}
+
+func TestParseStamp(t *testing.T) {
+	msg := "fix: handle empty queue\n\nBody mentioning Gt-Agent: nobody.\n\nGt-Agent: gastown/polecats/nux\nGt-Bead: gt-abc12\nGt-Version: 0.12.0\nSigned-off-by: Nux <nux@gastown.local>"
+	s := ParseStamp(msg)
+	if s.Agent != "gastown/polecats/nux" || s.Bead != "gt-abc12" || s.Version != "0.12.0" || s.Session != "" {
+		t.Errorf("ParseStamp = %+v", s)
+	}
+	// The last paragraph is the trailer block, whatever else it holds.
+	if s := ParseStamp("fix: x\n\nGt-Agent: in the body\nmore body"); s.Agent != "in the body" {
+		t.Errorf("ParseStamp last paragraph = %+v", s)
+	}
+	if s := ParseStamp("fix: x"); s != (Stamp{}) {
+		t.Errorf("ParseStamp without trailers = %+v", s)
+	}
+}
+
+func TestConfig_Check(t *testing.T) {
+	stamped := "feat: x\n\nGt-Agent: gastown/polecats/nux\nGt-Version: 0.12.0"
+	commits := []git.LoggedCommit{
+		{SHA: "aaaaaaaaaaaa", Message: stamped, Signature: "G"},
+		{SHA: "bbbbbbbbbbbb", Message: "feat: y\n\nGt-Agent: gastown/polecats/nux", Signature: "N"},
+		{SHA: "cccccccccccc", Message: stamped, Signature: "B"},
+	}
+
+	cfg := &Config{Trailers: true, Signing: &SigningConfig{Format: FormatOpenPGP, Key: "k"}, Verify: true}
+	got := make([]string, 0, 3)
+	for _, p := range cfg.Check(commits) {
+		got = append(got, p.String())
+	}
+	want := []string{
+		"bbbbbbbb: missing Gt-Version trailer",
+		"bbbbbbbb: unsigned",
+		"cccccccc: bad signature",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Check = %q, want %q", got, want)
+	}
+
+	cfg.Verify = false
+	if problems := cfg.Check(commits); problems != nil {
+		t.Errorf("Check without verify = %v", problems)
+	}
+}
+
+func TestStampFile(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	msgFile := filepath.Join(dir, "COMMIT_EDITMSG")
+	stamp := Stamp{Agent: "gastown/polecats/nux", Bead: "gt-abc12", Version: "0.12.0"}
+
+	if err := os.WriteFile(msgFile, []byte("fix: handle empty queue\n\nGt-Bead: gt-other\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := StampFile(msgFile, stamp); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(msgFile)
+	got := ParseStamp(string(data))
+	if got.Agent != stamp.Agent || got.Version != stamp.Version || got.Bead != "gt-other" {
+		t.Errorf("stamped message:\n%s", data)
+	}
+
+	// An aborted commit (comments only) stays empty so git still aborts.
+	empty := "\n# Please enter the commit message for your changes.\n"
+	if err := os.WriteFile(msgFile, []byte(empty), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := StampFile(msgFile, stamp); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(msgFile); string(data) != empty {
+		t.Errorf("comment-only message was stamped:\n%s", data)
+	}
+}
+
+func TestEnsureHooks(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hooks")
+	if err := EnsureHooks(dir); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "commit-msg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "gt provenance stamp") || !strings.Contains(string(data), ".githooks/$name") {
+		t.Errorf("commit-msg hook:\n%s", data)
+	}
+	data, err = os.ReadFile(filepath.Join(dir, "pre-push"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "gt provenance stamp") {
+		t.Error("only commit-msg should stamp")
+	}
+	if err := EnsureHooks(dir); err != nil {
+		t.Errorf("second EnsureHooks: %v", err)
+	}
+}
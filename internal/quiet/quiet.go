@@ -0,0 +1,303 @@
+// Package quiet implements quiet hours: a daily window during which the
+// town stops dispatching new work, parks idle agents and holds non-urgent
+// mail notifications, resuming on its own when the window ends.
+package quiet
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// defaultParkAfter is how long an agent must be idle during quiet hours
+// before it is parked.
+const defaultParkAfter = 15 * time.Minute
+
+// Config is the quiet_hours section of town or rig settings/config.json.
+//
+//	"quiet_hours": {
+//	  "enabled": true,
+//	  "start": "22:00",
+//	  "end": "07:00",
+//	  "days": ["mon", "tue", "wed", "thu", "fri"],
+//	  "park_after": "15m"
+//	}
+//
+// Times are local. A window whose end is not after its start runs past
+// midnight. Days lists the days a window starts on (default: every day).
+// A rig's quiet_hours replaces the town's entirely.
+type Config struct {
+	Enabled      bool     `json:"enabled"`
+	Start        string   `json:"start"`
+	End          string   `json:"end"`
+	Days         []string `json:"days,omitempty"`
+	ParkAfterStr string   `json:"park_after,omitempty"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// IsEnabled reports whether quiet hours are configured and on.
+func (c *Config) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// Validate checks the window. A nil config is valid (no quiet hours).
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if _, _, err := parseClock(c.Start); err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	if _, _, err := parseClock(c.End); err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	if c.Start == c.End {
+		return fmt.Errorf("start and end are both %s", c.Start)
+	}
+	for _, d := range c.Days {
+		if _, ok := weekdays[strings.ToLower(d)]; !ok {
+			return fmt.Errorf("unknown day %q (use mon, tue, ... sun)", d)
+		}
+	}
+	if c.ParkAfterStr != "" {
+		if d, err := time.ParseDuration(c.ParkAfterStr); err != nil || d <= 0 {
+			return fmt.Errorf("park_after: invalid duration %q", c.ParkAfterStr)
+		}
+	}
+	return nil
+}
+
+// ParkAfter returns how long an agent may sit idle during quiet hours
+// before it is parked (default 15m).
+func (c *Config) ParkAfter() time.Duration {
+	if c != nil && c.ParkAfterStr != "" {
+		if d, err := time.ParseDuration(c.ParkAfterStr); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultParkAfter
+}
+
+// Window returns the quiet window containing now, if any. Windows that
+// started yesterday are considered so overnight windows span midnight.
+func (c *Config) Window(now time.Time) (start, end time.Time, active bool) {
+	if !c.IsEnabled() || c.Validate() != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	sh, sm, _ := parseClock(c.Start)
+	eh, em, _ := parseClock(c.End)
+	for _, offset := range []int{-1, 0} {
+		day := now.AddDate(0, 0, offset)
+		if !c.startsOn(day.Weekday()) {
+			continue
+		}
+		start = time.Date(day.Year(), day.Month(), day.Day(), sh, sm, 0, 0, now.Location())
+		end = time.Date(day.Year(), day.Month(), day.Day(), eh, em, 0, 0, now.Location())
+		if !end.After(start) {
+			end = end.AddDate(0, 0, 1)
+		}
+		if !now.Before(start) && now.Before(end) {
+			return start, end, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// Active reports whether now falls inside a quiet window.
+func (c *Config) Active(now time.Time) bool {
+	_, _, active := c.Window(now)
+	return active
+}
+
+func (c *Config) startsOn(day time.Weekday) bool {
+	if len(c.Days) == 0 {
+		return true
+	}
+	for _, d := range c.Days {
+		if weekdays[strings.ToLower(d)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock parses an HH:MM time of day.
+func parseClock(s string) (hour, minute int, err error) {
+	h, m, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid time %q: expected HH:MM", s)
+	}
+	hour, err = strconv.Atoi(h)
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, 0, fmt.Errorf("invalid hour in %q: expected 0-23", s)
+	}
+	minute, err = strconv.Atoi(m)
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("invalid minute in %q: expected 0-59", s)
+	}
+	return hour, minute, nil
+}
+
+// Dir returns the directory holding quiet-hours state.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "quiet")
+}
+
+func statePath(townRoot string) string { return filepath.Join(Dir(townRoot), "state.json") }
+func heldPath(townRoot string) string  { return filepath.Join(Dir(townRoot), "held.jsonl") }
+
+// TownScope is the State key for town-level agents (mayor, deacon, overseer).
+const TownScope = "town"
+
+// State tracks which scopes were quiet at the last tick and which crew
+// were parked, so the end of a window can undo exactly what it did.
+type State struct {
+	Active     map[string]time.Time `json:"active,omitempty"`      // Scope -> window end
+	ParkedCrew map[string][]string  `json:"parked_crew,omitempty"` // Rig -> crew names
+}
+
+// LoadState reads the quiet-hours state, returning an empty state when the
+// file doesn't exist yet.
+func LoadState(townRoot string) (*State, error) {
+	state := &State{}
+	data, err := os.ReadFile(statePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Save writes the quiet-hours state.
+func (s *State) Save(townRoot string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(statePath(townRoot), data, 0644) //nolint:gosec // G306: runtime state
+}
+
+// Pending reports whether a window is still open in the state file or
+// notifications are held, i.e. whether a tick has something to undo.
+func Pending(townRoot string) bool {
+	if state, err := LoadState(townRoot); err == nil && (len(state.Active) > 0 || len(state.ParkedCrew) > 0) {
+		return true
+	}
+	info, err := os.Stat(heldPath(townRoot))
+	return err == nil && info.Size() > 0
+}
+
+// Held is a mail notification deferred until quiet hours end.
+type Held struct {
+	Scope   string    `json:"scope"` // Rig name or TownScope
+	To      string    `json:"to"`
+	From    string    `json:"from"`
+	Subject string    `json:"subject"`
+	At      time.Time `json:"at"`
+}
+
+// Hold defers a mail notification. The message itself is already in the
+// recipient's mailbox; only the nudge waits.
+func Hold(townRoot string, h Held) error {
+	if h.At.IsZero() {
+		h.At = time.Now().UTC()
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	return withHeldLock(townRoot, func(path string) error {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: runtime state
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(append(data, '\n')); err != nil {
+			_ = f.Close()
+			return err
+		}
+		return f.Close()
+	})
+}
+
+// PendingHeld returns held notifications without releasing them.
+func PendingHeld(townRoot string) ([]Held, error) {
+	return readHeld(heldPath(townRoot))
+}
+
+// TakeHeld removes and returns the held notifications for a scope.
+func TakeHeld(townRoot, scope string) ([]Held, error) {
+	var taken []Held
+	err := withHeldLock(townRoot, func(path string) error {
+		all, err := readHeld(path)
+		if err != nil {
+			return err
+		}
+		var keep []byte
+		for _, h := range all {
+			if h.Scope == scope {
+				taken = append(taken, h)
+				continue
+			}
+			line, _ := json.Marshal(h)
+			keep = append(append(keep, line...), '\n')
+		}
+		if len(taken) == 0 {
+			return nil
+		}
+		return os.WriteFile(path, keep, 0644) //nolint:gosec // G306: runtime state
+	})
+	return taken, err
+}
+
+func withHeldLock(townRoot string, fn func(path string) error) error {
+	path := heldPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating quiet directory: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking held notifications: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+	return fn(path)
+}
+
+func readHeld(path string) ([]Held, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var held []Held
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var h Held
+		if err := json.Unmarshal(scanner.Bytes(), &h); err == nil {
+			held = append(held, h)
+		}
+	}
+	return held, scanner.Err()
+}
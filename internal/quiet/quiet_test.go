@@ -0,0 +1,135 @@
+package quiet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindow(t *testing.T) {
+	overnight := &Config{Enabled: true, Start: "22:00", End: "07:00"}
+	daytime := &Config{Enabled: true, Start: "12:00", End: "13:30"}
+	weeknights := &Config{Enabled: true, Start: "22:00", End: "07:00", Days: []string{"Mon", "tue", "wed", "thu", "fri"}}
+
+	// 2026-10-12 is a Monday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, time.Local)
+	}
+	tests := []struct {
+		name    string
+		cfg     *Config
+		now     time.Time
+		want    bool
+		wantEnd time.Time
+	}{
+		{"before overnight window", overnight, at(12, 21, 59), false, time.Time{}},
+		{"overnight start", overnight, at(12, 22, 0), true, at(13, 7, 0)},
+		{"overnight after midnight", overnight, at(13, 3, 0), true, at(13, 7, 0)},
+		{"overnight end is exclusive", overnight, at(13, 7, 0), false, time.Time{}},
+		{"daytime inside", daytime, at(12, 12, 45), true, at(12, 13, 30)},
+		{"daytime outside", daytime, at(12, 14, 0), false, time.Time{}},
+		{"weeknight friday night", weeknights, at(16, 23, 0), true, at(17, 7, 0)},
+		{"weeknight saturday night", weeknights, at(17, 23, 0), false, time.Time{}},
+		{"weeknight early saturday", weeknights, at(17, 6, 0), true, at(17, 7, 0)},
+		{"disabled", &Config{Start: "00:00", End: "23:59"}, at(12, 12, 0), false, time.Time{}},
+		{"nil", nil, at(12, 12, 0), false, time.Time{}},
+	}
+	for _, tt := range tests {
+		_, end, active := tt.cfg.Window(tt.now)
+		if active != tt.want || !end.Equal(tt.wantEnd) {
+			t.Errorf("%s: Window(%s) = %v until %s, want %v until %s", tt.name, tt.now.Format("Mon 15:04"),
+				active, end.Format("Mon 15:04"), tt.want, tt.wantEnd.Format("Mon 15:04"))
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := []*Config{
+		nil,
+		{Start: "22:00", End: "07:00"},
+		{Start: "9:30", End: "17:00", Days: []string{"sat", "SUN"}, ParkAfterStr: "30m"},
+	}
+	for _, c := range valid {
+		if err := c.Validate(); err != nil {
+			t.Errorf("Validate(%+v) = %v, want nil", c, err)
+		}
+	}
+	invalid := []*Config{
+		{Start: "22", End: "07:00"},
+		{Start: "22:00", End: "24:00"},
+		{Start: "08:00", End: "08:00"},
+		{Start: "22:00", End: "07:00", Days: []string{"weekend"}},
+		{Start: "22:00", End: "07:00", ParkAfterStr: "-1m"},
+	}
+	for _, c := range invalid {
+		if err := c.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", c)
+		}
+	}
+}
+
+func TestParkAfter(t *testing.T) {
+	if got := (*Config)(nil).ParkAfter(); got != defaultParkAfter {
+		t.Errorf("nil ParkAfter() = %v, want %v", got, defaultParkAfter)
+	}
+	if got := (&Config{ParkAfterStr: "5m"}).ParkAfter(); got != 5*time.Minute {
+		t.Errorf("ParkAfter() = %v, want 5m", got)
+	}
+}
+
+func TestHoldAndTake(t *testing.T) {
+	townRoot := t.TempDir()
+	if Pending(townRoot) {
+		t.Error("Pending() on a fresh town = true")
+	}
+	for _, h := range []Held{
+		{Scope: "greenplace", To: "greenplace/Toast", From: "mayor/", Subject: "a"},
+		{Scope: "greenplace", To: "greenplace/Toast", From: "witness", Subject: "b"},
+		{Scope: TownScope, To: "mayor/", From: "greenplace/Toast", Subject: "c"},
+	} {
+		if err := Hold(townRoot, h); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !Pending(townRoot) {
+		t.Error("Pending() with held notifications = false")
+	}
+
+	taken, err := TakeHeld(townRoot, "greenplace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(taken) != 2 || taken[0].At.IsZero() {
+		t.Errorf("TakeHeld(greenplace) = %+v, want 2 timestamped entries", taken)
+	}
+	rest, _ := PendingHeld(townRoot)
+	if len(rest) != 1 || rest[0].Scope != TownScope {
+		t.Errorf("remaining = %+v, want only the town entry", rest)
+	}
+	if again, _ := TakeHeld(townRoot, "greenplace"); len(again) != 0 {
+		t.Errorf("second TakeHeld = %+v, want none", again)
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	state, err := LoadState(townRoot)
+	if err != nil || len(state.Active) != 0 {
+		t.Fatalf("LoadState() = %+v, %v; want empty", state, err)
+	}
+	end := time.Date(2026, 10, 13, 7, 0, 0, 0, time.UTC)
+	state.Active = map[string]time.Time{"greenplace": end}
+	state.ParkedCrew = map[string][]string{"greenplace": {"max"}}
+	if err := state.Save(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Active["greenplace"].Equal(end) || len(loaded.ParkedCrew["greenplace"]) != 1 {
+		t.Errorf("loaded = %+v, want saved state", loaded)
+	}
+	if !Pending(townRoot) {
+		t.Error("Pending() with an open window = false")
+	}
+}
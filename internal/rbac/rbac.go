@@ -0,0 +1,297 @@
+// Package rbac is a lightweight role-based access layer for human operators
+// sharing a town.
+//
+// Principals are configured in settings/config.json under "access". Each
+// human is an operator (full control), a viewer (observe and nudge), or a
+// rig admin (full control of listed rigs, observe and nudge elsewhere).
+// Commands are classified into levels (read, nudge, work, admin) and a
+// principal may run a command only if its role grants that level for the
+// rig being targeted.
+//
+// This is a guard rail against accidents between trusted teammates, not a
+// security boundary: anyone with write access to the town directory can edit
+// the access policy. Agent sessions (GT_ROLE set) are not subject to RBAC.
+package rbac
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Role names.
+const (
+	RoleOperator = "operator"  // Full control of the town
+	RoleViewer   = "viewer"    // Observe and nudge only
+	RoleRigAdmin = "rig-admin" // Full control of listed rigs; viewer elsewhere
+)
+
+// Level is the privilege a command requires.
+type Level int
+
+// Privilege levels, least to most privileged.
+const (
+	LevelRead  Level = iota // Observe: status, list, show, feed
+	LevelNudge              // Nudge agents and acknowledge escalations
+	LevelWork               // Dispatch and manage work: sling, mail, convoys, sessions
+	LevelAdmin              // Change the town: rig add/remove/park, config, policy
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelRead:
+		return "read"
+	case LevelNudge:
+		return "nudge"
+	case LevelWork:
+		return "work"
+	case LevelAdmin:
+		return "admin"
+	default:
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+}
+
+// ParseLevel parses a level name ("read", "nudge", "work", "admin").
+func ParseLevel(s string) (Level, error) {
+	for l := LevelRead; l <= LevelAdmin; l++ {
+		if l.String() == s {
+			return l, nil
+		}
+	}
+	return LevelRead, fmt.Errorf("invalid level %q: use read, nudge, work or admin", s)
+}
+
+// Principal grants a role to a named human.
+type Principal struct {
+	// Name matches the operator name ($GT_OPERATOR, then $USER).
+	Name string `json:"name"`
+	// Role is one of operator, viewer, rig-admin.
+	Role string `json:"role"`
+	// Rigs scopes a rig-admin. Ignored for other roles.
+	Rigs []string `json:"rigs,omitempty"`
+}
+
+// Config is the town access policy (settings/config.json "access").
+// A nil Config or one without principals disables enforcement.
+type Config struct {
+	// Principals lists known humans and their roles.
+	Principals []Principal `json:"principals,omitempty"`
+
+	// DefaultRole applies to humans not listed in Principals. Default: "viewer".
+	DefaultRole string `json:"default_role,omitempty"`
+}
+
+// IsEnforced reports whether any access policy is configured.
+func (c *Config) IsEnforced() bool {
+	return c != nil && len(c.Principals) > 0
+}
+
+// Lookup returns the principal for name, falling back to DefaultRole.
+func (c *Config) Lookup(name string) Principal {
+	if c != nil {
+		for _, p := range c.Principals {
+			if p.Name == name {
+				return p
+			}
+		}
+	}
+	role := RoleViewer
+	if c != nil && c.DefaultRole != "" {
+		role = c.DefaultRole
+	}
+	return Principal{Name: name, Role: role}
+}
+
+// Validate checks that roles are known and rig admins are scoped.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	for _, p := range c.Principals {
+		if p.Name == "" {
+			return fmt.Errorf("access: principal with empty name")
+		}
+		if !validRole(p.Role) {
+			return fmt.Errorf("access: principal %q has unknown role %q", p.Name, p.Role)
+		}
+		if p.Role == RoleRigAdmin && len(p.Rigs) == 0 {
+			return fmt.Errorf("access: rig-admin %q has no rigs", p.Name)
+		}
+	}
+	if c.DefaultRole != "" && (!validRole(c.DefaultRole) || c.DefaultRole == RoleRigAdmin) {
+		return fmt.Errorf("access: invalid default_role %q", c.DefaultRole)
+	}
+	return nil
+}
+
+func validRole(role string) bool {
+	return role == RoleOperator || role == RoleViewer || role == RoleRigAdmin
+}
+
+// Allows reports whether the principal may perform an action at level on rig.
+// rig is "" for town-wide actions.
+func (p Principal) Allows(level Level, rig string) bool {
+	switch p.Role {
+	case RoleOperator:
+		return true
+	case RoleRigAdmin:
+		if level <= LevelNudge {
+			return true
+		}
+		if rig == "" {
+			// Town-wide work (mail, convoys) is fine; town-wide admin is not.
+			return level == LevelWork
+		}
+		return slices.Contains(p.Rigs, rig)
+	default:
+		return level <= LevelNudge
+	}
+}
+
+// DeniedError is returned when a principal lacks the required level.
+type DeniedError struct {
+	Principal Principal
+	Command   string
+	Level     Level
+	Rig       string
+}
+
+func (e *DeniedError) Error() string {
+	scope := "the town"
+	if e.Rig != "" {
+		scope = "rig " + e.Rig
+	}
+	return fmt.Sprintf("access denied: %s (%s) cannot run %q, which needs %s access to %s",
+		e.Principal.Name, e.Principal.Role, e.Command, e.Level, scope)
+}
+
+// Check returns a *DeniedError if name may not run command (a space-separated
+// command path such as "rig remove") against rig. It returns nil when the
+// policy is not enforced.
+func (c *Config) Check(name, command, rig string) error {
+	if !c.IsEnforced() {
+		return nil
+	}
+	level := Classify(command)
+	p := c.Lookup(name)
+	if p.Allows(level, rig) {
+		return nil
+	}
+	return &DeniedError{Principal: p, Command: command, Level: level, Rig: rig}
+}
+
+// adminCommands change the shape or policy of the town. Matched by prefix.
+var adminCommands = []string{
+	"rig add", "rig remove", "rig rename", "rig park", "rig unpark", "rig dock", "rig undock",
+	"rig config", "rig settings", "rig reset", "rig adopt",
+	"config", "theme", "hooks install", "hooks sync", "hooks override",
+	"install", "uninstall", "enable", "disable", "upgrade", "account",
+	"down", "shutdown", "dolt", "daemon stop", "daemon start", "daemon reload",
+}
+
+// nudgeCommands are interventions a viewer may make.
+var nudgeCommands = []string{"nudge", "escalate ack", "shift"}
+
+// readCommands are top-level commands that only observe.
+var readCommands = []string{
+	"status", "feed", "activity", "audit", "info", "log", "version", "help",
+	"whoami", "whereami", "costs", "vitals", "trail", "peek", "cat", "show", "ready",
+	"stale", "dashboard", "doctor", "health", "metrics", "completion", "ask",
+	"knowledge search", "knowledge list", "mail check", "hooks diff",
+	"webhook test", "experiment report", "permissions check",
+	// Agent plumbing invoked on every turn; observes or refreshes local state only
+	"prime", "signal", "tap", "heartbeat", "statusline",
+}
+
+// adminFlags make a read-listed command change the town, so it needs admin
+// access ("doctor --fix" rewrites routes and configs).
+var adminFlags = map[string][]string{
+	"doctor": {"--fix", "--restart-sessions"},
+}
+
+// readVerbs mark read-only subcommands of any parent ("convoy list").
+var readVerbs = map[string]bool{
+	"list": true, "show": true, "status": true, "inbox": true, "peek": true,
+	"search": true, "stranded": true, "thread": true, "read": true,
+}
+
+// Classify returns the privilege level a command requires: a command path
+// optionally followed by the flags it was run with ("doctor --fix"). Only
+// adminFlags raise the level; other flags are ignored. Unknown commands
+// default to LevelWork.
+func Classify(command string) Level {
+	var fields, flags []string
+	for _, f := range strings.Fields(command) {
+		if strings.HasPrefix(f, "-") {
+			name, value, _ := strings.Cut(f, "=")
+			if value != "false" {
+				flags = append(flags, name)
+			}
+		} else {
+			fields = append(fields, f)
+		}
+	}
+	command = strings.Join(fields, " ")
+	for prefix, names := range adminFlags {
+		if hasCommandPrefix(command, []string{prefix}) {
+			for _, f := range flags {
+				if slices.Contains(names, f) {
+					return LevelAdmin
+				}
+			}
+		}
+	}
+	if len(fields) > 1 && readVerbs[fields[len(fields)-1]] {
+		return LevelRead
+	}
+	if hasCommandPrefix(command, adminCommands) {
+		return LevelAdmin
+	}
+	if hasCommandPrefix(command, nudgeCommands) {
+		return LevelNudge
+	}
+	if hasCommandPrefix(command, readCommands) {
+		return LevelRead
+	}
+	return LevelWork
+}
+
+func hasCommandPrefix(command string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if command == p || strings.HasPrefix(command, p+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// OperatorFromEnv returns the human operator name: $GT_OPERATOR, then $USER.
+func OperatorFromEnv() string {
+	for _, name := range []string{os.Getenv("GT_OPERATOR"), os.Getenv("USER")} {
+		if name = strings.TrimSpace(name); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// TargetRig returns the first positional argument (or its leading "<rig>/"
+// segment) that names a rig in the town, or "" for town-wide commands.
+func TargetRig(townRoot string, args []string) string {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, _, _ := strings.Cut(arg, "/")
+		if name == "" || strings.ContainsAny(name, `.\`) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(townRoot, name, "config.json")); err == nil {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,154 @@
+package rbac
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		command string
+		want    Level
+	}{
+		{"status", LevelRead},
+		{"convoy list", LevelRead},
+		{"polecat list --all", LevelRead},
+		{"rig config show", LevelRead},
+		{"nudge", LevelNudge},
+		{"escalate ack", LevelNudge},
+		{"sling", LevelWork},
+		{"mail send", LevelWork},
+		{"convoy check", LevelWork},
+		{"rig remove", LevelAdmin},
+		{"rig rename", LevelAdmin},
+		{"rig park", LevelAdmin},
+		{"config set", LevelAdmin},
+		{"something-new", LevelWork},
+		{"doctor", LevelRead},
+		{"doctor --fix", LevelAdmin},
+		{"doctor --fix=true", LevelAdmin},
+		{"doctor --fix=false", LevelRead},
+		{"doctor --verbose", LevelRead},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.command); got != tt.want {
+			t.Errorf("Classify(%q) = %s, want %s", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, l := range []Level{LevelRead, LevelNudge, LevelWork, LevelAdmin} {
+		if got, err := ParseLevel(l.String()); err != nil || got != l {
+			t.Errorf("ParseLevel(%q) = %s, %v", l, got, err)
+		}
+	}
+	if _, err := ParseLevel("root"); err == nil {
+		t.Error("ParseLevel(root) = nil error, want error")
+	}
+}
+
+func TestPrincipalAllows(t *testing.T) {
+	viewer := Principal{Name: "v", Role: RoleViewer}
+	admin := Principal{Name: "a", Role: RoleRigAdmin, Rigs: []string{"gastown"}}
+	op := Principal{Name: "o", Role: RoleOperator}
+
+	tests := []struct {
+		name  string
+		p     Principal
+		level Level
+		rig   string
+		want  bool
+	}{
+		{"viewer reads", viewer, LevelRead, "", true},
+		{"viewer nudges", viewer, LevelNudge, "gastown", true},
+		{"viewer cannot sling", viewer, LevelWork, "gastown", false},
+		{"viewer cannot archive", viewer, LevelAdmin, "gastown", false},
+		{"rig admin owns rig", admin, LevelAdmin, "gastown", true},
+		{"rig admin other rig", admin, LevelWork, "beads", false},
+		{"rig admin town work", admin, LevelWork, "", true},
+		{"rig admin town admin", admin, LevelAdmin, "", false},
+		{"operator anything", op, LevelAdmin, "", true},
+	}
+	for _, tt := range tests {
+		if got := tt.p.Allows(tt.level, tt.rig); got != tt.want {
+			t.Errorf("%s: Allows(%s, %q) = %v, want %v", tt.name, tt.level, tt.rig, got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var none *Config
+	if err := none.Check("anyone", "rig remove", "gastown"); err != nil {
+		t.Errorf("nil config should not enforce, got %v", err)
+	}
+
+	cfg := &Config{Principals: []Principal{{Name: "alice", Role: RoleOperator}}}
+	if err := cfg.Check("alice", "rig remove", "gastown"); err != nil {
+		t.Errorf("operator denied: %v", err)
+	}
+
+	// Unlisted humans fall back to viewer.
+	err := cfg.Check("bob", "rig remove", "gastown")
+	var denied *DeniedError
+	if !errors.As(err, &denied) {
+		t.Fatalf("Check(bob, rig remove) = %v, want DeniedError", err)
+	}
+	if denied.Principal.Role != RoleViewer || denied.Level != LevelAdmin || denied.Rig != "gastown" {
+		t.Errorf("unexpected denial details: %+v", denied)
+	}
+	if err := cfg.Check("bob", "nudge", "gastown"); err != nil {
+		t.Errorf("viewer nudge denied: %v", err)
+	}
+
+	cfg.DefaultRole = RoleOperator
+	if err := cfg.Check("bob", "rig remove", "gastown"); err != nil {
+		t.Errorf("default_role operator denied: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := &Config{Principals: []Principal{{Name: "a", Role: RoleRigAdmin, Rigs: []string{"x"}}}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+	for _, bad := range []*Config{
+		{Principals: []Principal{{Name: "a", Role: "root"}}},
+		{Principals: []Principal{{Name: "a", Role: RoleRigAdmin}}},
+		{Principals: []Principal{{Role: RoleViewer}}},
+		{DefaultRole: RoleRigAdmin},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", bad)
+		}
+	}
+}
+
+func TestTargetRig(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "gastown"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(town, "gastown", "config.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"gastown"}, "gastown"},
+		{[]string{"gt-abc", "gastown/polecats"}, "gastown"},
+		{[]string{"--force", "gastown/Toast"}, "gastown"},
+		{[]string{"gt-abc"}, ""},
+		{[]string{"../gastown"}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := TargetRig(town, tt.args); got != tt.want {
+			t.Errorf("TargetRig(%v) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -34,6 +34,33 @@ func DefaultBatchConfig() *BatchConfig {
 	}
 }
 
+// Enabled reports whether batch mode is on: a batch must hold more than one MR.
+func (c *BatchConfig) Enabled() bool {
+	return c != nil && c.MaxBatchSize > 1
+}
+
+// Wait returns how much longer to let the batch fill before processing.
+// It is zero once the ready MRs fill a batch or the oldest of them has
+// waited BatchWaitTime.
+func (c *BatchConfig) Wait(ready []*MRInfo, now time.Time) time.Duration {
+	if len(ready) == 0 || len(ready) >= c.MaxBatchSize || c.BatchWaitTime <= 0 {
+		return 0
+	}
+	var oldest time.Time
+	for _, mr := range ready {
+		if !mr.CreatedAt.IsZero() && (oldest.IsZero() || mr.CreatedAt.Before(oldest)) {
+			oldest = mr.CreatedAt
+		}
+	}
+	if oldest.IsZero() {
+		return 0
+	}
+	if remaining := c.BatchWaitTime - now.Sub(oldest); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
 // BatchResult holds the outcome of processing a batch of MRs.
 type BatchResult struct {
 	// Merged is the set of MRs that were successfully merged.
@@ -125,6 +152,20 @@ func (e *Engineer) BuildRebaseStack(ctx context.Context, batch []*MRInfo, target
 			continue
 		}
 
+		// Monorepo rigs only merge changes inside their subdirectory
+		if outside, scopeErr := e.checkMonorepoScope(target, mr.Branch); scopeErr == nil && len(outside) > 0 {
+			_, _ = fmt.Fprintf(e.output, "[Batch] MR %s: %s, removing from batch\n", mr.ID, e.describeScopeViolation(outside))
+			conflicts = append(conflicts, mr)
+			continue
+		}
+
+		// So do branches that fail provenance verification
+		if problem := e.checkProvenance(target, mr.Branch); problem != "" {
+			_, _ = fmt.Fprintf(e.output, "[Batch] MR %s: %s, removing from batch\n", mr.ID, problem)
+			conflicts = append(conflicts, mr)
+			continue
+		}
+
 		// Check for conflicts before merging
 		conflictFiles, conflictErr := e.git.CheckConflicts(mr.Branch, target)
 		if conflictErr != nil || len(conflictFiles) > 0 {
@@ -384,6 +425,7 @@ func (e *Engineer) fastForwardBatch(ctx context.Context, stacked []*MRInfo, targ
 		result.Error = fmt.Errorf("push to origin: %w", pushErr)
 		return result
 	}
+	e.pushMirrors(target)
 
 	ids := make([]string, len(stacked))
 	for i, mr := range stacked {
@@ -538,3 +580,140 @@ func (e *Engineer) resetAndRebuildStack(mrs []*MRInfo, target string) error {
 	}
 	return nil
 }
+
+// BatchCycle is the outcome of one RunBatchCycle.
+type BatchCycle struct {
+	// Target is the branch the batch lands on.
+	Target string
+
+	// Batch is the MRs claimed and processed, in stack order.
+	Batch []*MRInfo
+
+	// Wait is set instead of Batch while the batch is still filling.
+	Wait time.Duration
+
+	// Result is the batch outcome (nil when nothing was processed).
+	Result *BatchResult
+}
+
+// NextBatch picks the next batch off the ready queue without claiming it:
+// the MRs for the head MR's target, in queue order, up to MaxBatchSize.
+// While the batch is still filling only Wait is set.
+func (e *Engineer) NextBatch(now time.Time) (*BatchCycle, error) {
+	cfg := e.config.Batch
+	if !cfg.Enabled() {
+		return nil, fmt.Errorf("batch mode is off for rig %s (set merge_queue.batch.max_batch_size above 1)", e.rig.Name)
+	}
+
+	ready, err := e.ListReadyMRs()
+	if err != nil {
+		return nil, err
+	}
+	cycle := &BatchCycle{}
+	if len(ready) == 0 {
+		return cycle, nil
+	}
+
+	// A batch lands on one branch: the target of the MR at the head of the queue.
+	cycle.Target = e.batchTarget(ready[0])
+	var queue []*MRInfo
+	for _, mr := range ready {
+		if e.batchTarget(mr) == cycle.Target {
+			queue = append(queue, mr)
+		}
+	}
+	if cycle.Wait = cfg.Wait(queue, now); cycle.Wait > 0 {
+		return cycle, nil
+	}
+	cycle.Batch = e.AssembleBatch(queue, cfg)
+	return cycle, nil
+}
+
+// RunBatchCycle claims the next batch and lands it: the MRs are stacked
+// onto the target in queue order, gated once at the tip, and bisected if
+// the gates fail. Each MR is then settled the way the serial queue settles
+// it: merged MRs are closed with their source issues, culprits and
+// conflicts are bounced to their polecats.
+//
+// Culprits keep their claim, so they leave the ready queue until the
+// polecat resubmits or the claim goes stale; conflicted MRs are released
+// but blocked on their resolution task. Everything else that did not land
+// is released for the next cycle.
+func (e *Engineer) RunBatchCycle(ctx context.Context, workerID string, now time.Time) (*BatchCycle, error) {
+	cycle, err := e.NextBatch(now)
+	if err != nil || len(cycle.Batch) == 0 {
+		return cycle, err
+	}
+
+	batch := cycle.Batch
+	cycle.Batch = nil
+	for _, mr := range batch {
+		if err := e.ClaimMR(mr.ID, workerID); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Batch] Warning: could not claim %s, leaving it out: %v\n", mr.ID, err)
+			continue
+		}
+		cycle.Batch = append(cycle.Batch, mr)
+	}
+	if len(cycle.Batch) == 0 {
+		return cycle, nil
+	}
+
+	cycle.Result = e.ProcessBatch(ctx, cycle.Batch, cycle.Target, e.config.Batch)
+	e.settleBatch(cycle)
+	return cycle, nil
+}
+
+// batchTarget returns the branch an MR merges into.
+func (e *Engineer) batchTarget(mr *MRInfo) string {
+	if mr.Target != "" {
+		return mr.Target
+	}
+	return e.rig.DefaultBranch()
+}
+
+// settleBatch closes, bounces or releases each MR of a processed batch.
+func (e *Engineer) settleBatch(cycle *BatchCycle) {
+	result := cycle.Result
+	settled := make(map[string]bool, len(cycle.Batch))
+	for _, mr := range result.Merged {
+		e.HandleMRInfoSuccess(mr, ProcessResult{Success: true, MergeCommit: result.MergeCommit})
+		settled[mr.ID] = true
+	}
+	for _, mr := range result.Culprits {
+		e.HandleMRInfoFailure(mr, ProcessResult{
+			TestsFailed: true,
+			Error:       fmt.Sprintf("gates failed on the batch stack for %s; bisection isolated this branch", cycle.Target),
+		})
+		settled[mr.ID] = true
+	}
+	for _, mr := range result.Conflicts {
+		// Conflicts also collects MRs whose branch is gone, that touch files
+		// outside a monorepo rig's scope or that fail provenance checks. Only
+		// real conflicts get a resolution task (which blocks the MR); the
+		// others keep their claim.
+		if exists, err := e.git.BranchExists(mr.Branch); err != nil || !exists {
+			e.HandleMRInfoFailure(mr, ProcessResult{BranchNotFound: true, Error: "branch not found"})
+			settled[mr.ID] = true
+		} else if outside, err := e.checkMonorepoScope(cycle.Target, mr.Branch); err == nil && len(outside) > 0 {
+			e.HandleMRInfoFailure(mr, ProcessResult{ScopeViolated: true, Error: e.describeScopeViolation(outside)})
+			settled[mr.ID] = true
+		} else if problem := e.checkProvenance(cycle.Target, mr.Branch); problem != "" {
+			e.HandleMRInfoFailure(mr, ProcessResult{ProvenanceFailed: true, Error: problem})
+			settled[mr.ID] = true
+		} else {
+			e.HandleMRInfoFailure(mr, ProcessResult{Conflict: true, Error: fmt.Sprintf("could not be stacked onto %s", cycle.Target)})
+		}
+	}
+
+	if result.Error != nil {
+		_, _ = fmt.Fprintf(e.output, "[Batch] ✗ Batch failed: %v\n", result.Error)
+	}
+	for _, mr := range cycle.Batch {
+		if settled[mr.ID] {
+			continue
+		}
+		if err := e.ReleaseMR(mr.ID); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Batch] Warning: failed to release %s: %v\n", mr.ID, err)
+		}
+	}
+}
@@ -12,7 +12,9 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/changes"
 	gitpkg "github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/provenance"
 	"github.com/steveyegge/gastown/internal/rig"
 )
 
@@ -133,6 +135,47 @@ func TestDefaultBatchConfig(t *testing.T) {
 	}
 }
 
+func TestBatchConfig_Enabled(t *testing.T) {
+	var nilCfg *BatchConfig
+	if nilCfg.Enabled() {
+		t.Error("nil config should not enable batching")
+	}
+	if (&BatchConfig{MaxBatchSize: 1}).Enabled() {
+		t.Error("a batch of one is serial processing")
+	}
+	if !DefaultBatchConfig().Enabled() {
+		t.Error("default config should enable batching")
+	}
+}
+
+func TestBatchConfig_Wait(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cfg := &BatchConfig{MaxBatchSize: 3, BatchWaitTime: time.Minute}
+	mr := func(age time.Duration) *MRInfo {
+		return &MRInfo{ID: "gt-mr", CreatedAt: now.Add(-age)}
+	}
+
+	if got := cfg.Wait(nil, now); got != 0 {
+		t.Errorf("empty queue: Wait = %v, want 0", got)
+	}
+	if got := cfg.Wait([]*MRInfo{mr(10 * time.Second), mr(20 * time.Second)}, now); got != 40*time.Second {
+		t.Errorf("filling batch: Wait = %v, want 40s (counted from the oldest MR)", got)
+	}
+	if got := cfg.Wait([]*MRInfo{mr(2 * time.Minute)}, now); got != 0 {
+		t.Errorf("oldest MR waited long enough: Wait = %v, want 0", got)
+	}
+	if got := cfg.Wait([]*MRInfo{mr(0), mr(0), mr(0)}, now); got != 0 {
+		t.Errorf("full batch: Wait = %v, want 0", got)
+	}
+	if got := cfg.Wait([]*MRInfo{{ID: "gt-mr"}}, now); got != 0 {
+		t.Errorf("unknown age: Wait = %v, want 0", got)
+	}
+	cfg.BatchWaitTime = 0
+	if got := cfg.Wait([]*MRInfo{mr(0)}, now); got != 0 {
+		t.Errorf("no wait time: Wait = %v, want 0", got)
+	}
+}
+
 // --- AssembleBatch tests ---
 
 func TestAssembleBatch_EmptyQueue(t *testing.T) {
@@ -360,6 +403,73 @@ func TestBuildRebaseStack_MissingBranch(t *testing.T) {
 	}
 }
 
+func TestBuildRebaseStack_MonorepoScope(t *testing.T) {
+	workDir, g, cleanup := testGitRepo(t)
+	defer cleanup()
+
+	for _, dir := range []string{"api", "web"} {
+		if err := os.MkdirAll(filepath.Join(workDir, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	createFeatureBranch(t, workDir, "feature-api", "api/a.go", "package api\n")
+	createFeatureBranch(t, workDir, "feature-web", "web/w.ts", "export {}\n")
+
+	e := newTestEngineer(t, workDir, g)
+	e.monorepo = &rig.MonorepoConfig{Host: "platform", Subdir: "api"}
+	batch := []*MRInfo{
+		makeMR("mr-web", "feature-web", "main"),
+		makeMR("mr-api", "feature-api", "main"),
+	}
+
+	stacked, conflicts, err := e.BuildRebaseStack(context.Background(), batch, "main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stacked) != 1 || stacked[0].ID != "mr-api" {
+		t.Errorf("expected only mr-api stacked, got %v", stackedIDs(stacked))
+	}
+	if len(conflicts) != 1 || conflicts[0].ID != "mr-web" {
+		t.Errorf("expected mr-web removed, got %v", stackedIDs(conflicts))
+	}
+	if out := e.output.(*bytes.Buffer).String(); !strings.Contains(out, "changes outside api: web/w.ts (rig platform)") {
+		t.Errorf("output missing scope violation:\n%s", out)
+	}
+}
+
+func TestBuildRebaseStack_Provenance(t *testing.T) {
+	workDir, g, cleanup := testGitRepo(t)
+	defer cleanup()
+
+	createFeatureBranch(t, workDir, "feature-plain", "plain.txt", "plain\n")
+	run(t, workDir, "git", "checkout", "-b", "feature-stamped", "main")
+	writeFile(t, workDir, "stamped.txt", "stamped\n")
+	run(t, workDir, "git", "add", ".")
+	run(t, workDir, "git", "commit", "-m", "feat: add stamped.txt\n\nGt-Agent: gastown/polecats/nux\nGt-Version: 0.12.0")
+	run(t, workDir, "git", "checkout", "main")
+
+	e := newTestEngineer(t, workDir, g)
+	e.provenance = &provenance.Config{Trailers: true, Verify: true}
+	batch := []*MRInfo{
+		makeMR("mr-plain", "feature-plain", "main"),
+		makeMR("mr-stamped", "feature-stamped", "main"),
+	}
+
+	stacked, conflicts, err := e.BuildRebaseStack(context.Background(), batch, "main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stacked) != 1 || stacked[0].ID != "mr-stamped" {
+		t.Errorf("expected only mr-stamped stacked, got %v", stackedIDs(stacked))
+	}
+	if len(conflicts) != 1 || conflicts[0].ID != "mr-plain" {
+		t.Errorf("expected mr-plain removed, got %v", stackedIDs(conflicts))
+	}
+	if out := e.output.(*bytes.Buffer).String(); !strings.Contains(out, "missing Gt-Agent, Gt-Version trailer") {
+		t.Errorf("output missing provenance failure:\n%s", out)
+	}
+}
+
 // --- ProcessBatch tests ---
 
 func TestProcessBatch_EmptyBatch(t *testing.T) {
@@ -741,6 +851,67 @@ func TestProcessBatch_PushesAndLands(t *testing.T) {
 	}
 }
 
+func TestProcessBatch_PushesToMirrors(t *testing.T) {
+	workDir, g, cleanup := testGitRepo(t)
+	defer cleanup()
+
+	mirrorDir := filepath.Join(filepath.Dir(workDir), "mirror.git")
+	run(t, filepath.Dir(workDir), "git", "init", "--bare", "--initial-branch=main", mirrorDir)
+	run(t, workDir, "git", "remote", "add", "backup", mirrorDir)
+	createFeatureBranch(t, workDir, "feature-a", "a.txt", "hello a\n")
+	createFeatureBranch(t, workDir, "feature-b", "b.txt", "hello b\n")
+
+	e := newTestEngineer(t, workDir, g)
+	e.rig.Remotes = rig.Remotes{{Name: "backup", URL: mirrorDir, Push: rig.PushMirror}}
+	batch := []*MRInfo{
+		makeMR("mr-a", "feature-a", "main"),
+		makeMR("mr-b", "feature-b", "main"),
+	}
+
+	result := e.ProcessBatch(context.Background(), batch, "main", DefaultBatchConfig())
+	if result.Error != nil || len(result.Merged) != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+	if got := run(t, workDir, "git", "--git-dir", mirrorDir, "rev-parse", "main"); got != result.MergeCommit {
+		t.Errorf("mirror main = %s, want the merge commit %s", got, result.MergeCommit)
+	}
+}
+
+func TestRecordChangeSummary_PerBranchInBatch(t *testing.T) {
+	workDir, g, cleanup := testGitRepo(t)
+	defer cleanup()
+
+	createFeatureBranch(t, workDir, "feature-a", "a.txt", "hello a\n")
+	createFeatureBranch(t, workDir, "feature-b", "b.txt", "hello b\n")
+
+	e := newTestEngineer(t, workDir, g)
+	batch := []*MRInfo{
+		makeMR("mr-a", "feature-a", "main"),
+		makeMR("mr-b", "feature-b", "main"),
+	}
+	batch[0].SourceIssue = "gt-a"
+	batch[1].SourceIssue = "gt-b"
+
+	result := e.ProcessBatch(context.Background(), batch, "main", DefaultBatchConfig())
+	if result.Error != nil || len(result.Merged) != 2 {
+		t.Fatalf("result = %+v", result)
+	}
+	for _, mr := range result.Merged {
+		e.recordChangeSummary(mr, ProcessResult{Success: true, MergeCommit: result.MergeCommit})
+	}
+
+	// Both MRs share the batch's merge commit; each summary covers only its branch.
+	for bead, file := range map[string]string{"gt-a": "a.txt", "gt-b": "b.txt"} {
+		s, err := changes.Load(workDir, bead)
+		if err != nil || s == nil {
+			t.Fatalf("Load(%s) = %v, %v", bead, s, err)
+		}
+		if len(s.Files) != 1 || s.Files[0].Path != file || s.MergeCommit != result.MergeCommit {
+			t.Errorf("%s summary = %+v", bead, s)
+		}
+	}
+}
+
 func TestProcessBatch_BisectAndMergeGood(t *testing.T) {
 	workDir, g, cleanup := testGitRepo(t)
 	defer cleanup()
@@ -18,10 +18,18 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/bench"
+	"github.com/steveyegge/gastown/internal/changes"
+	"github.com/steveyegge/gastown/internal/codeindex"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deppolicy"
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/freeze"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/ownership"
+	"github.com/steveyegge/gastown/internal/provenance"
 	"github.com/steveyegge/gastown/internal/rig"
 )
 
@@ -127,6 +135,14 @@ type MergeQueueConfig struct {
 	// Batch holds configuration for the batch-then-bisect merge queue.
 	// When nil or MaxBatchSize <= 1, batching is disabled and MRs process sequentially.
 	Batch *BatchConfig `json:"batch,omitempty"`
+
+	// Bench runs benchmarks on each merged tree before push and bounces
+	// branches with significant regressions. nil = no benchmarks.
+	Bench *bench.Config `json:"bench,omitempty"`
+
+	// DependencyPolicy checks dependencies added by each merge against
+	// license and package allow/deny lists. nil = no policy.
+	DependencyPolicy *deppolicy.Config `json:"dependency_policy,omitempty"`
 }
 
 // DefaultMergeQueueConfig returns sensible defaults for merge queue configuration.
@@ -216,6 +232,11 @@ type Engineer struct {
 	mergeSlotRelease      func(holder string) error
 	mergeSlotMaxRetries   int           // Max retries for slot acquisition (0 = no retry)
 	mergeSlotRetryBackoff time.Duration // Initial backoff between retries
+	policyEscalated       map[string]string // MR ID → violation summary already escalated
+	monorepo              *rig.MonorepoConfig // Subdirectory scope for monorepo rigs; nil = whole repo
+	linkedConvoyReady     func(convoyID string) (bool, []string, error) // Merge gate for gt:linked MRs
+	provenance            *provenance.Config // Town commit provenance settings; verified before merging
+	ownership             *ownership.Config  // Rig ownership settings; owners review MRs when required
 }
 
 // NewEngineer creates a new Engineer for the given rig.
@@ -250,7 +271,52 @@ func NewEngineer(r *rig.Rig) *Engineer {
 		},
 		mergeSlotMaxRetries:   10,
 		mergeSlotRetryBackoff: 500 * time.Millisecond,
+		monorepo:              r.Monorepo,
+		linkedConvoyReady: func(convoyID string) (bool, []string, error) {
+			return checkLinkedConvoyReady(filepath.Dir(r.Path), convoyID)
+		},
+		provenance: loadProvenanceSettings(filepath.Dir(r.Path)),
+		ownership:  loadOwnershipSettings(r.Path),
+	}
+}
+
+// loadOwnershipSettings returns the rig's ownership settings, or nil.
+func loadOwnershipSettings(rigPath string) *ownership.Config {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		return nil
+	}
+	return settings.Ownership
+}
+
+// loadProvenanceSettings returns the town's provenance settings as written.
+// Invalid settings are kept so verification fails closed instead of being
+// silently skipped.
+func loadProvenanceSettings(townRoot string) *provenance.Config {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil
 	}
+	return settings.Provenance
+}
+
+// checkLinkedConvoyReady asks gt whether every member of a linked convoy
+// has submitted, returning the members still being waited on.
+func checkLinkedConvoyReady(townRoot, convoyID string) (bool, []string, error) {
+	cmd := exec.Command("gt", "convoy", "linked", convoyID, "--json")
+	cmd.Dir = townRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return false, nil, fmt.Errorf("gt convoy linked %s: %w", convoyID, err)
+	}
+	var status struct {
+		Ready   bool     `json:"ready"`
+		Waiting []string `json:"waiting"`
+	}
+	if err := json.Unmarshal(out, &status); err != nil {
+		return false, nil, fmt.Errorf("parsing gt convoy linked output: %w", err)
+	}
+	return status.Ready, status.Waiting, nil
 }
 
 // SetOutput sets the output writer for user-facing messages.
@@ -273,11 +339,15 @@ func (e *Engineer) LoadConfig() error {
 
 	// Parse config file to extract merge_queue section
 	var rawConfig struct {
-		MergeQueue json.RawMessage `json:"merge_queue"`
+		MergeQueue json.RawMessage     `json:"merge_queue"`
+		Monorepo   *rig.MonorepoConfig `json:"monorepo"`
 	}
 	if err := json.Unmarshal(data, &rawConfig); err != nil {
 		return fmt.Errorf("parsing config: %w", err)
 	}
+	if rawConfig.Monorepo != nil {
+		e.monorepo = rawConfig.Monorepo
+	}
 
 	if rawConfig.MergeQueue == nil {
 		// No merge_queue section, use defaults
@@ -298,6 +368,9 @@ func (e *Engineer) LoadConfig() error {
 		StaleClaimTimeout    *string                    `json:"stale_claim_timeout"`
 		Gates                map[string]*gateConfigRaw  `json:"gates"`
 		GatesParallel        *bool                      `json:"gates_parallel"`
+		Bench                *benchConfigRaw            `json:"bench"`
+		Batch                *batchConfigRaw            `json:"batch"`
+		DependencyPolicy     *deppolicy.Config          `json:"dependency_policy"`
 	}
 
 	if err := json.Unmarshal(rawConfig.MergeQueue, &mqRaw); err != nil {
@@ -366,6 +439,60 @@ func (e *Engineer) LoadConfig() error {
 		e.config.GatesParallel = *mqRaw.GatesParallel
 	}
 
+	if mqRaw.Bench != nil {
+		bc := &bench.Config{
+			Cmd:        mqRaw.Bench.Cmd,
+			Threshold:  mqRaw.Bench.Threshold,
+			Window:     mqRaw.Bench.Window,
+			MinSamples: mqRaw.Bench.MinSamples,
+		}
+		if mqRaw.Bench.Timeout != "" {
+			dur, err := time.ParseDuration(mqRaw.Bench.Timeout)
+			if err != nil {
+				return fmt.Errorf("invalid bench timeout %q: %w", mqRaw.Bench.Timeout, err)
+			}
+			if dur <= 0 {
+				return fmt.Errorf("bench timeout must be positive, got %v", dur)
+			}
+			bc.Timeout = dur
+		}
+		if bc.Threshold < 0 {
+			return fmt.Errorf("bench threshold must not be negative, got %v", bc.Threshold)
+		}
+		e.config.Bench = bc
+	}
+
+	if mqRaw.Batch != nil {
+		bc := DefaultBatchConfig()
+		if mqRaw.Batch.MaxBatchSize != nil {
+			bc.MaxBatchSize = *mqRaw.Batch.MaxBatchSize
+		}
+		if mqRaw.Batch.BatchWaitTime != nil {
+			dur, err := time.ParseDuration(*mqRaw.Batch.BatchWaitTime)
+			if err != nil {
+				return fmt.Errorf("invalid batch_wait_time %q: %w", *mqRaw.Batch.BatchWaitTime, err)
+			}
+			if dur < 0 {
+				return fmt.Errorf("batch_wait_time must not be negative, got %v", dur)
+			}
+			bc.BatchWaitTime = dur
+		}
+		if mqRaw.Batch.RetryBatchOnFlaky != nil {
+			bc.RetryBatchOnFlaky = *mqRaw.Batch.RetryBatchOnFlaky
+		}
+		if bc.MaxBatchSize < 0 {
+			return fmt.Errorf("max_batch_size must not be negative, got %d", bc.MaxBatchSize)
+		}
+		e.config.Batch = bc
+	}
+
+	if mqRaw.DependencyPolicy != nil {
+		if err := mqRaw.DependencyPolicy.Validate(); err != nil {
+			return fmt.Errorf("invalid dependency_policy: %w", err)
+		}
+		e.config.DependencyPolicy = mqRaw.DependencyPolicy
+	}
+
 	return nil
 }
 
@@ -376,6 +503,23 @@ type gateConfigRaw struct {
 	Timeout string `json:"timeout"`
 }
 
+// batchConfigRaw is the JSON-friendly representation of the batch config
+// with batch_wait_time as a string duration.
+type batchConfigRaw struct {
+	MaxBatchSize      *int    `json:"max_batch_size"`
+	BatchWaitTime     *string `json:"batch_wait_time"`
+	RetryBatchOnFlaky *bool   `json:"retry_batch_on_flaky"`
+}
+
+// benchConfigRaw is the JSON-friendly representation of the bench config.
+type benchConfigRaw struct {
+	Cmd        string  `json:"cmd"`
+	Timeout    string  `json:"timeout"`
+	Threshold  float64 `json:"threshold"`
+	Window     int     `json:"window"`
+	MinSamples int     `json:"min_samples"`
+}
+
 // Config returns the current merge queue configuration.
 func (e *Engineer) Config() *MergeQueueConfig {
 	return e.config
@@ -383,13 +527,17 @@ func (e *Engineer) Config() *MergeQueueConfig {
 
 // ProcessResult contains the result of processing a merge request.
 type ProcessResult struct {
-	Success        bool
-	MergeCommit    string
-	Error          string
-	Conflict       bool
-	TestsFailed    bool
-	SlotTimeout    bool // Merge slot contention timeout (distinct from build/test failure)
-	BranchNotFound bool // Source branch no longer exists (e.g. cleaned up after cherry-pick)
+	Success          bool
+	MergeCommit      string
+	Error            string
+	Conflict         bool
+	TestsFailed      bool
+	SlotTimeout      bool // Merge slot contention timeout (distinct from build/test failure)
+	BranchNotFound   bool // Source branch no longer exists (e.g. cleaned up after cherry-pick)
+	BenchRegressed   bool // Merged tree is significantly slower than recent merges
+	PolicyViolated   bool // Branch adds a dependency the rig's policy denies
+	ScopeViolated    bool // Monorepo rig's branch changes files outside its subdirectory
+	ProvenanceFailed bool // A branch commit lacks provenance trailers or a good signature
 }
 
 // doMerge performs the actual git merge operation.
@@ -444,6 +592,28 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
+	// Step 3.2: Monorepo rigs may only change their own subdirectory (and
+	// shared paths). Changes to a sibling subproject go through that rig's queue.
+	if outside, err := e.checkMonorepoScope(target, branch); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not check monorepo scope: %v\n", err)
+	} else if len(outside) > 0 {
+		return ProcessResult{
+			Success:       false,
+			ScopeViolated: true,
+			Error:         e.describeScopeViolation(outside),
+		}
+	}
+
+	// Step 3.3: With provenance verification on, every commit the branch
+	// brings in must carry the agent trailers and a good signature.
+	if problem := e.checkProvenance(target, branch); problem != "" {
+		return ProcessResult{
+			Success:          false,
+			ProvenanceFailed: true,
+			Error:            problem,
+		}
+	}
+
 	// Step 3.5: Push submodule commits if the branch changes submodule pointers.
 	// The refinery owns all remote pushes — submodule commits must land before the
 	// parent pointer is merged, otherwise main gets dangling submodule references.
@@ -544,6 +714,39 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 		}
 	}
 
+	// Step 6.4: Check dependencies the merge adds against the rig's policy.
+	// Runs even for pre-verified MRs: the policy is the refinery's, not the polecat's.
+	if e.config.DependencyPolicy.IsEnabled() {
+		if report := e.checkDependencyPolicy("origin/" + target); report != nil && len(report.Violations()) > 0 {
+			if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after dependency policy violation: %v\n", target, resetErr)
+			}
+			return ProcessResult{
+				Success:        false,
+				PolicyViolated: true,
+				Error:          report.Summary(),
+			}
+		}
+	}
+
+	// Step 6.5: Benchmark the merged tree against recent merges.
+	// Runs even for pre-verified MRs: polecats don't run benchmarks.
+	var benchResults bench.Results
+	if e.config.Bench.IsEnabled() {
+		var report *bench.Report
+		benchResults, report = e.runBench(ctx)
+		if report != nil && len(report.Regressions()) > 0 {
+			if resetErr := e.git.ResetHard("origin/" + target); resetErr != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to reset %s after benchmark regression: %v\n", target, resetErr)
+			}
+			return ProcessResult{
+				Success:        false,
+				BenchRegressed: true,
+				Error:          report.Summary(),
+			}
+		}
+	}
+
 	// Step 7: Acquire merge slot before push to serialize writes to the default branch.
 	// Only serialize pushes to the rig's default branch (typically main).
 	// Integration-branch and feature-branch pushes don't need serialization.
@@ -590,6 +793,14 @@ func (e *Engineer) doMerge(ctx context.Context, branch, target, sourceIssue stri
 			Error:   fmt.Sprintf("failed to push to origin: %v", err),
 		}
 	}
+	e.pushMirrors(target)
+
+	if benchResults != nil {
+		entry := bench.Entry{MergeCommit: mergeCommit, Branch: branch, Issue: sourceIssue, Results: benchResults}
+		if err := bench.Append(e.rig.Path, entry); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to record benchmark results: %v\n", err)
+		}
+	}
 
 	_, _ = fmt.Fprintf(e.output, "[Engineer] Successfully merged: %s\n", mergeCommit[:8])
 	return ProcessResult{
@@ -838,6 +1049,202 @@ func (e *Engineer) runGates(ctx context.Context) ProcessResult {
 	return ProcessResult{Success: true}
 }
 
+// checkMonorepoScope returns the files branch changes outside the rig's
+// monorepo subdirectory and shared paths. It returns nil for rigs that own
+// their whole repository.
+func (e *Engineer) checkMonorepoScope(target, branch string) ([]string, error) {
+	if e.monorepo == nil {
+		return nil, nil
+	}
+	files, err := e.git.DiffNameOnly(target, branch)
+	if err != nil {
+		return nil, err
+	}
+	return e.monorepo.OutOfScope(files), nil
+}
+
+// checkProvenance returns why branch fails provenance verification, or ""
+// when it passes or verification is off. Errors fail the check: an
+// unverifiable branch is not merged.
+func (e *Engineer) checkProvenance(target, branch string) string {
+	if !e.provenance.Verifies() {
+		return ""
+	}
+	if err := e.provenance.Validate(); err != nil {
+		return fmt.Sprintf("invalid provenance settings: %v", err)
+	}
+	commits, err := e.git.CommitLog(target, branch, e.provenance.VerifyGitConfig()...)
+	if err != nil {
+		return fmt.Sprintf("could not verify provenance: %v", err)
+	}
+	if problems := e.provenance.Check(commits); len(problems) > 0 {
+		return provenance.Describe(problems)
+	}
+	return ""
+}
+
+// reviewRequestedLabel marks an MR whose owners have been asked to review it.
+const reviewRequestedLabel = "gt:review-requested"
+
+// PendingOwnerReview returns the owning agents that have yet to approve an
+// MR, or nil when the rig doesn't require owner review.
+func (e *Engineer) PendingOwnerReview(issue *beads.Issue) ([]string, error) {
+	if !e.ownership.RequiresReview() {
+		return nil, nil
+	}
+	fields := beads.ParseMRFields(issue)
+	if fields == nil {
+		return nil, fmt.Errorf("%s has no merge request fields", issue.ID)
+	}
+	return e.pendingOwnerReview(issue, fields)
+}
+
+// pendingOwnerReview returns the owning agents that have yet to approve an
+// MR.
+func (e *Engineer) pendingOwnerReview(issue *beads.Issue, fields *beads.MRFields) ([]string, error) {
+	target := fields.Target
+	if target == "" {
+		target = e.rig.DefaultBranch()
+	}
+	reviewers, err := e.ownerReviewers(target, fields.Branch, mrAuthor(e.rig.Name, fields.Worker))
+	if err != nil {
+		return nil, err
+	}
+	return ownership.Pending(reviewers, issue.Labels), nil
+}
+
+// ownerReviewers returns the agents, other than author, that own files
+// branch changes. Ownership is read as of target, so a branch can't edit
+// CODEOWNERS to skip its own review.
+func (e *Engineer) ownerReviewers(target, branch, author string) ([]string, error) {
+	owners, err := ownership.Load(e.rig.Path, func(path string) ([]byte, error) {
+		return e.git.ShowFile(target, path)
+	})
+	if err != nil || owners == nil {
+		return nil, err
+	}
+	files, err := e.git.DiffNameOnly(target, branch)
+	if err != nil {
+		return nil, err
+	}
+	return owners.Reviewers(e.rig.Name, e.ownership, files, author), nil
+}
+
+// mrAuthor returns the address of the agent that submitted an MR.
+func mrAuthor(rigName, worker string) string {
+	switch {
+	case worker == "":
+		return ""
+	case strings.Contains(worker, "/"):
+		if strings.Count(worker, "/") == 1 {
+			return rigName + "/" + worker // polecats/Nux, crew/max
+		}
+		return worker
+	}
+	return rigName + "/polecats/" + worker
+}
+
+// requestOwnerReview mails the owners an MR waits on. It asks once per MR;
+// the gt:review-requested label records that it did.
+func (e *Engineer) requestOwnerReview(issue *beads.Issue, fields *beads.MRFields, pending []string) {
+	if beads.HasLabel(issue, reviewRequestedLabel) || e.router == nil {
+		return
+	}
+	subject := fmt.Sprintf("Review requested: %s", issue.Title)
+	body := fmt.Sprintf(`%s changes paths you own and waits on your review before it merges.
+
+Branch: %s
+Target: %s
+Issue:  %s
+
+Approve: gt mq approve %s %s
+Reject:  gt mq reject %s %s --reason "..."`,
+		issue.ID, fields.Branch, fields.Target, fields.SourceIssue,
+		e.rig.Name, issue.ID, e.rig.Name, issue.ID)
+	from := e.rig.Name + "/refinery"
+	for _, owner := range pending {
+		if err := e.router.Send(mail.NewMessage(from, owner, subject, body)); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to request review of %s from %s: %v\n", issue.ID, owner, err)
+		}
+	}
+	if err := e.beads.Update(issue.ID, beads.UpdateOptions{AddLabels: []string{reviewRequestedLabel}}); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to label %s: %v\n", issue.ID, err)
+	}
+}
+
+// describeScopeViolation lists out-of-scope files with the rig that owns
+// each, so the polecat knows which queue the change belongs in.
+func (e *Engineer) describeScopeViolation(files []string) string {
+	townRoot := filepath.Dir(e.rig.Path)
+	var rigNames []string
+	if entries, err := os.ReadDir(townRoot); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				rigNames = append(rigNames, entry.Name())
+			}
+		}
+	}
+	parts := make([]string, 0, len(files))
+	for _, f := range files {
+		owner := rig.SubprojectOwner(townRoot, rigNames, e.monorepo.Host, f)
+		if owner == "" {
+			owner = e.monorepo.Host
+		}
+		parts = append(parts, fmt.Sprintf("%s (rig %s)", f, owner))
+	}
+	return fmt.Sprintf("changes outside %s: %s", e.monorepo.Subdir, strings.Join(parts, ", "))
+}
+
+// checkDependencyPolicy scans the merged tree's manifest changes since base
+// and applies the rig's dependency policy. Scan failures are reported but
+// never block the merge; they return nil.
+func (e *Engineer) checkDependencyPolicy(base string) *deppolicy.Report {
+	deps, err := deppolicy.Scan(e.workDir, base)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: dependency scan failed: %v (merging without policy check)\n", err)
+		return nil
+	}
+	if len(deps) == 0 {
+		return nil
+	}
+	resolver := &deppolicy.Resolver{Dir: e.workDir}
+	report := deppolicy.Check(e.config.DependencyPolicy, deps, resolver.Resolve)
+	for _, f := range report.Warnings() {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %s: license unknown (add it to dependency_policy.licenses)\n", f.Dependency)
+	}
+	if v := report.Violations(); len(v) > 0 {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ %s\n", report.Summary())
+	} else {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Dependency policy passed (%d changed dependencies)\n", len(deps))
+	}
+	return report
+}
+
+// runBench runs the benchmark command on the merged tree and compares it with
+// the rig's history. Benchmark infrastructure failures are reported but never
+// block the merge; they return nil results so nothing is recorded.
+func (e *Engineer) runBench(ctx context.Context) (bench.Results, *bench.Report) {
+	cfg := e.config.Bench
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Running benchmarks: %s\n", cfg.Cmd)
+	results, err := bench.Run(ctx, cfg, e.workDir)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v (merging without benchmark check)\n", err)
+		return nil, nil
+	}
+	history, err := bench.History(e.rig.Path)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: %v\n", err)
+	}
+	report := bench.Compare(cfg, results, history)
+	_, _ = fmt.Fprint(e.output, report.Table())
+	if len(report.Regressions()) > 0 {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] ✗ %s\n", report.Summary())
+	} else {
+		_, _ = fmt.Fprintln(e.output, "[Engineer] Benchmarks within baseline")
+	}
+	return results, report
+}
+
 // syncCrewWorkspaces pulls latest changes to all crew workspaces.
 // This ensures crew members have access to newly merged code without manual sync.
 func (e *Engineer) syncCrewWorkspaces() {
@@ -902,6 +1309,17 @@ func (e *Engineer) ProcessMRInfo(ctx context.Context, mr *MRInfo) ProcessResult
 	return e.doMerge(ctx, mr.Branch, mr.Target, mr.SourceIssue, skipGates)
 }
 
+// pushMirrors copies a target branch just pushed to origin to the rig's
+// mirror remotes. Mirrors are best-effort: a failed copy doesn't fail the
+// merge, and the next merge catches the mirror up.
+func (e *Engineer) pushMirrors(target string) {
+	for _, remote := range e.rig.Remotes.Mirrors() {
+		if err := e.git.Push(remote, target, false); err != nil {
+			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to mirror %s to %s: %v\n", target, remote, err)
+		}
+	}
+}
+
 // HandleMRInfoSuccess handles a successful merge from MRInfo.
 func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 	// Release merge slot if this was a conflict resolution
@@ -968,6 +1386,12 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		}
 	}
 
+	// 1.6. Record what the bead changed while the branch still exists
+	e.recordChangeSummary(mr, result)
+
+	// 1.7. Bring the rig's code index up to date with the merge
+	e.refreshCodeIndex()
+
 	// 2. Delete source branch (local and remote).
 	// Polecat branches (polecat/*) are always cleaned up — they are ephemeral
 	// work branches that should never persist after merge. Other branches
@@ -983,7 +1407,7 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 		// to contributor forks with open upstream PRs; deleting them from origin
 		// causes GitHub to auto-close those PRs via head_ref_delete. (GH#2669)
 		if isPolecat {
-			if err := e.git.DeleteRemoteBranch("origin", mr.Branch); err != nil {
+			if err := e.git.DeleteRemoteBranch(e.rig.Remotes.BranchRemote(), mr.Branch); err != nil {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to delete remote branch %s: %v\n", mr.Branch, err)
 			} else {
 				_, _ = fmt.Fprintf(e.output, "[Engineer] Deleted remote branch: %s\n", mr.Branch)
@@ -1010,6 +1434,79 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
 }
 
+// refreshCodeIndex re-indexes the files the merge changed when the rig keeps
+// a code index. Failures only warn: a stale index is still usable.
+func (e *Engineer) refreshCodeIndex() {
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(e.rig.Path))
+	if err != nil || !settings.CodeIndex.IsEnabled() {
+		return
+	}
+	cfg := settings.CodeIndex
+	idx, err := codeindex.Load(e.rig.Path)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: code index: %v\n", err)
+		return
+	}
+	stats, err := codeindex.Refresh(context.Background(), idx, e.workDir, cfg, cfg.Embedder(), false)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: code index: %v\n", err)
+		return
+	}
+	idx.Rig = e.rig.Name
+	if err := idx.Save(e.rig.Path); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: code index: %v\n", err)
+		return
+	}
+	if stats.EmbedErr != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: code index embeddings: %v\n", stats.EmbedErr)
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Code index: %s\n", stats)
+}
+
+// recordChangeSummary saves the merged branch's change summary and notes
+// its headline on the source issue. Failures only warn: the merge has landed.
+func (e *Engineer) recordChangeSummary(mr *MRInfo, result ProcessResult) {
+	if mr.SourceIssue == "" || mr.Branch == "" {
+		return
+	}
+	// The branch's own changes, from where it forked off the target. Works
+	// for single and batch merges alike (a batch shares one merge commit).
+	head := mr.Branch
+	if result.MergeCommit != "" {
+		head = result.MergeCommit
+	}
+	base, err := e.git.MergeBase(head, mr.Branch)
+	if tip, tipErr := e.git.Rev(mr.Branch); err == nil && tipErr == nil && base == tip {
+		// The branch landed as-is: a single-commit branch whose squash
+		// reproduced the same commit. Measure it from its parent.
+		base, err = e.git.Rev(mr.Branch + "^")
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: change summary for %s: %v\n", mr.SourceIssue, err)
+		return
+	}
+	summary, err := changes.Generate(e.workDir, base, mr.Branch)
+	if err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: change summary for %s: %v\n", mr.SourceIssue, err)
+		return
+	}
+	summary.Bead = mr.SourceIssue
+	summary.MR = mr.ID
+	summary.Rig = e.rig.Name
+	summary.Branch = mr.Branch
+	summary.Target = mr.Target
+	summary.MergeCommit = result.MergeCommit
+	if err := changes.Save(e.rig.Path, summary); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: saving change summary for %s: %v\n", mr.SourceIssue, err)
+		return
+	}
+	comment := fmt.Sprintf("Changes: %s (gt changes %s)", summary.Headline(), mr.SourceIssue)
+	if _, err := e.beads.Run("comments", "add", mr.SourceIssue, comment); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to note change summary on %s: %v\n", mr.SourceIssue, err)
+	}
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Change summary for %s: %s\n", mr.SourceIssue, summary.Headline())
+}
+
 // HandleMRInfoFailure handles a failed merge from MRInfo.
 // For conflicts, creates a resolution task and blocks the MR until resolved.
 // For slot timeouts, the MR stays in queue for automatic retry without notifying polecats.
@@ -1040,6 +1537,14 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		failureType = "conflict"
 	} else if result.TestsFailed {
 		failureType = "tests"
+	} else if result.BenchRegressed {
+		failureType = "bench"
+	} else if result.PolicyViolated {
+		failureType = "dependency-policy"
+	} else if result.ScopeViolated {
+		failureType = "scope"
+	} else if result.ProvenanceFailed {
+		failureType = "provenance"
 	}
 	polecatName := strings.TrimPrefix(mr.Worker, "polecats/")
 	nudgeTarget := fmt.Sprintf("%s/%s", e.rig.Name, polecatName)
@@ -1053,6 +1558,12 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Nudged %s about merge failure (%s)\n", polecatName, failureType)
 	}
 
+	// Dependency policy violations need a human decision (remove the dep or
+	// amend the policy), so escalate once per distinct violation as well.
+	if result.PolicyViolated {
+		e.escalatePolicyViolation(mr, result)
+	}
+
 	// If this was a conflict, create a conflict-resolution task for dispatch
 	// and block the MR until the task is resolved (non-blocking delegation)
 	if result.Conflict {
@@ -1079,6 +1590,33 @@ func (e *Engineer) HandleMRInfoFailure(mr *MRInfo, result ProcessResult) {
 	}
 }
 
+// escalatePolicyViolation raises a high-severity escalation for a dependency
+// policy violation. Retries of the same MR with the same violation are not
+// re-escalated.
+func (e *Engineer) escalatePolicyViolation(mr *MRInfo, result ProcessResult) {
+	if e.policyEscalated == nil {
+		e.policyEscalated = make(map[string]string)
+	}
+	if e.policyEscalated[mr.ID] == result.Error {
+		return
+	}
+	related := mr.SourceIssue
+	if related == "" {
+		related = mr.ID
+	}
+	msg := fmt.Sprintf("Dependency policy blocked %s (%s)", mr.Branch, mr.ID)
+	reason := fmt.Sprintf("%s\n\nThe branch stays out of %s until the dependency is removed or %s/config.json merge_queue.dependency_policy is amended.",
+		result.Error, mr.Target, e.rig.Name)
+	cmd := exec.Command("gt", "escalate", "-s", "high", "--source", "refinery:dependency-policy", "--related", related, "--reason", reason, msg)
+	cmd.Dir = e.workDir
+	if err := cmd.Run(); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to escalate dependency policy violation: %v\n", err)
+		return
+	}
+	e.policyEscalated[mr.ID] = result.Error
+	_, _ = fmt.Fprintf(e.output, "[Engineer] Escalated dependency policy violation for %s\n", mr.ID)
+}
+
 // createConflictResolutionTaskForMR creates a dispatchable task for resolving merge conflicts.
 // This task will be picked up by bd ready and can be slung to a fresh polecat (spawned on demand).
 // Returns the created task's ID for blocking the MR until resolution.
@@ -1289,6 +1827,16 @@ func (e *Engineer) firstOpenBlocker(issue *beads.Issue) string {
 // bd ready filters out ephemeral issues (see gt-t5t6y). This matches the
 // pattern used by ListBlockedMRs and ListAllOpenMRs.
 func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
+	// Change freeze (gt freeze): hold the whole queue until the rig thaws.
+	if f := freeze.Check(filepath.Dir(e.rig.Path), e.rig.Name, time.Now()); f != nil {
+		reason := ""
+		if f.Reason != "" {
+			reason = ": " + f.Reason
+		}
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Rig %s is frozen%s; holding merge queue until gt thaw\n", e.rig.Name, reason)
+		return nil, nil
+	}
+
 	// Query beads for all open merge-request issues.
 	// Cannot use ReadyWithType here because bd ready excludes ephemeral beads,
 	// and MRs are ephemeral by design. Use List + manual blocker check instead.
@@ -1303,6 +1851,7 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 
 	// Convert beads issues to MRInfo
 	var mrs []*MRInfo
+	linkedReady := make(map[string]bool) // convoy ID → ready, checked once per call
 	for _, issue := range issues {
 		// Skip closed MRs (workaround for bd list not respecting --status filter)
 		if issue.Status != "open" {
@@ -1327,6 +1876,44 @@ func (e *Engineer) ListReadyMRs() ([]*MRInfo, error) {
 			continue // Skip issues without MR fields
 		}
 
+		// Linked convoy MRs wait until every rig's part has an MR, so the
+		// multi-repo change lands together. Fails closed: if readiness can't
+		// be determined, hold the MR and retry next poll.
+		if beads.HasLabel(issue, "gt:linked") && fields.ConvoyID != "" && e.linkedConvoyReady != nil {
+			ready, checked := linkedReady[fields.ConvoyID]
+			if !checked {
+				var waiting []string
+				var err error
+				ready, waiting, err = e.linkedConvoyReady(fields.ConvoyID)
+				switch {
+				case err != nil:
+					_, _ = fmt.Fprintf(e.output, "[Engineer] Holding MR %s: linked convoy %s readiness unknown: %v\n", issue.ID, fields.ConvoyID, err)
+				case !ready:
+					_, _ = fmt.Fprintf(e.output, "[Engineer] Holding MR %s: linked convoy %s waiting on %s\n", issue.ID, fields.ConvoyID, strings.Join(waiting, ", "))
+				}
+				linkedReady[fields.ConvoyID] = ready
+			}
+			if !ready {
+				continue
+			}
+		}
+
+		// Owner review: with require_review on, an MR that changes owned
+		// paths waits until every owning agent other than its author has
+		// approved. Fails closed like the linked convoy gate.
+		if e.ownership.RequiresReview() {
+			pending, err := e.pendingOwnerReview(issue, fields)
+			if err != nil {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Holding MR %s: owner review unknown: %v\n", issue.ID, err)
+				continue
+			}
+			if len(pending) > 0 {
+				_, _ = fmt.Fprintf(e.output, "[Engineer] Holding MR %s: waiting on review from %s\n", issue.ID, strings.Join(pending, ", "))
+				e.requestOwnerReview(issue, fields, pending)
+				continue
+			}
+		}
+
 		// Skip if already assigned, unless claim is stale (allows re-claim after crash).
 		// NOTE: Only one refinery runs per rig (enforced by ErrAlreadyRunning in
 		// manager.go), so concurrent re-claim race conditions are not a concern.
@@ -1421,7 +2008,7 @@ func (e *Engineer) ListAllOpenMRs() ([]*MRInfo, error) {
 
 		// Check branch existence (local + remote tracking refs)
 		mr.BranchExistsLocal, _ = e.git.BranchExists(fields.Branch)
-		mr.BranchExistsRemote, _ = e.git.RemoteTrackingBranchExists("origin", fields.Branch)
+		mr.BranchExistsRemote, _ = e.git.RemoteTrackingBranchExists(e.rig.Remotes.BranchRemote(), fields.Branch)
 		mr.BlockedBy = e.firstOpenBlocker(issue)
 
 		mrs = append(mrs, mr)
@@ -1447,7 +2034,7 @@ func (e *Engineer) ListQueueAnomalies(now time.Time) ([]*MRAnomaly, error) {
 		if err != nil {
 			return false, false, err
 		}
-		remoteTrackingExists, err := e.git.RemoteTrackingBranchExists("origin", branch)
+		remoteTrackingExists, err := e.git.RemoteTrackingBranchExists(e.rig.Remotes.BranchRemote(), branch)
 		if err != nil {
 			return false, false, err
 		}
@@ -1608,7 +2195,7 @@ func (e *Engineer) checkAndCloseCompletedConvoys(townRoot, townBeads string) []c
 	var stdout bytes.Buffer
 	listCmd.Stdout = &stdout
 
-	if err := listCmd.Run(); err != nil {
+	if err := beads.RunCmd(listCmd); err != nil {
 		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to list convoys: %v\n", err)
 		return nil
 	}
@@ -1633,7 +2220,7 @@ func (e *Engineer) checkAndCloseCompletedConvoys(townRoot, townBeads string) []c
 		var depOut bytes.Buffer
 		depCmd.Stdout = &depOut
 
-		if err := depCmd.Run(); err != nil {
+		if err := beads.RunCmd(depCmd); err != nil {
 			continue
 		}
 
@@ -1663,7 +2250,7 @@ func (e *Engineer) checkAndCloseCompletedConvoys(townRoot, townBeads string) []c
 			var showOut bytes.Buffer
 			showCmd.Stdout = &showOut
 
-			if err := showCmd.Run(); err != nil || showOut.Len() == 0 {
+			if err := beads.RunCmd(showCmd); err != nil || showOut.Len() == 0 {
 				// Can't verify - treat as open to be safe
 				allClosed = false
 				break
@@ -1696,7 +2283,7 @@ func (e *Engineer) checkAndCloseCompletedConvoys(townRoot, townBeads string) []c
 		closeCmd := exec.Command("bd", "close", convoy.ID, "-r", reason)
 		closeCmd.Dir = townBeads
 
-		if err := closeCmd.Run(); err != nil {
+		if err := beads.RunCmd(closeCmd); err != nil {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to close convoy %s: %v\n", convoy.ID, err)
 			continue
 		}
@@ -1728,6 +2315,15 @@ func (e *Engineer) notifyConvoyCompletion(townRoot, convoyID, title, description
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: could not notify %s: %v\n", addr, err)
 		}
 	}
+
+	// Desktop notification for the operator (no-op unless enabled in town settings)
+	notifyCmd := exec.Command("gt", "notifications", "send",
+		"--event", "convoy_landed", "--severity", "low",
+		"--title", fmt.Sprintf("Convoy landed: %s", title),
+		"--body", fmt.Sprintf("%s: all tracked issues are closed", convoyID),
+		"--key", "convoy-landed-"+convoyID)
+	notifyCmd.Dir = townRoot
+	_ = notifyCmd.Run()
 }
 
 // landConvoySwarm checks if a completed convoy has an associated swarm with an
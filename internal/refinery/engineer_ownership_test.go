@@ -0,0 +1,64 @@
+package refinery
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/ownership"
+)
+
+func TestOwnerReviewers_ReadsOwnershipFromTarget(t *testing.T) {
+	workDir, g, _ := testGitRepo(t)
+	if err := os.MkdirAll(filepath.Join(workDir, ".github"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, workDir, ".github/CODEOWNERS", "/src/ @alice polecats/Nux\n/docs/ @bob\n")
+	run(t, workDir, "git", "add", ".")
+	run(t, workDir, "git", "commit", "-m", "add CODEOWNERS")
+
+	// The branch changes owned code and tries to drop its owners.
+	run(t, workDir, "git", "checkout", "-b", "polecat/Nux/gt-1", "main")
+	if err := os.MkdirAll(filepath.Join(workDir, "src"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, workDir, "src/main.go", "package main\n")
+	writeFile(t, workDir, ".github/CODEOWNERS", "")
+	run(t, workDir, "git", "add", ".")
+	run(t, workDir, "git", "commit", "-m", "change src")
+	run(t, workDir, "git", "checkout", "main")
+
+	e := newTestEngineer(t, workDir, g)
+	e.ownership = &ownership.Config{Aliases: map[string]string{"@alice": "crew/alice"}, RequireReview: true}
+
+	got, err := e.ownerReviewers("main", "polecat/Nux/gt-1", mrAuthor("test-rig", "Nux"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"test-rig/crew/alice"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("reviewers = %q, want %q", got, want)
+	}
+
+	issue := &beads.Issue{ID: "gt-mr-1", Labels: []string{ownership.ApprovalLabel("test-rig/crew/alice")}}
+	pending, err := e.pendingOwnerReview(issue, &beads.MRFields{Branch: "polecat/Nux/gt-1", Target: "main", Worker: "Nux"})
+	if err != nil || len(pending) != 0 {
+		t.Errorf("pending after approval = %q, %v", pending, err)
+	}
+}
+
+func TestMRAuthor(t *testing.T) {
+	tests := map[string]string{
+		"Nux":              "gastown/polecats/Nux",
+		"polecats/Nux":     "gastown/polecats/Nux",
+		"crew/max":         "gastown/crew/max",
+		"gastown/crew/max": "gastown/crew/max",
+		"":                 "",
+	}
+	for worker, want := range tests {
+		if got := mrAuthor("gastown", worker); got != want {
+			t.Errorf("mrAuthor(%q) = %q, want %q", worker, got, want)
+		}
+	}
+}
@@ -839,3 +839,129 @@ func TestIsClaimStale(t *testing.T) {
 		})
 	}
 }
+
+func TestEngineer_LoadConfig_Bench(t *testing.T) {
+	write := func(t *testing.T, benchCfg map[string]interface{}) *Engineer {
+		t.Helper()
+		tmpDir := t.TempDir()
+		config := map[string]interface{}{
+			"type":        "rig",
+			"version":     1,
+			"name":        "test-rig",
+			"merge_queue": map[string]interface{}{"bench": benchCfg},
+		}
+		data, _ := json.MarshalIndent(config, "", "  ")
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+	}
+
+	e := write(t, map[string]interface{}{
+		"cmd":         "go test -bench=. -count=5 ./...",
+		"timeout":     "5m",
+		"threshold":   0.05,
+		"window":      10,
+		"min_samples": 3,
+	})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("unexpected error loading config: %v", err)
+	}
+	b := e.config.Bench
+	if !b.IsEnabled() {
+		t.Fatal("expected bench to be enabled")
+	}
+	if b.Timeout != 5*time.Minute || b.GetThreshold() != 0.05 || b.GetWindow() != 10 || b.GetMinSamples() != 3 {
+		t.Errorf("bench config = %+v", b)
+	}
+
+	if err := write(t, map[string]interface{}{"cmd": "true", "timeout": "soon"}).LoadConfig(); err == nil {
+		t.Error("expected error for invalid bench timeout")
+	}
+	if err := write(t, map[string]interface{}{"cmd": "true", "threshold": -0.1}).LoadConfig(); err == nil {
+		t.Error("expected error for negative bench threshold")
+	}
+}
+
+func TestEngineer_LoadConfig_DependencyPolicy(t *testing.T) {
+	load := func(t *testing.T, policy map[string]interface{}) (*Engineer, error) {
+		t.Helper()
+		tmpDir := t.TempDir()
+		config := map[string]interface{}{
+			"type":        "rig",
+			"version":     1,
+			"name":        "test-rig",
+			"merge_queue": map[string]interface{}{"dependency_policy": policy},
+		}
+		data, _ := json.MarshalIndent(config, "", "  ")
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+		e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+		return e, e.LoadConfig()
+	}
+
+	e, err := load(t, map[string]interface{}{
+		"deny_licenses": []string{"GPL-*"},
+		"licenses":      map[string]string{"github.com/x/y": "MIT"},
+		"unknown":       "block",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error loading config: %v", err)
+	}
+	p := e.config.DependencyPolicy
+	if !p.IsEnabled() || p.GetUnknown() != "block" || p.Licenses["github.com/x/y"] != "MIT" {
+		t.Errorf("dependency policy = %+v", p)
+	}
+
+	if _, err := load(t, map[string]interface{}{"deny_licenses": []string{"GPL-*"}, "unknown": "maybe"}); err == nil {
+		t.Error("expected error for invalid unknown mode")
+	}
+}
+
+func TestEngineer_LoadConfig_Batch(t *testing.T) {
+	load := func(t *testing.T, batch string) (*Engineer, error) {
+		t.Helper()
+		tmpDir := t.TempDir()
+		data := `{"type":"rig","name":"test-rig","merge_queue":{"batch":` + batch + `}}`
+		if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		e := NewEngineer(&rig.Rig{Name: "test-rig", Path: tmpDir})
+		return e, e.LoadConfig()
+	}
+
+	e, err := load(t, `{"max_batch_size":8,"batch_wait_time":"2m"}`)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	b := e.config.Batch
+	if !b.Enabled() || b.MaxBatchSize != 8 || b.BatchWaitTime != 2*time.Minute || !b.RetryBatchOnFlaky {
+		t.Errorf("batch config = %+v, want size 8, wait 2m, flaky retry defaulted on", b)
+	}
+
+	if e, err = load(t, `{"max_batch_size":1}`); err != nil || e.config.Batch.Enabled() {
+		t.Errorf("max_batch_size 1 should load with batching off (err = %v)", err)
+	}
+	if _, err := load(t, `{"batch_wait_time":"soon"}`); err == nil {
+		t.Error("expected error for invalid batch_wait_time")
+	}
+	if _, err := load(t, `{"max_batch_size":-2}`); err == nil {
+		t.Error("expected error for negative max_batch_size")
+	}
+}
+
+func TestEngineer_LoadConfig_Monorepo(t *testing.T) {
+	tmpDir := t.TempDir()
+	data := `{"type":"rig","name":"api","monorepo":{"host":"platform","subdir":"services/api","shared":["go.work"]}}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e := NewEngineer(&rig.Rig{Name: "api", Path: tmpDir})
+	if err := e.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if e.monorepo == nil || e.monorepo.Subdir != "services/api" || !e.monorepo.Owns("go.work") {
+		t.Errorf("monorepo = %+v", e.monorepo)
+	}
+}
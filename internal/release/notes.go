@@ -0,0 +1,270 @@
+package release
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/changes"
+	"github.com/steveyegge/gastown/internal/provenance"
+)
+
+// Kind classifies a change by its conventional-commit subject.
+type Kind int
+
+// Kinds, least significant first.
+const (
+	KindOther Kind = iota
+	KindFix
+	KindFeature
+	KindBreaking
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindBreaking:
+		return "breaking"
+	case KindFeature:
+		return "feature"
+	case KindFix:
+		return "fix"
+	}
+	return "other"
+}
+
+// MarshalText renders kinds by name in JSON.
+func (k Kind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// ParseKind returns the kind named name ("breaking", "feature", "fix" or
+// "other").
+func ParseKind(name string) (Kind, error) {
+	for _, k := range []Kind{KindOther, KindFix, KindFeature, KindBreaking} {
+		if k.String() == name {
+			return k, nil
+		}
+	}
+	return KindOther, fmt.Errorf("unknown kind %q (breaking, feature, fix, other)", name)
+}
+
+// conventionalRe matches "type(scope)!: description".
+var conventionalRe = regexp.MustCompile(`^([a-zA-Z]+)(\([^)]*\))?(!)?:\s*(.+)$`)
+
+// Classify returns a commit's kind and its subject without the type prefix.
+func Classify(message string) (Kind, string) {
+	subject, body, _ := strings.Cut(message, "\n")
+	m := conventionalRe.FindStringSubmatch(subject)
+	if m == nil {
+		return KindOther, subject
+	}
+	if m[3] == "!" || strings.Contains(body, "BREAKING CHANGE:") || strings.Contains(body, "BREAKING-CHANGE:") {
+		return KindBreaking, m[4]
+	}
+	switch strings.ToLower(m[1]) {
+	case "feat":
+		return KindFeature, m[4]
+	case "fix":
+		return KindFix, m[4]
+	}
+	return KindOther, m[4]
+}
+
+// Entry is one commit in a release.
+type Entry struct {
+	Commit  string           `json:"commit"`
+	Subject string           `json:"subject"` // Without the conventional-commit type
+	Kind    Kind             `json:"kind"`
+	Bead    string           `json:"bead,omitempty"`
+	Changes *changes.Summary `json:"changes,omitempty"` // Recorded when the refinery merged the bead
+}
+
+// Notes is a draft of a release's notes.
+type Notes struct {
+	Rig         string  `json:"rig,omitempty"`
+	Version     string  `json:"version,omitempty"`
+	Tag         string  `json:"tag,omitempty"`
+	PreviousTag string  `json:"previous_tag,omitempty"` // Empty for a first release
+	Head        string  `json:"head"`
+	Entries     []Entry `json:"entries"`
+}
+
+// Kind returns the most significant kind of change in the release.
+func (n *Notes) Kind() Kind {
+	k := KindOther
+	for _, e := range n.Entries {
+		if e.Kind > k {
+			k = e.Kind
+		}
+	}
+	return k
+}
+
+// Beads returns the merged beads in the release, in merge order.
+func (n *Notes) Beads() []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, e := range n.Entries {
+		if e.Bead != "" && !seen[e.Bead] {
+			seen[e.Bead] = true
+			out = append(out, e.Bead)
+		}
+	}
+	return out
+}
+
+// LastTag returns the most recent tag with prefix reachable from ref, or ""
+// if there is none.
+func LastTag(dir, prefix, ref string) (string, error) {
+	if _, err := git(dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+		return "", fmt.Errorf("unknown ref %s", ref)
+	}
+	tag, err := git(dir, "describe", "--tags", "--abbrev=0", "--match", prefix+"*", ref)
+	if err != nil {
+		// describe fails when no tag matches.
+		return "", nil
+	}
+	return tag, nil
+}
+
+// Collect drafts notes for the commits after prevTag (all history when
+// empty) up to head in the repository at dir. A commit's bead comes from
+// its Gt-Bead trailer or the rig's change summaries, which also supply its
+// dependency and API changes.
+func Collect(dir, rigPath, prevTag, head string) (*Notes, error) {
+	headSHA, err := git(dir, "rev-parse", head+"^{commit}")
+	if err != nil {
+		return nil, err
+	}
+	rev := headSHA
+	if prevTag != "" {
+		rev = prevTag + ".." + headSHA
+	}
+	out, err := git(dir, "log", "--reverse", "--no-merges", "--format=%H%x1f%B%x1e", rev)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := changes.List(rigPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading change summaries: %w", err)
+	}
+	byCommit := make(map[string]*changes.Summary, len(summaries))
+	byBead := make(map[string]*changes.Summary, len(summaries))
+	for _, s := range summaries {
+		if s.MergeCommit != "" {
+			byCommit[s.MergeCommit] = s
+		}
+		byBead[s.Bead] = s
+	}
+
+	notes := &Notes{PreviousTag: prevTag, Head: headSHA, Entries: []Entry{}}
+	for _, record := range strings.Split(out, "\x1e") {
+		sha, message, ok := strings.Cut(strings.TrimLeft(record, "\n"), "\x1f")
+		if !ok {
+			continue
+		}
+		message = strings.TrimSpace(message)
+		kind, subject := Classify(message)
+		e := Entry{Commit: sha, Subject: subject, Kind: kind, Bead: provenance.ParseStamp(message).Bead}
+		if s := byCommit[sha]; s != nil {
+			e.Changes = s
+			if e.Bead == "" {
+				e.Bead = s.Bead
+			}
+		} else if s := byBead[e.Bead]; s != nil && e.Bead != "" {
+			e.Changes = s
+		}
+		notes.Entries = append(notes.Entries, e)
+	}
+	return notes, nil
+}
+
+// Markdown renders the notes: changes grouped by kind, then the
+// dependency and API changes of the merged beads.
+func (n *Notes) Markdown() string {
+	var b strings.Builder
+	title := n.Tag
+	if title == "" {
+		title = "Unreleased"
+	}
+	fmt.Fprintf(&b, "## %s\n", title)
+	if n.PreviousTag != "" {
+		fmt.Fprintf(&b, "\nChanges since %s.\n", n.PreviousTag)
+	}
+	if len(n.Entries) == 0 {
+		b.WriteString("\nNo changes.\n")
+		return b.String()
+	}
+
+	for _, section := range []struct {
+		kind  Kind
+		title string
+	}{
+		{KindBreaking, "Breaking changes"},
+		{KindFeature, "Features"},
+		{KindFix, "Fixes"},
+		{KindOther, "Other changes"},
+	} {
+		var lines []string
+		for _, e := range n.Entries {
+			if e.Kind != section.kind {
+				continue
+			}
+			line := "- " + e.Subject
+			if e.Bead != "" {
+				line += " (" + e.Bead + ")"
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(&b, "\n### %s\n\n%s\n", section.title, strings.Join(lines, "\n"))
+		}
+	}
+
+	var deps, api []string
+	seen := map[*changes.Summary]bool{}
+	for _, e := range n.Entries {
+		s := e.Changes
+		if s == nil || seen[s] {
+			continue
+		}
+		seen[s] = true
+		for _, d := range s.Dependencies {
+			if d.Previous == "" {
+				deps = append(deps, fmt.Sprintf("- Added %s (%s)", d, s.Bead))
+			} else {
+				deps = append(deps, fmt.Sprintf("- Updated %s from %s (%s)", d, d.Previous, s.Bead))
+			}
+		}
+		for _, a := range s.API {
+			for _, name := range a.Removed {
+				api = append(api, fmt.Sprintf("- Removed %s.%s (%s)", a.Package, name, s.Bead))
+			}
+			for _, name := range a.Changed {
+				api = append(api, fmt.Sprintf("- Changed %s.%s (%s)", a.Package, name, s.Bead))
+			}
+		}
+	}
+	if len(deps) > 0 {
+		fmt.Fprintf(&b, "\n### Dependencies\n\n%s\n", strings.Join(deps, "\n"))
+	}
+	if len(api) > 0 {
+		fmt.Fprintf(&b, "\n### API changes\n\n%s\n", strings.Join(api, "\n"))
+	}
+	return b.String()
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok && len(ee.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(ee.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
@@ -0,0 +1,270 @@
+// Package release implements release orchestration for a rig: drafting
+// release notes from the beads merged since the last tag, picking the next
+// version, and tracking a release (its bead, formula and approval gates)
+// while the rig's release formula runs.
+//
+// Release state is kept under the rig's .runtime/releases/, one file per
+// release bead (gt release).
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Defaults for an unset release config.
+const (
+	DefaultFormula   = "mol-release"
+	DefaultTagPrefix = "v"
+	DefaultApprover  = "overseer"
+)
+
+// Release checklist steps that can be gated.
+const (
+	StepVersionBump = "version-bump"
+	StepChangelog   = "changelog"
+	StepTag         = "tag"
+	StepPublish     = "publish"
+)
+
+// Steps lists the gateable steps in checklist order.
+var Steps = []string{StepVersionBump, StepChangelog, StepTag, StepPublish}
+
+// Config is the release section of a rig's settings/config.json.
+//
+//	"release": {
+//	  "formula": "mol-release",
+//	  "tag_prefix": "v",
+//	  "gates": ["tag", "publish"],
+//	  "approver": "overseer",
+//	  "assignee": "gastown/crew/max",
+//	  "bump_command": "./scripts/bump-version.sh",
+//	  "publish_command": "goreleaser release --clean"
+//	}
+//
+// Every checklist step is gated unless gates says otherwise; an empty list
+// ("gates": []) gates nothing. The assignee is who gt release start slings
+// the formula to (default: a polecat in the rig). The commands are passed to
+// the formula as bump_command and publish_command.
+type Config struct {
+	Formula        string   `json:"formula,omitempty"`
+	TagPrefix      string   `json:"tag_prefix,omitempty"`
+	Gates          []string `json:"gates,omitempty"`
+	Approver       string   `json:"approver,omitempty"`
+	Assignee       string   `json:"assignee,omitempty"`
+	BumpCommand    string   `json:"bump_command,omitempty"`
+	PublishCommand string   `json:"publish_command,omitempty"`
+}
+
+// GetFormula returns the release formula's name.
+func (c *Config) GetFormula() string {
+	if c == nil || c.Formula == "" {
+		return DefaultFormula
+	}
+	return c.Formula
+}
+
+// GetTagPrefix returns the prefix of release tags ("v" in v1.2.3).
+func (c *Config) GetTagPrefix() string {
+	if c == nil || c.TagPrefix == "" {
+		return DefaultTagPrefix
+	}
+	return c.TagPrefix
+}
+
+// GetGates returns the gated steps.
+func (c *Config) GetGates() []string {
+	if c == nil || c.Gates == nil {
+		return Steps
+	}
+	return c.Gates
+}
+
+// Gated reports whether step needs approval before it runs.
+func (c *Config) Gated(step string) bool {
+	for _, g := range c.GetGates() {
+		if g == step {
+			return true
+		}
+	}
+	return false
+}
+
+// GetApprover returns the mail address gate approvals are requested from.
+func (c *Config) GetApprover() string {
+	if c == nil || c.Approver == "" {
+		return DefaultApprover
+	}
+	return c.Approver
+}
+
+// Validate checks the config. A nil config is valid (all defaults).
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	for _, g := range c.GetGates() {
+		if !IsStep(g) {
+			return fmt.Errorf("unknown gate %q (steps: %s)", g, strings.Join(Steps, ", "))
+		}
+	}
+	if strings.ContainsAny(c.GetTagPrefix(), " ~^:?*[\\") {
+		return fmt.Errorf("tag_prefix %q is not valid in a git tag", c.GetTagPrefix())
+	}
+	return nil
+}
+
+// IsStep reports whether name is a gateable checklist step.
+func IsStep(name string) bool {
+	for _, s := range Steps {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NextVersion returns the version after prev (without tag prefix) for a
+// release whose most significant change is kind. Before 1.0.0, breaking
+// changes bump the minor version. An empty prev starts at 0.1.0.
+func NextVersion(prev string, kind Kind) (string, error) {
+	if prev == "" {
+		return "0.1.0", nil
+	}
+	v, err := parseVersion(prev)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case kind == KindBreaking && v[0] > 0:
+		v = [3]int{v[0] + 1, 0, 0}
+	case kind == KindBreaking || kind == KindFeature:
+		v = [3]int{v[0], v[1] + 1, 0}
+	default:
+		v[2]++
+	}
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2]), nil
+}
+
+// parseVersion parses "1.2.3", ignoring any pre-release or build suffix.
+func parseVersion(s string) ([3]int, error) {
+	var v [3]int
+	core, _, _ := strings.Cut(s, "-")
+	core, _, _ = strings.Cut(core, "+")
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("version %q is not MAJOR.MINOR.PATCH", s)
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("version %q is not MAJOR.MINOR.PATCH", s)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+// ValidVersion reports whether s is a MAJOR.MINOR.PATCH version.
+func ValidVersion(s string) bool {
+	_, err := parseVersion(s)
+	return err == nil
+}
+
+// Release is the tracked state of one release, keyed by its bead.
+type Release struct {
+	Bead        string           `json:"bead"`
+	Rig         string           `json:"rig"`
+	Version     string           `json:"version"`
+	Tag         string           `json:"tag"`
+	PreviousTag string           `json:"previous_tag,omitempty"`
+	Commit      string           `json:"commit"` // Head the notes were drafted at
+	Formula     string           `json:"formula"`
+	Assignee    string           `json:"assignee,omitempty"`
+	Beads       []string         `json:"beads,omitempty"` // Merged beads in the release
+	Gates       map[string]*Gate `json:"gates,omitempty"` // By step
+	StartedAt   time.Time        `json:"started_at"`
+}
+
+// Gate is the approval state of one gated step.
+type Gate struct {
+	Thread      string     `json:"thread,omitempty"` // Mail thread of the approval request
+	Approver    string     `json:"approver"`
+	RequestedBy string     `json:"requested_by,omitempty"`
+	RequestedAt time.Time  `json:"requested_at"`
+	Decision    string     `json:"decision,omitempty"` // "approved" or "rejected"; empty while pending
+	Note        string     `json:"note,omitempty"`
+	DecidedAt   *time.Time `json:"decided_at,omitempty"`
+}
+
+// Pending reports whether the gate is still waiting for a decision.
+func (g *Gate) Pending() bool {
+	return g.Decision == ""
+}
+
+// Dir is where a rig's release state is kept.
+func Dir(rigPath string) string {
+	return filepath.Join(rigPath, constants.DirRuntime, "releases")
+}
+
+// Save records a release, replacing any earlier state for its bead.
+func Save(rigPath string, r *Release) error {
+	if r.Bead == "" {
+		return fmt.Errorf("release has no bead")
+	}
+	if err := os.MkdirAll(Dir(rigPath), 0755); err != nil {
+		return fmt.Errorf("creating releases directory: %w", err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(Dir(rigPath), r.Bead+".json"), append(data, '\n'), 0644) //nolint:gosec // G306: release state is not sensitive
+}
+
+// Load returns the release tracked by a bead, or nil if there is none.
+func Load(rigPath, bead string) (*Release, error) {
+	data, err := os.ReadFile(filepath.Join(Dir(rigPath), bead+".json")) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var r Release
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing release %s: %w", bead, err)
+	}
+	return &r, nil
+}
+
+// List returns a rig's releases, oldest first. Unreadable files are skipped.
+func List(rigPath string) ([]*Release, error) {
+	entries, err := os.ReadDir(Dir(rigPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []*Release
+	for _, entry := range entries {
+		bead, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if r, err := Load(rigPath, bead); err == nil && r != nil {
+			out = append(out, r)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].StartedAt.Before(out[j].StartedAt) })
+	return out, nil
+}
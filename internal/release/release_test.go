@@ -0,0 +1,210 @@
+package release
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/changes"
+	"github.com/steveyegge/gastown/internal/deppolicy"
+)
+
+func TestConfig(t *testing.T) {
+	var nilCfg *Config
+	if nilCfg.GetFormula() != DefaultFormula || nilCfg.GetTagPrefix() != "v" || nilCfg.GetApprover() != "overseer" {
+		t.Error("nil config should use the defaults")
+	}
+	if !nilCfg.Gated(StepVersionBump) || !nilCfg.Gated(StepPublish) {
+		t.Error("every step should be gated by default")
+	}
+	if cfg := (&Config{Gates: []string{}}); cfg.Gated(StepTag) {
+		t.Error("an empty gates list should gate nothing")
+	}
+	if cfg := (&Config{Gates: []string{StepTag}}); !cfg.Gated(StepTag) || cfg.Gated(StepChangelog) {
+		t.Error("only listed steps should be gated")
+	}
+
+	for _, tt := range []struct {
+		cfg     *Config
+		wantErr string
+	}{
+		{nil, ""},
+		{&Config{Gates: []string{"tag", "publish"}}, ""},
+		{&Config{Gates: []string{"deploy"}}, "unknown gate"},
+		{&Config{TagPrefix: "release "}, "tag_prefix"},
+	} {
+		err := tt.cfg.Validate()
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("Validate(%+v) = %v", tt.cfg, err)
+		} else if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("Validate(%+v) = %v, want containing %q", tt.cfg, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNextVersion(t *testing.T) {
+	tests := []struct {
+		prev string
+		kind Kind
+		want string
+	}{
+		{"", KindFix, "0.1.0"},
+		{"1.2.3", KindOther, "1.2.4"},
+		{"1.2.3", KindFix, "1.2.4"},
+		{"1.2.3", KindFeature, "1.3.0"},
+		{"1.2.3", KindBreaking, "2.0.0"},
+		{"0.4.1", KindBreaking, "0.5.0"},
+		{"1.2.3-rc.1", KindFix, "1.2.4"},
+	}
+	for _, tt := range tests {
+		got, err := NextVersion(tt.prev, tt.kind)
+		if err != nil || got != tt.want {
+			t.Errorf("NextVersion(%q, %v) = %q, %v, want %q", tt.prev, tt.kind, got, err, tt.want)
+		}
+	}
+	if _, err := NextVersion("nightly", KindFix); err == nil {
+		t.Error("NextVersion of a non-semver tag should fail")
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		message string
+		kind    Kind
+		subject string
+	}{
+		{"feat(mail): add approval gates", KindFeature, "add approval gates"},
+		{"fix: handle empty queue", KindFix, "handle empty queue"},
+		{"refactor!: drop the legacy mailbox", KindBreaking, "drop the legacy mailbox"},
+		{"feat: new config\n\nBREAKING CHANGE: settings moved", KindBreaking, "new config"},
+		{"docs: typo", KindOther, "typo"},
+		{"Update README", KindOther, "Update README"},
+	}
+	for _, tt := range tests {
+		kind, subject := Classify(tt.message)
+		if kind != tt.kind || subject != tt.subject {
+			t.Errorf("Classify(%q) = %v, %q, want %v, %q", tt.message, kind, subject, tt.kind, tt.subject)
+		}
+	}
+}
+
+func gitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q", "--initial-branch=main")
+	runGit(t, dir, "config", "user.email", "test@example.com")
+	runGit(t, dir, "config", "user.name", "Test")
+	return dir
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func commit(t *testing.T, dir, message string) string {
+	t.Helper()
+	runGit(t, dir, "commit", "-q", "--allow-empty", "-m", message)
+	return runGit(t, dir, "rev-parse", "HEAD")
+}
+
+func TestCollect(t *testing.T) {
+	dir := gitRepo(t)
+	rigPath := t.TempDir()
+
+	commit(t, dir, "feat: first release")
+	runGit(t, dir, "tag", "v0.1.0")
+	if tag, err := LastTag(dir, "v", "HEAD"); err != nil || tag != "v0.1.0" {
+		t.Fatalf("LastTag = %q, %v", tag, err)
+	}
+
+	commit(t, dir, "fix: handle empty queue\n\nGt-Agent: gastown/polecats/nux\nGt-Bead: gt-fix1")
+	merged := commit(t, dir, "feat(mail): approval gates")
+	commit(t, dir, "chore: tidy")
+	if err := changes.Save(rigPath, &changes.Summary{
+		Bead:         "gt-feat2",
+		MergeCommit:  merged,
+		MergedAt:     time.Now(),
+		Dependencies: []deppolicy.Dependency{{Name: "example.com/new", Version: "v0.3.0", Manifest: "go.mod"}},
+		API:          []changes.APIChange{{Package: "internal/mail", Removed: []string{"Send"}}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	notes, err := Collect(dir, rigPath, "v0.1.0", "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes.Entries) != 3 {
+		t.Fatalf("Entries = %+v", notes.Entries)
+	}
+	if got := strings.Join(notes.Beads(), ","); got != "gt-fix1,gt-feat2" {
+		t.Errorf("Beads = %s", got)
+	}
+	if notes.Kind() != KindFeature {
+		t.Errorf("Kind = %v", notes.Kind())
+	}
+
+	notes.Tag = "v0.2.0"
+	md := notes.Markdown()
+	for _, want := range []string{
+		"## v0.2.0",
+		"Changes since v0.1.0.",
+		"### Features\n\n- approval gates (gt-feat2)",
+		"### Fixes\n\n- handle empty queue (gt-fix1)",
+		"### Other changes\n\n- tidy",
+		"- Added example.com/new",
+		"- Removed internal/mail.Send (gt-feat2)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	if notes, err := Collect(dir, rigPath, "", "HEAD"); err != nil || len(notes.Entries) != 4 {
+		t.Errorf("Collect from the first commit = %+v, %v", notes, err)
+	}
+	if tag, err := LastTag(dir, "release-", "HEAD"); err != nil || tag != "" {
+		t.Errorf("LastTag without matching tags = %q, %v", tag, err)
+	}
+}
+
+func TestSaveLoadList(t *testing.T) {
+	rigPath := t.TempDir()
+	if r, err := Load(rigPath, "gt-none"); err != nil || r != nil {
+		t.Errorf("Load missing = %v, %v", r, err)
+	}
+
+	now := time.Now().UTC()
+	for i, bead := range []string{"gt-rel2", "gt-rel1"} {
+		r := &Release{Bead: bead, Tag: "v0.1.0", StartedAt: now.Add(time.Duration(i) * time.Hour)}
+		if err := Save(rigPath, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Save(rigPath, &Release{}); err == nil {
+		t.Error("a release without a bead should not be saved")
+	}
+
+	r, _ := Load(rigPath, "gt-rel1")
+	r.Gates = map[string]*Gate{StepTag: {Approver: "overseer", RequestedAt: now}}
+	if err := Save(rigPath, r); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Load(rigPath, "gt-rel1")
+	if err != nil || r.Gates[StepTag] == nil || !r.Gates[StepTag].Pending() {
+		t.Fatalf("Load = %+v, %v", r, err)
+	}
+	list, err := List(rigPath)
+	if err != nil || len(list) != 2 || list[0].Bead != "gt-rel2" {
+		t.Errorf("List = %+v, %v", list, err)
+	}
+}
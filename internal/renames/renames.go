@@ -0,0 +1,177 @@
+// Package renames keeps the town's record of renamed agents and rigs, so
+// an agent keeps its identity across a rename: mail to the old address is
+// forwarded, and history recorded under the old name (events, transcripts,
+// completed work) is still found under the new one.
+//
+// The ledger lives at mayor/renames.json. Entries are never removed: an old
+// name may be reused (a polecat name goes back to the pool), so an old
+// address only forwards while nothing else answers to it, and history under
+// an old address belongs to the renamed agent only up to the rename.
+package renames
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Kinds of rename.
+const (
+	KindAgent = "agent" // From and To are agent addresses (gastown/polecats/Toast)
+	KindRig   = "rig"   // From and To are rig names; every address in the rig moves
+)
+
+// Entry is one rename.
+type Entry struct {
+	Kind string    `json:"kind"`
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	At   time.Time `json:"at"`
+}
+
+// Ledger is the town's renames, oldest first.
+type Ledger struct {
+	Renames []Entry `json:"renames"`
+}
+
+// Alias is a former address of an agent.
+type Alias struct {
+	Address string    `json:"address"`
+	Until   time.Time `json:"until"` // When the agent stopped answering to it
+}
+
+// Path returns the ledger's path in a town.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, "mayor", "renames.json")
+}
+
+// Load reads a town's ledger. A town without one has an empty ledger.
+func Load(townRoot string) (*Ledger, error) {
+	data, err := os.ReadFile(Path(townRoot)) //nolint:gosec // G304: path is in the town
+	if os.IsNotExist(err) {
+		return &Ledger{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var l Ledger
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", Path(townRoot), err)
+	}
+	return &l, nil
+}
+
+// Save writes the ledger to a town.
+func (l *Ledger) Save(townRoot string) error {
+	if err := os.MkdirAll(filepath.Dir(Path(townRoot)), 0755); err != nil {
+		return err
+	}
+	return util.AtomicWriteJSON(Path(townRoot), l)
+}
+
+// Record appends a rename made now.
+func (l *Ledger) Record(kind, from, to string) {
+	l.Renames = append(l.Renames, Entry{Kind: kind, From: from, To: to, At: time.Now().UTC()})
+}
+
+// Current follows addr through every later rename and returns where it
+// points now, or addr itself if it was never renamed. Short agent addresses
+// (gastown/Toast) are followed like their long forms and stay short.
+func (l *Ledger) Current(addr string) string {
+	var since time.Time
+	for {
+		next, at, ok := l.forward(addr, since)
+		if !ok {
+			return addr
+		}
+		addr, since = next, at
+	}
+}
+
+// forward applies the first rename of addr made after since. Each step
+// moves since forward, so following a chain always ends.
+func (l *Ledger) forward(addr string, since time.Time) (string, time.Time, bool) {
+	for _, e := range l.Renames {
+		if !e.At.After(since) {
+			continue
+		}
+		if to, ok := rewrite(addr, e.Kind, e.From, e.To); ok {
+			return to, e.At, true
+		}
+	}
+	return "", time.Time{}, false
+}
+
+// Former returns every address the agent at addr answered to before, each
+// with the time it stopped answering to it, newest first.
+//
+// Walking back from each address, the latest rename that touches it
+// decides: a rename to it means the agent had the pre-image name before; a
+// rename away from it means the name belonged to someone else back then.
+func (l *Ledger) Former(addr string) []Alias {
+	var aliases []Alias
+	cur := Alias{Address: addr}
+	for {
+		prev, ok := l.before(cur)
+		if !ok {
+			return aliases
+		}
+		aliases = append(aliases, prev)
+		cur = prev
+	}
+}
+
+// before returns the address the agent at cur had before getting it.
+func (l *Ledger) before(cur Alias) (Alias, bool) {
+	for i := len(l.Renames) - 1; i >= 0; i-- {
+		e := l.Renames[i]
+		if !cur.Until.IsZero() && !e.At.Before(cur.Until) {
+			continue
+		}
+		if from, ok := rewrite(cur.Address, e.Kind, e.To, e.From); ok {
+			return Alias{Address: from, Until: e.At}, true
+		}
+		if _, ok := rewrite(cur.Address, e.Kind, e.From, e.To); ok {
+			return Alias{}, false
+		}
+	}
+	return Alias{}, false
+}
+
+// rewrite renames addr if the rename from → to covers it.
+func rewrite(addr, kind, from, to string) (string, bool) {
+	switch kind {
+	case KindRig:
+		rig, rest, found := strings.Cut(addr, "/")
+		if rig != from {
+			return "", false
+		}
+		if !found {
+			return to, true
+		}
+		return to + "/" + rest, true
+	case KindAgent:
+		if addr == from {
+			return to, true
+		}
+		if short := shortAddress(from); short != from && addr == short {
+			return shortAddress(to), true
+		}
+	}
+	return "", false
+}
+
+// shortAddress turns rig/polecats/name and rig/crew/name into rig/name, the
+// form mail also accepts.
+func shortAddress(addr string) string {
+	parts := strings.Split(addr, "/")
+	if len(parts) == 3 && (parts[1] == "polecats" || parts[1] == "crew") {
+		return parts[0] + "/" + parts[2]
+	}
+	return addr
+}
@@ -0,0 +1,100 @@
+package renames
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ledgerAt(entries ...Entry) *Ledger {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range entries {
+		entries[i].At = base.Add(time.Duration(i) * time.Hour)
+	}
+	return &Ledger{Renames: entries}
+}
+
+func TestCurrent(t *testing.T) {
+	l := ledgerAt(
+		Entry{Kind: KindAgent, From: "gastown/polecats/Toast", To: "gastown/polecats/Imperator"},
+		Entry{Kind: KindRig, From: "gastown", To: "gt"},
+		Entry{Kind: KindAgent, From: "gt/polecats/Imperator", To: "gt/polecats/Furiosa"},
+	)
+	tests := map[string]string{
+		"gastown/polecats/Toast": "gt/polecats/Furiosa",
+		"gastown/Toast":          "gt/Furiosa",
+		"gastown/polecats/nux":   "gt/polecats/nux",
+		"gastown/witness":        "gt/witness",
+		"gastown":                "gt",
+		"gt/polecats/Imperator":  "gt/polecats/Furiosa",
+		"beads/polecats/Toast":   "beads/polecats/Toast",
+		"mayor/":                 "mayor/",
+	}
+	for in, want := range tests {
+		if got := l.Current(in); got != want {
+			t.Errorf("Current(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCurrent_Cycle(t *testing.T) {
+	l := ledgerAt(
+		Entry{Kind: KindAgent, From: "gastown/polecats/a", To: "gastown/polecats/b"},
+		Entry{Kind: KindAgent, From: "gastown/polecats/b", To: "gastown/polecats/a"},
+	)
+	// a became b, then b became a again: a is the latest name.
+	if got := l.Current("gastown/polecats/a"); got != "gastown/polecats/a" {
+		t.Errorf("Current(a) = %q", got)
+	}
+}
+
+func TestFormer(t *testing.T) {
+	l := ledgerAt(
+		Entry{Kind: KindAgent, From: "gastown/polecats/Toast", To: "gastown/polecats/Imperator"},
+		Entry{Kind: KindRig, From: "gastown", To: "gt"},
+	)
+	got := l.Former("gt/polecats/Imperator")
+	want := []Alias{
+		{Address: "gastown/polecats/Imperator", Until: l.Renames[1].At},
+		{Address: "gastown/polecats/Toast", Until: l.Renames[0].At},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Former = %+v, want %+v", got, want)
+	}
+}
+
+func TestFormer_ReusedName(t *testing.T) {
+	l := ledgerAt(
+		Entry{Kind: KindAgent, From: "gastown/polecats/Toast", To: "gastown/polecats/Imperator"},
+		Entry{Kind: KindAgent, From: "gastown/polecats/Imperator", To: "gastown/polecats/Max"},
+		Entry{Kind: KindAgent, From: "gastown/polecats/Nux", To: "gastown/polecats/Imperator"},
+	)
+	// The second Imperator was Nux; the first Imperator's history is Max's.
+	got := l.Former("gastown/polecats/Imperator")
+	if len(got) != 1 || got[0].Address != "gastown/polecats/Nux" {
+		t.Errorf("Former(Imperator) = %+v", got)
+	}
+	got = l.Former("gastown/polecats/Max")
+	if len(got) != 2 || got[0].Address != "gastown/polecats/Imperator" || got[1].Address != "gastown/polecats/Toast" {
+		t.Errorf("Former(Max) = %+v", got)
+	}
+}
+
+func TestLoadSave(t *testing.T) {
+	town := t.TempDir()
+	l, err := Load(town)
+	if err != nil || len(l.Renames) != 0 {
+		t.Fatalf("Load(empty town) = %+v, %v", l, err)
+	}
+	l.Record(KindRig, "gastown", "gt")
+	if err := l.Save(town); err != nil {
+		t.Fatal(err)
+	}
+	back, err := Load(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(back.Renames) != 1 || back.Renames[0].To != "gt" {
+		t.Errorf("reloaded %+v", back)
+	}
+}
@@ -0,0 +1,87 @@
+package replay
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ContextDir is the directory inside a scratch worktree holding the
+// captured inputs.
+const ContextDir = ".replay"
+
+// Materialize writes a capture's inputs into workDir/.replay so the
+// replaying agent can read them.
+func Materialize(c *Capture, workDir string) error {
+	dir := filepath.Join(workDir, ContextDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+	files := map[string][]byte{
+		"BRIEFING.md": []byte(c.Briefing),
+		"REPLAY.md":   []byte(Summary(c)),
+	}
+	if len(c.BeadSnapshot) > 0 {
+		files["bead.json"] = c.BeadSnapshot
+	}
+	if len(c.MoleculeSnapshot) > 0 {
+		files["molecule.json"] = c.MoleculeSnapshot
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil { //nolint:gosec // G306: not sensitive
+			return fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Summary describes a capture in markdown.
+func Summary(c *Capture) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Replay of %s\n\n", c.Bead)
+	fmt.Fprintf(&sb, "Captured %s", c.CapturedAt.UTC().Format("2006-01-02 15:04:05 UTC"))
+	if c.Polecat != "" {
+		fmt.Fprintf(&sb, " when slung to %s/polecats/%s", c.Rig, c.Polecat)
+	}
+	sb.WriteString(".\n\n")
+	row := func(k, v string) {
+		if v != "" {
+			fmt.Fprintf(&sb, "- %s: %s\n", k, v)
+		}
+	}
+	row("Repo commit", c.RepoSHA)
+	row("Base branch", c.BaseBranch)
+	row("Formula", c.Formula)
+	row("Molecule", c.HookedBead)
+	row("Agent", c.Agent)
+	if c.PersonaVersion > 0 {
+		row("Persona", fmt.Sprintf("polecat v%d", c.PersonaVersion))
+	}
+	row("gt version", c.GTVersion)
+	if len(c.Vars) > 0 {
+		row("Vars", strings.Join(c.Vars, ", "))
+	}
+	if c.Args != "" {
+		fmt.Fprintf(&sb, "\n## Args\n\n%s\n", c.Args)
+	}
+	return sb.String()
+}
+
+// Prompt is the startup prompt for a replaying agent.
+func Prompt(c *Capture) string {
+	return fmt.Sprintf("This is a REPLAY of bead %s in a scratch checkout of %s. "+
+		"Read %s/BRIEFING.md — the briefing you were given when this work was dispatched — "+
+		"and %s/bead.json, then do the work exactly as that briefing instructs. "+
+		"This checkout is disconnected from Gas Town: skip gt done, gt mail, bd and any push; "+
+		"when finished, summarize what you did and stop.",
+		c.Bead, ShortSHA(c.RepoSHA), ContextDir, ContextDir)
+}
+
+// ShortSHA abbreviates a commit id for display.
+func ShortSHA(sha string) string {
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
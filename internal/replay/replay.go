@@ -0,0 +1,142 @@
+// Package replay captures the inputs of a polecat dispatch so the work can
+// be re-run later against the same starting state.
+//
+// When a bead is slung to a fresh polecat, gt sling records a Capture: a
+// snapshot of the bead (and attached molecule), the formula, args and vars,
+// the agent, the repo commit the worktree started from, and the briefing the
+// polecat would see from gt prime. gt replay materializes a capture into a
+// scratch worktree checked out at that commit and starts an agent there,
+// disconnected from the town's beads, mail and merge queue.
+//
+// Captures are stored as <town>/.runtime/replays/<bead>/<id>.json, where id
+// is the capture timestamp, so ids sort chronologically.
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// idFormat is the layout of capture ids (UTC, sortable, filename-safe).
+const idFormat = "20060102T150405.000Z"
+
+// Capture is everything needed to reproduce one dispatch.
+type Capture struct {
+	ID         string    `json:"id"`
+	Bead       string    `json:"bead"`
+	HookedBead string    `json:"hooked_bead,omitempty"` // Molecule root when a formula was applied
+	CapturedAt time.Time `json:"captured_at"`
+	Dispatcher string    `json:"dispatcher,omitempty"`
+
+	Rig        string `json:"rig"`
+	Polecat    string `json:"polecat,omitempty"`
+	BaseBranch string `json:"base_branch,omitempty"`
+	RepoSHA    string `json:"repo_sha"`
+
+	Formula string   `json:"formula,omitempty"`
+	Args    string   `json:"args,omitempty"`
+	Vars    []string `json:"vars,omitempty"`
+	Agent   string   `json:"agent,omitempty"` // Runtime override; empty means the rig default
+
+	// PersonaVersion is the polecat persona version active at dispatch.
+	PersonaVersion int `json:"persona_version,omitempty"`
+
+	// GTVersion is the gt build that produced the briefing.
+	GTVersion string `json:"gt_version,omitempty"`
+
+	BeadSnapshot     json.RawMessage `json:"bead_snapshot,omitempty"`
+	MoleculeSnapshot json.RawMessage `json:"molecule_snapshot,omitempty"`
+
+	// Briefing is the gt prime output the polecat would have seen.
+	Briefing string `json:"briefing,omitempty"`
+}
+
+// Dir returns the directory holding a bead's captures.
+func Dir(townRoot, bead string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "replays", bead)
+}
+
+func capturePath(townRoot, bead, id string) string {
+	return filepath.Join(Dir(townRoot, bead), id+".json")
+}
+
+// Save writes a capture, assigning its id and timestamp if unset.
+func Save(townRoot string, c *Capture) error {
+	if c.Bead == "" {
+		return fmt.Errorf("capture has no bead")
+	}
+	if c.CapturedAt.IsZero() {
+		c.CapturedAt = time.Now().UTC()
+	}
+	if c.ID == "" {
+		c.ID = c.CapturedAt.UTC().Format(idFormat)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding capture: %w", err)
+	}
+	if err := os.MkdirAll(Dir(townRoot, c.Bead), 0755); err != nil {
+		return fmt.Errorf("creating replay directory: %w", err)
+	}
+	return os.WriteFile(capturePath(townRoot, c.Bead, c.ID), append(data, '\n'), 0644) //nolint:gosec // G306: not sensitive
+}
+
+// List returns a bead's captures, oldest first.
+func List(townRoot, bead string) ([]*Capture, error) {
+	entries, err := os.ReadDir(Dir(townRoot, bead))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var out []*Capture
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		c, err := Load(townRoot, bead, id)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+// Load reads one capture.
+func Load(townRoot, bead, id string) (*Capture, error) {
+	data, err := os.ReadFile(capturePath(townRoot, bead, id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no capture %s for %s", id, bead)
+		}
+		return nil, err
+	}
+	var c Capture
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing capture %s: %w", id, err)
+	}
+	return &c, nil
+}
+
+// Latest returns the most recent capture for bead.
+func Latest(townRoot, bead string) (*Capture, error) {
+	captures, err := List(townRoot, bead)
+	if err != nil {
+		return nil, err
+	}
+	if len(captures) == 0 {
+		return nil, fmt.Errorf("no replay captures for %s (captures are recorded when a bead is slung to a new polecat)", bead)
+	}
+	return captures[len(captures)-1], nil
+}
@@ -0,0 +1,82 @@
+package replay
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveListLatest(t *testing.T) {
+	townRoot := t.TempDir()
+	if _, err := Latest(townRoot, "gt-abc"); err == nil {
+		t.Error("Latest with no captures = nil error")
+	}
+
+	first := &Capture{Bead: "gt-abc", Rig: "gastown", RepoSHA: "aaa", CapturedAt: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)}
+	second := &Capture{Bead: "gt-abc", Rig: "gastown", RepoSHA: "bbb", CapturedAt: time.Date(2026, 10, 2, 9, 0, 0, 0, time.UTC)}
+	for _, c := range []*Capture{second, first} {
+		if err := Save(townRoot, c); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	if first.ID == "" || first.ID >= second.ID {
+		t.Errorf("ids %q, %q should sort chronologically", first.ID, second.ID)
+	}
+
+	captures, err := List(townRoot, "gt-abc")
+	if err != nil || len(captures) != 2 || captures[0].RepoSHA != "aaa" {
+		t.Fatalf("List = %+v, %v", captures, err)
+	}
+	latest, err := Latest(townRoot, "gt-abc")
+	if err != nil || latest.RepoSHA != "bbb" {
+		t.Errorf("Latest = %+v, %v", latest, err)
+	}
+	if _, err := Load(townRoot, "gt-abc", "nope"); err == nil {
+		t.Error("Load of a missing id = nil error")
+	}
+	if err := Save(townRoot, &Capture{}); err == nil {
+		t.Error("Save without a bead = nil error")
+	}
+}
+
+func TestMaterialize(t *testing.T) {
+	workDir := t.TempDir()
+	c := &Capture{
+		Bead:         "gt-abc",
+		Rig:          "gastown",
+		Polecat:      "nux",
+		RepoSHA:      "0123456789abcdef0123",
+		Formula:      "mol-polecat-work",
+		Args:         "patch release",
+		Briefing:     "# Polecat Context\nYou are nux.\n",
+		BeadSnapshot: json.RawMessage(`{"id":"gt-abc","title":"Fix parser"}`),
+	}
+	if err := Materialize(c, workDir); err != nil {
+		t.Fatalf("Materialize: %v", err)
+	}
+
+	briefing, err := os.ReadFile(filepath.Join(workDir, ContextDir, "BRIEFING.md"))
+	if err != nil || string(briefing) != c.Briefing {
+		t.Errorf("BRIEFING.md = %q, %v", briefing, err)
+	}
+	if _, err := os.Stat(filepath.Join(workDir, ContextDir, "bead.json")); err != nil {
+		t.Errorf("bead.json: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(workDir, ContextDir, "molecule.json")); !os.IsNotExist(err) {
+		t.Errorf("molecule.json written without a molecule snapshot (err=%v)", err)
+	}
+	summary, _ := os.ReadFile(filepath.Join(workDir, ContextDir, "REPLAY.md"))
+	for _, want := range []string{"gastown/polecats/nux", "mol-polecat-work", "patch release"} {
+		if !strings.Contains(string(summary), want) {
+			t.Errorf("REPLAY.md missing %q:\n%s", want, summary)
+		}
+	}
+
+	prompt := Prompt(c)
+	if !strings.Contains(prompt, "gt-abc") || !strings.Contains(prompt, "checkout of 0123456789ab.") {
+		t.Errorf("Prompt = %q", prompt)
+	}
+}
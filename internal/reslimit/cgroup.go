@@ -0,0 +1,231 @@
+package reslimit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// procRoot is where a process's cgroup is looked up. Tests point it at a
+	// fake tree.
+	procRoot = "/proc"
+
+	// cgroupRoot is the cgroup v2 mount. Tests point it at a fake tree.
+	cgroupRoot = "/sys/fs/cgroup"
+)
+
+// Usage is a session cgroup's resource counters.
+type Usage struct {
+	Cgroup        string        `json:"cgroup"`                // Path under the cgroup v2 root
+	MemoryCurrent int64         `json:"memory_current"`        // Bytes in use
+	MemoryPeak    int64         `json:"memory_peak,omitempty"` // High-water mark, when the kernel tracks it
+	MemoryMax     int64         `json:"memory_max,omitempty"`  // 0 when unlimited
+	OOMKills      int64         `json:"oom_kills"`             // Processes the OOM killer took
+	Throttled     time.Duration `json:"throttled,omitempty"`   // CPU time withheld by CPUQuota
+}
+
+// MemoryPercent returns current memory as a share of the limit, or 0
+// without one.
+func (u *Usage) MemoryPercent() int {
+	if u.MemoryMax <= 0 {
+		return 0
+	}
+	return int(u.MemoryCurrent * 100 / u.MemoryMax)
+}
+
+// CgroupOf returns a process's cgroup v2 path.
+func CgroupOf(pid string) (string, error) {
+	f, err := os.Open(filepath.Join(procRoot, pid, "cgroup"))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// cgroup v2 has a single "0::<path>" line.
+		if path, ok := strings.CutPrefix(scanner.Text(), "0::"); ok {
+			return path, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("process %s is not in a cgroup v2 hierarchy", pid)
+}
+
+// ReadUsage reads the counters of the cgroup a process is in. A session
+// without limits reports its own (unlimited) cgroup, usually the tmux
+// server's.
+func ReadUsage(pid string) (*Usage, error) {
+	cg, err := CgroupOf(pid)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(cgroupRoot, filepath.FromSlash(cg))
+	u := &Usage{Cgroup: cg}
+	if u.MemoryCurrent, err = readInt(dir, "memory.current"); err != nil {
+		return nil, err
+	}
+	u.MemoryPeak, _ = readInt(dir, "memory.peak")
+	u.MemoryMax, _ = readInt(dir, "memory.max") // "max" parses as 0: unlimited
+	events, _ := readKeyed(dir, "memory.events")
+	u.OOMKills = events["oom_kill"]
+	cpu, _ := readKeyed(dir, "cpu.stat")
+	u.Throttled = time.Duration(cpu["throttled_usec"]) * time.Microsecond
+	return u, nil
+}
+
+func readInt(dir, file string) (int64, error) {
+	data, err := os.ReadFile(filepath.Join(dir, file)) //nolint:gosec // G304: cgroup interface files
+	if err != nil {
+		return 0, err
+	}
+	s := strings.TrimSpace(string(data))
+	if s == "max" {
+		return 0, nil
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// readKeyed reads a flat-keyed cgroup file ("key value" per line).
+func readKeyed(dir, file string) (map[string]int64, error) {
+	data, err := os.ReadFile(filepath.Join(dir, file)) //nolint:gosec // G304: cgroup interface files
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]int64)
+	for _, line := range strings.Split(string(data), "\n") {
+		if key, val, ok := strings.Cut(strings.TrimSpace(line), " "); ok {
+			if n, err := strconv.ParseInt(val, 10, 64); err == nil {
+				out[key] = n
+			}
+		}
+	}
+	return out, nil
+}
+
+// Alert kinds.
+const (
+	AlertOOM    = "oom"    // The OOM killer took processes in the session
+	AlertMemory = "memory" // The session is near its memory limit
+)
+
+// Alert is something the witness reports about a session's resources.
+type Alert struct {
+	Kind    string `json:"kind"`
+	Session string `json:"session"`
+	Agent   string `json:"agent"` // rig/name or rig/role
+	Detail  string `json:"detail"`
+}
+
+// State remembers what was already reported per cgroup, so each OOM kill
+// and each climb past the warning level is reported once.
+type State struct {
+	Cgroups map[string]*CgroupState `json:"cgroups"`
+}
+
+// CgroupState is what was last seen of one session cgroup.
+type CgroupState struct {
+	OOMKills int64     `json:"oom_kills"`
+	Warned   bool      `json:"warned,omitempty"`
+	SeenAt   time.Time `json:"seen_at"`
+}
+
+// StatePath is where a rig's alert state is kept.
+func StatePath(rigPath string) string {
+	return filepath.Join(rigPath, ".runtime", "resource-alerts.json")
+}
+
+// LoadState reads a rig's alert state, empty if there is none yet.
+func LoadState(rigPath string) (*State, error) {
+	s := &State{Cgroups: make(map[string]*CgroupState)}
+	data, err := os.ReadFile(StatePath(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", StatePath(rigPath), err)
+	}
+	if s.Cgroups == nil {
+		s.Cgroups = make(map[string]*CgroupState)
+	}
+	return s, nil
+}
+
+// Save writes the state, dropping cgroups not seen for a day (sessions
+// that ended).
+func (s *State) Save(rigPath string, now time.Time) error {
+	for cg, st := range s.Cgroups {
+		if now.Sub(st.SeenAt) > 24*time.Hour {
+			delete(s.Cgroups, cg)
+		}
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(StatePath(rigPath)), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(StatePath(rigPath), data, 0644) //nolint:gosec // G306: not sensitive
+}
+
+// Check compares a session's usage with what was last seen and returns the
+// alerts that are new. The first sighting of a cgroup that already had OOM
+// kills reports them too: they happened since the session started.
+func (s *State) Check(session, agent string, u *Usage, warnPercent int, now time.Time) []Alert {
+	st := s.Cgroups[u.Cgroup]
+	if st == nil {
+		st = &CgroupState{}
+		s.Cgroups[u.Cgroup] = st
+	}
+	st.SeenAt = now
+
+	var alerts []Alert
+	if u.OOMKills > st.OOMKills {
+		alerts = append(alerts, Alert{
+			Kind: AlertOOM, Session: session, Agent: agent,
+			Detail: fmt.Sprintf("OOM killer took %d process(es) at the %s memory limit", u.OOMKills-st.OOMKills, FormatBytes(u.MemoryMax)),
+		})
+		st.OOMKills = u.OOMKills
+	}
+	pct := u.MemoryPercent()
+	switch {
+	case u.MemoryMax > 0 && pct >= warnPercent && !st.Warned:
+		alerts = append(alerts, Alert{
+			Kind: AlertMemory, Session: session, Agent: agent,
+			Detail: fmt.Sprintf("using %s of its %s memory limit (%d%%)", FormatBytes(u.MemoryCurrent), FormatBytes(u.MemoryMax), pct),
+		})
+		st.Warned = true
+	case pct < warnPercent-10:
+		// Re-arm once usage has clearly come back down.
+		st.Warned = false
+	}
+	return alerts
+}
+
+// FormatBytes renders a byte count in binary units ("1.5G").
+func FormatBytes(n int64) string {
+	if n <= 0 {
+		return "unlimited"
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 3; m /= unit {
+		div *= unit
+		exp++
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(fmt.Sprintf("%.1f", float64(n)/float64(div)), "0"), ".") + string("KMGT"[exp])
+}
@@ -0,0 +1,212 @@
+// Package reslimit caps the CPU and memory of a rig's agent sessions, and
+// everything they spawn (builds, tests, language servers), so one runaway
+// process can't starve every other session on the host.
+//
+// On Linux each session's agent runs in its own transient systemd scope
+// (systemd-run --user --scope), which puts it in a cgroup v2 with
+// MemoryMax, CPUQuota and TasksMax set. The witness reads the cgroup's
+// counters to report OOM kills and sessions close to their memory limit.
+// Where systemd-run isn't usable (macOS, containers without a user
+// manager) sessions start unconstrained.
+package reslimit
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultWarnPercent is the share of the memory limit at which a session
+// is reported as close to it.
+const DefaultWarnPercent = 90
+
+// DefaultRoles are the rig roles limited when Config.Roles is empty.
+var DefaultRoles = []string{"polecat", "crew", "witness", "refinery"}
+
+// Config is the resource_limits section of a rig's settings/config.json.
+//
+//	"resource_limits": {
+//	  "memory": "8G",
+//	  "cpus": 2,
+//	  "tasks_max": 1024,
+//	  "roles": ["polecat", "crew"]
+//	}
+//
+// Limits apply to each session separately, not to the rig as a whole.
+type Config struct {
+	// Memory is the hard memory cap per session ("512M", "8G", "1.5GiB" or
+	// bytes). Past it the kernel OOM-kills a process in the session.
+	Memory string `json:"memory,omitempty"`
+	// CPUs caps CPU time per session, in cores (1.5 = 150% of one core).
+	CPUs float64 `json:"cpus,omitempty"`
+	// TasksMax caps processes and threads per session (fork bombs, runaway
+	// parallel builds).
+	TasksMax int `json:"tasks_max,omitempty"`
+	// Roles limits which rig roles are constrained; DefaultRoles if empty.
+	Roles []string `json:"roles,omitempty"`
+	// WarnPercent is the share of Memory at which the witness reports a
+	// session; DefaultWarnPercent if zero.
+	WarnPercent int `json:"warn_percent,omitempty"`
+}
+
+// IsEnabled reports whether any limit is set.
+func (c *Config) IsEnabled() bool {
+	return c != nil && (c.Memory != "" || c.CPUs > 0 || c.TasksMax > 0)
+}
+
+// Validate checks the config. A nil config is valid (no limits).
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.Memory != "" {
+		if _, err := ParseBytes(c.Memory); err != nil {
+			return fmt.Errorf("memory: %w", err)
+		}
+	}
+	if c.CPUs < 0 {
+		return fmt.Errorf("cpus must not be negative")
+	}
+	if c.TasksMax < 0 {
+		return fmt.Errorf("tasks_max must not be negative")
+	}
+	if c.WarnPercent < 0 || c.WarnPercent > 100 {
+		return fmt.Errorf("warn_percent must be between 0 and 100")
+	}
+	for _, role := range c.Roles {
+		if !isRigRole(role) {
+			return fmt.Errorf("unknown role %q (want %s)", role, strings.Join(DefaultRoles, ", "))
+		}
+	}
+	return nil
+}
+
+func isRigRole(role string) bool {
+	for _, r := range DefaultRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// AppliesTo reports whether sessions of the given role are limited.
+func (c *Config) AppliesTo(role string) bool {
+	if !c.IsEnabled() {
+		return false
+	}
+	roles := c.Roles
+	if len(roles) == 0 {
+		roles = DefaultRoles
+	}
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// GetWarnPercent returns WarnPercent or DefaultWarnPercent if unset.
+func (c *Config) GetWarnPercent() int {
+	if c == nil || c.WarnPercent <= 0 {
+		return DefaultWarnPercent
+	}
+	return c.WarnPercent
+}
+
+var bytesRe = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([KMGT]?)(I?B?)$`)
+
+// ParseBytes parses a size like "512M", "8G", "1.5GiB" or "1048576".
+// Suffixes are binary (K = 1024), as in systemd.
+func ParseBytes(s string) (int64, error) {
+	m := bytesRe.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if m == nil {
+		return 0, fmt.Errorf("invalid size %q (want e.g. 512M or 8G)", s)
+	}
+	n, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+	shift := strings.Index("KMGT", m[2]) + 1
+	if m[2] == "" {
+		shift = 0
+	}
+	bytes := int64(n * float64(int64(1)<<(10*shift)))
+	if bytes <= 0 {
+		return 0, fmt.Errorf("size %q must be positive", s)
+	}
+	return bytes, nil
+}
+
+// Available reports whether sessions can be placed in systemd scopes here.
+// The probe runs once per process. Available is a seam for tests.
+var Available = func() bool {
+	probeOnce.Do(func() {
+		if runtime.GOOS != "linux" {
+			return
+		}
+		if _, err := exec.LookPath("systemd-run"); err != nil {
+			return
+		}
+		probeOK = exec.Command("systemd-run", "--user", "--scope", "--quiet", "--collect", "true").Run() == nil
+	})
+	return probeOK
+}
+
+var (
+	probeOnce sync.Once
+	probeOK   bool
+)
+
+// unitUnsafe matches characters systemd doesn't allow in unit names.
+var unitUnsafe = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// UnitName is the scope unit for a session: gt-<rig>-<agent>-<suffix>.
+// The suffix keeps a restarted session's scope from colliding with the
+// old one while it is still being torn down.
+func UnitName(rig, agent, suffix string) string {
+	parts := []string{"gt"}
+	for _, p := range []string{rig, agent, suffix} {
+		if p = strings.Trim(unitUnsafe.ReplaceAllString(p, "-"), "-"); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, "-") + ".scope"
+}
+
+// IsSessionScope reports whether a cgroup path is a session scope made by
+// Wrapper, as opposed to the unlimited cgroup of a session started without
+// limits (or before they were configured).
+func IsSessionScope(cgroup string) bool {
+	base := path.Base(cgroup)
+	return strings.HasPrefix(base, "gt-") && strings.HasSuffix(base, ".scope")
+}
+
+// Wrapper returns the command prefix that starts a session inside a scope
+// with the configured limits, or nil when no limit is set.
+func (c *Config) Wrapper(unit string) []string {
+	if !c.IsEnabled() {
+		return nil
+	}
+	args := []string{"systemd-run", "--user", "--scope", "--quiet", "--collect", "--unit=" + unit}
+	if c.Memory != "" {
+		if n, err := ParseBytes(c.Memory); err == nil {
+			// No swap either: a session swapping at its cap is as stuck as
+			// one that was killed, and drags the host down with it.
+			args = append(args, "-p", fmt.Sprintf("MemoryMax=%d", n), "-p", "MemorySwapMax=0")
+		}
+	}
+	if c.CPUs > 0 {
+		args = append(args, "-p", fmt.Sprintf("CPUQuota=%d%%", int(c.CPUs*100+0.5)))
+	}
+	if c.TasksMax > 0 {
+		args = append(args, "-p", fmt.Sprintf("TasksMax=%d", c.TasksMax))
+	}
+	return append(args, "--")
+}
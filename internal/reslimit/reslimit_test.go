@@ -0,0 +1,140 @@
+package reslimit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBytes(t *testing.T) {
+	for in, want := range map[string]int64{
+		"1048576": 1 << 20,
+		"512M":    512 << 20,
+		"8g":      8 << 30,
+		"1.5GiB":  3 << 29,
+		"64KB":    64 << 10,
+	} {
+		if got, err := ParseBytes(in); err != nil || got != want {
+			t.Errorf("ParseBytes(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "8X", "-1G", "0"} {
+		if _, err := ParseBytes(in); err == nil {
+			t.Errorf("ParseBytes(%q) should fail", in)
+		}
+	}
+}
+
+func TestConfig(t *testing.T) {
+	var none *Config
+	if none.IsEnabled() || none.AppliesTo("polecat") || none.Wrapper("u") != nil || none.Validate() != nil {
+		t.Error("nil config should be valid and limit nothing")
+	}
+	for _, bad := range []*Config{{Memory: "lots"}, {CPUs: -1}, {WarnPercent: 150}, {Memory: "1G", Roles: []string{"mayor"}}} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v) should fail", bad)
+		}
+	}
+
+	cfg := &Config{Memory: "2G", CPUs: 1.5, TasksMax: 512, Roles: []string{"polecat"}}
+	if !cfg.AppliesTo("polecat") || cfg.AppliesTo("crew") {
+		t.Error("AppliesTo should follow Roles")
+	}
+	if !(&Config{CPUs: 1}).AppliesTo("refinery") {
+		t.Error("AppliesTo should default to every rig role")
+	}
+	got := strings.Join(cfg.Wrapper("gt-gastown-Toast-1.scope"), " ")
+	want := "systemd-run --user --scope --quiet --collect --unit=gt-gastown-Toast-1.scope -p MemoryMax=2147483648 -p MemorySwapMax=0 -p CPUQuota=150% -p TasksMax=512 --"
+	if got != want {
+		t.Errorf("Wrapper =\n  %s\nwant\n  %s", got, want)
+	}
+}
+
+func TestUnitName(t *testing.T) {
+	unit := UnitName("gastown", "crew/max power", "1700000000")
+	if unit != "gt-gastown-crew-max-power-1700000000.scope" {
+		t.Errorf("UnitName = %q", unit)
+	}
+	if !IsSessionScope("/user.slice/user-1000.slice/user@1000.service/app.slice/" + unit) {
+		t.Error("IsSessionScope should match a session scope")
+	}
+	if IsSessionScope("/user.slice/user-1000.slice/user@1000.service/app.slice/tmux-spawn-1.scope") {
+		t.Error("IsSessionScope should not match the tmux server's scope")
+	}
+}
+
+func TestReadUsageAndCheck(t *testing.T) {
+	proc, cgroups := t.TempDir(), t.TempDir()
+	procRoot, cgroupRoot = proc, cgroups
+	t.Cleanup(func() { procRoot, cgroupRoot = "/proc", "/sys/fs/cgroup" })
+
+	const cg = "/user.slice/gt-gastown-Toast-1.scope"
+	write := func(file, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(filepath.Join(proc, "42", "cgroup"), "0::"+cg+"\n")
+	dir := filepath.Join(cgroups, cg)
+	write(filepath.Join(dir, "memory.current"), "966367642\n")
+	write(filepath.Join(dir, "memory.max"), "1073741824\n")
+	write(filepath.Join(dir, "memory.events"), "low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n")
+	write(filepath.Join(dir, "cpu.stat"), "usage_usec 900\nthrottled_usec 2000000\n")
+
+	u, err := ReadUsage("42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Cgroup != cg || u.MemoryPercent() != 90 || u.OOMKills != 1 || u.Throttled != 2*time.Second {
+		t.Fatalf("ReadUsage = %+v", u)
+	}
+
+	rig := t.TempDir()
+	state, err := LoadState(rig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	alerts := state.Check("gt-gastown-Toast", "gastown/Toast", u, 90, now)
+	if len(alerts) != 2 || alerts[0].Kind != AlertOOM || alerts[1].Kind != AlertMemory {
+		t.Fatalf("first check = %+v", alerts)
+	}
+	if err := state.Save(rig, now); err != nil {
+		t.Fatal(err)
+	}
+	if state, err = LoadState(rig); err != nil {
+		t.Fatal(err)
+	}
+	if alerts := state.Check("gt-gastown-Toast", "gastown/Toast", u, 90, now); len(alerts) != 0 {
+		t.Errorf("repeat check alerted again: %+v", alerts)
+	}
+
+	// Usage falls well below the threshold, then climbs back: alert again.
+	u.MemoryCurrent = u.MemoryMax / 2
+	state.Check("gt-gastown-Toast", "gastown/Toast", u, 90, now)
+	u.MemoryCurrent = u.MemoryMax
+	if alerts := state.Check("gt-gastown-Toast", "gastown/Toast", u, 90, now); len(alerts) != 1 || alerts[0].Kind != AlertMemory {
+		t.Errorf("re-armed check = %+v", alerts)
+	}
+
+	if err := state.Save(rig, now.Add(48*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if state, _ = LoadState(rig); len(state.Cgroups) != 0 {
+		t.Errorf("stale cgroups not pruned: %+v", state.Cgroups)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[int64]string{0: "unlimited", 512: "512B", 1536: "1.5K", 8 << 30: "8G"} {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
@@ -0,0 +1,469 @@
+// Package retro builds the retrospective for a completed convoy: where time
+// and money went, which beads stalled or bounced in review, what escalated,
+// and a list of findings that can be turned into improvement beads.
+package retro
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+const (
+	// stallFactor marks a bead as stalled when its cycle time is this many
+	// times the convoy median (and at least minStall).
+	stallFactor = 2.0
+	minStall    = time.Hour
+
+	// costShare marks a bead as expensive when it used this share of the
+	// convoy's cost (with at least 3 costed beads).
+	costShare = 0.4
+
+	// highBounceRate is the review bounce rate worth a finding of its own.
+	highBounceRate = 0.3
+)
+
+// Bead is one tracked bead and what happened to it.
+type Bead struct {
+	ID            string        `json:"id"`
+	Title         string        `json:"title"`
+	Status        string        `json:"status"`
+	Assignee      string        `json:"assignee,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	ClosedAt      time.Time     `json:"closed_at,omitempty"`
+	Cycle         time.Duration `json:"cycle_ns"` // first sling (or creation) → close (or report time)
+	Slings        int           `json:"slings"`
+	Stalls        int           `json:"stalls"`
+	MergeFailures int           `json:"merge_failures"`
+	LastFailure   string        `json:"last_failure,omitempty"`
+	Merged        bool          `json:"merged"`
+	CostUSD       float64       `json:"cost_usd"`
+}
+
+// Escalation is an escalation raised about one of the convoy's beads.
+type Escalation struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Severity string `json:"severity,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Related  string `json:"related"`
+	Closed   bool   `json:"closed"`
+}
+
+// Cost is one session's cost attributed to a work item.
+type Cost struct {
+	WorkItem string
+	CostUSD  float64
+}
+
+// Finding is one thing worth improving. Findings become improvement beads.
+type Finding struct {
+	Kind   string `json:"kind"` // stall, cost, bounce, rework, escalation
+	Bead   string `json:"bead,omitempty"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+// Input is everything the retrospective is built from.
+type Input struct {
+	ConvoyID    string
+	Title       string
+	Status      string
+	CreatedAt   time.Time
+	ClosedAt    time.Time
+	Beads       []Bead // ID, title, status, assignee and times filled in
+	Events      []events.Event
+	Costs       []Cost
+	Escalations []Escalation
+}
+
+// Report is a convoy retrospective.
+type Report struct {
+	ConvoyID     string        `json:"convoy_id"`
+	Title        string        `json:"title"`
+	Status       string        `json:"status"`
+	GeneratedAt  time.Time     `json:"generated_at"`
+	CreatedAt    time.Time     `json:"created_at"`
+	ClosedAt     time.Time     `json:"closed_at,omitempty"`
+	Duration     time.Duration `json:"duration_ns"`
+	MedianCycle  time.Duration `json:"median_cycle_ns"`
+	TotalCostUSD float64       `json:"total_cost_usd"`
+	Reviewed     int           `json:"reviewed"` // beads that reached the merge queue
+	Bounced      int           `json:"bounced"`  // of those, beads with at least one failed merge
+	Beads        []Bead        `json:"beads"`
+	Escalations  []Escalation  `json:"escalations,omitempty"`
+	Findings     []Finding     `json:"findings,omitempty"`
+}
+
+// BounceRate is the share of reviewed beads that bounced at least once.
+func (r *Report) BounceRate() float64 {
+	if r.Reviewed == 0 {
+		return 0
+	}
+	return float64(r.Bounced) / float64(r.Reviewed)
+}
+
+// Build computes the retrospective at now.
+func Build(in Input, now time.Time) *Report {
+	r := &Report{
+		ConvoyID:    in.ConvoyID,
+		Title:       in.Title,
+		Status:      in.Status,
+		GeneratedAt: now,
+		CreatedAt:   in.CreatedAt,
+		ClosedAt:    in.ClosedAt,
+		Escalations: in.Escalations,
+	}
+	end := now
+	if !in.ClosedAt.IsZero() {
+		end = in.ClosedAt
+	}
+	if !in.CreatedAt.IsZero() {
+		r.Duration = end.Sub(in.CreatedAt)
+	}
+
+	byID := make(map[string]*Bead, len(in.Beads))
+	r.Beads = make([]Bead, len(in.Beads))
+	copy(r.Beads, in.Beads)
+	for i := range r.Beads {
+		byID[r.Beads[i].ID] = &r.Beads[i]
+	}
+
+	firstSling := attributeEvents(in.Events, r.Beads, byID)
+
+	for _, c := range in.Costs {
+		if b := byID[c.WorkItem]; b != nil {
+			b.CostUSD += c.CostUSD
+			r.TotalCostUSD += c.CostUSD
+		}
+	}
+
+	var cycles []time.Duration
+	for i := range r.Beads {
+		b := &r.Beads[i]
+		start := b.CreatedAt
+		if t, ok := firstSling[b.ID]; ok {
+			start = t
+		}
+		stop := b.ClosedAt
+		if stop.IsZero() {
+			stop = end
+		}
+		if !start.IsZero() && stop.After(start) {
+			b.Cycle = stop.Sub(start)
+			cycles = append(cycles, b.Cycle)
+		}
+		if b.Merged || b.MergeFailures > 0 {
+			r.Reviewed++
+			if b.MergeFailures > 0 {
+				r.Bounced++
+			}
+		}
+	}
+	r.MedianCycle = median(cycles)
+	r.Findings = findings(r)
+	return r
+}
+
+// attributeEvents tallies slings, stalls and merge outcomes onto beads and
+// returns when each bead was first slung. Stalls are attributed through the
+// bead the stalled agent last hooked; merges through the bead ID in the
+// branch name.
+func attributeEvents(evts []events.Event, beads []Bead, byID map[string]*Bead) map[string]time.Time {
+	firstSling := make(map[string]time.Time)
+	hooked := make(map[string]string) // agent address → bead
+	for _, e := range evts {
+		ts, _ := time.Parse(time.RFC3339, e.Timestamp)
+		bead, _ := e.Payload["bead"].(string)
+		switch e.Type {
+		case events.TypeSling:
+			if b := byID[bead]; b != nil {
+				b.Slings++
+				if _, ok := firstSling[bead]; !ok && !ts.IsZero() {
+					firstSling[bead] = ts
+				}
+			}
+		case events.TypeHook:
+			if byID[bead] != nil {
+				hooked[e.Actor] = bead
+			}
+		case events.TypeStallClassified:
+			rig, _ := e.Payload["rig"].(string)
+			polecat, _ := e.Payload["polecat"].(string)
+			if b := byID[hooked[rig+"/polecats/"+polecat]]; b != nil {
+				b.Stalls++
+			}
+		case events.TypeMerged, events.TypeMergeFailed:
+			branch, _ := e.Payload["branch"].(string)
+			b := beadForBranch(branch, beads, byID)
+			if b == nil {
+				continue
+			}
+			if e.Type == events.TypeMerged {
+				b.Merged = true
+			} else {
+				b.MergeFailures++
+				if reason, _ := e.Payload["reason"].(string); reason != "" {
+					b.LastFailure = reason
+				}
+			}
+		}
+	}
+	return firstSling
+}
+
+// beadForBranch finds the convoy bead whose ID appears in a polecat branch
+// name (e.g. polecat/nux/gt-abc@mk123).
+func beadForBranch(branch string, beads []Bead, byID map[string]*Bead) *Bead {
+	if branch == "" {
+		return nil
+	}
+	var best *Bead
+	for i := range beads {
+		id := beads[i].ID
+		if strings.Contains(branch, id) && (best == nil || len(id) > len(best.ID)) {
+			best = byID[id]
+		}
+	}
+	return best
+}
+
+// findings turns the numbers into improvement suggestions, most actionable
+// first: escalations, bounces, stalls, rework, cost.
+func findings(r *Report) []Finding {
+	var out []Finding
+	for _, e := range r.Escalations {
+		detail := fmt.Sprintf("Escalation %s about %s", e.ID, e.Related)
+		if e.Severity != "" {
+			detail += fmt.Sprintf(" (%s)", e.Severity)
+		}
+		if e.Reason != "" {
+			detail += ": " + e.Reason
+		}
+		out = append(out, Finding{
+			Kind:   "escalation",
+			Bead:   e.Related,
+			Title:  fmt.Sprintf("Address the cause of escalation on %s", e.Related),
+			Detail: detail + ". Fix the root cause so the next convoy doesn't escalate the same way.",
+		})
+	}
+
+	if rate := r.BounceRate(); r.Reviewed >= 3 && rate >= highBounceRate {
+		out = append(out, Finding{
+			Kind:  "bounce",
+			Title: fmt.Sprintf("Reduce review bounces (%.0f%% of %d beads bounced)", rate*100, r.Reviewed),
+			Detail: "Many beads failed the merge queue at least once. Tighten acceptance criteria, " +
+				"run the rig's tests before gt done, or enable verify_done for the rig.",
+		})
+	}
+	for _, b := range r.Beads {
+		if b.MergeFailures >= 2 {
+			detail := fmt.Sprintf("%s failed the merge queue %d times", b.ID, b.MergeFailures)
+			if b.LastFailure != "" {
+				detail += "; last failure: " + b.LastFailure
+			}
+			out = append(out, Finding{Kind: "bounce", Bead: b.ID,
+				Title:  fmt.Sprintf("Find why %s kept bouncing in review", b.ID),
+				Detail: detail + "."})
+		}
+	}
+
+	for _, b := range r.Beads {
+		stalledLong := r.MedianCycle > 0 && b.Cycle >= minStall &&
+			float64(b.Cycle) >= stallFactor*float64(r.MedianCycle)
+		if !stalledLong && b.Stalls == 0 {
+			continue
+		}
+		var why []string
+		if stalledLong {
+			why = append(why, fmt.Sprintf("took %s against a median of %s", FormatDuration(b.Cycle), FormatDuration(r.MedianCycle)))
+		}
+		if b.Stalls > 0 {
+			why = append(why, fmt.Sprintf("its worker stalled %d time(s)", b.Stalls))
+		}
+		out = append(out, Finding{Kind: "stall", Bead: b.ID,
+			Title:  fmt.Sprintf("Investigate why %s stalled", b.ID),
+			Detail: fmt.Sprintf("%s (%s) %s.", b.ID, b.Title, strings.Join(why, " and "))})
+	}
+
+	for _, b := range r.Beads {
+		if b.Slings > 1 {
+			out = append(out, Finding{Kind: "rework", Bead: b.ID,
+				Title:  fmt.Sprintf("Find why %s needed %d slings", b.ID, b.Slings),
+				Detail: fmt.Sprintf("%s (%s) was slung %d times before it finished; check whether it was underspecified or too large.", b.ID, b.Title, b.Slings)})
+		}
+	}
+
+	costed := 0
+	for _, b := range r.Beads {
+		if b.CostUSD > 0 {
+			costed++
+		}
+	}
+	if costed >= 3 {
+		for _, b := range r.Beads {
+			if b.CostUSD >= costShare*r.TotalCostUSD {
+				out = append(out, Finding{Kind: "cost", Bead: b.ID,
+					Title: fmt.Sprintf("Review the cost of %s", b.ID),
+					Detail: fmt.Sprintf("%s (%s) cost $%.2f, %.0f%% of the convoy's $%.2f.",
+						b.ID, b.Title, b.CostUSD, 100*b.CostUSD/r.TotalCostUSD, r.TotalCostUSD)})
+			}
+		}
+	}
+	return out
+}
+
+// Markdown renders the retrospective for reading, mail and the saved artifact.
+func (r *Report) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Retrospective: %s", r.ConvoyID)
+	if r.Title != "" {
+		fmt.Fprintf(&sb, " — %s", r.Title)
+	}
+	sb.WriteString("\n\n")
+	if r.Status != "closed" {
+		fmt.Fprintf(&sb, "_Convoy is still %s; this retrospective is partial._\n\n", r.Status)
+	}
+
+	closed := 0
+	for _, b := range r.Beads {
+		if b.Status == "closed" {
+			closed++
+		}
+	}
+	fmt.Fprintf(&sb, "- Beads: %d/%d closed\n", closed, len(r.Beads))
+	if r.Duration > 0 {
+		fmt.Fprintf(&sb, "- Duration: %s (median bead %s)\n", FormatDuration(r.Duration), FormatDuration(r.MedianCycle))
+	}
+	if r.TotalCostUSD > 0 {
+		fmt.Fprintf(&sb, "- Cost: $%.2f\n", r.TotalCostUSD)
+	}
+	if r.Reviewed > 0 {
+		fmt.Fprintf(&sb, "- Review bounce rate: %.0f%% (%d of %d)\n", r.BounceRate()*100, r.Bounced, r.Reviewed)
+	}
+	fmt.Fprintf(&sb, "- Escalations: %d\n", len(r.Escalations))
+
+	sb.WriteString("\n## Where time and cost went\n\n")
+	beads := make([]Bead, len(r.Beads))
+	copy(beads, r.Beads)
+	sort.SliceStable(beads, func(i, j int) bool { return beads[i].Cycle > beads[j].Cycle })
+	for _, b := range beads {
+		fmt.Fprintf(&sb, "- %s %s — %s", b.ID, b.Title, b.Status)
+		if b.Cycle > 0 {
+			fmt.Fprintf(&sb, ", %s", FormatDuration(b.Cycle))
+		}
+		if b.CostUSD > 0 {
+			fmt.Fprintf(&sb, ", $%.2f", b.CostUSD)
+		}
+		if b.MergeFailures > 0 {
+			fmt.Fprintf(&sb, ", %d merge failure(s)", b.MergeFailures)
+		}
+		if b.Stalls > 0 {
+			fmt.Fprintf(&sb, ", %d stall(s)", b.Stalls)
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(r.Escalations) > 0 {
+		sb.WriteString("\n## Escalations\n\n")
+		for _, e := range r.Escalations {
+			state := "open"
+			if e.Closed {
+				state = "closed"
+			}
+			fmt.Fprintf(&sb, "- %s [%s, %s] on %s: %s\n", e.ID, e.Severity, state, e.Related, e.Title)
+		}
+	}
+
+	sb.WriteString("\n## Findings\n\n")
+	if len(r.Findings) == 0 {
+		sb.WriteString("Nothing stood out.\n")
+	}
+	for i, f := range r.Findings {
+		fmt.Fprintf(&sb, "%d. %s\n   %s\n", i+1, f.Title, f.Detail)
+	}
+	return sb.String()
+}
+
+// Dir returns the directory retrospectives are saved in.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "retros")
+}
+
+// Save writes the report as <convoy>.json and <convoy>.md and returns the
+// markdown path.
+func (r *Report) Save(townRoot string) (string, error) {
+	base := filepath.Join(Dir(townRoot), r.ConvoyID)
+	if err := util.EnsureDirAndWriteJSON(base+".json", r); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(base+".md", []byte(r.Markdown()), 0644); err != nil { //nolint:gosec // G306: report is non-sensitive
+		return "", err
+	}
+	return base + ".md", nil
+}
+
+// LoadEvents reads the town's raw events log, keeping events at or after
+// since. A missing log yields no events.
+func LoadEvents(townRoot string, since time.Time) ([]events.Event, error) {
+	f, err := os.Open(filepath.Join(townRoot, events.EventsFile)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []events.Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e events.Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if ts, err := time.Parse(time.RFC3339, e.Timestamp); err == nil && ts.Before(since) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out, scanner.Err()
+}
+
+// FormatDuration renders d compactly: 45m, 3h20m, 2d4h.
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(math.Round(d.Minutes())))
+	case d < 24*time.Hour:
+		h := int(d.Hours())
+		return fmt.Sprintf("%dh%02dm", h, int(d.Minutes())-h*60)
+	default:
+		days := int(d.Hours()) / 24
+		return fmt.Sprintf("%dd%dh", days, int(d.Hours())-days*24)
+	}
+}
+
+func median(ds []time.Duration) time.Duration {
+	if len(ds) == 0 {
+		return 0
+	}
+	sorted := make([]time.Duration, len(ds))
+	copy(sorted, ds)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,164 @@
+package retro
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+var t0 = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func ev(at time.Duration, typ, actor string, payload map[string]interface{}) events.Event {
+	return events.Event{Timestamp: t0.Add(at).Format(time.RFC3339), Type: typ, Actor: actor, Payload: payload}
+}
+
+func testInput() Input {
+	closed := func(h int) time.Time { return t0.Add(time.Duration(h) * time.Hour) }
+	return Input{
+		ConvoyID:  "hq-cv-1",
+		Title:     "Auth rework",
+		Status:    "closed",
+		CreatedAt: t0,
+		ClosedAt:  closed(12),
+		Beads: []Bead{
+			{ID: "gt-a", Title: "Token refresh", Status: "closed", CreatedAt: t0, ClosedAt: closed(1)},
+			{ID: "gt-b", Title: "Session store", Status: "closed", CreatedAt: t0, ClosedAt: closed(2)},
+			{ID: "gt-c", Title: "Login form", Status: "closed", CreatedAt: t0, ClosedAt: closed(2)},
+			{ID: "gt-d", Title: "SSO", Status: "closed", CreatedAt: t0, ClosedAt: closed(10)},
+		},
+		Events: []events.Event{
+			ev(0, events.TypeSling, "mayor", events.SlingPayload("gt-d", "gastown")),
+			ev(time.Minute, events.TypeHook, "gastown/polecats/nux", events.HookPayload("gt-d")),
+			ev(3*time.Hour, events.TypeStallClassified, "witness", events.StallPayload("gastown", "nux", "idle", "nudge")),
+			ev(4*time.Hour, events.TypeSling, "mayor", events.SlingPayload("gt-d", "gastown")),
+			ev(time.Hour, events.TypeMergeFailed, "refinery", events.MergePayload("mr-1", "toast", "polecat/toast/gt-b@x", "tests failed")),
+			ev(90*time.Minute, events.TypeMergeFailed, "refinery", events.MergePayload("mr-2", "toast", "polecat/toast/gt-b@y", "conflict")),
+			ev(2*time.Hour, events.TypeMerged, "refinery", events.MergePayload("mr-3", "toast", "polecat/toast/gt-b@z", "")),
+			ev(time.Hour, events.TypeMerged, "refinery", events.MergePayload("mr-4", "ace", "polecat/ace/gt-a@z", "")),
+			ev(2*time.Hour, events.TypeMerged, "refinery", events.MergePayload("mr-5", "max", "polecat/max/gt-c@z", "")),
+			ev(time.Hour, events.TypeMerged, "refinery", events.MergePayload("mr-6", "max", "polecat/max/gt-zzz@z", "")),
+		},
+		Costs: []Cost{
+			{WorkItem: "gt-a", CostUSD: 1}, {WorkItem: "gt-b", CostUSD: 2},
+			{WorkItem: "gt-c", CostUSD: 1}, {WorkItem: "gt-d", CostUSD: 6},
+			{WorkItem: "gt-other", CostUSD: 50},
+		},
+		Escalations: []Escalation{{ID: "hq-e1", Title: "SSO provider down", Severity: "high", Reason: "no sandbox", Related: "gt-d"}},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	r := Build(testInput(), t0.Add(24*time.Hour))
+
+	if r.Duration != 12*time.Hour {
+		t.Errorf("Duration = %v, want 12h", r.Duration)
+	}
+	if r.TotalCostUSD != 10 {
+		t.Errorf("TotalCostUSD = %v, want 10 (untracked work excluded)", r.TotalCostUSD)
+	}
+	if r.Reviewed != 3 || r.Bounced != 1 {
+		t.Errorf("Reviewed/Bounced = %d/%d, want 3/1", r.Reviewed, r.Bounced)
+	}
+	d := r.Beads[3]
+	if d.Slings != 2 || d.Stalls != 1 || d.Cycle != 10*time.Hour {
+		t.Errorf("gt-d = %+v", d)
+	}
+	if b := r.Beads[1]; b.MergeFailures != 2 || b.LastFailure != "conflict" || !b.Merged {
+		t.Errorf("gt-b = %+v", b)
+	}
+
+	kinds := map[string]string{}
+	for _, f := range r.Findings {
+		kinds[f.Kind+":"+f.Bead] = f.Title
+	}
+	for _, want := range []string{"escalation:gt-d", "bounce:", "bounce:gt-b", "stall:gt-d", "rework:gt-d", "cost:gt-d"} {
+		if _, ok := kinds[want]; !ok {
+			t.Errorf("missing finding %s; got %v", want, kinds)
+		}
+	}
+	if len(r.Findings) != 6 {
+		t.Errorf("got %d findings, want 6: %v", len(r.Findings), kinds)
+	}
+	if r.Findings[0].Kind != "escalation" {
+		t.Errorf("escalations should come first, got %s", r.Findings[0].Kind)
+	}
+}
+
+func TestBuildHighBounceRate(t *testing.T) {
+	in := Input{ConvoyID: "hq-cv-2", Status: "closed"}
+	for _, id := range []string{"gt-a", "gt-b", "gt-c"} {
+		in.Beads = append(in.Beads, Bead{ID: id, Status: "closed"})
+		in.Events = append(in.Events, ev(0, events.TypeMergeFailed, "refinery", events.MergePayload("mr", "x", "polecat/x/"+id, "lint")))
+	}
+	r := Build(in, t0)
+	if r.BounceRate() != 1 || len(r.Findings) != 1 || !strings.Contains(r.Findings[0].Title, "100% of 3") {
+		t.Errorf("findings = %+v", r.Findings)
+	}
+}
+
+func TestBeadForBranchPrefersLongestID(t *testing.T) {
+	beads := []Bead{{ID: "gt-a"}, {ID: "gt-ab"}}
+	byID := map[string]*Bead{"gt-a": &beads[0], "gt-ab": &beads[1]}
+	if b := beadForBranch("polecat/nux/gt-ab@x", beads, byID); b == nil || b.ID != "gt-ab" {
+		t.Errorf("beadForBranch = %+v, want gt-ab", b)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	md := Build(testInput(), t0.Add(24*time.Hour)).Markdown()
+	for _, want := range []string{"# Retrospective: hq-cv-1 — Auth rework", "4/4 closed", "$10.00", "33% (1 of 3)", "hq-e1 [high, open] on gt-d", "## Findings"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Index(md, "gt-d SSO") > strings.Index(md, "gt-a Token") {
+		t.Error("slowest bead should be listed first")
+	}
+
+	open := Build(Input{ConvoyID: "hq-cv-3", Status: "open"}, t0).Markdown()
+	if !strings.Contains(open, "partial") || !strings.Contains(open, "Nothing stood out") {
+		t.Errorf("open convoy markdown:\n%s", open)
+	}
+}
+
+func TestSaveAndLoadEvents(t *testing.T) {
+	town := t.TempDir()
+	path, err := Build(Input{ConvoyID: "hq-cv-1", Status: "closed"}, t0).Save(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(strings.TrimSuffix(path, ".md") + ".json"); err != nil {
+		t.Errorf("json report not saved: %v", err)
+	}
+
+	if evts, err := LoadEvents(town, t0); err != nil || evts != nil {
+		t.Errorf("missing log: %v, %v", evts, err)
+	}
+	log := `{"ts":"2026-02-28T09:00:00Z","type":"sling"}
+not json
+{"ts":"2026-03-01T10:00:00Z","type":"done"}
+`
+	if err := os.WriteFile(filepath.Join(town, events.EventsFile), []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+	evts, err := LoadEvents(town, t0)
+	if err != nil || len(evts) != 1 || evts[0].Type != "done" {
+		t.Errorf("LoadEvents = %+v, %v", evts, err)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		45 * time.Minute:             "45m",
+		3*time.Hour + 20*time.Minute: "3h20m",
+		52 * time.Hour:               "2d4h",
+	} {
+		if got := FormatDuration(d); got != want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package rig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// CloneConfigDirs are the rig directories holding configuration that gt
+// rig clone copies into an experiment rig and can later merge back.
+var CloneConfigDirs = []string{"settings", "roles"}
+
+// CloneInfo records where an experiment rig was cloned from. Base is the
+// source's configuration at clone time (or at the last merge), so changes
+// made in the clone can be told apart from changes made in the source.
+type CloneInfo struct {
+	Source  string            `json:"source"`
+	Branch  string            `json:"branch,omitempty"`
+	Created time.Time         `json:"created"`
+	Base    map[string]string `json:"base"` // Rig-relative path → content
+}
+
+// Config change kinds.
+const (
+	ChangeAdded    = "added"
+	ChangeModified = "modified"
+	ChangeRemoved  = "removed"
+)
+
+// ConfigChange is one configuration file the clone changed. Conflict is
+// set when the source changed the same file since the clone's base.
+type ConfigChange struct {
+	Path     string `json:"path"`
+	Kind     string `json:"kind"`
+	Conflict bool   `json:"conflict,omitempty"`
+}
+
+// CloneInfoPath returns where a cloned rig records its origin.
+func CloneInfoPath(rigPath string) string {
+	return filepath.Join(rigPath, "clone.json")
+}
+
+// LoadCloneInfo reads a cloned rig's origin. It returns an error
+// satisfying os.IsNotExist for rigs that are not clones.
+func LoadCloneInfo(rigPath string) (*CloneInfo, error) {
+	data, err := os.ReadFile(CloneInfoPath(rigPath)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return nil, err
+	}
+	var info CloneInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", CloneInfoPath(rigPath), err)
+	}
+	if info.Base == nil {
+		info.Base = map[string]string{}
+	}
+	return &info, nil
+}
+
+// SaveCloneInfo writes a cloned rig's origin.
+func SaveCloneInfo(rigPath string, info *CloneInfo) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(CloneInfoPath(rigPath), append(data, '\n'), 0644) //nolint:gosec // G306: config file
+}
+
+// ReadConfigFiles returns the rig's configuration files under
+// CloneConfigDirs, keyed by rig-relative path.
+func ReadConfigFiles(rigPath string) (map[string]string, error) {
+	files := map[string]string{}
+	for _, dir := range CloneConfigDirs {
+		root := filepath.Join(rigPath, dir)
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) && path == root {
+					return filepath.SkipDir
+				}
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			data, err := os.ReadFile(path) //nolint:gosec // G304: walking the rig's own config
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(rigPath, path)
+			files[filepath.ToSlash(rel)] = string(data)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", root, err)
+		}
+	}
+	return files, nil
+}
+
+// CopyConfig copies the source rig's configuration files into dst,
+// overwriting what is there, and returns what was copied.
+func CopyConfig(srcRigPath, dstRigPath string) (map[string]string, error) {
+	files, err := ReadConfigFiles(srcRigPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeConfigFiles(dstRigPath, files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// DiffClone lists the configuration changes made in a clone since its
+// base, marking those the source has also changed.
+func DiffClone(info *CloneInfo, clone, source map[string]string) []ConfigChange {
+	paths := map[string]bool{}
+	for p := range info.Base {
+		paths[p] = true
+	}
+	for p := range clone {
+		paths[p] = true
+	}
+
+	var changes []ConfigChange
+	for p := range paths {
+		base, inBase := info.Base[p]
+		cur, inClone := clone[p]
+		var kind string
+		switch {
+		case inClone && !inBase:
+			kind = ChangeAdded
+		case !inClone && inBase:
+			kind = ChangeRemoved
+		case cur != base:
+			kind = ChangeModified
+		default:
+			continue
+		}
+		src, inSource := source[p]
+		conflict := inSource != inBase || src != base
+		if conflict && inSource == inClone && src == cur {
+			conflict = false // Both sides made the same change
+		}
+		changes = append(changes, ConfigChange{Path: p, Kind: kind, Conflict: conflict})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// MergeClone applies a clone's configuration changes to the source rig.
+// Conflicting changes are skipped unless force is set. The clone's base is
+// moved forward for every applied change, so the next diff shows only
+// what changed afterwards. It returns the applied and skipped changes.
+func MergeClone(sourceRigPath, cloneRigPath string, force bool) (applied, skipped []ConfigChange, err error) {
+	info, err := LoadCloneInfo(cloneRigPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	clone, err := ReadConfigFiles(cloneRigPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	source, err := ReadConfigFiles(sourceRigPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, c := range DiffClone(info, clone, source) {
+		if c.Conflict && !force {
+			skipped = append(skipped, c)
+			continue
+		}
+		path := filepath.Join(sourceRigPath, filepath.FromSlash(c.Path))
+		if c.Kind == ChangeRemoved {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return applied, skipped, err
+			}
+			delete(info.Base, c.Path)
+		} else {
+			if err := writeConfigFiles(sourceRigPath, map[string]string{c.Path: clone[c.Path]}); err != nil {
+				return applied, skipped, err
+			}
+			info.Base[c.Path] = clone[c.Path]
+		}
+		applied = append(applied, c)
+	}
+	if len(applied) > 0 {
+		if err := SaveCloneInfo(cloneRigPath, info); err != nil {
+			return applied, skipped, err
+		}
+	}
+	return applied, skipped, nil
+}
+
+func writeConfigFiles(rigPath string, files map[string]string) error {
+	for rel, content := range files {
+		path := filepath.Join(rigPath, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil { //nolint:gosec // G306: config file
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package rig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeRigFile(t *testing.T, rigPath, rel, content string) {
+	t.Helper()
+	path := filepath.Join(rigPath, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCloneDiffAndMerge(t *testing.T) {
+	src, clone := t.TempDir(), t.TempDir()
+	writeRigFile(t, src, "settings/config.json", `{"a":1}`)
+	writeRigFile(t, src, "roles/polecat.toml", "v1")
+	writeRigFile(t, src, "roles/witness.toml", "w1")
+	writeRigFile(t, src, "config.json", "identity is not copied")
+
+	base, err := CopyConfig(src, clone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(base) != 3 {
+		t.Fatalf("copied %v", base)
+	}
+	if _, err := os.Stat(filepath.Join(clone, "config.json")); !os.IsNotExist(err) {
+		t.Error("rig identity config should not be copied")
+	}
+	if err := SaveCloneInfo(clone, &CloneInfo{Source: "src", Base: base}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Clone changes config.json, adds a role and drops another; the source
+	// meanwhile edits polecat.toml, which the clone also edits.
+	writeRigFile(t, clone, "settings/config.json", `{"a":2}`)
+	writeRigFile(t, clone, "roles/refinery.toml", "r1")
+	_ = os.Remove(filepath.Join(clone, "roles", "witness.toml"))
+	writeRigFile(t, clone, "roles/polecat.toml", "v2-clone")
+	writeRigFile(t, src, "roles/polecat.toml", "v2-source")
+
+	info, _ := LoadCloneInfo(clone)
+	cloneFiles, _ := ReadConfigFiles(clone)
+	srcFiles, _ := ReadConfigFiles(src)
+	changes := DiffClone(info, cloneFiles, srcFiles)
+	want := map[string]ConfigChange{
+		"roles/polecat.toml":   {Path: "roles/polecat.toml", Kind: ChangeModified, Conflict: true},
+		"roles/refinery.toml":  {Path: "roles/refinery.toml", Kind: ChangeAdded},
+		"roles/witness.toml":   {Path: "roles/witness.toml", Kind: ChangeRemoved},
+		"settings/config.json": {Path: "settings/config.json", Kind: ChangeModified},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %+v", changes)
+	}
+	for _, c := range changes {
+		if want[c.Path] != c {
+			t.Errorf("change %+v, want %+v", c, want[c.Path])
+		}
+	}
+
+	applied, skipped, err := MergeClone(src, clone, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 3 || len(skipped) != 1 || skipped[0].Path != "roles/polecat.toml" {
+		t.Fatalf("applied %+v skipped %+v", applied, skipped)
+	}
+	srcFiles, _ = ReadConfigFiles(src)
+	if srcFiles["settings/config.json"] != `{"a":2}` || srcFiles["roles/refinery.toml"] != "r1" || srcFiles["roles/polecat.toml"] != "v2-source" {
+		t.Errorf("source after merge = %v", srcFiles)
+	}
+	if _, ok := srcFiles["roles/witness.toml"]; ok {
+		t.Error("removal was not applied")
+	}
+
+	// Applied changes are not offered again; the conflict still is.
+	info, _ = LoadCloneInfo(clone)
+	cloneFiles, _ = ReadConfigFiles(clone)
+	if changes := DiffClone(info, cloneFiles, srcFiles); len(changes) != 1 || !changes[0].Conflict {
+		t.Errorf("after merge, changes = %+v", changes)
+	}
+	if _, _, err := MergeClone(src, clone, true); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(src, "roles", "polecat.toml")); string(data) != "v2-clone" {
+		t.Errorf("--force merge left %q", data)
+	}
+}
@@ -98,6 +98,17 @@ type RigConfig struct {
 	// PolecatNames optionally specifies fixed names (overrides theme-based naming).
 	PolecatPoolSize int      `json:"polecat_pool_size,omitempty"`
 	PolecatNames    []string `json:"polecat_names,omitempty"`
+
+	// Monorepo is set when the rig owns one subdirectory of a repository
+	// shared with other rigs.
+	Monorepo *MonorepoConfig `json:"monorepo,omitempty"`
+
+	// Sparse configures sparse checkout profiles for polecat worktrees.
+	Sparse *SparseConfig `json:"sparse,omitempty"`
+
+	// Remotes are git remotes besides origin, each with a push policy
+	// (e.g. a fork that takes polecat branches). See gt rig set-remote.
+	Remotes Remotes `json:"remotes,omitempty"`
 }
 
 // BeadsConfig represents beads configuration for the rig.
@@ -178,6 +189,10 @@ func (m *Manager) loadRig(name string, entry config.RigEntry) (*Rig, error) {
 		LocalRepo: entry.LocalRepo,
 		Config:    entry.BeadsConfig,
 	}
+	if cfg, err := LoadRigConfig(rigPath); err == nil {
+		rig.Monorepo = cfg.Monorepo
+		rig.Remotes = cfg.Remotes
+	}
 
 	// Scan for polecats
 	polecatsDir := filepath.Join(rigPath, "polecats")
@@ -237,6 +252,8 @@ type AddRigOptions struct {
 	SkipDoltCheck   bool     // Skip Dolt server availability check (for tests with mocked beads)
 	CloneFilter     string   // Git clone filter spec (e.g. "blob:none", "tree:0") for partial clones
 	SparseCheckout  []string // Sparse checkout paths (cone mode); empty means no sparse checkout
+	MonorepoHost    string   // Rig whose repository this rig shares (monorepo mode)
+	Subdir          string   // Repo-relative directory the rig owns; required with MonorepoHost
 }
 
 func resolveLocalRepo(path, gitURL string) (string, string) {
@@ -286,21 +303,8 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		return nil, ErrRigExists
 	}
 
-	// Validate rig name: reject characters that break agent ID parsing
-	// Agent IDs use format <prefix>-<rig>-<role>[-<name>] with hyphens as delimiters
-	if strings.ContainsAny(opts.Name, "-. /\\") {
-		sanitized := strings.NewReplacer("-", "_", ".", "_", " ", "_", "/", "_", "\\", "_").Replace(opts.Name)
-		sanitized = strings.TrimLeft(sanitized, "_")
-		sanitized = strings.ToLower(sanitized)
-		return nil, fmt.Errorf("rig name %q contains invalid characters; hyphens, dots, spaces, and path separators are not allowed. Try %q instead (underscores are allowed)", opts.Name, sanitized)
-	}
-
-	// Reject reserved names that collide with town-level infrastructure.
-	// "hq" is special-cased by EnsureMetadata and dolt routing as the town-level alias.
-	for _, reserved := range reservedRigNames {
-		if strings.EqualFold(opts.Name, reserved) {
-			return nil, fmt.Errorf("rig name %q is reserved for town-level infrastructure", opts.Name)
-		}
+	if err := validateRigName(opts.Name); err != nil {
+		return nil, err
 	}
 
 	// Dolt server is required — refuse to proceed without it.
@@ -329,6 +333,15 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		opts.BeadsPrefix = deriveBeadsPrefix(opts.Name)
 	}
 
+	var monorepo *MonorepoConfig
+	if opts.MonorepoHost != "" {
+		mc, err := m.prepareMonorepo(&opts)
+		if err != nil {
+			return nil, err
+		}
+		monorepo = mc
+	}
+
 	localRepo, warn := resolveLocalRepo(opts.LocalRepo, opts.GitURL)
 	if warn != "" {
 		fmt.Printf("  Warning: %s\n", warn)
@@ -361,6 +374,7 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		Beads: &BeadsConfig{
 			Prefix: opts.BeadsPrefix,
 		},
+		Monorepo: monorepo,
 	}
 	if err := m.saveRigConfig(rigPath, rigConfig); err != nil {
 		return nil, fmt.Errorf("saving rig config: %w", err)
@@ -558,7 +572,7 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 			initArgs = append(initArgs, "--server-port", strconv.Itoa(doltCfg.Port))
 			cmd := exec.Command("bd", initArgs...)
 			cmd.Dir = mayorRigPath
-			if output, err := cmd.CombinedOutput(); err != nil {
+			if output, err := beads.CmdCombinedOutput(cmd); err != nil {
 				fmt.Printf("  Warning: Could not init bd database: %v (%s)\n", err, strings.TrimSpace(string(output)))
 			}
 			// Drop orphaned beads_<prefix> database if it differs from rigName (gt-sv1h).
@@ -573,11 +587,11 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		// the server-side database has issue_prefix set for this workspace.
 		configCmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
 		configCmd.Dir = mayorRigPath
-		_, _ = configCmd.CombinedOutput() // Ignore errors - older beads don't need this
+		_, _ = beads.CmdCombinedOutput(configCmd) // Ignore errors - older beads don't need this
 
 		prefixSetCmd := exec.Command("bd", "config", "set", "issue_prefix", opts.BeadsPrefix)
 		prefixSetCmd.Dir = mayorRigPath
-		if prefixOutput, prefixErr := prefixSetCmd.CombinedOutput(); prefixErr != nil {
+		if prefixOutput, prefixErr := beads.CmdCombinedOutput(prefixSetCmd); prefixErr != nil {
 			fmt.Printf("  Warning: Could not set issue_prefix: %v (%s)\n", prefixErr, strings.TrimSpace(string(prefixOutput)))
 		}
 	}
@@ -632,13 +646,13 @@ func (m *Manager) AddRig(opts AddRigOptions) (*Rig, error) {
 		prefixCmd := exec.Command("bd", "config", "set", "issue_prefix", opts.BeadsPrefix)
 		prefixCmd.Dir = rigPath
 		prefixCmd.Env = append(os.Environ(), "BEADS_DIR="+resolvedBeadsDir)
-		if out, err := prefixCmd.CombinedOutput(); err != nil {
+		if out, err := beads.CmdCombinedOutput(prefixCmd); err != nil {
 			fmt.Printf("  Warning: Could not set issue_prefix on rig database: %v (%s)\n", err, strings.TrimSpace(string(out)))
 		}
 		typesCmd := exec.Command("bd", "config", "set", "types.custom", constants.BeadsCustomTypes)
 		typesCmd.Dir = rigPath
 		typesCmd.Env = append(os.Environ(), "BEADS_DIR="+resolvedBeadsDir)
-		_, _ = typesCmd.CombinedOutput()
+		_, _ = beads.CmdCombinedOutput(typesCmd)
 	}
 
 	// Auto-create DoltHub remote for the rig's beads database.
@@ -938,7 +952,7 @@ func (m *Manager) InitBeads(rigPath, prefix, rigName string) error {
 	cmd := exec.Command("bd", initArgs...)
 	cmd.Dir = rigPath
 	cmd.Env = filteredEnv
-	_, bdInitErr := cmd.CombinedOutput()
+	_, bdInitErr := beads.CmdCombinedOutput(cmd)
 	if bdInitErr != nil {
 		// bd might not be installed or failed — the shared helper below will
 		// create config.yaml with the required defaults as a fallback.
@@ -951,14 +965,14 @@ func (m *Manager) InitBeads(rigPath, prefix, rigName string) error {
 		configCmd.Dir = rigPath
 		configCmd.Env = filteredEnv
 		// Ignore errors - older beads versions don't need this
-		_, _ = configCmd.CombinedOutput()
+		_, _ = beads.CmdCombinedOutput(configCmd)
 
 		// Explicitly set issue_prefix config (bd init --prefix may not persist it in newer versions).
 		// Without this, bd create and gt sling fail with "issue_prefix config is missing".
 		prefixSetCmd := exec.Command("bd", "config", "set", "issue_prefix", prefix)
 		prefixSetCmd.Dir = rigPath
 		prefixSetCmd.Env = filteredEnv
-		if prefixOutput, prefixErr := prefixSetCmd.CombinedOutput(); prefixErr != nil {
+		if prefixOutput, prefixErr := beads.CmdCombinedOutput(prefixSetCmd); prefixErr != nil {
 			return fmt.Errorf("bd config set issue_prefix failed: %s", strings.TrimSpace(string(prefixOutput)))
 		}
 
@@ -985,7 +999,7 @@ func (m *Manager) InitBeads(rigPath, prefix, rigName string) error {
 	migrateCmd.Dir = rigPath
 	migrateCmd.Env = filteredEnv
 	// Ignore errors - fingerprint is optional for functionality
-	_, _ = migrateCmd.CombinedOutput()
+	_, _ = beads.CmdCombinedOutput(migrateCmd)
 
 	// NOTE: We intentionally do NOT create routes.jsonl in rig beads.
 	// bd's routing walks up to find town root (via mayor/town.json) and uses
@@ -1292,6 +1306,162 @@ func detectBeadsPrefixFromConfig(configPath string) string {
 	return ""
 }
 
+// validateRigName rejects names that break agent ID parsing or collide
+// with town-level infrastructure.
+func validateRigName(name string) error {
+	// Agent IDs use format <prefix>-<rig>-<role>[-<name>] with hyphens as delimiters
+	if strings.ContainsAny(name, "-. /\\") {
+		sanitized := strings.NewReplacer("-", "_", ".", "_", " ", "_", "/", "_", "\\", "_").Replace(name)
+		sanitized = strings.TrimLeft(sanitized, "_")
+		sanitized = strings.ToLower(sanitized)
+		return fmt.Errorf("rig name %q contains invalid characters; hyphens, dots, spaces, and path separators are not allowed. Try %q instead (underscores are allowed)", name, sanitized)
+	}
+
+	// "hq" is special-cased by EnsureMetadata and dolt routing as the town-level alias.
+	for _, reserved := range reservedRigNames {
+		if strings.EqualFold(name, reserved) {
+			return fmt.Errorf("rig name %q is reserved for town-level infrastructure", name)
+		}
+	}
+	return nil
+}
+
+// RenameRig moves a rig from oldName to newName: its directory, the polecat
+// worktrees named after the rig (polecats/<name>/<rig>), git's record of
+// every worktree, the rig's config.json and its registry entry. The caller
+// saves the registry and moves routes, agent beads and assigned work (see
+// gt rig rename). The rig's sessions must not be running. If a step fails,
+// the completed ones are undone, leaving the rig where it was.
+func (m *Manager) RenameRig(oldName, newName string) (retErr error) {
+	if err := validateRigName(newName); err != nil {
+		return err
+	}
+	entry, ok := m.config.Rigs[oldName]
+	if !ok {
+		return ErrRigNotFound
+	}
+	if m.RigExists(newName) {
+		return ErrRigExists
+	}
+	oldPath := filepath.Join(m.townRoot, oldName)
+	newPath := filepath.Join(m.townRoot, newName)
+	if _, err := os.Stat(newPath); err == nil {
+		return fmt.Errorf("directory already exists: %s", newPath)
+	}
+
+	// Worktrees record each other's absolute paths, so note where each one
+	// will land before anything moves.
+	var repo *git.Git
+	var worktrees, oldWorktrees []string
+	for _, base := range []string{filepath.Join(oldPath, ".repo.git"), filepath.Join(oldPath, "mayor", "rig")} {
+		if _, err := os.Stat(base); err != nil {
+			continue
+		}
+		if strings.HasSuffix(base, ".repo.git") {
+			repo = git.NewGitWithDir(base, "")
+		} else {
+			repo = git.NewGit(base)
+		}
+		list, err := repo.WorktreeList()
+		if err != nil {
+			return fmt.Errorf("listing worktrees: %w", err)
+		}
+		for _, wt := range list {
+			if wt.Path == base {
+				continue // The repo itself, not a linked worktree
+			}
+			if moved, ok := renamedRigPath(wt.Path, oldPath, newPath, oldName, newName); ok {
+				worktrees = append(worktrees, moved)
+				oldWorktrees = append(oldWorktrees, wt.Path)
+			}
+		}
+		break
+	}
+
+	// Check every polecat worktree can move before anything does.
+	polecatDirs, _ := filepath.Glob(filepath.Join(oldPath, "polecats", "*", oldName))
+	for _, dir := range polecatDirs {
+		if _, err := os.Stat(filepath.Join(filepath.Dir(dir), newName)); err == nil {
+			return fmt.Errorf("polecat worktree already exists: %s", filepath.Join(filepath.Dir(dir), newName))
+		}
+	}
+
+	// Moves made so far, undone in reverse if a later step fails.
+	var moves [][2]string
+	defer func() {
+		if retErr == nil || len(moves) == 0 {
+			return
+		}
+		for i := len(moves) - 1; i >= 0; i-- {
+			if err := os.Rename(moves[i][1], moves[i][0]); err != nil {
+				retErr = fmt.Errorf("%w (undoing the rename also failed: %v; fix %s by hand)", retErr, err, moves[i][1])
+				return
+			}
+		}
+		if repo != nil {
+			_ = rigRepo(oldPath).WorktreeRepair(oldWorktrees...)
+		}
+	}()
+	move := func(from, to string) error {
+		if err := os.Rename(from, to); err != nil {
+			return err
+		}
+		moves = append(moves, [2]string{from, to})
+		return nil
+	}
+
+	if err := move(oldPath, newPath); err != nil {
+		return fmt.Errorf("renaming rig dir: %w", err)
+	}
+	for _, dir := range polecatDirs {
+		moved, _ := renamedRigPath(dir, oldPath, newPath, oldName, newName)
+		if err := move(filepath.Join(newPath, strings.TrimPrefix(dir, oldPath)), moved); err != nil {
+			return fmt.Errorf("renaming polecat worktree %s: %w", dir, err)
+		}
+	}
+	if repo != nil {
+		// The repo moved with the rig; repair from its new location.
+		if err := rigRepo(newPath).WorktreeRepair(worktrees...); err != nil {
+			return fmt.Errorf("repairing worktrees: %w", err)
+		}
+	}
+
+	if cfg, err := LoadRigConfig(newPath); err == nil {
+		cfg.Name = newName
+		if err := m.saveRigConfig(newPath, cfg); err != nil {
+			return fmt.Errorf("saving rig config: %w", err)
+		}
+	}
+
+	delete(m.config.Rigs, oldName)
+	m.config.Rigs[newName] = entry
+	return nil
+}
+
+// rigRepo returns the git repo holding a rig's worktrees: the bare
+// .repo.git, or mayor/rig in older rigs.
+func rigRepo(rigPath string) *git.Git {
+	if _, err := os.Stat(filepath.Join(rigPath, ".repo.git")); err == nil {
+		return git.NewGitWithDir(filepath.Join(rigPath, ".repo.git"), "")
+	}
+	return git.NewGit(filepath.Join(rigPath, "mayor", "rig"))
+}
+
+// renamedRigPath returns where path ends up when the rig at oldRig moves to
+// newRig, or false if path is outside the rig.
+func renamedRigPath(path, oldRig, newRig, oldName, newName string) (string, bool) {
+	rel, err := filepath.Rel(oldRig, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	parts := strings.Split(rel, string(filepath.Separator))
+	// Polecat worktrees are named after their rig: polecats/<name>/<rig>.
+	if len(parts) >= 3 && parts[0] == "polecats" && parts[2] == oldName {
+		parts[2] = newName
+	}
+	return filepath.Join(append([]string{newRig}, parts...)...), true
+}
+
 // RemoveRig unregisters a rig (does not delete files).
 func (m *Manager) RemoveRig(name string) error {
 	if !m.RigExists(name) {
@@ -1330,17 +1500,8 @@ func (m *Manager) RegisterRig(opts RegisterRigOptions) (*RegisterRigResult, erro
 		return nil, ErrRigExists
 	}
 
-	if strings.ContainsAny(opts.Name, "-. /\\") {
-		sanitized := strings.NewReplacer("-", "_", ".", "_", " ", "_", "/", "_", "\\", "_").Replace(opts.Name)
-		sanitized = strings.TrimLeft(sanitized, "_")
-		sanitized = strings.ToLower(sanitized)
-		return nil, fmt.Errorf("rig name %q contains invalid characters; hyphens, dots, spaces, and path separators are not allowed. Try %q instead (underscores are allowed)", opts.Name, sanitized)
-	}
-
-	for _, reserved := range reservedRigNames {
-		if strings.EqualFold(opts.Name, reserved) {
-			return nil, fmt.Errorf("rig name %q is reserved for town-level infrastructure", opts.Name)
-		}
+	if err := validateRigName(opts.Name); err != nil {
+		return nil, err
 	}
 
 	rigPath := filepath.Join(m.townRoot, opts.Name)
@@ -1561,7 +1722,7 @@ func (m *Manager) seedPatrolMolecules(rigPath string) error {
 	// Use bd command to seed molecules (more reliable than internal API)
 	cmd := exec.Command("bd", "mol", "seed", "--patrol")
 	cmd.Dir = rigPath
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmd(cmd); err != nil {
 		// Fallback: bd mol seed might not support --patrol yet
 		// Try creating them individually via bd create
 		return m.seedPatrolMoleculesManually(rigPath)
@@ -1594,7 +1755,7 @@ func (m *Manager) seedPatrolMoleculesManually(rigPath string) error {
 		// Check if already exists by title
 		checkCmd := exec.Command("bd", "list", "--type=molecule", "--format=json")
 		checkCmd.Dir = rigPath
-		output, _ := checkCmd.Output()
+		output, _ := beads.CmdOutput(checkCmd)
 		if strings.Contains(string(output), mol.title) {
 			continue // Already exists
 		}
@@ -1607,7 +1768,7 @@ func (m *Manager) seedPatrolMoleculesManually(rigPath string) error {
 			"--priority=2",
 		)
 		cmd.Dir = rigPath
-		if err := cmd.Run(); err != nil {
+		if err := beads.RunCmd(cmd); err != nil {
 			// Non-fatal, continue with others
 			continue
 		}
@@ -194,6 +194,102 @@ func TestRemoveRig(t *testing.T) {
 	}
 }
 
+func TestRenameRig(t *testing.T) {
+	for _, kv := range [][2]string{{"GIT_AUTHOR_NAME", "test"}, {"GIT_AUTHOR_EMAIL", "test@example.com"}, {"GIT_COMMITTER_NAME", "test"}, {"GIT_COMMITTER_EMAIL", "test@example.com"}} {
+		t.Setenv(kv[0], kv[1])
+	}
+	root, rigsConfig := setupTestTown(t)
+	rigsConfig.Rigs["oldrig"] = config.RigEntry{GitURL: "file:///src"}
+	rigPath := filepath.Join(root, "oldrig")
+
+	src := filepath.Join(t.TempDir(), "src")
+	for _, args := range [][]string{
+		{"init", src},
+		{"-C", src, "commit", "--allow-empty", "-m", "init"},
+		{"clone", "--bare", src, filepath.Join(rigPath, ".repo.git")},
+		{"--git-dir", filepath.Join(rigPath, ".repo.git"), "worktree", "add", filepath.Join(rigPath, "polecats", "toast", "oldrig"), "-b", "polecat/toast"},
+		{"--git-dir", filepath.Join(rigPath, ".repo.git"), "worktree", "add", filepath.Join(rigPath, "refinery", "rig"), "-b", "refinery"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(rigPath, "config.json"), []byte(`{"type":"rig","version":1,"name":"oldrig"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewManager(root, rigsConfig, git.NewGit(root))
+	if err := manager.RenameRig("oldrig", "newrig"); err != nil {
+		t.Fatalf("RenameRig: %v", err)
+	}
+
+	if manager.RigExists("oldrig") || !manager.RigExists("newrig") {
+		t.Errorf("registry = %v", rigsConfig.Rigs)
+	}
+	if rigsConfig.Rigs["newrig"].GitURL != "file:///src" {
+		t.Error("registry entry not carried over")
+	}
+	cfg, err := LoadRigConfig(filepath.Join(root, "newrig"))
+	if err != nil || cfg.Name != "newrig" {
+		t.Errorf("config.json name = %+v, %v", cfg, err)
+	}
+	for _, wt := range []string{
+		filepath.Join(root, "newrig", "polecats", "toast", "newrig"),
+		filepath.Join(root, "newrig", "refinery", "rig"),
+	} {
+		if out, err := exec.Command("git", "-C", wt, "status", "--short").CombinedOutput(); err != nil {
+			t.Errorf("worktree %s broken after rename: %v\n%s", wt, err, out)
+		}
+	}
+
+	if err := manager.RenameRig("newrig", "bad-name"); err == nil {
+		t.Error("RenameRig accepted a hyphenated name")
+	}
+	if err := manager.RenameRig("missing", "other"); err != ErrRigNotFound {
+		t.Errorf("RenameRig(missing) = %v, want ErrRigNotFound", err)
+	}
+}
+
+func TestRenameRig_ChecksBeforeMoving(t *testing.T) {
+	root, rigsConfig := setupTestTown(t)
+	rigsConfig.Rigs["oldrig"] = config.RigEntry{}
+	for _, dir := range []string{"oldrig/polecats/toast/oldrig", "oldrig/polecats/toast/newrig"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager := NewManager(root, rigsConfig, git.NewGit(root))
+	if err := manager.RenameRig("oldrig", "newrig"); err == nil {
+		t.Fatal("RenameRig moved onto an existing polecat worktree")
+	}
+	if _, err := os.Stat(filepath.Join(root, "oldrig", "polecats", "toast", "oldrig")); err != nil {
+		t.Errorf("rig moved despite the failed check: %v", err)
+	}
+	if !manager.RigExists("oldrig") {
+		t.Error("registry changed despite the failed check")
+	}
+}
+
+func TestRenamedRigPath(t *testing.T) {
+	tests := []struct {
+		path, want string
+		ok         bool
+	}{
+		{"/town/old/polecats/toast/old", "/town/new/polecats/toast/new", true},
+		{"/town/old/polecats/toast", "/town/new/polecats/toast", true},
+		{"/town/old/crew/max", "/town/new/crew/max", true},
+		{"/town/older/crew/max", "", false},
+		{"/elsewhere/wt", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := renamedRigPath(tt.path, "/town/old", "/town/new", "old", "new")
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("renamedRigPath(%q) = %q, %v", tt.path, got, ok)
+		}
+	}
+}
+
 func TestRemoveRigNotFound(t *testing.T) {
 	root, rigsConfig := setupTestTown(t)
 	manager := NewManager(root, rigsConfig, git.NewGit(root))
@@ -0,0 +1,163 @@
+package rig
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MonorepoConfig places a rig in one subdirectory of a repository that it
+// shares with other rigs. The host is the rig whose bare repo the member's
+// own bare repo borrows objects from, so adding a subproject does not
+// download the monorepo again and each member keeps its own branches,
+// refinery worktree and merge queue.
+type MonorepoConfig struct {
+	Host   string   `json:"host"`             // Rig that owns the shared object store
+	Subdir string   `json:"subdir"`           // Repo-relative directory this rig owns, slash-separated
+	Shared []string `json:"shared,omitempty"` // Paths outside Subdir the rig may also change (go.work, lockfiles)
+}
+
+// NormalizeSubdir cleans a repo-relative subdirectory, rejecting absolute
+// paths and paths that leave the repository.
+func NormalizeSubdir(subdir string) (string, error) {
+	s := path.Clean(filepath.ToSlash(strings.TrimSpace(subdir)))
+	switch {
+	case s == "." || s == "":
+		return "", fmt.Errorf("subdir is required for monorepo rigs")
+	case path.IsAbs(s) || filepath.IsAbs(subdir):
+		return "", fmt.Errorf("subdir %q must be relative to the repository root", subdir)
+	case s == ".." || strings.HasPrefix(s, "../"):
+		return "", fmt.Errorf("subdir %q is outside the repository", subdir)
+	}
+	return s, nil
+}
+
+// Owns reports whether a repo-relative file path is inside the rig's
+// subdirectory or one of its shared paths.
+func (m *MonorepoConfig) Owns(file string) bool {
+	file = path.Clean(filepath.ToSlash(file))
+	if under(file, m.Subdir) {
+		return true
+	}
+	for _, s := range m.Shared {
+		if under(file, path.Clean(filepath.ToSlash(s))) {
+			return true
+		}
+	}
+	return false
+}
+
+// OutOfScope returns the files the rig does not own, sorted.
+func (m *MonorepoConfig) OutOfScope(files []string) []string {
+	var out []string
+	for _, f := range files {
+		if strings.TrimSpace(f) != "" && !m.Owns(f) {
+			out = append(out, f)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Overlaps reports whether two subdirectories contain one another.
+func Overlaps(a, b string) bool {
+	return under(a, b) || under(b, a)
+}
+
+func under(file, dir string) bool {
+	return file == dir || strings.HasPrefix(file, dir+"/")
+}
+
+// LoadMonorepoConfig returns the monorepo placement of the rig at rigPath,
+// or nil if the rig owns its whole repository.
+func LoadMonorepoConfig(rigPath string) *MonorepoConfig {
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil {
+		return nil
+	}
+	return cfg.Monorepo
+}
+
+// MonorepoMembers returns the rigs that share host's repository, keyed by
+// rig name.
+func MonorepoMembers(townRoot string, rigNames []string, host string) map[string]*MonorepoConfig {
+	members := make(map[string]*MonorepoConfig)
+	for _, name := range rigNames {
+		if mc := LoadMonorepoConfig(filepath.Join(townRoot, name)); mc != nil && mc.Host == host {
+			members[name] = mc
+		}
+	}
+	return members
+}
+
+// SubprojectOwner returns the member rig of host's monorepo whose subdirectory
+// contains file, preferring the deepest match, or "" if none does.
+func SubprojectOwner(townRoot string, rigNames []string, host, file string) string {
+	file = path.Clean(filepath.ToSlash(file))
+	owner, depth := "", -1
+	for name, mc := range MonorepoMembers(townRoot, rigNames, host) {
+		if under(file, mc.Subdir) && len(mc.Subdir) > depth {
+			owner, depth = name, len(mc.Subdir)
+		}
+	}
+	return owner
+}
+
+// prepareMonorepo validates a monorepo member and fills in what it inherits
+// from its host: the git URL, the default branch, the host's bare repo as
+// the reference clone, and a sparse mayor clone of the subdirectory unless
+// sparse paths were given.
+func (m *Manager) prepareMonorepo(opts *AddRigOptions) (*MonorepoConfig, error) {
+	subdir, err := NormalizeSubdir(opts.Subdir)
+	if err != nil {
+		return nil, err
+	}
+	host, ok := m.config.Rigs[opts.MonorepoHost]
+	if !ok {
+		return nil, fmt.Errorf("monorepo host %q is not a rig in this town", opts.MonorepoHost)
+	}
+	hostPath := filepath.Join(m.townRoot, opts.MonorepoHost)
+	hostCfg, err := LoadRigConfig(hostPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading host rig config: %w", err)
+	}
+	if hostCfg.Monorepo != nil {
+		return nil, fmt.Errorf("rig %q is itself a subproject of %q; use %q as the host",
+			opts.MonorepoHost, hostCfg.Monorepo.Host, hostCfg.Monorepo.Host)
+	}
+
+	if opts.GitURL == "" {
+		opts.GitURL = host.GitURL
+	} else if opts.GitURL != host.GitURL {
+		return nil, fmt.Errorf("git URL %q does not match monorepo host %s (%s)", opts.GitURL, opts.MonorepoHost, host.GitURL)
+	}
+
+	for name, mc := range MonorepoMembers(m.townRoot, m.ListRigNames(), opts.MonorepoHost) {
+		if Overlaps(mc.Subdir, subdir) {
+			return nil, fmt.Errorf("subdir %s overlaps rig %s (%s)", subdir, name, mc.Subdir)
+		}
+	}
+
+	if opts.LocalRepo == "" {
+		for _, candidate := range []string{
+			filepath.Join(hostPath, ".repo.git"),
+			filepath.Join(hostPath, "mayor", "rig"),
+		} {
+			if _, err := os.Stat(candidate); err == nil {
+				opts.LocalRepo = candidate
+				break
+			}
+		}
+	}
+	if opts.DefaultBranch == "" {
+		opts.DefaultBranch = hostCfg.DefaultBranch
+	}
+	if len(opts.SparseCheckout) == 0 {
+		opts.SparseCheckout = []string{subdir}
+	}
+
+	return &MonorepoConfig{Host: opts.MonorepoHost, Subdir: subdir}, nil
+}
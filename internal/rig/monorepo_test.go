@@ -0,0 +1,131 @@
+package rig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestNormalizeSubdir(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"services/api", "services/api", false},
+		{"./services/api/", "services/api", false},
+		{"services//api", "services/api", false},
+		{"", "", true},
+		{".", "", true},
+		{"/services/api", "", true},
+		{"../other", "", true},
+		{"services/../..", "", true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeSubdir(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("NormalizeSubdir(%q) = %q, %v; want %q, err=%v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestMonorepoConfig_OutOfScope(t *testing.T) {
+	mc := &MonorepoConfig{Host: "platform", Subdir: "services/api", Shared: []string{"go.work", "tools/"}}
+	files := []string{
+		"services/api/main.go",
+		"services/apigw/main.go",
+		"go.work",
+		"tools/lint/run.sh",
+		"libs/auth/auth.go",
+		"",
+	}
+	got := mc.OutOfScope(files)
+	if strings.Join(got, ",") != "libs/auth/auth.go,services/apigw/main.go" {
+		t.Errorf("OutOfScope = %v", got)
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	if !Overlaps("services", "services/api") || !Overlaps("services/api", "services/api") {
+		t.Error("nested or equal subdirs should overlap")
+	}
+	if Overlaps("services/api", "services/apigw") {
+		t.Error("sibling subdirs with a common prefix should not overlap")
+	}
+}
+
+func writeMonorepoRig(t *testing.T, townRoot, name string, mc *MonorepoConfig) {
+	t.Helper()
+	m := &Manager{townRoot: townRoot}
+	rigPath := filepath.Join(townRoot, name)
+	if err := os.MkdirAll(rigPath, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.saveRigConfig(rigPath, &RigConfig{Type: "rig", Name: name, DefaultBranch: "trunk", Monorepo: mc}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSubprojectOwner(t *testing.T) {
+	root := t.TempDir()
+	writeMonorepoRig(t, root, "platform", nil)
+	writeMonorepoRig(t, root, "services", &MonorepoConfig{Host: "platform", Subdir: "services"})
+	writeMonorepoRig(t, root, "api", &MonorepoConfig{Host: "platform", Subdir: "services/api"})
+	writeMonorepoRig(t, root, "elsewhere", &MonorepoConfig{Host: "other", Subdir: "libs"})
+	names := []string{"platform", "services", "api", "elsewhere"}
+
+	if got := SubprojectOwner(root, names, "platform", "services/api/main.go"); got != "api" {
+		t.Errorf("owner of services/api/main.go = %q, want deepest match api", got)
+	}
+	if got := SubprojectOwner(root, names, "platform", "services/web/index.ts"); got != "services" {
+		t.Errorf("owner of services/web = %q, want services", got)
+	}
+	if got := SubprojectOwner(root, names, "platform", "libs/x.go"); got != "" {
+		t.Errorf("owner of libs/x.go = %q, want none (other host)", got)
+	}
+}
+
+func TestPrepareMonorepo(t *testing.T) {
+	root, rigsConfig := setupTestTown(t)
+	rigsConfig.Rigs["platform"] = config.RigEntry{GitURL: "git@example.com:org/platform.git"}
+	rigsConfig.Rigs["web"] = config.RigEntry{GitURL: "git@example.com:org/platform.git"}
+	writeMonorepoRig(t, root, "platform", nil)
+	writeMonorepoRig(t, root, "web", &MonorepoConfig{Host: "platform", Subdir: "apps/web"})
+	if err := os.MkdirAll(filepath.Join(root, "platform", ".repo.git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	m := NewManager(root, rigsConfig, git.NewGit(root))
+
+	opts := AddRigOptions{Name: "api", MonorepoHost: "platform", Subdir: "./services/api"}
+	mc, err := m.prepareMonorepo(&opts)
+	if err != nil {
+		t.Fatalf("prepareMonorepo: %v", err)
+	}
+	if mc.Host != "platform" || mc.Subdir != "services/api" {
+		t.Errorf("config = %+v", mc)
+	}
+	if opts.GitURL != "git@example.com:org/platform.git" || opts.DefaultBranch != "trunk" {
+		t.Errorf("inherited GitURL=%q DefaultBranch=%q", opts.GitURL, opts.DefaultBranch)
+	}
+	if opts.LocalRepo != filepath.Join(root, "platform", ".repo.git") {
+		t.Errorf("LocalRepo = %q, want host bare repo", opts.LocalRepo)
+	}
+	if len(opts.SparseCheckout) != 1 || opts.SparseCheckout[0] != "services/api" {
+		t.Errorf("SparseCheckout = %v", opts.SparseCheckout)
+	}
+
+	for _, bad := range []AddRigOptions{
+		{Name: "x", MonorepoHost: "missing", Subdir: "x"},
+		{Name: "x", MonorepoHost: "platform", Subdir: "apps"},
+		{Name: "x", MonorepoHost: "web", Subdir: "apps/web/admin"},
+		{Name: "x", MonorepoHost: "platform", Subdir: "x", GitURL: "git@example.com:org/other.git"},
+	} {
+		if _, err := m.prepareMonorepo(&bad); err == nil {
+			t.Errorf("prepareMonorepo(%+v) succeeded, want error", bad)
+		}
+	}
+}
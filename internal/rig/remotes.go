@@ -0,0 +1,218 @@
+package rig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Push policies for a rig's extra git remotes. The merge queue always lands
+// on origin; the policies decide where everything else is pushed.
+const (
+	// PushBranches sends polecat work branches here instead of to origin,
+	// e.g. your fork when origin is an upstream you only merge into.
+	PushBranches = "branches"
+
+	// PushMirror gets a best-effort copy of every merge-queue push.
+	PushMirror = "mirror"
+
+	// PushNone makes the remote fetch-only.
+	PushNone = "none"
+)
+
+// PushPolicies lists the valid push policies.
+var PushPolicies = []string{PushBranches, PushMirror, PushNone}
+
+// RemoteConfig is a git remote of a rig besides origin, set in the rig's
+// config.json under "remotes".
+type RemoteConfig struct {
+	Name    string `json:"name"`
+	URL     string `json:"url"`
+	PushURL string `json:"push_url,omitempty"`
+	Push    string `json:"push"` // One of PushPolicies
+}
+
+// Remotes are a rig's extra git remotes.
+type Remotes []RemoteConfig
+
+// Get returns the named remote, or nil.
+func (rs Remotes) Get(name string) *RemoteConfig {
+	for i := range rs {
+		if rs[i].Name == name {
+			return &rs[i]
+		}
+	}
+	return nil
+}
+
+// BranchRemote returns the remote polecat work branches are pushed to:
+// the remote with the branches policy, or origin.
+func (rs Remotes) BranchRemote() string {
+	for _, rc := range rs {
+		if rc.Push == PushBranches {
+			return rc.Name
+		}
+	}
+	return "origin"
+}
+
+// Mirrors returns the remotes that get a copy of merge-queue pushes.
+func (rs Remotes) Mirrors() []string {
+	var names []string
+	for _, rc := range rs {
+		if rc.Push == PushMirror {
+			names = append(names, rc.Name)
+		}
+	}
+	return names
+}
+
+// Set adds rc, replacing the remote of the same name.
+func (rs Remotes) Set(rc RemoteConfig) Remotes {
+	out := make(Remotes, 0, len(rs)+1)
+	replaced := false
+	for _, existing := range rs {
+		if existing.Name == rc.Name {
+			out = append(out, rc)
+			replaced = true
+			continue
+		}
+		out = append(out, existing)
+	}
+	if !replaced {
+		out = append(out, rc)
+	}
+	return out
+}
+
+// Remove drops the named remote, reporting whether it was there.
+func (rs Remotes) Remove(name string) (Remotes, bool) {
+	out := make(Remotes, 0, len(rs))
+	for _, rc := range rs {
+		if rc.Name != name {
+			out = append(out, rc)
+		}
+	}
+	return out, len(out) != len(rs)
+}
+
+// Validate checks names, URLs and policies. At most one remote takes the
+// work branches.
+func (rs Remotes) Validate() error {
+	seen := make(map[string]bool, len(rs))
+	branches := ""
+	for _, rc := range rs {
+		switch {
+		case rc.Name == "":
+			return fmt.Errorf("remote name is required")
+		case rc.Name == "origin":
+			return fmt.Errorf("origin is the rig's git_url; remotes configures the others")
+		case strings.ContainsAny(rc.Name, " /\t"):
+			return fmt.Errorf("invalid remote name %q", rc.Name)
+		case seen[rc.Name]:
+			return fmt.Errorf("remote %q is listed twice", rc.Name)
+		case rc.URL == "":
+			return fmt.Errorf("remote %q has no url", rc.Name)
+		}
+		seen[rc.Name] = true
+		if !isPushPolicy(rc.Push) {
+			return fmt.Errorf("remote %q: invalid push policy %q (want one of %s)", rc.Name, rc.Push, strings.Join(PushPolicies, ", "))
+		}
+		if rc.Push == PushBranches {
+			if branches != "" {
+				return fmt.Errorf("remotes %q and %q both take work branches; only one can", branches, rc.Name)
+			}
+			branches = rc.Name
+		}
+	}
+	return nil
+}
+
+func isPushPolicy(p string) bool {
+	for _, valid := range PushPolicies {
+		if p == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadRemotes returns the extra remotes from the rig's config.json, or nil
+// when there are none or the config can't be read.
+func LoadRemotes(rigPath string) Remotes {
+	cfg, err := LoadRigConfig(rigPath)
+	if err != nil {
+		return nil
+	}
+	return cfg.Remotes
+}
+
+// SaveRemotes writes the extra remotes to the rig's config.json, leaving
+// its other keys (including ones RigConfig doesn't model) untouched.
+func SaveRemotes(rigPath string, remotes Remotes) error {
+	if err := remotes.Validate(); err != nil {
+		return err
+	}
+	configPath := filepath.Join(rigPath, "config.json")
+	data, err := os.ReadFile(configPath) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("parsing %s: %w", configPath, err)
+	}
+	if len(remotes) == 0 {
+		delete(raw, "remotes")
+	} else {
+		encoded, err := json.Marshal(remotes)
+		if err != nil {
+			return err
+		}
+		raw["remotes"] = encoded
+	}
+	out, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, append(out, '\n'), 0644) //nolint:gosec // G306: config is not secret
+}
+
+// ConfigureRemote adds the remote to a repository, or updates its URLs if
+// it exists. Fetch-only remotes get a push URL that refuses pushes.
+func ConfigureRemote(g *git.Git, rc RemoteConfig) error {
+	if _, err := g.RemoteURL(rc.Name); err != nil {
+		if _, err := g.AddRemote(rc.Name, rc.URL); err != nil {
+			return fmt.Errorf("adding remote %s: %w", rc.Name, err)
+		}
+	} else if _, err := g.SetRemoteURL(rc.Name, rc.URL); err != nil {
+		return fmt.Errorf("setting %s url: %w", rc.Name, err)
+	}
+
+	switch {
+	case rc.Push == PushNone:
+		return g.ConfigurePushURL(rc.Name, "no-push")
+	case rc.PushURL != "":
+		return g.ConfigurePushURL(rc.Name, rc.PushURL)
+	default:
+		return g.ClearPushURL(rc.Name)
+	}
+}
+
+// RemoteRepo returns the git repository whose remotes the rig's agents
+// share: the bare repo when there is one, else mayor/rig.
+func RemoteRepo(rigPath string) (*git.Git, error) {
+	bareRepoPath := filepath.Join(rigPath, ".repo.git")
+	if info, err := os.Stat(bareRepoPath); err == nil && info.IsDir() {
+		return git.NewGitWithDir(bareRepoPath, ""), nil
+	}
+	mayorPath := filepath.Join(rigPath, "mayor", "rig")
+	if _, err := os.Stat(mayorPath); err == nil {
+		return git.NewGit(mayorPath), nil
+	}
+	return nil, fmt.Errorf("no repository in %s (neither .repo.git nor mayor/rig)", rigPath)
+}
@@ -0,0 +1,146 @@
+package rig
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+func TestRemotes_Routing(t *testing.T) {
+	var none Remotes
+	if none.BranchRemote() != "origin" || len(none.Mirrors()) != 0 {
+		t.Errorf("no remotes: branches → %s, mirrors %v", none.BranchRemote(), none.Mirrors())
+	}
+
+	rs := Remotes{
+		{Name: "upstream", URL: "https://example.com/up.git", Push: PushNone},
+		{Name: "fork", URL: "git@example.com:me/fork.git", Push: PushBranches},
+		{Name: "backup", URL: "git@backup:repo.git", Push: PushMirror},
+	}
+	if rs.BranchRemote() != "fork" {
+		t.Errorf("BranchRemote = %s, want fork", rs.BranchRemote())
+	}
+	if strings.Join(rs.Mirrors(), ",") != "backup" {
+		t.Errorf("Mirrors = %v, want [backup]", rs.Mirrors())
+	}
+}
+
+func TestRemotes_SetRemove(t *testing.T) {
+	rs := Remotes{{Name: "fork", URL: "a", Push: PushBranches}}
+	rs = rs.Set(RemoteConfig{Name: "backup", URL: "b", Push: PushMirror})
+	rs = rs.Set(RemoteConfig{Name: "fork", URL: "c", Push: PushNone})
+	if len(rs) != 2 || rs.Get("fork").URL != "c" || rs.Get("fork").Push != PushNone {
+		t.Fatalf("after Set: %+v", rs)
+	}
+	rs, ok := rs.Remove("fork")
+	if !ok || len(rs) != 1 || rs.Get("fork") != nil {
+		t.Errorf("after Remove: %+v, %v", rs, ok)
+	}
+	if _, ok := rs.Remove("fork"); ok {
+		t.Error("removing a missing remote should report false")
+	}
+}
+
+func TestRemotes_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		remotes Remotes
+		wantErr string
+	}{
+		{"ok", Remotes{{Name: "fork", URL: "u", Push: PushBranches}, {Name: "b", URL: "u", Push: PushMirror}}, ""},
+		{"origin", Remotes{{Name: "origin", URL: "u", Push: PushNone}}, "git_url"},
+		{"no url", Remotes{{Name: "fork", Push: PushNone}}, "no url"},
+		{"bad policy", Remotes{{Name: "fork", URL: "u", Push: "sometimes"}}, "invalid push policy"},
+		{"duplicate", Remotes{{Name: "fork", URL: "u", Push: PushNone}, {Name: "fork", URL: "v", Push: PushNone}}, "twice"},
+		{"two branch remotes", Remotes{{Name: "a", URL: "u", Push: PushBranches}, {Name: "b", URL: "v", Push: PushBranches}}, "only one"},
+	}
+	for _, tt := range tests {
+		err := tt.remotes.Validate()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: err = %v, want containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSaveRemotes_PreservesOtherKeys(t *testing.T) {
+	rigPath := t.TempDir()
+	orig := `{"type":"rig","name":"gastown","git_url":"https://example.com/up.git","merge_queue":{"batch":{"max_batch_size":4}}}`
+	if err := os.WriteFile(filepath.Join(rigPath, "config.json"), []byte(orig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rs := Remotes{{Name: "fork", URL: "git@example.com:me/fork.git", Push: PushBranches}}
+	if err := SaveRemotes(rigPath, rs); err != nil {
+		t.Fatal(err)
+	}
+	if got := LoadRemotes(rigPath); got.BranchRemote() != "fork" {
+		t.Errorf("LoadRemotes = %+v", got)
+	}
+	data, err := os.ReadFile(filepath.Join(rigPath, "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := raw["merge_queue"]; !ok {
+		t.Error("merge_queue was dropped from config.json")
+	}
+
+	if err := SaveRemotes(rigPath, nil); err != nil {
+		t.Fatal(err)
+	}
+	if LoadRemotes(rigPath) != nil {
+		t.Error("remotes should be gone after saving none")
+	}
+	if err := SaveRemotes(rigPath, Remotes{{Name: "origin", URL: "u", Push: PushNone}}); err == nil {
+		t.Error("invalid remotes should not be saved")
+	}
+}
+
+func TestConfigureRemote(t *testing.T) {
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	g := git.NewGit(dir)
+
+	rc := RemoteConfig{Name: "fork", URL: "https://example.com/fork.git", Push: PushBranches}
+	if err := ConfigureRemote(g, rc); err != nil {
+		t.Fatal(err)
+	}
+	if url, _ := g.RemoteURL("fork"); strings.TrimSpace(url) != rc.URL {
+		t.Errorf("fork url = %q", url)
+	}
+
+	// Updating an existing remote: new URL, then fetch-only.
+	rc.URL = "https://example.com/fork2.git"
+	rc.Push = PushNone
+	if err := ConfigureRemote(g, rc); err != nil {
+		t.Fatal(err)
+	}
+	if url, _ := g.RemoteURL("fork"); strings.TrimSpace(url) != rc.URL {
+		t.Errorf("fork url after update = %q", url)
+	}
+	if push, _ := g.GetPushURL("fork"); push == rc.URL {
+		t.Error("a fetch-only remote should not push to its fetch URL")
+	}
+
+	rc.Push = PushMirror
+	if err := ConfigureRemote(g, rc); err != nil {
+		t.Fatal(err)
+	}
+	if push, _ := g.GetPushURL("fork"); push != rc.URL {
+		t.Errorf("push url = %q, want the fetch URL once pushes are allowed again", push)
+	}
+}
@@ -0,0 +1,91 @@
+package rig
+
+import (
+	"sort"
+	"strings"
+)
+
+// SparseLabelPrefix marks a bead label that selects a sparse checkout
+// profile for the polecat worktree, e.g. "sparse:frontend".
+const SparseLabelPrefix = "sparse:"
+
+// SparseFull is the profile name that asks for a full checkout, overriding
+// the rig's default profile.
+const SparseFull = "full"
+
+// SparseConfig configures cone-mode sparse checkout of polecat worktrees so
+// agents on a large repository only materialize the paths they work on.
+//
+//	"sparse": {
+//	  "default": ["services/api", "libs/common"],
+//	  "profiles": {"frontend": ["apps/web", "libs/ui"]}
+//	}
+//
+// Files in the repository root are always checked out (cone mode).
+type SparseConfig struct {
+	Default  []string            `json:"default,omitempty"`  // Paths for beads without a sparse: label; empty = full checkout
+	Profiles map[string][]string `json:"profiles,omitempty"` // Named path sets selected by sparse:<name> labels
+}
+
+// SparsePaths returns the sparse checkout paths for a polecat worktree
+// working on a bead with the given labels, and any sparse:<name> labels
+// that name no profile. nil paths mean a full checkout.
+//
+// Labels are combined: sparse:api and sparse:docs check out both profiles.
+// Without labels the rig's default profile applies; a monorepo member with
+// no default gets its subdirectory and shared paths.
+func (c *RigConfig) SparsePaths(labels []string) (paths []string, unknown []string) {
+	var selected []string
+	for _, l := range labels {
+		if name, ok := strings.CutPrefix(l, SparseLabelPrefix); ok && name != "" {
+			selected = append(selected, name)
+		}
+	}
+
+	if len(selected) == 0 {
+		return c.defaultSparsePaths(), nil
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range selected {
+		if name == SparseFull {
+			return nil, unknown
+		}
+		profile, ok := c.sparseProfile(name)
+		if !ok {
+			unknown = append(unknown, SparseLabelPrefix+name)
+			continue
+		}
+		for _, p := range profile {
+			if !seen[p] {
+				seen[p] = true
+				paths = append(paths, p)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		// Only unknown profiles: fall back to the default rather than
+		// widening to a full checkout of a large repository.
+		return c.defaultSparsePaths(), unknown
+	}
+	sort.Strings(paths)
+	return paths, unknown
+}
+
+func (c *RigConfig) sparseProfile(name string) ([]string, bool) {
+	if c.Sparse == nil {
+		return nil, false
+	}
+	profile, ok := c.Sparse.Profiles[name]
+	return profile, ok
+}
+
+func (c *RigConfig) defaultSparsePaths() []string {
+	if c.Sparse != nil && len(c.Sparse.Default) > 0 {
+		return append([]string(nil), c.Sparse.Default...)
+	}
+	if c.Monorepo != nil {
+		return append([]string{c.Monorepo.Subdir}, c.Monorepo.Shared...)
+	}
+	return nil
+}
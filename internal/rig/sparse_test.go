@@ -0,0 +1,48 @@
+package rig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRigConfig_SparsePaths(t *testing.T) {
+	cfg := &RigConfig{Sparse: &SparseConfig{
+		Default: []string{"services/api"},
+		Profiles: map[string][]string{
+			"web":  {"apps/web", "libs/ui"},
+			"docs": {"docs", "libs/ui"},
+		},
+	}}
+	tests := []struct {
+		name        string
+		labels      []string
+		wantPaths   []string
+		wantUnknown []string
+	}{
+		{"no labels uses default", nil, []string{"services/api"}, nil},
+		{"unrelated labels", []string{"bug", "p1"}, []string{"services/api"}, nil},
+		{"one profile", []string{"sparse:web"}, []string{"apps/web", "libs/ui"}, nil},
+		{"profiles combine", []string{"sparse:web", "sparse:docs"}, []string{"apps/web", "docs", "libs/ui"}, nil},
+		{"full overrides default", []string{"sparse:full"}, nil, nil},
+		{"unknown falls back to default", []string{"sparse:nope"}, []string{"services/api"}, []string{"sparse:nope"}},
+		{"unknown alongside known", []string{"sparse:nope", "sparse:docs"}, []string{"docs", "libs/ui"}, []string{"sparse:nope"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths, unknown := cfg.SparsePaths(tt.labels)
+			if !reflect.DeepEqual(paths, tt.wantPaths) || !reflect.DeepEqual(unknown, tt.wantUnknown) {
+				t.Errorf("SparsePaths(%v) = %v, %v; want %v, %v", tt.labels, paths, unknown, tt.wantPaths, tt.wantUnknown)
+			}
+		})
+	}
+}
+
+func TestRigConfig_SparsePaths_Defaults(t *testing.T) {
+	if paths, _ := (&RigConfig{}).SparsePaths([]string{"sparse:web"}); paths != nil {
+		t.Errorf("rig without sparse config = %v, want full checkout", paths)
+	}
+	member := &RigConfig{Monorepo: &MonorepoConfig{Host: "platform", Subdir: "services/api", Shared: []string{"tools"}}}
+	if paths, _ := member.SparsePaths(nil); !reflect.DeepEqual(paths, []string{"services/api", "tools"}) {
+		t.Errorf("monorepo member default = %v, want subdir and shared paths", paths)
+	}
+}
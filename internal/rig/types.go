@@ -26,6 +26,13 @@ type Rig struct {
 	// Config is the rig-level configuration.
 	Config *config.BeadsConfig `json:"config,omitempty"`
 
+	// Monorepo is set when the rig owns one subdirectory of a repository
+	// shared with other rigs.
+	Monorepo *MonorepoConfig `json:"monorepo,omitempty"`
+
+	// Remotes are the rig's git remotes besides origin.
+	Remotes Remotes `json:"remotes,omitempty"`
+
 	// Polecats is the list of polecat names in this rig.
 	Polecats []string `json:"polecats,omitempty"`
 
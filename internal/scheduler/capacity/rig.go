@@ -0,0 +1,193 @@
+package capacity
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RigConfig is the capacity section of a rig's settings/config.json.
+// Unlike SchedulerConfig it applies to one rig: it caps the rig's own
+// polecats and advertises what kind of work the rig can take.
+//
+//	"capacity": {
+//	  "max_polecats": 4,
+//	  "burst": 2,
+//	  "capabilities": ["go", "frontend"]
+//	}
+//
+// A sling to a rig at max_polecats is refused with options instead of
+// spawning: queue for the rig, reroute to a rig with the same capabilities,
+// or scale up by at most burst extra polecats while within budget.
+type RigConfig struct {
+	MaxPolecats  int      `json:"max_polecats,omitempty"` // 0 = no per-rig cap
+	Burst        int      `json:"burst,omitempty"`        // Extra polecats a scale-up may add
+	Capabilities []string `json:"capabilities,omitempty"` // Matched against needs:<capability> labels
+}
+
+// LabelNeedsPrefix marks a capability a bead needs (e.g. "needs:frontend").
+const LabelNeedsPrefix = "needs:"
+
+// Validate checks the limits. A nil config is valid (no per-rig cap).
+func (c *RigConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MaxPolecats < 0 {
+		return fmt.Errorf("max_polecats must not be negative")
+	}
+	if c.Burst < 0 {
+		return fmt.Errorf("burst must not be negative")
+	}
+	if c.Burst > 0 && c.MaxPolecats == 0 {
+		return fmt.Errorf("burst requires max_polecats")
+	}
+	for _, capability := range c.Capabilities {
+		if strings.TrimSpace(capability) == "" {
+			return fmt.Errorf("capabilities must not be empty strings")
+		}
+	}
+	return nil
+}
+
+// Has reports whether the rig advertises every capability in needs.
+func (c *RigConfig) Has(needs []string) bool {
+	if c == nil {
+		return len(needs) == 0
+	}
+	for _, need := range needs {
+		found := false
+		for _, capability := range c.Capabilities {
+			if strings.EqualFold(capability, need) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// NeededCapabilities returns the capabilities named by a bead's
+// needs:<capability> labels.
+func NeededCapabilities(labels []string) []string {
+	var needs []string
+	for _, l := range labels {
+		if capability, ok := strings.CutPrefix(l, LabelNeedsPrefix); ok && capability != "" {
+			needs = append(needs, capability)
+		}
+	}
+	return needs
+}
+
+// RigLoad is a rig's polecat usage against its capacity config.
+type RigLoad struct {
+	Rig    string
+	Active int // Running polecat sessions
+	Queued int // Sling contexts waiting for a free slot in this rig
+	Config *RigConfig
+}
+
+// Max returns the rig's polecat cap, or 0 when it has none.
+func (l RigLoad) Max() int {
+	if l.Config == nil {
+		return 0
+	}
+	return l.Config.MaxPolecats
+}
+
+// Free returns the number of polecats the rig can start before it is
+// saturated. Rigs without a cap always have room.
+func (l RigLoad) Free() int {
+	if l.Max() == 0 {
+		return 1
+	}
+	if free := l.Max() - l.Active; free > 0 {
+		return free
+	}
+	return 0
+}
+
+// Saturated reports whether the rig is at its polecat cap.
+func (l RigLoad) Saturated() bool {
+	return l.Max() > 0 && l.Active >= l.Max()
+}
+
+// CanScale reports whether a scale-up has burst headroom left.
+func (l RigLoad) CanScale() bool {
+	return l.Saturated() && l.Active < l.Max()+l.Config.Burst
+}
+
+// Alternatives returns the unsaturated rigs other than exclude that
+// advertise every capability in needs, most free slots first. Rigs that
+// advertise no capabilities are never alternatives, and neither is anything
+// when needs is empty: there is nothing to tell a suitable rig by.
+func Alternatives(loads []RigLoad, exclude string, needs []string) []RigLoad {
+	if len(needs) == 0 {
+		return nil
+	}
+	var out []RigLoad
+	for _, l := range loads {
+		if l.Rig == exclude || l.Saturated() || l.Config == nil || len(l.Config.Capabilities) == 0 {
+			continue
+		}
+		if l.Config.Has(needs) {
+			out = append(out, l)
+		}
+	}
+	// Uncapped rigs first, then by free slots.
+	sort.SliceStable(out, func(i, j int) bool {
+		if (out[i].Max() == 0) != (out[j].Max() == 0) {
+			return out[i].Max() == 0
+		}
+		return out[i].Free() > out[j].Free()
+	})
+	return out
+}
+
+// EstimateWait estimates how long the bead at 1-based queue position waits
+// for a slot when slots polecats each take about typical per bead. It is
+// zero when there is no history to go on.
+func EstimateWait(position, slots int, typical time.Duration) time.Duration {
+	if position <= 0 || slots <= 0 || typical <= 0 {
+		return 0
+	}
+	rounds := (position + slots - 1) / slots
+	return time.Duration(rounds) * typical
+}
+
+// TypicalDuration returns the median of durations, ignoring non-positive
+// ones, or zero when none are left.
+func TypicalDuration(durations []time.Duration) time.Duration {
+	var ds []time.Duration
+	for _, d := range durations {
+		if d > 0 {
+			ds = append(ds, d)
+		}
+	}
+	if len(ds) == 0 {
+		return 0
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+	return ds[len(ds)/2]
+}
+
+// LimitToRigCapacity keeps, oldest first, at most free[rig] beads for each
+// rig in free. Beads for rigs not in free (no per-rig cap) pass through.
+func LimitToRigCapacity(pending []PendingBead, free map[string]int) []PendingBead {
+	taken := make(map[string]int)
+	var out []PendingBead
+	for _, b := range pending {
+		if slots, capped := free[b.TargetRig]; capped {
+			if taken[b.TargetRig] >= slots {
+				continue
+			}
+			taken[b.TargetRig]++
+		}
+		out = append(out, b)
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+package capacity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRigLoad(t *testing.T) {
+	cfg := &RigConfig{MaxPolecats: 2, Burst: 1}
+	for _, tc := range []struct {
+		active              int
+		saturated, canScale bool
+		free                int
+	}{
+		{1, false, false, 1},
+		{2, true, true, 0},
+		{3, true, false, 0},
+	} {
+		l := RigLoad{Rig: "app", Active: tc.active, Config: cfg}
+		if l.Saturated() != tc.saturated || l.CanScale() != tc.canScale || l.Free() != tc.free {
+			t.Errorf("active %d: saturated %v, canScale %v, free %d", tc.active, l.Saturated(), l.CanScale(), l.Free())
+		}
+	}
+	if l := (RigLoad{Rig: "app", Active: 40}); l.Saturated() || l.Free() != 1 {
+		t.Errorf("uncapped rig = saturated %v, free %d", l.Saturated(), l.Free())
+	}
+}
+
+func TestAlternatives(t *testing.T) {
+	loads := []RigLoad{
+		{Rig: "app", Active: 2, Config: &RigConfig{MaxPolecats: 2, Capabilities: []string{"go"}}},
+		{Rig: "full", Active: 3, Config: &RigConfig{MaxPolecats: 3, Capabilities: []string{"go"}}},
+		{Rig: "web", Active: 1, Config: &RigConfig{MaxPolecats: 2, Capabilities: []string{"Go", "frontend"}}},
+		{Rig: "tools", Active: 0, Config: &RigConfig{MaxPolecats: 4, Capabilities: []string{"go"}}},
+		{Rig: "docs", Active: 0, Config: &RigConfig{Capabilities: []string{"go"}}},
+		{Rig: "bare", Active: 0},
+	}
+	got := Alternatives(loads, "app", []string{"go"})
+	var rigs []string
+	for _, l := range got {
+		rigs = append(rigs, l.Rig)
+	}
+	if len(rigs) != 3 || rigs[0] != "docs" || rigs[1] != "tools" || rigs[2] != "web" {
+		t.Errorf("Alternatives = %v", rigs)
+	}
+	if got := Alternatives(loads, "app", []string{"go", "frontend"}); len(got) != 1 || got[0].Rig != "web" {
+		t.Errorf("frontend alternatives = %+v", got)
+	}
+	if got := Alternatives(loads, "app", nil); got != nil {
+		t.Errorf("no needs = %+v", got)
+	}
+}
+
+func TestNeededCapabilities(t *testing.T) {
+	got := NeededCapabilities([]string{"bug", "needs:frontend", "needs:", "needs:go"})
+	if len(got) != 2 || got[0] != "frontend" || got[1] != "go" {
+		t.Errorf("NeededCapabilities = %v", got)
+	}
+}
+
+func TestEstimateWait(t *testing.T) {
+	hour := time.Hour
+	for _, tc := range []struct {
+		position, slots int
+		typical         time.Duration
+		want            time.Duration
+	}{
+		{1, 2, hour, hour},
+		{2, 2, hour, hour},
+		{3, 2, hour, 2 * hour},
+		{1, 2, 0, 0},
+		{1, 0, hour, 0},
+	} {
+		if got := EstimateWait(tc.position, tc.slots, tc.typical); got != tc.want {
+			t.Errorf("EstimateWait(%d, %d, %v) = %v, want %v", tc.position, tc.slots, tc.typical, got, tc.want)
+		}
+	}
+	if got := TypicalDuration([]time.Duration{3 * hour, 0, hour, 2 * hour}); got != 2*hour {
+		t.Errorf("TypicalDuration = %v", got)
+	}
+}
+
+func TestLimitToRigCapacity(t *testing.T) {
+	pending := []PendingBead{
+		{ID: "a", TargetRig: "app"},
+		{ID: "b", TargetRig: "app"},
+		{ID: "c", TargetRig: "web"},
+		{ID: "d", TargetRig: "full"},
+	}
+	got := LimitToRigCapacity(pending, map[string]int{"app": 1, "full": 0})
+	if len(got) != 2 || got[0].ID != "a" || got[1].ID != "c" {
+		t.Errorf("LimitToRigCapacity = %+v", got)
+	}
+}
+
+func TestRigConfigValidate(t *testing.T) {
+	if err := (&RigConfig{MaxPolecats: 2, Burst: 1, Capabilities: []string{"go"}}).Validate(); err != nil {
+		t.Errorf("valid config: %v", err)
+	}
+	for _, cfg := range []*RigConfig{
+		{MaxPolecats: -1},
+		{MaxPolecats: 2, Burst: -1},
+		{Burst: 1},
+		{Capabilities: []string{" "}},
+	} {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%+v should not validate", cfg)
+		}
+	}
+}
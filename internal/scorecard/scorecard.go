@@ -0,0 +1,303 @@
+// Package scorecard scores polecats on how their work turns out over a
+// rolling window: how much of what they were slung they finished, how
+// often it bounced in review, what a bead cost, how often they stalled and
+// how many bugs were later linked back to their work.
+// Cards group by agent preset, so underperforming configurations stand out.
+package scorecard
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beadlink"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Thresholds for Flags. A card needs MinBeads assigned beads (or reviewed,
+// for bounces) before it is judged.
+const (
+	MinBeads          = 3
+	LowCompletionRate = 0.5
+	HighBounceRate    = 0.3
+	HighStallsPerBead = 1.0
+)
+
+// Group keys for Group.
+const (
+	ByPolecat = "polecat"
+	ByRig     = "rig"
+	ByConfig  = "config"
+)
+
+// Cost is one polecat session's cost.
+type Cost struct {
+	Rig     string
+	Worker  string // Polecat name
+	CostUSD float64
+	EndedAt time.Time
+}
+
+// Card is one polecat's (or group's) scorecard.
+type Card struct {
+	Key       string  `json:"key"`              // Polecat address, rig or config, per the grouping
+	Rig       string  `json:"rig,omitempty"`    // Empty when grouped by config
+	Config    string  `json:"config,omitempty"` // Agent preset the polecat last spawned with
+	Polecats  int     `json:"polecats"`
+	Assigned  int     `json:"assigned"`  // Beads slung or hooked to it
+	Completed int     `json:"completed"` // Of those, finished with gt done
+	Reviewed  int     `json:"reviewed"`  // Beads that reached the merge queue
+	Bounced   int     `json:"bounced"`   // Of those, beads with at least one failed merge
+	Stalls    int     `json:"stalls"`    // Stalls the witness classified
+	Caused    int     `json:"caused"`    // Beads later linked caused-by to its work
+	CostUSD   float64 `json:"cost_usd"`
+}
+
+// CompletionRate is the share of assigned beads finished with gt done.
+func (c Card) CompletionRate() float64 { return ratio(c.Completed, c.Assigned) }
+
+// BounceRate is the share of reviewed beads that failed a merge at least once.
+func (c Card) BounceRate() float64 { return ratio(c.Bounced, c.Reviewed) }
+
+// CostPerBead is the session cost per assigned bead.
+func (c Card) CostPerBead() float64 {
+	if c.Assigned == 0 {
+		return 0
+	}
+	return c.CostUSD / float64(c.Assigned)
+}
+
+// StallsPerBead is the number of stalls per assigned bead.
+func (c Card) StallsPerBead() float64 { return ratio(c.Stalls, c.Assigned) }
+
+// Flags names what makes the card underperform, if anything.
+func (c Card) Flags() []string {
+	var flags []string
+	if c.Assigned >= MinBeads && c.CompletionRate() < LowCompletionRate {
+		flags = append(flags, "low completion")
+	}
+	if c.Reviewed >= MinBeads && c.BounceRate() >= HighBounceRate {
+		flags = append(flags, "bounces")
+	}
+	if c.Assigned >= MinBeads && c.StallsPerBead() >= HighStallsPerBead {
+		flags = append(flags, "stalls")
+	}
+	return flags
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// polecat accumulates one polecat's work while folding the event log.
+type polecat struct {
+	config    string
+	assigned  map[string]bool
+	completed map[string]bool
+	merged    map[string]bool
+	bounced   map[string]bool
+	caused    map[string]bool // Beads linked caused-by to its work
+	stalls    int
+	costUSD   float64
+}
+
+// Compute folds events and costs at or after since into one card per
+// polecat, sorted by address. Merges are attributed through the bead ID in
+// the branch name to the polecat that last held the bead; stalls through
+// the stall event's rig and polecat; caused-by links through the culprit
+// bead to the polecat that last held it.
+func Compute(evs []events.Event, costs []Cost, since time.Time) []Card {
+	polecats := make(map[string]*polecat)
+	get := func(agent string) *polecat {
+		p := polecats[agent]
+		if p == nil {
+			p = &polecat{assigned: map[string]bool{}, completed: map[string]bool{}, merged: map[string]bool{}, bounced: map[string]bool{}, caused: map[string]bool{}}
+			polecats[agent] = p
+		}
+		return p
+	}
+	holder := make(map[string]string) // bead → polecat that last held it
+
+	assign := func(bead, agent string) {
+		agent = strings.TrimSuffix(agent, "/")
+		if bead == "" || !isPolecat(agent) {
+			return
+		}
+		get(agent).assigned[bead] = true
+		holder[bead] = agent
+	}
+
+	for _, e := range evs {
+		ts, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err == nil && ts.Before(since) {
+			continue
+		}
+		bead, _ := e.Payload["bead"].(string)
+		rig, _ := e.Payload["rig"].(string)
+		name, _ := e.Payload["polecat"].(string)
+		switch e.Type {
+		case events.TypeSpawn:
+			if agent, _ := e.Payload["agent"].(string); agent != "" && rig != "" && name != "" {
+				get(rig + "/polecats/" + name).config = agent
+			}
+		case events.TypeSling:
+			target, _ := e.Payload["target"].(string)
+			assign(bead, target)
+		case events.TypeHook:
+			assign(bead, e.Actor)
+		case events.TypeSchedulerDispatch:
+			if rig != "" && name != "" {
+				assign(bead, rig+"/polecats/"+name)
+			}
+		case events.TypeDone:
+			if agent := strings.TrimSuffix(e.Actor, "/"); isPolecat(agent) && bead != "" {
+				get(agent).completed[bead] = true
+				if !get(agent).assigned[bead] {
+					assign(bead, agent)
+				}
+			}
+		case events.TypeStallClassified:
+			if rig != "" && name != "" {
+				get(rig+"/polecats/"+name).stalls++
+			}
+		case events.TypeMerged, events.TypeMergeFailed:
+			branch, _ := e.Payload["branch"].(string)
+			b := beadInBranch(branch, holder)
+			if b == "" {
+				continue
+			}
+			p := get(holder[b])
+			if e.Type == events.TypeMerged {
+				p.merged[b] = true
+			} else {
+				p.bounced[b] = true
+			}
+		case events.TypeBeadLink:
+			typ, _ := e.Payload["type"].(string)
+			target, _ := e.Payload["target"].(string)
+			bug, culprit := bead, target
+			switch typ {
+			case beadlink.CausedBy:
+			case beadlink.Causes:
+				bug, culprit = target, bead
+			default:
+				continue
+			}
+			if agent, ok := holder[culprit]; ok && bug != "" {
+				linked, _ := e.Payload["linked"].(bool)
+				get(agent).caused[bug] = linked
+			}
+		}
+	}
+
+	for _, c := range costs {
+		if c.Rig == "" || c.Worker == "" || (!c.EndedAt.IsZero() && c.EndedAt.Before(since)) {
+			continue
+		}
+		get(c.Rig + "/polecats/" + c.Worker).costUSD += c.CostUSD
+	}
+
+	cards := make([]Card, 0, len(polecats))
+	for agent, p := range polecats {
+		reviewed := len(p.bounced)
+		for b := range p.merged {
+			if !p.bounced[b] {
+				reviewed++
+			}
+		}
+		cards = append(cards, Card{
+			Key:       agent,
+			Rig:       strings.SplitN(agent, "/", 2)[0],
+			Config:    p.config,
+			Polecats:  1,
+			Assigned:  len(p.assigned),
+			Completed: len(p.completed),
+			Reviewed:  reviewed,
+			Bounced:   len(p.bounced),
+			Stalls:    p.stalls,
+			Caused:    countTrue(p.caused),
+			CostUSD:   p.costUSD,
+		})
+	}
+	sort.Slice(cards, func(i, j int) bool { return cards[i].Key < cards[j].Key })
+	return cards
+}
+
+// Group sums per-polecat cards by rig or config (ByPolecat returns them
+// unchanged), sorted by key. Polecats with no known config group under
+// "unknown".
+func Group(cards []Card, by string) []Card {
+	if by == ByPolecat || by == "" {
+		return cards
+	}
+	groups := make(map[string]*Card)
+	var keys []string
+	for _, c := range cards {
+		key := c.Rig
+		if by == ByConfig {
+			key = c.Config
+			if key == "" {
+				key = "unknown"
+			}
+		}
+		g := groups[key]
+		if g == nil {
+			g = &Card{Key: key}
+			if by == ByRig {
+				g.Rig = key
+			} else {
+				g.Config = key
+			}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		g.Polecats += c.Polecats
+		g.Assigned += c.Assigned
+		g.Completed += c.Completed
+		g.Reviewed += c.Reviewed
+		g.Bounced += c.Bounced
+		g.Stalls += c.Stalls
+		g.Caused += c.Caused
+		g.CostUSD += c.CostUSD
+	}
+	sort.Strings(keys)
+	out := make([]Card, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, *groups[k])
+	}
+	return out
+}
+
+func countTrue(m map[string]bool) int {
+	n := 0
+	for _, v := range m {
+		if v {
+			n++
+		}
+	}
+	return n
+}
+
+// isPolecat reports whether agent is a polecat address (rig/polecats/name).
+func isPolecat(agent string) bool {
+	parts := strings.Split(agent, "/")
+	return len(parts) == 3 && parts[1] == "polecats" && parts[0] != "" && parts[2] != ""
+}
+
+// beadInBranch returns the longest known bead ID in a polecat branch name
+// (e.g. polecat/nux/gt-abc@mk123).
+func beadInBranch(branch string, holder map[string]string) string {
+	if branch == "" {
+		return ""
+	}
+	best := ""
+	for id := range holder {
+		if len(id) > len(best) && strings.Contains(branch, id) {
+			best = id
+		}
+	}
+	return best
+}
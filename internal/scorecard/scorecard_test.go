@@ -0,0 +1,139 @@
+package scorecard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+var t0 = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+func ev(at time.Duration, typ, actor string, payload map[string]interface{}) events.Event {
+	return events.Event{Timestamp: t0.Add(at).Format(time.RFC3339), Type: typ, Actor: actor, Payload: payload}
+}
+
+func spawn(at time.Duration, rig, polecat, agent string) events.Event {
+	p := events.SpawnPayload(rig, polecat)
+	p["agent"] = agent
+	return ev(at, events.TypeSpawn, "witness", p)
+}
+
+func testEvents() []events.Event {
+	return []events.Event{
+		// Too old for the window.
+		ev(-48*time.Hour, events.TypeHook, "gastown/polecats/nux", events.HookPayload("gt-old")),
+
+		spawn(0, "gastown", "nux", "claude"),
+		spawn(0, "gastown", "toast", "codex"),
+		spawn(0, "beads", "ace", "claude"),
+
+		ev(time.Minute, events.TypeHook, "gastown/polecats/nux", events.HookPayload("gt-a")),
+		ev(time.Minute, events.TypeHook, "gastown/polecats/nux", events.HookPayload("gt-b")),
+		ev(time.Minute, events.TypeSchedulerDispatch, "daemon", events.SchedulerDispatchPayload("gt-c", "gastown", "toast")),
+		ev(time.Minute, events.TypeHook, "gastown/polecats/toast", events.HookPayload("gt-d")),
+		ev(time.Minute, events.TypeHook, "gastown/polecats/toast", events.HookPayload("gt-e")),
+		ev(time.Minute, events.TypeHook, "beads/polecats/ace", events.HookPayload("bd-a")),
+		ev(time.Minute, events.TypeHook, "mayor/", events.HookPayload("hq-1")),
+
+		ev(time.Hour, events.TypeDone, "gastown/polecats/nux", events.DonePayload("gt-a", "polecat/nux/gt-a@x")),
+		ev(time.Hour, events.TypeDone, "gastown/polecats/nux", events.DonePayload("gt-b", "polecat/nux/gt-b@x")),
+		ev(time.Hour, events.TypeDone, "beads/polecats/ace", events.DonePayload("bd-a", "polecat/ace/bd-a@x")),
+		ev(2*time.Hour, events.TypeStallClassified, "witness", events.StallPayload("gastown", "toast", "idle", "nudge")),
+		ev(3*time.Hour, events.TypeStallClassified, "witness", events.StallPayload("gastown", "toast", "idle", "restart")),
+		ev(4*time.Hour, events.TypeStallClassified, "witness", events.StallPayload("gastown", "toast", "idle", "restart")),
+
+		ev(2*time.Hour, events.TypeMergeFailed, "refinery", events.MergePayload("mr-1", "nux", "polecat/nux/gt-a@x", "tests failed")),
+		ev(3*time.Hour, events.TypeMerged, "refinery", events.MergePayload("mr-2", "nux", "polecat/nux/gt-a@y", "")),
+		ev(2*time.Hour, events.TypeMerged, "refinery", events.MergePayload("mr-3", "nux", "polecat/nux/gt-b@x", "")),
+		ev(2*time.Hour, events.TypeMerged, "refinery", events.MergePayload("mr-4", "max", "polecat/max/gt-zzz@x", "")),
+	}
+}
+
+func TestCompute(t *testing.T) {
+	costs := []Cost{
+		{Rig: "gastown", Worker: "nux", CostUSD: 3, EndedAt: t0.Add(time.Hour)},
+		{Rig: "gastown", Worker: "nux", CostUSD: 100, EndedAt: t0.Add(-48 * time.Hour)},
+		{Rig: "gastown", Worker: "toast", CostUSD: 6, EndedAt: t0.Add(time.Hour)},
+	}
+	cards := Compute(testEvents(), costs, t0.Add(-time.Hour))
+
+	if len(cards) != 3 {
+		t.Fatalf("got %d cards, want 3: %+v", len(cards), cards)
+	}
+	ace, nux, toast := cards[0], cards[1], cards[2]
+	if ace.Key != "beads/polecats/ace" || nux.Key != "gastown/polecats/nux" || toast.Key != "gastown/polecats/toast" {
+		t.Fatalf("keys = %s, %s, %s", ace.Key, nux.Key, toast.Key)
+	}
+
+	if nux.Config != "claude" || nux.Rig != "gastown" || nux.Assigned != 2 || nux.Completed != 2 {
+		t.Errorf("nux = %+v", nux)
+	}
+	if nux.Reviewed != 2 || nux.Bounced != 1 || nux.BounceRate() != 0.5 {
+		t.Errorf("nux reviews = %d reviewed, %d bounced", nux.Reviewed, nux.Bounced)
+	}
+	if nux.CostPerBead() != 1.5 {
+		t.Errorf("nux cost per bead = %v, want 1.5", nux.CostPerBead())
+	}
+
+	if toast.Config != "codex" || toast.Assigned != 3 || toast.Completed != 0 || toast.Stalls != 3 {
+		t.Errorf("toast = %+v", toast)
+	}
+	if got := toast.Flags(); len(got) != 2 || got[0] != "low completion" || got[1] != "stalls" {
+		t.Errorf("toast flags = %v", got)
+	}
+	if got := nux.Flags(); len(got) != 0 {
+		t.Errorf("nux flags = %v; too few beads to judge", got)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	cards := Compute(testEvents(), nil, t0.Add(-time.Hour))
+	cards = append(cards, Card{Key: "gastown/polecats/max", Rig: "gastown", Polecats: 1, Assigned: 1})
+
+	byConfig := Group(cards, ByConfig)
+	if len(byConfig) != 3 {
+		t.Fatalf("by config = %+v", byConfig)
+	}
+	claude, codex, unknown := byConfig[0], byConfig[1], byConfig[2]
+	if claude.Key != "claude" || claude.Polecats != 2 || claude.Assigned != 3 || claude.Completed != 3 {
+		t.Errorf("claude = %+v", claude)
+	}
+	if codex.Key != "codex" || codex.Polecats != 1 || codex.Stalls != 3 {
+		t.Errorf("codex = %+v", codex)
+	}
+	if unknown.Key != "unknown" || unknown.Polecats != 1 {
+		t.Errorf("unknown = %+v", unknown)
+	}
+
+	byRig := Group(cards, ByRig)
+	if len(byRig) != 2 || byRig[0].Key != "beads" || byRig[1].Key != "gastown" || byRig[1].Polecats != 3 || byRig[1].Assigned != 6 {
+		t.Errorf("by rig = %+v", byRig)
+	}
+
+	if got := Group(cards, ByPolecat); len(got) != len(cards) {
+		t.Errorf("by polecat = %d cards, want %d", len(got), len(cards))
+	}
+}
+
+func TestComputeCaused(t *testing.T) {
+	link := func(at time.Duration, bead, typ, target string, linked bool) events.Event {
+		return ev(at, events.TypeBeadLink, "mayor/", map[string]interface{}{"bead": bead, "type": typ, "target": target, "linked": linked})
+	}
+	evs := append(testEvents(),
+		link(5*time.Hour, "gt-bug1", "caused-by", "gt-a", true),
+		link(5*time.Hour, "gt-b", "causes", "gt-bug2", true),
+		link(5*time.Hour, "gt-bug3", "caused-by", "gt-b", true),
+		link(6*time.Hour, "gt-b", "causes", "gt-bug3", false), // Unlinked again
+		link(5*time.Hour, "gt-bug4", "relates-to", "gt-a", true),
+		link(5*time.Hour, "gt-bug5", "caused-by", "gt-nobody", true),
+	)
+	cards := Compute(evs, nil, t0.Add(-time.Hour))
+	nux := cards[1]
+	if nux.Key != "gastown/polecats/nux" || nux.Caused != 2 {
+		t.Errorf("nux = %+v, want 2 caused", nux)
+	}
+	if got := Group(cards, ByConfig); got[0].Key != "claude" || got[0].Caused != 2 {
+		t.Errorf("claude = %+v, want 2 caused", got[0])
+	}
+}
@@ -0,0 +1,356 @@
+// Package selfupdate downloads and installs gt release binaries.
+//
+// Releases are the GitHub releases built by .goreleaser.yml: one archive
+// per platform named gastown_<version>_<os>_<arch>.tar.gz (.zip on
+// Windows) and a checksums.txt of SHA-256 sums. The archive is verified
+// against checksums.txt, and checksums.txt against an ed25519 signature
+// when the release publishes checksums.txt.sig and a public key is
+// configured.
+//
+// Install keeps the replaced binary next to the new one so a failed health
+// check can roll back.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// DefaultRepo is the GitHub repository releases are fetched from.
+const DefaultRepo = "steveyegge/gastown"
+
+// DefaultAPIBase is the GitHub API endpoint.
+const DefaultAPIBase = "https://api.github.com"
+
+// ChecksumsAsset and SignatureAsset are the release's checksum file and its
+// detached signature.
+const (
+	ChecksumsAsset = "checksums.txt"
+	SignatureAsset = "checksums.txt.sig"
+)
+
+// maxDownload bounds asset downloads.
+const maxDownload = 256 << 20
+
+var (
+	// ErrChecksum indicates a downloaded archive does not match checksums.txt.
+	ErrChecksum = errors.New("checksum mismatch")
+	// ErrSignature indicates checksums.txt failed signature verification.
+	ErrSignature = errors.New("signature verification failed")
+	// ErrIncompatible indicates a binary cannot operate the town's schemas.
+	ErrIncompatible = errors.New("incompatible with town")
+)
+
+// Release is a GitHub release.
+type Release struct {
+	Tag        string  `json:"tag_name"`
+	Prerelease bool    `json:"prerelease"`
+	Assets     []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Version returns the release version without the leading "v".
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.Tag, "v")
+}
+
+// Asset returns the asset with the given name.
+func (r *Release) Asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// Client fetches releases and assets.
+type Client struct {
+	HTTP    *http.Client
+	APIBase string
+	Repo    string
+}
+
+// NewClient returns a client for the default repository. GT_UPGRADE_API
+// overrides the API endpoint (for mirrors and tests).
+func NewClient() *Client {
+	base := DefaultAPIBase
+	if v := os.Getenv("GT_UPGRADE_API"); v != "" {
+		base = strings.TrimRight(v, "/")
+	}
+	return &Client{
+		HTTP:    &http.Client{Timeout: 5 * time.Minute},
+		APIBase: base,
+		Repo:    DefaultRepo,
+	}
+}
+
+// Latest returns the latest non-prerelease release.
+func (c *Client) Latest(ctx context.Context) (*Release, error) {
+	return c.release(ctx, "/repos/"+c.Repo+"/releases/latest")
+}
+
+// Tag returns the release for a version ("0.13.0" or "v0.13.0").
+func (c *Client) Tag(ctx context.Context, version string) (*Release, error) {
+	return c.release(ctx, "/repos/"+c.Repo+"/releases/tags/v"+strings.TrimPrefix(version, "v"))
+}
+
+func (c *Client) release(ctx context.Context, apiPath string) (*Release, error) {
+	data, err := c.get(ctx, c.APIBase+apiPath)
+	if err != nil {
+		return nil, err
+	}
+	var r Release
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing release: %w", err)
+	}
+	if r.Tag == "" {
+		return nil, fmt.Errorf("release response from %s has no tag", apiPath)
+	}
+	return &r, nil
+}
+
+// Download fetches an asset.
+func (c *Client) Download(ctx context.Context, a Asset) ([]byte, error) {
+	return c.get(ctx, a.URL)
+}
+
+func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" && strings.HasPrefix(url, c.APIBase) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownload+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", url, err)
+	}
+	if len(data) > maxDownload {
+		return nil, fmt.Errorf("GET %s: larger than %d MB", url, maxDownload>>20)
+	}
+	return data, nil
+}
+
+// ArchiveName returns the release archive for a platform.
+func ArchiveName(version, goos, goarch string) string {
+	ext := "tar.gz"
+	if goos == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("gastown_%s_%s_%s.%s", strings.TrimPrefix(version, "v"), goos, goarch, ext)
+}
+
+// CurrentArchiveName returns the release archive for this platform.
+func CurrentArchiveName(version string) string {
+	return ArchiveName(version, runtime.GOOS, runtime.GOARCH)
+}
+
+// VerifyChecksum checks data against the entry for name in a checksums.txt
+// ("<sha256>  <name>" per line).
+func VerifyChecksum(data, checksums []byte, name string) error {
+	sum := sha256.Sum256(data)
+	got := hex.EncodeToString(sum[:])
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			if !strings.EqualFold(fields[0], got) {
+				return fmt.Errorf("%w: %s is %s, checksums.txt says %s", ErrChecksum, name, got, fields[0])
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s not listed in checksums.txt", ErrChecksum, name)
+}
+
+// VerifySignature checks a base64 ed25519 signature of checksums.txt
+// against a base64 public key.
+func VerifySignature(checksums, sig []byte, publicKey string) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: invalid public key", ErrSignature)
+	}
+	rawSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return fmt.Errorf("%w: signature is not base64", ErrSignature)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), checksums, rawSig) {
+		return ErrSignature
+	}
+	return nil
+}
+
+// ExtractBinary returns the gt executable from a release archive.
+func ExtractBinary(archive []byte, name string) ([]byte, error) {
+	want := "gt"
+	if strings.HasSuffix(name, ".zip") {
+		want = "gt.exe"
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+		for _, f := range zr.File {
+			if path.Base(f.Name) == want {
+				rc, err := f.Open()
+				if err != nil {
+					return nil, err
+				}
+				defer rc.Close()
+				return io.ReadAll(io.LimitReader(rc, maxDownload))
+			}
+		}
+		return nil, fmt.Errorf("%s has no %s", name, want)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s has no %s", name, want)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == want {
+			return io.ReadAll(io.LimitReader(tr, maxDownload))
+		}
+	}
+}
+
+// Stage writes bin next to exe as exe+".new", ready for Install.
+func Stage(exe string, bin []byte) (string, error) {
+	staged := exe + ".new"
+	if err := os.WriteFile(staged, bin, 0755); err != nil { //nolint:gosec // G306: executables need the x bit
+		return "", fmt.Errorf("writing %s: %w", staged, err)
+	}
+	return staged, nil
+}
+
+// Install replaces exe with the staged binary, keeping the old one as
+// exe+".old" for Rollback. Both renames stay in exe's directory, so the
+// swap never leaves exe missing on the same filesystem.
+func Install(exe, staged string) (backup string, err error) {
+	backup = exe + ".old"
+	_ = os.Remove(backup)
+	if err := os.Rename(exe, backup); err != nil {
+		return "", fmt.Errorf("backing up %s: %w", exe, err)
+	}
+	if err := os.Rename(staged, exe); err != nil {
+		_ = os.Rename(backup, exe)
+		return "", fmt.Errorf("installing %s: %w", exe, err)
+	}
+	return backup, nil
+}
+
+// Rollback restores the binary Install replaced.
+func Rollback(exe, backup string) error {
+	if err := os.Rename(backup, exe); err != nil {
+		return fmt.Errorf("restoring %s from %s: %w", exe, backup, err)
+	}
+	return nil
+}
+
+// BinaryInfo is what a gt binary reports about itself (gt version --json).
+type BinaryInfo struct {
+	Version    string `json:"version"`
+	Build      string `json:"build"`
+	Commit     string `json:"commit,omitempty"`
+	TownSchema int    `json:"town_schema"` // Newest mayor/town.json version it reads
+	RigsSchema int    `json:"rigs_schema"` // Newest mayor/rigs.json version it reads
+}
+
+// Inspect runs a gt binary's version command. Binaries older than
+// version --json report only their version, with TownSchema 0.
+func Inspect(bin string) (*BinaryInfo, error) {
+	run := func(args ...string) ([]byte, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		return exec.CommandContext(ctx, bin, args...).Output() //nolint:gosec // G204: the binary being installed
+	}
+	if out, err := run("version", "--json"); err == nil {
+		var info BinaryInfo
+		if err := json.Unmarshal(out, &info); err != nil {
+			return nil, fmt.Errorf("parsing %s version output: %w", bin, err)
+		}
+		return &info, nil
+	}
+	out, err := run("version", "--short")
+	if err != nil {
+		return nil, fmt.Errorf("running %s version: %w", bin, err)
+	}
+	version, build, _ := strings.Cut(strings.TrimSpace(string(out)), "-")
+	return &BinaryInfo{Version: version, Build: build}, nil
+}
+
+// TownSchemas reads the schema versions of a town's mayor/town.json and
+// mayor/rigs.json without validating them, so towns newer than this
+// binary can still be inspected. Missing files read as 0.
+func TownSchemas(townRoot string) (town, rigs int) {
+	read := func(name string) int {
+		data, err := os.ReadFile(filepath.Join(townRoot, "mayor", name)) //nolint:gosec // G304: path is constructed internally
+		if err != nil {
+			return 0
+		}
+		var v struct {
+			Version int `json:"version"`
+		}
+		_ = json.Unmarshal(data, &v)
+		return v.Version
+	}
+	return read("town.json"), read("rigs.json")
+}
+
+// CheckCompatibility returns ErrIncompatible if the binary cannot read a
+// town at the given schema versions (a downgrade past a migration).
+// Binaries that predate schema reporting (TownSchema 0) are not checked.
+func CheckCompatibility(info *BinaryInfo, townSchema, rigsSchema int) error {
+	if info.TownSchema == 0 {
+		return nil
+	}
+	if townSchema > info.TownSchema {
+		return fmt.Errorf("%w: town.json is schema v%d, gt %s reads up to v%d", ErrIncompatible, townSchema, info.Version, info.TownSchema)
+	}
+	if info.RigsSchema > 0 && rigsSchema > info.RigsSchema {
+		return fmt.Errorf("%w: rigs.json is schema v%d, gt %s reads up to v%d", ErrIncompatible, rigsSchema, info.Version, info.RigsSchema)
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, body := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func checksumLine(data []byte, name string) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]) + "  " + name + "\n"
+}
+
+func TestArchiveName(t *testing.T) {
+	if got := ArchiveName("v0.13.0", "darwin", "arm64"); got != "gastown_0.13.0_darwin_arm64.tar.gz" {
+		t.Errorf("ArchiveName = %q", got)
+	}
+	if got := ArchiveName("0.13.0", "windows", "amd64"); got != "gastown_0.13.0_windows_amd64.zip" {
+		t.Errorf("ArchiveName = %q", got)
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("archive bytes")
+	sums := []byte(checksumLine([]byte("other"), "gastown_1_linux_arm64.tar.gz") + checksumLine(data, "gastown_1_linux_amd64.tar.gz"))
+
+	if err := VerifyChecksum(data, sums, "gastown_1_linux_amd64.tar.gz"); err != nil {
+		t.Errorf("matching checksum: %v", err)
+	}
+	if err := VerifyChecksum([]byte("tampered"), sums, "gastown_1_linux_amd64.tar.gz"); !errors.Is(err, ErrChecksum) {
+		t.Errorf("tampered archive: err = %v, want ErrChecksum", err)
+	}
+	if err := VerifyChecksum(data, sums, "gastown_1_freebsd_amd64.tar.gz"); !errors.Is(err, ErrChecksum) {
+		t.Errorf("unlisted archive: err = %v, want ErrChecksum", err)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sums := []byte("abc  gastown_1_linux_amd64.tar.gz\n")
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, sums))
+	key := base64.StdEncoding.EncodeToString(pub)
+
+	if err := VerifySignature(sums, []byte(sig+"\n"), key); err != nil {
+		t.Errorf("valid signature: %v", err)
+	}
+	if err := VerifySignature([]byte("changed"), []byte(sig), key); !errors.Is(err, ErrSignature) {
+		t.Errorf("changed checksums: err = %v, want ErrSignature", err)
+	}
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	if err := VerifySignature(sums, []byte(sig), base64.StdEncoding.EncodeToString(otherPub)); !errors.Is(err, ErrSignature) {
+		t.Errorf("wrong key: err = %v, want ErrSignature", err)
+	}
+	if err := VerifySignature(sums, []byte(sig), "not-a-key"); !errors.Is(err, ErrSignature) {
+		t.Errorf("invalid key: err = %v, want ErrSignature", err)
+	}
+}
+
+func TestExtractBinary(t *testing.T) {
+	archive := tarGz(t, map[string]string{"README.md": "readme", "gt": "binary"})
+	bin, err := ExtractBinary(archive, "gastown_1_linux_amd64.tar.gz")
+	if err != nil || string(bin) != "binary" {
+		t.Errorf("tar.gz: %q, %v", bin, err)
+	}
+	if _, err := ExtractBinary(tarGz(t, map[string]string{"LICENSE": "x"}), "a.tar.gz"); err == nil {
+		t.Error("archive without gt should fail")
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create("gt.exe")
+	_, _ = w.Write([]byte("windows binary"))
+	_ = zw.Close()
+	bin, err = ExtractBinary(buf.Bytes(), "gastown_1_windows_amd64.zip")
+	if err != nil || string(bin) != "windows binary" {
+		t.Errorf("zip: %q, %v", bin, err)
+	}
+}
+
+func TestInstallAndRollback(t *testing.T) {
+	dir := t.TempDir()
+	exe := filepath.Join(dir, "gt")
+	if err := os.WriteFile(exe, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	staged, err := Stage(exe, []byte("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup, err := Install(exe, staged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(exe); string(got) != "new" {
+		t.Errorf("installed = %q, want new", got)
+	}
+	if got, _ := os.ReadFile(backup); string(got) != "old" {
+		t.Errorf("backup = %q, want old", got)
+	}
+	if runtime.GOOS != "windows" {
+		if info, _ := os.Stat(exe); info.Mode()&0100 == 0 {
+			t.Error("installed binary is not executable")
+		}
+	}
+
+	if err := Rollback(exe, backup); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(exe); string(got) != "old" {
+		t.Errorf("after rollback = %q, want old", got)
+	}
+}
+
+func TestClient_FetchesReleaseAndAssets(t *testing.T) {
+	archive := tarGz(t, map[string]string{"gt": "binary"})
+	name := ArchiveName("0.13.0", "linux", "amd64")
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/" + DefaultRepo + "/releases/latest", "/repos/" + DefaultRepo + "/releases/tags/v0.13.0":
+			_ = json.NewEncoder(w).Encode(Release{Tag: "v0.13.0", Assets: []Asset{
+				{Name: name, URL: srv.URL + "/dl/" + name},
+				{Name: ChecksumsAsset, URL: srv.URL + "/dl/" + ChecksumsAsset},
+			}})
+		case "/dl/" + name:
+			_, _ = w.Write(archive)
+		case "/dl/" + ChecksumsAsset:
+			_, _ = fmt.Fprint(w, checksumLine(archive, name))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("GT_UPGRADE_API", srv.URL)
+
+	c := NewClient()
+	ctx := context.Background()
+	for _, get := range []func() (*Release, error){
+		func() (*Release, error) { return c.Latest(ctx) },
+		func() (*Release, error) { return c.Tag(ctx, "v0.13.0") },
+	} {
+		rel, err := get()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rel.Version() != "0.13.0" {
+			t.Errorf("Version = %q", rel.Version())
+		}
+	}
+	if _, err := c.Tag(ctx, "9.9.9"); err == nil {
+		t.Error("missing release should fail")
+	}
+
+	rel, _ := c.Latest(ctx)
+	asset, ok := rel.Asset(name)
+	if !ok {
+		t.Fatalf("no %s asset", name)
+	}
+	data, err := c.Download(ctx, asset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sumsAsset, _ := rel.Asset(ChecksumsAsset)
+	sums, err := c.Download(ctx, sumsAsset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyChecksum(data, sums, name); err != nil {
+		t.Errorf("downloaded archive fails its checksum: %v", err)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake gt script requires a POSIX shell")
+	}
+	dir := t.TempDir()
+	current := filepath.Join(dir, "gt-current")
+	script := "#!/bin/sh\n[ \"$2\" = --json ] && echo '{\"version\":\"0.13.0\",\"build\":\"abc\",\"town_schema\":3,\"rigs_schema\":1}'\n"
+	if err := os.WriteFile(current, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	info, err := Inspect(current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != "0.13.0" || info.TownSchema != 3 {
+		t.Errorf("Inspect = %+v", info)
+	}
+
+	// Binaries that predate --json fall back to --short.
+	legacy := filepath.Join(dir, "gt-legacy")
+	script = "#!/bin/sh\n[ \"$2\" = --short ] || { echo 'unknown flag' >&2; exit 1; }\necho 0.11.0-42\n"
+	if err := os.WriteFile(legacy, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	info, err = Inspect(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != "0.11.0" || info.Build != "42" || info.TownSchema != 0 {
+		t.Errorf("legacy Inspect = %+v", info)
+	}
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	info := &BinaryInfo{Version: "0.12.0", TownSchema: 2, RigsSchema: 1}
+	if err := CheckCompatibility(info, 2, 1); err != nil {
+		t.Errorf("same schemas: %v", err)
+	}
+	if err := CheckCompatibility(info, 1, 1); err != nil {
+		t.Errorf("older town should be migrated, not refused: %v", err)
+	}
+	if err := CheckCompatibility(info, 3, 1); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("newer town.json: err = %v, want ErrIncompatible", err)
+	}
+	if err := CheckCompatibility(info, 2, 2); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("newer rigs.json: err = %v, want ErrIncompatible", err)
+	}
+	if err := CheckCompatibility(&BinaryInfo{Version: "0.11.0"}, 9, 9); err != nil {
+		t.Errorf("binaries without schema info are not checked: %v", err)
+	}
+}
+
+func TestTownSchemas(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(town, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.WriteFile(filepath.Join(town, "mayor", "town.json"), []byte(`{"type":"town","version":5}`), 0644)
+	townV, rigsV := TownSchemas(town)
+	if townV != 5 || rigsV != 0 {
+		t.Errorf("TownSchemas = %d, %d; want 5, 0", townV, rigsV)
+	}
+}
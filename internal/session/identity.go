@@ -24,7 +24,7 @@ const (
 type AgentIdentity struct {
 	Role   Role   // mayor, deacon, witness, refinery, crew, polecat, dog
 	Rig    string // rig name (empty for mayor/deacon/dog)
-	Name   string // crew/polecat/dog name (empty for mayor/deacon/witness/refinery)
+	Name   string // crew/polecat/dog name, or mayor shard (empty for deacon/witness/refinery)
 	Prefix string // beads prefix for rig-level agents (e.g., "gt", "bd", "hop")
 }
 
@@ -46,6 +46,9 @@ func ParseAddress(address string) (*AgentIdentity, error) {
 	}
 
 	address = strings.TrimSuffix(address, "/")
+	if shard, ok := strings.CutPrefix(address, string(RoleMayor)+"/"); ok && !strings.Contains(shard, "/") {
+		return &AgentIdentity{Role: RoleMayor, Name: shard}, nil
+	}
 	parts := strings.Split(address, "/")
 	if len(parts) < 2 {
 		return nil, fmt.Errorf("invalid address %q", address)
@@ -87,6 +90,7 @@ func ParseAddress(address string) (*AgentIdentity, error) {
 //
 // Session name formats:
 //   - hq-mayor → Role: mayor (town-level, one per machine)
+//   - hq-mayor-<shard> → Role: mayor, Name: shard (shard mayor)
 //   - hq-deacon → Role: deacon (town-level, one per machine)
 //   - hq-boot → Role: deacon, Name: boot (boot watchdog)
 //   - <prefix>-witness → Role: witness (e.g., gt-witness for gastown)
@@ -121,6 +125,14 @@ func ParseSessionNameWithRegistry(session string, registry *PrefixRegistry) (*Ag
 		case "overseer":
 			return &AgentIdentity{Role: RoleOverseer}, nil
 		default:
+			// Shard mayors: hq-mayor-<shard>
+			if strings.HasPrefix(suffix, "mayor-") {
+				name := suffix[6:] // len("mayor-") = 6
+				if name == "" {
+					return nil, fmt.Errorf("invalid session name %q: empty mayor shard", session)
+				}
+				return &AgentIdentity{Role: RoleMayor, Name: name}, nil
+			}
 			// Dogs: hq-dog-<name>
 			if strings.HasPrefix(suffix, "dog-") {
 				name := suffix[4:] // len("dog-") = 4
@@ -173,6 +185,9 @@ func ParseSessionNameWithRegistry(session string, registry *PrefixRegistry) (*Ag
 func (a *AgentIdentity) SessionName() string {
 	switch a.Role {
 	case RoleMayor:
+		if a.Name != "" {
+			return MayorShardSessionName(a.Name)
+		}
 		return MayorSessionName()
 	case RoleDeacon:
 		if a.Name == "boot" {
@@ -212,6 +227,7 @@ func (a *AgentIdentity) prefix() string {
 // misinterpreting the recipient as a filesystem path.
 // Examples:
 //   - mayor → "mayor"
+//   - shard mayor → "mayor (shard: west)"
 //   - deacon → "deacon"
 //   - witness → "witness (rig: gastown)"
 //   - crew → "crew max (rig: gastown)"
@@ -219,6 +235,9 @@ func (a *AgentIdentity) prefix() string {
 func (a *AgentIdentity) BeaconAddress() string {
 	switch a.Role {
 	case RoleMayor:
+		if a.Name != "" {
+			return fmt.Sprintf("mayor (shard: %s)", a.Name)
+		}
 		return "mayor"
 	case RoleDeacon:
 		return "deacon"
@@ -242,6 +261,7 @@ func (a *AgentIdentity) BeaconAddress() string {
 // Address returns the mail-style address for this identity.
 // Examples:
 //   - mayor → "mayor"
+//   - shard mayor → "mayor/west"
 //   - deacon → "deacon"
 //   - witness → "gastown/witness"
 //   - refinery → "gastown/refinery"
@@ -250,6 +270,9 @@ func (a *AgentIdentity) BeaconAddress() string {
 func (a *AgentIdentity) Address() string {
 	switch a.Role {
 	case RoleMayor:
+		if a.Name != "" {
+			return "mayor/" + a.Name
+		}
 		return "mayor"
 	case RoleDeacon:
 		return "deacon"
@@ -272,10 +295,14 @@ func (a *AgentIdentity) Address() string {
 
 // GTRole returns the GT_ROLE environment variable format.
 // This is the same as Address() for most roles, except boot
-// which is a deacon variant with its own role identity.
+// which is a deacon variant with its own role identity, and shard
+// mayors, which run as "mayor" and carry their shard in GT_MAYOR_SHARD.
 func (a *AgentIdentity) GTRole() string {
 	if a.Role == RoleDeacon && a.Name == "boot" {
 		return "boot"
 	}
+	if a.Role == RoleMayor {
+		return "mayor"
+	}
 	return a.Address()
 }
@@ -49,6 +49,19 @@ func TestParseSessionName(t *testing.T) {
 			wantName: "boot",
 		},
 
+		// Shard mayors (town-level: hq-mayor-<shard>)
+		{
+			name:     "mayor shard",
+			session:  "hq-mayor-west",
+			wantRole: RoleMayor,
+			wantName: "west",
+		},
+		{
+			name:    "mayor empty shard",
+			session: "hq-mayor-",
+			wantErr: true,
+		},
+
 		// Dogs (town-level: hq-dog-<name>)
 		{
 			name:     "dog alpha",
@@ -257,6 +270,11 @@ func TestAgentIdentity_SessionName(t *testing.T) {
 			identity: AgentIdentity{Role: RolePolecat, Rig: "hop", Name: "ostrom", Prefix: "hop"},
 			want:     "hop-ostrom",
 		},
+		{
+			name:     "mayor shard",
+			identity: AgentIdentity{Role: RoleMayor, Name: "west"},
+			want:     "hq-mayor-west",
+		},
 		{
 			name:     "dog",
 			identity: AgentIdentity{Role: RoleDog, Name: "alpha"},
@@ -309,6 +327,11 @@ func TestAgentIdentity_Address(t *testing.T) {
 			identity: AgentIdentity{Role: RolePolecat, Rig: "gastown", Name: "Toast", Prefix: "gt"},
 			want:     "gastown/polecats/Toast",
 		},
+		{
+			name:     "mayor shard",
+			identity: AgentIdentity{Role: RoleMayor, Name: "west"},
+			want:     "mayor/west",
+		},
 		{
 			name:     "dog",
 			identity: AgentIdentity{Role: RoleDog, Name: "alpha"},
@@ -334,6 +357,7 @@ func TestParseSessionName_RoundTrip(t *testing.T) {
 	// Test that parsing then reconstructing gives the same result
 	sessions := []string{
 		"hq-mayor",
+		"hq-mayor-west",
 		"hq-deacon",
 		"hq-dog-alpha",
 		"gt-witness",
@@ -369,6 +393,11 @@ func TestParseAddress(t *testing.T) {
 			address: "mayor/",
 			want:    AgentIdentity{Role: RoleMayor},
 		},
+		{
+			name:    "mayor shard",
+			address: "mayor/west",
+			want:    AgentIdentity{Role: RoleMayor, Name: "west"},
+		},
 		{
 			name:    "deacon",
 			address: "deacon",
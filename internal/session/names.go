@@ -17,6 +17,12 @@ func MayorSessionName() string {
 	return HQPrefix + "mayor"
 }
 
+// MayorShardSessionName returns the session name for a shard mayor, which
+// coordinates a subset of the town's rigs. Pattern: hq-mayor-<shard>.
+func MayorShardSessionName(shard string) string {
+	return fmt.Sprintf("%smayor-%s", HQPrefix, shard)
+}
+
 // DeaconSessionName returns the session name for the Deacon agent.
 // One deacon per machine - multi-town requires containers/VMs for isolation.
 func DeaconSessionName() string {
@@ -5,6 +5,19 @@ import (
 	"github.com/steveyegge/gastown/internal/cli"
 	"fmt"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/i18n"
+)
+
+// Beacon instructions, localizable per town. %s is the CLI name.
+var (
+	msgBeaconPrime = i18n.New("beacon.prime", "Run `%s prime` to initialize your context.")
+	msgBeaconCheck = i18n.New("beacon.check_hook", "Check your hook and mail, then act on the hook if present:\n"+
+		"1. `%[1]s hook` - shows hooked work (if any)\n"+
+		"2. `%[1]s mail inbox` - check for messages\n"+
+		"3. If work is hooked → execute it immediately\n"+
+		"4. If nothing hooked → wait for instructions")
+	msgBeaconAssigned = i18n.New("beacon.assigned", "Run `%s prime --hook` and begin work on your hook.")
 )
 
 // BeaconRecipient formats a human-readable, non-path-like recipient for the
@@ -88,7 +101,7 @@ func FormatStartupBeacon(cfg BeaconConfig) string {
 	// SessionStart hook to do it automatically. Work instructions will
 	// come as a separate nudge after gt prime completes.
 	if cfg.IncludePrimeInstruction {
-		beacon += "\n\nRun `" + cli.Name() + " prime` to initialize your context."
+		beacon += "\n\n" + msgBeaconPrime.T(cli.Name())
 		// Don't add work instructions here - they come as a delayed nudge after gt prime
 		return beacon
 	}
@@ -96,11 +109,7 @@ func FormatStartupBeacon(cfg BeaconConfig) string {
 	// For handoff, cold-start, and attach, add explicit instructions so the agent knows
 	// what to do even if hooks haven't loaded CLAUDE.md yet
 	if cfg.Topic == "handoff" || cfg.Topic == "cold-start" || cfg.Topic == "attach" {
-		beacon += "\n\nCheck your hook and mail, then act on the hook if present:\n" +
-			"1. `" + cli.Name() + " hook` - shows hooked work (if any)\n" +
-			"2. `" + cli.Name() + " mail inbox` - check for messages\n" +
-			"3. If work is hooked → execute it immediately\n" +
-			"4. If nothing hooked → wait for instructions"
+		beacon += "\n\n" + msgBeaconCheck.T(cli.Name())
 	}
 
 	// For assigned, tell agent to prime then work on the hook.
@@ -108,7 +117,7 @@ func FormatStartupBeacon(cfg BeaconConfig) string {
 	// Matches refinery pattern: short instruction with prime before action.
 	// Exclude work instructions only if explicitly set (non-hook agents get them via delayed nudge)
 	if cfg.Topic == "assigned" && !cfg.ExcludeWorkInstructions {
-		beacon += "\n\nRun `" + cli.Name() + " prime --hook` and begin work on your hook."
+		beacon += "\n\n" + msgBeaconAssigned.T(cli.Name())
 	}
 
 	return beacon
@@ -0,0 +1,211 @@
+// Package shadow runs one bead on two agents side by side. The original
+// bead runs on one polecat and a copy of it (the shadow bead) on another,
+// each with its own agent or prompt and neither merged, so the operator can
+// compare the two branches and pick the one that lands.
+package shadow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Side labels: A runs the original bead, B the shadow bead.
+const (
+	SideA = "a"
+	SideB = "b"
+)
+
+// Side is one of the two runs.
+type Side struct {
+	Bead   string `json:"bead"`
+	Agent  string `json:"agent,omitempty"` // Empty: the rig's default agent
+	Args   string `json:"args,omitempty"`
+	Branch string `json:"branch,omitempty"` // Recorded once the work is found
+}
+
+// AgentName returns the side's agent, or "default".
+func (s *Side) AgentName() string {
+	if s.Agent == "" {
+		return "default"
+	}
+	return s.Agent
+}
+
+// Run is a bead dispatched to two agents.
+type Run struct {
+	Bead   string    `json:"bead"` // The original bead (side A)
+	Rig    string    `json:"rig"`
+	A      Side      `json:"a"`
+	B      Side      `json:"b"`
+	By     string    `json:"by,omitempty"`
+	At     time.Time `json:"at"`
+	Winner string    `json:"winner,omitempty"` // SideA or SideB once picked
+}
+
+// Side returns side a or b, or nil for another label.
+func (r *Run) Side(label string) *Side {
+	switch strings.ToLower(label) {
+	case SideA:
+		return &r.A
+	case SideB:
+		return &r.B
+	}
+	return nil
+}
+
+// State holds the shadow runs, by original bead ID.
+type State struct {
+	Runs map[string]*Run `json:"runs"`
+}
+
+// StatePath returns the path of the shadow state file.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "shadow.json")
+}
+
+// LoadState reads the state, returning an empty state when the file doesn't
+// exist yet.
+func LoadState(townRoot string) (*State, error) {
+	state := &State{Runs: make(map[string]*Run)}
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Runs == nil {
+		state.Runs = make(map[string]*Run)
+	}
+	return state, nil
+}
+
+// Save writes the state.
+func (s *State) Save(townRoot string) error {
+	return util.EnsureDirAndWriteJSON(StatePath(townRoot), s)
+}
+
+// Find returns the run for a bead, by its original or shadow bead ID.
+func (s *State) Find(id string) *Run {
+	if r, ok := s.Runs[id]; ok {
+		return r
+	}
+	for _, r := range s.Runs {
+		if r.B.Bead == id {
+			return r
+		}
+	}
+	return nil
+}
+
+// List returns the runs, newest first.
+func (s *State) List() []*Run {
+	list := make([]*Run, 0, len(s.Runs))
+	for _, r := range s.Runs {
+		list = append(list, r)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].At.Equal(list[j].At) {
+			return list[i].At.After(list[j].At)
+		}
+		return list[i].Bead < list[j].Bead
+	})
+	return list
+}
+
+// TestResult is the outcome of the rig's test command on a side's branch.
+type TestResult struct {
+	Command  string        `json:"command"`
+	Passed   bool          `json:"passed"`
+	Duration time.Duration `json:"duration"`
+	Output   string        `json:"output,omitempty"` // Tail of the output
+}
+
+// Result is what one side produced.
+type Result struct {
+	Label      string      `json:"label"`
+	Bead       string      `json:"bead"`
+	Agent      string      `json:"agent"`
+	Status     string      `json:"status,omitempty"`
+	Branch     string      `json:"branch,omitempty"`
+	Commits    int         `json:"commits"`
+	Files      []string    `json:"files,omitempty"`
+	Insertions int         `json:"insertions"`
+	Deletions  int         `json:"deletions"`
+	Tests      *TestResult `json:"tests,omitempty"`
+	Error      string      `json:"error,omitempty"` // Why the branch couldn't be read
+}
+
+// Comparison sets the two sides' results against each other.
+type Comparison struct {
+	Bead   string   `json:"bead"`
+	Base   string   `json:"base"`
+	A      Result   `json:"a"`
+	B      Result   `json:"b"`
+	Common []string `json:"common_files,omitempty"` // Changed by both
+	OnlyA  []string `json:"only_a,omitempty"`
+	OnlyB  []string `json:"only_b,omitempty"`
+}
+
+// Compare builds the comparison of two results, splitting the changed
+// files into those both sides touched and those only one did.
+func Compare(bead, base string, a, b Result) Comparison {
+	c := Comparison{Bead: bead, Base: base, A: a, B: b}
+	inB := make(map[string]bool, len(b.Files))
+	for _, f := range b.Files {
+		inB[f] = true
+	}
+	inA := make(map[string]bool, len(a.Files))
+	for _, f := range a.Files {
+		inA[f] = true
+		if inB[f] {
+			c.Common = append(c.Common, f)
+		} else {
+			c.OnlyA = append(c.OnlyA, f)
+		}
+	}
+	for _, f := range b.Files {
+		if !inA[f] {
+			c.OnlyB = append(c.OnlyB, f)
+		}
+	}
+	return c
+}
+
+// ParseNumstat reads git diff --numstat output into the changed files and
+// line totals. Binary files count as changed with no lines.
+func ParseNumstat(out string) (files []string, insertions, deletions int) {
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		ins, _ := strconv.Atoi(fields[0])
+		del, _ := strconv.Atoi(fields[1])
+		insertions += ins
+		deletions += del
+		files = append(files, fields[2])
+	}
+	sort.Strings(files)
+	return files, insertions, deletions
+}
+
+// Tail returns the last n lines of s.
+func Tail(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = append([]string{fmt.Sprintf("… (%d lines omitted)", len(lines)-n)}, lines[len(lines)-n:]...)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,66 @@
+package shadow
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	a := Result{Label: SideA, Files: []string{"cmd/main.go", "go.mod", "internal/x.go"}}
+	b := Result{Label: SideB, Files: []string{"internal/x.go", "internal/x_test.go"}}
+	c := Compare("gt-abc", "origin/main", a, b)
+	if strings.Join(c.Common, ",") != "internal/x.go" {
+		t.Errorf("Common = %v", c.Common)
+	}
+	if strings.Join(c.OnlyA, ",") != "cmd/main.go,go.mod" || strings.Join(c.OnlyB, ",") != "internal/x_test.go" {
+		t.Errorf("OnlyA = %v, OnlyB = %v", c.OnlyA, c.OnlyB)
+	}
+}
+
+func TestParseNumstat(t *testing.T) {
+	files, ins, del := ParseNumstat("10\t2\tinternal/x.go\n-\t-\tlogo.png\n3\t0\tREADME.md\n")
+	if strings.Join(files, ",") != "README.md,internal/x.go,logo.png" || ins != 13 || del != 2 {
+		t.Errorf("ParseNumstat = %v, +%d -%d", files, ins, del)
+	}
+	if files, _, _ := ParseNumstat(""); len(files) != 0 {
+		t.Errorf("empty diff gave %v", files)
+	}
+}
+
+func TestStateFind(t *testing.T) {
+	town := t.TempDir()
+	state, err := LoadState(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Runs["gt-abc"] = &Run{Bead: "gt-abc", Rig: "gastown", A: Side{Bead: "gt-abc"}, B: Side{Bead: "gt-xyz", Agent: "gemini"}, At: time.Now()}
+	if err := state.Save(town); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadState(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"gt-abc", "gt-xyz"} {
+		if r := loaded.Find(id); r == nil || r.Bead != "gt-abc" {
+			t.Errorf("Find(%s) = %+v", id, r)
+		}
+	}
+	if loaded.Find("gt-other") != nil {
+		t.Error("Find of an unrelated bead should be nil")
+	}
+	r := loaded.Find("gt-abc")
+	if r.Side("B").AgentName() != "gemini" || r.Side("a").AgentName() != "default" || r.Side("c") != nil {
+		t.Errorf("sides = %+v", r)
+	}
+}
+
+func TestTail(t *testing.T) {
+	if got := Tail("1\n2\n3\n4\n", 2); got != "… (2 lines omitted)\n3\n4" {
+		t.Errorf("Tail = %q", got)
+	}
+	if got := Tail("ok\n", 5); got != "ok" {
+		t.Errorf("Tail = %q", got)
+	}
+}
@@ -0,0 +1,176 @@
+// Package shard splits the Mayor's coordination load across several mayors.
+//
+// Past a handful of rigs a single Mayor becomes the bottleneck: every
+// escalation, stranded convoy and cross-rig question lands in one context.
+// With shards configured, each shard mayor owns a subset of rigs. Mail and
+// escalations addressed to "mayor/" from an agent in an owned rig are routed
+// to that rig's shard mayor ("mayor/<shard>"); everything else — rigs outside
+// any shard, town-level agents, the overseer, and the shard mayors themselves
+// — still reaches the primary Mayor, which acts as the super-coordinator.
+package shard
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Config configures mayor sharding (settings/config.json "mayors").
+// nil/absent or no shards = a single Mayor for the whole town.
+type Config struct {
+	// Shards lists the shard mayors and the rigs each owns.
+	Shards []Shard `json:"shards,omitempty"`
+}
+
+// Shard is one mayor's domain.
+type Shard struct {
+	// Name identifies the shard; the shard mayor's address is "mayor/<name>".
+	Name string `json:"name"`
+
+	// Rigs are the rigs this mayor coordinates. A rig belongs to at most
+	// one shard.
+	Rigs []string `json:"rigs"`
+
+	// CCPrimary is the lowest escalation severity that is also copied to the
+	// primary Mayor ("low", "medium", "high", "critical"). Default: critical.
+	// "never" disables copying.
+	CCPrimary string `json:"cc_primary,omitempty"`
+}
+
+// DefaultCCPrimary is the escalation severity copied to the primary Mayor
+// when a shard doesn't set CCPrimary.
+const DefaultCCPrimary = "critical"
+
+// PrimaryAddress is the primary Mayor's mail address.
+const PrimaryAddress = "mayor/"
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Address returns the shard mayor's mail address.
+func (s *Shard) Address() string {
+	return "mayor/" + s.Name
+}
+
+// GetCCPrimary returns the escalation threshold for copying the primary Mayor.
+func (s *Shard) GetCCPrimary() string {
+	if s == nil || s.CCPrimary == "" {
+		return DefaultCCPrimary
+	}
+	return s.CCPrimary
+}
+
+// Enabled reports whether any shards are configured.
+func (c *Config) Enabled() bool {
+	return c != nil && len(c.Shards) > 0
+}
+
+// Get returns the named shard, or nil.
+func (c *Config) Get(name string) *Shard {
+	if c == nil {
+		return nil
+	}
+	for i := range c.Shards {
+		if c.Shards[i].Name == name {
+			return &c.Shards[i]
+		}
+	}
+	return nil
+}
+
+// ForRig returns the shard owning rig, or nil if the primary Mayor owns it.
+func (c *Config) ForRig(rig string) *Shard {
+	if c == nil || rig == "" {
+		return nil
+	}
+	for i := range c.Shards {
+		for _, r := range c.Shards[i].Rigs {
+			if r == rig {
+				return &c.Shards[i]
+			}
+		}
+	}
+	return nil
+}
+
+// MayorFor returns the address of the mayor responsible for mail sent by
+// from: the shard mayor owning the sender's rig, or the primary Mayor.
+func (c *Config) MayorFor(from string) string {
+	if s := c.ForRig(rigOf(from)); s != nil {
+		return s.Address()
+	}
+	return PrimaryAddress
+}
+
+// CCPrimaryFor reports whether an escalation of severity sent by from, which
+// MayorFor routes to a shard mayor, should also be copied to the primary
+// Mayor under that shard's CCPrimary threshold.
+func (c *Config) CCPrimaryFor(from, severity string) bool {
+	s := c.ForRig(rigOf(from))
+	if s == nil || s.GetCCPrimary() == "never" {
+		return false
+	}
+	rank := severityRank(severity)
+	return rank > 0 && rank >= severityRank(s.GetCCPrimary())
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case "low":
+		return 1
+	case "medium":
+		return 2
+	case "high":
+		return 3
+	case "critical":
+		return 4
+	}
+	return 0
+}
+
+// rigOf extracts the rig from a rig-level agent address ("gastown/witness",
+// "gastown/crew/max"). Town-level addresses have no rig.
+func rigOf(address string) string {
+	address = strings.TrimSuffix(strings.TrimSpace(address), "/")
+	rig, _, found := strings.Cut(address, "/")
+	if !found {
+		return ""
+	}
+	switch rig {
+	case "mayor", "deacon", "overseer":
+		return ""
+	}
+	return rig
+}
+
+// Validate checks shard names and that no rig is owned twice.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	names := make(map[string]bool, len(c.Shards))
+	owner := make(map[string]string)
+	for _, s := range c.Shards {
+		if !validName.MatchString(s.Name) {
+			return fmt.Errorf("invalid shard name %q: use lowercase letters, digits, - and _", s.Name)
+		}
+		if names[s.Name] {
+			return fmt.Errorf("duplicate shard %q", s.Name)
+		}
+		names[s.Name] = true
+		if len(s.Rigs) == 0 {
+			return fmt.Errorf("shard %q owns no rigs", s.Name)
+		}
+		for _, rig := range s.Rigs {
+			if prev, ok := owner[rig]; ok {
+				return fmt.Errorf("rig %q is owned by both shard %q and %q", rig, prev, s.Name)
+			}
+			owner[rig] = s.Name
+		}
+		switch s.CCPrimary {
+		case "", "never", "low", "medium", "high", "critical":
+		default:
+			return fmt.Errorf("shard %q: invalid cc_primary %q", s.Name, s.CCPrimary)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package shard
+
+import (
+	"strings"
+	"testing"
+)
+
+func testConfig() *Config {
+	return &Config{Shards: []Shard{
+		{Name: "web", Rigs: []string{"frontend", "api"}},
+		{Name: "data", Rigs: []string{"pipeline"}, CCPrimary: "high"},
+	}}
+}
+
+func TestMayorFor(t *testing.T) {
+	c := testConfig()
+	tests := []struct {
+		from string
+		want string
+	}{
+		{"frontend/witness", "mayor/web"},
+		{"api/crew/max", "mayor/web"},
+		{"pipeline/polecats/Toast", "mayor/data"},
+		{"pipeline/refinery", "mayor/data"},
+		{"gastown/witness", "mayor/"}, // unsharded rig
+		{"deacon/", "mayor/"},
+		{"mayor/web", "mayor/"}, // shard mayors escalate to the primary
+		{"overseer", "mayor/"},
+		{"", "mayor/"},
+	}
+	for _, tt := range tests {
+		if got := c.MayorFor(tt.from); got != tt.want {
+			t.Errorf("MayorFor(%q) = %q, want %q", tt.from, got, tt.want)
+		}
+	}
+
+	var none *Config
+	if got := none.MayorFor("frontend/witness"); got != PrimaryAddress {
+		t.Errorf("nil config MayorFor = %q, want primary", got)
+	}
+	if none.Enabled() || none.Get("web") != nil {
+		t.Error("nil config should have no shards")
+	}
+}
+
+func TestGetAndCCPrimary(t *testing.T) {
+	c := testConfig()
+	if s := c.Get("web"); s == nil || s.Address() != "mayor/web" || s.GetCCPrimary() != DefaultCCPrimary {
+		t.Errorf("Get(web) = %+v", s)
+	}
+	if s := c.Get("data"); s == nil || s.GetCCPrimary() != "high" {
+		t.Errorf("Get(data) = %+v", s)
+	}
+	if c.Get("ops") != nil {
+		t.Error("Get of unknown shard should be nil")
+	}
+}
+
+func TestCCPrimaryFor(t *testing.T) {
+	c := testConfig()
+	c.Shards = append(c.Shards, Shard{Name: "quiet", Rigs: []string{"docs"}, CCPrimary: "never"})
+	tests := []struct {
+		from     string
+		severity string
+		want     bool
+	}{
+		{"frontend/witness", "high", false}, // default threshold: critical
+		{"frontend/witness", "critical", true},
+		{"pipeline/witness", "medium", false},
+		{"pipeline/witness", "high", true},
+		{"docs/witness", "critical", false},    // never
+		{"gastown/witness", "critical", false}, // not sharded: already primary
+		{"pipeline/witness", "bogus", false},
+	}
+	for _, tt := range tests {
+		if got := c.CCPrimaryFor(tt.from, tt.severity); got != tt.want {
+			t.Errorf("CCPrimaryFor(%q, %q) = %v, want %v", tt.from, tt.severity, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := testConfig().Validate(); err != nil {
+		t.Fatalf("valid config: %v", err)
+	}
+	tests := []struct {
+		name string
+		cfg  *Config
+		want string
+	}{
+		{"bad name", &Config{Shards: []Shard{{Name: "Web UI", Rigs: []string{"a"}}}}, "invalid shard name"},
+		{"duplicate", &Config{Shards: []Shard{{Name: "web", Rigs: []string{"a"}}, {Name: "web", Rigs: []string{"b"}}}}, "duplicate shard"},
+		{"no rigs", &Config{Shards: []Shard{{Name: "web"}}}, "owns no rigs"},
+		{"rig twice", &Config{Shards: []Shard{{Name: "web", Rigs: []string{"a"}}, {Name: "data", Rigs: []string{"a"}}}}, `rig "a" is owned by both`},
+		{"bad cc", &Config{Shards: []Shard{{Name: "web", Rigs: []string{"a"}, CCPrimary: "urgent"}}}, "invalid cc_primary"},
+	}
+	for _, tt := range tests {
+		err := tt.cfg.Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Validate() = %v, want error containing %q", tt.name, err, tt.want)
+		}
+	}
+}
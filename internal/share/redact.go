@@ -0,0 +1,97 @@
+package share
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// redactRule replaces matches of re with "[REDACTED <kind>]". When keep is
+// set, the first submatch (a key name, a URL scheme) is kept in front.
+type redactRule struct {
+	kind string
+	re   *regexp.Regexp
+	keep bool
+}
+
+// secretRules catch the credentials that turn up in transcripts, diffs and
+// mail. URL credentials come before emails: user:pass@host looks like one.
+var secretRules = []redactRule{
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`), false},
+	{"api key", regexp.MustCompile(`\bsk-(?:ant-)?[A-Za-z0-9_-]{20,}`), false},
+	{"github token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,})`), false},
+	{"aws key", regexp.MustCompile(`\b(?:AKIA|ASIA)[A-Z0-9]{16}\b`), false},
+	{"slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`), false},
+	{"jwt", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`), false},
+	{"token", regexp.MustCompile(`(?i)(\bbearer\s+)[A-Za-z0-9._~+/=-]{16,}`), true},
+	{"credentials", regexp.MustCompile(`(\b[a-z][a-z0-9+.-]*://)[^/\s:@]+:[^/\s@]+@`), true},
+	{"secret", regexp.MustCompile(`(?i)(\b(?:password|passwd|secret|token|api[_-]?key|access[_-]?key)["']?\s*[:=]\s*["']?)[^\s"',;]{4,}`), true},
+	{"email", regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`), false},
+}
+
+// pathAlias replaces a local path (the town root, the home directory) so a
+// bundle doesn't reveal how the sharer's machine is laid out.
+type pathAlias struct {
+	path, alias string
+}
+
+// Redactor scrubs secrets, emails and local paths from text bound for a
+// share bundle. It is deliberately greedy: a false positive costs a
+// readable token, a miss leaks a credential.
+type Redactor struct {
+	rules []redactRule
+	paths []pathAlias
+
+	// Count is the number of redactions made so far.
+	Count int
+}
+
+// NewRedactor returns a redactor with the built-in secret rules plus the
+// extra regular expressions given.
+func NewRedactor(extra []string) (*Redactor, error) {
+	r := &Redactor{rules: append([]redactRule(nil), secretRules...)}
+	for _, pattern := range extra {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact pattern %q: %w", pattern, err)
+		}
+		r.rules = append(r.rules, redactRule{kind: "custom", re: re})
+	}
+	return r, nil
+}
+
+// AliasPath replaces path (and anything below it) with alias. Longer paths
+// are replaced first, so the town root wins over the home directory that
+// contains it.
+func (r *Redactor) AliasPath(path, alias string) {
+	path = strings.TrimRight(path, "/")
+	if path == "" {
+		return
+	}
+	r.paths = append(r.paths, pathAlias{path: path, alias: alias})
+	sort.SliceStable(r.paths, func(i, j int) bool { return len(r.paths[i].path) > len(r.paths[j].path) })
+}
+
+// Redact returns s with secrets and aliased paths replaced.
+func (r *Redactor) Redact(s string) string {
+	if s == "" {
+		return s
+	}
+	for _, rule := range r.rules {
+		s = rule.re.ReplaceAllStringFunc(s, func(match string) string {
+			r.Count++
+			prefix := ""
+			if rule.keep {
+				if sub := rule.re.FindStringSubmatch(match); len(sub) > 1 {
+					prefix = sub[1]
+				}
+			}
+			return prefix + "[REDACTED " + rule.kind + "]"
+		})
+	}
+	for _, p := range r.paths {
+		s = strings.ReplaceAll(s, p.path, p.alias)
+	}
+	return s
+}
@@ -0,0 +1,156 @@
+package share
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// Markdown renders the bundle as a single markdown document, ready to paste
+// into an issue.
+func (b *Bundle) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s: %s\n\n", b.Bead, b.Title)
+	fmt.Fprintf(&sb, "- Status: %s\n", b.Status)
+	if b.Assignee != "" {
+		fmt.Fprintf(&sb, "- Assignee: %s\n", b.Assignee)
+	}
+	if !b.CreatedAt.IsZero() {
+		fmt.Fprintf(&sb, "- Created: %s\n", formatTime(b.CreatedAt))
+	}
+	if !b.ClosedAt.IsZero() {
+		fmt.Fprintf(&sb, "- Closed: %s\n", formatTime(b.ClosedAt))
+	}
+	fmt.Fprintf(&sb, "- Timeline: %s\n", b.summary())
+	fmt.Fprintf(&sb, "- Redactions: %d\n", b.Redactions)
+	if b.Description != "" {
+		sb.WriteString("\n## Description\n\n")
+		sb.WriteString(strings.TrimSpace(b.Description))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n## Timeline\n")
+	if len(b.Entries) == 0 {
+		sb.WriteString("\nNothing recorded.\n")
+	}
+	for _, e := range b.Entries {
+		fmt.Fprintf(&sb, "\n### %s · %s", formatTime(e.At), e.Kind)
+		if e.Actor != "" {
+			fmt.Fprintf(&sb, " · %s", e.Actor)
+		}
+		fmt.Fprintf(&sb, "\n\n%s\n", e.Title)
+		if e.Body != "" {
+			lang := "text"
+			if e.Kind == KindDiff {
+				lang = "diff"
+			}
+			fence := "```"
+			for strings.Contains(e.Body, fence) {
+				fence += "`"
+			}
+			fmt.Fprintf(&sb, "\n%s%s\n%s\n%s\n", fence, lang, strings.TrimRight(e.Body, "\n"), fence)
+		}
+	}
+	fmt.Fprintf(&sb, "\n---\nGenerated by gt share at %s.\n", formatTime(b.GeneratedAt))
+	return sb.String()
+}
+
+// HTML renders the bundle as one self-contained page: no scripts, no
+// external stylesheets, nothing fetched when it is opened.
+func (b *Bundle) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, b); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// summary counts the timeline's entries by kind: "4 events, 2 transcripts".
+func (b *Bundle) summary() string {
+	var parts []string
+	for _, kind := range []string{KindEvent, KindTranscript, KindDiff, KindMail} {
+		if n := b.Count(kind); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, kind))
+		}
+	}
+	if len(parts) == 0 {
+		return "empty"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "undated"
+	}
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// diffLine is one line of a diff body with the class that colors it.
+type diffLine struct {
+	Class string
+	Text  string
+}
+
+func diffLines(body string) []diffLine {
+	var out []diffLine
+	for _, line := range strings.Split(strings.TrimRight(body, "\n"), "\n") {
+		class := ""
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "commit "):
+			class = "hdr"
+		case strings.HasPrefix(line, "@@"):
+			class = "hunk"
+		case strings.HasPrefix(line, "+"):
+			class = "add"
+		case strings.HasPrefix(line, "-"):
+			class = "del"
+		}
+		out = append(out, diffLine{Class: class, Text: line})
+	}
+	return out
+}
+
+var htmlTemplate = template.Must(template.New("share").Funcs(template.FuncMap{
+	"time":      formatTime,
+	"diffLines": diffLines,
+	"isDiff":    func(kind string) bool { return kind == KindDiff },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Bead}}: {{.Title}}</title>
+<style>
+body { font: 14px/1.5 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 960px; margin: 2em auto; padding: 0 1em; color: #1f2328; }
+h1 { font-size: 1.5em; margin-bottom: .2em; }
+.meta { color: #59636e; font-size: .9em; }
+.entry { border-left: 3px solid #d1d9e0; padding: .2em 0 .2em 1em; margin: 1.2em 0; }
+.entry.event { border-color: #8c959f; }
+.entry.transcript { border-color: #0969da; }
+.entry.diff { border-color: #1a7f37; }
+.entry.mail { border-color: #9a6700; }
+.title { margin: .2em 0; white-space: pre-wrap; }
+pre { background: #f6f8fa; padding: .8em; overflow-x: auto; white-space: pre-wrap; font: 12px/1.45 ui-monospace, SFMono-Regular, Menlo, monospace; }
+pre span { display: block; }
+.add { background: #dafbe1; } .del { background: #ffebe9; } .hunk { color: #0969da; } .hdr { font-weight: bold; }
+footer { margin-top: 2em; color: #59636e; font-size: .85em; }
+</style>
+</head>
+<body>
+<h1>{{.Bead}}: {{.Title}}</h1>
+<div class="meta">{{.Status}}{{if .Assignee}} · {{.Assignee}}{{end}}{{if not .CreatedAt.IsZero}} · created {{time .CreatedAt}}{{end}}{{if not .ClosedAt.IsZero}} · closed {{time .ClosedAt}}{{end}} · {{.Redactions}} redaction(s)</div>
+{{if .Description}}<h2>Description</h2>
+<pre>{{.Description}}</pre>{{end}}
+<h2>Timeline</h2>
+{{range .Entries}}<div class="entry {{.Kind}}">
+<div class="meta">{{time .At}} · {{.Kind}}{{if .Actor}} · {{.Actor}}{{end}}</div>
+<div class="title">{{.Title}}</div>
+{{if .Body}}{{if isDiff .Kind}}<pre>{{range diffLines .Body}}<span class="{{.Class}}">{{.Text}}</span>{{end}}</pre>{{else}}<pre>{{.Body}}</pre>{{end}}{{end}}
+</div>
+{{else}}<p>Nothing recorded.</p>
+{{end}}<footer>Generated by gt share at {{time .GeneratedAt}}.</footer>
+</body>
+</html>
+`))
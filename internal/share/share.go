@@ -0,0 +1,181 @@
+// Package share assembles one bead's history — its events, the agents'
+// transcripts while they held it, the diff that landed and the mail about
+// it — into a redacted, self-contained bundle for bug reports and demos.
+package share
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentlog"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Entry kinds, in the order they are introduced in a bundle's legend.
+const (
+	KindEvent      = "event"      // Town events log: slings, hooks, merges, stalls
+	KindTranscript = "transcript" // Excerpt of an agent session while it held the bead
+	KindDiff       = "diff"       // Commits that landed for the bead
+	KindMail       = "mail"       // Mail that names the bead
+)
+
+const (
+	// maxExcerptLines caps a transcript excerpt; the start and the end of
+	// a session say the most, so the middle is elided.
+	maxExcerptLines = 40
+
+	// maxExcerptLine caps one line of an excerpt, in runes.
+	maxExcerptLine = 300
+)
+
+// Entry is one item on a bead's timeline.
+type Entry struct {
+	At    time.Time `json:"at"`
+	Kind  string    `json:"kind"`
+	Actor string    `json:"actor,omitempty"`
+	Title string    `json:"title"`
+	Body  string    `json:"body,omitempty"`
+}
+
+// Bundle is a bead's shareable history.
+type Bundle struct {
+	Bead        string    `json:"bead"`
+	Title       string    `json:"title"`
+	Status      string    `json:"status"`
+	Assignee    string    `json:"assignee,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	ClosedAt    time.Time `json:"closed_at,omitempty"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Entries     []Entry   `json:"entries"`
+	Redactions  int       `json:"redactions"`
+}
+
+// Add appends an entry to the timeline.
+func (b *Bundle) Add(e Entry) {
+	b.Entries = append(b.Entries, e)
+}
+
+// Sort orders the timeline oldest first. Entries without a time go last.
+func (b *Bundle) Sort() {
+	sort.SliceStable(b.Entries, func(i, j int) bool {
+		ti, tj := b.Entries[i].At, b.Entries[j].At
+		if ti.IsZero() != tj.IsZero() {
+			return tj.IsZero()
+		}
+		return ti.Before(tj)
+	})
+}
+
+// Redact scrubs every text field of the bundle with r and records how
+// many redactions were made.
+func (b *Bundle) Redact(r *Redactor) {
+	b.Title = r.Redact(b.Title)
+	b.Description = r.Redact(b.Description)
+	for i := range b.Entries {
+		b.Entries[i].Title = r.Redact(b.Entries[i].Title)
+		b.Entries[i].Body = r.Redact(b.Entries[i].Body)
+	}
+	b.Redactions = r.Count
+}
+
+// Count returns the number of entries of a kind.
+func (b *Bundle) Count(kind string) int {
+	n := 0
+	for _, e := range b.Entries {
+		if e.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// EventConcerns reports whether an event is about bead: a payload field
+// names it, or a branch carries it (polecat/<name>/<bead>).
+func EventConcerns(e events.Event, bead string) bool {
+	for key, v := range e.Payload {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if s == bead || (key == "branch" && strings.Contains(s, bead)) {
+			return true
+		}
+	}
+	return false
+}
+
+// EventEntry turns a town event into a timeline entry.
+func EventEntry(e events.Event) Entry {
+	at, _ := time.Parse(time.RFC3339, e.Timestamp)
+	keys := make([]string, 0, len(e.Payload))
+	for k := range e.Payload {
+		if k != "bead" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var details []string
+	for _, k := range keys {
+		if v := fmt.Sprint(e.Payload[k]); v != "" {
+			details = append(details, k+"="+v)
+		}
+	}
+	title := e.Type
+	if len(details) > 0 {
+		title += ": " + strings.Join(details, " ")
+	}
+	return Entry{At: at, Kind: KindEvent, Actor: e.Actor, Title: title}
+}
+
+// Excerpt renders the part of a session between from and to (zero: still
+// open) as prompts, replies and tool calls. Tool output and thinking are
+// left out. It returns the time of the first event kept and false when
+// nothing in the window is worth showing.
+func Excerpt(evs []agentlog.AgentEvent, from, to time.Time) (string, time.Time, bool) {
+	var lines []string
+	var first time.Time
+	for _, ev := range evs {
+		if ev.Timestamp.Before(from) || (!to.IsZero() && ev.Timestamp.After(to)) {
+			continue
+		}
+		var line string
+		switch {
+		case ev.EventType == "text" && ev.Role == "user":
+			line = "> " + ev.Content
+		case ev.EventType == "text":
+			line = ev.Content
+		case ev.EventType == "tool_use":
+			line = "→ " + ev.Content
+		default:
+			continue
+		}
+		line = truncateRunes(strings.Join(strings.Fields(line), " "), maxExcerptLine)
+		if line == "" || line == ">" {
+			continue
+		}
+		if first.IsZero() {
+			first = ev.Timestamp
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return "", time.Time{}, false
+	}
+	if len(lines) > maxExcerptLines {
+		half := maxExcerptLines / 2
+		elided := fmt.Sprintf("… %d lines omitted …", len(lines)-2*half)
+		lines = append(append(lines[:half:half], elided), lines[len(lines)-half:]...)
+	}
+	return strings.Join(lines, "\n"), first, true
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
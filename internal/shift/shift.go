@@ -0,0 +1,197 @@
+// Package shift tracks which human operator is currently on shift in a town
+// and the handoff notes passed between operators.
+//
+// Several humans may share one town. Agents all see the same "overseer"
+// identity, so the shift record is what distinguishes who approved or nudged
+// what. The current shift lives in <town>/.runtime/shift.json; completed
+// shifts (with their handoff summaries) are appended to
+// <town>/.runtime/shift-handoffs.jsonl.
+package shift
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	stateFile   = "shift.json"
+	handoffFile = "shift-handoffs.jsonl"
+)
+
+// ErrOnShift is returned by Start when another operator already holds the shift.
+var ErrOnShift = errors.New("another operator is on shift")
+
+// State is the active shift.
+type State struct {
+	Operator  string    `json:"operator"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// Item is a convoy or approval captured in a handoff.
+type Item struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Handoff is the summary an operator leaves for the next shift.
+type Handoff struct {
+	Operator         string    `json:"operator"`
+	StartedAt        time.Time `json:"started_at"`
+	EndedAt          time.Time `json:"ended_at"`
+	ActiveConvoys    []Item    `json:"active_convoys,omitempty"`
+	PendingApprovals []Item    `json:"pending_approvals,omitempty"`
+	KnownIssues      []string  `json:"known_issues,omitempty"`
+	Notes            string    `json:"notes,omitempty"`
+}
+
+// StatePath returns the current-shift file for a town.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", stateFile)
+}
+
+// HandoffPath returns the handoff history file for a town.
+func HandoffPath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", handoffFile)
+}
+
+// Current returns the active shift, or nil if nobody is on shift.
+func Current(townRoot string) (*State, error) {
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading shift state: %w", err)
+	}
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("parsing shift state: %w", err)
+	}
+	if st.Operator == "" {
+		return nil, nil
+	}
+	return &st, nil
+}
+
+// CurrentOperator returns the operator on shift, or "" if none (or unreadable).
+func CurrentOperator(townRoot string) string {
+	st, err := Current(townRoot)
+	if err != nil || st == nil {
+		return ""
+	}
+	return st.Operator
+}
+
+// Start puts operator on shift. If a different operator is already on shift,
+// ErrOnShift is returned unless takeover is set. Starting again as the same
+// operator keeps the original start time.
+func Start(townRoot, operator string, takeover bool, now time.Time) (*State, error) {
+	if operator == "" {
+		return nil, fmt.Errorf("operator name is required")
+	}
+	cur, err := Current(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil {
+		if cur.Operator == operator {
+			return cur, nil
+		}
+		if !takeover {
+			return cur, fmt.Errorf("%w: %s (since %s)", ErrOnShift, cur.Operator, cur.StartedAt.Local().Format("15:04"))
+		}
+	}
+
+	st := &State{Operator: operator, StartedAt: now.UTC()}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding shift state: %w", err)
+	}
+	path := StatePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating runtime directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: non-sensitive operational state
+		return nil, fmt.Errorf("writing shift state: %w", err)
+	}
+	return st, nil
+}
+
+// End records the handoff and clears the active shift. The handoff's
+// Operator, StartedAt, and EndedAt are filled from the active shift when unset.
+func End(townRoot string, h *Handoff, now time.Time) error {
+	cur, err := Current(townRoot)
+	if err != nil {
+		return err
+	}
+	if cur != nil {
+		if h.Operator == "" {
+			h.Operator = cur.Operator
+		}
+		if h.StartedAt.IsZero() {
+			h.StartedAt = cur.StartedAt
+		}
+	}
+	if h.EndedAt.IsZero() {
+		h.EndedAt = now.UTC()
+	}
+
+	data, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("encoding handoff: %w", err)
+	}
+	path := HandoffPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: non-sensitive operational state
+	if err != nil {
+		return fmt.Errorf("opening handoff log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing handoff: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing handoff log: %w", err)
+	}
+
+	if err := os.Remove(StatePath(townRoot)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("clearing shift state: %w", err)
+	}
+	return nil
+}
+
+// LatestHandoff returns the most recent handoff, or nil if none were recorded.
+func LatestHandoff(townRoot string) (*Handoff, error) {
+	f, err := os.Open(HandoffPath(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("opening handoff log: %w", err)
+	}
+	defer f.Close()
+
+	var latest *Handoff
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var h Handoff
+		if err := json.Unmarshal(scanner.Bytes(), &h); err != nil {
+			continue
+		}
+		latest = &h
+	}
+	if err := scanner.Err(); err != nil {
+		return latest, fmt.Errorf("reading handoff log: %w", err)
+	}
+	return latest, nil
+}
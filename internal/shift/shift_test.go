@@ -0,0 +1,82 @@
+package shift
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStartAndTakeover(t *testing.T) {
+	town := t.TempDir()
+	now := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	if op := CurrentOperator(town); op != "" {
+		t.Fatalf("CurrentOperator() on fresh town = %q, want empty", op)
+	}
+
+	if _, err := Start(town, "alice", false, now); err != nil {
+		t.Fatalf("Start(alice) error = %v", err)
+	}
+	if op := CurrentOperator(town); op != "alice" {
+		t.Errorf("CurrentOperator() = %q, want alice", op)
+	}
+
+	// Re-starting as the same operator is idempotent and keeps the start time.
+	st, err := Start(town, "alice", false, now.Add(time.Hour))
+	if err != nil || !st.StartedAt.Equal(now) {
+		t.Errorf("Start(alice) again = %+v, %v; want original start time", st, err)
+	}
+
+	if _, err := Start(town, "bob", false, now); !errors.Is(err, ErrOnShift) {
+		t.Errorf("Start(bob) error = %v, want ErrOnShift", err)
+	}
+	if _, err := Start(town, "bob", true, now); err != nil {
+		t.Fatalf("Start(bob, takeover) error = %v", err)
+	}
+	if op := CurrentOperator(town); op != "bob" {
+		t.Errorf("CurrentOperator() after takeover = %q, want bob", op)
+	}
+}
+
+func TestEndRecordsHandoff(t *testing.T) {
+	town := t.TempDir()
+	start := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	if h, err := LatestHandoff(town); err != nil || h != nil {
+		t.Fatalf("LatestHandoff() on fresh town = %v, %v; want nil, nil", h, err)
+	}
+
+	if _, err := Start(town, "alice", false, start); err != nil {
+		t.Fatal(err)
+	}
+	h := &Handoff{
+		ActiveConvoys: []Item{{ID: "hq-cv-1", Title: "Auth rework", Status: "open"}},
+		KnownIssues:   []string{"refinery flaky on gastown"},
+	}
+	if err := End(town, h, start.Add(8*time.Hour)); err != nil {
+		t.Fatalf("End() error = %v", err)
+	}
+	if op := CurrentOperator(town); op != "" {
+		t.Errorf("CurrentOperator() after End = %q, want empty", op)
+	}
+
+	got, err := LatestHandoff(town)
+	if err != nil {
+		t.Fatalf("LatestHandoff() error = %v", err)
+	}
+	if got == nil || got.Operator != "alice" || !got.StartedAt.Equal(start) {
+		t.Fatalf("LatestHandoff() = %+v, want alice's shift", got)
+	}
+	if len(got.ActiveConvoys) != 1 || len(got.KnownIssues) != 1 {
+		t.Errorf("handoff contents not preserved: %+v", got)
+	}
+
+	// A later handoff supersedes the earlier one.
+	if err := End(town, &Handoff{Operator: "bob", Notes: "quiet night"}, start.Add(16*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = LatestHandoff(town)
+	if got == nil || got.Operator != "bob" || got.Notes != "quiet night" {
+		t.Errorf("LatestHandoff() = %+v, want bob's handoff", got)
+	}
+}
@@ -0,0 +1,509 @@
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+)
+
+// Bead statuses in a Report.
+const (
+	StatusPending     = "pending" // Not yet arrived
+	StatusQueued      = "queued"
+	StatusWorking     = "working"
+	StatusClosed      = "closed"
+	StatusQuarantined = "quarantined" // Circuit-broken after repeated dispatch failures
+)
+
+// never marks an action with no end (an indefinite stall).
+const never time.Duration = -1
+
+// Options configures a run.
+type Options struct {
+	// Runtime supplies agent behavior. Nil uses the scenario's scripts.
+	Runtime Runtime
+
+	// Escalation is the town's escalation config, which the scenario's
+	// [escalation] table overrides. Nil uses the defaults.
+	Escalation *config.EscalationConfig
+}
+
+type beadState struct {
+	spec      BeadSpec
+	status    string
+	attempts  int
+	failures  int
+	completed map[string]bool // Finished formula steps
+	outcome   BeadOutcome
+}
+
+type polecat struct {
+	name   string
+	bead   string
+	action Action
+	until  time.Duration // When the current action ends, or never
+
+	stalled      bool
+	stalledSince time.Duration
+	nudged       bool
+	escalated    bool // Witness has escalated this stall
+}
+
+type escalation struct {
+	bead      string
+	severity  string
+	lastAt    time.Duration
+	count     int
+	ackAt     time.Duration // never if no one acknowledges it
+	acked     bool
+	exhausted bool
+}
+
+type engine struct {
+	s   *Scenario
+	rt  Runtime
+	esc *config.EscalationConfig
+	now time.Duration
+
+	beads       map[string]*beadState
+	queue       []string // FIFO of queued bead ids
+	polecats    []*polecat
+	escalations []*escalation
+	spawned     int
+	busy        time.Duration // Sum of polecat-time, for utilization
+
+	report *Report
+}
+
+// Run simulates a scenario to completion or its time limit.
+func Run(s *Scenario, opts Options) (*Report, error) {
+	e := &engine{
+		s:      s,
+		rt:     opts.Runtime,
+		esc:    s.escalationConfig(opts.Escalation),
+		beads:  make(map[string]*beadState, len(s.Beads)),
+		report: &Report{Scenario: s.Name, MaxPolecats: s.Scheduler.MaxPolecats},
+	}
+	if e.rt == nil {
+		e.rt = NewScriptedRuntime(s)
+	}
+	for _, spec := range s.Beads {
+		e.beads[spec.ID] = &beadState{
+			spec:      spec,
+			status:    StatusPending,
+			completed: make(map[string]bool),
+			outcome:   BeadOutcome{ID: spec.ID},
+		}
+	}
+
+	for e.now = 0; e.now <= s.Duration.Duration; e.now += s.Tick.Duration {
+		e.arrive()
+		e.advance()
+		e.witness()
+		e.ladder()
+		if err := e.dispatch(); err != nil {
+			return nil, err
+		}
+		e.sample()
+		if e.settled() {
+			break
+		}
+	}
+	if e.now > s.Duration.Duration {
+		e.now = s.Duration.Duration
+	}
+	return e.finishReport(), nil
+}
+
+func (e *engine) record(at time.Duration, kind, bead, pc, detail string) {
+	e.report.Events = append(e.report.Events, Event{At: Duration{at}, Kind: kind, Bead: bead, Polecat: pc, Detail: detail})
+}
+
+// arrive queues beads whose arrival time has come.
+func (e *engine) arrive() {
+	for _, spec := range e.s.Beads {
+		b := e.beads[spec.ID]
+		if b.status == StatusPending && spec.Arrive.Duration <= e.now {
+			b.status = StatusQueued
+			b.outcome.QueuedAt = &Duration{spec.Arrive.Duration}
+			e.queue = append(e.queue, spec.ID)
+			e.record(spec.Arrive.Duration, EventQueued, spec.ID, "", spec.Title)
+		}
+	}
+}
+
+// advance runs every polecat's script up to the current time.
+func (e *engine) advance() {
+	for _, p := range append([]*polecat(nil), e.polecats...) {
+		for e.alive(p) && p.until != never && p.until <= e.now {
+			at := p.until
+			e.complete(p, at)
+			e.startNext(p, at)
+		}
+	}
+}
+
+func (e *engine) alive(p *polecat) bool {
+	for _, q := range e.polecats {
+		if q == p {
+			return true
+		}
+	}
+	return false
+}
+
+// complete applies the effect of p's finished action.
+func (e *engine) complete(p *polecat, at time.Duration) {
+	switch p.action.Do {
+	case ActStep:
+		b := e.beads[p.bead]
+		if f := e.s.formulas[b.spec.Formula]; f != nil {
+			if ready := f.ReadySteps(b.completed); len(ready) > 0 {
+				b.completed[ready[0]] = true
+				e.record(at, EventStep, p.bead, p.name, ready[0])
+			}
+		}
+	case ActStall:
+		p.stalled = false
+		e.record(at, EventResumed, p.bead, p.name, "")
+	}
+}
+
+// startNext pulls actions from the runtime until one takes time.
+func (e *engine) startNext(p *polecat, at time.Duration) {
+	for e.start(p, e.rt.Next(p.bead), at) {
+	}
+}
+
+// start begins action a and reports whether it finished instantly, so the
+// polecat should take its next action at the same moment.
+func (e *engine) start(p *polecat, a Action, at time.Duration) bool {
+	p.action = a
+	switch a.Do {
+	case ActWork, ActStep:
+		p.until = at + orDefault(a.For.Duration, DefaultWorkTime)
+	case ActStall:
+		p.stalled, p.stalledSince, p.nudged, p.escalated = true, at, false, false
+		p.until = never
+		if a.For.Duration > 0 {
+			p.until = at + a.For.Duration
+		}
+	case ActEscalate:
+		e.raise(p.bead, p.name, a.Severity, a.Reason, a.AckAfter.Duration, at)
+		return true
+	case ActCrash, ActFail:
+		e.crash(p, a.Reason, at)
+	case ActDone:
+		e.done(p, at)
+	}
+	return false
+}
+
+func orDefault(d, fallback time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return fallback
+}
+
+func (e *engine) removePolecat(p *polecat) {
+	for i, q := range e.polecats {
+		if q == p {
+			e.polecats = append(e.polecats[:i], e.polecats[i+1:]...)
+			return
+		}
+	}
+}
+
+// crash kills p's session. As with a dead polecat the witness finds, the
+// bead goes back to the queue for a fresh polecat.
+func (e *engine) crash(p *polecat, reason string, at time.Duration) {
+	e.removePolecat(p)
+	b := e.beads[p.bead]
+	b.status = StatusQueued
+	e.queue = append(e.queue, p.bead)
+	e.record(at, EventCrashed, p.bead, p.name, reason)
+}
+
+// done is gt done: the bead closes and the slot frees.
+func (e *engine) done(p *polecat, at time.Duration) {
+	e.removePolecat(p)
+	b := e.beads[p.bead]
+	b.status = StatusClosed
+	b.outcome.ClosedAt = &Duration{at}
+	detail := ""
+	if f := e.s.formulas[b.spec.Formula]; f != nil {
+		var open []string
+		for _, id := range f.GetAllIDs() {
+			if !b.completed[id] {
+				open = append(open, id)
+			}
+		}
+		b.outcome.OpenSteps = len(open)
+		if len(open) > 0 {
+			detail = "formula steps still open: " + strings.Join(open, ", ")
+		}
+	}
+	e.record(at, EventDone, p.bead, p.name, detail)
+}
+
+// raise files an escalation, routed by severity as gt escalate would.
+func (e *engine) raise(bead, from, severity, reason string, ackAfter, at time.Duration) {
+	if severity == "" {
+		severity = config.SeverityMedium
+	}
+	esc := &escalation{bead: bead, severity: severity, lastAt: at, ackAt: never}
+	if ackAfter > 0 {
+		esc.ackAt = at + ackAfter
+	}
+	e.escalations = append(e.escalations, esc)
+	e.report.Escalations++
+	e.beads[bead].outcome.Escalations++
+	e.noteSeverity(bead, severity)
+	detail := fmt.Sprintf("%s → %s", severity, strings.Join(e.esc.GetRouteForSeverity(severity), ", "))
+	if reason != "" {
+		detail += ": " + reason
+	}
+	e.record(at, EventEscalated, bead, from, detail)
+}
+
+func (e *engine) noteSeverity(bead, severity string) {
+	b := e.beads[bead]
+	if severityRank(severity) > severityRank(b.outcome.MaxSeverity) {
+		b.outcome.MaxSeverity = severity
+	}
+	if severityRank(severity) > severityRank(e.report.MaxSeverity) {
+		e.report.MaxSeverity = severity
+	}
+}
+
+func severityRank(severity string) int {
+	for i, s := range config.ValidSeverities() {
+		if s == severity {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// witness nudges polecats that stop making progress, then escalates.
+func (e *engine) witness() {
+	threshold := e.s.Witness.StallThreshold.Duration
+	for _, p := range e.polecats {
+		if !p.stalled {
+			continue
+		}
+		idle := e.now - p.stalledSince
+		if idle >= threshold && !p.nudged {
+			p.nudged = true
+			e.record(e.now, EventNudged, p.bead, p.name, "no progress for "+idle.String())
+		}
+		if idle >= 2*threshold && !p.escalated {
+			p.escalated = true
+			e.raise(p.bead, "witness", config.SeverityMedium, p.name+" stalled", 0, e.now)
+		}
+	}
+}
+
+// ladder acknowledges and re-escalates open escalations, following the
+// same skip rules as gt escalate stale.
+func (e *engine) ladder() {
+	stale := e.esc.GetStaleThreshold()
+	maxRe := e.esc.GetMaxReescalations()
+	for _, esc := range e.escalations {
+		if esc.acked || esc.exhausted {
+			continue
+		}
+		if esc.ackAt != never && e.now >= esc.ackAt {
+			esc.acked = true
+			e.record(esc.ackAt, EventAcked, esc.bead, "", esc.severity)
+			continue
+		}
+		if e.now-esc.lastAt < stale {
+			continue
+		}
+		if (maxRe > 0 && esc.count >= maxRe) || esc.severity == config.SeverityCritical {
+			esc.exhausted = true
+			e.record(e.now, EventLadderTop, esc.bead, "", esc.severity)
+			continue
+		}
+		prev := esc.severity
+		esc.severity = config.NextSeverity(prev)
+		esc.count++
+		esc.lastAt = e.now
+		e.report.Reescalations++
+		e.noteSeverity(esc.bead, esc.severity)
+		e.record(e.now, EventReescalated, esc.bead, "", fmt.Sprintf("%s → %s (%s)",
+			prev, esc.severity, strings.Join(e.esc.GetRouteForSeverity(esc.severity), ", ")))
+	}
+}
+
+// dispatch runs one scheduler cycle through the real dispatch pipeline.
+func (e *engine) dispatch() error {
+	sched := e.s.Scheduler
+	direct := sched.MaxPolecats <= 0
+	batch := sched.BatchSize
+	if direct {
+		batch = len(e.queue)
+	}
+	policy := capacity.CircuitBreakerPolicy(sched.MaxDispatchFailures)
+	cycle := &capacity.DispatchCycle{
+		AvailableCapacity: func() (int, error) {
+			if direct {
+				return len(e.queue), nil
+			}
+			return sched.MaxPolecats - len(e.polecats), nil
+		},
+		QueryPending: func() ([]capacity.PendingBead, error) {
+			return e.pending(), nil
+		},
+		Execute: e.spawn,
+		OnFailure: func(pb capacity.PendingBead, err error) {
+			b := e.beads[pb.WorkBeadID]
+			b.failures++
+			b.outcome.DispatchFailures = b.failures
+			if policy(b.failures) == capacity.FailureQuarantine {
+				e.dequeue(pb.WorkBeadID)
+				b.status = StatusQuarantined
+				e.record(e.now, EventQuarantined, pb.WorkBeadID, "", fmt.Sprintf("%d dispatch failures", b.failures))
+				return
+			}
+			e.record(e.now, EventDispatchFailed, pb.WorkBeadID, "", err.Error())
+		},
+		BatchSize: batch,
+	}
+	if len(e.queue) == 0 {
+		return nil
+	}
+	_, err := cycle.Run()
+	return err
+}
+
+// pending lists queued beads as the scheduler sees sling contexts, with
+// blocked beads filtered out.
+func (e *engine) pending() []capacity.PendingBead {
+	ready := make(map[string]bool)
+	var pending []capacity.PendingBead
+	for _, id := range e.queue {
+		b := e.beads[id]
+		if e.unblocked(b) {
+			ready[id] = true
+		}
+		pending = append(pending, capacity.PendingBead{
+			ID:         "sim-ctx-" + id,
+			WorkBeadID: id,
+			Title:      b.spec.Title,
+			TargetRig:  b.spec.Rig,
+			Context: &capacity.SlingContextFields{
+				WorkBeadID:       id,
+				TargetRig:        b.spec.Rig,
+				Formula:          b.spec.Formula,
+				DispatchFailures: b.failures,
+			},
+		})
+	}
+	pending = capacity.BlockerAware(ready)(pending)
+	pending, _ = capacity.FilterCircuitBroken(pending, e.s.Scheduler.MaxDispatchFailures)
+	return pending
+}
+
+func (e *engine) unblocked(b *beadState) bool {
+	for _, need := range b.spec.Needs {
+		if e.beads[need].status != StatusClosed {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *engine) dequeue(id string) {
+	for i, q := range e.queue {
+		if q == id {
+			e.queue = append(e.queue[:i], e.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+var errSpawnFailed = errors.New("spawn failed")
+
+// spawn starts a polecat on a bead, or fails if its script says to.
+func (e *engine) spawn(pb capacity.PendingBead) error {
+	id := pb.WorkBeadID
+	b := e.beads[id]
+	first := e.rt.Next(id)
+	if first.Do == ActFail {
+		if first.Reason != "" {
+			return fmt.Errorf("%w: %s", errSpawnFailed, first.Reason)
+		}
+		return errSpawnFailed
+	}
+
+	e.dequeue(id)
+	e.spawned++
+	p := &polecat{name: fmt.Sprintf("polecat-%d", e.spawned), bead: id}
+	e.polecats = append(e.polecats, p)
+	b.status = StatusWorking
+	b.attempts++
+	b.outcome.Attempts = b.attempts
+	if b.outcome.DispatchedAt == nil {
+		b.outcome.DispatchedAt = &Duration{e.now}
+	}
+	e.record(e.now, EventDispatched, id, p.name, b.spec.Rig)
+	if e.start(p, first, e.now) {
+		e.startNext(p, e.now)
+	}
+	return nil
+}
+
+// sample records per-tick load.
+func (e *engine) sample() {
+	if n := len(e.polecats); n > e.report.PeakPolecats {
+		e.report.PeakPolecats = n
+	}
+	if n := len(e.queue); n > e.report.MaxQueueDepth {
+		e.report.MaxQueueDepth = n
+	}
+	e.busy += time.Duration(len(e.polecats)) * e.s.Tick.Duration
+}
+
+// settled reports whether nothing further can happen.
+func (e *engine) settled() bool {
+	if len(e.polecats) > 0 {
+		return false
+	}
+	for _, b := range e.beads {
+		if b.status == StatusPending {
+			return false
+		}
+		if b.status == StatusQueued && e.unblocked(b) {
+			return false
+		}
+	}
+	for _, esc := range e.escalations {
+		if !esc.acked && !esc.exhausted {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *engine) finishReport() *Report {
+	r := e.report
+	r.Elapsed = Duration{e.now}
+	if r.MaxPolecats > 0 && e.now > 0 {
+		r.Utilization = float64(e.busy) / float64(time.Duration(r.MaxPolecats)*(e.now+e.s.Tick.Duration))
+	}
+	for _, spec := range e.s.Beads {
+		b := e.beads[spec.ID]
+		b.outcome.Status = b.status
+		r.Beads = append(r.Beads, b.outcome)
+	}
+	sort.SliceStable(r.Events, func(i, j int) bool { return r.Events[i].At.Duration < r.Events[j].At.Duration })
+	return r
+}
@@ -0,0 +1,150 @@
+package sim
+
+import (
+	"fmt"
+	"time"
+)
+
+// Event kinds in a Report timeline.
+const (
+	EventQueued         = "queued"
+	EventDispatched     = "dispatched"
+	EventDispatchFailed = "dispatch-failed"
+	EventQuarantined    = "quarantined"
+	EventStep           = "step"
+	EventResumed        = "resumed"
+	EventNudged         = "nudged"
+	EventCrashed        = "crashed"
+	EventDone           = "done"
+	EventEscalated      = "escalated"
+	EventReescalated    = "reescalated"
+	EventAcked          = "acked"
+	EventLadderTop      = "ladder-exhausted"
+)
+
+// Report is the outcome of a run.
+type Report struct {
+	Scenario string   `json:"scenario"`
+	Elapsed  Duration `json:"elapsed"`
+
+	Beads  []BeadOutcome `json:"beads"`
+	Events []Event       `json:"events"`
+
+	MaxPolecats   int     `json:"max_polecats"`
+	PeakPolecats  int     `json:"peak_polecats"`
+	MaxQueueDepth int     `json:"max_queue_depth"`
+	Utilization   float64 `json:"utilization,omitempty"` // Polecat slots in use, 0-1; capped schedulers only
+
+	Escalations   int    `json:"escalations"`
+	Reescalations int    `json:"reescalations"`
+	MaxSeverity   string `json:"max_severity,omitempty"`
+}
+
+// BeadOutcome is what happened to one bead.
+type BeadOutcome struct {
+	ID               string    `json:"id"`
+	Status           string    `json:"status"`
+	Attempts         int       `json:"attempts"`
+	DispatchFailures int       `json:"dispatch_failures,omitempty"`
+	QueuedAt         *Duration `json:"queued_at,omitempty"`
+	DispatchedAt     *Duration `json:"dispatched_at,omitempty"`
+	ClosedAt         *Duration `json:"closed_at,omitempty"`
+	OpenSteps        int       `json:"open_steps,omitempty"` // Formula steps not finished at gt done
+	Escalations      int       `json:"escalations,omitempty"`
+	MaxSeverity      string    `json:"max_severity,omitempty"`
+}
+
+// Event is one entry in the timeline. At is the offset from the start of
+// the run.
+type Event struct {
+	At      Duration `json:"at"`
+	Kind    string   `json:"kind"`
+	Bead    string   `json:"bead,omitempty"`
+	Polecat string   `json:"polecat,omitempty"`
+	Detail  string   `json:"detail,omitempty"`
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Expect is a scenario's assertions about its outcome.
+type Expect struct {
+	AllClosed     bool     `toml:"all_closed"`
+	Within        Duration `toml:"within"` // Every bead closed by this offset
+	Closed        []string `toml:"closed"`
+	Quarantined   []string `toml:"quarantined"`
+	MaxSeverity   string   `toml:"max_severity"` // Highest severity any escalation reached
+	StepsComplete bool     `toml:"steps_complete"`
+}
+
+// Bead returns the outcome for id, or nil.
+func (r *Report) Bead(id string) *BeadOutcome {
+	for i := range r.Beads {
+		if r.Beads[i].ID == id {
+			return &r.Beads[i]
+		}
+	}
+	return nil
+}
+
+// Check returns a description of each expectation the run did not meet.
+func (r *Report) Check(x Expect) []string {
+	var failures []string
+	fail := func(format string, args ...any) {
+		failures = append(failures, fmt.Sprintf(format, args...))
+	}
+
+	for _, b := range r.Beads {
+		if x.AllClosed && b.Status != StatusClosed {
+			fail("%s is %s, want closed", b.ID, b.Status)
+		}
+		if x.Within.Duration > 0 && b.ClosedAt != nil && b.ClosedAt.Duration > x.Within.Duration {
+			fail("%s closed at %s, want within %s", b.ID, b.ClosedAt, x.Within)
+		}
+		if x.StepsComplete && b.OpenSteps > 0 {
+			fail("%s finished with %d formula steps open", b.ID, b.OpenSteps)
+		}
+	}
+	if x.Within.Duration > 0 && !x.AllClosed {
+		for _, b := range r.Beads {
+			if b.ClosedAt == nil && b.Status != StatusQuarantined {
+				fail("%s is %s, want closed within %s", b.ID, b.Status, x.Within)
+			}
+		}
+	}
+	for _, id := range x.Closed {
+		if b := r.Bead(id); b == nil || b.Status != StatusClosed {
+			fail("%s: want closed, got %s", id, statusOf(b))
+		}
+	}
+	for _, id := range x.Quarantined {
+		if b := r.Bead(id); b == nil || b.Status != StatusQuarantined {
+			fail("%s: want quarantined, got %s", id, statusOf(b))
+		}
+	}
+	if x.MaxSeverity != "" && r.MaxSeverity != x.MaxSeverity {
+		got := r.MaxSeverity
+		if got == "" {
+			got = "no escalations"
+		}
+		fail("max escalation severity is %s, want %s", got, x.MaxSeverity)
+	}
+	return failures
+}
+
+func statusOf(b *BeadOutcome) string {
+	if b == nil {
+		return "no such bead"
+	}
+	return b.Status
+}
+
+// CycleTime is how long a closed bead took from arrival to gt done.
+func (b BeadOutcome) CycleTime() (time.Duration, bool) {
+	if b.QueuedAt == nil || b.ClosedAt == nil {
+		return 0, false
+	}
+	return b.ClosedAt.Duration - b.QueuedAt.Duration, true
+}
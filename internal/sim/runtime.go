@@ -0,0 +1,84 @@
+package sim
+
+import (
+	"fmt"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Action kinds a scripted polecat can take.
+const (
+	ActWork     = "work"     // Make progress for For
+	ActStep     = "step"     // Finish the next ready formula step after For
+	ActEscalate = "escalate" // Raise an escalation and carry on
+	ActStall    = "stall"    // Stop making progress for For (forever if unset)
+	ActCrash    = "crash"    // Session dies; the bead goes back to the queue
+	ActFail     = "fail"     // Spawn fails (a dispatch failure)
+	ActDone     = "done"     // gt done: close the bead and free the slot
+)
+
+// Action is one thing a polecat does.
+type Action struct {
+	Do       string   `toml:"do"`
+	For      Duration `toml:"for"`
+	Severity string   `toml:"severity"`  // escalate
+	Reason   string   `toml:"reason"`    // escalate, crash, fail
+	AckAfter Duration `toml:"ack_after"` // escalate: when a human acknowledges it
+}
+
+func (a Action) validate() error {
+	switch a.Do {
+	case ActWork, ActStep, ActStall, ActCrash, ActFail, ActDone:
+	case ActEscalate:
+		if a.Severity != "" && !config.IsValidSeverity(a.Severity) {
+			return fmt.Errorf("invalid severity %q", a.Severity)
+		}
+	case "":
+		return fmt.Errorf("missing do")
+	default:
+		return fmt.Errorf("unknown action %q", a.Do)
+	}
+	return nil
+}
+
+// Runtime is the agent side of a simulation: it stands in for the LLM
+// session running in a polecat. Run calls Next when a polecat spawns and
+// each time its previous action finishes.
+type Runtime interface {
+	Next(bead string) Action
+}
+
+// ScriptedRuntime replays each bead's scenario script in order. When a
+// script runs out the polecat stalls, as an agent that never calls gt done.
+type ScriptedRuntime struct {
+	scripts map[string][]Action
+	cursor  map[string]int
+}
+
+// NewScriptedRuntime builds the runtime for a scenario's scripts.
+func NewScriptedRuntime(s *Scenario) *ScriptedRuntime {
+	fallback := []Action{{Do: ActWork, For: Duration{DefaultWorkTime}}, {Do: ActDone}}
+	if s.DefaultScript != "" {
+		fallback = s.Scripts[s.DefaultScript].Steps
+	}
+	r := &ScriptedRuntime{scripts: make(map[string][]Action), cursor: make(map[string]int)}
+	for _, b := range s.Beads {
+		steps := fallback
+		if b.Script != "" {
+			steps = s.Scripts[b.Script].Steps
+		}
+		r.scripts[b.ID] = steps
+	}
+	return r
+}
+
+// Next implements Runtime.
+func (r *ScriptedRuntime) Next(bead string) Action {
+	steps := r.scripts[bead]
+	i := r.cursor[bead]
+	if i >= len(steps) {
+		return Action{Do: ActStall}
+	}
+	r.cursor[bead] = i + 1
+	return steps[i]
+}
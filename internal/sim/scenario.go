@@ -0,0 +1,299 @@
+// Package sim runs Gas Town orchestration against scripted agents.
+//
+// A Scenario describes a town in miniature: the beads that arrive and when,
+// the scheduler's capacity, the escalation ladder, and a script for each
+// bead's polecat. Run drives the real dispatch cycle, formula step ordering
+// and escalation routing on a virtual clock, with the agent side supplied
+// by a Runtime instead of an LLM session, and returns a Report of what
+// happened. Nothing touches tmux, bd or the town's files, so a scenario runs
+// in milliseconds and is deterministic.
+//
+// Scenarios are TOML:
+//
+//	name = "escalation-ladder"
+//	tick = "3m"
+//	duration = "12h"
+//
+//	[scheduler]
+//	max_polecats = 2
+//
+//	[[bead]]
+//	id = "gt-a"
+//	formula = "mol-polecat-work"
+//	script = "stuck"
+//
+//	[script.stuck]
+//	steps = [
+//	  { do = "work", for = "20m" },
+//	  { do = "escalate", severity = "low", ack_after = "3h" },
+//	  { do = "done" },
+//	]
+//
+//	[expect]
+//	all_closed = true
+//	max_severity = "high"
+package sim
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+)
+
+// Defaults applied to unset scenario fields.
+const (
+	DefaultTick           = 3 * time.Minute // Daemon heartbeat interval
+	DefaultDuration       = 24 * time.Hour
+	DefaultStallThreshold = 30 * time.Minute
+	DefaultMaxFailures    = 3 // Matches the scheduler's circuit breaker
+	DefaultWorkTime       = 30 * time.Minute
+)
+
+// Scenario is a simulated town and its workload.
+type Scenario struct {
+	Name        string `toml:"name"`
+	Description string `toml:"description"`
+
+	// Tick is the orchestration heartbeat: dispatch, witness and
+	// re-escalation checks run once per tick.
+	Tick     Duration `toml:"tick"`
+	Duration Duration `toml:"duration"` // Simulated time limit
+
+	Scheduler  SchedulerSpec  `toml:"scheduler"`
+	Escalation EscalationSpec `toml:"escalation"`
+	Witness    WitnessSpec    `toml:"witness"`
+
+	Beads   []BeadSpec        `toml:"bead"`
+	Scripts map[string]Script `toml:"script"`
+	Expect  Expect            `toml:"expect"`
+
+	// DefaultScript is used by beads that name no script. Unset means
+	// work for DefaultWorkTime, then gt done.
+	DefaultScript string `toml:"default_script"`
+
+	// formulas holds the resolved formula for each formula name in use.
+	formulas map[string]*formula.Formula
+}
+
+// SchedulerSpec mirrors the town's scheduler settings.
+type SchedulerSpec struct {
+	// MaxPolecats caps concurrent polecats. Zero or less means direct
+	// dispatch: every queued bead is slung on the next tick.
+	MaxPolecats         int `toml:"max_polecats"`
+	BatchSize           int `toml:"batch_size"`
+	MaxDispatchFailures int `toml:"max_dispatch_failures"`
+}
+
+// EscalationSpec overrides fields of the town's settings/escalation.json.
+// Unset fields keep the town's values, or the defaults outside a town.
+type EscalationSpec struct {
+	StaleThreshold   Duration            `toml:"stale_threshold"`
+	MaxReescalations *int                `toml:"max_reescalations"`
+	Routes           map[string][]string `toml:"routes"`
+}
+
+// WitnessSpec controls stall detection.
+type WitnessSpec struct {
+	// StallThreshold is how long a polecat may go without progress before
+	// the witness nudges it. At twice the threshold the witness escalates.
+	StallThreshold Duration `toml:"stall_threshold"`
+}
+
+// BeadSpec is one unit of work in a scenario.
+type BeadSpec struct {
+	ID      string   `toml:"id"`
+	Title   string   `toml:"title"`
+	Rig     string   `toml:"rig"`
+	Formula string   `toml:"formula"`
+	Needs   []string `toml:"needs"`  // Beads that must close before this one is ready
+	Arrive  Duration `toml:"arrive"` // When the bead is slung, from the start of the run
+	Script  string   `toml:"script"`
+}
+
+// Script is the sequence of actions a polecat takes on a bead. Attempts
+// share one script: if the first session crashes, the respawned polecat
+// picks up at the action after the crash.
+type Script struct {
+	Steps []Action `toml:"steps"`
+}
+
+// Duration is a time.Duration that decodes from a Go duration string.
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+	return nil
+}
+
+// LoadScenario reads and validates a scenario file. searchPaths are
+// directories to look for formulas not embedded in gt.
+func LoadScenario(path string, searchPaths []string) (*Scenario, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is user-specified
+	if err != nil {
+		return nil, err
+	}
+	s, err := ParseScenario(data, searchPaths)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// ParseScenario decodes and validates a scenario.
+func ParseScenario(data []byte, searchPaths []string) (*Scenario, error) {
+	var s Scenario
+	if _, err := toml.Decode(string(data), &s); err != nil {
+		return nil, fmt.Errorf("parsing scenario: %w", err)
+	}
+	s.applyDefaults()
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	if err := s.loadFormulas(searchPaths); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *Scenario) applyDefaults() {
+	if s.Tick.Duration <= 0 {
+		s.Tick.Duration = DefaultTick
+	}
+	if s.Duration.Duration <= 0 {
+		s.Duration.Duration = DefaultDuration
+	}
+	if s.Scheduler.BatchSize <= 0 {
+		s.Scheduler.BatchSize = 1
+	}
+	if s.Scheduler.MaxDispatchFailures <= 0 {
+		s.Scheduler.MaxDispatchFailures = DefaultMaxFailures
+	}
+	if s.Witness.StallThreshold.Duration <= 0 {
+		s.Witness.StallThreshold.Duration = DefaultStallThreshold
+	}
+	for i := range s.Beads {
+		if s.Beads[i].Rig == "" {
+			s.Beads[i].Rig = "sim"
+		}
+	}
+}
+
+func (s *Scenario) validate() error {
+	if len(s.Beads) == 0 {
+		return fmt.Errorf("scenario has no beads")
+	}
+	ids := make(map[string]bool, len(s.Beads))
+	for _, b := range s.Beads {
+		if b.ID == "" {
+			return fmt.Errorf("bead with no id")
+		}
+		if ids[b.ID] {
+			return fmt.Errorf("duplicate bead %s", b.ID)
+		}
+		ids[b.ID] = true
+	}
+	for _, b := range s.Beads {
+		for _, need := range b.Needs {
+			if !ids[need] {
+				return fmt.Errorf("bead %s needs unknown bead %s", b.ID, need)
+			}
+		}
+		if b.Script != "" {
+			if _, ok := s.Scripts[b.Script]; !ok {
+				return fmt.Errorf("bead %s uses unknown script %q", b.ID, b.Script)
+			}
+		}
+	}
+	if s.DefaultScript != "" {
+		if _, ok := s.Scripts[s.DefaultScript]; !ok {
+			return fmt.Errorf("unknown default_script %q", s.DefaultScript)
+		}
+	}
+	names := make([]string, 0, len(s.Scripts))
+	for name := range s.Scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for i, a := range s.Scripts[name].Steps {
+			if err := a.validate(); err != nil {
+				return fmt.Errorf("script %s step %d: %w", name, i+1, err)
+			}
+		}
+	}
+	if sev := s.Expect.MaxSeverity; sev != "" && !config.IsValidSeverity(sev) {
+		return fmt.Errorf("expect.max_severity: invalid severity %q", sev)
+	}
+	return nil
+}
+
+func (s *Scenario) loadFormulas(searchPaths []string) error {
+	s.formulas = make(map[string]*formula.Formula)
+	for _, b := range s.Beads {
+		if b.Formula == "" || s.formulas[b.Formula] != nil {
+			continue
+		}
+		f, err := loadFormula(b.Formula, searchPaths)
+		if err != nil {
+			return fmt.Errorf("bead %s: %w", b.ID, err)
+		}
+		s.formulas[b.Formula] = f
+	}
+	return nil
+}
+
+// loadFormula finds a formula in gt's embedded set or searchPaths and
+// resolves its inheritance, the same lookup bd cook performs.
+func loadFormula(name string, searchPaths []string) (*formula.Formula, error) {
+	data, err := formula.GetEmbeddedFormulaContent(name)
+	if err != nil {
+		found := false
+		for _, dir := range searchPaths {
+			if data, err = os.ReadFile(filepath.Join(dir, name+".formula.toml")); err == nil { //nolint:gosec // G304: controlled search paths
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("formula %q not found", name)
+		}
+	}
+	f, err := formula.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("formula %s: %w", name, err)
+	}
+	return formula.Resolve(f, searchPaths)
+}
+
+// escalationConfig builds the escalation config a run uses: the scenario's
+// own ladder layered over base (the town's config, or defaults when nil).
+func (s *Scenario) escalationConfig(base *config.EscalationConfig) *config.EscalationConfig {
+	cfg := config.NewEscalationConfig()
+	if base != nil {
+		merged := *base
+		cfg = &merged
+	}
+	if s.Escalation.StaleThreshold.Duration > 0 {
+		cfg.StaleThreshold = s.Escalation.StaleThreshold.String()
+	}
+	if s.Escalation.MaxReescalations != nil {
+		cfg.MaxReescalations = s.Escalation.MaxReescalations
+	}
+	if len(s.Escalation.Routes) > 0 {
+		cfg.Routes = s.Escalation.Routes
+	}
+	return cfg
+}
@@ -0,0 +1,269 @@
+package sim
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustRun(t *testing.T, scenario string, searchPaths ...string) *Report {
+	t.Helper()
+	s, err := ParseScenario([]byte(scenario), searchPaths)
+	if err != nil {
+		t.Fatalf("ParseScenario: %v", err)
+	}
+	r, err := Run(s, Options{})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return r
+}
+
+func closedAt(t *testing.T, r *Report, id string) time.Duration {
+	t.Helper()
+	b := r.Bead(id)
+	if b == nil || b.ClosedAt == nil {
+		t.Fatalf("%s not closed: %+v", id, b)
+	}
+	return b.ClosedAt.Duration
+}
+
+func TestRun_CapacityQueueing(t *testing.T) {
+	r := mustRun(t, `
+[scheduler]
+max_polecats = 1
+
+[[bead]]
+id = "gt-a"
+[[bead]]
+id = "gt-b"
+[[bead]]
+id = "gt-c"
+`)
+	if got := closedAt(t, r, "gt-c"); got != 90*time.Minute {
+		t.Errorf("gt-c closed at %s, want 1h30m with one slot", got)
+	}
+	if r.PeakPolecats != 1 || r.MaxQueueDepth != 2 {
+		t.Errorf("peak = %d, queue depth = %d", r.PeakPolecats, r.MaxQueueDepth)
+	}
+	if len(r.Check(Expect{AllClosed: true, Within: Duration{2 * time.Hour}})) != 0 {
+		t.Errorf("Check failed: %v", r.Check(Expect{AllClosed: true, Within: Duration{2 * time.Hour}}))
+	}
+}
+
+func TestRun_DirectDispatchAndNeeds(t *testing.T) {
+	r := mustRun(t, `
+[[bead]]
+id = "gt-a"
+[[bead]]
+id = "gt-b"
+needs = ["gt-a"]
+[[bead]]
+id = "gt-c"
+`)
+	if closedAt(t, r, "gt-a") != 30*time.Minute || closedAt(t, r, "gt-c") != 30*time.Minute {
+		t.Error("unblocked beads should run in parallel under direct dispatch")
+	}
+	if got := closedAt(t, r, "gt-b"); got != time.Hour {
+		t.Errorf("gt-b closed at %s, want after its blocker", got)
+	}
+	if r.PeakPolecats != 2 {
+		t.Errorf("peak = %d, want 2", r.PeakPolecats)
+	}
+}
+
+func TestRun_DispatchFailuresQuarantine(t *testing.T) {
+	r := mustRun(t, `
+[scheduler]
+max_polecats = 2
+
+[[bead]]
+id = "gt-bad"
+script = "broken"
+[[bead]]
+id = "gt-after"
+needs = ["gt-bad"]
+
+[script.broken]
+steps = [{ do = "fail", reason = "worktree exists" }, { do = "fail" }, { do = "fail" }, { do = "done" }]
+`)
+	b := r.Bead("gt-bad")
+	if b.Status != StatusQuarantined || b.DispatchFailures != 3 || b.Attempts != 0 {
+		t.Errorf("gt-bad = %+v", b)
+	}
+	if got := r.Bead("gt-after").Status; got != StatusQueued {
+		t.Errorf("gt-after = %s, want left queued behind its quarantined blocker", got)
+	}
+	if r.Elapsed.Duration > time.Hour {
+		t.Errorf("run should settle once nothing can progress, ran %s", r.Elapsed)
+	}
+	if f := r.Check(Expect{Quarantined: []string{"gt-bad"}}); len(f) != 0 {
+		t.Errorf("Check: %v", f)
+	}
+}
+
+func TestRun_EscalationLadder(t *testing.T) {
+	r := mustRun(t, `
+[escalation]
+stale_threshold = "1h"
+max_reescalations = 2
+
+[[bead]]
+id = "gt-a"
+script = "blocked"
+
+[script.blocked]
+steps = [
+  { do = "escalate", severity = "low", reason = "need creds" },
+  { do = "work", for = "10m" },
+  { do = "done" },
+]
+`)
+	if r.MaxSeverity != "high" || r.Reescalations != 2 || r.Escalations != 1 {
+		t.Errorf("severity = %s, reescalations = %d, escalations = %d", r.MaxSeverity, r.Reescalations, r.Escalations)
+	}
+	var kinds []string
+	for _, e := range r.Events {
+		if strings.Contains(e.Kind, "escalat") || e.Kind == EventLadderTop {
+			kinds = append(kinds, e.At.String()+" "+e.Kind)
+		}
+	}
+	want := []string{"0s escalated", "1h0m0s reescalated", "2h0m0s reescalated", "3h0m0s ladder-exhausted"}
+	if strings.Join(kinds, "|") != strings.Join(want, "|") {
+		t.Errorf("ladder = %v, want %v", kinds, want)
+	}
+}
+
+func TestRun_AckStopsLadder(t *testing.T) {
+	r := mustRun(t, `
+[escalation]
+stale_threshold = "1h"
+
+[[bead]]
+id = "gt-a"
+script = "s"
+
+[script.s]
+steps = [{ do = "escalate", severity = "high", ack_after = "30m" }, { do = "done" }]
+`)
+	if r.Reescalations != 0 || r.MaxSeverity != "high" {
+		t.Errorf("reescalations = %d, severity = %s", r.Reescalations, r.MaxSeverity)
+	}
+	if f := r.Check(Expect{MaxSeverity: "critical"}); len(f) != 1 {
+		t.Errorf("Check(critical) = %v, want one failure", f)
+	}
+}
+
+func TestRun_CrashRespawns(t *testing.T) {
+	r := mustRun(t, `
+[[bead]]
+id = "gt-a"
+script = "flaky"
+
+[script.flaky]
+steps = [{ do = "work", for = "10m" }, { do = "crash", reason = "OOM" }, { do = "work", for = "10m" }, { do = "done" }]
+`)
+	b := r.Bead("gt-a")
+	if b.Status != StatusClosed || b.Attempts != 2 {
+		t.Errorf("gt-a = %+v, want closed on the second attempt", b)
+	}
+}
+
+func TestRun_WitnessStall(t *testing.T) {
+	r := mustRun(t, `
+duration = "3h"
+
+[witness]
+stall_threshold = "30m"
+
+[[bead]]
+id = "gt-a"
+script = "silent"
+
+[script.silent]
+steps = [{ do = "work", for = "5m" }]
+`)
+	var nudged, escalated bool
+	for _, e := range r.Events {
+		nudged = nudged || e.Kind == EventNudged
+		escalated = escalated || (e.Kind == EventEscalated && e.Polecat == "witness")
+	}
+	if !nudged || !escalated {
+		t.Errorf("nudged = %v, witness escalated = %v; events: %+v", nudged, escalated, r.Events)
+	}
+	if r.Elapsed.Duration != 3*time.Hour {
+		t.Errorf("elapsed = %s, want the full run for a stuck polecat", r.Elapsed)
+	}
+	if f := r.Check(Expect{AllClosed: true}); len(f) != 1 || !strings.Contains(f[0], "working") {
+		t.Errorf("Check = %v", f)
+	}
+}
+
+func TestRun_FormulaSteps(t *testing.T) {
+	dir := t.TempDir()
+	formula := `formula = "sim-two-step"
+type = "workflow"
+version = 1
+
+[[steps]]
+id = "implement"
+title = "Implement"
+
+[[steps]]
+id = "test"
+title = "Test"
+needs = ["implement"]
+`
+	if err := os.WriteFile(filepath.Join(dir, "sim-two-step.formula.toml"), []byte(formula), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := mustRun(t, `
+[[bead]]
+id = "gt-a"
+formula = "sim-two-step"
+script = "steps"
+[[bead]]
+id = "gt-b"
+formula = "sim-two-step"
+script = "hasty"
+
+[script.steps]
+steps = [{ do = "step", for = "10m" }, { do = "step", for = "10m" }, { do = "done" }]
+[script.hasty]
+steps = [{ do = "step" }, { do = "done" }]
+`, dir)
+	var order []string
+	for _, e := range r.Events {
+		if e.Kind == EventStep && e.Bead == "gt-a" {
+			order = append(order, e.Detail)
+		}
+	}
+	if strings.Join(order, ",") != "implement,test" {
+		t.Errorf("steps = %v, want formula order", order)
+	}
+	f := r.Check(Expect{StepsComplete: true})
+	if len(f) != 1 || !strings.Contains(f[0], "gt-b") {
+		t.Errorf("Check = %v, want gt-b flagged", f)
+	}
+}
+
+func TestParseScenario_Errors(t *testing.T) {
+	tests := map[string]string{
+		"no beads":        `name = "empty"`,
+		"duplicate":       "[[bead]]\nid = \"a\"\n[[bead]]\nid = \"a\"",
+		"unknown need":    "[[bead]]\nid = \"a\"\nneeds = [\"b\"]",
+		"unknown script":  "[[bead]]\nid = \"a\"\nscript = \"x\"",
+		"bad action":      "[[bead]]\nid = \"a\"\nscript = \"x\"\n[script.x]\nsteps = [{ do = \"dance\" }]",
+		"bad severity":    "[[bead]]\nid = \"a\"\nscript = \"x\"\n[script.x]\nsteps = [{ do = \"escalate\", severity = \"meh\" }]",
+		"bad duration":    "tick = \"soon\"\n[[bead]]\nid = \"a\"",
+		"unknown formula": "[[bead]]\nid = \"a\"\nformula = \"no-such-formula\"",
+	}
+	for name, scenario := range tests {
+		if _, err := ParseScenario([]byte(scenario), nil); err == nil {
+			t.Errorf("%s: ParseScenario = nil error", name)
+		}
+	}
+}
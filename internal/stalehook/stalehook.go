@@ -0,0 +1,209 @@
+// Package stalehook finds zombie hooks: beads still hooked to an agent that
+// has shown no sign of working on them (session activity, commits in its
+// worktree, bead updates) for longer than the town's stale_hooks "after".
+//
+// Stale hooks are listed by gt hook list --stale. With auto_unhook, gt hook
+// sweep flags each stale hook and, if it is still stale once the grace
+// period has passed, unhooks it and puts the bead back in the queue. The
+// state file remembers when each hook was flagged.
+//
+// Example (settings/config.json):
+//
+//	"stale_hooks": {"after": "6h", "auto_unhook": true, "grace": "1h"}
+package stalehook
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// DefaultAfter is how long a hook may go without activity before it is
+	// stale, when "after" is unset.
+	DefaultAfter = 6 * time.Hour
+	// DefaultGrace is how long a flagged hook has to recover before it is
+	// unhooked, when "grace" is unset.
+	DefaultGrace = time.Hour
+)
+
+// Config is the stale_hooks section of town settings.
+type Config struct {
+	// After is how long without activity makes a hook stale; DefaultAfter
+	// if empty.
+	After string `json:"after,omitempty"`
+	// AutoUnhook unhooks and requeues stale hooks once Grace has passed
+	// since they were flagged. Off by default: stale hooks are only listed.
+	AutoUnhook bool `json:"auto_unhook,omitempty"`
+	// Grace is how long a flagged hook has to show activity before it is
+	// unhooked; DefaultGrace if empty.
+	Grace string `json:"grace,omitempty"`
+}
+
+// AfterDuration returns After, or DefaultAfter if unset or invalid.
+func (c *Config) AfterDuration() time.Duration {
+	if c == nil {
+		return DefaultAfter
+	}
+	return durationOr(c.After, DefaultAfter)
+}
+
+// GraceDuration returns Grace, or DefaultGrace if unset or invalid.
+func (c *Config) GraceDuration() time.Duration {
+	if c == nil {
+		return DefaultGrace
+	}
+	return durationOr(c.Grace, DefaultGrace)
+}
+
+// Unhooks reports whether the sweep unhooks stale hooks.
+func (c *Config) Unhooks() bool {
+	return c != nil && c.AutoUnhook
+}
+
+// Validate checks the config. A nil config is valid (list only).
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.After != "" {
+		if d, err := time.ParseDuration(c.After); err != nil || d <= 0 {
+			return fmt.Errorf("invalid after %q", c.After)
+		}
+	}
+	if c.Grace != "" {
+		if d, err := time.ParseDuration(c.Grace); err != nil || d < 0 {
+			return fmt.Errorf("invalid grace %q", c.Grace)
+		}
+	}
+	return nil
+}
+
+func durationOr(s string, def time.Duration) time.Duration {
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return d
+	}
+	return def
+}
+
+// Hook is a hooked bead and the last signs of work on it.
+type Hook struct {
+	Bead     string    `json:"bead"`
+	Rig      string    `json:"rig"`
+	Title    string    `json:"title"`
+	Assignee string    `json:"assignee"`
+	Updated  time.Time `json:"updated,omitempty"`  // Bead last updated (at least when it was hooked)
+	Session  bool      `json:"session"`            // Assignee's session is running
+	Activity time.Time `json:"activity,omitempty"` // Last output in the assignee's session
+	Commit   time.Time `json:"commit,omitempty"`   // Last commit in the assignee's worktree
+}
+
+// LastSign returns the most recent sign of work on the hook, or the zero
+// time when there is none.
+func (h *Hook) LastSign() time.Time {
+	last := h.Updated
+	for _, t := range []time.Time{h.Activity, h.Commit} {
+		if t.After(last) {
+			last = t
+		}
+	}
+	return last
+}
+
+// Idle returns how long the hook has gone without a sign of work. A hook
+// with no signs at all has been idle for as long as can be known.
+func (h *Hook) Idle(now time.Time) time.Duration {
+	last := h.LastSign()
+	if last.IsZero() {
+		return time.Duration(math.MaxInt64)
+	}
+	return now.Sub(last)
+}
+
+// Stale reports whether the hook has been idle longer than after.
+func (h *Hook) Stale(after time.Duration, now time.Time) bool {
+	return h.Idle(now) > after
+}
+
+// Flag records when a hook was first seen stale.
+type Flag struct {
+	Assignee string    `json:"assignee"`
+	At       time.Time `json:"at"`
+}
+
+// State records the stale hooks the sweep has flagged, by bead ID.
+type State struct {
+	Flagged map[string]Flag `json:"flagged"`
+}
+
+// StatePath returns the path of the stale hook state file.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "stale-hooks.json")
+}
+
+// LoadState reads the state, returning an empty state when the file doesn't
+// exist yet.
+func LoadState(townRoot string) (*State, error) {
+	state := &State{Flagged: make(map[string]Flag)}
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Flagged == nil {
+		state.Flagged = make(map[string]Flag)
+	}
+	return state, nil
+}
+
+// Save writes the state.
+func (s *State) Save(townRoot string) error {
+	path := StatePath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644) //nolint:gosec // G306: state is not secret
+}
+
+// Sweep updates the flags from the currently stale hooks and returns the
+// ones flagged by this call and the ones whose grace has run out. Flags for
+// hooks that are no longer stale, or that moved to another assignee, are
+// dropped, so a hook that recovers gets a fresh grace period next time.
+func (s *State) Sweep(stale []*Hook, grace time.Duration, now time.Time) (flagged, due []*Hook) {
+	seen := make(map[string]bool, len(stale))
+	for _, h := range stale {
+		seen[h.Bead] = true
+		f, ok := s.Flagged[h.Bead]
+		if !ok || f.Assignee != h.Assignee {
+			f = Flag{Assignee: h.Assignee, At: now}
+			s.Flagged[h.Bead] = f
+			flagged = append(flagged, h)
+		}
+		if !now.Before(f.At.Add(grace)) {
+			due = append(due, h)
+		}
+	}
+	for id := range s.Flagged {
+		if !seen[id] {
+			delete(s.Flagged, id)
+		}
+	}
+	return flagged, due
+}
+
+// Clear drops a bead's flag once it has been unhooked.
+func (s *State) Clear(bead string) {
+	delete(s.Flagged, bead)
+}
@@ -0,0 +1,113 @@
+package stalehook
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigDurations(t *testing.T) {
+	var nilCfg *Config
+	if nilCfg.AfterDuration() != DefaultAfter || nilCfg.GraceDuration() != DefaultGrace || nilCfg.Unhooks() {
+		t.Error("nil config should use defaults and not unhook")
+	}
+	c := &Config{After: "2h", Grace: "0s", AutoUnhook: true}
+	if c.AfterDuration() != 2*time.Hour || c.GraceDuration() != 0 || !c.Unhooks() {
+		t.Errorf("got after %v grace %v unhooks %v", c.AfterDuration(), c.GraceDuration(), c.Unhooks())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"ok", &Config{After: "6h", Grace: "30m", AutoUnhook: true}, false},
+		{"zero grace", &Config{Grace: "0s"}, false},
+		{"bad after", &Config{After: "soon"}, true},
+		{"zero after", &Config{After: "0s"}, true},
+		{"negative grace", &Config{Grace: "-1h"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHookStale(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	h := &Hook{
+		Updated:  now.Add(-10 * time.Hour),
+		Activity: now.Add(-3 * time.Hour),
+		Commit:   now.Add(-5 * time.Hour),
+	}
+	if got := h.LastSign(); !got.Equal(h.Activity) {
+		t.Errorf("LastSign = %v, want session activity", got)
+	}
+	if h.Stale(6*time.Hour, now) {
+		t.Error("hook with activity 3h ago is not stale after 6h")
+	}
+	if !h.Stale(2*time.Hour, now) {
+		t.Error("hook idle 3h is stale after 2h")
+	}
+	if !(&Hook{}).Stale(24*time.Hour, now) {
+		t.Error("hook with no signs of work should be stale")
+	}
+}
+
+func TestSweep(t *testing.T) {
+	townRoot := t.TempDir()
+	state, err := LoadState(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t0 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	a := &Hook{Bead: "gt-a", Assignee: "gastown/polecats/nux"}
+	b := &Hook{Bead: "gt-b", Assignee: "gastown/crew/max"}
+
+	flagged, due := state.Sweep([]*Hook{a, b}, time.Hour, t0)
+	if len(flagged) != 2 || len(due) != 0 {
+		t.Fatalf("first sweep: flagged %d due %d, want 2 and 0", len(flagged), len(due))
+	}
+	if err := state.Save(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	if state, err = LoadState(townRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	// b recovered; a is still stale but within grace.
+	flagged, due = state.Sweep([]*Hook{a}, time.Hour, t0.Add(30*time.Minute))
+	if len(flagged) != 0 || len(due) != 0 {
+		t.Errorf("within grace: flagged %d due %d", len(flagged), len(due))
+	}
+	if _, ok := state.Flagged["gt-b"]; ok {
+		t.Error("recovered hook should lose its flag")
+	}
+
+	_, due = state.Sweep([]*Hook{a}, time.Hour, t0.Add(time.Hour))
+	if len(due) != 1 || due[0].Bead != "gt-a" {
+		t.Errorf("after grace: due = %v, want gt-a", due)
+	}
+
+	// Reassigned to someone else: a fresh grace period.
+	moved := &Hook{Bead: "gt-a", Assignee: "gastown/polecats/toast"}
+	flagged, due = state.Sweep([]*Hook{moved}, time.Hour, t0.Add(2*time.Hour))
+	if len(flagged) != 1 || len(due) != 0 {
+		t.Errorf("reassigned: flagged %d due %d, want 1 and 0", len(flagged), len(due))
+	}
+
+	state.Clear("gt-a")
+	if len(state.Flagged) != 0 {
+		t.Errorf("Clear left %v", state.Flagged)
+	}
+
+	// No grace: due as soon as flagged.
+	if _, due := state.Sweep([]*Hook{b}, 0, t0); len(due) != 1 {
+		t.Errorf("zero grace: due = %v", due)
+	}
+}
@@ -0,0 +1,258 @@
+// Package standup compiles the town standup: a short progress note per
+// active worker, collected on a schedule and published as one report.
+package standup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// maxNoteLen bounds a stored progress note; standup notes are one-liners.
+const maxNoteLen = 280
+
+// Note is the latest progress note an agent reported.
+type Note struct {
+	Agent string    `json:"agent"`
+	Text  string    `json:"text"`
+	At    time.Time `json:"at"`
+}
+
+// Dir returns the directory holding standup state.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "standup")
+}
+
+func notesPath(townRoot string) string { return filepath.Join(Dir(townRoot), "notes.json") }
+func statePath(townRoot string) string { return filepath.Join(Dir(townRoot), "state.json") }
+
+// LatestReportPath returns the path of the last published report.
+func LatestReportPath(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "latest.json")
+}
+
+// LoadNotes returns the latest note per agent, keyed by agent address.
+func LoadNotes(townRoot string) (map[string]Note, error) {
+	notes := make(map[string]Note)
+	if err := readJSON(notesPath(townRoot), &notes); err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// RecordNote stores an agent's progress note, replacing its previous one.
+func RecordNote(townRoot, agent, text string, at time.Time) (Note, error) {
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" {
+		return Note{}, fmt.Errorf("note is empty")
+	}
+	text = truncate(text, maxNoteLen)
+	notes, err := LoadNotes(townRoot)
+	if err != nil {
+		return Note{}, err
+	}
+	note := Note{Agent: agent, Text: text, At: at}
+	notes[agent] = note
+	return note, util.EnsureDirAndWriteJSON(notesPath(townRoot), notes)
+}
+
+// State records when the scheduled prompt and publish last ran, so each
+// happens once per day however often the daemon ticks.
+type State struct {
+	LastPrompt  time.Time `json:"last_prompt,omitempty"`
+	LastPublish time.Time `json:"last_publish,omitempty"`
+}
+
+// LoadState reads the schedule state, returning an empty state when the
+// file doesn't exist yet.
+func LoadState(townRoot string) (*State, error) {
+	state := &State{}
+	if err := readJSON(statePath(townRoot), state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Save writes the schedule state.
+func (s *State) Save(townRoot string) error {
+	return util.EnsureDirAndWriteJSON(statePath(townRoot), s)
+}
+
+// publishGrace is how late a missed publish still goes out (e.g. the daemon
+// was down at standup time). After that the day is skipped.
+const publishGrace = 12 * time.Hour
+
+// Due reports whether the prompt and publish steps are due. The report is
+// published daily at "at" (HH:MM, local time); workers are prompted lead
+// earlier so their notes are fresh.
+func Due(now time.Time, at string, lead time.Duration, state *State) (prompt, publish bool, err error) {
+	hour, minute, err := ParseClock(at)
+	if err != nil {
+		return false, false, err
+	}
+	publishAt := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	promptAt := publishAt.Add(-lead)
+	if now.Before(promptAt) {
+		// Before today's prompt: yesterday's publish may still be owed.
+		publishAt = publishAt.AddDate(0, 0, -1)
+		promptAt = promptAt.AddDate(0, 0, -1)
+	}
+
+	prompt = !now.Before(promptAt) && now.Before(publishAt) && state.LastPrompt.Before(promptAt)
+	publish = !now.Before(publishAt) && now.Before(publishAt.Add(publishGrace)) && state.LastPublish.Before(publishAt)
+	return prompt, publish, nil
+}
+
+// ParseClock parses an HH:MM time of day.
+func ParseClock(s string) (hour, minute int, err error) {
+	h, m, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid time %q: expected HH:MM", s)
+	}
+	hour, err = strconv.Atoi(h)
+	if err != nil || hour < 0 || hour > 23 {
+		return 0, 0, fmt.Errorf("invalid hour in %q: expected 0-23", s)
+	}
+	minute, err = strconv.Atoi(m)
+	if err != nil || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("invalid minute in %q: expected 0-59", s)
+	}
+	return hour, minute, nil
+}
+
+// Entry is one agent's line in the standup report.
+type Entry struct {
+	Agent     string     `json:"agent"`
+	Rig       string     `json:"rig"`
+	Role      string     `json:"role"`
+	Hook      string     `json:"hook,omitempty"`
+	HookTitle string     `json:"hook_title,omitempty"`
+	Note      string     `json:"note,omitempty"`
+	NoteAt    *time.Time `json:"note_at,omitempty"`
+	// Activity is summarized from the agent's pane when it has no fresh note.
+	Activity string `json:"activity,omitempty"`
+}
+
+// Report is the compiled town standup.
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Since       time.Time `json:"since"`
+	Entries     []Entry   `json:"entries"`
+}
+
+// Sort orders entries by rig, then agent.
+func (r *Report) Sort() {
+	sort.Slice(r.Entries, func(i, j int) bool {
+		if r.Entries[i].Rig != r.Entries[j].Rig {
+			return r.Entries[i].Rig < r.Entries[j].Rig
+		}
+		return r.Entries[i].Agent < r.Entries[j].Agent
+	})
+}
+
+// Save writes the report as the latest published standup.
+func (r *Report) Save(townRoot string) error {
+	return util.EnsureDirAndWriteJSON(LatestReportPath(townRoot), r)
+}
+
+// LoadLatest reads the last published report, or nil if none exists.
+func LoadLatest(townRoot string) (*Report, error) {
+	var r *Report
+	if err := readJSON(LatestReportPath(townRoot), &r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Markdown renders the report as a plain-text digest suitable for mail.
+func (r *Report) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Standup for %s (%d agents)\n", r.GeneratedAt.Local().Format("Mon Jan 2 15:04"), len(r.Entries))
+	if len(r.Entries) == 0 {
+		sb.WriteString("\nNo workers running.\n")
+		return sb.String()
+	}
+	rig := "\x00"
+	for _, e := range r.Entries {
+		if e.Rig != rig {
+			rig = e.Rig
+			name := rig
+			if name == "" {
+				name = "town"
+			}
+			fmt.Fprintf(&sb, "\n## %s\n", name)
+		}
+		fmt.Fprintf(&sb, "- %s", e.Agent)
+		if e.Hook != "" {
+			fmt.Fprintf(&sb, " [%s", e.Hook)
+			if e.HookTitle != "" {
+				fmt.Fprintf(&sb, ": %s", e.HookTitle)
+			}
+			sb.WriteString("]")
+		}
+		sb.WriteString("\n")
+		switch {
+		case e.Note != "":
+			fmt.Fprintf(&sb, "  %s\n", e.Note)
+		case e.Activity != "":
+			fmt.Fprintf(&sb, "  (no note) last seen: %s\n", e.Activity)
+		default:
+			sb.WriteString("  (no note)\n")
+		}
+	}
+	return sb.String()
+}
+
+// SummarizePane condenses captured pane lines into a one-line hint of what
+// the agent was last doing. Blank lines, prompt boxes and status chrome are
+// skipped; the last meaningful line wins.
+func SummarizePane(lines []string) string {
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		line = strings.TrimLeft(line, "│╭╰─>⏺●✻·* ")
+		line = strings.TrimRight(line, "│╮╯─ ")
+		if len(line) < 8 || isPaneChrome(line) {
+			continue
+		}
+		return truncate(line, 120)
+	}
+	return ""
+}
+
+// isPaneChrome reports lines that are UI decoration rather than output.
+func isPaneChrome(line string) bool {
+	lower := strings.ToLower(line)
+	for _, marker := range []string{"? for shortcuts", "esc to interrupt", "bypass permissions", "auto-accept", "context left", "ctrl+"} {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return strings.Trim(line, "─━═-_ ") == ""
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
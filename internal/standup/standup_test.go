@@ -0,0 +1,129 @@
+package standup
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordNote(t *testing.T) {
+	townRoot := t.TempDir()
+	at := time.Date(2026, 3, 2, 8, 45, 0, 0, time.UTC)
+
+	if _, err := RecordNote(townRoot, "gastown/Toast", "   ", at); err == nil {
+		t.Error("expected error for empty note")
+	}
+	if _, err := RecordNote(townRoot, "gastown/Toast", "first", at); err != nil {
+		t.Fatal(err)
+	}
+	note, err := RecordNote(townRoot, "gastown/Toast", "tests green,\n  next: docs", at.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note.Text != "tests green, next: docs" {
+		t.Errorf("note text = %q, want whitespace collapsed", note.Text)
+	}
+
+	notes, err := LoadNotes(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 1 || notes["gastown/Toast"].Text != "tests green, next: docs" {
+		t.Errorf("notes = %+v, want latest note only", notes)
+	}
+
+	long, err := RecordNote(townRoot, "gastown/max", strings.Repeat("x", 500), at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len([]rune(long.Text)); n != maxNoteLen {
+		t.Errorf("long note has %d runes, want %d", n, maxNoteLen)
+	}
+}
+
+func TestDue(t *testing.T) {
+	day := func(h, m int) time.Time { return time.Date(2026, 3, 2, h, m, 0, 0, time.Local) }
+	tests := []struct {
+		name        string
+		now         time.Time
+		state       State
+		wantPrompt  bool
+		wantPublish bool
+	}{
+		{"before prompt", day(8, 0), State{}, false, false},
+		{"prompt window", day(8, 40), State{}, true, false},
+		{"already prompted", day(8, 45), State{LastPrompt: day(8, 35)}, false, false},
+		{"publish time", day(9, 0), State{LastPrompt: day(8, 35)}, false, true},
+		{"already published", day(10, 0), State{LastPublish: day(9, 5)}, false, false},
+		{"missed publish within grace", day(15, 0), State{}, false, true},
+		{"yesterday's publish too late", day(7, 0), State{LastPublish: day(9, 0).AddDate(0, 0, -2)}, false, false},
+	}
+	for _, tt := range tests {
+		prompt, publish, err := Due(tt.now, "09:00", 30*time.Minute, &tt.state)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if prompt != tt.wantPrompt || publish != tt.wantPublish {
+			t.Errorf("%s: Due() = (%v, %v), want (%v, %v)", tt.name, prompt, publish, tt.wantPrompt, tt.wantPublish)
+		}
+	}
+
+	if _, _, err := Due(day(9, 0), "9am", 0, &State{}); err == nil {
+		t.Error("expected error for invalid time")
+	}
+}
+
+func TestSummarizePane(t *testing.T) {
+	lines := []string{
+		"⏺ Running go test ./internal/auth/...",
+		"  ok  github.com/x/auth 0.4s",
+		"",
+		"╭──────────────────────╮",
+		"│ >                    │",
+		"╰──────────────────────╯",
+		"  ? for shortcuts",
+	}
+	if got := SummarizePane(lines); got != "ok  github.com/x/auth 0.4s" {
+		t.Errorf("SummarizePane() = %q", got)
+	}
+	if got := SummarizePane([]string{"", "│ > │"}); got != "" {
+		t.Errorf("SummarizePane(chrome only) = %q, want empty", got)
+	}
+}
+
+func TestReportMarkdown(t *testing.T) {
+	at := time.Date(2026, 3, 2, 8, 50, 0, 0, time.UTC)
+	r := &Report{GeneratedAt: at, Entries: []Entry{
+		{Agent: "gastown/Toast", Rig: "gastown", Hook: "gt-abc", HookTitle: "Fix auth", Note: "tests green"},
+		{Agent: "beads/crew/max", Rig: "beads", Activity: "editing README.md"},
+	}}
+	r.Sort()
+	if r.Entries[0].Rig != "beads" {
+		t.Fatalf("Sort() did not order by rig: %+v", r.Entries)
+	}
+	md := r.Markdown()
+	for _, want := range []string{"## beads", "## gastown", "- gastown/Toast [gt-abc: Fix auth]", "  tests green", "(no note) last seen: editing README.md"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestLatestReportRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	r, err := LoadLatest(townRoot)
+	if err != nil || r != nil {
+		t.Fatalf("LoadLatest() on empty town = %v, %v; want nil, nil", r, err)
+	}
+	want := &Report{GeneratedAt: time.Now().UTC().Truncate(time.Second), Entries: []Entry{{Agent: "gastown/Toast"}}}
+	if err := want.Save(townRoot); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadLatest(townRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.GeneratedAt.Equal(want.GeneratedAt) || len(got.Entries) != 1 {
+		t.Errorf("LoadLatest() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,109 @@
+// Package stash defers beads until a date or until another bead closes.
+// A stashed bead is set to status deferred, which keeps it out of the ready
+// queue; the deacon resurfaces it once its condition is met. The state file
+// remembers each stash's condition and note.
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// Stash is one deferred bead and when to bring it back.
+type Stash struct {
+	Bead  string    `json:"bead"`
+	Until time.Time `json:"until,omitzero"`  // Resurface at this time
+	After string    `json:"after,omitempty"` // Resurface once this bead closes
+	Note  string    `json:"note,omitempty"`
+	By    string    `json:"by,omitempty"`
+	At    time.Time `json:"at"`
+}
+
+// Condition describes when the stash resurfaces.
+func (s *Stash) Condition() string {
+	if s.After != "" {
+		return "after " + s.After + " closes"
+	}
+	return "until " + s.Until.Local().Format("2006-01-02 15:04")
+}
+
+// Due reports whether the stash should resurface: its time has come, or
+// the bead it waits for has closed.
+func (s *Stash) Due(now time.Time, afterClosed bool) bool {
+	if s.After != "" {
+		return afterClosed
+	}
+	return !now.Before(s.Until)
+}
+
+// ResurfaceComment is the comment left on a bead when it comes back.
+func (s *Stash) ResurfaceComment() string {
+	msg := fmt.Sprintf("Resurfaced from stash (stashed %s by %s, %s)", s.At.Local().Format("2006-01-02"), orUnknown(s.By), s.Condition())
+	if s.Note != "" {
+		msg += ": " + s.Note
+	}
+	return msg
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
+// State holds the stashed beads, by bead ID.
+type State struct {
+	Stashes map[string]*Stash `json:"stashes"`
+}
+
+// StatePath returns the path of the stash state file.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "stash.json")
+}
+
+// LoadState reads the state, returning an empty state when the file doesn't
+// exist yet.
+func LoadState(townRoot string) (*State, error) {
+	state := &State{Stashes: make(map[string]*Stash)}
+	data, err := os.ReadFile(StatePath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.Stashes == nil {
+		state.Stashes = make(map[string]*Stash)
+	}
+	return state, nil
+}
+
+// Save writes the state.
+func (s *State) Save(townRoot string) error {
+	return util.EnsureDirAndWriteJSON(StatePath(townRoot), s)
+}
+
+// List returns the stashes ordered by when they were stashed.
+func (s *State) List() []*Stash {
+	list := make([]*Stash, 0, len(s.Stashes))
+	for _, st := range s.Stashes {
+		list = append(list, st)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].At.Equal(list[j].At) {
+			return list[i].At.Before(list[j].At)
+		}
+		return list[i].Bead < list[j].Bead
+	})
+	return list
+}
@@ -0,0 +1,48 @@
+package stash
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDue(t *testing.T) {
+	now := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+	until := &Stash{Bead: "gt-a", Until: now}
+	if !until.Due(now, false) || until.Due(now.Add(-time.Minute), false) {
+		t.Error("an --until stash is due from its time on")
+	}
+	after := &Stash{Bead: "gt-a", After: "gt-b"}
+	if after.Due(now, false) || !after.Due(now, true) {
+		t.Error("an --after stash is due once the other bead closes")
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	town := t.TempDir()
+	state, err := LoadState(town)
+	if err != nil || len(state.Stashes) != 0 {
+		t.Fatalf("empty state: %+v, %v", state, err)
+	}
+	at := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	state.Stashes["gt-b"] = &Stash{Bead: "gt-b", After: "gt-c", At: at.Add(time.Hour)}
+	state.Stashes["gt-a"] = &Stash{Bead: "gt-a", Until: at.AddDate(0, 6, 0), Note: "after launch", By: "mayor", At: at}
+	if err := state.Save(town); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadState(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := loaded.List()
+	if len(list) != 2 || list[0].Bead != "gt-a" || !list[0].Until.Equal(at.AddDate(0, 6, 0)) || !list[1].Until.IsZero() {
+		t.Fatalf("loaded = %+v", list)
+	}
+	if c := list[1].Condition(); c != "after gt-c closes" {
+		t.Errorf("Condition() = %q", c)
+	}
+	if c := list[0].ResurfaceComment(); !strings.Contains(c, "by mayor") || !strings.HasSuffix(c, ": after launch") {
+		t.Errorf("ResurfaceComment() = %q", c)
+	}
+}
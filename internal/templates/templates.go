@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"embed"
 	"fmt"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 	"sync"
 	"text/template"
 
+	"github.com/steveyegge/gastown/internal/i18n"
 	"github.com/steveyegge/gastown/internal/templates/commands"
 )
 
@@ -116,7 +118,8 @@ type SupervisorData struct {
 	TownRoot string // Path to the Gas Town workspace
 }
 
-// New creates a new Templates instance.
+// New creates a new Templates instance. Templates translated for the
+// active locale (see package i18n) replace the embedded ones.
 func New() (*Templates, error) {
 	t := &Templates{}
 
@@ -134,9 +137,62 @@ func New() (*Templates, error) {
 	}
 	t.messageTemplates = msgTempl
 
+	if dir := i18n.ActiveDir(); dir != "" {
+		if err := overlayTemplates(t.roleTemplates, filepath.Join(dir, "roles")); err != nil {
+			return nil, err
+		}
+		if err := overlayTemplates(t.messageTemplates, filepath.Join(dir, "messages")); err != nil {
+			return nil, err
+		}
+	}
+
 	return t, nil
 }
 
+// overlayTemplates parses dir's *.md.tmpl files into set, replacing the
+// embedded templates of the same name.
+func overlayTemplates(set *template.Template, dir string) error {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.md.tmpl"))
+	if len(files) == 0 {
+		return nil
+	}
+	if _, err := set.ParseFiles(files...); err != nil {
+		return fmt.Errorf("parsing translated templates in %s: %w", dir, err)
+	}
+	return nil
+}
+
+// ExportTranslatable copies the embedded role and message templates into
+// dir (a locale directory) for translation, leaving files already there
+// alone. It returns the files it wrote, relative to dir.
+func ExportTranslatable(dir string) ([]string, error) {
+	var written []string
+	for _, pattern := range []string{"roles/*.md.tmpl", "messages/*.md.tmpl"} {
+		names, err := fs.Glob(templateFS, pattern)
+		if err != nil {
+			return written, err
+		}
+		for _, name := range names {
+			dest := filepath.Join(dir, filepath.FromSlash(name))
+			if _, err := os.Stat(dest); err == nil {
+				continue
+			}
+			data, err := templateFS.ReadFile(name)
+			if err != nil {
+				return written, err
+			}
+			if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+				return written, err
+			}
+			if err := os.WriteFile(dest, data, 0644); err != nil { //nolint:gosec // G306: templates are not secret
+				return written, err
+			}
+			written = append(written, name)
+		}
+	}
+	return written, nil
+}
+
 // RenderRole renders a role context template.
 func (t *Templates) RenderRole(role string, data RoleData) (string, error) {
 	templateName := role + ".md.tmpl"
@@ -246,14 +302,27 @@ func MissingCommandsFor(workspacePath, agent string) []string {
 	return commands.MissingFor(workspacePath, agent)
 }
 
-// ProvisionSupervisor creates and configures supervisor files for the daemon.
-// On macOS: creates and loads a launchd plist.
-// On Linux: creates and enables a systemd user unit.
-// Returns a message indicating what action was taken (or skipped).
-func ProvisionSupervisor(townRoot string) (string, error) {
+// Supervisor service names.
+const (
+	launchdLabel   = "com.gastown.daemon"
+	systemdService = "gastown-daemon.service"
+)
+
+// SupervisorService is a rendered supervisor service file for the daemon.
+type SupervisorService struct {
+	Kind    string // "launchd" or "systemd"
+	Name    string // launchd label or systemd unit name
+	Path    string // Where the file is installed
+	Content []byte
+}
+
+// RenderSupervisor renders the supervisor service file for this platform
+// without installing it. It returns an error on platforms without a
+// supported supervisor.
+func RenderSupervisor(townRoot string) (*SupervisorService, error) {
 	gtPath, err := os.Executable()
 	if err != nil {
-		return "", fmt.Errorf("finding gt executable: %w", err)
+		return nil, fmt.Errorf("finding gt executable: %w", err)
 	}
 
 	data := SupervisorData{
@@ -263,116 +332,152 @@ func ProvisionSupervisor(townRoot string) (string, error) {
 
 	switch runtime.GOOS {
 	case "darwin":
-		return provisionLaunchd(data)
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("finding home directory: %w", err)
+		}
+		content, err := renderSupervisorTemplate("launchd/"+launchdLabel+".plist", data)
+		if err != nil {
+			return nil, err
+		}
+		return &SupervisorService{
+			Kind:    "launchd",
+			Name:    launchdLabel,
+			Path:    filepath.Join(homeDir, "Library", "LaunchAgents", launchdLabel+".plist"),
+			Content: content,
+		}, nil
 	case "linux":
-		return provisionSystemd(data)
+		// Get XDG_DATA_HOME or use ~/.local/share
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if dataHome == "" {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("finding home directory: %w", err)
+			}
+			dataHome = filepath.Join(homeDir, ".local", "share")
+		}
+		content, err := renderSupervisorTemplate("systemd/"+systemdService, data)
+		if err != nil {
+			return nil, err
+		}
+		return &SupervisorService{
+			Kind:    "systemd",
+			Name:    systemdService,
+			Path:    filepath.Join(dataHome, "systemd", "user", systemdService),
+			Content: content,
+		}, nil
 	default:
-		return fmt.Sprintf("Supervisor auto-configuration skipped on %s (not supported yet)", runtime.GOOS), nil
+		return nil, fmt.Errorf("no supported supervisor on %s", runtime.GOOS)
 	}
 }
 
-// provisionLaunchd creates and loads a launchd plist on macOS.
-func provisionLaunchd(data SupervisorData) (string, error) {
-	homeDir, err := os.UserHomeDir()
+// renderSupervisorTemplate executes an embedded supervisor template.
+func renderSupervisorTemplate(name string, data SupervisorData) ([]byte, error) {
+	templateContent, err := supervisorFS.ReadFile(name)
 	if err != nil {
-		return "", fmt.Errorf("finding home directory: %w", err)
+		return nil, fmt.Errorf("reading supervisor template %s: %w", name, err)
 	}
 
-	agentsDir := filepath.Join(homeDir, "Library", "LaunchAgents")
-	if err := os.MkdirAll(agentsDir, 0755); err != nil {
-		return "", fmt.Errorf("creating LaunchAgents directory: %w", err)
+	tmpl, err := template.New(filepath.Base(name)).Parse(string(templateContent))
+	if err != nil {
+		return nil, fmt.Errorf("parsing supervisor template %s: %w", name, err)
 	}
 
-	plistPath := filepath.Join(agentsDir, "com.gastown.daemon.plist")
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("rendering supervisor template %s: %w", name, err)
+	}
+	return buf.Bytes(), nil
+}
 
-	// Read the template
-	templateContent, err := supervisorFS.ReadFile("launchd/com.gastown.daemon.plist")
-	if err != nil {
-		return "", fmt.Errorf("reading launchd template: %w", err)
+// ProvisionSupervisor creates and configures supervisor files for the daemon.
+// On macOS: creates and loads a launchd plist.
+// On Linux: creates and enables a systemd user unit.
+// Returns a message indicating what action was taken (or skipped).
+func ProvisionSupervisor(townRoot string) (string, error) {
+	switch runtime.GOOS {
+	case "darwin", "linux":
+	default:
+		return fmt.Sprintf("Supervisor auto-configuration skipped on %s (not supported yet)", runtime.GOOS), nil
 	}
 
-	// Parse and execute template
-	tmpl, err := template.New("launchd").Parse(string(templateContent))
+	svc, err := RenderSupervisor(townRoot)
 	if err != nil {
-		return "", fmt.Errorf("parsing launchd template: %w", err)
+		return "", err
 	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("rendering launchd template: %w", err)
+	if err := os.MkdirAll(filepath.Dir(svc.Path), 0755); err != nil {
+		return "", fmt.Errorf("creating %s: %w", filepath.Dir(svc.Path), err)
+	}
+	if err := os.WriteFile(svc.Path, svc.Content, 0644); err != nil {
+		return "", fmt.Errorf("writing %s: %w", svc.Path, err)
 	}
 
-	// Write plist file
-	if err := os.WriteFile(plistPath, buf.Bytes(), 0644); err != nil {
-		return "", fmt.Errorf("writing plist file: %w", err)
+	if svc.Kind == "launchd" {
+		return loadLaunchd(svc)
 	}
+	return enableSystemd(svc)
+}
 
+// loadLaunchd loads an installed launchd plist on macOS.
+func loadLaunchd(svc *SupervisorService) (string, error) {
 	// Unload if already loaded (ignore errors)
-	_ = exec.Command("launchctl", "unload", plistPath).Run()
+	_ = exec.Command("launchctl", "unload", svc.Path).Run()
 
 	// Load the service
-	if output, err := exec.Command("launchctl", "load", plistPath).CombinedOutput(); err != nil {
+	if output, err := exec.Command("launchctl", "load", svc.Path).CombinedOutput(); err != nil {
 		return "", fmt.Errorf("loading launchd service: %s", string(output))
 	}
 
-	return "Created and loaded launchd service: com.gastown.daemon", nil
+	return "Created and loaded launchd service: " + svc.Name, nil
 }
 
-// provisionSystemd creates and enables a systemd user unit on Linux.
-func provisionSystemd(data SupervisorData) (string, error) {
-	// Get XDG_DATA_HOME or use ~/.local/share
-	dataHome := os.Getenv("XDG_DATA_HOME")
-	if dataHome == "" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return "", fmt.Errorf("finding home directory: %w", err)
-		}
-		dataHome = filepath.Join(homeDir, ".local", "share")
+// enableSystemd enables and starts an installed systemd user unit on Linux.
+func enableSystemd(svc *SupervisorService) (string, error) {
+	// Reload systemd daemon
+	if output, err := exec.Command("systemctl", "--user", "daemon-reload").CombinedOutput(); err != nil {
+		return "", fmt.Errorf("reloading systemd: %s", string(output))
 	}
 
-	systemdDir := filepath.Join(dataHome, "systemd", "user")
-	if err := os.MkdirAll(systemdDir, 0755); err != nil {
-		return "", fmt.Errorf("creating systemd user directory: %w", err)
+	// Enable the service
+	if output, err := exec.Command("systemctl", "--user", "enable", svc.Name).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("enabling systemd service: %s", string(output))
 	}
 
-	servicePath := filepath.Join(systemdDir, "gastown-daemon.service")
-
-	// Read the template
-	templateContent, err := supervisorFS.ReadFile("systemd/gastown-daemon.service")
-	if err != nil {
-		return "", fmt.Errorf("reading systemd template: %w", err)
+	// Start the service
+	if output, err := exec.Command("systemctl", "--user", "start", svc.Name).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("starting systemd service: %s", string(output))
 	}
 
-	// Parse and execute template
-	tmpl, err := template.New("systemd").Parse(string(templateContent))
-	if err != nil {
-		return "", fmt.Errorf("parsing systemd template: %w", err)
-	}
+	return "Created and enabled systemd user service: " + svc.Name, nil
+}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("rendering systemd template: %w", err)
+// RemoveSupervisor stops and removes the daemon's supervisor service.
+// The daemon itself is stopped by the supervisor as the service unloads.
+// Returns a message indicating what was removed.
+func RemoveSupervisor(townRoot string) (string, error) {
+	svc, err := RenderSupervisor(townRoot)
+	if err != nil {
+		return "", err
 	}
-
-	// Write service file
-	if err := os.WriteFile(servicePath, buf.Bytes(), 0644); err != nil {
-		return "", fmt.Errorf("writing service file: %w", err)
+	if _, err := os.Stat(svc.Path); os.IsNotExist(err) {
+		return fmt.Sprintf("No %s service installed at %s", svc.Kind, svc.Path), nil
 	}
 
-	// Reload systemd daemon
-	if output, err := exec.Command("systemctl", "--user", "daemon-reload").CombinedOutput(); err != nil {
-		return "", fmt.Errorf("reloading systemd: %s", string(output))
+	if svc.Kind == "launchd" {
+		if output, err := exec.Command("launchctl", "unload", svc.Path).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("unloading launchd service: %s", string(output))
+		}
+	} else {
+		if output, err := exec.Command("systemctl", "--user", "disable", "--now", svc.Name).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("disabling systemd service: %s", string(output))
+		}
 	}
-
-	// Enable the service
-	if output, err := exec.Command("systemctl", "--user", "enable", "gastown-daemon.service").CombinedOutput(); err != nil {
-		return "", fmt.Errorf("enabling systemd service: %s", string(output))
+	if err := os.Remove(svc.Path); err != nil {
+		return "", fmt.Errorf("removing %s: %w", svc.Path, err)
 	}
-
-	// Start the service
-	if output, err := exec.Command("systemctl", "--user", "start", "gastown-daemon.service").CombinedOutput(); err != nil {
-		return "", fmt.Errorf("starting systemd service: %s", string(output))
+	if svc.Kind == "systemd" {
+		_ = exec.Command("systemctl", "--user", "daemon-reload").Run()
 	}
 
-	return "Created and enabled systemd user service: gastown-daemon.service", nil
+	return fmt.Sprintf("Removed %s service: %s", svc.Kind, svc.Name), nil
 }
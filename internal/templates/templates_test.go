@@ -2,10 +2,14 @@ package templates
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/i18n"
 )
 
 func TestNew(t *testing.T) {
@@ -535,3 +539,100 @@ func TestRoleNames(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderSupervisor(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skipf("no supervisor on %s", runtime.GOOS)
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", "")
+	townRoot := "/tmp/test-town"
+
+	svc, err := RenderSupervisor(townRoot)
+	if err != nil {
+		t.Fatalf("RenderSupervisor() error = %v", err)
+	}
+	content := string(svc.Content)
+	if !strings.Contains(content, townRoot) || !strings.Contains(content, "daemon") {
+		t.Errorf("service file missing town root or daemon command:\n%s", content)
+	}
+	if strings.Contains(content, "{{") {
+		t.Errorf("unrendered template placeholders:\n%s", content)
+	}
+	switch svc.Kind {
+	case "systemd":
+		if !strings.HasSuffix(svc.Path, "systemd/user/gastown-daemon.service") || !strings.Contains(content, "Restart=always") {
+			t.Errorf("unexpected systemd unit at %s:\n%s", svc.Path, content)
+		}
+	case "launchd":
+		if !strings.HasSuffix(svc.Path, "LaunchAgents/com.gastown.daemon.plist") || !strings.Contains(content, "KeepAlive") {
+			t.Errorf("unexpected launchd plist at %s:\n%s", svc.Path, content)
+		}
+	default:
+		t.Errorf("unexpected kind %q", svc.Kind)
+	}
+}
+
+func TestNew_LocaleOverlay(t *testing.T) {
+	town := t.TempDir()
+	dir := i18n.Dir(town, "ja")
+	if err := os.MkdirAll(filepath.Join(dir, "roles"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	translated := "# 市長 {{ .TownRoot }}\n"
+	if err := os.WriteFile(filepath.Join(dir, "roles", "mayor.md.tmpl"), []byte(translated), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := i18n.Use(town, "ja"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(i18n.Reset)
+
+	tmpl, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	data := RoleData{Role: "mayor", TownRoot: "/test/town"}
+	output, err := tmpl.RenderRole("mayor", data)
+	if err != nil {
+		t.Fatalf("RenderRole(mayor) error = %v", err)
+	}
+	if output != "# 市長 /test/town\n" {
+		t.Errorf("translated mayor = %q", output)
+	}
+	// Roles without a translation keep the embedded English.
+	output, err = tmpl.RenderRole("polecat", RoleData{Role: "polecat", RigName: "gastown", Polecat: "toast"})
+	if err != nil {
+		t.Fatalf("RenderRole(polecat) error = %v", err)
+	}
+	if !strings.Contains(output, "Polecat") {
+		t.Errorf("untranslated polecat lost its English:\n%s", output)
+	}
+}
+
+func TestExportTranslatable(t *testing.T) {
+	dir := t.TempDir()
+	mine := filepath.Join(dir, "roles", "mayor.md.tmpl")
+	if err := os.MkdirAll(filepath.Dir(mine), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(mine, []byte("translated"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := ExportTranslatable(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range written {
+		if name == "roles/mayor.md.tmpl" {
+			t.Error("existing translation reported as written")
+		}
+	}
+	if data, _ := os.ReadFile(mine); string(data) != "translated" {
+		t.Errorf("existing translation overwritten: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "messages", "nudge.md.tmpl")); err != nil {
+		t.Errorf("nudge template not exported: %v", err)
+	}
+}
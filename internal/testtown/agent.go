@@ -0,0 +1,151 @@
+package testtown
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Step is one action of a scripted agent. Exactly one field is set; use
+// the constructors below.
+type Step struct {
+	Run   []string      `json:"run,omitempty"`   // Command to run; $VARS are expanded from the agent's environment
+	Say   string        `json:"say,omitempty"`   // Line printed to stdout, as an agent's reply would be
+	Sleep time.Duration `json:"sleep,omitempty"` // Pause, e.g. to let a patrol notice the session
+	Exit  *int          `json:"exit,omitempty"`  // Stop with this status
+}
+
+// Run returns a step that runs argv and stops the agent if it fails.
+func Run(argv ...string) Step { return Step{Run: argv} }
+
+// Say returns a step that prints text.
+func Say(text string) Step { return Step{Say: text} }
+
+// Sleep returns a step that pauses for d.
+func Sleep(d time.Duration) Step { return Step{Sleep: d} }
+
+// Exit returns a step that stops the agent with code.
+func Exit(code int) Step { return Step{Exit: &code} }
+
+// agentScript is the file a scripted agent is started with.
+type agentScript struct {
+	Steps []Step `json:"steps"`
+	Log   string `json:"log"`
+}
+
+// Agent registers a custom agent runtime named name that plays steps
+// instead of running a model, and returns the path of its log. Point a
+// role or rig at it with the usual agent settings (default_agent,
+// role_agents, --agent). Every start of the agent appends its argv,
+// working directory and each step's outcome to the log.
+func (town *Town) Agent(name string, steps ...Step) string {
+	town.t.Helper()
+	dir := filepath.Join(town.Root, ".testtown", "agents")
+	scriptPath := filepath.Join(dir, name+".json")
+	logPath := filepath.Join(dir, name+".log")
+	data, err := json.MarshalIndent(agentScript{Steps: steps, Log: logPath}, "", "  ")
+	if err != nil {
+		town.t.Fatalf("testtown: %v", err)
+	}
+	town.write(scriptPath, string(data))
+
+	settingsPath := config.TownSettingsPath(town.Root)
+	settings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		town.t.Fatalf("testtown: %v", err)
+	}
+	if settings.Agents == nil {
+		settings.Agents = make(map[string]*config.RuntimeConfig)
+	}
+	settings.Agents[name] = &config.RuntimeConfig{
+		Provider:   "generic",
+		Command:    filepath.Join(town.BinDir, AgentName),
+		Args:       []string{scriptPath},
+		PromptMode: "none",
+	}
+	if err := config.SaveTownSettings(settingsPath, settings); err != nil {
+		town.t.Fatalf("testtown: %v", err)
+	}
+	return logPath
+}
+
+// AgentLog returns the lines a scripted agent has logged so far.
+func (town *Town) AgentLog(logPath string) []string {
+	town.t.Helper()
+	data, err := os.ReadFile(logPath) //nolint:gosec // G304: path returned by Agent
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		town.t.Fatalf("testtown: %v", err)
+	}
+	return strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+}
+
+// runAgent plays the script named by args[0]. Further args are what the
+// caller passed the runtime (a prompt, flags) and are only logged.
+func runAgent(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "gt-fake-agent: script path required")
+		return 2
+	}
+	data, err := os.ReadFile(args[0]) //nolint:gosec // G304: script written by Agent
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gt-fake-agent: %v\n", err)
+		return 2
+	}
+	var script agentScript
+	if err := json.Unmarshal(data, &script); err != nil {
+		fmt.Fprintf(os.Stderr, "gt-fake-agent: parsing script: %v\n", err)
+		return 2
+	}
+
+	logf := func(format string, a ...any) {
+		f, err := os.OpenFile(script.Log, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G304: path from script
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		fmt.Fprintf(f, format+"\n", a...)
+	}
+	wd, _ := os.Getwd()
+	logf("start dir=%s args=%q", wd, args[1:])
+
+	for _, step := range script.Steps {
+		switch {
+		case len(step.Run) > 0:
+			argv := make([]string, len(step.Run))
+			for i, a := range step.Run {
+				argv[i] = os.ExpandEnv(a)
+			}
+			cmd := exec.Command(argv[0], argv[1:]...) //nolint:gosec // G204: test script
+			out, err := cmd.CombinedOutput()
+			os.Stdout.Write(out)
+			if err != nil {
+				logf("run %s: %v", strings.Join(argv, " "), err)
+				if exitErr, ok := err.(*exec.ExitError); ok {
+					return exitErr.ExitCode()
+				}
+				return 1
+			}
+			logf("run %s: ok", strings.Join(argv, " "))
+		case step.Say != "":
+			fmt.Println(step.Say)
+			logf("say %s", step.Say)
+		case step.Sleep > 0:
+			time.Sleep(step.Sleep)
+			logf("sleep %s", step.Sleep)
+		case step.Exit != nil:
+			logf("exit %d", *step.Exit)
+			return *step.Exit
+		}
+	}
+	logf("exit 0")
+	return 0
+}
@@ -0,0 +1,766 @@
+package testtown
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// FakeBDVersion is what the fake bd reports for "bd version".
+const FakeBDVersion = "bd version 0.99.0 (testtown)"
+
+// storeFile holds a fake database inside its .beads directory.
+const storeFile = "testtown.json"
+
+// The fake bd implements the subset of bd that gt drives:
+//
+//	version, init, create, show, list, search, ready, blocked, update,
+//	close, reopen, label add|remove|list, dep add|remove|list,
+//	config get|set
+//
+// Each .beads directory gets its own JSON database. BEADS_DIR picks the
+// database like it does for bd; without it the working directory's .beads
+// is used, following redirects. IDs whose prefix belongs to another rig
+// are routed through the town's routes.jsonl, as bd's prefix routing does.
+// Anything else fails loudly so a test never passes against a command the
+// fake only pretends to know.
+
+// valueFlags take the next argument as their value when not written as
+// --flag=value.
+var valueFlags = map[string]bool{
+	"--title": true, "--status": true, "--label": true, "--labels": true,
+	"--assignee": true, "--parent": true, "--priority": true,
+	"--description": true, "--id": true, "--type": true, "--limit": true,
+	"--reason": true, "--mol": true, "--actor": true, "--session": true,
+	"--notes": true, "--add-label": true, "--remove-label": true,
+	"--set-labels": true, "--prefix": true, "--db": true,
+	"--desc-contains": true,
+}
+
+// flagAliases maps bd's short flags to their long form.
+var flagAliases = map[string]string{
+	"-t": "--type", "-p": "--priority", "-d": "--description", "-n": "--limit",
+	"-a": "--assignee", "-l": "--label", "-s": "--status", "-q": "--quiet",
+}
+
+// fakeArgs is a parsed bd command line.
+type fakeArgs struct {
+	pos   []string
+	flags map[string][]string
+}
+
+func parseFakeArgs(args []string) fakeArgs {
+	fa := fakeArgs{flags: make(map[string][]string)}
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			fa.pos = append(fa.pos, args[i+1:]...)
+			break
+		}
+		if len(a) < 2 || a[0] != '-' {
+			fa.pos = append(fa.pos, a)
+			continue
+		}
+		name, value, hasValue := strings.Cut(a, "=")
+		if alias, ok := flagAliases[name]; ok {
+			name = alias
+		}
+		if !hasValue {
+			if valueFlags[name] && i+1 < len(args) {
+				i++
+				value = args[i]
+			} else {
+				value = "true"
+			}
+		}
+		fa.flags[name] = append(fa.flags[name], value)
+	}
+	return fa
+}
+
+func (fa fakeArgs) get(name string) string {
+	if v := fa.flags[name]; len(v) > 0 {
+		return v[len(v)-1]
+	}
+	return ""
+}
+
+func (fa fakeArgs) has(name string) bool {
+	return len(fa.flags[name]) > 0
+}
+
+// list returns every value given for the flags, splitting comma lists.
+func (fa fakeArgs) list(names ...string) []string {
+	var out []string
+	for _, name := range names {
+		for _, v := range fa.flags[name] {
+			for _, part := range strings.Split(v, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					out = append(out, part)
+				}
+			}
+		}
+	}
+	return out
+}
+
+// fakeDep is one dependency edge: From depends on To.
+type fakeDep struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+// fakeStore is one fake database.
+type fakeStore struct {
+	Prefix string            `json:"prefix"`
+	Seq    int               `json:"seq"`
+	Issues []*beads.Issue    `json:"issues"`
+	Deps   []fakeDep         `json:"deps,omitempty"`
+	Config map[string]string `json:"config,omitempty"`
+
+	dir string
+}
+
+func loadStore(beadsDir string) (*fakeStore, error) {
+	s := &fakeStore{dir: beadsDir}
+	data, err := os.ReadFile(filepath.Join(beadsDir, storeFile)) //nolint:gosec // G304: test database path
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		s.Prefix = configuredPrefix(beadsDir)
+		return s, nil
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Join(beadsDir, storeFile), err)
+	}
+	return s, nil
+}
+
+func (s *fakeStore) save() error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.dir, storeFile), data, 0644)
+}
+
+// configuredPrefix reads the prefix from a .beads/config.yaml, the way a
+// rig's database is configured before bd init has run.
+func configuredPrefix(beadsDir string) string {
+	data, err := os.ReadFile(filepath.Join(beadsDir, "config.yaml")) //nolint:gosec // G304: test database path
+	if err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			for _, key := range []string{"issue-prefix:", "prefix:"} {
+				if v, ok := strings.CutPrefix(strings.TrimSpace(line), key); ok {
+					if v = strings.Trim(strings.TrimSpace(v), `"'`); v != "" {
+						return strings.TrimSuffix(v, "-")
+					}
+				}
+			}
+		}
+	}
+	return "bd"
+}
+
+func (s *fakeStore) find(id string) *beads.Issue {
+	for _, issue := range s.Issues {
+		if issue.ID == id {
+			return issue
+		}
+	}
+	return nil
+}
+
+func (s *fakeStore) nextID(parent string) string {
+	if parent != "" {
+		n := 0
+		for _, issue := range s.Issues {
+			if issue.Parent == parent {
+				n++
+			}
+		}
+		return fmt.Sprintf("%s.%d", parent, n+1)
+	}
+	s.Seq++
+	return fmt.Sprintf("%s-%03d", s.Prefix, s.Seq)
+}
+
+// view returns a copy of an issue with its dependency fields filled in.
+func (s *fakeStore) view(issue *beads.Issue) *beads.Issue {
+	v := *issue
+	v.Labels = append([]string(nil), issue.Labels...)
+	v.DependsOn, v.BlockedBy, v.Dependencies, v.Dependents, v.Children = nil, nil, nil, nil, nil
+	for _, d := range s.Deps {
+		if d.From == issue.ID {
+			v.DependsOn = append(v.DependsOn, d.To)
+			if dep := s.find(d.To); dep != nil {
+				v.Dependencies = append(v.Dependencies, depView(dep, d.Type))
+				if d.Type == "blocks" && dep.Status != "closed" {
+					v.BlockedBy = append(v.BlockedBy, d.To)
+				}
+			}
+		}
+		if d.To == issue.ID {
+			if dep := s.find(d.From); dep != nil {
+				v.Dependents = append(v.Dependents, depView(dep, d.Type))
+			}
+		}
+	}
+	for _, other := range s.Issues {
+		if other.Parent == issue.ID {
+			v.Children = append(v.Children, other.ID)
+		}
+	}
+	v.DependencyCount = len(v.Dependencies)
+	v.DependentCount = len(v.Dependents)
+	v.BlockedByCount = len(v.BlockedBy)
+	return &v
+}
+
+func depView(issue *beads.Issue, depType string) beads.IssueDep {
+	return beads.IssueDep{
+		ID:             issue.ID,
+		Title:          issue.Title,
+		Status:         issue.Status,
+		Priority:       issue.Priority,
+		Type:           issue.Type,
+		DependencyType: depType,
+	}
+}
+
+// fakeBD is one invocation of the fake.
+type fakeBD struct {
+	dir      string // Working directory
+	beadsDir string // BEADS_DIR, if set
+	actor    string // BD_ACTOR, if set
+	stdout   io.Writer
+	stderr   io.Writer
+}
+
+// runFakeBD runs one bd command line and returns its exit code.
+func runFakeBD(f *fakeBD, args []string) int {
+	if err := f.run(parseFakeArgs(args)); err != nil {
+		fmt.Fprintf(f.stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func (f *fakeBD) run(fa fakeArgs) error {
+	if fa.has("--version") || (len(fa.pos) > 0 && fa.pos[0] == "version") {
+		fmt.Fprintln(f.stdout, FakeBDVersion)
+		return nil
+	}
+	if len(fa.pos) == 0 {
+		return fmt.Errorf("fake bd: no command given")
+	}
+	cmd, rest := fa.pos[0], fa.pos[1:]
+	if cmd == "label" || cmd == "dep" || cmd == "config" {
+		if len(rest) == 0 {
+			return fmt.Errorf("fake bd: %s needs a subcommand", cmd)
+		}
+		cmd, rest = cmd+" "+rest[0], rest[1:]
+	}
+
+	// Commands naming an existing issue go to the database its prefix
+	// routes to.
+	target := f.resolveBeadsDir()
+	switch cmd {
+	case "show", "update", "close", "reopen", "label add", "label remove", "label list", "dep add", "dep remove", "dep list":
+		if len(rest) > 0 {
+			target = f.routeID(target, rest[0])
+		}
+	}
+
+	lock := flock.New(filepath.Join(target, ".testtown.lock"))
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return err
+	}
+	if err := lock.Lock(); err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	s, err := loadStore(target)
+	if err != nil {
+		return err
+	}
+	changed, err := f.dispatch(s, cmd, rest, fa)
+	if err != nil {
+		return err
+	}
+	if changed {
+		return s.save()
+	}
+	return nil
+}
+
+func (f *fakeBD) dispatch(s *fakeStore, cmd string, rest []string, fa fakeArgs) (bool, error) {
+	switch cmd {
+	case "init":
+		if p := fa.get("--prefix"); p != "" {
+			s.Prefix = strings.TrimSuffix(p, "-")
+		}
+		fmt.Fprintf(f.stdout, "Initialized beads database with prefix %s\n", s.Prefix)
+		return true, nil
+	case "create":
+		return true, f.create(s, rest, fa)
+	case "show":
+		return false, f.show(s, rest)
+	case "list", "search", "ready", "blocked":
+		return false, f.list(s, cmd, rest, fa)
+	case "update":
+		return true, f.update(s, rest, fa)
+	case "close", "reopen":
+		return true, f.setClosed(s, cmd == "close", rest, fa)
+	case "label add", "label remove":
+		return true, f.label(s, cmd == "label add", rest)
+	case "label list":
+		issue, err := f.issue(s, rest)
+		if err != nil {
+			return false, err
+		}
+		return false, f.print(fa, issue.Labels, strings.Join(issue.Labels, "\n"))
+	case "dep add", "dep remove":
+		return true, f.dep(s, cmd == "dep add", rest, fa)
+	case "dep list":
+		return false, f.depList(s, rest, fa)
+	case "config get":
+		if len(rest) != 1 {
+			return false, fmt.Errorf("usage: bd config get <key>")
+		}
+		v := s.Config[rest[0]]
+		if rest[0] == "issue_prefix" && v == "" {
+			v = s.Prefix
+		}
+		fmt.Fprintln(f.stdout, v)
+		return false, nil
+	case "config set":
+		if len(rest) != 2 {
+			return false, fmt.Errorf("usage: bd config set <key> <value>")
+		}
+		if s.Config == nil {
+			s.Config = make(map[string]string)
+		}
+		s.Config[rest[0]] = rest[1]
+		if rest[0] == "issue_prefix" {
+			s.Prefix = strings.TrimSuffix(rest[1], "-")
+		}
+		return true, nil
+	}
+	return false, fmt.Errorf("fake bd: unsupported command %q", cmd)
+}
+
+func (f *fakeBD) create(s *fakeStore, rest []string, fa fakeArgs) error {
+	title := fa.get("--title")
+	if title == "" && len(rest) > 0 {
+		title = strings.Join(rest, " ")
+	}
+	if title == "" {
+		return fmt.Errorf("title required")
+	}
+	parent := fa.get("--parent")
+	if parent != "" && s.find(parent) == nil {
+		return fmt.Errorf("parent issue %s not found", parent)
+	}
+	id := fa.get("--id")
+	if id == "" {
+		id = s.nextID(parent)
+	} else if s.find(id) != nil {
+		return fmt.Errorf("issue %s already exists", id)
+	}
+	priority := 2
+	if p := fa.get("--priority"); p != "" {
+		n, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(p), "P"))
+		if err != nil {
+			return fmt.Errorf("invalid priority %q", p)
+		}
+		priority = n
+	}
+	issueType := fa.get("--type")
+	if issueType == "" {
+		issueType = "task"
+	}
+	actor := fa.get("--actor")
+	if actor == "" {
+		actor = f.actor
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	issue := &beads.Issue{
+		ID:          id,
+		Title:       title,
+		Description: fa.get("--description"),
+		Status:      "open",
+		Priority:    priority,
+		Type:        issueType,
+		CreatedAt:   now,
+		CreatedBy:   actor,
+		UpdatedAt:   now,
+		Parent:      parent,
+		Assignee:    fa.get("--assignee"),
+		Labels:      fa.list("--labels", "--label"),
+		Ephemeral:   fa.has("--ephemeral"),
+	}
+	s.Issues = append(s.Issues, issue)
+	if parent != "" {
+		s.Deps = append(s.Deps, fakeDep{From: id, To: parent, Type: "parent-child"})
+	}
+	return f.print(fa, s.view(issue), "Created issue: "+id)
+}
+
+func (f *fakeBD) show(s *fakeStore, ids []string) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("usage: bd show <id>...")
+	}
+	var out []*beads.Issue
+	for _, id := range ids {
+		issue := s.find(id)
+		if issue == nil {
+			return fmt.Errorf("no issue found matching %q", id)
+		}
+		out = append(out, s.view(issue))
+	}
+	return f.printJSON(out)
+}
+
+func (f *fakeBD) list(s *fakeStore, cmd string, rest []string, fa fakeArgs) error {
+	statuses := fa.list("--status")
+	query := strings.ToLower(strings.Join(rest, " "))
+	limit := 50
+	if l := fa.get("--limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil {
+			return fmt.Errorf("invalid limit %q", l)
+		}
+		limit = n
+	}
+
+	var out []*beads.Issue
+	for _, issue := range s.Issues {
+		v := s.view(issue)
+		switch cmd {
+		case "ready":
+			if v.Status != "open" || len(v.BlockedBy) > 0 || v.Ephemeral {
+				continue
+			}
+			if mol := fa.get("--mol"); mol != "" && v.Parent != mol {
+				continue
+			}
+		case "blocked":
+			if v.Status == "closed" || len(v.BlockedBy) == 0 {
+				continue
+			}
+		case "search":
+			if query != "" && !strings.Contains(strings.ToLower(v.ID+" "+v.Title+" "+v.Description), query) {
+				continue
+			}
+		}
+		if !matchStatus(v.Status, statuses, cmd) {
+			continue
+		}
+		if !hasAllLabels(v, fa.list("--label")) {
+			continue
+		}
+		if t := fa.get("--type"); t != "" && v.Type != t && !beads.HasLabel(v, "gt:"+t) {
+			continue
+		}
+		if a := fa.get("--assignee"); a != "" && v.Assignee != a {
+			continue
+		}
+		if fa.has("--no-assignee") && v.Assignee != "" {
+			continue
+		}
+		if p := fa.get("--parent"); p != "" && v.Parent != p {
+			continue
+		}
+		if p := fa.get("--priority"); p != "" && strconv.Itoa(v.Priority) != p {
+			continue
+		}
+		if d := fa.get("--desc-contains"); d != "" && !strings.Contains(v.Description, d) {
+			continue
+		}
+		out = append(out, v)
+	}
+	if cmd == "ready" {
+		sort.SliceStable(out, func(i, j int) bool { return out[i].Priority < out[j].Priority })
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	if out == nil {
+		out = []*beads.Issue{}
+	}
+	if !fa.has("--json") && len(out) == 0 {
+		fmt.Fprintln(f.stdout, "No issues found.")
+		return nil
+	}
+	var lines []string
+	for _, issue := range out {
+		lines = append(lines, fmt.Sprintf("%s [%s] %s", issue.ID, issue.Status, issue.Title))
+	}
+	return f.print(fa, out, strings.Join(lines, "\n"))
+}
+
+// matchStatus applies a --status filter. Without one, list and search
+// leave closed issues out, as bd does.
+func matchStatus(status string, want []string, cmd string) bool {
+	if len(want) == 0 {
+		return cmd == "ready" || cmd == "blocked" || status != "closed"
+	}
+	for _, w := range want {
+		if w == "all" || w == status {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAllLabels(issue *beads.Issue, labels []string) bool {
+	for _, l := range labels {
+		if !beads.HasLabel(issue, l) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *fakeBD) update(s *fakeStore, ids []string, fa fakeArgs) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("usage: bd update <id>... [flags]")
+	}
+	var out []*beads.Issue
+	for _, id := range ids {
+		issue := s.find(id)
+		if issue == nil {
+			return fmt.Errorf("no issue found matching %q", id)
+		}
+		if fa.has("--title") {
+			issue.Title = fa.get("--title")
+		}
+		if fa.has("--status") {
+			issue.Status = fa.get("--status")
+		}
+		if fa.has("--description") {
+			issue.Description = fa.get("--description")
+		}
+		if fa.has("--assignee") {
+			issue.Assignee = fa.get("--assignee")
+		}
+		if fa.has("--parent") {
+			issue.Parent = fa.get("--parent")
+		}
+		if p := fa.get("--priority"); p != "" {
+			n, err := strconv.Atoi(strings.TrimPrefix(strings.ToUpper(p), "P"))
+			if err != nil {
+				return fmt.Errorf("invalid priority %q", p)
+			}
+			issue.Priority = n
+		}
+		if fa.has("--set-labels") {
+			issue.Labels = fa.list("--set-labels")
+		}
+		for _, l := range fa.list("--add-label") {
+			if !beads.HasLabel(issue, l) {
+				issue.Labels = append(issue.Labels, l)
+			}
+		}
+		issue.Labels = removeAll(issue.Labels, fa.list("--remove-label"))
+		issue.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+		out = append(out, s.view(issue))
+	}
+	return f.print(fa, out, "Updated "+strings.Join(ids, ", "))
+}
+
+func (f *fakeBD) setClosed(s *fakeStore, closed bool, ids []string, fa fakeArgs) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("usage: bd close <id>...")
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	var out []*beads.Issue
+	for _, id := range ids {
+		issue := s.find(id)
+		if issue == nil {
+			return fmt.Errorf("no issue found matching %q", id)
+		}
+		if closed {
+			issue.Status, issue.ClosedAt = "closed", now
+		} else {
+			issue.Status, issue.ClosedAt = "open", ""
+		}
+		issue.UpdatedAt = now
+		out = append(out, s.view(issue))
+	}
+	verb := "Closed"
+	if !closed {
+		verb = "Reopened"
+	}
+	return f.print(fa, out, verb+" "+strings.Join(ids, ", "))
+}
+
+func (f *fakeBD) label(s *fakeStore, add bool, rest []string) error {
+	if len(rest) < 2 {
+		return fmt.Errorf("usage: bd label add|remove <id> <label>")
+	}
+	issue := s.find(rest[0])
+	if issue == nil {
+		return fmt.Errorf("no issue found matching %q", rest[0])
+	}
+	for _, l := range rest[1:] {
+		if add && !beads.HasLabel(issue, l) {
+			issue.Labels = append(issue.Labels, l)
+		}
+	}
+	if !add {
+		issue.Labels = removeAll(issue.Labels, rest[1:])
+	}
+	return nil
+}
+
+func (f *fakeBD) dep(s *fakeStore, add bool, rest []string, fa fakeArgs) error {
+	if len(rest) != 2 {
+		return fmt.Errorf("usage: bd dep add|remove <issue> <depends-on>")
+	}
+	if s.find(rest[0]) == nil {
+		return fmt.Errorf("no issue found matching %q", rest[0])
+	}
+	kept := s.Deps[:0]
+	for _, d := range s.Deps {
+		if d.From != rest[0] || d.To != rest[1] {
+			kept = append(kept, d)
+		}
+	}
+	s.Deps = kept
+	if add {
+		depType := fa.get("--type")
+		if depType == "" {
+			depType = "blocks"
+		}
+		s.Deps = append(s.Deps, fakeDep{From: rest[0], To: rest[1], Type: depType})
+	}
+	return nil
+}
+
+func (f *fakeBD) depList(s *fakeStore, rest []string, fa fakeArgs) error {
+	issue, err := f.issue(s, rest)
+	if err != nil {
+		return err
+	}
+	deps := []beads.IssueDep{}
+	for _, d := range s.view(issue).Dependencies {
+		if t := fa.get("--type"); t == "" || d.DependencyType == t {
+			deps = append(deps, d)
+		}
+	}
+	var lines []string
+	for _, d := range deps {
+		lines = append(lines, d.ID+" ("+d.DependencyType+")")
+	}
+	return f.print(fa, deps, strings.Join(lines, "\n"))
+}
+
+func (f *fakeBD) issue(s *fakeStore, rest []string) (*beads.Issue, error) {
+	if len(rest) == 0 {
+		return nil, fmt.Errorf("issue ID required")
+	}
+	issue := s.find(rest[0])
+	if issue == nil {
+		return nil, fmt.Errorf("no issue found matching %q", rest[0])
+	}
+	return issue, nil
+}
+
+// print writes v as JSON under --json, text otherwise.
+func (f *fakeBD) print(fa fakeArgs, v any, text string) error {
+	if fa.has("--json") {
+		return f.printJSON(v)
+	}
+	if text != "" {
+		fmt.Fprintln(f.stdout, text)
+	}
+	return nil
+}
+
+func (f *fakeBD) printJSON(v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(f.stdout, string(data))
+	return err
+}
+
+func removeAll(list, drop []string) []string {
+	var out []string
+	for _, v := range list {
+		keep := true
+		for _, d := range drop {
+			if v == d {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// resolveBeadsDir picks the database: BEADS_DIR, else the nearest .beads
+// above the working directory, following redirects.
+func (f *fakeBD) resolveBeadsDir() string {
+	if f.beadsDir != "" {
+		return f.beadsDir
+	}
+	for dir := f.dir; ; dir = filepath.Dir(dir) {
+		if info, err := os.Stat(filepath.Join(dir, ".beads")); err == nil && info.IsDir() {
+			return beads.ResolveBeadsDir(dir)
+		}
+		if filepath.Dir(dir) == dir {
+			return filepath.Join(f.dir, ".beads")
+		}
+	}
+}
+
+// routeID returns the database an ID lives in: the current one when the
+// prefix matches, else the one the town's routes.jsonl names.
+func (f *fakeBD) routeID(current, id string) string {
+	prefix := beads.ExtractPrefix(id)
+	if prefix == "" || strings.TrimSuffix(prefix, "-") == configuredStorePrefix(current) {
+		return current
+	}
+	for dir := filepath.Dir(current); ; dir = filepath.Dir(dir) {
+		routes, _ := beads.LoadRoutes(filepath.Join(dir, ".beads"))
+		for _, r := range routes {
+			if r.Prefix == prefix {
+				return beads.ResolveBeadsDir(filepath.Join(dir, r.Path))
+			}
+		}
+		if filepath.Dir(dir) == dir {
+			return current
+		}
+	}
+}
+
+// configuredStorePrefix is the prefix of the database in beadsDir.
+func configuredStorePrefix(beadsDir string) string {
+	s, err := loadStore(beadsDir)
+	if err != nil {
+		return ""
+	}
+	return s.Prefix
+}
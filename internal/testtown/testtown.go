@@ -0,0 +1,248 @@
+// Package testtown builds throwaway Gas Town workspaces for tests, with a
+// fake bd and a scripted agent runtime standing in for the real binaries.
+//
+// Both fakes are the test binary itself: New puts symlinks named bd and
+// gt-fake-agent to it on PATH, and Main, called from the package's
+// TestMain, turns into the fake when the binary is started under one of
+// those names. Code under test runs bd as a subprocess exactly as it does
+// in production, so routing, redirects and argument building are exercised
+// without bd, Dolt or an agent CLI installed.
+//
+//	func TestMain(m *testing.M) { testtown.Main(m) }
+//
+//	func TestSomething(t *testing.T) {
+//		town := testtown.New(t)
+//		rig := town.AddRig("gastown", "gt")
+//		id := town.CreateBead(rig.Path, "Fix the widget")
+//		...
+//	}
+package testtown
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Names the test binary answers to.
+const (
+	BDName    = "bd"
+	AgentName = "gt-fake-agent"
+)
+
+// mainCalled is set by Main. Without it the bd symlink would start the
+// test binary's tests instead of the fake.
+var mainCalled bool
+
+// Main runs the tests, or a fake when the binary was started as one.
+// Call it from TestMain in any package that uses New.
+func Main(m *testing.M) {
+	RunFakes()
+	os.Exit(m.Run())
+}
+
+// RunFakes runs a fake and exits when the binary was started as one, and
+// returns otherwise. It is Main for a TestMain that has setup of its own;
+// call it first thing.
+func RunFakes() {
+	mainCalled = true
+	switch filepath.Base(os.Args[0]) {
+	case BDName:
+		wd, _ := os.Getwd()
+		os.Exit(runFakeBD(&fakeBD{
+			dir:      wd,
+			beadsDir: os.Getenv("BEADS_DIR"),
+			actor:    os.Getenv("BD_ACTOR"),
+			stdout:   os.Stdout,
+			stderr:   os.Stderr,
+		}, os.Args[1:]))
+	case AgentName:
+		os.Exit(runAgent(os.Args[1:]))
+	}
+}
+
+// Town is a test workspace.
+type Town struct {
+	Root   string // Town root (contains mayor/town.json)
+	BinDir string // Directory on PATH holding the fakes
+
+	t    testing.TB
+	rigs map[string]*Rig
+}
+
+// Rig is a rig inside a test town.
+type Rig struct {
+	Name   string
+	Prefix string // Bead ID prefix, without the dash
+	Path   string // <town>/<rig>
+
+	town *Town
+}
+
+// New creates a town with an hq beads database and puts the fakes first
+// on PATH for the rest of the test. BEADS_DIR and BD_ACTOR are cleared
+// so the host's beads can't leak in.
+func New(t testing.TB) *Town {
+	t.Helper()
+	if !mainCalled {
+		t.Fatal("testtown: TestMain must call testtown.Main(m) or testtown.RunFakes()")
+	}
+
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("testtown: resolving temp dir: %v", err)
+	}
+	town := &Town{Root: root, BinDir: filepath.Join(root, ".testtown", "bin"), t: t, rigs: make(map[string]*Rig)}
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("testtown: locating test binary: %v", err)
+	}
+	town.mkdir(town.BinDir)
+	for _, name := range []string{BDName, AgentName} {
+		if err := os.Symlink(exe, filepath.Join(town.BinDir, name)); err != nil {
+			t.Fatalf("testtown: linking %s: %v", name, err)
+		}
+	}
+	t.Setenv("PATH", town.BinDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("BEADS_DIR", "")
+	t.Setenv("BD_ACTOR", "")
+
+	townConfig := &config.TownConfig{Type: "town", Version: config.CurrentTownVersion, Name: "test", CreatedAt: time.Now()}
+	if err := config.SaveTownConfig(filepath.Join(root, "mayor", "town.json"), townConfig); err != nil {
+		t.Fatalf("testtown: %v", err)
+	}
+	rigsConfig := &config.RigsConfig{Version: config.CurrentRigsVersion, Rigs: make(map[string]config.RigEntry)}
+	if err := config.SaveRigsConfig(filepath.Join(root, "mayor", "rigs.json"), rigsConfig); err != nil {
+		t.Fatalf("testtown: %v", err)
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(root), config.NewTownSettings()); err != nil {
+		t.Fatalf("testtown: %v", err)
+	}
+
+	townBeads := filepath.Join(root, ".beads")
+	town.write(filepath.Join(townBeads, "config.yaml"), "prefix: hq\n")
+	if err := beads.WriteRoutes(townBeads, []beads.Route{{Prefix: "hq-", Path: "."}}); err != nil {
+		t.Fatalf("testtown: %v", err)
+	}
+	return town
+}
+
+// AddRig adds a rig with its own beads database under mayor/rig, registers
+// it in rigs.json and routes its prefix.
+func (town *Town) AddRig(name, prefix string) *Rig {
+	town.t.Helper()
+	rig := &Rig{Name: name, Prefix: prefix, Path: filepath.Join(town.Root, name), town: town}
+	town.write(filepath.Join(rig.Path, "mayor", "rig", ".beads", "config.yaml"), "prefix: "+prefix+"\n")
+	town.write(filepath.Join(rig.Path, ".beads", "redirect"), "mayor/rig/.beads\n")
+	town.mkdir(filepath.Join(rig.Path, "polecats"))
+	town.mkdir(filepath.Join(rig.Path, "crew"))
+
+	rigsPath := filepath.Join(town.Root, "mayor", "rigs.json")
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		town.t.Fatalf("testtown: %v", err)
+	}
+	rigsConfig.Rigs[name] = config.RigEntry{
+		GitURL:      "file:///dev/null/" + name,
+		AddedAt:     time.Now(),
+		BeadsConfig: &config.BeadsConfig{Repo: "local", Prefix: prefix},
+	}
+	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+		town.t.Fatalf("testtown: %v", err)
+	}
+	if err := beads.AppendRoute(town.Root, beads.Route{Prefix: prefix + "-", Path: name + "/mayor/rig"}); err != nil {
+		town.t.Fatalf("testtown: %v", err)
+	}
+	town.rigs[name] = rig
+	return rig
+}
+
+// Rig returns a rig added with AddRig.
+func (town *Town) Rig(name string) *Rig {
+	town.t.Helper()
+	rig, ok := town.rigs[name]
+	if !ok {
+		town.t.Fatalf("testtown: no rig %q", name)
+	}
+	return rig
+}
+
+// BeadsDir is the rig's beads database.
+func (r *Rig) BeadsDir() string {
+	return filepath.Join(r.Path, "mayor", "rig", ".beads")
+}
+
+// AddPolecat creates a polecat worktree directory whose .beads redirects
+// to the rig's database, and returns its path.
+func (r *Rig) AddPolecat(name string) string {
+	return r.addWorker("polecats", name)
+}
+
+// AddCrew creates a crew workspace whose .beads redirects to the rig's
+// database, and returns its path.
+func (r *Rig) AddCrew(name string) string {
+	return r.addWorker("crew", name)
+}
+
+func (r *Rig) addWorker(kind, name string) string {
+	r.town.t.Helper()
+	dir := filepath.Join(r.Path, kind, name)
+	r.town.write(filepath.Join(dir, ".beads", "redirect"), "../../mayor/rig/.beads\n")
+	return dir
+}
+
+// Chdir changes into dir for the rest of the test.
+func (town *Town) Chdir(dir string) {
+	town.t.Helper()
+	town.t.Chdir(dir)
+}
+
+// BD runs the fake bd in-process from dir, for arranging and inspecting
+// state. The test fails if the command does.
+func (town *Town) BD(dir string, args ...string) string {
+	town.t.Helper()
+	var stdout, stderr bytes.Buffer
+	f := &fakeBD{dir: dir, stdout: &stdout, stderr: &stderr}
+	if code := runFakeBD(f, args); code != 0 {
+		town.t.Fatalf("testtown: bd %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String()
+}
+
+// CreateBead creates a bead in the database dir resolves to and returns
+// its ID.
+func (town *Town) CreateBead(dir, title string, labels ...string) string {
+	town.t.Helper()
+	args := []string{"create", "--title=" + title}
+	if len(labels) > 0 {
+		args = append(args, "--labels="+strings.Join(labels, ","))
+	}
+	out := strings.TrimSpace(town.BD(dir, args...))
+	id, ok := strings.CutPrefix(out, "Created issue: ")
+	if !ok {
+		town.t.Fatalf("testtown: unexpected bd create output %q", out)
+	}
+	return id
+}
+
+func (town *Town) mkdir(dir string) {
+	town.t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		town.t.Fatalf("testtown: %v", err)
+	}
+}
+
+func (town *Town) write(path, content string) {
+	town.t.Helper()
+	town.mkdir(filepath.Dir(path))
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		town.t.Fatalf("testtown: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package testtown
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestMain(m *testing.M) { Main(m) }
+
+func TestFakeBD_BeadsRoundTrip(t *testing.T) {
+	town := New(t)
+	rig := town.AddRig("gastown", "gt")
+	b := beads.New(rig.Path)
+
+	issue, err := b.Create(beads.CreateOptions{Title: "Fix the widget", Labels: []string{"gt:task"}, Priority: 1, Actor: "mayor"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if !strings.HasPrefix(issue.ID, "gt-") || issue.Status != "open" || issue.CreatedBy != "mayor" {
+		t.Fatalf("created issue = %+v", issue)
+	}
+
+	status, assignee := "in_progress", "gastown/polecats/toast"
+	if err := b.Update(issue.ID, beads.UpdateOptions{Status: &status, Assignee: &assignee, AddLabels: []string{"urgent"}}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	got, err := b.Show(issue.ID)
+	if err != nil {
+		t.Fatalf("Show: %v", err)
+	}
+	if got.Status != status || got.Assignee != assignee || !beads.HasLabel(got, "urgent") || got.Priority != 1 {
+		t.Errorf("after update = %+v", got)
+	}
+
+	open, err := b.List(beads.ListOptions{Status: "in_progress", Label: "gt:task", Priority: -1})
+	if err != nil || len(open) != 1 || open[0].ID != issue.ID {
+		t.Fatalf("List in_progress = %v, %v", open, err)
+	}
+
+	if err := b.CloseWithReason("done", issue.ID); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if remaining, _ := b.List(beads.ListOptions{Priority: -1}); len(remaining) != 0 {
+		t.Errorf("default list should leave closed issues out, got %d", len(remaining))
+	}
+	if got, _ := b.Show(issue.ID); got.Status != "closed" || got.ClosedAt == "" {
+		t.Errorf("after close = %+v", got)
+	}
+}
+
+func TestFakeBD_NotFound(t *testing.T) {
+	town := New(t)
+	rig := town.AddRig("gastown", "gt")
+
+	_, err := beads.New(rig.Path).Show("gt-zzz")
+	if !errors.Is(err, beads.ErrNotFound) {
+		t.Errorf("Show missing = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFakeBD_Routing(t *testing.T) {
+	town := New(t)
+	gastown := town.AddRig("gastown", "gt")
+	town.AddRig("beads", "bd")
+
+	// A polecat's redirect lands its beads in the rig database.
+	polecat := gastown.AddPolecat("toast")
+	issue, err := beads.New(polecat).Create(beads.CreateOptions{Title: "From a polecat", Priority: -1})
+	if err != nil {
+		t.Fatalf("Create from polecat: %v", err)
+	}
+	if !strings.HasPrefix(issue.ID, "gt-") {
+		t.Errorf("polecat bead ID = %s, want gt- prefix", issue.ID)
+	}
+	if _, err := os.Stat(filepath.Join(gastown.BeadsDir(), storeFile)); err != nil {
+		t.Errorf("rig database not written: %v", err)
+	}
+
+	// gt routes rig IDs from the town root through routes.jsonl.
+	hq := town.CreateBead(town.Root, "Town work")
+	townBeads := beads.New(town.Root)
+	for _, id := range []string{hq, issue.ID} {
+		got, err := townBeads.Show(id)
+		if err != nil || got.ID != id {
+			t.Errorf("Show(%s) from town root = %v, %v", id, got, err)
+		}
+	}
+
+	// So does bd itself when run without BEADS_DIR.
+	cmd := exec.Command("bd", "show", issue.ID, "--json")
+	cmd.Dir = town.Root
+	out, err := cmd.CombinedOutput()
+	if err != nil || !strings.Contains(string(out), "From a polecat") {
+		t.Errorf("bd show via prefix routing = %s, %v", out, err)
+	}
+}
+
+func TestFakeBD_ReadyAndDeps(t *testing.T) {
+	town := New(t)
+	rig := town.AddRig("gastown", "gt")
+	b := beads.New(rig.Path)
+
+	blocker := town.CreateBead(rig.Path, "Blocker")
+	blocked := town.CreateBead(rig.Path, "Blocked")
+	if err := b.AddDependency(blocked, blocker); err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+
+	ready, err := b.Ready()
+	if err != nil {
+		t.Fatalf("Ready: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != blocker {
+		t.Fatalf("ready = %v, want only %s", ready, blocker)
+	}
+
+	if err := b.Close(blocker); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	ready, _ = b.Ready()
+	if len(ready) != 1 || ready[0].ID != blocked {
+		t.Errorf("ready after closing blocker = %v, want %s", ready, blocked)
+	}
+}
+
+func TestFakeBD_UnsupportedCommandFails(t *testing.T) {
+	town := New(t)
+	cmd := exec.Command("bd", "mol", "squash", "hq-001")
+	cmd.Dir = town.Root
+	out, err := cmd.CombinedOutput()
+	if err == nil || !strings.Contains(string(out), "unsupported command") {
+		t.Errorf("bd mol squash = %s, %v; want an unsupported command error", out, err)
+	}
+}
+
+func TestAgent_PlaysScript(t *testing.T) {
+	town := New(t)
+	rig := town.AddRig("gastown", "gt")
+	bead := town.CreateBead(rig.Path, "Scripted work")
+
+	logPath := town.Agent("scripted",
+		Say("picking up $BEAD"),
+		Run("bd", "close", "$BEAD"),
+		Exit(3),
+		Say("never reached"),
+	)
+
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(town.Root))
+	if err != nil {
+		t.Fatalf("loading settings: %v", err)
+	}
+	rc := settings.Agents["scripted"]
+	if rc == nil {
+		t.Fatal("agent not registered in town settings")
+	}
+
+	cmd := exec.Command(rc.Command, append(rc.Args, "--prompt", "go")...)
+	cmd.Dir = rig.Path
+	cmd.Env = append(os.Environ(), "BEAD="+bead)
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Fatalf("agent exit = %v, want status 3", err)
+	}
+	if strings.Contains(string(out), "never reached") {
+		t.Errorf("steps after exit ran: %s", out)
+	}
+
+	got, err := beads.New(rig.Path).Show(bead)
+	if err != nil || got.Status != "closed" {
+		t.Errorf("bead after agent run = %v, %v; want closed", got, err)
+	}
+
+	log := town.AgentLog(logPath)
+	if len(log) != 4 || !strings.Contains(log[0], `args=["--prompt" "go"]`) || log[2] != "run bd close "+bead+": ok" || log[3] != "exit 3" {
+		t.Errorf("agent log = %q", log)
+	}
+}
@@ -0,0 +1,269 @@
+// Package timetrack records agent session activity and turns it into
+// active-work time per agent and bead.
+//
+// Active time comes from activity signals, not wall clock. On every
+// heartbeat the daemon reads each agent session's last tmux activity and
+// records a Signal when it has moved. Two consecutive signals from an agent
+// no further apart than the idle gap count the time between them as active;
+// a longer gap is idle and counts for nothing. Active time is attributed to
+// the bead the agent was hooked to at the time, as the event log tells it
+// (sling, hook, unhook, done, scheduler_dispatch).
+//
+// Signals are appended to one file per month under .runtime/timetrack/, so
+// old months can be archived or deleted without touching current data.
+package timetrack
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// DefaultIdleGap is the longest gap between two signals that still counts
+// as active time. It is a few daemon heartbeats (3m by default), so an agent
+// busy across consecutive samples is counted throughout.
+const DefaultIdleGap = 10 * time.Minute
+
+// Signal is one observation of activity in an agent's session.
+type Signal struct {
+	Agent string    `json:"agent"` // Agent address, e.g. gastown/polecats/Toast
+	At    time.Time `json:"at"`    // When the session was last active
+}
+
+// Dir returns the directory holding activity signals.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "timetrack")
+}
+
+func monthPath(townRoot string, t time.Time) string {
+	return filepath.Join(Dir(townRoot), "activity-"+t.UTC().Format("2006-01")+".jsonl")
+}
+
+// Record appends signals to the activity log.
+func Record(townRoot string, signals []Signal) error {
+	if len(signals) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return err
+	}
+	lock := flock.New(filepath.Join(Dir(townRoot), ".lock"))
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking activity log: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	byMonth := map[string][]Signal{}
+	for _, s := range signals {
+		path := monthPath(townRoot, s.At)
+		byMonth[path] = append(byMonth[path], s)
+	}
+	for path, batch := range byMonth {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(f)
+		for _, s := range batch {
+			if err := enc.Encode(s); err != nil {
+				_ = f.Close()
+				return err
+			}
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load returns the signals recorded at or after since, oldest first.
+// Malformed lines are skipped.
+func Load(townRoot string, since time.Time) ([]Signal, error) {
+	paths, err := filepath.Glob(filepath.Join(Dir(townRoot), "activity-*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	firstMonth := ""
+	if !since.IsZero() {
+		firstMonth = "activity-" + since.UTC().Format("2006-01") + ".jsonl"
+	}
+
+	var signals []Signal
+	for _, path := range paths {
+		if filepath.Base(path) < firstMonth {
+			continue
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var s Signal
+			if err := json.Unmarshal(scanner.Bytes(), &s); err != nil || s.Agent == "" {
+				continue
+			}
+			if s.At.Before(since) {
+				continue
+			}
+			signals = append(signals, s)
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+	sort.SliceStable(signals, func(i, j int) bool { return signals[i].At.Before(signals[j].At) })
+	return signals, nil
+}
+
+// Span is a period an agent held a bead. A zero To means it still does.
+type Span struct {
+	Agent string
+	Bead  string
+	From  time.Time
+	To    time.Time
+}
+
+func (s Span) covers(t time.Time) bool {
+	return !t.Before(s.From) && (s.To.IsZero() || t.Before(s.To))
+}
+
+// Assignments folds the event log, oldest first, into the spans each bead
+// was hooked to an agent. A bead slung or hooked to a new agent ends its
+// previous span.
+func Assignments(evs []events.Event) []Span {
+	var spans []Span
+	open := map[string]int{} // bead -> index of its open span
+	end := func(bead string, at time.Time) {
+		if i, ok := open[bead]; ok {
+			spans[i].To = at
+			delete(open, bead)
+		}
+	}
+	start := func(bead, agent string, at time.Time) {
+		end(bead, at)
+		if agent = NormalizeAgent(agent); agent == "" {
+			return
+		}
+		open[bead] = len(spans)
+		spans = append(spans, Span{Agent: agent, Bead: bead, From: at})
+	}
+
+	for _, e := range evs {
+		at, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			continue
+		}
+		bead, _ := e.Payload["bead"].(string)
+		if bead == "" {
+			continue
+		}
+		switch e.Type {
+		case events.TypeSling:
+			target, _ := e.Payload["target"].(string)
+			start(bead, target, at)
+		case events.TypeHook:
+			start(bead, e.Actor, at)
+		case events.TypeSchedulerDispatch:
+			rig, _ := e.Payload["rig"].(string)
+			if polecat, _ := e.Payload["polecat"].(string); rig != "" && polecat != "" {
+				start(bead, rig+"/polecats/"+polecat, at)
+			}
+		case events.TypeUnhook, events.TypeDone:
+			end(bead, at)
+		}
+	}
+	return spans
+}
+
+// NormalizeAgent strips the trailing slash of town agent addresses
+// ("mayor/" is recorded as "mayor").
+func NormalizeAgent(agent string) string {
+	return strings.TrimSuffix(strings.TrimSpace(agent), "/")
+}
+
+// RigOf returns the rig an agent address belongs to, or "" for town-level
+// agents (mayor, deacon and its dogs).
+func RigOf(agent string) string {
+	parts := strings.Split(agent, "/")
+	if len(parts) < 2 || parts[0] == "mayor" || parts[0] == "deacon" {
+		return ""
+	}
+	return parts[0]
+}
+
+// Entry is the active time an agent spent on one bead. Bead is "" for
+// active time while the agent had nothing hooked.
+type Entry struct {
+	Agent  string        `json:"agent"`
+	Rig    string        `json:"rig,omitempty"`
+	Bead   string        `json:"bead,omitempty"`
+	Active time.Duration `json:"active_ns"`
+}
+
+// Compute turns signals into active time per agent and bead. The interval
+// between two consecutive signals is attributed to the bead the agent held
+// at the start of it. Entries are sorted by agent, then bead.
+func Compute(signals []Signal, spans []Span, idleGap time.Duration) []Entry {
+	if idleGap <= 0 {
+		idleGap = DefaultIdleGap
+	}
+	byAgent := map[string][]time.Time{}
+	for _, s := range signals {
+		agent := NormalizeAgent(s.Agent)
+		byAgent[agent] = append(byAgent[agent], s.At)
+	}
+	spansByAgent := map[string][]Span{}
+	for _, sp := range spans {
+		spansByAgent[sp.Agent] = append(spansByAgent[sp.Agent], sp)
+	}
+
+	type key struct{ agent, bead string }
+	totals := map[key]time.Duration{}
+	for agent, times := range byAgent {
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+		for i := 1; i < len(times); i++ {
+			gap := times[i].Sub(times[i-1])
+			if gap <= 0 || gap > idleGap {
+				continue
+			}
+			totals[key{agent, beadAt(spansByAgent[agent], times[i-1])}] += gap
+		}
+	}
+
+	entries := make([]Entry, 0, len(totals))
+	for k, d := range totals {
+		entries = append(entries, Entry{Agent: k.agent, Rig: RigOf(k.agent), Bead: k.bead, Active: d})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Agent != entries[j].Agent {
+			return entries[i].Agent < entries[j].Agent
+		}
+		return entries[i].Bead < entries[j].Bead
+	})
+	return entries
+}
+
+// beadAt returns the bead an agent held at t. When spans overlap, the most
+// recently hooked bead wins.
+func beadAt(spans []Span, t time.Time) string {
+	bead := ""
+	var from time.Time
+	for _, sp := range spans {
+		if sp.covers(t) && !sp.From.Before(from) {
+			bead, from = sp.Bead, sp.From
+		}
+	}
+	return bead
+}
@@ -0,0 +1,96 @@
+package timetrack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func TestRecordAndLoad(t *testing.T) {
+	town := t.TempDir()
+	sep := time.Date(2026, 9, 30, 23, 58, 0, 0, time.UTC)
+	oct := time.Date(2026, 10, 1, 0, 1, 0, 0, time.UTC)
+	if err := Record(town, []Signal{
+		{Agent: "gastown/polecats/Toast", At: oct},
+		{Agent: "gastown/polecats/Toast", At: sep},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Signals land in per-month files.
+	for _, month := range []string{"2026-09", "2026-10"} {
+		if _, err := os.Stat(filepath.Join(Dir(town), "activity-"+month+".jsonl")); err != nil {
+			t.Errorf("missing %s file: %v", month, err)
+		}
+	}
+
+	all, err := Load(town, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 || !all[0].At.Equal(sep) {
+		t.Errorf("all = %+v", all)
+	}
+	recent, err := Load(town, oct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 1 || !recent[0].At.Equal(oct) {
+		t.Errorf("recent = %+v", recent)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	t0 := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	at := func(min int) time.Time { return t0.Add(time.Duration(min) * time.Minute) }
+	ts := func(min int) string { return at(min).Format(time.RFC3339) }
+
+	evs := []events.Event{
+		{Timestamp: ts(0), Type: events.TypeSling, Payload: map[string]interface{}{"bead": "gt-1", "target": "gastown/polecats/Toast"}},
+		{Timestamp: ts(20), Type: events.TypeDone, Payload: map[string]interface{}{"bead": "gt-1"}},
+		{Timestamp: ts(30), Type: events.TypeHook, Actor: "mayor/", Payload: map[string]interface{}{"bead": "hq-2"}},
+	}
+	spans := Assignments(evs)
+	if len(spans) != 2 || spans[1].Agent != "mayor" || !spans[0].To.Equal(at(20)) {
+		t.Fatalf("spans = %+v", spans)
+	}
+
+	signals := []Signal{
+		// Toast: 0-9 active on gt-1, 9-30 idle, 30-33 active after done.
+		{Agent: "gastown/polecats/Toast", At: at(0)},
+		{Agent: "gastown/polecats/Toast", At: at(3)},
+		{Agent: "gastown/polecats/Toast", At: at(9)},
+		{Agent: "gastown/polecats/Toast", At: at(30)},
+		{Agent: "gastown/polecats/Toast", At: at(33)},
+		{Agent: "mayor/", At: at(31)},
+		{Agent: "mayor/", At: at(35)},
+	}
+	entries := Compute(signals, spans, 10*time.Minute)
+	want := []Entry{
+		{Agent: "gastown/polecats/Toast", Rig: "gastown", Bead: "", Active: 3 * time.Minute},
+		{Agent: "gastown/polecats/Toast", Rig: "gastown", Bead: "gt-1", Active: 9 * time.Minute},
+		{Agent: "mayor", Bead: "hq-2", Active: 4 * time.Minute},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("entries = %+v", entries)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestAssignmentsReassign(t *testing.T) {
+	t0 := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	evs := []events.Event{
+		{Timestamp: t0.Format(time.RFC3339), Type: events.TypeSling, Payload: map[string]interface{}{"bead": "gt-1", "target": "gastown/polecats/Toast"}},
+		{Timestamp: t0.Add(time.Hour).Format(time.RFC3339), Type: events.TypeSchedulerDispatch, Payload: map[string]interface{}{"bead": "gt-1", "rig": "gastown", "polecat": "Nux"}},
+	}
+	spans := Assignments(evs)
+	if len(spans) != 2 || !spans[0].To.Equal(t0.Add(time.Hour)) || spans[1].Agent != "gastown/polecats/Nux" || !spans[1].To.IsZero() {
+		t.Errorf("spans = %+v", spans)
+	}
+}
@@ -0,0 +1,179 @@
+// Package townlock implements the advisory town lock: an operator takes the
+// town for a risky maintenance window (a migration, route surgery) and
+// other operators' mutating gt commands warn or refuse, per the town's
+// town_lock policy, until the lock is released or expires.
+//
+// Example (settings/config.json):
+//
+//	"town_lock": {"policy": "warn"}
+package townlock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultFor is how long a lock lasts when no expiry is given.
+const DefaultFor = time.Hour
+
+// EnvIgnore runs one command despite another operator's lock (with a
+// warning).
+const EnvIgnore = "GT_IGNORE_TOWN_LOCK"
+
+// Policies for other operators' mutating commands while the town is locked.
+const (
+	PolicyBlock = "block" // Refuse them (default)
+	PolicyWarn  = "warn"  // Run them after a warning
+)
+
+// Config is the town_lock section of town settings.
+type Config struct {
+	// Policy is what happens to other operators' mutating commands while
+	// the town is locked: "block" (default) or "warn".
+	Policy string `json:"policy,omitempty"`
+}
+
+// GetPolicy returns Policy, or PolicyBlock if unset.
+func (c *Config) GetPolicy() string {
+	if c == nil || c.Policy == "" {
+		return PolicyBlock
+	}
+	return c.Policy
+}
+
+// Validate checks the config. A nil config is valid (block).
+func (c *Config) Validate() error {
+	if c == nil || c.Policy == "" || c.Policy == PolicyBlock || c.Policy == PolicyWarn {
+		return nil
+	}
+	return fmt.Errorf("invalid policy %q (want %s or %s)", c.Policy, PolicyBlock, PolicyWarn)
+}
+
+// ErrHeld is returned when another operator holds the town lock.
+var ErrHeld = errors.New("town is locked")
+
+// HeldError reports the lock that stopped an operation.
+type HeldError struct {
+	Lock *Lock
+}
+
+func (e *HeldError) Error() string {
+	return fmt.Sprintf("%v by %s: %s (until %s)", ErrHeld, e.Lock.Owner, e.Lock.Reason,
+		e.Lock.Expires.Local().Format("2006-01-02 15:04"))
+}
+
+func (e *HeldError) Unwrap() error { return ErrHeld }
+
+// Lock is the town lock.
+type Lock struct {
+	Owner   string    `json:"owner"`
+	Reason  string    `json:"reason"`
+	Since   time.Time `json:"since"`
+	Expires time.Time `json:"expires"`
+}
+
+// Active reports whether the lock is in force at now.
+func (l *Lock) Active(now time.Time) bool {
+	return l != nil && now.Before(l.Expires)
+}
+
+// Path returns the lock file.
+func Path(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "town-lock.json")
+}
+
+// Load returns the recorded lock, expired or not, or nil if there is none.
+func Load(townRoot string) (*Lock, error) {
+	data, err := os.ReadFile(Path(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	l := &Lock{}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("parsing town lock: %w", err)
+	}
+	return l, nil
+}
+
+// Acquire takes the lock for owner until expires. It fails with a
+// *HeldError while another owner holds an active lock; the owner's own lock
+// is renewed with the new reason and expiry. The lock file is created
+// exclusively, so two operators locking at once can't both win.
+func Acquire(townRoot, owner, reason string, expires, now time.Time) (*Lock, error) {
+	l := &Lock{Owner: owner, Reason: reason, Since: now, Expires: expires}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	path := Path(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644) //nolint:gosec // G302: runtime state
+	if err == nil {
+		_, err = f.Write(data)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return l, err
+	}
+	if !os.IsExist(err) {
+		return nil, err
+	}
+
+	held, err := Load(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	if held.Active(now) && held.Owner != owner {
+		return nil, &HeldError{Lock: held}
+	}
+	if held != nil && held.Owner == owner && held.Active(now) {
+		l.Since = held.Since
+		if data, err = json.MarshalIndent(l, "", "  "); err != nil {
+			return nil, err
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: runtime state
+		return nil, err
+	}
+	return l, os.Rename(tmp, path)
+}
+
+// Release drops the lock and returns it, or nil when the town wasn't
+// locked. Another owner's active lock is only released with force.
+func Release(townRoot, owner string, force bool, now time.Time) (*Lock, error) {
+	held, err := Load(townRoot)
+	if err != nil && !force {
+		return nil, err
+	}
+	if held.Active(now) && held.Owner != owner && !force {
+		return nil, &HeldError{Lock: held}
+	}
+	if err := os.Remove(Path(townRoot)); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return held, nil
+}
+
+// Check returns the lock if it is in force at now and held by someone
+// other than operator. An unreadable lock file counts as held: the lock
+// guards against concurrent maintenance, so it fails closed.
+func Check(townRoot, operator string, now time.Time) *Lock {
+	l, err := Load(townRoot)
+	if err != nil {
+		return &Lock{Owner: "unknown", Reason: fmt.Sprintf("town lock unreadable: %v", err), Expires: now.Add(DefaultFor)}
+	}
+	if !l.Active(now) || l.Owner == operator {
+		return nil
+	}
+	return l
+}
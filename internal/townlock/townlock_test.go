@@ -0,0 +1,91 @@
+package townlock
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestConfig(t *testing.T) {
+	var nilCfg *Config
+	if nilCfg.GetPolicy() != PolicyBlock || nilCfg.Validate() != nil {
+		t.Error("nil config should block and be valid")
+	}
+	if (&Config{Policy: PolicyWarn}).GetPolicy() != PolicyWarn {
+		t.Error("warn policy lost")
+	}
+	if (&Config{Policy: "ignore"}).Validate() == nil {
+		t.Error("unknown policy should be invalid")
+	}
+}
+
+func TestAcquireRelease(t *testing.T) {
+	town := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	l, err := Acquire(town, "dana", "route migration", now.Add(time.Hour), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Owner != "dana" || !l.Active(now) {
+		t.Fatalf("lock = %+v", l)
+	}
+
+	_, err = Acquire(town, "sam", "also migrating", now.Add(time.Hour), now.Add(time.Minute))
+	var held *HeldError
+	if !errors.As(err, &held) || !errors.Is(err, ErrHeld) || held.Lock.Owner != "dana" {
+		t.Fatalf("second operator's Acquire = %v, want HeldError by dana", err)
+	}
+
+	// Renewing keeps the start time.
+	l, err = Acquire(town, "dana", "route migration, part 2", now.Add(2*time.Hour), now.Add(30*time.Minute))
+	if err != nil || !l.Since.Equal(now) || !l.Expires.Equal(now.Add(2*time.Hour)) {
+		t.Fatalf("renewed lock = %+v, %v", l, err)
+	}
+
+	if Check(town, "dana", now.Add(time.Minute)) != nil {
+		t.Error("the owner should not be held by their own lock")
+	}
+	if c := Check(town, "sam", now.Add(time.Minute)); c == nil || c.Reason != "route migration, part 2" {
+		t.Errorf("Check for another operator = %+v", c)
+	}
+
+	if _, err := Release(town, "sam", false, now.Add(time.Minute)); !errors.Is(err, ErrHeld) {
+		t.Errorf("Release by another operator = %v, want ErrHeld", err)
+	}
+	if l, err := Release(town, "sam", true, now.Add(time.Minute)); err != nil || l == nil || l.Owner != "dana" {
+		t.Errorf("forced Release = %+v, %v", l, err)
+	}
+	if l, err := Release(town, "dana", false, now); err != nil || l != nil {
+		t.Errorf("Release of unlocked town = %+v, %v", l, err)
+	}
+}
+
+func TestExpiredLock(t *testing.T) {
+	town := t.TempDir()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if _, err := Acquire(town, "dana", "migration", now.Add(time.Hour), now); err != nil {
+		t.Fatal(err)
+	}
+	later := now.Add(2 * time.Hour)
+	if Check(town, "sam", later) != nil {
+		t.Error("expired lock should not hold anyone")
+	}
+	if l, err := Acquire(town, "sam", "cleanup", later.Add(time.Hour), later); err != nil || l.Owner != "sam" {
+		t.Errorf("Acquire over expired lock = %+v, %v", l, err)
+	}
+}
+
+func TestCheckUnreadableFailsClosed(t *testing.T) {
+	town := t.TempDir()
+	if err := os.MkdirAll(town+"/.runtime", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(Path(town), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if Check(town, "sam", time.Now()) == nil {
+		t.Error("unreadable lock should count as held")
+	}
+}
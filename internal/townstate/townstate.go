@@ -0,0 +1,281 @@
+// Package townstate rebuilds critical town state from the event log, so
+// derived state can be checked against the log and repaired when the two
+// disagree.
+//
+// The event log (.events.jsonl) records every change to three pieces of
+// state that are otherwise only kept in derived files and bead fields:
+//
+//   - Assignments: which agent a bead is hooked to (sling, hook, unhook,
+//     done, scheduler_dispatch)
+//   - The scheduler queue: beads scheduled for deferred dispatch and not
+//     yet dispatched (scheduler_enqueue, scheduler_dispatch)
+//   - Rig freezes (freeze)
+//
+// Replay folds the log into a State. Compare checks it against what the
+// town holds now and reports each disagreement as a Drift. Where the log
+// says something, it wins. Where it says nothing (the change predates the
+// log, or KRC pruned its events), or where a fix would need a human's
+// judgement, the drift is only reported.
+package townstate
+
+import (
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Hook is the last assignment event for a bead.
+type Hook struct {
+	Bead   string    `json:"bead"`
+	Target string    `json:"target,omitempty"` // Agent address the bead is hooked to
+	Hooked bool      `json:"hooked"`           // false after unhook or done
+	Event  string    `json:"event"`
+	At     time.Time `json:"at"`
+}
+
+// Queued is the last scheduler event for a bead.
+type Queued struct {
+	Bead   string    `json:"bead"`
+	Rig    string    `json:"rig"`
+	Queued bool      `json:"queued"` // false once dispatched
+	At     time.Time `json:"at"`
+}
+
+// Freeze is the last freeze event for a rig.
+type Freeze struct {
+	Rig    string    `json:"rig"`
+	Frozen bool      `json:"frozen"`
+	Reason string    `json:"reason,omitempty"`
+	By     string    `json:"by,omitempty"`
+	From   time.Time `json:"from,omitempty"`
+	Until  time.Time `json:"until,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// State is town state as the event log tells it.
+type State struct {
+	Events  int               `json:"events"`
+	First   time.Time         `json:"first,omitempty"`
+	Hooks   map[string]Hook   `json:"hooks"`
+	Queue   map[string]Queued `json:"queue"`
+	Freezes map[string]Freeze `json:"freezes"`
+}
+
+// Replay folds events, oldest first, into a State.
+func Replay(evs []events.Event) *State {
+	s := &State{Hooks: map[string]Hook{}, Queue: map[string]Queued{}, Freezes: map[string]Freeze{}}
+	for _, e := range evs {
+		at, _ := time.Parse(time.RFC3339, e.Timestamp)
+		if s.Events == 0 || (!at.IsZero() && at.Before(s.First)) {
+			s.First = at
+		}
+		s.Events++
+
+		bead := str(e.Payload, "bead")
+		switch e.Type {
+		case events.TypeSling:
+			if bead != "" {
+				s.Hooks[bead] = Hook{Bead: bead, Target: str(e.Payload, "target"), Hooked: true, Event: e.Type, At: at}
+				delete(s.Queue, bead)
+			}
+		case events.TypeHook:
+			if bead != "" {
+				s.Hooks[bead] = Hook{Bead: bead, Target: e.Actor, Hooked: true, Event: e.Type, At: at}
+			}
+		case events.TypeUnhook, events.TypeDone:
+			if bead != "" {
+				s.Hooks[bead] = Hook{Bead: bead, Target: s.Hooks[bead].Target, Event: e.Type, At: at}
+			}
+		case events.TypeSchedulerEnqueue:
+			if bead != "" {
+				s.Queue[bead] = Queued{Bead: bead, Rig: str(e.Payload, "rig"), Queued: true, At: at}
+			}
+		case events.TypeSchedulerDispatch:
+			if bead != "" {
+				rig := str(e.Payload, "rig")
+				s.Queue[bead] = Queued{Bead: bead, Rig: rig, At: at}
+				if polecat := str(e.Payload, "polecat"); polecat != "" {
+					s.Hooks[bead] = Hook{Bead: bead, Target: rig + "/polecats/" + polecat, Hooked: true, Event: e.Type, At: at}
+				}
+			}
+		case events.TypeFreeze:
+			rig := str(e.Payload, "rig")
+			if rig == "" {
+				continue
+			}
+			f := Freeze{Rig: rig, At: at}
+			if frozen, _ := e.Payload["frozen"].(bool); frozen {
+				f.Frozen = true
+				f.Reason = str(e.Payload, "reason")
+				f.By = e.Actor
+				f.From, _ = time.Parse(time.RFC3339, str(e.Payload, "from"))
+				f.Until, _ = time.Parse(time.RFC3339, str(e.Payload, "until"))
+			}
+			s.Freezes[rig] = f
+		}
+	}
+	return s
+}
+
+// Bead is a bead's status and assignee as the beads database holds it.
+type Bead struct {
+	Status   string
+	Assignee string
+}
+
+// Observed is the town's current state, gathered by the caller.
+type Observed struct {
+	// Beads holds the status of every bead the log mentions. A bead that
+	// doesn't exist is absent.
+	Beads map[string]Bead
+
+	// Hooked maps every bead in hooked status to its assignee.
+	Hooked map[string]string
+
+	// Queued maps every bead with an open sling context to its rig.
+	Queued map[string]string
+
+	// Frozen holds the rigs with a freeze file and the freeze.
+	Frozen map[string]Freeze
+}
+
+// Drift kinds.
+const (
+	KindAssignment = "assignment"
+	KindQueue      = "queue"
+	KindFreeze     = "freeze"
+)
+
+// Repairs a drift can be fixed with.
+const (
+	RepairNone     = ""
+	RepairHook     = "hook"     // Hook the bead to Want
+	RepairEnqueue  = "enqueue"  // Open a sling context for the bead on Want's rig
+	RepairDequeue  = "dequeue"  // Close the bead's sling context
+	RepairFreeze   = "freeze"   // Write the freeze from the log
+	RepairUnfreeze = "unfreeze" // Remove the freeze file
+)
+
+// Drift is one disagreement between the log and the town.
+type Drift struct {
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"` // Bead ID or rig
+	Want    string `json:"want"`    // Per the event log
+	Have    string `json:"have"`    // Per the town
+	Repair  string `json:"repair,omitempty"`
+}
+
+// Repairable reports whether the log says enough to fix the drift.
+func (d Drift) Repairable() bool { return d.Repair != RepairNone }
+
+// Compare checks the town against the log at now. Closed beads are
+// finished work and never drift, and a freeze whose scheduled thaw has
+// passed may be gone without a thaw event.
+func Compare(want *State, have *Observed, now time.Time) []Drift {
+	var drifts []Drift
+
+	for _, id := range sortedKeys(want.Hooks) {
+		h := want.Hooks[id]
+		b, exists := have.Beads[id]
+		if !h.Hooked || !exists || b.Status == "closed" {
+			continue
+		}
+		switch {
+		case b.Assignee == h.Target && (b.Status == "hooked" || b.Status == "in_progress"):
+		case b.Assignee == h.Target:
+			drifts = append(drifts, Drift{Kind: KindAssignment, Subject: id, Want: "hooked to " + h.Target, Have: b.Status + ", assigned to " + h.Target, Repair: RepairHook})
+		case b.Assignee == "":
+			drifts = append(drifts, Drift{Kind: KindAssignment, Subject: id, Want: "hooked to " + h.Target, Have: b.Status + ", unassigned", Repair: RepairHook})
+		default:
+			drifts = append(drifts, Drift{Kind: KindAssignment, Subject: id, Want: "hooked to " + h.Target, Have: b.Status + ", assigned to " + b.Assignee, Repair: RepairHook})
+		}
+	}
+	for _, id := range sortedKeys(have.Hooked) {
+		h, known := want.Hooks[id]
+		switch {
+		case !known:
+			drifts = append(drifts, Drift{Kind: KindAssignment, Subject: id, Want: "no assignment events", Have: "hooked to " + have.Hooked[id]})
+		case !h.Hooked:
+			drifts = append(drifts, Drift{Kind: KindAssignment, Subject: id, Want: "released by " + h.Event + " at " + h.At.Format(time.RFC3339), Have: "hooked to " + have.Hooked[id]})
+		}
+	}
+
+	for _, id := range sortedKeys(want.Queue) {
+		q := want.Queue[id]
+		b, exists := have.Beads[id]
+		if !q.Queued || !exists || b.Status == "closed" {
+			continue
+		}
+		if _, ok := have.Queued[id]; !ok {
+			drifts = append(drifts, Drift{Kind: KindQueue, Subject: id, Want: "scheduled for " + q.Rig, Have: "no sling context", Repair: RepairEnqueue})
+		}
+	}
+	for _, id := range sortedKeys(have.Queued) {
+		q, known := want.Queue[id]
+		switch {
+		case !known:
+			if h, hooked := want.Hooks[id]; hooked && h.Hooked {
+				drifts = append(drifts, Drift{Kind: KindQueue, Subject: id, Want: "dispatched to " + h.Target, Have: "scheduled for " + have.Queued[id], Repair: RepairDequeue})
+			} else {
+				drifts = append(drifts, Drift{Kind: KindQueue, Subject: id, Want: "no scheduler events", Have: "scheduled for " + have.Queued[id]})
+			}
+		case !q.Queued:
+			drifts = append(drifts, Drift{Kind: KindQueue, Subject: id, Want: "dispatched at " + q.At.Format(time.RFC3339), Have: "scheduled for " + have.Queued[id], Repair: RepairDequeue})
+		}
+	}
+
+	rigs := map[string]bool{}
+	for rig := range want.Freezes {
+		rigs[rig] = true
+	}
+	for rig := range have.Frozen {
+		rigs[rig] = true
+	}
+	for _, rig := range sortedKeys(rigs) {
+		f, known := want.Freezes[rig]
+		cur, frozen := have.Frozen[rig]
+		switch {
+		case !known:
+			drifts = append(drifts, Drift{Kind: KindFreeze, Subject: rig, Want: "no freeze events", Have: "frozen"})
+		case f.Frozen && !frozen && !f.Until.IsZero() && !now.Before(f.Until):
+		case f.Frozen && !frozen:
+			drifts = append(drifts, Drift{Kind: KindFreeze, Subject: rig, Want: "frozen", Have: "not frozen", Repair: RepairFreeze})
+		case !f.Frozen && frozen:
+			drifts = append(drifts, Drift{Kind: KindFreeze, Subject: rig, Want: "thawed at " + f.At.Format(time.RFC3339), Have: "frozen", Repair: RepairUnfreeze})
+		case f.Frozen && (!sameSecond(cur.From, f.From) || !sameSecond(cur.Until, f.Until) || cur.Reason != f.Reason):
+			drifts = append(drifts, Drift{Kind: KindFreeze, Subject: rig, Want: describeFreeze(f), Have: describeFreeze(cur), Repair: RepairFreeze})
+		}
+	}
+	return drifts
+}
+
+func describeFreeze(f Freeze) string {
+	s := "frozen from " + f.From.UTC().Format(time.RFC3339)
+	if !f.Until.IsZero() {
+		s += " until " + f.Until.UTC().Format(time.RFC3339)
+	}
+	if f.Reason != "" {
+		s += " (" + f.Reason + ")"
+	}
+	return s
+}
+
+// sameSecond compares times at the log's one-second resolution.
+func sameSecond(a, b time.Time) bool {
+	return a.Truncate(time.Second).Equal(b.Truncate(time.Second))
+}
+
+func str(payload map[string]interface{}, key string) string {
+	v, _ := payload[key].(string)
+	return v
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
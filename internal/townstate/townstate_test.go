@@ -0,0 +1,118 @@
+package townstate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+func ev(ts, typ, actor string, payload map[string]interface{}) events.Event {
+	return events.Event{Timestamp: ts, Type: typ, Actor: actor, Payload: payload}
+}
+
+func TestReplay(t *testing.T) {
+	s := Replay([]events.Event{
+		ev("2026-01-02T10:00:00Z", events.TypeSchedulerEnqueue, "mayor", events.SchedulerEnqueuePayload("gt-q", "gastown")),
+		ev("2026-01-02T10:01:00Z", events.TypeSling, "mayor", events.SlingPayload("gt-a", "gastown/polecats/Toast")),
+		ev("2026-01-02T10:02:00Z", events.TypeHook, "gastown/crew/max", events.HookPayload("gt-b")),
+		ev("2026-01-02T10:03:00Z", events.TypeDone, "gastown/crew/max", events.DonePayload("gt-b", "feat")),
+		ev("2026-01-02T10:04:00Z", events.TypeSchedulerEnqueue, "mayor", events.SchedulerEnqueuePayload("gt-c", "gastown")),
+		ev("2026-01-02T10:05:00Z", events.TypeSchedulerDispatch, "daemon", events.SchedulerDispatchPayload("gt-c", "gastown", "Nux")),
+		ev("2026-01-02T10:06:00Z", events.TypeFreeze, "overseer", events.FreezePayload("beads", true, "release", time.Date(2026, 1, 2, 10, 6, 0, 0, time.UTC), time.Time{})),
+		ev("2026-01-02T10:07:00Z", events.TypeFreeze, "overseer", events.FreezePayload("gastown", true, "", time.Date(2026, 1, 2, 10, 7, 0, 0, time.UTC), time.Time{})),
+		ev("2026-01-02T10:08:00Z", events.TypeFreeze, "overseer", events.FreezePayload("gastown", false, "", time.Time{}, time.Time{})),
+	})
+
+	if s.Events != 9 || s.First.Hour() != 10 || s.First.Minute() != 0 {
+		t.Errorf("events %d first %v", s.Events, s.First)
+	}
+	if h := s.Hooks["gt-a"]; !h.Hooked || h.Target != "gastown/polecats/Toast" {
+		t.Errorf("gt-a = %+v", h)
+	}
+	if h := s.Hooks["gt-b"]; h.Hooked || h.Event != events.TypeDone || h.Target != "gastown/crew/max" {
+		t.Errorf("gt-b = %+v", h)
+	}
+	if h := s.Hooks["gt-c"]; !h.Hooked || h.Target != "gastown/polecats/Nux" || s.Queue["gt-c"].Queued {
+		t.Errorf("gt-c hook %+v queue %+v", h, s.Queue["gt-c"])
+	}
+	if q := s.Queue["gt-q"]; !q.Queued || q.Rig != "gastown" {
+		t.Errorf("gt-q = %+v", q)
+	}
+	if f := s.Freezes["beads"]; !f.Frozen || f.Reason != "release" || f.By != "overseer" {
+		t.Errorf("beads freeze = %+v", f)
+	}
+	if f := s.Freezes["gastown"]; f.Frozen {
+		t.Errorf("gastown should be thawed: %+v", f)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	at := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	want := &State{
+		Hooks: map[string]Hook{
+			"gt-ok":     {Target: "gastown/polecats/Toast", Hooked: true},
+			"gt-lost":   {Target: "gastown/polecats/Nux", Hooked: true},
+			"gt-stolen": {Target: "gastown/polecats/Nux", Hooked: true},
+			"gt-closed": {Target: "gastown/polecats/Nux", Hooked: true},
+			"gt-done":   {Target: "gastown/polecats/Nux", Event: events.TypeDone, At: at},
+			"gt-disp":   {Target: "gastown/polecats/Slit", Hooked: true},
+		},
+		Queue: map[string]Queued{
+			"gt-q":    {Rig: "gastown", Queued: true},
+			"gt-disp": {Rig: "gastown"},
+		},
+		Freezes: map[string]Freeze{
+			"beads":   {Rig: "beads", Frozen: true, From: at},
+			"gastown": {Rig: "gastown", At: at},
+			"old":     {Rig: "old", Frozen: true, From: at, Until: at.Add(time.Hour)},
+		},
+	}
+	have := &Observed{
+		Beads: map[string]Bead{
+			"gt-ok":     {Status: "hooked", Assignee: "gastown/polecats/Toast"},
+			"gt-lost":   {Status: "open"},
+			"gt-stolen": {Status: "hooked", Assignee: "gastown/crew/max"},
+			"gt-closed": {Status: "closed"},
+			"gt-q":      {Status: "open"},
+			"gt-disp":   {Status: "hooked", Assignee: "gastown/polecats/Slit"},
+		},
+		Hooked: map[string]string{
+			"gt-ok":     "gastown/polecats/Toast",
+			"gt-stolen": "gastown/crew/max",
+			"gt-done":   "gastown/polecats/Nux",
+			"gt-orphan": "gastown/crew/max",
+			"gt-disp":   "gastown/polecats/Slit",
+		},
+		Queued: map[string]string{"gt-disp": "gastown"},
+		Frozen: map[string]Freeze{"gastown": {Rig: "gastown", Frozen: true}},
+	}
+
+	got := map[string]Drift{}
+	for _, d := range Compare(want, have, at.Add(2*time.Hour)) {
+		got[d.Kind+" "+d.Subject] = d
+	}
+	expect := map[string]string{
+		"assignment gt-lost":   RepairHook,
+		"assignment gt-stolen": RepairHook,
+		"assignment gt-done":   RepairNone,
+		"assignment gt-orphan": RepairNone,
+		"queue gt-q":           RepairEnqueue,
+		"queue gt-disp":        RepairDequeue,
+		"freeze beads":         RepairFreeze,
+		"freeze gastown":       RepairUnfreeze,
+	}
+	for key, repair := range expect {
+		d, ok := got[key]
+		if !ok {
+			t.Errorf("missing drift %s", key)
+			continue
+		}
+		if d.Repair != repair {
+			t.Errorf("%s repair = %q, want %q", key, d.Repair, repair)
+		}
+	}
+	if len(got) != len(expect) {
+		t.Errorf("drifts = %+v", got)
+	}
+}
@@ -0,0 +1,457 @@
+// Package towntemplate exports a town's structure as a shareable template
+// and bootstraps towns from one.
+//
+// A template carries what a team would otherwise rebuild by hand: town
+// settings (agents, role assignments, policies), role definitions and
+// briefings, the active persona of each role, the town's own formulas and
+// per-rig settings as rig templates. It never carries code, beads, runtime
+// state or secrets: environment variables, the people named in the access
+// policy and mayor shards (which name this town's rigs) are stripped on
+// export and listed in the manifest.
+//
+// On disk a template is a plain directory, so it can be shared as a git
+// repository:
+//
+//	template.json            manifest
+//	settings/config.json     town settings
+//	roles/*.toml, roles/*.md role definitions, overrides and briefings
+//	personas/<role>.md       active persona per role
+//	formulas/*.formula.toml  formulas that aren't stock gt formulas
+//	rig-templates/<name>.json rig settings, applied with gt rig add --template
+package towntemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/persona"
+)
+
+// ManifestFile names the manifest at the root of a template directory.
+const ManifestFile = "template.json"
+
+const formulaSuffix = ".formula.toml"
+
+// Manifest describes a template.
+type Manifest struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	ExportedAt  time.Time `json:"exported_at"`
+	GTVersion   string    `json:"gt_version,omitempty"` // gt that exported it
+
+	Settings     bool     `json:"settings,omitempty"`
+	Roles        []string `json:"roles,omitempty"`
+	Personas     []string `json:"personas,omitempty"`
+	Formulas     []string `json:"formulas,omitempty"`
+	RigTemplates []string `json:"rig_templates,omitempty"`
+
+	// Stripped lists the settings removed on export. Whoever applies the
+	// template has to supply them.
+	Stripped []string `json:"stripped,omitempty"`
+}
+
+// ExportOptions configure Export.
+type ExportOptions struct {
+	Name        string
+	Description string
+	GTVersion   string
+}
+
+// ApplyResult reports what Apply wrote and what it left alone.
+type ApplyResult struct {
+	Written []string
+	Skipped []string // Already present in the town
+}
+
+// RigTemplatesDir returns the directory holding a town's rig templates.
+func RigTemplatesDir(townRoot string) string {
+	return filepath.Join(townRoot, "settings", "rig-templates")
+}
+
+// RigTemplatePath returns the settings file for rig template name.
+func RigTemplatePath(townRoot, name string) string {
+	return filepath.Join(RigTemplatesDir(townRoot), name+".json")
+}
+
+// LoadRigTemplate loads rig template name from the town.
+func LoadRigTemplate(townRoot, name string) (*config.RigSettings, error) {
+	settings, err := config.LoadRigSettings(RigTemplatePath(townRoot, name))
+	if errors.Is(err, config.ErrNotFound) {
+		return nil, fmt.Errorf("no rig template %q (see %s)", name, RigTemplatesDir(townRoot))
+	}
+	return settings, err
+}
+
+// Load reads the manifest of the template in dir.
+func Load(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile)) //nolint:gosec // G304: template path chosen by the operator
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%s is not a town template (no %s)", dir, ManifestFile)
+		}
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ManifestFile, err)
+	}
+	return &m, nil
+}
+
+// Export writes townRoot's structure as a template into dest, which must
+// be empty or not exist yet.
+func Export(townRoot, dest string, opts ExportOptions) (*Manifest, error) {
+	if entries, err := os.ReadDir(dest); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%s is not empty", dest)
+	}
+	if opts.Name == "" {
+		opts.Name = filepath.Base(townRoot)
+	}
+	m := &Manifest{
+		Name:        opts.Name,
+		Description: opts.Description,
+		ExportedAt:  time.Now().UTC(),
+		GTVersion:   opts.GTVersion,
+	}
+
+	if err := exportSettings(townRoot, dest, m); err != nil {
+		return nil, err
+	}
+	if err := exportRoles(townRoot, dest, m); err != nil {
+		return nil, err
+	}
+	if err := exportPersonas(townRoot, dest, m); err != nil {
+		return nil, err
+	}
+	if err := exportFormulas(townRoot, dest, m); err != nil {
+		return nil, err
+	}
+	if err := exportRigTemplates(townRoot, dest, m); err != nil {
+		return nil, err
+	}
+	sort.Strings(m.Stripped)
+
+	if err := writeJSON(filepath.Join(dest, ManifestFile), m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func exportSettings(townRoot, dest string, m *Manifest) error {
+	path := config.TownSettingsPath(townRoot)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	settings, err := config.LoadOrCreateTownSettings(path)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	m.Stripped = append(m.Stripped, stripTownSettings(settings)...)
+	m.Settings = true
+	return config.SaveTownSettings(filepath.Join(dest, "settings", "config.json"), settings)
+}
+
+// stripTownSettings removes secrets and town-specific settings in place
+// and returns what it removed.
+func stripTownSettings(s *config.TownSettings) []string {
+	stripped := stripAgentEnv("agents", s.Agents)
+	if s.Access != nil && len(s.Access.Principals) > 0 {
+		s.Access.Principals = nil
+		stripped = append(stripped, "access.principals")
+	}
+	if s.Mayors != nil {
+		s.Mayors = nil
+		stripped = append(stripped, "mayors")
+	}
+	return stripped
+}
+
+// stripRigSettings removes secrets from rig settings in place and returns
+// what it removed, prefixed with prefix.
+func stripRigSettings(prefix string, s *config.RigSettings) []string {
+	stripped := stripAgentEnv(prefix+".agents", s.Agents)
+	if s.Runtime != nil && len(s.Runtime.Env) > 0 {
+		s.Runtime.Env = nil
+		stripped = append(stripped, prefix+".runtime.env")
+	}
+	if s.Env != nil && len(s.Env.Vars) > 0 {
+		s.Env.Vars = nil
+		stripped = append(stripped, prefix+".env.vars")
+	}
+	return stripped
+}
+
+func stripAgentEnv(prefix string, agents map[string]*config.RuntimeConfig) []string {
+	var stripped []string
+	for name, rc := range agents {
+		if rc != nil && len(rc.Env) > 0 {
+			rc.Env = nil
+			stripped = append(stripped, prefix+"."+name+".env")
+		}
+	}
+	return stripped
+}
+
+// exportRoles copies role definitions, overrides and briefings. Env tables
+// are dropped from definitions since they commonly hold credentials.
+func exportRoles(townRoot, dest string, m *Manifest) error {
+	entries, err := os.ReadDir(config.CustomRolesDir(townRoot))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, e := range entries {
+		name := e.Name()
+		ext := filepath.Ext(name)
+		if e.IsDir() || (ext != ".toml" && ext != ".md") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(config.CustomRolesDir(townRoot), name))
+		if err != nil {
+			return err
+		}
+		if ext == ".toml" {
+			var hadEnv bool
+			if data, hadEnv, err = stripRoleEnv(data); err != nil {
+				return fmt.Errorf("role %s: %w", name, err)
+			}
+			role := strings.TrimSuffix(name, ext)
+			if hadEnv {
+				m.Stripped = append(m.Stripped, "roles."+role+".env")
+			}
+			m.Roles = append(m.Roles, role)
+		}
+		if err := writeFile(filepath.Join(dest, "roles", name), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stripRoleEnv drops the env table from a role definition. Files without
+// one are returned unchanged, comments and all.
+func stripRoleEnv(data []byte) ([]byte, bool, error) {
+	var def map[string]any
+	if _, err := toml.Decode(string(data), &def); err != nil {
+		return nil, false, err
+	}
+	if _, ok := def["env"]; !ok {
+		return data, false, nil
+	}
+	delete(def, "env")
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(def); err != nil {
+		return nil, false, err
+	}
+	return buf.Bytes(), true, nil
+}
+
+// exportPersonas writes the active persona of each role. History stays
+// behind: it records who changed what in this town.
+func exportPersonas(townRoot, dest string, m *Manifest) error {
+	dir := filepath.Join(config.CustomRolesDir(townRoot), "personas")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	store := persona.NewStore(townRoot)
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		v, content, err := store.Current(e.Name())
+		if err != nil {
+			return fmt.Errorf("persona %s: %w", e.Name(), err)
+		}
+		if v == nil {
+			continue
+		}
+		if err := writeFile(filepath.Join(dest, "personas", e.Name()+".md"), []byte(content)); err != nil {
+			return err
+		}
+		m.Personas = append(m.Personas, e.Name())
+	}
+	return nil
+}
+
+// exportFormulas copies the town's formulas, skipping unmodified stock
+// ones that every gt install provisions anyway.
+func exportFormulas(townRoot, dest string, m *Manifest) error {
+	dir := filepath.Join(townRoot, ".beads", "formulas")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), formulaSuffix) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return err
+		}
+		if stock, err := formula.GetEmbeddedFormulaContent(e.Name()); err == nil && bytes.Equal(stock, data) {
+			continue
+		}
+		if err := writeFile(filepath.Join(dest, "formulas", e.Name()), data); err != nil {
+			return err
+		}
+		m.Formulas = append(m.Formulas, strings.TrimSuffix(e.Name(), formulaSuffix))
+	}
+	return nil
+}
+
+// exportRigTemplates turns each rig's settings into a rig template, and
+// carries over the town's own rig templates. The rig's repository, beads
+// and agents are not exported.
+func exportRigTemplates(townRoot, dest string, m *Manifest) error {
+	sources := map[string]string{}
+	if entries, err := os.ReadDir(RigTemplatesDir(townRoot)); err == nil {
+		for _, e := range entries {
+			if name, ok := strings.CutSuffix(e.Name(), ".json"); ok && !e.IsDir() {
+				sources[name] = RigTemplatePath(townRoot, name)
+			}
+		}
+	}
+	if rigs, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+		for name := range rigs.Rigs {
+			path := config.RigSettingsPath(filepath.Join(townRoot, name))
+			if _, err := os.Stat(path); err == nil {
+				sources[name] = path
+			}
+		}
+	}
+
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		settings, err := config.LoadRigSettings(sources[name])
+		if err != nil {
+			return fmt.Errorf("rig %s: %w", name, err)
+		}
+		m.Stripped = append(m.Stripped, stripRigSettings("rigs."+name, settings)...)
+		if err := config.SaveRigSettings(filepath.Join(dest, "rig-templates", name+".json"), settings); err != nil {
+			return err
+		}
+		m.RigTemplates = append(m.RigTemplates, name)
+	}
+	return nil
+}
+
+// Apply copies the template in dir into townRoot. Files the town already
+// has are skipped unless force is set. Personas are saved as new versions
+// attributed to author, so gt role rollback can undo them.
+func Apply(dir, townRoot string, force bool, author string) (*ApplyResult, error) {
+	m, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+	r := &ApplyResult{}
+
+	if m.Settings {
+		if err := r.copy(filepath.Join(dir, "settings", "config.json"), config.TownSettingsPath(townRoot), force); err != nil {
+			return r, err
+		}
+	}
+	for _, file := range listFiles(filepath.Join(dir, "roles")) {
+		if err := r.copy(filepath.Join(dir, "roles", file), filepath.Join(config.CustomRolesDir(townRoot), file), force); err != nil {
+			return r, err
+		}
+	}
+	for _, file := range listFiles(filepath.Join(dir, "formulas")) {
+		if err := r.copy(filepath.Join(dir, "formulas", file), filepath.Join(townRoot, ".beads", "formulas", file), force); err != nil {
+			return r, err
+		}
+	}
+	for _, file := range listFiles(filepath.Join(dir, "rig-templates")) {
+		if err := r.copy(filepath.Join(dir, "rig-templates", file), filepath.Join(RigTemplatesDir(townRoot), file), force); err != nil {
+			return r, err
+		}
+	}
+
+	store := persona.NewStore(townRoot)
+	for _, role := range m.Personas {
+		data, err := os.ReadFile(filepath.Join(dir, "personas", role+".md")) //nolint:gosec // G304: template path chosen by the operator
+		if err != nil {
+			return r, fmt.Errorf("persona %s: %w", role, err)
+		}
+		_, err = store.Save(role, string(data), author, "from template "+m.Name)
+		switch {
+		case errors.Is(err, persona.ErrUnchanged):
+			r.Skipped = append(r.Skipped, "persona "+role)
+		case err != nil:
+			return r, fmt.Errorf("persona %s: %w", role, err)
+		default:
+			r.Written = append(r.Written, "persona "+role)
+		}
+	}
+	return r, nil
+}
+
+func (r *ApplyResult) copy(src, dst string, force bool) error {
+	if _, err := os.Stat(dst); err == nil && !force {
+		r.Skipped = append(r.Skipped, dst)
+		return nil
+	}
+	data, err := os.ReadFile(src) //nolint:gosec // G304: template path chosen by the operator
+	if err != nil {
+		return err
+	}
+	if err := writeFile(dst, data); err != nil {
+		return err
+	}
+	r.Written = append(r.Written, dst)
+	return nil
+}
+
+// listFiles returns the names of regular files in dir, sorted.
+func listFiles(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, e := range entries {
+		if e.Type().IsRegular() {
+			files = append(files, e.Name())
+		}
+	}
+	return files
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644) //nolint:gosec // G306: templates are meant to be shared
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(path, append(data, '\n'))
+}
@@ -0,0 +1,167 @@
+package towntemplate
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/formula"
+	"github.com/steveyegge/gastown/internal/persona"
+	"github.com/steveyegge/gastown/internal/rbac"
+)
+
+func mustWrite(t *testing.T, path, content string) {
+	t.Helper()
+	if err := writeFile(path, []byte(content)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testTown builds a town with one of everything a template carries, plus
+// secrets that must not leave it.
+func testTown(t *testing.T) string {
+	t.Helper()
+	town := t.TempDir()
+
+	settings := config.NewTownSettings()
+	settings.DefaultAgent = "claude"
+	settings.Agents = map[string]*config.RuntimeConfig{
+		"claude-work": {Command: "claude", Env: map[string]string{"ANTHROPIC_API_KEY": "sk-secret"}},
+	}
+	settings.Access = &rbac.Config{DefaultRole: "viewer", Principals: []rbac.Principal{{Name: "alice", Role: "operator"}}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(town), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	mustWrite(t, filepath.Join(town, "roles", "architect.toml"), "role = \"architect\"\nscope = \"town\"\nprompt_template = \"architect.md\"\n\n[env]\nGITHUB_TOKEN = \"ghp-secret\"\n")
+	mustWrite(t, filepath.Join(town, "roles", "architect.md"), "# Architect\n")
+	if _, err := persona.NewStore(town).Save("polecat", "Keep commits small.\n", "alice", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	stock, err := formula.GetEmbeddedFormulaContent("mol-polecat-work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mustWrite(t, filepath.Join(town, ".beads", "formulas", "mol-polecat-work.formula.toml"), string(stock))
+	mustWrite(t, filepath.Join(town, ".beads", "formulas", "mol-release.formula.toml"), "formula = \"mol-release\"\n")
+
+	mustWrite(t, filepath.Join(town, "mayor", "rigs.json"), `{"version": 1, "rigs": {"api": {"git_url": "https://example.com/api.git"}}}`)
+	rig := config.NewRigSettings()
+	rig.Env = &config.RigEnvConfig{Vars: map[string]string{"DATABASE_URL": "postgres://secret"}, Tools: map[string]string{"node": "20"}}
+	if err := config.SaveRigSettings(config.RigSettingsPath(filepath.Join(town, "api")), rig); err != nil {
+		t.Fatal(err)
+	}
+	return town
+}
+
+func TestExport(t *testing.T) {
+	town := testTown(t)
+	dest := filepath.Join(t.TempDir(), "tpl")
+
+	m, err := Export(town, dest, ExportOptions{Name: "acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.Settings || !slices.Equal(m.Roles, []string{"architect"}) || !slices.Equal(m.Personas, []string{"polecat"}) {
+		t.Errorf("manifest = %+v", m)
+	}
+	if !slices.Equal(m.Formulas, []string{"mol-release"}) {
+		t.Errorf("formulas = %v, want only the town's own", m.Formulas)
+	}
+	if !slices.Equal(m.RigTemplates, []string{"api"}) {
+		t.Errorf("rig templates = %v", m.RigTemplates)
+	}
+	wantStripped := []string{"access.principals", "agents.claude-work.env", "rigs.api.env.vars", "roles.architect.env"}
+	if !slices.Equal(m.Stripped, wantStripped) {
+		t.Errorf("stripped = %v, want %v", m.Stripped, wantStripped)
+	}
+
+	err = filepath.Walk(dest, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, secret := range []string{"sk-secret", "ghp-secret", "postgres://secret", "alice"} {
+			if strings.Contains(string(data), secret) {
+				t.Errorf("%s leaks %q", path, secret)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rig, err := config.LoadRigSettings(filepath.Join(dest, "rig-templates", "api.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rig.Env.Tools["node"] != "20" {
+		t.Errorf("rig template lost env.tools: %+v", rig.Env)
+	}
+
+	if _, err := Export(town, dest, ExportOptions{}); err == nil {
+		t.Error("export into a non-empty directory should fail")
+	}
+}
+
+func TestApply(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "tpl")
+	if _, err := Export(testTown(t), dest, ExportOptions{Name: "acme"}); err != nil {
+		t.Fatal(err)
+	}
+
+	town := t.TempDir()
+	mustWrite(t, filepath.Join(town, "roles", "architect.md"), "# Ours\n")
+
+	r, err := Apply(dest, town, false, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(r.Skipped, filepath.Join(town, "roles", "architect.md")) {
+		t.Errorf("skipped = %v, want existing briefing kept", r.Skipped)
+	}
+	if data, _ := os.ReadFile(filepath.Join(town, "roles", "architect.md")); string(data) != "# Ours\n" {
+		t.Errorf("briefing overwritten without --force: %q", data)
+	}
+	if !config.IsCustomRole(town, "architect") {
+		t.Error("custom role not applied")
+	}
+	if _, err := os.Stat(filepath.Join(town, ".beads", "formulas", "mol-release.formula.toml")); err != nil {
+		t.Errorf("formula not applied: %v", err)
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(town))
+	if err != nil || settings.DefaultAgent != "claude" {
+		t.Errorf("settings not applied: %+v, %v", settings, err)
+	}
+	if _, err := LoadRigTemplate(town, "api"); err != nil {
+		t.Errorf("rig template not applied: %v", err)
+	}
+	v, content, err := persona.NewStore(town).Current("polecat")
+	if err != nil || v == nil || content != "Keep commits small.\n" || v.Author != "bob" {
+		t.Errorf("persona = %+v %q %v", v, content, err)
+	}
+
+	if _, err := Apply(dest, town, true, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(town, "roles", "architect.md")); string(data) != "# Architect\n" {
+		t.Errorf("briefing not overwritten with force: %q", data)
+	}
+	if h, _ := persona.NewStore(town).History("polecat"); len(h) != 1 {
+		t.Errorf("re-applying an unchanged persona added versions: %d", len(h))
+	}
+}
+
+func TestLoad_NotATemplate(t *testing.T) {
+	if _, err := Load(t.TempDir()); err == nil || !strings.Contains(err.Error(), "not a town template") {
+		t.Errorf("Load(empty dir) = %v", err)
+	}
+}
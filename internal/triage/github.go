@@ -0,0 +1,83 @@
+package triage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// runGH executes gh. Replaced in tests.
+var runGH = func(args ...string) ([]byte, error) {
+	cmd := exec.Command("gh", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("gh %s: %v: %s", strings.Join(args[:2], " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// Issue is an open GitHub issue.
+type Issue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	URL    string `json:"url"`
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// Key identifies the issue in the import ledger.
+func (i *Issue) Key(repo string) string {
+	return fmt.Sprintf("github:%s#%d", repo, i.Number)
+}
+
+// ListIssues returns the source repo's open issues, oldest first.
+func ListIssues(src GitHubSource) ([]Issue, error) {
+	args := []string{"issue", "list", "--repo", src.Repo, "--state", "open", "--limit", "100",
+		"--json", "number,title,body,url,author,labels"}
+	for _, l := range src.Labels {
+		args = append(args, "--label", l)
+	}
+	out, err := runGH(args...)
+	if err != nil {
+		return nil, err
+	}
+	var issues []Issue
+	if err := json.Unmarshal(out, &issues); err != nil {
+		return nil, fmt.Errorf("parsing gh issue list: %w", err)
+	}
+	// gh lists newest first; import in filing order.
+	for i, j := 0, len(issues)-1; i < j; i, j = i+1, j-1 {
+		issues[i], issues[j] = issues[j], issues[i]
+	}
+	return issues, nil
+}
+
+// Description renders the intake bead description for an issue.
+func (i *Issue) Description(repo string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "GitHub issue %s#%d", repo, i.Number)
+	if i.Author.Login != "" {
+		fmt.Fprintf(&b, " by @%s", i.Author.Login)
+	}
+	fmt.Fprintf(&b, "\n%s\n", i.URL)
+	if len(i.Labels) > 0 {
+		var names []string
+		for _, l := range i.Labels {
+			names = append(names, l.Name)
+		}
+		fmt.Fprintf(&b, "Labels: %s\n", strings.Join(names, ", "))
+	}
+	if body := strings.TrimSpace(i.Body); body != "" {
+		fmt.Fprintf(&b, "\n%s\n", body)
+	}
+	return b.String()
+}
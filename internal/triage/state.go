@@ -0,0 +1,75 @@
+package triage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Imported records one intake item turned into a bead.
+type Imported struct {
+	Bead string    `json:"bead"`
+	At   time.Time `json:"at"`
+}
+
+// State is the import ledger in <town>/.runtime/triage.json. It keeps
+// GitHub issues and mail from being imported twice.
+type State struct {
+	Imported map[string]Imported `json:"imported"` // Source key → bead
+	path     string
+}
+
+// StatePath returns the ledger path for a town.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "triage.json")
+}
+
+// LoadState reads the ledger; a missing file yields an empty one.
+func LoadState(townRoot string) (*State, error) {
+	s := &State{Imported: make(map[string]Imported), path: StatePath(townRoot)}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("reading triage state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing triage state: %w", err)
+	}
+	if s.Imported == nil {
+		s.Imported = make(map[string]Imported)
+	}
+	return s, nil
+}
+
+// Has reports whether the source item was already imported.
+func (s *State) Has(key string) bool {
+	_, ok := s.Imported[key]
+	return ok
+}
+
+// Record notes that the source item became bead.
+func (s *State) Record(key, bead string, at time.Time) {
+	s.Imported[key] = Imported{Bead: bead, At: at}
+}
+
+// Save writes the ledger.
+func (s *State) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: state is not sensitive
+		return fmt.Errorf("writing triage state: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,353 @@
+// Package triage sorts new beads from intake sources for the standing
+// triage agent (gt triage).
+//
+// Intake beads arrive labeled triage:new: imported from GitHub issues or
+// mail to the triage agent, or created by webhook rules that add the label.
+// Each is checked against recent work for duplicates, matched against the
+// configured routes, and sized from the active time similar beads took.
+// Confident decisions are applied automatically; the rest are left for the
+// agent, which escalates to the human only what it can't decide either.
+package triage
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/dedup"
+)
+
+// Labels tracking a bead through triage.
+const (
+	LabelNew       = "triage:new"       // Waiting for triage
+	LabelRouted    = "triage:routed"    // Slung to a rig
+	LabelDuplicate = "triage:duplicate" // Closed as a duplicate
+	LabelEscalated = "triage:escalated" // Waiting for the human
+)
+
+// Defaults for an unset triage config.
+const (
+	DefaultRole           = "triage"
+	DefaultEscalateTo     = "overseer"
+	DefaultMinConfidence  = 0.7
+	DefaultDuplicateScore = 0.8
+)
+
+// Config is the triage section of town settings/config.json.
+//
+//	"triage": {
+//	  "github": [{"repo": "acme/app", "labels": ["bug"]}],
+//	  "mail": true,
+//	  "routes": [
+//	    {"rig": "app", "keywords": ["checkout", "cart"], "labels": ["github:acme/app"]},
+//	    {"rig": "infra", "keywords": ["deploy", "dns"], "add_labels": ["ops"]}
+//	  ],
+//	  "min_confidence": 0.7,
+//	  "duplicate_score": 0.8,
+//	  "escalate_to": "overseer"
+//	}
+type Config struct {
+	// GitHub lists repos whose open issues are imported as intake beads.
+	GitHub []GitHubSource `json:"github,omitempty"`
+
+	// Mail imports mail sent to the triage agent (triage/) as intake beads.
+	Mail bool `json:"mail,omitempty"`
+
+	// Routes map intake beads to rigs.
+	Routes []Route `json:"routes,omitempty"`
+
+	// MinConfidence is the routing confidence (0-1) needed to sling a bead
+	// without review. Default: 0.7.
+	MinConfidence float64 `json:"min_confidence,omitempty"`
+
+	// DuplicateScore is the similarity (0-1) at which a bead is closed as a
+	// duplicate without review. Default: 0.8.
+	DuplicateScore float64 `json:"duplicate_score,omitempty"`
+
+	// EscalateTo is the mail address ambiguous items go to. Default: overseer.
+	EscalateTo string `json:"escalate_to,omitempty"`
+}
+
+// GitHubSource is a repo whose issues are imported. Imported beads carry
+// "github:<repo>", so routes can match on where an issue was filed.
+type GitHubSource struct {
+	Repo   string   `json:"repo"`             // owner/name
+	Labels []string `json:"labels,omitempty"` // Only issues with all of these labels
+}
+
+// Route sends matching beads to a rig.
+type Route struct {
+	Rig string `json:"rig"`
+
+	// Keywords are matched as whole words in the bead's title and
+	// description, case-insensitively. Each hit counts once.
+	Keywords []string `json:"keywords,omitempty"`
+
+	// Labels are matched against the bead's labels (e.g. "github:acme/app",
+	// "webhook:sentry"). Each hit counts twice: the source is strong evidence.
+	Labels []string `json:"labels,omitempty"`
+
+	// AddLabels are added to beads routed here.
+	AddLabels []string `json:"add_labels,omitempty"`
+}
+
+var repoRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+// GetMinConfidence returns MinConfidence or the default if unset or out of range.
+func (c *Config) GetMinConfidence() float64 {
+	if c == nil || c.MinConfidence <= 0 || c.MinConfidence > 1 {
+		return DefaultMinConfidence
+	}
+	return c.MinConfidence
+}
+
+// GetDuplicateScore returns DuplicateScore or the default if unset or out of range.
+func (c *Config) GetDuplicateScore() float64 {
+	if c == nil || c.DuplicateScore <= 0 || c.DuplicateScore > 1 {
+		return DefaultDuplicateScore
+	}
+	return c.DuplicateScore
+}
+
+// GetEscalateTo returns the address ambiguous items are escalated to.
+func (c *Config) GetEscalateTo() string {
+	if c == nil || c.EscalateTo == "" {
+		return DefaultEscalateTo
+	}
+	return c.EscalateTo
+}
+
+// Validate checks the config. A nil config is valid (nothing imported, no
+// routes: every bead goes to review).
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	for _, s := range c.GitHub {
+		if !repoRe.MatchString(s.Repo) {
+			return fmt.Errorf("github repo %q must be owner/name", s.Repo)
+		}
+	}
+	for i, r := range c.Routes {
+		if r.Rig == "" {
+			return fmt.Errorf("routes[%d]: rig is required", i)
+		}
+		if len(r.Keywords) == 0 && len(r.Labels) == 0 {
+			return fmt.Errorf("route to %s: set keywords or labels", r.Rig)
+		}
+	}
+	if c.MinConfidence < 0 || c.MinConfidence > 1 {
+		return fmt.Errorf("min_confidence must be between 0 and 1")
+	}
+	if c.DuplicateScore < 0 || c.DuplicateScore > 1 {
+		return fmt.Errorf("duplicate_score must be between 0 and 1")
+	}
+	return nil
+}
+
+// Item is an intake bead reduced to what triage looks at.
+type Item struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+}
+
+// Candidate is a rig a bead could route to, with the evidence for it.
+type Candidate struct {
+	Rig     string   `json:"rig"`
+	Score   int      `json:"score"`
+	Matched []string `json:"matched"`
+}
+
+// Candidates scores every route against item, best first. Routes to the
+// same rig add up; routes with no hits are dropped.
+func (c *Config) Candidates(item Item) []Candidate {
+	if c == nil {
+		return nil
+	}
+	words := wordSet(item.Title + "\n" + item.Description)
+	byRig := map[string]*Candidate{}
+	var order []string
+	for _, r := range c.Routes {
+		cand := byRig[r.Rig]
+		if cand == nil {
+			cand = &Candidate{Rig: r.Rig}
+		}
+		for _, k := range r.Keywords {
+			if containsPhrase(words, k) {
+				cand.Score++
+				cand.Matched = append(cand.Matched, k)
+			}
+		}
+		for _, l := range r.Labels {
+			for _, have := range item.Labels {
+				if have == l {
+					cand.Score += 2
+					cand.Matched = append(cand.Matched, "label "+l)
+				}
+			}
+		}
+		if cand.Score > 0 && byRig[r.Rig] == nil {
+			byRig[r.Rig] = cand
+			order = append(order, r.Rig)
+		}
+	}
+	out := make([]Candidate, 0, len(order))
+	for _, rig := range order {
+		out = append(out, *byRig[rig])
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out
+}
+
+// addLabels returns the labels routes to rig add, without repeats.
+func (c *Config) addLabels(rig string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, r := range c.Routes {
+		if r.Rig != rig {
+			continue
+		}
+		for _, l := range r.AddLabels {
+			if !seen[l] {
+				seen[l] = true
+				out = append(out, l)
+			}
+		}
+	}
+	return out
+}
+
+var wordRe = regexp.MustCompile(`[a-z0-9][a-z0-9_.-]*[a-z0-9]|[a-z0-9]`)
+
+// wordSet returns the text's lowercased words as a space-padded string, so
+// a phrase matches on word boundaries with a plain substring search.
+func wordSet(text string) string {
+	return " " + strings.Join(wordRe.FindAllString(strings.ToLower(text), -1), " ") + " "
+}
+
+func containsPhrase(words, phrase string) bool {
+	p := strings.TrimSpace(wordSet(phrase))
+	return p != "" && strings.Contains(words, " "+p+" ")
+}
+
+// Action is what triage does with a bead.
+type Action string
+
+// Actions.
+const (
+	ActionRoute     Action = "route"     // Sling to Decision.Rig
+	ActionDuplicate Action = "duplicate" // Close as a duplicate of Decision.DuplicateOf
+	ActionReview    Action = "review"    // Ambiguous: the agent decides, or escalates
+)
+
+// Decision is the triage verdict for one bead.
+type Decision struct {
+	Bead        string      `json:"bead"`
+	Action      Action      `json:"action"`
+	Rig         string      `json:"rig,omitempty"`
+	Confidence  float64     `json:"confidence"`
+	Labels      []string    `json:"labels,omitempty"` // Labels to add when routing
+	Estimate    Size        `json:"estimate,omitempty"`
+	DuplicateOf string      `json:"duplicate_of,omitempty"`
+	Similarity  float64     `json:"similarity,omitempty"`
+	Candidates  []Candidate `json:"candidates,omitempty"`
+	Reason      string      `json:"reason"`
+}
+
+// Decide triages item given its likely duplicates (best first) and the
+// active time similar finished beads took. A close enough duplicate wins;
+// then a route whose share of the evidence reaches MinConfidence. Anything
+// else is left for review.
+func (c *Config) Decide(item Item, duplicates []dedup.Match, history []time.Duration) *Decision {
+	d := &Decision{Bead: item.ID, Action: ActionReview, Candidates: c.Candidates(item)}
+	d.Estimate = Estimate(history)
+
+	if len(duplicates) > 0 {
+		d.DuplicateOf, d.Similarity = duplicates[0].ID, duplicates[0].Score
+		if d.Similarity >= c.GetDuplicateScore() {
+			d.Action, d.Confidence = ActionDuplicate, d.Similarity
+			d.Reason = fmt.Sprintf("%.0f%% similar to %s [%s] %s", d.Similarity*100, d.DuplicateOf, duplicates[0].Status, duplicates[0].Title)
+			return d
+		}
+	}
+
+	if len(d.Candidates) == 0 {
+		d.Reason = "no route matches"
+		return d
+	}
+	total := 0
+	for _, cand := range d.Candidates {
+		total += cand.Score
+	}
+	best := d.Candidates[0]
+	d.Rig = best.Rig
+	d.Confidence = float64(best.Score) / float64(total)
+	d.Labels = c.addLabels(best.Rig)
+	if d.Estimate != "" {
+		d.Labels = append(d.Labels, d.Estimate.Label())
+	}
+	switch {
+	case d.Confidence < c.GetMinConfidence():
+		var rigs []string
+		for _, cand := range d.Candidates {
+			rigs = append(rigs, fmt.Sprintf("%s (%d)", cand.Rig, cand.Score))
+		}
+		d.Reason = "routes disagree: " + strings.Join(rigs, ", ")
+	case d.DuplicateOf != "":
+		d.Action = ActionRoute
+		d.Reason = fmt.Sprintf("matched %s; resembles %s (%.0f%%)", strings.Join(best.Matched, ", "), d.DuplicateOf, d.Similarity*100)
+	default:
+		d.Action = ActionRoute
+		d.Reason = "matched " + strings.Join(best.Matched, ", ")
+	}
+	return d
+}
+
+// Size is a rough estimate of a bead's work.
+type Size string
+
+// Sizes, by the median active time of similar finished beads.
+const (
+	SizeS  Size = "s"  // Up to an hour
+	SizeM  Size = "m"  // Up to half a day
+	SizeL  Size = "l"  // Up to two days
+	SizeXL Size = "xl" // More
+)
+
+// Label returns the bead label recording the estimate.
+func (s Size) Label() string {
+	return "estimate:" + string(s)
+}
+
+// Estimate sizes a bead from the active time similar finished beads took.
+// Returns "" without history.
+func Estimate(history []time.Duration) Size {
+	var ds []time.Duration
+	for _, d := range history {
+		if d > 0 {
+			ds = append(ds, d)
+		}
+	}
+	if len(ds) == 0 {
+		return ""
+	}
+	sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+	median := ds[len(ds)/2]
+	if len(ds)%2 == 0 {
+		median = (ds[len(ds)/2-1] + ds[len(ds)/2]) / 2
+	}
+	switch {
+	case median <= time.Hour:
+		return SizeS
+	case median <= 4*time.Hour:
+		return SizeM
+	case median <= 16*time.Hour:
+		return SizeL
+	default:
+		return SizeXL
+	}
+}
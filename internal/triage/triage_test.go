@@ -0,0 +1,156 @@
+package triage
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/dedup"
+)
+
+func testConfig() *Config {
+	return &Config{Routes: []Route{
+		{Rig: "app", Keywords: []string{"checkout", "cart"}, Labels: []string{"github:acme/app"}, AddLabels: []string{"area:shop"}},
+		{Rig: "infra", Keywords: []string{"deploy", "dns", "load balancer"}},
+	}}
+}
+
+func TestCandidates(t *testing.T) {
+	cfg := testConfig()
+	got := cfg.Candidates(Item{
+		Title:       "Checkout fails after deploy",
+		Description: "The load balancer returns 502 on /cart.",
+		Labels:      []string{"github:acme/app"},
+	})
+	if len(got) != 2 || got[0].Rig != "app" || got[0].Score != 4 || got[1].Rig != "infra" || got[1].Score != 2 {
+		t.Fatalf("Candidates = %+v", got)
+	}
+	if got := cfg.Candidates(Item{Title: "Carts and checkouts"}); len(got) != 0 {
+		t.Errorf("partial words should not match: %+v", got)
+	}
+	if got := (*Config)(nil).Candidates(Item{Title: "deploy"}); got != nil {
+		t.Errorf("nil config = %+v", got)
+	}
+}
+
+func TestDecide(t *testing.T) {
+	cfg := testConfig()
+	hour := []time.Duration{30 * time.Minute, 2 * time.Hour, 3 * time.Hour}
+
+	d := cfg.Decide(Item{ID: "hq-1", Title: "Cart total wrong at checkout"}, nil, hour)
+	if d.Action != ActionRoute || d.Rig != "app" || d.Confidence != 1 || d.Estimate != SizeM {
+		t.Errorf("clear route = %+v", d)
+	}
+	if strings.Join(d.Labels, ",") != "area:shop,estimate:m" {
+		t.Errorf("labels = %v", d.Labels)
+	}
+
+	d = cfg.Decide(Item{ID: "hq-2", Title: "Checkout broken after deploy"}, nil, nil)
+	if d.Action != ActionReview || d.Confidence != 0.5 || !strings.Contains(d.Reason, "disagree") {
+		t.Errorf("split evidence = %+v", d)
+	}
+
+	d = cfg.Decide(Item{ID: "hq-3", Title: "Slow emails"}, nil, nil)
+	if d.Action != ActionReview || d.Rig != "" {
+		t.Errorf("no route = %+v", d)
+	}
+
+	dup := []dedup.Match{{Document: dedup.Document{ID: "app-9", Title: "Cart total wrong", Status: "closed"}, Score: 0.91}}
+	d = cfg.Decide(Item{ID: "hq-4", Title: "Cart total wrong"}, dup, nil)
+	if d.Action != ActionDuplicate || d.DuplicateOf != "app-9" {
+		t.Errorf("duplicate = %+v", d)
+	}
+
+	dup[0].Score = 0.5
+	d = cfg.Decide(Item{ID: "hq-5", Title: "Cart total wrong"}, dup, nil)
+	if d.Action != ActionRoute || d.DuplicateOf != "app-9" || !strings.Contains(d.Reason, "resembles app-9") {
+		t.Errorf("weak duplicate = %+v", d)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	for _, tc := range []struct {
+		history []time.Duration
+		want    Size
+	}{
+		{nil, ""},
+		{[]time.Duration{0}, ""},
+		{[]time.Duration{20 * time.Minute}, SizeS},
+		{[]time.Duration{time.Hour, 3 * time.Hour}, SizeM},
+		{[]time.Duration{10 * time.Hour, 2 * time.Hour, 12 * time.Hour}, SizeL},
+		{[]time.Duration{40 * time.Hour}, SizeXL},
+	} {
+		if got := Estimate(tc.history); got != tc.want {
+			t.Errorf("Estimate(%v) = %q, want %q", tc.history, got, tc.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := testConfig().Validate(); err != nil {
+		t.Errorf("valid config: %v", err)
+	}
+	for _, cfg := range []*Config{
+		{GitHub: []GitHubSource{{Repo: "acme"}}},
+		{Routes: []Route{{Keywords: []string{"x"}}}},
+		{Routes: []Route{{Rig: "app"}}},
+		{MinConfidence: 2},
+	} {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%+v should not validate", cfg)
+		}
+	}
+}
+
+func TestListIssues(t *testing.T) {
+	orig := runGH
+	t.Cleanup(func() { runGH = orig })
+	var gotArgs []string
+	runGH = func(args ...string) ([]byte, error) {
+		gotArgs = args
+		return []byte(`[
+			{"number": 8, "title": "Newer", "url": "https://github.com/acme/app/issues/8"},
+			{"number": 7, "title": "Older", "body": "Steps.", "url": "https://github.com/acme/app/issues/7",
+			 "author": {"login": "ana"}, "labels": [{"name": "bug"}]}
+		]`), nil
+	}
+	issues, err := ListIssues(GitHubSource{Repo: "acme/app", Labels: []string{"bug"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 || issues[0].Number != 7 {
+		t.Fatalf("issues = %+v", issues)
+	}
+	if !strings.Contains(strings.Join(gotArgs, " "), "--repo acme/app --state open") || gotArgs[len(gotArgs)-1] != "bug" {
+		t.Errorf("args = %v", gotArgs)
+	}
+	if key := issues[0].Key("acme/app"); key != "github:acme/app#7" {
+		t.Errorf("Key = %s", key)
+	}
+	want := "GitHub issue acme/app#7 by @ana\nhttps://github.com/acme/app/issues/7\nLabels: bug\n\nSteps.\n"
+	if got := issues[0].Description("acme/app"); got != want {
+		t.Errorf("Description = %q", got)
+	}
+
+	runGH = func(args ...string) ([]byte, error) { return nil, fmt.Errorf("gh: not logged in") }
+	if _, err := ListIssues(GitHubSource{Repo: "acme/app"}); err == nil {
+		t.Error("gh failure should be returned")
+	}
+}
+
+func TestState(t *testing.T) {
+	town := t.TempDir()
+	s, err := LoadState(town)
+	if err != nil || s.Has("github:acme/app#7") {
+		t.Fatalf("empty state = %+v, %v", s, err)
+	}
+	s.Record("github:acme/app#7", "hq-1", time.Now())
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	s, err = LoadState(town)
+	if err != nil || !s.Has("github:acme/app#7") || s.Imported["github:acme/app#7"].Bead != "hq-1" {
+		t.Errorf("reloaded state = %+v, %v", s, err)
+	}
+}
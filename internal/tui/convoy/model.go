@@ -97,7 +97,7 @@ func loadConvoys(townBeads string) ([]ConvoyItem, error) {
 	var stdout bytes.Buffer
 	listCmd.Stdout = &stdout
 
-	if err := listCmd.Run(); err != nil {
+	if err := beads.RunCmdContext(ctx, listCmd); err != nil {
 		return nil, fmt.Errorf("listing convoys: %w", err)
 	}
 
@@ -143,7 +143,7 @@ func loadTrackedIssues(townBeads, convoyID string) ([]IssueItem, int, int) {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmdContext(ctx, cmd); err != nil {
 		return nil, 0, 0
 	}
 
@@ -213,7 +213,7 @@ func refreshIssueStatus(ctx context.Context, tracked []struct {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmdContext(ctx, cmd); err != nil {
 		return nil
 	}
 
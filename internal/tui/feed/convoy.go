@@ -15,6 +15,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/constants"
 )
 
@@ -97,7 +98,7 @@ func listConvoys(beadsDir, status string) ([]convoyListItem, error) {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmdContext(ctx, cmd); err != nil {
 		return nil, err
 	}
 
@@ -30,7 +30,7 @@ func getTrackedIssueStatus(beadsDir, convoyID string) []trackedStatus {
 	cmd.Dir = beadsDir
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmdContext(ctx, cmd); err != nil {
 		return nil
 	}
 
@@ -83,7 +83,7 @@ func refreshTrackedStatus(ctx context.Context, deps []struct {
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	if err := cmd.Run(); err != nil {
+	if err := beads.RunCmdContext(ctx, cmd); err != nil {
 		return nil
 	}
 
@@ -340,6 +340,12 @@ func (s *GtEventsSource) Close() error {
 	return s.file.Close()
 }
 
+// ParseGtEventLine parses a line from .events.jsonl into a feed event.
+// Returns nil for malformed lines and events not meant for the feed.
+func ParseGtEventLine(line string) *Event {
+	return parseGtEventLine(line)
+}
+
 // parseGtEventLine parses a line from .events.jsonl
 func parseGtEventLine(line string) *Event {
 	if strings.TrimSpace(line) == "" {
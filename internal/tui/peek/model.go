@@ -0,0 +1,203 @@
+// Package peek provides a tiled, read-only live view of several agent
+// sessions at once, with stalled agents highlighted.
+package peek
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+// Target is one agent session to show.
+type Target struct {
+	Address string // e.g., gastown/furiosa or gastown/crew/max
+	Session string // tmux session name
+}
+
+// Source reads agent panes. *tmux.Tmux satisfies it.
+type Source interface {
+	CapturePaneLines(session string, lines int) ([]string, error)
+	GetSessionActivity(session string) (time.Time, error)
+}
+
+// TileState is the last capture of one target.
+type TileState struct {
+	Target   Target
+	Lines    []string
+	Idle     time.Duration // Time since the pane last changed
+	Stalled  bool          // Idle for at least the stall threshold
+	Cause    witness.StallCause
+	Evidence string // Pane line that decided Cause
+	Err      error
+}
+
+// Capture reads every target once. A target is stalled when its pane has
+// been idle for at least stallAfter; stalled tiles get a probable cause
+// from the same classifier the witness uses.
+func Capture(src Source, targets []Target, lines int, stallAfter time.Duration, now time.Time) []TileState {
+	states := make([]TileState, len(targets))
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t Target) {
+			defer wg.Done()
+			st := TileState{Target: t}
+			st.Lines, st.Err = src.CapturePaneLines(t.Session, lines)
+			if st.Err == nil {
+				if activity, err := src.GetSessionActivity(t.Session); err == nil && !activity.IsZero() {
+					st.Idle = now.Sub(activity)
+				}
+				if stallAfter > 0 && st.Idle >= stallAfter {
+					st.Stalled = true
+					st.Cause, st.Evidence = witness.ClassifyStall(st.Lines)
+				}
+			}
+			states[i] = st
+		}(i, t)
+	}
+	wg.Wait()
+	return states
+}
+
+// Model is the bubbletea model for the tiled peek view.
+type Model struct {
+	src        Source
+	targets    []Target
+	lines      int
+	stallAfter time.Duration
+	interval   time.Duration
+
+	tiles  []TileState
+	focus  int
+	zoomed bool
+
+	// UI state
+	keys     KeyMap
+	help     help.Model
+	showHelp bool
+	width    int
+	height   int
+
+	// mu protects all fields read by View() from concurrent access:
+	// tiles, focus, zoomed, showHelp, help, width, height.
+	mu sync.RWMutex
+}
+
+// New creates a tiled peek model that captures lines of each target every
+// interval.
+func New(src Source, targets []Target, lines int, stallAfter, interval time.Duration) *Model {
+	return &Model{
+		src:        src,
+		targets:    targets,
+		lines:      lines,
+		stallAfter: stallAfter,
+		interval:   interval,
+		keys:       DefaultKeyMap(),
+		help:       help.New(),
+	}
+}
+
+// Init initializes the model.
+func (m *Model) Init() tea.Cmd {
+	return m.capture
+}
+
+// captureMsg is the result of capturing every target.
+type captureMsg struct {
+	tiles []TileState
+}
+
+// tickMsg triggers the next capture.
+type tickMsg struct{}
+
+func (m *Model) capture() tea.Msg {
+	return captureMsg{tiles: Capture(m.src, m.targets, m.lines, m.stallAfter, time.Now())}
+}
+
+func (m *Model) tick() tea.Cmd {
+	return tea.Tick(m.interval, func(time.Time) tea.Msg { return tickMsg{} })
+}
+
+// Update handles messages.
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.mu.Lock()
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		m.mu.Unlock()
+		return m, nil
+
+	case captureMsg:
+		m.mu.Lock()
+		m.tiles = msg.tiles
+		m.mu.Unlock()
+		return m, m.tick()
+
+	case tickMsg:
+		return m, m.capture
+
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+
+		case key.Matches(msg, m.keys.Help):
+			m.mu.Lock()
+			m.showHelp = !m.showHelp
+			m.mu.Unlock()
+
+		case key.Matches(msg, m.keys.Zoom):
+			m.mu.Lock()
+			m.zoomed = !m.zoomed
+			m.mu.Unlock()
+
+		case key.Matches(msg, m.keys.Refresh):
+			return m, m.capture
+
+		case key.Matches(msg, m.keys.Left):
+			m.moveFocus(-1)
+		case key.Matches(msg, m.keys.Right):
+			m.moveFocus(1)
+		case key.Matches(msg, m.keys.Up):
+			m.moveFocus(-gridColumns(len(m.targets)))
+		case key.Matches(msg, m.keys.Down):
+			m.moveFocus(gridColumns(len(m.targets)))
+		}
+	}
+
+	return m, nil
+}
+
+// moveFocus moves the focused tile by delta, staying on the grid.
+func (m *Model) moveFocus(delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if f := m.focus + delta; f >= 0 && f < len(m.targets) {
+		m.focus = f
+	}
+}
+
+// gridColumns returns how many tiles go on one row: the grid is as close
+// to square as the tile count allows.
+func gridColumns(n int) int {
+	if n <= 1 {
+		return 1
+	}
+	return int(math.Ceil(math.Sqrt(float64(n))))
+}
+
+// View renders the model.
+// Acquires read lock to safely access all View-visible fields.
+func (m *Model) View() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.renderView()
+}
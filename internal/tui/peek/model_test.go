@@ -0,0 +1,123 @@
+package peek
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+type fakeSource struct {
+	panes    map[string][]string
+	activity map[string]time.Time
+}
+
+func (f *fakeSource) CapturePaneLines(session string, lines int) ([]string, error) {
+	pane, ok := f.panes[session]
+	if !ok {
+		return nil, errors.New("no such session")
+	}
+	return pane, nil
+}
+
+func (f *fakeSource) GetSessionActivity(session string) (time.Time, error) {
+	return f.activity[session], nil
+}
+
+func TestCapture(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	src := &fakeSource{
+		panes: map[string][]string{
+			"gt-nux":   {"Running tests...", "esc to interrupt"},
+			"gt-toast": {"Do you want to proceed?", "❯ 1. Yes"},
+		},
+		activity: map[string]time.Time{
+			"gt-nux":   now.Add(-time.Minute),
+			"gt-toast": now.Add(-20 * time.Minute),
+		},
+	}
+	targets := []Target{
+		{Address: "gastown/nux", Session: "gt-nux"},
+		{Address: "gastown/toast", Session: "gt-toast"},
+		{Address: "gastown/gone", Session: "gt-gone"},
+	}
+
+	tiles := Capture(src, targets, 50, 15*time.Minute, now)
+	if len(tiles) != 3 {
+		t.Fatalf("got %d tiles, want 3", len(tiles))
+	}
+	if tiles[0].Stalled || tiles[0].Idle != time.Minute {
+		t.Errorf("nux: stalled=%v idle=%v, want active for 1m", tiles[0].Stalled, tiles[0].Idle)
+	}
+	if !tiles[1].Stalled || tiles[1].Cause != witness.StallPermissionPrompt {
+		t.Errorf("toast: stalled=%v cause=%q, want stalled on permission prompt", tiles[1].Stalled, tiles[1].Cause)
+	}
+	if tiles[2].Err == nil {
+		t.Error("gone: expected capture error")
+	}
+}
+
+func TestGridColumns(t *testing.T) {
+	for n, want := range map[int]int{0: 1, 1: 1, 2: 2, 4: 2, 5: 3, 9: 3, 10: 4} {
+		if got := gridColumns(n); got != want {
+			t.Errorf("gridColumns(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	got := tailLines([]string{"a", "b", "c", "", "  "}, 2)
+	if strings.Join(got, ",") != "b,c" {
+		t.Errorf("tailLines = %q, want [b c]", got)
+	}
+}
+
+func TestModelFocusAndZoom(t *testing.T) {
+	targets := []Target{{"r/a", "a"}, {"r/b", "b"}, {"r/c", "c"}, {"r/d", "d"}}
+	m := New(&fakeSource{}, targets, 10, time.Minute, time.Second)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m.Update(captureMsg{tiles: []TileState{
+		{Target: targets[0], Lines: []string{"alpha"}},
+		{Target: targets[1], Lines: []string{"bravo"}},
+		{Target: targets[2], Lines: []string{"charlie"}, Stalled: true, Cause: witness.StallRateLimit},
+		{Target: targets[3], Lines: []string{"delta"}},
+	}})
+
+	view := m.View()
+	for _, want := range []string{"r/a", "bravo", "stalled: rate-limit", "1 stalled"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("grid view missing %q", want)
+		}
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m.focus != 3 {
+		t.Fatalf("focus = %d, want 3", m.focus)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m.focus != 3 {
+		t.Errorf("focus moved off the grid: %d", m.focus)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	view = m.View()
+	if !strings.Contains(view, "delta") || strings.Contains(view, "alpha") {
+		t.Error("zoomed view should show only the focused tile")
+	}
+}
+
+func TestRenderPlain(t *testing.T) {
+	out := RenderPlain([]TileState{
+		{Target: Target{Address: "r/a"}, Lines: []string{"one", "two", ""}, Idle: 3 * time.Minute},
+		{Target: Target{Address: "r/b"}, Stalled: true, Cause: witness.StallHumanQuestion, Idle: 2 * time.Hour},
+	}, 1)
+	want := "=== r/a (idle 3m) ===\ntwo\n\n=== r/b (idle 2h, STALLED: human-question) ===\n"
+	if out != want {
+		t.Errorf("RenderPlain =\n%q\nwant\n%q", out, want)
+	}
+}
@@ -0,0 +1,188 @@
+package peek
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles for the peek TUI
+var (
+	headerStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("12"))
+
+	stalledStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("9")) // red
+
+	dimStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")) // gray
+
+	tileStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("8"))
+
+	helpStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8"))
+)
+
+// Tile border colors, by state.
+var (
+	focusedBorder = lipgloss.Color("12") // blue
+	stalledBorder = lipgloss.Color("9")  // red
+)
+
+// renderView renders the whole grid, or just the focused tile when zoomed.
+// Caller must hold m.mu.
+func (m *Model) renderView() string {
+	if m.width == 0 || m.height == 0 {
+		return "Capturing sessions..."
+	}
+
+	var footer string
+	if m.showHelp {
+		footer = m.help.View(m.keys)
+	} else {
+		footer = helpStyle.Render(fmt.Sprintf("%s  ←→↑↓:focus  enter:zoom  r:refresh  q:quit  ?:help", m.summary()))
+	}
+	gridHeight := m.height - lipgloss.Height(footer)
+
+	if len(m.tiles) == 0 {
+		return lipgloss.JoinVertical(lipgloss.Left, lipgloss.PlaceVertical(gridHeight, lipgloss.Top, "Capturing sessions..."), footer)
+	}
+
+	if m.zoomed && m.focus < len(m.tiles) {
+		return lipgloss.JoinVertical(lipgloss.Left, renderTile(m.tiles[m.focus], m.width, gridHeight, true), footer)
+	}
+
+	cols := gridColumns(len(m.tiles))
+	rows := (len(m.tiles) + cols - 1) / cols
+	tileWidth := m.width / cols
+	tileHeight := gridHeight / rows
+
+	var gridRows []string
+	for r := 0; r < rows; r++ {
+		var row []string
+		for c := 0; c < cols; c++ {
+			i := r*cols + c
+			if i >= len(m.tiles) {
+				break
+			}
+			row = append(row, renderTile(m.tiles[i], tileWidth, tileHeight, i == m.focus))
+		}
+		gridRows = append(gridRows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, append(gridRows, footer)...)
+}
+
+// summary counts the agents shown and how many of them are stalled.
+// Caller must hold m.mu.
+func (m *Model) summary() string {
+	stalled := 0
+	for _, t := range m.tiles {
+		if t.Stalled {
+			stalled++
+		}
+	}
+	s := fmt.Sprintf("%d agents", len(m.tiles))
+	if stalled > 0 {
+		s += ", " + stalledStyle.Render(fmt.Sprintf("%d stalled", stalled))
+	}
+	return s
+}
+
+// renderTile renders one session in a bordered box of the given outer
+// size: a header line, then as much of the pane tail as fits.
+func renderTile(st TileState, width, height int, focused bool) string {
+	innerWidth := max(width-2, 1)
+	innerHeight := max(height-2, 1)
+
+	clip := lipgloss.NewStyle().MaxWidth(innerWidth)
+	body := []string{clip.Render(tileHeader(st))}
+	if st.Err != nil {
+		body = append(body, clip.Render(stalledStyle.Render(st.Err.Error())))
+	} else {
+		for _, line := range tailLines(st.Lines, innerHeight-1) {
+			body = append(body, clip.Render(strings.ReplaceAll(line, "\t", "    ")))
+		}
+	}
+
+	style := tileStyle.Width(innerWidth).Height(innerHeight).MaxHeight(height)
+	switch {
+	case st.Stalled:
+		style = style.BorderForeground(stalledBorder)
+	case focused:
+		style = style.BorderForeground(focusedBorder)
+	}
+	if focused {
+		style = style.BorderStyle(lipgloss.ThickBorder())
+	}
+	return style.Render(strings.Join(body, "\n"))
+}
+
+// tileHeader is the address, how long the pane has been idle and, for a
+// stalled session, the probable cause.
+func tileHeader(st TileState) string {
+	h := headerStyle.Render(st.Target.Address)
+	if st.Err != nil {
+		return h + dimStyle.Render(" · no session")
+	}
+	h += dimStyle.Render(" · idle " + formatIdle(st.Idle))
+	if st.Stalled {
+		h += " " + stalledStyle.Render("⚠ stalled: "+string(st.Cause))
+	}
+	return h
+}
+
+// tailLines returns the last n lines, ignoring trailing blank lines (an
+// idle pane is mostly empty rows below the prompt).
+func tailLines(lines []string, n int) []string {
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	start := max(end-n, 0)
+	return lines[start:end]
+}
+
+// formatIdle formats an idle duration as a short string (e.g., "5m", "2h").
+func formatIdle(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// RenderPlain renders a single capture without the grid, one section per
+// session, for output that isn't a terminal.
+func RenderPlain(tiles []TileState, lines int) string {
+	var b strings.Builder
+	for i, st := range tiles {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		header := fmt.Sprintf("=== %s", st.Target.Address)
+		switch {
+		case st.Err != nil:
+			header += " (no session)"
+		case st.Stalled:
+			header += fmt.Sprintf(" (idle %s, STALLED: %s)", formatIdle(st.Idle), st.Cause)
+		default:
+			header += fmt.Sprintf(" (idle %s)", formatIdle(st.Idle))
+		}
+		b.WriteString(header + " ===\n")
+		for _, line := range tailLines(st.Lines, lines) {
+			b.WriteString(line + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,241 @@
+// Package undo keeps a log of destructive town operations together with the
+// state needed to reverse them, so gt undo can put things back.
+//
+// Commands record an operation after it succeeds: removing a rig, nuking a
+// polecat, rewriting bead routes, closing beads. Each record carries what
+// the operation destroyed (the registry entry, the branch tip, the previous
+// routes, the beads' previous status). gt undo reverses the newest
+// operations first. Only the last MaxOps operations are kept.
+package undo
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// MaxOps is how many operations the log keeps.
+const MaxOps = 50
+
+// Operation kinds.
+const (
+	KindRigRemove   = "rig-remove"
+	KindPolecatNuke = "polecat-nuke"
+	KindRoutes      = "routes"
+	KindClose       = "close"
+)
+
+// Op is one recorded operation.
+type Op struct {
+	ID       string          `json:"id"`
+	Kind     string          `json:"kind"`
+	Summary  string          `json:"summary"`
+	Actor    string          `json:"actor,omitempty"`
+	Time     time.Time       `json:"time"`
+	State    json.RawMessage `json:"state"`
+	UndoneAt time.Time       `json:"undone_at,omitempty"`
+}
+
+// Undone reports whether the operation has been reversed.
+func (o Op) Undone() bool { return !o.UndoneAt.IsZero() }
+
+// RigRemoveState is what gt rig remove took out of the town.
+type RigRemoveState struct {
+	Rig   string          `json:"rig"`
+	Entry json.RawMessage `json:"entry"`           // The rig's mayor/rigs.json entry
+	Route *beads.Route    `json:"route,omitempty"` // The rig's routes.jsonl entry
+}
+
+// PolecatNukeState is what gt polecat nuke deleted. The branch's commits
+// stay in the rig's repository after the branch is deleted, so the
+// worktree can be recreated at the same commit.
+type PolecatNukeState struct {
+	Rig     string `json:"rig"`
+	Polecat string `json:"polecat"`
+	Branch  string `json:"branch,omitempty"`
+	Commit  string `json:"commit,omitempty"`
+	Issue   string `json:"issue,omitempty"` // Work bead hooked at the time
+}
+
+// RoutesState is routes.jsonl before and after a rewrite.
+type RoutesState struct {
+	BeadsDir string        `json:"beads_dir"`
+	Before   []beads.Route `json:"before"`
+	After    []beads.Route `json:"after"`
+}
+
+// CloseState is the beads a close closed and how they were before.
+type CloseState struct {
+	Beads []ClosedBead `json:"beads"`
+}
+
+// ClosedBead is a closed bead's status and assignee before the close.
+type ClosedBead struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Assignee string `json:"assignee,omitempty"`
+}
+
+// Dir returns the directory holding the undo log.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "undo")
+}
+
+func logPath(townRoot string) string { return filepath.Join(Dir(townRoot), "ops.jsonl") }
+
+// Record adds an operation to the log, dropping the oldest beyond MaxOps.
+func Record(townRoot, kind, summary, actor string, state any, now time.Time) (*Op, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("encoding undo state: %w", err)
+	}
+	op := Op{ID: newID(), Kind: kind, Summary: summary, Actor: actor, Time: now.UTC(), State: data}
+	err = withLock(townRoot, func(path string) error {
+		ops, err := readOps(path)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, op)
+		if len(ops) > MaxOps {
+			ops = ops[len(ops)-MaxOps:]
+		}
+		return writeOps(path, ops)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// List returns the logged operations, newest first.
+func List(townRoot string) ([]Op, error) {
+	ops, err := readOps(logPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Op, 0, len(ops))
+	for i := len(ops) - 1; i >= 0; i-- {
+		out = append(out, ops[i])
+	}
+	return out, nil
+}
+
+// Pending returns up to n operations not yet undone, newest first.
+func Pending(townRoot string, n int) ([]Op, error) {
+	ops, err := List(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	var out []Op
+	for _, op := range ops {
+		if len(out) == n {
+			break
+		}
+		if !op.Undone() {
+			out = append(out, op)
+		}
+	}
+	return out, nil
+}
+
+// Get returns the operation with the given ID.
+func Get(townRoot, id string) (*Op, error) {
+	ops, err := readOps(logPath(townRoot))
+	if err != nil {
+		return nil, err
+	}
+	for i := range ops {
+		if ops[i].ID == id {
+			return &ops[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no operation %q in the undo log", id)
+}
+
+// MarkUndone records that an operation was reversed.
+func MarkUndone(townRoot, id string, now time.Time) error {
+	return withLock(townRoot, func(path string) error {
+		ops, err := readOps(path)
+		if err != nil {
+			return err
+		}
+		for i := range ops {
+			if ops[i].ID != id {
+				continue
+			}
+			if ops[i].Undone() {
+				return fmt.Errorf("%s was already undone", id)
+			}
+			ops[i].UndoneAt = now.UTC()
+			return writeOps(path, ops)
+		}
+		return fmt.Errorf("no operation %q in the undo log", id)
+	})
+}
+
+func newID() string {
+	var b [4]byte
+	_, _ = rand.Read(b[:])
+	return "u-" + hex.EncodeToString(b[:])
+}
+
+func withLock(townRoot string, fn func(path string) error) error {
+	path := logPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating undo directory: %w", err)
+	}
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking undo log: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+	return fn(path)
+}
+
+func readOps(path string) ([]Op, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var ops []Op
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var op Op
+		if err := json.Unmarshal(scanner.Bytes(), &op); err == nil {
+			ops = append(ops, op)
+		}
+	}
+	return ops, scanner.Err()
+}
+
+func writeOps(path string, ops []Op) error {
+	var b strings.Builder
+	for _, op := range ops {
+		data, err := json.Marshal(op)
+		if err != nil {
+			return err
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil { //nolint:gosec // G306: runtime state
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,57 @@
+package undo
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordAndUndo(t *testing.T) {
+	town := t.TempDir()
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+
+	first, err := Record(town, KindRigRemove, "rig remove alpha", "overseer", RigRemoveState{Rig: "alpha"}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(first.ID, "u-") {
+		t.Errorf("ID = %q", first.ID)
+	}
+	second, err := Record(town, KindClose, "close gt-a gt-b", "overseer", CloseState{Beads: []ClosedBead{{ID: "gt-a", Status: "hooked", Assignee: "gastown/polecats/Toast"}}}, now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pending, _ := Pending(town, 1)
+	if len(pending) != 1 || pending[0].ID != second.ID {
+		t.Fatalf("Pending(1) = %+v, want newest", pending)
+	}
+	if err := MarkUndone(town, second.ID, now.Add(2*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if err := MarkUndone(town, second.ID, now); err == nil {
+		t.Error("undoing twice should fail")
+	}
+	pending, _ = Pending(town, 5)
+	if len(pending) != 1 || pending[0].ID != first.ID {
+		t.Errorf("after undo, Pending = %+v", pending)
+	}
+	op, err := Get(town, second.ID)
+	if err != nil || !op.Undone() || !strings.Contains(string(op.State), "gastown/polecats/Toast") {
+		t.Errorf("Get = %+v, %v", op, err)
+	}
+}
+
+func TestRecordKeepsLastMaxOps(t *testing.T) {
+	town := t.TempDir()
+	now := time.Now()
+	for i := 0; i < MaxOps+5; i++ {
+		if _, err := Record(town, KindRoutes, "routes", "", RoutesState{}, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ops, err := List(town)
+	if err != nil || len(ops) != MaxOps {
+		t.Errorf("kept %d ops (%v), want %d", len(ops), err, MaxOps)
+	}
+}
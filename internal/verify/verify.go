@@ -0,0 +1,177 @@
+// Package verify implements the acceptance gate gt done can run before a
+// polecat's work is submitted: a read-only verifier agent reads the bead's
+// acceptance criteria, the branch diff and the rig's test output, and either
+// accepts the work or bounces it back with the specific gaps it found.
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds one verifier run.
+	DefaultTimeout = 10 * time.Minute
+
+	// maxDiffBytes and maxTestBytes keep the prompt within agent limits.
+	// The diff keeps its head (file order), test output its tail (summary).
+	maxDiffBytes = 60 * 1024
+	maxTestBytes = 16 * 1024
+)
+
+// Config is the rig's verify_done settings.
+//
+//	"verify_done": {"enabled": true, "test": "go test ./...", "agent": "claude"}
+type Config struct {
+	Enabled bool `json:"enabled"`
+
+	// Test is a shell command run in the worktree whose output is shown to
+	// the verifier (e.g. "go test ./..."). Empty = no test output.
+	Test string `json:"test,omitempty"`
+
+	// Agent overrides the runtime the verifier uses (default: rig's agent).
+	Agent string `json:"agent,omitempty"`
+
+	// Timeout bounds the test command and the verifier run each (default 10m).
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// IsEnabled reports whether the gate is on. Nil-safe.
+func (c *Config) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// Validate checks the settings. Nil-safe.
+func (c *Config) Validate() error {
+	if c == nil || c.Timeout == "" {
+		return nil
+	}
+	if d, err := time.ParseDuration(c.Timeout); err != nil || d <= 0 {
+		return fmt.Errorf("timeout: invalid duration %q", c.Timeout)
+	}
+	return nil
+}
+
+// GetTimeout returns the configured timeout or DefaultTimeout.
+func (c *Config) GetTimeout() time.Duration {
+	if c != nil && c.Timeout != "" {
+		if d, err := time.ParseDuration(c.Timeout); err == nil && d > 0 {
+			return d
+		}
+	}
+	return DefaultTimeout
+}
+
+// Request is what the verifier checks.
+type Request struct {
+	BeadID      string
+	Title       string
+	Criteria    string // Acceptance criteria; falls back to Description when empty
+	Description string
+	Diff        string
+	TestCommand string
+	TestOutput  string
+	TestFailed  bool
+}
+
+// HasCriteria reports whether there is anything to verify against.
+func (r *Request) HasCriteria() bool {
+	return strings.TrimSpace(r.Criteria) != "" || strings.TrimSpace(r.Description) != ""
+}
+
+// Verdict is the verifier's decision.
+type Verdict struct {
+	Accepted bool     `json:"accepted"`
+	Gaps     []string `json:"gaps,omitempty"`
+	Summary  string   `json:"summary,omitempty"`
+}
+
+// Prompt frames the request for a read-only verifier agent.
+func (r *Request) Prompt() string {
+	var b strings.Builder
+	b.WriteString("You are the acceptance verifier for a Gas Town polecat that claims its work is done.\n")
+	b.WriteString("The worktree in your working directory holds its branch. This is read-only:\n")
+	b.WriteString("do not modify files, commit, or run gt/bd commands that write state.\n\n")
+	b.WriteString("Decide whether the change meets EVERY criterion below. Judge only the criteria,\n")
+	b.WriteString("not style preferences. A criterion is unmet if the diff doesn't implement it, the\n")
+	b.WriteString("tests don't cover it when it asks for tests, or the test output shows it failing.\n\n")
+	b.WriteString("Reply with ONLY a JSON object, no prose:\n")
+	b.WriteString(`{"accepted": false, "gaps": ["criterion X: what is missing and where"], "summary": "one line"}`)
+	b.WriteString("\nGaps must be specific enough for the polecat to fix without asking.\n\n")
+
+	fmt.Fprintf(&b, "Bead %s: %s\n\n", r.BeadID, r.Title)
+	if strings.TrimSpace(r.Criteria) != "" {
+		b.WriteString("Acceptance criteria:\n")
+		b.WriteString(strings.TrimSpace(r.Criteria))
+	} else {
+		b.WriteString("No explicit acceptance criteria; verify against the bead description:\n")
+		b.WriteString(strings.TrimSpace(r.Description))
+	}
+	b.WriteString("\n\n")
+
+	if r.TestCommand != "" {
+		status := "passed"
+		if r.TestFailed {
+			status = "FAILED"
+		}
+		fmt.Fprintf(&b, "Test output (%s, %s):\n%s\n\n", r.TestCommand, status, truncateTail(r.TestOutput, maxTestBytes))
+	}
+	b.WriteString("Diff:\n")
+	b.WriteString(truncateHead(r.Diff, maxDiffBytes))
+	return b.String()
+}
+
+// ParseVerdict extracts the verdict object from the agent's answer,
+// tolerating prose or code fences around it. A rejection must name at
+// least one gap.
+func ParseVerdict(answer string) (*Verdict, error) {
+	start, end := strings.Index(answer, "{"), strings.LastIndex(answer, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("verifier did not return a JSON verdict")
+	}
+	var v Verdict
+	if err := json.Unmarshal([]byte(answer[start:end+1]), &v); err != nil {
+		return nil, fmt.Errorf("parsing verdict: %w", err)
+	}
+	gaps := v.Gaps[:0]
+	for _, g := range v.Gaps {
+		if g = strings.TrimSpace(g); g != "" {
+			gaps = append(gaps, g)
+		}
+	}
+	v.Gaps = gaps
+	if !v.Accepted && len(v.Gaps) == 0 {
+		return nil, fmt.Errorf("verifier rejected the work without naming any gaps")
+	}
+	return &v, nil
+}
+
+// BounceMessage is what the polecat sees when its done claim is bounced.
+func (v *Verdict) BounceMessage(beadID string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "The acceptance verifier bounced %s back. Fix these gaps, commit, and run gt done again:\n", beadID)
+	for i, g := range v.Gaps {
+		fmt.Fprintf(&b, "  %d. %s\n", i+1, g)
+	}
+	if v.Summary != "" {
+		fmt.Fprintf(&b, "\nVerifier: %s\n", v.Summary)
+	}
+	b.WriteString("\nIf a criterion can't be met, escalate instead: gt done --status ESCALATED\n")
+	return b.String()
+}
+
+func truncateHead(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + fmt.Sprintf("\n... (%d more bytes truncated)\n", len(s)-n)
+}
+
+func truncateTail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return fmt.Sprintf("... (%d earlier bytes truncated)\n", len(s)-n) + s[len(s)-n:]
+}
@@ -0,0 +1,96 @@
+package verify
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfig(t *testing.T) {
+	var nilCfg *Config
+	if nilCfg.IsEnabled() || nilCfg.Validate() != nil || nilCfg.GetTimeout() != DefaultTimeout {
+		t.Error("nil config should be disabled, valid and use the default timeout")
+	}
+	cfg := &Config{Enabled: true, Timeout: "3m"}
+	if !cfg.IsEnabled() || cfg.Validate() != nil || cfg.GetTimeout() != 3*time.Minute {
+		t.Errorf("config %+v: enabled/valid/3m expected", cfg)
+	}
+	if (&Config{Timeout: "soon"}).Validate() == nil {
+		t.Error("invalid timeout should fail validation")
+	}
+}
+
+func TestParseVerdict(t *testing.T) {
+	v, err := ParseVerdict("```json\n{\"accepted\": true, \"summary\": \"all criteria met\"}\n```")
+	if err != nil || !v.Accepted || v.Summary != "all criteria met" {
+		t.Errorf("accepted verdict = %+v, %v", v, err)
+	}
+
+	v, err = ParseVerdict(`{"accepted": false, "gaps": ["criterion 2: no test for retries", "  "]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Accepted || len(v.Gaps) != 1 || v.Gaps[0] != "criterion 2: no test for retries" {
+		t.Errorf("rejected verdict = %+v", v)
+	}
+
+	for _, answer := range []string{
+		"Looks good to me!",
+		`{"accepted": false}`,
+		`{"accepted": "maybe"}`,
+	} {
+		if _, err := ParseVerdict(answer); err == nil {
+			t.Errorf("ParseVerdict(%q) should fail", answer)
+		}
+	}
+}
+
+func TestRequestPrompt(t *testing.T) {
+	req := &Request{
+		BeadID:      "gt-42",
+		Title:       "Retry webhooks",
+		Criteria:    "- [ ] retries 3 times\n- [ ] has a test",
+		Description: "ignored when criteria exist",
+		Diff:        "diff --git a/x b/x",
+		TestCommand: "go test ./...",
+		TestOutput:  "FAIL TestRetry",
+		TestFailed:  true,
+	}
+	prompt := req.Prompt()
+	for _, want := range []string{"gt-42: Retry webhooks", "retries 3 times", "go test ./..., FAILED", "FAIL TestRetry", "diff --git"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q", want)
+		}
+	}
+	if strings.Contains(prompt, "ignored when criteria exist") {
+		t.Error("description should not be used when criteria exist")
+	}
+
+	req = &Request{BeadID: "gt-1", Description: "Make login faster"}
+	if !req.HasCriteria() || !strings.Contains(req.Prompt(), "verify against the bead description:\nMake login faster") {
+		t.Error("description should stand in for missing criteria")
+	}
+	if (&Request{}).HasCriteria() {
+		t.Error("empty request has nothing to verify against")
+	}
+}
+
+func TestPromptTruncates(t *testing.T) {
+	req := &Request{Criteria: "x", Diff: strings.Repeat("d", maxDiffBytes+100), TestCommand: "t", TestOutput: strings.Repeat("o", maxTestBytes) + "SUMMARY"}
+	prompt := req.Prompt()
+	if !strings.Contains(prompt, "100 more bytes truncated") {
+		t.Error("long diff should be truncated")
+	}
+	if !strings.Contains(prompt, "SUMMARY") || !strings.Contains(prompt, "earlier bytes truncated") {
+		t.Error("test output should keep its tail")
+	}
+}
+
+func TestBounceMessage(t *testing.T) {
+	msg := (&Verdict{Gaps: []string{"no test", "wrong default"}, Summary: "two gaps"}).BounceMessage("gt-42")
+	for _, want := range []string{"gt-42", "1. no test", "2. wrong default", "two gaps", "--status ESCALATED"} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("bounce message missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Package views defines saved bead queries: named filters kept in town
+// settings and run across every rig with gt view, shown as dashboard panels
+// and offered as pick lists by gt sling --interactive.
+package views
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Me is the assignee placeholder for whoever runs the view.
+const Me = "$me"
+
+// View is one saved query.
+//
+//	"views": {
+//	  "ready-frontend":  {"ready": true, "label": "frontend", "panel": true},
+//	  "stalled-over-2h": {"status": "in_progress", "stale_for": "2h"},
+//	  "my-escalations":  {"label": "gt:escalation", "status": "open", "assignee": "$me"}
+//	}
+type View struct {
+	Description string `json:"description,omitempty"`
+
+	// Query is a text search over bead titles, descriptions and IDs.
+	Query string `json:"query,omitempty"`
+
+	// Ready limits results to beads with no open blockers (bd ready).
+	Ready bool `json:"ready,omitempty"`
+
+	Status   string `json:"status,omitempty"`
+	Label    string `json:"label,omitempty"`
+	Assignee string `json:"assignee,omitempty"` // "$me" is the caller
+
+	// Rigs limits the view to these rigs ("town" for town beads).
+	// Empty searches every routed database.
+	Rigs []string `json:"rigs,omitempty"`
+
+	// StaleFor keeps only beads not updated for at least this long ("2h").
+	StaleFor string `json:"stale_for,omitempty"`
+
+	// Limit caps the number of results (0 = no cap).
+	Limit int `json:"limit,omitempty"`
+
+	// Panel shows the view on the web dashboard.
+	Panel bool `json:"panel,omitempty"`
+}
+
+// Config is the views section of town settings/config.json, keyed by name.
+type Config map[string]*View
+
+var nameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Validate checks every view.
+func (c Config) Validate() error {
+	for _, name := range c.Names() {
+		if !nameRe.MatchString(name) {
+			return fmt.Errorf("view %q: names use lowercase letters, digits, - and _", name)
+		}
+		if err := c[name].Validate(); err != nil {
+			return fmt.Errorf("view %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// Names returns the view names, sorted.
+func (c Config) Names() []string {
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks one view. A view must filter on something.
+func (v *View) Validate() error {
+	if v == nil {
+		return fmt.Errorf("empty view")
+	}
+	if v.StaleFor != "" {
+		if d, err := time.ParseDuration(v.StaleFor); err != nil || d <= 0 {
+			return fmt.Errorf("stale_for: invalid duration %q", v.StaleFor)
+		}
+	}
+	if v.Limit < 0 {
+		return fmt.Errorf("limit must not be negative")
+	}
+	if v.Query == "" && !v.Ready && v.Status == "" && v.Label == "" && v.Assignee == "" && v.StaleFor == "" {
+		return fmt.Errorf("set at least one of query, ready, status, label, assignee, stale_for")
+	}
+	return nil
+}
+
+// ResolveAssignee returns the assignee filter with $me replaced by me.
+func (v *View) ResolveAssignee(me string) string {
+	return strings.ReplaceAll(v.Assignee, Me, me)
+}
+
+// StaleBefore returns the update time a bead must be older than, or the
+// zero time when the view has no stale_for.
+func (v *View) StaleBefore(now time.Time) time.Time {
+	d, err := time.ParseDuration(v.StaleFor)
+	if v.StaleFor == "" || err != nil {
+		return time.Time{}
+	}
+	return now.Add(-d)
+}
+
+// IncludesRig reports whether the view searches the named rig.
+func (v *View) IncludesRig(rig string) bool {
+	if len(v.Rigs) == 0 {
+		return true
+	}
+	for _, r := range v.Rigs {
+		if r == rig {
+			return true
+		}
+	}
+	return false
+}
+
+// Describe summarizes the view's filters, e.g. "ready, label=frontend".
+func (v *View) Describe() string {
+	var parts []string
+	if v.Query != "" {
+		parts = append(parts, fmt.Sprintf("%q", v.Query))
+	}
+	if v.Ready {
+		parts = append(parts, "ready")
+	}
+	for _, f := range []struct{ key, val string }{
+		{"status", v.Status}, {"label", v.Label}, {"assignee", v.Assignee}, {"stale", v.StaleFor},
+	} {
+		if f.val != "" {
+			parts = append(parts, f.key+"="+f.val)
+		}
+	}
+	if len(v.Rigs) > 0 {
+		parts = append(parts, "rigs="+strings.Join(v.Rigs, ","))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Panel is a view's results as shown on the web dashboard.
+type Panel struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Results     []PanelItem `json:"results"`
+	Error       string      `json:"error,omitempty"`
+}
+
+// PanelItem is one bead in a Panel.
+type PanelItem struct {
+	Rig      string `json:"rig"`
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Status   string `json:"status"`
+	Priority int    `json:"priority"`
+	Assignee string `json:"assignee,omitempty"`
+}
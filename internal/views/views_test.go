@@ -0,0 +1,84 @@
+package views
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"empty", nil, false},
+		{"ready", Config{"ready-frontend": {Ready: true, Label: "frontend"}}, false},
+		{"stale", Config{"stalled": {Status: "in_progress", StaleFor: "2h"}}, false},
+		{"me", Config{"mine": {Assignee: Me}}, false},
+		{"bad name", Config{"Ready Frontend": {Ready: true}}, true},
+		{"nil view", Config{"x": nil}, true},
+		{"no filters", Config{"x": {Description: "everything"}}, true},
+		{"bad stale", Config{"x": {Status: "open", StaleFor: "soon"}}, true},
+		{"zero stale", Config{"x": {Status: "open", StaleFor: "0s"}}, true},
+		{"negative limit", Config{"x": {Status: "open", Limit: -1}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigNames(t *testing.T) {
+	cfg := Config{"b": {Ready: true}, "a": {Ready: true}, "c": {Ready: true}}
+	got := cfg.Names()
+	if len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Errorf("Names() = %v, want [a b c]", got)
+	}
+}
+
+func TestViewResolveAssignee(t *testing.T) {
+	v := &View{Assignee: Me}
+	if got := v.ResolveAssignee("greenplace/crew/mel"); got != "greenplace/crew/mel" {
+		t.Errorf("ResolveAssignee($me) = %q", got)
+	}
+	v = &View{Assignee: "mayor"}
+	if got := v.ResolveAssignee("greenplace/crew/mel"); got != "mayor" {
+		t.Errorf("ResolveAssignee(mayor) = %q", got)
+	}
+}
+
+func TestViewStaleBefore(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if got := (&View{}).StaleBefore(now); !got.IsZero() {
+		t.Errorf("StaleBefore without stale_for = %v, want zero", got)
+	}
+	got := (&View{StaleFor: "2h"}).StaleBefore(now)
+	if want := now.Add(-2 * time.Hour); !got.Equal(want) {
+		t.Errorf("StaleBefore = %v, want %v", got, want)
+	}
+}
+
+func TestViewIncludesRig(t *testing.T) {
+	all := &View{}
+	if !all.IncludesRig("greenplace") {
+		t.Error("view without rigs should include every rig")
+	}
+	some := &View{Rigs: []string{"town", "greenplace"}}
+	if !some.IncludesRig("town") || !some.IncludesRig("greenplace") {
+		t.Error("view should include its listed rigs")
+	}
+	if some.IncludesRig("gastown") {
+		t.Error("view should not include unlisted rigs")
+	}
+}
+
+func TestViewDescribe(t *testing.T) {
+	v := &View{Ready: true, Label: "frontend", StaleFor: "2h", Rigs: []string{"greenplace"}}
+	if got, want := v.Describe(), "ready, label=frontend, stale=2h, rigs=greenplace"; got != want {
+		t.Errorf("Describe() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,165 @@
+// Package watch turns file drops into work.
+//
+// A rule watches a directory for files matching a glob. When a matching file
+// appears (or changes, if the rule opts in), a bead is created from the rule's
+// title/description templates and slung to the rule's rig. Dropping a spec
+// into designs/ is enough to start implementation.
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Defaults for rules that leave fields unset.
+const (
+	DefaultGlob        = "*"
+	DefaultTitle       = "Implement {{.Stem}}"
+	DefaultDescription = "New file in watched folder: {{.RelPath}}\n\n{{.Content}}"
+)
+
+// ErrInvalidRule indicates a malformed watch rule.
+var ErrInvalidRule = errors.New("invalid watch rule")
+
+// Config holds the town's watch rules (settings/config.json "watch").
+type Config struct {
+	Rules []Rule `json:"rules,omitempty"`
+}
+
+// Rule maps files in a directory to beads slung at a rig.
+type Rule struct {
+	// Name identifies the rule in gt watch list/remove and in seen-file state.
+	Name string `json:"name"`
+
+	// Path is the directory to watch, absolute or relative to the town root.
+	Path string `json:"path"`
+
+	// Glob is matched against file base names. Default: "*".
+	Glob string `json:"glob,omitempty"`
+
+	// Recursive also watches subdirectories of Path.
+	Recursive bool `json:"recursive,omitempty"`
+
+	// OnChange re-triggers when a file's content changes, not only when it
+	// first appears.
+	OnChange bool `json:"on_change,omitempty"`
+
+	// Rig is the sling target. Empty creates the bead without slinging.
+	Rig string `json:"rig,omitempty"`
+
+	// Title and Description are text/template strings rendered with File.
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Labels are added to created beads in addition to "gt:task".
+	Labels []string `json:"labels,omitempty"`
+}
+
+// Validate checks rule fields and name uniqueness.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, r := range c.Rules {
+		if err := r.Validate(); err != nil {
+			return err
+		}
+		if seen[r.Name] {
+			return fmt.Errorf("%w: duplicate name %q", ErrInvalidRule, r.Name)
+		}
+		seen[r.Name] = true
+	}
+	return nil
+}
+
+// Find returns the rule with the given name.
+func (c *Config) Find(name string) (Rule, bool) {
+	if c == nil {
+		return Rule{}, false
+	}
+	for _, r := range c.Rules {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Remove deletes the named rule, reporting whether it existed.
+func (c *Config) Remove(name string) bool {
+	if c == nil {
+		return false
+	}
+	for i, r := range c.Rules {
+		if r.Name == name {
+			c.Rules = append(c.Rules[:i], c.Rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks a single rule.
+func (r Rule) Validate() error {
+	if r.Name == "" || strings.ContainsAny(r.Name, " \t/") {
+		return fmt.Errorf("%w: name %q must be non-empty without spaces or slashes", ErrInvalidRule, r.Name)
+	}
+	if r.Path == "" {
+		return fmt.Errorf("%w %q: path is required", ErrInvalidRule, r.Name)
+	}
+	if _, err := filepath.Match(r.glob(), "x"); err != nil {
+		return fmt.Errorf("%w %q: bad glob %q: %v", ErrInvalidRule, r.Name, r.Glob, err)
+	}
+	for _, t := range []string{r.Title, r.Description} {
+		if _, err := parseTemplate(t); err != nil {
+			return fmt.Errorf("%w %q: %v", ErrInvalidRule, r.Name, err)
+		}
+	}
+	return nil
+}
+
+// Dir returns the rule's watched directory as an absolute path.
+func (r Rule) Dir(townRoot string) string {
+	if filepath.IsAbs(r.Path) {
+		return filepath.Clean(r.Path)
+	}
+	return filepath.Join(townRoot, r.Path)
+}
+
+// Matches reports whether path (absolute) is a file this rule cares about.
+func (r Rule) Matches(townRoot, path string) bool {
+	dir := r.Dir(townRoot)
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false
+	}
+	if !r.Recursive && strings.ContainsRune(rel, filepath.Separator) {
+		return false
+	}
+	base := filepath.Base(path)
+	if isTempFile(base) {
+		return false
+	}
+	ok, _ := filepath.Match(r.glob(), base)
+	return ok
+}
+
+func (r Rule) glob() string {
+	if r.Glob == "" {
+		return DefaultGlob
+	}
+	return r.Glob
+}
+
+// isTempFile skips editor swap files and partial downloads.
+func isTempFile(base string) bool {
+	return strings.HasPrefix(base, ".") ||
+		strings.HasSuffix(base, "~") ||
+		strings.HasSuffix(base, ".swp") ||
+		strings.HasSuffix(base, ".tmp") ||
+		strings.HasSuffix(base, ".part") ||
+		strings.HasSuffix(base, ".crdownload")
+}
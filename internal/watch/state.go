@@ -0,0 +1,99 @@
+package watch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// State records which files each rule has already acted on, so restarts and
+// repeated write events do not create duplicate beads.
+type State struct {
+	path string
+	mu   sync.Mutex
+	// Seen maps rule name → file path → content hash.
+	Seen map[string]map[string]string `json:"seen"`
+}
+
+// StatePath returns the watch state file for a town.
+func StatePath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "watch-state.json")
+}
+
+// NewMemoryState returns state that is never persisted (for dry runs).
+func NewMemoryState() *State {
+	return &State{Seen: make(map[string]map[string]string)}
+}
+
+// LoadState reads the state file; a missing file yields empty state.
+func LoadState(townRoot string) (*State, error) {
+	s := &State{path: StatePath(townRoot), Seen: make(map[string]map[string]string)}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("reading watch state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing watch state: %w", err)
+	}
+	if s.Seen == nil {
+		s.Seen = make(map[string]map[string]string)
+	}
+	return s, nil
+}
+
+// Lookup returns the recorded hash for a rule's file.
+func (s *State) Lookup(rule, path string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, ok := s.Seen[rule][path]
+	return h, ok
+}
+
+// Mark records a file's hash and persists the state (unless in-memory).
+func (s *State) Mark(rule, path, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Seen[rule] == nil {
+		s.Seen[rule] = make(map[string]string)
+	}
+	s.Seen[rule][path] = hash
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: state is not sensitive
+		return fmt.Errorf("writing watch state: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// HashFile returns the sha256 of a file's content.
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path) //nolint:gosec // G304: path is under a configured watch dir
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
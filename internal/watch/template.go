@@ -0,0 +1,81 @@
+package watch
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// maxContent bounds how much of a file is inlined into bead descriptions.
+const maxContent = 16 * 1024
+
+// File is the data available to title and description templates.
+type File struct {
+	Path    string // Absolute path
+	RelPath string // Path relative to the town root (or absolute if outside)
+	Name    string // Base name, e.g. "auth-spec.md"
+	Stem    string // Base name without extension, e.g. "auth-spec"
+	Dir     string // Directory containing the file
+	Rule    string // Name of the rule that matched
+	Content string // File content (text files only, truncated)
+}
+
+// NewFile describes path for template rendering.
+func NewFile(townRoot, ruleName, path string) File {
+	rel, err := filepath.Rel(townRoot, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		rel = path
+	}
+	name := filepath.Base(path)
+	f := File{
+		Path:    path,
+		RelPath: rel,
+		Name:    name,
+		Stem:    strings.TrimSuffix(name, filepath.Ext(name)),
+		Dir:     filepath.Dir(path),
+		Rule:    ruleName,
+	}
+	if data, err := os.ReadFile(path); err == nil && utf8.Valid(data) { //nolint:gosec // G304: path is under a configured watch dir
+		if len(data) > maxContent {
+			f.Content = string(data[:maxContent]) + "\n…(truncated)"
+		} else {
+			f.Content = string(data)
+		}
+	}
+	return f
+}
+
+// Render returns the bead title and description for f.
+func (r Rule) Render(f File) (title, description string, err error) {
+	title, err = render(r.Title, DefaultTitle, f)
+	if err != nil {
+		return "", "", err
+	}
+	description, err = render(r.Description, DefaultDescription, f)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(title), strings.TrimSpace(description), nil
+}
+
+func render(text, fallback string, f File) (string, error) {
+	if text == "" {
+		text = fallback
+	}
+	tmpl, err := parseTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, f); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("watch").Option("missingkey=error").Parse(text)
+}
@@ -0,0 +1,235 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRuleMatches(t *testing.T) {
+	town := "/town"
+	r := Rule{Name: "designs", Path: "designs", Glob: "*.md"}
+	rec := Rule{Name: "all", Path: "/specs", Recursive: true}
+
+	tests := []struct {
+		rule Rule
+		path string
+		want bool
+	}{
+		{r, "/town/designs/auth.md", true},
+		{r, "/town/designs/auth.txt", false},
+		{r, "/town/designs/sub/auth.md", false},
+		{r, "/town/designs/.auth.md.swp", false},
+		{r, "/town/other/auth.md", false},
+		{r, "/town/designs", false},
+		{rec, "/specs/a/b/c.json", true},
+		{rec, "/specs/a/notes.md~", false},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Matches(town, tt.path); got != tt.want {
+			t.Errorf("%s.Matches(%q) = %v, want %v", tt.rule.Name, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"valid", Config{Rules: []Rule{{Name: "d", Path: "designs", Glob: "*.md", Title: "Spec: {{.Stem}}"}}}, false},
+		{"missing path", Config{Rules: []Rule{{Name: "d"}}}, true},
+		{"bad name", Config{Rules: []Rule{{Name: "a b", Path: "x"}}}, true},
+		{"bad glob", Config{Rules: []Rule{{Name: "d", Path: "x", Glob: "["}}}, true},
+		{"bad template", Config{Rules: []Rule{{Name: "d", Path: "x", Title: "{{.Stem"}}}, true},
+		{"duplicate", Config{Rules: []Rule{{Name: "d", Path: "x"}, {Name: "d", Path: "y"}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidRule) {
+				t.Errorf("error %v should wrap ErrInvalidRule", err)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	town := t.TempDir()
+	path := filepath.Join(town, "designs", "auth-spec.md")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("Add OAuth login."), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f := NewFile(town, "designs", path)
+
+	title, desc, err := Rule{}.Render(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title != "Implement auth-spec" {
+		t.Errorf("title = %q", title)
+	}
+	if !strings.Contains(desc, "designs/auth-spec.md") || !strings.Contains(desc, "Add OAuth login.") {
+		t.Errorf("description = %q", desc)
+	}
+
+	if _, _, err := (Rule{Title: "{{.Missing}}"}).Render(f); err == nil {
+		t.Error("expected error for unknown template field")
+	}
+}
+
+type recorder struct {
+	mu    sync.Mutex
+	files []string
+	fail  bool
+}
+
+func (r *recorder) trigger(rule Rule, f File) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fail {
+		return errors.New("boom")
+	}
+	r.files = append(r.files, rule.Name+":"+f.Name)
+	return nil
+}
+
+func (r *recorder) got() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.files...)
+}
+
+func newTestWatcher(t *testing.T, rules ...Rule) (*Watcher, *recorder) {
+	t.Helper()
+	town := t.TempDir()
+	state, err := LoadState(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := &recorder{}
+	return &Watcher{TownRoot: town, Rules: rules, State: state, Trigger: rec.trigger}, rec
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProcessDedupesAndOnChange(t *testing.T) {
+	w, rec := newTestWatcher(t,
+		Rule{Name: "once", Path: "designs"},
+		Rule{Name: "changes", Path: "designs", OnChange: true},
+	)
+	path := filepath.Join(w.TownRoot, "designs", "spec.md")
+	writeFile(t, path, "v1")
+
+	w.Process(path)
+	w.Process(path)
+	writeFile(t, path, "v2")
+	w.Process(path)
+
+	want := []string{"once:spec.md", "changes:spec.md", "changes:spec.md"}
+	if got := rec.got(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("triggers = %v, want %v", got, want)
+	}
+
+	// State survives a reload.
+	state, err := LoadState(w.TownRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := state.Lookup("once", path); !ok {
+		t.Error("seen state not persisted")
+	}
+}
+
+func TestProcessRetriesAfterFailure(t *testing.T) {
+	w, rec := newTestWatcher(t, Rule{Name: "d", Path: "designs"})
+	path := filepath.Join(w.TownRoot, "designs", "spec.md")
+	writeFile(t, path, "x")
+
+	rec.fail = true
+	w.Process(path)
+	rec.fail = false
+	w.Process(path)
+
+	if got := rec.got(); len(got) != 1 {
+		t.Errorf("triggers = %v, want one after failed attempt", got)
+	}
+}
+
+func TestScan(t *testing.T) {
+	w, rec := newTestWatcher(t, Rule{Name: "d", Path: "designs", Glob: "*.md"})
+	existing := filepath.Join(w.TownRoot, "designs", "old.md")
+	writeFile(t, existing, "old")
+
+	if err := w.Scan(false); err != nil {
+		t.Fatal(err)
+	}
+	w.Process(existing)
+	if got := rec.got(); len(got) != 0 {
+		t.Errorf("baseline scan should not trigger, got %v", got)
+	}
+
+	w2, rec2 := newTestWatcher(t, Rule{Name: "d", Path: "designs", Glob: "*.md"})
+	writeFile(t, filepath.Join(w2.TownRoot, "designs", "a.md"), "a")
+	writeFile(t, filepath.Join(w2.TownRoot, "designs", "b.txt"), "b")
+	if err := w2.Scan(true); err != nil {
+		t.Fatal(err)
+	}
+	if got := rec2.got(); len(got) != 1 || got[0] != "d:a.md" {
+		t.Errorf("catch-up triggers = %v, want [d:a.md]", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	w, rec := newTestWatcher(t, Rule{Name: "d", Path: "designs", Glob: "*.md", Recursive: true})
+	w.Debounce = 50 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Give the watcher a moment to register directories.
+	time.Sleep(100 * time.Millisecond)
+	writeFile(t, filepath.Join(w.TownRoot, "designs", "new.md"), "spec")
+	if err := os.MkdirAll(filepath.Join(w.TownRoot, "designs", "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	writeFile(t, filepath.Join(w.TownRoot, "designs", "sub", "nested.md"), "spec")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if len(rec.got()) >= 2 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	got := strings.Join(rec.got(), ",")
+	if !strings.Contains(got, "d:new.md") || !strings.Contains(got, "d:nested.md") {
+		t.Errorf("triggers = %q, want new.md and nested.md", got)
+	}
+}
@@ -0,0 +1,211 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is how long a file must be quiet before it is acted on, so
+// half-copied files and editor save bursts produce a single trigger.
+const DefaultDebounce = 2 * time.Second
+
+// TriggerFunc acts on a matched file (create the bead, sling it).
+type TriggerFunc func(rule Rule, file File) error
+
+// Watcher applies rules to file events under a town.
+type Watcher struct {
+	TownRoot string
+	Rules    []Rule
+	State    *State
+	Debounce time.Duration
+	Trigger  TriggerFunc
+
+	// Logf reports trigger failures and skipped files. Optional.
+	Logf func(format string, args ...any)
+}
+
+func (w *Watcher) logf(format string, args ...any) {
+	if w.Logf != nil {
+		w.Logf(format, args...)
+	}
+}
+
+// Scan walks every rule's directory. With catchUp, unseen files are
+// triggered; otherwise they are recorded as seen so only files that arrive
+// after the watcher starts create work.
+func (w *Watcher) Scan(catchUp bool) error {
+	for _, r := range w.Rules {
+		err := filepath.WalkDir(r.Dir(w.TownRoot), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil //nolint:nilerr // unreadable entries are skipped
+			}
+			if d.IsDir() {
+				if path != r.Dir(w.TownRoot) && !r.Recursive {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !r.Matches(w.TownRoot, path) {
+				return nil
+			}
+			if catchUp {
+				w.processRule(r, path)
+				return nil
+			}
+			if _, seen := w.State.Lookup(r.Name, path); !seen {
+				if hash, err := HashFile(path); err == nil {
+					return w.State.Mark(r.Name, path, hash)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Process applies every matching rule to path.
+func (w *Watcher) Process(path string) {
+	for _, r := range w.Rules {
+		if r.Matches(w.TownRoot, path) {
+			w.processRule(r, path)
+		}
+	}
+}
+
+func (w *Watcher) processRule(r Rule, path string) {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return
+	}
+	hash, err := HashFile(path)
+	if err != nil {
+		w.logf("%s: hashing %s: %v", r.Name, path, err)
+		return
+	}
+	if prev, seen := w.State.Lookup(r.Name, path); seen && (!r.OnChange || prev == hash) {
+		return
+	}
+	if err := w.Trigger(r, NewFile(w.TownRoot, r.Name, path)); err != nil {
+		w.logf("%s: %s: %v", r.Name, path, err)
+		return
+	}
+	if err := w.State.Mark(r.Name, path, hash); err != nil {
+		w.logf("%s: recording %s: %v", r.Name, path, err)
+	}
+}
+
+// Run watches rule directories until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) error {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating file watcher: %w", err)
+	}
+	defer fw.Close()
+
+	for _, r := range w.Rules {
+		dir := r.Dir(w.TownRoot)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("creating watch directory %s: %w", dir, err)
+		}
+		if err := w.addDir(fw, dir, r.Recursive); err != nil {
+			return err
+		}
+	}
+
+	debounce := w.Debounce
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+	pending := make(map[string]*time.Timer)
+	ready := make(chan string, 64)
+	defer func() {
+		for _, t := range pending {
+			t.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-fw.Errors:
+			if !ok {
+				return nil
+			}
+			w.logf("watcher error: %v", err)
+		case event, ok := <-fw.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Rename) == 0 {
+				continue
+			}
+			path := event.Name
+			if info, err := os.Stat(path); err == nil && info.IsDir() {
+				if event.Op&fsnotify.Create != 0 && w.coversRecursively(path) {
+					_ = w.addDir(fw, path, true)
+				}
+				continue
+			}
+			if t, ok := pending[path]; ok {
+				t.Reset(debounce)
+				continue
+			}
+			pending[path] = time.AfterFunc(debounce, func() {
+				select {
+				case ready <- path:
+				case <-ctx.Done():
+				}
+			})
+		case path := <-ready:
+			delete(pending, path)
+			w.Process(path)
+		}
+	}
+}
+
+// addDir watches dir, and its subdirectories when recursive.
+func (w *Watcher) addDir(fw *fsnotify.Watcher, dir string, recursive bool) error {
+	if !recursive {
+		if err := fw.Add(dir); err != nil {
+			return fmt.Errorf("watching %s: %w", dir, err)
+		}
+		return nil
+	}
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil //nolint:nilerr // unreadable entries are skipped
+		}
+		if path != dir && isTempFile(d.Name()) {
+			return filepath.SkipDir
+		}
+		if err := fw.Add(path); err != nil {
+			return fmt.Errorf("watching %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// coversRecursively reports whether a new directory falls under a recursive rule.
+func (w *Watcher) coversRecursively(dir string) bool {
+	for _, r := range w.Rules {
+		if !r.Recursive {
+			continue
+		}
+		rel, err := filepath.Rel(r.Dir(w.TownRoot), dir)
+		if err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/rbac"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// checkAccess applies the town's RBAC policy to a dashboard action. The
+// dashboard acts as the operator who started it ($GT_OPERATOR, then $USER),
+// so a viewer-run dashboard cannot remove rigs or change policy.
+// Commands run through gt are checked again by the CLI itself; this check
+// covers handlers that call bd directly and gives a clean 403.
+func (h *APIHandler) checkAccess(w http.ResponseWriter, command string, args []string) bool {
+	townRoot, err := workspace.Find(h.workDir)
+	if err != nil || townRoot == "" {
+		return true
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil || !settings.Access.IsEnforced() {
+		return true
+	}
+	if err := settings.Access.Check(rbac.OperatorFromEnv(), command, rbac.TargetRig(townRoot, args)); err != nil {
+		h.sendError(w, err.Error(), http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -14,9 +14,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/beadlink"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/standup"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/views"
 )
 
 // CommandRequest is the JSON request body for /api/run.
@@ -121,6 +124,8 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleMailRead(w, r)
 	case path == "/mail/send" && r.Method == http.MethodPost:
 		h.handleMailSend(w, r)
+	case path == "/mail/decide" && r.Method == http.MethodPost:
+		h.handleMailDecide(w, r)
 	case path == "/issues/show" && r.Method == http.MethodGet:
 		h.handleIssueShow(w, r)
 	case path == "/issues/create" && r.Method == http.MethodPost:
@@ -133,6 +138,10 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handlePRShow(w, r)
 	case path == "/crew" && r.Method == http.MethodGet:
 		h.handleCrew(w, r)
+	case path == "/standup" && r.Method == http.MethodGet:
+		h.handleStandup(w, r)
+	case path == "/views" && r.Method == http.MethodGet:
+		h.handleViews(w, r)
 	case path == "/ready" && r.Method == http.MethodGet:
 		h.handleReady(w, r)
 	case path == "/events" && r.Method == http.MethodGet:
@@ -184,6 +193,11 @@ func (h *APIHandler) handleRun(w http.ResponseWriter, r *http.Request) {
 	// Sanitize args
 	args = SanitizeArgs(args)
 
+	// Enforce the town access policy
+	if !h.checkAccess(w, extractBaseCommand(req.Command), args) {
+		return
+	}
+
 	// Execute command
 	start := time.Now()
 	output, err := h.runGtCommand(r.Context(), timeout, args)
@@ -291,6 +305,9 @@ type MailMessage struct {
 	Priority  string `json:"priority,omitempty"`
 	ThreadID  string `json:"thread_id,omitempty"`
 	ReplyTo   string `json:"reply_to,omitempty"`
+	// Type and Fields describe structured mail (question, report, approval).
+	Type   string            `json:"type,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
 }
 
 // MailInboxResponse is the response for /api/mail/inbox.
@@ -504,14 +521,19 @@ func (h *APIHandler) handleMailRead(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	output, err := h.runGtCommand(r.Context(), 10*time.Second, []string{"mail", "read", msgID})
-	if err != nil {
-		h.sendError(w, "Failed to read message: "+err.Error(), http.StatusInternalServerError)
-		return
-	}
+	// Prefer JSON, which carries the type and fields of structured mail.
+	var msg MailMessage
+	output, err := h.runGtCommand(r.Context(), 10*time.Second, []string{"mail", "read", "--json", msgID})
+	if err != nil || json.Unmarshal([]byte(output), &msg) != nil {
+		output, err = h.runGtCommand(r.Context(), 10*time.Second, []string{"mail", "read", msgID})
+		if err != nil {
+			h.sendError(w, "Failed to read message: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-	// Parse the message output
-	msg := parseMailReadOutput(output, msgID)
+		// Parse the message output
+		msg = parseMailReadOutput(output, msgID)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(msg)
@@ -588,6 +610,58 @@ func (h *APIHandler) handleMailSend(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// MailDecideRequest is the request body for /api/mail/decide.
+type MailDecideRequest struct {
+	ID       string `json:"id"`
+	Decision string `json:"decision"` // "approve" or "reject"
+	Note     string `json:"note,omitempty"`
+}
+
+// handleMailDecide approves or rejects an approval request.
+func (h *APIHandler) handleMailDecide(w http.ResponseWriter, r *http.Request) {
+	var req MailDecideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !isValidID(req.ID) {
+		h.sendError(w, "Invalid message ID format", http.StatusBadRequest)
+		return
+	}
+	if req.Decision != "approve" && req.Decision != "reject" {
+		h.sendError(w, "Decision must be approve or reject", http.StatusBadRequest)
+		return
+	}
+	if req.Decision == "reject" && strings.TrimSpace(req.Note) == "" {
+		h.sendError(w, "A reason is required to reject", http.StatusBadRequest)
+		return
+	}
+	const maxNoteLen = 10_000
+	if len(req.Note) > maxNoteLen || strings.Contains(req.Note, "\x00") {
+		h.sendError(w, "Invalid note", http.StatusBadRequest)
+		return
+	}
+
+	args := []string{"mail", req.Decision}
+	if req.Note != "" {
+		args = append(args, "-m", req.Note)
+	}
+	args = append(args, "--", req.ID)
+
+	output, err := h.runGtCommand(r.Context(), 30*time.Second, args)
+	if err != nil {
+		h.sendError(w, "Failed to "+req.Decision+": "+err.Error()+"\n"+output, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "Decision sent",
+		"output":  output,
+	})
+}
+
 // parseMailInboxText parses text output from "gt mail inbox".
 func parseMailInboxText(output string) []MailMessage {
 	var messages []MailMessage
@@ -984,18 +1058,26 @@ func parseJSONPaths(jsonStr string) []string {
 
 // IssueShowResponse is the response for /api/issues/show.
 type IssueShowResponse struct {
-	ID          string   `json:"id"`
-	Title       string   `json:"title"`
-	Type        string   `json:"type,omitempty"`
-	Status      string   `json:"status,omitempty"`
-	Priority    string   `json:"priority,omitempty"`
-	Owner       string   `json:"owner,omitempty"`
-	Description string   `json:"description,omitempty"`
-	Created     string   `json:"created,omitempty"`
-	Updated     string   `json:"updated,omitempty"`
-	DependsOn   []string `json:"depends_on,omitempty"`
-	Blocks      []string `json:"blocks,omitempty"`
-	RawOutput   string   `json:"raw_output"`
+	ID          string      `json:"id"`
+	Title       string      `json:"title"`
+	Type        string      `json:"type,omitempty"`
+	Status      string      `json:"status,omitempty"`
+	Priority    string      `json:"priority,omitempty"`
+	Owner       string      `json:"owner,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Created     string      `json:"created,omitempty"`
+	Updated     string      `json:"updated,omitempty"`
+	DependsOn   []string    `json:"depends_on,omitempty"`
+	Blocks      []string    `json:"blocks,omitempty"`
+	Links       []IssueLink `json:"links,omitempty"`
+	RawOutput   string      `json:"raw_output"`
+}
+
+// IssueLink is a typed link from an issue to another (gt bead link).
+type IssueLink struct {
+	Type   string `json:"type"`
+	Phrase string `json:"phrase"`
+	ID     string `json:"id"`
 }
 
 // handleIssueShow returns details for a specific issue/bead.
@@ -1062,6 +1144,10 @@ type IssueCreateResponse struct {
 
 // handleIssueCreate creates a new issue via bd create.
 func (h *APIHandler) handleIssueCreate(w http.ResponseWriter, r *http.Request) {
+	if !h.checkAccess(w, "issue create", nil) {
+		return
+	}
+
 	var req IssueCreateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.sendError(w, "Invalid request body", http.StatusBadRequest)
@@ -1154,6 +1240,10 @@ type IssueCloseRequest struct {
 
 // handleIssueClose closes an issue via bd close.
 func (h *APIHandler) handleIssueClose(w http.ResponseWriter, r *http.Request) {
+	if !h.checkAccess(w, "issue close", nil) {
+		return
+	}
+
 	var req IssueCloseRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.sendError(w, "Invalid request body", http.StatusBadRequest)
@@ -1197,6 +1287,10 @@ type IssueUpdateRequest struct {
 
 // handleIssueUpdate updates issue fields via bd update.
 func (h *APIHandler) handleIssueUpdate(w http.ResponseWriter, r *http.Request) {
+	if !h.checkAccess(w, "issue update", nil) {
+		return
+	}
+
 	var req IssueUpdateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.sendError(w, "Invalid request body", http.StatusBadRequest)
@@ -1288,7 +1382,7 @@ func (h *APIHandler) runBdCommand(ctx context.Context, timeout time.Duration, ar
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
+	err := beads.RunCmdContext(ctx, cmd)
 
 	output := stdout.String()
 	if stderr.Len() > 0 {
@@ -1324,6 +1418,7 @@ func parseIssueShowJSON(output string) (IssueShowResponse, bool) {
 		UpdatedAt   string   `json:"updated_at"`
 		DependsOn   []string `json:"depends_on,omitempty"`
 		Blocks      []string `json:"blocks,omitempty"`
+		Labels      []string `json:"labels,omitempty"`
 	}
 	if err := json.Unmarshal([]byte(output), &items); err != nil || len(items) == 0 {
 		return IssueShowResponse{}, false
@@ -1335,6 +1430,11 @@ func parseIssueShowJSON(output string) (IssueShowResponse, bool) {
 		priority = fmt.Sprintf("P%d", item.Priority)
 	}
 
+	var links []IssueLink
+	for _, l := range beadlink.FromLabels(item.Labels) {
+		links = append(links, IssueLink{Type: l.Type, Phrase: beadlink.Phrase(l.Type), ID: l.Bead})
+	}
+
 	return IssueShowResponse{
 		ID:          item.ID,
 		Title:       item.Title,
@@ -1347,6 +1447,7 @@ func parseIssueShowJSON(output string) (IssueShowResponse, bool) {
 		Updated:     item.UpdatedAt,
 		DependsOn:   item.DependsOn,
 		Blocks:      item.Blocks,
+		Links:       links,
 		RawOutput:   output,
 	}, true
 }
@@ -1754,6 +1855,43 @@ func (h *APIHandler) handleCrew(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
+// handleStandup returns the town standup report (gt standup --json).
+// Errors yield an empty report so the panel shows "no workers" rather than
+// failing.
+func (h *APIHandler) handleStandup(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 45*time.Second)
+	defer cancel()
+
+	report := standup.Report{Entries: make([]standup.Entry, 0)}
+	if output, err := h.runGtCommand(ctx, 40*time.Second, []string{"standup", "--json"}); err == nil {
+		_ = json.Unmarshal([]byte(output), &report)
+	}
+	if report.Entries == nil {
+		report.Entries = make([]standup.Entry, 0)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// handleViews returns the results of every saved view marked as a dashboard
+// panel (gt view --panels --json). Errors yield an empty list.
+func (h *APIHandler) handleViews(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 35*time.Second)
+	defer cancel()
+
+	panels := make([]views.Panel, 0)
+	if output, err := h.runGtCommand(ctx, 30*time.Second, []string{"view", "--panels", "--json"}); err == nil {
+		_ = json.Unmarshal([]byte(output), &panels)
+	}
+	if panels == nil {
+		panels = make([]views.Panel, 0)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(panels)
+}
+
 // detectCrewState determines crew member state from tmux session.
 // Returns: state (spinning/finished/questions/ready), lastActive string, session status
 func (h *APIHandler) detectCrewState(ctx context.Context, sessionName, hook string) (string, string, string) {
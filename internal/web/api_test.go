@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/standup"
+	"github.com/steveyegge/gastown/internal/views"
 )
 
 func TestValidateCommand(t *testing.T) {
@@ -487,6 +489,62 @@ func TestAPIHandler_Crew(t *testing.T) {
 	}
 }
 
+func TestAPIHandler_Standup(t *testing.T) {
+	handler := &APIHandler{
+		gtPath:            "false", // fast-failing stub — standup handler returns an empty report on error
+		workDir:           t.TempDir(),
+		defaultRunTimeout: 5 * time.Second,
+		maxRunTimeout:     10 * time.Second,
+		cmdSem:            make(chan struct{}, maxConcurrentCommands),
+		csrfToken:         "test-token",
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/standup", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("GET /api/standup status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var resp standup.Report
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Entries == nil {
+		t.Error("Expected Entries field to be initialized")
+	}
+}
+
+func TestAPIHandler_Views(t *testing.T) {
+	handler := &APIHandler{
+		gtPath:            "false", // fast-failing stub — views handler returns an empty list on error
+		workDir:           t.TempDir(),
+		defaultRunTimeout: 5 * time.Second,
+		maxRunTimeout:     10 * time.Second,
+		cmdSem:            make(chan struct{}, maxConcurrentCommands),
+		csrfToken:         "test-token",
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/views", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("GET /api/views status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var resp []views.Panel
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp == nil {
+		t.Error("Expected an empty list, got null")
+	}
+}
+
 func TestAPIHandler_Ready(t *testing.T) {
 	handler := &APIHandler{
 		gtPath:            "false", // fast-failing stub — ready handler gracefully returns empty on error
@@ -606,6 +664,33 @@ func TestAPIHandler_IssueCreate_InvalidJSON(t *testing.T) {
 
 // --- parseIssueShowOutput edge-case tests (issue #1228: panic-safe string indexing) ---
 
+func TestAPIHandler_MailDecide_Validation(t *testing.T) {
+	handler := NewAPIHandler(30*time.Second, 60*time.Second, "test-token")
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid id", `{"id": "../etc", "decision": "approve"}`},
+		{"unknown decision", `{"id": "hq-abc", "decision": "maybe"}`},
+		{"reject without reason", `{"id": "hq-abc", "decision": "reject"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/mail/decide", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Dashboard-Token", "test-token")
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("POST /api/mail/decide status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
 func TestParseIssueShowOutput_EmptyOutput(t *testing.T) {
 	resp := parseIssueShowOutput("", "gt-123")
 	if resp.ID != "gt-123" {
@@ -836,6 +921,18 @@ func TestParseIssueShowJSON_ZeroPriority(t *testing.T) {
 	}
 }
 
+func TestParseIssueShowJSON_Links(t *testing.T) {
+	input := `[{"id": "gt-bug", "title": "Crash", "labels": ["gt:task", "link:caused-by:gt-abc"]}]`
+	resp, ok := parseIssueShowJSON(input)
+	if !ok {
+		t.Fatal("parseIssueShowJSON returned ok=false")
+	}
+	want := IssueLink{Type: "caused-by", Phrase: "caused by", ID: "gt-abc"}
+	if len(resp.Links) != 1 || resp.Links[0] != want {
+		t.Errorf("Links = %+v, want [%+v]", resp.Links, want)
+	}
+}
+
 func TestParseIssueShowJSON_InvalidInputs(t *testing.T) {
 	tests := []struct {
 		name  string
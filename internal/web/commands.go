@@ -34,11 +34,14 @@ var AllowedCommands = map[string]CommandMeta{
 	"mail inbox":  {Safe: true, Desc: "Check inbox", Category: "Mail"},
 	"mail check":  {Safe: true, Desc: "Check for new mail", Category: "Mail"},
 	"mail peek":   {Safe: true, Desc: "Peek at message", Category: "Mail", Args: "<message-id>"},
+	"mail needs-human": {Safe: true, Desc: "Questions and approvals awaiting a decision", Category: "Mail"},
 	"rig list":    {Safe: true, Desc: "List rigs", Category: "Rigs"},
 	"rig show":    {Safe: true, Desc: "Show rig details", Category: "Rigs", Args: "<rig-name>", ArgType: "rigs"},
 	"doctor":      {Safe: true, Desc: "Health check", Category: "Diagnostics"},
 	"hooks list":  {Safe: true, Desc: "List hooks", Category: "Hooks"},
 	"activity":    {Safe: true, Desc: "Show recent activity", Category: "Status"},
+	"standup":     {Safe: true, Desc: "Town standup: progress per worker", Category: "Status"},
+	"view":        {Safe: true, Desc: "Run a saved bead view", Category: "Status", Args: "[name]"},
 	"info":        {Safe: true, Desc: "Show workspace info", Category: "Status"},
 	"log":         {Safe: true, Desc: "View logs", Category: "Diagnostics"},
 	"audit":       {Safe: true, Desc: "View audit log", Category: "Diagnostics"},
@@ -58,6 +61,8 @@ var AllowedCommands = map[string]CommandMeta{
 	"mail mark-read": {Confirm: false, Desc: "Mark as read", Category: "Mail", Args: "<message-id>", ArgType: "messages"},
 	"mail archive":   {Confirm: false, Desc: "Archive message", Category: "Mail", Args: "<message-id>", ArgType: "messages"},
 	"mail reply":     {Confirm: true, Desc: "Reply to message", Category: "Mail", Args: "<message-id> -m <message>", ArgType: "messages"},
+	"mail approve":   {Confirm: true, Desc: "Approve an approval request", Category: "Mail", Args: "<message-id>", ArgType: "messages"},
+	"mail reject":    {Confirm: true, Desc: "Reject an approval request", Category: "Mail", Args: "<message-id> -m <reason>", ArgType: "messages"},
 
 	// Escalation actions
 	"escalate ack":      {Confirm: true, Desc: "Acknowledge escalation", Category: "Escalations", Args: "<escalation-id>", ArgType: "escalations"},
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/activity"
+	"github.com/steveyegge/gastown/internal/beadlink"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
@@ -1638,7 +1639,7 @@ func (f *LiveConvoyFetcher) FetchIssues() ([]IssueRow, error) {
 		// Format labels (skip internal labels)
 		var displayLabels []string
 		for _, label := range bead.Labels {
-			if !strings.HasPrefix(label, "gt:") && !strings.HasPrefix(label, "internal:") {
+			if !strings.HasPrefix(label, "gt:") && !strings.HasPrefix(label, "internal:") && !strings.HasPrefix(label, beadlink.LabelPrefix) {
 				displayLabels = append(displayLabels, label)
 			}
 		}
@@ -147,7 +147,9 @@
         }
         // Reload dynamic panels after swap (handled via window functions)
         if (window.refreshCrewPanel) window.refreshCrewPanel();
+        if (window.refreshStandupPanel) window.refreshStandupPanel();
         if (window.refreshReadyPanel) window.refreshReadyPanel();
+        if (window.refreshViewsPanel) window.refreshViewsPanel();
         // Update connection status indicator after morph
         updateConnectionStatus(window.sseConnected ? 'live' : 'reconnecting');
     });
@@ -1100,6 +1102,165 @@
             });
     }
 
+    // ============================================
+    // STANDUP PANEL
+    // ============================================
+    // Compiling the standup captures every worker's pane, so the report is
+    // fetched at most every few minutes and re-rendered from cache on swaps.
+    var STANDUP_MAX_AGE_MS = 5 * 60 * 1000;
+    var standupCache = null;
+    var standupFetchedAt = 0;
+
+    function loadStandup() {
+        if (standupCache && Date.now() - standupFetchedAt < STANDUP_MAX_AGE_MS) {
+            renderStandup(standupCache);
+            return;
+        }
+        fetch('/api/standup')
+            .then(function(r) { return r.json(); })
+            .then(function(data) {
+                standupCache = data;
+                standupFetchedAt = Date.now();
+                renderStandup(data);
+            })
+            .catch(function(err) {
+                var loading = document.getElementById('standup-loading');
+                if (loading) loading.textContent = 'Failed to load standup';
+                console.error('Standup load error:', err);
+            });
+    }
+
+    function renderStandup(data) {
+        var loading = document.getElementById('standup-loading');
+        var table = document.getElementById('standup-table');
+        var tbody = document.getElementById('standup-tbody');
+        var empty = document.getElementById('standup-empty');
+        var count = document.getElementById('standup-count');
+
+        if (!loading || !table || !tbody) return;
+        loading.style.display = 'none';
+
+        var entries = data.entries || [];
+        if (entries.length === 0) {
+            table.style.display = 'none';
+            empty.style.display = 'block';
+            if (count) count.textContent = '0';
+            return;
+        }
+
+        table.style.display = 'table';
+        empty.style.display = 'none';
+        tbody.innerHTML = '';
+        entries.forEach(function(entry) {
+            var tr = document.createElement('tr');
+            var progress;
+            if (entry.note) {
+                progress = '<span class="standup-note">' + escapeHtml(entry.note) + '</span>';
+            } else {
+                tr.className = 'standup-missing';
+                progress = '<span class="standup-activity">no note' +
+                    (entry.activity ? ' — last seen: ' + escapeHtml(entry.activity) : '') + '</span>';
+            }
+            var hook = entry.hook ? escapeHtml(entry.hook) : '—';
+            if (entry.hook_title) hook = '<span title="' + escapeHtml(entry.hook_title) + '">' + hook + '</span>';
+            tr.innerHTML =
+                '<td><span class="crew-name">' + escapeHtml(entry.agent) + '</span></td>' +
+                '<td><span class="crew-hook">' + hook + '</span></td>' +
+                '<td>' + progress + '</td>' +
+                '<td class="crew-activity">' + (entry.note_at ? formatStandupAge(entry.note_at) : '—') + '</td>';
+            tbody.appendChild(tr);
+        });
+        if (count) count.textContent = entries.length;
+    }
+
+    function formatStandupAge(ts) {
+        var mins = Math.floor((Date.now() - new Date(ts).getTime()) / 60000);
+        if (mins < 1) return 'just now';
+        if (mins < 60) return mins + 'm ago';
+        if (mins < 1440) return Math.floor(mins / 60) + 'h ago';
+        return Math.floor(mins / 1440) + 'd ago';
+    }
+
+    loadStandup();
+    window.refreshStandupPanel = loadStandup;
+
+    // ============================================
+    // SAVED VIEWS PANEL
+    // ============================================
+    // Views marked "panel": true in town settings. Each view queries every
+    // rig's beads, so results are cached for a minute across swaps.
+    var VIEWS_MAX_AGE_MS = 60 * 1000;
+    var viewsCache = null;
+    var viewsFetchedAt = 0;
+
+    function loadViews() {
+        if (viewsCache && Date.now() - viewsFetchedAt < VIEWS_MAX_AGE_MS) {
+            renderViews(viewsCache);
+            return;
+        }
+        fetch('/api/views')
+            .then(function(r) { return r.json(); })
+            .then(function(data) {
+                viewsCache = data;
+                viewsFetchedAt = Date.now();
+                renderViews(data);
+            })
+            .catch(function(err) {
+                var loading = document.getElementById('views-loading');
+                if (loading) loading.textContent = 'Failed to load views';
+                console.error('Views load error:', err);
+            });
+    }
+
+    function renderViews(panels) {
+        var loading = document.getElementById('views-loading');
+        var table = document.getElementById('views-table');
+        var tbody = document.getElementById('views-tbody');
+        var empty = document.getElementById('views-empty');
+        var count = document.getElementById('views-count');
+
+        if (!loading || !table || !tbody) return;
+        loading.style.display = 'none';
+
+        panels = panels || [];
+        if (panels.length === 0) {
+            table.style.display = 'none';
+            empty.style.display = 'block';
+            if (count) count.textContent = '0';
+            return;
+        }
+
+        table.style.display = 'table';
+        empty.style.display = 'none';
+        tbody.innerHTML = '';
+        var total = 0;
+        panels.forEach(function(panel) {
+            var results = panel.results || [];
+            total += results.length;
+            var head = document.createElement('tr');
+            head.className = 'views-heading';
+            head.innerHTML = '<td colspan="4"><span class="views-name">' + escapeHtml(panel.name) + '</span>' +
+                ' <span class="count">' + results.length + '</span>' +
+                (panel.description ? ' <span class="views-desc">' + escapeHtml(panel.description) + '</span>' : '') +
+                (panel.error ? ' <span class="views-error">' + escapeHtml(panel.error) + '</span>' : '') + '</td>';
+            tbody.appendChild(head);
+            results.forEach(function(item) {
+                var tr = document.createElement('tr');
+                tr.innerHTML =
+                    '<td><span class="crew-hook">' + escapeHtml(item.id) + '</span></td>' +
+                    '<td>' + escapeHtml(item.title) + '</td>' +
+                    '<td class="crew-activity">' + escapeHtml(item.rig) + '</td>' +
+                    '<td class="crew-activity">P' + item.priority + ' ' + escapeHtml(item.status) +
+                    (item.assignee ? ' @' + escapeHtml(item.assignee) : '') + '</td>';
+                tbody.appendChild(tr);
+            });
+        });
+        if (count) count.textContent = total;
+    }
+
+    loadViews();
+    window.refreshViewsPanel = loadViews;
+
     // Track previous crew states for notifications
     var previousCrewStates = {};
     var crewNeedsAttention = 0;
@@ -1934,6 +2095,7 @@
         document.getElementById('mail-detail-from').textContent = from || '';
         document.getElementById('mail-detail-body').textContent = '';
         document.getElementById('mail-detail-time').textContent = '';
+        setApprovalActions(false);
 
         // Hide both list views and compose, show detail
         mailList.style.display = 'none';
@@ -1949,12 +2111,51 @@
                 document.getElementById('mail-detail-from').textContent = msg.from || from;
                 document.getElementById('mail-detail-body').textContent = msg.body || '(no content)';
                 document.getElementById('mail-detail-time').textContent = msg.timestamp || '';
+                // Approval requests get approve/reject buttons
+                setApprovalActions(msg.type === 'approval' && !msg.read);
             })
             .catch(function(err) {
                 document.getElementById('mail-detail-body').textContent = 'Error loading message: ' + err.message;
             });
     }
 
+    function setApprovalActions(visible) {
+        document.getElementById('mail-approve-btn').style.display = visible ? '' : 'none';
+        document.getElementById('mail-reject-btn').style.display = visible ? '' : 'none';
+    }
+
+    function decideApproval(decision) {
+        var note = '';
+        if (decision === 'reject') {
+            note = prompt('Reason for rejecting:');
+            if (!note) return;
+        }
+        fetch('/api/mail/decide', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ id: currentMessageId, decision: decision, note: note })
+        })
+            .then(function(r) { return r.json(); })
+            .then(function(data) {
+                if (data.success) {
+                    showToast('success', decision === 'approve' ? 'Approved' : 'Rejected', 'Reply sent to ' + (currentMessageFrom || 'requester'));
+                    setApprovalActions(false);
+                } else {
+                    showToast('error', 'Decision failed', data.error || 'Unknown error');
+                }
+            })
+            .catch(function(err) {
+                showToast('error', 'Decision failed', err.message);
+            });
+    }
+
+    document.getElementById('mail-approve-btn').addEventListener('click', function() {
+        decideApproval('approve');
+    });
+    document.getElementById('mail-reject-btn').addEventListener('click', function() {
+        decideApproval('reject');
+    });
+
     // Back button from detail view - return to correct tab
     document.getElementById('mail-back-btn').addEventListener('click', function() {
         mailDetail.style.display = 'none';
@@ -2200,6 +2401,8 @@
         document.getElementById('issue-detail-blocks').innerHTML = '';
         document.getElementById('issue-detail-deps').style.display = 'none';
         document.getElementById('issue-detail-blocks-section').style.display = 'none';
+        document.getElementById('issue-detail-links').innerHTML = '';
+        document.getElementById('issue-detail-links-section').style.display = 'none';
 
         // Show detail view
         issuesList.style.display = 'none';
@@ -2268,6 +2471,15 @@
                     }).join(' ');
                     document.getElementById('issue-detail-blocks').innerHTML = blocksHtml;
                 }
+
+                // Links (gt bead link)
+                if (data.links && data.links.length > 0) {
+                    document.getElementById('issue-detail-links-section').style.display = 'block';
+                    var linksHtml = data.links.map(function(link) {
+                        return '<span class="issue-dep-item" data-issue-id="' + escapeHtml(link.id) + '">' + escapeHtml(link.phrase) + ' ' + escapeHtml(link.id) + '</span>';
+                    }).join(' ');
+                    document.getElementById('issue-detail-links').innerHTML = linksHtml;
+                }
             })
             .catch(function(err) {
                 document.getElementById('issue-detail-title-text').textContent = 'Error';
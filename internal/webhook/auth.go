@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrUnauthorized is returned when a request fails signature or token checks.
+var ErrUnauthorized = errors.New("webhook authentication failed")
+
+// Verify checks a delivery against the endpoint's auth mode and secret.
+func Verify(auth, secret string, header http.Header, body []byte) error {
+	switch auth {
+	case AuthGitHub:
+		sig, ok := strings.CutPrefix(header.Get("X-Hub-Signature-256"), "sha256=")
+		if !ok || !validHMAC(secret, body, sig) {
+			return ErrUnauthorized
+		}
+	case AuthSentry:
+		if !validHMAC(secret, body, header.Get("Sentry-Hook-Signature")) {
+			return ErrUnauthorized
+		}
+	case AuthToken:
+		token := header.Get("X-Gastown-Token")
+		if bearer, ok := strings.CutPrefix(header.Get("Authorization"), "Bearer "); ok {
+			token = bearer
+		}
+		if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return ErrUnauthorized
+		}
+	default:
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of body (as GitHub and Sentry send it).
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func validHMAC(secret string, body []byte, sigHex string) bool {
+	got, err := hex.DecodeString(strings.TrimSpace(sigHex))
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	want, _ := hex.DecodeString(Sign(secret, body))
+	return hmac.Equal(got, want)
+}
@@ -0,0 +1,184 @@
+// Package webhook turns inbound HTTP webhooks into beads and slings.
+//
+// Endpoints are served at /hooks/<name> and authenticated with a shared
+// secret (GitHub-style HMAC, Sentry-style HMAC, or a bearer token). Each
+// endpoint has rules that match on payload fields and headers; the first
+// matching rule renders a bead from templates and optionally slings it to a
+// rig. A rule's dedup key suppresses repeat alerts for the same problem.
+// Intake endpoints (Sentry) normalize error-tracker events first, so beads
+// carry the stack trace and group by fingerprint. Dictation endpoints take a
+// text blob, have an agent formulate it into a task, and answer with the
+// bead ID.
+//
+// The daemon runs the server when endpoints are configured in town settings;
+// gt webhook serve runs it in the foreground.
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// DefaultListen is the address used when Config.Listen is empty.
+// Loopback by default: expose it through a reverse proxy or tunnel.
+const DefaultListen = "127.0.0.1:8790"
+
+// Auth modes.
+const (
+	AuthGitHub = "github" // X-Hub-Signature-256: sha256=<hmac hex>
+	AuthSentry = "sentry" // Sentry-Hook-Signature: <hmac hex>
+	AuthToken  = "token"  // Authorization: Bearer <secret> or X-Gastown-Token
+)
+
+// Default templates for rules that leave them unset.
+const (
+	DefaultTitle       = "[{{.Endpoint}}] {{or .Event \"webhook\"}} event"
+	DefaultDescription = "Received on webhook endpoint {{.Endpoint}}.\n\n```json\n{{.Body}}\n```"
+)
+
+// ErrInvalidConfig indicates a malformed webhook configuration.
+var ErrInvalidConfig = errors.New("invalid webhook config")
+
+var endpointNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Config configures inbound webhooks (settings/config.json "webhooks").
+type Config struct {
+	// Listen is the host:port to serve on. Default: 127.0.0.1:8790.
+	Listen string `json:"listen,omitempty"`
+
+	Endpoints []Endpoint `json:"endpoints,omitempty"`
+}
+
+// Endpoint is one URL (/hooks/<name>) with its auth and routing rules.
+type Endpoint struct {
+	Name string `json:"name"`
+
+	// Auth selects how requests are verified: "github", "sentry", or "token".
+	Auth string `json:"auth"`
+
+	// SecretEnv names the environment variable holding the shared secret.
+	// The secret itself is never stored in config.
+	SecretEnv string `json:"secret_env"`
+
+	// Intake parses payloads from an error tracker ("sentry") into .Error:
+	// title, level, culprit, stack trace, and a fingerprint. Rules then
+	// default to error-report templates and dedup by fingerprint.
+	// "dictation" takes text from a dictation tool into .Dictation and has
+	// an agent formulate it into a task title and description.
+	Intake string `json:"intake,omitempty"`
+
+	// Rules are tried in order; the first match handles the request.
+	// Requests matching no rule are acknowledged and ignored.
+	Rules []Rule `json:"rules"`
+}
+
+// Rule maps a matching payload to a bead.
+type Rule struct {
+	// Match requires payload fields (dotted paths, e.g. "workflow_run.conclusion")
+	// or headers ("header:X-GitHub-Event") to equal the given value.
+	// A value of "*" only requires the field to be present.
+	Match map[string]string `json:"match,omitempty"`
+
+	// Rig is the sling target. Empty creates a town-level bead without slinging.
+	Rig string `json:"rig,omitempty"`
+
+	// Title and Description are text/template strings. Fields: .Endpoint,
+	// .Event, .Payload, .Headers, .Body. Use {{get .Payload "a.b"}} for
+	// nested fields.
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Labels are added to created beads in addition to "gt:task".
+	Labels []string `json:"labels,omitempty"`
+
+	// DedupKey is a template; requests rendering the same key within the
+	// dedup window reuse the existing bead instead of creating another.
+	DedupKey string `json:"dedup_key,omitempty"`
+
+	// MaxSlingsPerHour caps how many beads this rule slings per hour.
+	// Beads over the cap are still created, just left unslung, so an alert
+	// storm can't flood an on-call rig. 0 = no cap.
+	MaxSlingsPerHour int `json:"max_slings_per_hour,omitempty"`
+}
+
+// IsEnabled reports whether any endpoint is configured.
+func (c *Config) IsEnabled() bool {
+	return c != nil && len(c.Endpoints) > 0
+}
+
+// ListenAddr returns the configured listen address or the default.
+func (c *Config) ListenAddr() string {
+	if c == nil || c.Listen == "" {
+		return DefaultListen
+	}
+	return c.Listen
+}
+
+// Find returns the endpoint with the given name.
+func (c *Config) Find(name string) (*Endpoint, bool) {
+	if c == nil {
+		return nil, false
+	}
+	for i := range c.Endpoints {
+		if c.Endpoints[i].Name == name {
+			return &c.Endpoints[i], true
+		}
+	}
+	return nil, false
+}
+
+// Validate checks endpoint names, auth modes, and templates.
+func (c *Config) Validate() error {
+	if c == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, ep := range c.Endpoints {
+		if !endpointNameRe.MatchString(ep.Name) {
+			return fmt.Errorf("%w: endpoint name %q must be letters, digits, - or _", ErrInvalidConfig, ep.Name)
+		}
+		if seen[ep.Name] {
+			return fmt.Errorf("%w: duplicate endpoint %q", ErrInvalidConfig, ep.Name)
+		}
+		seen[ep.Name] = true
+		switch ep.Auth {
+		case AuthGitHub, AuthSentry, AuthToken:
+		default:
+			return fmt.Errorf("%w: endpoint %q: auth %q, want github, sentry, or token", ErrInvalidConfig, ep.Name, ep.Auth)
+		}
+		switch ep.Intake {
+		case "", IntakeSentry, IntakeDictation:
+		default:
+			return fmt.Errorf("%w: endpoint %q: intake %q, want sentry or dictation", ErrInvalidConfig, ep.Name, ep.Intake)
+		}
+		if ep.SecretEnv == "" {
+			return fmt.Errorf("%w: endpoint %q: secret_env is required", ErrInvalidConfig, ep.Name)
+		}
+		if len(ep.Rules) == 0 {
+			return fmt.Errorf("%w: endpoint %q has no rules", ErrInvalidConfig, ep.Name)
+		}
+		for i, r := range ep.Rules {
+			if r.MaxSlingsPerHour < 0 {
+				return fmt.Errorf("%w: endpoint %q rule %d: max_slings_per_hour must be >= 0", ErrInvalidConfig, ep.Name, i+1)
+			}
+			for _, t := range []string{r.Title, r.Description, r.DedupKey} {
+				if _, err := parseTemplate(t); err != nil {
+					return fmt.Errorf("%w: endpoint %q rule %d: %v", ErrInvalidConfig, ep.Name, i+1, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Secret returns the endpoint's shared secret from the environment.
+func (ep *Endpoint) Secret() (string, error) {
+	secret := strings.TrimSpace(os.Getenv(ep.SecretEnv))
+	if secret == "" {
+		return "", fmt.Errorf("endpoint %q: $%s is not set", ep.Name, ep.SecretEnv)
+	}
+	return secret, nil
+}
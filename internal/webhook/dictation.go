@@ -0,0 +1,139 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// FormulateTimeout bounds the task-formulation agent. Dictation senders
+// wait for the bead ID, so a slow agent falls back to the raw text.
+const FormulateTimeout = 45 * time.Second
+
+// Default templates for dictation endpoints whose rules leave them unset.
+const (
+	DefaultDictationTitle       = "{{.Dictation.Title}}"
+	DefaultDictationDescription = `{{with .Dictation}}{{if .Description}}{{.Description}}
+
+---
+{{end}}Dictated via {{$.Endpoint}}{{if not .Formulated}} (not formulated; raw text){{end}}:
+
+{{.Text}}{{end}}`
+	DefaultDictationDedupKey = "{{.Dictation.Hash}}"
+)
+
+// dictationFields are the JSON fields dictation tools put their text in.
+var dictationFields = []string{"text", "transcript", "message", "content", "body"}
+
+// Dictation is a text blob from a dictation tool, turned into a task by a
+// formulation agent. Templates see it as .Dictation.
+type Dictation struct {
+	Text        string
+	Hash        string // Dedup key: a retried delivery reuses the bead
+	Title       string
+	Description string
+	Formulated  bool // false when the title is the raw text's first line
+}
+
+// parseDictation takes the text from a JSON payload field or, for non-JSON
+// bodies, the body itself. Returns nil when there is no text.
+func parseDictation(payload map[string]any, body []byte) *Dictation {
+	text := ""
+	if len(payload) > 0 {
+		for _, field := range dictationFields {
+			if s, ok := payload[field].(string); ok && strings.TrimSpace(s) != "" {
+				text = s
+				break
+			}
+		}
+	} else if !json.Valid(body) {
+		text = string(body)
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	d := &Dictation{Text: text, Hash: "dictation:" + shortHash(strings.Join(strings.Fields(text), " "))}
+	d.Title = firstLine(text)
+	return d
+}
+
+// SetTask records the formulation agent's title and description.
+func (d *Dictation) SetTask(title, description string) {
+	d.Title, d.Description, d.Formulated = title, description, true
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return strings.TrimSpace(line)
+}
+
+// Formulator turns dictated text into a task for a rig ("" for town).
+type Formulator interface {
+	Formulate(ctx context.Context, text, rig string) (title, description string, err error)
+}
+
+// CLIFormulator runs gt webhook formulate, which asks an agent.
+type CLIFormulator struct {
+	TownRoot string
+	GTPath   string // default "gt"
+}
+
+// Formulate pipes text to gt webhook formulate and parses its JSON answer.
+func (f *CLIFormulator) Formulate(ctx context.Context, text, rig string) (string, string, error) {
+	args := []string{"webhook", "formulate"}
+	if rig != "" {
+		args = append(args, "--rig", rig)
+	}
+	cmd := exec.CommandContext(ctx, orDefault(f.GTPath, "gt"), args...) //nolint:gosec // G204: rig from config
+	cmd.Dir = f.TownRoot
+	cmd.Stdin = strings.NewReader(text)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", "", fmt.Errorf("gt webhook formulate: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return ParseFormulation(stdout.String())
+}
+
+// ParseFormulation extracts {"title", "description"} from an agent's
+// answer, tolerating prose or code fences around the JSON.
+func ParseFormulation(answer string) (title, description string, err error) {
+	start, end := strings.Index(answer, "{"), strings.LastIndex(answer, "}")
+	if start < 0 || end < start {
+		return "", "", fmt.Errorf("formulation is not a JSON object")
+	}
+	var task struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal([]byte(answer[start:end+1]), &task); err != nil {
+		return "", "", fmt.Errorf("parsing formulation: %w", err)
+	}
+	title = strings.TrimSpace(task.Title)
+	if title == "" {
+		return "", "", fmt.Errorf("formulation has no title")
+	}
+	return title, strings.TrimSpace(task.Description), nil
+}
+
+// formulate runs the server's formulator on d. On failure d keeps the raw
+// text's first line as its title; a dictated idea is never dropped.
+func (s *Server) formulate(endpoint string, d *Dictation, rig string) {
+	if s.Formulator == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, FormulateTimeout)
+	defer cancel()
+	title, description, err := s.Formulator.Formulate(ctx, d.Text, rig)
+	if err != nil {
+		s.logf("webhook %s: formulation failed, using raw text: %v", endpoint, err)
+		return
+	}
+	d.SetTask(title, description)
+}
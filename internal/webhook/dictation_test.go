@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type fakeFormulator struct {
+	rigs []string
+	err  error
+}
+
+func (f *fakeFormulator) Formulate(_ context.Context, text, rig string) (string, string, error) {
+	f.rigs = append(f.rigs, rig)
+	if f.err != nil {
+		return "", "", f.err
+	}
+	return "Add retry to the sync job", "The nightly sync gives up on the first 503.", nil
+}
+
+func TestParseDictation(t *testing.T) {
+	ep := &Endpoint{Name: "phone", Intake: IntakeDictation}
+	for _, body := range []string{
+		"um so the sync job should retry\nwhen it gets a 503",
+		`{"transcript": "um so the sync job should retry\nwhen it gets a 503", "source": "watch"}`,
+	} {
+		d := ep.Decode(http.Header{}, []byte(body)).Dictation
+		if d == nil || d.Title != "um so the sync job should retry" || !strings.HasPrefix(d.Hash, "dictation:") {
+			t.Errorf("Decode(%q).Dictation = %+v", body, d)
+		}
+	}
+	for _, body := range []string{"", "  \n", `{"text": ""}`, `{"other": "x"}`} {
+		if d := ep.Decode(http.Header{}, []byte(body)).Dictation; d != nil {
+			t.Errorf("Decode(%q) should carry no dictation, got %+v", body, d)
+		}
+	}
+}
+
+func TestParseFormulation(t *testing.T) {
+	title, desc, err := ParseFormulation("Sure:\n```json\n{\"title\": \" Fix login \", \"description\": \"Steps\"}\n```")
+	if err != nil || title != "Fix login" || desc != "Steps" {
+		t.Errorf("got %q %q %v", title, desc, err)
+	}
+	for _, answer := range []string{"no idea", `{"description": "x"}`, `{"title": 3}`} {
+		if _, _, err := ParseFormulation(answer); err == nil {
+			t.Errorf("ParseFormulation(%q) should fail", answer)
+		}
+	}
+}
+
+func TestServerDictation(t *testing.T) {
+	ep := &Endpoint{Name: "phone", Intake: IntakeDictation, Rules: []Rule{{Rig: "gastown"}}}
+	fake := &fakeDispatcher{}
+	formulator := &fakeFormulator{}
+	srv := NewServer(t.TempDir(), &Config{Endpoints: []Endpoint{*ep}}, fake, t.Logf)
+	srv.Formulator = formulator
+
+	body := []byte(`{"text": "sync job should retry on 503"}`)
+	resp, code := srv.Handle(ep, ep.Decode(http.Header{}, body))
+	srv.Wait()
+	if code != http.StatusOK || resp.Status != StatusCreated || resp.Bead == "" || resp.Note != "" {
+		t.Fatalf("dictation: code %d resp %+v", code, resp)
+	}
+	if len(fake.created) != 1 || fake.created[0] != "Add retry to the sync job" || formulator.rigs[0] != "gastown" {
+		t.Errorf("created %v, formulated for %v", fake.created, formulator.rigs)
+	}
+	if len(fake.slung) != 1 || fake.slung[0] != resp.Bead+"→gastown" {
+		t.Errorf("slung = %v", fake.slung)
+	}
+
+	// A retried delivery returns the same bead without running the agent again.
+	dup, _ := srv.Handle(ep, ep.Decode(http.Header{}, body))
+	if dup.Status != StatusDuplicate || dup.Bead != resp.Bead || len(formulator.rigs) != 1 {
+		t.Errorf("retry: %+v, formulations %d", dup, len(formulator.rigs))
+	}
+
+	// Without a working agent the raw text still becomes a bead.
+	formulator.err = errors.New("agent timed out")
+	resp, _ = srv.Handle(ep, ep.Decode(http.Header{}, []byte("Call the vendor about the invoice API\nbefore Friday")))
+	srv.Wait()
+	if resp.Status != StatusCreated || resp.Note == "" || fake.created[1] != "Call the vendor about the invoice API" {
+		t.Errorf("fallback: resp %+v created %v", resp, fake.created)
+	}
+
+	rendered, err := Rule{}.Render(ep.Decode(http.Header{}, []byte("raw idea")))
+	if err != nil || !strings.Contains(rendered.Description, "not formulated") || !strings.Contains(rendered.Description, "raw idea") {
+		t.Errorf("raw description = %q, %v", rendered.Description, err)
+	}
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Dispatcher creates and slings the bead for a matched request.
+type Dispatcher interface {
+	// Create makes the bead and returns its ID.
+	Create(ctx context.Context, rule *Rule, r *Rendered, endpoint string) (string, error)
+	// Sling dispatches a created bead to a rig.
+	Sling(ctx context.Context, beadID, rig string) error
+}
+
+// CLIDispatcher shells out to bd and gt, like the daemon's other dispatchers.
+type CLIDispatcher struct {
+	TownRoot string
+	GTPath   string // default "gt"
+	BDPath   string // default "bd"
+
+	// RunBD runs a bd command; nil runs it directly. gt sets it to
+	// beads.RunCmdContext (which this package can't import) so the write is
+	// serialized with gt's other bd calls.
+	RunBD func(ctx context.Context, cmd *exec.Cmd) error
+}
+
+// Create runs bd create in the rig (or town) so the bead gets the right prefix.
+func (d *CLIDispatcher) Create(ctx context.Context, rule *Rule, r *Rendered, endpoint string) (string, error) {
+	labels := append([]string{"gt:task", "webhook:" + endpoint}, rule.Labels...)
+	args := []string{"create", "--json",
+		"--title=" + r.Title,
+		"--labels=" + strings.Join(labels, ","),
+		"--description=" + r.Description,
+	}
+	cmd := exec.CommandContext(ctx, orDefault(d.BDPath, "bd"), args...) //nolint:gosec // G204: args built from config templates
+	cmd.Dir = d.TownRoot
+	if rule.Rig != "" {
+		cmd.Dir = filepath.Join(d.TownRoot, rule.Rig)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	run := d.RunBD
+	if run == nil {
+		run = func(_ context.Context, cmd *exec.Cmd) error { return cmd.Run() }
+	}
+	if err := run(ctx, cmd); err != nil {
+		return "", fmt.Errorf("bd create: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &created); err != nil || created.ID == "" {
+		return "", fmt.Errorf("parsing bd create output: %q", strings.TrimSpace(stdout.String()))
+	}
+	return created.ID, nil
+}
+
+// Sling runs gt sling <bead> <rig>.
+func (d *CLIDispatcher) Sling(ctx context.Context, beadID, rig string) error {
+	cmd := exec.CommandContext(ctx, orDefault(d.GTPath, "gt"), "sling", beadID, rig) //nolint:gosec // G204: bead ID from bd, rig from config
+	cmd.Dir = d.TownRoot
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("gt sling %s %s: %v: %s", beadID, rig, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Intake adapters.
+const (
+	IntakeSentry    = "sentry"
+	IntakeDictation = "dictation" // Plain text or {"text": ...}; see dictation.go
+)
+
+// maxStackFrames bounds the stack trace inlined into beads.
+const maxStackFrames = 30
+
+// Default templates for intake endpoints whose rules leave them unset.
+const (
+	DefaultIntakeTitle       = "[{{.Endpoint}}] {{.Error.Title}}"
+	DefaultIntakeDescription = `{{with .Error}}Production error reported by {{$.Endpoint}}.
+
+Level: {{or .Level "error"}}
+{{if .Project}}Project: {{.Project}}
+{{end}}{{if .Environment}}Environment: {{.Environment}}
+{{end}}{{if .Culprit}}Culprit: {{.Culprit}}
+{{end}}{{if .URL}}Link: {{.URL}}
+{{end}}Fingerprint: {{.Fingerprint}}
+{{if .Message}}
+{{.Message}}
+{{end}}{{if .Stacktrace}}
+Stack trace (innermost first):
+` + "```" + `
+{{.Stacktrace}}
+` + "```" + `
+{{end}}
+Find the root cause, add a regression test, and fix it. Repeat
+occurrences are grouped onto this bead by fingerprint.{{end}}`
+	DefaultIntakeDedupKey = "{{.Error.Fingerprint}}"
+)
+
+// ErrorEvent is an error-tracker event normalized by an intake adapter.
+// Templates see it as .Error.
+type ErrorEvent struct {
+	Fingerprint string // Grouping key: same problem → same bead
+	Title       string
+	Message     string
+	Level       string
+	Culprit     string
+	Project     string
+	Environment string
+	URL         string
+	Stacktrace  string // Formatted, innermost frame first
+}
+
+// Decode builds the Request for a delivery to this endpoint, running the
+// endpoint's intake adapter when one is configured.
+func (ep *Endpoint) Decode(header http.Header, body []byte) *Request {
+	req := NewRequest(ep.Name, header, body)
+	switch ep.Intake {
+	case IntakeSentry:
+		req.Error = parseSentry(req.Payload)
+	case IntakeDictation:
+		req.Dictation = parseDictation(req.Payload, body)
+	}
+	return req
+}
+
+// parseSentry normalizes the payload shapes Sentry sends: integration
+// webhooks (data.event for event alerts, data.error for errors, data.issue
+// for issue resources) and legacy plugin webhooks (top-level event).
+// Returns nil if the payload carries no recognizable event.
+func parseSentry(payload map[string]any) *ErrorEvent {
+	var event, issue map[string]any
+	if data, ok := payload["data"].(map[string]any); ok {
+		event, _ = data["event"].(map[string]any)
+		if event == nil {
+			event, _ = data["error"].(map[string]any)
+		}
+		issue, _ = data["issue"].(map[string]any)
+	} else if legacy, ok := payload["event"].(map[string]any); ok {
+		event = legacy
+		issue = payload // Legacy payloads carry group fields at the top level
+	}
+	if event == nil && issue == nil {
+		return nil
+	}
+
+	first := func(paths ...string) string {
+		for _, p := range paths {
+			for _, src := range []map[string]any{event, issue} {
+				if src == nil {
+					continue
+				}
+				if s, ok := lookupString(src, p); ok && s != "" {
+					return s
+				}
+			}
+		}
+		return ""
+	}
+
+	e := &ErrorEvent{
+		Title:       first("title", "message"),
+		Message:     first("logentry.formatted", "message"),
+		Level:       first("level"),
+		Culprit:     first("culprit"),
+		Project:     first("project_slug", "project.slug", "project_name", "project"),
+		Environment: first("environment"),
+		URL:         first("web_url", "permalink", "url"),
+	}
+	if e.Message == e.Title {
+		e.Message = ""
+	}
+	if event != nil {
+		e.Stacktrace = formatSentryStack(event)
+	}
+	e.Fingerprint = sentryFingerprint(event, issue, e)
+	if e.Title == "" {
+		e.Title = "Unknown error"
+	}
+	return e
+}
+
+// sentryFingerprint prefers Sentry's own grouping (the issue ID), then a
+// custom fingerprint, then a hash of the title and culprit.
+func sentryFingerprint(event, issue map[string]any, e *ErrorEvent) string {
+	if event != nil {
+		if id, ok := lookupString(event, "issue_id"); ok && id != "" {
+			return "sentry-issue:" + id
+		}
+	}
+	if issue != nil {
+		if id, ok := lookupString(issue, "id"); ok && id != "" {
+			return "sentry-issue:" + id
+		}
+	}
+	if event != nil {
+		if parts, ok := event["fingerprint"].([]any); ok && len(parts) > 0 {
+			var custom []string
+			for _, p := range parts {
+				if s, ok := p.(string); ok && s != "{{ default }}" {
+					custom = append(custom, s)
+				}
+			}
+			if len(custom) > 0 {
+				return "fp:" + shortHash(strings.Join(custom, "\x00"))
+			}
+		}
+	}
+	return "fp:" + shortHash(e.Title+"\x00"+e.Culprit)
+}
+
+// formatSentryStack renders exception stack frames, innermost first.
+// Sentry lists frames outermost first.
+func formatSentryStack(event map[string]any) string {
+	values, _ := Lookup(event, "exception.values")
+	exceptions, _ := values.([]any)
+	var b strings.Builder
+	frames, truncated := 0, false
+	// The last exception is the one that was raised; earlier ones are causes.
+	for i := len(exceptions) - 1; i >= 0 && !truncated; i-- {
+		exc, _ := exceptions[i].(map[string]any)
+		if exc == nil {
+			continue
+		}
+		typ, _ := lookupString(exc, "type")
+		val, _ := lookupString(exc, "value")
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s: %s\n", typ, val)
+
+		raw, _ := Lookup(exc, "stacktrace.frames")
+		list, _ := raw.([]any)
+		for j := len(list) - 1; j >= 0; j-- {
+			if frames == maxStackFrames {
+				truncated = true
+				break
+			}
+			f, _ := list[j].(map[string]any)
+			if f == nil {
+				continue
+			}
+			fn, _ := lookupString(f, "function")
+			file, _ := lookupString(f, "filename")
+			if file == "" {
+				file, _ = lookupString(f, "abs_path")
+			}
+			line, _ := lookupString(f, "lineno")
+			fmt.Fprintf(&b, "  at %s (%s:%s)", orDefault(fn, "?"), file, line)
+			if inApp, _ := f["in_app"].(bool); inApp {
+				b.WriteString(" [in app]")
+			}
+			b.WriteString("\n")
+			frames++
+		}
+	}
+	if truncated {
+		b.WriteString("  …\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func shortHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:8])
+}
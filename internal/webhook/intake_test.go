@@ -0,0 +1,166 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sentryEventAlert = `{
+  "action": "triggered",
+  "data": {
+    "event": {
+      "issue_id": "4021",
+      "title": "ZeroDivisionError: division by zero",
+      "level": "error",
+      "culprit": "billing.invoice in total",
+      "environment": "production",
+      "project": 7,
+      "web_url": "https://sentry.example/issues/4021/events/abc/",
+      "exception": {"values": [
+        {"type": "ZeroDivisionError", "value": "division by zero", "stacktrace": {"frames": [
+          {"filename": "app/main.py", "function": "handle", "lineno": 10, "in_app": true},
+          {"filename": "billing/invoice.py", "function": "total", "lineno": 42, "in_app": true}
+        ]}}
+      ]}
+    }
+  }
+}`
+
+func TestParseSentryEventAlert(t *testing.T) {
+	ep := &Endpoint{Name: "sentry", Intake: IntakeSentry}
+	req := ep.Decode(http.Header{"Sentry-Hook-Resource": {"event_alert"}}, []byte(sentryEventAlert))
+	e := req.Error
+	if e == nil {
+		t.Fatal("Decode() did not parse the Sentry event")
+	}
+	if e.Fingerprint != "sentry-issue:4021" {
+		t.Errorf("Fingerprint = %q, want Sentry issue grouping", e.Fingerprint)
+	}
+	if e.Title != "ZeroDivisionError: division by zero" || e.Environment != "production" || e.Project != "7" {
+		t.Errorf("event = %+v", e)
+	}
+	// Innermost frame first.
+	wantStack := "ZeroDivisionError: division by zero\n" +
+		"  at total (billing/invoice.py:42) [in app]\n" +
+		"  at handle (app/main.py:10) [in app]"
+	if e.Stacktrace != wantStack {
+		t.Errorf("Stacktrace =\n%s\nwant\n%s", e.Stacktrace, wantStack)
+	}
+
+	out, err := Rule{}.Render(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Title != "[sentry] ZeroDivisionError: division by zero" {
+		t.Errorf("Title = %q", out.Title)
+	}
+	if out.DedupKey != "sentry-issue:4021" {
+		t.Errorf("DedupKey = %q, want fingerprint", out.DedupKey)
+	}
+	for _, want := range []string{"Environment: production", "at total (billing/invoice.py:42)", "https://sentry.example/issues/4021"} {
+		if !strings.Contains(out.Description, want) {
+			t.Errorf("Description missing %q:\n%s", want, out.Description)
+		}
+	}
+}
+
+func TestParseSentryShapes(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string // Fingerprint; "" = not an error event
+	}{
+		{"issue resource", `{"action":"created","data":{"issue":{"id":"99","title":"Boom","culprit":"x"}}}`, "sentry-issue:99"},
+		{"custom fingerprint", `{"data":{"error":{"title":"Boom","fingerprint":["{{ default }}","payments"]}}}`, "fp:" + shortHash("payments")},
+		{"title hash", `{"data":{"error":{"title":"Boom","culprit":"pay"}}}`, "fp:" + shortHash("Boom\x00pay")},
+		{"legacy plugin", `{"id":"12","project":"web","message":"Boom","event":{"level":"fatal"}}`, "sentry-issue:12"},
+		{"installation ping", `{"action":"created","data":{"installation":{"uuid":"u"}}}`, ""},
+	}
+	for _, tt := range tests {
+		e := parseSentry(NewRequest("sentry", http.Header{}, []byte(tt.payload)).Payload)
+		if tt.want == "" {
+			if e != nil {
+				t.Errorf("%s: parseSentry() = %+v, want nil", tt.name, e)
+			}
+			continue
+		}
+		if e == nil || e.Fingerprint != tt.want {
+			t.Errorf("%s: parseSentry() = %+v, want fingerprint %q", tt.name, e, tt.want)
+		}
+	}
+}
+
+func TestFormatSentryStackTruncates(t *testing.T) {
+	var frames []string
+	for i := 0; i < maxStackFrames+5; i++ {
+		frames = append(frames, fmt.Sprintf(`{"function":"f%d","filename":"a.go","lineno":%d}`, i, i))
+	}
+	payload := `{"data":{"event":{"title":"deep","exception":{"values":[{"type":"E","value":"v","stacktrace":{"frames":[` +
+		strings.Join(frames, ",") + `]}}]}}}}`
+	e := parseSentry(NewRequest("sentry", http.Header{}, []byte(payload)).Payload)
+	if got := strings.Count(e.Stacktrace, "  at "); got != maxStackFrames {
+		t.Errorf("stack has %d frames, want %d", got, maxStackFrames)
+	}
+	if !strings.HasSuffix(e.Stacktrace, "…") {
+		t.Error("truncated stack should end with an ellipsis")
+	}
+}
+
+func TestServerSentryIntake(t *testing.T) {
+	t.Setenv("TEST_SENTRY_SECRET", "s3cret")
+	cfg := &Config{Endpoints: []Endpoint{{
+		Name:      "sentry",
+		Auth:      AuthSentry,
+		SecretEnv: "TEST_SENTRY_SECRET",
+		Intake:    IntakeSentry,
+		Rules:     []Rule{{Rig: "oncall", MaxSlingsPerHour: 2}},
+	}}}
+	fake := &fakeDispatcher{}
+	srv := NewServer(t.TempDir(), cfg, fake, t.Logf)
+	now := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+	srv.now = func() time.Time { return now }
+	ep := &cfg.Endpoints[0]
+
+	alert := func(issueID string) *Request {
+		body := strings.Replace(sentryEventAlert, `"4021"`, `"`+issueID+`"`, 1)
+		return ep.Decode(http.Header{}, []byte(body))
+	}
+
+	// Same fingerprint groups onto one bead.
+	first, _ := srv.Handle(ep, alert("1"))
+	again, _ := srv.Handle(ep, alert("1"))
+	if first.Status != StatusCreated || again.Status != StatusDuplicate || again.Bead != first.Bead {
+		t.Fatalf("grouping: first %+v, again %+v", first, again)
+	}
+
+	// Distinct problems hit the rate cap on the third sling.
+	second, _ := srv.Handle(ep, alert("2"))
+	third, _ := srv.Handle(ep, alert("3"))
+	if second.Rig != "oncall" {
+		t.Errorf("second alert should be slung, got %+v", second)
+	}
+	if third.Status != StatusCreated || third.Rig != "" || third.Note == "" {
+		t.Errorf("third alert should be created but unslung, got %+v", third)
+	}
+
+	// The cap is a sliding hour.
+	now = now.Add(61 * time.Minute)
+	fourth, _ := srv.Handle(ep, alert("4"))
+	if fourth.Rig != "oncall" {
+		t.Errorf("alert after an hour should be slung, got %+v", fourth)
+	}
+
+	// Non-event deliveries are ignored.
+	ping, _ := srv.Handle(ep, ep.Decode(http.Header{}, []byte(`{"action":"created","data":{"installation":{}}}`)))
+	if ping.Status != StatusIgnored {
+		t.Errorf("installation ping = %+v, want ignored", ping)
+	}
+
+	srv.Wait()
+	if len(fake.created) != 4 || len(fake.slung) != 3 {
+		t.Errorf("created %d, slung %d; want 4 created, 3 slung", len(fake.created), len(fake.slung))
+	}
+}
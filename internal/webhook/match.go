@@ -0,0 +1,201 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// maxBodyInDescription bounds how much raw payload is inlined into beads.
+const maxBodyInDescription = 8 * 1024
+
+// Request is a verified webhook delivery, as seen by rules and templates.
+type Request struct {
+	Endpoint string
+	Event    string // X-GitHub-Event / Sentry-Hook-Resource / X-Gastown-Event
+	Headers  map[string]string
+	Payload  map[string]any
+	Body     string // Raw body (pretty-printed when JSON), truncated
+
+	// Error and Dictation are set by intake endpoints (see Endpoint.Decode).
+	Error     *ErrorEvent
+	Dictation *Dictation
+}
+
+// NewRequest decodes a delivery. Non-JSON bodies leave Payload empty.
+func NewRequest(endpoint string, header http.Header, body []byte) *Request {
+	req := &Request{Endpoint: endpoint, Headers: make(map[string]string), Payload: map[string]any{}}
+	for k := range header {
+		req.Headers[http.CanonicalHeaderKey(k)] = header.Get(k)
+	}
+	for _, h := range []string{"X-Github-Event", "Sentry-Hook-Resource", "X-Gastown-Event"} {
+		if v := header.Get(h); v != "" {
+			req.Event = v
+			break
+		}
+	}
+
+	text := string(body)
+	if err := json.Unmarshal(body, &req.Payload); err == nil {
+		var buf bytes.Buffer
+		if json.Indent(&buf, body, "", "  ") == nil {
+			text = buf.String()
+		}
+	} else {
+		req.Payload = map[string]any{}
+	}
+	if len(text) > maxBodyInDescription {
+		text = text[:maxBodyInDescription] + "\n…(truncated)"
+	}
+	req.Body = text
+	return req
+}
+
+// Lookup resolves a dotted path ("a.b.0.c") in a decoded JSON value.
+func Lookup(v any, path string) (any, bool) {
+	if path == "" {
+		return v, true
+	}
+	for _, part := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			next, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// lookupString formats a looked-up value for comparison and templates.
+func lookupString(v any, path string) (string, bool) {
+	got, ok := Lookup(v, path)
+	if !ok || got == nil {
+		return "", ok
+	}
+	switch t := got.(type) {
+	case string:
+		return t, true
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(t), true
+	default:
+		data, _ := json.Marshal(t)
+		return string(data), true
+	}
+}
+
+// Matches reports whether req satisfies every condition in the rule.
+func (r Rule) Matches(req *Request) bool {
+	for key, want := range r.Match {
+		var got string
+		var ok bool
+		if name, isHeader := strings.CutPrefix(key, "header:"); isHeader {
+			got, ok = req.Headers[http.CanonicalHeaderKey(name)]
+		} else {
+			got, ok = lookupString(req.Payload, key)
+		}
+		if !ok || (want != "*" && got != want) {
+			return false
+		}
+	}
+	return true
+}
+
+// Match returns the first rule matching req.
+func (ep *Endpoint) Match(req *Request) (*Rule, bool) {
+	for i := range ep.Rules {
+		if ep.Rules[i].Matches(req) {
+			return &ep.Rules[i], true
+		}
+	}
+	return nil, false
+}
+
+// Rendered is the bead a rule produces for a request.
+type Rendered struct {
+	Title       string
+	Description string
+	DedupKey    string
+}
+
+// Render expands the rule's templates for req. Requests decoded by an intake
+// adapter fall back to its templates: error reports with fingerprint dedup,
+// or the formulated dictation with dedup on its text.
+func (r Rule) Render(req *Request) (*Rendered, error) {
+	defTitle, defDesc, defKey := DefaultTitle, DefaultDescription, ""
+	switch {
+	case req.Error != nil:
+		defTitle, defDesc, defKey = DefaultIntakeTitle, DefaultIntakeDescription, DefaultIntakeDedupKey
+	case req.Dictation != nil:
+		defTitle, defDesc, defKey = DefaultDictationTitle, DefaultDictationDescription, DefaultDictationDedupKey
+	}
+	title, err := render(r.Title, defTitle, req)
+	if err != nil {
+		return nil, fmt.Errorf("title: %w", err)
+	}
+	desc, err := render(r.Description, defDesc, req)
+	if err != nil {
+		return nil, fmt.Errorf("description: %w", err)
+	}
+	key := ""
+	if r.DedupKey != "" || defKey != "" {
+		if key, err = render(r.DedupKey, defKey, req); err != nil {
+			return nil, fmt.Errorf("dedup_key: %w", err)
+		}
+	}
+	// Titles are single-line; payload fields often are not.
+	title = strings.Join(strings.Fields(title), " ")
+	if len(title) > 200 {
+		title = title[:200] + "…"
+	}
+	return &Rendered{Title: title, Description: strings.TrimSpace(desc), DedupKey: strings.TrimSpace(key)}, nil
+}
+
+var templateFuncs = template.FuncMap{
+	// get looks up a dotted path: {{get .Payload "repository.full_name"}}.
+	"get": func(v any, path string) string {
+		s, _ := lookupString(v, path)
+		return s
+	},
+	"truncate": func(n int, s string) string {
+		if len(s) <= n {
+			return s
+		}
+		return s[:n] + "…"
+	},
+}
+
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("webhook").Funcs(templateFuncs).Parse(text)
+}
+
+func render(text, fallback string, req *Request) (string, error) {
+	if text == "" {
+		text = fallback
+	}
+	tmpl, err := parseTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, req); err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(buf.String(), "<no value>", ""), nil
+}
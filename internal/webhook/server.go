@@ -0,0 +1,297 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/injectguard"
+)
+
+// maxBodySize bounds accepted payloads (GitHub caps deliveries at 25MB, but
+// nothing we route on needs more than this).
+const maxBodySize = 1 << 20
+
+// Response statuses.
+const (
+	StatusCreated   = "created"
+	StatusDuplicate = "duplicate"
+	StatusIgnored   = "ignored"
+)
+
+// Response is the JSON body returned to the sender.
+type Response struct {
+	Status string `json:"status"`
+	Bead   string `json:"bead,omitempty"`
+	Rig    string `json:"rig,omitempty"`
+	Note   string `json:"note,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Server routes webhook deliveries to beads.
+type Server struct {
+	TownRoot   string
+	Config     *Config
+	Dispatcher Dispatcher
+	Logf       func(format string, args ...any)
+
+	// Guard scans rendered beads for prompt injection. Suspicious beads
+	// are created quarantined and not slung (see injectguard).
+	Guard *injectguard.Config
+
+	// Formulator turns dictation into tasks. Nil uses the raw text.
+	Formulator Formulator
+
+	dedup *dedupStore
+	now   func() time.Time
+	ctx   context.Context
+	wg    sync.WaitGroup
+
+	rateMu sync.Mutex
+	slings map[string][]time.Time // endpoint/rig → recent sling times
+}
+
+// NewServer creates a server for the town's webhook config.
+func NewServer(townRoot string, cfg *Config, d Dispatcher, logf func(format string, args ...any)) *Server {
+	return &Server{
+		TownRoot:   townRoot,
+		Config:     cfg,
+		Dispatcher: d,
+		Logf:       logf,
+		dedup:      &dedupStore{path: DedupPath(townRoot)},
+		now:        time.Now,
+		ctx:        context.Background(),
+		slings:     make(map[string][]time.Time),
+	}
+}
+
+func (s *Server) logf(format string, args ...any) {
+	if s.Logf != nil {
+		s.Logf(format, args...)
+	}
+}
+
+// Handler returns the HTTP handler: POST /hooks/<name>, GET /healthz.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok\n")
+	})
+	mux.HandleFunc("/hooks/", s.handleHook)
+	return mux
+}
+
+// ListenAndServe serves until ctx is cancelled, then waits for in-flight slings.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	s.ctx = ctx
+	ln, err := net.Listen("tcp", s.Config.ListenAddr())
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", s.Config.ListenAddr(), err)
+	}
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      FormulateTimeout + 30*time.Second, // Dictation waits for the agent
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	err = srv.Serve(ln)
+	s.wg.Wait()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func (s *Server) handleHook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeResponse(w, http.StatusMethodNotAllowed, Response{Error: "POST required"})
+		return
+	}
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/hooks/"), "/")
+	ep, ok := s.Config.Find(name)
+	if !ok {
+		writeResponse(w, http.StatusNotFound, Response{Error: "unknown endpoint"})
+		return
+	}
+	secret, err := ep.Secret()
+	if err != nil {
+		s.logf("webhook %s: %v", name, err)
+		writeResponse(w, http.StatusServiceUnavailable, Response{Error: "endpoint secret not configured"})
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		writeResponse(w, http.StatusRequestEntityTooLarge, Response{Error: "body too large"})
+		return
+	}
+	if err := Verify(ep.Auth, secret, r.Header, body); err != nil {
+		s.logf("webhook %s: rejected delivery from %s: %v", name, r.RemoteAddr, err)
+		writeResponse(w, http.StatusUnauthorized, Response{Error: "unauthorized"})
+		return
+	}
+
+	req := ep.Decode(r.Header, body)
+	if req.Event == "ping" {
+		writeResponse(w, http.StatusOK, Response{Status: StatusIgnored})
+		return
+	}
+	resp, code := s.Handle(ep, req)
+	writeResponse(w, code, resp)
+}
+
+// Handle routes a verified request: match a rule, dedup, create, sling.
+// Slings run in the background so senders are not held past their timeouts.
+func (s *Server) Handle(ep *Endpoint, req *Request) (Response, int) {
+	if ep.Intake != "" && req.Error == nil && req.Dictation == nil {
+		// Installation pings, comments, etc. from the error tracker, or an
+		// empty dictation.
+		return Response{Status: StatusIgnored}, http.StatusOK
+	}
+	rule, ok := ep.Match(req)
+	if !ok {
+		return Response{Status: StatusIgnored}, http.StatusOK
+	}
+	rendered, err := rule.Render(req)
+	if err != nil {
+		s.logf("webhook %s: rendering: %v", ep.Name, err)
+		return Response{Error: err.Error()}, http.StatusUnprocessableEntity
+	}
+
+	dedupKey := ""
+	if rendered.DedupKey != "" {
+		dedupKey = ep.Name + ":" + rendered.DedupKey
+		beadID, reserved := s.dedup.TryReserve(dedupKey, s.now())
+		if !reserved {
+			s.logf("webhook %s: duplicate %q → %s", ep.Name, rendered.DedupKey, beadID)
+			resp := Response{Status: StatusDuplicate, Bead: beadID, Rig: rule.Rig}
+			if beadID == "" {
+				resp.Note = "bead is being created by an earlier delivery"
+			}
+			return resp, http.StatusOK
+		}
+	}
+	// Give the key back if no bead gets created, so a retry can succeed.
+	created := false
+	defer func() {
+		if dedupKey != "" && !created {
+			s.dedup.Release(dedupKey, s.now())
+		}
+	}()
+
+	// Dictation is formulated only once it is known not to be a retry.
+	if req.Dictation != nil {
+		s.formulate(ep.Name, req.Dictation, rule.Rig)
+		if rendered, err = rule.Render(req); err != nil {
+			s.logf("webhook %s: rendering: %v", ep.Name, err)
+			return Response{Error: err.Error()}, http.StatusUnprocessableEntity
+		}
+	}
+
+	findings := injectguard.Scan(s.Guard, rendered.Title+"\n"+rendered.Description)
+	quarantine := len(findings) > 0 && s.Guard.Quarantines()
+	createRule := rule
+	if quarantine {
+		r := *rule
+		r.Labels = append(append([]string(nil), rule.Labels...), injectguard.LabelQuarantined)
+		createRule = &r
+	}
+
+	beadID, err := s.Dispatcher.Create(s.ctx, createRule, rendered, ep.Name)
+	if err != nil {
+		s.logf("webhook %s: %v", ep.Name, err)
+		return Response{Error: "creating bead failed"}, http.StatusBadGateway
+	}
+	created = true
+	if dedupKey != "" {
+		if err := s.dedup.Record(dedupKey, beadID, s.now()); err != nil {
+			s.logf("webhook %s: recording dedup key: %v", ep.Name, err)
+		}
+	}
+	s.logf("webhook %s: created %s %q", ep.Name, beadID, rendered.Title)
+
+	if len(findings) > 0 {
+		s.logf("webhook %s: %s looks like prompt injection (%s)", ep.Name, beadID, injectguard.Summary(findings))
+	}
+	if quarantine {
+		item := injectguard.Item{
+			Kind:     injectguard.KindBead,
+			Source:   "webhook:" + ep.Name,
+			Title:    rendered.Title,
+			Findings: findings,
+			BeadID:   beadID,
+			Rig:      rule.Rig,
+		}
+		if _, err := injectguard.Hold(s.TownRoot, item, s.now()); err != nil {
+			s.logf("webhook %s: recording quarantine for %s: %v", ep.Name, beadID, err)
+		}
+		return Response{Status: StatusCreated, Bead: beadID, Note: "quarantined for review; not slung"}, http.StatusOK
+	}
+
+	if rule.Rig != "" && !s.allowSling(ep.Name+"/"+rule.Rig, rule.MaxSlingsPerHour) {
+		s.logf("webhook %s: sling cap (%d/h) reached for %s; left %s unslung", ep.Name, rule.MaxSlingsPerHour, rule.Rig, beadID)
+		return Response{Status: StatusCreated, Bead: beadID, Note: "sling rate cap reached; not slung"}, http.StatusOK
+	}
+	if rule.Rig != "" {
+		s.wg.Add(1)
+		go func(rig string) {
+			defer s.wg.Done()
+			if err := s.Dispatcher.Sling(s.ctx, beadID, rig); err != nil {
+				s.logf("webhook %s: %v", ep.Name, err)
+				return
+			}
+			s.logf("webhook %s: slung %s to %s", ep.Name, beadID, rig)
+		}(rule.Rig)
+	}
+	resp := Response{Status: StatusCreated, Bead: beadID, Rig: rule.Rig}
+	if req.Dictation != nil && !req.Dictation.Formulated {
+		resp.Note = "not formulated; created from the raw text"
+	}
+	return resp, http.StatusOK
+}
+
+// allowSling reports whether another sling fits under a per-hour cap and,
+// if so, counts it. limit <= 0 means no cap.
+func (s *Server) allowSling(key string, limit int) bool {
+	if limit <= 0 {
+		return true
+	}
+	s.rateMu.Lock()
+	defer s.rateMu.Unlock()
+	now := s.now()
+	recent := s.slings[key][:0]
+	for _, t := range s.slings[key] {
+		if now.Sub(t) < time.Hour {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) >= limit {
+		s.slings[key] = recent
+		return false
+	}
+	s.slings[key] = append(recent, now)
+	return true
+}
+
+// Wait blocks until background slings finish (used by tests and shutdown).
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+func writeResponse(w http.ResponseWriter, code int, resp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
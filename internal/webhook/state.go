@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// DedupWindow is how long a dedup key keeps pointing at its bead.
+const DedupWindow = 7 * 24 * time.Hour
+
+// reserveTimeout is how long a reservation holds a key while its bead is
+// being created; after that a crashed creator's key is free again.
+const reserveTimeout = 5 * time.Minute
+
+// dedupEntry records the bead created for a dedup key. An entry without a
+// bead is a reservation: the bead is still being created.
+type dedupEntry struct {
+	BeadID    string    `json:"bead_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Count     int       `json:"count"`
+}
+
+func (e *dedupEntry) live(now time.Time) bool {
+	if e.BeadID == "" {
+		return now.Sub(e.CreatedAt) <= reserveTimeout
+	}
+	return now.Sub(e.CreatedAt) <= DedupWindow
+}
+
+// dedupStore persists dedup keys in <town>/.runtime/webhook-dedup.json.
+type dedupStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// DedupPath returns the dedup state file for a town.
+func DedupPath(townRoot string) string {
+	return filepath.Join(townRoot, constants.DirRuntime, "webhook-dedup.json")
+}
+
+func (s *dedupStore) load() map[string]*dedupEntry {
+	entries := make(map[string]*dedupEntry)
+	data, err := os.ReadFile(s.path)
+	if err == nil {
+		_ = json.Unmarshal(data, &entries)
+	}
+	return entries
+}
+
+// TryReserve claims key for a new bead in one step, so concurrent or
+// retried deliveries of the same event can't both create one. It returns
+// reserved=false for a live key, with its bead (empty while that bead is
+// still being created), and bumps the key's count. A reservation is
+// completed by Record or given up with Release.
+func (s *dedupStore) TryReserve(key string, now time.Time) (beadID string, reserved bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := s.load()
+	if e, ok := entries[key]; ok && e.live(now) {
+		e.Count++
+		_ = s.save(entries, now)
+		return e.BeadID, false
+	}
+	entries[key] = &dedupEntry{CreatedAt: now, Count: 1}
+	_ = s.save(entries, now)
+	return "", true
+}
+
+// Record stores key → beadID, pruning expired keys.
+func (s *dedupStore) Record(key, beadID string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := s.load()
+	count := 1
+	if e, ok := entries[key]; ok && e.BeadID == "" {
+		count = e.Count
+	}
+	entries[key] = &dedupEntry{BeadID: beadID, CreatedAt: now, Count: count}
+	return s.save(entries, now)
+}
+
+// Release drops key's reservation after its bead could not be created, so
+// a retry can try again.
+func (s *dedupStore) Release(key string, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := s.load()
+	if e, ok := entries[key]; ok && e.BeadID == "" {
+		delete(entries, key)
+		_ = s.save(entries, now)
+	}
+}
+
+func (s *dedupStore) save(entries map[string]*dedupEntry, now time.Time) error {
+	for k, e := range entries {
+		if !e.live(now) {
+			delete(entries, k)
+		}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("creating runtime directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil { //nolint:gosec // G306: state is not sensitive
+		return fmt.Errorf("writing webhook dedup state: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -1426,9 +1426,10 @@ const SpawnGracePeriod = 5 * time.Minute
 
 // StalledResult represents a single stalled polecat detection.
 type StalledResult struct {
-	PolecatName string // e.g., "alpha"
-	StallType   string // "startup-stall", "unknown-prompt"
-	Action      string // "auto-dismissed", "escalated"
+	PolecatName string     // e.g., "alpha"
+	StallType   string     // "startup-stall", "unknown-prompt"
+	Cause       StallCause // Probable cause from the pane (see ClassifyStall)
+	Action      string     // "auto-dismissed", "escalated"
 	Error       error
 }
 
@@ -1537,9 +1538,16 @@ func DetectStalledPolecats(workDir, rigName string) *DetectStalledPolecatsResult
 		// Session is old enough and has no recent activity: startup stall.
 		// Send blind key sequences to dismiss any startup dialogs without
 		// screen-scraping pane content (avoids coupling to third-party TUI strings).
+		// The pane is still classified (not acted on) so the stall log shows
+		// what startup stalls were actually waiting on.
 		stalled := StalledResult{
 			PolecatName: polecatName,
 			StallType:   "startup-stall",
+			Cause:       StallUnknown,
+		}
+		var evidence string
+		if lines, err := t.CapturePaneLines(sessionName, stallTailLines); err == nil {
+			stalled.Cause, evidence = ClassifyStall(lines)
 		}
 		if err := t.DismissStartupDialogsBlind(sessionName); err != nil {
 			stalled.Action = "escalated"
@@ -1547,6 +1555,15 @@ func DetectStalledPolecats(workDir, rigName string) *DetectStalledPolecatsResult
 		} else {
 			stalled.Action = "auto-dismissed"
 		}
+		_ = RecordStall(townRoot, &StallRecord{
+			Rig:      rigName,
+			Polecat:  polecatName,
+			Cause:    stalled.Cause,
+			Action:   stalled.Action,
+			Evidence: evidence,
+			Idle:     activityAge,
+			Acted:    stalled.Error == nil,
+		})
 		result.Stalled = append(result.Stalled, stalled)
 	}
 
@@ -0,0 +1,184 @@
+package witness
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// StallCause is the probable reason a live polecat stopped making progress.
+type StallCause string
+
+// Stall causes, in the order ClassifyStall checks them.
+const (
+	// StallPermissionPrompt: the agent is blocked on a tool-permission dialog.
+	StallPermissionPrompt StallCause = "permission-prompt"
+	// StallRateLimit: the provider is throttling the agent.
+	StallRateLimit StallCause = "rate-limit"
+	// StallLongComputation: a tool or model turn is still running.
+	StallLongComputation StallCause = "long-computation"
+	// StallCrashedTool: a tool died and the agent didn't recover.
+	StallCrashedTool StallCause = "crashed-tool"
+	// StallHumanQuestion: the agent asked a question and is waiting for an answer.
+	StallHumanQuestion StallCause = "human-question"
+	// StallUnknown: nothing recognizable in the pane.
+	StallUnknown StallCause = "unknown"
+)
+
+// Recovery actions chosen for a stall cause.
+const (
+	StallActionWait     = "wait"     // Leave it alone; it will resume
+	StallActionNudge    = "nudge"    // Prompt the agent to continue
+	StallActionEscalate = "escalate" // Needs a human or the Mayor
+	StallActionRestart  = "restart"  // Restart the session (worktree preserved)
+	StallActionAnswer   = "answer"   // Prompt answered by the rig's permission policy
+)
+
+// StallAction returns the recovery action for a cause. Only a crashed tool
+// warrants a restart: the other causes are slow or blocked, not broken, and
+// restarting would throw away the in-flight turn.
+func StallAction(cause StallCause) string {
+	switch cause {
+	case StallRateLimit, StallLongComputation:
+		return StallActionWait
+	case StallPermissionPrompt, StallHumanQuestion:
+		return StallActionEscalate
+	case StallCrashedTool:
+		return StallActionRestart
+	default:
+		return StallActionNudge
+	}
+}
+
+// stallPatterns are matched case-insensitively against the pane tail.
+var stallPatterns = []struct {
+	cause StallCause
+	re    *regexp.Regexp
+}{
+	{StallPermissionPrompt, regexp.MustCompile(`(?i)do you want to (proceed|make this edit|create|run|allow)|yes, and don't ask again|requires (your )?(approval|permission)|permission to (use|run)`)},
+	{StallRateLimit, regexp.MustCompile(`(?i)rate.?limit|usage limit|limit reached|too many requests|\b429\b|overloaded_error|resets? (at|in) `)},
+	{StallLongComputation, regexp.MustCompile(`(?i)esc to interrupt|\(\d+m? ?\d*s ·|running…|still running`)},
+	{StallCrashedTool, regexp.MustCompile(`(?i)^panic:|traceback \(most recent call last\)|segmentation fault|fatal error:|^killed$|signal: killed|core dumped|exited with (code|status) [1-9]|npm err!|command timed out`)},
+}
+
+// questionRe matches an assistant line that asks for a decision or answer.
+var questionRe = regexp.MustCompile(`(?i)(should i|shall i|would you like|do you want me|which (option|approach)|let me know|please (confirm|advise))|\?\s*$`)
+
+// stallTailLines is how much of the pane tail ClassifyStall looks at.
+// Older output is usually stale history rather than the current state.
+const stallTailLines = 30
+
+// ClassifyStall infers why a session stopped making progress from its
+// captured pane lines, returning the cause and the line that decided it.
+// Checks run from most to least specific: a visible permission dialog or
+// rate-limit notice wins over a busy indicator, which wins over a crash
+// trace (the agent may already be handling it) and trailing questions.
+func ClassifyStall(lines []string) (StallCause, string) {
+	if len(lines) > stallTailLines {
+		lines = lines[len(lines)-stallTailLines:]
+	}
+	for _, p := range stallPatterns {
+		for i := len(lines) - 1; i >= 0; i-- {
+			line := strings.TrimSpace(lines[i])
+			if p.re.MatchString(line) {
+				return p.cause, line
+			}
+		}
+	}
+	// Questions only count near the end: the last few meaningful lines.
+	seen := 0
+	for i := len(lines) - 1; i >= 0 && seen < 5; i-- {
+		line := strings.Trim(strings.TrimSpace(lines[i]), "│╭╰╮╯─⏺●> ")
+		if line == "" || strings.Contains(strings.ToLower(line), "for shortcuts") {
+			continue
+		}
+		seen++
+		if questionRe.MatchString(line) {
+			return StallHumanQuestion, line
+		}
+	}
+	return StallUnknown, ""
+}
+
+// StallRecord is one classified stall, kept for analytics.
+type StallRecord struct {
+	Timestamp time.Time     `json:"ts"`
+	Rig       string        `json:"rig"`
+	Polecat   string        `json:"polecat"`
+	Cause     StallCause    `json:"cause"`
+	Action    string        `json:"action"`
+	Evidence  string        `json:"evidence,omitempty"`
+	Idle      time.Duration `json:"idle_ns,omitempty"`
+	Acted     bool          `json:"acted,omitempty"` // Recovery action was carried out
+}
+
+// StallLogPath returns the stall classification log for a town.
+func StallLogPath(townRoot string) string {
+	return filepath.Join(townRoot, "logs", "stalls.jsonl")
+}
+
+// RecordStall appends a classification to the stall log.
+func RecordStall(townRoot string, r *StallRecord) error {
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now().UTC()
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("encoding stall record: %w", err)
+	}
+	path := StallLogPath(townRoot)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating log directory: %w", err)
+	}
+
+	fl := flock.New(path + ".lock")
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking stall log: %w", err)
+	}
+	defer fl.Unlock() //nolint:errcheck // best-effort unlock
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G302: operational log
+	if err != nil {
+		return fmt.Errorf("opening stall log: %w", err)
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing stall log: %w", err)
+	}
+	return f.Close()
+}
+
+// ReadStalls returns stall records at or after since, oldest first.
+// Malformed lines are skipped.
+func ReadStalls(townRoot string, since time.Time) ([]StallRecord, error) {
+	f, err := os.Open(StallLogPath(townRoot)) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []StallRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r StallRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue
+		}
+		if r.Timestamp.Before(since) {
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
@@ -0,0 +1,140 @@
+package witness
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClassifyStall(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  StallCause
+	}{
+		{
+			name: "permission prompt",
+			lines: []string{
+				"⏺ Bash(rm -rf build/)",
+				" Do you want to proceed?",
+				" ❯ 1. Yes",
+				"   2. Yes, and don't ask again for rm commands",
+				"   3. No, and tell Claude what to do differently (esc)",
+			},
+			want: StallPermissionPrompt,
+		},
+		{
+			name:  "rate limit",
+			lines: []string{"⎿ API Error: Rate limit reached", "Claude usage limit reached. Your limit will reset at 3pm."},
+			want:  StallRateLimit,
+		},
+		{
+			name:  "long computation",
+			lines: []string{"⏺ Bash(go test ./...)", "  ⎿  Running…", "✻ Compiling… (412s · ↓ 1.2k tokens · esc to interrupt)"},
+			want:  StallLongComputation,
+		},
+		{
+			name: "crashed tool",
+			lines: []string{
+				"⏺ Bash(./scripts/migrate.sh)",
+				"  ⎿  panic: runtime error: invalid memory address",
+				"panic: runtime error: invalid memory address or nil pointer dereference",
+				"",
+				"╭────────╮",
+				"│ >      │",
+				"╰────────╯",
+			},
+			want: StallCrashedTool,
+		},
+		{
+			name: "question for human",
+			lines: []string{
+				"⏺ I found two ways to fix the flaky test.",
+				"  Option A retries, option B mocks the clock.",
+				"  Which approach do you prefer?",
+				"",
+				"╭────────╮",
+				"│ >      │",
+				"╰────────╯",
+				"  ? for shortcuts",
+			},
+			want: StallHumanQuestion,
+		},
+		{
+			name:  "unknown",
+			lines: []string{"⏺ Updated 3 files.", "", "│ > │"},
+			want:  StallUnknown,
+		},
+		{
+			name:  "empty pane",
+			lines: nil,
+			want:  StallUnknown,
+		},
+	}
+	for _, tt := range tests {
+		got, evidence := ClassifyStall(tt.lines)
+		if got != tt.want {
+			t.Errorf("%s: ClassifyStall() = %s (evidence %q), want %s", tt.name, got, evidence, tt.want)
+		}
+		if got != StallUnknown && evidence == "" {
+			t.Errorf("%s: expected evidence for %s", tt.name, got)
+		}
+	}
+}
+
+func TestClassifyStall_OnlyRecentTail(t *testing.T) {
+	lines := []string{"Do you want to proceed?"}
+	for i := 0; i < stallTailLines; i++ {
+		lines = append(lines, "⏺ Edited internal/foo.go")
+	}
+	if got, _ := ClassifyStall(lines); got != StallUnknown {
+		t.Errorf("ClassifyStall() = %s, want old prompt outside the tail ignored", got)
+	}
+}
+
+func TestStallAction(t *testing.T) {
+	want := map[StallCause]string{
+		StallPermissionPrompt: StallActionEscalate,
+		StallRateLimit:        StallActionWait,
+		StallLongComputation:  StallActionWait,
+		StallCrashedTool:      StallActionRestart,
+		StallHumanQuestion:    StallActionEscalate,
+		StallUnknown:          StallActionNudge,
+	}
+	for cause, action := range want {
+		if got := StallAction(cause); got != action {
+			t.Errorf("StallAction(%s) = %s, want %s", cause, got, action)
+		}
+	}
+}
+
+func TestStallLogRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	if records, err := ReadStalls(townRoot, time.Time{}); err != nil || records != nil {
+		t.Fatalf("ReadStalls() on empty town = %v, %v", records, err)
+	}
+
+	old := time.Now().Add(-48 * time.Hour).UTC()
+	for _, r := range []*StallRecord{
+		{Timestamp: old, Rig: "gastown", Polecat: "Toast", Cause: StallRateLimit, Action: StallActionWait},
+		{Rig: "gastown", Polecat: "Nux", Cause: StallCrashedTool, Action: StallActionRestart, Acted: true},
+	} {
+		if err := RecordStall(townRoot, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := ReadStalls(townRoot, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("got %d records, want 2", len(all))
+	}
+	recent, err := ReadStalls(townRoot, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 1 || recent[0].Polecat != "Nux" || !recent[0].Acted {
+		t.Errorf("recent = %+v, want only Nux", recent)
+	}
+}
@@ -28,24 +28,34 @@ const (
 
 // Find locates the town root by walking up from the given directory.
 // It prefers mayor/town.json over mayor/ directory as workspace marker.
-// When in a worktree path (polecats/ or crew/), continues to outermost workspace.
-// Does not resolve symlinks to stay consistent with os.Getwd().
+// When in a worktree path (polecats/ or crew/), town markers inside the
+// worktree itself are skipped, so a checked-out repo that contains its own
+// mayor/town.json does not shadow the town it lives in; nested towns still
+// resolve to the innermost one. Does not resolve symlinks to stay consistent
+// with os.Getwd(), unless the literal path finds nothing.
 func Find(startDir string) (string, error) {
-	absDir, err := filepath.Abs(startDir)
+	res, err := Resolve(startDir)
 	if err != nil {
-		return "", fmt.Errorf("resolving path: %w", err)
+		return "", err
 	}
+	return res.Root, nil
+}
 
+// walk searches upward from absDir for town markers.
+func walk(absDir string) (root, marker string, skipped []string) {
 	inWorktree := isInWorktreePath(absDir)
-	var primaryMatch, secondaryMatch string
+	var outermostPrimary, secondaryMatch string
 
 	current := absDir
 	for {
 		if _, err := os.Stat(filepath.Join(current, PrimaryMarker)); err == nil {
-			if !inWorktree {
-				return current, nil
+			// Inside a worktree, only a town that is not itself within a
+			// polecats/ or crew/ path counts; the rest are checked-out repos.
+			if !inWorktree || !isInWorktreePath(current+string(filepath.Separator)) {
+				return current, PrimaryMarker, skipped
 			}
-			primaryMatch = current
+			skipped = append(skipped, current)
+			outermostPrimary = current
 		}
 
 		// Always keep updating secondaryMatch to find the outermost mayor/ directory.
@@ -58,10 +68,13 @@ func Find(startDir string) (string, error) {
 
 		parent := filepath.Dir(current)
 		if parent == current {
-			if primaryMatch != "" {
-				return primaryMatch, nil
+			if outermostPrimary != "" {
+				return outermostPrimary, PrimaryMarker, skipped[:len(skipped)-1]
+			}
+			if secondaryMatch != "" {
+				return secondaryMatch, SecondaryMarker + "/", skipped
 			}
-			return secondaryMatch, nil
+			return "", "", skipped
 		}
 		current = parent
 	}
@@ -84,29 +97,27 @@ func FindOrError(startDir string) (string, error) {
 	return root, nil
 }
 
-// FindFromCwd locates the town root from the current working directory.
+// FindFromCwd locates the town root from the current working directory,
+// honoring the GT_TOWN_ROOT override (see ResolveFromCwd).
 func FindFromCwd() (string, error) {
-	cwd, err := os.Getwd()
+	res, err := ResolveFromCwd()
 	if err != nil {
-		return "", fmt.Errorf("getting current directory: %w", err)
+		return "", err
 	}
-	return Find(cwd)
+	return res.Root, nil
 }
 
 // FindFromCwdOrError is like FindFromCwd but returns an error if not found.
-// It searches for a workspace starting from the CWD. If none is found, it
-// falls back to the GT_TOWN_ROOT or GT_ROOT environment variables.
+// If the CWD is unusable or no workspace is found, it falls back to the
+// GT_TOWN_ROOT or GT_ROOT environment variables.
 func FindFromCwdOrError() (string, error) {
-	cwd, err := os.Getwd()
-	if err == nil {
-		root, err := Find(cwd)
-		if err == nil && root != "" {
-			return root, nil
-		}
+	res, err := ResolveFromCwd()
+	if err == nil && res.Root != "" {
+		return res.Root, nil
 	}
 
 	// Fallback: try GT_TOWN_ROOT or GT_ROOT env vars (set by shell integration or session manager)
-	for _, envName := range []string{"GT_TOWN_ROOT", "GT_ROOT"} {
+	for _, envName := range []string{EnvTownRoot, "GT_ROOT"} {
 		if townRoot := os.Getenv(envName); townRoot != "" {
 			// Verify it's actually a workspace
 			if ok, _ := IsWorkspace(townRoot); ok {
@@ -116,7 +127,7 @@ func FindFromCwdOrError() (string, error) {
 	}
 
 	if err != nil {
-		return "", fmt.Errorf("getting current directory: %w", err)
+		return "", err
 	}
 	return "", ErrNotFound
 }
@@ -0,0 +1,185 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EnvTownRoot names the environment variable that pins the town root.
+const EnvTownRoot = "GT_TOWN_ROOT"
+
+// Sources of a resolved town root.
+const (
+	SourceWalk     = "walk"     // Found by walking up from the start directory
+	SourcePhysical = "physical" // Found by walking up the symlink-resolved path
+	SourceEnv      = "env"      // Taken from GT_TOWN_ROOT
+)
+
+// Resolution explains how a town root was found. gt whereami prints it.
+type Resolution struct {
+	Start    string   // Directory the search started from
+	Physical string   // Start with symlinks resolved, if different
+	Root     string   // Town root; "" if none was found
+	Marker   string   // Marker that identified Root (mayor/town.json or mayor/)
+	Source   string   // SourceWalk, SourcePhysical or SourceEnv
+	Skipped  []string // Town markers passed over because they sit inside a worktree
+	Outer    []string // Enclosing towns above Root, for nested towns
+	Notes    []string // How GT_TOWN_ROOT was applied, if set
+}
+
+// Resolve locates the town root for startDir and records how. The literal
+// path is walked first so symlinked towns keep the path the user typed; if
+// that finds nothing, the symlink-resolved path is walked, which handles a
+// rig directory that is a symlink into a town from outside it.
+func Resolve(startDir string) (*Resolution, error) {
+	absDir, err := filepath.Abs(startDir)
+	if err != nil {
+		return nil, fmt.Errorf("resolving path: %w", err)
+	}
+	res := &Resolution{Start: absDir}
+	if physical, err := filepath.EvalSymlinks(absDir); err == nil && physical != absDir {
+		res.Physical = physical
+	}
+
+	res.Root, res.Marker, res.Skipped = walk(absDir)
+	res.Source = SourceWalk
+	if res.Root == "" && res.Physical != "" {
+		res.Root, res.Marker, res.Skipped = walk(res.Physical)
+		res.Source = SourcePhysical
+	}
+	if res.Root == "" {
+		res.Source = ""
+		return res, nil
+	}
+
+	for dir := filepath.Dir(res.Root); ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, PrimaryMarker)); err == nil {
+			res.Outer = append(res.Outer, dir)
+		}
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	return res, nil
+}
+
+// ResolveFromCwd resolves the town for the current directory, applying the
+// GT_TOWN_ROOT override.
+//
+// GT_TOWN_ROOT wins when it names a workspace and either no town was found
+// (bind mounts, deleted worktrees) or the directory, literally or with
+// symlinks resolved, is inside it — which settles nested towns in its
+// favour. A GT_TOWN_ROOT for an unrelated town is ignored, so a stale
+// variable in a long-lived shell does not send commands to the wrong town.
+func ResolveFromCwd() (*Resolution, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("getting current directory: %w", err)
+	}
+	res, err := Resolve(cwd)
+	if err != nil {
+		return nil, err
+	}
+	applyEnvOverride(res, os.Getenv(EnvTownRoot))
+	return res, nil
+}
+
+func applyEnvOverride(res *Resolution, envRoot string) {
+	if envRoot == "" {
+		return
+	}
+	envRoot = filepath.Clean(envRoot)
+	if ok, _ := IsWorkspace(envRoot); !ok {
+		res.Notes = append(res.Notes, fmt.Sprintf("%s=%s ignored: not a workspace", EnvTownRoot, envRoot))
+		return
+	}
+	if res.Root != "" && samePath(res.Root, envRoot) {
+		return
+	}
+
+	inside := within(res.Start, envRoot) || (res.Physical != "" && within(res.Physical, envRoot))
+	var why string
+	switch {
+	case res.Root == "":
+		why = "no town found above " + res.Start
+	case !inside:
+		res.Notes = append(res.Notes, fmt.Sprintf("%s=%s ignored: %s is in another town", EnvTownRoot, envRoot, res.Start))
+		return
+	case within(res.Root, envRoot):
+		why = "overrides nested town " + res.Root
+	case within(envRoot, res.Root):
+		why = "overrides enclosing town " + res.Root
+	default:
+		why = "physical path " + res.Physical + " is inside it"
+	}
+	res.Root = envRoot
+	res.Marker = PrimaryMarker
+	if _, err := os.Stat(filepath.Join(envRoot, PrimaryMarker)); err != nil {
+		res.Marker = SecondaryMarker + "/"
+	}
+	res.Source = SourceEnv
+	res.Notes = append(res.Notes, fmt.Sprintf("%s=%s %s", EnvTownRoot, envRoot, why))
+}
+
+// RelPath is filepath.Rel for paths inside a town. When path reaches the
+// town through a symlink or bind mount, so that the literal relative path
+// would climb out with "..", it returns the path relative to the town as
+// the town sees it. If no such relation exists it returns filepath.Rel's
+// result unchanged.
+func RelPath(townRoot, path string) (string, error) {
+	rel, err := filepath.Rel(townRoot, path)
+	if err != nil || !escapes(rel) {
+		return rel, err
+	}
+
+	realRoot, rerr := filepath.EvalSymlinks(townRoot)
+	realPath, perr := filepath.EvalSymlinks(path)
+	if rerr == nil && perr == nil {
+		if r, err := filepath.Rel(realRoot, realPath); err == nil && !escapes(r) {
+			return r, nil
+		}
+	}
+
+	// Bind mounts: find the ancestor of path that is the town root, or one
+	// of its top-level directories (a rig mounted under its own name).
+	rootInfo, err := os.Stat(townRoot)
+	if err != nil {
+		return rel, nil
+	}
+	var tail []string
+	for dir := filepath.Clean(path); ; dir = filepath.Dir(dir) {
+		if info, err := os.Stat(dir); err == nil {
+			if os.SameFile(info, rootInfo) {
+				return filepath.Join(append([]string{"."}, tail...)...), nil
+			}
+			if rigInfo, err := os.Stat(filepath.Join(townRoot, filepath.Base(dir))); err == nil && os.SameFile(info, rigInfo) {
+				return filepath.Join(append([]string{filepath.Base(dir)}, tail...)...), nil
+			}
+		}
+		if filepath.Dir(dir) == dir {
+			return rel, nil
+		}
+		tail = append([]string{filepath.Base(dir)}, tail...)
+	}
+}
+
+func escapes(rel string) bool {
+	return rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// within reports whether path is dir or below it.
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && !escapes(rel)
+}
+
+func samePath(a, b string) bool {
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+	ai, aerr := os.Stat(a)
+	bi, berr := os.Stat(b)
+	return aerr == nil && berr == nil && os.SameFile(ai, bi)
+}
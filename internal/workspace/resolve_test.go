@@ -0,0 +1,146 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// makeTown creates a town marker at dir and returns dir.
+func makeTown(t *testing.T, dir string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, "mayor"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, PrimaryMarker), []byte(`{"type":"town"}`), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return dir
+}
+
+func mkdirs(t *testing.T, dir string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	return dir
+}
+
+func TestResolve_NestedTownWorktree(t *testing.T) {
+	outer := makeTown(t, realPath(t, t.TempDir()))
+	inner := makeTown(t, filepath.Join(outer, "sandbox", "inner"))
+	// A polecat worktree in the inner town whose repo carries its own town marker.
+	worktree := makeTown(t, filepath.Join(inner, "myrig", "polecats", "Toast", "myrig"))
+
+	res, err := Resolve(mkdirs(t, filepath.Join(worktree, "src")))
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.Root != inner {
+		t.Errorf("Root = %q, want inner town %q", res.Root, inner)
+	}
+	if len(res.Skipped) != 1 || res.Skipped[0] != worktree {
+		t.Errorf("Skipped = %v, want [%s]", res.Skipped, worktree)
+	}
+	if len(res.Outer) != 1 || res.Outer[0] != outer {
+		t.Errorf("Outer = %v, want [%s]", res.Outer, outer)
+	}
+}
+
+func TestResolve_SymlinkedRigFromOutside(t *testing.T) {
+	base := realPath(t, t.TempDir())
+	town := makeTown(t, filepath.Join(base, "town"))
+	rig := mkdirs(t, filepath.Join(town, "myrig", "crew", "max"))
+
+	// An editor opened through a link outside the town.
+	link := filepath.Join(base, "projects", "myrig")
+	mkdirs(t, filepath.Dir(link))
+	if err := os.Symlink(filepath.Join(town, "myrig"), link); err != nil {
+		t.Skipf("symlink not supported: %v", err)
+	}
+
+	res, err := Resolve(filepath.Join(link, "crew", "max"))
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if res.Root != town || res.Source != SourcePhysical || res.Physical != rig {
+		t.Errorf("Resolve = %+v, want %s via physical path", res, town)
+	}
+
+	rel, err := RelPath(town, filepath.Join(link, "crew", "max"))
+	if err != nil {
+		t.Fatalf("RelPath: %v", err)
+	}
+	if filepath.ToSlash(rel) != "myrig/crew/max" {
+		t.Errorf("RelPath = %q, want myrig/crew/max", rel)
+	}
+}
+
+func TestRelPath_Plain(t *testing.T) {
+	town := makeTown(t, realPath(t, t.TempDir()))
+	rel, err := RelPath(town, filepath.Join(town, "myrig", "witness"))
+	if err != nil || filepath.ToSlash(rel) != "myrig/witness" {
+		t.Errorf("RelPath = %q, %v", rel, err)
+	}
+	outside := realPath(t, t.TempDir())
+	if rel, _ := RelPath(town, outside); !strings.HasPrefix(rel, "..") {
+		t.Errorf("RelPath outside town = %q, want unchanged ../ path", rel)
+	}
+}
+
+func TestApplyEnvOverride(t *testing.T) {
+	base := realPath(t, t.TempDir())
+	outer := makeTown(t, filepath.Join(base, "outer"))
+	inner := makeTown(t, filepath.Join(outer, "inner"))
+	other := makeTown(t, filepath.Join(base, "other"))
+	notTown := mkdirs(t, filepath.Join(base, "plain"))
+	innerRig := mkdirs(t, filepath.Join(inner, "rig"))
+
+	tests := []struct {
+		name     string
+		start    string
+		env      string
+		wantRoot string
+		wantNote string
+	}{
+		{"no env", innerRig, "", inner, ""},
+		{"same town", innerRig, inner, inner, ""},
+		{"nested town", innerRig, outer, outer, "overrides nested town"},
+		{"no town found", notTown, other, other, "no town found"},
+		{"unrelated town", innerRig, other, inner, "ignored"},
+		{"not a workspace", innerRig, notTown, inner, "not a workspace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Resolve(tt.start)
+			if err != nil {
+				t.Fatalf("Resolve: %v", err)
+			}
+			applyEnvOverride(res, tt.env)
+			if res.Root != tt.wantRoot {
+				t.Errorf("Root = %q, want %q", res.Root, tt.wantRoot)
+			}
+			notes := strings.Join(res.Notes, "; ")
+			if tt.wantNote == "" && notes != "" || !strings.Contains(notes, tt.wantNote) {
+				t.Errorf("Notes = %q, want %q", notes, tt.wantNote)
+			}
+		})
+	}
+}
+
+func TestFindFromCwd_EnvOverride(t *testing.T) {
+	base := realPath(t, t.TempDir())
+	town := makeTown(t, filepath.Join(base, "town"))
+	mount := mkdirs(t, filepath.Join(base, "mnt", "work"))
+
+	t.Chdir(mount)
+	t.Setenv(EnvTownRoot, "")
+	if root, err := FindFromCwd(); err != nil || root != "" {
+		t.Fatalf("FindFromCwd without override = %q, %v", root, err)
+	}
+	t.Setenv(EnvTownRoot, town)
+	if root, err := FindFromCwd(); err != nil || root != town {
+		t.Errorf("FindFromCwd = %q, %v; want %q", root, err, town)
+	}
+}
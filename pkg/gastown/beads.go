@@ -0,0 +1,94 @@
+package gastown
+
+import (
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+// Bead is an issue, task or other tracked item.
+type Bead struct {
+	ID          string
+	Title       string
+	Description string
+	Status      string // open, in_progress, hooked, closed, ...
+	Type        string
+	Priority    int // 0 (highest) to 4
+	Assignee    string
+	Labels      []string
+	Parent      string
+	BlockedBy   []string
+	CreatedAt   string // RFC 3339
+	UpdatedAt   string
+	ClosedAt    string
+
+	// Rig is the rig that owns the bead, or "" for town-level beads.
+	Rig string
+}
+
+// BeadQuery filters ListBeads.
+type BeadQuery struct {
+	Rig      string // Rig to list; "" lists town-level beads
+	Status   string // open (default), closed, all, or a specific status
+	Label    string
+	Assignee string
+	Limit    int // 0 = no limit
+}
+
+// Bead returns a bead by ID from whichever rig its prefix routes to.
+func (t *Town) Bead(id string) (*Bead, error) {
+	issue, err := beads.New(t.root).Show(id)
+	if err != nil {
+		return nil, err
+	}
+	return t.convertBead(issue), nil
+}
+
+// ListBeads lists beads in one rig, or at town level.
+func (t *Town) ListBeads(q BeadQuery) ([]Bead, error) {
+	dir := t.root
+	if q.Rig != "" {
+		if _, err := t.Rig(q.Rig); err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(t.root, q.Rig)
+	}
+	status := q.Status
+	if status == "" {
+		status = "open"
+	}
+	issues, err := beads.New(dir).List(beads.ListOptions{
+		Status:   status,
+		Label:    q.Label,
+		Assignee: q.Assignee,
+		Priority: -1,
+		Limit:    q.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Bead, 0, len(issues))
+	for _, issue := range issues {
+		out = append(out, *t.convertBead(issue))
+	}
+	return out, nil
+}
+
+func (t *Town) convertBead(issue *beads.Issue) *Bead {
+	return &Bead{
+		ID:          issue.ID,
+		Title:       issue.Title,
+		Description: issue.Description,
+		Status:      issue.Status,
+		Type:        issue.Type,
+		Priority:    issue.Priority,
+		Assignee:    issue.Assignee,
+		Labels:      append([]string(nil), issue.Labels...),
+		Parent:      issue.Parent,
+		BlockedBy:   append([]string(nil), issue.BlockedBy...),
+		CreatedAt:   issue.CreatedAt,
+		UpdatedAt:   issue.UpdatedAt,
+		ClosedAt:    issue.ClosedAt,
+		Rig:         t.RigForBead(issue.ID),
+	}
+}
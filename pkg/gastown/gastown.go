@@ -0,0 +1,131 @@
+// Package gastown is a Go client for a Gas Town workspace.
+//
+// It exposes the operations other programs most often need — finding a
+// town, reading and routing beads, slinging work, sending and reading mail,
+// and checking which agents are running — without shelling out to gt and
+// parsing its output:
+//
+//	town, err := gastown.Discover(".")
+//	if err != nil {
+//		return err
+//	}
+//	bead, err := town.Bead("gt-abc")
+//	...
+//	ticket, err := town.Sling(gastown.SlingRequest{Bead: bead.ID, Rig: "gastown"})
+//
+// The types in this package are its API: they are defined here rather than
+// aliased from gt's internals, and fields are only ever added, so programs
+// built against one release keep compiling against the next. Bead and mail
+// operations still need the bd binary on PATH, as gt itself does.
+package gastown
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// ErrNotTown is returned when a directory is not inside a Gas Town workspace.
+var ErrNotTown = errors.New("not a Gas Town workspace")
+
+// Town is a handle on one Gas Town workspace. It holds no open resources;
+// every call reads the town's current state.
+type Town struct {
+	root string
+	name string
+}
+
+// Rig is a project registered in the town.
+type Rig struct {
+	Name   string
+	Path   string // Absolute path to the rig directory
+	GitURL string
+	Prefix string // Bead ID prefix, such as "gt"
+}
+
+// Open returns the town rooted at root. Like gt at startup, it loads the
+// town's rig prefixes, agent presets and tmux socket into process-wide
+// registries, so a process should work with one town at a time.
+func Open(root string) (*Town, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := workspace.IsWorkspace(abs)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", abs, ErrNotTown)
+	}
+	_ = session.InitRegistry(abs)
+
+	t := &Town{root: abs}
+	if name, err := workspace.GetTownName(abs); err == nil {
+		t.name = name
+	}
+	return t, nil
+}
+
+// Discover finds the town containing dir, walking up as gt does.
+func Discover(dir string) (*Town, error) {
+	root, err := workspace.Find(dir)
+	if err != nil {
+		return nil, err
+	}
+	if root == "" {
+		return nil, fmt.Errorf("%s: %w", dir, ErrNotTown)
+	}
+	return Open(root)
+}
+
+// Root returns the town's root directory.
+func (t *Town) Root() string { return t.root }
+
+// Name returns the town's name from mayor/town.json, or "" if unset.
+func (t *Town) Name() string { return t.name }
+
+// Rigs returns the town's rigs, sorted by name.
+func (t *Town) Rigs() ([]Rig, error) {
+	cfg, err := config.LoadRigsConfig(constants.MayorRigsPath(t.root))
+	if err != nil {
+		return nil, fmt.Errorf("loading rigs: %w", err)
+	}
+	rigs := make([]Rig, 0, len(cfg.Rigs))
+	for name, entry := range cfg.Rigs {
+		r := Rig{Name: name, Path: filepath.Join(t.root, name), GitURL: entry.GitURL}
+		if entry.BeadsConfig != nil {
+			r.Prefix = entry.BeadsConfig.Prefix
+		}
+		rigs = append(rigs, r)
+	}
+	sort.Slice(rigs, func(i, j int) bool { return rigs[i].Name < rigs[j].Name })
+	return rigs, nil
+}
+
+// Rig returns the named rig.
+func (t *Town) Rig(name string) (*Rig, error) {
+	rigs, err := t.Rigs()
+	if err != nil {
+		return nil, err
+	}
+	for i := range rigs {
+		if rigs[i].Name == name {
+			return &rigs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown rig %q", name)
+}
+
+// RigForBead returns the rig that owns a bead ID, from the town's prefix
+// routes. Town-level beads (such as hq-) and unrouted prefixes return "".
+func (t *Town) RigForBead(id string) string {
+	return beads.GetRigNameForPrefix(t.root, beads.ExtractPrefix(id))
+}
@@ -0,0 +1,153 @@
+package gastown
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestTown lays out the files a town's rig and routing lookups read.
+func newTestTown(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"mayor/town.json": `{"type":"town","version":1,"name":"testtown"}`,
+		"mayor/rigs.json": `{"version":1,"rigs":{
+			"gastown":{"git_url":"https://example.com/gastown.git","beads":{"prefix":"gt"}},
+			"beads":{"git_url":"https://example.com/beads.git","beads":{"prefix":"bd"}}}}`,
+		".beads/routes.jsonl": `{"prefix":"hq-","path":"."}
+{"prefix":"gt-","path":"gastown/mayor/rig"}
+{"prefix":"bd-","path":"beads/mayor/rig"}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestOpen_NotTown(t *testing.T) {
+	if _, err := Open(t.TempDir()); !errors.Is(err, ErrNotTown) {
+		t.Errorf("Open = %v, want ErrNotTown", err)
+	}
+	if _, err := Discover(t.TempDir()); !errors.Is(err, ErrNotTown) {
+		t.Errorf("Discover = %v, want ErrNotTown", err)
+	}
+}
+
+func TestDiscover_FromSubdirectory(t *testing.T) {
+	root := newTestTown(t)
+	sub := filepath.Join(root, "gastown", "polecats", "Toast")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	town, err := Discover(sub)
+	if err != nil {
+		t.Fatalf("Discover: %v", err)
+	}
+	want, _ := filepath.EvalSymlinks(root)
+	got, _ := filepath.EvalSymlinks(town.Root())
+	if got != want {
+		t.Errorf("Root = %s, want %s", town.Root(), root)
+	}
+	if town.Name() != "testtown" {
+		t.Errorf("Name = %q", town.Name())
+	}
+}
+
+func TestRigs(t *testing.T) {
+	town, err := Open(newTestTown(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rigs, err := town.Rigs()
+	if err != nil {
+		t.Fatalf("Rigs: %v", err)
+	}
+	if len(rigs) != 2 || rigs[0].Name != "beads" || rigs[1].Name != "gastown" {
+		t.Fatalf("Rigs = %+v, want beads and gastown sorted", rigs)
+	}
+	if rigs[1].Prefix != "gt" || rigs[1].Path != filepath.Join(town.Root(), "gastown") {
+		t.Errorf("gastown = %+v", rigs[1])
+	}
+	if _, err := town.Rig("nope"); err == nil {
+		t.Error("Rig(nope) = nil error")
+	}
+}
+
+func TestRigForBead(t *testing.T) {
+	town, err := Open(newTestTown(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"gt-abc":   "gastown",
+		"bd-x1.2":  "beads",
+		"hq-cv-1":  "",
+		"zz-9":     "",
+		"noprefix": "",
+	}
+	for id, want := range tests {
+		if got := town.RigForBead(id); got != want {
+			t.Errorf("RigForBead(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestSling_Validation(t *testing.T) {
+	town, err := Open(newTestTown(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := town.Sling(SlingRequest{Bead: "gt-abc"}); err == nil {
+		t.Error("Sling without rig = nil error")
+	}
+	if _, err := town.Sling(SlingRequest{Bead: "gt-abc", Rig: "nope"}); err == nil || !strings.Contains(err.Error(), "unknown rig") {
+		t.Errorf("Sling to unknown rig = %v", err)
+	}
+	if _, err := town.Sling(SlingRequest{Bead: "gt-abc", Rig: "gastown"}); !errors.Is(err, ErrDirectDispatch) {
+		t.Errorf("Sling in direct-dispatch town = %v, want ErrDirectDispatch", err)
+	}
+}
+
+func TestStatus_NoServer(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+	t.Setenv("GT_TMUX_SOCKET", "gt-sdk-test-no-server")
+	town, err := Open(newTestTown(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := town.Status()
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if len(st.Agents) != 0 || len(st.Rigs) != 2 || st.Town != "testtown" {
+		t.Errorf("Status = %+v", st)
+	}
+}
+
+func TestStatus_Running(t *testing.T) {
+	st := &Status{Agents: []Agent{
+		{Address: "mayor", Role: "mayor"},
+		{Address: "gastown/witness", Role: "witness", Rig: "gastown"},
+		{Address: "gastown/polecats/Toast", Role: "polecat", Rig: "gastown", Name: "Toast"},
+	}}
+	if got := st.Running("gastown"); len(got) != 2 {
+		t.Errorf("Running(gastown) = %+v", got)
+	}
+	if got := st.Running(""); len(got) != 1 || got[0].Address != "mayor" {
+		t.Errorf("Running(\"\") = %+v", got)
+	}
+}
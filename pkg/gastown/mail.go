@@ -0,0 +1,97 @@
+package gastown
+
+import (
+	"errors"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/mail"
+)
+
+// Message is one piece of agent mail.
+type Message struct {
+	ID        string
+	From      string
+	To        string
+	Subject   string
+	Body      string
+	Timestamp time.Time
+	Read      bool
+	Priority  string // low, normal, high or urgent
+	Type      string // task, scavenge, notification or reply
+	ThreadID  string
+	ReplyTo   string
+}
+
+// Mail is an outgoing message. To accepts any address gt mail send does:
+// an agent ("gastown/witness", "mayor/"), a list or queue, or a group.
+type Mail struct {
+	From     string // Sender address; defaults to "overseer"
+	To       string
+	Subject  string
+	Body     string
+	Priority string // Defaults to normal
+	Type     string // Defaults to notification
+	ReplyTo  string // Message ID this replies to
+}
+
+// SendMail delivers a message and waits for recipient notifications (tmux
+// nudges) to go out before returning.
+func (t *Town) SendMail(m Mail) (string, error) {
+	if m.To == "" || m.Subject == "" {
+		return "", errors.New("mail: to and subject are required")
+	}
+	from := m.From
+	if from == "" {
+		from = "overseer"
+	}
+	msg := mail.NewMessage(from, m.To, m.Subject, m.Body)
+	if m.Priority != "" {
+		msg.Priority = mail.Priority(m.Priority)
+	}
+	if m.Type != "" {
+		msg.Type = mail.MessageType(m.Type)
+	}
+	msg.ReplyTo = m.ReplyTo
+
+	router := mail.NewRouterWithTownRoot(t.root, t.root)
+	if err := router.Send(msg); err != nil {
+		return "", err
+	}
+	router.WaitPendingNotifications()
+	return msg.ID, nil
+}
+
+// Inbox lists the open messages in address's mailbox, highest priority
+// first and then newest first, as gt mail inbox shows them.
+func (t *Town) Inbox(address string, unreadOnly bool) ([]Message, error) {
+	mbox, err := mail.NewRouterWithTownRoot(t.root, t.root).GetMailbox(address)
+	if err != nil {
+		return nil, err
+	}
+	var msgs []*mail.Message
+	if unreadOnly {
+		msgs, err = mbox.ListUnread()
+	} else {
+		msgs, err = mbox.List()
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Message, 0, len(msgs))
+	for _, m := range msgs {
+		out = append(out, Message{
+			ID:        m.ID,
+			From:      m.From,
+			To:        m.To,
+			Subject:   m.Subject,
+			Body:      m.Body,
+			Timestamp: m.Timestamp,
+			Read:      m.Read,
+			Priority:  string(m.Priority),
+			Type:      string(m.Type),
+			ThreadID:  m.ThreadID,
+			ReplyTo:   m.ReplyTo,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,111 @@
+package gastown
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/scheduler/capacity"
+)
+
+// ErrDirectDispatch is returned by Sling when the town's scheduler is not in
+// deferred mode (scheduler.max_polecats <= 0). Direct dispatch spawns the
+// polecat in the calling process, which this package does not do; set
+// scheduler.max_polecats or run `gt sling` instead.
+var ErrDirectDispatch = errors.New("town uses direct dispatch; set scheduler.max_polecats to sling through the scheduler")
+
+// SlingRequest describes work to hand to a rig.
+type SlingRequest struct {
+	Bead string // Work bead ID (required)
+	Rig  string // Target rig (required)
+
+	Formula     string   // Formula to run, such as "mol-polecat-work"
+	Args        string   // Natural-language instructions for the polecat
+	Vars        []string // Formula variables, key=value
+	Agent       string   // Agent preset override
+	Merge       string   // Merge strategy: direct, mr or local
+	BaseBranch  string
+	NoMerge     bool
+	HookRawBead bool // Hook the bead without a formula
+
+	// Force schedules the bead even if it is already hooked or in progress.
+	Force bool
+}
+
+// SlingTicket is the result of a successful Sling.
+type SlingTicket struct {
+	Bead    string
+	Rig     string
+	Context string // Sling context bead tracking the queued work
+
+	// AlreadyQueued is true when the bead was already scheduled; Context is
+	// then the existing sling context and nothing new was created.
+	AlreadyQueued bool
+}
+
+// Sling queues a bead for a polecat on a rig. The town's scheduler picks it
+// up on its next dispatch cycle, exactly as with `gt sling` in a deferred
+// town. Slinging a bead that is already queued is a no-op.
+//
+// Unlike gt sling, Sling does not create an auto-convoy or cook the formula
+// at enqueue time; the formula is applied when the scheduler dispatches.
+func (t *Town) Sling(req SlingRequest) (*SlingTicket, error) {
+	if req.Bead == "" || req.Rig == "" {
+		return nil, errors.New("sling: bead and rig are required")
+	}
+	if _, err := t.Rig(req.Rig); err != nil {
+		return nil, err
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(t.root))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if settings.Scheduler == nil || !settings.Scheduler.IsDeferred() {
+		return nil, ErrDirectDispatch
+	}
+
+	bead, err := t.Bead(req.Bead)
+	if err != nil {
+		return nil, fmt.Errorf("bead %s: %w", req.Bead, err)
+	}
+
+	townBeads := beads.NewWithBeadsDir(t.root, filepath.Join(t.root, ".beads"))
+	existing, _, err := townBeads.FindOpenSlingContext(req.Bead)
+	if err != nil {
+		return nil, fmt.Errorf("checking for existing sling context: %w", err)
+	}
+	if existing != nil {
+		return &SlingTicket{Bead: req.Bead, Rig: req.Rig, Context: existing.ID, AlreadyQueued: true}, nil
+	}
+
+	switch bead.Status {
+	case "pinned", "hooked", "in_progress":
+		if !req.Force {
+			return nil, fmt.Errorf("bead %s is already %s to %s", req.Bead, bead.Status, bead.Assignee)
+		}
+	}
+
+	fields := &capacity.SlingContextFields{
+		Version:     1,
+		WorkBeadID:  req.Bead,
+		TargetRig:   req.Rig,
+		EnqueuedAt:  time.Now().UTC().Format(time.RFC3339),
+		Formula:     req.Formula,
+		Args:        req.Args,
+		Vars:        strings.Join(req.Vars, "\n"),
+		Merge:       req.Merge,
+		BaseBranch:  req.BaseBranch,
+		NoMerge:     req.NoMerge,
+		Agent:       req.Agent,
+		HookRawBead: req.HookRawBead,
+	}
+	ctx, err := townBeads.CreateSlingContext(bead.Title, req.Bead, fields)
+	if err != nil {
+		return nil, fmt.Errorf("creating sling context: %w", err)
+	}
+	return &SlingTicket{Bead: req.Bead, Rig: req.Rig, Context: ctx.ID}, nil
+}
@@ -0,0 +1,71 @@
+package gastown
+
+import (
+	"sort"
+
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Agent is a running agent session.
+type Agent struct {
+	Address string // Mail address, such as "gastown/polecats/Toast"
+	Role    string // mayor, deacon, witness, refinery, crew, polecat or dog
+	Rig     string // "" for town-level agents
+	Name    string // Crew, polecat or dog name
+	Session string // tmux session name
+}
+
+// Status is a snapshot of the town's rigs and running agents.
+type Status struct {
+	Town   string
+	Root   string
+	Rigs   []Rig
+	Agents []Agent
+}
+
+// Running returns the agents in rig ("" for town-level agents).
+func (s *Status) Running(rig string) []Agent {
+	var out []Agent
+	for _, a := range s.Agents {
+		if a.Rig == rig {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// Status reports the town's rigs and the agents whose tmux sessions are
+// running. Sessions that are not Gas Town agents are ignored; a stopped
+// tmux server reports no agents rather than an error.
+func (t *Town) Status() (*Status, error) {
+	rigs, err := t.Rigs()
+	if err != nil {
+		return nil, err
+	}
+	registry, err := session.BuildPrefixRegistryFromTown(t.root)
+	if err != nil {
+		return nil, err
+	}
+	names, err := tmux.NewTmux().ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	st := &Status{Town: t.name, Root: t.root, Rigs: rigs}
+	for _, name := range names {
+		id, err := session.ParseSessionNameWithRegistry(name, registry)
+		if err != nil {
+			continue
+		}
+		st.Agents = append(st.Agents, Agent{
+			Address: id.Address(),
+			Role:    string(id.Role),
+			Rig:     id.Rig,
+			Name:    id.Name,
+			Session: name,
+		})
+	}
+	sort.Slice(st.Agents, func(i, j int) bool { return st.Agents[i].Address < st.Agents[j].Address })
+	return st, nil
+}